@@ -2635,7 +2635,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Deletes retros older than {config.cleanup_retros_days_old} based on last activity date",
+                "description": "Soft deletes retros older than {config.cleanup_retros_days_old} based on last activity date, they can be restored for 30 days",
                 "produces": [
                     "application/json"
                 ],
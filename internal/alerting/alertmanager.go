@@ -0,0 +1,150 @@
+// Package alerting sends Thunderdome operational alerts to Prometheus Alertmanager
+package alerting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// StaleGamesAlertName is the alertname label of the stale games alert
+	StaleGamesAlertName = "ThunderdomeStaleGames"
+	// LowCacheHitRateAlertName is the alertname label of the low cache hit rate alert
+	LowCacheHitRateAlertName = "ThunderdomeLowCacheHitRate"
+
+	// staleGamesListLimit caps the game IDs listed in the stale games alert description
+	staleGamesListLimit = 20
+	// requestTimeout is how long alertmanager has to accept the alerts
+	requestTimeout = 10 * time.Second
+)
+
+// Alert is an Alertmanager alert as accepted by the alerts API, an alert with an endsAt in the past is resolved
+type Alert struct {
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       *time.Time        `json:"endsAt,omitempty"`
+	GeneratorURL string            `json:"generatorURL"`
+}
+
+// AlertmanagerNotifier sends alerts to an Alertmanager and keeps track of those firing
+// so they can be resolved once the condition clears
+type AlertmanagerNotifier struct {
+	webhookURL   string
+	generatorURL string
+	client       *http.Client
+	now          func() time.Time
+
+	mu     sync.Mutex
+	firing map[string]Alert
+}
+
+// NewAlertmanagerNotifier returns a new notifier posting to the Alertmanager alerts API webhookURL,
+// generatorURL is the Thunderdome url alerts link back to
+func NewAlertmanagerNotifier(webhookURL string, generatorURL string) *AlertmanagerNotifier {
+	return &AlertmanagerNotifier{
+		webhookURL:   webhookURL,
+		generatorURL: generatorURL,
+		client:       &http.Client{Timeout: requestTimeout},
+		now:          time.Now,
+		firing:       make(map[string]Alert),
+	}
+}
+
+// NotifyStaleGames fires the stale games alert listing the stale poker game IDs
+func (n *AlertmanagerNotifier) NotifyStaleGames(ctx context.Context, games []string) error {
+	listed := games
+	if len(listed) > staleGamesListLimit {
+		listed = listed[:staleGamesListLimit]
+	}
+	description := "Stale games: " + strings.Join(listed, ", ")
+	if len(games) > len(listed) {
+		description += fmt.Sprintf(" and %d more", len(games)-len(listed))
+	}
+
+	return n.fire(ctx, StaleGamesAlertName, "warning", map[string]string{
+		"summary":     fmt.Sprintf("%d poker games have active participants but no recent activity", len(games)),
+		"description": description,
+	})
+}
+
+// NotifyLowCacheHitRate fires the low cache hit rate alert with the current hit rate percentage
+func (n *AlertmanagerNotifier) NotifyLowCacheHitRate(ctx context.Context, rate float64) error {
+	return n.fire(ctx, LowCacheHitRateAlertName, "warning", map[string]string{
+		"summary":     "Redis cache hit rate is low",
+		"description": fmt.Sprintf("Redis cache hit rate is %.2f%%", rate),
+	})
+}
+
+// Resolved resolves the firing alert by re-sending it with endsAt set to now,
+// nothing is sent when the alert isn't firing
+func (n *AlertmanagerNotifier) Resolved(ctx context.Context, alertName string) error {
+	n.mu.Lock()
+	alert, firing := n.firing[alertName]
+	if firing {
+		delete(n.firing, alertName)
+	}
+	n.mu.Unlock()
+	if !firing {
+		return nil
+	}
+
+	endsAt := n.now().UTC()
+	alert.EndsAt = &endsAt
+
+	return n.send(ctx, alert)
+}
+
+// fire sends the alert, an alert that is already firing keeps its original startsAt
+func (n *AlertmanagerNotifier) fire(ctx context.Context, alertName string, severity string, annotations map[string]string) error {
+	n.mu.Lock()
+	startsAt := n.now().UTC()
+	if existing, firing := n.firing[alertName]; firing {
+		startsAt = existing.StartsAt
+	}
+	alert := Alert{
+		Labels: map[string]string{
+			"alertname": alertName,
+			"service":   "thunderdome",
+			"severity":  severity,
+		},
+		Annotations:  annotations,
+		StartsAt:     startsAt,
+		GeneratorURL: n.generatorURL,
+	}
+	n.firing[alertName] = alert
+	n.mu.Unlock()
+
+	return n.send(ctx, alert)
+}
+
+func (n *AlertmanagerNotifier) send(ctx context.Context, alerts ...Alert) error {
+	body, err := json.Marshal(alerts)
+	if err != nil {
+		return fmt.Errorf("alertmanager marshal alerts error: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("alertmanager request error: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("alertmanager send alerts error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("alertmanager send alerts unexpected status: %d", resp.StatusCode)
+	}
+
+	return nil
+}
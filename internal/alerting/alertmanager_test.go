@@ -0,0 +1,106 @@
+package alerting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden files")
+
+// recordingAlertmanager captures the alert payloads posted to it
+func recordingAlertmanager(t *testing.T) (*httptest.Server, *[][]byte) {
+	t.Helper()
+	payloads := make([][]byte, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("expected a json POST, got %s %s", r.Method, r.Header.Get("Content-Type"))
+		}
+		body, _ := io.ReadAll(r.Body)
+		payloads = append(payloads, body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &payloads
+}
+
+// assertGolden compares the indented payload against the golden file
+func assertGolden(t *testing.T, name string, payload []byte) {
+	t.Helper()
+	golden := filepath.Join("testdata", name)
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, payload, "", "  "); err != nil {
+		t.Fatalf("payload isn't valid json: %v", err)
+	}
+	indented.WriteString("\n")
+
+	if *updateGolden {
+		if err := os.WriteFile(golden, indented.Bytes(), 0644); err != nil {
+			t.Fatalf("failed to update golden file: %v", err)
+		}
+	}
+
+	expected, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("failed to read golden file: %v", err)
+	}
+	if indented.String() != string(expected) {
+		t.Errorf("alertmanager payload does not match %s\ngot:\n%s\nexpected:\n%s", golden, indented.String(), expected)
+	}
+}
+
+// TestAlertmanagerPayloadGolden compares the alert payloads sent to alertmanager against the golden files
+func TestAlertmanagerPayloadGolden(t *testing.T) {
+	server, payloads := recordingAlertmanager(t)
+	ctx := context.Background()
+
+	notifier := NewAlertmanagerNotifier(server.URL, "https://thunderdome.dev/admin")
+	current := time.Date(2025, 3, 16, 12, 0, 0, 0, time.UTC)
+	notifier.now = func() time.Time { return current }
+
+	if err := notifier.NotifyStaleGames(ctx, []string{"game-1", "game-2"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := notifier.NotifyLowCacheHitRate(ctx, 12.5); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// resolving keeps the labels and startsAt of the firing alert so alertmanager matches it
+	current = current.Add(10 * time.Minute)
+	if err := notifier.Resolved(ctx, StaleGamesAlertName); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// an alert that isn't firing is not sent
+	if err := notifier.Resolved(ctx, StaleGamesAlertName); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(*payloads) != 3 {
+		t.Fatalf("expected 3 payloads, got %d", len(*payloads))
+	}
+	assertGolden(t, "stale_games.json", (*payloads)[0])
+	assertGolden(t, "low_cache_hit_rate.json", (*payloads)[1])
+	assertGolden(t, "stale_games_resolved.json", (*payloads)[2])
+}
+
+// TestAlertmanagerErrorStatus makes sure alerts rejected by alertmanager are reported
+func TestAlertmanagerErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	notifier := NewAlertmanagerNotifier(server.URL, "")
+	if err := notifier.NotifyLowCacheHitRate(context.Background(), 10); err == nil {
+		t.Fatal("expected an error")
+	}
+}
@@ -0,0 +1,91 @@
+package alerting
+
+import (
+	"context"
+	"time"
+
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+// HealthCheckInterval is how often the alert conditions are checked
+const HealthCheckInterval = 5 * time.Minute
+
+// StaleGamesSource provides the poker games with active participants but no activity since inactiveSince
+type StaleGamesSource interface {
+	GetStaleGames(ctx context.Context, inactiveSince time.Time) ([]string, error)
+}
+
+// CacheStatsSource provides the Redis cache statistics, matching redis.GetCacheStats
+type CacheStatsSource func() map[string]interface{}
+
+// HealthChecker periodically checks the alert conditions, firing their alert while
+// the condition holds and resolving it once it clears
+type HealthChecker struct {
+	logger                *otelzap.Logger
+	notifier              *AlertmanagerNotifier
+	staleGames            StaleGamesSource
+	staleAfter            time.Duration
+	cacheStats            CacheStatsSource
+	cacheHitRateThreshold float64
+}
+
+// NewHealthChecker returns a new health checker, cacheStats may be nil when Redis isn't configured
+func NewHealthChecker(logger *otelzap.Logger, notifier *AlertmanagerNotifier, staleGames StaleGamesSource, staleAfter time.Duration,
+	cacheStats CacheStatsSource, cacheHitRateThreshold float64) *HealthChecker {
+	return &HealthChecker{
+		logger:                logger,
+		notifier:              notifier,
+		staleGames:            staleGames,
+		staleAfter:            staleAfter,
+		cacheStats:            cacheStats,
+		cacheHitRateThreshold: cacheHitRateThreshold,
+	}
+}
+
+// Run checks the alert conditions every interval until ctx is cancelled
+func (h *HealthChecker) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			h.Check(ctx)
+		}
+	}
+}
+
+// Check checks the alert conditions once
+func (h *HealthChecker) Check(ctx context.Context) {
+	games, err := h.staleGames.GetStaleGames(ctx, time.Now().Add(-h.staleAfter))
+	if err != nil {
+		h.logger.Ctx(ctx).Error("alerting get stale games error", zap.Error(err))
+	} else if len(games) > 0 {
+		h.report(ctx, StaleGamesAlertName, h.notifier.NotifyStaleGames(ctx, games))
+	} else {
+		h.report(ctx, StaleGamesAlertName, h.notifier.Resolved(ctx, StaleGamesAlertName))
+	}
+
+	if h.cacheStats == nil {
+		return
+	}
+	stats := h.cacheStats()
+	// the hit rate is meaningless without any cache requests
+	if totalRequests, _ := stats["total_requests"].(int64); totalRequests == 0 {
+		return
+	}
+	if hitRate, _ := stats["hit_rate"].(float64); hitRate < h.cacheHitRateThreshold {
+		h.report(ctx, LowCacheHitRateAlertName, h.notifier.NotifyLowCacheHitRate(ctx, hitRate))
+	} else {
+		h.report(ctx, LowCacheHitRateAlertName, h.notifier.Resolved(ctx, LowCacheHitRateAlertName))
+	}
+}
+
+func (h *HealthChecker) report(ctx context.Context, alertName string, err error) {
+	if err != nil {
+		h.logger.Ctx(ctx).Error("alerting notify alertmanager error", zap.Error(err), zap.String("alert_name", alertName))
+	}
+}
@@ -0,0 +1,128 @@
+// Package analysis provides story text metrics used as estimation hints
+package analysis
+
+import (
+	"html"
+	"math"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// StoryAnalysis holds story text metrics used as estimation hints
+type StoryAnalysis = thunderdome.StoryAnalysis
+
+var (
+	htmlTagPattern      = regexp.MustCompile(`<[^>]*>`)
+	blockTagPattern     = regexp.MustCompile(`(?i)</?(p|div|br|li|ul|ol|h[1-6])[^>]*>`)
+	listItemPattern     = regexp.MustCompile(`(?i)<li[\s>]`)
+	listMarkerPattern   = regexp.MustCompile(`^\s*([-*+•]|\d+[.)]|\[[ xX]?\])\s*`)
+	sentenceEndPattern  = regexp.MustCompile(`[.!?]+`)
+	vowelGroupPattern   = regexp.MustCompile(`[aeiouy]+`)
+	silentEndingPattern = regexp.MustCompile(`[^aeiouy]e$`)
+)
+
+// AnalyzeStory computes the word count, Flesch-Kincaid grade level and acceptance criteria count
+// for the story description and acceptance criteria
+func AnalyzeStory(story *thunderdome.Story) StoryAnalysis {
+	if story == nil {
+		return StoryAnalysis{}
+	}
+
+	text := strings.TrimSpace(plainText(story.Description) + "\n" + plainText(story.AcceptanceCriteria))
+
+	return StoryAnalysis{
+		WordCount:               len(words(text)),
+		ReadabilityScore:        ReadabilityScore(text),
+		AcceptanceCriteriaCount: countAcceptanceCriteria(story.AcceptanceCriteria),
+	}
+}
+
+// ReadabilityScore returns the Flesch-Kincaid grade level of the text rounded to two decimals,
+// or 0 when the text has no words
+func ReadabilityScore(text string) float64 {
+	textWords := words(text)
+	wordCount := len(textWords)
+	if wordCount == 0 {
+		return 0
+	}
+
+	syllableCount := 0
+	for _, word := range textWords {
+		syllableCount += countSyllables(word)
+	}
+
+	sentenceCount := 0
+	for _, sentence := range sentenceEndPattern.Split(text, -1) {
+		if strings.TrimSpace(sentence) != "" {
+			sentenceCount++
+		}
+	}
+	if sentenceCount == 0 {
+		sentenceCount = 1
+	}
+
+	grade := 0.39*(float64(wordCount)/float64(sentenceCount)) +
+		11.8*(float64(syllableCount)/float64(wordCount)) - 15.59
+
+	return math.Round(grade*100) / 100
+}
+
+// words splits the text into lowercase words, ignoring tokens without letters or numbers such as list markers
+func words(text string) []string {
+	result := make([]string, 0)
+	for _, word := range strings.Fields(text) {
+		word = strings.ToLower(strings.TrimFunc(word, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+		}))
+		if word != "" {
+			result = append(result, word)
+		}
+	}
+
+	return result
+}
+
+// countSyllables estimates the syllables in a lowercase word by counting vowel groups
+func countSyllables(word string) int {
+	if len(word) <= 3 {
+		return 1
+	}
+
+	count := len(vowelGroupPattern.FindAllString(word, -1))
+	if silentEndingPattern.MatchString(word) && !strings.HasSuffix(word, "le") {
+		count--
+	}
+	if count < 1 {
+		count = 1
+	}
+
+	return count
+}
+
+// countAcceptanceCriteria counts list items in the acceptance criteria,
+// falling back to non-empty lines when it isn't a list
+func countAcceptanceCriteria(criteria string) int {
+	if items := listItemPattern.FindAllString(criteria, -1); len(items) > 0 {
+		return len(items)
+	}
+
+	count := 0
+	for _, line := range strings.Split(plainText(criteria), "\n") {
+		if strings.TrimSpace(listMarkerPattern.ReplaceAllString(line, "")) != "" {
+			count++
+		}
+	}
+
+	return count
+}
+
+// plainText strips html markup from rich text, keeping block elements on separate lines
+func plainText(content string) string {
+	content = blockTagPattern.ReplaceAllString(content, "\n")
+	content = htmlTagPattern.ReplaceAllString(content, "")
+
+	return html.UnescapeString(content)
+}
@@ -0,0 +1,79 @@
+package analysis
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+func TestReadabilityScore(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want float64
+	}{
+		{name: "empty", text: "", want: 0},
+		{name: "punctuation only", text: "...", want: 0},
+		{name: "single syllable words", text: "The cat sat on the mat.", want: -1.45},
+		{name: "pangram", text: "The quick brown fox jumps over the lazy dog.", want: 2.34},
+		{name: "multiple sentences", text: "This story is simple. It has two sentences.", want: 3.67},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ReadabilityScore(tt.text); got != tt.want {
+				t.Errorf("ReadabilityScore(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeStory(t *testing.T) {
+	tests := []struct {
+		name  string
+		story *thunderdome.Story
+		want  StoryAnalysis
+	}{
+		{name: "nil story", story: nil, want: StoryAnalysis{}},
+		{name: "empty story", story: &thunderdome.Story{Name: "Empty"}, want: StoryAnalysis{}},
+		{
+			name: "html description and list criteria",
+			story: &thunderdome.Story{
+				Description:        "<p>The cat sat on the mat.</p>",
+				AcceptanceCriteria: "<ul><li>one</li><li>two</li></ul>",
+			},
+			want: StoryAnalysis{WordCount: 8, ReadabilityScore: -2.23, AcceptanceCriteriaCount: 2},
+		},
+		{
+			name: "plain text criteria lines",
+			story: &thunderdome.Story{
+				AcceptanceCriteria: "- user can log in\n\n- user can log out\n",
+			},
+			want: StoryAnalysis{WordCount: 8, ReadabilityScore: 2.28, AcceptanceCriteriaCount: 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AnalyzeStory(tt.story); got != tt.want {
+				t.Errorf("AnalyzeStory() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func benchmarkAnalyzeStory(b *testing.B, sentences int) {
+	story := &thunderdome.Story{
+		Description:        "<p>" + strings.Repeat("As a facilitator I want to import stories so that the team can estimate them quickly. ", sentences) + "</p>",
+		AcceptanceCriteria: "<ul>" + strings.Repeat("<li>stories are imported</li>", sentences) + "</ul>",
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		AnalyzeStory(story)
+	}
+}
+
+func BenchmarkAnalyzeStoryShort(b *testing.B)  { benchmarkAnalyzeStory(b, 1) }
+func BenchmarkAnalyzeStoryMedium(b *testing.B) { benchmarkAnalyzeStory(b, 20) }
+func BenchmarkAnalyzeStoryLong(b *testing.B)   { benchmarkAnalyzeStory(b, 500) }
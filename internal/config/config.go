@@ -37,6 +37,13 @@ func InitConfig(logger *otelzap.Logger) Config {
 	viper.SetDefault("http.websocket_pong_wait_sec", 60)
 	viper.SetDefault("http.websocket_ping_period_sec", 54)
 	viper.SetDefault("http.websocket_subdomain", "")
+	viper.SetDefault("http.websocket_shutdown_grace_sec", 30)
+	viper.SetDefault("http.websocket_read_buffer_size", 4096)
+	viper.SetDefault("http.websocket_write_buffer_size", 4096)
+	viper.SetDefault("http.participant_sync_interval_sec", 30)
+	viper.SetDefault("http.mobile_app_scheme", "")
+	viper.SetDefault("http.trusted_proxies", []string{})
+	viper.SetDefault("http.admin_ip_allowlist", []string{})
 
 	viper.SetDefault("analytics.enabled", true)
 	viper.SetDefault("analytics.id", "UA-140245309-1")
@@ -45,6 +52,20 @@ func InitConfig(logger *otelzap.Logger) Config {
 	viper.SetDefault("otel.service_name", "thunderdome")
 	viper.SetDefault("otel.collector_url", "localhost:4317")
 	viper.SetDefault("otel.insecure_mode", false)
+	viper.SetDefault("otel.sampling_strategy", "always")
+	viper.SetDefault("otel.sampling_ratio", 1.0)
+
+	viper.SetDefault("redis.cache_hit_rate_alert_threshold", 50)
+
+	viper.SetDefault("alerting.alertmanager_url", "")
+	viper.SetDefault("alerting.stale_game_hours", 24)
+
+	viper.SetDefault("cache.disabled", false)
+	viper.SetDefault("cache.ttl_game_hours", 24)
+	viper.SetDefault("cache.ttl_story_hours", 1)
+	viper.SetDefault("cache.ttl_user_hours", 24)
+	viper.SetDefault("cache.ttl_team_hours", 24)
+	viper.SetDefault("cache.ttl_org_hours", 24)
 
 	viper.SetDefault("db.host", "db")
 	viper.SetDefault("db.port", 5432)
@@ -55,6 +76,10 @@ func InitConfig(logger *otelzap.Logger) Config {
 	viper.SetDefault("db.max_open_conns", 25)
 	viper.SetDefault("db.max_idle_conns", 25)
 	viper.SetDefault("db.conn_max_lifetime", 5)
+	viper.SetDefault("db.read_replica_host", "")
+	viper.SetDefault("db.read_replica_port", 5432)
+	viper.SetDefault("db.read_replica_user", "")
+	viper.SetDefault("db.read_replica_pass", "")
 
 	viper.SetDefault("smtp.enabled", true)
 	viper.SetDefault("smtp.host", "localhost")
@@ -67,6 +92,7 @@ func InitConfig(logger *otelzap.Logger) Config {
 	viper.SetDefault("smtp.auth", "PLAIN")
 
 	viper.SetDefault("config.aes_hashkey", "therevengers")
+	viper.SetDefault("config.ip_hash_salt", "")
 	viper.SetDefault("config.allowedPointValues",
 		[]string{"0", "1/2", "1", "2", "3", "5", "8", "13", "20", "21", "34", "40", "55", "100", "?", "☕️"})
 	viper.SetDefault("config.defaultPointValues",
@@ -77,6 +103,8 @@ func InitConfig(logger *otelzap.Logger) Config {
 	viper.SetDefault("config.allow_guests", true)
 	viper.SetDefault("config.allow_registration", true)
 	viper.SetDefault("config.allow_jira_import", true)
+	viper.SetDefault("config.allow_asana_import", false)
+	viper.SetDefault("config.allow_notion_import", false)
 	viper.SetDefault("config.allow_csv_import", true)
 	viper.SetDefault("config.default_locale", "en")
 	viper.SetDefault("config.friendly_ui_verbs", false)
@@ -88,14 +116,25 @@ func InitConfig(logger *otelzap.Logger) Config {
 	viper.SetDefault("config.cleanup_guests_days_old", 180)
 	viper.SetDefault("config.cleanup_retros_days_old", 180)
 	viper.SetDefault("config.cleanup_storyboards_days_old", 180)
+	viper.SetDefault("config.cleanup_notifications_days_old", 30)
 	viper.SetDefault("config.organizations_enabled", true)
 	viper.SetDefault("config.require_teams", false)
 	viper.SetDefault("config.subscriptions_enabled", false)
 	viper.SetDefault("config.retro_default_template_id", "5c3b4783-82cb-45a4-ac7b-c956c6b4047e")
 	viper.SetDefault("config.default_point_average_rounding", "ceil")
+	viper.SetDefault("config.import_deduplication_enabled", true)
+	viper.SetDefault("config.ws_idle_timeout_minutes", 0)
+	viper.SetDefault("config.require_registration_approval", false)
+	viper.SetDefault("config.org_bulk_import_enabled", false)
+	viper.SetDefault("config.activity_score_weights.game", 3)
+	viper.SetDefault("config.activity_score_weights.story_vote", 1)
+	viper.SetDefault("config.activity_score_weights.retro", 3)
+	viper.SetDefault("config.activity_score_weights.standup", 2)
 
 	viper.SetDefault("subscription.account_secret", "")
 	viper.SetDefault("subscription.webhook_secret", "")
+	viper.SetDefault("subscription.billing_webhook_url", "")
+	viper.SetDefault("subscription.billing_webhook_event_filters", []string{})
 	viper.SetDefault("subscription.manage_link", "https://billing.stripe.com/p/login/5kA5lKeb7eU9bp6cMM")
 	viper.SetDefault("subscription.individual.enabled", true)
 	viper.SetDefault("subscription.individual.month_price", "5")
@@ -113,6 +152,19 @@ func InitConfig(logger *otelzap.Logger) Config {
 	viper.SetDefault("subscription.organization.month_checkout_link", "https://buy.stripe.com/8wM6qK2mr0kF5IA8wC")
 	viper.SetDefault("subscription.organization.year_checkout_link", "https://buy.stripe.com/eVa02m2mr7N74EwcMT")
 
+	viper.SetDefault("storage.provider", "")
+	viper.SetDefault("storage.bucket", "")
+	viper.SetDefault("storage.region", "us-east-1")
+	viper.SetDefault("storage.endpoint", "")
+	viper.SetDefault("storage.access_key_id", "")
+	viper.SetDefault("storage.secret_access_key", "")
+	viper.SetDefault("storage.local_path", "attachments")
+	viper.SetDefault("storage.url_expiry_minutes", 15)
+	viper.SetDefault("storage.max_upload_size_mb", 10)
+
+	viper.SetDefault("github.api_url", "https://api.github.com")
+	viper.SetDefault("github.allowed_repos", []string{})
+
 	viper.SetDefault("admin.email", "")
 
 	// feature flags
@@ -131,9 +183,23 @@ func InitConfig(logger *otelzap.Logger) Config {
 	viper.SetDefault("auth.ldap.cn_attr", "cn")
 	viper.SetDefault("auth.header.usernameHeader", "Remote-User")
 	viper.SetDefault("auth.header.emailHeader", "Remote-Email")
+	viper.SetDefault("auth.password.min_length", 6)
+	viper.SetDefault("auth.password.max_length", 72)
+	viper.SetDefault("auth.password.require_uppercase", false)
+	viper.SetDefault("auth.password.require_lowercase", false)
+	viper.SetDefault("auth.password.require_digit", false)
+	viper.SetDefault("auth.password.require_special", false)
 	viper.SetDefault("auth.google.enabled", false)
 	viper.SetDefault("auth.google.client_id", "")
 	viper.SetDefault("auth.google.client_secret", "")
+	viper.SetDefault("auth.saml.idp_url", "")
+	viper.SetDefault("auth.saml.idp_metadata_url", "")
+	viper.SetDefault("auth.saml.sp_entity_id", "")
+	viper.SetDefault("auth.saml.sp_callback_url", "")
+	viper.SetDefault("auth.saml.cert_file", "")
+	viper.SetDefault("auth.saml.key_file", "")
+	viper.SetDefault("auth.saml.group_attribute", "")
+	viper.SetDefault("auth.saml.group_organization_id", "")
 
 	// automatically load matching envs
 	viper.SetEnvKeyReplacer(strings.NewReplacer(`.`, `_`))
@@ -153,6 +219,13 @@ func InitConfig(logger *otelzap.Logger) Config {
 	_ = viper.BindEnv("config.show_warrior_rank", "CONFIG_SHOW_RANK")
 	_ = viper.BindEnv("auth.header.usernameHeader", "AUTH_HEADER_USERNAME_HEADER")
 	_ = viper.BindEnv("auth.header.emailHeader", "AUTH_HEADER_EMAIL_HEADER")
+	_ = viper.BindEnv("cache.disabled", "THUNDERDOME_CACHE_DISABLED")
+	_ = viper.BindEnv("cache.ttl_game_hours", "THUNDERDOME_CACHE_TTL_GAME_HOURS")
+	_ = viper.BindEnv("cache.ttl_story_hours", "THUNDERDOME_CACHE_TTL_STORY_HOURS")
+	_ = viper.BindEnv("cache.ttl_user_hours", "THUNDERDOME_CACHE_TTL_USER_HOURS")
+	_ = viper.BindEnv("cache.ttl_team_hours", "THUNDERDOME_CACHE_TTL_TEAM_HOURS")
+	_ = viper.BindEnv("cache.ttl_org_hours", "THUNDERDOME_CACHE_TTL_ORG_HOURS")
+	_ = viper.BindEnv("github.api_url", "GITHUB_API_URL", "THUNDERDOME_GITHUB_API_URL")
 
 	err := viper.ReadInConfig()
 	if err != nil {
@@ -0,0 +1,120 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/zap"
+)
+
+// TestCacheTTLConfig makes sure each cache TTL env var resolves to its entity's TTL
+func TestCacheTTLConfig(t *testing.T) {
+	t.Setenv("THUNDERDOME_CACHE_TTL_GAME_HOURS", "2")
+	t.Setenv("THUNDERDOME_CACHE_TTL_STORY_HOURS", "3")
+	t.Setenv("THUNDERDOME_CACHE_TTL_USER_HOURS", "4")
+	t.Setenv("THUNDERDOME_CACHE_TTL_TEAM_HOURS", "5")
+	t.Setenv("THUNDERDOME_CACHE_TTL_ORG_HOURS", "6")
+
+	c := InitConfig(otelzap.New(zap.NewNop()))
+
+	expected := thunderdome.CacheTTLConfig{
+		GameTTL:  2 * time.Hour,
+		StoryTTL: 3 * time.Hour,
+		UserTTL:  4 * time.Hour,
+		TeamTTL:  5 * time.Hour,
+		OrgTTL:   6 * time.Hour,
+	}
+	if ttl := c.Cache.TTLConfig(); ttl != expected {
+		t.Errorf("expected cache TTLs %+v, got %+v", expected, ttl)
+	}
+}
+
+// TestCacheTTLConfigDisabled makes sure disabling the cache zeroes every TTL
+func TestCacheTTLConfigDisabled(t *testing.T) {
+	t.Setenv("THUNDERDOME_CACHE_TTL_GAME_HOURS", "2")
+	t.Setenv("THUNDERDOME_CACHE_DISABLED", "true")
+
+	c := InitConfig(otelzap.New(zap.NewNop()))
+
+	if ttl := c.Cache.TTLConfig(); ttl != (thunderdome.CacheTTLConfig{}) {
+		t.Errorf("expected every cache TTL to be 0, got %+v", ttl)
+	}
+}
+
+// TestOtelSampler makes sure each sampling strategy builds its sampler and invalid configuration is rejected
+func TestOtelSampler(t *testing.T) {
+	tests := []struct {
+		name     string
+		otel     Otel
+		expected sdktrace.Sampler
+		wantErr  bool
+	}{
+		{name: "default", otel: Otel{SamplingRatio: 1}, expected: sdktrace.AlwaysSample()},
+		{name: "always", otel: Otel{SamplingStrategy: "always", SamplingRatio: 1}, expected: sdktrace.AlwaysSample()},
+		{name: "never", otel: Otel{SamplingStrategy: "never", SamplingRatio: 1}, expected: sdktrace.NeverSample()},
+		{name: "ratio", otel: Otel{SamplingStrategy: "ratio", SamplingRatio: 0.25}, expected: sdktrace.TraceIDRatioBased(0.25)},
+		{name: "parent based", otel: Otel{SamplingStrategy: "parent_based", SamplingRatio: 0.25},
+			expected: sdktrace.ParentBased(sdktrace.TraceIDRatioBased(0.25))},
+		{name: "ratio below 0", otel: Otel{SamplingStrategy: "ratio", SamplingRatio: -0.1}, wantErr: true},
+		{name: "ratio above 1", otel: Otel{SamplingStrategy: "parent_based", SamplingRatio: 1.5}, wantErr: true},
+		{name: "unknown strategy", otel: Otel{SamplingStrategy: "sometimes", SamplingRatio: 1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sampler, err := tt.otel.Sampler()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if reflect.TypeOf(sampler) != reflect.TypeOf(tt.expected) {
+				t.Errorf("expected sampler type %T, got %T", tt.expected, sampler)
+			}
+			if sampler.Description() != tt.expected.Description() {
+				t.Errorf("expected sampler %s, got %s", tt.expected.Description(), sampler.Description())
+			}
+		})
+	}
+}
+
+// TestValidateWebsocketBufferSizes makes sure the default buffer sizes are valid and unreasonable sizes are rejected
+func TestValidateWebsocketBufferSizes(t *testing.T) {
+	c := InitConfig(otelzap.New(zap.NewNop()))
+	if c.Http.WebsocketReadBufferSize != 4096 || c.Http.WebsocketWriteBufferSize != 4096 {
+		t.Errorf("expected 4096 byte default buffers, got %d and %d", c.Http.WebsocketReadBufferSize, c.Http.WebsocketWriteBufferSize)
+	}
+	if err := c.Http.ValidateWebsocketBufferSizes(); err != nil {
+		t.Errorf("expected the default buffer sizes to be valid, got %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		read    int
+		write   int
+		wantErr bool
+	}{
+		{name: "minimum", read: 1024, write: 1024},
+		{name: "maximum", read: 1024 * 1024, write: 1024 * 1024},
+		{name: "read too small", read: 512, write: 4096, wantErr: true},
+		{name: "write too large", read: 4096, write: 2 * 1024 * 1024, wantErr: true},
+		{name: "unset", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := Http{WebsocketReadBufferSize: tt.read, WebsocketWriteBufferSize: tt.write}
+			if err := h.ValidateWebsocketBufferSizes(); (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -1,6 +1,12 @@
 package config
 
-import "github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+import (
+	"fmt"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
 
 // Config is the main application configuration
 type Config struct {
@@ -8,34 +14,71 @@ type Config struct {
 	Analytics
 	Admin
 	Otel
+	Redis
+	Alerting
+	Cache
 	Db
 	Smtp
 	Config AppConfig
 	Feature
 	Auth
 	Subscription thunderdome.SubscriptionConfig
+	Storage
+	Github
 }
 
 // Http is the application HTTP server configuration
 type Http struct {
-	Port                   string
-	SecureCookie           bool   `mapstructure:"secure_cookie"`
-	BackendCookieName      string `mapstructure:"backend_cookie_name"`
-	SessionCookieName      string `mapstructure:"session_cookie_name"`
-	FrontendCookieName     string `mapstructure:"frontend_cookie_name"`
-	AuthStateCookieName    string `mapstructure:"auth_state_cookie_name"`
-	Domain                 string
-	PathPrefix             string `mapstructure:"path_prefix"`
-	SecureProtocol         bool   `mapstructure:"secure_protocol"`
-	WriteTimeout           int    `mapstructure:"write_timeout"`
-	ReadTimeout            int    `mapstructure:"read_timeout"`
-	IdleTimeout            int    `mapstructure:"idle_timeout"`
-	ReadHeaderTimeout      int    `mapstructure:"read_header_timeout"`
-	CookieHashkey          string `mapstructure:"cookie_hashkey"`
-	WebsocketWriteWaitSec  int    `mapstructure:"websocket_write_wait_sec"`
-	WebsocketPingPeriodSec int    `mapstructure:"websocket_ping_period_sec"`
-	WebsocketPongWaitSec   int    `mapstructure:"websocket_pong_wait_sec"`
-	WebsocketSubdomain     string `mapstructure:"websocket_subdomain"`
+	Port                       string
+	SecureCookie               bool   `mapstructure:"secure_cookie"`
+	BackendCookieName          string `mapstructure:"backend_cookie_name"`
+	SessionCookieName          string `mapstructure:"session_cookie_name"`
+	FrontendCookieName         string `mapstructure:"frontend_cookie_name"`
+	AuthStateCookieName        string `mapstructure:"auth_state_cookie_name"`
+	Domain                     string
+	PathPrefix                 string   `mapstructure:"path_prefix"`
+	SecureProtocol             bool     `mapstructure:"secure_protocol"`
+	WriteTimeout               int      `mapstructure:"write_timeout"`
+	ReadTimeout                int      `mapstructure:"read_timeout"`
+	IdleTimeout                int      `mapstructure:"idle_timeout"`
+	ReadHeaderTimeout          int      `mapstructure:"read_header_timeout"`
+	CookieHashkey              string   `mapstructure:"cookie_hashkey"`
+	WebsocketWriteWaitSec      int      `mapstructure:"websocket_write_wait_sec"`
+	WebsocketPingPeriodSec     int      `mapstructure:"websocket_ping_period_sec"`
+	WebsocketPongWaitSec       int      `mapstructure:"websocket_pong_wait_sec"`
+	WebsocketSubdomain         string   `mapstructure:"websocket_subdomain"`
+	WebsocketShutdownGraceSec  int      `mapstructure:"websocket_shutdown_grace_sec"`
+	WebsocketReadBufferSize    int      `mapstructure:"websocket_read_buffer_size"`
+	WebsocketWriteBufferSize   int      `mapstructure:"websocket_write_buffer_size"`
+	ParticipantSyncIntervalSec int      `mapstructure:"participant_sync_interval_sec"`
+	MobileAppScheme            string   `mapstructure:"mobile_app_scheme"`
+	TrustedProxies             []string `mapstructure:"trusted_proxies"`
+	AdminIPAllowlist           []string `mapstructure:"admin_ip_allowlist"`
+}
+
+// Websocket buffer size limits in bytes
+const (
+	MinWebsocketBufferSize = 1024
+	MaxWebsocketBufferSize = 1024 * 1024
+)
+
+// ValidateWebsocketBufferSizes makes sure the websocket read and write buffer sizes are between 1KB and 1MB
+func (h Http) ValidateWebsocketBufferSizes() error {
+	buffers := []struct {
+		name string
+		size int
+	}{
+		{name: "websocket_read_buffer_size", size: h.WebsocketReadBufferSize},
+		{name: "websocket_write_buffer_size", size: h.WebsocketWriteBufferSize},
+	}
+	for _, buffer := range buffers {
+		if buffer.size < MinWebsocketBufferSize || buffer.size > MaxWebsocketBufferSize {
+			return fmt.Errorf("http %s must be between %d and %d bytes, got %d",
+				buffer.name, MinWebsocketBufferSize, MaxWebsocketBufferSize, buffer.size)
+		}
+	}
+
+	return nil
 }
 
 // Analytics is the application analytics configuration
@@ -49,12 +92,77 @@ type Admin struct {
 	Email string
 }
 
+// Redis is the application Redis cache configuration
+type Redis struct {
+	// CacheHitRateAlertThreshold is the cache hit rate percentage below which a warning is logged
+	CacheHitRateAlertThreshold float64 `mapstructure:"cache_hit_rate_alert_threshold"`
+}
+
+// Alerting is the application operational alerting configuration
+type Alerting struct {
+	// AlertmanagerURL is the Prometheus Alertmanager alerts API url, alerting is disabled when empty
+	AlertmanagerURL string `mapstructure:"alertmanager_url"`
+	// StaleGameHours is how long a poker game with active participants can go without activity before it's stale
+	StaleGameHours int `mapstructure:"stale_game_hours"`
+}
+
+// Cache is the application Redis cache TTL configuration
+type Cache struct {
+	// Disabled sets every cache TTL to 0, for testing environments
+	Disabled      bool
+	TTLGameHours  int `mapstructure:"ttl_game_hours"`
+	TTLStoryHours int `mapstructure:"ttl_story_hours"`
+	TTLUserHours  int `mapstructure:"ttl_user_hours"`
+	TTLTeamHours  int `mapstructure:"ttl_team_hours"`
+	TTLOrgHours   int `mapstructure:"ttl_org_hours"`
+}
+
+// TTLConfig resolves the cache TTL for each entity type
+func (c Cache) TTLConfig() thunderdome.CacheTTLConfig {
+	if c.Disabled {
+		return thunderdome.CacheTTLConfig{}
+	}
+
+	return thunderdome.CacheTTLConfig{
+		GameTTL:  time.Duration(c.TTLGameHours) * time.Hour,
+		StoryTTL: time.Duration(c.TTLStoryHours) * time.Hour,
+		UserTTL:  time.Duration(c.TTLUserHours) * time.Hour,
+		TeamTTL:  time.Duration(c.TTLTeamHours) * time.Hour,
+		OrgTTL:   time.Duration(c.TTLOrgHours) * time.Hour,
+	}
+}
+
 // Otel is the application OpenTelemetry configuration
 type Otel struct {
 	Enabled      bool
 	ServiceName  string `mapstructure:"service_name"`
 	CollectorUrl string `mapstructure:"collector_url"`
 	InsecureMode bool   `mapstructure:"insecure_mode"`
+	// SamplingStrategy is how traces are sampled, one of always, never, ratio or parent_based
+	SamplingStrategy string `mapstructure:"sampling_strategy"`
+	// SamplingRatio is the fraction of traces sampled by the ratio and parent_based strategies
+	SamplingRatio float64 `mapstructure:"sampling_ratio"`
+}
+
+// Sampler builds the trace sampler for the sampling strategy, the parent_based strategy follows the parent span's
+// sampling decision and samples root spans by ratio
+func (o Otel) Sampler() (sdktrace.Sampler, error) {
+	if o.SamplingRatio < 0 || o.SamplingRatio > 1 {
+		return nil, fmt.Errorf("otel sampling_ratio must be between 0.0 and 1.0, got %v", o.SamplingRatio)
+	}
+
+	switch o.SamplingStrategy {
+	case "", "always":
+		return sdktrace.AlwaysSample(), nil
+	case "never":
+		return sdktrace.NeverSample(), nil
+	case "ratio":
+		return sdktrace.TraceIDRatioBased(o.SamplingRatio), nil
+	case "parent_based":
+		return sdktrace.ParentBased(sdktrace.TraceIDRatioBased(o.SamplingRatio)), nil
+	}
+
+	return nil, fmt.Errorf("otel sampling_strategy %q must be one of always, never, ratio or parent_based", o.SamplingStrategy)
 }
 
 // Db is the application database configuration
@@ -65,9 +173,34 @@ type Db struct {
 	Pass            string
 	Name            string
 	Sslmode         string
-	MaxOpenConns    int `mapstructure:"max_open_conns"`
-	MaxIdleConns    int `mapstructure:"max_idle_conns"`
-	ConnMaxLifetime int `mapstructure:"conn_max_lifetime"`
+	MaxOpenConns    int    `mapstructure:"max_open_conns"`
+	MaxIdleConns    int    `mapstructure:"max_idle_conns"`
+	ConnMaxLifetime int    `mapstructure:"conn_max_lifetime"`
+	ReadReplicaHost string `mapstructure:"read_replica_host"`
+	ReadReplicaPort int    `mapstructure:"read_replica_port"`
+	ReadReplicaUser string `mapstructure:"read_replica_user"`
+	ReadReplicaPass string `mapstructure:"read_replica_pass"`
+}
+
+// Storage is the application story attachment storage configuration
+type Storage struct {
+	Provider         string
+	Bucket           string
+	Region           string
+	Endpoint         string
+	AccessKeyID      string `mapstructure:"access_key_id"`
+	SecretAccessKey  string `mapstructure:"secret_access_key"`
+	LocalPath        string `mapstructure:"local_path"`
+	URLExpiryMinutes int    `mapstructure:"url_expiry_minutes"`
+	MaxUploadSizeMB  int    `mapstructure:"max_upload_size_mb"`
+}
+
+// Github is the application GitHub pull request integration configuration
+type Github struct {
+	// APIURL is the GitHub REST API url, set to a GitHub Enterprise server's API url to use it instead
+	APIURL string `mapstructure:"api_url"`
+	// AllowedRepos are the owner/name repositories game results can be posted to, empty allows every repository
+	AllowedRepos []string `mapstructure:"allowed_repos"`
 }
 
 // Smtp is the application SMTP configuration
@@ -86,6 +219,7 @@ type Smtp struct {
 // AppConfig is the application configuration
 type AppConfig struct {
 	AesHashkey                  string   `mapstructure:"aes_hashkey"`
+	IPHashSalt                  string   `mapstructure:"ip_hash_salt"`
 	AllowedPointValues          []string `mapstructure:"allowedPointValues"`
 	DefaultPointValues          []string `mapstructure:"defaultPointValues"`
 	ShowWarriorRank             bool     `mapstructure:"show_warrior_rank"`
@@ -94,6 +228,8 @@ type AppConfig struct {
 	AllowGuests                 bool     `mapstructure:"allow_guests"`
 	AllowRegistration           bool     `mapstructure:"allow_registration"`
 	AllowJiraImport             bool     `mapstructure:"allow_jira_import"`
+	AllowAsanaImport            bool     `mapstructure:"allow_asana_import"`
+	AllowNotionImport           bool     `mapstructure:"allow_notion_import"`
 	AllowCsvImport              bool     `mapstructure:"allow_csv_import"`
 	DefaultLocale               string   `mapstructure:"default_locale"`
 	AllowExternalApi            bool     `mapstructure:"allow_external_api"`
@@ -104,11 +240,18 @@ type AppConfig struct {
 	CleanupGuestsDaysOld        int      `mapstructure:"cleanup_guests_days_old"`
 	CleanupRetrosDaysOld        int      `mapstructure:"cleanup_retros_days_old"`
 	CleanupStoryboardsDaysOld   int      `mapstructure:"cleanup_storyboards_days_old"`
+	CleanupNotificationsDaysOld int      `mapstructure:"cleanup_notifications_days_old"`
 	OrganizationsEnabled        bool     `mapstructure:"organizations_enabled"`
 	RequireTeams                bool     `mapstructure:"require_teams"`
 	SubscriptionsEnabled        bool     `mapstructure:"subscriptions_enabled"`
 	RetroDefaultTemplateID      string   `mapstructure:"retro_default_template_id"`
 	DefaultPointAverageRounding string   `mapstructure:"default_point_average_rounding"`
+	ImportDeduplicationEnabled  bool     `mapstructure:"import_deduplication_enabled"`
+	WsIdleTimeoutMinutes        int      `mapstructure:"ws_idle_timeout_minutes"`
+	RequireRegistrationApproval bool     `mapstructure:"require_registration_approval"`
+	OrgBulkImportEnabled        bool     `mapstructure:"org_bulk_import_enabled"`
+	// ActivityScoreWeights are the points each activity adds to a team member's activity score
+	ActivityScoreWeights thunderdome.ActivityScoreWeights `mapstructure:"activity_score_weights"`
 }
 
 // Feature is the application feature enablement configuration
@@ -127,10 +270,24 @@ type Google struct {
 
 // Auth is the application authentication configuration
 type Auth struct {
-	Method string
-	Ldap   AuthLdap
-	Header AuthHeader
+	Method   string
+	Ldap     AuthLdap
+	Header   AuthHeader
+	Password thunderdome.PasswordPolicy
 	Google
+	SAML AuthSAML `mapstructure:"saml"`
+}
+
+// AuthSAML is the application SAML 2.0 authentication configuration
+type AuthSAML struct {
+	IDPURL              string `mapstructure:"idp_url"`
+	IDPMetadataURL      string `mapstructure:"idp_metadata_url"`
+	SPEntityID          string `mapstructure:"sp_entity_id"`
+	SPCallbackURL       string `mapstructure:"sp_callback_url"`
+	CertFile            string `mapstructure:"cert_file"`
+	KeyFile             string `mapstructure:"key_file"`
+	GroupAttribute      string `mapstructure:"group_attribute"`
+	GroupOrganizationID string `mapstructure:"group_organization_id"`
 }
 
 // AuthHeader is the application authentication header configuration
@@ -1,6 +1,7 @@
 package cookie
 
 import (
+	"crypto/subtle"
 	"fmt"
 	"net/http"
 	"time"
@@ -21,7 +22,7 @@ func (s *CookieService) ValidateAuthStateCookie(w http.ResponseWriter, r *http.R
 
 	_ = s.DeleteAuthStateCookie(w)
 
-	if cookieVal != state {
+	if state == "" || subtle.ConstantTimeCompare([]byte(cookieVal), []byte(state)) != 1 {
 		return fmt.Errorf("INVALID_AUTH_STATE")
 	}
 
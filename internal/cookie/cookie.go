@@ -20,6 +20,11 @@ func New(config Config) *CookieService {
 
 // CreateCookie creates a secure cookie with given cookieName
 func (s *CookieService) CreateCookie(w http.ResponseWriter, cookieName string, value string, maxAge int) error {
+	return s.createSecureCookie(w, cookieName, value, maxAge)
+}
+
+// createSecureCookie creates a secure cookie with given cookieName holding the encoded value
+func (s *CookieService) createSecureCookie(w http.ResponseWriter, cookieName string, value any, maxAge int) error {
 	encoded, err := s.sc.Encode(cookieName, value)
 	if err != nil {
 		return err
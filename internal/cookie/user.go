@@ -40,9 +40,19 @@ func (s *CookieService) CreateUserCookie(w http.ResponseWriter, userID string) e
 	return s.CreateCookie(w, s.config.SecureCookieName, userID, int(time.Hour.Seconds()*24*365))
 }
 
+// sessionCookieValue is the session cookie value, IssuedAt (unix nanoseconds) is compared against
+// the time the user's sessions were last invalidated
+type sessionCookieValue struct {
+	SessionID string
+	IssuedAt  int64
+}
+
 // CreateSessionCookie creates the user's session CookieService
 func (s *CookieService) CreateSessionCookie(w http.ResponseWriter, sessionID string) error {
-	return s.CreateCookie(w, s.config.SessionCookieName, sessionID, int(time.Hour.Seconds()*24*30))
+	return s.createSecureCookie(w, s.config.SessionCookieName, sessionCookieValue{
+		SessionID: sessionID,
+		IssuedAt:  time.Now().UnixNano(),
+	}, int(time.Hour.Seconds()*24*30))
 }
 
 // CreateUserUICookie creates the user's frontend UI cookie
@@ -87,5 +97,28 @@ func (s *CookieService) ValidateUserCookie(w http.ResponseWriter, r *http.Reques
 
 // ValidateSessionCookie returns the SessionID from secure cookies or errors if failures getting it
 func (s *CookieService) ValidateSessionCookie(w http.ResponseWriter, r *http.Request) (string, error) {
-	return s.GetCookie(w, r, s.config.SessionCookieName)
+	sessionID, _, err := s.ValidateSessionCookieIssuedAt(w, r)
+	return sessionID, err
+}
+
+// ValidateSessionCookieIssuedAt returns the SessionID and when the session cookie was issued from secure cookies
+// or errors if failures getting it, session cookies created before the issued at was added have a zero issued at
+func (s *CookieService) ValidateSessionCookieIssuedAt(w http.ResponseWriter, r *http.Request) (string, time.Time, error) {
+	cookie, err := r.Cookie(s.config.SessionCookieName)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("COOKIE_NOT_FOUND")
+	}
+
+	var value sessionCookieValue
+	if err := s.sc.Decode(s.config.SessionCookieName, cookie.Value, &value); err == nil && value.SessionID != "" {
+		return value.SessionID, time.Unix(0, value.IssuedAt), nil
+	}
+
+	var sessionID string
+	if err := s.sc.Decode(s.config.SessionCookieName, cookie.Value, &sessionID); err != nil {
+		s.DeleteCookie(w, s.config.SessionCookieName)
+		return "", time.Time{}, fmt.Errorf("INVALID_COOKIE")
+	}
+
+	return sessionID, time.Time{}, nil
 }
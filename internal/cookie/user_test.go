@@ -0,0 +1,82 @@
+package cookie
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func testCookieService() *CookieService {
+	return New(Config{CookieHashKey: "test-cookie-hash-key", SessionCookieName: "session"})
+}
+
+// requestWithCookies makes a request carrying the cookies set on the recorder
+func requestWithCookies(rr *httptest.ResponseRecorder) *http.Request {
+	req := httptest.NewRequest("GET", "/", nil)
+	for _, c := range rr.Result().Cookies() {
+		req.AddCookie(c)
+	}
+
+	return req
+}
+
+// TestSessionCookieIssuedAt makes sure the session cookie returns the session ID and when it was issued
+func TestSessionCookieIssuedAt(t *testing.T) {
+	s := testCookieService()
+	before := time.Now()
+
+	rr := httptest.NewRecorder()
+	if err := s.CreateSessionCookie(rr, "session-id"); err != nil {
+		t.Fatalf("expected the session cookie to be created, got %v", err)
+	}
+
+	sessionID, issuedAt, err := s.ValidateSessionCookieIssuedAt(httptest.NewRecorder(), requestWithCookies(rr))
+	if err != nil {
+		t.Fatalf("expected the session cookie to be valid, got %v", err)
+	}
+	if sessionID != "session-id" {
+		t.Errorf("expected session-id, got %s", sessionID)
+	}
+	if issuedAt.Before(before) || issuedAt.After(time.Now()) {
+		t.Errorf("expected the issued at to be when the cookie was created, got %v", issuedAt)
+	}
+
+	sessionID, err = s.ValidateSessionCookie(httptest.NewRecorder(), requestWithCookies(rr))
+	if err != nil || sessionID != "session-id" {
+		t.Errorf("expected session-id, got %s %v", sessionID, err)
+	}
+}
+
+// TestSessionCookieLegacy makes sure session cookies without an issued at are still read
+func TestSessionCookieLegacy(t *testing.T) {
+	s := testCookieService()
+
+	rr := httptest.NewRecorder()
+	if err := s.CreateCookie(rr, "session", "legacy-session-id", 60); err != nil {
+		t.Fatalf("expected the session cookie to be created, got %v", err)
+	}
+
+	sessionID, issuedAt, err := s.ValidateSessionCookieIssuedAt(httptest.NewRecorder(), requestWithCookies(rr))
+	if err != nil {
+		t.Fatalf("expected the legacy session cookie to be valid, got %v", err)
+	}
+	if sessionID != "legacy-session-id" || !issuedAt.IsZero() {
+		t.Errorf("expected the legacy session ID with no issued at, got %s %v", sessionID, issuedAt)
+	}
+}
+
+// TestSessionCookieInvalid makes sure tampered session cookies are rejected
+func TestSessionCookieInvalid(t *testing.T) {
+	s := testCookieService()
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: "tampered"})
+	if _, _, err := s.ValidateSessionCookieIssuedAt(httptest.NewRecorder(), req); err == nil || err.Error() != "INVALID_COOKIE" {
+		t.Errorf("expected INVALID_COOKIE, got %v", err)
+	}
+
+	if _, _, err := s.ValidateSessionCookieIssuedAt(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil)); err == nil || err.Error() != "COOKIE_NOT_FOUND" {
+		t.Errorf("expected COOKIE_NOT_FOUND, got %v", err)
+	}
+}
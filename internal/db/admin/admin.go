@@ -5,7 +5,9 @@ import (
 	"database/sql"
 	"fmt"
 
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/db"
 	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/redis/go-redis/v9"
 	"github.com/uptrace/opentelemetry-go-extra/otelzap"
 )
 
@@ -13,6 +15,9 @@ import (
 type Service struct {
 	DB     *sql.DB
 	Logger *otelzap.Logger
+	Redis  *redis.Client
+	// CacheTTL is how long organization reports are cached, a zero TTL disables the cache
+	CacheTTL thunderdome.CacheTTLConfig
 }
 
 // GetAppStats gets counts of common application metrics such as users and poker games
@@ -32,7 +37,7 @@ func (d *Service) GetAppStats(ctx context.Context) (*thunderdome.ApplicationStat
     (SELECT COUNT(DISTINCT poker_id) FROM thunderdome.poker_user WHERE active IS true) AS active_poker_count,
     (SELECT COUNT(user_id) FROM thunderdome.poker_user WHERE active IS true) AS active_poker_user_count,
     (SELECT COUNT(*) FROM thunderdome.team_checkin) AS team_checkins_count,
-    (SELECT COUNT(*) FROM thunderdome.retro) AS retro_count,
+    (SELECT COUNT(*) FROM thunderdome.retro WHERE deleted_at IS NULL) AS retro_count,
     (SELECT COUNT(DISTINCT retro_id) FROM thunderdome.retro_user WHERE active IS true) AS active_retro_count,
     (SELECT COUNT(user_id) FROM thunderdome.retro_user WHERE active IS true) AS active_retro_user_count,
     (SELECT COUNT(*) FROM thunderdome.retro_item) AS retro_item_count,
@@ -98,3 +103,8 @@ func (d *Service) GetAppStats(ctx context.Context) (*thunderdome.ApplicationStat
 
 	return &appStats, nil
 }
+
+// GetMigrationStatus gets the database migration status, including how many migrations are pending
+func (d *Service) GetMigrationStatus(ctx context.Context) (*thunderdome.MigrationStatus, error) {
+	return db.GetMigrationStatus(ctx, d.DB)
+}
@@ -0,0 +1,76 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// anonymizeUserStatement clears the user's ($1) identifying profile fields replacing the name ($2) and email ($3),
+// game and team participation rows keep referencing the user so the games history stays intact
+const anonymizeUserStatement = `UPDATE thunderdome.users SET name = $2, email = $3, avatar = '', picture = NULL,
+		country = NULL, company = NULL, job_title = NULL, anonymized_at = NOW(), updated_date = NOW()
+		WHERE id = $1;`
+
+// anonymizeCredentialStatement replaces the user's ($1) login email with the anonymized email ($2)
+// and removes the password so the account can no longer be logged into
+const anonymizeCredentialStatement = `UPDATE thunderdome.auth_credential SET email = $2, password = NULL,
+		verified = false, mfa_enabled = false, updated_date = NOW()
+		WHERE user_id = $1;`
+
+// anonymizeUserDeleteStatements remove the user's ($1) remaining identifying data and ways to authenticate
+var anonymizeUserDeleteStatements = []struct {
+	name  string
+	query string
+}{
+	{name: "identities", query: `DELETE FROM thunderdome.auth_identity WHERE user_id = $1;`},
+	{name: "mfa", query: `DELETE FROM thunderdome.user_mfa WHERE user_id = $1;`},
+	{name: "resets", query: `DELETE FROM thunderdome.user_reset WHERE user_id = $1;`},
+	{name: "verifications", query: `DELETE FROM thunderdome.user_verify WHERE user_id = $1;`},
+	{name: "api keys", query: `DELETE FROM thunderdome.api_key WHERE user_id = $1;`},
+	{name: "sessions", query: `DELETE FROM thunderdome.user_session WHERE user_id = $1;`},
+}
+
+// AnonymizeUser replaces the user's name and email with anonymized values and clears the rest of their profile
+// for privacy compliance requests, the user's games keep referencing them showing them as anonymous.
+// The user's login email is anonymized and their identities, API keys and sessions deleted.
+// The anonymization can't be undone.
+func (d *Service) AnonymizeUser(ctx context.Context, userID string) error {
+	user := &thunderdome.User{ID: userID}
+	thunderdome.AnonymizeUser(user)
+
+	tx, err := d.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("anonymize user begin transaction error: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	result, err := tx.ExecContext(ctx, anonymizeUserStatement, userID, user.Name, user.Email)
+	if err != nil {
+		return fmt.Errorf("anonymize user query error: %v", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("anonymize user rows error: %v", err)
+	}
+	if rows != 1 {
+		return fmt.Errorf("USER_NOT_FOUND")
+	}
+
+	if _, err := tx.ExecContext(ctx, anonymizeCredentialStatement, userID, user.Email); err != nil {
+		return fmt.Errorf("anonymize user credential query error: %v", err)
+	}
+
+	for _, statement := range anonymizeUserDeleteStatements {
+		if _, err := tx.ExecContext(ctx, statement.query, userID); err != nil {
+			return fmt.Errorf("anonymize user delete %s query error: %v", statement.name, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("anonymize user commit error: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,236 @@
+package admin
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// recordingDB is an in memory stand in for the database recording each statement executed
+// and whether the transaction was committed
+type recordingDB struct {
+	mu sync.Mutex
+	// users is how many users rows the users update affects
+	users int64
+	// failOn makes statements containing it fail
+	failOn     string
+	statements []recordedStatement
+	committed  bool
+	rolledBack bool
+}
+
+type recordedStatement struct {
+	query string
+	args  []driver.Value
+	inTx  bool
+}
+
+var (
+	recordingDBs   = make(map[string]*recordingDB)
+	recordingDBsMu sync.Mutex
+)
+
+func init() {
+	sql.Register("anonymize-recording", recordingDriver{})
+}
+
+// openRecordingDB opens a sql.DB backed by the recordingDB
+func openRecordingDB(t *testing.T, rdb *recordingDB) *sql.DB {
+	t.Helper()
+
+	recordingDBsMu.Lock()
+	recordingDBs[t.Name()] = rdb
+	recordingDBsMu.Unlock()
+
+	db, err := sql.Open("anonymize-recording", t.Name())
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	return db
+}
+
+type recordingDriver struct{}
+
+func (recordingDriver) Open(name string) (driver.Conn, error) {
+	recordingDBsMu.Lock()
+	defer recordingDBsMu.Unlock()
+
+	return &recordingConn{db: recordingDBs[name]}, nil
+}
+
+type recordingConn struct {
+	db   *recordingDB
+	inTx bool
+}
+
+func (c *recordingConn) Prepare(query string) (driver.Stmt, error) {
+	return &recordingStmt{conn: c, query: query}, nil
+}
+
+func (c *recordingConn) Close() error { return nil }
+
+func (c *recordingConn) Begin() (driver.Tx, error) {
+	c.inTx = true
+	return &recordingTx{conn: c}, nil
+}
+
+type recordingTx struct {
+	conn *recordingConn
+}
+
+func (tx *recordingTx) Commit() error {
+	tx.conn.inTx = false
+	tx.conn.db.mu.Lock()
+	defer tx.conn.db.mu.Unlock()
+	tx.conn.db.committed = true
+	return nil
+}
+
+func (tx *recordingTx) Rollback() error {
+	tx.conn.inTx = false
+	tx.conn.db.mu.Lock()
+	defer tx.conn.db.mu.Unlock()
+	tx.conn.db.rolledBack = true
+	return nil
+}
+
+type recordingStmt struct {
+	conn  *recordingConn
+	query string
+}
+
+func (s *recordingStmt) Close() error  { return nil }
+func (s *recordingStmt) NumInput() int { return -1 }
+
+func (s *recordingStmt) Exec(args []driver.Value) (driver.Result, error) {
+	db := s.conn.db
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if db.failOn != "" && strings.Contains(s.query, db.failOn) {
+		return nil, errors.New("statement failed")
+	}
+	db.statements = append(db.statements, recordedStatement{query: s.query, args: args, inTx: s.conn.inTx})
+
+	if strings.Contains(s.query, "UPDATE thunderdome.users") {
+		return driver.RowsAffected(db.users), nil
+	}
+	return driver.RowsAffected(1), nil
+}
+
+// Query returns the users being locked by id, other queries aren't supported
+func (s *recordingStmt) Query(args []driver.Value) (driver.Rows, error) {
+	if !strings.Contains(s.query, "FROM thunderdome.users") {
+		return nil, errors.New("queries aren't supported")
+	}
+
+	rows := &recordingRows{}
+	for _, id := range args {
+		rows.values = append(rows.values, []driver.Value{id, ""})
+	}
+	return rows, nil
+}
+
+type recordingRows struct {
+	values [][]driver.Value
+}
+
+func (r *recordingRows) Columns() []string { return []string{"id", "merged_into_user_id"} }
+func (r *recordingRows) Close() error      { return nil }
+
+func (r *recordingRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+// touches returns the statement run against the table or nil
+func (r *recordingDB) touches(table string) *recordedStatement {
+	for i, statement := range r.statements {
+		if strings.Contains(statement.query, "thunderdome."+table+" ") {
+			return &r.statements[i]
+		}
+	}
+	return nil
+}
+
+// TestAnonymizeUserRemovesIdentifyingData makes sure the login email, identities, API keys and sessions
+// are anonymized or deleted in the same transaction as the profile while game participation is left alone
+func TestAnonymizeUserRemovesIdentifyingData(t *testing.T) {
+	rdb := &recordingDB{users: 1}
+	d := &Service{DB: openRecordingDB(t, rdb)}
+
+	if err := d.AnonymizeUser(context.Background(), "user-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !rdb.committed {
+		t.Fatal("expected the anonymization to be committed")
+	}
+
+	anonymized := &thunderdome.User{ID: "user-1"}
+	thunderdome.AnonymizeUser(anonymized)
+
+	credential := rdb.touches("auth_credential")
+	if credential == nil {
+		t.Fatal("expected the login credential to be anonymized")
+	}
+	if credential.args[1] != anonymized.Email {
+		t.Errorf("expected the login email to be replaced with %s, got %v", anonymized.Email, credential.args[1])
+	}
+	for _, table := range []string{"users", "auth_identity", "user_mfa", "api_key", "user_session"} {
+		statement := rdb.touches(table)
+		if statement == nil {
+			t.Errorf("expected %s to be anonymized or deleted", table)
+			continue
+		}
+		if !statement.inTx {
+			t.Errorf("expected %s to be changed in the anonymization transaction", table)
+		}
+		if statement.args[0] != "user-1" {
+			t.Errorf("expected %s to be changed for user-1, got %v", table, statement.args[0])
+		}
+	}
+	for _, table := range []string{"poker_user", "retro_user", "storyboard_user", "team_user"} {
+		if rdb.touches(table) != nil {
+			t.Errorf("expected %s to be left alone", table)
+		}
+	}
+}
+
+// TestAnonymizeUserRollsBack makes sure nothing is committed when removing the user's data fails
+func TestAnonymizeUserRollsBack(t *testing.T) {
+	rdb := &recordingDB{users: 1, failOn: "thunderdome.api_key "}
+	d := &Service{DB: openRecordingDB(t, rdb)}
+
+	if err := d.AnonymizeUser(context.Background(), "user-1"); err == nil {
+		t.Fatal("expected an error when deleting the API keys fails")
+	}
+	if rdb.committed || !rdb.rolledBack {
+		t.Error("expected the anonymization to be rolled back")
+	}
+}
+
+// TestAnonymizeUserNotFound makes sure an unknown user isn't anonymized
+func TestAnonymizeUserNotFound(t *testing.T) {
+	rdb := &recordingDB{users: 0}
+	d := &Service{DB: openRecordingDB(t, rdb)}
+
+	if err := d.AnonymizeUser(context.Background(), "user-1"); err == nil || err.Error() != "USER_NOT_FOUND" {
+		t.Fatalf("expected USER_NOT_FOUND, got %v", err)
+	}
+	if rdb.committed || rdb.touches("api_key") != nil {
+		t.Error("expected nothing to change for an unknown user")
+	}
+}
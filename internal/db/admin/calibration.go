@@ -0,0 +1,184 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+// calibrationCacheTTL is long as the report covers weeks of history that rarely changes
+const calibrationCacheTTL = 4 * time.Hour
+
+// calibrationTrendThreshold is the weekly change in consensus rate below which the trend is considered steady
+const calibrationTrendThreshold = 0.01
+
+// calibrationStory is a finalized story's points along with how its voting went
+type calibrationStory struct {
+	Points      string
+	Rounds      int
+	EstimatedAt time.Time
+	Consensus   bool
+}
+
+// GetEstimationCalibration gets the organization's estimation calibration report for stories estimated since the time
+func (d *Service) GetEstimationCalibration(ctx context.Context, orgID string, since time.Time) (*thunderdome.CalibrationReport, error) {
+	cacheKey := calibrationCacheKey(orgID, since)
+	ttl := thunderdome.CappedTTL(d.CacheTTL.OrgTTL, calibrationCacheTTL)
+	if d.Redis != nil && ttl > 0 {
+		if cachedData, err := d.Redis.Get(ctx, cacheKey).Result(); err == nil {
+			var report thunderdome.CalibrationReport
+			if err := json.Unmarshal([]byte(cachedData), &report); err == nil {
+				d.Logger.Ctx(ctx).Debug("Estimation calibration cache hit", zap.String("organization_id", orgID))
+				return &report, nil
+			}
+		}
+	}
+
+	stories := make([]calibrationStory, 0)
+	rows, err := d.DB.QueryContext(ctx,
+		`WITH org_games AS (
+			SELECT p.id
+			FROM thunderdome.poker p
+			JOIN thunderdome.team t ON t.id = p.team_id
+			LEFT JOIN thunderdome.organization_department od ON od.id = t.department_id
+			WHERE COALESCE(t.organization_id, od.organization_id) = $1
+		), story_rounds AS (
+			SELECT vr.story_id, COUNT(*) AS rounds, MIN(vr.round) AS first_round, MAX(vr.voteend_time) AS estimated_at
+			FROM thunderdome.poker_story_vote_round vr
+			WHERE vr.poker_id IN (SELECT id FROM org_games)
+			GROUP BY vr.story_id
+		), first_round_votes AS (
+			SELECT vr.story_id,
+				COUNT(DISTINCT v.value->>'vote') FILTER (
+					WHERE COALESCE(v.value->>'vote', '') NOT IN ('', '?', '☕️', '☕')
+				) AS distinct_votes
+			FROM thunderdome.poker_story_vote_round vr
+			JOIN story_rounds sr ON sr.story_id = vr.story_id AND sr.first_round = vr.round
+			LEFT JOIN LATERAL jsonb_array_elements(vr.votes) v ON TRUE
+			GROUP BY vr.story_id
+		)
+		SELECT ps.points, sr.rounds, sr.estimated_at, COALESCE(frv.distinct_votes, 0) = 1 AS consensus
+		FROM thunderdome.poker_story ps
+		JOIN story_rounds sr ON sr.story_id = ps.id
+		LEFT JOIN first_round_votes frv ON frv.story_id = ps.id
+		WHERE ps.points <> '' AND sr.estimated_at >= $2
+		ORDER BY sr.estimated_at;`,
+		orgID, since,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get estimation calibration query error: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var s calibrationStory
+		if err := rows.Scan(&s.Points, &s.Rounds, &s.EstimatedAt, &s.Consensus); err != nil {
+			d.Logger.Ctx(ctx).Error("get estimation calibration query scan error", zap.Error(err))
+			continue
+		}
+		stories = append(stories, s)
+	}
+
+	report := computeCalibrationReport(stories)
+	report.OrganizationID = orgID
+	report.Since = since
+
+	if d.Redis != nil && ttl > 0 {
+		if reportJSON, err := json.Marshal(report); err == nil {
+			if err := d.Redis.Set(ctx, cacheKey, reportJSON, ttl).Err(); err != nil {
+				d.Logger.Ctx(ctx).Error("Failed to set estimation calibration cache", zap.Error(err),
+					zap.String("organization_id", orgID))
+			}
+		}
+	}
+
+	return report, nil
+}
+
+func calibrationCacheKey(orgID string, since time.Time) string {
+	return fmt.Sprintf("org:estimation-calibration:%s:%s", orgID, since.UTC().Format(time.DateOnly))
+}
+
+// computeCalibrationReport summarizes the finalized stories scale usage and weekly consensus rate
+func computeCalibrationReport(stories []calibrationStory) *thunderdome.CalibrationReport {
+	report := &thunderdome.CalibrationReport{
+		ScaleUsageDistribution:   make(map[string]int),
+		AverageRoundsToConsensus: make(map[string]float64),
+		ConsensusVelocityTrend:   make([]*thunderdome.ConsensusWeek, 0),
+		TrendDirection:           thunderdome.CalibrationTrendSteady,
+	}
+
+	pointRounds := make(map[string]int)
+	weeks := make(map[time.Time]*thunderdome.ConsensusWeek)
+	weekConsensus := make(map[time.Time]int)
+	for _, s := range stories {
+		report.ScaleUsageDistribution[s.Points]++
+		pointRounds[s.Points] += s.Rounds
+
+		weekStart := startOfWeek(s.EstimatedAt)
+		week, ok := weeks[weekStart]
+		if !ok {
+			week = &thunderdome.ConsensusWeek{WeekStart: weekStart}
+			weeks[weekStart] = week
+			report.ConsensusVelocityTrend = append(report.ConsensusVelocityTrend, week)
+		}
+		week.Stories++
+		if s.Consensus {
+			weekConsensus[weekStart]++
+		}
+	}
+
+	for points, count := range report.ScaleUsageDistribution {
+		report.AverageRoundsToConsensus[points] = float64(pointRounds[points]) / float64(count)
+	}
+
+	slices.SortFunc(report.ConsensusVelocityTrend, func(a, b *thunderdome.ConsensusWeek) int {
+		return a.WeekStart.Compare(b.WeekStart)
+	})
+	rates := make([]float64, 0, len(report.ConsensusVelocityTrend))
+	for _, week := range report.ConsensusVelocityTrend {
+		week.ConsensusRate = float64(weekConsensus[week.WeekStart]) / float64(week.Stories)
+		rates = append(rates, week.ConsensusRate)
+	}
+
+	slope := trendSlope(rates)
+	if slope > calibrationTrendThreshold {
+		report.TrendDirection = thunderdome.CalibrationTrendImproving
+	} else if slope < -calibrationTrendThreshold {
+		report.TrendDirection = thunderdome.CalibrationTrendDeclining
+	}
+
+	return report
+}
+
+// startOfWeek gets the start of the (Monday first) UTC week the time falls in
+func startOfWeek(t time.Time) time.Time {
+	t = t.UTC()
+	daysSinceMonday := (int(t.Weekday()) + 6) % 7
+
+	return time.Date(t.Year(), t.Month(), t.Day()-daysSinceMonday, 0, 0, 0, 0, time.UTC)
+}
+
+// trendSlope gets the least squares slope of the values over their index
+func trendSlope(values []float64) float64 {
+	n := float64(len(values))
+	if n < 2 {
+		return 0
+	}
+
+	var sumX, sumY, sumXY, sumXX float64
+	for i, y := range values {
+		x := float64(i)
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+
+	return (n*sumXY - sumX*sumY) / (n*sumXX - sumX*sumX)
+}
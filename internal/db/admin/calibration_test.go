@@ -0,0 +1,100 @@
+package admin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// seedCalibrationWeeks creates four finalized stories a week for six weeks starting on a Monday,
+// with consensusPerWeek stories reaching consensus in their first round each week
+func seedCalibrationWeeks(consensusPerWeek []int) []calibrationStory {
+	start := time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC)
+	points := []string{"1", "3", "5", "8"}
+
+	stories := make([]calibrationStory, 0)
+	for week, consensus := range consensusPerWeek {
+		for i, p := range points {
+			rounds := 1
+			if i >= consensus {
+				rounds = 3
+			}
+			stories = append(stories, calibrationStory{
+				Points:      p,
+				Rounds:      rounds,
+				EstimatedAt: start.AddDate(0, 0, week*7+i),
+				Consensus:   i < consensus,
+			})
+		}
+	}
+
+	return stories
+}
+
+// TestComputeCalibrationReportTrend makes sure the weekly consensus rate trend direction follows the seeded data
+func TestComputeCalibrationReportTrend(t *testing.T) {
+	tests := []struct {
+		name      string
+		consensus []int
+		expected  string
+	}{
+		{name: "improving", consensus: []int{0, 1, 1, 2, 3, 4}, expected: thunderdome.CalibrationTrendImproving},
+		{name: "declining", consensus: []int{4, 4, 3, 2, 1, 0}, expected: thunderdome.CalibrationTrendDeclining},
+		{name: "steady", consensus: []int{2, 2, 2, 2, 2, 2}, expected: thunderdome.CalibrationTrendSteady},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := computeCalibrationReport(seedCalibrationWeeks(tt.consensus))
+
+			if report.TrendDirection != tt.expected {
+				t.Errorf("expected trend %s, got %s", tt.expected, report.TrendDirection)
+			}
+			if len(report.ConsensusVelocityTrend) != 6 {
+				t.Fatalf("expected 6 weeks, got %d", len(report.ConsensusVelocityTrend))
+			}
+			for i, week := range report.ConsensusVelocityTrend {
+				expectedRate := float64(tt.consensus[i]) / 4
+				if week.Stories != 4 || week.ConsensusRate != expectedRate {
+					t.Errorf("expected week %d to have 4 stories at %v consensus, got %d at %v",
+						i, expectedRate, week.Stories, week.ConsensusRate)
+				}
+				if week.WeekStart.Weekday() != time.Monday {
+					t.Errorf("expected week %d to start on a Monday, got %s", i, week.WeekStart.Weekday())
+				}
+			}
+		})
+	}
+}
+
+// TestComputeCalibrationReportScaleUsage makes sure scale values usage and average rounds are tallied per final points
+func TestComputeCalibrationReportScaleUsage(t *testing.T) {
+	report := computeCalibrationReport(seedCalibrationWeeks([]int{0, 1, 1, 2, 3, 4}))
+
+	for _, points := range []string{"1", "3", "5", "8"} {
+		if report.ScaleUsageDistribution[points] != 6 {
+			t.Errorf("expected %s to be used 6 times, got %d", points, report.ScaleUsageDistribution[points])
+		}
+	}
+
+	// "1" reached consensus in 5 of 6 weeks, "8" only in the last week
+	if got := report.AverageRoundsToConsensus["1"]; got != float64(5*1+3)/6 {
+		t.Errorf("expected 1 to average %v rounds, got %v", float64(5*1+3)/6, got)
+	}
+	if got := report.AverageRoundsToConsensus["8"]; got != float64(1+5*3)/6 {
+		t.Errorf("expected 8 to average %v rounds, got %v", float64(1+5*3)/6, got)
+	}
+}
+
+// TestComputeCalibrationReportEmpty makes sure an organization without finalized stories gets an empty steady report
+func TestComputeCalibrationReportEmpty(t *testing.T) {
+	report := computeCalibrationReport(nil)
+
+	if len(report.ConsensusVelocityTrend) != 0 || len(report.ScaleUsageDistribution) != 0 {
+		t.Errorf("expected an empty report, got %+v", report)
+	}
+	if report.TrendDirection != thunderdome.CalibrationTrendSteady {
+		t.Errorf("expected steady trend, got %s", report.TrendDirection)
+	}
+}
@@ -0,0 +1,151 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+// cleanupBatchSize is the number of games deleted per statement to avoid long-held locks
+const cleanupBatchSize = 100
+
+// CleanupOldGames deletes poker games with no activity in {daysOld} days in batches,
+// in dry run mode nothing is deleted and the result shows the games that would be affected
+func (d *Service) CleanupOldGames(ctx context.Context, daysOld int, dryRun bool) (*thunderdome.CleanupResult, error) {
+	result := &thunderdome.CleanupResult{
+		DryRun:  dryRun,
+		GameIDs: make([]string, 0),
+	}
+	cutoff := time.Now().AddDate(0, 0, -daysOld)
+
+	if dryRun {
+		rows, err := d.DB.QueryContext(ctx,
+			`SELECT p.id, (SELECT COUNT(*) FROM thunderdome.poker_story ps WHERE ps.poker_id = p.id)
+			FROM thunderdome.poker p
+			WHERE p.last_active < $1
+			ORDER BY p.last_active;`,
+			cutoff,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("cleanup old games dry run query error: %v", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var gameID string
+			var storyCount int
+			if err := rows.Scan(&gameID, &storyCount); err != nil {
+				return nil, fmt.Errorf("cleanup old games dry run query scan error: %v", err)
+			}
+			result.GameIDs = append(result.GameIDs, gameID)
+			result.StoryCount += storyCount
+		}
+		result.GameCount = len(result.GameIDs)
+
+		return result, nil
+	}
+
+	for {
+		batchIDs, storyCount, err := d.deleteOldGamesBatch(ctx, cutoff)
+		if err != nil {
+			return nil, err
+		}
+		if len(batchIDs) > 0 {
+			result.GameIDs = append(result.GameIDs, batchIDs...)
+			result.StoryCount += storyCount
+			d.deleteGameCacheKeys(ctx, batchIDs)
+		}
+		// a partial batch means there are no old games left
+		if len(batchIDs) < cleanupBatchSize {
+			break
+		}
+	}
+	result.GameCount = len(result.GameIDs)
+
+	return result, nil
+}
+
+// deleteOldGamesBatch deletes up to cleanupBatchSize games last active before the cutoff
+func (d *Service) deleteOldGamesBatch(ctx context.Context, cutoff time.Time) ([]string, int, error) {
+	gameIDs := make([]string, 0)
+	storyCount := 0
+
+	rows, err := d.DB.QueryContext(ctx,
+		`WITH deleted AS (
+			DELETE FROM thunderdome.poker WHERE id IN (
+				SELECT id FROM thunderdome.poker WHERE last_active < $1 LIMIT $2
+			) RETURNING id
+		)
+		SELECT d.id, (SELECT COUNT(*) FROM thunderdome.poker_story ps WHERE ps.poker_id = d.id)
+		FROM deleted d;`,
+		cutoff, cleanupBatchSize,
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("cleanup old games delete query error: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var gameID string
+		var count int
+		if err := rows.Scan(&gameID, &count); err != nil {
+			return nil, 0, fmt.Errorf("cleanup old games delete query scan error: %v", err)
+		}
+		gameIDs = append(gameIDs, gameID)
+		storyCount += count
+	}
+
+	return gameIDs, storyCount, nil
+}
+
+// deleteGameCacheKeys removes the cached games from redis
+func (d *Service) deleteGameCacheKeys(ctx context.Context, gameIDs []string) {
+	if d.Redis == nil {
+		return
+	}
+
+	if err := d.Redis.Del(ctx, gameCacheKeys(gameIDs)...).Err(); err != nil {
+		d.Logger.Ctx(ctx).Error("cleanup old games cache delete error", zap.Error(err))
+	}
+}
+
+// gameCacheKeys gets the redis keys the games are cached under
+func gameCacheKeys(gameIDs []string) []string {
+	keys := make([]string, 0, len(gameIDs))
+	for _, gameID := range gameIDs {
+		keys = append(keys, fmt.Sprintf("game:%s", gameID))
+	}
+
+	return keys
+}
+
+// GetStaleGames gets the IDs of poker games that still have active participants but no activity since inactiveSince,
+// usually left behind by connections that never disconnected cleanly
+func (d *Service) GetStaleGames(ctx context.Context, inactiveSince time.Time) ([]string, error) {
+	gameIDs := make([]string, 0)
+
+	rows, err := d.DB.QueryContext(ctx,
+		`SELECT p.id FROM thunderdome.poker p
+		WHERE p.last_active < $1
+			AND EXISTS (SELECT 1 FROM thunderdome.poker_user pu WHERE pu.poker_id = p.id AND pu.active = true)
+		ORDER BY p.last_active;`,
+		inactiveSince,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get stale games query error: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var gameID string
+		if err := rows.Scan(&gameID); err != nil {
+			return nil, fmt.Errorf("get stale games query scan error: %v", err)
+		}
+		gameIDs = append(gameIDs, gameID)
+	}
+
+	return gameIDs, nil
+}
@@ -0,0 +1,199 @@
+package admin
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// cleanupDB is an in memory stand in for the poker games older than the cleanup cutoff,
+// recording each query run
+type cleanupDB struct {
+	mu       sync.Mutex
+	oldGames []string
+	queries  []string
+}
+
+var (
+	cleanupDBs   = make(map[string]*cleanupDB)
+	cleanupDBsMu sync.Mutex
+)
+
+func init() {
+	sql.Register("admin-cleanup", cleanupDriver{})
+}
+
+// openCleanupDB opens a sql.DB backed by the cleanupDB
+func openCleanupDB(t *testing.T, cdb *cleanupDB) *sql.DB {
+	t.Helper()
+
+	cleanupDBsMu.Lock()
+	cleanupDBs[t.Name()] = cdb
+	cleanupDBsMu.Unlock()
+
+	db, err := sql.Open("admin-cleanup", t.Name())
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	return db
+}
+
+type cleanupDriver struct{}
+
+func (cleanupDriver) Open(name string) (driver.Conn, error) {
+	cleanupDBsMu.Lock()
+	defer cleanupDBsMu.Unlock()
+
+	return &cleanupConn{db: cleanupDBs[name]}, nil
+}
+
+type cleanupConn struct {
+	db *cleanupDB
+}
+
+func (c *cleanupConn) Prepare(query string) (driver.Stmt, error) {
+	return &cleanupStmt{db: c.db, query: query}, nil
+}
+
+func (c *cleanupConn) Close() error { return nil }
+func (c *cleanupConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions aren't supported")
+}
+
+type cleanupStmt struct {
+	db    *cleanupDB
+	query string
+}
+
+func (s *cleanupStmt) Close() error  { return nil }
+func (s *cleanupStmt) NumInput() int { return -1 }
+
+func (s *cleanupStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, errors.New("statements aren't supported")
+}
+
+// Query deletes up to the limit of old games returning them, otherwise lists the old games
+func (s *cleanupStmt) Query(args []driver.Value) (driver.Rows, error) {
+	db := s.db
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.queries = append(db.queries, s.query)
+
+	gameIDs := db.oldGames
+	if strings.Contains(s.query, "DELETE FROM thunderdome.poker") {
+		limit := int(args[1].(int64))
+		if limit < len(gameIDs) {
+			gameIDs = gameIDs[:limit]
+		}
+		db.oldGames = db.oldGames[len(gameIDs):]
+	}
+
+	rows := &cleanupRows{}
+	for _, gameID := range gameIDs {
+		rows.values = append(rows.values, []driver.Value{gameID, int64(2)})
+	}
+	return rows, nil
+}
+
+// deletes counts the delete queries run
+func (db *cleanupDB) deletes() int {
+	count := 0
+	for _, query := range db.queries {
+		if strings.Contains(query, "DELETE") {
+			count++
+		}
+	}
+	return count
+}
+
+type cleanupRows struct {
+	values [][]driver.Value
+}
+
+func (r *cleanupRows) Columns() []string { return []string{"id", "story_count"} }
+func (r *cleanupRows) Close() error      { return nil }
+
+func (r *cleanupRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+func oldGameIDs(count int) []string {
+	gameIDs := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		gameIDs = append(gameIDs, fmt.Sprintf("game-%d", i))
+	}
+	return gameIDs
+}
+
+// TestCleanupOldGamesDryRun makes sure a dry run reports the old games without deleting any
+func TestCleanupOldGamesDryRun(t *testing.T) {
+	cdb := &cleanupDB{oldGames: oldGameIDs(150)}
+	d := &Service{DB: openCleanupDB(t, cdb)}
+
+	result, err := d.CleanupOldGames(context.Background(), 90, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.DryRun || result.GameCount != 150 || result.StoryCount != 300 {
+		t.Errorf("expected 150 games with 300 stories in the dry run, got %+v", result)
+	}
+	if cdb.deletes() != 0 || len(cdb.oldGames) != 150 {
+		t.Errorf("expected nothing to be deleted, got %d delete queries", cdb.deletes())
+	}
+}
+
+// TestCleanupOldGamesBatches makes sure old games are deleted in batches until a batch comes back partial
+func TestCleanupOldGamesBatches(t *testing.T) {
+	tests := []struct {
+		name            string
+		oldGames        int
+		expectedDeletes int
+	}{
+		{name: "no old games", oldGames: 0, expectedDeletes: 1},
+		{name: "partial batch", oldGames: 40, expectedDeletes: 1},
+		{name: "multiple batches", oldGames: 250, expectedDeletes: 3},
+		{name: "exact batches", oldGames: 200, expectedDeletes: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cdb := &cleanupDB{oldGames: oldGameIDs(tt.oldGames)}
+			d := &Service{DB: openCleanupDB(t, cdb)}
+
+			result, err := d.CleanupOldGames(context.Background(), 90, false)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.GameCount != tt.oldGames || len(result.GameIDs) != tt.oldGames || result.StoryCount != 2*tt.oldGames {
+				t.Errorf("expected %d games deleted, got %+v", tt.oldGames, result)
+			}
+			if len(cdb.oldGames) != 0 {
+				t.Errorf("expected every old game to be deleted, %d left", len(cdb.oldGames))
+			}
+			if cdb.deletes() != tt.expectedDeletes {
+				t.Errorf("expected %d delete batches, got %d", tt.expectedDeletes, cdb.deletes())
+			}
+		})
+	}
+}
+
+// TestGameCacheKeys makes sure deleted games are removed from the game cache
+func TestGameCacheKeys(t *testing.T) {
+	keys := gameCacheKeys([]string{"game-1", "game-2"})
+	if len(keys) != 2 || keys[0] != "game:game-1" || keys[1] != "game:game-2" {
+		t.Errorf("expected the game cache keys, got %v", keys)
+	}
+}
@@ -0,0 +1,95 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+// biasVote is the user's vote in the last voting round of a finalized story
+type biasVote struct {
+	PokerID string
+	Vote    string
+	Points  string
+}
+
+// GetUserEstimationBias gets how the user's votes compared to the final points of stories voted on since the time,
+// the user's vote in each story's last voting round is used and stories they didn't vote on in that round are left out
+func (d *Service) GetUserEstimationBias(ctx context.Context, userID string, since time.Time) (*thunderdome.EstimationBias, error) {
+	votes := make([]biasVote, 0)
+	rows, err := d.DB.QueryContext(ctx,
+		`SELECT fr.poker_id, COALESCE(v.value->>'vote', ''), ps.points
+		FROM (
+			SELECT DISTINCT ON (vr.story_id) vr.story_id, vr.poker_id, vr.votes
+			FROM thunderdome.poker_story_vote_round vr
+			WHERE vr.voteend_time >= $2
+			ORDER BY vr.story_id, vr.round DESC
+		) fr
+		JOIN thunderdome.poker_story ps ON ps.id = fr.story_id
+		CROSS JOIN LATERAL jsonb_array_elements(fr.votes) v
+		WHERE v.value->>'warriorId' = $1 AND ps.points <> '';`,
+		userID, since,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get user estimation bias query error: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var v biasVote
+		if err := rows.Scan(&v.PokerID, &v.Vote, &v.Points); err != nil {
+			d.Logger.Ctx(ctx).Error("get user estimation bias query scan error", zap.Error(err))
+			continue
+		}
+		votes = append(votes, v)
+	}
+
+	bias := computeEstimationBias(votes)
+	bias.UserID = userID
+	bias.Since = since
+
+	return bias, nil
+}
+
+// computeEstimationBias compares the votes to their story's final points, votes or points that aren't
+// numbers (e.g. ? or coffee) are left out
+func computeEstimationBias(votes []biasVote) *thunderdome.EstimationBias {
+	bias := &thunderdome.EstimationBias{}
+
+	games := make(map[string]bool)
+	var totalDeviation float64
+	var under, over int
+	for _, v := range votes {
+		vote, ok := thunderdome.ParsePointValue(v.Vote)
+		if !ok {
+			continue
+		}
+		points, ok := thunderdome.ParsePointValue(v.Points)
+		if !ok {
+			continue
+		}
+
+		bias.VotesAnalyzed++
+		games[v.PokerID] = true
+		deviation := vote - points
+		totalDeviation += deviation
+		if deviation < 0 {
+			under++
+		} else if deviation > 0 {
+			over++
+		}
+	}
+	bias.GamesAnalyzed = len(games)
+
+	if bias.VotesAnalyzed == 0 {
+		return bias
+	}
+	bias.AverageDeviation = totalDeviation / float64(bias.VotesAnalyzed)
+	bias.UnderestimationRate = float64(under) / float64(bias.VotesAnalyzed)
+	bias.OverestimationRate = float64(over) / float64(bias.VotesAnalyzed)
+
+	return bias
+}
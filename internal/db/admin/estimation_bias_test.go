@@ -0,0 +1,48 @@
+package admin
+
+import (
+	"testing"
+)
+
+// TestComputeEstimationBias makes sure under and over estimation rates are based on the compared votes
+func TestComputeEstimationBias(t *testing.T) {
+	votes := []biasVote{
+		{PokerID: "a", Vote: "1", Points: "8"},
+		{PokerID: "a", Vote: "2", Points: "13"},
+		{PokerID: "b", Vote: "3", Points: "3"},
+		{PokerID: "b", Vote: "8", Points: "5"},
+		{PokerID: "c", Vote: "?", Points: "5"},
+		{PokerID: "c", Vote: "1/2", Points: "1"},
+	}
+
+	bias := computeEstimationBias(votes)
+
+	if bias.VotesAnalyzed != 5 {
+		t.Errorf("expected 5 votes analyzed, got %d", bias.VotesAnalyzed)
+	}
+	if bias.GamesAnalyzed != 3 {
+		t.Errorf("expected 3 games analyzed, got %d", bias.GamesAnalyzed)
+	}
+	if bias.UnderestimationRate != 0.6 {
+		t.Errorf("expected an underestimation rate of 0.6, got %v", bias.UnderestimationRate)
+	}
+	if bias.OverestimationRate != 0.2 {
+		t.Errorf("expected an overestimation rate of 0.2, got %v", bias.OverestimationRate)
+	}
+	// (-7 - 11 + 0 + 3 - 0.5) / 5
+	if bias.AverageDeviation != -3.1 {
+		t.Errorf("expected an average deviation of -3.1, got %v", bias.AverageDeviation)
+	}
+}
+
+// TestComputeEstimationBiasNoVotes makes sure a user without comparable votes gets zero rates
+func TestComputeEstimationBiasNoVotes(t *testing.T) {
+	bias := computeEstimationBias([]biasVote{{PokerID: "a", Vote: "☕️", Points: "3"}})
+
+	if bias.GamesAnalyzed != 0 || bias.VotesAnalyzed != 0 {
+		t.Errorf("expected nothing analyzed, got %+v", bias)
+	}
+	if bias.AverageDeviation != 0 || bias.UnderestimationRate != 0 || bias.OverestimationRate != 0 {
+		t.Errorf("expected zero rates, got %+v", bias)
+	}
+}
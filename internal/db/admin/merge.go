@@ -0,0 +1,166 @@
+package admin
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// mergeUserStatements move the source user ($1) game, team and organization participation, votes
+// and authored content to the target user ($2), rows the target user already has are kept
+// and the source user's duplicates are removed
+var mergeUserStatements = []string{
+	`INSERT INTO thunderdome.poker_user (poker_id, user_id, active, abandoned, spectator, observer)
+		SELECT poker_id, $2, false, abandoned, spectator, observer FROM thunderdome.poker_user WHERE user_id = $1
+		ON CONFLICT DO NOTHING;`,
+	`DELETE FROM thunderdome.poker_user WHERE user_id = $1;`,
+	`INSERT INTO thunderdome.poker_facilitator (poker_id, user_id, is_primary, created_date)
+		SELECT poker_id, $2, is_primary, created_date FROM thunderdome.poker_facilitator WHERE user_id = $1
+		ON CONFLICT DO NOTHING;`,
+	`DELETE FROM thunderdome.poker_facilitator WHERE user_id = $1;`,
+	`INSERT INTO thunderdome.retro_user (retro_id, user_id, active, abandoned, spectator)
+		SELECT retro_id, $2, false, abandoned, spectator FROM thunderdome.retro_user WHERE user_id = $1
+		ON CONFLICT DO NOTHING;`,
+	`DELETE FROM thunderdome.retro_user WHERE user_id = $1;`,
+	`INSERT INTO thunderdome.retro_facilitator (retro_id, user_id, created_date)
+		SELECT retro_id, $2, created_date FROM thunderdome.retro_facilitator WHERE user_id = $1
+		ON CONFLICT DO NOTHING;`,
+	`DELETE FROM thunderdome.retro_facilitator WHERE user_id = $1;`,
+	`INSERT INTO thunderdome.storyboard_user (storyboard_id, user_id, active, abandoned)
+		SELECT storyboard_id, $2, false, abandoned FROM thunderdome.storyboard_user WHERE user_id = $1
+		ON CONFLICT DO NOTHING;`,
+	`DELETE FROM thunderdome.storyboard_user WHERE user_id = $1;`,
+	`INSERT INTO thunderdome.storyboard_facilitator (storyboard_id, user_id, created_date)
+		SELECT storyboard_id, $2, created_date FROM thunderdome.storyboard_facilitator WHERE user_id = $1
+		ON CONFLICT DO NOTHING;`,
+	`DELETE FROM thunderdome.storyboard_facilitator WHERE user_id = $1;`,
+	`INSERT INTO thunderdome.team_user (team_id, user_id, created_date, updated_date, role)
+		SELECT team_id, $2, created_date, NOW(), role FROM thunderdome.team_user WHERE user_id = $1
+		ON CONFLICT DO NOTHING;`,
+	`DELETE FROM thunderdome.team_user WHERE user_id = $1;`,
+	`INSERT INTO thunderdome.organization_user (organization_id, user_id, role, created_date, updated_date)
+		SELECT organization_id, $2, role, created_date, NOW() FROM thunderdome.organization_user WHERE user_id = $1
+		ON CONFLICT DO NOTHING;`,
+	`DELETE FROM thunderdome.organization_user WHERE user_id = $1;`,
+	`INSERT INTO thunderdome.department_user (department_id, user_id, role, created_date, updated_date)
+		SELECT department_id, $2, role, created_date, NOW() FROM thunderdome.department_user WHERE user_id = $1
+		ON CONFLICT DO NOTHING;`,
+	`DELETE FROM thunderdome.department_user WHERE user_id = $1;`,
+	// poker votes are stored by user in the story's votes, a story the target user also voted on keeps their vote
+	`UPDATE thunderdome.poker_story ps SET votes = (
+			SELECT COALESCE(jsonb_agg(
+				CASE WHEN v.vote->>'warriorId' = $1::text
+					THEN jsonb_set(v.vote, '{warriorId}', to_jsonb($2::text)) ELSE v.vote END
+				ORDER BY v.idx
+			), '[]'::jsonb)
+			FROM jsonb_array_elements(ps.votes) WITH ORDINALITY AS v(vote, idx)
+			WHERE v.vote->>'warriorId' <> $1::text
+				OR NOT ps.votes @> jsonb_build_array(jsonb_build_object('warriorId', $2::text))
+		)
+		WHERE ps.votes @> jsonb_build_array(jsonb_build_object('warriorId', $1::text));`,
+	`UPDATE thunderdome.poker_story_vote_round vr SET votes = (
+			SELECT COALESCE(jsonb_agg(
+				CASE WHEN v.vote->>'warriorId' = $1::text
+					THEN jsonb_set(v.vote, '{warriorId}', to_jsonb($2::text)) ELSE v.vote END
+				ORDER BY v.idx
+			), '[]'::jsonb)
+			FROM jsonb_array_elements(vr.votes) WITH ORDINALITY AS v(vote, idx)
+			WHERE v.vote->>'warriorId' <> $1::text
+				OR NOT vr.votes @> jsonb_build_array(jsonb_build_object('warriorId', $2::text))
+		)
+		WHERE vr.votes @> jsonb_build_array(jsonb_build_object('warriorId', $1::text));`,
+	`INSERT INTO thunderdome.retro_group_vote (retro_id, group_id, user_id)
+		SELECT retro_id, group_id, $2 FROM thunderdome.retro_group_vote WHERE user_id = $1
+		ON CONFLICT DO NOTHING;`,
+	`DELETE FROM thunderdome.retro_group_vote WHERE user_id = $1;`,
+	`UPDATE thunderdome.retro_item SET user_id = $2 WHERE user_id = $1;`,
+	`UPDATE thunderdome.retro_item_comment SET user_id = $2 WHERE user_id = $1;`,
+	`INSERT INTO thunderdome.retro_action_assignee (action_id, user_id, created_date)
+		SELECT action_id, $2, created_date FROM thunderdome.retro_action_assignee WHERE user_id = $1
+		ON CONFLICT DO NOTHING;`,
+	`DELETE FROM thunderdome.retro_action_assignee WHERE user_id = $1;`,
+	`UPDATE thunderdome.retro_action_comment SET user_id = $2 WHERE user_id = $1;`,
+	`UPDATE thunderdome.storyboard_story_comment SET user_id = $2 WHERE user_id = $1;`,
+	`UPDATE thunderdome.poker_story_comment SET author_id = $2 WHERE author_id = $1;`,
+	`UPDATE thunderdome.team_checkin SET user_id = $2 WHERE user_id = $1;`,
+	`UPDATE thunderdome.team_checkin_comment SET user_id = $2 WHERE user_id = $1;`,
+	`UPDATE thunderdome.poker SET owner_id = $2 WHERE owner_id = $1;`,
+	`UPDATE thunderdome.retro SET owner_id = $2 WHERE owner_id = $1;`,
+	`UPDATE thunderdome.storyboard SET owner_id = $2 WHERE owner_id = $1;`,
+	// api keys named the same as one of the target user's keys are left with the merged user and deactivated
+	`UPDATE thunderdome.api_key k SET user_id = $2, updated_date = NOW()
+		WHERE k.user_id = $1 AND NOT EXISTS (
+			SELECT 1 FROM thunderdome.api_key t WHERE t.user_id = $2 AND t.name = k.name
+		);`,
+	`UPDATE thunderdome.api_key SET active = false, updated_date = NOW() WHERE user_id = $1;`,
+	`UPDATE thunderdome.user_notification SET user_id = $2 WHERE user_id = $1;`,
+	`DELETE FROM thunderdome.user_session WHERE user_id = $1;`,
+	`UPDATE thunderdome.users SET disabled = true, merged_into_user_id = $2, updated_date = NOW() WHERE id = $1;`,
+}
+
+// MergeUsers merges the duplicate source user into the target user, moving the source user's games, teams,
+// organizations, votes, retro items, api keys and notifications to the target user. The source user is disabled and can no longer log in,
+// the merge can't be undone.
+func (d *Service) MergeUsers(ctx context.Context, sourceUserID string, targetUserID string) error {
+	tx, err := d.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("merge users begin transaction error: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	source, target, err := lockMergeUsers(ctx, tx, sourceUserID, targetUserID)
+	if err != nil {
+		return err
+	}
+
+	if err := thunderdome.ValidateUserMerge(source, target); err != nil {
+		return err
+	}
+
+	for _, statement := range mergeUserStatements {
+		if _, err := tx.ExecContext(ctx, statement, sourceUserID, targetUserID); err != nil {
+			return fmt.Errorf("merge users query error: %v", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("merge users commit error: %v", err)
+	}
+
+	return nil
+}
+
+// lockMergeUsers gets the users being merged, locking them in ID order until the merge is committed
+// so concurrent merges of the same users in opposite directions can't deadlock
+func lockMergeUsers(ctx context.Context, tx *sql.Tx, sourceUserID string, targetUserID string) (*thunderdome.User, *thunderdome.User, error) {
+	rows, err := tx.QueryContext(ctx,
+		`SELECT id, COALESCE(merged_into_user_id::text, '') FROM thunderdome.users
+		WHERE id IN ($1, $2) ORDER BY id FOR UPDATE;`,
+		sourceUserID, targetUserID,
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("merge users get users query error: %v", err)
+	}
+	defer rows.Close()
+
+	users := make(map[string]*thunderdome.User, 2)
+	for rows.Next() {
+		var user thunderdome.User
+		if err := rows.Scan(&user.ID, &user.MergedIntoUserID); err != nil {
+			return nil, nil, fmt.Errorf("merge users get users scan error: %v", err)
+		}
+		users[user.ID] = &user
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("merge users get users query error: %v", err)
+	}
+
+	source, target := users[sourceUserID], users[targetUserID]
+	if source == nil || target == nil {
+		return nil, nil, fmt.Errorf("USER_NOT_FOUND")
+	}
+
+	return source, target, nil
+}
@@ -0,0 +1,35 @@
+package admin
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// TestMergeUsersKeepsPokerParticipation makes sure the source user's poker participation moves to the target
+// with its spectator and observer flags, so an observer can't vote after the merge
+func TestMergeUsersKeepsPokerParticipation(t *testing.T) {
+	rdb := &recordingDB{}
+	d := &Service{DB: openRecordingDB(t, rdb)}
+
+	if err := d.MergeUsers(context.Background(), "source-user", "target-user"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !rdb.committed {
+		t.Fatal("expected the merge to be committed")
+	}
+
+	statement := rdb.touches("poker_user")
+	if statement == nil || !strings.HasPrefix(statement.query, "INSERT") {
+		t.Fatal("expected the poker participation to be copied to the target user")
+	}
+	columns, selected, _ := strings.Cut(statement.query, "SELECT")
+	for _, column := range []string{"spectator", "observer"} {
+		if !strings.Contains(columns, column) || !strings.Contains(selected, column) {
+			t.Errorf("expected %s to be copied to the target user, got %s", column, statement.query)
+		}
+	}
+	if statement.args[0] != "source-user" || statement.args[1] != "target-user" {
+		t.Errorf("expected the source and target users, got %v", statement.args)
+	}
+}
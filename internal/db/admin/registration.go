@@ -0,0 +1,119 @@
+package admin
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/db"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+// ApproveUser approves a pending or rejected user registration allowing the user to log in
+func (d *Service) ApproveUser(ctx context.Context, userID string) error {
+	return d.transitionRegistration(ctx, userID, thunderdome.RegistrationStatusApproved, "")
+}
+
+// RejectUser rejects a pending user registration storing the reason it was rejected
+func (d *Service) RejectUser(ctx context.Context, userID string, reason string) error {
+	return d.transitionRegistration(ctx, userID, thunderdome.RegistrationStatusRejected, reason)
+}
+
+// transitionRegistration moves the user registration to the new status when the transition is allowed
+func (d *Service) transitionRegistration(ctx context.Context, userID string, status thunderdome.RegistrationStatus, reason string) error {
+	tx, err := d.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("update user registration status begin transaction error: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var current thunderdome.RegistrationStatus
+	err = tx.QueryRowContext(ctx,
+		`SELECT registration_status FROM thunderdome.users WHERE id = $1 AND type <> 'GUEST' FOR UPDATE;`,
+		userID,
+	).Scan(&current)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("USER_NOT_FOUND")
+		}
+		return fmt.Errorf("get user registration status query error: %v", err)
+	}
+
+	if err := thunderdome.ValidateRegistrationTransition(current, status); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE thunderdome.users
+		SET registration_status = $2, registration_rejected_reason = NULLIF($3, ''), updated_date = NOW()
+		WHERE id = $1;`,
+		userID, status, reason,
+	); err != nil {
+		return fmt.Errorf("update user registration status query error: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("update user registration status commit error: %v", err)
+	}
+
+	return nil
+}
+
+// GetUsersByRegistrationStatus gets a list of registered users with the registration status
+func (d *Service) GetUsersByRegistrationStatus(ctx context.Context, status thunderdome.RegistrationStatus, limit int, offset int) ([]*thunderdome.User, int, error) {
+	var users = make([]*thunderdome.User, 0)
+	var count int
+
+	err := d.DB.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM thunderdome.users WHERE type <> 'GUEST' AND registration_status = $1;`,
+		status,
+	).Scan(&count)
+	if err != nil {
+		return nil, 0, fmt.Errorf("get users by registration status count query error: %v", err)
+	}
+
+	rows, err := d.DB.QueryContext(ctx,
+		`SELECT u.id, u.name, COALESCE(u.email, ''), u.type, u.avatar, u.verified, COALESCE(u.country, ''),
+		 COALESCE(u.company, ''), COALESCE(u.job_title, ''), u.disabled, COALESCE(u.picture, ''),
+		 u.created_date, u.registration_status, COALESCE(u.registration_rejected_reason, '')
+		FROM thunderdome.users u
+		WHERE u.type <> 'GUEST' AND u.registration_status = $1
+		ORDER BY u.created_date
+		LIMIT $2
+		OFFSET $3;`,
+		status, limit, offset,
+	)
+	if err != nil {
+		return nil, count, fmt.Errorf("get users by registration status query error: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var u thunderdome.User
+		if err := rows.Scan(
+			&u.ID,
+			&u.Name,
+			&u.Email,
+			&u.Type,
+			&u.Avatar,
+			&u.Verified,
+			&u.Country,
+			&u.Company,
+			&u.JobTitle,
+			&u.Disabled,
+			&u.Picture,
+			&u.CreatedDate,
+			&u.RegistrationStatus,
+			&u.RegistrationRejectedReason,
+		); err != nil {
+			d.Logger.Ctx(ctx).Error("get users by registration status query scan error", zap.Error(err))
+		} else {
+			u.GravatarHash = db.CreateGravatarHash(u.Email)
+			users = append(users, &u)
+		}
+	}
+
+	return users, count, nil
+}
@@ -0,0 +1,46 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/validation"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// GetInvalidStoryLinks gets the poker stories whose link isn't a valid http or https URL, new links are
+// validated before storage but stories saved beforehand may hold placeholders. Returns the page of
+// invalid links along with how many there are in total.
+func (d *Service) GetInvalidStoryLinks(ctx context.Context, limit int, offset int) ([]*thunderdome.InvalidStoryLink, int, error) {
+	rows, err := d.DB.QueryContext(ctx,
+		`SELECT id, name, poker_id, link FROM thunderdome.poker_story
+		WHERE link IS NOT NULL AND link <> ''
+		ORDER BY poker_id, position;`,
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("get invalid story links query error: %v", err)
+	}
+	defer rows.Close()
+
+	// links are validated the same way new links are, which SQL can't express
+	invalid := make([]*thunderdome.InvalidStoryLink, 0)
+	for rows.Next() {
+		var l thunderdome.InvalidStoryLink
+		if err := rows.Scan(&l.StoryID, &l.StoryName, &l.GameID, &l.Link); err != nil {
+			return nil, 0, fmt.Errorf("get invalid story links scan error: %v", err)
+		}
+		if validation.ValidateURL(l.Link) != nil {
+			invalid = append(invalid, &l)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("get invalid story links rows error: %v", err)
+	}
+
+	count := len(invalid)
+	if offset >= count {
+		return make([]*thunderdome.InvalidStoryLink, 0), count, nil
+	}
+
+	return invalid[offset:min(offset+limit, count)], count, nil
+}
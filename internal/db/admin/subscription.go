@@ -0,0 +1,187 @@
+package admin
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/db/subscription"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// overrideSubscriptionCustomerID is the customer ID of subscriptions created by an admin override
+// for organizations without a payment processor subscription, customer IDs are unique so it includes the organization ID
+const overrideSubscriptionCustomerID = "admin_override_"
+
+// insertAuditLogStatement records an admin audit log entry
+const insertAuditLogStatement = `INSERT INTO thunderdome.admin_audit_log (actor_id, action, entity_type, entity_id, details)
+		VALUES ($1, $2, $3, $4, $5);`
+
+// insertAuditLog records the admin audit log entry in the transaction
+func insertAuditLog(ctx context.Context, tx *sql.Tx, entry *thunderdome.AdminAuditLog) error {
+	if _, err := tx.ExecContext(ctx, insertAuditLogStatement,
+		entry.ActorID, entry.Action, entry.EntityType, entry.EntityID, string(entry.Details),
+	); err != nil {
+		return fmt.Errorf("insert admin audit log query error: %v", err)
+	}
+
+	return nil
+}
+
+// invalidateOrgTier removes the organization's cached subscription tier so the change takes effect right away
+func (d *Service) invalidateOrgTier(ctx context.Context, orgID string) {
+	if d.Redis != nil {
+		d.Redis.Del(ctx, subscription.OrgTierCacheKey(orgID))
+	}
+}
+
+// OverrideSubscription sets the organization's subscription tier and expiry directly bypassing the payment processor,
+// organizations without a subscription get one owned by their first admin, overriding to the free tier deactivates it.
+// The override is recorded in the admin audit log along with the admin making it and their reason.
+func (d *Service) OverrideSubscription(ctx context.Context, actorID string, orgID string, tier string, expiresAt time.Time, reason string) error {
+	subscriptionTier := thunderdome.SubscriptionTier(tier)
+	subscriptionType, err := thunderdome.TierSubscriptionType(subscriptionTier)
+	if err != nil {
+		return err
+	}
+	active := subscriptionTier != thunderdome.SubscriptionTierFree
+
+	tx, err := d.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("override subscription begin transaction error: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var subscriptionID string
+	var previousExpiry time.Time
+	err = tx.QueryRowContext(ctx,
+		`SELECT id, expires FROM thunderdome.subscription
+		WHERE organization_id = $1
+		ORDER BY active DESC, expires DESC
+		LIMIT 1
+		FOR UPDATE;`,
+		orgID,
+	).Scan(&subscriptionID, &previousExpiry)
+	details := thunderdome.SubscriptionAuditDetails{Tier: subscriptionTier, Expires: expiresAt, Reason: reason}
+
+	switch {
+	case err == nil:
+		details.PreviousExpiry = &previousExpiry
+		if !active {
+			// the free tier keeps the subscription's type so it isn't lost should it be reactivated
+			_, err = tx.ExecContext(ctx,
+				`UPDATE thunderdome.subscription SET active = false, expires = $2, updated_date = NOW() WHERE id = $1;`,
+				subscriptionID, expiresAt,
+			)
+		} else {
+			_, err = tx.ExecContext(ctx,
+				`UPDATE thunderdome.subscription SET active = true, type = $2, expires = $3, updated_date = NOW() WHERE id = $1;`,
+				subscriptionID, subscriptionType, expiresAt,
+			)
+		}
+		if err != nil {
+			return fmt.Errorf("override subscription update query error: %v", err)
+		}
+	case errors.Is(err, sql.ErrNoRows):
+		if active {
+			err = tx.QueryRowContext(ctx,
+				`INSERT INTO thunderdome.subscription
+					(user_id, organization_id, customer_id, subscription_id, type, expires)
+				SELECT ou.user_id, ou.organization_id, $2 || ou.organization_id::text, 'admin_override', $3, $4
+				FROM thunderdome.organization_user ou
+				WHERE ou.organization_id = $1 AND ou.role = 'ADMIN'
+				ORDER BY ou.created_date ASC
+				LIMIT 1
+				RETURNING id;`,
+				orgID, overrideSubscriptionCustomerID, subscriptionType, expiresAt,
+			).Scan(&subscriptionID)
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("ORGANIZATION_ADMIN_NOT_FOUND")
+			}
+			if err != nil {
+				return fmt.Errorf("override subscription insert query error: %v", err)
+			}
+		}
+	default:
+		return fmt.Errorf("override subscription query error: %v", err)
+	}
+
+	entry, err := thunderdome.NewSubscriptionAuditLog(actorID, orgID, thunderdome.AuditActionSubscriptionOverride, details)
+	if err != nil {
+		return fmt.Errorf("override subscription audit log error: %v", err)
+	}
+	if err := insertAuditLog(ctx, tx, entry); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("override subscription commit error: %v", err)
+	}
+	d.invalidateOrgTier(ctx, orgID)
+
+	return nil
+}
+
+// ExtendSubscription adds the days to the expiry of the organization's active subscription,
+// a lapsed subscription is extended from today. The extension is recorded in the admin audit log.
+func (d *Service) ExtendSubscription(ctx context.Context, actorID string, orgID string, days int) error {
+	tx, err := d.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("extend subscription begin transaction error: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var subscriptionID string
+	var subscriptionType string
+	var expires time.Time
+	err = tx.QueryRowContext(ctx,
+		`SELECT id, type, expires FROM thunderdome.subscription
+		WHERE organization_id = $1 AND active = true
+		ORDER BY expires DESC
+		LIMIT 1
+		FOR UPDATE;`,
+		orgID,
+	).Scan(&subscriptionID, &subscriptionType, &expires)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("SUBSCRIPTION_NOT_FOUND")
+	}
+	if err != nil {
+		return fmt.Errorf("extend subscription query error: %v", err)
+	}
+
+	newExpiry, err := thunderdome.ExtendSubscriptionExpiry(expires, time.Now(), days)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE thunderdome.subscription SET expires = $2, updated_date = NOW() WHERE id = $1;`,
+		subscriptionID, newExpiry,
+	); err != nil {
+		return fmt.Errorf("extend subscription update query error: %v", err)
+	}
+
+	entry, err := thunderdome.NewSubscriptionAuditLog(actorID, orgID, thunderdome.AuditActionSubscriptionExtend,
+		thunderdome.SubscriptionAuditDetails{
+			Tier:           thunderdome.SubscriptionTypeTier(subscriptionType),
+			Days:           days,
+			PreviousExpiry: &expires,
+			Expires:        newExpiry,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("extend subscription audit log error: %v", err)
+	}
+	if err := insertAuditLog(ctx, tx, entry); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("extend subscription commit error: %v", err)
+	}
+	d.invalidateOrgTier(ctx, orgID)
+
+	return nil
+}
@@ -5,11 +5,13 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/StevenWeathers/thunderdome-planning-poker/internal/db"
 
 	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/redis/go-redis/v9"
 	"github.com/uptrace/opentelemetry-go-extra/otelzap"
 
 	"go.uber.org/zap"
@@ -19,6 +21,11 @@ import (
 type Service struct {
 	DB     *sql.DB
 	Logger *otelzap.Logger
+	// Redis batches api key usage counts across instances when set, otherwise they're batched in memory
+	Redis *redis.Client
+
+	usageOnce    sync.Once
+	usageCounter usageCounter
 }
 
 // GenerateAPIKey generates a new API key for a User
@@ -129,10 +136,7 @@ func (d *Service) DeleteUserAPIKey(ctx context.Context, userID string, keyID str
 // GetAPIKeyUser checks to see if the API key exists and returns the User
 func (d *Service) GetAPIKeyUser(ctx context.Context, apiKey string) (*thunderdome.User, error) {
 	user := &thunderdome.User{}
-
-	splitKey := strings.Split(apiKey, ".")
-	hashedKey := db.HashString(apiKey)
-	keyID := splitKey[0] + "." + hashedKey
+	keyID := apiKeyID(apiKey)
 
 	err := d.DB.QueryRowContext(ctx, `
 		SELECT u.id, u.name, u.email, u.type, u.avatar, u.verified, u.notifications_enabled, COALESCE(u.country, ''), COALESCE(u.locale, ''), COALESCE(u.company, ''), COALESCE(u.job_title, ''), u.created_date, u.updated_date, u.last_active
@@ -165,6 +169,14 @@ func (d *Service) GetAPIKeyUser(ctx context.Context, apiKey string) (*thunderdom
 	return user, nil
 }
 
+// apiKeyID derives the stored api key ID (prefix and hashed key) from the raw api key
+func apiKeyID(apiKey string) string {
+	splitKey := strings.Split(apiKey, ".")
+	hashedKey := db.HashString(apiKey)
+
+	return splitKey[0] + "." + hashedKey
+}
+
 // GetAPIKeys gets a list of api keys
 func (d *Service) GetAPIKeys(ctx context.Context, limit int, offset int) []*thunderdome.UserAPIKey {
 	var keys = make([]*thunderdome.UserAPIKey, 0)
@@ -0,0 +1,231 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// UsageFlushInterval is how often the batched api key usage counts are written to the database
+const UsageFlushInterval = 5 * time.Minute
+
+const usageCacheKey = "apikey:usage"
+
+// usageCounter batches api key usage counts by usage field until they're drained for flushing
+type usageCounter interface {
+	incrBy(ctx context.Context, field string, count int64) error
+	drain(ctx context.Context) (map[string]int64, error)
+}
+
+// redisUsageCounter batches the usage counts in a redis hash shared by all instances
+type redisUsageCounter struct {
+	client *redis.Client
+}
+
+func (c *redisUsageCounter) incrBy(ctx context.Context, field string, count int64) error {
+	return c.client.HIncrBy(ctx, usageCacheKey, field, count).Err()
+}
+
+// drain moves the usage hash aside before reading it so increments made while flushing aren't lost
+func (c *redisUsageCounter) drain(ctx context.Context) (map[string]int64, error) {
+	flushKey := fmt.Sprintf("%s:flushing:%d", usageCacheKey, time.Now().UnixNano())
+	if err := c.client.Rename(ctx, usageCacheKey, flushKey).Err(); err != nil {
+		if strings.Contains(err.Error(), "no such key") {
+			return map[string]int64{}, nil
+		}
+		return nil, err
+	}
+	defer c.client.Del(context.Background(), flushKey)
+
+	values, err := c.client.HGetAll(ctx, flushKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(values))
+	for field, value := range values {
+		count, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			continue
+		}
+		counts[field] = count
+	}
+
+	return counts, nil
+}
+
+// memoryUsageCounter batches the usage counts in memory when redis isn't configured
+type memoryUsageCounter struct {
+	mu     sync.Mutex
+	counts map[string]int64
+}
+
+func (c *memoryUsageCounter) incrBy(ctx context.Context, field string, count int64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.counts[field] += count
+
+	return nil
+}
+
+func (c *memoryUsageCounter) drain(ctx context.Context) (map[string]int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	counts := c.counts
+	c.counts = make(map[string]int64)
+
+	return counts, nil
+}
+
+func (d *Service) usage() usageCounter {
+	d.usageOnce.Do(func() {
+		if d.usageCounter != nil {
+			return
+		}
+		if d.Redis != nil {
+			d.usageCounter = &redisUsageCounter{client: d.Redis}
+		} else {
+			d.usageCounter = &memoryUsageCounter{counts: make(map[string]int64)}
+		}
+	})
+
+	return d.usageCounter
+}
+
+// usageField encodes the api key usage count hash field
+func usageField(keyID string, date string, endpoint string) string {
+	return keyID + "|" + date + "|" + endpoint
+}
+
+// parseUsageField decodes the api key usage count hash field
+func parseUsageField(field string) (keyID string, date string, endpoint string, ok bool) {
+	parts := strings.SplitN(field, "|", 3)
+	if len(parts) != 3 {
+		return "", "", "", false
+	}
+
+	return parts[0], parts[1], parts[2], true
+}
+
+// RecordAPIKeyUsage counts a call made with the api key to the endpoint,
+// counts are batched and written to the database every flush interval
+func (d *Service) RecordAPIKeyUsage(ctx context.Context, apiKey string, endpoint string) error {
+	field := usageField(apiKeyID(apiKey), time.Now().UTC().Format(time.DateOnly), endpoint)
+	if err := d.usage().incrBy(ctx, field, 1); err != nil {
+		return fmt.Errorf("record api key usage error: %v", err)
+	}
+
+	return nil
+}
+
+// drainAPIKeyUsage takes the batched api key usage counts
+func (d *Service) drainAPIKeyUsage(ctx context.Context) ([]*thunderdome.APIKeyUsage, error) {
+	counts, err := d.usage().drain(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("drain api key usage error: %v", err)
+	}
+
+	usage := make([]*thunderdome.APIKeyUsage, 0, len(counts))
+	for field, count := range counts {
+		keyID, date, endpoint, ok := parseUsageField(field)
+		if !ok || count <= 0 {
+			continue
+		}
+		usage = append(usage, &thunderdome.APIKeyUsage{
+			APIKeyID:  keyID,
+			Date:      date,
+			Endpoint:  endpoint,
+			CallCount: int(count),
+		})
+	}
+
+	return usage, nil
+}
+
+// FlushAPIKeyUsage writes the batched api key usage counts to the database,
+// counts that fail to be written are kept for the next flush
+func (d *Service) FlushAPIKeyUsage(ctx context.Context) error {
+	usage, err := d.drainAPIKeyUsage(ctx)
+	if err != nil {
+		return err
+	}
+	if len(usage) == 0 {
+		return nil
+	}
+
+	var errs []error
+	for _, u := range usage {
+		if _, err := d.DB.ExecContext(ctx,
+			`INSERT INTO thunderdome.api_key_usage (api_key_id, date, endpoint, call_count)
+			SELECT ak.id, $2::date, $3, $4 FROM thunderdome.api_key ak WHERE ak.id = $1
+			ON CONFLICT (api_key_id, date, endpoint)
+			DO UPDATE SET call_count = api_key_usage.call_count + EXCLUDED.call_count;`,
+			u.APIKeyID, u.Date, u.Endpoint, u.CallCount,
+		); err != nil {
+			errs = append(errs, err)
+			if retryErr := d.usage().incrBy(ctx, usageField(u.APIKeyID, u.Date, u.Endpoint), int64(u.CallCount)); retryErr != nil {
+				d.Logger.Ctx(ctx).Error("api key usage requeue error", zap.Error(retryErr),
+					zap.String("apikey_id", u.APIKeyID), zap.Int("call_count", u.CallCount))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("flush api key usage error: %v", errors.Join(errs...))
+	}
+
+	return nil
+}
+
+// RunUsageFlusher flushes the batched api key usage counts to the database every flush interval
+func (d *Service) RunUsageFlusher(ctx context.Context, flushInterval time.Duration) {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := d.FlushAPIKeyUsage(ctx); err != nil {
+				d.Logger.Ctx(ctx).Error("api key usage flush error", zap.Error(err))
+			}
+		}
+	}
+}
+
+// GetAPIKeyUsage gets the users api key daily call counts per endpoint between the since and until dates
+func (d *Service) GetAPIKeyUsage(ctx context.Context, userID string, keyID string, since time.Time, until time.Time) ([]*thunderdome.APIKeyUsage, error) {
+	usage := make([]*thunderdome.APIKeyUsage, 0)
+
+	rows, err := d.DB.QueryContext(ctx,
+		`SELECT aku.api_key_id, to_char(aku.date, 'YYYY-MM-DD'), aku.endpoint, aku.call_count
+		FROM thunderdome.api_key_usage aku
+		JOIN thunderdome.api_key ak ON ak.id = aku.api_key_id
+		WHERE ak.id = $1 AND ak.user_id = $2 AND aku.date BETWEEN $3::date AND $4::date
+		ORDER BY aku.date, aku.endpoint;`,
+		keyID, userID, since.Format(time.DateOnly), until.Format(time.DateOnly),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get api key usage query error: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var u thunderdome.APIKeyUsage
+		if err := rows.Scan(&u.APIKeyID, &u.Date, &u.Endpoint, &u.CallCount); err != nil {
+			d.Logger.Ctx(ctx).Error("GetAPIKeyUsage scan error", zap.Error(err))
+		} else {
+			usage = append(usage, &u)
+		}
+	}
+
+	return usage, nil
+}
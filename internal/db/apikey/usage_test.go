@@ -0,0 +1,72 @@
+package apikey
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestAPIKeyUsageFlushAggregates makes sure batched api key calls are aggregated per key, day and endpoint when flushed
+func TestAPIKeyUsageFlushAggregates(t *testing.T) {
+	ctx := context.Background()
+	d := &Service{}
+	apiKey := "abc123.secretkeyvalue"
+	endpoints := map[string]int{
+		"GET /api/users/{userId}":          7,
+		"POST /api/users/{userId}/battles": 3,
+	}
+
+	for endpoint, calls := range endpoints {
+		for i := 0; i < calls; i++ {
+			if err := d.RecordAPIKeyUsage(ctx, apiKey, endpoint); err != nil {
+				t.Fatalf("unexpected record error: %v", err)
+			}
+		}
+	}
+
+	usage, err := d.drainAPIKeyUsage(ctx)
+	if err != nil {
+		t.Fatalf("unexpected drain error: %v", err)
+	}
+	if len(usage) != len(endpoints) {
+		t.Fatalf("expected %d usage rows, got %d", len(endpoints), len(usage))
+	}
+
+	today := time.Now().UTC().Format(time.DateOnly)
+	total := 0
+	for _, u := range usage {
+		if u.APIKeyID != apiKeyID(apiKey) {
+			t.Errorf("expected api key id %s, got %s", apiKeyID(apiKey), u.APIKeyID)
+		}
+		if u.Date != today {
+			t.Errorf("expected date %s, got %s", today, u.Date)
+		}
+		if u.CallCount != endpoints[u.Endpoint] {
+			t.Errorf("expected %d calls for %s, got %d", endpoints[u.Endpoint], u.Endpoint, u.CallCount)
+		}
+		total += u.CallCount
+	}
+	if total != 10 {
+		t.Errorf("expected 10 total calls, got %d", total)
+	}
+
+	usage, err = d.drainAPIKeyUsage(ctx)
+	if err != nil {
+		t.Fatalf("unexpected drain error: %v", err)
+	}
+	if len(usage) != 0 {
+		t.Errorf("expected flushed usage to be cleared, got %d rows", len(usage))
+	}
+}
+
+// TestParseUsageField makes sure usage fields round trip, including endpoints containing the separator
+func TestParseUsageField(t *testing.T) {
+	keyID, date, endpoint, ok := parseUsageField(usageField("key.hash", "2025-03-14", "GET /a|b"))
+	if !ok || keyID != "key.hash" || date != "2025-03-14" || endpoint != "GET /a|b" {
+		t.Errorf("unexpected parse result %q %q %q %v", keyID, date, endpoint, ok)
+	}
+
+	if _, _, _, ok := parseUsageField("invalid"); ok {
+		t.Error("expected invalid field to fail parsing")
+	}
+}
@@ -0,0 +1,22 @@
+package asana
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+)
+
+// DefaultAPIURL is the Asana REST API base url
+const DefaultAPIURL = "https://app.asana.com/api/1.0"
+
+// Service represents the Asana database service
+type Service struct {
+	DB         *sql.DB
+	Logger     *otelzap.Logger
+	AESHashKey string
+	// APIURL overrides the Asana REST API base url, defaults to DefaultAPIURL
+	APIURL string
+	// HTTPClient is used for Asana API requests, defaults to a client with a 10 second timeout
+	HTTPClient *http.Client
+}
@@ -0,0 +1,134 @@
+package asana
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/db"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// FindConnectionsByUserID returns all AsanaConnections for a given user ID.
+func (s *Service) FindConnectionsByUserID(ctx context.Context, userID string) ([]thunderdome.AsanaConnection, error) {
+	connections := make([]thunderdome.AsanaConnection, 0)
+
+	rows, err := s.DB.QueryContext(ctx,
+		`SELECT id, user_id, name, access_token, created_date, updated_date
+ 				FROM thunderdome.asana_connection WHERE user_id = $1 ORDER BY created_date;`,
+		userID,
+	)
+	if err != nil {
+		return connections, fmt.Errorf("find asana connection by user id query error: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		connection := thunderdome.AsanaConnection{}
+		if err := rows.Scan(
+			&connection.ID, &connection.UserID, &connection.Name, &connection.AccessToken,
+			&connection.CreatedDate, &connection.UpdatedDate,
+		); err != nil {
+			return connections, fmt.Errorf("find asana connection by user id row scan error: %v", err)
+		}
+		connection.AccessToken, err = db.Decrypt(connection.AccessToken, s.AESHashKey)
+		if err != nil {
+			return connections, fmt.Errorf("error decrypting asana_connection %s access_token:  %v", connection.ID, err)
+		}
+		connections = append(connections, connection)
+	}
+
+	return connections, nil
+}
+
+// GetConnectionByID returns an AsanaConnection for a given connection ID.
+func (s *Service) GetConnectionByID(ctx context.Context, connectionID string) (thunderdome.AsanaConnection, error) {
+	connection := thunderdome.AsanaConnection{}
+
+	err := s.DB.QueryRowContext(ctx,
+		`SELECT id, user_id, name, access_token, created_date, updated_date
+ 				FROM thunderdome.asana_connection WHERE id = $1;`,
+		connectionID,
+	).Scan(
+		&connection.ID, &connection.UserID, &connection.Name, &connection.AccessToken,
+		&connection.CreatedDate, &connection.UpdatedDate,
+	)
+	if err != nil {
+		return connection, fmt.Errorf("error encountered getting asana_connection %s:  %v", connectionID, err)
+	}
+	connection.AccessToken, err = db.Decrypt(connection.AccessToken, s.AESHashKey)
+	if err != nil {
+		return connection, fmt.Errorf("error decrypting asana_connection %s access_token:  %v", connectionID, err)
+	}
+
+	return connection, nil
+}
+
+// CreateConnection creates a new AsanaConnection.
+func (s *Service) CreateConnection(ctx context.Context, userID string, name string, accessToken string) (thunderdome.AsanaConnection, error) {
+	connection := thunderdome.AsanaConnection{}
+	secureToken, err := db.Encrypt(accessToken, s.AESHashKey)
+	if err != nil {
+		return connection, fmt.Errorf("error encountered creating asana_connection:  %v", err)
+	}
+
+	err = s.DB.QueryRowContext(ctx,
+		`INSERT INTO thunderdome.asana_connection
+				(user_id, name, access_token)
+				VALUES ($1, $2, $3)
+				RETURNING id, user_id, name, created_date, updated_date;`,
+		userID, name, secureToken,
+	).Scan(
+		&connection.ID, &connection.UserID, &connection.Name,
+		&connection.CreatedDate, &connection.UpdatedDate,
+	)
+	if err != nil {
+		return connection, fmt.Errorf("error encountered creating asana_connection:  %v", err)
+	}
+	connection.AccessToken = accessToken
+
+	return connection, nil
+}
+
+// UpdateConnection updates an existing AsanaConnection.
+func (s *Service) UpdateConnection(ctx context.Context, connectionID string, name string, accessToken string) (thunderdome.AsanaConnection, error) {
+	connection := thunderdome.AsanaConnection{}
+	secureToken, err := db.Encrypt(accessToken, s.AESHashKey)
+	if err != nil {
+		return connection, fmt.Errorf("error encountered updating asana_connection:  %v", err)
+	}
+
+	err = s.DB.QueryRowContext(ctx,
+		`UPDATE thunderdome.asana_connection
+				SET name = $2, access_token = $3, updated_date = NOW()
+				WHERE id = $1
+				RETURNING id, user_id, name, created_date, updated_date;`,
+		connectionID, name, secureToken,
+	).Scan(
+		&connection.ID, &connection.UserID, &connection.Name,
+		&connection.CreatedDate, &connection.UpdatedDate,
+	)
+	if err != nil {
+		return connection, fmt.Errorf("error encountered updating asana_connection:  %v", err)
+	}
+	connection.AccessToken = accessToken
+
+	return connection, nil
+}
+
+// DeleteConnection deletes an existing AsanaConnection.
+func (s *Service) DeleteConnection(ctx context.Context, connectionID string) error {
+	result, err := s.DB.ExecContext(ctx, `DELETE FROM thunderdome.asana_connection WHERE id = $1;`, connectionID)
+	if err != nil {
+		return fmt.Errorf("delete asana connection query error: %v", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("delete asana connection rows error: %v", err)
+	}
+	if rows != 1 {
+		return fmt.Errorf("delete asana connection expected to affect 1 row, affected %d", rows)
+	}
+
+	return nil
+}
@@ -0,0 +1,147 @@
+package asana
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+const (
+	// tasksPageLimit is the number of tasks requested per Asana API page
+	tasksPageLimit = 100
+	// tasksOptFields are the task fields requested from the Asana API
+	tasksOptFields = "name,notes,permalink_url,custom_fields.name,custom_fields.display_value"
+)
+
+// storyPointFieldNames are the (lowercased) Asana custom field names treated as the story point estimate
+var storyPointFieldNames = map[string]struct{}{
+	"story points": {},
+	"story point":  {},
+	"points":       {},
+	"estimate":     {},
+}
+
+type asanaCustomField struct {
+	Name         string  `json:"name"`
+	DisplayValue *string `json:"display_value"`
+}
+
+type asanaTask struct {
+	GID          string             `json:"gid"`
+	Name         string             `json:"name"`
+	Notes        string             `json:"notes"`
+	PermalinkURL string             `json:"permalink_url"`
+	CustomFields []asanaCustomField `json:"custom_fields"`
+}
+
+type asanaTasksPage struct {
+	Data     []asanaTask `json:"data"`
+	NextPage *struct {
+		Offset string `json:"offset"`
+	} `json:"next_page"`
+}
+
+// ImportTasksFromProject retrieves the tasks of an Asana project, or only those of the project section
+// when a section ID is provided, as poker stories using the connection's access token
+func (s *Service) ImportTasksFromProject(ctx context.Context, connectionID string, projectID string, sectionID *string) ([]*thunderdome.Story, error) {
+	connection, err := s.GetConnectionByID(ctx, connectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	tasksPath := "/projects/" + url.PathEscape(projectID) + "/tasks"
+	if sectionID != nil && *sectionID != "" {
+		tasksPath = "/sections/" + url.PathEscape(*sectionID) + "/tasks"
+	}
+
+	tasks, err := s.getTasks(ctx, connection.AccessToken, tasksPath)
+	if err != nil {
+		return nil, err
+	}
+
+	stories := make([]*thunderdome.Story, 0, len(tasks))
+	for _, task := range tasks {
+		stories = append(stories, taskToStory(task))
+	}
+
+	return stories, nil
+}
+
+// getTasks retrieves every page of tasks from the Asana API tasks endpoint
+func (s *Service) getTasks(ctx context.Context, accessToken string, tasksPath string) ([]asanaTask, error) {
+	apiURL := s.APIURL
+	if apiURL == "" {
+		apiURL = DefaultAPIURL
+	}
+	httpClient := s.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	tasks := make([]asanaTask, 0)
+	offset := ""
+	for {
+		query := url.Values{}
+		query.Set("limit", fmt.Sprintf("%d", tasksPageLimit))
+		query.Set("opt_fields", tasksOptFields)
+		if offset != "" {
+			query.Set("offset", offset)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(apiURL, "/")+tasksPath+"?"+query.Encode(), nil)
+		if err != nil {
+			return nil, fmt.Errorf("asana tasks request error: %v", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+		req.Header.Set("Accept", "application/json")
+
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("asana tasks request error: %v", err)
+		}
+
+		var page asanaTasksPage
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("asana tasks unexpected status: %d", resp.StatusCode)
+		}
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("asana tasks response decode error: %v", err)
+		}
+
+		tasks = append(tasks, page.Data...)
+
+		if page.NextPage == nil || page.NextPage.Offset == "" {
+			return tasks, nil
+		}
+		offset = page.NextPage.Offset
+	}
+}
+
+// taskToStory maps an Asana task to a poker story, the story point custom field becomes the estimate hint
+func taskToStory(task asanaTask) *thunderdome.Story {
+	story := &thunderdome.Story{
+		Name:        task.Name,
+		Type:        "Story",
+		ReferenceID: task.GID,
+		Link:        task.PermalinkURL,
+		Description: task.Notes,
+	}
+
+	for _, field := range task.CustomFields {
+		if _, ok := storyPointFieldNames[strings.ToLower(strings.TrimSpace(field.Name))]; ok && field.DisplayValue != nil {
+			story.EstimateHint = *field.DisplayValue
+			break
+		}
+	}
+
+	return story
+}
@@ -0,0 +1,107 @@
+package asana
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newMockAsanaServer returns an Asana API mock serving the project tasks in pages of 50
+func newMockAsanaServer(t *testing.T, totalTasks int) *httptest.Server {
+	t.Helper()
+	const pageSize = 50
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/projects/1201/tasks" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		start := 0
+		if offset := r.URL.Query().Get("offset"); offset != "" {
+			if _, err := fmt.Sscanf(offset, "page-%d", &start); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+		}
+		end := min(start+pageSize, totalTasks)
+
+		page := map[string]any{"next_page": nil}
+		data := make([]map[string]any, 0, pageSize)
+		for i := start; i < end; i++ {
+			points := fmt.Sprintf("%d", i%8)
+			data = append(data, map[string]any{
+				"gid":           fmt.Sprintf("task-%d", i),
+				"name":          fmt.Sprintf("Task %d", i),
+				"notes":         fmt.Sprintf("Notes %d", i),
+				"permalink_url": fmt.Sprintf("https://app.asana.com/0/1201/task-%d", i),
+				"custom_fields": []map[string]any{
+					{"name": "Priority", "display_value": "High"},
+					{"name": "Story Points", "display_value": points},
+				},
+			})
+		}
+		page["data"] = data
+		if end < totalTasks {
+			page["next_page"] = map[string]string{"offset": fmt.Sprintf("page-%d", end)}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(page)
+	}))
+}
+
+// TestGetTasksPagination makes sure every page of tasks is retrieved following next_page.offset
+func TestGetTasksPagination(t *testing.T) {
+	server := newMockAsanaServer(t, 100)
+	defer server.Close()
+
+	s := &Service{APIURL: server.URL, HTTPClient: server.Client()}
+	tasks, err := s.getTasks(context.Background(), "test-token", "/projects/1201/tasks")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(tasks) != 100 {
+		t.Fatalf("expected 100 tasks, got %d", len(tasks))
+	}
+	if tasks[0].GID != "task-0" || tasks[99].GID != "task-99" {
+		t.Errorf("expected tasks in order, got %s through %s", tasks[0].GID, tasks[99].GID)
+	}
+
+	story := taskToStory(tasks[51])
+	if story.Name != "Task 51" || story.Description != "Notes 51" || story.ReferenceID != "task-51" {
+		t.Errorf("unexpected story mapping %+v", story)
+	}
+	if story.Link != "https://app.asana.com/0/1201/task-51" {
+		t.Errorf("expected task permalink, got %s", story.Link)
+	}
+	if story.EstimateHint != "3" {
+		t.Errorf("expected estimate hint 3, got %q", story.EstimateHint)
+	}
+}
+
+// TestGetTasksUnauthorized makes sure Asana API errors are surfaced
+func TestGetTasksUnauthorized(t *testing.T) {
+	server := newMockAsanaServer(t, 100)
+	defer server.Close()
+
+	s := &Service{APIURL: server.URL, HTTPClient: server.Client()}
+	if _, err := s.getTasks(context.Background(), "bad-token", "/projects/1201/tasks"); err == nil {
+		t.Fatal("expected an error for an invalid access token")
+	}
+}
+
+// TestTaskToStoryWithoutEstimate makes sure tasks without a story point field have no estimate hint
+func TestTaskToStoryWithoutEstimate(t *testing.T) {
+	story := taskToStory(asanaTask{GID: "1", Name: "Task", CustomFields: []asanaCustomField{{Name: "Points"}}})
+	if story.EstimateHint != "" {
+		t.Errorf("expected no estimate hint, got %q", story.EstimateHint)
+	}
+}
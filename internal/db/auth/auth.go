@@ -34,7 +34,8 @@ func (d *Service) AuthUser(ctx context.Context, userEmail string, userPassword s
 
 	err := d.DB.QueryRowContext(ctx,
 		`SELECT u.id, u.name, c.email, u.type, c.password, u.avatar, c.verified, u.notifications_enabled,
- 			COALESCE(u.locale, ''), u.disabled, c.mfa_enabled, u.theme, COALESCE(u.picture, '')
+ 			COALESCE(u.locale, ''), u.disabled, c.mfa_enabled, u.theme, COALESCE(u.picture, ''),
+			u.registration_status, COALESCE(u.merged_into_user_id::text, '')
 			FROM thunderdome.auth_credential c
 			JOIN thunderdome.users u ON c.user_id = u.id
 			WHERE c.email = $1`,
@@ -53,6 +54,8 @@ func (d *Service) AuthUser(ctx context.Context, userEmail string, userPassword s
 		&cred.MFAEnabled,
 		&user.Theme,
 		&user.Picture,
+		&user.RegistrationStatus,
+		&user.MergedIntoUserID,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -67,10 +70,22 @@ func (d *Service) AuthUser(ctx context.Context, userEmail string, userPassword s
 		return nil, nil, "", errors.New("INVALID_PASSWORD")
 	}
 
+	if user.MergedIntoUserID != "" {
+		return nil, nil, "", thunderdome.ErrUserMerged
+	}
+
 	if user.Disabled {
 		return nil, nil, "", errors.New("USER_DISABLED")
 	}
 
+	if !user.RegistrationStatus.CanLogin() {
+		return nil, nil, "", thunderdome.ErrAccountPending
+	}
+
+	if err := d.CheckSSORequired(ctx, user.ID, thunderdome.AuthMethodPassword); err != nil {
+		return nil, nil, "", err
+	}
+
 	// check to see if the bcrypt cost has been updated, if not do so
 	if db.CheckPasswordCost(passHash) {
 		hashedPassword, hashErr := db.HashSaltPassword(userPassword)
@@ -82,7 +97,7 @@ func (d *Service) AuthUser(ctx context.Context, userEmail string, userPassword s
 		}
 	}
 
-	sessionID, sessErr := d.CreateSession(ctx, user.ID, !cred.MFAEnabled)
+	sessionID, sessErr := d.CreateSession(ctx, user.ID, !cred.MFAEnabled, thunderdome.AuthMethodPassword)
 	if sessErr != nil {
 		return nil, nil, "", sessErr
 	}
@@ -48,6 +48,16 @@ func (d *Service) OauthValidateNonce(ctx context.Context, nonceID string) error
 
 // OauthAuthUser authenticate the oauth user or creates a new user
 func (d *Service) OauthAuthUser(ctx context.Context, provider string, sub string, email string, emailVerified bool, name string, pictureUrl string) (*thunderdome.User, string, error) {
+	return d.identityAuthUser(ctx, provider, sub, email, emailVerified, name, pictureUrl, thunderdome.AuthMethodOIDC)
+}
+
+// SAMLAuthUser authenticate the saml user by their NameID (email) or creates a new user
+func (d *Service) SAMLAuthUser(ctx context.Context, nameID string, name string) (*thunderdome.User, string, error) {
+	return d.identityAuthUser(ctx, thunderdome.AuthMethodSAML, nameID, nameID, true, name, "", thunderdome.AuthMethodSAML)
+}
+
+// identityAuthUser authenticate the external identity user or creates a new user, creating a session with the auth method
+func (d *Service) identityAuthUser(ctx context.Context, provider string, sub string, email string, emailVerified bool, name string, pictureUrl string, authMethod string) (*thunderdome.User, string, error) {
 	var user thunderdome.User
 
 	err := d.DB.QueryRowContext(ctx,
@@ -116,7 +126,7 @@ func (d *Service) OauthAuthUser(ctx context.Context, provider string, sub string
 		return nil, "", errors.New("USER_DISABLED")
 	}
 
-	sessionID, sessErr := d.CreateSession(ctx, user.ID, true)
+	sessionID, sessErr := d.CreateSession(ctx, user.ID, true, authMethod)
 	if sessErr != nil {
 		return nil, "", sessErr
 	}
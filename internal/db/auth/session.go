@@ -3,25 +3,27 @@ package auth
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/StevenWeathers/thunderdome-planning-poker/internal/db"
 
 	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
 )
 
-// CreateSession creates a new user authenticated session
-func (d *Service) CreateSession(ctx context.Context, userID string, enabled bool) (string, error) {
+// CreateSession creates a new user authenticated session, recording the auth method used
+func (d *Service) CreateSession(ctx context.Context, userID string, enabled bool, authMethod string) (string, error) {
 	sessionID, err := db.RandomBase64String(32)
 	if err != nil {
 		return "", err
 	}
 
 	if _, sessionErr := d.DB.ExecContext(ctx, `
-		INSERT INTO thunderdome.user_session (session_id, user_id, disabled) VALUES ($1, $2, $3);
+		INSERT INTO thunderdome.user_session (session_id, user_id, disabled, auth_method) VALUES ($1, $2, $3, $4);
 		`,
 		sessionID,
 		userID,
 		enabled,
+		authMethod,
 	); sessionErr != nil {
 		return "", fmt.Errorf("create user session query error: %v", sessionErr)
 	}
@@ -29,6 +31,47 @@ func (d *Service) CreateSession(ctx context.Context, userID string, enabled bool
 	return sessionID, nil
 }
 
+// CheckSSORequired checks whether the user belongs to an organization that requires SSO
+// and if so whether the auth method is allowed, users marked as SSO exempt (e.g. admin created) are skipped
+func (d *Service) CheckSSORequired(ctx context.Context, userID string, authMethod string) error {
+	rows, err := d.DB.QueryContext(ctx, `
+		SELECT u.sso_exempt, ou.allowed_auth_methods IS NULL, COALESCE(array_to_string(ou.allowed_auth_methods, ','), '')
+		FROM thunderdome.organization_user ou
+		JOIN thunderdome.organization o ON o.id = ou.organization_id
+		JOIN thunderdome.users u ON u.id = ou.user_id
+		WHERE ou.user_id = $1 AND o.sso_required = true;`,
+		userID,
+	)
+	if err != nil {
+		return fmt.Errorf("check user sso required query error: %v", err)
+	}
+	defer rows.Close()
+
+	var ssoExempt bool
+	orgAllowedAuthMethods := make([][]string, 0)
+	for rows.Next() {
+		var defaultMethods bool
+		var methods string
+		if err := rows.Scan(&ssoExempt, &defaultMethods, &methods); err != nil {
+			return fmt.Errorf("check user sso required query scan error: %v", err)
+		}
+
+		var allowed []string
+		if !defaultMethods {
+			allowed = make([]string, 0)
+			if methods != "" {
+				allowed = strings.Split(methods, ",")
+			}
+		}
+		orgAllowedAuthMethods = append(orgAllowedAuthMethods, allowed)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("check user sso required query error: %v", err)
+	}
+
+	return thunderdome.CheckSSOAuthMethod(authMethod, ssoExempt, orgAllowedAuthMethods)
+}
+
 // EnableSession enables a user authenticated session
 func (d *Service) EnableSession(ctx context.Context, sessionID string) error {
 	if _, sessionErr := d.DB.ExecContext(ctx, `
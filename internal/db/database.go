@@ -3,6 +3,7 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"embed"
 	"fmt"
 	"time"
@@ -38,8 +39,7 @@ func New(adminEmail string, config *Config, logger *otelzap.Logger) *Service {
 		Logger:              logger,
 	}
 
-	psqlInfo := fmt.Sprintf(
-		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+	psqlInfo := connectionString(
 		d.Config.Host,
 		d.Config.Port,
 		d.Config.User,
@@ -59,6 +59,14 @@ func New(adminEmail string, config *Config, logger *otelzap.Logger) *Service {
 	d.DB.SetMaxIdleConns(d.Config.MaxIdleConns)
 	d.DB.SetConnMaxLifetime(time.Duration(d.Config.ConnMaxLifetime) * time.Minute)
 
+	if d.Config.ReadReplicaHost != "" {
+		d.ReadDB = NewReadReplica(d.Config)
+		if d.ReadDB == nil {
+			d.Logger.Ctx(ctx).Error("error connecting to the read replica, reads will use the primary database",
+				zap.String("read_replica_host", d.Config.ReadReplicaHost))
+		}
+	}
+
 	err = otelsql.RegisterDBStatsMetrics(pdb, otelsql.WithAttributes(
 		semconv.DBSystemPostgreSQL,
 	))
@@ -77,6 +85,7 @@ func New(adminEmail string, config *Config, logger *otelzap.Logger) *Service {
 	if err := goose.Up(d.DB, "migrations", goose.WithAllowMissing()); err != nil {
 		d.Logger.Ctx(ctx).Error("migrations error", zap.Error(err))
 	}
+	d.logMigrationStatus(ctx)
 
 	// on server start reset all users to active false for games
 	if _, err := d.DB.Exec(
@@ -108,3 +117,44 @@ func New(adminEmail string, config *Config, logger *otelzap.Logger) *Service {
 
 	return d
 }
+
+// NewReadReplica creates a connection pool to the read replica, returning nil when no replica is configured
+// or the connection can't be opened so callers fall back to the primary database
+func NewReadReplica(config *Config) *sql.DB {
+	if config.ReadReplicaHost == "" {
+		return nil
+	}
+
+	rdb, err := otelsql.Open("pgx", replicaConnectionString(config), otelsql.WithAttributes(
+		semconv.DBSystemPostgreSQL,
+	))
+	if err != nil {
+		return nil
+	}
+	rdb.SetMaxOpenConns(config.MaxOpenConns)
+	rdb.SetMaxIdleConns(config.MaxIdleConns)
+	rdb.SetConnMaxLifetime(time.Duration(config.ConnMaxLifetime) * time.Minute)
+
+	return rdb
+}
+
+// replicaConnectionString builds the read replica connection string,
+// the port, user and password default to the primary database's
+func replicaConnectionString(config *Config) string {
+	port, user, password := config.ReadReplicaPort, config.ReadReplicaUser, config.ReadReplicaPassword
+	if port == 0 {
+		port = config.Port
+	}
+	if user == "" {
+		user, password = config.User, config.Password
+	}
+
+	return connectionString(config.ReadReplicaHost, port, user, password, config.Name, config.SSLMode)
+}
+
+func connectionString(host string, port int, user string, password string, name string, sslMode string) string {
+	return fmt.Sprintf(
+		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		host, port, user, password, name, sslMode,
+	)
+}
@@ -14,7 +14,8 @@ func (s *Service) FindInstancesByUserID(ctx context.Context, userID string) ([]t
 	instances := make([]thunderdome.JiraInstance, 0)
 
 	rows, err := s.DB.QueryContext(ctx,
-		`SELECT id, user_id, host, client_mail, access_token, jira_data_center, created_date, updated_date
+		`SELECT id, user_id, host, client_mail, access_token, jira_data_center, story_points_field_id,
+ 				created_date, updated_date
  				FROM thunderdome.jira_instance WHERE user_id = $1;`,
 		userID,
 	)
@@ -27,7 +28,7 @@ func (s *Service) FindInstancesByUserID(ctx context.Context, userID string) ([]t
 		instance := thunderdome.JiraInstance{}
 		if err := rows.Scan(
 			&instance.ID, &instance.UserID, &instance.Host, &instance.ClientMail, &instance.AccessToken, &instance.JiraDataCenter,
-			&instance.CreatedDate, &instance.UpdatedDate,
+			&instance.StoryPointsFieldID, &instance.CreatedDate, &instance.UpdatedDate,
 		); err != nil {
 			return instances, fmt.Errorf("find jira instance by user id row scan error: %v", err)
 		}
@@ -46,12 +47,13 @@ func (s *Service) GetInstanceByID(ctx context.Context, instanceID string) (thund
 	instance := thunderdome.JiraInstance{}
 
 	err := s.DB.QueryRowContext(ctx,
-		`SELECT id, user_id, host, client_mail, access_token, jira_data_center, created_date, updated_date
+		`SELECT id, user_id, host, client_mail, access_token, jira_data_center, story_points_field_id,
+ 				created_date, updated_date
  				FROM thunderdome.jira_instance WHERE id = $1;`,
 		instanceID,
 	).Scan(
 		&instance.ID, &instance.UserID, &instance.Host, &instance.ClientMail, &instance.AccessToken, &instance.JiraDataCenter,
-		&instance.CreatedDate, &instance.UpdatedDate,
+		&instance.StoryPointsFieldID, &instance.CreatedDate, &instance.UpdatedDate,
 	)
 	if err != nil {
 		return instance, fmt.Errorf("error encountered getting jira_instance %s:  %v", instanceID, err)
@@ -65,7 +67,7 @@ func (s *Service) GetInstanceByID(ctx context.Context, instanceID string) (thund
 }
 
 // CreateInstance creates a new JiraInstance.
-func (s *Service) CreateInstance(ctx context.Context, userID string, host string, clientMail string, accessToken string, jiraDataCenter bool) (thunderdome.JiraInstance, error) {
+func (s *Service) CreateInstance(ctx context.Context, userID string, host string, clientMail string, accessToken string, jiraDataCenter bool, storyPointsFieldID string) (thunderdome.JiraInstance, error) {
 	instance := thunderdome.JiraInstance{}
 	secureToken, err := db.Encrypt(accessToken, s.AESHashKey)
 	if err != nil {
@@ -74,13 +76,14 @@ func (s *Service) CreateInstance(ctx context.Context, userID string, host string
 
 	err = s.DB.QueryRowContext(ctx,
 		`INSERT INTO thunderdome.jira_instance
-				(user_id, host, client_mail, access_token, jira_data_center)
-				VALUES ($1, $2, $3, $4, $5)
-				RETURNING id, user_id, host, client_mail, access_token, jira_data_center, created_date, updated_date;`,
-		userID, host, clientMail, secureToken, jiraDataCenter,
+				(user_id, host, client_mail, access_token, jira_data_center, story_points_field_id)
+				VALUES ($1, $2, $3, $4, $5, $6)
+				RETURNING id, user_id, host, client_mail, access_token, jira_data_center, story_points_field_id,
+				created_date, updated_date;`,
+		userID, host, clientMail, secureToken, jiraDataCenter, storyPointsFieldID,
 	).Scan(
 		&instance.ID, &instance.UserID, &instance.Host, &instance.ClientMail, &instance.AccessToken, &instance.JiraDataCenter,
-		&instance.CreatedDate, &instance.UpdatedDate,
+		&instance.StoryPointsFieldID, &instance.CreatedDate, &instance.UpdatedDate,
 	)
 	if err != nil {
 		return instance, fmt.Errorf("error encountered creating jira_instance:  %v", err)
@@ -90,7 +93,7 @@ func (s *Service) CreateInstance(ctx context.Context, userID string, host string
 }
 
 // UpdateInstance updates an existing JiraInstance.
-func (s *Service) UpdateInstance(ctx context.Context, instanceID string, host string, clientMail string, accessToken string) (thunderdome.JiraInstance, error) {
+func (s *Service) UpdateInstance(ctx context.Context, instanceID string, host string, clientMail string, accessToken string, storyPointsFieldID string) (thunderdome.JiraInstance, error) {
 	instance := thunderdome.JiraInstance{}
 	at, err := db.Encrypt(accessToken, s.AESHashKey)
 	if err != nil {
@@ -99,13 +102,13 @@ func (s *Service) UpdateInstance(ctx context.Context, instanceID string, host st
 
 	err = s.DB.QueryRowContext(ctx,
 		`UPDATE thunderdome.jira_instance
-				SET host = $2, client_mail = $3, access_token = $4
+				SET host = $2, client_mail = $3, access_token = $4, story_points_field_id = $5
 				WHERE id = $1
-				RETURNING id, user_id, host, client_mail, access_token, created_date, updated_date;`,
-		instanceID, host, clientMail, at,
+				RETURNING id, user_id, host, client_mail, access_token, story_points_field_id, created_date, updated_date;`,
+		instanceID, host, clientMail, at, storyPointsFieldID,
 	).Scan(
 		&instance.ID, &instance.UserID, &instance.Host, &instance.ClientMail, &instance.AccessToken,
-		&instance.CreatedDate, &instance.UpdatedDate,
+		&instance.StoryPointsFieldID, &instance.CreatedDate, &instance.UpdatedDate,
 	)
 	if err != nil {
 		return instance, fmt.Errorf("error encountered updating jira_instance:  %v", err)
@@ -0,0 +1,82 @@
+package jira
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// UpdateJiraIssuePoints sets the story points of the Jira issue using the instance's story points field
+func (s *Service) UpdateJiraIssuePoints(ctx context.Context, instanceID string, issueKey string, points string) error {
+	instance, err := s.GetInstanceByID(ctx, instanceID)
+	if err != nil {
+		return err
+	}
+
+	return s.updateIssuePoints(ctx, instance, issueKey, points)
+}
+
+// updateIssuePoints edits the Jira issue's story points field, Jira Data Center only supports the v2 REST API
+// and authenticates with a personal access token while Jira Cloud uses the account email and API token
+func (s *Service) updateIssuePoints(ctx context.Context, instance thunderdome.JiraInstance, issueKey string, points string) error {
+	storyPoints, err := strconv.ParseFloat(points, 64)
+	if err != nil {
+		return fmt.Errorf("JIRA_POINTS_NOT_NUMERIC")
+	}
+
+	fieldID := instance.StoryPointsFieldID
+	if fieldID == "" {
+		fieldID = DefaultStoryPointsFieldID
+	}
+	body, err := json.Marshal(map[string]any{
+		"fields": map[string]any{fieldID: storyPoints},
+	})
+	if err != nil {
+		return fmt.Errorf("jira issue points encode error: %v", err)
+	}
+
+	apiVersion := "3"
+	if instance.JiraDataCenter {
+		apiVersion = "2"
+	}
+	issueURL := strings.TrimSuffix(instance.Host, "/") + "/rest/api/" + apiVersion + "/issue/" + url.PathEscape(issueKey)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, issueURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("jira issue points request error: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if instance.JiraDataCenter {
+		req.Header.Set("Authorization", "Bearer "+instance.AccessToken)
+	} else {
+		req.SetBasicAuth(instance.ClientMail, instance.AccessToken)
+	}
+
+	httpClient := s.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("jira issue points request error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("JIRA_ISSUE_NOT_FOUND")
+	case resp.StatusCode >= 300:
+		return fmt.Errorf("jira issue points unexpected status: %d", resp.StatusCode)
+	}
+
+	return nil
+}
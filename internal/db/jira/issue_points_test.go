@@ -0,0 +1,102 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// newMockJiraServer returns a Jira API mock that only knows the TD-1 issue, recording its edited fields
+func newMockJiraServer(t *testing.T, edited map[string]any) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		switch r.URL.Path {
+		case "/rest/api/3/issue/TD-1":
+			if user, token, ok := r.BasicAuth(); !ok || user != "dev@example.com" || token != "test-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+		case "/rest/api/2/issue/TD-1":
+			if r.Header.Get("Authorization") != "Bearer test-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errorMessages":["Issue does not exist or you do not have permission to see it."]}`))
+			return
+		}
+
+		var body struct {
+			Fields map[string]any `json:"fields"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		for field, value := range body.Fields {
+			edited[field] = value
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+}
+
+func TestUpdateIssuePoints(t *testing.T) {
+	tests := []struct {
+		name          string
+		dataCenter    bool
+		fieldID       string
+		issueKey      string
+		points        string
+		expectedField string
+		expectedError string
+	}{
+		{name: "cloud default field", issueKey: "TD-1", points: "5", expectedField: DefaultStoryPointsFieldID},
+		{name: "configured field", fieldID: "customfield_10028", issueKey: "TD-1", points: "0.5", expectedField: "customfield_10028"},
+		{name: "data center", dataCenter: true, fieldID: "customfield_10002", issueKey: "TD-1", points: "8", expectedField: "customfield_10002"},
+		{name: "issue not found", issueKey: "TD-404", points: "3", expectedError: "JIRA_ISSUE_NOT_FOUND"},
+		{name: "non numeric points", issueKey: "TD-1", points: "?", expectedError: "JIRA_POINTS_NOT_NUMERIC"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			edited := make(map[string]any)
+			server := newMockJiraServer(t, edited)
+			defer server.Close()
+
+			s := &Service{HTTPClient: server.Client()}
+			err := s.updateIssuePoints(context.Background(), thunderdome.JiraInstance{
+				Host:               server.URL + "/",
+				ClientMail:         "dev@example.com",
+				AccessToken:        "test-token",
+				JiraDataCenter:     tt.dataCenter,
+				StoryPointsFieldID: tt.fieldID,
+			}, tt.issueKey, tt.points)
+
+			if tt.expectedError != "" {
+				if err == nil || err.Error() != tt.expectedError {
+					t.Fatalf("expected error %s, got %v", tt.expectedError, err)
+				}
+				if len(edited) != 0 {
+					t.Errorf("expected no issue to be edited, got %v", edited)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if len(edited) != 1 || edited[tt.expectedField] == nil {
+				t.Fatalf("expected only %s to be edited, got %v", tt.expectedField, edited)
+			}
+		})
+	}
+}
@@ -2,13 +2,19 @@ package jira
 
 import (
 	"database/sql"
+	"net/http"
 
 	"github.com/uptrace/opentelemetry-go-extra/otelzap"
 )
 
+// DefaultStoryPointsFieldID is the Jira Cloud story points custom field, used when the instance doesn't set one
+const DefaultStoryPointsFieldID = "customfield_10016"
+
 // Service represents the JIRA database service
 type Service struct {
 	DB         *sql.DB
 	Logger     *otelzap.Logger
 	AESHashKey string
+	// HTTPClient is used for Jira API requests, defaults to a client with a 10 second timeout
+	HTTPClient *http.Client
 }
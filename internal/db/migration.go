@@ -0,0 +1,150 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path"
+	"sort"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/pressly/goose/v3"
+	"go.uber.org/zap"
+)
+
+// MigrationVersions gets the versions of the embedded migrations in ascending order
+func MigrationVersions() ([]int64, error) {
+	entries, err := fs.ReadDir("migrations")
+	if err != nil {
+		return nil, fmt.Errorf("list migrations error: %v", err)
+	}
+
+	versions := make([]int64, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".sql" {
+			continue
+		}
+		version, err := goose.NumericComponent(entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("parse migration version error: %v", err)
+		}
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] < versions[j] })
+
+	return versions, nil
+}
+
+// GetMigrationStatus gets the applied migrations from the goose version table and how many embedded migrations
+// are still pending, the status is degraded when the version table doesn't exist
+func GetMigrationStatus(ctx context.Context, conn *sql.DB) (*thunderdome.MigrationStatus, error) {
+	versions, err := MigrationVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	var tableExists bool
+	err = conn.QueryRowContext(ctx,
+		`SELECT to_regclass($1) IS NOT NULL;`, goose.TableName(),
+	).Scan(&tableExists)
+	if err != nil {
+		return nil, fmt.Errorf("get migration table query error: %v", err)
+	}
+	if !tableExists {
+		status := computeMigrationStatus(versions, nil)
+		status.Degraded = true
+		return status, nil
+	}
+
+	// goose records a row per up or down migration so the latest row per version is its current state
+	rows, err := conn.QueryContext(ctx, fmt.Sprintf(
+		`SELECT DISTINCT ON (version_id) version_id, is_applied, tstamp
+		FROM %s
+		WHERE version_id > 0
+		ORDER BY version_id, id DESC;`, goose.TableName()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get migrations query error: %v", err)
+	}
+	defer rows.Close()
+
+	applied := make([]*thunderdome.MigrationRecord, 0)
+	for rows.Next() {
+		var version int64
+		var isApplied bool
+		var appliedAt sql.NullTime
+		if err := rows.Scan(&version, &isApplied, &appliedAt); err != nil {
+			return nil, fmt.Errorf("get migrations query scan error: %v", err)
+		}
+		if !isApplied {
+			continue
+		}
+		record := &thunderdome.MigrationRecord{Version: version, Status: thunderdome.MigrationStatusApplied}
+		if appliedAt.Valid {
+			record.AppliedAt = &appliedAt.Time
+		}
+		applied = append(applied, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get migrations query rows error: %v", err)
+	}
+
+	return computeMigrationStatus(versions, applied), nil
+}
+
+// computeMigrationStatus combines the applied migration records with the available migration versions,
+// any available version without an applied record is pending
+func computeMigrationStatus(versions []int64, applied []*thunderdome.MigrationRecord) *thunderdome.MigrationStatus {
+	status := &thunderdome.MigrationStatus{
+		MigrationHistory: make([]*thunderdome.MigrationRecord, 0, len(versions)),
+	}
+
+	appliedVersions := make(map[int64]bool, len(applied))
+	for _, record := range applied {
+		appliedVersions[record.Version] = true
+		if record.Version > status.Current {
+			status.Current = record.Version
+		}
+		status.MigrationHistory = append(status.MigrationHistory, record)
+	}
+
+	for _, version := range versions {
+		if appliedVersions[version] {
+			continue
+		}
+		status.PendingCount++
+		status.MigrationHistory = append(status.MigrationHistory, &thunderdome.MigrationRecord{
+			Version: version,
+			Status:  thunderdome.MigrationStatusPending,
+		})
+	}
+
+	sort.SliceStable(status.MigrationHistory, func(i, j int) bool {
+		return status.MigrationHistory[i].Version < status.MigrationHistory[j].Version
+	})
+
+	return status
+}
+
+// logMigrationStatus logs the migration status so operators can confirm migrations ran on startup
+func (d *Service) logMigrationStatus(ctx context.Context) {
+	status, err := GetMigrationStatus(ctx, d.DB)
+	if err != nil {
+		d.Logger.Ctx(ctx).Error("get migration status error", zap.Error(err))
+		return
+	}
+
+	logger := d.Logger.Ctx(ctx)
+	fields := []zap.Field{
+		zap.Int64("migration_version", status.Current),
+		zap.Int("migration_pending_count", status.PendingCount),
+	}
+	switch {
+	case status.Degraded:
+		logger.Warn("migration status degraded, migration table not found", fields...)
+	case status.PendingCount > 0:
+		logger.Warn("migrations pending after startup", fields...)
+	default:
+		logger.Info("migrations up to date", fields...)
+	}
+}
@@ -0,0 +1,105 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// TestComputeMigrationStatus makes sure pending migrations are the available versions without an applied record
+func TestComputeMigrationStatus(t *testing.T) {
+	appliedAt := time.Date(2025, 3, 15, 17, 0, 0, 0, time.UTC)
+	versions := []int64{20230823231905, 20240906151222, 20250315170412, 20250315180000}
+
+	tests := []struct {
+		name            string
+		applied         []*thunderdome.MigrationRecord
+		expectedCurrent int64
+		expectedPending int
+	}{
+		{
+			name:            "none applied",
+			applied:         nil,
+			expectedCurrent: 0,
+			expectedPending: 4,
+		},
+		{
+			name: "partially applied",
+			applied: []*thunderdome.MigrationRecord{
+				{Version: 20230823231905, Status: thunderdome.MigrationStatusApplied, AppliedAt: &appliedAt},
+				{Version: 20240906151222, Status: thunderdome.MigrationStatusApplied, AppliedAt: &appliedAt},
+			},
+			expectedCurrent: 20240906151222,
+			expectedPending: 2,
+		},
+		{
+			name: "missing migration applied out of order",
+			applied: []*thunderdome.MigrationRecord{
+				{Version: 20230823231905, Status: thunderdome.MigrationStatusApplied, AppliedAt: &appliedAt},
+				{Version: 20250315180000, Status: thunderdome.MigrationStatusApplied, AppliedAt: &appliedAt},
+			},
+			expectedCurrent: 20250315180000,
+			expectedPending: 2,
+		},
+		{
+			name: "all applied",
+			applied: []*thunderdome.MigrationRecord{
+				{Version: 20230823231905, Status: thunderdome.MigrationStatusApplied, AppliedAt: &appliedAt},
+				{Version: 20240906151222, Status: thunderdome.MigrationStatusApplied, AppliedAt: &appliedAt},
+				{Version: 20250315170412, Status: thunderdome.MigrationStatusApplied, AppliedAt: &appliedAt},
+				{Version: 20250315180000, Status: thunderdome.MigrationStatusApplied, AppliedAt: &appliedAt},
+			},
+			expectedCurrent: 20250315180000,
+			expectedPending: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := computeMigrationStatus(versions, tt.applied)
+
+			if status.Current != tt.expectedCurrent {
+				t.Errorf("expected current %d, got %d", tt.expectedCurrent, status.Current)
+			}
+			if status.PendingCount != tt.expectedPending {
+				t.Errorf("expected pending count %d, got %d", tt.expectedPending, status.PendingCount)
+			}
+			if len(status.MigrationHistory) != len(versions) {
+				t.Fatalf("expected %d migration history records, got %d", len(versions), len(status.MigrationHistory))
+			}
+
+			pending := 0
+			for i, record := range status.MigrationHistory {
+				if i > 0 && record.Version < status.MigrationHistory[i-1].Version {
+					t.Errorf("expected migration history in ascending version order")
+				}
+				if record.Status == thunderdome.MigrationStatusPending {
+					pending++
+					if record.AppliedAt != nil {
+						t.Errorf("expected pending migration %d to have no applied at", record.Version)
+					}
+				}
+			}
+			if pending != tt.expectedPending {
+				t.Errorf("expected %d pending history records, got %d", tt.expectedPending, pending)
+			}
+		})
+	}
+}
+
+// TestMigrationVersions makes sure the embedded migration versions are parsed in ascending order
+func TestMigrationVersions(t *testing.T) {
+	versions, err := MigrationVersions()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(versions) == 0 {
+		t.Fatal("expected embedded migration versions")
+	}
+	for i := 1; i < len(versions); i++ {
+		if versions[i] <= versions[i-1] {
+			t.Errorf("expected versions in ascending order, got %d after %d", versions[i], versions[i-1])
+		}
+	}
+}
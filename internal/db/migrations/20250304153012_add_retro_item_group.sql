@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE thunderdome.retro_group ADD COLUMN color character varying(32);
+
+CREATE TABLE IF NOT EXISTS thunderdome.retro_item_group (
+    item_id uuid NOT NULL PRIMARY KEY REFERENCES thunderdome.retro_item(id) ON DELETE CASCADE,
+    group_id uuid NOT NULL REFERENCES thunderdome.retro_group(id) ON DELETE CASCADE,
+    created_date timestamp with time zone DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS retro_item_group_group_id_idx ON thunderdome.retro_item_group USING btree (group_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS thunderdome.retro_item_group;
+ALTER TABLE thunderdome.retro_group DROP COLUMN color;
+-- +goose StatementEnd
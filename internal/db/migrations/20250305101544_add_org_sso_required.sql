@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE thunderdome.organization ADD COLUMN sso_required boolean NOT NULL DEFAULT false;
+ALTER TABLE thunderdome.organization_user ADD COLUMN allowed_auth_methods character varying(16)[];
+ALTER TABLE thunderdome.users ADD COLUMN sso_exempt boolean NOT NULL DEFAULT false;
+ALTER TABLE thunderdome.user_session ADD COLUMN auth_method character varying(16);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE thunderdome.user_session DROP COLUMN auth_method;
+ALTER TABLE thunderdome.users DROP COLUMN sso_exempt;
+ALTER TABLE thunderdome.organization_user DROP COLUMN allowed_auth_methods;
+ALTER TABLE thunderdome.organization DROP COLUMN sso_required;
+-- +goose StatementEnd
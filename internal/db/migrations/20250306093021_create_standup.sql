@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS thunderdome.standup (
+    id uuid DEFAULT gen_random_uuid() NOT NULL PRIMARY KEY,
+    team_id uuid NOT NULL REFERENCES thunderdome.team(id) ON DELETE CASCADE,
+    user_id uuid NOT NULL REFERENCES thunderdome.users(id) ON DELETE CASCADE,
+    yesterday text,
+    today text,
+    blockers text,
+    standup_date date DEFAULT CURRENT_DATE NOT NULL,
+    created_date timestamp with time zone DEFAULT now() NOT NULL,
+    updated_date timestamp with time zone DEFAULT now() NOT NULL,
+    UNIQUE (team_id, user_id, standup_date)
+);
+CREATE INDEX IF NOT EXISTS standup_team_id_standup_date_idx ON thunderdome.standup (team_id, standup_date);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS thunderdome.standup;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE thunderdome.retro ADD COLUMN submission_phase boolean NOT NULL DEFAULT false;
+ALTER TABLE thunderdome.retro ADD COLUMN submission_deadline timestamp with time zone;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE thunderdome.retro DROP COLUMN submission_deadline;
+ALTER TABLE thunderdome.retro DROP COLUMN submission_phase;
+-- +goose StatementEnd
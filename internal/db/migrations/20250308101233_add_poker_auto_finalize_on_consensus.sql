@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE thunderdome.poker ADD COLUMN auto_finalize_on_consensus boolean NOT NULL DEFAULT false;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE thunderdome.poker DROP COLUMN auto_finalize_on_consensus;
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE thunderdome.poker ADD COLUMN observer_code text;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE thunderdome.poker DROP COLUMN observer_code;
+-- +goose StatementEnd
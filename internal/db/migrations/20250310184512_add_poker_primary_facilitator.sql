@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE thunderdome.poker_facilitator ADD COLUMN is_primary boolean DEFAULT false NOT NULL;
+ALTER TABLE thunderdome.poker_facilitator ADD COLUMN created_date timestamp with time zone DEFAULT now() NOT NULL;
+UPDATE thunderdome.poker_facilitator pf SET is_primary = true
+    FROM thunderdome.poker p WHERE p.id = pf.poker_id AND p.owner_id = pf.user_id;
+UPDATE thunderdome.poker_facilitator pf SET is_primary = true
+    WHERE pf.user_id = (
+        SELECT f.user_id FROM thunderdome.poker_facilitator f
+        WHERE f.poker_id = pf.poker_id ORDER BY f.user_id LIMIT 1
+    ) AND NOT EXISTS (
+        SELECT 1 FROM thunderdome.poker_facilitator f
+        WHERE f.poker_id = pf.poker_id AND f.is_primary = true
+    );
+CREATE UNIQUE INDEX IF NOT EXISTS poker_facilitator_primary_idx
+    ON thunderdome.poker_facilitator (poker_id) WHERE is_primary = true;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS thunderdome.poker_facilitator_primary_idx;
+ALTER TABLE thunderdome.poker_facilitator DROP COLUMN created_date;
+ALTER TABLE thunderdome.poker_facilitator DROP COLUMN is_primary;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE thunderdome.asana_connection (
+    id uuid DEFAULT gen_random_uuid() NOT NULL PRIMARY KEY,
+    user_id uuid NOT NULL REFERENCES thunderdome.users(id) ON DELETE CASCADE,
+    name text NOT NULL,
+    access_token text NOT NULL,
+    created_date timestamp with time zone NOT NULL DEFAULT now(),
+    updated_date timestamp with time zone NOT NULL DEFAULT now()
+);
+CREATE INDEX asana_connection_user_id_idx ON thunderdome.asana_connection (user_id);
+ALTER TABLE thunderdome.poker_story ADD COLUMN estimate_hint text;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE thunderdome.poker_story DROP COLUMN estimate_hint;
+DROP TABLE thunderdome.asana_connection;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE thunderdome.poker_story_facilitator_note (
+    story_id uuid NOT NULL PRIMARY KEY REFERENCES thunderdome.poker_story(id) ON DELETE CASCADE,
+    notes character varying(10000) NOT NULL,
+    updated_by uuid REFERENCES thunderdome.users(id) ON DELETE SET NULL,
+    created_date timestamp with time zone NOT NULL DEFAULT now(),
+    updated_date timestamp with time zone NOT NULL DEFAULT now()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE thunderdome.poker_story_facilitator_note;
+-- +goose StatementEnd
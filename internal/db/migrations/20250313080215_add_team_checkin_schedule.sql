@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE thunderdome.team_checkin_schedule (
+    team_id uuid NOT NULL PRIMARY KEY REFERENCES thunderdome.team(id) ON DELETE CASCADE,
+    cron_expression character varying(128) NOT NULL,
+    timezone_offset integer NOT NULL DEFAULT 0,
+    reminder_enabled boolean NOT NULL DEFAULT true,
+    created_date timestamp with time zone NOT NULL DEFAULT now(),
+    updated_date timestamp with time zone NOT NULL DEFAULT now()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE thunderdome.team_checkin_schedule;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE thunderdome.retro_template ADD COLUMN is_public_marketplace boolean NOT NULL DEFAULT false;
+ALTER TABLE thunderdome.retro_template ADD COLUMN marketplace_downloads integer NOT NULL DEFAULT 0;
+CREATE INDEX retro_template_marketplace_idx ON thunderdome.retro_template (marketplace_downloads DESC) WHERE is_public_marketplace = true;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX thunderdome.retro_template_marketplace_idx;
+ALTER TABLE thunderdome.retro_template DROP COLUMN marketplace_downloads;
+ALTER TABLE thunderdome.retro_template DROP COLUMN is_public_marketplace;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE thunderdome.api_key_usage (
+    api_key_id text NOT NULL REFERENCES thunderdome.api_key(id) ON DELETE CASCADE,
+    date date NOT NULL,
+    endpoint character varying(256) NOT NULL,
+    call_count integer NOT NULL DEFAULT 0,
+    PRIMARY KEY (api_key_id, date, endpoint)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE thunderdome.api_key_usage;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE thunderdome.poker_story_vote_round (
+    id uuid DEFAULT gen_random_uuid() NOT NULL PRIMARY KEY,
+    poker_id uuid NOT NULL REFERENCES thunderdome.poker(id) ON DELETE CASCADE,
+    story_id uuid NOT NULL REFERENCES thunderdome.poker_story(id) ON DELETE CASCADE,
+    round integer NOT NULL,
+    votes jsonb DEFAULT '[]'::jsonb NOT NULL,
+    votestart_time timestamp with time zone NOT NULL,
+    voteend_time timestamp with time zone NOT NULL,
+    created_date timestamp with time zone DEFAULT now(),
+    UNIQUE (story_id, round)
+);
+CREATE INDEX poker_story_vote_round_poker_id_idx ON thunderdome.poker_story_vote_round (poker_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE thunderdome.poker_story_vote_round;
+-- +goose StatementEnd
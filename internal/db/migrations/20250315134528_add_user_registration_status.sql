@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE thunderdome.users ADD COLUMN registration_status character varying(16) NOT NULL DEFAULT 'approved';
+ALTER TABLE thunderdome.users ADD COLUMN registration_rejected_reason text;
+CREATE INDEX users_registration_status_idx ON thunderdome.users (registration_status) WHERE registration_status <> 'approved';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX thunderdome.users_registration_status_idx;
+ALTER TABLE thunderdome.users DROP COLUMN registration_rejected_reason;
+ALTER TABLE thunderdome.users DROP COLUMN registration_status;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE thunderdome.poker_access_log (
+    id uuid DEFAULT gen_random_uuid() NOT NULL PRIMARY KEY,
+    poker_id uuid NOT NULL REFERENCES thunderdome.poker(id) ON DELETE CASCADE,
+    user_id uuid NOT NULL REFERENCES thunderdome.users(id) ON DELETE CASCADE,
+    event_type character varying(16) NOT NULL,
+    ip_address character varying(64) NOT NULL DEFAULT '',
+    user_agent text NOT NULL DEFAULT '',
+    created_at timestamp with time zone DEFAULT now() NOT NULL
+);
+CREATE INDEX poker_access_log_poker_id_created_at_idx ON thunderdome.poker_access_log (poker_id, created_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE thunderdome.poker_access_log;
+-- +goose StatementEnd
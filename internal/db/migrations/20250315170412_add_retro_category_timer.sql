@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE thunderdome.retro_category_timer (
+    retro_id uuid NOT NULL REFERENCES thunderdome.retro(id) ON DELETE CASCADE,
+    category_id character varying(64) NOT NULL,
+    duration_seconds integer NOT NULL,
+    started_at timestamp with time zone,
+    paused_at timestamp with time zone,
+    updated_date timestamp with time zone DEFAULT now(),
+    PRIMARY KEY (retro_id, category_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE thunderdome.retro_category_timer;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE thunderdome.user_notification (
+    id uuid NOT NULL DEFAULT gen_random_uuid() PRIMARY KEY,
+    user_id uuid NOT NULL REFERENCES thunderdome.users(id) ON DELETE CASCADE,
+    type character varying(64) NOT NULL,
+    payload jsonb NOT NULL DEFAULT '{}'::jsonb,
+    is_read boolean NOT NULL DEFAULT false,
+    created_at timestamp with time zone NOT NULL DEFAULT now()
+);
+CREATE INDEX idx_user_notification_user_unread ON thunderdome.user_notification (user_id, is_read, created_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE thunderdome.user_notification;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE thunderdome.story_attachment (
+    id uuid NOT NULL DEFAULT gen_random_uuid() PRIMARY KEY,
+    story_id uuid NOT NULL REFERENCES thunderdome.poker_story(id) ON DELETE CASCADE,
+    filename character varying(255) NOT NULL,
+    download_url text NOT NULL,
+    content_type character varying(255) NOT NULL,
+    size bigint NOT NULL,
+    uploaded_by uuid REFERENCES thunderdome.users(id) ON DELETE SET NULL,
+    created_date timestamp with time zone NOT NULL DEFAULT now()
+);
+CREATE INDEX idx_story_attachment_story ON thunderdome.story_attachment(story_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE thunderdome.story_attachment;
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE thunderdome.retro ADD COLUMN active_item_id uuid REFERENCES thunderdome.retro_item(id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE thunderdome.retro DROP COLUMN active_item_id;
+-- +goose StatementEnd
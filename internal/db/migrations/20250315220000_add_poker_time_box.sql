@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE thunderdome.poker ADD COLUMN time_box_minutes integer NOT NULL DEFAULT 0;
+CREATE TABLE thunderdome.poker_story_timebox (
+    poker_id uuid PRIMARY KEY REFERENCES thunderdome.poker(id) ON DELETE CASCADE,
+    story_id uuid NOT NULL REFERENCES thunderdome.poker_story(id) ON DELETE CASCADE,
+    duration_seconds integer NOT NULL,
+    started_at timestamp with time zone NOT NULL,
+    updated_date timestamp with time zone DEFAULT now()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE thunderdome.poker_story_timebox;
+ALTER TABLE thunderdome.poker DROP COLUMN time_box_minutes;
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE thunderdome.users ADD COLUMN merged_into_user_id uuid REFERENCES thunderdome.users(id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE thunderdome.users DROP COLUMN merged_into_user_id;
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE thunderdome.poker ADD COLUMN story_type_scale_map jsonb NOT NULL DEFAULT '{}'::jsonb;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE thunderdome.poker DROP COLUMN story_type_scale_map;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE thunderdome.poker_story_comment (
+    id uuid NOT NULL DEFAULT gen_random_uuid() PRIMARY KEY,
+    story_id uuid NOT NULL REFERENCES thunderdome.poker_story(id) ON DELETE CASCADE,
+    parent_id uuid REFERENCES thunderdome.poker_story_comment(id) ON DELETE CASCADE,
+    author_id uuid REFERENCES thunderdome.users(id) ON DELETE SET NULL,
+    body text NOT NULL,
+    created_date timestamp with time zone NOT NULL DEFAULT now(),
+    updated_date timestamp with time zone NOT NULL DEFAULT now()
+);
+CREATE INDEX idx_poker_story_comment_story ON thunderdome.poker_story_comment(story_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE thunderdome.poker_story_comment;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE thunderdome.game_integration (
+    id uuid NOT NULL DEFAULT gen_random_uuid() PRIMARY KEY,
+    poker_id uuid NOT NULL REFERENCES thunderdome.poker(id) ON DELETE CASCADE,
+    provider character varying(32) NOT NULL,
+    target character varying(256) NOT NULL,
+    access_token text NOT NULL,
+    created_date timestamp with time zone NOT NULL DEFAULT now(),
+    updated_date timestamp with time zone NOT NULL DEFAULT now(),
+    UNIQUE (poker_id, provider)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE thunderdome.game_integration;
+-- +goose StatementEnd
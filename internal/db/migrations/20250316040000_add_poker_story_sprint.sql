@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE thunderdome.poker_story ADD COLUMN sprint_name character varying(256);
+ALTER TABLE thunderdome.poker_story ADD COLUMN sprint_start_date timestamp with time zone;
+ALTER TABLE thunderdome.poker_story ADD COLUMN sprint_end_date timestamp with time zone;
+CREATE INDEX poker_story_sprint_name_idx ON thunderdome.poker_story (poker_id, sprint_name);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX thunderdome.poker_story_sprint_name_idx;
+ALTER TABLE thunderdome.poker_story DROP COLUMN sprint_end_date;
+ALTER TABLE thunderdome.poker_story DROP COLUMN sprint_start_date;
+ALTER TABLE thunderdome.poker_story DROP COLUMN sprint_name;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE thunderdome.retro ADD COLUMN deleted_at timestamp with time zone;
+CREATE INDEX retro_deleted_at_idx ON thunderdome.retro (deleted_at) WHERE deleted_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX thunderdome.retro_deleted_at_idx;
+ALTER TABLE thunderdome.retro DROP COLUMN deleted_at;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE thunderdome.poker_story ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    to_tsvector('simple', COALESCE(name, '') || ' ' || COALESCE(description, '') || ' ' || COALESCE(acceptance_criteria, ''))
+) STORED;
+CREATE INDEX poker_story_search_vector_idx ON thunderdome.poker_story USING GIN (search_vector);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX thunderdome.poker_story_search_vector_idx;
+ALTER TABLE thunderdome.poker_story DROP COLUMN search_vector;
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE thunderdome.users ADD COLUMN sessions_invalidated_at timestamp with time zone;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE thunderdome.users DROP COLUMN sessions_invalidated_at;
+-- +goose StatementEnd
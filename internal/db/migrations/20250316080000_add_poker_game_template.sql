@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS thunderdome.poker_game_template (
+    id uuid DEFAULT gen_random_uuid() NOT NULL PRIMARY KEY,
+    team_id uuid NOT NULL REFERENCES thunderdome.team(id) ON DELETE CASCADE,
+    created_by uuid NOT NULL REFERENCES thunderdome.users(id) ON DELETE CASCADE,
+    name character varying(256) NOT NULL,
+    estimation_scale_id uuid REFERENCES thunderdome.estimation_scale(id) ON DELETE SET NULL,
+    point_values_allowed jsonb DEFAULT '["1/2", "1", "2", "3", "5", "8", "13", "?"]'::jsonb NOT NULL,
+    auto_finish_voting boolean DEFAULT true NOT NULL,
+    point_average_rounding character varying(5) DEFAULT 'ceil'::character varying NOT NULL,
+    hide_voter_identity boolean DEFAULT false NOT NULL,
+    recurrence_cron character varying(128) DEFAULT '' NOT NULL,
+    created_date timestamp with time zone DEFAULT now() NOT NULL,
+    updated_date timestamp with time zone DEFAULT now() NOT NULL
+);
+CREATE INDEX IF NOT EXISTS poker_game_template_team_id_idx ON thunderdome.poker_game_template (team_id);
+ALTER TABLE thunderdome.poker ADD COLUMN template_id uuid REFERENCES thunderdome.poker_game_template(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS poker_template_id_idx ON thunderdome.poker (template_id, created_date);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE thunderdome.poker DROP COLUMN template_id;
+DROP TABLE IF EXISTS thunderdome.poker_game_template;
+-- +goose StatementEnd
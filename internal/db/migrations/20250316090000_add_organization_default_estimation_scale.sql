@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE thunderdome.organization ADD COLUMN default_estimation_scale_id uuid
+    REFERENCES thunderdome.estimation_scale(id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE thunderdome.organization DROP COLUMN default_estimation_scale_id;
+-- +goose StatementEnd
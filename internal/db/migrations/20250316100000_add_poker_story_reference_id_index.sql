@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS poker_story_poker_id_reference_id_idx ON thunderdome.poker_story (poker_id, reference_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS thunderdome.poker_story_poker_id_reference_id_idx;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS thunderdome.retro_schedule (
+    id uuid DEFAULT gen_random_uuid() NOT NULL PRIMARY KEY,
+    team_id uuid NOT NULL REFERENCES thunderdome.team(id) ON DELETE CASCADE,
+    created_by uuid NOT NULL REFERENCES thunderdome.users(id) ON DELETE CASCADE,
+    name character varying(256) NOT NULL,
+    template_id uuid REFERENCES thunderdome.retro_template(id) ON DELETE SET NULL,
+    max_votes smallint DEFAULT 3 NOT NULL,
+    brainstorm_visibility character varying(12) DEFAULT 'visible'::character varying NOT NULL,
+    recurrence_cron character varying(128) NOT NULL,
+    last_window_date timestamp with time zone,
+    created_date timestamp with time zone DEFAULT now() NOT NULL,
+    updated_date timestamp with time zone DEFAULT now() NOT NULL
+);
+CREATE INDEX IF NOT EXISTS retro_schedule_team_id_idx ON thunderdome.retro_schedule (team_id);
+ALTER TABLE thunderdome.retro ADD COLUMN schedule_id uuid REFERENCES thunderdome.retro_schedule(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS retro_schedule_id_idx ON thunderdome.retro (schedule_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE thunderdome.retro DROP COLUMN schedule_id;
+DROP TABLE IF EXISTS thunderdome.retro_schedule;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE thunderdome.poker_story ADD COLUMN status character varying(16) DEFAULT 'pending'::character varying NOT NULL
+    CHECK (status IN ('pending', 'estimated', 'ready', 'in-sprint'));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE thunderdome.poker_story DROP COLUMN status;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS thunderdome.storyboard_template (
+    id uuid DEFAULT gen_random_uuid() NOT NULL PRIMARY KEY,
+    organization_id uuid REFERENCES thunderdome.organization(id) ON DELETE CASCADE,
+    team_id uuid REFERENCES thunderdome.team(id) ON DELETE CASCADE,
+    name character varying(256) NOT NULL,
+    description text DEFAULT '' NOT NULL,
+    format jsonb NOT NULL,
+    is_public boolean DEFAULT false NOT NULL,
+    created_by uuid REFERENCES thunderdome.users(id) ON DELETE SET NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at timestamp with time zone DEFAULT now() NOT NULL,
+    CONSTRAINT storyboard_template_scope_check CHECK (organization_id IS NULL OR team_id IS NULL)
+);
+CREATE INDEX IF NOT EXISTS storyboard_template_organization_id_idx ON thunderdome.storyboard_template (organization_id);
+CREATE INDEX IF NOT EXISTS storyboard_template_team_id_idx ON thunderdome.storyboard_template (team_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS thunderdome.storyboard_template;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE thunderdome.poker ADD COLUMN archived_at timestamp with time zone;
+CREATE INDEX poker_archived_at_idx ON thunderdome.poker (archived_at) WHERE archived_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX thunderdome.poker_archived_at_idx;
+ALTER TABLE thunderdome.poker DROP COLUMN archived_at;
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE thunderdome.jira_instance ADD COLUMN story_points_field_id text NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE thunderdome.jira_instance DROP COLUMN story_points_field_id;
+-- +goose StatementEnd
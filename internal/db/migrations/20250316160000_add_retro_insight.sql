@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE thunderdome.retro_insight (
+    retro_id uuid NOT NULL PRIMARY KEY REFERENCES thunderdome.retro(id) ON DELETE CASCADE,
+    themes jsonb NOT NULL DEFAULT '[]'::jsonb,
+    action_items jsonb NOT NULL DEFAULT '[]'::jsonb,
+    mood_summary text NOT NULL DEFAULT '',
+    generated_at timestamp with time zone NOT NULL DEFAULT now()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE thunderdome.retro_insight;
+-- +goose StatementEnd
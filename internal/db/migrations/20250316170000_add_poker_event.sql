@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE thunderdome.poker ADD COLUMN event_sequence bigint NOT NULL DEFAULT 0;
+CREATE TABLE thunderdome.poker_event (
+    id uuid NOT NULL DEFAULT gen_random_uuid() PRIMARY KEY,
+    poker_id uuid NOT NULL REFERENCES thunderdome.poker(id) ON DELETE CASCADE,
+    sequence_number bigint NOT NULL,
+    event_type character varying(64) NOT NULL,
+    payload jsonb NOT NULL DEFAULT '{}'::jsonb,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    UNIQUE (poker_id, sequence_number)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE thunderdome.poker_event;
+ALTER TABLE thunderdome.poker DROP COLUMN event_sequence;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE thunderdome.poker ADD COLUMN quorum_percentage double precision NOT NULL DEFAULT 0
+    CHECK (quorum_percentage >= 0 AND quorum_percentage <= 100);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE thunderdome.poker DROP COLUMN quorum_percentage;
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE INDEX poker_story_vote_round_votes_idx ON thunderdome.poker_story_vote_round USING GIN (votes jsonb_path_ops);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX thunderdome.poker_story_vote_round_votes_idx;
+-- +goose StatementEnd
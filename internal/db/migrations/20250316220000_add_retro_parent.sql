@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE thunderdome.retro ADD COLUMN parent_retro_id uuid REFERENCES thunderdome.retro(id) ON DELETE SET NULL;
+ALTER TABLE thunderdome.retro ADD CONSTRAINT retro_parent_not_self CHECK (parent_retro_id <> id);
+CREATE INDEX retro_parent_retro_id_idx ON thunderdome.retro (parent_retro_id) WHERE parent_retro_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS thunderdome.retro_parent_retro_id_idx;
+ALTER TABLE thunderdome.retro DROP CONSTRAINT IF EXISTS retro_parent_not_self;
+ALTER TABLE thunderdome.retro DROP COLUMN parent_retro_id;
+-- +goose StatementEnd
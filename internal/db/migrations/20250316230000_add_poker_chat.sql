@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE thunderdome.poker_chat (
+    id uuid NOT NULL DEFAULT gen_random_uuid() PRIMARY KEY,
+    poker_id uuid NOT NULL REFERENCES thunderdome.poker(id) ON DELETE CASCADE,
+    user_id uuid REFERENCES thunderdome.users(id) ON DELETE SET NULL,
+    message text NOT NULL,
+    spectator_only boolean NOT NULL DEFAULT false,
+    created_date timestamp with time zone NOT NULL DEFAULT now()
+);
+CREATE INDEX idx_poker_chat_poker_created ON thunderdome.poker_chat(poker_id, created_date);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE thunderdome.poker_chat;
+-- +goose StatementEnd
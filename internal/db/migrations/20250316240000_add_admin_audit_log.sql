@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE thunderdome.admin_audit_log (
+    id uuid NOT NULL DEFAULT gen_random_uuid() PRIMARY KEY,
+    actor_id uuid REFERENCES thunderdome.users(id) ON DELETE SET NULL,
+    action text NOT NULL,
+    entity_type text NOT NULL,
+    entity_id text NOT NULL,
+    details jsonb NOT NULL DEFAULT '{}'::jsonb,
+    created_date timestamp with time zone NOT NULL DEFAULT now()
+);
+CREATE INDEX idx_admin_audit_log_entity ON thunderdome.admin_audit_log(entity_type, entity_id, created_date);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE thunderdome.admin_audit_log;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE thunderdome.webhook_event_log (
+    id uuid NOT NULL DEFAULT gen_random_uuid() PRIMARY KEY,
+    event_type character varying(64) NOT NULL,
+    payload jsonb NOT NULL,
+    http_status integer NOT NULL DEFAULT 0,
+    response_body text NOT NULL DEFAULT '',
+    error text NOT NULL DEFAULT '',
+    succeeded boolean NOT NULL DEFAULT false,
+    sent_at timestamp with time zone NOT NULL DEFAULT now()
+);
+CREATE INDEX idx_webhook_event_log_sent_at ON thunderdome.webhook_event_log(sent_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE thunderdome.webhook_event_log;
+-- +goose StatementEnd
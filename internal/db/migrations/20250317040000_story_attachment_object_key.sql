@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE thunderdome.story_attachment RENAME COLUMN download_url TO object_key;
+UPDATE thunderdome.story_attachment SET object_key = substring(object_key from 'stories/[^?]*');
+CREATE UNIQUE INDEX idx_story_attachment_object_key ON thunderdome.story_attachment(object_key);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX thunderdome.idx_story_attachment_object_key;
+ALTER TABLE thunderdome.story_attachment RENAME COLUMN object_key TO download_url;
+-- +goose StatementEnd
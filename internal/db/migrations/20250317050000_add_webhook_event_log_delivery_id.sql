@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE thunderdome.webhook_event_log ADD COLUMN delivery_id uuid;
+UPDATE thunderdome.webhook_event_log SET delivery_id = id;
+ALTER TABLE thunderdome.webhook_event_log ALTER COLUMN delivery_id SET NOT NULL;
+CREATE INDEX idx_webhook_event_log_delivery_id ON thunderdome.webhook_event_log(delivery_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE thunderdome.webhook_event_log DROP COLUMN delivery_id;
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE thunderdome.team_checkin_schedule ADD COLUMN last_reminded_at timestamp with time zone;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE thunderdome.team_checkin_schedule DROP COLUMN last_reminded_at;
+-- +goose StatementEnd
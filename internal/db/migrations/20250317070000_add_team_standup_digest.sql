@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE thunderdome.team_standup_digest (
+    team_id uuid NOT NULL PRIMARY KEY REFERENCES thunderdome.team(id) ON DELETE CASCADE,
+    last_sent_at timestamp with time zone NOT NULL
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE thunderdome.team_standup_digest;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+UPDATE thunderdome.organization_user ou SET allowed_auth_methods = array_append(ou.allowed_auth_methods, 'saml')
+FROM thunderdome.organization o
+WHERE o.id = ou.organization_id AND o.sso_required = true
+    AND ou.allowed_auth_methods = ARRAY['oidc', 'ldap', 'header']::varchar[];
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE thunderdome.organization_user SET allowed_auth_methods = array_remove(allowed_auth_methods, 'saml')
+WHERE allowed_auth_methods = ARRAY['oidc', 'ldap', 'header', 'saml']::varchar[];
+-- +goose StatementEnd
@@ -0,0 +1,118 @@
+package notification
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+// Service represents the user notification database service
+type Service struct {
+	DB     *sql.DB
+	Logger *otelzap.Logger
+}
+
+// CreateNotification creates a new notification for the user
+func (d *Service) CreateNotification(ctx context.Context, userID string, notificationType string, payload json.RawMessage) (*thunderdome.Notification, error) {
+	if payload == nil {
+		payload = json.RawMessage(`{}`)
+	}
+
+	n := thunderdome.Notification{
+		UserID:  userID,
+		Type:    notificationType,
+		Payload: payload,
+	}
+	err := d.DB.QueryRowContext(ctx,
+		`INSERT INTO thunderdome.user_notification (user_id, type, payload)
+		VALUES ($1, $2, $3)
+		RETURNING id, is_read, created_at;`,
+		userID, notificationType, []byte(payload),
+	).Scan(&n.ID, &n.IsRead, &n.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("create notification query error: %v", err)
+	}
+
+	return &n, nil
+}
+
+// MarkAsRead marks the user's notification as read
+func (d *Service) MarkAsRead(ctx context.Context, userID string, notificationID string) error {
+	result, err := d.DB.ExecContext(ctx,
+		`UPDATE thunderdome.user_notification SET is_read = true
+		WHERE id = $1 AND user_id = $2;`,
+		notificationID, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("mark notification read query error: %v", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("mark notification read rows affected error: %v", err)
+	}
+	if rows == 0 {
+		return errors.New("NOTIFICATION_NOT_FOUND")
+	}
+
+	return nil
+}
+
+// ListUnread gets the user's unread notifications newest first
+func (d *Service) ListUnread(ctx context.Context, userID string, limit int, offset int) ([]*thunderdome.Notification, int, error) {
+	notifications := make([]*thunderdome.Notification, 0)
+	var count int
+
+	err := d.DB.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM thunderdome.user_notification
+		WHERE user_id = $1 AND is_read = false;`,
+		userID,
+	).Scan(&count)
+	if err != nil {
+		return nil, count, fmt.Errorf("get unread notifications count query error: %v", err)
+	}
+
+	rows, err := d.DB.QueryContext(ctx,
+		`SELECT id, user_id, type, payload, is_read, created_at
+		FROM thunderdome.user_notification
+		WHERE user_id = $1 AND is_read = false
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3;`,
+		userID, limit, offset,
+	)
+	if err != nil {
+		return nil, count, fmt.Errorf("get unread notifications query error: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var n thunderdome.Notification
+		var payload []byte
+		if err := rows.Scan(&n.ID, &n.UserID, &n.Type, &payload, &n.IsRead, &n.CreatedAt); err != nil {
+			d.Logger.Ctx(ctx).Error("get unread notifications query scan error", zap.Error(err))
+			continue
+		}
+		n.Payload = payload
+		notifications = append(notifications, &n)
+	}
+
+	return notifications, count, nil
+}
+
+// DeleteOld deletes notifications older than the days specified
+func (d *Service) DeleteOld(ctx context.Context, daysOld int) error {
+	if _, err := d.DB.ExecContext(ctx,
+		`DELETE FROM thunderdome.user_notification WHERE created_at < (NOW() - $1 * interval '1 day');`,
+		daysOld,
+	); err != nil {
+		return fmt.Errorf("clean notifications query error: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,176 @@
+package notification
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+// recordingDB is an in memory stand in for the database recording each statement run,
+// updates affect rowsAffected rows and queries return the rows for the first column name matched
+type recordingDB struct {
+	mu           sync.Mutex
+	rowsAffected int64
+	rows         map[string][][]driver.Value
+	statements   []recordedStatement
+}
+
+type recordedStatement struct {
+	query string
+	args  []driver.Value
+}
+
+var (
+	recordingDBs   = make(map[string]*recordingDB)
+	recordingDBsMu sync.Mutex
+)
+
+func init() {
+	sql.Register("notification-recording", recordingDriver{})
+}
+
+// openRecordingDB opens a sql.DB backed by the recordingDB
+func openRecordingDB(t *testing.T, rdb *recordingDB) *sql.DB {
+	t.Helper()
+
+	recordingDBsMu.Lock()
+	recordingDBs[t.Name()] = rdb
+	recordingDBsMu.Unlock()
+
+	db, err := sql.Open("notification-recording", t.Name())
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	return db
+}
+
+type recordingDriver struct{}
+
+func (recordingDriver) Open(name string) (driver.Conn, error) {
+	recordingDBsMu.Lock()
+	defer recordingDBsMu.Unlock()
+
+	return &recordingConn{db: recordingDBs[name]}, nil
+}
+
+type recordingConn struct {
+	db *recordingDB
+}
+
+func (c *recordingConn) Prepare(query string) (driver.Stmt, error) {
+	return &recordingStmt{db: c.db, query: query}, nil
+}
+
+func (c *recordingConn) Close() error              { return nil }
+func (c *recordingConn) Begin() (driver.Tx, error) { return nil, driver.ErrSkip }
+
+type recordingStmt struct {
+	db    *recordingDB
+	query string
+}
+
+func (s *recordingStmt) Close() error  { return nil }
+func (s *recordingStmt) NumInput() int { return -1 }
+
+func (s *recordingStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	s.db.statements = append(s.db.statements, recordedStatement{query: s.query, args: args})
+
+	return driver.RowsAffected(s.db.rowsAffected), nil
+}
+
+func (s *recordingStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	s.db.statements = append(s.db.statements, recordedStatement{query: s.query, args: args})
+
+	for columns, values := range s.db.rows {
+		if strings.Contains(s.query, columns) {
+			return &recordingRows{columns: strings.Split(columns, ", "), values: values}, nil
+		}
+	}
+	return &recordingRows{}, nil
+}
+
+type recordingRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *recordingRows) Columns() []string { return r.columns }
+func (r *recordingRows) Close() error      { return nil }
+
+func (r *recordingRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+// TestMarkAsReadScopedToUser makes sure only the user's own notification is marked read
+// and a notification that isn't theirs is reported as not found
+func TestMarkAsReadScopedToUser(t *testing.T) {
+	rdb := &recordingDB{rowsAffected: 1}
+	d := &Service{DB: openRecordingDB(t, rdb), Logger: otelzap.New(zap.NewNop())}
+
+	if err := d.MarkAsRead(context.Background(), "user-1", "notification-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	statement := rdb.statements[0]
+	if !strings.Contains(statement.query, "WHERE id = $1 AND user_id = $2") {
+		t.Errorf("expected the update to be scoped to the notification and user, got %s", statement.query)
+	}
+	if statement.args[0] != "notification-1" || statement.args[1] != "user-1" {
+		t.Errorf("expected the notification and user ids, got %v", statement.args)
+	}
+
+	rdb.rowsAffected = 0
+	if err := d.MarkAsRead(context.Background(), "user-2", "notification-1"); err == nil || err.Error() != "NOTIFICATION_NOT_FOUND" {
+		t.Errorf("expected NOTIFICATION_NOT_FOUND for another user's notification, got %v", err)
+	}
+}
+
+// TestListUnread makes sure only the user's unread notifications are listed newest first with their payload
+func TestListUnread(t *testing.T) {
+	createdAt := time.Date(2025, 3, 15, 18, 0, 0, 0, time.UTC)
+	rdb := &recordingDB{rows: map[string][][]driver.Value{
+		"COUNT(*)": {{int64(1)}},
+		"id, user_id, type, payload, is_read, created_at": {
+			{"notification-1", "user-1", "team_invite", []byte(`{"teamId":"team-1"}`), false, createdAt},
+		},
+	}}
+	d := &Service{DB: openRecordingDB(t, rdb), Logger: otelzap.New(zap.NewNop())}
+
+	notifications, count, err := d.ListUnread(context.Background(), "user-1", 20, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count != 1 || len(notifications) != 1 {
+		t.Fatalf("expected 1 unread notification, got %d (%d)", len(notifications), count)
+	}
+	if string(notifications[0].Payload) != `{"teamId":"team-1"}` || !notifications[0].CreatedAt.Equal(createdAt) {
+		t.Errorf("unexpected notification %+v", notifications[0])
+	}
+
+	for _, statement := range rdb.statements {
+		if !strings.Contains(statement.query, "WHERE user_id = $1 AND is_read = false") || statement.args[0] != "user-1" {
+			t.Errorf("expected only the user's unread notifications, got %s %v", statement.query, statement.args)
+		}
+	}
+	if list := rdb.statements[1]; !strings.Contains(list.query, "ORDER BY created_at DESC") {
+		t.Errorf("expected the newest notifications first, got %s", list.query)
+	}
+}
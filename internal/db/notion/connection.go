@@ -0,0 +1,134 @@
+package notion
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/db"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// FindConnectionsByUserID returns all NotionConnections for a given user ID.
+func (s *Service) FindConnectionsByUserID(ctx context.Context, userID string) ([]thunderdome.NotionConnection, error) {
+	connections := make([]thunderdome.NotionConnection, 0)
+
+	rows, err := s.DB.QueryContext(ctx,
+		`SELECT id, user_id, name, access_token, description_property, created_date, updated_date
+ 				FROM thunderdome.notion_connection WHERE user_id = $1 ORDER BY created_date;`,
+		userID,
+	)
+	if err != nil {
+		return connections, fmt.Errorf("find notion connection by user id query error: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		connection := thunderdome.NotionConnection{}
+		if err := rows.Scan(
+			&connection.ID, &connection.UserID, &connection.Name, &connection.AccessToken,
+			&connection.DescriptionProperty, &connection.CreatedDate, &connection.UpdatedDate,
+		); err != nil {
+			return connections, fmt.Errorf("find notion connection by user id row scan error: %v", err)
+		}
+		connection.AccessToken, err = db.Decrypt(connection.AccessToken, s.AESHashKey)
+		if err != nil {
+			return connections, fmt.Errorf("error decrypting notion_connection %s access_token:  %v", connection.ID, err)
+		}
+		connections = append(connections, connection)
+	}
+
+	return connections, nil
+}
+
+// GetConnectionByID returns a NotionConnection for a given connection ID.
+func (s *Service) GetConnectionByID(ctx context.Context, connectionID string) (thunderdome.NotionConnection, error) {
+	connection := thunderdome.NotionConnection{}
+
+	err := s.DB.QueryRowContext(ctx,
+		`SELECT id, user_id, name, access_token, description_property, created_date, updated_date
+ 				FROM thunderdome.notion_connection WHERE id = $1;`,
+		connectionID,
+	).Scan(
+		&connection.ID, &connection.UserID, &connection.Name, &connection.AccessToken,
+		&connection.DescriptionProperty, &connection.CreatedDate, &connection.UpdatedDate,
+	)
+	if err != nil {
+		return connection, fmt.Errorf("error encountered getting notion_connection %s:  %v", connectionID, err)
+	}
+	connection.AccessToken, err = db.Decrypt(connection.AccessToken, s.AESHashKey)
+	if err != nil {
+		return connection, fmt.Errorf("error decrypting notion_connection %s access_token:  %v", connectionID, err)
+	}
+
+	return connection, nil
+}
+
+// CreateConnection creates a new NotionConnection.
+func (s *Service) CreateConnection(ctx context.Context, userID string, name string, accessToken string, descriptionProperty string) (thunderdome.NotionConnection, error) {
+	connection := thunderdome.NotionConnection{}
+	secureToken, err := db.Encrypt(accessToken, s.AESHashKey)
+	if err != nil {
+		return connection, fmt.Errorf("error encountered creating notion_connection:  %v", err)
+	}
+
+	err = s.DB.QueryRowContext(ctx,
+		`INSERT INTO thunderdome.notion_connection
+				(user_id, name, access_token, description_property)
+				VALUES ($1, $2, $3, $4)
+				RETURNING id, user_id, name, description_property, created_date, updated_date;`,
+		userID, name, secureToken, descriptionProperty,
+	).Scan(
+		&connection.ID, &connection.UserID, &connection.Name, &connection.DescriptionProperty,
+		&connection.CreatedDate, &connection.UpdatedDate,
+	)
+	if err != nil {
+		return connection, fmt.Errorf("error encountered creating notion_connection:  %v", err)
+	}
+	connection.AccessToken = accessToken
+
+	return connection, nil
+}
+
+// UpdateConnection updates an existing NotionConnection.
+func (s *Service) UpdateConnection(ctx context.Context, connectionID string, name string, accessToken string, descriptionProperty string) (thunderdome.NotionConnection, error) {
+	connection := thunderdome.NotionConnection{}
+	secureToken, err := db.Encrypt(accessToken, s.AESHashKey)
+	if err != nil {
+		return connection, fmt.Errorf("error encountered updating notion_connection:  %v", err)
+	}
+
+	err = s.DB.QueryRowContext(ctx,
+		`UPDATE thunderdome.notion_connection
+				SET name = $2, access_token = $3, description_property = $4, updated_date = NOW()
+				WHERE id = $1
+				RETURNING id, user_id, name, description_property, created_date, updated_date;`,
+		connectionID, name, secureToken, descriptionProperty,
+	).Scan(
+		&connection.ID, &connection.UserID, &connection.Name, &connection.DescriptionProperty,
+		&connection.CreatedDate, &connection.UpdatedDate,
+	)
+	if err != nil {
+		return connection, fmt.Errorf("error encountered updating notion_connection:  %v", err)
+	}
+	connection.AccessToken = accessToken
+
+	return connection, nil
+}
+
+// DeleteConnection deletes an existing NotionConnection.
+func (s *Service) DeleteConnection(ctx context.Context, connectionID string) error {
+	result, err := s.DB.ExecContext(ctx, `DELETE FROM thunderdome.notion_connection WHERE id = $1;`, connectionID)
+	if err != nil {
+		return fmt.Errorf("delete notion connection query error: %v", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("delete notion connection rows error: %v", err)
+	}
+	if rows != 1 {
+		return fmt.Errorf("delete notion connection expected to affect 1 row, affected %d", rows)
+	}
+
+	return nil
+}
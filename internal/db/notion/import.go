@@ -0,0 +1,236 @@
+package notion
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+const (
+	// pagesPageSize is the number of database pages requested per Notion API query, the API maximum
+	pagesPageSize = 100
+	// maxRateLimitRetries is how many times a rate limited query is retried before giving up
+	maxRateLimitRetries = 3
+	// defaultRetryWait is how long to wait before retrying a rate limited query without a Retry-After header
+	defaultRetryWait = time.Second
+	// maxRetryWait caps the Retry-After wait so an import can't hang on a long rate limit window
+	maxRetryWait = 30 * time.Second
+)
+
+type notionRichText struct {
+	PlainText string `json:"plain_text"`
+}
+
+type notionOption struct {
+	Name string `json:"name"`
+}
+
+type notionProperty struct {
+	Type     string           `json:"type"`
+	Title    []notionRichText `json:"title"`
+	RichText []notionRichText `json:"rich_text"`
+	Select   *notionOption    `json:"select"`
+	Status   *notionOption    `json:"status"`
+}
+
+type notionPage struct {
+	ID         string                    `json:"id"`
+	URL        string                    `json:"url"`
+	Properties map[string]notionProperty `json:"properties"`
+}
+
+type notionQueryResponse struct {
+	Results    []notionPage `json:"results"`
+	HasMore    bool         `json:"has_more"`
+	NextCursor *string      `json:"next_cursor"`
+}
+
+// ImportPagesFromDatabase retrieves the pages of a Notion database, optionally only those matching the filter,
+// as poker stories using the connection's access token and description property
+func (s *Service) ImportPagesFromDatabase(ctx context.Context, connectionID string, databaseID string, filter *thunderdome.NotionFilter) ([]*thunderdome.Story, error) {
+	connection, err := s.GetConnectionByID(ctx, connectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	pages, err := s.queryPages(ctx, connection.AccessToken, databaseID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	stories := make([]*thunderdome.Story, 0, len(pages))
+	for _, page := range pages {
+		stories = append(stories, pageToStory(page, connection.DescriptionProperty))
+	}
+
+	return stories, nil
+}
+
+// queryPages retrieves every page of the database from the Notion API following next_cursor
+func (s *Service) queryPages(ctx context.Context, accessToken string, databaseID string, filter *thunderdome.NotionFilter) ([]notionPage, error) {
+	apiURL := s.APIURL
+	if apiURL == "" {
+		apiURL = DefaultAPIURL
+	}
+	queryURL := strings.TrimSuffix(apiURL, "/") + "/databases/" + url.PathEscape(databaseID) + "/query"
+
+	query := map[string]any{"page_size": pagesPageSize}
+	if filter != nil {
+		filterBody, err := queryFilter(filter)
+		if err != nil {
+			return nil, err
+		}
+		query["filter"] = filterBody
+	}
+
+	pages := make([]notionPage, 0)
+	for {
+		body, err := json.Marshal(query)
+		if err != nil {
+			return nil, fmt.Errorf("notion database query encode error: %v", err)
+		}
+
+		result, err := s.query(ctx, accessToken, queryURL, body)
+		if err != nil {
+			return nil, err
+		}
+		pages = append(pages, result.Results...)
+
+		if !result.HasMore || result.NextCursor == nil || *result.NextCursor == "" {
+			return pages, nil
+		}
+		query["start_cursor"] = *result.NextCursor
+	}
+}
+
+// query sends a single database query, retrying when the Notion API rate limits the request
+func (s *Service) query(ctx context.Context, accessToken string, queryURL string, body []byte) (*notionQueryResponse, error) {
+	httpClient := s.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, queryURL, bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("notion database query request error: %v", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+		req.Header.Set("Notion-Version", APIVersion)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json")
+
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("notion database query request error: %v", err)
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests && attempt < maxRateLimitRetries {
+			resp.Body.Close()
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(retryWait(resp.Header.Get("Retry-After"))):
+			}
+			continue
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("notion database query unexpected status: %d", resp.StatusCode)
+		}
+
+		var result notionQueryResponse
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("notion database query response decode error: %v", err)
+		}
+
+		return &result, nil
+	}
+}
+
+// retryWait gets how long to wait from the Retry-After header seconds
+func retryWait(retryAfter string) time.Duration {
+	seconds, err := strconv.Atoi(strings.TrimSpace(retryAfter))
+	if err != nil || seconds < 0 {
+		return defaultRetryWait
+	}
+
+	return min(time.Duration(seconds)*time.Second, maxRetryWait)
+}
+
+// queryFilter builds the Notion database query filter for an equals comparison on the filter property
+func queryFilter(filter *thunderdome.NotionFilter) (map[string]any, error) {
+	var equals any = filter.Equals
+	if filter.Type == "checkbox" {
+		checked, err := strconv.ParseBool(filter.Equals)
+		if err != nil {
+			return nil, fmt.Errorf("NOTION_FILTER_INVALID_CHECKBOX")
+		}
+		equals = checked
+	}
+
+	return map[string]any{
+		"property":  filter.Property,
+		filter.Type: map[string]any{"equals": equals},
+	}, nil
+}
+
+// pageToStory maps a Notion database page to a poker story, the page title becomes the story name
+func pageToStory(page notionPage, descriptionProperty string) *thunderdome.Story {
+	story := &thunderdome.Story{
+		Type:        "Story",
+		ReferenceID: page.ID,
+		Link:        page.URL,
+	}
+
+	for _, property := range page.Properties {
+		if property.Type == "title" {
+			story.Name = propertyText(property)
+			break
+		}
+	}
+	if descriptionProperty != "" {
+		if property, ok := page.Properties[descriptionProperty]; ok {
+			story.Description = propertyText(property)
+		}
+	}
+
+	return story
+}
+
+// propertyText gets the plain text value of a text, select or status property
+func propertyText(property notionProperty) string {
+	var text []notionRichText
+	switch property.Type {
+	case "title":
+		text = property.Title
+	case "rich_text":
+		text = property.RichText
+	case "select":
+		if property.Select != nil {
+			return property.Select.Name
+		}
+	case "status":
+		if property.Status != nil {
+			return property.Status.Name
+		}
+	}
+
+	var value strings.Builder
+	for _, t := range text {
+		value.WriteString(t.PlainText)
+	}
+
+	return value.String()
+}
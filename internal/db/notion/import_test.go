@@ -0,0 +1,171 @@
+package notion
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+func mockNotionPage(i int) map[string]any {
+	return map[string]any{
+		"id":  fmt.Sprintf("page-%d", i),
+		"url": fmt.Sprintf("https://www.notion.so/page-%d", i),
+		"properties": map[string]any{
+			"Task": map[string]any{
+				"type":  "title",
+				"title": []map[string]string{{"plain_text": "Page "}, {"plain_text": fmt.Sprintf("%d", i)}},
+			},
+			"Details": map[string]any{
+				"type":      "rich_text",
+				"rich_text": []map[string]string{{"plain_text": fmt.Sprintf("Details %d", i)}},
+			},
+		},
+	}
+}
+
+// newMockNotionServer returns a Notion API mock serving the database pages over two cursor pages,
+// rate limiting the first rateLimited requests
+func newMockNotionServer(t *testing.T, rateLimited int, queries *[]map[string]any) *httptest.Server {
+	t.Helper()
+	requests := 0
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/databases/db-1/query" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer test-token" || r.Header.Get("Notion-Version") != APIVersion {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		requests++
+		if requests <= rateLimited {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+
+		var query map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&query); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		*queries = append(*queries, query)
+
+		response := map[string]any{}
+		switch query["start_cursor"] {
+		case nil:
+			response["results"] = []map[string]any{mockNotionPage(0), mockNotionPage(1)}
+			response["has_more"] = true
+			response["next_cursor"] = "cursor-2"
+		case "cursor-2":
+			response["results"] = []map[string]any{mockNotionPage(2)}
+			response["has_more"] = false
+			response["next_cursor"] = nil
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+}
+
+// TestQueryPagesPagination makes sure every page of results is retrieved following next_cursor
+func TestQueryPagesPagination(t *testing.T) {
+	queries := make([]map[string]any, 0)
+	server := newMockNotionServer(t, 0, &queries)
+	defer server.Close()
+
+	s := &Service{APIURL: server.URL, HTTPClient: server.Client()}
+	filter := &thunderdome.NotionFilter{Property: "Status", Type: "status", Equals: "Ready"}
+	pages, err := s.queryPages(context.Background(), "test-token", "db-1", filter)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(pages) != 3 || pages[0].ID != "page-0" || pages[2].ID != "page-2" {
+		t.Fatalf("expected pages 0 through 2 in order, got %+v", pages)
+	}
+	if len(queries) != 2 {
+		t.Fatalf("expected 2 queries, got %d", len(queries))
+	}
+	expectedFilter := map[string]any{"property": "Status", "status": map[string]any{"equals": "Ready"}}
+	for _, query := range queries {
+		filterJSON, _ := json.Marshal(query["filter"])
+		expectedJSON, _ := json.Marshal(expectedFilter)
+		if string(filterJSON) != string(expectedJSON) {
+			t.Errorf("expected filter %s on every query, got %s", expectedJSON, filterJSON)
+		}
+	}
+
+	story := pageToStory(pages[2], "Details")
+	if story.Name != "Page 2" || story.Description != "Details 2" || story.ReferenceID != "page-2" {
+		t.Errorf("unexpected story mapping %+v", story)
+	}
+	if story.Link != "https://www.notion.so/page-2" {
+		t.Errorf("expected page url, got %s", story.Link)
+	}
+	if story := pageToStory(pages[0], ""); story.Description != "" {
+		t.Errorf("expected no description without a description property, got %q", story.Description)
+	}
+}
+
+// TestQueryPagesRateLimited makes sure rate limited queries are retried
+func TestQueryPagesRateLimited(t *testing.T) {
+	queries := make([]map[string]any, 0)
+	server := newMockNotionServer(t, 2, &queries)
+	defer server.Close()
+
+	s := &Service{APIURL: server.URL, HTTPClient: server.Client()}
+	pages, err := s.queryPages(context.Background(), "test-token", "db-1", nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(pages) != 3 {
+		t.Errorf("expected 3 pages, got %d", len(pages))
+	}
+}
+
+// TestQueryPagesRateLimitExceeded makes sure the import gives up once the retries are used up
+func TestQueryPagesRateLimitExceeded(t *testing.T) {
+	queries := make([]map[string]any, 0)
+	server := newMockNotionServer(t, maxRateLimitRetries+1, &queries)
+	defer server.Close()
+
+	s := &Service{APIURL: server.URL, HTTPClient: server.Client()}
+	if _, err := s.queryPages(context.Background(), "test-token", "db-1", nil); err == nil {
+		t.Fatal("expected an error once the rate limit retries are exhausted")
+	}
+}
+
+// TestQueryPagesUnauthorized makes sure Notion API errors are surfaced
+func TestQueryPagesUnauthorized(t *testing.T) {
+	queries := make([]map[string]any, 0)
+	server := newMockNotionServer(t, 0, &queries)
+	defer server.Close()
+
+	s := &Service{APIURL: server.URL, HTTPClient: server.Client()}
+	if _, err := s.queryPages(context.Background(), "bad-token", "db-1", nil); err == nil {
+		t.Fatal("expected an error for an invalid access token")
+	}
+}
+
+func TestQueryFilterCheckbox(t *testing.T) {
+	filter, err := queryFilter(&thunderdome.NotionFilter{Property: "Ready", Type: "checkbox", Equals: "true"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if filter["checkbox"].(map[string]any)["equals"] != true {
+		t.Errorf("expected a boolean checkbox filter, got %+v", filter)
+	}
+
+	if _, err := queryFilter(&thunderdome.NotionFilter{Property: "Ready", Type: "checkbox", Equals: "yes please"}); err == nil {
+		t.Error("expected an error for a checkbox filter that isn't a boolean")
+	}
+}
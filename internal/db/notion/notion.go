@@ -0,0 +1,26 @@
+package notion
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+)
+
+const (
+	// DefaultAPIURL is the Notion REST API base url
+	DefaultAPIURL = "https://api.notion.com/v1"
+	// APIVersion is the Notion API version requested
+	APIVersion = "2022-06-28"
+)
+
+// Service represents the Notion database service
+type Service struct {
+	DB         *sql.DB
+	Logger     *otelzap.Logger
+	AESHashKey string
+	// APIURL overrides the Notion REST API base url, defaults to DefaultAPIURL
+	APIURL string
+	// HTTPClient is used for Notion API requests, defaults to a client with a 10 second timeout
+	HTTPClient *http.Client
+}
@@ -0,0 +1,80 @@
+package poker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/db"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+// LogAccess records a user joining or leaving the poker game, the ip address is salted and hashed before being stored
+// and left empty when no ip hash salt is configured
+func (d *Service) LogAccess(ctx context.Context, pokerID string, userID string, eventType string, ip string, userAgent string) error {
+	switch eventType {
+	case thunderdome.PokerAccessEventJoin, thunderdome.PokerAccessEventLeave:
+	default:
+		return fmt.Errorf("INVALID_ACCESS_EVENT_TYPE")
+	}
+
+	ipHash := ""
+	if d.IPHashSalt != "" {
+		ipHash = db.HashIPAddress(ip, d.IPHashSalt)
+	}
+
+	if _, err := d.DB.ExecContext(ctx,
+		`INSERT INTO thunderdome.poker_access_log
+		(poker_id, user_id, event_type, ip_address, user_agent)
+		VALUES ($1, $2, $3, $4, $5);`,
+		pokerID, userID, eventType, ipHash, userAgent,
+	); err != nil {
+		return fmt.Errorf("insert poker access log query error: %v", err)
+	}
+
+	return nil
+}
+
+// GetAccessLog gets the poker game's access log newest first, optionally filtered by event type
+func (d *Service) GetAccessLog(ctx context.Context, pokerID string, eventType string, limit int, offset int) ([]*thunderdome.PokerAccessLog, int, error) {
+	entries := make([]*thunderdome.PokerAccessLog, 0)
+	var count int
+
+	err := d.reader().QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM thunderdome.poker_access_log
+		WHERE poker_id = $1 AND ($2 = '' OR event_type = $2);`,
+		pokerID, eventType,
+	).Scan(&count)
+	if err != nil {
+		return nil, count, fmt.Errorf("get poker access log count query error: %v", err)
+	}
+
+	rows, err := d.reader().QueryContext(ctx,
+		`SELECT al.id, al.poker_id, al.user_id, COALESCE(u.name, ''), al.event_type,
+			al.ip_address, al.user_agent, al.created_at
+		FROM thunderdome.poker_access_log al
+		LEFT JOIN thunderdome.users u ON u.id = al.user_id
+		WHERE al.poker_id = $1 AND ($2 = '' OR al.event_type = $2)
+		ORDER BY al.created_at DESC
+		LIMIT $3 OFFSET $4;`,
+		pokerID, eventType, limit, offset,
+	)
+	if err != nil {
+		return nil, count, fmt.Errorf("get poker access log query error: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e thunderdome.PokerAccessLog
+		if err := rows.Scan(
+			&e.ID, &e.PokerID, &e.UserID, &e.UserName, &e.EventType,
+			&e.IPAddress, &e.UserAgent, &e.CreatedDate,
+		); err != nil {
+			d.Logger.Ctx(ctx).Error("get poker access log query scan error", zap.Error(err))
+			continue
+		}
+		entries = append(entries, &e)
+	}
+
+	return entries, count, nil
+}
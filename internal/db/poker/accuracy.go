@@ -0,0 +1,113 @@
+package poker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+// leaderboardCacheTTL is the longest the estimation accuracy leaderboard is cached
+const leaderboardCacheTTL = time.Hour
+
+// ComputeEstimationAccuracy computes the estimation accuracy leaderboard for a poker game,
+// ranking participants by how far their votes deviated from the finalized story points
+func (d *Service) ComputeEstimationAccuracy(ctx context.Context, pokerID string) ([]thunderdome.ParticipantAccuracy, error) {
+	cacheKey := leaderboardCacheKey(pokerID)
+	ttl := thunderdome.CappedTTL(d.CacheTTL.GameTTL, leaderboardCacheTTL)
+	if d.Redis != nil && ttl > 0 {
+		if cachedData, err := d.Redis.Get(ctx, cacheKey).Result(); err == nil {
+			var leaderboard []thunderdome.ParticipantAccuracy
+			if err := json.Unmarshal([]byte(cachedData), &leaderboard); err == nil {
+				d.Logger.Ctx(ctx).Debug("Leaderboard cache hit", zap.String("game_id", pokerID))
+				return leaderboard, nil
+			}
+		}
+	}
+
+	var exists bool
+	err := d.DB.QueryRowContext(ctx,
+		`SELECT EXISTS(SELECT 1 FROM thunderdome.poker WHERE id = $1);`,
+		pokerID,
+	).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("get poker leaderboard query error: %v", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("get poker leaderboard error: POKER_NOT_FOUND")
+	}
+
+	leaderboard := computeEstimationAccuracy(d.GetStories(pokerID, ""), d.GetUsers(pokerID))
+
+	if d.Redis != nil && ttl > 0 {
+		if leaderboardJSON, err := json.Marshal(leaderboard); err == nil {
+			d.Redis.Set(ctx, cacheKey, leaderboardJSON, ttl)
+		}
+	}
+
+	return leaderboard, nil
+}
+
+func leaderboardCacheKey(pokerID string) string {
+	return fmt.Sprintf("game:leaderboard:%s", pokerID)
+}
+
+// computeEstimationAccuracy averages each participant's vote deviation from the final points,
+// stories without numeric final points and non-numeric votes (e.g. ? or ☕️) are left out
+func computeEstimationAccuracy(stories []*thunderdome.Story, users []*thunderdome.PokerUser) []thunderdome.ParticipantAccuracy {
+	type accuracyTotal struct {
+		deviation float64
+		count     int
+	}
+	totals := make(map[string]*accuracyTotal)
+
+	for _, story := range stories {
+		if story.Skipped || story.Active {
+			continue
+		}
+		points, ok := thunderdome.ParsePointValue(story.Points)
+		if !ok {
+			continue
+		}
+
+		for _, vote := range story.Votes {
+			voteValue, ok := thunderdome.ParsePointValue(vote.VoteValue)
+			if !ok {
+				continue
+			}
+			if _, ok := totals[vote.UserID]; !ok {
+				totals[vote.UserID] = &accuracyTotal{}
+			}
+			totals[vote.UserID].deviation += math.Abs(voteValue - points)
+			totals[vote.UserID].count++
+		}
+	}
+
+	leaderboard := make([]thunderdome.ParticipantAccuracy, 0, len(totals))
+	for _, user := range users {
+		total, ok := totals[user.ID]
+		if !ok {
+			continue
+		}
+		leaderboard = append(leaderboard, thunderdome.ParticipantAccuracy{
+			UserID:           user.ID,
+			Username:         user.Name,
+			AverageDeviation: total.deviation / float64(total.count),
+			StoryCount:       total.count,
+		})
+	}
+
+	sort.SliceStable(leaderboard, func(i, j int) bool {
+		if leaderboard[i].AverageDeviation != leaderboard[j].AverageDeviation {
+			return leaderboard[i].AverageDeviation < leaderboard[j].AverageDeviation
+		}
+		return leaderboard[i].StoryCount > leaderboard[j].StoryCount
+	})
+
+	return leaderboard
+}
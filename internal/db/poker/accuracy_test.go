@@ -0,0 +1,83 @@
+package poker
+
+import (
+	"math"
+	"testing"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+func accuracyStory(points string, votes map[string]string) *thunderdome.Story {
+	s := &thunderdome.Story{Points: points, Votes: make([]*thunderdome.Vote, 0)}
+	for userID, vote := range votes {
+		s.Votes = append(s.Votes, &thunderdome.Vote{UserID: userID, VoteValue: vote})
+	}
+	return s
+}
+
+var accuracyUsers = []*thunderdome.PokerUser{
+	{ID: "exact", Name: "Exact"},
+	{ID: "close", Name: "Close"},
+	{ID: "unsure", Name: "Unsure"},
+}
+
+// TestComputeEstimationAccuracyExactMatch makes sure a participant who always matches the final points has no deviation
+func TestComputeEstimationAccuracyExactMatch(t *testing.T) {
+	stories := []*thunderdome.Story{
+		accuracyStory("3", map[string]string{"exact": "3", "close": "5"}),
+		accuracyStory("8", map[string]string{"exact": "8", "close": "5"}),
+		accuracyStory("1/2", map[string]string{"exact": "1/2", "close": "1"}),
+	}
+
+	leaderboard := computeEstimationAccuracy(stories, accuracyUsers)
+	if len(leaderboard) != 2 {
+		t.Fatalf("expected 2 participants, got %d", len(leaderboard))
+	}
+	if leaderboard[0].UserID != "exact" || leaderboard[0].AverageDeviation != 0 || leaderboard[0].StoryCount != 3 {
+		t.Errorf("expected exact participant first with deviation 0 over 3 stories, got %+v", leaderboard[0])
+	}
+	// (2 + 3 + 0.5) / 3
+	if leaderboard[1].UserID != "close" || math.Abs(leaderboard[1].AverageDeviation-5.5/3) > 1e-9 || leaderboard[1].StoryCount != 3 {
+		t.Errorf("expected close participant second with deviation 1.83 over 3 stories, got %+v", leaderboard[1])
+	}
+	if leaderboard[0].Username != "Exact" {
+		t.Errorf("expected username Exact, got %s", leaderboard[0].Username)
+	}
+}
+
+// TestComputeEstimationAccuracySpecialPoints makes sure non-numeric votes and points are excluded
+func TestComputeEstimationAccuracySpecialPoints(t *testing.T) {
+	stories := []*thunderdome.Story{
+		accuracyStory("5", map[string]string{"exact": "5", "unsure": "?"}),
+		accuracyStory("?", map[string]string{"exact": "13", "close": "1"}),
+		accuracyStory("☕️", map[string]string{"exact": "2"}),
+		accuracyStory("", map[string]string{"close": "2"}),
+		accuracyStory("2", map[string]string{"unsure": "☕️", "close": "3"}),
+	}
+
+	leaderboard := computeEstimationAccuracy(stories, accuracyUsers)
+	if len(leaderboard) != 2 {
+		t.Fatalf("expected 2 participants, got %d: %+v", len(leaderboard), leaderboard)
+	}
+	for _, p := range leaderboard {
+		if p.UserID == "unsure" {
+			t.Errorf("expected participant with only special votes to be excluded")
+		}
+		if p.StoryCount != 1 {
+			t.Errorf("expected %s to have 1 story counted, got %d", p.UserID, p.StoryCount)
+		}
+	}
+}
+
+// TestComputeEstimationAccuracySkipsUnfinished makes sure skipped and active stories are not scored
+func TestComputeEstimationAccuracySkipsUnfinished(t *testing.T) {
+	skipped := accuracyStory("3", map[string]string{"close": "8"})
+	skipped.Skipped = true
+	active := accuracyStory("3", map[string]string{"close": "8"})
+	active.Active = true
+
+	leaderboard := computeEstimationAccuracy([]*thunderdome.Story{skipped, active}, accuracyUsers)
+	if len(leaderboard) != 0 {
+		t.Fatalf("expected no participants, got %+v", leaderboard)
+	}
+}
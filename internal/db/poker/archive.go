@@ -0,0 +1,97 @@
+package poker
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// ArchiveGame archives the game instead of deleting it so it's kept for auditing, its users are marked
+// inactive rather than removed so the game still shows up in their archived games
+func (d *Service) ArchiveGame(ctx context.Context, pokerID string) error {
+	tx, err := d.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("poker archive begin transaction error: %v", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		`UPDATE thunderdome.poker SET archived_at = NOW() WHERE id = $1 AND archived_at IS NULL;`,
+		pokerID,
+	)
+	if err != nil {
+		return fmt.Errorf("poker archive query error: %v", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("BATTLE_NOT_FOUND")
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE thunderdome.poker_user SET active = false WHERE poker_id = $1 AND active = true;`,
+		pokerID,
+	); err != nil {
+		return fmt.Errorf("poker archive users query error: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("poker archive commit error: %v", err)
+	}
+
+	d.clearGameCache(ctx, pokerID)
+
+	return nil
+}
+
+// RestoreGame restores an archived game
+func (d *Service) RestoreGame(ctx context.Context, pokerID string) error {
+	result, err := d.DB.ExecContext(ctx,
+		`UPDATE thunderdome.poker SET archived_at = NULL WHERE id = $1 AND archived_at IS NOT NULL;`,
+		pokerID,
+	)
+	if err != nil {
+		return fmt.Errorf("poker restore query error: %v", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("BATTLE_NOT_FOUND")
+	}
+
+	d.clearGameCache(ctx, pokerID)
+
+	return nil
+}
+
+// IsGameArchived checks whether the game has been archived
+func (d *Service) IsGameArchived(ctx context.Context, pokerID string) (bool, error) {
+	var archived bool
+	if err := d.DB.QueryRowContext(ctx,
+		`SELECT archived_at IS NOT NULL FROM thunderdome.poker WHERE id = $1;`,
+		pokerID,
+	).Scan(&archived); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, fmt.Errorf("BATTLE_NOT_FOUND")
+		}
+		return false, fmt.Errorf("poker archived query error: %v", err)
+	}
+
+	return archived, nil
+}
+
+// GetArchivedGamesByUser gets a list of archived games by UserID
+func (d *Service) GetArchivedGamesByUser(userID string, limit int, offset int) ([]*thunderdome.Poker, int, error) {
+	return d.getGamesByUser(userID, limit, offset, true)
+}
+
+// GetArchivedGames gets a list of archived games
+func (d *Service) GetArchivedGames(limit int, offset int) ([]*thunderdome.Poker, int, error) {
+	return d.getGames(limit, offset, true)
+}
+
+// clearGameCache removes the cached game so its archived state isn't stale
+func (d *Service) clearGameCache(ctx context.Context, pokerID string) {
+	if d.Redis != nil {
+		d.Redis.Del(ctx, fmt.Sprintf("game:%s", pokerID))
+	}
+}
@@ -0,0 +1,96 @@
+package poker
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+// ConfirmStoryUser checks the user is a user of the story's poker game
+func (d *Service) ConfirmStoryUser(ctx context.Context, storyID string, userID string) error {
+	var isGameUser bool
+	err := d.DB.QueryRowContext(ctx,
+		`SELECT EXISTS(
+			SELECT 1 FROM thunderdome.poker_user pu
+			WHERE pu.poker_id = ps.poker_id AND pu.user_id = $2
+		)
+		FROM thunderdome.poker_story ps
+		WHERE ps.id = $1;`,
+		storyID, userID,
+	).Scan(&isGameUser)
+	if err != nil && errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("STORY_NOT_FOUND")
+	} else if err != nil {
+		return fmt.Errorf("confirm poker story user query error: %v", err)
+	}
+
+	if !isGameUser {
+		return fmt.Errorf("REQUIRES_GAME_USER")
+	}
+
+	return nil
+}
+
+// CreateStoryAttachment records a file uploaded to the poker story, each object can only be recorded once
+func (d *Service) CreateStoryAttachment(ctx context.Context, storyID string, userID string, filename string, objectKey string, contentType string, size int64) (*thunderdome.StoryAttachment, error) {
+	a := thunderdome.StoryAttachment{
+		StoryID:     storyID,
+		Filename:    filename,
+		ObjectKey:   objectKey,
+		ContentType: contentType,
+		Size:        size,
+		UploadedBy:  userID,
+	}
+
+	err := d.DB.QueryRowContext(ctx,
+		`INSERT INTO thunderdome.story_attachment
+		(story_id, filename, object_key, content_type, size, uploaded_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (object_key) DO NOTHING
+		RETURNING id, created_date;`,
+		storyID, filename, objectKey, contentType, size, userID,
+	).Scan(&a.ID, &a.CreatedDate)
+	if err != nil && errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("ATTACHMENT_ALREADY_CONFIRMED")
+	} else if err != nil {
+		return nil, fmt.Errorf("create poker story attachment query error: %v", err)
+	}
+
+	return &a, nil
+}
+
+// GetStoryAttachments gets the poker story's attachments oldest first
+func (d *Service) GetStoryAttachments(ctx context.Context, storyID string) ([]*thunderdome.StoryAttachment, error) {
+	attachments := make([]*thunderdome.StoryAttachment, 0)
+
+	rows, err := d.DB.QueryContext(ctx,
+		`SELECT id, story_id, filename, object_key, content_type, size,
+			COALESCE(uploaded_by::text, ''), created_date
+		FROM thunderdome.story_attachment
+		WHERE story_id = $1
+		ORDER BY created_date;`,
+		storyID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get poker story attachments query error: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var a thunderdome.StoryAttachment
+		if err := rows.Scan(
+			&a.ID, &a.StoryID, &a.Filename, &a.ObjectKey, &a.ContentType, &a.Size,
+			&a.UploadedBy, &a.CreatedDate,
+		); err != nil {
+			d.Logger.Ctx(ctx).Error("get poker story attachments query scan error", zap.Error(err))
+			continue
+		}
+		attachments = append(attachments, &a)
+	}
+
+	return attachments, nil
+}
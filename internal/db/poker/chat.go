@@ -0,0 +1,98 @@
+package poker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+func chatRateLimitCacheKey(userID string) string {
+	return fmt.Sprintf("chat:ratelimit:%s", userID)
+}
+
+// allowChatMessage counts the user's chat message towards the rate limit, reporting whether it's within the limit,
+// without redis there's no rate limit and should redis fail the message is let through
+func (d *Service) allowChatMessage(ctx context.Context, userID string) bool {
+	if d.Redis == nil {
+		return true
+	}
+
+	key := chatRateLimitCacheKey(userID)
+	count, err := d.Redis.Incr(ctx, key).Result()
+	if err != nil {
+		d.Logger.Ctx(ctx).Warn("poker chat rate limit error", zap.Error(err), zap.String("user_id", userID))
+		return true
+	}
+	if count == 1 {
+		d.Redis.Expire(ctx, key, thunderdome.GameChatRateWindow)
+	}
+
+	return count <= thunderdome.GameChatRateLimit
+}
+
+// AddChatMessage adds a chat message to the poker game, spectator only messages are only
+// seen by spectators and facilitators
+func (d *Service) AddChatMessage(ctx context.Context, gameID string, userID string, message string, spectatorOnly bool) (*thunderdome.GameChat, error) {
+	if utf8.RuneCountInString(message) > thunderdome.GameChatMaxLength {
+		return nil, thunderdome.ErrGameChatMessageTooLong
+	}
+	sanitizedMessage := strings.TrimSpace(d.HTMLSanitizerPolicy.Sanitize(message))
+	if sanitizedMessage == "" {
+		return nil, thunderdome.ErrGameChatMessageRequired
+	}
+	if !d.allowChatMessage(ctx, userID) {
+		return nil, thunderdome.ErrGameChatRateLimited
+	}
+
+	chat := thunderdome.GameChat{
+		GameID:          gameID,
+		UserID:          userID,
+		Message:         sanitizedMessage,
+		IsSpectatorOnly: spectatorOnly,
+	}
+	err := d.DB.QueryRowContext(ctx,
+		`INSERT INTO thunderdome.poker_chat (poker_id, user_id, message, spectator_only)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_date;`,
+		gameID, userID, sanitizedMessage, spectatorOnly,
+	).Scan(&chat.ID, &chat.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("add poker chat message query error: %v", err)
+	}
+
+	return &chat, nil
+}
+
+// GetChatHistory gets the poker game's chat messages sent after since, oldest first
+func (d *Service) GetChatHistory(ctx context.Context, gameID string, since time.Time) ([]thunderdome.GameChat, error) {
+	messages := make([]thunderdome.GameChat, 0)
+
+	rows, err := d.DB.QueryContext(ctx,
+		`SELECT id, poker_id, COALESCE(user_id::TEXT, ''), message, spectator_only, created_date
+		FROM thunderdome.poker_chat
+		WHERE poker_id = $1 AND created_date > $2
+		ORDER BY created_date ASC;`,
+		gameID, since,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get poker chat history query error: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var chat thunderdome.GameChat
+		if err := rows.Scan(
+			&chat.ID, &chat.GameID, &chat.UserID, &chat.Message, &chat.IsSpectatorOnly, &chat.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("get poker chat history row scan error: %v", err)
+		}
+		messages = append(messages, chat)
+	}
+
+	return messages, nil
+}
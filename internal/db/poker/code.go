@@ -0,0 +1,56 @@
+package poker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/db"
+)
+
+// regeneratedCodeBytes is the random bytes of a regenerated code, base64 encoded to 12 characters
+const regeneratedCodeBytes = 9
+
+// RegenerateJoinCode replaces the game join code with a new random code, the old code no longer grants access
+func (d *Service) RegenerateJoinCode(ctx context.Context, pokerID string, facilitatorID string) (string, error) {
+	return d.regenerateCode(ctx, pokerID, facilitatorID, "join_code")
+}
+
+// RegenerateFacilitatorCode replaces the game facilitator code with a new random code,
+// the old code can no longer be used to become a facilitator
+func (d *Service) RegenerateFacilitatorCode(ctx context.Context, pokerID string, facilitatorID string) (string, error) {
+	return d.regenerateCode(ctx, pokerID, facilitatorID, "leader_code")
+}
+
+// regenerateCode stores a new encrypted code in the code column, column is never user input
+func (d *Service) regenerateCode(ctx context.Context, pokerID string, facilitatorID string, column string) (string, error) {
+	if err := d.ConfirmFacilitator(pokerID, facilitatorID); err != nil {
+		return "", fmt.Errorf("REQUIRES_FACILITATOR")
+	}
+
+	code, err := db.RandomBase64String(regeneratedCodeBytes)
+	if err != nil {
+		return "", fmt.Errorf("regenerate poker %s error: %v", column, err)
+	}
+	encryptedCode, err := db.Encrypt(code, d.AESHashKey)
+	if err != nil {
+		return "", fmt.Errorf("regenerate poker encrypt %s error: %v", column, err)
+	}
+
+	result, err := d.DB.ExecContext(ctx,
+		`UPDATE thunderdome.poker SET `+column+` = $2, updated_date = NOW() WHERE id = $1;`,
+		pokerID, encryptedCode,
+	)
+	if err != nil {
+		return "", fmt.Errorf("regenerate poker %s query error: %v", column, err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return "", fmt.Errorf("BATTLE_NOT_FOUND")
+	}
+
+	// the cached game and join deep link hold the old code
+	if d.Redis != nil {
+		d.Redis.Del(ctx, fmt.Sprintf("game:%s", pokerID), joinDeepLinkCacheKey(pokerID))
+	}
+
+	return code, nil
+}
@@ -0,0 +1,85 @@
+package poker
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+// AddComment adds a comment to the poker story, a parent ID makes the comment a reply to another comment on the story
+func (d *Service) AddComment(ctx context.Context, storyID string, authorID string, parentID string, body string) (*thunderdome.PokerStoryComment, error) {
+	sanitizedBody := d.HTMLSanitizerPolicy.Sanitize(body)
+	if sanitizedBody == "" {
+		return nil, fmt.Errorf("COMMENT_BODY_REQUIRED")
+	}
+
+	if parentID != "" {
+		var sameStory bool
+		err := d.DB.QueryRowContext(ctx,
+			`SELECT story_id = $2 FROM thunderdome.poker_story_comment WHERE id = $1;`,
+			parentID, storyID,
+		).Scan(&sameStory)
+		switch {
+		case errors.Is(err, sql.ErrNoRows), err == nil && !sameStory:
+			return nil, fmt.Errorf("COMMENT_PARENT_NOT_FOUND")
+		case err != nil:
+			return nil, fmt.Errorf("add poker story comment parent query error: %v", err)
+		}
+	}
+
+	c := thunderdome.PokerStoryComment{
+		StoryID:  storyID,
+		ParentID: parentID,
+		AuthorID: authorID,
+		Body:     sanitizedBody,
+		Replies:  make([]thunderdome.PokerStoryComment, 0),
+	}
+	err := d.DB.QueryRowContext(ctx,
+		`INSERT INTO thunderdome.poker_story_comment (story_id, parent_id, author_id, body)
+		VALUES ($1, NULLIF($2, '')::uuid, $3, $4)
+		RETURNING id, created_date, updated_date,
+			(SELECT poker_id FROM thunderdome.poker_story WHERE id = $1);`,
+		storyID, parentID, authorID, sanitizedBody,
+	).Scan(&c.ID, &c.CreatedAt, &c.UpdatedAt, &c.PokerID)
+	if err != nil {
+		return nil, fmt.Errorf("add poker story comment query error: %v", err)
+	}
+
+	return &c, nil
+}
+
+// ListComments gets the poker story's comments oldest first with replies nested under the comment they reply to
+func (d *Service) ListComments(ctx context.Context, storyID string) ([]thunderdome.PokerStoryComment, error) {
+	comments := make([]thunderdome.PokerStoryComment, 0)
+
+	rows, err := d.DB.QueryContext(ctx,
+		`SELECT c.id, ps.poker_id, c.story_id, COALESCE(c.parent_id::text, ''), COALESCE(c.author_id::text, ''),
+			c.body, c.created_date, c.updated_date
+		FROM thunderdome.poker_story_comment c
+		JOIN thunderdome.poker_story ps ON ps.id = c.story_id
+		WHERE c.story_id = $1
+		ORDER BY c.created_date;`,
+		storyID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list poker story comments query error: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var c thunderdome.PokerStoryComment
+		if err := rows.Scan(
+			&c.ID, &c.PokerID, &c.StoryID, &c.ParentID, &c.AuthorID, &c.Body, &c.CreatedAt, &c.UpdatedAt,
+		); err != nil {
+			d.Logger.Ctx(ctx).Error("list poker story comments query scan error", zap.Error(err))
+			continue
+		}
+		comments = append(comments, c)
+	}
+
+	return thunderdome.ThreadPokerStoryComments(comments), nil
+}
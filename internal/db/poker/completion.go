@@ -0,0 +1,55 @@
+package poker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// GetGameCompletionStats gets how many of the poker game's stories and points have been finalized
+func (d *Service) GetGameCompletionStats(ctx context.Context, pokerID string) (*thunderdome.CompletionStats, error) {
+	var exists bool
+	err := d.reader().QueryRowContext(ctx,
+		`SELECT EXISTS(SELECT 1 FROM thunderdome.poker WHERE id = $1);`,
+		pokerID,
+	).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("get poker completion stats query error: %v", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("get poker completion stats error: POKER_NOT_FOUND")
+	}
+
+	return computeCompletionStats(d.GetStories(pokerID, "")), nil
+}
+
+// computeCompletionStats summarizes the finalized stories, a story is finalized once it has points,
+// unfinalized stories count their estimate hint towards the total points
+func computeCompletionStats(stories []*thunderdome.Story) *thunderdome.CompletionStats {
+	stats := &thunderdome.CompletionStats{
+		TotalStories: len(stories),
+	}
+
+	for _, story := range stories {
+		if strings.TrimSpace(story.Points) == "" {
+			if points, ok := thunderdome.ParsePointValue(story.EstimateHint); ok {
+				stats.TotalPoints += points
+			}
+			continue
+		}
+
+		stats.FinalizedStories++
+		if points, ok := thunderdome.ParsePointValue(story.Points); ok {
+			stats.TotalPoints += points
+			stats.FinalizedPoints += points
+		}
+	}
+
+	if stats.TotalStories > 0 {
+		stats.CompletionPercentage = float64(stats.FinalizedStories) / float64(stats.TotalStories) * 100
+	}
+
+	return stats
+}
@@ -0,0 +1,79 @@
+package poker
+
+import (
+	"math"
+	"testing"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// TestComputeCompletionStats makes sure completion is computed for none, some and all stories finalized
+func TestComputeCompletionStats(t *testing.T) {
+	tests := []struct {
+		name       string
+		stories    []*thunderdome.Story
+		finalized  int
+		total      float64
+		finalPts   float64
+		percentage float64
+	}{
+		{
+			name:    "no stories",
+			stories: []*thunderdome.Story{},
+		},
+		{
+			name: "none finalized",
+			stories: []*thunderdome.Story{
+				{ID: "1"},
+				{ID: "2", EstimateHint: "5"},
+				{ID: "3", Points: " "},
+			},
+			total: 5,
+		},
+		{
+			name: "partially finalized",
+			stories: []*thunderdome.Story{
+				{ID: "1", Points: "3"},
+				{ID: "2", Points: "?"},
+				{ID: "3", EstimateHint: "8"},
+			},
+			finalized:  2,
+			total:      11,
+			finalPts:   3,
+			percentage: 200.0 / 3,
+		},
+		{
+			name: "all finalized",
+			stories: []*thunderdome.Story{
+				{ID: "1", Points: "1/2"},
+				{ID: "2", Points: "13", EstimateHint: "8"},
+			},
+			finalized:  2,
+			total:      13.5,
+			finalPts:   13.5,
+			percentage: 100,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats := computeCompletionStats(tt.stories)
+
+			if stats.TotalStories != len(tt.stories) {
+				t.Errorf("expected %d total stories, got %d", len(tt.stories), stats.TotalStories)
+			}
+			if stats.FinalizedStories != tt.finalized {
+				t.Errorf("expected %d finalized stories, got %d", tt.finalized, stats.FinalizedStories)
+			}
+			if stats.TotalPoints != tt.total {
+				t.Errorf("expected %v total points, got %v", tt.total, stats.TotalPoints)
+			}
+			if stats.FinalizedPoints != tt.finalPts {
+				t.Errorf("expected %v finalized points, got %v", tt.finalPts, stats.FinalizedPoints)
+			}
+			if math.Abs(stats.CompletionPercentage-tt.percentage) > 1e-9 {
+				t.Errorf("expected %v completion percentage, got %v", tt.percentage, stats.CompletionPercentage)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package poker
+
+import (
+	"context"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// ImportStoriesFromCSV adds the stories parsed from the csv rows to the game, columnMapping maps csv header
+// names to story fields and deduplicate matches stories by reference_id the same as BulkAddStories.
+// Rows that can't be imported are skipped and returned as thunderdome.StoryCSVImportRowErrors
+// along with the stories that were imported
+func (d *Service) ImportStoriesFromCSV(ctx context.Context, pokerID string, csvData []byte, columnMapping map[string]string, deduplicate bool) ([]*thunderdome.Story, *thunderdome.DuplicationResult, error) {
+	stories, rowErrors, err := thunderdome.ParseStoryCSV(csvData, columnMapping)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	duplication := &thunderdome.DuplicationResult{}
+	if len(stories) > 0 {
+		duplication, err = d.BulkAddStories(ctx, pokerID, stories, deduplicate)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if len(rowErrors) > 0 {
+		return stories, duplication, rowErrors
+	}
+
+	return stories, duplication, nil
+}
@@ -0,0 +1,39 @@
+package poker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// DetectDuplicateStories groups the game's stories whose names have a trigram similarity at or above the threshold,
+// most similar group first
+func (d *Service) DetectDuplicateStories(ctx context.Context, pokerID string, threshold float64) ([]thunderdome.DuplicateGroup, error) {
+	rows, err := d.reader().QueryContext(ctx,
+		`SELECT s.id, o.id, similarity(s.name, o.name) AS name_similarity
+		FROM thunderdome.poker_story s
+		JOIN thunderdome.poker_story o ON o.poker_id = s.poker_id AND o.id > s.id
+		WHERE s.poker_id = $1 AND similarity(s.name, o.name) >= $2
+		ORDER BY name_similarity DESC;`,
+		pokerID, threshold,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("detect duplicate poker stories query error: %v", err)
+	}
+	defer rows.Close()
+
+	var pairs []thunderdome.StorySimilarity
+	for rows.Next() {
+		var pair thunderdome.StorySimilarity
+		if err := rows.Scan(&pair.StoryID, &pair.OtherStoryID, &pair.Similarity); err != nil {
+			return nil, fmt.Errorf("detect duplicate poker stories scan error: %v", err)
+		}
+		pairs = append(pairs, pair)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("detect duplicate poker stories rows error: %v", err)
+	}
+
+	return thunderdome.GroupDuplicateStories(pairs), nil
+}
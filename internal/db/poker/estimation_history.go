@@ -0,0 +1,65 @@
+package poker
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+// GetUserEstimationHistory gets the user's vote in the last voting round of each finalized story
+// of the games they participated in, newest games first. Stories the user didn't vote on in the
+// last round are left out rather than using an earlier round's vote
+func (d *Service) GetUserEstimationHistory(ctx context.Context, userID string, limit int, offset int) ([]thunderdome.UserEstimationRecord, error) {
+	records := make([]thunderdome.UserEstimationRecord, 0)
+
+	rows, err := d.DB.QueryContext(ctx,
+		`SELECT p.name, ps.name, COALESCE(v.value->>'vote', ''), ps.points, fr.voteend_time
+		FROM (
+			SELECT DISTINCT ON (vr.story_id) vr.story_id, vr.poker_id, vr.votes, vr.voteend_time
+			FROM thunderdome.poker_story_vote_round vr
+			WHERE vr.poker_id IN (SELECT poker_id FROM thunderdome.poker_user WHERE user_id = $1::uuid)
+			ORDER BY vr.story_id, vr.round DESC
+		) fr
+		JOIN thunderdome.poker p ON p.id = fr.poker_id
+		JOIN thunderdome.poker_story ps ON ps.id = fr.story_id
+		CROSS JOIN LATERAL jsonb_array_elements(fr.votes) v
+		WHERE v.value->>'warriorId' = $1::text AND ps.points <> ''
+		ORDER BY p.created_date DESC, fr.voteend_time DESC
+		LIMIT $2 OFFSET $3;`,
+		userID, limit, offset,
+	)
+	if err != nil {
+		return records, fmt.Errorf("get user estimation history query error: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var r thunderdome.UserEstimationRecord
+		if err := rows.Scan(&r.GameName, &r.StoryName, &r.UserVote, &r.FinalPoints, &r.CreatedAt); err != nil {
+			d.Logger.Ctx(ctx).Error("get user estimation history query scan error", zap.Error(err))
+			continue
+		}
+		r.Delta = estimationDelta(r.UserVote, r.FinalPoints)
+		records = append(records, r)
+	}
+
+	return records, nil
+}
+
+// estimationDelta gets the vote minus the final points rounded to a whole number,
+// votes or points that aren't numbers (e.g. ? or coffee) have no delta
+func estimationDelta(vote string, points string) int {
+	voteValue, ok := thunderdome.ParsePointValue(vote)
+	if !ok {
+		return 0
+	}
+	pointsValue, ok := thunderdome.ParsePointValue(points)
+	if !ok {
+		return 0
+	}
+
+	return int(math.Round(voteValue - pointsValue))
+}
@@ -0,0 +1,24 @@
+package poker
+
+import "testing"
+
+func TestEstimationDelta(t *testing.T) {
+	tests := []struct {
+		vote     string
+		points   string
+		expected int
+	}{
+		{vote: "8", points: "5", expected: 3},
+		{vote: "3", points: "5", expected: -2},
+		{vote: "5", points: "5", expected: 0},
+		{vote: "1/2", points: "2", expected: -2},
+		{vote: "?", points: "5", expected: 0},
+		{vote: "5", points: "☕️", expected: 0},
+	}
+
+	for _, tt := range tests {
+		if delta := estimationDelta(tt.vote, tt.points); delta != tt.expected {
+			t.Errorf("expected %s vs %s delta %d, got %d", tt.vote, tt.points, tt.expected, delta)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package poker
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// eventExecer is satisfied by both the database and a transaction so events can be appended as part of a transaction
+type eventExecer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// appendGameEvent appends an event to the game's event log, the game's event_sequence counter is incremented
+// in the same statement so concurrent events on the same game get sequential numbers
+func appendGameEvent(ctx context.Context, q eventExecer, pokerID string, eventType string, payload any) error {
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("poker event payload encode error: %v", err)
+	}
+
+	if _, err := q.ExecContext(ctx,
+		`WITH seq AS (
+			UPDATE thunderdome.poker SET event_sequence = event_sequence + 1 WHERE id = $1 RETURNING event_sequence
+		)
+		INSERT INTO thunderdome.poker_event (poker_id, sequence_number, event_type, payload)
+		SELECT $1, seq.event_sequence, $2, $3 FROM seq;`,
+		pokerID, eventType, payloadJSON,
+	); err != nil {
+		return fmt.Errorf("poker event append query error: %v", err)
+	}
+
+	return nil
+}
+
+// withGameEvent runs the game mutation and appends the event it returns in the same transaction,
+// the mutation is rolled back when its event can't be appended so the event log never misses a change
+func (d *Service) withGameEvent(ctx context.Context, pokerID string, eventType string, mutate func(tx *sql.Tx) (any, error)) error {
+	tx, err := d.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("poker event begin transaction error: %v", err)
+	}
+	defer tx.Rollback()
+
+	payload, err := mutate(tx)
+	if err != nil {
+		return err
+	}
+
+	if err := appendGameEvent(ctx, tx, pokerID, eventType, payload); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("poker event commit error: %v", err)
+	}
+
+	return nil
+}
+
+// GetGameEvents gets the game's event log in sequence number order
+func (d *Service) GetGameEvents(ctx context.Context, pokerID string) ([]*thunderdome.GameEvent, error) {
+	events := make([]*thunderdome.GameEvent, 0)
+
+	rows, err := d.DB.QueryContext(ctx,
+		`SELECT id, poker_id, sequence_number, event_type, payload, created_at
+		FROM thunderdome.poker_event WHERE poker_id = $1 ORDER BY sequence_number;`,
+		pokerID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get poker events query error: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e thunderdome.GameEvent
+		if err := rows.Scan(&e.ID, &e.PokerID, &e.SequenceNumber, &e.EventType, &e.Payload, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("get poker events scan error: %v", err)
+		}
+		events = append(events, &e)
+	}
+
+	return events, rows.Err()
+}
+
+// RebuildGameState replays the game's event log to reconstruct its state, then writes the rebuilt
+// settings, voting state and story points over the stored game and refreshes the cached game
+func (d *Service) RebuildGameState(ctx context.Context, pokerID string) (*thunderdome.Poker, error) {
+	events, err := d.GetGameEvents(ctx, pokerID)
+	if err != nil {
+		return nil, err
+	}
+
+	rebuilt, err := thunderdome.ReplayGameEvents(pokerID, events)
+	if err != nil {
+		return nil, err
+	}
+
+	scaleMapJSON, err := storyTypeScaleMapJSON(rebuilt.StoryTypeScaleMap)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := d.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("poker rebuild begin transaction error: %v", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		`UPDATE thunderdome.poker
+		SET name = $2, point_values_allowed = $3, auto_finish_voting = $4, point_average_rounding = $5,
+		 hide_voter_identity = $6, auto_finalize_on_consensus = $7, min_participants = $8, time_box_minutes = $9,
+		 story_type_scale_map = $10, voting_locked = $11, active_story_id = NULLIF($12, '')::uuid,
+		 quorum_percentage = $13, updated_date = NOW()
+		WHERE id = $1;`,
+		pokerID, rebuilt.Name, rebuilt.PointValuesAllowed, rebuilt.AutoFinishVoting, rebuilt.PointAverageRounding,
+		rebuilt.HideVoterIdentity, rebuilt.AutoFinalizeOnConsensus, rebuilt.MinParticipants, rebuilt.TimeBoxMinutes,
+		scaleMapJSON, rebuilt.VotingLocked, rebuilt.ActiveStoryID, rebuilt.QuorumPercentage,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("poker rebuild query error: %v", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return nil, fmt.Errorf("BATTLE_NOT_FOUND")
+	}
+
+	for _, story := range rebuilt.Stories {
+		votesJSON, err := json.Marshal(story.Votes)
+		if err != nil {
+			return nil, fmt.Errorf("poker rebuild story votes encode error: %v", err)
+		}
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE thunderdome.poker_story
+			SET active = $3, skipped = $4, points = $5, votes = $6, updated_date = NOW()
+			WHERE id = $1 AND poker_id = $2;`,
+			story.ID, pokerID, story.Active, story.Skipped, story.Points, votesJSON,
+		); err != nil {
+			return nil, fmt.Errorf("poker rebuild story query error: %v", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("poker rebuild commit error: %v", err)
+	}
+
+	if d.Redis != nil {
+		d.Redis.Del(ctx, fmt.Sprintf("game:%s", pokerID), fmt.Sprintf("game:%s:stories", pokerID),
+			leaderboardCacheKey(pokerID), statisticsCacheKey(pokerID))
+	}
+
+	// reading the game back caches the rebuilt state
+	return d.GetGameByID(pokerID, "")
+}
+
+// gameSettingsEvent gets the game created event payload for a new game
+func gameSettingsEvent(b *thunderdome.Poker) thunderdome.GameSettingsEvent {
+	return thunderdome.GameSettingsEvent{
+		Name:                    b.Name,
+		PointValuesAllowed:      b.PointValuesAllowed,
+		AutoFinishVoting:        b.AutoFinishVoting,
+		PointAverageRounding:    b.PointAverageRounding,
+		HideVoterIdentity:       b.HideVoterIdentity,
+		AutoFinalizeOnConsensus: b.AutoFinalizeOnConsensus,
+		MinParticipants:         b.MinParticipants,
+		TimeBoxMinutes:          b.TimeBoxMinutes,
+		QuorumPercentage:        b.QuorumPercentage,
+		StoryTypeScaleMap:       b.StoryTypeScaleMap,
+	}
+}
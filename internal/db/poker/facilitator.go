@@ -65,6 +65,18 @@ func (d *Service) RemoveFacilitator(pokerID string, userID string) ([]string, er
 		return nil, fmt.Errorf("poker remove facilitator query error: %v", err)
 	}
 
+	// removing the primary facilitator promotes the longest serving remaining facilitator
+	if _, err := d.DB.Exec(
+		`UPDATE thunderdome.poker_facilitator SET is_primary = true
+		WHERE poker_id = $1 AND user_id = (
+			SELECT user_id FROM thunderdome.poker_facilitator WHERE poker_id = $1 ORDER BY created_date LIMIT 1
+		) AND NOT EXISTS (
+			SELECT 1 FROM thunderdome.poker_facilitator WHERE poker_id = $1 AND is_primary = true
+		);`,
+		pokerID); err != nil {
+		return nil, fmt.Errorf("poker remove facilitator promote primary query error: %v", err)
+	}
+
 	rows, facilitatorErr := d.DB.Query(`
 		SELECT user_id FROM thunderdome.poker_facilitator WHERE poker_id = $1;
 	`, pokerID)
@@ -87,6 +99,85 @@ func (d *Service) RemoveFacilitator(pokerID string, userID string) ([]string, er
 	return facilitators, nil
 }
 
+// GetFacilitators retrieves the game facilitators ordered by when they became a facilitator
+func (d *Service) GetFacilitators(pokerID string) ([]*thunderdome.PokerFacilitator, error) {
+	facilitators := make([]*thunderdome.PokerFacilitator, 0)
+
+	rows, err := d.DB.Query(
+		`SELECT user_id, is_primary, created_date FROM thunderdome.poker_facilitator
+		WHERE poker_id = $1 ORDER BY created_date;`,
+		pokerID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("poker get facilitators query error: %v", err)
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var f thunderdome.PokerFacilitator
+		if err := rows.Scan(&f.UserID, &f.IsPrimaryFacilitator, &f.CreatedDate); err != nil {
+			d.Logger.Error("poker_facilitator query scan error", zap.Error(err))
+		} else {
+			facilitators = append(facilitators, &f)
+		}
+	}
+
+	return facilitators, nil
+}
+
+// TransferPrimaryFacilitator hands the primary facilitator role over to another facilitator of the game
+func (d *Service) TransferPrimaryFacilitator(ctx context.Context, pokerID string, currentPrimaryID string, newPrimaryID string) error {
+	tx, err := d.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("poker transfer primary facilitator begin transaction error: %v", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx,
+		`SELECT user_id, is_primary, created_date FROM thunderdome.poker_facilitator
+		WHERE poker_id = $1 ORDER BY created_date FOR UPDATE;`,
+		pokerID,
+	)
+	if err != nil {
+		return fmt.Errorf("poker transfer primary facilitator query error: %v", err)
+	}
+
+	facilitators := make([]*thunderdome.PokerFacilitator, 0)
+	for rows.Next() {
+		var f thunderdome.PokerFacilitator
+		if err := rows.Scan(&f.UserID, &f.IsPrimaryFacilitator, &f.CreatedDate); err != nil {
+			rows.Close()
+			return fmt.Errorf("poker transfer primary facilitator query scan error: %v", err)
+		}
+		facilitators = append(facilitators, &f)
+	}
+	rows.Close()
+
+	if err := thunderdome.ValidatePrimaryFacilitatorTransfer(facilitators, currentPrimaryID, newPrimaryID); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE thunderdome.poker_facilitator SET is_primary = false WHERE poker_id = $1 AND user_id = $2;`,
+		pokerID, currentPrimaryID,
+	); err != nil {
+		return fmt.Errorf("poker transfer primary facilitator demote query error: %v", err)
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE thunderdome.poker_facilitator SET is_primary = true WHERE poker_id = $1 AND user_id = $2;`,
+		pokerID, newPrimaryID,
+	); err != nil {
+		return fmt.Errorf("poker transfer primary facilitator promote query error: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("poker transfer primary facilitator commit error: %v", err)
+	}
+
+	return nil
+}
+
 // AddFacilitatorsByEmail adds additional game facilitators by email
 func (d *Service) AddFacilitatorsByEmail(ctx context.Context, pokerID string, facilitatorEmails []string) ([]string, error) {
 	var facilitators string
@@ -152,3 +243,26 @@ func (d *Service) GetFacilitatorCode(pokerID string) (string, error) {
 
 	return decryptedCode, nil
 }
+
+// GetObserverCode retrieve the game observer_code
+func (d *Service) GetObserverCode(pokerID string) (string, error) {
+	var encryptedObserverCode string
+
+	if err := d.DB.QueryRow(`
+		SELECT COALESCE(observer_code, '') FROM thunderdome.poker
+		WHERE id = $1`,
+		pokerID,
+	).Scan(&encryptedObserverCode); err != nil {
+		return "", fmt.Errorf("get poker observer code query error: %v", err)
+	}
+
+	if encryptedObserverCode == "" {
+		return "", fmt.Errorf("poker observer code not set")
+	}
+	decryptedCode, codeErr := db.Decrypt(encryptedObserverCode, d.AESHashKey)
+	if codeErr != nil {
+		return "", fmt.Errorf("get poker observer code decrypt error: %v", codeErr)
+	}
+
+	return decryptedCode, nil
+}
@@ -0,0 +1,86 @@
+package poker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+// SetFacilitatorNote sets the private facilitator notes of a story, empty notes remove them
+func (d *Service) SetFacilitatorNote(ctx context.Context, storyID string, facilitatorID string, notes string) error {
+	var pokerID string
+	if err := d.DB.QueryRowContext(ctx,
+		`SELECT poker_id FROM thunderdome.poker_story WHERE id = $1;`,
+		storyID,
+	).Scan(&pokerID); err != nil {
+		return fmt.Errorf("STORY_NOT_FOUND")
+	}
+
+	if err := d.ConfirmFacilitator(pokerID, facilitatorID); err != nil {
+		return fmt.Errorf("REQUIRES_FACILITATOR")
+	}
+
+	if notes == "" {
+		if _, err := d.DB.ExecContext(ctx,
+			`DELETE FROM thunderdome.poker_story_facilitator_note WHERE story_id = $1;`,
+			storyID,
+		); err != nil {
+			return fmt.Errorf("delete poker story facilitator note query error: %v", err)
+		}
+		return nil
+	}
+
+	if _, err := d.DB.ExecContext(ctx,
+		`INSERT INTO thunderdome.poker_story_facilitator_note (story_id, notes, updated_by)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (story_id) DO UPDATE
+		SET notes = EXCLUDED.notes, updated_by = EXCLUDED.updated_by, updated_date = NOW();`,
+		storyID, notes, facilitatorID,
+	); err != nil {
+		return fmt.Errorf("set poker story facilitator note query error: %v", err)
+	}
+
+	return nil
+}
+
+// attachFacilitatorNotes populates the stories facilitator notes when the user is a game facilitator
+func (d *Service) attachFacilitatorNotes(pokerID string, userID string, stories []*thunderdome.Story) {
+	if userID == "" || len(stories) == 0 {
+		return
+	}
+	if err := d.ConfirmFacilitator(pokerID, userID); err != nil {
+		return
+	}
+
+	rows, err := d.DB.Query(
+		`SELECT fn.story_id, fn.notes
+		FROM thunderdome.poker_story_facilitator_note fn
+		JOIN thunderdome.poker_story ps ON ps.id = fn.story_id
+		WHERE ps.poker_id = $1;`,
+		pokerID,
+	)
+	if err != nil {
+		d.Logger.Error("get poker story facilitator notes query error", zap.Error(err),
+			zap.String("poker_id", pokerID))
+		return
+	}
+	defer rows.Close()
+
+	notes := make(map[string]string)
+	for rows.Next() {
+		var storyID, note string
+		if err := rows.Scan(&storyID, &note); err != nil {
+			d.Logger.Error("get poker story facilitator notes scan error", zap.Error(err))
+			continue
+		}
+		notes[storyID] = note
+	}
+
+	for _, story := range stories {
+		if note, ok := notes[story.ID]; ok {
+			story.FacilitatorNotes = &note
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package poker
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// gameTemplateColumns are the poker_game_template columns scanned by scanGameTemplate, along with
+// the created date of the last game created from the template
+const gameTemplateColumns = `t.id, t.team_id, t.created_by, t.name, COALESCE(t.estimation_scale_id::text, ''),
+	t.point_values_allowed, t.auto_finish_voting, t.point_average_rounding, t.hide_voter_identity, t.recurrence_cron,
+	t.created_date, t.updated_date,
+	(SELECT MAX(p.created_date) FROM thunderdome.poker p WHERE p.template_id = t.id)`
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanGameTemplate scans a row selected with gameTemplateColumns
+func scanGameTemplate(row rowScanner) (*thunderdome.GameTemplate, error) {
+	template := thunderdome.GameTemplate{}
+	var pointValues []byte
+	var lastGameDate sql.NullTime
+
+	if err := row.Scan(
+		&template.ID, &template.TeamID, &template.CreatedBy, &template.Name, &template.EstimationScaleID,
+		&pointValues, &template.AutoFinishVoting, &template.PointAverageRounding, &template.HideVoterIdentity,
+		&template.RecurrenceCron, &template.CreatedDate, &template.UpdatedDate, &lastGameDate,
+	); err != nil {
+		return nil, err
+	}
+
+	_ = json.Unmarshal(pointValues, &template.PointValuesAllowed)
+	if lastGameDate.Valid {
+		template.LastGameDate = &lastGameDate.Time
+	}
+
+	return &template, nil
+}
+
+// GetGameTemplates gets the team's poker game templates
+func (d *Service) GetGameTemplates(ctx context.Context, teamID string) ([]*thunderdome.GameTemplate, error) {
+	templates := make([]*thunderdome.GameTemplate, 0)
+
+	rows, err := d.DB.QueryContext(ctx,
+		`SELECT `+gameTemplateColumns+`
+		FROM thunderdome.poker_game_template t WHERE t.team_id = $1 ORDER BY t.name;`,
+		teamID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get poker game templates query error: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		template, err := scanGameTemplate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("get poker game templates scan error: %v", err)
+		}
+		templates = append(templates, template)
+	}
+
+	return templates, nil
+}
+
+// GetGameTemplateByID gets the team's poker game template
+func (d *Service) GetGameTemplateByID(ctx context.Context, teamID string, templateID string) (*thunderdome.GameTemplate, error) {
+	template, err := scanGameTemplate(d.DB.QueryRowContext(ctx,
+		`SELECT `+gameTemplateColumns+`
+		FROM thunderdome.poker_game_template t WHERE t.team_id = $1 AND t.id = $2;`,
+		teamID, templateID,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("GAME_TEMPLATE_NOT_FOUND")
+		}
+		return nil, fmt.Errorf("get poker game template query error: %v", err)
+	}
+
+	return template, nil
+}
+
+// CreateGameTemplate creates a poker game template for the team, the user facilitates the games created from it
+func (d *Service) CreateGameTemplate(ctx context.Context, teamID string, userID string, template *thunderdome.GameTemplate) (*thunderdome.GameTemplate, error) {
+	pointValues, err := json.Marshal(template.PointValuesAllowed)
+	if err != nil {
+		return nil, fmt.Errorf("create poker game template point values error: %v", err)
+	}
+
+	var templateID string
+	err = d.DB.QueryRowContext(ctx,
+		`INSERT INTO thunderdome.poker_game_template (
+			team_id, created_by, name, estimation_scale_id, point_values_allowed, auto_finish_voting,
+			point_average_rounding, hide_voter_identity, recurrence_cron
+		) VALUES ($1, $2, $3, NULLIF($4, '')::uuid, $5, $6, $7, $8, $9)
+		RETURNING id;`,
+		teamID, userID, template.Name, template.EstimationScaleID, pointValues, template.AutoFinishVoting,
+		template.PointAverageRounding, template.HideVoterIdentity, template.RecurrenceCron,
+	).Scan(&templateID)
+	if err != nil {
+		return nil, fmt.Errorf("create poker game template query error: %v", err)
+	}
+
+	return d.GetGameTemplateByID(ctx, teamID, templateID)
+}
+
+// UpdateGameTemplate updates the team's poker game template
+func (d *Service) UpdateGameTemplate(ctx context.Context, teamID string, templateID string, template *thunderdome.GameTemplate) (*thunderdome.GameTemplate, error) {
+	pointValues, err := json.Marshal(template.PointValuesAllowed)
+	if err != nil {
+		return nil, fmt.Errorf("update poker game template point values error: %v", err)
+	}
+
+	result, err := d.DB.ExecContext(ctx,
+		`UPDATE thunderdome.poker_game_template SET name = $3, estimation_scale_id = NULLIF($4, '')::uuid,
+			point_values_allowed = $5, auto_finish_voting = $6, point_average_rounding = $7,
+			hide_voter_identity = $8, recurrence_cron = $9, updated_date = NOW()
+		WHERE team_id = $1 AND id = $2;`,
+		teamID, templateID, template.Name, template.EstimationScaleID, pointValues, template.AutoFinishVoting,
+		template.PointAverageRounding, template.HideVoterIdentity, template.RecurrenceCron,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("update poker game template query error: %v", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return nil, errors.New("GAME_TEMPLATE_NOT_FOUND")
+	}
+
+	return d.GetGameTemplateByID(ctx, teamID, templateID)
+}
+
+// DeleteGameTemplate deletes the team's poker game template, games created from it are kept
+func (d *Service) DeleteGameTemplate(ctx context.Context, teamID string, templateID string) error {
+	result, err := d.DB.ExecContext(ctx,
+		`DELETE FROM thunderdome.poker_game_template WHERE team_id = $1 AND id = $2;`,
+		teamID, templateID,
+	)
+	if err != nil {
+		return fmt.Errorf("delete poker game template query error: %v", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return errors.New("GAME_TEMPLATE_NOT_FOUND")
+	}
+
+	return nil
+}
+
+// GetRecurringGameTemplates gets every poker game template with a recurrence cron
+func (d *Service) GetRecurringGameTemplates(ctx context.Context) ([]*thunderdome.GameTemplate, error) {
+	templates := make([]*thunderdome.GameTemplate, 0)
+
+	rows, err := d.DB.QueryContext(ctx,
+		`SELECT `+gameTemplateColumns+`
+		FROM thunderdome.poker_game_template t WHERE t.recurrence_cron != '';`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get recurring poker game templates query error: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		template, err := scanGameTemplate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("get recurring poker game templates scan error: %v", err)
+		}
+		templates = append(templates, template)
+	}
+
+	return templates, nil
+}
+
+// CreateRecurringGame claims the template's cron window and creates the team game from the template in one
+// transaction, returning false when a game was already created for that window (or a later one). The conditional
+// update keeps two servers evaluating the same template from both creating the game.
+func (d *Service) CreateRecurringGame(ctx context.Context, template *thunderdome.GameTemplate, name string, windowStart time.Time) (*thunderdome.Poker, bool, error) {
+	b := &thunderdome.Poker{
+		Name:                 name,
+		Users:                make([]*thunderdome.PokerUser, 0),
+		Stories:              make([]*thunderdome.Story, 0),
+		VotingLocked:         true,
+		PointValuesAllowed:   template.PointValuesAllowed,
+		AutoFinishVoting:     template.AutoFinishVoting,
+		PointAverageRounding: template.PointAverageRounding,
+		HideVoterIdentity:    template.HideVoterIdentity,
+		Facilitators:         []string{template.CreatedBy},
+		EstimationScaleID:    template.EstimationScaleID,
+		TeamID:               template.TeamID,
+	}
+
+	tx, err := d.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("create recurring poker begin transaction error: %v", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		`UPDATE thunderdome.poker_game_template SET last_window_date = $2
+		WHERE id = $1 AND (last_window_date IS NULL OR last_window_date < $2);`,
+		template.ID, windowStart,
+	)
+	if err != nil {
+		return nil, false, fmt.Errorf("claim poker game template window query error: %v", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return nil, false, nil
+	}
+
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO thunderdome.poker (
+			name, voting_locked, point_values_allowed, auto_finish_voting, point_average_rounding,
+			hide_voter_identity, estimation_scale_id, team_id, template_id, created_date, updated_date
+		) VALUES ($1, true, $2, $3, $4, $5, NULLIF($6, '')::uuid, $7, $8, NOW(), NOW())
+		RETURNING id;`,
+		name, template.PointValuesAllowed, template.AutoFinishVoting, template.PointAverageRounding,
+		template.HideVoterIdentity, template.EstimationScaleID, template.TeamID, template.ID,
+	).Scan(&b.ID)
+	if err != nil {
+		return nil, false, fmt.Errorf("create recurring poker query error: %v", err)
+	}
+
+	if _, err = tx.ExecContext(ctx,
+		`INSERT INTO thunderdome.poker_facilitator (poker_id, user_id, is_primary) VALUES ($1, $2, true);`,
+		b.ID, template.CreatedBy,
+	); err != nil {
+		return nil, false, fmt.Errorf("create recurring poker facilitator error: %v", err)
+	}
+
+	if err = appendGameEvent(ctx, tx, b.ID, thunderdome.GameEventCreated, gameSettingsEvent(b)); err != nil {
+		return nil, false, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, false, fmt.Errorf("create recurring poker commit error: %v", err)
+	}
+
+	return b, true, nil
+}
@@ -0,0 +1,133 @@
+package poker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// BulkAddStories adds multiple stories to the game, when deduplicate is enabled a story with a
+// reference_id already in the game has its description and acceptance criteria updated instead
+func (d *Service) BulkAddStories(ctx context.Context, pokerID string, stories []*thunderdome.Story, deduplicate bool) (*thunderdome.DuplicationResult, error) {
+	if err := validateStoryLinks(stories); err != nil {
+		return nil, err
+	}
+
+	existingRefs := make(map[string]struct{})
+
+	if deduplicate {
+		rows, err := d.DB.QueryContext(ctx,
+			`SELECT reference_id FROM thunderdome.poker_story WHERE poker_id = $1 AND COALESCE(reference_id, '') <> '';`,
+			pokerID,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("bulk add stories get reference ids query error: %v", err)
+		}
+		for rows.Next() {
+			var referenceID string
+			if err := rows.Scan(&referenceID); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("bulk add stories get reference ids scan error: %v", err)
+			}
+			existingRefs[referenceID] = struct{}{}
+		}
+		rows.Close()
+	}
+
+	inserts, updates, result := planStoryImport(existingRefs, stories, deduplicate)
+
+	tx, err := d.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("bulk add stories begin transaction error: %v", err)
+	}
+	defer tx.Rollback()
+
+	for _, s := range updates {
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE thunderdome.poker_story
+			SET description = $3, acceptance_criteria = $4, updated_date = NOW()
+			WHERE poker_id = $1 AND reference_id = $2;`,
+			pokerID, s.ReferenceID,
+			d.HTMLSanitizerPolicy.Sanitize(s.Description),
+			d.HTMLSanitizerPolicy.Sanitize(s.AcceptanceCriteria),
+		); err != nil {
+			return nil, fmt.Errorf("bulk add stories update query error: %v", err)
+		}
+	}
+
+	for _, s := range inserts {
+		priority := s.Priority
+		// default priority should be 99 for sort order purposes
+		if priority == 0 {
+			priority = 99
+		}
+		var storyID string
+		if err := tx.QueryRowContext(ctx,
+			`INSERT INTO thunderdome.poker_story (
+			poker_id, name, type, reference_id, link, description, acceptance_criteria, priority, estimate_hint, position)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9, ''), (
+			  coalesce(
+				(select max(position) from thunderdome.poker_story where poker_id = $1),
+				-1
+			  ) + 1
+			))
+			RETURNING id;`,
+			pokerID, s.Name, s.Type, s.ReferenceID, s.Link,
+			d.HTMLSanitizerPolicy.Sanitize(s.Description),
+			d.HTMLSanitizerPolicy.Sanitize(s.AcceptanceCriteria),
+			priority, s.EstimateHint,
+		).Scan(&storyID); err != nil {
+			return nil, fmt.Errorf("bulk add stories insert query error: %v", err)
+		}
+		if err := appendGameEvent(ctx, tx, pokerID, thunderdome.GameEventStoryAdded, thunderdome.GameStoryEvent{
+			StoryID: storyID, Name: s.Name,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("bulk add stories commit error: %v", err)
+	}
+
+	// 清除缓存
+	if d.Redis != nil {
+		d.Redis.Del(ctx, fmt.Sprintf("game:%s:stories", pokerID), fmt.Sprintf("game:%s", pokerID))
+	}
+
+	return result, nil
+}
+
+// planStoryImport splits the imported stories into those to insert and those to update based on
+// the reference_ids already in the game, repeated reference_ids within the import are skipped
+func planStoryImport(existingRefs map[string]struct{}, stories []*thunderdome.Story, deduplicate bool) ([]*thunderdome.Story, []*thunderdome.Story, *thunderdome.DuplicationResult) {
+	inserts := make([]*thunderdome.Story, 0)
+	updates := make([]*thunderdome.Story, 0)
+	result := &thunderdome.DuplicationResult{}
+	seenRefs := make(map[string]struct{})
+
+	for _, s := range stories {
+		if !deduplicate || s.ReferenceID == "" {
+			inserts = append(inserts, s)
+			result.InsertedCount++
+			continue
+		}
+
+		if _, seen := seenRefs[s.ReferenceID]; seen {
+			result.SkippedCount++
+			continue
+		}
+		seenRefs[s.ReferenceID] = struct{}{}
+
+		if _, exists := existingRefs[s.ReferenceID]; exists {
+			updates = append(updates, s)
+			result.UpdatedCount++
+		} else {
+			inserts = append(inserts, s)
+			result.InsertedCount++
+		}
+	}
+
+	return inserts, updates, result
+}
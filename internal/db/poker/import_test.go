@@ -0,0 +1,65 @@
+package poker
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// TestPlanStoryImportReimport imports the same set of stories twice and makes sure
+// the second import updates the existing stories instead of inserting duplicates
+func TestPlanStoryImportReimport(t *testing.T) {
+	stories := make([]*thunderdome.Story, 0)
+	for i := 1; i <= 10; i++ {
+		stories = append(stories, &thunderdome.Story{
+			Name:        fmt.Sprintf("Story %d", i),
+			ReferenceID: fmt.Sprintf("TD-%d", i),
+		})
+	}
+	// a story without a reference id can't be deduplicated
+	stories = append(stories, &thunderdome.Story{Name: "No Reference"})
+
+	existingRefs := make(map[string]struct{})
+	inserts, updates, result := planStoryImport(existingRefs, stories, true)
+	if result.InsertedCount+result.UpdatedCount != len(stories) {
+		t.Fatalf(`expected first import InsertedCount+UpdatedCount to be %d, got %d`,
+			len(stories), result.InsertedCount+result.UpdatedCount)
+	}
+	if len(inserts) != 11 || len(updates) != 0 {
+		t.Fatalf(`expected first import to insert 11 and update 0, got %d and %d`, len(inserts), len(updates))
+	}
+
+	for _, s := range inserts {
+		if s.ReferenceID != "" {
+			existingRefs[s.ReferenceID] = struct{}{}
+		}
+	}
+
+	_, updates, result = planStoryImport(existingRefs, stories, true)
+	if result.UpdatedCount != 10 || result.InsertedCount != 1 {
+		t.Fatalf(`expected second import to update 10 and insert 1, got %d and %d`,
+			result.UpdatedCount, result.InsertedCount)
+	}
+	if len(updates) != 10 {
+		t.Fatalf(`expected 10 stories to update, got %d`, len(updates))
+	}
+}
+
+// TestPlanStoryImportSkipsRepeatedReference makes sure a reference id repeated within an import is skipped
+func TestPlanStoryImportSkipsRepeatedReference(t *testing.T) {
+	stories := []*thunderdome.Story{
+		{Name: "Story 1", ReferenceID: "TD-1"},
+		{Name: "Story 1 Again", ReferenceID: "TD-1"},
+	}
+
+	_, _, result := planStoryImport(map[string]struct{}{}, stories, true)
+	if result.InsertedCount != 1 || result.SkippedCount != 1 {
+		t.Fatalf(`expected 1 inserted and 1 skipped, got %d and %d`, result.InsertedCount, result.SkippedCount)
+	}
+
+	_, _, result = planStoryImport(map[string]struct{}{}, stories, false)
+	if result.InsertedCount != 2 {
+		t.Fatalf(`expected 2 inserted with deduplication disabled, got %d`, result.InsertedCount)
+	}
+}
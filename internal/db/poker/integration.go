@@ -0,0 +1,55 @@
+package poker
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/db"
+)
+
+// SaveGameIntegration stores the game's integration target and access token, the token is encrypted at rest
+// and replaces any token previously saved for the provider
+func (d *Service) SaveGameIntegration(ctx context.Context, pokerID string, provider string, target string, accessToken string) error {
+	encryptedToken, err := db.Encrypt(accessToken, d.AESHashKey)
+	if err != nil {
+		return fmt.Errorf("save poker integration encrypt token error: %v", err)
+	}
+
+	_, err = d.DB.ExecContext(ctx,
+		`INSERT INTO thunderdome.game_integration (poker_id, provider, target, access_token)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (poker_id, provider) DO UPDATE
+		SET target = EXCLUDED.target, access_token = EXCLUDED.access_token, updated_date = NOW();`,
+		pokerID, provider, target, encryptedToken,
+	)
+	if err != nil {
+		return fmt.Errorf("save poker integration query error: %v", err)
+	}
+
+	return nil
+}
+
+// GetGameIntegrationToken gets the decrypted access token saved for the game's provider integration
+// along with the target it was saved for
+func (d *Service) GetGameIntegrationToken(ctx context.Context, pokerID string, provider string) (string, string, error) {
+	var encryptedToken, target string
+	err := d.DB.QueryRowContext(ctx,
+		`SELECT access_token, target FROM thunderdome.game_integration WHERE poker_id = $1 AND provider = $2;`,
+		pokerID, provider,
+	).Scan(&encryptedToken, &target)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", "", fmt.Errorf("INTEGRATION_NOT_FOUND")
+		}
+		return "", "", fmt.Errorf("get poker integration query error: %v", err)
+	}
+
+	token, err := db.Decrypt(encryptedToken, d.AESHashKey)
+	if err != nil {
+		return "", "", fmt.Errorf("get poker integration decrypt token error: %v", err)
+	}
+
+	return token, target, nil
+}
@@ -0,0 +1,72 @@
+package poker
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/db"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// joinDeepLinkCacheTTL is the longest a generated join deep link is cached
+const joinDeepLinkCacheTTL = time.Hour
+
+func joinDeepLinkCacheKey(pokerID string) string {
+	return fmt.Sprintf("game:join-link:%s", pokerID)
+}
+
+// GenerateJoinDeepLink gets the link that joins the game with its join code in one step,
+// only the link is cached, the join code itself is never stored in the cache
+func (d *Service) GenerateJoinDeepLink(ctx context.Context, pokerID string) (string, error) {
+	cacheKey := joinDeepLinkCacheKey(pokerID)
+	ttl := thunderdome.CappedTTL(d.CacheTTL.GameTTL, joinDeepLinkCacheTTL)
+	if d.Redis != nil && ttl > 0 {
+		if link, err := d.Redis.Get(ctx, cacheKey).Result(); err == nil {
+			return link, nil
+		}
+	}
+
+	var encryptedJoinCode string
+	err := d.DB.QueryRowContext(ctx,
+		`SELECT COALESCE(join_code, '') FROM thunderdome.poker WHERE id = $1;`,
+		pokerID,
+	).Scan(&encryptedJoinCode)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("BATTLE_NOT_FOUND")
+		}
+		return "", fmt.Errorf("generate poker join deep link query error: %v", err)
+	}
+	if encryptedJoinCode == "" {
+		return "", fmt.Errorf("JOIN_CODE_NOT_SET")
+	}
+
+	joinCode, err := db.Decrypt(encryptedJoinCode, d.AESHashKey)
+	if err != nil {
+		return "", fmt.Errorf("generate poker join deep link decrypt error: %v", err)
+	}
+
+	link := thunderdome.JoinDeepLink(pokerID, joinCode)
+	if d.Redis != nil && ttl > 0 {
+		d.Redis.Set(ctx, cacheKey, link, ttl)
+	}
+
+	return link, nil
+}
+
+// JoinGame adds the user to the game as an inactive participant, the user is activated once they connect to the game
+func (d *Service) JoinGame(ctx context.Context, pokerID string, userID string) error {
+	if _, err := d.DB.ExecContext(ctx,
+		`INSERT INTO thunderdome.poker_user (poker_id, user_id, active)
+		VALUES ($1, $2, false)
+		ON CONFLICT (poker_id, user_id) DO UPDATE SET abandoned = false;`,
+		pokerID, userID,
+	); err != nil {
+		return fmt.Errorf("join poker query error: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package poker
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// participantsCSVHeaders are the columns of the participant attendance export
+var participantsCSVHeaders = []string{"UserID", "Username", "Email", "JoinedAt", "LeftAt", "VoteCount", "WasSpectator"}
+
+// redactedEmail replaces participant emails in exports requested by facilitators who aren't organization admins
+const redactedEmail = "REDACTED"
+
+// ExportParticipants exports the poker game participants' attendance as csv, only facilitators can export it and
+// emails are only included when the facilitator is an admin of the organization the game's team belongs to
+func (d *Service) ExportParticipants(ctx context.Context, pokerID string, requestingUserID string) ([]byte, error) {
+	if err := d.ConfirmFacilitator(pokerID, requestingUserID); err != nil {
+		return nil, fmt.Errorf("REQUIRES_FACILITATOR")
+	}
+
+	var isOrgAdmin bool
+	if err := d.DB.QueryRowContext(ctx,
+		`SELECT EXISTS (
+			SELECT 1 FROM thunderdome.poker p
+			JOIN thunderdome.team t ON t.id = p.team_id
+			JOIN thunderdome.organization_user ou ON ou.organization_id = COALESCE(t.organization_id,
+				(SELECT organization_id FROM thunderdome.organization_department WHERE id = t.department_id))
+			WHERE p.id = $1 AND ou.user_id = $2 AND ou.role = 'ADMIN'
+		);`,
+		pokerID, requestingUserID,
+	).Scan(&isOrgAdmin); err != nil {
+		return nil, fmt.Errorf("export poker participants org admin query error: %v", err)
+	}
+
+	rows, err := d.DB.QueryContext(ctx,
+		`SELECT u.id, u.name, COALESCE(u.email, ''),
+			(SELECT MIN(al.created_at) FROM thunderdome.poker_access_log al
+				WHERE al.poker_id = pu.poker_id AND al.user_id = u.id AND al.event_type = $2),
+			(SELECT MAX(al.created_at) FROM thunderdome.poker_access_log al
+				WHERE al.poker_id = pu.poker_id AND al.user_id = u.id AND al.event_type = $3),
+			(SELECT COUNT(*) FROM thunderdome.poker_story ps
+				CROSS JOIN LATERAL jsonb_array_elements(COALESCE(ps.votes, '[]'::jsonb)) v
+				WHERE ps.poker_id = pu.poker_id AND v->>'warriorId' = u.id::text),
+			pu.spectator
+		FROM thunderdome.poker_user pu
+		JOIN thunderdome.users u ON u.id = pu.user_id
+		WHERE pu.poker_id = $1
+		ORDER BY u.name;`,
+		pokerID, thunderdome.PokerAccessEventJoin, thunderdome.PokerAccessEventLeave,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("export poker participants query error: %v", err)
+	}
+	defer rows.Close()
+
+	participants := make([]*thunderdome.PokerParticipantRecord, 0)
+	for rows.Next() {
+		var p thunderdome.PokerParticipantRecord
+		if err := rows.Scan(&p.UserID, &p.Username, &p.Email, &p.JoinedAt, &p.LeftAt, &p.VoteCount, &p.WasSpectator); err != nil {
+			return nil, fmt.Errorf("export poker participants query scan error: %v", err)
+		}
+		participants = append(participants, &p)
+	}
+
+	var export bytes.Buffer
+	if err := writeParticipantsCSV(&export, participants, isOrgAdmin); err != nil {
+		return nil, fmt.Errorf("export poker participants csv error: %v", err)
+	}
+
+	return export.Bytes(), nil
+}
+
+// writeParticipantsCSV writes the participants' attendance as csv, emails are redacted unless includeEmail is set
+func writeParticipantsCSV(w io.Writer, participants []*thunderdome.PokerParticipantRecord, includeEmail bool) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(participantsCSVHeaders); err != nil {
+		return err
+	}
+
+	for _, p := range participants {
+		email := redactedEmail
+		if includeEmail {
+			email = p.Email
+		}
+		if err := writer.Write([]string{
+			p.UserID,
+			p.Username,
+			email,
+			formatParticipantTime(p.JoinedAt),
+			formatParticipantTime(p.LeftAt),
+			strconv.Itoa(p.VoteCount),
+			strconv.FormatBool(p.WasSpectator),
+		}); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
+
+func formatParticipantTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
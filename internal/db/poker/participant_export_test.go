@@ -0,0 +1,53 @@
+package poker
+
+import (
+	"bytes"
+	"encoding/csv"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// TestWriteParticipantsCSVEmailRedaction makes sure participant emails are only exported
+// for facilitators who are organization admins
+func TestWriteParticipantsCSVEmailRedaction(t *testing.T) {
+	joinedAt := time.Date(2025, 3, 16, 9, 0, 0, 0, time.UTC)
+	leftAt := time.Date(2025, 3, 16, 10, 30, 0, 0, time.UTC)
+	participants := []*thunderdome.PokerParticipantRecord{
+		{UserID: "user-1", Username: "Ann", Email: "ann@example.com", JoinedAt: &joinedAt, LeftAt: &leftAt, VoteCount: 7},
+		{UserID: "user-2", Username: "Bob", Email: "bob@example.com", JoinedAt: &joinedAt, WasSpectator: true},
+	}
+
+	tests := []struct {
+		name         string
+		includeEmail bool
+		emails       []string
+	}{
+		{name: "org admin facilitator", includeEmail: true, emails: []string{"ann@example.com", "bob@example.com"}},
+		{name: "non admin facilitator", includeEmail: false, emails: []string{redactedEmail, redactedEmail}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var export bytes.Buffer
+			if err := writeParticipantsCSV(&export, participants, tt.includeEmail); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			records, err := csv.NewReader(&export).ReadAll()
+			if err != nil {
+				t.Fatalf("export isn't valid csv: %v", err)
+			}
+			expected := [][]string{
+				participantsCSVHeaders,
+				{"user-1", "Ann", tt.emails[0], "2025-03-16T09:00:00Z", "2025-03-16T10:30:00Z", "7", "false"},
+				{"user-2", "Bob", tt.emails[1], "2025-03-16T09:00:00Z", "", "0", "true"},
+			}
+			if !reflect.DeepEqual(records, expected) {
+				t.Errorf("expected %v, got %v", expected, records)
+			}
+		})
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
-	"time"
 
 	"github.com/jackc/pgx/v5/pgtype"
 
@@ -22,16 +21,37 @@ import (
 // Service represents the poker database service
 type Service struct {
 	DB                  *sql.DB
+	ReadDB              *sql.DB
 	Logger              *otelzap.Logger
 	AESHashKey          string
 	HTMLSanitizerPolicy *bluemonday.Policy
 	Redis               *redis.Client
+	// CacheTTL is how long games and stories are cached, a zero TTL disables the cache
+	CacheTTL thunderdome.CacheTTLConfig
+	// IPHashSalt salts the hashed access log ip addresses, ip addresses aren't recorded when it's empty
+	IPHashSalt string
+}
+
+// reader gets the optional read replica for the reporting and admin listing queries, falling back to the primary
+// when no replica is configured. Reads a user expects to see their own changes in (the game, its stories, users,
+// comments, chat, attachments and the user's game list) use the primary so they aren't affected by replication lag
+func (d *Service) reader() *sql.DB {
+	if d.ReadDB != nil {
+		return d.ReadDB
+	}
+
+	return d.DB
 }
 
 // CreateGame creates a new story pointing session
-func (d *Service) CreateGame(ctx context.Context, facilitatorID string, name string, estimationScaleID string, pointValuesAllowed []string, stories []*thunderdome.Story, autoFinishVoting bool, pointAverageRounding string, joinCode string, facilitatorCode string, hideVoterIdentity bool) (*thunderdome.Poker, error) {
+func (d *Service) CreateGame(ctx context.Context, facilitatorID string, name string, estimationScaleID string, pointValuesAllowed []string, stories []*thunderdome.Story, autoFinishVoting bool, pointAverageRounding string, joinCode string, facilitatorCode string, observerCode string, hideVoterIdentity bool, autoFinalizeOnConsensus bool, minParticipants int, timeBoxMinutes int, quorumPercentage float64, storyTypeScaleMap map[string]string) (*thunderdome.Poker, error) {
+	if err := validateStoryLinks(stories); err != nil {
+		return nil, err
+	}
+
 	var encryptedJoinCode string
 	var encryptedLeaderCode string
+	var encryptedObserverCode string
 
 	if joinCode != "" {
 		EncryptedCode, codeErr := db.Encrypt(joinCode, d.AESHashKey)
@@ -49,19 +69,38 @@ func (d *Service) CreateGame(ctx context.Context, facilitatorID string, name str
 		encryptedLeaderCode = EncryptedCode
 	}
 
+	if observerCode != "" {
+		EncryptedCode, codeErr := db.Encrypt(observerCode, d.AESHashKey)
+		if codeErr != nil {
+			return nil, fmt.Errorf("create poker encrypt observer_code error: %v", codeErr)
+		}
+		encryptedObserverCode = EncryptedCode
+	}
+
+	scaleMapJSON, err := storyTypeScaleMapJSON(storyTypeScaleMap)
+	if err != nil {
+		return nil, err
+	}
+
 	var b = &thunderdome.Poker{
-		Name:                 name,
-		Users:                make([]*thunderdome.PokerUser, 0),
-		Stories:              make([]*thunderdome.Story, 0),
-		VotingLocked:         true,
-		PointValuesAllowed:   pointValuesAllowed,
-		AutoFinishVoting:     autoFinishVoting,
-		PointAverageRounding: pointAverageRounding,
-		HideVoterIdentity:    hideVoterIdentity,
-		Facilitators:         make([]string, 0),
-		JoinCode:             joinCode,
-		FacilitatorCode:      facilitatorCode,
-		EstimationScaleID:    estimationScaleID,
+		Name:                    name,
+		Users:                   make([]*thunderdome.PokerUser, 0),
+		Stories:                 make([]*thunderdome.Story, 0),
+		VotingLocked:            true,
+		PointValuesAllowed:      pointValuesAllowed,
+		AutoFinishVoting:        autoFinishVoting,
+		PointAverageRounding:    pointAverageRounding,
+		HideVoterIdentity:       hideVoterIdentity,
+		AutoFinalizeOnConsensus: autoFinalizeOnConsensus,
+		MinParticipants:         minParticipants,
+		TimeBoxMinutes:          timeBoxMinutes,
+		QuorumPercentage:        quorumPercentage,
+		StoryTypeScaleMap:       thunderdome.NormalizeStoryTypeScaleMap(storyTypeScaleMap),
+		Facilitators:            make([]string, 0),
+		JoinCode:                joinCode,
+		FacilitatorCode:         facilitatorCode,
+		ObserverCode:            observerCode,
+		EstimationScaleID:       estimationScaleID,
 	}
 	b.Facilitators = append(b.Facilitators, facilitatorID)
 
@@ -75,12 +114,14 @@ func (d *Service) CreateGame(ctx context.Context, facilitatorID string, name str
 		`INSERT INTO thunderdome.poker (
 			name, voting_locked, point_values_allowed, auto_finish_voting,
 			point_average_rounding, hide_voter_identity, join_code, leader_code,
-			estimation_scale_id, created_date, updated_date
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW(), NOW())
+			estimation_scale_id, auto_finalize_on_consensus, observer_code, min_participants, time_box_minutes, story_type_scale_map,
+			quorum_percentage, created_date, updated_date
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, NOW(), NOW())
 		RETURNING id`,
 		name, true, pointValuesAllowed, autoFinishVoting,
 		pointAverageRounding, hideVoterIdentity, encryptedJoinCode, encryptedLeaderCode,
-		estimationScaleID,
+		estimationScaleID, autoFinalizeOnConsensus, encryptedObserverCode, minParticipants, timeBoxMinutes, scaleMapJSON,
+		quorumPercentage,
 	).Scan(&b.ID)
 	if err != nil {
 		tx.Rollback()
@@ -89,7 +130,7 @@ func (d *Service) CreateGame(ctx context.Context, facilitatorID string, name str
 
 	// Insert facilitator
 	_, err = tx.Exec(
-		`INSERT INTO thunderdome.poker_facilitator (poker_id, user_id) VALUES ($1, $2);`,
+		`INSERT INTO thunderdome.poker_facilitator (poker_id, user_id, is_primary) VALUES ($1, $2, true);`,
 		b.ID, facilitatorID,
 	)
 	if err != nil {
@@ -97,23 +138,36 @@ func (d *Service) CreateGame(ctx context.Context, facilitatorID string, name str
 		return nil, fmt.Errorf("create poker facilitator error: %v", err)
 	}
 
+	if err = appendGameEvent(ctx, tx, b.ID, thunderdome.GameEventCreated, gameSettingsEvent(b)); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
 	// Insert stories
 	for i, story := range stories {
 		// 使用循环索引作为位置值，确保唯一性
 		position := i
-		_, err = tx.Exec(
+		var storyID string
+		err = tx.QueryRow(
 			`INSERT INTO thunderdome.poker_story (
 				poker_id, name, type, reference_id, link, description,
 				acceptance_criteria, priority, position
-			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);`,
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			RETURNING id;`,
 			b.ID, story.Name, story.Type, story.ReferenceID, story.Link,
 			story.Description, story.AcceptanceCriteria, story.Priority,
 			position,
-		)
+		).Scan(&storyID)
 		if err != nil {
 			tx.Rollback()
 			return nil, fmt.Errorf("create poker story error: %v", err)
 		}
+		if err = appendGameEvent(ctx, tx, b.ID, thunderdome.GameEventStoryAdded, thunderdome.GameStoryEvent{
+			StoryID: storyID, Name: story.Name,
+		}); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
 	}
 
 	err = tx.Commit()
@@ -131,7 +185,7 @@ func (d *Service) CreateGame(ctx context.Context, facilitatorID string, name str
 	}
 
 	// 设置缓存
-	if d.Redis != nil {
+	if d.Redis != nil && d.CacheTTL.GameTTL > 0 {
 		d.Logger.Info("Attempting to set game cache", zap.String("game_id", b.ID))
 		if gameJSON, err := json.Marshal(completeGame); err == nil {
 			cacheKey := fmt.Sprintf("game:%s", b.ID)
@@ -140,7 +194,7 @@ func (d *Service) CreateGame(ctx context.Context, facilitatorID string, name str
 				zap.String("cache_key", cacheKey),
 				zap.Int("data_size", len(gameJSON)))
 
-			if err := d.Redis.Set(context.Background(), cacheKey, gameJSON, 24*time.Hour).Err(); err != nil {
+			if err := d.Redis.Set(context.Background(), cacheKey, gameJSON, d.CacheTTL.GameTTL).Err(); err != nil {
 				d.Logger.Error("Failed to set game cache",
 					zap.Error(err),
 					zap.String("game_id", b.ID),
@@ -171,9 +225,14 @@ func (d *Service) CreateGame(ctx context.Context, facilitatorID string, name str
 }
 
 // TeamCreateGame creates a new story pointing session associated to a team
-func (d *Service) TeamCreateGame(ctx context.Context, teamID string, facilitatorID string, name string, estimationScaleID string, pointValuesAllowed []string, stories []*thunderdome.Story, autoFinishVoting bool, pointAverageRounding string, joinCode string, facilitatorCode string, hideVoterIdentity bool) (*thunderdome.Poker, error) {
+func (d *Service) TeamCreateGame(ctx context.Context, teamID string, facilitatorID string, name string, estimationScaleID string, pointValuesAllowed []string, stories []*thunderdome.Story, autoFinishVoting bool, pointAverageRounding string, joinCode string, facilitatorCode string, observerCode string, hideVoterIdentity bool, autoFinalizeOnConsensus bool, minParticipants int, timeBoxMinutes int, quorumPercentage float64, storyTypeScaleMap map[string]string) (*thunderdome.Poker, error) {
+	if err := validateStoryLinks(stories); err != nil {
+		return nil, err
+	}
+
 	var encryptedJoinCode string
 	var encryptedLeaderCode string
+	var encryptedObserverCode string
 
 	if joinCode != "" {
 		EncryptedCode, codeErr := db.Encrypt(joinCode, d.AESHashKey)
@@ -191,20 +250,39 @@ func (d *Service) TeamCreateGame(ctx context.Context, teamID string, facilitator
 		encryptedLeaderCode = EncryptedCode
 	}
 
+	if observerCode != "" {
+		EncryptedCode, codeErr := db.Encrypt(observerCode, d.AESHashKey)
+		if codeErr != nil {
+			return nil, fmt.Errorf("team create poker encrypt observer_code error: %v", codeErr)
+		}
+		encryptedObserverCode = EncryptedCode
+	}
+
+	scaleMapJSON, err := storyTypeScaleMapJSON(storyTypeScaleMap)
+	if err != nil {
+		return nil, err
+	}
+
 	var b = &thunderdome.Poker{
-		Name:                 name,
-		Users:                make([]*thunderdome.PokerUser, 0),
-		Stories:              make([]*thunderdome.Story, 0),
-		VotingLocked:         true,
-		PointValuesAllowed:   pointValuesAllowed,
-		AutoFinishVoting:     autoFinishVoting,
-		PointAverageRounding: pointAverageRounding,
-		HideVoterIdentity:    hideVoterIdentity,
-		Facilitators:         make([]string, 0),
-		JoinCode:             joinCode,
-		FacilitatorCode:      facilitatorCode,
-		EstimationScaleID:    estimationScaleID,
-		TeamID:               teamID,
+		Name:                    name,
+		Users:                   make([]*thunderdome.PokerUser, 0),
+		Stories:                 make([]*thunderdome.Story, 0),
+		VotingLocked:            true,
+		PointValuesAllowed:      pointValuesAllowed,
+		AutoFinishVoting:        autoFinishVoting,
+		PointAverageRounding:    pointAverageRounding,
+		HideVoterIdentity:       hideVoterIdentity,
+		AutoFinalizeOnConsensus: autoFinalizeOnConsensus,
+		MinParticipants:         minParticipants,
+		TimeBoxMinutes:          timeBoxMinutes,
+		QuorumPercentage:        quorumPercentage,
+		StoryTypeScaleMap:       thunderdome.NormalizeStoryTypeScaleMap(storyTypeScaleMap),
+		Facilitators:            make([]string, 0),
+		JoinCode:                joinCode,
+		FacilitatorCode:         facilitatorCode,
+		ObserverCode:            observerCode,
+		EstimationScaleID:       estimationScaleID,
+		TeamID:                  teamID,
 	}
 	b.Facilitators = append(b.Facilitators, facilitatorID)
 
@@ -218,12 +296,14 @@ func (d *Service) TeamCreateGame(ctx context.Context, teamID string, facilitator
 		`INSERT INTO thunderdome.poker (
 			name, voting_locked, point_values_allowed, auto_finish_voting,
 			point_average_rounding, hide_voter_identity, join_code, leader_code,
-			estimation_scale_id, team_id, created_date, updated_date
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NOW(), NOW())
+			estimation_scale_id, team_id, auto_finalize_on_consensus, observer_code, min_participants, time_box_minutes,
+			story_type_scale_map, quorum_percentage, created_date, updated_date
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, NOW(), NOW())
 		RETURNING id`,
 		name, true, pointValuesAllowed, autoFinishVoting,
 		pointAverageRounding, hideVoterIdentity, encryptedJoinCode, encryptedLeaderCode,
-		estimationScaleID, teamID,
+		estimationScaleID, teamID, autoFinalizeOnConsensus, encryptedObserverCode, minParticipants, timeBoxMinutes,
+		scaleMapJSON, quorumPercentage,
 	).Scan(&b.ID)
 	if err != nil {
 		tx.Rollback()
@@ -232,7 +312,7 @@ func (d *Service) TeamCreateGame(ctx context.Context, teamID string, facilitator
 
 	// Insert facilitator
 	_, err = tx.Exec(
-		`INSERT INTO thunderdome.poker_facilitator (poker_id, user_id) VALUES ($1, $2);`,
+		`INSERT INTO thunderdome.poker_facilitator (poker_id, user_id, is_primary) VALUES ($1, $2, true);`,
 		b.ID, facilitatorID,
 	)
 	if err != nil {
@@ -240,23 +320,36 @@ func (d *Service) TeamCreateGame(ctx context.Context, teamID string, facilitator
 		return nil, fmt.Errorf("create poker facilitator error: %v", err)
 	}
 
+	if err = appendGameEvent(ctx, tx, b.ID, thunderdome.GameEventCreated, gameSettingsEvent(b)); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
 	// Insert stories
 	for i, story := range stories {
 		// 使用循环索引作为位置值，确保唯一性
 		position := i
-		_, err = tx.Exec(
+		var storyID string
+		err = tx.QueryRow(
 			`INSERT INTO thunderdome.poker_story (
 				poker_id, name, type, reference_id, link, description,
 				acceptance_criteria, priority, position
-			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);`,
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			RETURNING id;`,
 			b.ID, story.Name, story.Type, story.ReferenceID, story.Link,
 			story.Description, story.AcceptanceCriteria, story.Priority,
 			position,
-		)
+		).Scan(&storyID)
 		if err != nil {
 			tx.Rollback()
 			return nil, fmt.Errorf("create poker story error: %v", err)
 		}
+		if err = appendGameEvent(ctx, tx, b.ID, thunderdome.GameEventStoryAdded, thunderdome.GameStoryEvent{
+			StoryID: storyID, Name: story.Name,
+		}); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
 	}
 
 	err = tx.Commit()
@@ -274,10 +367,10 @@ func (d *Service) TeamCreateGame(ctx context.Context, teamID string, facilitator
 	}
 
 	// 设置缓存
-	if d.Redis != nil {
+	if d.Redis != nil && d.CacheTTL.GameTTL > 0 {
 		if gameJSON, err := json.Marshal(completeGame); err == nil {
 			cacheKey := fmt.Sprintf("game:%s", b.ID)
-			if err := d.Redis.Set(context.Background(), cacheKey, gameJSON, 24*time.Hour).Err(); err != nil {
+			if err := d.Redis.Set(context.Background(), cacheKey, gameJSON, d.CacheTTL.GameTTL).Err(); err != nil {
 				d.Logger.Error("Failed to set game cache", zap.Error(err), zap.String("game_id", b.ID))
 			} else {
 				d.Logger.Info("Game cache set successfully", zap.String("game_id", b.ID))
@@ -289,9 +382,10 @@ func (d *Service) TeamCreateGame(ctx context.Context, teamID string, facilitator
 }
 
 // UpdateGame updates a game by ID
-func (d *Service) UpdateGame(pokerID string, name string, pointValuesAllowed []string, autoFinishVoting bool, pointAverageRounding string, hideVoterIdentity bool, joinCode string, facilitatorCode string, teamID string) error {
+func (d *Service) UpdateGame(pokerID string, name string, pointValuesAllowed []string, autoFinishVoting bool, pointAverageRounding string, hideVoterIdentity bool, autoFinalizeOnConsensus bool, minParticipants int, timeBoxMinutes int, quorumPercentage float64, storyTypeScaleMap map[string]string, joinCode string, facilitatorCode string, observerCode string, teamID string) error {
 	var encryptedJoinCode string
 	var encryptedLeaderCode string
+	var encryptedObserverCode string
 
 	if joinCode != "" {
 		EncryptedCode, codeErr := db.Encrypt(joinCode, d.AESHashKey)
@@ -309,21 +403,49 @@ func (d *Service) UpdateGame(pokerID string, name string, pointValuesAllowed []s
 		encryptedLeaderCode = EncryptedCode
 	}
 
-	if _, err := d.DB.Exec(`
+	if observerCode != "" {
+		EncryptedCode, codeErr := db.Encrypt(observerCode, d.AESHashKey)
+		if codeErr != nil {
+			return fmt.Errorf("update poker encrypt observer_code error: %v", codeErr)
+		}
+		encryptedObserverCode = EncryptedCode
+	}
+
+	scaleMapJSON, err := storyTypeScaleMapJSON(storyTypeScaleMap)
+	if err != nil {
+		return err
+	}
+
+	if err := d.withGameEvent(context.Background(), pokerID, thunderdome.GameEventUpdated, func(tx *sql.Tx) (any, error) {
+		if _, err := tx.Exec(`
 		UPDATE thunderdome.poker
 		SET name = $2, point_values_allowed = $3, auto_finish_voting = $4, point_average_rounding = $5,
-		 hide_voter_identity = $6, join_code = $7, leader_code = $8, updated_date = NOW(), team_id = NULLIF($9, '')::uuid
+		 hide_voter_identity = $6, join_code = $7, leader_code = $8, updated_date = NOW(), team_id = NULLIF($9, '')::uuid,
+		 auto_finalize_on_consensus = $10, observer_code = $11, min_participants = $12, time_box_minutes = $13,
+		 story_type_scale_map = $14, quorum_percentage = $15
 		WHERE id = $1`,
-		pokerID, name, pointValuesAllowed, autoFinishVoting, pointAverageRounding,
-		hideVoterIdentity, encryptedJoinCode, encryptedLeaderCode, teamID,
-	); err != nil {
-		return fmt.Errorf("update poker query error: %v", err)
+			pokerID, name, pointValuesAllowed, autoFinishVoting, pointAverageRounding,
+			hideVoterIdentity, encryptedJoinCode, encryptedLeaderCode, teamID, autoFinalizeOnConsensus,
+			encryptedObserverCode, minParticipants, timeBoxMinutes, scaleMapJSON, quorumPercentage,
+		); err != nil {
+			return nil, fmt.Errorf("update poker query error: %v", err)
+		}
+
+		return thunderdome.GameSettingsEvent{
+			Name: name, PointValuesAllowed: pointValuesAllowed, AutoFinishVoting: autoFinishVoting,
+			PointAverageRounding: pointAverageRounding, HideVoterIdentity: hideVoterIdentity,
+			AutoFinalizeOnConsensus: autoFinalizeOnConsensus, MinParticipants: minParticipants,
+			TimeBoxMinutes: timeBoxMinutes, QuorumPercentage: quorumPercentage,
+			StoryTypeScaleMap: thunderdome.NormalizeStoryTypeScaleMap(storyTypeScaleMap),
+		}, nil
+	}); err != nil {
+		return err
 	}
 
 	// 清除缓存
 	if d.Redis != nil {
 		cacheKey := fmt.Sprintf("game:%s", pokerID)
-		d.Redis.Del(context.Background(), cacheKey)
+		d.Redis.Del(context.Background(), cacheKey, statisticsCacheKey(pokerID), joinDeepLinkCacheKey(pokerID))
 	}
 
 	return nil
@@ -333,13 +455,21 @@ func (d *Service) UpdateGame(pokerID string, name string, pointValuesAllowed []s
 func (d *Service) GetGameByID(pokerID string, userID string) (*thunderdome.Poker, error) {
 	// 尝试从Redis缓存获取
 	cacheKey := fmt.Sprintf("game:%s", pokerID)
-	if d.Redis != nil {
+	if d.Redis != nil && d.CacheTTL.GameTTL > 0 {
 		if cachedData, err := d.Redis.Get(context.Background(), cacheKey).Result(); err == nil {
 			var game thunderdome.Poker
 			if err := json.Unmarshal([]byte(cachedData), &game); err == nil {
 				d.Logger.Debug("Game cache hit", zap.String("game_id", pokerID))
 				// 确保缓存中的游戏数据包含所有必要的信息
 				if len(game.Stories) > 0 && len(game.Users) > 0 {
+					// observer code is never cached, only facilitators may see it
+					if db.Contains(game.Facilitators, userID) {
+						if observerCode, err := d.GetObserverCode(pokerID); err == nil {
+							game.ObserverCode = observerCode
+						}
+					}
+					d.attachFacilitatorNotes(pokerID, userID, game.Stories)
+					game.Completion = computeCompletionStats(game.Stories)
 					return &game, nil
 				} else {
 					d.Logger.Warn("Incomplete game data in cache, fetching from database",
@@ -363,7 +493,9 @@ func (d *Service) GetGameByID(pokerID string, userID string) (*thunderdome.Poker
 	var facilitators string
 	var joinCode string
 	var facilitatorCode string
+	var observerCode string
 	var estimationScaleJSON []byte
+	var scaleMapJSON []byte
 	var vArray pgtype.Array[string]
 	m := pgtype.NewMap()
 	e := d.DB.QueryRow(
@@ -371,6 +503,8 @@ func (d *Service) GetGameByID(pokerID string, userID string) (*thunderdome.Poker
 		SELECT b.id, b.name, b.voting_locked, COALESCE(b.active_story_id::text, ''), b.auto_finish_voting,
 		b.point_average_rounding, b.hide_voter_identity, COALESCE(b.join_code, ''), COALESCE(b.leader_code, ''),
 		b.estimation_scale_id, b.point_values_allowed, COALESCE(b.team_id::text, ''), b.created_date, b.updated_date,
+		b.auto_finalize_on_consensus, COALESCE(b.observer_code, ''), b.min_participants, b.time_box_minutes,
+		b.story_type_scale_map, b.archived_at, b.quorum_percentage,
 		CASE WHEN COUNT(bl) = 0 THEN '[]'::json ELSE array_to_json(array_agg(bl.user_id)) END AS leaders,
 		COALESCE(
 			json_build_object(
@@ -410,6 +544,13 @@ func (d *Service) GetGameByID(pokerID string, userID string) (*thunderdome.Poker
 		&b.TeamID,
 		&b.CreatedDate,
 		&b.UpdatedDate,
+		&b.AutoFinalizeOnConsensus,
+		&observerCode,
+		&b.MinParticipants,
+		&b.TimeBoxMinutes,
+		&scaleMapJSON,
+		&b.ArchivedAt,
+		&b.QuorumPercentage,
 		&facilitators,
 		&estimationScaleJSON,
 	)
@@ -420,6 +561,7 @@ func (d *Service) GetGameByID(pokerID string, userID string) (*thunderdome.Poker
 	b.PointValuesAllowed = vArray.Elements
 
 	_ = json.Unmarshal([]byte(facilitators), &b.Facilitators)
+	_ = json.Unmarshal(scaleMapJSON, &b.StoryTypeScaleMap)
 
 	// Unmarshal the estimation scale JSON into the EstimationScale field
 	if len(estimationScaleJSON) > 0 {
@@ -448,28 +590,50 @@ func (d *Service) GetGameByID(pokerID string, userID string) (*thunderdome.Poker
 		b.FacilitatorCode = decryptedCode
 	}
 
+	if observerCode != "" && isFacilitator {
+		decryptedCode, codeErr := db.Decrypt(observerCode, d.AESHashKey)
+		if codeErr != nil {
+			return nil, fmt.Errorf("get poker decode observer_code error: %v", codeErr)
+		}
+		b.ObserverCode = decryptedCode
+	}
+
 	b.Users = d.GetUsers(pokerID)
 	b.Stories = d.GetStories(pokerID, userID)
+	b.Completion = computeCompletionStats(b.Stories)
 
 	// 设置缓存
-	if d.Redis != nil {
-		if gameJSON, err := json.Marshal(b); err == nil {
-			d.Redis.Set(context.Background(), cacheKey, gameJSON, 24*time.Hour)
+	if d.Redis != nil && d.CacheTTL.GameTTL > 0 {
+		cachedGame := *b
+		cachedGame.ObserverCode = ""
+		cachedGame.Stories = make([]*thunderdome.Story, 0, len(b.Stories))
+		for _, story := range b.Stories {
+			cachedStory := *story
+			cachedStory.FacilitatorNotes = nil
+			cachedGame.Stories = append(cachedGame.Stories, &cachedStory)
+		}
+		if gameJSON, err := json.Marshal(cachedGame); err == nil {
+			d.Redis.Set(context.Background(), cacheKey, gameJSON, d.CacheTTL.GameTTL)
 		}
 	}
 
 	return b, nil
 }
 
-// GetGamesByUser gets a list of games by UserID
+// GetGamesByUser gets a list of games by UserID, archived games are excluded
 func (d *Service) GetGamesByUser(userID string, limit int, offset int) ([]*thunderdome.Poker, int, error) {
+	return d.getGamesByUser(userID, limit, offset, false)
+}
+
+// getGamesByUser gets a list of either the archived or unarchived games by UserID
+func (d *Service) getGamesByUser(userID string, limit int, offset int, archived bool) ([]*thunderdome.Poker, int, error) {
 	var count int
 	var games = make([]*thunderdome.Poker, 0)
 
 	// 注意：这里不使用Redis缓存，因为用户的游戏列表需要实时从数据库获取
 	// 特别是在测试环境中，这确保了测试能够正确验证API功能
 
-	e := d.DB.QueryRow(`
+	e := d.reader().QueryRow(`
 		WITH user_teams AS (
 			SELECT t.id FROM thunderdome.team_user tu
 			LEFT JOIN thunderdome.team t ON t.id = tu.team_id
@@ -491,15 +655,16 @@ func (d *Service) GetGamesByUser(userID string, limit int, offset int) ([]*thund
 			UNION SELECT id FROM team_games
 			UNION SELECT id FROM facilitator_games
 		)
-		SELECT COUNT(*) FROM games;
-	`, userID).Scan(
+		SELECT COUNT(*) FROM thunderdome.poker p
+		WHERE p.id IN (SELECT id FROM games) AND (p.archived_at IS NOT NULL) = $2;
+	`, userID, archived).Scan(
 		&count,
 	)
 	if e != nil {
 		return nil, count, fmt.Errorf("get poker by user count query error: %v", e)
 	}
 
-	gameRows, gamesErr := d.DB.Query(`
+	gameRows, gamesErr := d.reader().Query(`
 		WITH user_teams AS (
 			SELECT t.id, t.name FROM thunderdome.team_user tu
 			LEFT JOIN thunderdome.team t ON t.id = tu.team_id
@@ -554,11 +719,11 @@ func (d *Service) GetGamesByUser(userID string, limit int, offset int) ([]*thund
 		FROM thunderdome.poker p
 		LEFT JOIN user_teams t ON t.id = p.team_id
 		LEFT JOIN thunderdome.estimation_scale es ON p.estimation_scale_id = es.id
-		WHERE p.id IN (SELECT id FROM games)
+		WHERE p.id IN (SELECT id FROM games) AND (p.archived_at IS NOT NULL) = $4
 		GROUP BY p.id, p.created_date, es.id
 		ORDER BY p.created_date DESC
 		LIMIT $2 OFFSET $3
-	`, userID, limit, offset)
+	`, userID, limit, offset, archived)
 	if gamesErr != nil {
 		d.Logger.Error("get poker by user query error", zap.Error(gamesErr))
 		return nil, count, fmt.Errorf("get poker by user query error: %v", gamesErr)
@@ -626,13 +791,19 @@ func (d *Service) DeleteGame(pokerID string) error {
 	return nil
 }
 
-// GetGames gets a list of games
+// GetGames gets a list of games, archived games are excluded
 func (d *Service) GetGames(limit int, offset int) ([]*thunderdome.Poker, int, error) {
+	return d.getGames(limit, offset, false)
+}
+
+// getGames gets a list of either the archived or unarchived games
+func (d *Service) getGames(limit int, offset int, archived bool) ([]*thunderdome.Poker, int, error) {
 	var games = make([]*thunderdome.Poker, 0)
 	var count int
 
-	e := d.DB.QueryRow(
-		"SELECT COUNT(*) FROM thunderdome.poker;",
+	e := d.reader().QueryRow(
+		"SELECT COUNT(*) FROM thunderdome.poker WHERE (archived_at IS NOT NULL) = $1;",
+		archived,
 	).Scan(
 		&count,
 	)
@@ -640,15 +811,17 @@ func (d *Service) GetGames(limit int, offset int) ([]*thunderdome.Poker, int, er
 		return nil, count, fmt.Errorf("get poker games count query error: %v", e)
 	}
 
-	rows, gamesErr := d.DB.Query(`
+	rows, gamesErr := d.reader().Query(`
 		SELECT b.id, b.name, b.voting_locked, b.active_story_id, b.point_values_allowed,
 		 b.auto_finish_voting, b.point_average_rounding, b.created_date, b.updated_date, COALESCE(b.team_id::TEXT, ''),
+		 b.archived_at,
 		CASE WHEN COUNT(bl) = 0 THEN '[]'::json ELSE array_to_json(array_agg(bl.user_id)) END AS leaders
 		FROM thunderdome.poker b
 		LEFT JOIN thunderdome.poker_facilitator bl ON b.id = bl.poker_id
+		WHERE (b.archived_at IS NOT NULL) = $3
 		GROUP BY b.id, b.created_date ORDER BY b.created_date DESC
 		LIMIT $1 OFFSET $2;
-	`, limit, offset)
+	`, limit, offset, archived)
 	if gamesErr != nil {
 		return nil, count, fmt.Errorf("get poker games query error: %v", gamesErr)
 	}
@@ -678,6 +851,7 @@ func (d *Service) GetGames(limit int, offset int) ([]*thunderdome.Poker, int, er
 			&b.CreatedDate,
 			&b.UpdatedDate,
 			&b.TeamID,
+			&b.ArchivedAt,
 			&facilitators,
 		); err != nil {
 			d.Logger.Error("get poker games query error", zap.Error(err))
@@ -697,8 +871,10 @@ func (d *Service) GetActiveGames(limit int, offset int) ([]*thunderdome.Poker, i
 	var games = make([]*thunderdome.Poker, 0)
 	var count int
 
-	e := d.DB.QueryRow(
-		"SELECT COUNT(DISTINCT pu.poker_id) FROM thunderdome.poker_user pu WHERE pu.active IS TRUE;",
+	e := d.reader().QueryRow(
+		`SELECT COUNT(DISTINCT pu.poker_id) FROM thunderdome.poker_user pu
+		JOIN thunderdome.poker p ON p.id = pu.poker_id
+		WHERE pu.active IS TRUE AND p.archived_at IS NULL;`,
 	).Scan(
 		&count,
 	)
@@ -706,14 +882,14 @@ func (d *Service) GetActiveGames(limit int, offset int) ([]*thunderdome.Poker, i
 		return nil, count, fmt.Errorf("get active poker games count query error: %v", e)
 	}
 
-	rows, gamesErr := d.DB.Query(`
+	rows, gamesErr := d.reader().Query(`
 		SELECT b.id, b.name, b.voting_locked, b.active_story_id, b.point_values_allowed, b.auto_finish_voting,
 		 b.point_average_rounding, b.created_date, b.updated_date, COALESCE(b.team_id::TEXT, ''),
 		CASE WHEN COUNT(bl) = 0 THEN '[]'::json ELSE array_to_json(array_agg(bl.user_id)) END AS leaders
 		FROM thunderdome.poker_user bu
 		LEFT JOIN thunderdome.poker b ON b.id = bu.poker_id
 		LEFT JOIN thunderdome.poker_facilitator bl ON b.id = bl.poker_id
-		WHERE bu.active IS TRUE GROUP BY b.id
+		WHERE bu.active IS TRUE AND b.archived_at IS NULL GROUP BY b.id
 		LIMIT $1 OFFSET $2;
 	`, limit, offset)
 	if gamesErr != nil {
@@ -758,3 +934,13 @@ func (d *Service) GetActiveGames(limit int, offset int) ([]*thunderdome.Poker, i
 
 	return games, count, nil
 }
+
+// storyTypeScaleMapJSON encodes the story type estimation scale overrides for the jsonb column
+func storyTypeScaleMapJSON(storyTypeScaleMap map[string]string) ([]byte, error) {
+	scaleMapJSON, err := json.Marshal(thunderdome.NormalizeStoryTypeScaleMap(storyTypeScaleMap))
+	if err != nil {
+		return nil, fmt.Errorf("poker story type scale map encode error: %v", err)
+	}
+
+	return scaleMapJSON, nil
+}
@@ -0,0 +1,61 @@
+package poker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// storySearchVectors are the full-text vectors searched for each allowed search field,
+// only these are ever added to the search query
+var storySearchVectors = map[thunderdome.StorySearchField]string{
+	thunderdome.StorySearchFieldName:               `to_tsvector('simple', COALESCE(name, ''))`,
+	thunderdome.StorySearchFieldDescription:        `to_tsvector('simple', COALESCE(description, ''))`,
+	thunderdome.StorySearchFieldAcceptanceCriteria: `to_tsvector('simple', COALESCE(acceptance_criteria, ''))`,
+	thunderdome.StorySearchFieldAll:                `search_vector`,
+}
+
+// SearchStories gets the game stories whose search field matches the full-text query, in game order
+func (d *Service) SearchStories(ctx context.Context, pokerID string, userID string, query string, field thunderdome.StorySearchField) ([]*thunderdome.Story, error) {
+	vector, ok := storySearchVectors[field]
+	if !ok {
+		return nil, thunderdome.ErrInvalidStorySearchField
+	}
+
+	rows, err := d.reader().QueryContext(ctx,
+		`SELECT id FROM thunderdome.poker_story
+		WHERE poker_id = $1 AND `+vector+` @@ websearch_to_tsquery('simple', $2);`,
+		pokerID, query,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("search poker stories query error: %v", err)
+	}
+	defer rows.Close()
+
+	matches := make(map[string]bool)
+	for rows.Next() {
+		var storyID string
+		if err := rows.Scan(&storyID); err != nil {
+			return nil, fmt.Errorf("search poker stories scan error: %v", err)
+		}
+		matches[storyID] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("search poker stories rows error: %v", err)
+	}
+
+	return filterStoriesByID(d.GetStories(pokerID, userID), matches), nil
+}
+
+// filterStoriesByID keeps the stories with a matching ID, keeping their game order
+func filterStoriesByID(stories []*thunderdome.Story, storyIDs map[string]bool) []*thunderdome.Story {
+	filtered := make([]*thunderdome.Story, 0, len(storyIDs))
+	for _, story := range stories {
+		if storyIDs[story.ID] {
+			filtered = append(filtered, story)
+		}
+	}
+
+	return filtered
+}
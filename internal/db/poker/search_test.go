@@ -0,0 +1,39 @@
+package poker
+
+import (
+	"testing"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// TestStorySearchVectors makes sure every allowed search field has a search vector
+func TestStorySearchVectors(t *testing.T) {
+	for _, field := range []thunderdome.StorySearchField{
+		thunderdome.StorySearchFieldName,
+		thunderdome.StorySearchFieldDescription,
+		thunderdome.StorySearchFieldAcceptanceCriteria,
+		thunderdome.StorySearchFieldAll,
+	} {
+		if storySearchVectors[field] == "" {
+			t.Errorf("expected a search vector for field %s", field)
+		}
+	}
+}
+
+// TestFilterStoriesByID makes sure only the matched stories are kept in game order
+func TestFilterStoriesByID(t *testing.T) {
+	stories := []*thunderdome.Story{
+		{ID: "1", Name: "Login page", AcceptanceCriteria: "shows the logo"},
+		{ID: "2", Name: "Export results", AcceptanceCriteria: "requires login"},
+		{ID: "3", Name: "Profile", AcceptanceCriteria: "login required to edit"},
+	}
+
+	filtered := filterStoriesByID(stories, map[string]bool{"3": true, "2": true})
+	if len(filtered) != 2 || filtered[0].ID != "2" || filtered[1].ID != "3" {
+		t.Fatalf("expected stories 2 and 3 in game order, got %v", filtered)
+	}
+
+	if filtered := filterStoriesByID(stories, map[string]bool{}); len(filtered) != 0 {
+		t.Errorf("expected no stories without matches, got %v", filtered)
+	}
+}
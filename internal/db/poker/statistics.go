@@ -0,0 +1,182 @@
+package poker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+// statisticsCacheTTL is the longest the game statistics are cached
+const statisticsCacheTTL = 10 * time.Minute
+
+// recordVoteRound adds the story's just ended round of voting to its voting round history
+func (d *Service) recordVoteRound(pokerID string, storyID string) error {
+	if _, err := d.DB.Exec(
+		`INSERT INTO thunderdome.poker_story_vote_round
+		(poker_id, story_id, round, votes, votestart_time, voteend_time)
+		SELECT ps.poker_id, ps.id,
+			COALESCE((SELECT MAX(vr.round) FROM thunderdome.poker_story_vote_round vr WHERE vr.story_id = ps.id), 0) + 1,
+			COALESCE(ps.votes, '[]'::jsonb), ps.votestart_time, ps.voteend_time
+		FROM thunderdome.poker_story ps
+		WHERE ps.poker_id = $1 AND ps.id = $2;`,
+		pokerID, storyID,
+	); err != nil {
+		return fmt.Errorf("insert poker story vote round query error: %v", err)
+	}
+
+	return nil
+}
+
+// GetVoteRounds gets the poker game's voting round history ordered by story and round
+func (d *Service) GetVoteRounds(ctx context.Context, pokerID string) ([]*thunderdome.StoryVoteRound, error) {
+	rounds := make([]*thunderdome.StoryVoteRound, 0)
+
+	rows, err := d.reader().QueryContext(ctx,
+		`SELECT story_id, round, votes, votestart_time, voteend_time
+		FROM thunderdome.poker_story_vote_round
+		WHERE poker_id = $1
+		ORDER BY story_id, round;`,
+		pokerID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get poker vote rounds query error: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var votes string
+		r := &thunderdome.StoryVoteRound{Votes: make([]*thunderdome.Vote, 0)}
+		if err := rows.Scan(&r.StoryID, &r.Round, &votes, &r.VoteStartTime, &r.VoteEndTime); err != nil {
+			d.Logger.Ctx(ctx).Error("get poker vote rounds query scan error", zap.Error(err))
+			continue
+		}
+		if err := json.Unmarshal([]byte(votes), &r.Votes); err != nil {
+			d.Logger.Ctx(ctx).Error("poker vote round votes json error", zap.Error(err))
+		}
+		rounds = append(rounds, r)
+	}
+
+	return rounds, nil
+}
+
+// GetGameStatistics gets the poker game's voting statistics computed from its voting round history
+func (d *Service) GetGameStatistics(ctx context.Context, pokerID string) (*thunderdome.GameStatistics, error) {
+	cacheKey := statisticsCacheKey(pokerID)
+	ttl := thunderdome.CappedTTL(d.CacheTTL.GameTTL, statisticsCacheTTL)
+	if d.Redis != nil && ttl > 0 {
+		if cachedData, err := d.Redis.Get(ctx, cacheKey).Result(); err == nil {
+			var statistics thunderdome.GameStatistics
+			if err := json.Unmarshal([]byte(cachedData), &statistics); err == nil {
+				d.Logger.Ctx(ctx).Debug("Game statistics cache hit", zap.String("game_id", pokerID))
+				return &statistics, nil
+			}
+		}
+	}
+
+	var exists bool
+	err := d.DB.QueryRowContext(ctx,
+		`SELECT EXISTS(SELECT 1 FROM thunderdome.poker WHERE id = $1);`,
+		pokerID,
+	).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("get poker statistics query error: %v", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("get poker statistics error: POKER_NOT_FOUND")
+	}
+
+	rounds, err := d.GetVoteRounds(ctx, pokerID)
+	if err != nil {
+		return nil, err
+	}
+
+	statistics := computeGameStatistics(d.GetStories(pokerID, ""), rounds)
+
+	if d.Redis != nil && ttl > 0 {
+		if statisticsJSON, err := json.Marshal(statistics); err == nil {
+			d.Redis.Set(ctx, cacheKey, statisticsJSON, ttl)
+		}
+	}
+
+	return statistics, nil
+}
+
+func statisticsCacheKey(pokerID string) string {
+	return fmt.Sprintf("game:statistics:%s", pokerID)
+}
+
+// computeGameStatistics summarizes the voting round history of the game stories,
+// the averages and consensus rate only include stories that have had at least one round of voting
+func computeGameStatistics(stories []*thunderdome.Story, rounds []*thunderdome.StoryVoteRound) *thunderdome.GameStatistics {
+	statistics := &thunderdome.GameStatistics{
+		TotalStories: len(stories),
+	}
+
+	storyRounds := make(map[string][]*thunderdome.StoryVoteRound)
+	for _, round := range rounds {
+		storyRounds[round.StoryID] = append(storyRounds[round.StoryID], round)
+	}
+
+	votedStories := 0
+	totalRounds := 0
+	consensusStories := 0
+	var totalDuration time.Duration
+	highestDisagreement := 0.0
+
+	for _, story := range stories {
+		sRounds := storyRounds[story.ID]
+		if len(sRounds) == 0 {
+			continue
+		}
+		votedStories++
+		totalRounds += len(sRounds)
+
+		firstRound := sRounds[0]
+		for _, round := range sRounds {
+			if round.Round < firstRound.Round {
+				firstRound = round
+			}
+			if duration := round.VoteEndTime.Sub(round.VoteStartTime); duration > 0 {
+				totalDuration += duration
+			}
+			if spread := voteSpread(round.Votes); spread > highestDisagreement {
+				highestDisagreement = spread
+				statistics.HighestDisagreementStory = story
+			}
+		}
+		if consensus, _ := thunderdome.VoteConsensus(firstRound.Votes, nil); consensus {
+			consensusStories++
+		}
+	}
+
+	if votedStories > 0 {
+		statistics.AverageRoundsPerStory = float64(totalRounds) / float64(votedStories)
+		statistics.AverageVotingDuration = totalDuration / time.Duration(votedStories)
+		statistics.ConsensusRate = float64(consensusStories) / float64(votedStories)
+	}
+
+	return statistics
+}
+
+// voteSpread gets the difference between the highest and lowest numeric votes
+func voteSpread(votes []*thunderdome.Vote) float64 {
+	lowest, highest := math.Inf(1), math.Inf(-1)
+	for _, vote := range votes {
+		value, ok := thunderdome.ParsePointValue(vote.VoteValue)
+		if !ok {
+			continue
+		}
+		lowest = math.Min(lowest, value)
+		highest = math.Max(highest, value)
+	}
+	if highest < lowest {
+		return 0
+	}
+
+	return highest - lowest
+}
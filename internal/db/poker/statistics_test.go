@@ -0,0 +1,78 @@
+package poker
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+var statisticsStart = time.Date(2025, 3, 15, 10, 0, 0, 0, time.UTC)
+
+func voteRound(storyID string, round int, duration time.Duration, votes ...string) *thunderdome.StoryVoteRound {
+	r := &thunderdome.StoryVoteRound{
+		StoryID:       storyID,
+		Round:         round,
+		Votes:         make([]*thunderdome.Vote, 0, len(votes)),
+		VoteStartTime: statisticsStart,
+		VoteEndTime:   statisticsStart.Add(duration),
+	}
+	for i, vote := range votes {
+		r.Votes = append(r.Votes, &thunderdome.Vote{UserID: string(rune('a' + i)), VoteValue: vote})
+	}
+	return r
+}
+
+// TestComputeGameStatistics makes sure each statistic is computed from the seeded round history
+func TestComputeGameStatistics(t *testing.T) {
+	stories := []*thunderdome.Story{
+		{ID: "consensus"},
+		{ID: "revoted"},
+		{ID: "split"},
+		{ID: "unvoted"},
+	}
+	rounds := []*thunderdome.StoryVoteRound{
+		voteRound("consensus", 1, 2*time.Minute, "3", "3", "?"),
+		voteRound("revoted", 1, 3*time.Minute, "3", "8"),
+		voteRound("revoted", 2, time.Minute, "5", "5"),
+		voteRound("split", 1, 4*time.Minute, "1", "13", "☕️"),
+		voteRound("split", 2, 2*time.Minute, "8", "13"),
+		voteRound("split", 3, time.Minute, "13", "13"),
+	}
+
+	statistics := computeGameStatistics(stories, rounds)
+
+	if statistics.TotalStories != 4 {
+		t.Errorf("expected 4 total stories, got %d", statistics.TotalStories)
+	}
+	// (1 + 2 + 3) / 3
+	if statistics.AverageRoundsPerStory != 2 {
+		t.Errorf("expected 2 average rounds per story, got %v", statistics.AverageRoundsPerStory)
+	}
+	// (2 + 4 + 7) / 3 minutes
+	if expected := 13 * time.Minute / 3; statistics.AverageVotingDuration != expected {
+		t.Errorf("expected %v average voting duration, got %v", expected, statistics.AverageVotingDuration)
+	}
+	if statistics.HighestDisagreementStory == nil || statistics.HighestDisagreementStory.ID != "split" {
+		t.Errorf("expected split to be the highest disagreement story, got %+v", statistics.HighestDisagreementStory)
+	}
+	if math.Abs(statistics.ConsensusRate-1.0/3) > 1e-9 {
+		t.Errorf("expected 0.33 consensus rate, got %v", statistics.ConsensusRate)
+	}
+}
+
+// TestComputeGameStatisticsNoRounds makes sure a game without voting history has empty statistics
+func TestComputeGameStatisticsNoRounds(t *testing.T) {
+	statistics := computeGameStatistics([]*thunderdome.Story{{ID: "unvoted"}}, nil)
+
+	if statistics.TotalStories != 1 {
+		t.Errorf("expected 1 total story, got %d", statistics.TotalStories)
+	}
+	if statistics.AverageRoundsPerStory != 0 || statistics.AverageVotingDuration != 0 || statistics.ConsensusRate != 0 {
+		t.Errorf("expected zero averages, got %+v", statistics)
+	}
+	if statistics.HighestDisagreementStory != nil {
+		t.Errorf("expected no highest disagreement story, got %+v", statistics.HighestDisagreementStory)
+	}
+}
@@ -5,8 +5,10 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
-	"time"
+	"strings"
 
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/analysis"
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/validation"
 	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
 
 	"go.uber.org/zap"
@@ -16,11 +18,13 @@ import (
 func (d *Service) GetStories(pokerID string, userID string) []*thunderdome.Story {
 	// 尝试从Redis缓存获取
 	cacheKey := fmt.Sprintf("game:%s:stories", pokerID)
-	if d.Redis != nil {
+	if d.Redis != nil && d.CacheTTL.StoryTTL > 0 {
 		if cachedData, err := d.Redis.Get(context.Background(), cacheKey).Result(); err == nil {
 			var stories []*thunderdome.Story
 			if err := json.Unmarshal([]byte(cachedData), &stories); err == nil {
 				d.Logger.Debug("Stories cache hit", zap.String("game_id", pokerID))
+				analyzeStories(stories)
+				d.attachFacilitatorNotes(pokerID, userID, stories)
 				return stories
 			}
 		}
@@ -31,7 +35,8 @@ func (d *Service) GetStories(pokerID string, userID string) []*thunderdome.Story
 		`SELECT
 			id, name, type, reference_id, link, description, acceptance_criteria, priority,
 			points, active, skipped, votestart_time, voteend_time, votes,
-			row_number() OVER (ORDER BY position ASC) as position
+			row_number() OVER (ORDER BY position ASC) as position, COALESCE(estimate_hint, ''),
+			COALESCE(sprint_name, ''), sprint_start_date, sprint_end_date, status
 			FROM thunderdome.poker_story WHERE poker_id = $1 ORDER BY position
 		`,
 		pokerID,
@@ -65,6 +70,11 @@ func (d *Service) GetStories(pokerID string, userID string) []*thunderdome.Story
 				&p.VoteEndTime,
 				&v,
 				&p.Position,
+				&p.EstimateHint,
+				&p.SprintName,
+				&p.SprintStartDate,
+				&p.SprintEndDate,
+				&p.Status,
 			); err != nil {
 				d.Logger.Error("error getting poker stories", zap.Error(err))
 			} else {
@@ -79,36 +89,61 @@ func (d *Service) GetStories(pokerID string, userID string) []*thunderdome.Story
 	}
 
 	// 设置缓存
-	if d.Redis != nil {
+	if d.Redis != nil && d.CacheTTL.StoryTTL > 0 {
 		if storiesJSON, err := json.Marshal(stories); err == nil {
-			d.Redis.Set(context.Background(), cacheKey, storiesJSON, 1*time.Hour)
+			d.Redis.Set(context.Background(), cacheKey, storiesJSON, d.CacheTTL.StoryTTL)
 		}
 	}
 
+	analyzeStories(stories)
+	// facilitator notes are attached after caching so they never end up in the shared stories cache
+	d.attachFacilitatorNotes(pokerID, userID, stories)
+
 	return stories
 }
 
+// analyzeStories populates the estimation hint analysis for each story,
+// computed on retrieval so it never goes stale
+func analyzeStories(stories []*thunderdome.Story) {
+	for _, story := range stories {
+		a := analysis.AnalyzeStory(story)
+		story.Analysis = &a
+	}
+}
+
 // CreateStory adds a new story to the game
 func (d *Service) CreateStory(pokerID string, name string, storyType string, referenceID string, link string, description string, acceptanceCriteria string, priority int32) ([]*thunderdome.Story, error) {
+	if err := validation.ValidateURL(link); err != nil {
+		return nil, err
+	}
 	sanitizedDescription := d.HTMLSanitizerPolicy.Sanitize(description)
 	sanitizedAcceptanceCriteria := d.HTMLSanitizerPolicy.Sanitize(acceptanceCriteria)
 	// default priority should be 99 for sort order purposes
 	if priority == 0 {
 		priority = 99
 	}
-	if _, err := d.DB.Exec(
-		`INSERT INTO thunderdome.poker_story (
+	if err := d.withGameEvent(context.Background(), pokerID, thunderdome.GameEventStoryAdded, func(tx *sql.Tx) (any, error) {
+		var storyID string
+		if err := tx.QueryRow(
+			`INSERT INTO thunderdome.poker_story (
 		poker_id, name, type, reference_id, link, description, acceptance_criteria, priority, position)
     VALUES ($1, $2, $3, $4, $5, $6, $7, $8, (
       coalesce(
         (select max(position) from thunderdome.poker_story where poker_id = $1),
         -1
       ) + 1
-    ));`,
-		pokerID, name, storyType, referenceID, link, sanitizedDescription, sanitizedAcceptanceCriteria, priority,
-	); err != nil {
+    ))
+    RETURNING id;`,
+			pokerID, name, storyType, referenceID, link, sanitizedDescription, sanitizedAcceptanceCriteria, priority,
+		).Scan(&storyID); err != nil {
+			return nil, fmt.Errorf("create poker story query error: %v", err)
+		}
+
+		return thunderdome.GameStoryEvent{StoryID: storyID, Name: name}, nil
+	}); err != nil {
 		d.Logger.Error("error creating poker story", zap.Error(err),
 			zap.String("PokerID", pokerID), zap.String("Name", name))
+		return nil, err
 	}
 
 	// 清除缓存
@@ -124,10 +159,24 @@ func (d *Service) CreateStory(pokerID string, name string, storyType string, ref
 
 // ActivateStoryVoting sets the story by ID to active, wipes any previous votes/points, and disables votingLock
 func (d *Service) ActivateStoryVoting(pokerID string, storyID string) ([]*thunderdome.Story, error) {
-	if _, err := d.DB.Exec(
-		`CALL thunderdome.poker_story_activate($1, $2);`, pokerID, storyID,
-	); err != nil {
-		d.Logger.Error("CALL thunderdome.poker_story_activate error", zap.Error(err),
+	if err := d.withGameEvent(context.Background(), pokerID, thunderdome.GameEventStoryActivated, func(tx *sql.Tx) (any, error) {
+		if _, err := tx.Exec(
+			`CALL thunderdome.poker_story_activate($1, $2);`, pokerID, storyID,
+		); err != nil {
+			return nil, fmt.Errorf("CALL thunderdome.poker_story_activate error: %v", err)
+		}
+
+		// 更新游戏的ActiveStoryID
+		if _, err := tx.Exec(
+			`UPDATE thunderdome.poker SET active_story_id = $1, voting_locked = false WHERE id = $2;`,
+			storyID, pokerID,
+		); err != nil {
+			return nil, fmt.Errorf("update poker active_story_id error: %v", err)
+		}
+
+		return thunderdome.GameStoryEvent{StoryID: storyID}, nil
+	}); err != nil {
+		d.Logger.Error("poker story activate error", zap.Error(err),
 			zap.String("PokerID", pokerID), zap.String("StoryID", storyID))
 		return nil, err
 	}
@@ -140,21 +189,14 @@ func (d *Service) ActivateStoryVoting(pokerID string, storyID string) ([]*thunde
 		// 清除游戏缓存
 		gameCacheKey := fmt.Sprintf("game:%s", pokerID)
 		d.Redis.Del(context.Background(), gameCacheKey)
+		// the leaderboard is ranked from the story votes, points and active/skipped state
+		d.Redis.Del(context.Background(), leaderboardCacheKey(pokerID))
 
 		d.Logger.Info("Cleared cache after story activation",
 			zap.String("poker_id", pokerID),
 			zap.String("story_id", storyID))
 	}
 
-	// 更新游戏的ActiveStoryID
-	if _, err := d.DB.Exec(
-		`UPDATE thunderdome.poker SET active_story_id = $1, voting_locked = false WHERE id = $2;`,
-		storyID, pokerID,
-	); err != nil {
-		d.Logger.Error("Failed to update active_story_id", zap.Error(err),
-			zap.String("PokerID", pokerID), zap.String("StoryID", storyID))
-	}
-
 	stories := d.GetStories(pokerID, "")
 
 	return stories, nil
@@ -162,8 +204,10 @@ func (d *Service) ActivateStoryVoting(pokerID string, storyID string) ([]*thunde
 
 // SetVote sets a users vote for the story
 func (d *Service) SetVote(pokerID string, userID string, storyID string, voteValue string) (Stories []*thunderdome.Story, allUsersVoted bool) {
-	if _, err := d.DB.Exec(
-		`UPDATE thunderdome.poker_story p1
+	// the vote isn't set when its event can't be appended, the returned stories show the vote missing
+	if err := d.withGameEvent(context.Background(), pokerID, thunderdome.GameEventVoteCast, func(tx *sql.Tx) (any, error) {
+		if _, err := tx.Exec(
+			`UPDATE thunderdome.poker_story p1
 		SET votes = (
 			SELECT json_agg(data)
 			FROM (
@@ -176,7 +220,12 @@ func (d *Service) SetVote(pokerID string, userID string, storyID string, voteVal
 			) data
 		)
 		WHERE p1.id = $1;`,
-		storyID, userID, voteValue); err != nil {
+			storyID, userID, voteValue); err != nil {
+			return nil, fmt.Errorf("poker set vote query error: %v", err)
+		}
+
+		return thunderdome.GameStoryEvent{StoryID: storyID, UserID: userID, Vote: voteValue}, nil
+	}); err != nil {
 		d.Logger.Error("CALL thunderdome.poker_user_vote_set error", zap.Error(err),
 			zap.String("PokerID", pokerID), zap.String("UserID", userID),
 			zap.String("StoryID", storyID), zap.String("VoteValue", voteValue))
@@ -190,6 +239,7 @@ func (d *Service) SetVote(pokerID string, userID string, storyID string, voteVal
 		// 清除游戏缓存
 		gameCacheKey := fmt.Sprintf("game:%s", pokerID)
 		d.Redis.Del(context.Background(), gameCacheKey)
+		d.Redis.Del(context.Background(), leaderboardCacheKey(pokerID))
 
 		d.Logger.Info("Cleared cache after vote",
 			zap.String("poker_id", pokerID),
@@ -224,8 +274,9 @@ func (d *Service) SetVote(pokerID string, userID string, storyID string, voteVal
 
 // RetractVote removes a users vote for the story
 func (d *Service) RetractVote(pokerID string, userID string, storyID string) ([]*thunderdome.Story, error) {
-	if _, err := d.DB.Exec(
-		`UPDATE thunderdome.poker_story p1
+	if err := d.withGameEvent(context.Background(), pokerID, thunderdome.GameEventVoteRetracted, func(tx *sql.Tx) (any, error) {
+		if _, err := tx.Exec(
+			`UPDATE thunderdome.poker_story p1
 		SET votes = (
 			SELECT coalesce(json_agg(data), '[]'::JSON)
 			FROM (
@@ -236,9 +287,14 @@ func (d *Service) RetractVote(pokerID string, userID string, storyID string) ([]
 		)
 		WHERE p1.id = $1;
     `, storyID, userID); err != nil {
-		d.Logger.Error("poker retract vote query error", zap.Error(err),
+			return nil, fmt.Errorf("poker retract vote query error: %v", err)
+		}
+
+		return thunderdome.GameStoryEvent{StoryID: storyID, UserID: userID}, nil
+	}); err != nil {
+		d.Logger.Error("poker retract vote error", zap.Error(err),
 			zap.String("PokerID", pokerID), zap.String("UserID", userID), zap.String("StoryID", storyID))
-		return nil, fmt.Errorf("poker retract vote query error: %v", err)
+		return nil, err
 	}
 
 	// 清除缓存
@@ -249,6 +305,7 @@ func (d *Service) RetractVote(pokerID string, userID string, storyID string) ([]
 		// 清除游戏缓存
 		gameCacheKey := fmt.Sprintf("game:%s", pokerID)
 		d.Redis.Del(context.Background(), gameCacheKey)
+		d.Redis.Del(context.Background(), leaderboardCacheKey(pokerID))
 
 		d.Logger.Info("Cleared cache after vote retraction",
 			zap.String("poker_id", pokerID),
@@ -263,9 +320,20 @@ func (d *Service) RetractVote(pokerID string, userID string, storyID string) ([]
 
 // EndStoryVoting sets story to active: false
 func (d *Service) EndStoryVoting(pokerID string, storyID string) ([]*thunderdome.Story, error) {
-	if _, err := d.DB.Exec(
-		`CALL thunderdome.poker_plan_voting_stop($1, $2);`, pokerID, storyID); err != nil {
-		d.Logger.Error("CALL thunderdome.poker_plan_voting_stop error", zap.Error(err),
+	if err := d.withGameEvent(context.Background(), pokerID, thunderdome.GameEventVotingEnded, func(tx *sql.Tx) (any, error) {
+		if _, err := tx.Exec(
+			`CALL thunderdome.poker_plan_voting_stop($1, $2);`, pokerID, storyID); err != nil {
+			return nil, fmt.Errorf("CALL thunderdome.poker_plan_voting_stop error: %v", err)
+		}
+
+		return thunderdome.GameStoryEvent{StoryID: storyID}, nil
+	}); err != nil {
+		d.Logger.Error("poker end story voting error", zap.Error(err),
+			zap.String("PokerID", pokerID), zap.String("StoryID", storyID))
+		return nil, err
+	}
+	if err := d.recordVoteRound(pokerID, storyID); err != nil {
+		d.Logger.Error("poker story vote round record error", zap.Error(err),
 			zap.String("PokerID", pokerID), zap.String("StoryID", storyID))
 	}
 
@@ -277,6 +345,7 @@ func (d *Service) EndStoryVoting(pokerID string, storyID string) ([]*thunderdome
 		// 清除游戏缓存
 		gameCacheKey := fmt.Sprintf("game:%s", pokerID)
 		d.Redis.Del(context.Background(), gameCacheKey)
+		d.Redis.Del(context.Background(), leaderboardCacheKey(pokerID), statisticsCacheKey(pokerID))
 
 		d.Logger.Info("Cleared cache after ending story voting",
 			zap.String("poker_id", pokerID),
@@ -290,10 +359,17 @@ func (d *Service) EndStoryVoting(pokerID string, storyID string) ([]*thunderdome
 
 // SkipStory sets story to active: false and unsets games activeStoryId
 func (d *Service) SkipStory(pokerID string, storyID string) ([]*thunderdome.Story, error) {
-	if _, err := d.DB.Exec(
-		`CALL thunderdome.poker_vote_skip($1, $2);`, pokerID, storyID); err != nil {
-		d.Logger.Error("CALL thunderdome.poker_vote_skip error", zap.Error(err),
+	if err := d.withGameEvent(context.Background(), pokerID, thunderdome.GameEventStorySkipped, func(tx *sql.Tx) (any, error) {
+		if _, err := tx.Exec(
+			`CALL thunderdome.poker_vote_skip($1, $2);`, pokerID, storyID); err != nil {
+			return nil, fmt.Errorf("CALL thunderdome.poker_vote_skip error: %v", err)
+		}
+
+		return thunderdome.GameStoryEvent{StoryID: storyID}, nil
+	}); err != nil {
+		d.Logger.Error("poker skip story error", zap.Error(err),
 			zap.String("PokerID", pokerID), zap.String("StoryID", storyID))
+		return nil, err
 	}
 
 	// 清除缓存
@@ -304,6 +380,7 @@ func (d *Service) SkipStory(pokerID string, storyID string) ([]*thunderdome.Stor
 		// 清除游戏缓存
 		gameCacheKey := fmt.Sprintf("game:%s", pokerID)
 		d.Redis.Del(context.Background(), gameCacheKey)
+		d.Redis.Del(context.Background(), leaderboardCacheKey(pokerID))
 
 		d.Logger.Info("Cleared cache after skipping story",
 			zap.String("poker_id", pokerID),
@@ -317,6 +394,9 @@ func (d *Service) SkipStory(pokerID string, storyID string) ([]*thunderdome.Stor
 
 // UpdateStory updates the story by ID
 func (d *Service) UpdateStory(pokerID string, storyID string, name string, storyType string, referenceID string, link string, description string, acceptanceCriteria string, priority int32) ([]*thunderdome.Story, error) {
+	if err := validation.ValidateURL(link); err != nil {
+		return nil, err
+	}
 	sanitizedDescription := d.HTMLSanitizerPolicy.Sanitize(description)
 	sanitizedAcceptanceCriteria := d.HTMLSanitizerPolicy.Sanitize(acceptanceCriteria)
 	// default priority should be 99 for sort order purposes
@@ -362,10 +442,17 @@ func (d *Service) UpdateStory(pokerID string, storyID string, name string, story
 
 // DeleteStory removes a story from the current game by ID
 func (d *Service) DeleteStory(pokerID string, storyID string) ([]*thunderdome.Story, error) {
-	if _, err := d.DB.Exec(
-		`CALL thunderdome.poker_story_delete($1, $2);`, pokerID, storyID); err != nil {
-		d.Logger.Error("CALL thunderdome.poker_story_delete error", zap.Error(err),
+	if err := d.withGameEvent(context.Background(), pokerID, thunderdome.GameEventStoryDeleted, func(tx *sql.Tx) (any, error) {
+		if _, err := tx.Exec(
+			`CALL thunderdome.poker_story_delete($1, $2);`, pokerID, storyID); err != nil {
+			return nil, fmt.Errorf("CALL thunderdome.poker_story_delete error: %v", err)
+		}
+
+		return thunderdome.GameStoryEvent{StoryID: storyID}, nil
+	}); err != nil {
+		d.Logger.Error("poker delete story error", zap.Error(err),
 			zap.String("PokerID", pokerID), zap.String("StoryID", storyID))
+		return nil, err
 	}
 
 	// 清除缓存
@@ -376,6 +463,7 @@ func (d *Service) DeleteStory(pokerID string, storyID string) ([]*thunderdome.St
 		// 清除游戏缓存
 		gameCacheKey := fmt.Sprintf("game:%s", pokerID)
 		d.Redis.Del(context.Background(), gameCacheKey)
+		d.Redis.Del(context.Background(), leaderboardCacheKey(pokerID), statisticsCacheKey(pokerID))
 
 		d.Logger.Info("Cleared cache after deleting story",
 			zap.String("poker_id", pokerID),
@@ -447,21 +535,186 @@ func (d *Service) ArrangeStory(pokerID string, storyID string, beforeStoryID str
 
 // FinalizeStory sets story to active: false and updates the points
 func (d *Service) FinalizeStory(pokerID string, storyID string, points string) ([]*thunderdome.Story, error) {
-	if _, err := d.DB.Exec(
-		`CALL thunderdome.poker_story_finalize($1, $2, $3);`, pokerID, storyID, points); err != nil {
-		d.Logger.Error("CALL thunderdome.poker_story_finalize error", zap.Error(err),
+	if err := d.withGameEvent(context.Background(), pokerID, thunderdome.GameEventStoryFinalized, func(tx *sql.Tx) (any, error) {
+		if _, err := tx.Exec(
+			`CALL thunderdome.poker_story_finalize($1, $2, $3);`, pokerID, storyID, points); err != nil {
+			return nil, fmt.Errorf("CALL thunderdome.poker_story_finalize error: %v", err)
+		}
+
+		return thunderdome.GameStoryEvent{StoryID: storyID, Points: points}, nil
+	}); err != nil {
+		d.Logger.Error("poker finalize story error", zap.Error(err),
 			zap.String("PokerID", pokerID),
 			zap.String("StoryID", storyID),
 			zap.String("Points", points))
+		return nil, err
 	}
 
-	// 清除缓存
+	// 清除缓存, the game cache holds the story points used for its completion stats
 	if d.Redis != nil {
 		cacheKey := fmt.Sprintf("game:%s:stories", pokerID)
-		d.Redis.Del(context.Background(), cacheKey)
+		d.Redis.Del(context.Background(), cacheKey, fmt.Sprintf("game:%s", pokerID), leaderboardCacheKey(pokerID))
 	}
 
 	stories := d.GetStories(pokerID, "")
 
 	return stories, nil
 }
+
+// BulkUpdateStoryPriority sets the priority of multiple game stories in a single update
+func (d *Service) BulkUpdateStoryPriority(ctx context.Context, pokerID string, updates []thunderdome.StoryPriorityUpdate) error {
+	if len(updates) == 0 {
+		return nil
+	}
+
+	args := make([]any, 0, len(updates)*2+1)
+	args = append(args, pokerID)
+	cases := make([]string, 0, len(updates))
+	storyIDs := make([]string, 0, len(updates))
+	for _, update := range updates {
+		priority := update.Priority
+		// default priority should be 99 for sort order purposes
+		if priority == 0 {
+			priority = 99
+		}
+		args = append(args, update.StoryID, priority)
+		cases = append(cases, fmt.Sprintf("WHEN $%d::uuid THEN $%d::int", len(args)-1, len(args)))
+		storyIDs = append(storyIDs, fmt.Sprintf("$%d::uuid", len(args)-1))
+	}
+
+	if _, err := d.DB.ExecContext(ctx,
+		fmt.Sprintf(
+			`UPDATE thunderdome.poker_story
+			SET updated_date = NOW(), priority = CASE id %s END
+			WHERE poker_id = $1 AND id IN (%s);`,
+			strings.Join(cases, " "), strings.Join(storyIDs, ", "),
+		),
+		args...,
+	); err != nil {
+		return fmt.Errorf("bulk update poker story priority query error: %v", err)
+	}
+
+	if d.Redis != nil {
+		d.Redis.Del(ctx, fmt.Sprintf("game:%s:stories", pokerID), fmt.Sprintf("game:%s", pokerID))
+	}
+
+	return nil
+}
+
+// BulkUpdateStorySprint assigns multiple game stories to the sprint in a single update,
+// an empty sprint name removes the stories from their sprint
+func (d *Service) BulkUpdateStorySprint(ctx context.Context, pokerID string, sprint thunderdome.StorySprint, storyIDs []string) error {
+	if len(storyIDs) == 0 {
+		return nil
+	}
+
+	args := make([]any, 0, len(storyIDs)+4)
+	args = append(args, pokerID, sql.NullString{String: sprint.Name, Valid: sprint.Name != ""})
+	if sprint.Name == "" {
+		args = append(args, nil, nil)
+	} else {
+		args = append(args, sprint.StartDate, sprint.EndDate)
+	}
+	placeholders := make([]string, 0, len(storyIDs))
+	for _, storyID := range storyIDs {
+		args = append(args, storyID)
+		placeholders = append(placeholders, fmt.Sprintf("$%d::uuid", len(args)))
+	}
+
+	result, err := d.DB.ExecContext(ctx,
+		fmt.Sprintf(
+			`UPDATE thunderdome.poker_story
+			SET updated_date = NOW(), sprint_name = $2, sprint_start_date = $3, sprint_end_date = $4
+			WHERE poker_id = $1 AND id IN (%s);`,
+			strings.Join(placeholders, ", "),
+		),
+		args...,
+	)
+	if err != nil {
+		return fmt.Errorf("bulk update poker story sprint query error: %v", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("STORY_NOT_FOUND")
+	}
+
+	if d.Redis != nil {
+		d.Redis.Del(ctx, fmt.Sprintf("game:%s:stories", pokerID), fmt.Sprintf("game:%s", pokerID))
+	}
+
+	return nil
+}
+
+// BulkUpdateStoryStatus moves multiple game stories to the status, every story must be in the game and
+// able to move to the status otherwise none of the stories are updated
+func (d *Service) BulkUpdateStoryStatus(ctx context.Context, pokerID string, status string, storyIDs []string) error {
+	if len(storyIDs) == 0 {
+		return nil
+	}
+
+	tx, err := d.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("bulk update poker story status begin transaction error: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	args := make([]any, 0, len(storyIDs)+1)
+	args = append(args, pokerID)
+	placeholders := make([]string, 0, len(storyIDs))
+	for _, storyID := range storyIDs {
+		args = append(args, storyID)
+		placeholders = append(placeholders, fmt.Sprintf("$%d::uuid", len(args)))
+	}
+	inStories := strings.Join(placeholders, ", ")
+
+	rows, err := tx.QueryContext(ctx,
+		fmt.Sprintf(
+			`SELECT id, points, status FROM thunderdome.poker_story
+			WHERE poker_id = $1 AND id IN (%s) FOR UPDATE;`,
+			inStories,
+		),
+		args...,
+	)
+	if err != nil {
+		return fmt.Errorf("bulk update poker story status query error: %v", err)
+	}
+	found := make(map[string]bool, len(storyIDs))
+	for rows.Next() {
+		var story thunderdome.Story
+		if err := rows.Scan(&story.ID, &story.Points, &story.Status); err != nil {
+			rows.Close()
+			return fmt.Errorf("bulk update poker story status scan error: %v", err)
+		}
+		if err := thunderdome.ValidateStoryStatusTransition(&story, status); err != nil {
+			rows.Close()
+			return err
+		}
+		found[story.ID] = true
+	}
+	rows.Close()
+	for _, storyID := range storyIDs {
+		if !found[storyID] {
+			return fmt.Errorf("STORY_NOT_FOUND")
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		fmt.Sprintf(
+			`UPDATE thunderdome.poker_story SET updated_date = NOW(), status = $%d
+			WHERE poker_id = $1 AND id IN (%s);`,
+			len(args)+1, inStories,
+		),
+		append(args, status)...,
+	); err != nil {
+		return fmt.Errorf("bulk update poker story status query error: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("bulk update poker story status commit error: %v", err)
+	}
+
+	if d.Redis != nil {
+		d.Redis.Del(ctx, fmt.Sprintf("game:%s:stories", pokerID), fmt.Sprintf("game:%s", pokerID))
+	}
+
+	return nil
+}
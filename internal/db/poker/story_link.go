@@ -0,0 +1,20 @@
+package poker
+
+import (
+	"fmt"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/validation"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// validateStoryLinks checks every story link is a valid http or https URL before any story is stored,
+// the returned error wraps validation.ErrInvalidURL
+func validateStoryLinks(stories []*thunderdome.Story) error {
+	for _, story := range stories {
+		if err := validation.ValidateURL(story.Link); err != nil {
+			return fmt.Errorf("story %q link: %w", story.Name, err)
+		}
+	}
+
+	return nil
+}
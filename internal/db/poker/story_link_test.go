@@ -0,0 +1,25 @@
+package poker
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/validation"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// TestValidateStoryLinks makes sure a single invalid link rejects the whole set of stories
+func TestValidateStoryLinks(t *testing.T) {
+	stories := []*thunderdome.Story{
+		{Name: "No Link"},
+		{Name: "Linked", Link: "https://example.com/TD-1"},
+	}
+	if err := validateStoryLinks(stories); err != nil {
+		t.Fatalf("expected valid links, got %v", err)
+	}
+
+	stories = append(stories, &thunderdome.Story{Name: "Placeholder", Link: "TBD"})
+	if err := validateStoryLinks(stories); !errors.Is(err, validation.ErrInvalidURL) {
+		t.Errorf("expected an invalid URL error, got %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+package poker
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// GetStoriesByReferenceID gets the stories with the reference ID (e.g. a Jira issue key) across the team's games,
+// newest game first, votes aren't included as the team users may not be in every game
+func (d *Service) GetStoriesByReferenceID(ctx context.Context, teamID string, referenceID string) ([]*thunderdome.StoryWithGame, error) {
+	stories := make([]*thunderdome.StoryWithGame, 0)
+
+	rows, err := d.reader().QueryContext(ctx,
+		`SELECT s.id, s.name, s.type, s.reference_id, COALESCE(s.link, ''), COALESCE(s.description, ''),
+			COALESCE(s.acceptance_criteria, ''), s.priority, s.points, s.active, s.skipped,
+			COALESCE(s.sprint_name, ''), p.id, p.name, p.created_date
+		FROM thunderdome.poker_story s
+		JOIN thunderdome.poker p ON p.id = s.poker_id
+		WHERE p.team_id = $1 AND s.reference_id = $2
+		ORDER BY p.created_date DESC, s.position;`,
+		teamID, referenceID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get poker stories by reference id query error: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var referenceID sql.NullString
+		s := &thunderdome.StoryWithGame{Story: thunderdome.Story{Votes: make([]*thunderdome.Vote, 0)}}
+		if err := rows.Scan(
+			&s.ID, &s.Name, &s.Type, &referenceID, &s.Link, &s.Description,
+			&s.AcceptanceCriteria, &s.Priority, &s.Points, &s.Active, &s.Skipped,
+			&s.SprintName, &s.GameID, &s.GameName, &s.CreatedDate,
+		); err != nil {
+			return nil, fmt.Errorf("get poker stories by reference id scan error: %v", err)
+		}
+		s.ReferenceID = referenceID.String
+		stories = append(stories, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get poker stories by reference id rows error: %v", err)
+	}
+
+	return stories, nil
+}
@@ -0,0 +1,69 @@
+package poker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+// storyTypeReportCacheTTL is the longest the team story type report is cached
+const storyTypeReportCacheTTL = 2 * time.Hour
+
+// CrossGameStoryTypeComparison compares the final points of the team's stories of the type
+// across its poker games created since the date, skipped and unestimated stories are left out
+func (d *Service) CrossGameStoryTypeComparison(ctx context.Context, teamID string, storyType string, since time.Time) (*thunderdome.StoryTypeReport, error) {
+	cacheKey := storyTypeReportCacheKey(teamID, storyType, since)
+	ttl := thunderdome.CappedTTL(d.CacheTTL.TeamTTL, storyTypeReportCacheTTL)
+	if d.Redis != nil && ttl > 0 {
+		if cachedData, err := d.Redis.Get(ctx, cacheKey).Result(); err == nil {
+			var report thunderdome.StoryTypeReport
+			if err := json.Unmarshal([]byte(cachedData), &report); err == nil {
+				d.Logger.Ctx(ctx).Debug("Story type report cache hit", zap.String("team_id", teamID))
+				return &report, nil
+			}
+		}
+	}
+
+	rows, err := d.reader().QueryContext(ctx,
+		`SELECT s.poker_id, s.points
+		FROM thunderdome.poker_story s
+		JOIN thunderdome.poker p ON p.id = s.poker_id
+		WHERE p.team_id = $1 AND s.type = $2 AND p.created_date >= $3
+			AND s.points <> '' AND s.skipped = false;`,
+		teamID, storyType, since,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get story type report query error: %v", err)
+	}
+	defer rows.Close()
+
+	gamePoints := make(map[string][]string)
+	for rows.Next() {
+		var pokerID, points string
+		if err := rows.Scan(&pokerID, &points); err != nil {
+			return nil, fmt.Errorf("get story type report scan error: %v", err)
+		}
+		gamePoints[pokerID] = append(gamePoints[pokerID], points)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get story type report rows error: %v", err)
+	}
+
+	report := thunderdome.ComputeStoryTypeReport(storyType, gamePoints)
+
+	if d.Redis != nil && ttl > 0 {
+		if reportJSON, err := json.Marshal(report); err == nil {
+			d.Redis.Set(ctx, cacheKey, reportJSON, ttl)
+		}
+	}
+
+	return report, nil
+}
+
+func storyTypeReportCacheKey(teamID string, storyType string, since time.Time) string {
+	return fmt.Sprintf("team:story-type-report:%s:%s:%d", teamID, storyType, since.Unix())
+}
@@ -0,0 +1,246 @@
+package poker
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+// timeBoxEventsChannel is the redis pub/sub channel poker story time box events are published to
+const timeBoxEventsChannel = "poker:timebox"
+
+// StartStoryTimeBox starts the game's time box for the story, replacing the time box of the previously active story,
+// returns nil when the game has no time box
+func (d *Service) StartStoryTimeBox(ctx context.Context, pokerID string, storyID string) (*thunderdome.PokerTimeBox, error) {
+	timeBox := &thunderdome.PokerTimeBox{StoryID: storyID}
+	err := d.DB.QueryRowContext(ctx,
+		`INSERT INTO thunderdome.poker_story_timebox (poker_id, story_id, duration_seconds, started_at)
+		SELECT p.id, $2, p.time_box_minutes * 60, NOW()
+		FROM thunderdome.poker p
+		WHERE p.id = $1 AND p.time_box_minutes > 0
+		ON CONFLICT (poker_id) DO UPDATE
+		SET story_id = EXCLUDED.story_id, duration_seconds = EXCLUDED.duration_seconds,
+			started_at = EXCLUDED.started_at, updated_date = NOW()
+		RETURNING duration_seconds, started_at;`,
+		pokerID, storyID,
+	).Scan(&timeBox.DurationSeconds, &timeBox.StartedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		// the time box may have been disabled while a story was time boxed
+		if _, err := d.DB.ExecContext(ctx,
+			`DELETE FROM thunderdome.poker_story_timebox WHERE poker_id = $1;`, pokerID,
+		); err != nil {
+			return nil, fmt.Errorf("start poker story time box delete query error: %v", err)
+		}
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("start poker story time box query error: %v", err)
+	}
+
+	d.publishTimeBoxEvent(ctx, thunderdome.PokerTimeBoxEventStart, pokerID, timeBox)
+
+	return timeBox, nil
+}
+
+// ExtendTimeBox adds the seconds to the game's running story time box
+func (d *Service) ExtendTimeBox(ctx context.Context, pokerID string, additionalSeconds int) error {
+	if additionalSeconds <= 0 {
+		return thunderdome.ErrInvalidTimeBoxExtension
+	}
+
+	timeBox := &thunderdome.PokerTimeBox{}
+	err := d.DB.QueryRowContext(ctx,
+		`UPDATE thunderdome.poker_story_timebox
+		SET duration_seconds = duration_seconds + $2, updated_date = NOW()
+		WHERE poker_id = $1 AND started_at + make_interval(secs => duration_seconds) > NOW()
+		RETURNING story_id, duration_seconds, started_at;`,
+		pokerID, additionalSeconds,
+	).Scan(&timeBox.StoryID, &timeBox.DurationSeconds, &timeBox.StartedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return thunderdome.ErrTimeBoxNotRunning
+	}
+	if err != nil {
+		return fmt.Errorf("extend poker story time box query error: %v", err)
+	}
+
+	d.publishTimeBoxEvent(ctx, thunderdome.PokerTimeBoxEventStart, pokerID, timeBox)
+
+	return nil
+}
+
+// GetStoryTimeBox gets the game's story time box, nil when there isn't one
+func (d *Service) GetStoryTimeBox(ctx context.Context, pokerID string) (*thunderdome.PokerTimeBox, error) {
+	timeBox := &thunderdome.PokerTimeBox{}
+	err := d.DB.QueryRowContext(ctx,
+		`SELECT story_id, duration_seconds, started_at
+		FROM thunderdome.poker_story_timebox
+		WHERE poker_id = $1;`,
+		pokerID,
+	).Scan(&timeBox.StoryID, &timeBox.DurationSeconds, &timeBox.StartedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get poker story time box query error: %v", err)
+	}
+
+	return timeBox, nil
+}
+
+// runningTimeBoxColumns are the poker_story_timebox columns scanned by scanRunningTimeBox, the remaining
+// time is measured with the database clock
+const runningTimeBoxColumns = `poker_id, story_id, duration_seconds, started_at,
+	GREATEST(EXTRACT(EPOCH FROM started_at + make_interval(secs => duration_seconds) - NOW()), 0)`
+
+// scanRunningTimeBox scans a row selected with runningTimeBoxColumns
+func scanRunningTimeBox(row rowScanner) (*thunderdome.PokerRunningTimeBox, error) {
+	running := &thunderdome.PokerRunningTimeBox{}
+	var remainingSeconds float64
+	if err := row.Scan(
+		&running.PokerID, &running.TimeBox.StoryID, &running.TimeBox.DurationSeconds, &running.TimeBox.StartedAt,
+		&remainingSeconds,
+	); err != nil {
+		return nil, err
+	}
+	running.Remaining = time.Duration(remainingSeconds * float64(time.Second))
+
+	return running, nil
+}
+
+// GetRunningTimeBox gets the game's story time box and how much of it is left, nil when there isn't one
+func (d *Service) GetRunningTimeBox(ctx context.Context, pokerID string) (*thunderdome.PokerRunningTimeBox, error) {
+	running, err := scanRunningTimeBox(d.DB.QueryRowContext(ctx,
+		`SELECT `+runningTimeBoxColumns+` FROM thunderdome.poker_story_timebox WHERE poker_id = $1;`,
+		pokerID,
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get running poker story time box query error: %v", err)
+	}
+
+	return running, nil
+}
+
+// GetRunningTimeBoxes gets every game's story time box and how much of it is left, including
+// time boxes that ran out while no instance was running
+func (d *Service) GetRunningTimeBoxes(ctx context.Context) ([]*thunderdome.PokerRunningTimeBox, error) {
+	timeBoxes := make([]*thunderdome.PokerRunningTimeBox, 0)
+
+	rows, err := d.DB.QueryContext(ctx,
+		`SELECT `+runningTimeBoxColumns+` FROM thunderdome.poker_story_timebox;`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get running poker story time boxes query error: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		running, err := scanRunningTimeBox(rows)
+		if err != nil {
+			return nil, fmt.Errorf("get running poker story time boxes scan error: %v", err)
+		}
+		timeBoxes = append(timeBoxes, running)
+	}
+
+	return timeBoxes, nil
+}
+
+// ExpireStoryTimeBox removes the story's time box once it has run out, returning whether it was removed
+// so only one instance acts on the expiry
+func (d *Service) ExpireStoryTimeBox(ctx context.Context, pokerID string, storyID string) (bool, error) {
+	result, err := d.DB.ExecContext(ctx,
+		`DELETE FROM thunderdome.poker_story_timebox
+		WHERE poker_id = $1 AND story_id = $2 AND started_at + make_interval(secs => duration_seconds) <= NOW();`,
+		pokerID, storyID,
+	)
+	if err != nil {
+		return false, fmt.Errorf("expire poker story time box query error: %v", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("expire poker story time box rows affected error: %v", err)
+	}
+
+	return rows > 0, nil
+}
+
+// StopStoryTimeBox removes the game's story time box, e.g. when voting is ended before it runs out
+func (d *Service) StopStoryTimeBox(ctx context.Context, pokerID string) error {
+	if _, err := d.DB.ExecContext(ctx,
+		`DELETE FROM thunderdome.poker_story_timebox WHERE poker_id = $1;`,
+		pokerID,
+	); err != nil {
+		return fmt.Errorf("stop poker story time box query error: %v", err)
+	}
+
+	d.publishTimeBoxEvent(ctx, thunderdome.PokerTimeBoxEventStop, pokerID, &thunderdome.PokerTimeBox{})
+
+	return nil
+}
+
+// publishTimeBoxEvent publishes the poker time box event to every instance, failures are only logged
+// as the instance handling the change still tracks the time box for its own participants
+func (d *Service) publishTimeBoxEvent(ctx context.Context, eventType string, pokerID string, timeBox *thunderdome.PokerTimeBox) {
+	if d.Redis == nil {
+		return
+	}
+
+	payload, err := json.Marshal(thunderdome.PokerTimeBoxEvent{Type: eventType, PokerID: pokerID, TimeBox: *timeBox})
+	if err != nil {
+		d.Logger.Ctx(ctx).Error("poker time box event json error", zap.Error(err))
+		return
+	}
+
+	if err := d.Redis.Publish(ctx, timeBoxEventsChannel, payload).Err(); err != nil {
+		d.Logger.Ctx(ctx).Error("publish poker time box event error", zap.Error(err),
+			zap.String("poker_id", pokerID), zap.String("event_type", eventType))
+	}
+}
+
+// SubscribeTimeBoxEvents receives the poker time box events published by every instance until the context is done,
+// without redis there is nothing to subscribe to and a nil channel is returned
+func (d *Service) SubscribeTimeBoxEvents(ctx context.Context) <-chan thunderdome.PokerTimeBoxEvent {
+	if d.Redis == nil {
+		return nil
+	}
+
+	pubsub := d.Redis.Subscribe(ctx, timeBoxEventsChannel)
+	events := make(chan thunderdome.PokerTimeBoxEvent)
+
+	go func() {
+		defer close(events)
+		defer pubsub.Close()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-pubsub.Channel():
+				if !ok {
+					return
+				}
+				var event thunderdome.PokerTimeBoxEvent
+				if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+					d.Logger.Ctx(ctx).Error("poker time box event json error", zap.Error(err))
+					continue
+				}
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return events
+}
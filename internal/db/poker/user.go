@@ -1,9 +1,11 @@
 package poker
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/StevenWeathers/thunderdome-planning-poker/internal/db"
 	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
@@ -41,9 +43,11 @@ func (d *Service) GetUsers(pokerID string) []*thunderdome.PokerUser {
 	var users = make([]*thunderdome.PokerUser, 0)
 	rows, err := d.DB.Query(
 		`SELECT
-			u.id, u.name, u.type, u.avatar, pu.active, pu.spectator, COALESCE(u.email, ''), COALESCE(u.picture, '')
+			u.id, CASE WHEN u.anonymized_at IS NOT NULL THEN 'Anonymous' ELSE u.name END, u.type, u.avatar, pu.active, pu.spectator, pu.observer, COALESCE(pf.is_primary, false),
+			COALESCE(u.email, ''), COALESCE(u.picture, '')
 		FROM thunderdome.poker_user pu
 		LEFT JOIN thunderdome.users u ON pu.user_id = u.id
+		LEFT JOIN thunderdome.poker_facilitator pf ON pf.poker_id = pu.poker_id AND pf.user_id = pu.user_id
 		WHERE pu.poker_id = $1
 		ORDER BY u.name`,
 		pokerID,
@@ -52,7 +56,7 @@ func (d *Service) GetUsers(pokerID string) []*thunderdome.PokerUser {
 		defer rows.Close()
 		for rows.Next() {
 			var w thunderdome.PokerUser
-			if err := rows.Scan(&w.ID, &w.Name, &w.Type, &w.Avatar, &w.Active, &w.Spectator, &w.GravatarHash, &w.PictureURL); err != nil {
+			if err := rows.Scan(&w.ID, &w.Name, &w.Type, &w.Avatar, &w.Active, &w.Spectator, &w.Observer, &w.IsPrimaryFacilitator, &w.GravatarHash, &w.PictureURL); err != nil {
 				d.Logger.Error("error getting poker users", zap.Error(err))
 			} else {
 				if w.GravatarHash != "" {
@@ -73,9 +77,11 @@ func (d *Service) GetActiveUsers(pokerID string) []*thunderdome.PokerUser {
 	var users = make([]*thunderdome.PokerUser, 0)
 	rows, err := d.DB.Query(
 		`SELECT
-			w.id, w.name, w.type, w.avatar, bw.active, bw.spectator, COALESCE(w.email, ''), COALESCE(w.picture, '')
+			w.id, w.name, w.type, w.avatar, bw.active, bw.spectator, COALESCE(pf.is_primary, false),
+			COALESCE(w.email, ''), COALESCE(w.picture, '')
 		FROM thunderdome.poker_user bw
 		LEFT JOIN thunderdome.users w ON bw.user_id = w.id
+		LEFT JOIN thunderdome.poker_facilitator pf ON pf.poker_id = bw.poker_id AND pf.user_id = bw.user_id
 		WHERE bw.poker_id = $1 AND bw.active = true
 		ORDER BY w.name`,
 		pokerID,
@@ -84,7 +90,7 @@ func (d *Service) GetActiveUsers(pokerID string) []*thunderdome.PokerUser {
 		defer rows.Close()
 		for rows.Next() {
 			var w thunderdome.PokerUser
-			if err := rows.Scan(&w.ID, &w.Name, &w.Type, &w.Avatar, &w.Active, &w.Spectator, &w.GravatarHash, &w.PictureURL); err != nil {
+			if err := rows.Scan(&w.ID, &w.Name, &w.Type, &w.Avatar, &w.Active, &w.Spectator, &w.IsPrimaryFacilitator, &w.GravatarHash, &w.PictureURL); err != nil {
 				d.Logger.Error("error getting active poker users", zap.Error(err))
 			} else {
 				if w.GravatarHash != "" {
@@ -134,6 +140,54 @@ func (d *Service) RetreatUser(pokerID string, userID string) []*thunderdome.Poke
 	return users
 }
 
+// DeactivateDisconnectedUsers sets the active game users that aren't connected to their game inactive,
+// connectedUsers is the IDs of the users connected to each game. Only the games in connectedUsers are
+// checked as other instances of the application hold the connections to the rest. Returns the IDs of
+// the games that had active users who weren't connected.
+func (d *Service) DeactivateDisconnectedUsers(ctx context.Context, connectedUsers map[string][]string) ([]string, error) {
+	games := make([]string, 0)
+	if len(connectedUsers) == 0 {
+		return games, nil
+	}
+
+	rooms := make([]string, 0, len(connectedUsers))
+	pokerIDs := make([]string, 0)
+	userIDs := make([]string, 0)
+	for pokerID, users := range connectedUsers {
+		rooms = append(rooms, pokerID)
+		for _, userID := range users {
+			pokerIDs = append(pokerIDs, pokerID)
+			userIDs = append(userIDs, userID)
+		}
+	}
+
+	rows, err := d.DB.QueryContext(ctx,
+		`UPDATE thunderdome.poker_user pu SET active = false
+		WHERE pu.active = true AND pu.poker_id = ANY($3::uuid[]) AND NOT EXISTS (
+			SELECT 1 FROM unnest($1::uuid[], $2::uuid[]) AS c(poker_id, user_id)
+			WHERE c.poker_id = pu.poker_id AND c.user_id = pu.user_id
+		)
+		RETURNING pu.poker_id;`,
+		pokerIDs, userIDs, rooms,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("deactivate disconnected poker users query error: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var pokerID string
+		if err := rows.Scan(&pokerID); err != nil {
+			return nil, fmt.Errorf("deactivate disconnected poker users scan error: %v", err)
+		}
+		if !slices.Contains(games, pokerID) {
+			games = append(games, pokerID)
+		}
+	}
+
+	return games, nil
+}
+
 // AbandonGame removes a user from the current game by ID and sets abandoned true
 func (d *Service) AbandonGame(pokerID string, userID string) ([]*thunderdome.PokerUser, error) {
 	if _, err := d.DB.Exec(
@@ -153,8 +207,10 @@ func (d *Service) AbandonGame(pokerID string, userID string) ([]*thunderdome.Pok
 
 // ToggleSpectator changes a game users spectator status
 func (d *Service) ToggleSpectator(pokerID string, userID string, spectator bool) ([]*thunderdome.PokerUser, error) {
+	// a user made a participant is no longer an observer
 	if _, err := d.DB.Exec(
-		`UPDATE thunderdome.poker_user SET spectator = $3 WHERE poker_id = $1 AND user_id = $2`, pokerID, userID, spectator); err != nil {
+		`UPDATE thunderdome.poker_user SET spectator = $3, observer = observer AND $3
+		WHERE poker_id = $1 AND user_id = $2`, pokerID, userID, spectator); err != nil {
 		return nil, fmt.Errorf("poker toggle spectator query error: %v", err)
 	}
 
@@ -167,3 +223,32 @@ func (d *Service) ToggleSpectator(pokerID string, userID string, spectator bool)
 
 	return users, nil
 }
+
+// SetObserver makes the user an observer of the game, observers are spectators that
+// only a facilitator can make a participant
+func (d *Service) SetObserver(pokerID string, userID string) ([]*thunderdome.PokerUser, error) {
+	if _, err := d.DB.Exec(
+		`UPDATE thunderdome.poker_user SET spectator = true, observer = true WHERE poker_id = $1 AND user_id = $2`,
+		pokerID, userID); err != nil {
+		return nil, fmt.Errorf("poker set observer query error: %v", err)
+	}
+
+	users := d.GetUsers(pokerID)
+
+	return users, nil
+}
+
+// IsObserver checks whether the user joined the game as an observer
+func (d *Service) IsObserver(pokerID string, userID string) (bool, error) {
+	var observer bool
+	if err := d.DB.QueryRow(
+		`SELECT observer FROM thunderdome.poker_user WHERE poker_id = $1 AND user_id = $2`,
+		pokerID, userID).Scan(&observer); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("poker is observer query error: %v", err)
+	}
+
+	return observer, nil
+}
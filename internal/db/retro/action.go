@@ -104,7 +104,7 @@ func (d *Service) GetTeamRetroActions(teamID string, limit int, offset int, comp
 	e := d.DB.QueryRow(
 		`SELECT COUNT(ra.*) FROM thunderdome.retro tr
 				LEFT JOIN thunderdome.retro_action ra ON ra.retro_id = tr.id
-				WHERE tr.team_id = $1 AND ra.completed = $2;`,
+				WHERE tr.team_id = $1 AND tr.deleted_at IS NULL AND ra.completed = $2;`,
 		teamID,
 		completed,
 	).Scan(
@@ -126,7 +126,7 @@ func (d *Service) GetTeamRetroActions(teamID string, limit int, offset int, comp
 				FROM thunderdome.retro_action ra
 				LEFT JOIN thunderdome.retro_action_assignee as t ON t.action_id = ra.id
 				LEFT JOIN thunderdome.users u ON t.user_id = u.id
-				WHERE ra.retro_id IN (SELECT id FROM thunderdome.retro WHERE team_id = $1) AND ra.completed = $2
+				WHERE ra.retro_id IN (SELECT id FROM thunderdome.retro WHERE team_id = $1 AND deleted_at IS NULL) AND ra.completed = $2
 				GROUP BY ra.id, ra.created_date
 				ORDER BY ra.created_date DESC
 				LIMIT $3 OFFSET $4;`,
@@ -0,0 +1,100 @@
+package retro
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+func emotionHistogramCacheKey(retroID string) string {
+	return fmt.Sprintf("retro:emotions:%s", retroID)
+}
+
+// invalidateEmotionHistogram removes the cached retro emotion histogram once its items change
+func (d *Service) invalidateEmotionHistogram(retroID string) {
+	if d.Redis != nil {
+		d.Redis.Del(context.Background(), emotionHistogramCacheKey(retroID))
+	}
+}
+
+// TagItemEmotion tags the retro item with an emotion, an empty emotion removes the tag
+func (d *Service) TagItemEmotion(ctx context.Context, retroID string, itemID string, emotion string) error {
+	if err := thunderdome.ValidateRetroEmotion(emotion); err != nil {
+		return err
+	}
+
+	result, err := d.DB.ExecContext(ctx,
+		`UPDATE thunderdome.retro_item SET emotion = NULLIF($3, ''), updated_date = NOW()
+		WHERE retro_id = $1 AND id = $2;`,
+		retroID, itemID, emotion,
+	)
+	if err != nil {
+		return fmt.Errorf("tag retro item emotion query error: %v", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("RETRO_ITEM_NOT_FOUND")
+	}
+
+	d.invalidateEmotionHistogram(retroID)
+
+	return nil
+}
+
+// GetEmotionHistogram gets the number of the retro's items tagged with each emotion,
+// items without an emotion are counted as untagged
+func (d *Service) GetEmotionHistogram(ctx context.Context, retroID string) (map[string]int, error) {
+	cacheKey := emotionHistogramCacheKey(retroID)
+	if d.Redis != nil {
+		if cachedData, err := d.Redis.Get(ctx, cacheKey).Result(); err == nil {
+			var histogram map[string]int
+			if err := json.Unmarshal([]byte(cachedData), &histogram); err == nil {
+				d.Logger.Ctx(ctx).Debug("Retro emotion histogram cache hit", zap.String("retro_id", retroID))
+				return histogram, nil
+			}
+		}
+	}
+
+	var exists bool
+	err := d.DB.QueryRowContext(ctx,
+		`SELECT EXISTS(SELECT 1 FROM thunderdome.retro WHERE id = $1 AND deleted_at IS NULL);`,
+		retroID,
+	).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("get retro emotion histogram query error: %v", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("RETRO_NOT_FOUND")
+	}
+
+	rows, err := d.DB.QueryContext(ctx,
+		`SELECT COALESCE(emotion, '') FROM thunderdome.retro_item WHERE retro_id = $1;`,
+		retroID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get retro item emotions query error: %v", err)
+	}
+	defer rows.Close()
+
+	items := make([]*thunderdome.RetroItem, 0)
+	for rows.Next() {
+		item := &thunderdome.RetroItem{}
+		if err := rows.Scan(&item.Emotion); err != nil {
+			return nil, fmt.Errorf("get retro item emotions query scan error: %v", err)
+		}
+		items = append(items, item)
+	}
+
+	histogram := thunderdome.RetroEmotionHistogram(items)
+
+	if d.Redis != nil {
+		if histogramJSON, err := json.Marshal(histogram); err == nil {
+			d.Redis.Set(ctx, cacheKey, histogramJSON, 10*time.Minute)
+		}
+	}
+
+	return histogram, nil
+}
@@ -14,7 +14,7 @@ func (d *Service) GetRetroFacilitatorCode(retroID string) (string, error) {
 
 	if err := d.DB.QueryRow(`
 		SELECT COALESCE(facilitator_code, '') FROM thunderdome.retro
-		WHERE id = $1`,
+		WHERE id = $1 AND deleted_at IS NULL`,
 		retroID,
 	).Scan(&encryptedCode); err != nil {
 		return "", fmt.Errorf("get retro facilitator_code query error: %v", err)
@@ -0,0 +1,157 @@
+package retro
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// CreateGroup creates a named and colored retro group that facilitators can cluster items into
+func (d *Service) CreateGroup(retroID string, name string, color string) ([]*thunderdome.RetroGroup, error) {
+	if _, err := d.DB.Exec(
+		`INSERT INTO thunderdome.retro_group (retro_id, name, color) VALUES ($1, $2, $3);`,
+		retroID, name, color,
+	); err != nil {
+		return nil, fmt.Errorf("create retro group query error: %v", err)
+	}
+
+	return d.GetRetroGroups(retroID), nil
+}
+
+// AddItemToGroup adds a retro item to a group, an item can only belong to one group
+// so adding an item that is already in another group moves it
+func (d *Service) AddItemToGroup(retroID string, groupID string, itemID string) ([]*thunderdome.RetroGroup, error) {
+	tx, err := d.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("add item to retro group begin transaction error: %v", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
+		`INSERT INTO thunderdome.retro_item_group (item_id, group_id)
+			SELECT ri.id, rg.id
+			FROM thunderdome.retro_item ri
+			JOIN thunderdome.retro_group rg ON rg.retro_id = ri.retro_id
+			WHERE ri.retro_id = $1 AND rg.id = $2 AND ri.id = $3
+			ON CONFLICT (item_id) DO UPDATE SET group_id = EXCLUDED.group_id, created_date = NOW();`,
+		retroID, groupID, itemID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("add item to retro group query error: %v", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return nil, fmt.Errorf("add item to retro group error: retro item or group not found")
+	}
+
+	if _, err := tx.Exec(
+		`UPDATE thunderdome.retro_item SET group_id = $2, updated_date = NOW() WHERE retro_id = $1 AND id = $3;`,
+		retroID, groupID, itemID,
+	); err != nil {
+		return nil, fmt.Errorf("add item to retro group update item query error: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("add item to retro group commit error: %v", err)
+	}
+
+	return d.GetRetroGroups(retroID), nil
+}
+
+// RemoveItemFromGroup removes a retro item from a group, placing the item back into its own group
+func (d *Service) RemoveItemFromGroup(retroID string, groupID string, itemID string) ([]*thunderdome.RetroGroup, error) {
+	tx, err := d.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("remove item from retro group begin transaction error: %v", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
+		`DELETE FROM thunderdome.retro_item_group rig
+			USING thunderdome.retro_group rg
+			WHERE rig.group_id = rg.id AND rg.retro_id = $1 AND rig.group_id = $2 AND rig.item_id = $3;`,
+		retroID, groupID, itemID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("remove item from retro group query error: %v", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return nil, fmt.Errorf("remove item from retro group error: retro item not in group")
+	}
+
+	if err := ungroupRetroItem(tx, retroID, itemID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("remove item from retro group commit error: %v", err)
+	}
+
+	return d.GetRetroGroups(retroID), nil
+}
+
+// DeleteGroup deletes a retro group, any items in the group are placed back into their own group
+func (d *Service) DeleteGroup(retroID string, groupID string) ([]*thunderdome.RetroGroup, error) {
+	tx, err := d.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("delete retro group begin transaction error: %v", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(
+		`SELECT id FROM thunderdome.retro_item WHERE retro_id = $1 AND group_id = $2;`,
+		retroID, groupID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("delete retro group get items query error: %v", err)
+	}
+	itemIDs := make([]string, 0)
+	for rows.Next() {
+		var itemID string
+		if err := rows.Scan(&itemID); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("delete retro group get items scan error: %v", err)
+		}
+		itemIDs = append(itemIDs, itemID)
+	}
+	rows.Close()
+
+	for _, itemID := range itemIDs {
+		if err := ungroupRetroItem(tx, retroID, itemID); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := tx.Exec(
+		`DELETE FROM thunderdome.retro_group WHERE retro_id = $1 AND id = $2;`,
+		retroID, groupID,
+	); err != nil {
+		return nil, fmt.Errorf("delete retro group query error: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("delete retro group commit error: %v", err)
+	}
+
+	return d.GetRetroGroups(retroID), nil
+}
+
+// ungroupRetroItem moves a retro item into a new group of its own
+func ungroupRetroItem(tx *sql.Tx, retroID string, itemID string) error {
+	var groupID string
+	if err := tx.QueryRow(
+		`INSERT INTO thunderdome.retro_group (retro_id) VALUES ($1) RETURNING id;`,
+		retroID,
+	).Scan(&groupID); err != nil {
+		return fmt.Errorf("insert retro group error: %v", err)
+	}
+
+	if _, err := tx.Exec(
+		`UPDATE thunderdome.retro_item SET group_id = $3, updated_date = NOW() WHERE retro_id = $1 AND id = $2;`,
+		retroID, itemID, groupID,
+	); err != nil {
+		return fmt.Errorf("ungroup retro item query error: %v", err)
+	}
+
+	return nil
+}
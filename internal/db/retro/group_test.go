@@ -0,0 +1,212 @@
+package retro
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+// groupDB is an in memory stand in for the retro group tables, retro_item_group is keyed by
+// item_id the same as its primary key so an item can only be in one group
+type groupDB struct {
+	mu        sync.Mutex
+	retroID   string
+	groups    []string
+	items     map[string]bool
+	itemGroup map[string]string
+	committed bool
+}
+
+var (
+	groupDBs   = make(map[string]*groupDB)
+	groupDBsMu sync.Mutex
+)
+
+func init() {
+	sql.Register("retro-group", groupDriver{})
+}
+
+// openGroupDB opens a sql.DB backed by the groupDB
+func openGroupDB(t *testing.T, gdb *groupDB) *sql.DB {
+	t.Helper()
+
+	groupDBsMu.Lock()
+	groupDBs[t.Name()] = gdb
+	groupDBsMu.Unlock()
+
+	db, err := sql.Open("retro-group", t.Name())
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	return db
+}
+
+type groupDriver struct{}
+
+func (groupDriver) Open(name string) (driver.Conn, error) {
+	groupDBsMu.Lock()
+	defer groupDBsMu.Unlock()
+
+	return &groupConn{db: groupDBs[name]}, nil
+}
+
+type groupConn struct {
+	db *groupDB
+}
+
+func (c *groupConn) Prepare(query string) (driver.Stmt, error) {
+	return &groupStmt{db: c.db, query: query}, nil
+}
+
+func (c *groupConn) Close() error              { return nil }
+func (c *groupConn) Begin() (driver.Tx, error) { return c, nil }
+
+func (c *groupConn) Commit() error {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	c.db.committed = true
+	return nil
+}
+
+func (c *groupConn) Rollback() error { return nil }
+
+type groupStmt struct {
+	db    *groupDB
+	query string
+}
+
+func (s *groupStmt) Close() error  { return nil }
+func (s *groupStmt) NumInput() int { return -1 }
+
+func (s *groupStmt) Exec(args []driver.Value) (driver.Result, error) {
+	db := s.db
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if strings.Contains(s.query, "INSERT INTO thunderdome.retro_item_group") {
+		retroID, groupID, itemID := args[0].(string), args[1].(string), args[2].(string)
+		if retroID != db.retroID || !db.items[itemID] || !db.hasGroup(groupID) {
+			return driver.RowsAffected(0), nil
+		}
+		if _, ok := db.itemGroup[itemID]; ok && !strings.Contains(s.query, "ON CONFLICT (item_id) DO UPDATE") {
+			return nil, errors.New("duplicate key value violates unique constraint retro_item_group_pkey")
+		}
+		db.itemGroup[itemID] = groupID
+	}
+
+	return driver.RowsAffected(1), nil
+}
+
+func (s *groupStmt) Query(args []driver.Value) (driver.Rows, error) {
+	db := s.db
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if !strings.Contains(s.query, "FROM thunderdome.retro_group rg") {
+		return nil, errors.New("unexpected query")
+	}
+
+	rows := &groupRows{}
+	for _, groupID := range db.groups {
+		itemIDs := make([]string, 0)
+		for itemID, itemGroupID := range db.itemGroup {
+			if itemGroupID == groupID {
+				itemIDs = append(itemIDs, itemID)
+			}
+		}
+		ids, _ := json.Marshal(itemIDs)
+		rows.values = append(rows.values, []driver.Value{groupID, db.retroID, "", "", string(ids)})
+	}
+
+	return rows, nil
+}
+
+func (db *groupDB) hasGroup(groupID string) bool {
+	for _, id := range db.groups {
+		if id == groupID {
+			return true
+		}
+	}
+	return false
+}
+
+type groupRows struct {
+	values [][]driver.Value
+}
+
+func (r *groupRows) Columns() []string {
+	return []string{"id", "retro_id", "name", "color", "item_ids"}
+}
+
+func (r *groupRows) Close() error { return nil }
+
+func (r *groupRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+// TestAddItemToGroupMovesItem makes sure adding an item that is already in a group moves it
+// to the new group instead of it belonging to both
+func TestAddItemToGroupMovesItem(t *testing.T) {
+	gdb := &groupDB{
+		retroID:   "retro",
+		groups:    []string{"went-well", "to-improve"},
+		items:     map[string]bool{"item": true},
+		itemGroup: make(map[string]string),
+	}
+	d := &Service{DB: openGroupDB(t, gdb), Logger: otelzap.New(zap.NewNop())}
+
+	groups, err := d.AddItemToGroup("retro", "went-well", "item")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(groups[0].ItemIDs) != 1 || len(groups[1].ItemIDs) != 0 {
+		t.Fatalf("expected the item in the first group, got %v and %v", groups[0].ItemIDs, groups[1].ItemIDs)
+	}
+
+	groups, err = d.AddItemToGroup("retro", "to-improve", "item")
+	if err != nil {
+		t.Fatalf("unexpected error moving the item: %v", err)
+	}
+	if !gdb.committed {
+		t.Error("expected the move to be committed")
+	}
+	if len(groups[0].ItemIDs) != 0 {
+		t.Errorf("expected the item to be removed from the first group, got %v", groups[0].ItemIDs)
+	}
+	if len(groups[1].ItemIDs) != 1 || groups[1].ItemIDs[0] != "item" {
+		t.Errorf("expected the item in the second group, got %v", groups[1].ItemIDs)
+	}
+}
+
+// TestAddItemToGroupNotFound makes sure items and groups from another retro can't be grouped
+func TestAddItemToGroupNotFound(t *testing.T) {
+	gdb := &groupDB{
+		retroID:   "retro",
+		groups:    []string{"went-well"},
+		items:     map[string]bool{"item": true},
+		itemGroup: make(map[string]string),
+	}
+	d := &Service{DB: openGroupDB(t, gdb), Logger: otelzap.New(zap.NewNop())}
+
+	if _, err := d.AddItemToGroup("retro", "other-retro-group", "item"); err == nil {
+		t.Error("expected an error for a group from another retro")
+	}
+	if len(gdb.itemGroup) != 0 {
+		t.Errorf("expected the item to stay ungrouped, got %v", gdb.itemGroup)
+	}
+}
@@ -0,0 +1,74 @@
+package retro
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// GetRetroInsights gets the retro's generated insights, insights generated before the retro's latest item
+// was added are stale and reported as not found so they get regenerated
+func (d *Service) GetRetroInsights(ctx context.Context, retroID string) (*thunderdome.RetroInsights, error) {
+	insights := &thunderdome.RetroInsights{RetroID: retroID}
+	var themes, actionItems []byte
+
+	err := d.DB.QueryRowContext(ctx,
+		`SELECT ri.themes, ri.action_items, ri.mood_summary, ri.generated_at
+		FROM thunderdome.retro_insight ri
+		WHERE ri.retro_id = $1 AND NOT EXISTS (
+			SELECT 1 FROM thunderdome.retro_item i WHERE i.retro_id = ri.retro_id AND i.created_date > ri.generated_at
+		);`,
+		retroID,
+	).Scan(&themes, &actionItems, &insights.MoodSummary, &insights.GeneratedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("RETRO_INSIGHTS_NOT_FOUND")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get retro insights query error: %v", err)
+	}
+
+	if err := json.Unmarshal(themes, &insights.TopThemes); err != nil {
+		return nil, fmt.Errorf("get retro insights themes decode error: %v", err)
+	}
+	if err := json.Unmarshal(actionItems, &insights.KeyActionItems); err != nil {
+		return nil, fmt.Errorf("get retro insights action items decode error: %v", err)
+	}
+
+	return insights, nil
+}
+
+// SaveRetroInsights saves the retro's generated insights, replacing any previously generated
+func (d *Service) SaveRetroInsights(ctx context.Context, retroID string, insights *thunderdome.RetroInsights) (*thunderdome.RetroInsights, error) {
+	themes, err := json.Marshal(insights.TopThemes)
+	if err != nil {
+		return nil, fmt.Errorf("save retro insights themes encode error: %v", err)
+	}
+	actionItems, err := json.Marshal(insights.KeyActionItems)
+	if err != nil {
+		return nil, fmt.Errorf("save retro insights action items encode error: %v", err)
+	}
+
+	saved := &thunderdome.RetroInsights{
+		RetroID:        retroID,
+		TopThemes:      insights.TopThemes,
+		KeyActionItems: insights.KeyActionItems,
+		MoodSummary:    insights.MoodSummary,
+	}
+	err = d.DB.QueryRowContext(ctx,
+		`INSERT INTO thunderdome.retro_insight (retro_id, themes, action_items, mood_summary, generated_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		ON CONFLICT (retro_id) DO UPDATE SET themes = EXCLUDED.themes, action_items = EXCLUDED.action_items,
+			mood_summary = EXCLUDED.mood_summary, generated_at = EXCLUDED.generated_at
+		RETURNING generated_at;`,
+		retroID, themes, actionItems, insights.MoodSummary,
+	).Scan(&saved.GeneratedAt)
+	if err != nil {
+		return nil, fmt.Errorf("save retro insights query error: %v", err)
+	}
+
+	return saved, nil
+}
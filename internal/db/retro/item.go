@@ -29,6 +29,7 @@ func (d *Service) CreateRetroItem(retroID string, userID string, itemType string
 	); err != nil {
 		d.Logger.Error("insert retro item error", zap.Error(err))
 	}
+	d.invalidateEmotionHistogram(retroID)
 
 	items := d.GetRetroItems(retroID)
 
@@ -42,15 +43,23 @@ func (d *Service) GroupRetroItem(retroID string, itemID string, groupID string)
 	err := d.DB.QueryRow(
 		`UPDATE thunderdome.retro_item SET group_id = $3
  				WHERE retro_id = $1 AND id = $2
- 				RETURNING id, user_id, group_id, content, type;`,
+ 				RETURNING id, user_id, group_id, content, type, COALESCE(emotion, '');`,
 		retroID, itemID, groupID,
-	).Scan(&ri.ID, &ri.UserID, &ri.GroupID, &ri.Content, &ri.Type)
+	).Scan(&ri.ID, &ri.UserID, &ri.GroupID, &ri.Content, &ri.Type, &ri.Emotion)
 
 	if err != nil {
 		d.Logger.Error("move (group) retro item error", zap.Error(err))
 		return ri, err
 	}
 
+	// an item moved out of a facilitator group no longer belongs to it
+	if _, err := d.DB.Exec(
+		`DELETE FROM thunderdome.retro_item_group WHERE item_id = $1 AND group_id <> $2;`,
+		itemID, groupID,
+	); err != nil {
+		d.Logger.Error("move (group) retro item group membership error", zap.Error(err))
+	}
+
 	return ri, nil
 }
 
@@ -60,6 +69,7 @@ func (d *Service) DeleteRetroItem(retroID string, userID string, itemType string
 		`DELETE FROM thunderdome.retro_item WHERE id = $1 AND type = $2;`, itemID, itemType); err != nil {
 		d.Logger.Error("delete retro item error", zap.Error(err))
 	}
+	d.invalidateEmotionHistogram(retroID)
 
 	items := d.GetRetroItems(retroID)
 
@@ -72,7 +82,7 @@ func (d *Service) GetRetroItems(retroID string) []*thunderdome.RetroItem {
 
 	itemRows, itemsErr := d.DB.Query(
 		`SELECT
-				ri.id, ri.user_id, ri.group_id, ri.content, ri.type,
+				ri.id, ri.user_id, ri.group_id, ri.content, ri.type, COALESCE(ri.emotion, ''),
 				COALESCE(
 					json_agg(rc ORDER BY rc.created_date) FILTER (WHERE rc.id IS NOT NULL), '[]'
 				) AS comments
@@ -90,7 +100,7 @@ func (d *Service) GetRetroItems(retroID string) []*thunderdome.RetroItem {
 			var ri = &thunderdome.RetroItem{
 				Comments: make([]*thunderdome.RetroItemComment, 0),
 			}
-			if err := itemRows.Scan(&ri.ID, &ri.UserID, &ri.GroupID, &ri.Content, &ri.Type, &comments); err != nil {
+			if err := itemRows.Scan(&ri.ID, &ri.UserID, &ri.GroupID, &ri.Content, &ri.Type, &ri.Emotion, &comments); err != nil {
 				d.Logger.Error("get retro items query scan error", zap.Error(err))
 			} else {
 				jsonErr := json.Unmarshal([]byte(comments), &ri.Comments)
@@ -112,16 +122,32 @@ func (d *Service) GetRetroGroups(retroID string) []*thunderdome.RetroGroup {
 	var groups = make([]*thunderdome.RetroGroup, 0)
 
 	itemRows, itemsErr := d.DB.Query(
-		`SELECT id, COALESCE(name, '') FROM thunderdome.retro_group WHERE retro_id = $1 ORDER BY created_date ASC;`,
+		`SELECT
+				rg.id, rg.retro_id, COALESCE(rg.name, ''), COALESCE(rg.color, ''),
+				COALESCE(
+					json_agg(rig.item_id ORDER BY rig.created_date) FILTER (WHERE rig.item_id IS NOT NULL), '[]'
+				) AS item_ids
+			FROM thunderdome.retro_group rg
+			LEFT JOIN thunderdome.retro_item_group rig ON rig.group_id = rg.id
+			WHERE rg.retro_id = $1
+			GROUP BY rg.id, rg.created_date
+			ORDER BY rg.created_date ASC;`,
 		retroID,
 	)
 	if itemsErr == nil {
 		defer itemRows.Close()
 		for itemRows.Next() {
-			var ri = &thunderdome.RetroGroup{}
-			if err := itemRows.Scan(&ri.ID, &ri.Name); err != nil {
+			var itemIDs string
+			var ri = &thunderdome.RetroGroup{
+				ItemIDs: make([]string, 0),
+			}
+			if err := itemRows.Scan(&ri.ID, &ri.RetroID, &ri.Name, &ri.Color, &itemIDs); err != nil {
 				d.Logger.Error("get retro groups query scan error", zap.Error(err))
 			} else {
+				jsonErr := json.Unmarshal([]byte(itemIDs), &ri.ItemIDs)
+				if jsonErr != nil {
+					d.Logger.Error("retro group item ids json error", zap.Error(jsonErr))
+				}
 				groups = append(groups, ri)
 			}
 		}
@@ -4,11 +4,14 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/StevenWeathers/thunderdome-planning-poker/internal/db"
 
 	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/redis/go-redis/v9"
 	"github.com/uptrace/opentelemetry-go-extra/otelzap"
 
 	"go.uber.org/zap"
@@ -19,9 +22,10 @@ type Service struct {
 	DB         *sql.DB
 	Logger     *otelzap.Logger
 	AESHashKey string
+	Redis      *redis.Client
 }
 
-func (d *Service) CreateRetro(ctx context.Context, ownerID, teamID string, retroName, joinCode, facilitatorCode string, maxVotes int, brainstormVisibility string, phaseTimeLimitMin int, phaseAutoAdvance bool, allowCumulativeVoting bool, templateID string) (*thunderdome.Retro, error) {
+func (d *Service) CreateRetro(ctx context.Context, ownerID, teamID string, retroName, joinCode, facilitatorCode string, maxVotes int, brainstormVisibility string, phaseTimeLimitMin int, phaseAutoAdvance bool, allowCumulativeVoting bool, templateID string, submissionPhase bool, submissionDeadline *time.Time) (*thunderdome.Retro, error) {
 	var encryptedFacilitatorCode string
 	var encryptedJoinCode string
 	var retro = &thunderdome.Retro{
@@ -38,6 +42,8 @@ func (d *Service) CreateRetro(ctx context.Context, ownerID, teamID string, retro
 		MaxVotes:              maxVotes,
 		TemplateID:            templateID,
 		AllowCumulativeVoting: allowCumulativeVoting,
+		SubmissionPhase:       submissionPhase,
+		SubmissionDeadline:    submissionDeadline,
 	}
 
 	if joinCode != "" {
@@ -67,12 +73,12 @@ func (d *Service) CreateRetro(ctx context.Context, ownerID, teamID string, retro
 		INSERT INTO thunderdome.retro (
 			owner_id, team_id, name, join_code, facilitator_code,
 			max_votes, brainstorm_visibility, phase_time_limit_min, phase_auto_advance,
-			allow_cumulative_voting, template_id
+			allow_cumulative_voting, template_id, submission_phase, submission_deadline
 		)
-		VALUES ($1, NULLIF($2::text, '')::uuid, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		VALUES ($1, NULLIF($2::text, '')::uuid, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 		RETURNING id, created_date, updated_date;
 	`, ownerID, teamID, retroName, encryptedJoinCode, encryptedFacilitatorCode, maxVotes, brainstormVisibility,
-		phaseTimeLimitMin, phaseAutoAdvance, allowCumulativeVoting, templateID).Scan(
+		phaseTimeLimitMin, phaseAutoAdvance, allowCumulativeVoting, templateID, submissionPhase, submissionDeadline).Scan(
 		&retro.ID, &retro.CreatedDate, &retro.UpdatedDate,
 	)
 
@@ -168,11 +174,12 @@ func (d *Service) RetroGetByID(retroID string, userID string) (*thunderdome.Retr
 			r.id, r.name, r.owner_id, COALESCE(r.team_id::TEXT, ''), r.phase, r.phase_time_limit_min, r.phase_time_start, r.phase_auto_advance,
 			 COALESCE(r.join_code, ''), COALESCE(r.facilitator_code, ''), r.allow_cumulative_voting,
 			r.max_votes, r.brainstorm_visibility, r.ready_users, r.created_date, r.updated_date, r.template_id,
+			r.submission_phase, r.submission_deadline, r.active_item_id::TEXT, r.parent_retro_id::TEXT,
 			CASE WHEN COUNT(rf) = 0 THEN '[]'::json ELSE array_to_json(array_agg(rf.user_id)) END AS facilitators,
 			(SELECT row_to_json(t.*) as template FROM thunderdome.retro_template t WHERE t.id = r.template_id) AS template
 		FROM thunderdome.retro r
 		LEFT JOIN thunderdome.retro_facilitator rf ON r.id = rf.retro_id
-		WHERE r.id = $1
+		WHERE r.id = $1 AND r.deleted_at IS NULL
 		GROUP BY r.id`,
 		retroID,
 	).Scan(
@@ -193,6 +200,10 @@ func (d *Service) RetroGetByID(retroID string, userID string) (*thunderdome.Retr
 		&b.CreatedDate,
 		&b.UpdatedDate,
 		&b.TemplateID,
+		&b.SubmissionPhase,
+		&b.SubmissionDeadline,
+		&b.ActiveItemID,
+		&b.ParentRetroID,
 		&facilitators,
 		&template,
 	)
@@ -235,10 +246,20 @@ func (d *Service) RetroGetByID(retroID string, userID string) (*thunderdome.Retr
 	}
 
 	b.Items = d.GetRetroItems(retroID)
+	if thunderdome.SubmissionPhaseActive(b.SubmissionPhase, b.SubmissionDeadline, time.Now()) {
+		b.Items = thunderdome.SubmissionVisibleItems(b.Items, userID, isFacilitator)
+	}
+	b.Items = thunderdome.FocusVisibleItems(b.Items, b.ActiveItemID, isFacilitator)
 	b.Groups = d.GetRetroGroups(retroID)
 	b.Users = d.RetroGetUsers(retroID)
 	b.ActionItems = d.GetRetroActions(retroID)
 	b.Votes = d.GetRetroVotes(retroID)
+	timers, timersErr := d.GetRetroTimers(context.Background(), retroID)
+	if timersErr != nil {
+		d.Logger.Error("get retro timers error", zap.Error(timersErr))
+		timers = make([]*thunderdome.RetroTimer, 0)
+	}
+	b.Timers = timers
 
 	return b, nil
 }
@@ -264,7 +285,7 @@ func (d *Service) RetroGetByUser(userID string, limit int, offset int) ([]*thund
 		retros AS (
 			SELECT id from user_retros UNION SELECT id FROM team_retros
 		)
-		SELECT COUNT(*) FROM retros;
+		SELECT COUNT(*) FROM thunderdome.retro r WHERE r.id IN (SELECT id FROM retros) AND r.deleted_at IS NULL;
 	`, userID).Scan(
 		&count,
 	)
@@ -294,7 +315,7 @@ func (d *Service) RetroGetByUser(userID string, limit int, offset int) ([]*thund
 		  (SELECT row_to_json(t.*) as template FROM thunderdome.retro_template t WHERE t.id = r.template_id) AS template
 		FROM thunderdome.retro r
 		LEFT JOIN user_teams t ON t.id = r.team_id
-		WHERE r.id IN (SELECT id FROM retros)
+		WHERE r.id IN (SELECT id FROM retros) AND r.deleted_at IS NULL
 		GROUP BY r.id, r.created_date ORDER BY r.created_date DESC LIMIT $2 OFFSET $3;
 	`, userID, limit, offset)
 	if retrosErr != nil {
@@ -338,15 +359,16 @@ func (d *Service) RetroGetByUser(userID string, limit int, offset int) ([]*thund
 	return retros, count, nil
 }
 
-// RetroAdvancePhase sets the phase for the retro
+// RetroAdvancePhase sets the phase for the retro, any item focus is removed
 func (d *Service) RetroAdvancePhase(retroID string, phase string) (*thunderdome.Retro, error) {
 	var b thunderdome.Retro
 	err := d.DB.QueryRow(
 		`UPDATE thunderdome.retro
-			SET updated_date = NOW(), phase = $2, phase_time_start = NOW(), ready_users = '[]'::jsonb
-			WHERE id = $1 RETURNING name, phase_time_start, template_id;`,
+			SET updated_date = NOW(), phase = $2, phase_time_start = NOW(), ready_users = '[]'::jsonb,
+			active_item_id = NULL
+			WHERE id = $1 RETURNING name, phase_time_start, template_id, submission_phase, submission_deadline;`,
 		retroID, phase,
-	).Scan(&b.Name, &b.PhaseTimeStart, &b.TemplateID)
+	).Scan(&b.Name, &b.PhaseTimeStart, &b.TemplateID, &b.SubmissionPhase, &b.SubmissionDeadline)
 	if err != nil {
 		return nil, fmt.Errorf("retro advance phase query error: %v", err)
 	}
@@ -377,7 +399,7 @@ func (d *Service) GetRetros(limit int, offset int) ([]*thunderdome.Retro, int, e
 	var count int
 
 	err := d.DB.QueryRow(
-		"SELECT COUNT(*) FROM thunderdome.retro;",
+		"SELECT COUNT(*) FROM thunderdome.retro WHERE deleted_at IS NULL;",
 	).Scan(
 		&count,
 	)
@@ -390,6 +412,7 @@ func (d *Service) GetRetros(limit int, offset int) ([]*thunderdome.Retro, int, e
 		 r.created_date, r.updated_date, r.template_id,
 		 (SELECT row_to_json(t.*) as template FROM thunderdome.retro_template t WHERE t.id = r.template_id) AS template
 		FROM thunderdome.retro r
+		WHERE r.deleted_at IS NULL
 		GROUP BY r.id ORDER BY r.created_date DESC
 		LIMIT $1 OFFSET $2;
 	`, limit, offset)
@@ -437,7 +460,9 @@ func (d *Service) GetActiveRetros(limit int, offset int) ([]*thunderdome.Retro,
 	var count int
 
 	err := d.DB.QueryRow(
-		"SELECT COUNT(DISTINCT ru.retro_id) FROM thunderdome.retro_user ru WHERE ru.active IS TRUE;",
+		`SELECT COUNT(DISTINCT ru.retro_id) FROM thunderdome.retro_user ru
+		JOIN thunderdome.retro r ON r.id = ru.retro_id
+		WHERE ru.active IS TRUE AND r.deleted_at IS NULL;`,
 	).Scan(
 		&count,
 	)
@@ -451,7 +476,7 @@ func (d *Service) GetActiveRetros(limit int, offset int) ([]*thunderdome.Retro,
 		r.template_id, (SELECT row_to_json(t.*) as template FROM thunderdome.retro_template t WHERE t.id = r.template_id) AS template
 		FROM thunderdome.retro_user ru
 		LEFT JOIN thunderdome.retro r ON r.id = ru.retro_id
-		WHERE ru.active IS TRUE GROUP BY r.id
+		WHERE ru.active IS TRUE AND r.deleted_at IS NULL GROUP BY r.id
 		LIMIT $1 OFFSET $2;
 	`, limit, offset)
 	if retrosErr != nil {
@@ -491,3 +516,82 @@ func (d *Service) GetActiveRetros(limit int, offset int) ([]*thunderdome.Retro,
 
 	return retros, count, nil
 }
+
+// GetRetroSubmissionPhase gets whether the retro is in its submission phase and the submission deadline
+func (d *Service) GetRetroSubmissionPhase(retroID string) (bool, *time.Time, error) {
+	var submissionPhase bool
+	var submissionDeadline *time.Time
+
+	err := d.DB.QueryRow(
+		`SELECT submission_phase, submission_deadline FROM thunderdome.retro WHERE id = $1 AND deleted_at IS NULL;`,
+		retroID,
+	).Scan(&submissionPhase, &submissionDeadline)
+	if err != nil {
+		return false, nil, fmt.Errorf("get retro submission phase query error: %v", err)
+	}
+
+	return submissionPhase, submissionDeadline, nil
+}
+
+// OpenRetroSession ends the retro submission phase revealing all items
+func (d *Service) OpenRetroSession(ctx context.Context, retroID string) error {
+	result, err := d.DB.ExecContext(ctx,
+		`UPDATE thunderdome.retro SET submission_phase = false, updated_date = NOW()
+		WHERE id = $1 AND submission_phase = true;`,
+		retroID,
+	)
+	if err != nil {
+		return fmt.Errorf("open retro session query error: %v", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("open retro session rows affected error: %v", err)
+	}
+	if rows == 0 {
+		return errors.New("RETRO_SESSION_ALREADY_OPEN")
+	}
+
+	return nil
+}
+
+// SetFocusItem sets the retro item currently being reviewed, an empty itemID removes the focus
+func (d *Service) SetFocusItem(ctx context.Context, retroID string, itemID string) error {
+	if itemID != "" {
+		var exists bool
+		err := d.DB.QueryRowContext(ctx,
+			`SELECT EXISTS(SELECT 1 FROM thunderdome.retro_item WHERE id = $1 AND retro_id = $2);`,
+			itemID, retroID,
+		).Scan(&exists)
+		if err != nil {
+			return fmt.Errorf("set retro focus item query error: %v", err)
+		}
+		if !exists {
+			return errors.New("RETRO_ITEM_NOT_FOUND")
+		}
+	}
+
+	if _, err := d.DB.ExecContext(ctx,
+		`UPDATE thunderdome.retro SET active_item_id = NULLIF($2, '')::uuid, updated_date = NOW() WHERE id = $1;`,
+		retroID, itemID,
+	); err != nil {
+		return fmt.Errorf("set retro focus item query error: %v", err)
+	}
+
+	return nil
+}
+
+// GetRetroFocusItem gets the ID of the retro item currently being reviewed, nil when the retro isn't focused
+func (d *Service) GetRetroFocusItem(retroID string) (*string, error) {
+	var activeItemID *string
+
+	err := d.DB.QueryRow(
+		`SELECT active_item_id::TEXT FROM thunderdome.retro WHERE id = $1 AND deleted_at IS NULL;`,
+		retroID,
+	).Scan(&activeItemID)
+	if err != nil {
+		return nil, fmt.Errorf("get retro focus item query error: %v", err)
+	}
+
+	return activeItemID, nil
+}
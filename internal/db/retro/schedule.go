@@ -0,0 +1,194 @@
+package retro
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// retroScheduleColumns are the retro_schedule columns scanned by scanRetroSchedule
+const retroScheduleColumns = `id, team_id, created_by, name, COALESCE(template_id::text, ''), max_votes,
+	brainstorm_visibility, recurrence_cron, last_window_date, created_date, updated_date`
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanRetroSchedule scans a row selected with retroScheduleColumns
+func scanRetroSchedule(row rowScanner) (*thunderdome.RetroSchedule, error) {
+	schedule := thunderdome.RetroSchedule{}
+	var lastWindowDate sql.NullTime
+
+	if err := row.Scan(
+		&schedule.ID,
+		&schedule.TeamID,
+		&schedule.CreatedBy,
+		&schedule.Name,
+		&schedule.TemplateID,
+		&schedule.MaxVotes,
+		&schedule.BrainstormVisibility,
+		&schedule.RecurrenceCron,
+		&lastWindowDate,
+		&schedule.CreatedDate,
+		&schedule.UpdatedDate,
+	); err != nil {
+		return nil, err
+	}
+	if lastWindowDate.Valid {
+		schedule.LastWindowDate = &lastWindowDate.Time
+	}
+
+	return &schedule, nil
+}
+
+// GetRetroSchedules gets the team's recurring retro schedules
+func (d *Service) GetRetroSchedules(ctx context.Context, teamID string) ([]*thunderdome.RetroSchedule, error) {
+	schedules := make([]*thunderdome.RetroSchedule, 0)
+
+	rows, err := d.DB.QueryContext(ctx,
+		`SELECT `+retroScheduleColumns+`
+		FROM thunderdome.retro_schedule WHERE team_id = $1 ORDER BY name;`,
+		teamID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get retro schedules query error: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		schedule, err := scanRetroSchedule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("get retro schedules scan error: %v", err)
+		}
+		schedules = append(schedules, schedule)
+	}
+
+	return schedules, nil
+}
+
+// GetRetroScheduleByID gets the team's recurring retro schedule
+func (d *Service) GetRetroScheduleByID(ctx context.Context, teamID string, scheduleID string) (*thunderdome.RetroSchedule, error) {
+	schedule, err := scanRetroSchedule(d.DB.QueryRowContext(ctx,
+		`SELECT `+retroScheduleColumns+`
+		FROM thunderdome.retro_schedule WHERE team_id = $1 AND id = $2;`,
+		teamID, scheduleID,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("RETRO_SCHEDULE_NOT_FOUND")
+		}
+		return nil, fmt.Errorf("get retro schedule query error: %v", err)
+	}
+
+	return schedule, nil
+}
+
+// CreateRetroSchedule creates a recurring retro schedule for the team, the user facilitates the retros created from it
+func (d *Service) CreateRetroSchedule(ctx context.Context, teamID string, userID string, schedule *thunderdome.RetroSchedule) (*thunderdome.RetroSchedule, error) {
+	var scheduleID string
+	err := d.DB.QueryRowContext(ctx,
+		`INSERT INTO thunderdome.retro_schedule (
+			team_id, created_by, name, template_id, max_votes, brainstorm_visibility, recurrence_cron
+		) VALUES ($1, $2, $3, NULLIF($4, '')::uuid, $5, $6, $7)
+		RETURNING id;`,
+		teamID, userID, schedule.Name, schedule.TemplateID, schedule.MaxVotes, schedule.BrainstormVisibility,
+		schedule.RecurrenceCron,
+	).Scan(&scheduleID)
+	if err != nil {
+		return nil, fmt.Errorf("create retro schedule query error: %v", err)
+	}
+
+	return d.GetRetroScheduleByID(ctx, teamID, scheduleID)
+}
+
+// UpdateRetroSchedule updates the team's recurring retro schedule
+func (d *Service) UpdateRetroSchedule(ctx context.Context, teamID string, scheduleID string, schedule *thunderdome.RetroSchedule) (*thunderdome.RetroSchedule, error) {
+	result, err := d.DB.ExecContext(ctx,
+		`UPDATE thunderdome.retro_schedule SET name = $3, template_id = NULLIF($4, '')::uuid, max_votes = $5,
+			brainstorm_visibility = $6, recurrence_cron = $7, updated_date = NOW()
+		WHERE team_id = $1 AND id = $2;`,
+		teamID, scheduleID, schedule.Name, schedule.TemplateID, schedule.MaxVotes, schedule.BrainstormVisibility,
+		schedule.RecurrenceCron,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("update retro schedule query error: %v", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return nil, errors.New("RETRO_SCHEDULE_NOT_FOUND")
+	}
+
+	return d.GetRetroScheduleByID(ctx, teamID, scheduleID)
+}
+
+// DeleteRetroSchedule deletes the team's recurring retro schedule, retros created from it are kept
+func (d *Service) DeleteRetroSchedule(ctx context.Context, teamID string, scheduleID string) error {
+	result, err := d.DB.ExecContext(ctx,
+		`DELETE FROM thunderdome.retro_schedule WHERE team_id = $1 AND id = $2;`,
+		teamID, scheduleID,
+	)
+	if err != nil {
+		return fmt.Errorf("delete retro schedule query error: %v", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return errors.New("RETRO_SCHEDULE_NOT_FOUND")
+	}
+
+	return nil
+}
+
+// GetRecurringRetroSchedules gets every team's recurring retro schedules
+func (d *Service) GetRecurringRetroSchedules(ctx context.Context) ([]*thunderdome.RetroSchedule, error) {
+	schedules := make([]*thunderdome.RetroSchedule, 0)
+
+	rows, err := d.DB.QueryContext(ctx,
+		`SELECT `+retroScheduleColumns+` FROM thunderdome.retro_schedule;`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get recurring retro schedules query error: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		schedule, err := scanRetroSchedule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("get recurring retro schedules scan error: %v", err)
+		}
+		schedules = append(schedules, schedule)
+	}
+
+	return schedules, nil
+}
+
+// ClaimRetroScheduleWindow records the window a retro is being created for, returning false when a retro
+// was already created for that window (or a later one), the conditional update keeps two servers
+// evaluating the same schedule from both creating the retro
+func (d *Service) ClaimRetroScheduleWindow(ctx context.Context, scheduleID string, windowStart time.Time) (bool, error) {
+	result, err := d.DB.ExecContext(ctx,
+		`UPDATE thunderdome.retro_schedule SET last_window_date = $2
+		WHERE id = $1 AND (last_window_date IS NULL OR last_window_date < $2);`,
+		scheduleID, windowStart,
+	)
+	if err != nil {
+		return false, fmt.Errorf("claim retro schedule window query error: %v", err)
+	}
+	rows, _ := result.RowsAffected()
+
+	return rows > 0, nil
+}
+
+// SetRetroScheduleID records the schedule the retro was created from
+func (d *Service) SetRetroScheduleID(ctx context.Context, retroID string, scheduleID string) error {
+	if _, err := d.DB.ExecContext(ctx,
+		`UPDATE thunderdome.retro SET schedule_id = $2 WHERE id = $1;`,
+		retroID, scheduleID,
+	); err != nil {
+		return fmt.Errorf("set retro schedule query error: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package retro
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// SoftDeleteRetro marks the retro deleted, it can be restored until it's permanently deleted
+// after thunderdome.RetroSoftDeleteRetentionDays
+func (d *Service) SoftDeleteRetro(ctx context.Context, retroID string) error {
+	result, err := d.DB.ExecContext(ctx,
+		`UPDATE thunderdome.retro SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL;`,
+		retroID,
+	)
+	if err != nil {
+		return fmt.Errorf("soft delete retro query error: %v", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("RETRO_NOT_FOUND")
+	}
+
+	return nil
+}
+
+// SoftDeleteOldRetros marks retros not updated in {daysOld} days deleted
+func (d *Service) SoftDeleteOldRetros(ctx context.Context, daysOld int) (*thunderdome.RetroCleanupResult, error) {
+	result := &thunderdome.RetroCleanupResult{
+		RetroIDs: make([]string, 0),
+	}
+
+	rows, err := d.DB.QueryContext(ctx,
+		`UPDATE thunderdome.retro SET deleted_at = NOW()
+		WHERE updated_date < (NOW() - $1 * interval '1 day') AND deleted_at IS NULL
+		RETURNING id;`,
+		daysOld,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("soft delete old retros query error: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var retroID string
+		if err := rows.Scan(&retroID); err != nil {
+			return nil, fmt.Errorf("soft delete old retros query scan error: %v", err)
+		}
+		result.RetroIDs = append(result.RetroIDs, retroID)
+	}
+	result.RetroCount = len(result.RetroIDs)
+
+	return result, nil
+}
+
+// RestoreRetro restores a soft deleted retro that hasn't been permanently deleted yet
+func (d *Service) RestoreRetro(ctx context.Context, retroID string) error {
+	result, err := d.DB.ExecContext(ctx,
+		`UPDATE thunderdome.retro SET deleted_at = NULL
+		WHERE id = $1 AND deleted_at IS NOT NULL AND deleted_at >= (NOW() - $2 * interval '1 day');`,
+		retroID, thunderdome.RetroSoftDeleteRetentionDays,
+	)
+	if err != nil {
+		return fmt.Errorf("restore retro query error: %v", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("RETRO_NOT_FOUND")
+	}
+
+	return nil
+}
+
+// PurgeDeletedRetros permanently deletes retros soft deleted more than thunderdome.RetroSoftDeleteRetentionDays ago
+func (d *Service) PurgeDeletedRetros(ctx context.Context) (int64, error) {
+	result, err := d.DB.ExecContext(ctx,
+		`DELETE FROM thunderdome.retro WHERE deleted_at < (NOW() - $1 * interval '1 day');`,
+		thunderdome.RetroSoftDeleteRetentionDays,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("purge deleted retros query error: %v", err)
+	}
+
+	deleted, _ := result.RowsAffected()
+
+	return deleted, nil
+}
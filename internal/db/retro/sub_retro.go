@@ -0,0 +1,276 @@
+package retro
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+// subRetroItemsQuery selects the items of every retro nested below the parent retro,
+// the depth bound keeps the recursion from running away should the parent chain be circular
+const subRetroItemsQuery = `WITH RECURSIVE sub_retros AS (
+		SELECT r.id, 1 AS depth FROM thunderdome.retro r
+		WHERE r.parent_retro_id = $1 AND r.deleted_at IS NULL
+		UNION
+		SELECT r.id, sr.depth + 1 FROM thunderdome.retro r
+		JOIN sub_retros sr ON r.parent_retro_id = sr.id
+		WHERE r.deleted_at IS NULL AND sr.depth < $2
+	)
+	SELECT ri.user_id, ri.type, ri.content, COALESCE(ri.emotion, '')
+	FROM thunderdome.retro_item ri
+	WHERE ri.retro_id IN (SELECT id FROM sub_retros WHERE id <> $1)
+	ORDER BY ri.created_date ASC;`
+
+// getRetroParents gets the retro's parent chain as a map of retro ID to parent retro ID
+func (d *Service) getRetroParents(ctx context.Context, retroID string) (map[string]string, error) {
+	rows, err := d.DB.QueryContext(ctx,
+		`WITH RECURSIVE ancestors AS (
+			SELECT r.id, r.parent_retro_id, 0 AS depth FROM thunderdome.retro r
+			WHERE r.id = $1 AND r.deleted_at IS NULL
+			UNION
+			SELECT r.id, r.parent_retro_id, a.depth + 1 FROM thunderdome.retro r
+			JOIN ancestors a ON r.id = a.parent_retro_id
+			WHERE a.depth <= $2
+		)
+		SELECT id, COALESCE(parent_retro_id::TEXT, '') FROM ancestors;`,
+		retroID, thunderdome.MaxSubRetroDepth+1,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get retro parents query error: %v", err)
+	}
+	defer rows.Close()
+
+	parents := make(map[string]string)
+	for rows.Next() {
+		var id, parentID string
+		if err := rows.Scan(&id, &parentID); err != nil {
+			return nil, fmt.Errorf("get retro parents row scan error: %v", err)
+		}
+		parents[id] = parentID
+	}
+	if _, ok := parents[retroID]; !ok {
+		return nil, fmt.Errorf("RETRO_NOT_FOUND")
+	}
+
+	return parents, nil
+}
+
+// scanSubRetroItems scans the rows of the sub retro items query
+func scanSubRetroItems(rows *sql.Rows) ([]*thunderdome.RetroItem, error) {
+	defer rows.Close()
+
+	items := make([]*thunderdome.RetroItem, 0)
+	for rows.Next() {
+		item := &thunderdome.RetroItem{}
+		if err := rows.Scan(&item.UserID, &item.Type, &item.Content, &item.Emotion); err != nil {
+			return nil, fmt.Errorf("get sub retro items row scan error: %v", err)
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// CreateSubRetro creates a retro nested below the parent retro for a sub group of the parent's team,
+// the sub retro uses the parent's template and settings with the facilitator as its owner
+func (d *Service) CreateSubRetro(ctx context.Context, parentRetroID string, facilitatorID string, name string) (*thunderdome.Retro, error) {
+	parents, err := d.getRetroParents(ctx, parentRetroID)
+	if err != nil {
+		return nil, err
+	}
+	if err := thunderdome.ValidateSubRetroParent(parentRetroID, parents); err != nil {
+		return nil, err
+	}
+
+	var retro = &thunderdome.Retro{
+		OwnerID:       facilitatorID,
+		Name:          name,
+		Phase:         "intro",
+		ParentRetroID: &parentRetroID,
+		Users:         make([]*thunderdome.RetroUser, 0),
+		Items:         make([]*thunderdome.RetroItem, 0),
+		ActionItems:   make([]*thunderdome.RetroAction, 0),
+	}
+
+	tx, err := d.DB.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+	if err != nil {
+		d.Logger.Error("create sub retro error", zap.Error(err))
+		return nil, fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO thunderdome.retro (
+			owner_id, team_id, name, join_code, facilitator_code,
+			max_votes, brainstorm_visibility, phase_time_limit_min, phase_auto_advance,
+			allow_cumulative_voting, template_id, parent_retro_id
+		)
+		SELECT $2, p.team_id, $3, p.join_code, p.facilitator_code,
+			p.max_votes, p.brainstorm_visibility, p.phase_time_limit_min, p.phase_auto_advance,
+			p.allow_cumulative_voting, p.template_id, p.id
+		FROM thunderdome.retro p
+		WHERE p.id = $1 AND p.deleted_at IS NULL
+		RETURNING id, COALESCE(team_id::TEXT, ''), max_votes, brainstorm_visibility, phase_time_limit_min,
+			phase_auto_advance, allow_cumulative_voting, template_id, created_date, updated_date;
+	`, parentRetroID, facilitatorID, name).Scan(
+		&retro.ID, &retro.TeamID, &retro.MaxVotes, &retro.BrainstormVisibility, &retro.PhaseTimeLimitMin,
+		&retro.PhaseAutoAdvance, &retro.AllowCumulativeVoting, &retro.TemplateID, &retro.CreatedDate, &retro.UpdatedDate,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("RETRO_NOT_FOUND")
+	}
+	if err != nil {
+		d.Logger.Error("create sub retro error", zap.Error(err),
+			zap.String("parent_retro_id", parentRetroID), zap.String("facilitator_id", facilitatorID))
+		return nil, fmt.Errorf("failed to insert into retro table: %v", err)
+	}
+
+	if _, err = tx.ExecContext(ctx, `
+		INSERT INTO thunderdome.retro_facilitator (retro_id, user_id)
+		VALUES ($1, $2)
+	`, retro.ID, facilitatorID); err != nil {
+		d.Logger.Error("create sub retro error", zap.Error(err))
+		return nil, fmt.Errorf("failed to insert into retro_facilitator table: %v", err)
+	}
+
+	if _, err = tx.ExecContext(ctx, `
+		INSERT INTO thunderdome.retro_user (retro_id, user_id)
+		VALUES ($1, $2)
+	`, retro.ID, facilitatorID); err != nil {
+		d.Logger.Error("create sub retro error", zap.Error(err))
+		return nil, fmt.Errorf("failed to insert into retro_user table: %v", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		d.Logger.Error("create sub retro error", zap.Error(err))
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return retro, nil
+}
+
+// GetSubRetroSummary gets the retros nested directly below the parent retro
+// along with the item counts aggregated from every retro nested below it
+func (d *Service) GetSubRetroSummary(ctx context.Context, parentRetroID string) (*thunderdome.SubRetroSummary, error) {
+	if _, err := d.getRetroParents(ctx, parentRetroID); err != nil {
+		return nil, err
+	}
+
+	summary := &thunderdome.SubRetroSummary{
+		ParentRetroID: parentRetroID,
+		SubRetros:     make([]*thunderdome.Retro, 0),
+	}
+
+	rows, err := d.DB.QueryContext(ctx,
+		`SELECT r.id, r.name, r.owner_id, COALESCE(r.team_id::TEXT, ''), r.phase,
+			r.parent_retro_id::TEXT, r.created_date, r.updated_date
+		FROM thunderdome.retro r
+		WHERE r.parent_retro_id = $1 AND r.deleted_at IS NULL
+		ORDER BY r.created_date ASC;`,
+		parentRetroID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get sub retros query error: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		retro := &thunderdome.Retro{}
+		if err := rows.Scan(
+			&retro.ID, &retro.Name, &retro.OwnerID, &retro.TeamID, &retro.Phase,
+			&retro.ParentRetroID, &retro.CreatedDate, &retro.UpdatedDate,
+		); err != nil {
+			return nil, fmt.Errorf("get sub retros row scan error: %v", err)
+		}
+		summary.SubRetros = append(summary.SubRetros, retro)
+	}
+
+	itemRows, err := d.DB.QueryContext(ctx, subRetroItemsQuery, parentRetroID, thunderdome.MaxSubRetroDepth)
+	if err != nil {
+		return nil, fmt.Errorf("get sub retro items query error: %v", err)
+	}
+	items, err := scanSubRetroItems(itemRows)
+	if err != nil {
+		return nil, err
+	}
+	summary.ItemCount, summary.ItemCountsByType = thunderdome.CountSubRetroItems(items)
+
+	return summary, nil
+}
+
+// MergeSubRetros copies the items of every retro nested below the parent retro into the parent retro,
+// each merged item keeps its author and items the author already has in the parent are skipped
+func (d *Service) MergeSubRetros(ctx context.Context, parentRetroID string) error {
+	tx, err := d.DB.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+	if err != nil {
+		return fmt.Errorf("merge sub retros failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	if err := tx.QueryRowContext(ctx,
+		`SELECT EXISTS(SELECT 1 FROM thunderdome.retro WHERE id = $1 AND deleted_at IS NULL);`,
+		parentRetroID,
+	).Scan(&exists); err != nil {
+		return fmt.Errorf("merge sub retros query error: %v", err)
+	}
+	if !exists {
+		return fmt.Errorf("RETRO_NOT_FOUND")
+	}
+
+	parentRows, err := tx.QueryContext(ctx,
+		`SELECT user_id, type, content FROM thunderdome.retro_item WHERE retro_id = $1;`,
+		parentRetroID,
+	)
+	if err != nil {
+		return fmt.Errorf("merge sub retros parent items query error: %v", err)
+	}
+	parentItems := make([]*thunderdome.RetroItem, 0)
+	for parentRows.Next() {
+		item := &thunderdome.RetroItem{}
+		if err := parentRows.Scan(&item.UserID, &item.Type, &item.Content); err != nil {
+			parentRows.Close()
+			return fmt.Errorf("merge sub retros parent items row scan error: %v", err)
+		}
+		parentItems = append(parentItems, item)
+	}
+	parentRows.Close()
+
+	itemRows, err := tx.QueryContext(ctx, subRetroItemsQuery, parentRetroID, thunderdome.MaxSubRetroDepth)
+	if err != nil {
+		return fmt.Errorf("merge sub retros items query error: %v", err)
+	}
+	subRetroItems, err := scanSubRetroItems(itemRows)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range thunderdome.MergeableSubRetroItems(parentItems, subRetroItems) {
+		var groupID string
+		if err := tx.QueryRowContext(ctx,
+			`INSERT INTO thunderdome.retro_group (retro_id) VALUES ($1) RETURNING id;`,
+			parentRetroID,
+		).Scan(&groupID); err != nil {
+			return fmt.Errorf("merge sub retros insert group error: %v", err)
+		}
+
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO thunderdome.retro_item (retro_id, group_id, type, content, user_id, emotion)
+			VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''));`,
+			parentRetroID, groupID, item.Type, item.Content, item.UserID, item.Emotion,
+		); err != nil {
+			return fmt.Errorf("merge sub retros insert item error: %v", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("merge sub retros failed to commit transaction: %v", err)
+	}
+	d.invalidateEmotionHistogram(parentRetroID)
+
+	return nil
+}
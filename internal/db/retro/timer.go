@@ -0,0 +1,172 @@
+package retro
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+// timerEventsChannel is the redis pub/sub channel retro timer events are published to
+const timerEventsChannel = "retro:timer"
+
+// GetRetroTimers gets the retro's category timers
+func (d *Service) GetRetroTimers(ctx context.Context, retroID string) ([]*thunderdome.RetroTimer, error) {
+	timers := make([]*thunderdome.RetroTimer, 0)
+
+	rows, err := d.DB.QueryContext(ctx,
+		`SELECT category_id, duration_seconds, started_at, paused_at
+		FROM thunderdome.retro_category_timer
+		WHERE retro_id = $1
+		ORDER BY category_id;`,
+		retroID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get retro timers query error: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var t thunderdome.RetroTimer
+		if err := rows.Scan(&t.CategoryID, &t.DurationSeconds, &t.StartedAt, &t.PausedAt); err != nil {
+			d.Logger.Ctx(ctx).Error("get retro timers query scan error", zap.Error(err))
+			continue
+		}
+		timers = append(timers, &t)
+	}
+
+	return timers, nil
+}
+
+// StartCategoryTimer starts the retro category timer with the duration, or resumes it when paused
+func (d *Service) StartCategoryTimer(ctx context.Context, retroID string, categoryID string, durationSeconds int) (*thunderdome.RetroTimer, error) {
+	timer, err := d.updateCategoryTimer(ctx, retroID, categoryID, func(t *thunderdome.RetroTimer, now time.Time) error {
+		return t.Start(durationSeconds, now)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	d.publishTimerEvent(ctx, thunderdome.RetroTimerEventStart, retroID, timer)
+
+	return timer, nil
+}
+
+// PauseCategoryTimer pauses the running retro category timer
+func (d *Service) PauseCategoryTimer(ctx context.Context, retroID string, categoryID string) (*thunderdome.RetroTimer, error) {
+	timer, err := d.updateCategoryTimer(ctx, retroID, categoryID, func(t *thunderdome.RetroTimer, now time.Time) error {
+		return t.Pause(now)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	d.publishTimerEvent(ctx, thunderdome.RetroTimerEventPause, retroID, timer)
+
+	return timer, nil
+}
+
+// updateCategoryTimer applies the change to the retro category timer, creating the timer if it doesn't exist yet
+func (d *Service) updateCategoryTimer(ctx context.Context, retroID string, categoryID string, change func(t *thunderdome.RetroTimer, now time.Time) error) (*thunderdome.RetroTimer, error) {
+	tx, err := d.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("update retro timer begin transaction error: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	timer := &thunderdome.RetroTimer{CategoryID: categoryID}
+	err = tx.QueryRowContext(ctx,
+		`SELECT duration_seconds, started_at, paused_at
+		FROM thunderdome.retro_category_timer
+		WHERE retro_id = $1 AND category_id = $2 FOR UPDATE;`,
+		retroID, categoryID,
+	).Scan(&timer.DurationSeconds, &timer.StartedAt, &timer.PausedAt)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("get retro timer query error: %v", err)
+	}
+
+	if err := change(timer, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO thunderdome.retro_category_timer
+		(retro_id, category_id, duration_seconds, started_at, paused_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (retro_id, category_id) DO UPDATE
+		SET duration_seconds = EXCLUDED.duration_seconds, started_at = EXCLUDED.started_at,
+			paused_at = EXCLUDED.paused_at, updated_date = NOW();`,
+		retroID, categoryID, timer.DurationSeconds, timer.StartedAt, timer.PausedAt,
+	); err != nil {
+		return nil, fmt.Errorf("update retro timer query error: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("update retro timer commit error: %v", err)
+	}
+
+	return timer, nil
+}
+
+// publishTimerEvent publishes the retro timer event to every instance, failures are only logged
+// as the instance handling the change still relays the timer to its own participants
+func (d *Service) publishTimerEvent(ctx context.Context, eventType string, retroID string, timer *thunderdome.RetroTimer) {
+	if d.Redis == nil {
+		return
+	}
+
+	payload, err := json.Marshal(thunderdome.RetroTimerEvent{Type: eventType, RetroID: retroID, Timer: *timer})
+	if err != nil {
+		d.Logger.Ctx(ctx).Error("retro timer event json error", zap.Error(err))
+		return
+	}
+
+	if err := d.Redis.Publish(ctx, timerEventsChannel, payload).Err(); err != nil {
+		d.Logger.Ctx(ctx).Error("publish retro timer event error", zap.Error(err),
+			zap.String("retro_id", retroID), zap.String("event_type", eventType))
+	}
+}
+
+// SubscribeTimerEvents receives the retro timer events published by every instance until the context is done,
+// without redis there is nothing to subscribe to and a nil channel is returned
+func (d *Service) SubscribeTimerEvents(ctx context.Context) <-chan thunderdome.RetroTimerEvent {
+	if d.Redis == nil {
+		return nil
+	}
+
+	pubsub := d.Redis.Subscribe(ctx, timerEventsChannel)
+	events := make(chan thunderdome.RetroTimerEvent)
+
+	go func() {
+		defer close(events)
+		defer pubsub.Close()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-pubsub.Channel():
+				if !ok {
+					return
+				}
+				var event thunderdome.RetroTimerEvent
+				if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+					d.Logger.Ctx(ctx).Error("retro timer event json error", zap.Error(err))
+					continue
+				}
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return events
+}
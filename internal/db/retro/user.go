@@ -16,7 +16,7 @@ func (d *Service) RetroGetUsers(retroID string) []*thunderdome.RetroUser {
 	var users = make([]*thunderdome.RetroUser, 0)
 	rows, err := d.DB.Query(
 		`SELECT
-			u.id, u.name, su.active, u.avatar, COALESCE(u.email, ''), COALESCE(u.picture, '')
+			u.id, CASE WHEN u.anonymized_at IS NOT NULL THEN 'Anonymous' ELSE u.name END, su.active, u.avatar, COALESCE(u.email, ''), COALESCE(u.picture, '')
 		FROM thunderdome.retro_user su
 		LEFT JOIN thunderdome.users u ON su.user_id = u.id
 		WHERE su.retro_id = $1
@@ -91,7 +91,7 @@ func (d *Service) GroupUserVote(retroID string, groupID string, userID string) (
 				FROM thunderdome.retro_group_vote rgv
 				WHERE rgv.retro_id = $1 AND rgv.user_id = $2 LIMIT 1), '[]'::jsonb) as votes
 				FROM thunderdome.retro r
-				WHERE r.id = $1;`,
+				WHERE r.id = $1 AND r.deleted_at IS NULL;`,
 		retroID, userID,
 	).Scan(&maxVotes, &allowCumulativeVoting, &phase, &votesString)
 	if err != nil {
@@ -135,7 +135,7 @@ func (d *Service) GroupUserVote(retroID string, groupID string, userID string) (
 				  AND EXISTS (
 					SELECT 1
 					FROM thunderdome.retro
-					WHERE id = $1 AND allow_cumulative_voting = true
+					WHERE id = $1 AND allow_cumulative_voting = true AND deleted_at IS NULL
   			);`,
 		retroID, groupID, userID,
 	)
@@ -179,7 +179,7 @@ func (d *Service) GroupUserSubtractVote(retroID string, groupID string, userID s
         SELECT r.allow_cumulative_voting, COALESCE(rgv.vote_count, 0)
         FROM thunderdome.retro r
         LEFT JOIN thunderdome.retro_group_vote rgv ON r.id = rgv.retro_id AND rgv.group_id = $2 AND rgv.user_id = $3
-        WHERE r.id = $1
+        WHERE r.id = $1 AND r.deleted_at IS NULL
     `, retroID, groupID, userID).Scan(&allowCumulativeVoting, &currentVoteCount)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get retro and vote information: %w", err)
@@ -0,0 +1,113 @@
+package retrotemplate
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+// GetMarketplaceTemplates retrieves the templates shared to the marketplace, most downloaded first
+func (d *Service) GetMarketplaceTemplates(ctx context.Context) ([]*thunderdome.RetroTemplate, error) {
+	templates := make([]*thunderdome.RetroTemplate, 0)
+
+	rows, err := d.DB.QueryContext(ctx,
+		`SELECT id, name, description, format, is_public_marketplace, marketplace_downloads, COALESCE(created_by::text, ''), created_at, updated_at
+		FROM thunderdome.retro_template
+		WHERE is_public_marketplace = true
+		ORDER BY marketplace_downloads DESC, name ASC;`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error querying marketplace templates: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var t thunderdome.RetroTemplate
+		var format string
+		if err := rows.Scan(
+			&t.ID,
+			&t.Name,
+			&t.Description,
+			&format,
+			&t.IsPublicMarketplace,
+			&t.MarketplaceDownloads,
+			&t.CreatedBy,
+			&t.CreatedAt,
+			&t.UpdatedAt,
+		); err != nil {
+			d.Logger.Ctx(ctx).Error("GetMarketplaceTemplates row scan error", zap.Error(err))
+		} else {
+			formatErr := json.Unmarshal([]byte(format), &t.Format)
+			if formatErr != nil {
+				d.Logger.Error("retro template json error", zap.Error(formatErr))
+				return nil, fmt.Errorf("get template format error: %v", formatErr)
+			}
+			templates = append(templates, &t)
+		}
+	}
+
+	return templates, nil
+}
+
+// PublishMarketplaceTemplate shares the template to the marketplace
+func (d *Service) PublishMarketplaceTemplate(ctx context.Context, templateID string) error {
+	_, err := d.DB.ExecContext(ctx,
+		`UPDATE thunderdome.retro_template SET is_public_marketplace = true, updated_at = NOW() WHERE id = $1;`,
+		templateID,
+	)
+	if err != nil {
+		return fmt.Errorf("error publishing marketplace template: %v", err)
+	}
+
+	return nil
+}
+
+// InstallMarketplaceTemplate copies the marketplace template into the organization
+// and increments the marketplace template download count
+func (d *Service) InstallMarketplaceTemplate(ctx context.Context, templateID string, organizationID string, userID string) (*thunderdome.RetroTemplate, error) {
+	tx, err := d.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("install marketplace template begin transaction error: %v", err)
+	}
+	defer tx.Rollback()
+
+	var original thunderdome.RetroTemplate
+	var format string
+	err = tx.QueryRowContext(ctx,
+		`UPDATE thunderdome.retro_template SET marketplace_downloads = marketplace_downloads + 1
+		WHERE id = $1 AND is_public_marketplace = true
+		RETURNING name, description, format;`,
+		templateID,
+	).Scan(&original.Name, &original.Description, &format)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("MARKETPLACE_TEMPLATE_NOT_FOUND")
+		}
+		return nil, fmt.Errorf("install marketplace template download count error: %v", err)
+	}
+	if err := json.Unmarshal([]byte(format), &original.Format); err != nil {
+		return nil, fmt.Errorf("install marketplace template format error: %v", err)
+	}
+
+	installed := thunderdome.MarketplaceTemplateCopy(&original, organizationID, userID)
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO thunderdome.retro_template (name, description, format, created_by, organization_id)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at;`,
+		installed.Name, installed.Description, installed.Format, userID, organizationID,
+	).Scan(&installed.ID, &installed.CreatedAt, &installed.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("install marketplace template insert error: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("install marketplace template commit error: %v", err)
+	}
+
+	return installed, nil
+}
@@ -157,7 +157,7 @@ func (d *Service) GetTemplateByID(ctx context.Context, templateID string) (*thun
 	var format string
 
 	err := d.DB.QueryRowContext(ctx,
-		`SELECT id, name, description, format, is_public, default_template, COALESCE(created_by::text, ''), COALESCE(organization_id::text, ''), COALESCE(team_id::text, ''), created_at, updated_at
+		`SELECT id, name, description, format, is_public, is_public_marketplace, marketplace_downloads, default_template, COALESCE(created_by::text, ''), COALESCE(organization_id::text, ''), COALESCE(team_id::text, ''), created_at, updated_at
 		FROM thunderdome.retro_template
 		WHERE id = $1;`,
 		templateID,
@@ -167,6 +167,8 @@ func (d *Service) GetTemplateByID(ctx context.Context, templateID string) (*thun
 		&t.Description,
 		&format,
 		&t.IsPublic,
+		&t.IsPublicMarketplace,
+		&t.MarketplaceDownloads,
 		&t.DefaultTemplate,
 		&t.CreatedBy,
 		&t.OrganizationID,
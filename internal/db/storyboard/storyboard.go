@@ -215,7 +215,8 @@ func (d *Service) GetStoryboardByID(storyboardID string, userID string) (*thunde
 	return b, nil
 }
 
-// GetStoryboardsByUser gets a list of storyboards by user ID
+// GetStoryboardsByUser gets a list of storyboards the user joined (and hasn't abandoned) or that belong to
+// one of the user's teams, along with the team name and member count
 func (d *Service) GetStoryboardsByUser(userID string, limit int, offset int) ([]*thunderdome.Storyboard, int, error) {
 	var count int
 	var storyboards = make([]*thunderdome.Storyboard, 0)
@@ -261,7 +262,9 @@ func (d *Service) GetStoryboardsByUser(userID string, limit int, offset int) ([]
 			SELECT id from user_storyboards UNION SELECT id FROM team_storyboards
 		)
 		SELECT s.id, s.name, s.owner_id, COALESCE(s.team_id::TEXT, ''), s.created_date, s.updated_date,
-		  min(COALESCE(t.name, '')) as team_name
+		  min(COALESCE(t.name, '')) as team_name,
+		  (SELECT COUNT(*) FROM thunderdome.storyboard_user su
+		    WHERE su.storyboard_id = s.id AND su.abandoned = false) AS member_count
 		FROM thunderdome.storyboard s
 		LEFT JOIN user_teams t ON t.id = s.team_id
 		WHERE s.id IN (SELECT id FROM storyboards)
@@ -287,6 +290,7 @@ func (d *Service) GetStoryboardsByUser(userID string, limit int, offset int) ([]
 			&b.CreatedDate,
 			&b.UpdatedDate,
 			&b.TeamName,
+			&b.MemberCount,
 		); err != nil {
 			d.Logger.Error("get_storyboards_by_user query scan error", zap.Error(err))
 		} else {
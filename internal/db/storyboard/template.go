@@ -0,0 +1,215 @@
+package storyboard
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/fracindex"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// storyboardTemplateColumns are the storyboard_template columns scanned by scanStoryboardTemplate
+const storyboardTemplateColumns = `id, organization_id, team_id, name, description, format, is_public,
+	COALESCE(created_by::text, ''), created_at, updated_at`
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanStoryboardTemplate scans a row selected with storyboardTemplateColumns
+func scanStoryboardTemplate(row rowScanner) (*thunderdome.StoryboardTemplate, error) {
+	t := thunderdome.StoryboardTemplate{}
+	var format []byte
+
+	if err := row.Scan(
+		&t.ID, &t.OrgID, &t.TeamID, &t.Name, &t.Description, &format, &t.IsPublic,
+		&t.CreatedBy, &t.CreatedAt, &t.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	t.Format = format
+
+	return &t, nil
+}
+
+// CreateTemplate creates a storyboard template
+func (d *Service) CreateTemplate(ctx context.Context, template *thunderdome.StoryboardTemplate) (*thunderdome.StoryboardTemplate, error) {
+	var templateID string
+	err := d.DB.QueryRowContext(ctx,
+		`INSERT INTO thunderdome.storyboard_template (organization_id, team_id, name, description, format, is_public, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, '')::uuid)
+		RETURNING id;`,
+		template.OrgID, template.TeamID, template.Name, template.Description, []byte(template.Format),
+		template.IsPublic, template.CreatedBy,
+	).Scan(&templateID)
+	if err != nil {
+		return nil, fmt.Errorf("create storyboard template query error: %v", err)
+	}
+
+	return d.GetTemplate(ctx, templateID)
+}
+
+// GetTemplate gets a storyboard template by ID
+func (d *Service) GetTemplate(ctx context.Context, templateID string) (*thunderdome.StoryboardTemplate, error) {
+	template, err := scanStoryboardTemplate(d.DB.QueryRowContext(ctx,
+		`SELECT `+storyboardTemplateColumns+` FROM thunderdome.storyboard_template WHERE id = $1;`,
+		templateID,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("STORYBOARD_TEMPLATE_NOT_FOUND")
+		}
+		return nil, fmt.Errorf("get storyboard template query error: %v", err)
+	}
+
+	return template, nil
+}
+
+// UpdateTemplate updates the storyboard template's name, description, format and visibility,
+// the organization or team it belongs to can't be changed
+func (d *Service) UpdateTemplate(ctx context.Context, template *thunderdome.StoryboardTemplate) (*thunderdome.StoryboardTemplate, error) {
+	result, err := d.DB.ExecContext(ctx,
+		`UPDATE thunderdome.storyboard_template
+		SET name = $2, description = $3, format = $4, is_public = $5, updated_at = NOW()
+		WHERE id = $1;`,
+		template.ID, template.Name, template.Description, []byte(template.Format), template.IsPublic,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("update storyboard template query error: %v", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return nil, errors.New("STORYBOARD_TEMPLATE_NOT_FOUND")
+	}
+
+	return d.GetTemplate(ctx, template.ID)
+}
+
+// DeleteTemplate deletes a storyboard template, storyboards created from it are kept
+func (d *Service) DeleteTemplate(ctx context.Context, templateID string) error {
+	result, err := d.DB.ExecContext(ctx,
+		`DELETE FROM thunderdome.storyboard_template WHERE id = $1;`,
+		templateID,
+	)
+	if err != nil {
+		return fmt.Errorf("delete storyboard template query error: %v", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return errors.New("STORYBOARD_TEMPLATE_NOT_FOUND")
+	}
+
+	return nil
+}
+
+// ListTemplatesForOrg lists the public storyboard templates along with the organization's templates
+// and those of its teams, only public templates are listed when no organization is given
+func (d *Service) ListTemplatesForOrg(ctx context.Context, orgID string) ([]*thunderdome.StoryboardTemplate, error) {
+	templates := make([]*thunderdome.StoryboardTemplate, 0)
+
+	rows, err := d.DB.QueryContext(ctx,
+		`SELECT `+storyboardTemplateColumns+` FROM thunderdome.storyboard_template
+		WHERE is_public = true
+			OR organization_id = NULLIF($1, '')::uuid
+			OR team_id IN (SELECT id FROM thunderdome.team WHERE organization_id = NULLIF($1, '')::uuid)
+		ORDER BY name;`,
+		orgID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list storyboard templates query error: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		template, err := scanStoryboardTemplate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("list storyboard templates scan error: %v", err)
+		}
+		templates = append(templates, template)
+	}
+
+	return templates, nil
+}
+
+// storyboardTemplateGoal is a goal to create from a template along with its columns, in display order
+type storyboardTemplateGoal struct {
+	Name         string
+	DisplayOrder string
+	Columns      []storyboardTemplateColumn
+}
+
+// storyboardTemplateColumn is a goal column to create from a template
+type storyboardTemplateColumn struct {
+	Name         string
+	DisplayOrder string
+}
+
+// storyboardTemplateGoals orders the template's goals, and the columns within each goal, in the template's order
+func storyboardTemplateGoals(format *thunderdome.StoryboardTemplateFormat) ([]storyboardTemplateGoal, error) {
+	goals := make([]storyboardTemplateGoal, 0, len(format.Goals))
+	var lastGoalOrder *string
+
+	for _, g := range format.Goals {
+		goalOrder, err := fracindex.KeyBetween(lastGoalOrder, nil)
+		if err != nil {
+			return nil, err
+		}
+		lastGoalOrder = goalOrder
+
+		goal := storyboardTemplateGoal{Name: g.Name, DisplayOrder: *goalOrder}
+		var lastColumnOrder *string
+		for _, c := range g.Columns {
+			columnOrder, err := fracindex.KeyBetween(lastColumnOrder, nil)
+			if err != nil {
+				return nil, err
+			}
+			lastColumnOrder = columnOrder
+			goal.Columns = append(goal.Columns, storyboardTemplateColumn{Name: c.Name, DisplayOrder: *columnOrder})
+		}
+		goals = append(goals, goal)
+	}
+
+	return goals, nil
+}
+
+// ApplyTemplate adds the template's goals and columns to a new storyboard
+func (d *Service) ApplyTemplate(ctx context.Context, storyboardID string, format *thunderdome.StoryboardTemplateFormat) ([]*thunderdome.StoryboardGoal, error) {
+	goals, err := storyboardTemplateGoals(format)
+	if err != nil {
+		return nil, fmt.Errorf("apply storyboard template display_order error: %v", err)
+	}
+
+	tx, err := d.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("apply storyboard template begin transaction error: %v", err)
+	}
+	defer tx.Rollback()
+
+	for _, goal := range goals {
+		var goalID string
+		if err := tx.QueryRowContext(ctx,
+			`INSERT INTO thunderdome.storyboard_goal (storyboard_id, name, display_order)
+			VALUES ($1, $2, $3) RETURNING id;`,
+			storyboardID, goal.Name, goal.DisplayOrder,
+		).Scan(&goalID); err != nil {
+			return nil, fmt.Errorf("apply storyboard template goal query error: %v", err)
+		}
+
+		for _, column := range goal.Columns {
+			if _, err := tx.ExecContext(ctx,
+				`INSERT INTO thunderdome.storyboard_column (storyboard_id, goal_id, name, display_order)
+				VALUES ($1, $2, $3, $4);`,
+				storyboardID, goalID, column.Name, column.DisplayOrder,
+			); err != nil {
+				return nil, fmt.Errorf("apply storyboard template column query error: %v", err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("apply storyboard template commit error: %v", err)
+	}
+
+	return d.GetStoryboardGoals(storyboardID), nil
+}
@@ -16,7 +16,7 @@ func (d *Service) GetStoryboardUsers(storyboardID string) []*thunderdome.Storybo
 	var users = make([]*thunderdome.StoryboardUser, 0)
 	rows, err := d.DB.Query(
 		`SELECT
-			w.id, w.name, su.active, w.avatar, COALESCE(w.email, ''), COALESCE(w.picture, '')
+			w.id, CASE WHEN w.anonymized_at IS NOT NULL THEN 'Anonymous' ELSE w.name END, su.active, w.avatar, COALESCE(w.email, ''), COALESCE(w.picture, '')
 		FROM thunderdome.storyboard_user su
 		LEFT JOIN thunderdome.users w ON su.user_id = w.id
 		WHERE su.storyboard_id = $1
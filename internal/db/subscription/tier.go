@@ -0,0 +1,68 @@
+package subscription
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+// orgTierCacheTTL is the longest an organization's subscription tier is cached for,
+// subscription changes can take this long to take effect
+const orgTierCacheTTL = 5 * time.Minute
+
+// OrgTierCacheKey is the cache key of the organization's subscription tier
+func OrgTierCacheKey(orgID string) string {
+	return fmt.Sprintf("org:tier:%s", orgID)
+}
+
+// GetOrgTier gets the organization's subscription tier from its active subscriptions along with
+// those of its teams, the highest tier wins and organizations without an active subscription are free
+func (s *Service) GetOrgTier(ctx context.Context, orgID string) (thunderdome.SubscriptionTier, error) {
+	cacheKey := OrgTierCacheKey(orgID)
+	ttl := thunderdome.CappedTTL(s.CacheTTL.OrgTTL, orgTierCacheTTL)
+	if s.Redis != nil && ttl > 0 {
+		if cachedTier, err := s.Redis.Get(ctx, cacheKey).Result(); err == nil {
+			s.Logger.Ctx(ctx).Debug("Organization tier cache hit", zap.String("organization_id", orgID))
+			return thunderdome.SubscriptionTier(cachedTier), nil
+		}
+	}
+
+	rows, err := s.DB.QueryContext(ctx,
+		`SELECT s.type
+		FROM thunderdome.subscription s
+		WHERE s.active = true AND s.expires > NOW()
+		AND (
+			s.organization_id = $1
+			OR s.team_id IN (
+				SELECT t.id FROM thunderdome.team t
+				LEFT JOIN thunderdome.organization_department od ON od.id = t.department_id
+				WHERE t.organization_id = $1 OR od.organization_id = $1
+			)
+		);`,
+		orgID,
+	)
+	if err != nil {
+		return thunderdome.SubscriptionTierFree, fmt.Errorf("error getting organization %s subscription tier: %v", orgID, err)
+	}
+	defer rows.Close()
+
+	tier := thunderdome.SubscriptionTierFree
+	for rows.Next() {
+		var subscriptionType string
+		if err := rows.Scan(&subscriptionType); err != nil {
+			return thunderdome.SubscriptionTierFree, fmt.Errorf("error getting organization %s subscription tier: %v", orgID, err)
+		}
+		if subTier := thunderdome.SubscriptionTypeTier(subscriptionType); subTier.Meets(tier) {
+			tier = subTier
+		}
+	}
+
+	if s.Redis != nil && ttl > 0 {
+		s.Redis.Set(ctx, cacheKey, string(tier), ttl)
+	}
+
+	return tier, nil
+}
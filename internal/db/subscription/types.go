@@ -3,10 +3,15 @@ package subscription
 import (
 	"database/sql"
 
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/redis/go-redis/v9"
 	"github.com/uptrace/opentelemetry-go-extra/otelzap"
 )
 
 type Service struct {
 	DB     *sql.DB
 	Logger *otelzap.Logger
+	Redis  *redis.Client
+	// CacheTTL is how long organization subscription data is cached, a zero TTL disables the cache
+	CacheTTL thunderdome.CacheTTLConfig
 }
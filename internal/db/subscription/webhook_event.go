@@ -0,0 +1,114 @@
+package subscription
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// webhookEventStatusConditions are the conditions matching each webhook event log status filter
+var webhookEventStatusConditions = map[string]string{
+	"":                                    "TRUE",
+	thunderdome.WebhookEventStatusSuccess: "succeeded = true",
+	thunderdome.WebhookEventStatusFailure: "succeeded = false",
+}
+
+// LogWebhookEvent records a webhook delivery attempt in the webhook event log
+func (s *Service) LogWebhookEvent(ctx context.Context, event *thunderdome.WebhookEvent) error {
+	err := s.DB.QueryRowContext(ctx,
+		`INSERT INTO thunderdome.webhook_event_log
+			(delivery_id, event_type, payload, http_status, response_body, error, succeeded, sent_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id;`,
+		event.DeliveryID, event.EventType, string(event.Payload), event.HTTPStatus, event.ResponseBody, event.Error, event.Succeeded, event.SentAt,
+	).Scan(&event.ID)
+	if err != nil {
+		return fmt.Errorf("log webhook event query error: %v", err)
+	}
+
+	return nil
+}
+
+// GetWebhookEvent gets a logged webhook delivery attempt
+func (s *Service) GetWebhookEvent(ctx context.Context, eventID string) (*thunderdome.WebhookEvent, error) {
+	event := &thunderdome.WebhookEvent{}
+	var payload string
+	err := s.DB.QueryRowContext(ctx,
+		`SELECT id, delivery_id, event_type, payload, http_status, response_body, error, succeeded, sent_at
+		FROM thunderdome.webhook_event_log WHERE id = $1;`,
+		eventID,
+	).Scan(&event.ID, &event.DeliveryID, &event.EventType, &payload, &event.HTTPStatus, &event.ResponseBody, &event.Error, &event.Succeeded, &event.SentAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("WEBHOOK_EVENT_NOT_FOUND")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get webhook event query error: %v", err)
+	}
+	event.Payload = []byte(payload)
+
+	return event, nil
+}
+
+// GetWebhookEvents gets the logged webhook delivery attempts newest first,
+// optionally filtered by event type and delivery status
+func (s *Service) GetWebhookEvents(ctx context.Context, eventType string, status string, limit int, offset int) ([]*thunderdome.WebhookEvent, int, error) {
+	condition, ok := webhookEventStatusConditions[status]
+	if !ok {
+		return nil, 0, thunderdome.ErrInvalidWebhookEventStatus
+	}
+	filter := `WHERE ($1 = '' OR event_type = $1) AND ` + condition
+
+	var count int
+	if err := s.DB.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM thunderdome.webhook_event_log `+filter+`;`,
+		eventType,
+	).Scan(&count); err != nil {
+		return nil, 0, fmt.Errorf("get webhook events count query error: %v", err)
+	}
+
+	events := make([]*thunderdome.WebhookEvent, 0)
+	rows, err := s.DB.QueryContext(ctx,
+		`SELECT id, delivery_id, event_type, payload, http_status, response_body, error, succeeded, sent_at
+		FROM thunderdome.webhook_event_log `+filter+`
+		ORDER BY sent_at DESC LIMIT $2 OFFSET $3;`,
+		eventType, limit, offset,
+	)
+	if err != nil {
+		return nil, count, fmt.Errorf("get webhook events query error: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		event := &thunderdome.WebhookEvent{}
+		var payload string
+		if err := rows.Scan(
+			&event.ID, &event.DeliveryID, &event.EventType, &payload, &event.HTTPStatus, &event.ResponseBody, &event.Error, &event.Succeeded, &event.SentAt,
+		); err != nil {
+			return nil, count, fmt.Errorf("get webhook events scan error: %v", err)
+		}
+		event.Payload = []byte(payload)
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, count, fmt.Errorf("get webhook events rows error: %v", err)
+	}
+
+	return events, count, nil
+}
+
+// CleanWebhookEvents deletes the webhook delivery attempts logged longer than the retention ago
+func (s *Service) CleanWebhookEvents(ctx context.Context, retention time.Duration) (int64, error) {
+	result, err := s.DB.ExecContext(ctx,
+		`DELETE FROM thunderdome.webhook_event_log WHERE sent_at < $1;`,
+		time.Now().Add(-retention),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("clean webhook events query error: %v", err)
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,152 @@
+package team
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+// memberActivityCacheTTL is the longest the team's member activity scores are cached
+const memberActivityCacheTTL = time.Hour
+
+// Member activity types counted by the activity score query
+const (
+	activityGame      = "game"
+	activityStoryVote = "story_vote"
+	activityRetro     = "retro"
+	activityStandup   = "standup"
+)
+
+// memberActivityRow is the count of a single activity type for a team member,
+// Activity is empty for members without any activity
+type memberActivityRow struct {
+	UserID   string
+	Username string
+	Activity string
+	Count    int
+}
+
+// ComputeMemberActivityScores scores the team members' participation in the team's poker games, story voting,
+// retros and standups since the date, ordered by the highest score
+func (d *Service) ComputeMemberActivityScores(ctx context.Context, teamID string, since time.Time) ([]thunderdome.MemberActivityScore, error) {
+	cacheKey := memberActivityCacheKey(teamID, since)
+	ttl := thunderdome.CappedTTL(d.CacheTTL.TeamTTL, memberActivityCacheTTL)
+	if d.Redis != nil && ttl > 0 {
+		if cachedData, err := d.Redis.Get(ctx, cacheKey).Result(); err == nil {
+			var scores []thunderdome.MemberActivityScore
+			if err := json.Unmarshal([]byte(cachedData), &scores); err == nil {
+				d.Logger.Ctx(ctx).Debug("Team member activity cache hit", zap.String("team_id", teamID))
+				return scores, nil
+			}
+		}
+	}
+
+	rows, err := d.DB.QueryContext(ctx,
+		`SELECT tu.user_id, u.name, COALESCE(a.activity, ''), COALESCE(a.activity_count, 0)
+		FROM thunderdome.team_user tu
+		JOIN thunderdome.users u ON u.id = tu.user_id
+		LEFT JOIN (
+			SELECT pu.user_id::text AS user_id, 'game' AS activity, COUNT(*) AS activity_count
+			FROM thunderdome.poker_user pu
+			JOIN thunderdome.poker p ON p.id = pu.poker_id
+			WHERE p.team_id = $1 AND p.created_date >= $2
+			GROUP BY pu.user_id
+			UNION ALL
+			SELECT v->>'warriorId', 'story_vote', COUNT(*)
+			FROM thunderdome.poker_story ps
+			JOIN thunderdome.poker p ON p.id = ps.poker_id
+			CROSS JOIN LATERAL jsonb_array_elements(ps.votes) v
+			WHERE p.team_id = $1 AND ps.votestart_time >= $2
+			GROUP BY v->>'warriorId'
+			UNION ALL
+			SELECT ru.user_id::text, 'retro', COUNT(*)
+			FROM thunderdome.retro_user ru
+			JOIN thunderdome.retro r ON r.id = ru.retro_id
+			WHERE r.team_id = $1 AND r.created_date >= $2 AND r.deleted_at IS NULL
+			GROUP BY ru.user_id
+			UNION ALL
+			SELECT tc.user_id::text, 'standup', COUNT(*)
+			FROM thunderdome.team_checkin tc
+			WHERE tc.team_id = $1 AND tc.created_date >= $2
+			GROUP BY tc.user_id
+		) a ON a.user_id = tu.user_id::text
+		WHERE tu.team_id = $1;`,
+		teamID, since,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get team member activity query error: %v", err)
+	}
+	defer rows.Close()
+
+	activity := make([]memberActivityRow, 0)
+	for rows.Next() {
+		var a memberActivityRow
+		if err := rows.Scan(&a.UserID, &a.Username, &a.Activity, &a.Count); err != nil {
+			return nil, fmt.Errorf("get team member activity query scan error: %v", err)
+		}
+		activity = append(activity, a)
+	}
+
+	scores := computeMemberActivityScores(activity, d.ActivityScoreWeights)
+
+	if d.Redis != nil && ttl > 0 {
+		if scoresJSON, err := json.Marshal(scores); err == nil {
+			if err := d.Redis.Set(ctx, cacheKey, scoresJSON, ttl).Err(); err != nil {
+				d.Logger.Ctx(ctx).Error("Failed to set team member activity cache", zap.Error(err),
+					zap.String("team_id", teamID))
+			}
+		}
+	}
+
+	return scores, nil
+}
+
+// computeMemberActivityScores totals each member's activity counts and weighted score,
+// members are ordered by the highest score then by username
+func computeMemberActivityScores(activity []memberActivityRow, weights thunderdome.ActivityScoreWeights) []thunderdome.MemberActivityScore {
+	members := make(map[string]*thunderdome.MemberActivityScore)
+	for _, a := range activity {
+		score, ok := members[a.UserID]
+		if !ok {
+			score = &thunderdome.MemberActivityScore{UserID: a.UserID, Username: a.Username}
+			members[a.UserID] = score
+		}
+
+		switch a.Activity {
+		case activityGame:
+			score.GamesParticipated += a.Count
+			score.TotalScore += a.Count * weights.Game
+		case activityStoryVote:
+			score.StoriesVoted += a.Count
+			score.TotalScore += a.Count * weights.StoryVote
+		case activityRetro:
+			score.RetrosParticipated += a.Count
+			score.TotalScore += a.Count * weights.Retro
+		case activityStandup:
+			score.StandupsFiled += a.Count
+			score.TotalScore += a.Count * weights.Standup
+		}
+	}
+
+	scores := make([]thunderdome.MemberActivityScore, 0, len(members))
+	for _, score := range members {
+		scores = append(scores, *score)
+	}
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].TotalScore != scores[j].TotalScore {
+			return scores[i].TotalScore > scores[j].TotalScore
+		}
+		return scores[i].Username < scores[j].Username
+	})
+
+	return scores
+}
+
+func memberActivityCacheKey(teamID string, since time.Time) string {
+	return fmt.Sprintf("team:activity:%s:%s", teamID, since.UTC().Format(time.DateOnly))
+}
@@ -0,0 +1,38 @@
+package team
+
+import (
+	"testing"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// TestComputeMemberActivityScores makes sure every activity type is counted and weighted into the total score
+func TestComputeMemberActivityScores(t *testing.T) {
+	weights := thunderdome.ActivityScoreWeights{Game: 3, StoryVote: 1, Retro: 4, Standup: 2}
+	activity := []memberActivityRow{
+		{UserID: "thor", Username: "Thor", Activity: activityGame, Count: 2},
+		{UserID: "thor", Username: "Thor", Activity: activityStoryVote, Count: 10},
+		{UserID: "thor", Username: "Thor", Activity: activityRetro, Count: 1},
+		{UserID: "thor", Username: "Thor", Activity: activityStandup, Count: 5},
+		{UserID: "loki", Username: "Loki", Activity: activityStandup, Count: 1},
+		{UserID: "hulk", Username: "Hulk", Activity: activityRetro, Count: 1},
+		{UserID: "odin", Username: "Odin"},
+	}
+
+	scores := computeMemberActivityScores(activity, weights)
+
+	expected := []thunderdome.MemberActivityScore{
+		{UserID: "thor", Username: "Thor", GamesParticipated: 2, StoriesVoted: 10, RetrosParticipated: 1, StandupsFiled: 5, TotalScore: 30},
+		{UserID: "hulk", Username: "Hulk", RetrosParticipated: 1, TotalScore: 4},
+		{UserID: "loki", Username: "Loki", StandupsFiled: 1, TotalScore: 2},
+		{UserID: "odin", Username: "Odin"},
+	}
+	if len(scores) != len(expected) {
+		t.Fatalf("expected %d members, got %d", len(expected), len(scores))
+	}
+	for i := range expected {
+		if scores[i] != expected[i] {
+			t.Errorf("expected member %d to be %+v, got %+v", i, expected[i], scores[i])
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package team
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+// CheckinScheduleGet gets the team's checkin reminder schedule
+func (d *CheckinService) CheckinScheduleGet(ctx context.Context, teamID string) (*thunderdome.CheckinSchedule, error) {
+	schedule := thunderdome.CheckinSchedule{}
+
+	err := d.DB.QueryRowContext(ctx,
+		`SELECT team_id, cron_expression, timezone_offset, reminder_enabled, created_date, updated_date
+		FROM thunderdome.team_checkin_schedule WHERE team_id = $1;`,
+		teamID,
+	).Scan(
+		&schedule.TeamID, &schedule.CronExpression, &schedule.TimezoneOffset, &schedule.ReminderEnabled,
+		&schedule.CreatedDate, &schedule.UpdatedDate,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("CHECKIN_SCHEDULE_NOT_FOUND")
+		}
+		return nil, fmt.Errorf("get team checkin schedule query error: %v", err)
+	}
+
+	return &schedule, nil
+}
+
+// CheckinScheduleUpsert creates or updates the team's checkin reminder schedule
+func (d *CheckinService) CheckinScheduleUpsert(
+	ctx context.Context, teamID string, cronExpression string, timezoneOffset int, reminderEnabled bool,
+) (*thunderdome.CheckinSchedule, error) {
+	schedule := thunderdome.CheckinSchedule{}
+
+	err := d.DB.QueryRowContext(ctx,
+		`INSERT INTO thunderdome.team_checkin_schedule (team_id, cron_expression, timezone_offset, reminder_enabled)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (team_id) DO UPDATE
+		SET cron_expression = EXCLUDED.cron_expression, timezone_offset = EXCLUDED.timezone_offset,
+			reminder_enabled = EXCLUDED.reminder_enabled, updated_date = NOW()
+		RETURNING team_id, cron_expression, timezone_offset, reminder_enabled, created_date, updated_date;`,
+		teamID, cronExpression, timezoneOffset, reminderEnabled,
+	).Scan(
+		&schedule.TeamID, &schedule.CronExpression, &schedule.TimezoneOffset, &schedule.ReminderEnabled,
+		&schedule.CreatedDate, &schedule.UpdatedDate,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("upsert team checkin schedule query error: %v", err)
+	}
+
+	return &schedule, nil
+}
+
+// CheckinScheduleDelete deletes the team's checkin reminder schedule
+func (d *CheckinService) CheckinScheduleDelete(ctx context.Context, teamID string) error {
+	if _, err := d.DB.ExecContext(ctx,
+		`DELETE FROM thunderdome.team_checkin_schedule WHERE team_id = $1;`,
+		teamID,
+	); err != nil {
+		return fmt.Errorf("delete team checkin schedule query error: %v", err)
+	}
+
+	return nil
+}
+
+// CheckinSchedulesEnabled gets every team checkin schedule with reminders enabled
+func (d *CheckinService) CheckinSchedulesEnabled(ctx context.Context) ([]*thunderdome.CheckinSchedule, error) {
+	schedules := make([]*thunderdome.CheckinSchedule, 0)
+
+	rows, err := d.DB.QueryContext(ctx,
+		`SELECT team_id, cron_expression, timezone_offset, reminder_enabled, created_date, updated_date
+		FROM thunderdome.team_checkin_schedule WHERE reminder_enabled = true;`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get enabled team checkin schedules query error: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var schedule thunderdome.CheckinSchedule
+		if err := rows.Scan(
+			&schedule.TeamID, &schedule.CronExpression, &schedule.TimezoneOffset, &schedule.ReminderEnabled,
+			&schedule.CreatedDate, &schedule.UpdatedDate,
+		); err != nil {
+			d.Logger.Ctx(ctx).Error("get enabled team checkin schedules scan error", zap.Error(err))
+			continue
+		}
+		schedules = append(schedules, &schedule)
+	}
+
+	return schedules, nil
+}
+
+// CheckinScheduleClaim claims sending the team's reminder that came due at dueAt, only one claim
+// succeeds for each due time so multiple instances running the scheduler don't send duplicate reminders
+func (d *CheckinService) CheckinScheduleClaim(ctx context.Context, teamID string, dueAt time.Time) (bool, error) {
+	result, err := d.DB.ExecContext(ctx,
+		`UPDATE thunderdome.team_checkin_schedule SET last_reminded_at = $2
+		WHERE team_id = $1 AND reminder_enabled = true AND (last_reminded_at IS NULL OR last_reminded_at < $2);`,
+		teamID, dueAt,
+	)
+	if err != nil {
+		return false, fmt.Errorf("claim team checkin schedule query error: %v", err)
+	}
+
+	claimed, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("claim team checkin schedule query error: %v", err)
+	}
+
+	return claimed == 1, nil
+}
+
+// CheckinMissingUsers gets the team name and the team users who have not checked in on the given day,
+// the day is evaluated in the timezone offset (minutes from UTC)
+func (d *CheckinService) CheckinMissingUsers(
+	ctx context.Context, teamID string, date time.Time, timezoneOffset int,
+) (string, []*thunderdome.TeamUser, error) {
+	var teamName string
+	if err := d.DB.QueryRowContext(ctx,
+		`SELECT name FROM thunderdome.team WHERE id = $1;`,
+		teamID,
+	).Scan(&teamName); err != nil {
+		return "", nil, fmt.Errorf("get checkin missing users team query error: %v", err)
+	}
+
+	users := make([]*thunderdome.TeamUser, 0)
+	rows, err := d.DB.QueryContext(ctx,
+		`SELECT u.id, u.name, COALESCE(u.email, ''), tu.role
+		FROM thunderdome.team_user tu
+		JOIN thunderdome.users u ON u.id = tu.user_id
+		WHERE tu.team_id = $1 AND NOT EXISTS (
+			SELECT 1 FROM thunderdome.team_checkin tc
+			WHERE tc.team_id = tu.team_id AND tc.user_id = tu.user_id
+			AND date((tc.created_date AT TIME ZONE 'UTC') + $3 * interval '1 minute') = $2::date
+		)
+		ORDER BY u.name;`,
+		teamID, date.Format(time.DateOnly), timezoneOffset,
+	)
+	if err != nil {
+		return "", nil, fmt.Errorf("get checkin missing users query error: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var user thunderdome.TeamUser
+		if err := rows.Scan(&user.ID, &user.Name, &user.Email, &user.Role); err != nil {
+			d.Logger.Ctx(ctx).Error("get checkin missing users scan error", zap.Error(err))
+			continue
+		}
+		users = append(users, &user)
+	}
+
+	return teamName, users, nil
+}
@@ -9,15 +9,26 @@ import (
 	"github.com/StevenWeathers/thunderdome-planning-poker/internal/db"
 
 	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/redis/go-redis/v9"
 	"github.com/uptrace/opentelemetry-go-extra/otelzap"
 
 	"go.uber.org/zap"
 )
 
+// organizationAllowedAuthMethodsSQL resolves the auth methods allowed for members of the organization ($1),
+// NULL meaning any auth method is allowed
+const organizationAllowedAuthMethodsSQL = `(
+	SELECT CASE WHEN o.sso_required THEN ARRAY['oidc', 'ldap', 'header', 'saml']::varchar[] END
+	FROM thunderdome.organization o WHERE o.id = $1
+)`
+
 // OrganizationService represents the database service for organizations
 type OrganizationService struct {
 	DB     *sql.DB
 	Logger *otelzap.Logger
+	Redis  *redis.Client
+	// CacheTTL is how long organization data is cached, a zero TTL disables the cache
+	CacheTTL thunderdome.CacheTTLConfig
 }
 
 // OrganizationGetByID gets an organization by ID
@@ -25,7 +36,7 @@ func (d *OrganizationService) OrganizationGetByID(ctx context.Context, orgID str
 	var org = &thunderdome.Organization{}
 
 	err := d.DB.QueryRowContext(ctx,
-		`SELECT o.id, o.name, o.created_date, o.updated_date,
+		`SELECT o.id, o.name, o.created_date, o.updated_date, o.sso_required, o.default_estimation_scale_id::TEXT,
  		CASE WHEN s.id IS NOT NULL AND s.expires > NOW() AND s.active = true THEN true ELSE false END AS is_subscribed
         FROM thunderdome.organization o
         LEFT JOIN thunderdome.subscription s ON o.id = s.organization_id
@@ -36,6 +47,8 @@ func (d *OrganizationService) OrganizationGetByID(ctx context.Context, orgID str
 		&org.Name,
 		&org.CreatedDate,
 		&org.UpdatedDate,
+		&org.SSORequired,
+		&org.DefaultEstimationScaleID,
 		&org.Subscribed,
 	)
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
@@ -144,6 +157,80 @@ func (d *OrganizationService) OrganizationUpdate(ctx context.Context, orgID stri
 	return o, nil
 }
 
+// OrganizationUpdateSSORequired updates whether an organization requires its members to authenticate via SSO,
+// recording the allowed auth methods for existing members
+func (d *OrganizationService) OrganizationUpdateSSORequired(ctx context.Context, orgID string, ssoRequired bool) (*thunderdome.Organization, error) {
+	o := &thunderdome.Organization{}
+
+	tx, err := d.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("organization update sso required begin transaction error: %v", err)
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, `
+		UPDATE thunderdome.organization
+		SET sso_required = $1, updated_date = NOW()
+		WHERE id = $2
+		RETURNING id, name, sso_required, created_date, updated_date;`,
+		ssoRequired, orgID,
+	).Scan(&o.ID, &o.Name, &o.SSORequired, &o.CreatedDate, &o.UpdatedDate)
+	if err != nil {
+		return nil, fmt.Errorf("organization update sso required query error: %v", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE thunderdome.organization_user
+		SET allowed_auth_methods = `+organizationAllowedAuthMethodsSQL+`, updated_date = NOW()
+		WHERE organization_id = $1;`,
+		orgID,
+	); err != nil {
+		return nil, fmt.Errorf("organization update sso required users query error: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("organization update sso required commit error: %v", err)
+	}
+
+	return o, nil
+}
+
+// SetDefaultEstimationScale sets the scale the organization's games use when created without one,
+// the scale must belong to the organization or be public, an empty scaleID clears the default
+func (d *OrganizationService) SetDefaultEstimationScale(ctx context.Context, orgID string, scaleID string) error {
+	if scaleID != "" {
+		var allowed bool
+		err := d.DB.QueryRowContext(ctx,
+			`SELECT EXISTS (
+				SELECT 1 FROM thunderdome.estimation_scale
+				WHERE id = $2 AND (is_public = true OR organization_id = $1)
+			);`,
+			orgID, scaleID,
+		).Scan(&allowed)
+		if err != nil {
+			return fmt.Errorf("organization set default estimation scale query error: %v", err)
+		}
+		if !allowed {
+			return fmt.Errorf("ESTIMATION_SCALE_NOT_FOUND")
+		}
+	}
+
+	result, err := d.DB.ExecContext(ctx,
+		`UPDATE thunderdome.organization
+		SET default_estimation_scale_id = NULLIF($2, '')::uuid, updated_date = NOW()
+		WHERE id = $1;`,
+		orgID, scaleID,
+	)
+	if err != nil {
+		return fmt.Errorf("organization set default estimation scale query error: %v", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("ORGANIZATION_NOT_FOUND")
+	}
+
+	return nil
+}
+
 // OrganizationUserList gets a list of organization users
 func (d *OrganizationService) OrganizationUserList(ctx context.Context, orgID string, limit int, offset int) []*thunderdome.OrganizationUser {
 	var users = make([]*thunderdome.OrganizationUser, 0)
@@ -189,7 +276,8 @@ func (d *OrganizationService) OrganizationUserList(ctx context.Context, orgID st
 // OrganizationAddUser adds a user to an organization
 func (d *OrganizationService) OrganizationAddUser(ctx context.Context, orgID string, userID string, role string) (string, error) {
 	_, err := d.DB.ExecContext(ctx,
-		`INSERT INTO thunderdome.organization_user (organization_id, user_id, role) VALUES ($1, $2, $3);`,
+		`INSERT INTO thunderdome.organization_user (organization_id, user_id, role, allowed_auth_methods)
+		VALUES ($1, $2, $3, `+organizationAllowedAuthMethodsSQL+`);`,
 		orgID,
 		userID,
 		role,
@@ -205,7 +293,8 @@ func (d *OrganizationService) OrganizationAddUser(ctx context.Context, orgID str
 // OrganizationUpsertUser adds a user to an organization if not existing otherwise does nothing
 func (d *OrganizationService) OrganizationUpsertUser(ctx context.Context, orgID string, userID string, role string) (string, error) {
 	_, err := d.DB.ExecContext(ctx,
-		`INSERT INTO thunderdome.organization_user (organization_id, user_id, role) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING;`,
+		`INSERT INTO thunderdome.organization_user (organization_id, user_id, role, allowed_auth_methods)
+		VALUES ($1, $2, $3, `+organizationAllowedAuthMethodsSQL+`) ON CONFLICT DO NOTHING;`,
 		orgID,
 		userID,
 		role,
@@ -0,0 +1,92 @@
+package team
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+// orgActiveGamesCacheTTL is shorter than the game caches as user presence changes frequently
+const orgActiveGamesCacheTTL = 30 * time.Second
+
+// GetActiveGamesForOrg gets the poker games of the organization teams (including department teams)
+// that currently have active users, most active first
+func (d *OrganizationService) GetActiveGamesForOrg(ctx context.Context, orgID string) ([]*thunderdome.Poker, error) {
+	cacheKey := orgActiveGamesCacheKey(orgID)
+	ttl := thunderdome.CappedTTL(d.CacheTTL.OrgTTL, orgActiveGamesCacheTTL)
+	if d.Redis != nil && ttl > 0 {
+		if cachedData, err := d.Redis.Get(ctx, cacheKey).Result(); err == nil {
+			var games []*thunderdome.Poker
+			if err := json.Unmarshal([]byte(cachedData), &games); err == nil {
+				d.Logger.Ctx(ctx).Debug("Organization active games cache hit", zap.String("organization_id", orgID))
+				return games, nil
+			}
+		}
+	}
+
+	var games = make([]*thunderdome.Poker, 0)
+	rows, err := d.DB.QueryContext(ctx,
+		`SELECT g.id, g.name, g.voting_locked, g.active_story_id, g.team_id, g.team_name,
+			g.active_user_count, g.created_date, g.updated_date
+		FROM (
+			SELECT p.id, p.name, p.voting_locked, COALESCE(p.active_story_id::TEXT, '') AS active_story_id,
+				t.id::TEXT AS team_id, t.name AS team_name, p.created_date, p.updated_date,
+				(SELECT COUNT(*) FROM thunderdome.poker_user pu
+					WHERE pu.poker_id = p.id AND pu.active IS TRUE) AS active_user_count
+			FROM thunderdome.poker p
+			JOIN thunderdome.team t ON t.id = p.team_id
+			LEFT JOIN thunderdome.organization_department od ON od.id = t.department_id
+			WHERE COALESCE(t.organization_id, od.organization_id) = $1 AND p.archived_at IS NULL
+		) g
+		WHERE g.active_user_count > 0
+		ORDER BY g.active_user_count DESC, g.updated_date DESC;`,
+		orgID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get organization active games query error: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var g = &thunderdome.Poker{
+			Users:              make([]*thunderdome.PokerUser, 0),
+			Stories:            make([]*thunderdome.Story, 0),
+			PointValuesAllowed: make([]string, 0),
+			Facilitators:       make([]string, 0),
+		}
+		if err := rows.Scan(
+			&g.ID,
+			&g.Name,
+			&g.VotingLocked,
+			&g.ActiveStoryID,
+			&g.TeamID,
+			&g.TeamName,
+			&g.ActiveUserCount,
+			&g.CreatedDate,
+			&g.UpdatedDate,
+		); err != nil {
+			d.Logger.Ctx(ctx).Error("get organization active games query scan error", zap.Error(err))
+		} else {
+			games = append(games, g)
+		}
+	}
+
+	if d.Redis != nil && ttl > 0 {
+		if gamesJSON, err := json.Marshal(games); err == nil {
+			if err := d.Redis.Set(ctx, cacheKey, gamesJSON, ttl).Err(); err != nil {
+				d.Logger.Ctx(ctx).Error("Failed to set organization active games cache", zap.Error(err),
+					zap.String("organization_id", orgID))
+			}
+		}
+	}
+
+	return games, nil
+}
+
+func orgActiveGamesCacheKey(orgID string) string {
+	return fmt.Sprintf("org:active-games:%s", orgID)
+}
@@ -0,0 +1,99 @@
+package team
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/db"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+// OrganizationImportUsers adds the imported members to the organization in a single transaction,
+// creating guest users for emails without an account. Each row is added within its own savepoint
+// so a failing row is rolled back without undoing the rest of the import.
+func (d *OrganizationService) OrganizationImportUsers(ctx context.Context, orgID string, rows []thunderdome.OrganizationMemberImportRow) ([]thunderdome.OrganizationMemberImportError, error) {
+	failures := make([]thunderdome.OrganizationMemberImportError, 0)
+
+	tx, err := d.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("organization import users begin transaction error: %v", err)
+	}
+	defer tx.Rollback()
+
+	for _, row := range rows {
+		if _, err := tx.ExecContext(ctx, `SAVEPOINT organization_import_row;`); err != nil {
+			return nil, fmt.Errorf("organization import users savepoint error: %v", err)
+		}
+
+		if reason, err := organizationImportUser(ctx, tx, orgID, row); err != nil {
+			d.Logger.Ctx(ctx).Warn("organization import users row error", zap.Error(err),
+				zap.String("organization_id", orgID), zap.Int("row", row.Row))
+			if _, err := tx.ExecContext(ctx, `ROLLBACK TO SAVEPOINT organization_import_row;`); err != nil {
+				return nil, fmt.Errorf("organization import users rollback savepoint error: %v", err)
+			}
+			failures = append(failures, thunderdome.OrganizationMemberImportError{
+				Row: row.Row, Email: row.Email, Reason: reason,
+			})
+			continue
+		}
+
+		if _, err := tx.ExecContext(ctx, `RELEASE SAVEPOINT organization_import_row;`); err != nil {
+			return nil, fmt.Errorf("organization import users release savepoint error: %v", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("organization import users commit error: %v", err)
+	}
+
+	return failures, nil
+}
+
+// organizationImportUser adds a single imported member to the organization, returning the failure reason on error
+func organizationImportUser(ctx context.Context, tx *sql.Tx, orgID string, row thunderdome.OrganizationMemberImportRow) (string, error) {
+	email := db.SanitizeEmail(row.Email)
+
+	var userID string
+	err := tx.QueryRowContext(ctx,
+		`SELECT id FROM thunderdome.users WHERE LOWER(email) = $1;`,
+		email,
+	).Scan(&userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		name, _, _ := strings.Cut(email, "@")
+		err = tx.QueryRowContext(ctx,
+			`INSERT INTO thunderdome.users (name, email) VALUES ($1, $2) RETURNING id;`,
+			name, email,
+		).Scan(&userID)
+		if err != nil {
+			return "USER_CREATE_FAILED", fmt.Errorf("create guest user query error: %v", err)
+		}
+	} else if err != nil {
+		return "USER_LOOKUP_FAILED", fmt.Errorf("get user by email query error: %v", err)
+	}
+
+	var isMember bool
+	err = tx.QueryRowContext(ctx,
+		`SELECT EXISTS(SELECT 1 FROM thunderdome.organization_user WHERE organization_id = $1 AND user_id = $2);`,
+		orgID, userID,
+	).Scan(&isMember)
+	if err != nil {
+		return "MEMBER_LOOKUP_FAILED", fmt.Errorf("organization user exists query error: %v", err)
+	}
+	if isMember {
+		return "ALREADY_MEMBER", fmt.Errorf("user %s is already an organization member", userID)
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO thunderdome.organization_user (organization_id, user_id, role, allowed_auth_methods)
+		VALUES ($1, $2, $3, `+organizationAllowedAuthMethodsSQL+`);`,
+		orgID, userID, row.Role,
+	); err != nil {
+		return "MEMBER_ADD_FAILED", fmt.Errorf("organization add user query error: %v", err)
+	}
+
+	return "", nil
+}
@@ -8,13 +8,13 @@ import (
 	"go.uber.org/zap"
 )
 
-// TeamPokerList gets a list of team poker games
+// TeamPokerList gets a list of the team poker games that aren't archived
 func (d *Service) TeamPokerList(ctx context.Context, teamID string, limit int, offset int) []*thunderdome.Poker {
 	var pokers = make([]*thunderdome.Poker, 0)
 	rows, err := d.DB.QueryContext(ctx,
 		`SELECT p.id, p.name
         FROM thunderdome.poker p
-        WHERE p.team_id = $1
+        WHERE p.team_id = $1 AND p.archived_at IS NULL
         ORDER BY p.created_date DESC
 		LIMIT $2
 		OFFSET $3;`,
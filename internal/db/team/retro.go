@@ -14,7 +14,7 @@ func (d *Service) TeamRetroList(ctx context.Context, teamID string, limit int, o
 	rows, err := d.DB.QueryContext(ctx,
 		`SELECT r.id, r.name, r.template_id, r.phase
         FROM thunderdome.retro r
-        WHERE r.team_id = $1
+        WHERE r.team_id = $1 AND r.deleted_at IS NULL
         ORDER BY r.created_date DESC
 		LIMIT $2
 		OFFSET $3;`,
@@ -0,0 +1,104 @@
+package team
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+// retroCadenceCacheTTL is the longest the team's retro cadence is cached, new retros show up within the hour
+const retroCadenceCacheTTL = time.Hour
+
+// retroCadenceRow is a team retro with the time since the team's previous retro, Gap is invalid for the first retro
+type retroCadenceRow struct {
+	CreatedDate time.Time
+	Gap         sql.NullFloat64
+}
+
+// GetRetroCadenceStats gets how often the team holds retros, based on the gaps between successive retro created dates
+func (d *Service) GetRetroCadenceStats(ctx context.Context, teamID string) (*thunderdome.RetroCadenceStats, error) {
+	cacheKey := retroCadenceCacheKey(teamID)
+	ttl := thunderdome.CappedTTL(d.CacheTTL.TeamTTL, retroCadenceCacheTTL)
+	if d.Redis != nil && ttl > 0 {
+		if cachedData, err := d.Redis.Get(ctx, cacheKey).Result(); err == nil {
+			var stats thunderdome.RetroCadenceStats
+			if err := json.Unmarshal([]byte(cachedData), &stats); err == nil {
+				d.Logger.Ctx(ctx).Debug("Team retro cadence cache hit", zap.String("team_id", teamID))
+				return &stats, nil
+			}
+		}
+	}
+
+	rows, err := d.DB.QueryContext(ctx,
+		`SELECT r.created_date,
+			EXTRACT(EPOCH FROM r.created_date - LAG(r.created_date) OVER (ORDER BY r.created_date))
+		FROM thunderdome.retro r
+		WHERE r.team_id = $1 AND r.deleted_at IS NULL
+		ORDER BY r.created_date;`,
+		teamID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get team retro cadence query error: %v", err)
+	}
+	defer rows.Close()
+
+	retros := make([]retroCadenceRow, 0)
+	for rows.Next() {
+		var r retroCadenceRow
+		if err := rows.Scan(&r.CreatedDate, &r.Gap); err != nil {
+			return nil, fmt.Errorf("get team retro cadence query scan error: %v", err)
+		}
+		retros = append(retros, r)
+	}
+
+	stats := computeRetroCadence(retros)
+
+	if d.Redis != nil && ttl > 0 {
+		if statsJSON, err := json.Marshal(stats); err == nil {
+			if err := d.Redis.Set(ctx, cacheKey, statsJSON, ttl).Err(); err != nil {
+				d.Logger.Ctx(ctx).Error("Failed to set team retro cadence cache", zap.Error(err),
+					zap.String("team_id", teamID))
+			}
+		}
+	}
+
+	return stats, nil
+}
+
+// computeRetroCadence totals the gaps between the team's retros, which are ordered by created date
+func computeRetroCadence(retros []retroCadenceRow) *thunderdome.RetroCadenceStats {
+	stats := &thunderdome.RetroCadenceStats{TotalRetros: len(retros)}
+	if len(retros) == 0 {
+		return stats
+	}
+
+	lastRetroDate := retros[len(retros)-1].CreatedDate
+	stats.LastRetroDate = &lastRetroDate
+
+	var totalGap float64
+	var gaps int
+	for _, r := range retros {
+		if !r.Gap.Valid {
+			continue
+		}
+		totalGap += r.Gap.Float64
+		gaps++
+		if gap := time.Duration(r.Gap.Float64 * float64(time.Second)); gap > stats.LongestGap {
+			stats.LongestGap = gap
+		}
+	}
+	if gaps > 0 {
+		stats.AverageDaysBetweenRetros = totalGap / float64(gaps) / (24 * time.Hour).Seconds()
+	}
+
+	return stats
+}
+
+func retroCadenceCacheKey(teamID string) string {
+	return fmt.Sprintf("team:retro-cadence:%s", teamID)
+}
@@ -0,0 +1,67 @@
+package team
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+)
+
+// retroCadenceRows builds the rows the cadence query returns for retros created on the given dates,
+// gaps are the seconds since the previous retro the same as LAG over the created date
+func retroCadenceRows(dates ...string) []retroCadenceRow {
+	rows := make([]retroCadenceRow, 0, len(dates))
+	for i, date := range dates {
+		created, _ := time.Parse(time.RFC3339, date)
+		row := retroCadenceRow{CreatedDate: created}
+		if i > 0 {
+			row.Gap = sql.NullFloat64{Float64: created.Sub(rows[i-1].CreatedDate).Seconds(), Valid: true}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows
+}
+
+// TestComputeRetroCadence makes sure the average and longest gap between retros are calculated
+func TestComputeRetroCadence(t *testing.T) {
+	stats := computeRetroCadence(retroCadenceRows(
+		"2025-01-01T10:00:00Z",
+		"2025-01-15T10:00:00Z",
+		"2025-01-29T10:00:00Z",
+		"2025-03-01T10:00:00Z",
+	))
+
+	if stats.TotalRetros != 4 {
+		t.Errorf("expected 4 retros, got %d", stats.TotalRetros)
+	}
+	if stats.AverageDaysBetweenRetros != 59.0/3 {
+		t.Errorf("expected an average of %f days, got %f", 59.0/3, stats.AverageDaysBetweenRetros)
+	}
+	if stats.LongestGap != 31*24*time.Hour {
+		t.Errorf("expected the longest gap to be 31 days, got %s", stats.LongestGap)
+	}
+	if stats.LastRetroDate == nil || stats.LastRetroDate.Format(time.DateOnly) != "2025-03-01" {
+		t.Errorf("expected the last retro date to be 2025-03-01, got %v", stats.LastRetroDate)
+	}
+}
+
+// TestComputeRetroCadenceSingleRetro makes sure a team with a single retro has no gap
+func TestComputeRetroCadenceSingleRetro(t *testing.T) {
+	stats := computeRetroCadence(retroCadenceRows("2025-01-01T10:00:00Z"))
+
+	if stats.TotalRetros != 1 || stats.LongestGap != 0 || stats.AverageDaysBetweenRetros != 0 {
+		t.Errorf("expected a single retro without gaps, got %+v", stats)
+	}
+	if stats.LastRetroDate == nil {
+		t.Error("expected the last retro date to be set")
+	}
+}
+
+// TestComputeRetroCadenceNoRetros makes sure a team without retros has empty stats
+func TestComputeRetroCadenceNoRetros(t *testing.T) {
+	stats := computeRetroCadence(nil)
+
+	if stats.TotalRetros != 0 || stats.LastRetroDate != nil {
+		t.Errorf("expected empty stats, got %+v", stats)
+	}
+}
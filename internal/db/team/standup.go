@@ -0,0 +1,255 @@
+package team
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/db"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+// standupDigestLimit is the number of most recent standups per user included in the digest
+const standupDigestLimit = 5
+
+// CreateStandup logs a team users daily standup, replacing any standup they already logged that day
+func (d *CheckinService) CreateStandup(
+	ctx context.Context,
+	teamID string, userID string,
+	yesterday string, today string, blockers string,
+) (*thunderdome.Standup, error) {
+	var userCount int
+	// target user must be on team to log a standup
+	usrErr := d.DB.QueryRowContext(ctx, `SELECT count(user_id) FROM thunderdome.team_user WHERE team_id = $1 AND user_id = $2;`,
+		teamID,
+		userID,
+	).Scan(&userCount)
+	if usrErr != nil {
+		return nil, fmt.Errorf("standup create get team user error: %v", usrErr)
+	}
+	if userCount != 1 {
+		return nil, errors.New("REQUIRES_TEAM_USER")
+	}
+
+	standup := thunderdome.Standup{}
+	err := d.DB.QueryRowContext(ctx, `INSERT INTO thunderdome.standup
+		(team_id, user_id, yesterday, today, blockers)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (team_id, user_id, standup_date) DO UPDATE
+		SET yesterday = EXCLUDED.yesterday, today = EXCLUDED.today,
+			blockers = EXCLUDED.blockers, updated_date = NOW()
+		RETURNING id, team_id, user_id, COALESCE(yesterday, ''), COALESCE(today, ''),
+			COALESCE(blockers, ''), standup_date;
+		`,
+		teamID,
+		userID,
+		d.HTMLSanitizerPolicy.Sanitize(yesterday),
+		d.HTMLSanitizerPolicy.Sanitize(today),
+		d.HTMLSanitizerPolicy.Sanitize(blockers),
+	).Scan(
+		&standup.ID,
+		&standup.TeamID,
+		&standup.UserID,
+		&standup.Yesterday,
+		&standup.Today,
+		&standup.Blockers,
+		&standup.Date,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("standup create error: %v", err)
+	}
+
+	return &standup, nil
+}
+
+// GetStandupsByTeam gets a list of team standups between the start and end dates (inclusive)
+func (d *CheckinService) GetStandupsByTeam(ctx context.Context, teamID string, startDate time.Time, endDate time.Time) ([]*thunderdome.Standup, error) {
+	standups := make([]*thunderdome.Standup, 0)
+
+	rows, err := d.DB.QueryContext(ctx, `SELECT
+		s.id, s.team_id, s.user_id, COALESCE(s.yesterday, ''), COALESCE(s.today, ''),
+		COALESCE(s.blockers, ''), s.standup_date
+		FROM thunderdome.standup s
+		WHERE s.team_id = $1 AND s.standup_date BETWEEN $2::date AND $3::date
+		ORDER BY s.standup_date DESC, s.created_date DESC;
+		`,
+		teamID,
+		startDate.Format(time.DateOnly),
+		endDate.Format(time.DateOnly),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get team standups query error: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var standup thunderdome.Standup
+		if err := rows.Scan(
+			&standup.ID,
+			&standup.TeamID,
+			&standup.UserID,
+			&standup.Yesterday,
+			&standup.Today,
+			&standup.Blockers,
+			&standup.Date,
+		); err != nil {
+			d.Logger.Ctx(ctx).Error("get team standups query scan error", zap.Error(err))
+		} else {
+			standups = append(standups, &standup)
+		}
+	}
+
+	return standups, nil
+}
+
+// DeleteStandup deletes a team standup
+func (d *CheckinService) DeleteStandup(ctx context.Context, teamID string, standupID string) error {
+	_, err := d.DB.ExecContext(ctx,
+		`DELETE FROM thunderdome.standup WHERE team_id = $1 AND id = $2;`,
+		teamID,
+		standupID,
+	)
+	if err != nil {
+		return fmt.Errorf("standup delete query error: %v", err)
+	}
+
+	return nil
+}
+
+// GetStandupDigest gets the team users with their most recent standups between the start and end dates
+func (d *CheckinService) GetStandupDigest(ctx context.Context, teamID string, startDate time.Time, endDate time.Time) ([]*thunderdome.StandupDigestEntry, error) {
+	members := make([]*thunderdome.TeamUser, 0)
+
+	rows, err := d.DB.QueryContext(ctx,
+		`SELECT u.id, u.name, COALESCE(u.email, ''), tu.role, u.avatar, COALESCE(u.picture, '')
+        FROM thunderdome.team_user tu
+        JOIN thunderdome.users u ON tu.user_id = u.id
+        WHERE tu.team_id = $1
+        ORDER BY u.name;`,
+		teamID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("standup digest team users query error: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var usr thunderdome.TeamUser
+		if err := rows.Scan(
+			&usr.ID,
+			&usr.Name,
+			&usr.Email,
+			&usr.Role,
+			&usr.Avatar,
+			&usr.PictureURL,
+		); err != nil {
+			d.Logger.Ctx(ctx).Error("standup digest team users query scan error", zap.Error(err))
+		} else {
+			usr.GravatarHash = db.CreateGravatarHash(usr.Email)
+			members = append(members, &usr)
+		}
+	}
+
+	standups, err := d.GetStandupsByTeam(ctx, teamID, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildStandupDigest(members, standups, startDate, endDate, standupDigestLimit), nil
+}
+
+// StandupDigestTeams gets the teams with at least one standup between the start and end dates
+func (d *CheckinService) StandupDigestTeams(ctx context.Context, startDate time.Time, endDate time.Time) ([]*thunderdome.Team, error) {
+	teams := make([]*thunderdome.Team, 0)
+
+	rows, err := d.DB.QueryContext(ctx,
+		`SELECT t.id, t.name FROM thunderdome.team t
+		WHERE EXISTS (
+			SELECT 1 FROM thunderdome.standup s
+			WHERE s.team_id = t.id AND s.standup_date BETWEEN $1::date AND $2::date
+		)
+		ORDER BY t.name;`,
+		startDate.Format(time.DateOnly),
+		endDate.Format(time.DateOnly),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("standup digest teams query error: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var team thunderdome.Team
+		if err := rows.Scan(&team.ID, &team.Name); err != nil {
+			d.Logger.Ctx(ctx).Error("standup digest teams query scan error", zap.Error(err))
+		} else {
+			teams = append(teams, &team)
+		}
+	}
+
+	return teams, nil
+}
+
+// ClaimStandupDigest claims sending the team's standup digest that came due at dueAt, only one claim
+// succeeds for each due time so multiple instances running the scheduler don't send duplicate digests
+func (d *CheckinService) ClaimStandupDigest(ctx context.Context, teamID string, dueAt time.Time) (bool, error) {
+	result, err := d.DB.ExecContext(ctx,
+		`INSERT INTO thunderdome.team_standup_digest (team_id, last_sent_at) VALUES ($1, $2)
+		ON CONFLICT (team_id) DO UPDATE SET last_sent_at = EXCLUDED.last_sent_at
+		WHERE thunderdome.team_standup_digest.last_sent_at < EXCLUDED.last_sent_at;`,
+		teamID, dueAt,
+	)
+	if err != nil {
+		return false, fmt.Errorf("claim standup digest query error: %v", err)
+	}
+
+	claimed, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("claim standup digest query error: %v", err)
+	}
+
+	return claimed == 1, nil
+}
+
+// buildStandupDigest groups standups within the date range by team user, keeping each users most
+// recent {limit} standups and omitting users without any
+func buildStandupDigest(
+	members []*thunderdome.TeamUser, standups []*thunderdome.Standup,
+	startDate time.Time, endDate time.Time, limit int,
+) []*thunderdome.StandupDigestEntry {
+	start := startDate.Format(time.DateOnly)
+	end := endDate.Format(time.DateOnly)
+	byUser := make(map[string][]*thunderdome.Standup)
+
+	for _, standup := range standups {
+		date := standup.Date.Format(time.DateOnly)
+		if date < start || date > end {
+			continue
+		}
+		byUser[standup.UserID] = append(byUser[standup.UserID], standup)
+	}
+
+	digest := make([]*thunderdome.StandupDigestEntry, 0)
+	for _, member := range members {
+		userStandups, ok := byUser[member.ID]
+		if !ok {
+			continue
+		}
+
+		sort.SliceStable(userStandups, func(i, j int) bool {
+			return userStandups[i].Date.After(userStandups[j].Date)
+		})
+		if len(userStandups) > limit {
+			userStandups = userStandups[:limit]
+		}
+
+		digest = append(digest, &thunderdome.StandupDigestEntry{
+			User:     member,
+			Standups: userStandups,
+		})
+	}
+
+	return digest
+}
@@ -0,0 +1,78 @@
+package team
+
+import (
+	"testing"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+func standupOn(userID string, date string) *thunderdome.Standup {
+	d, _ := time.Parse(time.DateOnly, date)
+	return &thunderdome.Standup{ID: userID + date, UserID: userID, Date: d}
+}
+
+// TestBuildStandupDigestDateRange makes sure only standups within the date range are included
+func TestBuildStandupDigestDateRange(t *testing.T) {
+	members := []*thunderdome.TeamUser{{ID: "a", Name: "Alice"}}
+	standups := []*thunderdome.Standup{
+		standupOn("a", "2025-02-28"),
+		standupOn("a", "2025-03-03"),
+		standupOn("a", "2025-03-05"),
+		standupOn("a", "2025-03-07"),
+		standupOn("a", "2025-03-08"),
+	}
+	start, _ := time.Parse(time.DateOnly, "2025-03-03")
+	end, _ := time.Parse(time.DateOnly, "2025-03-07")
+
+	digest := buildStandupDigest(members, standups, start, end, standupDigestLimit)
+	if len(digest) != 1 {
+		t.Fatalf("expected 1 digest entry, got %d", len(digest))
+	}
+	if len(digest[0].Standups) != 3 {
+		t.Fatalf("expected 3 standups within range, got %d", len(digest[0].Standups))
+	}
+	for _, s := range digest[0].Standups {
+		if s.Date.Before(start) || s.Date.After(end) {
+			t.Errorf("standup dated %s is outside of the range", s.Date.Format(time.DateOnly))
+		}
+	}
+	if got := digest[0].Standups[0].Date.Format(time.DateOnly); got != "2025-03-07" {
+		t.Errorf("expected most recent standup first, got %s", got)
+	}
+}
+
+// TestBuildStandupDigestMembers makes sure every team user with at least one standup is included
+// with no more than the limit of standups, and users without standups are left out
+func TestBuildStandupDigestMembers(t *testing.T) {
+	members := []*thunderdome.TeamUser{
+		{ID: "a", Name: "Alice"},
+		{ID: "b", Name: "Bob"},
+		{ID: "c", Name: "Carol"},
+	}
+	standups := []*thunderdome.Standup{
+		standupOn("a", "2025-03-01"),
+		standupOn("a", "2025-03-02"),
+		standupOn("a", "2025-03-03"),
+		standupOn("a", "2025-03-04"),
+		standupOn("a", "2025-03-05"),
+		standupOn("a", "2025-03-06"),
+		standupOn("c", "2025-03-04"),
+	}
+	start, _ := time.Parse(time.DateOnly, "2025-03-01")
+	end, _ := time.Parse(time.DateOnly, "2025-03-07")
+
+	digest := buildStandupDigest(members, standups, start, end, standupDigestLimit)
+	if len(digest) != 2 {
+		t.Fatalf("expected 2 digest entries, got %d", len(digest))
+	}
+	if digest[0].User.ID != "a" || digest[1].User.ID != "c" {
+		t.Fatalf("expected digest entries for users a and c, got %s and %s", digest[0].User.ID, digest[1].User.ID)
+	}
+	if len(digest[0].Standups) != standupDigestLimit {
+		t.Errorf("expected %d standups for user a, got %d", standupDigestLimit, len(digest[0].Standups))
+	}
+	if len(digest[1].Standups) != 1 {
+		t.Errorf("expected 1 standup for user c, got %d", len(digest[1].Standups))
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/redis/go-redis/v9"
 	"github.com/uptrace/opentelemetry-go-extra/otelzap"
 
 	"go.uber.org/zap"
@@ -15,6 +16,11 @@ import (
 type Service struct {
 	DB     *sql.DB
 	Logger *otelzap.Logger
+	Redis  *redis.Client
+	// CacheTTL is how long team data is cached, a zero TTL disables the cache
+	CacheTTL thunderdome.CacheTTLConfig
+	// ActivityScoreWeights are the points each activity adds to a team member's activity score
+	ActivityScoreWeights thunderdome.ActivityScoreWeights
 }
 
 // TeamGetByID gets a team by ID
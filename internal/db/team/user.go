@@ -173,6 +173,30 @@ func (d *Service) TeamAddUser(ctx context.Context, teamID string, userID string,
 	return teamID, nil
 }
 
+// TeamAddUserByNames adds a user to every team of the organization matching one of the team names,
+// existing memberships are left untouched
+func (d *Service) TeamAddUserByNames(ctx context.Context, organizationID string, userID string, teamNames []string, role string) error {
+	if len(teamNames) == 0 {
+		return nil
+	}
+
+	_, err := d.DB.ExecContext(ctx,
+		`INSERT INTO thunderdome.team_user (team_id, user_id, role)
+		SELECT t.id, $1, $3 FROM thunderdome.team t WHERE t.organization_id = $4 AND t.name = ANY($2)
+		ON CONFLICT (team_id, user_id) DO NOTHING;`,
+		userID,
+		teamNames,
+		role,
+		organizationID,
+	)
+
+	if err != nil {
+		return fmt.Errorf("team add user by names query error: %v", err)
+	}
+
+	return nil
+}
+
 // TeamUpdateUser updates a team user
 func (d *Service) TeamUpdateUser(ctx context.Context, teamID string, userID string, role string) (string, error) {
 	_, err := d.DB.ExecContext(ctx,
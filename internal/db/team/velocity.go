@@ -0,0 +1,157 @@
+package team
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+// velocityTrendCacheTTL keeps the trend fresh enough for planning without re-aggregating on every chart load
+const velocityTrendCacheTTL = 2 * time.Hour
+
+// velocityStory is a story of a completed poker game, Points is empty for games without pointed stories
+type velocityStory struct {
+	PokerID     string
+	CreatedDate time.Time
+	Points      string
+}
+
+// GetEstimationVelocityTrend gets the team's completed poker games, stories and points grouped by the week or month
+// the game was created in, between the since and until days inclusive. A game is completed once every story
+// has been pointed or skipped.
+func (d *Service) GetEstimationVelocityTrend(ctx context.Context, teamID string, granularity string, since time.Time, until time.Time) ([]thunderdome.VelocityDataPoint, error) {
+	switch granularity {
+	case thunderdome.VelocityGranularityWeek, thunderdome.VelocityGranularityMonth:
+	default:
+		return nil, fmt.Errorf("INVALID_VELOCITY_GRANULARITY")
+	}
+
+	cacheKey := velocityTrendCacheKey(teamID, granularity, since, until)
+	ttl := thunderdome.CappedTTL(d.CacheTTL.TeamTTL, velocityTrendCacheTTL)
+	if d.Redis != nil && ttl > 0 {
+		if cachedData, err := d.Redis.Get(ctx, cacheKey).Result(); err == nil {
+			var trend []thunderdome.VelocityDataPoint
+			if err := json.Unmarshal([]byte(cachedData), &trend); err == nil {
+				d.Logger.Ctx(ctx).Debug("Team velocity trend cache hit", zap.String("team_id", teamID))
+				return trend, nil
+			}
+		}
+	}
+
+	rows, err := d.DB.QueryContext(ctx,
+		`SELECT p.id, p.created_date, COALESCE(ps.points, '')
+		FROM thunderdome.poker p
+		LEFT JOIN thunderdome.poker_story ps ON ps.poker_id = p.id AND ps.points <> '' AND ps.skipped IS NOT TRUE
+		WHERE p.team_id = $1 AND p.created_date >= $2 AND p.created_date < $3
+			AND EXISTS (SELECT 1 FROM thunderdome.poker_story s WHERE s.poker_id = p.id)
+			AND NOT EXISTS (
+				SELECT 1 FROM thunderdome.poker_story s
+				WHERE s.poker_id = p.id AND COALESCE(s.points, '') = '' AND s.skipped IS NOT TRUE
+			);`,
+		teamID, startOfDay(since), startOfDay(until).AddDate(0, 0, 1),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get team velocity trend query error: %v", err)
+	}
+	defer rows.Close()
+
+	stories := make([]velocityStory, 0)
+	for rows.Next() {
+		var s velocityStory
+		if err := rows.Scan(&s.PokerID, &s.CreatedDate, &s.Points); err != nil {
+			d.Logger.Ctx(ctx).Error("get team velocity trend query scan error", zap.Error(err))
+			continue
+		}
+		stories = append(stories, s)
+	}
+
+	trend := computeVelocityTrend(stories, granularity, since, until)
+
+	if d.Redis != nil && ttl > 0 {
+		if trendJSON, err := json.Marshal(trend); err == nil {
+			if err := d.Redis.Set(ctx, cacheKey, trendJSON, ttl).Err(); err != nil {
+				d.Logger.Ctx(ctx).Error("Failed to set team velocity trend cache", zap.Error(err),
+					zap.String("team_id", teamID))
+			}
+		}
+	}
+
+	return trend, nil
+}
+
+// computeVelocityTrend groups the completed games stories into every period between since and until,
+// periods without completed games are included so the trend has no gaps
+func computeVelocityTrend(stories []velocityStory, granularity string, since time.Time, until time.Time) []thunderdome.VelocityDataPoint {
+	trend := make([]thunderdome.VelocityDataPoint, 0)
+	periodIndex := make(map[string]int)
+	last := truncateVelocityPeriod(until, granularity)
+	for period := truncateVelocityPeriod(since, granularity); !period.After(last); period = nextVelocityPeriod(period, granularity) {
+		key := velocityPeriodKey(period, granularity)
+		periodIndex[key] = len(trend)
+		trend = append(trend, thunderdome.VelocityDataPoint{Period: key})
+	}
+
+	countedGames := make(map[string]bool)
+	for _, story := range stories {
+		idx, ok := periodIndex[velocityPeriodKey(truncateVelocityPeriod(story.CreatedDate, granularity), granularity)]
+		if !ok {
+			continue
+		}
+
+		if !countedGames[story.PokerID] {
+			countedGames[story.PokerID] = true
+			trend[idx].GamesCompleted++
+		}
+		if strings.TrimSpace(story.Points) == "" {
+			continue
+		}
+
+		trend[idx].StoriesCompleted++
+		if points, ok := thunderdome.ParsePointValue(story.Points); ok {
+			trend[idx].PointsCompleted += points
+		}
+	}
+
+	return trend
+}
+
+// truncateVelocityPeriod truncates the time (in UTC) to the start of its ISO week (Monday) or month
+func truncateVelocityPeriod(t time.Time, granularity string) time.Time {
+	day := startOfDay(t)
+	if granularity == thunderdome.VelocityGranularityMonth {
+		return day.AddDate(0, 0, 1-day.Day())
+	}
+
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
+
+func nextVelocityPeriod(period time.Time, granularity string) time.Time {
+	if granularity == thunderdome.VelocityGranularityMonth {
+		return period.AddDate(0, 1, 0)
+	}
+
+	return period.AddDate(0, 0, 7)
+}
+
+func velocityPeriodKey(period time.Time, granularity string) string {
+	if granularity == thunderdome.VelocityGranularityMonth {
+		return period.Format("2006-01")
+	}
+
+	return period.Format(time.DateOnly)
+}
+
+func startOfDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func velocityTrendCacheKey(teamID string, granularity string, since time.Time, until time.Time) string {
+	return fmt.Sprintf("team:velocity-trend:%s:%s:%s:%s",
+		teamID, granularity, since.UTC().Format(time.DateOnly), until.UTC().Format(time.DateOnly))
+}
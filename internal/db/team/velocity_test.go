@@ -0,0 +1,68 @@
+package team
+
+import (
+	"testing"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+func velocityGameStories(pokerID string, created string, points ...string) []velocityStory {
+	d, _ := time.Parse(time.RFC3339, created)
+	stories := make([]velocityStory, 0, len(points))
+	for _, p := range points {
+		stories = append(stories, velocityStory{PokerID: pokerID, CreatedDate: d, Points: p})
+	}
+	return stories
+}
+
+func velocityTestStories() []velocityStory {
+	stories := velocityGameStories("a", "2025-01-30T10:00:00Z", "3", "5")
+	stories = append(stories, velocityGameStories("b", "2025-01-31T23:30:00Z", "8", "?")...)
+	stories = append(stories, velocityGameStories("c", "2025-02-01T00:15:00Z", "1/2", "2")...)
+	stories = append(stories, velocityGameStories("d", "2025-02-10T09:00:00Z", "")...)
+	return stories
+}
+
+// TestComputeVelocityTrendMonthBoundary makes sure games either side of a month boundary are grouped into their own months
+func TestComputeVelocityTrendMonthBoundary(t *testing.T) {
+	since, _ := time.Parse(time.DateOnly, "2025-01-15")
+	until, _ := time.Parse(time.DateOnly, "2025-02-15")
+
+	trend := computeVelocityTrend(velocityTestStories(), thunderdome.VelocityGranularityMonth, since, until)
+
+	expected := []thunderdome.VelocityDataPoint{
+		{Period: "2025-01", PointsCompleted: 16, StoriesCompleted: 4, GamesCompleted: 2},
+		{Period: "2025-02", PointsCompleted: 2.5, StoriesCompleted: 2, GamesCompleted: 2},
+	}
+	if len(trend) != len(expected) {
+		t.Fatalf("expected %d periods, got %d", len(expected), len(trend))
+	}
+	for i := range expected {
+		if trend[i] != expected[i] {
+			t.Errorf("expected period %d to be %+v, got %+v", i, expected[i], trend[i])
+		}
+	}
+}
+
+// TestComputeVelocityTrendWeekAcrossMonthBoundary makes sure a week spanning a month boundary groups all of its games together
+func TestComputeVelocityTrendWeekAcrossMonthBoundary(t *testing.T) {
+	since, _ := time.Parse(time.DateOnly, "2025-01-27")
+	until, _ := time.Parse(time.DateOnly, "2025-02-16")
+
+	trend := computeVelocityTrend(velocityTestStories(), thunderdome.VelocityGranularityWeek, since, until)
+
+	expected := []thunderdome.VelocityDataPoint{
+		{Period: "2025-01-27", PointsCompleted: 18.5, StoriesCompleted: 6, GamesCompleted: 3},
+		{Period: "2025-02-03"},
+		{Period: "2025-02-10", GamesCompleted: 1},
+	}
+	if len(trend) != len(expected) {
+		t.Fatalf("expected %d periods, got %d", len(expected), len(trend))
+	}
+	for i := range expected {
+		if trend[i] != expected[i] {
+			t.Errorf("expected period %d to be %+v, got %+v", i, expected[i], trend[i])
+		}
+	}
+}
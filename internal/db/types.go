@@ -23,12 +23,17 @@ type Config struct {
 	MaxIdleConns           int
 	ConnMaxLifetime        int
 	DefaultEstimationScale []string
+	ReadReplicaHost        string
+	ReadReplicaPort        int
+	ReadReplicaUser        string
+	ReadReplicaPassword    string
 }
 
 // Service contains all the methods to interact with DB
 type Service struct {
 	Config              *Config
 	DB                  *sql.DB
+	ReadDB              *sql.DB
 	HTMLSanitizerPolicy *bluemonday.Policy
 	Logger              *otelzap.Logger
 }
@@ -0,0 +1,103 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+func sessionsInvalidatedCacheKey(userID string) string {
+	return fmt.Sprintf("user:session_invalidated:%s", userID)
+}
+
+// InvalidateAllSessions logs the user out of every device, the user's sessions are deleted and
+// sessions issued before now are no longer accepted
+func (d *Service) InvalidateAllSessions(ctx context.Context, userID string) error {
+	tx, err := d.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("invalidate user sessions begin transaction error: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var invalidatedAt time.Time
+	err = tx.QueryRowContext(ctx,
+		`UPDATE thunderdome.users SET sessions_invalidated_at = NOW(), updated_date = NOW()
+		WHERE id = $1 RETURNING sessions_invalidated_at;`,
+		userID,
+	).Scan(&invalidatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("USER_NOT_FOUND")
+		}
+		return fmt.Errorf("invalidate user sessions query error: %v", err)
+	}
+
+	// deleting the sessions also logs the user out of the websocket connections which only check the session exists
+	if _, err := tx.ExecContext(ctx, `DELETE FROM thunderdome.user_session WHERE user_id = $1;`, userID); err != nil {
+		return fmt.Errorf("invalidate user sessions delete query error: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("invalidate user sessions commit error: %v", err)
+	}
+
+	if d.Redis != nil {
+		if d.CacheTTL.UserTTL > 0 {
+			d.Redis.Set(ctx, sessionsInvalidatedCacheKey(userID), invalidatedAt.Format(time.RFC3339Nano), d.CacheTTL.UserTTL)
+		} else {
+			// a value cached before caching was disabled would still allow the invalidated sessions
+			d.Redis.Del(ctx, sessionsInvalidatedCacheKey(userID))
+		}
+	}
+
+	return nil
+}
+
+// GetSessionsInvalidatedAt gets when the user's sessions were last invalidated, nil when they never have been
+func (d *Service) GetSessionsInvalidatedAt(ctx context.Context, userID string) (*time.Time, error) {
+	cacheKey := sessionsInvalidatedCacheKey(userID)
+	if d.Redis != nil && d.CacheTTL.UserTTL > 0 {
+		if cached, err := d.Redis.Get(ctx, cacheKey).Result(); err == nil {
+			return parseSessionsInvalidatedAt(cached)
+		}
+	}
+
+	var invalidatedAt sql.NullTime
+	err := d.DB.QueryRowContext(ctx,
+		`SELECT sessions_invalidated_at FROM thunderdome.users WHERE id = $1;`,
+		userID,
+	).Scan(&invalidatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("USER_NOT_FOUND")
+		}
+		return nil, fmt.Errorf("get user sessions invalidated at query error: %v", err)
+	}
+
+	// users that never invalidated their sessions are cached too, as most users never do
+	cached := ""
+	if invalidatedAt.Valid {
+		cached = invalidatedAt.Time.Format(time.RFC3339Nano)
+	}
+	if d.Redis != nil && d.CacheTTL.UserTTL > 0 {
+		d.Redis.Set(ctx, cacheKey, cached, d.CacheTTL.UserTTL)
+	}
+
+	return parseSessionsInvalidatedAt(cached)
+}
+
+// parseSessionsInvalidatedAt parses the cached sessions invalidated at, empty meaning never invalidated
+func parseSessionsInvalidatedAt(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	invalidatedAt, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return nil, fmt.Errorf("parse user sessions invalidated at error: %v", err)
+	}
+
+	return &invalidatedAt, nil
+}
@@ -0,0 +1,24 @@
+package user
+
+import (
+	"testing"
+	"time"
+)
+
+// TestParseSessionsInvalidatedAt makes sure cached invalidated at values round trip, empty meaning never invalidated
+func TestParseSessionsInvalidatedAt(t *testing.T) {
+	invalidatedAt, err := parseSessionsInvalidatedAt("")
+	if err != nil || invalidatedAt != nil {
+		t.Errorf("expected no invalidated at for an empty value, got %v %v", invalidatedAt, err)
+	}
+
+	expected := time.Date(2025, 3, 16, 7, 0, 0, 123456000, time.UTC)
+	invalidatedAt, err = parseSessionsInvalidatedAt(expected.Format(time.RFC3339Nano))
+	if err != nil || invalidatedAt == nil || !invalidatedAt.Equal(expected) {
+		t.Errorf("expected %v, got %v %v", expected, invalidatedAt, err)
+	}
+
+	if _, err := parseSessionsInvalidatedAt("yesterday"); err == nil {
+		t.Error("expected an error for an invalid value")
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/StevenWeathers/thunderdome-planning-poker/internal/db"
 
 	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/redis/go-redis/v9"
 	"github.com/uptrace/opentelemetry-go-extra/otelzap"
 
 	"go.uber.org/zap"
@@ -17,6 +18,11 @@ import (
 type Service struct {
 	DB     *sql.DB
 	Logger *otelzap.Logger
+	Redis  *redis.Client
+	// CacheTTL is how long user data is cached, a zero TTL disables the cache
+	CacheTTL thunderdome.CacheTTLConfig
+	// RequireRegistrationApproval creates self registered users pending admin approval
+	RequireRegistrationApproval bool
 }
 
 // GetRegisteredUsers gets a list of registered users
@@ -36,7 +42,8 @@ func (d *Service) GetRegisteredUsers(ctx context.Context, limit int, offset int)
 	rows, err := d.DB.QueryContext(ctx,
 		`
 		SELECT u.id, u.name, COALESCE(u.email, ''), u.type, u.avatar, u.verified, COALESCE(u.country, ''),
-		 COALESCE(u.company, ''), COALESCE(u.job_title, ''), u.disabled, COALESCE(u.picture, '')
+		 COALESCE(u.company, ''), COALESCE(u.job_title, ''), u.disabled, COALESCE(u.picture, ''),
+		 u.registration_status
 		FROM thunderdome.users u
 		WHERE u.type <> 'GUEST'
 		ORDER BY u.created_date
@@ -65,6 +72,7 @@ func (d *Service) GetRegisteredUsers(ctx context.Context, limit int, offset int)
 			&w.JobTitle,
 			&w.Disabled,
 			&w.Picture,
+			&w.RegistrationStatus,
 		); err != nil {
 			d.Logger.Ctx(ctx).Error("registered_users_list query scan error", zap.Error(err))
 		} else {
@@ -217,8 +225,14 @@ func (d *Service) CreateUserRegistered(ctx context.Context, userName string, use
 		GravatarHash: db.CreateGravatarHash(userEmail),
 	}
 
+	tx, err := d.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("create registered user begin transaction error: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
 	if activeUserID != "" {
-		err := d.DB.QueryRowContext(ctx,
+		err := tx.QueryRowContext(ctx,
 			`SELECT userId, verifyId FROM thunderdome.user_register_existing($1, $2, $3, $4, $5);`,
 			activeUserID,
 			userName,
@@ -230,7 +244,7 @@ func (d *Service) CreateUserRegistered(ctx context.Context, userName string, use
 			return nil, "", fmt.Errorf("create registered user from guest query error: %v", err)
 		}
 	} else {
-		err := d.DB.QueryRow(
+		err := tx.QueryRowContext(ctx,
 			`SELECT userId, verifyId FROM thunderdome.user_register($1, $2, $3, $4);`,
 			userName,
 			sanitizedEmail,
@@ -242,6 +256,23 @@ func (d *Service) CreateUserRegistered(ctx context.Context, userName string, use
 		}
 	}
 
+	user.RegistrationStatus = thunderdome.RegistrationStatusApproved
+	// users provisioned by an external identity provider (LDAP, header auth) have no password
+	// and are approved by that provider
+	if d.RequireRegistrationApproval && userPassword != "" {
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE thunderdome.users SET registration_status = $2 WHERE id = $1;`,
+			user.ID, thunderdome.RegistrationStatusPending,
+		); err != nil {
+			return nil, "", fmt.Errorf("create registered user registration status query error: %v", err)
+		}
+		user.RegistrationStatus = thunderdome.RegistrationStatusPending
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, "", fmt.Errorf("create registered user commit error: %v", err)
+	}
+
 	return user, verificationID, nil
 }
 
@@ -275,6 +306,14 @@ func (d *Service) CreateUser(ctx context.Context, name string, email string, pas
 		return nil, "", fmt.Errorf("create registered user query error: %v", err)
 	}
 
+	// admin created accounts are exempt from organization SSO enforcement
+	if _, err := d.DB.ExecContext(ctx,
+		`UPDATE thunderdome.users SET sso_exempt = true WHERE id = $1;`,
+		user.ID,
+	); err != nil {
+		return nil, "", fmt.Errorf("create registered user sso exempt query error: %v", err)
+	}
+
 	return user, verifyID, nil
 }
 
@@ -84,6 +84,15 @@ func HashString(s string) string {
 	return result
 }
 
+// HashIPAddress salts and hashes the ip address using SHA256 so it can be compared without being stored (not reversible)
+func HashIPAddress(ip string, salt string) string {
+	if ip == "" {
+		return ""
+	}
+
+	return HashString(salt + ip)
+}
+
 // HashSaltPassword takes a password byte then salt + hashes it returning a hash string
 func HashSaltPassword(password string) (string, error) {
 	pwd := []byte(password)
@@ -24,3 +24,60 @@ func TestHashString(t *testing.T) {
 		t.Fatalf(`expected HashedResult1: %s to match HashedString: %s`, HashedResult1, HashedString)
 	}
 }
+
+// TestHashIPAddress makes sure ip addresses hash consistently for the same salt,
+// differently for different salts and that an unknown ip address stays empty
+func TestHashIPAddress(t *testing.T) {
+	ip := "203.0.113.42"
+	hashed1 := HashIPAddress(ip, "salt")
+	hashed2 := HashIPAddress(ip, "salt")
+
+	if hashed1 == ip {
+		t.Fatalf(`expected hashed ip: %s to not match ip: %s`, hashed1, ip)
+	}
+
+	if hashed1 != hashed2 {
+		t.Fatalf(`expected hashed ip: %s to match hashed ip: %s`, hashed1, hashed2)
+	}
+
+	if otherSalt := HashIPAddress(ip, "pepper"); otherSalt == hashed1 {
+		t.Fatalf(`expected hashed ip with a different salt: %s to not match: %s`, otherSalt, hashed1)
+	}
+
+	if otherIP := HashIPAddress("203.0.113.43", "salt"); otherIP == hashed1 {
+		t.Fatalf(`expected hash of a different ip: %s to not match: %s`, otherIP, hashed1)
+	}
+
+	if empty := HashIPAddress("", "salt"); empty != "" {
+		t.Fatalf(`expected unknown ip to hash to an empty string, got: %s`, empty)
+	}
+}
+
+// TestReplicaConnectionString makes sure the read replica connects to its own host
+// using the primary database port and credentials unless its own are configured
+func TestReplicaConnectionString(t *testing.T) {
+	config := &Config{
+		Host: "primary", Port: 5432, User: "thor", Password: "odinson", Name: "thunderdome", SSLMode: "disable",
+		ReadReplicaHost: "replica",
+	}
+
+	expected := "host=replica port=5432 user=thor password=odinson dbname=thunderdome sslmode=disable"
+	if got := replicaConnectionString(config); got != expected {
+		t.Fatalf(`expected: %s, got: %s`, expected, got)
+	}
+
+	config.ReadReplicaPort = 5433
+	config.ReadReplicaUser = "loki"
+	config.ReadReplicaPassword = "mischief"
+	expected = "host=replica port=5433 user=loki password=mischief dbname=thunderdome sslmode=disable"
+	if got := replicaConnectionString(config); got != expected {
+		t.Fatalf(`expected: %s, got: %s`, expected, got)
+	}
+}
+
+// TestNewReadReplicaNotConfigured makes sure no replica connection is created without a replica host
+func TestNewReadReplicaNotConfigured(t *testing.T) {
+	if rdb := NewReadReplica(&Config{Host: "primary", Port: 5432}); rdb != nil {
+		t.Fatal("expected no read replica connection without a read replica host")
+	}
+}
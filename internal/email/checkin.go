@@ -0,0 +1,51 @@
+package email
+
+import (
+	"fmt"
+
+	"github.com/matcornic/hermes/v2"
+	"go.uber.org/zap"
+)
+
+// SendCheckinReminder sends a reminder to a team user who has not checked in today
+func (s *Service) SendCheckinReminder(teamID string, teamName string, userName string, userEmail string) error {
+	subject := fmt.Sprintf("Reminder to submit your %s checkin for today", teamName)
+	emailBody, err := s.generateBody(
+		hermes.Body{
+			Name: userName,
+			Intros: []string{
+				subject,
+			},
+			Actions: []hermes.Action{
+				{
+					Instructions: "Use the following link to check in with your team.",
+					Button: hermes.Button{
+						Color: "#22BC66",
+						Text:  "Check In",
+						Link:  s.Config.AppURL + "team/" + teamID + "/checkin",
+					},
+				},
+			},
+		},
+	)
+	if err != nil {
+		s.Logger.Error("Error Generating Checkin Reminder Email HTML", zap.Error(err),
+			zap.String("user_email", userEmail))
+
+		return err
+	}
+
+	sendErr := s.send(
+		userName,
+		userEmail,
+		subject,
+		emailBody,
+	)
+	if sendErr != nil {
+		s.Logger.Error("Error sending Checkin Reminder Email", zap.Error(sendErr),
+			zap.String("user_email", userEmail), zap.String("team_id", teamID))
+		return sendErr
+	}
+
+	return nil
+}
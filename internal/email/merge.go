@@ -0,0 +1,43 @@
+package email
+
+import (
+	"fmt"
+
+	"github.com/matcornic/hermes/v2"
+	"go.uber.org/zap"
+)
+
+// SendAccountMerged sends the user a notification that their duplicate account was merged into their account
+func (s *Service) SendAccountMerged(userName string, userEmail string, mergedUserEmail string) error {
+	emailBody, err := s.generateBody(
+		hermes.Body{
+			Name: userName,
+			Intros: []string{
+				fmt.Sprintf("Your duplicate Thunderdome account %s has been merged into this account by an administrator.", mergedUserEmail),
+				"Your games, teams and API keys from the duplicate account are now available here, the duplicate account can no longer be used to log in.",
+			},
+			Outros: []string{
+				"If you didn't expect this, please reach out to your Thunderdome administrator.",
+			},
+		},
+	)
+	if err != nil {
+		s.Logger.Error("Error Generating Account Merged Email HTML", zap.Error(err),
+			zap.String("user_email", userEmail))
+		return err
+	}
+
+	sendErr := s.send(
+		userName,
+		userEmail,
+		"Your Thunderdome duplicate account has been merged.",
+		emailBody,
+	)
+	if sendErr != nil {
+		s.Logger.Error("Error sending Account Merged Email", zap.Error(sendErr),
+			zap.String("user_email", userEmail))
+		return sendErr
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package email
+
+import (
+	"github.com/matcornic/hermes/v2"
+	"go.uber.org/zap"
+)
+
+// SendRegistrationRejected sends the user the reason their registration was rejected
+func (s *Service) SendRegistrationRejected(userName string, userEmail string, reason string) error {
+	emailBody, err := s.generateBody(
+		hermes.Body{
+			Name: userName,
+			Intros: []string{
+				"Your Thunderdome registration was not approved.",
+			},
+			Dictionary: []hermes.Entry{
+				{Key: "Reason", Value: reason},
+			},
+			Outros: []string{
+				"If you believe this was a mistake, please reach out to your Thunderdome administrator.",
+			},
+		},
+	)
+	if err != nil {
+		s.Logger.Error("Error Generating Registration Rejected Email HTML", zap.Error(err),
+			zap.String("user_email", userEmail))
+		return err
+	}
+
+	sendErr := s.send(
+		userName,
+		userEmail,
+		"Your Thunderdome registration was not approved.",
+		emailBody,
+	)
+	if sendErr != nil {
+		s.Logger.Error("Error sending Registration Rejected Email", zap.Error(sendErr),
+			zap.String("user_email", userEmail))
+		return sendErr
+	}
+
+	return nil
+}
@@ -69,3 +69,67 @@ func (s *Service) SendRetroOverview(retro *thunderdome.Retro, template *thunderd
 
 	return nil
 }
+
+// SendRetroActionAssigned sends the retro action item assigned email to the assignee
+func (s *Service) SendRetroActionAssigned(retro *thunderdome.Retro, action *thunderdome.RetroAction, userName string, userEmail string) error {
+	return s.sendRetroActionAssignee(retroActionAssignedSubject(retro.Name), retro, action, userName, userEmail)
+}
+
+// SendRetroActionUnassigned sends the retro action item unassigned email to the previous assignee
+func (s *Service) SendRetroActionUnassigned(retro *thunderdome.Retro, action *thunderdome.RetroAction, userName string, userEmail string) error {
+	return s.sendRetroActionAssignee(retroActionUnassignedSubject(retro.Name), retro, action, userName, userEmail)
+}
+
+func retroActionAssignedSubject(retroName string) string {
+	return fmt.Sprintf("You've been assigned a %s Retro action item", retroName)
+}
+
+func retroActionUnassignedSubject(retroName string) string {
+	return fmt.Sprintf("You've been unassigned from a %s Retro action item", retroName)
+}
+
+// sendRetroActionAssignee sends a retro action item assignment change email with a link to the retro
+func (s *Service) sendRetroActionAssignee(subject string, retro *thunderdome.Retro, action *thunderdome.RetroAction, userName string, userEmail string) error {
+	emailBody, err := s.generateBody(
+		hermes.Body{
+			Name: userName,
+			Intros: []string{
+				subject,
+			},
+			FreeMarkdown: `
+## Action Item
+` + hermes.Markdown(formatRetroItemForMarkdownList(action.Content)),
+			Actions: []hermes.Action{
+				{
+					Instructions: "Use the following link to view the retro.",
+					Button: hermes.Button{
+						Color: "#22BC66",
+						Text:  "View Retro",
+						Link:  s.Config.AppURL + "retro/" + retro.ID,
+					},
+				},
+			},
+		},
+	)
+	if err != nil {
+		s.Logger.Error("Error Generating Retro Action Assignee Email HTML", zap.Error(err),
+			zap.String("user_email", userEmail))
+
+		return err
+	}
+
+	sendErr := s.send(
+		userName,
+		userEmail,
+		subject,
+		emailBody,
+	)
+	if sendErr != nil {
+		s.Logger.Error("Error sending Retro Action Assignee Email", zap.Error(sendErr),
+			zap.String("user_email", userEmail), zap.String("retro_id", retro.ID),
+			zap.String("action_id", action.ID))
+		return sendErr
+	}
+
+	return nil
+}
@@ -0,0 +1,12 @@
+package email
+
+import "testing"
+
+func TestRetroActionAssigneeSubjects(t *testing.T) {
+	if got := retroActionAssignedSubject("Sprint 12"); got != "You've been assigned a Sprint 12 Retro action item" {
+		t.Errorf("unexpected assigned subject %q", got)
+	}
+	if got := retroActionUnassignedSubject("Sprint 12"); got != "You've been unassigned from a Sprint 12 Retro action item" {
+		t.Errorf("unexpected unassigned subject %q", got)
+	}
+}
@@ -0,0 +1,54 @@
+package email
+
+import (
+	"fmt"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/matcornic/hermes/v2"
+	"go.uber.org/zap"
+)
+
+// SendStandupDigest sends the weekly team standup digest listing each users recent standups
+func (s *Service) SendStandupDigest(teamName string, digest []*thunderdome.StandupDigestEntry, userName string, userEmail string) error {
+	var standupsList string
+	for _, entry := range digest {
+		standupsList += fmt.Sprintf(`
+## %s
+
+`, entry.User.Name)
+		for _, standup := range entry.Standups {
+			standupsList += formatStandupForMarkdownList(standup)
+		}
+	}
+
+	subject := fmt.Sprintf("Here is your %s Weekly Standup Digest", teamName)
+	emailBody, err := s.generateBody(
+		hermes.Body{
+			Name: userName,
+			Intros: []string{
+				subject,
+			},
+			FreeMarkdown: hermes.Markdown(standupsList),
+		},
+	)
+	if err != nil {
+		s.Logger.Error("Error Generating Standup Digest Email HTML", zap.Error(err),
+			zap.String("user_email", userEmail))
+
+		return err
+	}
+
+	sendErr := s.send(
+		userName,
+		userEmail,
+		subject,
+		emailBody,
+	)
+	if sendErr != nil {
+		s.Logger.Error("Error sending Standup Digest Email", zap.Error(sendErr),
+			zap.String("user_email", userEmail))
+		return sendErr
+	}
+
+	return nil
+}
@@ -26,3 +26,19 @@ func formatRetroActionWithAssignee(action *thunderdome.RetroAction) string {
 
 	return formatRetroItemForMarkdownList(actionItem)
 }
+
+// formatStandupForMarkdownList formats a standup for a markdown list
+func formatStandupForMarkdownList(standup *thunderdome.Standup) string {
+	item := fmt.Sprintf("- **%s**\n", standup.Date.Format("Mon Jan 2"))
+	if standup.Yesterday != "" {
+		item += fmt.Sprintf("  - Yesterday: %s\n", standup.Yesterday)
+	}
+	if standup.Today != "" {
+		item += fmt.Sprintf("  - Today: %s\n", standup.Today)
+	}
+	if standup.Blockers != "" {
+		item += fmt.Sprintf("  - Blockers: %s\n", standup.Blockers)
+	}
+
+	return item
+}
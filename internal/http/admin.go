@@ -1,13 +1,19 @@
 package http
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
+	"time"
 
 	"go.uber.org/zap"
 
 	"github.com/gorilla/mux"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/http/response"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
 )
 
 // handleAppStats gets the applications stats
@@ -35,15 +41,341 @@ func (s *Service) handleAppStats() http.HandlerFunc {
 	}
 }
 
-// handleGetRegisteredUsers gets a list of registered users
+// handleGetMigrationStatus gets the database migration status
 //
-//	@Summary		Get Registered Users
-//	@Description	Get list of registered users
+//	@Summary		Get Migration Status
+//	@Description	Get the current database migration version and how many migrations are pending, degraded when the migration table doesn't exist
+//	@Tags			admin
+//	@Produce		json
+//	@Success		200	object	standardJsonResponse{data=thunderdome.MigrationStatus}
+//	@Failure		500	object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/admin/migrations/status [get]
+func (s *Service) handleGetMigrationStatus() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		status, err := s.AdminDataSvc.GetMigrationStatus(ctx)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleGetMigrationStatus error", zap.Error(err), zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, status, nil)
+	}
+}
+
+// handleGetInvalidStoryLinks gets the poker stories with links that aren't valid http or https URLs
+//
+//	@Summary		Get Invalid Story Links
+//	@Description	Get the poker stories whose link isn't a valid http or https URL, saved before story links were validated
 //	@Tags			admin
 //	@Produce		json
 //	@Param			limit	query	int	false	"Max number of results to return"
 //	@Param			offset	query	int	false	"Starting point to return rows from, should be multiplied by limit or 0"
+//	@Success		200		object	standardJsonResponse{data=[]thunderdome.InvalidStoryLink}
+//	@Failure		500		object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/admin/battles/invalid-story-links [get]
+func (s *Service) handleGetInvalidStoryLinks() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		limit, offset := getLimitOffsetFromRequest(r)
+
+		links, count, err := s.AdminDataSvc.GetInvalidStoryLinks(ctx, limit, offset)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleGetInvalidStoryLinks error", zap.Error(err),
+				zap.Int("limit", limit), zap.Int("offset", offset), zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		meta := response.NewMeta(count, limit, offset)
+
+		s.Success(w, r, http.StatusOK, links, meta)
+	}
+}
+
+// handleGetPasswordPolicy gets the active password policy
+//
+//	@Summary		Get Password Policy
+//	@Description	Get the password strength policy enforced when users register or change their password
+//	@Tags			admin
+//	@Produce		json
+//	@Success		200	object	standardJsonResponse{data=thunderdome.PasswordPolicy}
+//	@Security		ApiKeyAuth
+//	@Router			/admin/config/password-policy [get]
+func (s *Service) handleGetPasswordPolicy() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.Success(w, r, http.StatusOK, s.Config.PasswordPolicy, nil)
+	}
+}
+
+type cleanupGamesRequestBody struct {
+	DaysOld int  `json:"days_old" validate:"omitempty,min=1"`
+	DryRun  bool `json:"dry_run"`
+}
+
+// handleCleanupOldGames handles cleaning up old poker games on demand
+//
+//	@Summary		Cleanup Old Games
+//	@Description	Deletes poker games older than {days_old} (defaults to {config.cleanup_battles_days_old}) based on last activity date
+//	@Description	In dry run mode nothing is deleted, the result shows what would be affected
+//	@Tags			admin
+//	@Produce		json
+//	@Param			cleanup	body	cleanupGamesRequestBody	true	"cleanup games object"
+//	@Success		200		object	standardJsonResponse{data=thunderdome.CleanupResult}
+//	@Failure		400		object	standardJsonResponse{}
+//	@Failure		500		object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/admin/cleanup/games [post]
+func (s *Service) handleCleanupOldGames() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+
+		body, bodyErr := io.ReadAll(r.Body)
+		if bodyErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, bodyErr.Error()))
+			return
+		}
+
+		var c = cleanupGamesRequestBody{}
+		jsonErr := json.Unmarshal(body, &c)
+		if jsonErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, jsonErr.Error()))
+			return
+		}
+
+		inputErr := validate.Struct(c)
+		if inputErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, inputErr.Error()))
+			return
+		}
+
+		daysOld := c.DaysOld
+		if daysOld == 0 {
+			daysOld = s.Config.CleanupBattlesDaysOld
+		}
+
+		result, err := s.AdminDataSvc.CleanupOldGames(ctx, daysOld, c.DryRun)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleCleanupOldGames error", zap.Error(err),
+				zap.String("session_user_id", sessionUserID),
+				zap.Int("days_old", daysOld),
+				zap.Bool("dry_run", c.DryRun))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, result, nil)
+	}
+}
+
+type cleanupRetrosRequestBody struct {
+	DaysOld int `json:"days_old" validate:"omitempty,min=1"`
+}
+
+// handleCleanupOldRetros handles soft deleting old retros on demand
+//
+//	@Summary		Cleanup Old Retros
+//	@Description	Soft deletes retros older than {days_old} (defaults to {config.cleanup_retros_days_old}) based on last updated date,
+//	@Description	soft deleted retros can be restored for 30 days before they're permanently deleted
+//	@Tags			admin
+//	@Produce		json
+//	@Param			cleanup	body	cleanupRetrosRequestBody	true	"cleanup retros object"
+//	@Success		200		object	standardJsonResponse{data=thunderdome.RetroCleanupResult}
+//	@Failure		400		object	standardJsonResponse{}
+//	@Failure		500		object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/admin/retros/cleanup [post]
+func (s *Service) handleCleanupOldRetros() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+
+		body, bodyErr := io.ReadAll(r.Body)
+		if bodyErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, bodyErr.Error()))
+			return
+		}
+
+		var c = cleanupRetrosRequestBody{}
+		jsonErr := json.Unmarshal(body, &c)
+		if jsonErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, jsonErr.Error()))
+			return
+		}
+
+		inputErr := validate.Struct(c)
+		if inputErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, inputErr.Error()))
+			return
+		}
+
+		daysOld := c.DaysOld
+		if daysOld == 0 {
+			daysOld = s.Config.CleanupRetrosDaysOld
+		}
+
+		result, err := s.RetroDataSvc.SoftDeleteOldRetros(ctx, daysOld)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleCleanupOldRetros error", zap.Error(err),
+				zap.String("session_user_id", sessionUserID),
+				zap.Int("days_old", daysOld))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, result, nil)
+	}
+}
+
+// handleRetroRestore handles restoring a soft deleted retro
+//
+//	@Summary		Restore Retro
+//	@Description	Restores a soft deleted retro, retros are permanently deleted 30 days after being soft deleted
+//	@Tags			admin
+//	@Produce		json
+//	@Param			retroId	path	string	true	"the retro ID"
+//	@Success		200		object	standardJsonResponse{}
+//	@Failure		400		object	standardJsonResponse{}
+//	@Failure		404		object	standardJsonResponse{}
+//	@Failure		500		object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/admin/retros/{retroId}/restore [post]
+func (s *Service) handleRetroRestore() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		vars := mux.Vars(r)
+		retroID := vars["retroId"]
+		idErr := validate.Var(retroID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		err := s.RetroDataSvc.RestoreRetro(ctx, retroID)
+		if err != nil {
+			if err.Error() == "RETRO_NOT_FOUND" {
+				s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, "RETRO_NOT_FOUND"))
+				return
+			}
+			s.Logger.Ctx(ctx).Error("handleRetroRestore error", zap.Error(err),
+				zap.String("retro_id", retroID), zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, nil, nil)
+	}
+}
+
+// handlePokerRestore handles restoring an archived poker game
+//
+//	@Summary		Restore Poker Game
+//	@Description	Restores an archived poker game
+//	@Tags			admin
+//	@Produce		json
+//	@Param			battleId	path	string	true	"the poker game ID"
+//	@Success		200			object	standardJsonResponse{}
+//	@Failure		400			object	standardJsonResponse{}
+//	@Failure		404			object	standardJsonResponse{}
+//	@Failure		500			object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/admin/battles/{battleId}/restore [post]
+func (s *Service) handlePokerRestore() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		vars := mux.Vars(r)
+		gameID := vars["battleId"]
+		idErr := validate.Var(gameID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		err := s.PokerDataSvc.RestoreGame(ctx, gameID)
+		if err != nil {
+			if err.Error() == "BATTLE_NOT_FOUND" {
+				s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, "BATTLE_NOT_FOUND"))
+				return
+			}
+			s.Logger.Ctx(ctx).Error("handlePokerRestore error", zap.Error(err),
+				zap.String("poker_id", gameID), zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, nil, nil)
+	}
+}
+
+// handlePokerRebuild handles rebuilding a poker game's state from its event log
+//
+//	@Summary		Rebuild Poker Game State
+//	@Description	Rebuilds a poker game's state by replaying its event log in order,
+//	@Description	reconciling the stored and cached game with the rebuilt state
+//	@Tags			admin
+//	@Produce		json
+//	@Param			battleId	path	string	true	"the poker game ID"
+//	@Success		200			object	standardJsonResponse{data=thunderdome.Poker}
+//	@Failure		400			object	standardJsonResponse{}
+//	@Failure		404			object	standardJsonResponse{}
+//	@Failure		409			object	standardJsonResponse{}
+//	@Failure		500			object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/admin/battles/{battleId}/rebuild [post]
+func (s *Service) handlePokerRebuild() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		vars := mux.Vars(r)
+		gameID := vars["battleId"]
+		idErr := validate.Var(gameID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		game, err := s.PokerDataSvc.RebuildGameState(ctx, gameID)
+		if err != nil {
+			switch {
+			case err.Error() == "BATTLE_NOT_FOUND":
+				s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, "BATTLE_NOT_FOUND"))
+				return
+			case errors.Is(err, thunderdome.ErrGameEventsNotFound):
+				s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, err.Error()))
+				return
+			case errors.Is(err, thunderdome.ErrGameEventSequenceGap):
+				s.Failure(w, r, http.StatusConflict, Errorf(ECONFLICT, err.Error()))
+				return
+			}
+			s.Logger.Ctx(ctx).Error("handlePokerRebuild error", zap.Error(err),
+				zap.String("poker_id", gameID), zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, game, nil)
+	}
+}
+
+// handleGetRegisteredUsers gets a list of registered users
+//
+//	@Summary		Get Registered Users
+//	@Description	Get list of registered users, optionally filtered by registration status
+//	@Tags			admin
+//	@Produce		json
+//	@Param			limit	query	int		false	"Max number of results to return"
+//	@Param			offset	query	int		false	"Starting point to return rows from, should be multiplied by limit or 0"
+//	@Param			status	query	string	false	"registration status to filter by (pending, approved, rejected)"
 //	@Success		200		object	standardJsonResponse{data=[]thunderdome.User}
+//	@Failure		400		object	standardJsonResponse{}
 //	@Failure		500		object	standardJsonResponse{}
 //	@Security		ApiKeyAuth
 //	@Router			/admin/users [get]
@@ -53,7 +385,18 @@ func (s *Service) handleGetRegisteredUsers() http.HandlerFunc {
 		sessionUserID := ctx.Value(contextKeyUserID).(string)
 		limit, offset := getLimitOffsetFromRequest(r)
 
-		users, count, err := s.UserDataSvc.GetRegisteredUsers(ctx, limit, offset)
+		var users []*thunderdome.User
+		var count int
+		var err error
+		if status := thunderdome.RegistrationStatus(r.URL.Query().Get("status")); status != "" {
+			if !status.Valid() {
+				s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "INVALID_REGISTRATION_STATUS"))
+				return
+			}
+			users, count, err = s.AdminDataSvc.GetUsersByRegistrationStatus(ctx, status, limit, offset)
+		} else {
+			users, count, err = s.UserDataSvc.GetRegisteredUsers(ctx, limit, offset)
+		}
 		if err != nil {
 			s.Logger.Ctx(ctx).Error("handleGetRegisteredUsers error", zap.Error(err),
 				zap.Int("limit", limit), zap.Int("offset", offset), zap.String("session_user_id", sessionUserID))
@@ -61,11 +404,7 @@ func (s *Service) handleGetRegisteredUsers() http.HandlerFunc {
 			return
 		}
 
-		meta := &pagination{
-			Count:  count,
-			Offset: offset,
-			Limit:  limit,
-		}
+		meta := response.NewMeta(count, limit, offset)
 
 		s.Success(w, r, http.StatusOK, users, meta)
 	}
@@ -114,6 +453,11 @@ func (s *Service) handleUserCreate() http.HandlerFunc {
 			return
 		}
 
+		if policyErr := thunderdome.ValidatePassword(user.Password1, s.Config.PasswordPolicy); policyErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, policyErr.Error()))
+			return
+		}
+
 		newUser, verifyID, err := s.UserDataSvc.CreateUser(ctx, user.Name, user.Email, user.Password1)
 		if err != nil {
 			s.Logger.Ctx(ctx).Error("handleUserCreate error", zap.Error(err),
@@ -199,6 +543,122 @@ func (s *Service) handleUserDemote() http.HandlerFunc {
 	}
 }
 
+// handleUserRegistrationApprove handles approving a users registration
+//
+//	@Summary		Approve User Registration
+//	@Description	Approve a pending or rejected user registration allowing the user to log in
+//	@Tags			admin
+//	@Produce		json
+//	@Param			userId	path	string	true	"the user ID to approve"
+//	@Success		200		object	standardJsonResponse{}
+//	@Failure		400		object	standardJsonResponse{}
+//	@Failure		404		object	standardJsonResponse{}
+//	@Failure		500		object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/admin/users/{userId}/approve [patch]
+func (s *Service) handleUserRegistrationApprove() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		userID := vars["userId"]
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		idErr := validate.Var(userID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		err := s.AdminDataSvc.ApproveUser(ctx, userID)
+		if err != nil {
+			s.registrationTransitionFailure(w, r, "handleUserRegistrationApprove", err, userID, sessionUserID)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, nil, nil)
+	}
+}
+
+type userRegistrationRejectRequestBody struct {
+	Reason string `json:"reason" validate:"required,max=1024"`
+}
+
+// handleUserRegistrationReject handles rejecting a users registration
+//
+//	@Summary		Reject User Registration
+//	@Description	Reject a pending user registration, the user is emailed the reason
+//	@Tags			admin
+//	@Produce		json
+//	@Param			userId	path	string								true	"the user ID to reject"
+//	@Param			reject	body	userRegistrationRejectRequestBody	true	"rejection reason"
+//	@Success		200		object	standardJsonResponse{}
+//	@Failure		400		object	standardJsonResponse{}
+//	@Failure		404		object	standardJsonResponse{}
+//	@Failure		500		object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/admin/users/{userId}/reject [patch]
+func (s *Service) handleUserRegistrationReject() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		userID := vars["userId"]
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		idErr := validate.Var(userID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		body, bodyErr := io.ReadAll(r.Body)
+		if bodyErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, bodyErr.Error()))
+			return
+		}
+
+		var rr = userRegistrationRejectRequestBody{}
+		jsonErr := json.Unmarshal(body, &rr)
+		if jsonErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, jsonErr.Error()))
+			return
+		}
+
+		inputErr := validate.Struct(rr)
+		if inputErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, inputErr.Error()))
+			return
+		}
+
+		err := s.AdminDataSvc.RejectUser(ctx, userID, rr.Reason)
+		if err != nil {
+			s.registrationTransitionFailure(w, r, "handleUserRegistrationReject", err, userID, sessionUserID)
+			return
+		}
+
+		user, userErr := s.UserDataSvc.GetUserByID(ctx, userID)
+		if userErr != nil {
+			s.Logger.Ctx(ctx).Error("handleUserRegistrationReject error", zap.Error(userErr),
+				zap.String("entity_user_id", userID), zap.String("session_user_id", sessionUserID))
+		} else if user.Email != "" {
+			_ = s.Email.SendRegistrationRejected(user.Name, user.Email, rr.Reason)
+		}
+
+		s.Success(w, r, http.StatusOK, nil, nil)
+	}
+}
+
+// registrationTransitionFailure responds with the failure of a user registration status update
+func (s *Service) registrationTransitionFailure(w http.ResponseWriter, r *http.Request, handler string, err error, userID string, sessionUserID string) {
+	switch {
+	case errors.Is(err, thunderdome.ErrInvalidRegistrationTransition):
+		s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, err.Error()))
+	case err.Error() == "USER_NOT_FOUND":
+		s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, "USER_NOT_FOUND"))
+	default:
+		s.Logger.Ctx(r.Context()).Error(handler+" error", zap.Error(err),
+			zap.String("entity_user_id", userID), zap.String("session_user_id", sessionUserID))
+		s.Failure(w, r, http.StatusInternalServerError, err)
+	}
+}
+
 // handleUserDisable handles disabling a user
 //
 //	@Summary		Disable User
@@ -269,6 +729,169 @@ func (s *Service) handleUserEnable() http.HandlerFunc {
 	}
 }
 
+type userMergeRequestBody struct {
+	TargetUserID string `json:"targetUserId" validate:"required,uuid"`
+}
+
+// handleUserMerge handles merging a duplicate user into another user
+//
+//	@Summary		Merge User
+//	@Description	Merges a duplicate user into the target user, moving their games, teams, organizations, votes, retro items and API keys.
+//	@Description	The duplicate user can no longer log in, the merge can't be undone.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			userId	path	string					true	"the duplicate user ID to merge"
+//	@Param			merge	body	userMergeRequestBody	true	"the user to merge into"
+//	@Success		200		object	standardJsonResponse{}
+//	@Failure		400		object	standardJsonResponse{}
+//	@Failure		404		object	standardJsonResponse{}
+//	@Failure		500		object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/admin/users/{userId}/merge [post]
+func (s *Service) handleUserMerge() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		userID := vars["userId"]
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		idErr := validate.Var(userID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		body, bodyErr := io.ReadAll(r.Body)
+		if bodyErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, bodyErr.Error()))
+			return
+		}
+
+		var mr = userMergeRequestBody{}
+		jsonErr := json.Unmarshal(body, &mr)
+		if jsonErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, jsonErr.Error()))
+			return
+		}
+
+		inputErr := validate.Struct(mr)
+		if inputErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, inputErr.Error()))
+			return
+		}
+
+		err := s.AdminDataSvc.MergeUsers(ctx, userID, mr.TargetUserID)
+		if err != nil {
+			switch {
+			case errors.Is(err, thunderdome.ErrInvalidUserMerge):
+				s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, err.Error()))
+			case err.Error() == "USER_NOT_FOUND":
+				s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, "USER_NOT_FOUND"))
+			default:
+				s.Logger.Ctx(ctx).Error("handleUserMerge error", zap.Error(err),
+					zap.String("entity_user_id", userID), zap.String("target_user_id", mr.TargetUserID),
+					zap.String("session_user_id", sessionUserID))
+				s.Failure(w, r, http.StatusInternalServerError, err)
+			}
+			return
+		}
+
+		s.sendAccountMerged(ctx, userID, mr.TargetUserID, sessionUserID)
+
+		s.Success(w, r, http.StatusOK, nil, nil)
+	}
+}
+
+// sendAccountMerged emails the target user that the duplicate user was merged into their account
+func (s *Service) sendAccountMerged(ctx context.Context, sourceUserID string, targetUserID string, sessionUserID string) {
+	source, err := s.UserDataSvc.GetUserByID(ctx, sourceUserID)
+	if err != nil {
+		s.Logger.Ctx(ctx).Error("handleUserMerge error", zap.Error(err),
+			zap.String("entity_user_id", sourceUserID), zap.String("session_user_id", sessionUserID))
+		return
+	}
+	target, err := s.UserDataSvc.GetUserByID(ctx, targetUserID)
+	if err != nil {
+		s.Logger.Ctx(ctx).Error("handleUserMerge error", zap.Error(err),
+			zap.String("entity_user_id", targetUserID), zap.String("session_user_id", sessionUserID))
+		return
+	}
+	if target.Email != "" {
+		_ = s.Email.SendAccountMerged(target.Name, target.Email, source.Email)
+	}
+}
+
+type userAnonymizeRequestBody struct {
+	UserID string `json:"userId" validate:"required,uuid"`
+}
+
+// handleUserAnonymize handles anonymizing a user for privacy compliance requests
+//
+//	@Summary		Anonymize User
+//	@Description	Replaces the user's name and email with anonymized values and clears their profile,
+//	@Description	their games are kept showing them as anonymous. Their login is disabled and their identities,
+//	@Description	API keys and sessions are deleted. The request body must repeat the user ID
+//	@Description	to confirm, the anonymization can't be undone.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			userId		path	string						true	"the user ID to anonymize"
+//	@Param			anonymize	body	userAnonymizeRequestBody	true	"the user ID again to confirm"
+//	@Success		200			object	standardJsonResponse{}
+//	@Failure		400			object	standardJsonResponse{}
+//	@Failure		404			object	standardJsonResponse{}
+//	@Failure		500			object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/admin/users/{userId}/anonymize [post]
+func (s *Service) handleUserAnonymize() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		userID := vars["userId"]
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		idErr := validate.Var(userID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		body, bodyErr := io.ReadAll(r.Body)
+		if bodyErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, bodyErr.Error()))
+			return
+		}
+
+		var ar = userAnonymizeRequestBody{}
+		jsonErr := json.Unmarshal(body, &ar)
+		if jsonErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, jsonErr.Error()))
+			return
+		}
+
+		inputErr := validate.Struct(ar)
+		if inputErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, inputErr.Error()))
+			return
+		}
+		if ar.UserID != userID {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "ANONYMIZE_CONFIRMATION_MISMATCH"))
+			return
+		}
+
+		err := s.AdminDataSvc.AnonymizeUser(ctx, userID)
+		if err != nil {
+			if err.Error() == "USER_NOT_FOUND" {
+				s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, "USER_NOT_FOUND"))
+				return
+			}
+			s.Logger.Ctx(ctx).Error("handleUserAnonymize error", zap.Error(err),
+				zap.String("entity_user_id", userID), zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, nil, nil)
+	}
+}
+
 // handleAdminUpdateUserPassword attempts to update a user's password
 //
 //	@Summary		Update Password
@@ -312,6 +935,11 @@ func (s *Service) handleAdminUpdateUserPassword() http.HandlerFunc {
 			return
 		}
 
+		if policyErr := thunderdome.ValidatePassword(u.Password1, s.Config.PasswordPolicy); policyErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, policyErr.Error()))
+			return
+		}
+
 		userName, userEmail, updateErr := s.AuthDataSvc.UserUpdatePassword(ctx, userID, u.Password1)
 		if updateErr != nil {
 			s.Logger.Ctx(ctx).Error("handleAdminUpdateUserPassword error", zap.Error(updateErr),
@@ -372,11 +1000,7 @@ func (s *Service) handleGetTeams() http.HandlerFunc {
 
 		teams, count := s.TeamDataSvc.TeamList(ctx, limit, offset)
 
-		meta := &pagination{
-			Count:  count,
-			Offset: offset,
-			Limit:  limit,
-		}
+		meta := response.NewMeta(count, limit, offset)
 
 		s.Success(w, r, http.StatusOK, teams, meta)
 	}
@@ -439,12 +1063,59 @@ func (s *Service) handleSearchRegisteredUsersByEmail() http.HandlerFunc {
 			return
 		}
 
-		meta := &pagination{
-			Count:  count,
-			Offset: offset,
-			Limit:  limit,
-		}
+		meta := response.NewMeta(count, limit, offset)
 
 		s.Success(w, r, http.StatusOK, users, meta)
 	}
 }
+
+// estimationBiasDefaultWeeks is the number of weeks of votes analyzed when no since date is given
+const estimationBiasDefaultWeeks = 26
+
+// handleGetUserEstimationBias gets how a user's votes compare to the final points of the stories they voted on
+//
+//	@Summary		Get User Estimation Bias
+//	@Description	Get the user's average deviation from final story points along with their under and over estimation rates,
+//	@Description	based on their vote in each finalized story's last voting round
+//	@Tags			admin
+//	@Produce		json
+//	@Param			userId	path	string	true	"the user ID"
+//	@Param			since	query	string	false	"the first day to include (YYYY-MM-DD), defaults to 26 weeks ago"
+//	@Success		200		object	standardJsonResponse{data=thunderdome.EstimationBias}
+//	@Failure		400		object	standardJsonResponse{}
+//	@Failure		500		object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/admin/users/{userId}/estimation-bias [get]
+func (s *Service) handleGetUserEstimationBias() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		userID := vars["userId"]
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		idErr := validate.Var(userID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		since := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -7*estimationBiasDefaultWeeks)
+		if v := r.URL.Query().Get("since"); v != "" {
+			d, err := time.Parse(time.DateOnly, v)
+			if err != nil {
+				s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "INVALID_DATE"))
+				return
+			}
+			since = d
+		}
+
+		bias, err := s.AdminDataSvc.GetUserEstimationBias(ctx, userID, since)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleGetUserEstimationBias error", zap.Error(err),
+				zap.String("entity_user_id", userID), zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, bias, nil)
+	}
+}
@@ -0,0 +1,136 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+type subscriptionOverrideRequestBody struct {
+	Tier      string    `json:"tier" validate:"required,oneof=free team enterprise" example:"enterprise"`
+	ExpiresAt time.Time `json:"expiresAt" validate:"required"`
+	Reason    string    `json:"reason" validate:"required,max=500" example:"extended sales trial"`
+}
+
+type subscriptionExtendRequestBody struct {
+	Days int `json:"days" validate:"required,min=1,max=366" example:"30"`
+}
+
+// readAdminSubscriptionRequest reads and validates the request's organization ID and body
+func (s *Service) readAdminSubscriptionRequest(w http.ResponseWriter, r *http.Request, requestBody any) (string, bool) {
+	orgID := mux.Vars(r)["orgId"]
+	if idErr := validate.Var(orgID, "required,uuid"); idErr != nil {
+		s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+		return "", false
+	}
+
+	body, bodyErr := io.ReadAll(r.Body)
+	if bodyErr != nil {
+		s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, bodyErr.Error()))
+		return "", false
+	}
+	if jsonErr := json.Unmarshal(body, requestBody); jsonErr != nil {
+		s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, jsonErr.Error()))
+		return "", false
+	}
+	if inputErr := validate.Struct(requestBody); inputErr != nil {
+		s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, inputErr.Error()))
+		return "", false
+	}
+
+	return orgID, true
+}
+
+// adminSubscriptionFailure responds with the status matching an admin subscription data service error
+func (s *Service) adminSubscriptionFailure(w http.ResponseWriter, r *http.Request, handler string, orgID string, err error) {
+	switch {
+	case err.Error() == "SUBSCRIPTION_NOT_FOUND", err.Error() == "ORGANIZATION_ADMIN_NOT_FOUND":
+		s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, err.Error()))
+	case errors.Is(err, thunderdome.ErrInvalidSubscriptionTier), errors.Is(err, thunderdome.ErrInvalidSubscriptionExtension):
+		s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, err.Error()))
+	default:
+		s.Logger.Ctx(r.Context()).Error(handler+" error", zap.Error(err),
+			zap.String("organization_id", orgID), zap.String("session_user_id", r.Context().Value(contextKeyUserID).(string)))
+		s.Failure(w, r, http.StatusInternalServerError, err)
+	}
+}
+
+// handleSubscriptionOverride handles overriding an organization's subscription tier and expiry
+//
+//	@Summary		Override Organization Subscription
+//	@Description	Sets the organization's subscription tier and expiry directly bypassing the payment processor,
+//	@Description	organizations without a subscription get one owned by their first admin and the free tier deactivates it.
+//	@Description	The override is recorded in the admin audit log with the admin and their reason.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			orgId		path	string							true	"the organization ID"
+//	@Param			override	body	subscriptionOverrideRequestBody	true	"subscription override object"
+//	@Success		200			object	standardJsonResponse{}
+//	@Failure		400			object	standardJsonResponse{}
+//	@Failure		404			object	standardJsonResponse{}
+//	@Failure		500			object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/admin/organizations/{orgId}/subscription/override [post]
+func (s *Service) handleSubscriptionOverride() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+
+		var or = subscriptionOverrideRequestBody{}
+		orgID, ok := s.readAdminSubscriptionRequest(w, r, &or)
+		if !ok {
+			return
+		}
+
+		err := s.AdminDataSvc.OverrideSubscription(ctx, sessionUserID, orgID, or.Tier, or.ExpiresAt, or.Reason)
+		if err != nil {
+			s.adminSubscriptionFailure(w, r, "handleSubscriptionOverride", orgID, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, nil, nil)
+	}
+}
+
+// handleSubscriptionExtend handles extending an organization's subscription
+//
+//	@Summary		Extend Organization Subscription
+//	@Description	Adds the days to the expiry of the organization's active subscription, a lapsed subscription
+//	@Description	is extended from today. The extension is recorded in the admin audit log.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			orgId	path	string							true	"the organization ID"
+//	@Param			extend	body	subscriptionExtendRequestBody	true	"subscription extension object"
+//	@Success		200		object	standardJsonResponse{}
+//	@Failure		400		object	standardJsonResponse{}
+//	@Failure		404		object	standardJsonResponse{}
+//	@Failure		500		object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/admin/organizations/{orgId}/subscription/extend [post]
+func (s *Service) handleSubscriptionExtend() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+
+		var er = subscriptionExtendRequestBody{}
+		orgID, ok := s.readAdminSubscriptionRequest(w, r, &er)
+		if !ok {
+			return
+		}
+
+		err := s.AdminDataSvc.ExtendSubscription(ctx, sessionUserID, orgID, er.Days)
+		if err != nil {
+			s.adminSubscriptionFailure(w, r, "handleSubscriptionExtend", orgID, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, nil, nil)
+	}
+}
@@ -0,0 +1,111 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+const testSubscriptionOrgID = "b23e4567-e89b-12d3-a456-426614174000"
+
+func (m *MockAdminDataSvc) OverrideSubscription(ctx context.Context, actorID string, orgID string, tier string, expiresAt time.Time, reason string) error {
+	args := m.Called(ctx, actorID, orgID, tier, expiresAt, reason)
+	return args.Error(0)
+}
+
+func (m *MockAdminDataSvc) ExtendSubscription(ctx context.Context, actorID string, orgID string, days int) error {
+	args := m.Called(ctx, actorID, orgID, days)
+	return args.Error(0)
+}
+
+func adminSubscriptionRequest(target string, body string) *http.Request {
+	req := httptest.NewRequest("POST", target, bytes.NewBufferString(body))
+	req = mux.SetURLVars(req, map[string]string{"orgId": testSubscriptionOrgID})
+	return req.WithContext(context.WithValue(req.Context(), contextKeyUserID, testFacilitatorID))
+}
+
+func TestHandleSubscriptionOverride(t *testing.T) {
+	expiresAt := time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)
+	validBody := `{"tier":"enterprise","expiresAt":"2026-01-31T00:00:00Z","reason":"extended sales trial"}`
+
+	tests := []struct {
+		name         string
+		body         string
+		overrides    bool
+		overrideErr  error
+		expectedCode int
+	}{
+		{name: "overridden", body: validBody, overrides: true, expectedCode: http.StatusOK},
+		{name: "unknown tier", body: `{"tier":"platinum","expiresAt":"2026-01-31T00:00:00Z","reason":"trial"}`, expectedCode: http.StatusBadRequest},
+		{name: "missing reason", body: `{"tier":"team","expiresAt":"2026-01-31T00:00:00Z"}`, expectedCode: http.StatusBadRequest},
+		{name: "missing expiry", body: `{"tier":"team","reason":"trial"}`, expectedCode: http.StatusBadRequest},
+		{name: "no organization admin", body: validBody, overrides: true, overrideErr: fmt.Errorf("ORGANIZATION_ADMIN_NOT_FOUND"), expectedCode: http.StatusNotFound},
+		{name: "query error", body: validBody, overrides: true, overrideErr: fmt.Errorf("override subscription query error"), expectedCode: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAdminDataSvc := new(MockAdminDataSvc)
+			service := &Service{AdminDataSvc: mockAdminDataSvc, Logger: otelzap.New(zap.NewNop())}
+			if tt.overrides {
+				// the session admin is recorded as the override's actor in the audit log
+				mockAdminDataSvc.On("OverrideSubscription", mock.Anything, testFacilitatorID, testSubscriptionOrgID,
+					"enterprise", expiresAt, "extended sales trial").Return(tt.overrideErr)
+			}
+
+			rr := httptest.NewRecorder()
+			req := adminSubscriptionRequest("/admin/organizations/"+testSubscriptionOrgID+"/subscription/override", tt.body)
+			service.handleSubscriptionOverride().ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+			mockAdminDataSvc.AssertExpectations(t)
+			if !tt.overrides {
+				mockAdminDataSvc.AssertNotCalled(t, "OverrideSubscription")
+			}
+		})
+	}
+}
+
+func TestHandleSubscriptionExtend(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		extends      bool
+		extendErr    error
+		expectedCode int
+	}{
+		{name: "extended", body: `{"days":30}`, extends: true, expectedCode: http.StatusOK},
+		{name: "zero days", body: `{"days":0}`, expectedCode: http.StatusBadRequest},
+		{name: "too many days", body: `{"days":400}`, expectedCode: http.StatusBadRequest},
+		{name: "no active subscription", body: `{"days":30}`, extends: true, extendErr: fmt.Errorf("SUBSCRIPTION_NOT_FOUND"), expectedCode: http.StatusNotFound},
+		{name: "invalid extension", body: `{"days":30}`, extends: true, extendErr: thunderdome.ErrInvalidSubscriptionExtension, expectedCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAdminDataSvc := new(MockAdminDataSvc)
+			service := &Service{AdminDataSvc: mockAdminDataSvc, Logger: otelzap.New(zap.NewNop())}
+			if tt.extends {
+				mockAdminDataSvc.On("ExtendSubscription", mock.Anything, testFacilitatorID, testSubscriptionOrgID, 30).Return(tt.extendErr)
+			}
+
+			rr := httptest.NewRecorder()
+			req := adminSubscriptionRequest("/admin/organizations/"+testSubscriptionOrgID+"/subscription/extend", tt.body)
+			service.handleSubscriptionExtend().ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+			mockAdminDataSvc.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,696 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+// MockAdminDataSvc is a mock implementation of the AdminDataSvc
+type MockAdminDataSvc struct {
+	mock.Mock
+}
+
+func (m *MockAdminDataSvc) GetAppStats(ctx context.Context) (*thunderdome.ApplicationStats, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m *MockAdminDataSvc) CleanupOldGames(ctx context.Context, daysOld int, dryRun bool) (*thunderdome.CleanupResult, error) {
+	args := m.Called(ctx, daysOld, dryRun)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*thunderdome.CleanupResult), args.Error(1)
+}
+
+func (m *MockAdminDataSvc) ApproveUser(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockAdminDataSvc) RejectUser(ctx context.Context, userID string, reason string) error {
+	args := m.Called(ctx, userID, reason)
+	return args.Error(0)
+}
+
+func (m *MockAdminDataSvc) GetEstimationCalibration(ctx context.Context, orgID string, since time.Time) (*thunderdome.CalibrationReport, error) {
+	args := m.Called(ctx, orgID, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*thunderdome.CalibrationReport), args.Error(1)
+}
+
+func (m *MockAdminDataSvc) GetUserEstimationBias(ctx context.Context, userID string, since time.Time) (*thunderdome.EstimationBias, error) {
+	args := m.Called(ctx, userID, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*thunderdome.EstimationBias), args.Error(1)
+}
+
+func (m *MockAdminDataSvc) GetMigrationStatus(ctx context.Context) (*thunderdome.MigrationStatus, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m *MockAdminDataSvc) GetInvalidStoryLinks(ctx context.Context, limit int, offset int) ([]*thunderdome.InvalidStoryLink, int, error) {
+	args := m.Called(ctx, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*thunderdome.InvalidStoryLink), args.Int(1), args.Error(2)
+}
+
+func (m *MockAdminDataSvc) GetUsersByRegistrationStatus(ctx context.Context, status thunderdome.RegistrationStatus, limit int, offset int) ([]*thunderdome.User, int, error) {
+	args := m.Called(ctx, status, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*thunderdome.User), args.Int(1), args.Error(2)
+}
+
+func (m *MockAdminDataSvc) MergeUsers(ctx context.Context, sourceUserID string, targetUserID string) error {
+	args := m.Called(ctx, sourceUserID, targetUserID)
+	return args.Error(0)
+}
+
+func (m *MockAdminDataSvc) AnonymizeUser(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func TestHandleCleanupOldGames(t *testing.T) {
+	gameIDs := make([]string, 50)
+	for i := range gameIDs {
+		gameIDs[i] = fmt.Sprintf("game-%d", i)
+	}
+
+	mockAdminDataSvc := new(MockAdminDataSvc)
+	service := &Service{
+		Config:       &Config{CleanupBattlesDaysOld: 180},
+		AdminDataSvc: mockAdminDataSvc,
+	}
+
+	mockAdminDataSvc.On("CleanupOldGames", mock.Anything, 30, true).
+		Return(&thunderdome.CleanupResult{DryRun: true, GameCount: 50, StoryCount: 100, GameIDs: gameIDs}, nil).Once()
+	mockAdminDataSvc.On("CleanupOldGames", mock.Anything, 30, false).
+		Return(&thunderdome.CleanupResult{GameCount: 50, StoryCount: 100, GameIDs: gameIDs}, nil).Once()
+	mockAdminDataSvc.On("CleanupOldGames", mock.Anything, 30, true).
+		Return(&thunderdome.CleanupResult{DryRun: true, GameIDs: []string{}}, nil).Once()
+
+	runCleanup := func(body string) (int, thunderdome.CleanupResult) {
+		req := httptest.NewRequest("POST", "/admin/cleanup/games", strings.NewReader(body))
+		req = req.WithContext(context.WithValue(req.Context(), contextKeyUserID, "323e4567-e89b-12d3-a456-426614174000"))
+		rr := httptest.NewRecorder()
+		service.handleCleanupOldGames().ServeHTTP(rr, req)
+
+		var response struct {
+			Data thunderdome.CleanupResult `json:"data"`
+		}
+		err := json.Unmarshal(rr.Body.Bytes(), &response)
+		assert.NoError(t, err)
+
+		return rr.Code, response.Data
+	}
+
+	code, result := runCleanup(`{"days_old":30,"dry_run":true}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.True(t, result.DryRun)
+	assert.Equal(t, 50, result.GameCount)
+	assert.Len(t, result.GameIDs, 50)
+
+	code, result = runCleanup(`{"days_old":30,"dry_run":false}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.False(t, result.DryRun)
+	assert.Equal(t, 50, result.GameCount)
+
+	code, result = runCleanup(`{"days_old":30,"dry_run":true}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 0, result.GameCount)
+
+	mockAdminDataSvc.AssertExpectations(t)
+}
+
+func TestHandleCleanupOldGamesDefaultsDaysOld(t *testing.T) {
+	mockAdminDataSvc := new(MockAdminDataSvc)
+	service := &Service{
+		Config:       &Config{CleanupBattlesDaysOld: 180},
+		AdminDataSvc: mockAdminDataSvc,
+	}
+
+	mockAdminDataSvc.On("CleanupOldGames", mock.Anything, 180, true).
+		Return(&thunderdome.CleanupResult{DryRun: true, GameIDs: []string{}}, nil).Once()
+
+	req := httptest.NewRequest("POST", "/admin/cleanup/games", strings.NewReader(`{"dry_run":true}`))
+	req = req.WithContext(context.WithValue(req.Context(), contextKeyUserID, "323e4567-e89b-12d3-a456-426614174000"))
+	rr := httptest.NewRecorder()
+	service.handleCleanupOldGames().ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockAdminDataSvc.AssertExpectations(t)
+}
+
+func TestHandleGetPasswordPolicy(t *testing.T) {
+	policy := thunderdome.PasswordPolicy{
+		MinLength:        10,
+		MaxLength:        64,
+		RequireUppercase: true,
+		RequireDigit:     true,
+	}
+	service := &Service{
+		Config: &Config{PasswordPolicy: policy},
+	}
+
+	req := httptest.NewRequest("GET", "/admin/config/password-policy", nil)
+	rr := httptest.NewRecorder()
+	service.handleGetPasswordPolicy().ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var response struct {
+		Success bool                       `json:"success"`
+		Data    thunderdome.PasswordPolicy `json:"data"`
+	}
+	err := json.Unmarshal(rr.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.True(t, response.Success)
+	assert.Equal(t, policy, response.Data)
+}
+
+func (m *MockEmailService) SendRegistrationRejected(userName string, userEmail string, reason string) error {
+	args := m.Called(userName, userEmail, reason)
+	return args.Error(0)
+}
+
+const testPendingUserID = "423e4567-e89b-12d3-a456-426614174000"
+
+// registrationAdminDataSvc transitions registrations through the registration state machine
+type registrationAdminDataSvc struct {
+	*MockAdminDataSvc
+	statuses map[string]thunderdome.RegistrationStatus
+}
+
+func (m *registrationAdminDataSvc) transition(userID string, to thunderdome.RegistrationStatus) error {
+	from, ok := m.statuses[userID]
+	if !ok {
+		return fmt.Errorf("USER_NOT_FOUND")
+	}
+	if err := thunderdome.ValidateRegistrationTransition(from, to); err != nil {
+		return err
+	}
+	m.statuses[userID] = to
+	return nil
+}
+
+func (m *registrationAdminDataSvc) ApproveUser(ctx context.Context, userID string) error {
+	return m.transition(userID, thunderdome.RegistrationStatusApproved)
+}
+
+func (m *registrationAdminDataSvc) RejectUser(ctx context.Context, userID string, reason string) error {
+	return m.transition(userID, thunderdome.RegistrationStatusRejected)
+}
+
+func registrationStatusAdminDataSvc(statuses map[string]thunderdome.RegistrationStatus) *registrationAdminDataSvc {
+	return &registrationAdminDataSvc{MockAdminDataSvc: new(MockAdminDataSvc), statuses: statuses}
+}
+
+func TestHandleUserRegistrationStateMachine(t *testing.T) {
+	tests := []struct {
+		name               string
+		from               thunderdome.RegistrationStatus
+		action             string
+		expectedStatusCode int
+		expectedStatus     thunderdome.RegistrationStatus
+		expectEmail        bool
+	}{
+		{name: "approve pending", from: thunderdome.RegistrationStatusPending, action: "approve", expectedStatusCode: http.StatusOK, expectedStatus: thunderdome.RegistrationStatusApproved},
+		{name: "reject pending", from: thunderdome.RegistrationStatusPending, action: "reject", expectedStatusCode: http.StatusOK, expectedStatus: thunderdome.RegistrationStatusRejected, expectEmail: true},
+		{name: "approve rejected", from: thunderdome.RegistrationStatusRejected, action: "approve", expectedStatusCode: http.StatusOK, expectedStatus: thunderdome.RegistrationStatusApproved},
+		{name: "reject rejected", from: thunderdome.RegistrationStatusRejected, action: "reject", expectedStatusCode: http.StatusBadRequest, expectedStatus: thunderdome.RegistrationStatusRejected},
+		{name: "approve approved", from: thunderdome.RegistrationStatusApproved, action: "approve", expectedStatusCode: http.StatusBadRequest, expectedStatus: thunderdome.RegistrationStatusApproved},
+		{name: "reject approved", from: thunderdome.RegistrationStatusApproved, action: "reject", expectedStatusCode: http.StatusBadRequest, expectedStatus: thunderdome.RegistrationStatusApproved},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statuses := map[string]thunderdome.RegistrationStatus{testPendingUserID: tt.from}
+			mockAdminDataSvc := registrationStatusAdminDataSvc(statuses)
+			mockUserDataSvc := new(MockUserDataService)
+			mockEmailSvc := new(MockEmailService)
+			service := &Service{
+				Config:       &Config{},
+				Logger:       otelzap.New(zap.NewNop()),
+				AdminDataSvc: mockAdminDataSvc,
+				UserDataSvc:  mockUserDataSvc,
+				Email:        mockEmailSvc,
+			}
+			if tt.expectEmail {
+				mockUserDataSvc.On("GetUserByID", mock.Anything, testPendingUserID).
+					Return(&thunderdome.User{ID: testPendingUserID, Name: "Thor", Email: "thor@thunderdome.dev"}, nil)
+				mockEmailSvc.On("SendRegistrationRejected", "Thor", "thor@thunderdome.dev", "unknown domain").Return(nil)
+			}
+
+			handler := service.handleUserRegistrationApprove()
+			body := ""
+			if tt.action == "reject" {
+				handler = service.handleUserRegistrationReject()
+				body = `{"reason":"unknown domain"}`
+			}
+			req := httptest.NewRequest("PATCH", "/admin/users/"+testPendingUserID+"/"+tt.action, strings.NewReader(body))
+			req = mux.SetURLVars(req, map[string]string{"userId": testPendingUserID})
+			req = req.WithContext(context.WithValue(req.Context(), contextKeyUserID, "323e4567-e89b-12d3-a456-426614174000"))
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tt.expectedStatus, statuses[testPendingUserID])
+			if tt.expectedStatusCode == http.StatusBadRequest {
+				var response standardJsonResponse
+				assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+				assert.Equal(t, "INVALID_REGISTRATION_STATUS_TRANSITION", response.Error)
+			}
+			mockUserDataSvc.AssertExpectations(t)
+			mockEmailSvc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandleUserRegistrationNotFound(t *testing.T) {
+	service := &Service{
+		Config:       &Config{},
+		Logger:       otelzap.New(zap.NewNop()),
+		AdminDataSvc: registrationStatusAdminDataSvc(map[string]thunderdome.RegistrationStatus{}),
+	}
+
+	req := httptest.NewRequest("PATCH", "/admin/users/"+testPendingUserID+"/approve", nil)
+	req = mux.SetURLVars(req, map[string]string{"userId": testPendingUserID})
+	req = req.WithContext(context.WithValue(req.Context(), contextKeyUserID, "323e4567-e89b-12d3-a456-426614174000"))
+	rr := httptest.NewRecorder()
+	service.handleUserRegistrationApprove().ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestHandleUserRegistrationRejectRequiresReason(t *testing.T) {
+	mockAdminDataSvc := new(MockAdminDataSvc)
+	service := &Service{
+		Config:       &Config{},
+		Logger:       otelzap.New(zap.NewNop()),
+		AdminDataSvc: mockAdminDataSvc,
+	}
+
+	req := httptest.NewRequest("PATCH", "/admin/users/"+testPendingUserID+"/reject", strings.NewReader(`{"reason":""}`))
+	req = mux.SetURLVars(req, map[string]string{"userId": testPendingUserID})
+	req = req.WithContext(context.WithValue(req.Context(), contextKeyUserID, "323e4567-e89b-12d3-a456-426614174000"))
+	rr := httptest.NewRecorder()
+	service.handleUserRegistrationReject().ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockAdminDataSvc.AssertNotCalled(t, "RejectUser", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHandleGetRegisteredUsersByStatus(t *testing.T) {
+	pending := []*thunderdome.User{
+		{ID: testPendingUserID, Name: "Thor", RegistrationStatus: thunderdome.RegistrationStatusPending},
+	}
+	mockAdminDataSvc := new(MockAdminDataSvc)
+	mockAdminDataSvc.On("GetUsersByRegistrationStatus", mock.Anything, thunderdome.RegistrationStatusPending, 20, 0).
+		Return(pending, 1, nil)
+	service := &Service{
+		Config:       &Config{},
+		Logger:       otelzap.New(zap.NewNop()),
+		AdminDataSvc: mockAdminDataSvc,
+	}
+
+	getUsers := func(status string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/admin/users?status="+status, nil)
+		req = req.WithContext(context.WithValue(req.Context(), contextKeyUserID, "323e4567-e89b-12d3-a456-426614174000"))
+		rr := httptest.NewRecorder()
+		service.handleGetRegisteredUsers().ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := getUsers("pending")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response struct {
+		Data []thunderdome.User `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	if assert.Len(t, response.Data, 1) {
+		assert.Equal(t, thunderdome.RegistrationStatusPending, response.Data[0].RegistrationStatus)
+	}
+
+	rr = getUsers("unknown")
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockAdminDataSvc.AssertExpectations(t)
+}
+
+func TestHandleGetInvalidStoryLinks(t *testing.T) {
+	links := []*thunderdome.InvalidStoryLink{
+		{StoryID: "story-1", StoryName: "Login", GameID: "game-1", Link: "TBD"},
+	}
+	mockAdminDataSvc := new(MockAdminDataSvc)
+	mockAdminDataSvc.On("GetInvalidStoryLinks", mock.Anything, 20, 0).Return(links, 1, nil)
+	service := &Service{
+		Config:       &Config{},
+		Logger:       otelzap.New(zap.NewNop()),
+		AdminDataSvc: mockAdminDataSvc,
+	}
+
+	req := httptest.NewRequest("GET", "/admin/battles/invalid-story-links", nil)
+	req = req.WithContext(context.WithValue(req.Context(), contextKeyUserID, "323e4567-e89b-12d3-a456-426614174000"))
+	rr := httptest.NewRecorder()
+	service.handleGetInvalidStoryLinks().ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response struct {
+		Data []thunderdome.InvalidStoryLink `json:"data"`
+		Meta struct {
+			Count int `json:"count"`
+		} `json:"meta"`
+	}
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	if assert.Len(t, response.Data, 1) {
+		assert.Equal(t, "TBD", response.Data[0].Link)
+	}
+	assert.Equal(t, 1, response.Meta.Count)
+	mockAdminDataSvc.AssertExpectations(t)
+}
+
+func (m *MockEmailService) SendAccountMerged(userName string, userEmail string, mergedUserEmail string) error {
+	args := m.Called(userName, userEmail, mergedUserEmail)
+	return args.Error(0)
+}
+
+const (
+	testMergeSourceUserID = "523e4567-e89b-12d3-a456-426614174000"
+	testMergeTargetUserID = "623e4567-e89b-12d3-a456-426614174000"
+)
+
+// mergeAccounts tracks the users game participation and logins through a merge
+type mergeAccounts struct {
+	users        map[string]*thunderdome.User
+	participants map[string][]string
+}
+
+func (a *mergeAccounts) merge(sourceUserID string, targetUserID string) error {
+	source, sourceOk := a.users[sourceUserID]
+	target, targetOk := a.users[targetUserID]
+	if !sourceOk || !targetOk {
+		return fmt.Errorf("USER_NOT_FOUND")
+	}
+	if err := thunderdome.ValidateUserMerge(source, target); err != nil {
+		return err
+	}
+
+	for gameID, userIDs := range a.participants {
+		moved := make([]string, 0, len(userIDs))
+		for _, userID := range userIDs {
+			if userID == sourceUserID {
+				userID = targetUserID
+			}
+			if !slices.Contains(moved, userID) {
+				moved = append(moved, userID)
+			}
+		}
+		a.participants[gameID] = moved
+	}
+	source.Disabled = true
+	source.MergedIntoUserID = targetUserID
+
+	return nil
+}
+
+// mergeAdminDataSvc merges the accounts
+type mergeAdminDataSvc struct {
+	*MockAdminDataSvc
+	accounts *mergeAccounts
+}
+
+func (m *mergeAdminDataSvc) MergeUsers(ctx context.Context, sourceUserID string, targetUserID string) error {
+	return m.accounts.merge(sourceUserID, targetUserID)
+}
+
+// mergeAuthDataSvc logs in the accounts, merged accounts can't log in
+type mergeAuthDataSvc struct {
+	*MockAuthDataSvc
+	accounts *mergeAccounts
+}
+
+func (m *mergeAuthDataSvc) AuthUser(ctx context.Context, email string, password string) (*thunderdome.User, *thunderdome.Credential, string, error) {
+	for _, user := range m.accounts.users {
+		if user.Email != email {
+			continue
+		}
+		if user.MergedIntoUserID != "" {
+			return nil, nil, "", thunderdome.ErrUserMerged
+		}
+		return user, &thunderdome.Credential{MFAEnabled: true}, "session", nil
+	}
+
+	return nil, nil, "", fmt.Errorf("USER_NOT_FOUND")
+}
+
+func newMergeAccounts() *mergeAccounts {
+	return &mergeAccounts{
+		users: map[string]*thunderdome.User{
+			testMergeSourceUserID: {ID: testMergeSourceUserID, Name: "Thor", Email: "thor@asgard.dev"},
+			testMergeTargetUserID: {ID: testMergeTargetUserID, Name: "Thor Odinson", Email: "thor@thunderdome.dev"},
+		},
+		participants: map[string][]string{
+			"only-source": {testMergeSourceUserID},
+			"both":        {testMergeSourceUserID, testMergeTargetUserID},
+			"only-target": {testMergeTargetUserID},
+		},
+	}
+}
+
+func mergeUserRequest(service *Service, sourceUserID string, targetUserID string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/admin/users/"+sourceUserID+"/merge", strings.NewReader(`{"targetUserId":"`+targetUserID+`"}`))
+	req = mux.SetURLVars(req, map[string]string{"userId": sourceUserID})
+	req = req.WithContext(context.WithValue(req.Context(), contextKeyUserID, "323e4567-e89b-12d3-a456-426614174000"))
+	rr := httptest.NewRecorder()
+	service.handleUserMerge().ServeHTTP(rr, req)
+	return rr
+}
+
+func TestHandleUserMerge(t *testing.T) {
+	accounts := newMergeAccounts()
+	mockUserDataSvc := new(MockUserDataService)
+	mockEmailSvc := new(MockEmailService)
+	service := &Service{
+		Config:       &Config{},
+		Logger:       otelzap.New(zap.NewNop()),
+		AdminDataSvc: &mergeAdminDataSvc{MockAdminDataSvc: new(MockAdminDataSvc), accounts: accounts},
+		AuthDataSvc:  &mergeAuthDataSvc{MockAuthDataSvc: new(MockAuthDataSvc), accounts: accounts},
+		UserDataSvc:  mockUserDataSvc,
+		Email:        mockEmailSvc,
+	}
+	mockUserDataSvc.On("GetUserByID", mock.Anything, testMergeSourceUserID).Return(accounts.users[testMergeSourceUserID], nil)
+	mockUserDataSvc.On("GetUserByID", mock.Anything, testMergeTargetUserID).Return(accounts.users[testMergeTargetUserID], nil)
+	mockEmailSvc.On("SendAccountMerged", "Thor Odinson", "thor@thunderdome.dev", "thor@asgard.dev").Return(nil).Once()
+
+	rr := mergeUserRequest(service, testMergeSourceUserID, testMergeTargetUserID)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	for gameID, userIDs := range accounts.participants {
+		assert.Equal(t, []string{testMergeTargetUserID}, userIDs, "game %s participants", gameID)
+	}
+
+	login := func(email string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/auth", strings.NewReader(`{"email":"`+email+`","password":"infinitystones"}`))
+		rr := httptest.NewRecorder()
+		service.handleLogin().ServeHTTP(rr, req)
+		return rr
+	}
+	rr = login("thor@asgard.dev")
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	var response standardJsonResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, "USER_MERGED", response.Error)
+
+	mockSubDataSvc := new(MockSubscriptionDataService)
+	mockSubDataSvc.On("CheckActiveSubscriber", mock.Anything, testMergeTargetUserID).Return(nil)
+	service.SubscriptionDataSvc = mockSubDataSvc
+	assert.Equal(t, http.StatusOK, login("thor@thunderdome.dev").Code)
+
+	// the merge can't be repeated or reversed
+	assert.Equal(t, http.StatusBadRequest, mergeUserRequest(service, testMergeSourceUserID, testMergeTargetUserID).Code)
+	assert.Equal(t, http.StatusBadRequest, mergeUserRequest(service, testMergeTargetUserID, testMergeSourceUserID).Code)
+	mockEmailSvc.AssertExpectations(t)
+}
+
+func TestHandleUserMergeSameUser(t *testing.T) {
+	accounts := newMergeAccounts()
+	service := &Service{
+		Config:       &Config{},
+		Logger:       otelzap.New(zap.NewNop()),
+		AdminDataSvc: &mergeAdminDataSvc{MockAdminDataSvc: new(MockAdminDataSvc), accounts: accounts},
+	}
+
+	rr := mergeUserRequest(service, testMergeSourceUserID, testMergeSourceUserID)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Empty(t, accounts.users[testMergeSourceUserID].MergedIntoUserID)
+}
+
+// anonymizeAdminDataSvc anonymizes the accounts
+type anonymizeAdminDataSvc struct {
+	*MockAdminDataSvc
+	accounts *mergeAccounts
+}
+
+func (m *anonymizeAdminDataSvc) AnonymizeUser(ctx context.Context, userID string) error {
+	user, ok := m.accounts.users[userID]
+	if !ok {
+		return fmt.Errorf("USER_NOT_FOUND")
+	}
+	thunderdome.AnonymizeUser(user)
+
+	return nil
+}
+
+func anonymizeUserRequest(service *Service, userID string, confirmUserID string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/admin/users/"+userID+"/anonymize", strings.NewReader(`{"userId":"`+confirmUserID+`"}`))
+	req = mux.SetURLVars(req, map[string]string{"userId": userID})
+	req = req.WithContext(context.WithValue(req.Context(), contextKeyUserID, "323e4567-e89b-12d3-a456-426614174000"))
+	rr := httptest.NewRecorder()
+	service.handleUserAnonymize().ServeHTTP(rr, req)
+	return rr
+}
+
+func TestHandleUserAnonymize(t *testing.T) {
+	accounts := newMergeAccounts()
+	accounts.users[testMergeSourceUserID].Avatar = "robohash"
+	service := &Service{
+		Config:       &Config{},
+		Logger:       otelzap.New(zap.NewNop()),
+		AdminDataSvc: &anonymizeAdminDataSvc{MockAdminDataSvc: new(MockAdminDataSvc), accounts: accounts},
+	}
+
+	// the body must confirm the user being anonymized
+	rr := anonymizeUserRequest(service, testMergeSourceUserID, testMergeTargetUserID)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, "Thor", accounts.users[testMergeSourceUserID].Name)
+
+	rr = anonymizeUserRequest(service, testMergeSourceUserID, testMergeSourceUserID)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	user := accounts.users[testMergeSourceUserID]
+	hash := thunderdome.AnonymizedUserHash(testMergeSourceUserID)
+	assert.Equal(t, "Anonymized User "+hash, user.Name)
+	assert.Equal(t, "anonymized+"+hash+"@thunderdome.invalid", user.Email)
+	assert.Empty(t, user.Avatar)
+	assert.Equal(t, "Thor Odinson", accounts.users[testMergeTargetUserID].Name)
+
+	// the game participation is kept
+	assert.Contains(t, accounts.participants["only-source"], testMergeSourceUserID)
+	assert.Contains(t, accounts.participants["both"], testMergeSourceUserID)
+
+	assert.Equal(t, http.StatusNotFound, anonymizeUserRequest(service, "723e4567-e89b-12d3-a456-426614174000", "723e4567-e89b-12d3-a456-426614174000").Code)
+}
+
+// MockRetroDataSvc is a mock implementation of the RetroDataSvc
+type MockRetroDataSvc struct {
+	mock.Mock
+	RetroDataSvc
+}
+
+func (m *MockRetroDataSvc) SoftDeleteOldRetros(ctx context.Context, daysOld int) (*thunderdome.RetroCleanupResult, error) {
+	args := m.Called(ctx, daysOld)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*thunderdome.RetroCleanupResult), args.Error(1)
+}
+
+func (m *MockRetroDataSvc) RestoreRetro(ctx context.Context, retroID string) error {
+	args := m.Called(ctx, retroID)
+	return args.Error(0)
+}
+
+func TestHandleCleanupOldRetros(t *testing.T) {
+	mockRetroDataSvc := new(MockRetroDataSvc)
+	service := &Service{
+		Config:       &Config{CleanupRetrosDaysOld: 180},
+		RetroDataSvc: mockRetroDataSvc,
+	}
+
+	mockRetroDataSvc.On("SoftDeleteOldRetros", mock.Anything, 30).
+		Return(&thunderdome.RetroCleanupResult{RetroCount: 2, RetroIDs: []string{"retro-1", "retro-2"}}, nil).Once()
+	mockRetroDataSvc.On("SoftDeleteOldRetros", mock.Anything, 180).
+		Return(&thunderdome.RetroCleanupResult{RetroIDs: []string{}}, nil).Once()
+
+	runCleanup := func(body string) (int, thunderdome.RetroCleanupResult) {
+		req := httptest.NewRequest("POST", "/admin/retros/cleanup", strings.NewReader(body))
+		req = req.WithContext(context.WithValue(req.Context(), contextKeyUserID, "323e4567-e89b-12d3-a456-426614174000"))
+		rr := httptest.NewRecorder()
+		service.handleCleanupOldRetros().ServeHTTP(rr, req)
+
+		var response struct {
+			Data thunderdome.RetroCleanupResult `json:"data"`
+		}
+		_ = json.Unmarshal(rr.Body.Bytes(), &response)
+
+		return rr.Code, response.Data
+	}
+
+	code, result := runCleanup(`{"days_old":30}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 2, result.RetroCount)
+	assert.Equal(t, []string{"retro-1", "retro-2"}, result.RetroIDs)
+
+	code, result = runCleanup(`{}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 0, result.RetroCount)
+
+	code, _ = runCleanup(`{"days_old":-1}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	mockRetroDataSvc.AssertExpectations(t)
+}
+
+func TestHandleRetroRestore(t *testing.T) {
+	const restoredRetroID = "423e4567-e89b-12d3-a456-426614174000"
+	const purgedRetroID = "523e4567-e89b-12d3-a456-426614174000"
+
+	mockRetroDataSvc := new(MockRetroDataSvc)
+	service := &Service{
+		Config:       &Config{},
+		RetroDataSvc: mockRetroDataSvc,
+	}
+
+	mockRetroDataSvc.On("RestoreRetro", mock.Anything, restoredRetroID).Return(nil).Once()
+	mockRetroDataSvc.On("RestoreRetro", mock.Anything, purgedRetroID).Return(fmt.Errorf("RETRO_NOT_FOUND")).Once()
+
+	restore := func(retroID string) int {
+		req := httptest.NewRequest("POST", "/admin/retros/"+retroID+"/restore", nil)
+		req = mux.SetURLVars(req, map[string]string{"retroId": retroID})
+		req = req.WithContext(context.WithValue(req.Context(), contextKeyUserID, "323e4567-e89b-12d3-a456-426614174000"))
+		rr := httptest.NewRecorder()
+		service.handleRetroRestore().ServeHTTP(rr, req)
+
+		return rr.Code
+	}
+
+	assert.Equal(t, http.StatusOK, restore(restoredRetroID))
+	assert.Equal(t, http.StatusNotFound, restore(purgedRetroID))
+	assert.Equal(t, http.StatusBadRequest, restore("not-a-uuid"))
+
+	mockRetroDataSvc.AssertExpectations(t)
+}
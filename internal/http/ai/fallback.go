@@ -0,0 +1,250 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// 熔断器相关常量
+const (
+	// circuitBreakerThreshold 连续失败达到该次数后熔断提供方
+	circuitBreakerThreshold = 3
+	// circuitBreakerOpenDuration 熔断后跳过提供方的时长
+	circuitBreakerOpenDuration = 5 * time.Minute
+	// circuitBreakerFailureTTL 连续失败计数的保留时长，避免长时间前的失败累计触发熔断
+	circuitBreakerFailureTTL = time.Hour
+)
+
+// ProviderFailure 记录一个提供方失败的原因
+type ProviderFailure struct {
+	Provider string `json:"provider"`
+	Reason   string `json:"reason"`
+}
+
+// FallbackError 为所有提供方都失败时返回的错误，列出每个提供方及其失败原因
+type FallbackError struct {
+	Failures []ProviderFailure `json:"failures"`
+}
+
+// Error 返回包含所有提供方失败原因的错误信息
+func (e *FallbackError) Error() string {
+	if len(e.Failures) == 0 {
+		return "no AI providers configured"
+	}
+
+	reasons := make([]string, 0, len(e.Failures))
+	for _, failure := range e.Failures {
+		reasons = append(reasons, fmt.Sprintf("%s: %s", failure.Provider, failure.Reason))
+	}
+
+	return "all AI providers failed: " + strings.Join(reasons, "; ")
+}
+
+// FallbackChain 按顺序尝试AI提供方，返回第一个成功的建议
+type FallbackChain struct {
+	Providers []AIProvider
+	Breaker   *CircuitBreaker
+}
+
+// NewFallbackChain 创建一个新的AI提供方备用链
+func NewFallbackChain(providers []AIProvider, breaker *CircuitBreaker) *FallbackChain {
+	return &FallbackChain{
+		Providers: providers,
+		Breaker:   breaker,
+	}
+}
+
+// SuggestPoints 依次调用提供方，跳过已熔断的提供方，全部失败时返回 *FallbackError
+func (c *FallbackChain) SuggestPoints(ctx context.Context, prompt string, availablePoints []string) (*PointSuggestionResponse, error) {
+	var suggestion *PointSuggestionResponse
+	err := c.call(ctx, func(provider AIProvider) error {
+		var err error
+		suggestion, err = provider.SuggestPoints(ctx, prompt, availablePoints)
+		return err
+	})
+
+	return suggestion, err
+}
+
+// Generate 依次调用提供方生成文本，跳过已熔断的提供方，全部失败时返回 *FallbackError
+func (c *FallbackChain) Generate(ctx context.Context, prompt string) (string, error) {
+	var content string
+	err := c.call(ctx, func(provider AIProvider) error {
+		var err error
+		content, err = provider.Generate(ctx, prompt)
+		return err
+	})
+
+	return content, err
+}
+
+// call 依次使用提供方调用 fn 直到成功，记录每个提供方的成功或失败
+func (c *FallbackChain) call(ctx context.Context, fn func(provider AIProvider) error) error {
+	fallbackErr := &FallbackError{Failures: make([]ProviderFailure, 0)}
+
+	for _, provider := range c.Providers {
+		name := provider.Name()
+		if c.Breaker != nil && c.Breaker.IsOpen(ctx, name) {
+			fallbackErr.Failures = append(fallbackErr.Failures, ProviderFailure{Provider: name, Reason: "circuit breaker open"})
+			continue
+		}
+
+		if err := fn(provider); err != nil {
+			fallbackErr.Failures = append(fallbackErr.Failures, ProviderFailure{Provider: name, Reason: err.Error()})
+			if c.Breaker != nil {
+				c.Breaker.RecordFailure(ctx, name)
+			}
+			continue
+		}
+
+		if c.Breaker != nil {
+			c.Breaker.RecordSuccess(ctx, name)
+		}
+		return nil
+	}
+
+	return fallbackErr
+}
+
+// CircuitBreakerStore 保存每个提供方的熔断器状态
+type CircuitBreakerStore interface {
+	// IncrFailures 增加提供方的连续失败次数并返回当前次数
+	IncrFailures(ctx context.Context, provider string) (int64, error)
+	// ResetFailures 清除提供方的连续失败次数
+	ResetFailures(ctx context.Context, provider string) error
+	// Open 在指定时长内熔断提供方
+	Open(ctx context.Context, provider string, duration time.Duration) error
+	// IsOpen 返回提供方是否处于熔断状态
+	IsOpen(ctx context.Context, provider string) (bool, error)
+}
+
+// CircuitBreaker 在提供方连续失败后的一段时间内跳过该提供方
+type CircuitBreaker struct {
+	store CircuitBreakerStore
+}
+
+// NewCircuitBreaker 创建一个新的熔断器，配置了Redis时状态保存在Redis中，在所有实例间共享，
+// 否则保存在内存中
+func NewCircuitBreaker(redisClient *redis.Client) *CircuitBreaker {
+	if redisClient != nil {
+		return &CircuitBreaker{store: &redisBreakerStore{client: redisClient}}
+	}
+
+	return &CircuitBreaker{store: newMemoryBreakerStore(time.Now)}
+}
+
+// IsOpen 返回提供方是否处于熔断状态，读取状态失败时不熔断
+func (b *CircuitBreaker) IsOpen(ctx context.Context, provider string) bool {
+	open, err := b.store.IsOpen(ctx, provider)
+	return err == nil && open
+}
+
+// RecordFailure 记录提供方的一次失败，连续失败达到阈值时熔断提供方
+func (b *CircuitBreaker) RecordFailure(ctx context.Context, provider string) {
+	failures, err := b.store.IncrFailures(ctx, provider)
+	if err != nil || failures < circuitBreakerThreshold {
+		return
+	}
+
+	if err := b.store.Open(ctx, provider, circuitBreakerOpenDuration); err == nil {
+		_ = b.store.ResetFailures(ctx, provider)
+	}
+}
+
+// RecordSuccess 记录提供方的一次成功，清除连续失败次数
+func (b *CircuitBreaker) RecordSuccess(ctx context.Context, provider string) {
+	_ = b.store.ResetFailures(ctx, provider)
+}
+
+// redisBreakerStore 将熔断器状态保存在Redis中
+type redisBreakerStore struct {
+	client *redis.Client
+}
+
+func breakerFailuresKey(provider string) string {
+	return fmt.Sprintf("ai:breaker:failures:%s", provider)
+}
+
+func breakerOpenKey(provider string) string {
+	return fmt.Sprintf("ai:breaker:open:%s", provider)
+}
+
+func (s *redisBreakerStore) IncrFailures(ctx context.Context, provider string) (int64, error) {
+	key := breakerFailuresKey(provider)
+	failures, err := s.client.Incr(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	s.client.Expire(ctx, key, circuitBreakerFailureTTL)
+
+	return failures, nil
+}
+
+func (s *redisBreakerStore) ResetFailures(ctx context.Context, provider string) error {
+	return s.client.Del(ctx, breakerFailuresKey(provider)).Err()
+}
+
+func (s *redisBreakerStore) Open(ctx context.Context, provider string, duration time.Duration) error {
+	return s.client.Set(ctx, breakerOpenKey(provider), "1", duration).Err()
+}
+
+func (s *redisBreakerStore) IsOpen(ctx context.Context, provider string) (bool, error) {
+	exists, err := s.client.Exists(ctx, breakerOpenKey(provider)).Result()
+	if err != nil {
+		return false, err
+	}
+
+	return exists > 0, nil
+}
+
+// memoryBreakerStore 在未配置Redis时将熔断器状态保存在内存中
+type memoryBreakerStore struct {
+	mu        sync.Mutex
+	now       func() time.Time
+	failures  map[string]int64
+	openUntil map[string]time.Time
+}
+
+func newMemoryBreakerStore(now func() time.Time) *memoryBreakerStore {
+	return &memoryBreakerStore{
+		now:       now,
+		failures:  make(map[string]int64),
+		openUntil: make(map[string]time.Time),
+	}
+}
+
+func (s *memoryBreakerStore) IncrFailures(ctx context.Context, provider string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures[provider]++
+	return s.failures[provider], nil
+}
+
+func (s *memoryBreakerStore) ResetFailures(ctx context.Context, provider string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.failures, provider)
+	return nil
+}
+
+func (s *memoryBreakerStore) Open(ctx context.Context, provider string, duration time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.openUntil[provider] = s.now().Add(duration)
+	return nil
+}
+
+func (s *memoryBreakerStore) IsOpen(ctx context.Context, provider string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.now().Before(s.openUntil[provider]), nil
+}
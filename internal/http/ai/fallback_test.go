@@ -0,0 +1,131 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeProvider 返回固定的建议、生成文本或错误，并记录调用次数和最后的提示
+type fakeProvider struct {
+	name    string
+	content string
+	err     error
+	calls   int
+	prompt  string
+}
+
+func (p *fakeProvider) Name() string {
+	return p.name
+}
+
+func (p *fakeProvider) SuggestPoints(ctx context.Context, prompt string, availablePoints []string) (*PointSuggestionResponse, error) {
+	p.calls++
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	return &PointSuggestionResponse{SuggestedPoint: "5", Reason: p.name, Confidence: 1}, nil
+}
+
+func (p *fakeProvider) Generate(ctx context.Context, prompt string) (string, error) {
+	p.calls++
+	p.prompt = prompt
+	if p.err != nil {
+		return "", p.err
+	}
+
+	return p.content, nil
+}
+
+// newTestBreaker 返回使用内存状态和可控时钟的熔断器
+func newTestBreaker(now *time.Time) *CircuitBreaker {
+	return &CircuitBreaker{store: newMemoryBreakerStore(func() time.Time { return *now })}
+}
+
+// TestFallbackChainUsesFirstSuccessfulProvider makes sure the chain falls through failing providers
+func TestFallbackChainUsesFirstSuccessfulProvider(t *testing.T) {
+	now := time.Now()
+	primary := &fakeProvider{name: "primary", err: errors.New("503 service unavailable")}
+	backup := &fakeProvider{name: "backup", err: errors.New("timeout")}
+	local := &fakeProvider{name: "local"}
+	chain := NewFallbackChain([]AIProvider{primary, backup, local}, newTestBreaker(&now))
+
+	suggestion, err := chain.SuggestPoints(context.Background(), "prompt", []string{"1", "3", "5"})
+	if err != nil {
+		t.Fatalf("expected a suggestion from the third provider, got %v", err)
+	}
+	if suggestion.Reason != "local" {
+		t.Errorf("expected the local provider suggestion, got %+v", suggestion)
+	}
+	if primary.calls != 1 || backup.calls != 1 || local.calls != 1 {
+		t.Errorf("expected each provider to be called once, got %d %d %d", primary.calls, backup.calls, local.calls)
+	}
+}
+
+// TestFallbackChainAllFail makes sure the error lists every provider and its failure reason
+func TestFallbackChainAllFail(t *testing.T) {
+	chain := NewFallbackChain([]AIProvider{
+		&fakeProvider{name: "primary", err: errors.New("503 service unavailable")},
+		&fakeProvider{name: "backup", err: errors.New("timeout")},
+	}, nil)
+
+	_, err := chain.SuggestPoints(context.Background(), "prompt", nil)
+	var fallbackErr *FallbackError
+	if !errors.As(err, &fallbackErr) {
+		t.Fatalf("expected a FallbackError, got %v", err)
+	}
+	if len(fallbackErr.Failures) != 2 || fallbackErr.Failures[0].Provider != "primary" || fallbackErr.Failures[1].Reason != "timeout" {
+		t.Errorf("expected both provider failures, got %+v", fallbackErr.Failures)
+	}
+	if !strings.Contains(err.Error(), "primary: 503 service unavailable") || !strings.Contains(err.Error(), "backup: timeout") {
+		t.Errorf("expected the error to list each provider, got %q", err.Error())
+	}
+}
+
+// TestCircuitBreakerSkipsFailingProvider makes sure a provider is skipped for five minutes after three consecutive failures
+func TestCircuitBreakerSkipsFailingProvider(t *testing.T) {
+	now := time.Now()
+	primary := &fakeProvider{name: "primary", err: errors.New("503 service unavailable")}
+	backup := &fakeProvider{name: "backup"}
+	chain := NewFallbackChain([]AIProvider{primary, backup}, newTestBreaker(&now))
+
+	for i := 0; i < circuitBreakerThreshold+2; i++ {
+		if _, err := chain.SuggestPoints(context.Background(), "prompt", nil); err != nil {
+			t.Fatalf("expected the backup provider to succeed, got %v", err)
+		}
+	}
+	if primary.calls != circuitBreakerThreshold {
+		t.Errorf("expected the primary provider to be skipped after %d failures, got %d calls", circuitBreakerThreshold, primary.calls)
+	}
+
+	now = now.Add(circuitBreakerOpenDuration)
+	primary.err = nil
+	suggestion, err := chain.SuggestPoints(context.Background(), "prompt", nil)
+	if err != nil || suggestion.Reason != "primary" {
+		t.Fatalf("expected the primary provider to be tried again once the breaker closed, got %+v %v", suggestion, err)
+	}
+}
+
+// TestCircuitBreakerResetsOnSuccess makes sure only consecutive failures open the breaker
+func TestCircuitBreakerResetsOnSuccess(t *testing.T) {
+	now := time.Now()
+	breaker := newTestBreaker(&now)
+	ctx := context.Background()
+
+	breaker.RecordFailure(ctx, "primary")
+	breaker.RecordFailure(ctx, "primary")
+	breaker.RecordSuccess(ctx, "primary")
+	breaker.RecordFailure(ctx, "primary")
+	if breaker.IsOpen(ctx, "primary") {
+		t.Error("expected the breaker to stay closed without consecutive failures")
+	}
+
+	breaker.RecordFailure(ctx, "primary")
+	breaker.RecordFailure(ctx, "primary")
+	if !breaker.IsOpen(ctx, "primary") {
+		t.Error("expected the breaker to open after consecutive failures")
+	}
+}
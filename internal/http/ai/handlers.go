@@ -1,32 +1,38 @@
 package ai
 
 import (
-	"bytes"
 	"encoding/json"
-	"fmt"
-	"io"
 	"math"
 	"net/http"
 	"os"
 	"regexp"
 	"strconv"
 	"strings"
-	"time"
+
+	"github.com/redis/go-redis/v9"
 )
 
 // Service 用于处理AI相关服务
 type Service struct {
-	AiApiKey string
-	AiApiUrl string
-	AiModel  string
+	AiModel string
+	Prompts *PromptBuilder
+	// Chain 为按顺序尝试的AI提供方，主提供方失败时使用备用提供方
+	Chain *FallbackChain
 }
 
-// NewAIService 创建一个新的AI服务
-func NewAIService() *Service {
+// NewAIService 创建一个新的AI服务，defaultLocale 为请求未指定语言时提示使用的语言，
+// redisClient 用于在实例间共享提供方熔断器状态，可以为 nil
+func NewAIService(defaultLocale string, redisClient *redis.Client) *Service {
+	// 提示模板嵌入在程序中，加载失败说明模板文件有误
+	prompts, err := NewPromptBuilder(defaultLocale)
+	if err != nil {
+		panic(err)
+	}
+
 	return &Service{
-		AiApiKey: os.Getenv("THUNDERDOME_AI_API_KEY"),
-		AiApiUrl: os.Getenv("THUNDERDOME_AI_API_URL"),
-		AiModel:  os.Getenv("THUNDERDOME_AI_MODEL"),
+		AiModel: os.Getenv("THUNDERDOME_AI_MODEL"),
+		Prompts: prompts,
+		Chain:   NewFallbackChain(ProvidersFromEnv(), NewCircuitBreaker(redisClient)),
 	}
 }
 
@@ -36,14 +42,33 @@ type PointSuggestionRequest struct {
 	Description        string   `json:"description"`
 	AcceptanceCriteria string   `json:"acceptanceCriteria"`
 	AvailablePoints    []string `json:"availablePoints"`
+	// 提示使用的语言，可通过 locale 查询参数覆盖
+	Locale string `json:"locale"`
 }
 
 // 故事点数建议响应结构
 type PointSuggestionResponse struct {
 	SuggestedPoint string `json:"suggestedPoint"`
 	Reason         string `json:"reason"`
+	// 建议的可信度，范围 0.0–1.0
+	Confidence float64 `json:"confidence"`
 }
 
+// AI回复中的JSON结构，Confidence 为空表示AI未提供可信度
+type aiSuggestion struct {
+	SuggestedPoint string   `json:"suggestedPoint"`
+	Reason         string   `json:"reason"`
+	Confidence     *float64 `json:"confidence"`
+}
+
+// 当AI未提供可信度时，根据解析方式估算的可信度
+const (
+	confidenceDirectMatch  = 1.0
+	confidenceClosestPoint = 0.7
+	confidenceNumberMatch  = 0.3
+	confidenceDefault      = 0.1
+)
+
 // Hugging Face API请求结构
 type HuggingFaceRequest struct {
 	Inputs     string                 `json:"inputs"`
@@ -71,133 +96,38 @@ func (s *Service) SuggestPoints(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// 检查API密钥和URL是否已配置
-	if s.AiApiUrl == "" {
-		http.Error(w, "AI API not configured", http.StatusInternalServerError)
-		return
+	// 查询参数中的语言优先
+	if locale := r.URL.Query().Get("locale"); locale != "" {
+		req.Locale = locale
 	}
 
-	// 构建发送给AI的提示
-	prompt := buildAIPrompt(req)
-
-	// 创建Hugging Face API请求
-	aiReq := HuggingFaceRequest{
-		Inputs: prompt,
-		Parameters: map[string]interface{}{
-			"max_new_tokens":   200,
-			"temperature":      0.7,
-			"top_p":            0.95,
-			"return_full_text": false,
-		},
-	}
-
-	// 将请求序列化为JSON
-	aiReqBody, err := json.Marshal(aiReq)
-	if err != nil {
-		http.Error(w, "Error creating AI request", http.StatusInternalServerError)
+	// 检查是否配置了AI提供方
+	if len(s.Chain.Providers) == 0 {
+		http.Error(w, "AI API not configured", http.StatusInternalServerError)
 		return
 	}
 
-	// 创建HTTP客户端并设置超时
-	client := &http.Client{
-		Timeout: 30 * time.Second,
-	}
-
-	// 创建HTTP请求
-	aiRequest, err := http.NewRequest("POST", s.AiApiUrl, bytes.NewBuffer(aiReqBody))
+	// 构建发送给AI的提示
+	prompt, err := s.Prompts.Build(req)
 	if err != nil {
-		http.Error(w, "Error creating HTTP request", http.StatusInternalServerError)
+		http.Error(w, "Error building AI prompt", http.StatusInternalServerError)
 		return
 	}
 
-	// 设置请求头
-	aiRequest.Header.Set("Content-Type", "application/json")
-	if s.AiApiKey != "" {
-		aiRequest.Header.Set("Authorization", "Bearer "+s.AiApiKey)
-	}
-
-	// 发送请求
-	aiResp, err := client.Do(aiRequest)
+	// 依次尝试AI提供方，返回第一个成功的建议
+	response, err := s.Chain.SuggestPoints(r.Context(), prompt, req.AvailablePoints)
 	if err != nil {
 		http.Error(w, "Error calling AI API: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
-	defer aiResp.Body.Close()
-
-	// 读取响应体
-	aiRespBody, err := io.ReadAll(aiResp.Body)
-	if err != nil {
-		http.Error(w, "Error reading AI API response", http.StatusInternalServerError)
-		return
-	}
-
-	// 检查响应状态码
-	if aiResp.StatusCode != http.StatusOK {
-		http.Error(w, fmt.Sprintf("AI API returned an error: %d - %s", aiResp.StatusCode, string(aiRespBody)), http.StatusInternalServerError)
-		return
-	}
 
-	// 解析Hugging Face响应
-	var hfResponse HuggingFaceResponse
-	if err := json.Unmarshal(aiRespBody, &hfResponse); err != nil {
-		// 尝试解析为纯文本响应
-		suggestedPoint, reason := parseAIResponse(string(aiRespBody), req.AvailablePoints)
-
-		// 准备响应
-		response := PointSuggestionResponse{
-			SuggestedPoint: suggestedPoint,
-			Reason:         reason,
-		}
-
-		// 将响应发送回客户端
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(response)
-		return
-	}
-
-	// 如果成功解析为HuggingFaceResponse
-	if len(hfResponse) > 0 && hfResponse[0].GeneratedText != "" {
-		suggestedPoint, reason := parseAIResponse(hfResponse[0].GeneratedText, req.AvailablePoints)
-
-		// 准备响应
-		response := PointSuggestionResponse{
-			SuggestedPoint: suggestedPoint,
-			Reason:         reason,
-		}
-
-		// 将响应发送回客户端
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(response)
-		return
-	}
-
-	// 如果无法解析响应
-	http.Error(w, "Unable to parse AI response", http.StatusInternalServerError)
-}
-
-// 构建发送给AI的提示文本
-func buildAIPrompt(req PointSuggestionRequest) string {
-	var prompt strings.Builder
-
-	prompt.WriteString("作为敏捷估算专家，请为以下用户故事提供一个点数估计，并给出理由。\n\n")
-	prompt.WriteString("故事名称: " + req.StoryName + "\n")
-
-	if req.Description != "" {
-		prompt.WriteString("描述: " + req.Description + "\n")
-	}
-
-	if req.AcceptanceCriteria != "" {
-		prompt.WriteString("验收标准: " + req.AcceptanceCriteria + "\n")
-	}
-
-	prompt.WriteString("\n可用的点数值: " + joinStrings(req.AvailablePoints) + "\n\n")
-	prompt.WriteString("请以JSON格式回复，结构为：{\"suggestedPoint\": \"<点数>\", \"reason\": \"<理由>\"}")
-
-	return prompt.String()
+	// 将响应发送回客户端
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
 }
 
-// 解析AI响应并提取建议的点数和理由，限制点数在可用值范围内
-func parseAIResponse(content string, availablePoints []string) (string, string) {
+// 解析AI响应并提取建议的点数、理由和可信度，限制点数在可用值范围内
+func parseAIResponse(content string, availablePoints []string) (string, string, float64) {
 	// 尝试从回复中提取JSON
 	content = strings.TrimSpace(content)
 
@@ -213,17 +143,17 @@ func parseAIResponse(content string, availablePoints []string) (string, string)
 
 	if jsonStart >= 0 && jsonEnd > jsonStart {
 		jsonContent := content[jsonStart : jsonEnd+1]
-		var response PointSuggestionResponse
+		var response aiSuggestion
 		err := json.Unmarshal([]byte(jsonContent), &response)
 
 		if err == nil && response.SuggestedPoint != "" {
 			// 验证点数是否在可用值范围内
 			if validPoints[response.SuggestedPoint] {
-				return response.SuggestedPoint, response.Reason
+				return response.SuggestedPoint, response.Reason, aiConfidence(response.Confidence, confidenceDirectMatch)
 			} else {
 				// 如果不在范围内，寻找最接近的值
 				closestPoint := findClosestPoint(response.SuggestedPoint, availablePoints)
-				return closestPoint, response.Reason
+				return closestPoint, response.Reason, aiConfidence(response.Confidence, confidenceClosestPoint)
 			}
 		}
 	}
@@ -245,7 +175,7 @@ func parseAIResponse(content string, availablePoints []string) (string, string)
 
 		for _, pattern := range patterns {
 			if strings.Contains(content, pattern) {
-				return point, extractReason(content)
+				return point, extractReason(content), confidenceDirectMatch
 			}
 		}
 	}
@@ -254,11 +184,20 @@ func parseAIResponse(content string, availablePoints []string) (string, string)
 	foundNumber := findNumberInContent(content)
 	if foundNumber != "" {
 		closestPoint := findClosestPoint(foundNumber, availablePoints)
-		return closestPoint, extractReason(content)
+		return closestPoint, extractReason(content), confidenceNumberMatch
 	}
 
 	// 默认返回问号
-	return "?", extractReason(content)
+	return "?", extractReason(content), confidenceDefault
+}
+
+// 优先使用AI提供的可信度（限制在0到1之间），否则使用估算的可信度
+func aiConfidence(confidence *float64, estimated float64) float64 {
+	if confidence == nil || math.IsNaN(*confidence) {
+		return estimated
+	}
+
+	return math.Max(0, math.Min(1, *confidence))
 }
 
 // 从内容中提取理由
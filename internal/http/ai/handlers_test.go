@@ -0,0 +1,70 @@
+package ai
+
+import "testing"
+
+// TestParseAIResponseConfidence makes sure each way of parsing the suggestion gets its confidence tier
+func TestParseAIResponseConfidence(t *testing.T) {
+	availablePoints := []string{"1", "2", "3", "5", "8", "?"}
+
+	tests := []struct {
+		name               string
+		content            string
+		expectedPoint      string
+		expectedConfidence float64
+	}{
+		{
+			name:               "ai provided confidence",
+			content:            `{"suggestedPoint": "5", "reason": "medium effort", "confidence": 0.85}`,
+			expectedPoint:      "5",
+			expectedConfidence: 0.85,
+		},
+		{
+			name:               "ai provided confidence out of range",
+			content:            `{"suggestedPoint": "5", "reason": "medium effort", "confidence": 1.5}`,
+			expectedPoint:      "5",
+			expectedConfidence: 1,
+		},
+		{
+			name:               "json suggestion in available points",
+			content:            `{"suggestedPoint": "3", "reason": "small change"}`,
+			expectedPoint:      "3",
+			expectedConfidence: confidenceDirectMatch,
+		},
+		{
+			name:               "point pattern in available points",
+			content:            "建议点数: 8，理由: 涉及多个服务",
+			expectedPoint:      "8",
+			expectedConfidence: confidenceDirectMatch,
+		},
+		{
+			name:               "json suggestion closest point",
+			content:            `{"suggestedPoint": "6", "reason": "a bit more than medium"}`,
+			expectedPoint:      "5",
+			expectedConfidence: confidenceClosestPoint,
+		},
+		{
+			name:               "number match",
+			content:            "this feels like roughly 7 days of work",
+			expectedPoint:      "8",
+			expectedConfidence: confidenceNumberMatch,
+		},
+		{
+			name:               "default fallback",
+			content:            "not enough information to estimate",
+			expectedPoint:      "?",
+			expectedConfidence: confidenceDefault,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			point, _, confidence := parseAIResponse(tt.content, availablePoints)
+			if point != tt.expectedPoint {
+				t.Errorf("expected point %s, got %s", tt.expectedPoint, point)
+			}
+			if confidence != tt.expectedConfidence {
+				t.Errorf("expected confidence %v, got %v", tt.expectedConfidence, confidence)
+			}
+		})
+	}
+}
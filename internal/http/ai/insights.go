@@ -0,0 +1,110 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// 回顾洞察中主题的数量范围
+const (
+	minRetroThemes = 3
+	maxRetroThemes = 5
+)
+
+// AI回复中的回顾洞察JSON结构
+type aiRetroInsights struct {
+	Themes      []string `json:"themes"`
+	ActionItems []string `json:"actionItems"`
+	MoodSummary string   `json:"moodSummary"`
+}
+
+// ExtractRetroInsights 将回顾条目按类别分组发送给AI，提取最重要的主题、关键行动项和情绪总结
+func (s *Service) ExtractRetroInsights(ctx context.Context, items []*thunderdome.RetroItem) (*thunderdome.RetroInsights, error) {
+	if len(items) == 0 {
+		return nil, fmt.Errorf("RETRO_HAS_NO_ITEMS")
+	}
+
+	content, err := s.Chain.Generate(ctx, buildRetroInsightsPrompt(items))
+	if err != nil {
+		return nil, err
+	}
+
+	return parseRetroInsights(content)
+}
+
+// 构建回顾洞察提示，条目按类别（type）分组，类别按名称排序以保证提示稳定
+func buildRetroInsightsPrompt(items []*thunderdome.RetroItem) string {
+	categories := make(map[string][]string)
+	for _, item := range items {
+		if item.Redacted || strings.TrimSpace(item.Content) == "" {
+			continue
+		}
+		categories[item.Type] = append(categories[item.Type], item.Content)
+	}
+
+	names := make([]string, 0, len(categories))
+	for name := range categories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var prompt strings.Builder
+	prompt.WriteString("As an agile coach, review the following retrospective feedback grouped by category.\n")
+	fmt.Fprintf(&prompt, "Identify the %d to %d most important themes, the key action items the team should take, ", minRetroThemes, maxRetroThemes)
+	prompt.WriteString("and summarize the overall mood of the team.\n")
+	for _, name := range names {
+		fmt.Fprintf(&prompt, "\nCategory: %s\n", name)
+		for _, content := range categories[name] {
+			fmt.Fprintf(&prompt, "- %s\n", content)
+		}
+	}
+	prompt.WriteString("\nReply in JSON using the structure: ")
+	prompt.WriteString(`{"themes": ["<theme>"], "actionItems": ["<action item>"], "moodSummary": "<mood summary>"}`)
+
+	return prompt.String()
+}
+
+// 解析AI回复中的回顾洞察，最多保留 maxRetroThemes 个主题
+func parseRetroInsights(content string) (*thunderdome.RetroInsights, error) {
+	jsonStart := strings.Index(content, "{")
+	jsonEnd := strings.LastIndex(content, "}")
+	if jsonStart < 0 || jsonEnd <= jsonStart {
+		return nil, fmt.Errorf("unable to parse AI retro insights response")
+	}
+
+	var response aiRetroInsights
+	if err := json.Unmarshal([]byte(content[jsonStart:jsonEnd+1]), &response); err != nil {
+		return nil, fmt.Errorf("unable to parse AI retro insights response: %v", err)
+	}
+
+	themes := nonEmptyStrings(response.Themes)
+	if len(themes) == 0 {
+		return nil, fmt.Errorf("AI retro insights response has no themes")
+	}
+	if len(themes) > maxRetroThemes {
+		themes = themes[:maxRetroThemes]
+	}
+
+	return &thunderdome.RetroInsights{
+		TopThemes:      themes,
+		KeyActionItems: nonEmptyStrings(response.ActionItems),
+		MoodSummary:    strings.TrimSpace(response.MoodSummary),
+	}, nil
+}
+
+// 去除字符串两端空白并忽略空字符串
+func nonEmptyStrings(values []string) []string {
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			result = append(result, value)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,71 @@
+package ai
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// TestExtractRetroInsights makes sure the items are grouped by category in the prompt
+// and the AI reply populates the insights
+func TestExtractRetroInsights(t *testing.T) {
+	provider := &fakeProvider{name: "primary", content: `Here are the insights:
+{"themes": ["Deploys are slow", " Pairing helps ", "", "Unclear requirements", "On-call load", "Flaky tests", "Too many meetings"],
+ "actionItems": ["Parallelize the deploy pipeline", "Refine stories before planning"],
+ "moodSummary": " Mostly positive, frustrated by tooling "}`}
+	s := &Service{Chain: NewFallbackChain([]AIProvider{provider}, nil)}
+
+	items := []*thunderdome.RetroItem{
+		{Type: "improve", Content: "Deploys take an hour"},
+		{Type: "worked", Content: "Pairing on the migration"},
+		{Type: "improve", Content: "Requirements changed mid sprint"},
+		{Type: "question", Content: "Hidden while focusing another item", Redacted: true},
+	}
+	insights, err := s.ExtractRetroInsights(context.Background(), items)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expectedThemes := []string{"Deploys are slow", "Pairing helps", "Unclear requirements", "On-call load", "Flaky tests"}
+	if !reflect.DeepEqual(insights.TopThemes, expectedThemes) {
+		t.Errorf("expected themes %v, got %v", expectedThemes, insights.TopThemes)
+	}
+	expectedActions := []string{"Parallelize the deploy pipeline", "Refine stories before planning"}
+	if !reflect.DeepEqual(insights.KeyActionItems, expectedActions) {
+		t.Errorf("expected action items %v, got %v", expectedActions, insights.KeyActionItems)
+	}
+	if insights.MoodSummary != "Mostly positive, frustrated by tooling" {
+		t.Errorf("unexpected mood summary %q", insights.MoodSummary)
+	}
+
+	improve := strings.Index(provider.prompt, "Category: improve\n- Deploys take an hour\n- Requirements changed mid sprint\n")
+	worked := strings.Index(provider.prompt, "Category: worked\n- Pairing on the migration\n")
+	if improve < 0 || worked < improve {
+		t.Errorf("expected the items grouped by category, got prompt:\n%s", provider.prompt)
+	}
+	if strings.Contains(provider.prompt, "Hidden while focusing") || strings.Contains(provider.prompt, "Category: question") {
+		t.Errorf("expected redacted items to be left out, got prompt:\n%s", provider.prompt)
+	}
+}
+
+func TestExtractRetroInsightsInvalidResponse(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "not json", content: "the team seems happy"},
+		{name: "no themes", content: `{"themes": [], "actionItems": ["ship it"], "moodSummary": "fine"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Service{Chain: NewFallbackChain([]AIProvider{&fakeProvider{name: "primary", content: tt.content}}, nil)}
+			if _, err := s.ExtractRetroInsights(context.Background(), []*thunderdome.RetroItem{{Type: "worked", Content: "deploys"}}); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package ai
+
+import (
+	"embed"
+	"fmt"
+	"path"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/analysis"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// 不支持的语言使用的提示模板语言
+const fallbackPromptLocale = "en"
+
+//go:embed prompts/*.yaml
+var promptFiles embed.FS
+
+// 提示模板文件结构，每种语言一个文件
+type promptTemplateFile struct {
+	System string `yaml:"system"`
+	User   string `yaml:"user"`
+}
+
+// 已解析的系统提示和用户提示模板
+type promptTemplate struct {
+	system *template.Template
+	user   *template.Template
+}
+
+// 提示模板中可用的变量
+type promptData struct {
+	StoryName               string
+	Description             string
+	AcceptanceCriteria      string
+	AvailablePoints         string
+	WordCount               int
+	ReadabilityScore        float64
+	AcceptanceCriteriaCount int
+}
+
+// PromptBuilder 根据语言构建发送给AI的提示
+type PromptBuilder struct {
+	// DefaultLocale 请求未指定语言时使用的语言
+	DefaultLocale string
+	templates     map[string]*promptTemplate
+}
+
+// NewPromptBuilder 从嵌入的文件系统加载所有语言的提示模板
+func NewPromptBuilder(defaultLocale string) (*PromptBuilder, error) {
+	files, err := promptFiles.ReadDir("prompts")
+	if err != nil {
+		return nil, fmt.Errorf("read ai prompts error: %v", err)
+	}
+
+	templates := make(map[string]*promptTemplate, len(files))
+	for _, file := range files {
+		locale := strings.TrimSuffix(file.Name(), path.Ext(file.Name()))
+		tmpl, err := loadPromptTemplate(path.Join("prompts", file.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("load ai prompt %s error: %v", locale, err)
+		}
+		templates[locale] = tmpl
+	}
+
+	if templates[fallbackPromptLocale] == nil {
+		return nil, fmt.Errorf("ai prompt %s not found", fallbackPromptLocale)
+	}
+
+	return &PromptBuilder{DefaultLocale: defaultLocale, templates: templates}, nil
+}
+
+// 加载并解析单个语言的提示模板文件
+func loadPromptTemplate(name string) (*promptTemplate, error) {
+	content, err := promptFiles.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var file promptTemplateFile
+	if err := yaml.Unmarshal(content, &file); err != nil {
+		return nil, err
+	}
+
+	system, err := template.New("system").Parse(file.System)
+	if err != nil {
+		return nil, err
+	}
+	user, err := template.New("user").Parse(file.User)
+	if err != nil {
+		return nil, err
+	}
+
+	return &promptTemplate{system: system, user: user}, nil
+}
+
+// Locale 获取请求使用的提示语言，未指定时使用默认语言，不支持的语言使用英语
+// 地区变体（如 zh-CN、pt_BR）按基础语言匹配
+func (p *PromptBuilder) Locale(locale string) string {
+	if locale == "" {
+		locale = p.DefaultLocale
+	}
+
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	if _, ok := p.templates[locale]; ok {
+		return locale
+	}
+	if base, _, found := strings.Cut(strings.ReplaceAll(locale, "_", "-"), "-"); found {
+		if _, ok := p.templates[base]; ok {
+			return base
+		}
+	}
+
+	return fallbackPromptLocale
+}
+
+// Build 使用请求语言的模板构建发送给AI的提示
+func (p *PromptBuilder) Build(req PointSuggestionRequest) (string, error) {
+	tmpl := p.templates[p.Locale(req.Locale)]
+
+	// 故事文本分析作为估算的额外参考
+	storyAnalysis := analysis.AnalyzeStory(&thunderdome.Story{
+		Name:               req.StoryName,
+		Description:        req.Description,
+		AcceptanceCriteria: req.AcceptanceCriteria,
+	})
+	data := promptData{
+		StoryName:               req.StoryName,
+		Description:             req.Description,
+		AcceptanceCriteria:      req.AcceptanceCriteria,
+		AvailablePoints:         joinStrings(req.AvailablePoints),
+		WordCount:               storyAnalysis.WordCount,
+		ReadabilityScore:        storyAnalysis.ReadabilityScore,
+		AcceptanceCriteriaCount: storyAnalysis.AcceptanceCriteriaCount,
+	}
+
+	var prompt strings.Builder
+	if err := tmpl.system.Execute(&prompt, data); err != nil {
+		return "", fmt.Errorf("build ai system prompt error: %v", err)
+	}
+	prompt.WriteString("\n")
+	if err := tmpl.user.Execute(&prompt, data); err != nil {
+		return "", fmt.Errorf("build ai user prompt error: %v", err)
+	}
+
+	return prompt.String(), nil
+}
@@ -0,0 +1,100 @@
+package ai
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestPromptBuilderLocales makes sure every locale's template loads and interpolates the story
+func TestPromptBuilderLocales(t *testing.T) {
+	prompts, err := NewPromptBuilder("en")
+	if err != nil {
+		t.Fatalf("expected the prompt templates to load, got %v", err)
+	}
+
+	req := PointSuggestionRequest{
+		StoryName:          "Export results to CSV",
+		Description:        "Facilitators can download the game results",
+		AcceptanceCriteria: "- includes every story\n- includes final points",
+		AvailablePoints:    []string{"1", "2", "3", "5", "8", "?"},
+	}
+
+	tests := []struct {
+		locale      string
+		instruction string
+	}{
+		{locale: "en", instruction: "Available point values: 1, 2, 3, 5, 8, ?"},
+		{locale: "zh", instruction: "可用的点数值: 1, 2, 3, 5, 8, ?"},
+		{locale: "fr", instruction: "Valeurs de points disponibles : 1, 2, 3, 5, 8, ?"},
+		{locale: "de", instruction: "Verfügbare Punktwerte: 1, 2, 3, 5, 8, ?"},
+		{locale: "ja", instruction: "利用可能なポイント: 1, 2, 3, 5, 8, ?"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.locale, func(t *testing.T) {
+			req.Locale = tt.locale
+			prompt, err := prompts.Build(req)
+			if err != nil {
+				t.Fatalf("expected the prompt to build, got %v", err)
+			}
+
+			for _, expected := range []string{tt.instruction, req.StoryName, req.Description, "includes final points", `"suggestedPoint"`} {
+				if !strings.Contains(prompt, expected) {
+					t.Errorf("expected %q in the prompt, got %q", expected, prompt)
+				}
+			}
+			if strings.Contains(prompt, "{{") {
+				t.Errorf("expected every template variable to be interpolated, got %q", prompt)
+			}
+		})
+	}
+}
+
+// TestPromptBuilderOptionalFields makes sure the description and acceptance criteria lines are left out when empty
+func TestPromptBuilderOptionalFields(t *testing.T) {
+	prompts, err := NewPromptBuilder("en")
+	if err != nil {
+		t.Fatalf("expected the prompt templates to load, got %v", err)
+	}
+
+	prompt, err := prompts.Build(PointSuggestionRequest{StoryName: "Login", AvailablePoints: []string{"1", "2"}})
+	if err != nil {
+		t.Fatalf("expected the prompt to build, got %v", err)
+	}
+	if strings.Contains(prompt, "Description:") || strings.Contains(prompt, "Acceptance criteria:") {
+		t.Errorf("expected empty fields to be left out, got %q", prompt)
+	}
+}
+
+// TestPromptBuilderLocaleFallback makes sure the default locale, regional variants and unsupported locales resolve
+func TestPromptBuilderLocaleFallback(t *testing.T) {
+	prompts, err := NewPromptBuilder("de")
+	if err != nil {
+		t.Fatalf("expected the prompt templates to load, got %v", err)
+	}
+
+	tests := []struct {
+		locale   string
+		expected string
+	}{
+		{locale: "", expected: "de"},
+		{locale: "ja", expected: "ja"},
+		{locale: "zh-CN", expected: "zh"},
+		{locale: "FR_ca", expected: "fr"},
+		{locale: "ru", expected: "en"},
+	}
+
+	for _, tt := range tests {
+		if locale := prompts.Locale(tt.locale); locale != tt.expected {
+			t.Errorf("expected locale %q to resolve to %s, got %s", tt.locale, tt.expected, locale)
+		}
+	}
+
+	unsupported, err := NewPromptBuilder("pt")
+	if err != nil {
+		t.Fatalf("expected the prompt templates to load, got %v", err)
+	}
+	if locale := unsupported.Locale(""); locale != "en" {
+		t.Errorf("expected an unsupported default locale to fall back to en, got %s", locale)
+	}
+}
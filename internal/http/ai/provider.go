@@ -0,0 +1,154 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// PrimaryProviderName 为 THUNDERDOME_AI_API_URL 配置的主AI提供方名称
+const PrimaryProviderName = "primary"
+
+// AIProvider 根据提示给出故事点数建议
+type AIProvider interface {
+	// Name 返回提供方名称，用于错误信息和熔断器状态
+	Name() string
+	// SuggestPoints 发送提示并将回复解析为可用点数中的建议
+	SuggestPoints(ctx context.Context, prompt string, availablePoints []string) (*PointSuggestionResponse, error)
+	// Generate 发送提示并返回AI生成的文本
+	Generate(ctx context.Context, prompt string) (string, error)
+}
+
+// HuggingFaceProvider 调用 Hugging Face 推理接口（或兼容接口）的AI提供方
+type HuggingFaceProvider struct {
+	ProviderName string
+	APIURL       string
+	APIKey       string
+	HTTPClient   *http.Client
+}
+
+// NewHuggingFaceProvider 创建一个新的 Hugging Face AI提供方
+func NewHuggingFaceProvider(name string, apiURL string, apiKey string) *HuggingFaceProvider {
+	return &HuggingFaceProvider{
+		ProviderName: name,
+		APIURL:       apiURL,
+		APIKey:       apiKey,
+		HTTPClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// ProvidersFromEnv 按顺序返回配置的AI提供方，主提供方在前，
+// 之后为 THUNDERDOME_AI_FALLBACK_PROVIDERS 中逗号分隔的备用提供方，
+// 备用提供方 name 从 THUNDERDOME_AI_<NAME>_API_URL 和 THUNDERDOME_AI_<NAME>_API_KEY 读取配置，未配置URL的提供方被忽略
+func ProvidersFromEnv() []AIProvider {
+	providers := make([]AIProvider, 0)
+	if apiURL := os.Getenv("THUNDERDOME_AI_API_URL"); apiURL != "" {
+		providers = append(providers, NewHuggingFaceProvider(PrimaryProviderName, apiURL, os.Getenv("THUNDERDOME_AI_API_KEY")))
+	}
+
+	for _, name := range strings.Split(os.Getenv("THUNDERDOME_AI_FALLBACK_PROVIDERS"), ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		envPrefix := "THUNDERDOME_AI_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+		apiURL := os.Getenv(envPrefix + "_API_URL")
+		if apiURL == "" {
+			continue
+		}
+		providers = append(providers, NewHuggingFaceProvider(name, apiURL, os.Getenv(envPrefix+"_API_KEY")))
+	}
+
+	return providers
+}
+
+// Name 返回提供方名称
+func (p *HuggingFaceProvider) Name() string {
+	return p.ProviderName
+}
+
+// SuggestPoints 调用AI接口获取故事点数建议
+func (p *HuggingFaceProvider) SuggestPoints(ctx context.Context, prompt string, availablePoints []string) (*PointSuggestionResponse, error) {
+	content, err := p.Generate(ctx, prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestedPoint, reason, confidence := parseAIResponse(content, availablePoints)
+
+	return &PointSuggestionResponse{
+		SuggestedPoint: suggestedPoint,
+		Reason:         reason,
+		Confidence:     confidence,
+	}, nil
+}
+
+// Generate 调用AI接口并返回生成的文本
+func (p *HuggingFaceProvider) Generate(ctx context.Context, prompt string) (string, error) {
+	// 创建Hugging Face API请求
+	aiReq := HuggingFaceRequest{
+		Inputs: prompt,
+		Parameters: map[string]interface{}{
+			"max_new_tokens":   200,
+			"temperature":      0.7,
+			"top_p":            0.95,
+			"return_full_text": false,
+		},
+	}
+
+	// 将请求序列化为JSON
+	aiReqBody, err := json.Marshal(aiReq)
+	if err != nil {
+		return "", fmt.Errorf("error creating AI request: %v", err)
+	}
+
+	// 创建HTTP请求
+	aiRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, p.APIURL, bytes.NewBuffer(aiReqBody))
+	if err != nil {
+		return "", fmt.Errorf("error creating HTTP request: %v", err)
+	}
+
+	// 设置请求头
+	aiRequest.Header.Set("Content-Type", "application/json")
+	if p.APIKey != "" {
+		aiRequest.Header.Set("Authorization", "Bearer "+p.APIKey)
+	}
+
+	// 发送请求
+	aiResp, err := p.HTTPClient.Do(aiRequest)
+	if err != nil {
+		return "", fmt.Errorf("error calling AI API: %v", err)
+	}
+	defer aiResp.Body.Close()
+
+	// 读取响应体
+	aiRespBody, err := io.ReadAll(aiResp.Body)
+	if err != nil {
+		return "", fmt.Errorf("error reading AI API response: %v", err)
+	}
+
+	// 检查响应状态码
+	if aiResp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("AI API returned an error: %d - %s", aiResp.StatusCode, string(aiRespBody))
+	}
+
+	// 解析Hugging Face响应，无法解析时按纯文本响应处理
+	content := string(aiRespBody)
+	var hfResponse HuggingFaceResponse
+	if err := json.Unmarshal(aiRespBody, &hfResponse); err == nil {
+		if len(hfResponse) == 0 || hfResponse[0].GeneratedText == "" {
+			return "", fmt.Errorf("unable to parse AI response")
+		}
+		content = hfResponse[0].GeneratedText
+	}
+
+	return content, nil
+}
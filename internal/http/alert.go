@@ -8,6 +8,8 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/gorilla/mux"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/http/response"
 )
 
 var ActiveAlerts []interface{}
@@ -47,11 +49,7 @@ func (s *Service) handleGetAlerts() http.HandlerFunc {
 			return
 		}
 
-		meta := &pagination{
-			Count:  count,
-			Offset: offset,
-			Limit:  limit,
-		}
+		meta := response.NewMeta(count, limit, offset)
 
 		s.Success(w, r, http.StatusOK, alerts, meta)
 	}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -230,3 +231,74 @@ func (s *Service) handleUserAPIKeyDelete() http.HandlerFunc {
 		s.Success(w, r, http.StatusOK, keys, nil)
 	}
 }
+
+// handleUserAPIKeyUsage handles getting a users API key daily call counts per endpoint
+//
+//	@Summary		Get API Key Usage
+//	@Description	Get the API key daily call counts per endpoint, defaults to the last 30 days
+//	@Tags			apikey
+//	@Produce		json
+//	@Param			userId	path	string	true	"the user ID"
+//	@Param			keyID	path	string	true	"the API Key ID"
+//	@Param			since	query	string	false	"the first day to include (YYYY-MM-DD)"
+//	@Param			until	query	string	false	"the last day to include (YYYY-MM-DD)"
+//	@Success		200		object	standardJsonResponse{data=[]thunderdome.APIKeyUsage}
+//	@Failure		400		object	standardJsonResponse{}
+//	@Failure		403		object	standardJsonResponse{}
+//	@Failure		500		object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/users/{userId}/apikeys/{keyID}/usage [get]
+func (s *Service) handleUserAPIKeyUsage() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		vars := mux.Vars(r)
+		userID := vars["userId"]
+		idErr := validate.Var(userID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+		keyID := vars["keyID"]
+		keyIDErr := validate.Var(keyID, "required")
+		if keyIDErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, keyIDErr.Error()))
+			return
+		}
+
+		until := time.Now().UTC().Truncate(24 * time.Hour)
+		since := until.AddDate(0, 0, -29)
+		query := r.URL.Query()
+		if v := query.Get("since"); v != "" {
+			d, err := time.Parse(time.DateOnly, v)
+			if err != nil {
+				s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "INVALID_DATE"))
+				return
+			}
+			since = d
+		}
+		if v := query.Get("until"); v != "" {
+			d, err := time.Parse(time.DateOnly, v)
+			if err != nil {
+				s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "INVALID_DATE"))
+				return
+			}
+			until = d
+		}
+		if since.After(until) {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "INVALID_DATE"))
+			return
+		}
+
+		usage, err := s.ApiKeyDataSvc.GetAPIKeyUsage(ctx, userID, keyID, since, until)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleUserAPIKeyUsage error", zap.Error(err),
+				zap.String("entity_user_id", userID), zap.String("apikey_id", keyID),
+				zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, usage, nil)
+	}
+}
@@ -0,0 +1,323 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/http/poker"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// handleGetUserAsanaConnections gets a list of asana connections associated to user
+//
+//	@Summary		Get User Asana Connections
+//	@Description	get list of Asana connections associated to user
+//	@Tags			asana
+//	@Produce		json
+//	@Param			userId	path	string	true	"the user ID to find asana connections for"
+//	@Success		200		object	standardJsonResponse{data=[]thunderdome.AsanaConnection}
+//	@Failure		500		object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/users/{userId}/asana [get]
+func (s *Service) handleGetUserAsanaConnections() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		vars := mux.Vars(r)
+		userID := vars["userId"]
+
+		idErr := validate.Var(userID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		connections, err := s.AsanaDataSvc.FindConnectionsByUserID(ctx, userID)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error(
+				"handleGetUserAsanaConnections error", zap.Error(err), zap.String("entity_user_id", userID),
+				zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, connections, nil)
+	}
+}
+
+type asanaConnectionRequestBody struct {
+	Name        string `json:"name" validate:"required,max=256"`
+	AccessToken string `json:"access_token" validate:"required"`
+}
+
+// handleAsanaConnectionCreate creates a new Asana Connection
+//
+//	@Summary		Create Asana Connection
+//	@Description	Creates an Asana Connection associated to user
+//	@Tags			asana
+//	@Produce		json
+//	@Param			userId	path	string													true	"the user ID to associate asana connection to"
+//	@Param			asana	body	asanaConnectionRequestBody								true	"new asana_connection object"
+//	@Success		200		object	standardJsonResponse{data=thunderdome.AsanaConnection}	"returns new asana connection"
+//	@Failure		500		object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/users/{userId}/asana [post]
+func (s *Service) handleAsanaConnectionCreate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		vars := mux.Vars(r)
+		userID := vars["userId"]
+
+		idErr := validate.Var(userID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		var req = asanaConnectionRequestBody{}
+		body, bodyErr := io.ReadAll(r.Body)
+		if bodyErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, bodyErr.Error()))
+			return
+		}
+
+		jsonErr := json.Unmarshal(body, &req)
+		if jsonErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, jsonErr.Error()))
+			return
+		}
+
+		inputErr := validate.Struct(req)
+		if inputErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, inputErr.Error()))
+			return
+		}
+
+		connection, err := s.AsanaDataSvc.CreateConnection(ctx, userID, req.Name, req.AccessToken)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error(
+				"handleAsanaConnectionCreate error", zap.Error(err), zap.String("entity_user_id", userID),
+				zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, connection, nil)
+	}
+}
+
+// handleAsanaConnectionUpdate updates an Asana Connection
+//
+//	@Summary		Update Asana Connection
+//	@Description	Updates an Asana Connection associated to user
+//	@Tags			asana
+//	@Produce		json
+//	@Param			userId			path	string													true	"the user ID asana connection associated to"
+//	@Param			connectionId	path	string													true	"the asana_connection ID to update"
+//	@Param			asana			body	asanaConnectionRequestBody								true	"updated asana_connection object"
+//	@Success		200				object	standardJsonResponse{data=thunderdome.AsanaConnection}	"returns updated asana connection"
+//	@Failure		404				object	standardJsonResponse{}
+//	@Failure		500				object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/users/{userId}/asana/{connectionId} [put]
+func (s *Service) handleAsanaConnectionUpdate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		vars := mux.Vars(r)
+		userID := vars["userId"]
+		connectionID := vars["connectionId"]
+
+		cidErr := validate.Var(connectionID, "required,uuid")
+		if cidErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, cidErr.Error()))
+			return
+		}
+
+		idErr := validate.Var(userID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		var req = asanaConnectionRequestBody{}
+		body, bodyErr := io.ReadAll(r.Body)
+		if bodyErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, bodyErr.Error()))
+			return
+		}
+
+		jsonErr := json.Unmarshal(body, &req)
+		if jsonErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, jsonErr.Error()))
+			return
+		}
+
+		inputErr := validate.Struct(req)
+		if inputErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, inputErr.Error()))
+			return
+		}
+
+		existing, err := s.AsanaDataSvc.GetConnectionByID(ctx, connectionID)
+		if err != nil || existing.UserID != userID {
+			s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, "ASANA_CONNECTION_NOT_FOUND"))
+			return
+		}
+
+		connection, err := s.AsanaDataSvc.UpdateConnection(ctx, connectionID, req.Name, req.AccessToken)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error(
+				"handleAsanaConnectionUpdate error", zap.Error(err), zap.String("entity_user_id", userID),
+				zap.String("session_user_id", sessionUserID), zap.String("asana_connection_id", connectionID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, connection, nil)
+	}
+}
+
+// handleAsanaConnectionDelete deletes an Asana Connection
+//
+//	@Summary		Delete Asana Connection
+//	@Description	Deletes an Asana Connection associated to user
+//	@Tags			asana
+//	@Produce		json
+//	@Param			userId			path	string	true	"the user ID asana connection associated to"
+//	@Param			connectionId	path	string	true	"the asana_connection ID to delete"
+//	@Success		200				object	standardJsonResponse{}
+//	@Failure		404				object	standardJsonResponse{}
+//	@Failure		500				object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/users/{userId}/asana/{connectionId} [delete]
+func (s *Service) handleAsanaConnectionDelete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		vars := mux.Vars(r)
+		userID := vars["userId"]
+		connectionID := vars["connectionId"]
+
+		cidErr := validate.Var(connectionID, "required,uuid")
+		if cidErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, cidErr.Error()))
+			return
+		}
+
+		idErr := validate.Var(userID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		existing, err := s.AsanaDataSvc.GetConnectionByID(ctx, connectionID)
+		if err != nil || existing.UserID != userID {
+			s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, "ASANA_CONNECTION_NOT_FOUND"))
+			return
+		}
+
+		err = s.AsanaDataSvc.DeleteConnection(ctx, connectionID)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error(
+				"handleAsanaConnectionDelete error", zap.Error(err), zap.String("entity_user_id", userID),
+				zap.String("session_user_id", sessionUserID), zap.String("asana_connection_id", connectionID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, nil, nil)
+	}
+}
+
+type asanaImportRequestBody struct {
+	ConnectionID string  `json:"connectionId" validate:"required,uuid"`
+	ProjectID    string  `json:"projectId" validate:"required,numeric"`
+	SectionID    *string `json:"sectionId" validate:"omitempty,numeric"`
+}
+
+// handlePokerAsanaImport handles importing the tasks of an Asana project (or project section) as poker stories
+//
+//	@Summary		Import Poker Stories from Asana
+//	@Description	Imports the tasks of an Asana project as poker stories, stories with a referenceId already in the game are updated instead of duplicated
+//	@Param			battleId	path	string					true	"the poker game ID"
+//	@Param			asana		body	asanaImportRequestBody	true	"asana project to import"
+//	@Tags			poker
+//	@Produce		json
+//	@Success		200	object	standardJsonResponse{data=thunderdome.DuplicationResult}
+//	@Success		403	object	standardJsonResponse{}
+//	@Success		404	object	standardJsonResponse{}
+//	@Failure		422	object	standardJsonResponse{data=[]thunderdome.ValidationError}
+//	@Success		500	object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/battles/{battleId}/plans/import/asana [post]
+func (s *Service) handlePokerAsanaImport(pokerSvc *poker.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		gameID := vars["battleId"]
+		idErr := validate.Var(gameID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+
+		body, bodyErr := io.ReadAll(r.Body)
+		if bodyErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, bodyErr.Error()))
+			return
+		}
+
+		var req = asanaImportRequestBody{}
+		jsonErr := json.Unmarshal(body, &req)
+		if jsonErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, jsonErr.Error()))
+			return
+		}
+
+		inputErr := validate.Struct(req)
+		if inputErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, inputErr.Error()))
+			return
+		}
+
+		connection, err := s.AsanaDataSvc.GetConnectionByID(ctx, req.ConnectionID)
+		if err != nil || connection.UserID != sessionUserID {
+			s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, "ASANA_CONNECTION_NOT_FOUND"))
+			return
+		}
+
+		stories, err := s.AsanaDataSvc.ImportTasksFromProject(ctx, req.ConnectionID, req.ProjectID, req.SectionID)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handlePokerAsanaImport asana tasks error", zap.Error(err),
+				zap.String("poker_id", gameID), zap.String("session_user_id", sessionUserID),
+				zap.String("asana_connection_id", req.ConnectionID), zap.String("asana_project_id", req.ProjectID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		if len(stories) == 0 {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "ASANA_PROJECT_HAS_NO_TASKS"))
+			return
+		}
+
+		if s.storyLinksInvalid(w, stories) {
+			return
+		}
+
+		result, err := pokerSvc.ImportStories(ctx, gameID, sessionUserID, stories, s.Config.ImportDeduplicationEnabled)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handlePokerAsanaImport error", zap.Error(err),
+				zap.String("poker_id", gameID), zap.String("session_user_id", sessionUserID),
+				zap.Int("story_count", len(stories)))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, result, nil)
+	}
+}
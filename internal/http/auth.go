@@ -2,6 +2,8 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"strings"
@@ -23,6 +25,8 @@ type loginResponse struct {
 	SessionId   string            `json:"sessionId"`
 	MFARequired bool              `json:"mfaRequired"`
 	Subscribed  bool              `json:"subscribed"`
+	// PasswordUpdateRequired is set when the user's current password no longer satisfies the password policy
+	PasswordUpdateRequired bool `json:"passwordUpdateRequired"`
 }
 
 // handleLogin attempts to log in the user
@@ -35,6 +39,7 @@ type loginResponse struct {
 //	@Param			credentials	body	userLoginRequestBody	false	"user login object"
 //	@Success		200			object	standardJsonResponse{data=loginResponse}
 //	@Failure		401			object	standardJsonResponse{}
+//	@Failure		403			object	standardJsonResponse{}
 //	@Failure		500			object	standardJsonResponse{}
 //	@Router			/auth [post]
 func (s *Service) handleLogin() http.HandlerFunc {
@@ -62,7 +67,13 @@ func (s *Service) handleLogin() http.HandlerFunc {
 		authedUser, credential, sessionID, err := s.AuthDataSvc.AuthUser(ctx, u.Email, u.Password)
 		if err != nil {
 			userErr := err.Error()
-			if userErr == "USER_NOT_FOUND" || userErr == "INVALID_PASSWORD" || userErr == "USER_DISABLED" {
+			if errors.Is(err, thunderdome.ErrSSORequired) {
+				s.Failure(w, r, http.StatusForbidden, Errorf(EUNAUTHORIZED, s.ssoRequiredMessage()))
+			} else if errors.Is(err, thunderdome.ErrAccountPending) {
+				s.Failure(w, r, http.StatusForbidden, Errorf(EUNAUTHORIZED, "ACCOUNT_PENDING"))
+			} else if errors.Is(err, thunderdome.ErrUserMerged) {
+				s.Failure(w, r, http.StatusForbidden, Errorf(EUNAUTHORIZED, "USER_MERGED"))
+			} else if userErr == "USER_NOT_FOUND" || userErr == "INVALID_PASSWORD" || userErr == "USER_DISABLED" {
 				s.Failure(w, r, http.StatusUnauthorized, Errorf(EINVALID, "INVALID_LOGIN"))
 			} else {
 				s.Logger.Ctx(ctx).Error("handleLogin error", zap.Error(err),
@@ -75,10 +86,11 @@ func (s *Service) handleLogin() http.HandlerFunc {
 		subscribed := s.SubscriptionDataSvc.CheckActiveSubscriber(ctx, authedUser.ID)
 
 		res := loginResponse{
-			User:        authedUser,
-			SessionId:   sessionID,
-			MFARequired: credential.MFAEnabled,
-			Subscribed:  subscribed == nil,
+			User:                   authedUser,
+			SessionId:              sessionID,
+			MFARequired:            credential.MFAEnabled,
+			Subscribed:             subscribed == nil,
+			PasswordUpdateRequired: thunderdome.ValidatePassword(u.Password, s.Config.PasswordPolicy) != nil,
 		}
 
 		if res.MFARequired {
@@ -98,6 +110,15 @@ func (s *Service) handleLogin() http.HandlerFunc {
 	}
 }
 
+// ssoRequiredMessage directs the user to sign in via the configured SSO provider
+func (s *Service) ssoRequiredMessage() string {
+	if !s.Config.GoogleAuth.Enabled {
+		return "SSO_REQUIRED"
+	}
+
+	return fmt.Sprintf("SSO_REQUIRED: sign in via %s/oauth/%s/login", s.Config.PathPrefix, s.Config.GoogleAuth.ProviderName)
+}
+
 type userLoginLdapRequestBody struct {
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required"`
@@ -444,6 +465,11 @@ func (s *Service) handleUserRegistration() http.HandlerFunc {
 			return
 		}
 
+		if policyErr := thunderdome.ValidatePassword(userPassword, s.Config.PasswordPolicy); policyErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, policyErr.Error()))
+			return
+		}
+
 		newUser, verifyID, err := s.UserDataSvc.CreateUserRegistered(ctx, userName, userEmail, userPassword, activeUserID)
 		if err != nil {
 			s.Logger.Ctx(ctx).Error("handleUserRegistration error", zap.Error(err),
@@ -459,7 +485,13 @@ func (s *Service) handleUserRegistration() http.HandlerFunc {
 			s.Cookie.ClearUserCookies(w)
 		}
 
-		sessionID, err := s.AuthDataSvc.CreateSession(ctx, newUser.ID, true)
+		// users pending registration approval can't log in until an admin approves them
+		if newUser.RegistrationStatus == thunderdome.RegistrationStatusPending {
+			s.Success(w, r, http.StatusOK, newUser, nil)
+			return
+		}
+
+		sessionID, err := s.AuthDataSvc.CreateSession(ctx, newUser.ID, true, thunderdome.AuthMethodPassword)
 		if err != nil {
 			s.Logger.Ctx(ctx).Error("handleUserRegistration error", zap.Error(err),
 				zap.String("session_user_id", newUser.ID))
@@ -568,6 +600,11 @@ func (s *Service) handleResetPassword() http.HandlerFunc {
 			return
 		}
 
+		if policyErr := thunderdome.ValidatePassword(u.Password1, s.Config.PasswordPolicy); policyErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, policyErr.Error()))
+			return
+		}
+
 		userName, userEmail, resetErr := s.AuthDataSvc.UserResetPassword(ctx, u.ResetID, u.Password1)
 		if resetErr != nil {
 			s.Logger.Ctx(ctx).Error("handleResetPassword error", zap.Error(resetErr),
@@ -622,6 +659,11 @@ func (s *Service) handleUpdatePassword() http.HandlerFunc {
 			return
 		}
 
+		if policyErr := thunderdome.ValidatePassword(u.Password1, s.Config.PasswordPolicy); policyErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, policyErr.Error()))
+			return
+		}
+
 		userName, userEmail, updateErr := s.AuthDataSvc.UserUpdatePassword(ctx, sessionUserID, u.Password1)
 		if updateErr != nil {
 			s.Logger.Ctx(ctx).Error("handleResetPassword error", zap.Error(updateErr),
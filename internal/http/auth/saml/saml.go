@@ -0,0 +1,297 @@
+package saml
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	gosaml "github.com/crewjam/saml"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+const metadataRequestTimeout = 10 * time.Second
+
+// nameAttributes are the assertion attributes checked in order for the user's display name
+var nameAttributes = []string{"displayName", "cn", "urn:oid:2.16.840.1.113730.3.1.241", "urn:oid:2.5.4.3"}
+
+// New creates a new saml service, fetching the identity provider metadata
+func New(
+	config Config,
+	cookie CookieManager,
+	logger *otelzap.Logger,
+	authDataSvc AuthDataSvc,
+	teamDataSvc TeamDataSvc,
+	subscriptionDataSvc SubscriptionDataSvc,
+	ctx context.Context,
+) (*Service, error) {
+	s := Service{
+		config:              config,
+		cookie:              cookie,
+		logger:              logger,
+		authDataSvc:         authDataSvc,
+		teamDataSvc:         teamDataSvc,
+		subscriptionDataSvc: subscriptionDataSvc,
+	}
+
+	keyPair, err := tls.LoadX509KeyPair(config.CertFile, config.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("saml load sp key pair error: %v", err)
+	}
+	certificate, err := x509.ParseCertificate(keyPair.Certificate[0])
+	if err != nil {
+		return nil, fmt.Errorf("saml parse sp certificate error: %v", err)
+	}
+	key, ok := keyPair.PrivateKey.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("saml sp private key must be an RSA key")
+	}
+
+	acsURL, err := url.Parse(config.SPCallbackURL)
+	if err != nil {
+		return nil, fmt.Errorf("saml parse sp callback url error: %v", err)
+	}
+
+	idpMetadata, err := fetchIDPMetadata(ctx, config.IDPMetadataURL)
+	if err != nil {
+		return nil, err
+	}
+
+	s.sp = &gosaml.ServiceProvider{
+		EntityID:    config.SPEntityID,
+		Key:         key,
+		Certificate: certificate,
+		MetadataURL: *acsURL.ResolveReference(&url.URL{Path: "metadata"}),
+		AcsURL:      *acsURL,
+		IDPMetadata: idpMetadata,
+	}
+
+	return &s, nil
+}
+
+// fetchIDPMetadata gets and parses the identity provider metadata
+func fetchIDPMetadata(ctx context.Context, metadataURL string) (*gosaml.EntityDescriptor, error) {
+	ctx, cancel := context.WithTimeout(ctx, metadataRequestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, metadataURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("saml idp metadata request error: %v", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("saml idp metadata fetch error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("saml idp metadata unexpected status: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("saml idp metadata read error: %v", err)
+	}
+
+	var metadata gosaml.EntityDescriptor
+	if err := xml.Unmarshal(body, &metadata); err != nil {
+		return nil, fmt.Errorf("saml idp metadata parse error: %v", err)
+	}
+
+	return &metadata, nil
+}
+
+// relayState hex encodes the auth nonce as base64 characters don't survive every idp's relay state handling
+func relayState(nonce string) string {
+	return hex.EncodeToString([]byte(nonce))
+}
+
+// requestID converts the relay state into the AuthnRequest ID, which must be a valid xml ID
+func requestID(relayState string) string {
+	return "id-" + relayState
+}
+
+// assertionAttributeValues gets the values of the first attribute matching the name or friendly name
+func assertionAttributeValues(assertion *gosaml.Assertion, name string) []string {
+	values := make([]string, 0)
+	for _, statement := range assertion.AttributeStatements {
+		for _, attr := range statement.Attributes {
+			if attr.Name != name && attr.FriendlyName != name {
+				continue
+			}
+			for _, value := range attr.Values {
+				if value.Value != "" {
+					values = append(values, value.Value)
+				}
+			}
+			return values
+		}
+	}
+
+	return values
+}
+
+// assertionUserName gets the user's display name from the assertion, falling back to the NameID
+func assertionUserName(assertion *gosaml.Assertion, nameID string) string {
+	for _, name := range nameAttributes {
+		if values := assertionAttributeValues(assertion, name); len(values) > 0 {
+			return values[0]
+		}
+	}
+
+	return nameID
+}
+
+// HandleMetadata serves the service provider metadata
+func (s *Service) HandleMetadata() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		metadata, err := xml.MarshalIndent(s.sp.Metadata(), "", "  ")
+		if err != nil {
+			s.logger.Ctx(r.Context()).Error("error marshaling saml sp metadata", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/samlmetadata+xml")
+		_, _ = w.Write(metadata)
+	}
+}
+
+// HandleLogin redirects the user to the identity provider with a new AuthnRequest
+func (s *Service) HandleLogin() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		logger := s.logger.Ctx(ctx)
+
+		// the nonce is sent as the relay state to validate the assertion is in response to this request
+		nonce, err := s.authDataSvc.OauthCreateNonce(ctx)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		state := relayState(nonce)
+
+		idpURL := s.config.IDPURL
+		if idpURL == "" {
+			idpURL = s.sp.GetSSOBindingLocation(gosaml.HTTPRedirectBinding)
+		}
+
+		authnRequest, err := s.sp.MakeAuthenticationRequest(idpURL, gosaml.HTTPRedirectBinding, gosaml.HTTPPostBinding)
+		if err != nil {
+			logger.Error("error making saml authn request", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		authnRequest.ID = requestID(state)
+
+		redirectURL, err := authnRequest.Redirect(state, s.sp)
+		if err != nil {
+			logger.Error("error making saml authn request redirect", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		http.Redirect(w, r, redirectURL.String(), http.StatusSeeOther)
+	}
+}
+
+// HandleACS handles the identity provider posted assertion, authenticating the user
+func (s *Service) HandleACS() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		logger := s.logger.Ctx(ctx)
+
+		if err := r.ParseForm(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		state := r.PostForm.Get("RelayState")
+		nonce, err := hex.DecodeString(state)
+		if err != nil {
+			logger.Error("invalid saml relay state", zap.Error(err))
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if err := s.authDataSvc.OauthValidateNonce(ctx, string(nonce)); err != nil {
+			logger.Error("saml relay state nonce validation failed", zap.Error(err))
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		assertion, err := s.sp.ParseResponse(r, []string{requestID(state)})
+		if err != nil {
+			var invalidErr *gosaml.InvalidResponseError
+			if errors.As(err, &invalidErr) {
+				err = invalidErr.PrivateErr
+			}
+			logger.Error("error validating saml assertion", zap.Error(err))
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if assertion.Subject == nil || assertion.Subject.NameID == nil || assertion.Subject.NameID.Value == "" {
+			logger.Error("saml assertion missing NameID")
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		nameID := assertion.Subject.NameID.Value
+
+		user, sessionID, userErr := s.authDataSvc.SAMLAuthUser(ctx, nameID, assertionUserName(assertion, nameID))
+		if userErr != nil {
+			logger.Error("error authenticating saml user", zap.Error(userErr))
+			if userErr.Error() == "USER_DISABLED" {
+				w.WriteHeader(http.StatusUnauthorized)
+			} else {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+			return
+		}
+
+		if s.config.GroupAttribute != "" && s.config.GroupOrganizationID != "" {
+			groups := assertionAttributeValues(assertion, s.config.GroupAttribute)
+			if err := s.teamDataSvc.TeamAddUserByNames(ctx, s.config.GroupOrganizationID, user.ID, groups, thunderdome.EntityMemberUserType); err != nil {
+				// group mapping failures shouldn't prevent the user from logging in
+				logger.Error("error mapping saml groups to teams", zap.Error(err),
+					zap.String("userId", user.ID))
+			}
+		}
+
+		if scErr := s.cookie.CreateSessionCookie(w, sessionID); scErr != nil {
+			logger.Error("error creating saml user session cookie", zap.Error(scErr),
+				zap.String("userId", user.ID))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		subscribedErr := s.subscriptionDataSvc.CheckActiveSubscriber(ctx, user.ID)
+
+		if err := s.cookie.CreateUserUICookie(w, thunderdome.UserUICookie{
+			ID:                   user.ID,
+			Name:                 user.Name,
+			Email:                user.Email,
+			Rank:                 user.Type,
+			Locale:               user.Locale,
+			NotificationsEnabled: user.NotificationsEnabled,
+			Subscribed:           subscribedErr == nil,
+		}); err != nil {
+			logger.Error("error creating saml user ui cookie", zap.Error(err),
+				zap.String("userId", user.ID))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		http.Redirect(w, r, s.config.UIRedirectURL, http.StatusFound)
+	}
+}
@@ -0,0 +1,357 @@
+package saml
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"encoding/xml"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	gosaml "github.com/crewjam/saml"
+	"github.com/crewjam/saml/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+// MockAuthDataSvc is a mock implementation of the AuthDataSvc
+type MockAuthDataSvc struct {
+	mock.Mock
+}
+
+func (m *MockAuthDataSvc) OauthCreateNonce(ctx context.Context) (string, error) {
+	args := m.Called(ctx)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockAuthDataSvc) OauthValidateNonce(ctx context.Context, nonceId string) error {
+	args := m.Called(ctx, nonceId)
+	return args.Error(0)
+}
+
+func (m *MockAuthDataSvc) SAMLAuthUser(ctx context.Context, nameID string, name string) (*thunderdome.User, string, error) {
+	args := m.Called(ctx, nameID, name)
+	var user *thunderdome.User
+	if args.Get(0) != nil {
+		user = args.Get(0).(*thunderdome.User)
+	}
+	return user, args.String(1), args.Error(2)
+}
+
+// MockTeamDataSvc is a mock implementation of the TeamDataSvc
+type MockTeamDataSvc struct {
+	mock.Mock
+}
+
+func (m *MockTeamDataSvc) TeamAddUserByNames(ctx context.Context, organizationID string, userID string, teamNames []string, role string) error {
+	args := m.Called(ctx, organizationID, userID, teamNames, role)
+	return args.Error(0)
+}
+
+// MockSubscriptionDataSvc is a mock implementation of the SubscriptionDataSvc
+type MockSubscriptionDataSvc struct {
+	mock.Mock
+}
+
+func (m *MockSubscriptionDataSvc) CheckActiveSubscriber(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+// MockCookieManager is a mock implementation of the CookieManager
+type MockCookieManager struct {
+	mock.Mock
+}
+
+func (m *MockCookieManager) CreateSessionCookie(w http.ResponseWriter, sessionID string) error {
+	args := m.Called(w, sessionID)
+	return args.Error(0)
+}
+
+func (m *MockCookieManager) CreateUserUICookie(w http.ResponseWriter, userUiCookie thunderdome.UserUICookie) error {
+	args := m.Called(w, userUiCookie)
+	return args.Error(0)
+}
+
+// testSessionProvider always returns the same signed in idp user
+type testSessionProvider struct {
+	session *gosaml.Session
+}
+
+func (p testSessionProvider) GetSession(w http.ResponseWriter, r *http.Request, req *gosaml.IdpAuthnRequest) *gosaml.Session {
+	return p.session
+}
+
+// testServiceProviderProvider fetches the service provider metadata from its metadata endpoint
+type testServiceProviderProvider struct {
+	metadataURL string
+}
+
+func (p testServiceProviderProvider) GetServiceProvider(r *http.Request, serviceProviderID string) (*gosaml.EntityDescriptor, error) {
+	if serviceProviderID != p.metadataURL {
+		return nil, os.ErrNotExist
+	}
+
+	resp, err := http.Get(p.metadataURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var metadata gosaml.EntityDescriptor
+	if err := xml.Unmarshal(body, &metadata); err != nil {
+		return nil, err
+	}
+
+	return &metadata, nil
+}
+
+// newTestCertificate generates a self-signed certificate and its RSA key
+func newTestCertificate(t *testing.T, commonName string) (*rsa.PrivateKey, *x509.Certificate) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return key, cert
+}
+
+type samlTestEnv struct {
+	spServer    *httptest.Server
+	client      *http.Client
+	authDataSvc *MockAuthDataSvc
+	teamDataSvc *MockTeamDataSvc
+	subDataSvc  *MockSubscriptionDataSvc
+	cookie      *MockCookieManager
+}
+
+// newSAMLTestEnv starts a test identity provider and a service provider using the saml service
+func newSAMLTestEnv(t *testing.T, session *gosaml.Session) *samlTestEnv {
+	t.Helper()
+
+	env := &samlTestEnv{
+		client: &http.Client{
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		authDataSvc: new(MockAuthDataSvc),
+		teamDataSvc: new(MockTeamDataSvc),
+		subDataSvc:  new(MockSubscriptionDataSvc),
+		cookie:      new(MockCookieManager),
+	}
+
+	spMux := http.NewServeMux()
+	env.spServer = httptest.NewServer(spMux)
+	t.Cleanup(env.spServer.Close)
+
+	idpMux := http.NewServeMux()
+	idpServer := httptest.NewServer(idpMux)
+	t.Cleanup(idpServer.Close)
+
+	idpKey, idpCert := newTestCertificate(t, "idp.example.com")
+	idpMetadataURL, _ := url.Parse(idpServer.URL + "/metadata")
+	idpSSOURL, _ := url.Parse(idpServer.URL + "/sso")
+	idp := &gosaml.IdentityProvider{
+		Key:             idpKey,
+		Certificate:     idpCert,
+		Logger:          logger.DefaultLogger,
+		MetadataURL:     *idpMetadataURL,
+		SSOURL:          *idpSSOURL,
+		SessionProvider: testSessionProvider{session: session},
+		ServiceProviderProvider: testServiceProviderProvider{
+			metadataURL: env.spServer.URL + "/auth/saml/metadata",
+		},
+	}
+	idpMux.HandleFunc("/metadata", idp.ServeMetadata)
+	idpMux.HandleFunc("/sso", idp.ServeSSO)
+
+	spKey, spCert := newTestCertificate(t, "sp.example.com")
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "sp.crt")
+	keyFile := filepath.Join(dir, "sp.key")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: spCert.Raw}), 0600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(spKey)}), 0600))
+
+	s, err := New(Config{
+		IDPMetadataURL:      idpMetadataURL.String(),
+		SPEntityID:          env.spServer.URL + "/auth/saml/metadata",
+		SPCallbackURL:       env.spServer.URL + "/auth/saml/acs",
+		CertFile:            certFile,
+		KeyFile:             keyFile,
+		GroupAttribute:      "eduPersonAffiliation",
+		GroupOrganizationID: "org-id",
+		UIRedirectURL:       "/",
+	}, env.cookie, otelzap.New(zap.NewNop()), env.authDataSvc, env.teamDataSvc, env.subDataSvc, context.Background())
+	require.NoError(t, err)
+
+	spMux.HandleFunc("GET /auth/saml/metadata", s.HandleMetadata())
+	spMux.HandleFunc("GET /auth/saml/login", s.HandleLogin())
+	spMux.HandleFunc("POST /auth/saml/acs", s.HandleACS())
+
+	return env
+}
+
+var formInputPattern = regexp.MustCompile(`name="(SAMLResponse|RelayState)" value="([^"]*)"`)
+
+// idpLogin starts the login at the service provider and returns the assertion form the idp posts back
+func (env *samlTestEnv) idpLogin(t *testing.T) url.Values {
+	t.Helper()
+
+	loginResp, err := env.client.Get(env.spServer.URL + "/auth/saml/login")
+	require.NoError(t, err)
+	loginResp.Body.Close()
+	require.Equal(t, http.StatusSeeOther, loginResp.StatusCode)
+
+	idpResp, err := env.client.Get(loginResp.Header.Get("Location"))
+	require.NoError(t, err)
+	defer idpResp.Body.Close()
+	require.Equal(t, http.StatusOK, idpResp.StatusCode)
+
+	body, err := io.ReadAll(idpResp.Body)
+	require.NoError(t, err)
+
+	form := url.Values{}
+	for _, match := range formInputPattern.FindAllStringSubmatch(string(body), -1) {
+		form.Set(match[1], match[2])
+	}
+	require.NotEmpty(t, form.Get("SAMLResponse"))
+
+	return form
+}
+
+// TestSAMLLoginFlow makes sure a user signed in at the idp is authenticated, added to their group teams and given a session
+func TestSAMLLoginFlow(t *testing.T) {
+	env := newSAMLTestEnv(t, &gosaml.Session{
+		ID:             "idp-session",
+		CreateTime:     time.Now(),
+		ExpireTime:     time.Now().Add(time.Hour),
+		NameID:         "jane@example.com",
+		NameIDFormat:   "urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress",
+		UserCommonName: "Jane Doe",
+		Groups:         []string{"Platform", "Mobile"},
+	})
+
+	// base64 characters aren't valid in an xml ID nor safe in every relay state so make sure the nonce is encoded
+	nonce := "bm9uY2U+/="
+	user := &thunderdome.User{ID: "user-id", Name: "Jane Doe", Email: "jane@example.com", Type: thunderdome.RegisteredUserType}
+	env.authDataSvc.On("OauthCreateNonce", mock.Anything).Return(nonce, nil)
+	env.authDataSvc.On("OauthValidateNonce", mock.Anything, nonce).Return(nil)
+	env.authDataSvc.On("SAMLAuthUser", mock.Anything, "jane@example.com", "Jane Doe").Return(user, "session-id", nil)
+	env.teamDataSvc.On("TeamAddUserByNames", mock.Anything, "org-id", "user-id", []string{"Platform", "Mobile"}, thunderdome.EntityMemberUserType).Return(nil)
+	env.subDataSvc.On("CheckActiveSubscriber", mock.Anything, "user-id").Return(errors.New("NOT_SUBSCRIBED"))
+	env.cookie.On("CreateSessionCookie", mock.Anything, "session-id").Return(nil)
+	env.cookie.On("CreateUserUICookie", mock.Anything, mock.MatchedBy(func(c thunderdome.UserUICookie) bool {
+		return c.ID == "user-id" && c.Email == "jane@example.com" && !c.Subscribed
+	})).Return(nil)
+
+	form := env.idpLogin(t)
+	assert.Equal(t, relayState(nonce), form.Get("RelayState"))
+
+	resp, err := env.client.PostForm(env.spServer.URL+"/auth/saml/acs", form)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusFound, resp.StatusCode)
+	assert.Equal(t, "/", resp.Header.Get("Location"))
+	env.authDataSvc.AssertExpectations(t)
+	env.teamDataSvc.AssertExpectations(t)
+	env.cookie.AssertExpectations(t)
+}
+
+// TestSAMLACSInvalidRelayState makes sure assertions not in response to a known login request are rejected
+func TestSAMLACSInvalidRelayState(t *testing.T) {
+	env := newSAMLTestEnv(t, &gosaml.Session{
+		ID:         "idp-session",
+		CreateTime: time.Now(),
+		ExpireTime: time.Now().Add(time.Hour),
+		NameID:     "jane@example.com",
+	})
+
+	env.authDataSvc.On("OauthCreateNonce", mock.Anything).Return("nonce", nil)
+	env.authDataSvc.On("OauthValidateNonce", mock.Anything, "forged").Return(errors.New("nonce invalid"))
+
+	form := env.idpLogin(t)
+	form.Set("RelayState", relayState("forged"))
+
+	resp, err := env.client.PostForm(env.spServer.URL+"/auth/saml/acs", form)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	env.authDataSvc.AssertNotCalled(t, "SAMLAuthUser", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestSAMLACSMismatchedRequestID makes sure an assertion can't be replayed with another login request's nonce
+func TestSAMLACSMismatchedRequestID(t *testing.T) {
+	env := newSAMLTestEnv(t, &gosaml.Session{
+		ID:         "idp-session",
+		CreateTime: time.Now(),
+		ExpireTime: time.Now().Add(time.Hour),
+		NameID:     "jane@example.com",
+	})
+
+	env.authDataSvc.On("OauthCreateNonce", mock.Anything).Return("nonce", nil)
+	env.authDataSvc.On("OauthValidateNonce", mock.Anything, "other-nonce").Return(nil)
+
+	form := env.idpLogin(t)
+	form.Set("RelayState", relayState("other-nonce"))
+
+	resp, err := env.client.PostForm(env.spServer.URL+"/auth/saml/acs", form)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	env.authDataSvc.AssertNotCalled(t, "SAMLAuthUser", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestSAMLMetadata makes sure the service provider metadata advertises the assertion consumer service
+func TestSAMLMetadata(t *testing.T) {
+	env := newSAMLTestEnv(t, &gosaml.Session{})
+
+	resp, err := env.client.Get(env.spServer.URL + "/auth/saml/metadata")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/samlmetadata+xml", resp.Header.Get("Content-Type"))
+	assert.True(t, strings.Contains(string(body), env.spServer.URL+"/auth/saml/acs"))
+}
@@ -0,0 +1,62 @@
+package saml
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	gosaml "github.com/crewjam/saml"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+)
+
+// Config holds the configuration for the saml service
+type Config struct {
+	// Identity provider SSO URL, defaults to the HTTP-Redirect location from the metadata
+	IDPURL         string
+	IDPMetadataURL string
+	// Service provider entity ID, defaults to the SP metadata URL
+	SPEntityID string
+	// Assertion consumer service URL the identity provider posts assertions to
+	SPCallbackURL string
+	CertFile      string
+	KeyFile       string
+	// Assertion attribute whose values are matched to team names, group mapping is disabled when empty
+	GroupAttribute string
+	// Organization whose teams the group names are matched to, group mapping is disabled when empty
+	GroupOrganizationID string
+	UIRedirectURL       string
+}
+
+// CookieManager is an interface for managing cookies
+type CookieManager interface {
+	CreateSessionCookie(w http.ResponseWriter, sessionID string) error
+	CreateUserUICookie(w http.ResponseWriter, userUiCookie thunderdome.UserUICookie) error
+}
+
+// AuthDataSvc is an interface for the auth data service
+type AuthDataSvc interface {
+	OauthCreateNonce(ctx context.Context) (string, error)
+	OauthValidateNonce(ctx context.Context, nonceId string) error
+	SAMLAuthUser(ctx context.Context, nameID string, name string) (*thunderdome.User, string, error)
+}
+
+// TeamDataSvc is an interface for the team data service
+type TeamDataSvc interface {
+	TeamAddUserByNames(ctx context.Context, organizationID string, userID string, teamNames []string, role string) error
+}
+
+// SubscriptionDataSvc is an interface for the subscription data service
+type SubscriptionDataSvc interface {
+	CheckActiveSubscriber(ctx context.Context, userID string) error
+}
+
+// Service is the saml service
+type Service struct {
+	config              Config
+	cookie              CookieManager
+	sp                  *gosaml.ServiceProvider
+	logger              *otelzap.Logger
+	authDataSvc         AuthDataSvc
+	teamDataSvc         TeamDataSvc
+	subscriptionDataSvc SubscriptionDataSvc
+}
@@ -0,0 +1,337 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAuthDataSvc is a mock implementation of the AuthDataSvc
+type MockAuthDataSvc struct {
+	mock.Mock
+}
+
+func (m *MockAuthDataSvc) AuthUser(ctx context.Context, email string, password string) (*thunderdome.User, *thunderdome.Credential, string, error) {
+	args := m.Called(ctx, email, password)
+	var user *thunderdome.User
+	if args.Get(0) != nil {
+		user = args.Get(0).(*thunderdome.User)
+	}
+	var cred *thunderdome.Credential
+	if args.Get(1) != nil {
+		cred = args.Get(1).(*thunderdome.Credential)
+	}
+	return user, cred, args.String(2), args.Error(3)
+}
+
+func (m *MockAuthDataSvc) OauthCreateNonce(ctx context.Context) (string, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m *MockAuthDataSvc) OauthValidateNonce(ctx context.Context, nonceId string) error {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m *MockAuthDataSvc) OauthAuthUser(ctx context.Context, provider string, sub string, email string, emailVerified bool, name string, pictureUrl string) (*thunderdome.User, string, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m *MockAuthDataSvc) SAMLAuthUser(ctx context.Context, nameID string, name string) (*thunderdome.User, string, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m *MockAuthDataSvc) UserResetRequest(ctx context.Context, email string) (resetID string, userName string, resetErr error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m *MockAuthDataSvc) UserResetPassword(ctx context.Context, resetID string, password string) (userName string, email string, resetErr error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m *MockAuthDataSvc) UserUpdatePassword(ctx context.Context, userID string, password string) (name string, email string, resetErr error) {
+	args := m.Called(ctx, userID, password)
+	return args.String(0), args.String(1), args.Error(2)
+}
+
+func (m *MockAuthDataSvc) UserVerifyRequest(ctx context.Context, userId string) (*thunderdome.User, string, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m *MockAuthDataSvc) VerifyUserAccount(ctx context.Context, verifyID string) error {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m *MockAuthDataSvc) MFASetupGenerate(email string) (string, string, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m *MockAuthDataSvc) MFASetupValidate(ctx context.Context, userID string, secret string, passcode string) error {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m *MockAuthDataSvc) MFARemove(ctx context.Context, userID string) error {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m *MockAuthDataSvc) MFATokenValidate(ctx context.Context, sessionId string, passcode string) error {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m *MockAuthDataSvc) CreateSession(ctx context.Context, userId string, enabled bool, authMethod string) (string, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m *MockAuthDataSvc) EnableSession(ctx context.Context, sessionId string) error {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m *MockAuthDataSvc) GetSessionUserByID(ctx context.Context, sessionId string) (*thunderdome.User, error) {
+	args := m.Called(ctx, sessionId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*thunderdome.User), args.Error(1)
+}
+
+func (m *MockAuthDataSvc) DeleteSession(ctx context.Context, sessionId string) error {
+	//TODO implement me
+	panic("implement me")
+}
+
+// MockEmailService is a mock implementation of the EmailService, methods not overridden panic when called
+type MockEmailService struct {
+	mock.Mock
+	EmailService
+}
+
+func (m *MockEmailService) SendPasswordUpdate(userName string, userEmail string) error {
+	args := m.Called(userName, userEmail)
+	return args.Error(0)
+}
+
+func TestHandleLoginSSORequired(t *testing.T) {
+	tests := []struct {
+		name               string
+		email              string
+		expectedStatusCode int
+		expectedError      string
+		setupMocks         func(*MockAuthDataSvc, *MockSubscriptionDataService)
+	}{
+		{
+			name:               "SSO required org member rejected",
+			email:              "member@thunderdome.dev",
+			expectedStatusCode: http.StatusForbidden,
+			expectedError:      "SSO_REQUIRED: sign in via /oauth/google/login",
+			setupMocks: func(mockAuthDataSvc *MockAuthDataSvc, mockSubDataSvc *MockSubscriptionDataService) {
+				mockAuthDataSvc.On("AuthUser", mock.Anything, "member@thunderdome.dev", "infinitystones").
+					Return(nil, nil, "", thunderdome.ErrSSORequired)
+			},
+		},
+		{
+			name:               "SSO exempt user allowed",
+			email:              "exempt@thunderdome.dev",
+			expectedStatusCode: http.StatusOK,
+			setupMocks: func(mockAuthDataSvc *MockAuthDataSvc, mockSubDataSvc *MockSubscriptionDataService) {
+				mockAuthDataSvc.On("AuthUser", mock.Anything, "exempt@thunderdome.dev", "infinitystones").
+					Return(
+						&thunderdome.User{ID: "323e4567-e89b-12d3-a456-426614174000"},
+						&thunderdome.Credential{MFAEnabled: true},
+						"session-id",
+						nil,
+					)
+				mockSubDataSvc.On("CheckActiveSubscriber", mock.Anything, "323e4567-e89b-12d3-a456-426614174000").Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAuthDataSvc := new(MockAuthDataSvc)
+			mockSubDataSvc := new(MockSubscriptionDataService)
+			service := &Service{
+				Config: &Config{
+					GoogleAuth: AuthProvider{
+						Enabled:            true,
+						AuthProviderConfig: thunderdome.AuthProviderConfig{ProviderName: "google"},
+					},
+				},
+				AuthDataSvc:         mockAuthDataSvc,
+				SubscriptionDataSvc: mockSubDataSvc,
+			}
+
+			tt.setupMocks(mockAuthDataSvc, mockSubDataSvc)
+
+			body := `{"email":"` + tt.email + `","password":"infinitystones"}`
+			req := httptest.NewRequest("POST", "/auth", strings.NewReader(body))
+			rr := httptest.NewRecorder()
+			service.handleLogin().ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+
+			var response standardJsonResponse
+			err := json.Unmarshal(rr.Body.Bytes(), &response)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedError, response.Error)
+
+			mockAuthDataSvc.AssertExpectations(t)
+			mockSubDataSvc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandleLoginPasswordUpdateRequired(t *testing.T) {
+	tests := []struct {
+		name           string
+		policy         thunderdome.PasswordPolicy
+		updateRequired bool
+	}{
+		{
+			name:           "password satisfies policy",
+			policy:         thunderdome.PasswordPolicy{MinLength: 6, MaxLength: 72},
+			updateRequired: false,
+		},
+		{
+			name:           "password fails stricter policy",
+			policy:         thunderdome.PasswordPolicy{MinLength: 6, MaxLength: 72, RequireDigit: true},
+			updateRequired: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAuthDataSvc := new(MockAuthDataSvc)
+			mockSubDataSvc := new(MockSubscriptionDataService)
+			service := &Service{
+				Config:              &Config{PasswordPolicy: tt.policy},
+				AuthDataSvc:         mockAuthDataSvc,
+				SubscriptionDataSvc: mockSubDataSvc,
+			}
+
+			mockAuthDataSvc.On("AuthUser", mock.Anything, "thor@thunderdome.dev", "infinitystones").
+				Return(
+					&thunderdome.User{ID: "323e4567-e89b-12d3-a456-426614174000"},
+					&thunderdome.Credential{MFAEnabled: true},
+					"session-id",
+					nil,
+				)
+			mockSubDataSvc.On("CheckActiveSubscriber", mock.Anything, "323e4567-e89b-12d3-a456-426614174000").Return(nil)
+
+			body := `{"email":"thor@thunderdome.dev","password":"infinitystones"}`
+			req := httptest.NewRequest("POST", "/auth", strings.NewReader(body))
+			rr := httptest.NewRecorder()
+			service.handleLogin().ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+
+			var response struct {
+				Data loginResponse `json:"data"`
+			}
+			err := json.Unmarshal(rr.Body.Bytes(), &response)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.updateRequired, response.Data.PasswordUpdateRequired)
+		})
+	}
+}
+
+func TestHandleUpdatePasswordPolicy(t *testing.T) {
+	tests := []struct {
+		name               string
+		password           string
+		expectedStatusCode int
+		expectedError      string
+		setupMocks         func(*MockAuthDataSvc)
+	}{
+		{
+			name:               "password violates policy",
+			password:           "infinitystones",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError:      "PASSWORD_POLICY_VIOLATION: PASSWORD_REQUIRES_UPPERCASE, PASSWORD_REQUIRES_DIGIT",
+			setupMocks:         func(mockAuthDataSvc *MockAuthDataSvc) {},
+		},
+		{
+			name:               "password satisfies policy",
+			password:           "Infinity5tones",
+			expectedStatusCode: http.StatusOK,
+			setupMocks: func(mockAuthDataSvc *MockAuthDataSvc) {
+				mockAuthDataSvc.On("UserUpdatePassword", mock.Anything, "323e4567-e89b-12d3-a456-426614174000", "Infinity5tones").
+					Return("Thor", "thor@thunderdome.dev", nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAuthDataSvc := new(MockAuthDataSvc)
+			mockEmail := new(MockEmailService)
+			service := &Service{
+				Config: &Config{PasswordPolicy: thunderdome.PasswordPolicy{
+					MinLength: 6, MaxLength: 72, RequireUppercase: true, RequireDigit: true,
+				}},
+				AuthDataSvc: mockAuthDataSvc,
+				Email:       mockEmail,
+			}
+
+			tt.setupMocks(mockAuthDataSvc)
+			mockEmail.On("SendPasswordUpdate", "Thor", "thor@thunderdome.dev").Return(nil)
+
+			body := `{"password1":"` + tt.password + `","password2":"` + tt.password + `"}`
+			req := httptest.NewRequest("PATCH", "/auth/update-password", strings.NewReader(body))
+			req = req.WithContext(context.WithValue(req.Context(), contextKeyUserID, "323e4567-e89b-12d3-a456-426614174000"))
+			rr := httptest.NewRecorder()
+			service.handleUpdatePassword().ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+
+			var response standardJsonResponse
+			err := json.Unmarshal(rr.Body.Bytes(), &response)
+			assert.NoError(t, err)
+			if tt.expectedError != "" {
+				assert.Equal(t, tt.expectedError, response.Error)
+			}
+
+			mockAuthDataSvc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandleLoginAccountPending(t *testing.T) {
+	mockAuthDataSvc := new(MockAuthDataSvc)
+	service := &Service{
+		Config:      &Config{},
+		AuthDataSvc: mockAuthDataSvc,
+	}
+	mockAuthDataSvc.On("AuthUser", mock.Anything, "pending@thunderdome.dev", "infinitystones").
+		Return(nil, nil, "", thunderdome.ErrAccountPending)
+
+	body := `{"email":"pending@thunderdome.dev","password":"infinitystones"}`
+	req := httptest.NewRequest("POST", "/auth", strings.NewReader(body))
+	rr := httptest.NewRecorder()
+	service.handleLogin().ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	var response standardJsonResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, "ACCOUNT_PENDING", response.Error)
+	mockAuthDataSvc.AssertExpectations(t)
+}
@@ -24,6 +24,16 @@ type Config struct {
 
 	// Websocket Subdomain (for Websocket origin check)
 	WebsocketSubdomain string
+
+	// Time allowed for connections to be closed cleanly on shutdown
+	ShutdownGracePeriodSec int
+	// Websocket read buffer size in bytes
+	ReadBufferSize int
+	// Websocket write buffer size in bytes
+	WriteBufferSize int
+
+	// Store for messages to replay to clients reconnecting after a shutdown
+	ReplayStore wshub.ReplayStore
 }
 
 type CheckinDataSvc interface {
@@ -35,6 +45,8 @@ type CheckinDataSvc interface {
 	CheckinCommentEdit(ctx context.Context, teamID string, userID string, commentID string, comment string) error
 	CheckinCommentDelete(ctx context.Context, commentID string) error
 	CheckinLastByUser(ctx context.Context, teamID string, userID string) (*thunderdome.TeamCheckin, error)
+	CreateStandup(ctx context.Context, teamID string, userID string, yesterday string, today string, blockers string) (*thunderdome.Standup, error)
+	DeleteStandup(ctx context.Context, teamID string, standupID string) error
 }
 
 type AuthDataSvc interface {
@@ -84,11 +96,15 @@ func New(
 	}
 
 	c.hub = wshub.NewHub(logger, wshub.Config{
-		AppDomain:          config.AppDomain,
-		WebsocketSubdomain: config.WebsocketSubdomain,
-		WriteWaitSec:       config.WriteWaitSec,
-		PongWaitSec:        config.PongWaitSec,
-		PingPeriodSec:      config.PingPeriodSec,
+		AppDomain:              config.AppDomain,
+		WebsocketSubdomain:     config.WebsocketSubdomain,
+		ShutdownGracePeriodSec: config.ShutdownGracePeriodSec,
+		ReadBufferSize:         config.ReadBufferSize,
+		WriteBufferSize:        config.WriteBufferSize,
+		ReplayStore:            config.ReplayStore,
+		WriteWaitSec:           config.WriteWaitSec,
+		PongWaitSec:            config.PongWaitSec,
+		PingPeriodSec:          config.PingPeriodSec,
 	}, map[string]func(context.Context, string, string, string) ([]byte, error, bool){
 		"checkin_create": c.CheckinCreate,
 		"checkin_update": c.CheckinUpdate,
@@ -96,6 +112,8 @@ func New(
 		"comment_create": c.CommentCreate,
 		"comment_update": c.CommentUpdate,
 		"comment_delete": c.CommentDelete,
+		"standup_create": c.StandupCreate,
+		"standup_delete": c.StandupDelete,
 	},
 		map[string]struct{}{},
 		nil,
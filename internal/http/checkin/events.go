@@ -140,3 +140,47 @@ func (b *Service) CommentDelete(ctx context.Context, teamID string, userID strin
 
 	return msg, nil, false
 }
+
+// StandupCreate logs a team users daily standup
+func (b *Service) StandupCreate(ctx context.Context, teamID string, userID string, eventValue string) ([]byte, error, bool) {
+	var c struct {
+		UserID    string `json:"userId"`
+		Yesterday string `json:"yesterday"`
+		Today     string `json:"today"`
+		Blockers  string `json:"blockers"`
+	}
+	err := json.Unmarshal([]byte(eventValue), &c)
+	if err != nil {
+		return nil, err, false
+	}
+
+	standup, err := b.CheckinService.CreateStandup(ctx, teamID, c.UserID, c.Yesterday, c.Today, c.Blockers)
+	if err != nil {
+		return nil, err, false
+	}
+
+	standupJSON, _ := json.Marshal(standup)
+	msg := wshub.CreateSocketEvent("standup_created", string(standupJSON), "")
+
+	return msg, nil, false
+}
+
+// StandupDelete deletes a team standup
+func (b *Service) StandupDelete(ctx context.Context, teamID string, userID string, eventValue string) ([]byte, error, bool) {
+	var c struct {
+		StandupID string `json:"standupId"`
+	}
+	err := json.Unmarshal([]byte(eventValue), &c)
+	if err != nil {
+		return nil, err, false
+	}
+
+	err = b.CheckinService.DeleteStandup(ctx, teamID, c.StandupID)
+	if err != nil {
+		return nil, err, false
+	}
+
+	msg := wshub.CreateSocketEvent("standup_deleted", c.StandupID, "")
+
+	return msg, nil, false
+}
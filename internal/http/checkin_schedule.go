@@ -0,0 +1,155 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/reminder"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+type checkinScheduleRequestBody struct {
+	CronExpression  string `json:"cronExpression" validate:"required,max=128"`
+	TimezoneOffset  int    `json:"timezoneOffset" validate:"min=-720,max=840"`
+	ReminderEnabled bool   `json:"reminderEnabled"`
+}
+
+// handleCheckinScheduleGet gets the team checkin reminder schedule
+//
+//	@Summary		Get Team Checkin Schedule
+//	@Description	Gets the team checkin reminder schedule
+//	@Tags			team
+//	@Produce		json
+//	@Param			teamId	path	string	true	"the team ID"
+//	@Success		200		object	standardJsonResponse{data=thunderdome.CheckinSchedule}
+//	@Failure		400		object	standardJsonResponse{}
+//	@Failure		404		object	standardJsonResponse{}
+//	@Failure		500		object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/teams/{teamId}/checkin-schedule [get]
+func (s *Service) handleCheckinScheduleGet() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		vars := mux.Vars(r)
+		teamID := vars["teamId"]
+		idErr := validate.Var(teamID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		schedule, err := s.CheckinDataSvc.CheckinScheduleGet(ctx, teamID)
+		if err != nil {
+			if err.Error() == "CHECKIN_SCHEDULE_NOT_FOUND" {
+				s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, err.Error()))
+				return
+			}
+			s.Logger.Ctx(ctx).Error("handleCheckinScheduleGet error", zap.Error(err), zap.String("team_id", teamID),
+				zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, schedule, nil)
+	}
+}
+
+// handleCheckinScheduleUpdate handles creating or updating the team checkin reminder schedule
+//
+//	@Summary		Update Team Checkin Schedule
+//	@Description	Creates or updates the team checkin reminder schedule, the cron expression uses the standard 5 field format
+//	@Tags			team
+//	@Produce		json
+//	@Param			teamId		path	string						true	"the team ID"
+//	@Param			schedule	body	checkinScheduleRequestBody	true	"checkin schedule object"
+//	@Success		200			object	standardJsonResponse{data=thunderdome.CheckinSchedule}
+//	@Failure		400			object	standardJsonResponse{}
+//	@Failure		500			object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/teams/{teamId}/checkin-schedule [put]
+func (s *Service) handleCheckinScheduleUpdate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		vars := mux.Vars(r)
+		teamID := vars["teamId"]
+		idErr := validate.Var(teamID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		var c = checkinScheduleRequestBody{}
+		body, bodyErr := io.ReadAll(r.Body)
+		if bodyErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, bodyErr.Error()))
+			return
+		}
+
+		jsonErr := json.Unmarshal(body, &c)
+		if jsonErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, jsonErr.Error()))
+			return
+		}
+
+		inputErr := validate.Struct(c)
+		if inputErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, inputErr.Error()))
+			return
+		}
+
+		if err := reminder.ValidateCronExpression(c.CronExpression); err != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "INVALID_CRON_EXPRESSION"))
+			return
+		}
+
+		schedule, err := s.CheckinDataSvc.CheckinScheduleUpsert(ctx, teamID, c.CronExpression, c.TimezoneOffset, c.ReminderEnabled)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleCheckinScheduleUpdate error", zap.Error(err), zap.String("team_id", teamID),
+				zap.String("session_user_id", sessionUserID), zap.String("cron_expression", c.CronExpression))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, schedule, nil)
+	}
+}
+
+// handleCheckinScheduleDelete handles deleting the team checkin reminder schedule
+//
+//	@Summary		Delete Team Checkin Schedule
+//	@Description	Deletes the team checkin reminder schedule
+//	@Tags			team
+//	@Produce		json
+//	@Param			teamId	path	string	true	"the team ID"
+//	@Success		200		object	standardJsonResponse{}
+//	@Failure		400		object	standardJsonResponse{}
+//	@Failure		500		object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/teams/{teamId}/checkin-schedule [delete]
+func (s *Service) handleCheckinScheduleDelete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		vars := mux.Vars(r)
+		teamID := vars["teamId"]
+		idErr := validate.Var(teamID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		err := s.CheckinDataSvc.CheckinScheduleDelete(ctx, teamID)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleCheckinScheduleDelete error", zap.Error(err), zap.String("team_id", teamID),
+				zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, nil, nil)
+	}
+}
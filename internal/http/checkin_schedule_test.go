@@ -0,0 +1,72 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+// MockCheckinDataSvc is a mock implementation of the CheckinDataSvc, methods not overridden panic when called
+type MockCheckinDataSvc struct {
+	mock.Mock
+	CheckinDataSvc
+}
+
+func (m *MockCheckinDataSvc) CheckinScheduleUpsert(
+	ctx context.Context, teamID string, cronExpression string, timezoneOffset int, reminderEnabled bool,
+) (*thunderdome.CheckinSchedule, error) {
+	args := m.Called(ctx, teamID, cronExpression, timezoneOffset, reminderEnabled)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*thunderdome.CheckinSchedule), args.Error(1)
+}
+
+const testTeamID = "923e4567-e89b-12d3-a456-426614174000"
+
+func TestHandleCheckinScheduleUpdate(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectCall     bool
+	}{
+		{name: "valid schedule", body: `{"cronExpression":"0 9 * * 1-5","timezoneOffset":-300,"reminderEnabled":true}`, expectedStatus: http.StatusOK, expectCall: true},
+		{name: "invalid cron expression", body: `{"cronExpression":"every morning","timezoneOffset":-300,"reminderEnabled":true}`, expectedStatus: http.StatusBadRequest},
+		{name: "cron expression out of range", body: `{"cronExpression":"0 25 * * *","timezoneOffset":-300,"reminderEnabled":true}`, expectedStatus: http.StatusBadRequest},
+		{name: "missing cron expression", body: `{"timezoneOffset":-300,"reminderEnabled":true}`, expectedStatus: http.StatusBadRequest},
+		{name: "invalid timezone offset", body: `{"cronExpression":"0 9 * * 1-5","timezoneOffset":1000,"reminderEnabled":true}`, expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCheckinDataSvc := new(MockCheckinDataSvc)
+			if tt.expectCall {
+				mockCheckinDataSvc.On("CheckinScheduleUpsert", mock.Anything, testTeamID, "0 9 * * 1-5", -300, true).
+					Return(&thunderdome.CheckinSchedule{TeamID: testTeamID, CronExpression: "0 9 * * 1-5", TimezoneOffset: -300, ReminderEnabled: true}, nil)
+			}
+			service := &Service{
+				CheckinDataSvc: mockCheckinDataSvc,
+				Logger:         otelzap.New(zap.NewNop()),
+			}
+
+			req := httptest.NewRequest("PUT", "/teams/"+testTeamID+"/checkin-schedule", strings.NewReader(tt.body))
+			req = mux.SetURLVars(req, map[string]string{"teamId": testTeamID})
+			req = req.WithContext(context.WithValue(req.Context(), contextKeyUserID, testFacilitatorID))
+			rr := httptest.NewRecorder()
+			service.handleCheckinScheduleUpdate().ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			mockCheckinDataSvc.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,68 @@
+// Package clientip resolves the ip address a request came from, only trusting the X-Forwarded-For
+// header when the request came through a trusted reverse proxy
+package clientip
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ParseCIDRs parses the CIDR ranges skipping empty values, an invalid range is returned as an error
+// naming the setting it came from
+func ParseCIDRs(name string, cidrs []string) ([]*net.IPNet, error) {
+	ipNets := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s cidr %q: %v", name, cidr, err)
+		}
+		ipNets = append(ipNets, ipNet)
+	}
+
+	return ipNets, nil
+}
+
+// InNets whether the ip is in any of the ranges
+func InNets(ip net.IP, ipNets []*net.IPNet) bool {
+	for _, ipNet := range ipNets {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// FromRequest gets the ip address the request came from. When the remote address is a trusted proxy the
+// X-Forwarded-For addresses are walked from the right skipping trusted proxies, the first untrusted address
+// is the one the nearest trusted proxy saw so addresses a client added to the left of it are ignored
+func FromRequest(r *http.Request, trustedProxies []*net.IPNet) net.IP {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !InNets(ip, trustedProxies) {
+		return ip
+	}
+
+	forwarded := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		forwardedIP := net.ParseIP(strings.TrimSpace(forwarded[i]))
+		if forwardedIP == nil {
+			break
+		}
+		ip = forwardedIP
+		if !InNets(ip, trustedProxies) {
+			break
+		}
+	}
+
+	return ip
+}
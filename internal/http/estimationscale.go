@@ -5,6 +5,7 @@ import (
 	"io"
 	"net/http"
 
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/http/response"
 	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
 
 	"go.uber.org/zap"
@@ -16,11 +17,22 @@ type estimationScaleRequestBody struct {
 	Name         string   `json:"name" validate:"required"`
 	Description  string   `json:"description"`
 	ScaleType    string   `json:"scaleType" validate:"required,oneof=modified_fibonacci fibonacci t_shirt powers_of_two custom"`
-	Values       []string `json:"values" validate:"required,min=2"`
+	Values       []string `json:"values" validate:"required"`
 	IsPublic     bool     `json:"isPublic"`
 	DefaultScale bool     `json:"defaultScale"`
 }
 
+// estimationScaleInvalid responds with the scale's validation errors, returning whether the scale was invalid
+func (s *Service) estimationScaleInvalid(w http.ResponseWriter, r *http.Request, scale *thunderdome.EstimationScale) bool {
+	validationErrs := thunderdome.ValidateEstimationScale(scale)
+	if len(validationErrs) == 0 {
+		return false
+	}
+
+	response.RespondErrorData(w, http.StatusUnprocessableEntity, "INVALID_ESTIMATION_SCALE", validationErrs)
+	return true
+}
+
 // handleGetEstimationScales gets a list of estimation scales
 //
 //	@Summary		Get Estimation Scales
@@ -47,11 +59,7 @@ func (s *Service) handleGetEstimationScales() http.HandlerFunc {
 			return
 		}
 
-		meta := &pagination{
-			Count:  count,
-			Offset: offset,
-			Limit:  limit,
-		}
+		meta := response.NewMeta(count, limit, offset)
 
 		s.Success(w, r, http.StatusOK, scales, meta)
 	}
@@ -65,6 +73,7 @@ func (s *Service) handleGetEstimationScales() http.HandlerFunc {
 //	@Produce		json
 //	@Param			scale	body	estimationScaleRequestBody								true	"new estimation scale object"
 //	@Success		200		object	standardJsonResponse{data=thunderdome.EstimationScale}	"returns created estimation scale"
+//	@Failure		422		object	standardJsonResponse{data=[]thunderdome.ValidationError}
 //	@Failure		500		object	standardJsonResponse{}
 //	@Security		ApiKeyAuth
 //	@Router			/admin/estimation-scales [post]
@@ -101,6 +110,10 @@ func (s *Service) handleEstimationScaleCreate() http.HandlerFunc {
 			CreatedBy:    sessionUserID,
 		}
 
+		if s.estimationScaleInvalid(w, r, &es) {
+			return
+		}
+
 		createdScale, err := s.PokerDataSvc.CreateEstimationScale(ctx, &es)
 		if err != nil {
 			s.Logger.Ctx(ctx).Error("handleEstimationScaleCreate error", zap.Error(err),
@@ -123,6 +136,7 @@ func (s *Service) handleEstimationScaleCreate() http.HandlerFunc {
 //	@Param			scaleId	path	string													true	"the estimation scale ID to update"
 //	@Param			scale	body	estimationScaleRequestBody								true	"estimation scale object to update"
 //	@Success		200		object	standardJsonResponse{data=thunderdome.EstimationScale}	"returns updated estimation scale"
+//	@Failure		422		object	standardJsonResponse{data=[]thunderdome.ValidationError}
 //	@Failure		500		object	standardJsonResponse{}
 //	@Security		ApiKeyAuth
 //	@Router			/admin/estimation-scales/{scaleId} [put]
@@ -167,6 +181,10 @@ func (s *Service) handleEstimationScaleUpdate() http.HandlerFunc {
 			IsPublic:     scale.IsPublic,
 		}
 
+		if s.estimationScaleInvalid(w, r, &es) {
+			return
+		}
+
 		updatedScale, err := s.PokerDataSvc.UpdateEstimationScale(ctx, &es)
 		if err != nil {
 			s.Logger.Ctx(ctx).Error("handleEstimationScaleUpdate error", zap.Error(err), zap.String("scale_id", scaleID),
@@ -249,11 +267,7 @@ func (s *Service) handleGetOrganizationEstimationScales() http.HandlerFunc {
 			return
 		}
 
-		meta := &pagination{
-			Count:  count,
-			Offset: offset,
-			Limit:  limit,
-		}
+		meta := response.NewMeta(count, limit, offset)
 
 		s.Success(w, r, http.StatusOK, scales, meta)
 	}
@@ -262,7 +276,7 @@ func (s *Service) handleGetOrganizationEstimationScales() http.HandlerFunc {
 type privateEstimationScaleRequestBody struct {
 	Name         string   `json:"name" validate:"required"`
 	Description  string   `json:"description"`
-	Values       []string `json:"values" validate:"required,min=2"`
+	Values       []string `json:"values" validate:"required"`
 	DefaultScale bool     `json:"defaultScale"`
 }
 
@@ -275,6 +289,7 @@ type privateEstimationScaleRequestBody struct {
 //	@Param			orgId	path	string													true	"Organization ID"
 //	@Param			scale	body	privateEstimationScaleRequestBody						true	"new estimation scale object"
 //	@Success		200		object	standardJsonResponse{data=thunderdome.EstimationScale}	"returns created estimation scale"
+//	@Failure		422		object	standardJsonResponse{data=[]thunderdome.ValidationError}
 //	@Failure		500		object	standardJsonResponse{}
 //	@Security		ApiKeyAuth
 //	@Router			/organizations/{orgId}/estimation-scales [post]
@@ -320,6 +335,10 @@ func (s *Service) handleOrganizationEstimationScaleCreate() http.HandlerFunc {
 			IsPublic:       false,
 		}
 
+		if s.estimationScaleInvalid(w, r, &es) {
+			return
+		}
+
 		createdScale, err := s.PokerDataSvc.CreateEstimationScale(ctx, &es)
 		if err != nil {
 			s.Logger.Ctx(ctx).Error("handleOrganizationEstimationScaleCreate error", zap.Error(err),
@@ -368,11 +387,7 @@ func (s *Service) handleGetTeamEstimationScales() http.HandlerFunc {
 			return
 		}
 
-		meta := &pagination{
-			Count:  count,
-			Offset: offset,
-			Limit:  limit,
-		}
+		meta := response.NewMeta(count, limit, offset)
 
 		s.Success(w, r, http.StatusOK, scales, meta)
 	}
@@ -387,6 +402,7 @@ func (s *Service) handleGetTeamEstimationScales() http.HandlerFunc {
 //	@Param			teamId	path	string													true	"Team ID"
 //	@Param			scale	body	privateEstimationScaleRequestBody						true	"new estimation scale object"
 //	@Success		200		object	standardJsonResponse{data=thunderdome.EstimationScale}	"returns created estimation scale"
+//	@Failure		422		object	standardJsonResponse{data=[]thunderdome.ValidationError}
 //	@Failure		500		object	standardJsonResponse{}
 //	@Security		ApiKeyAuth
 //	@Router			/teams/{teamId}/estimation-scales [post]
@@ -432,6 +448,10 @@ func (s *Service) handleTeamEstimationScaleCreate() http.HandlerFunc {
 			IsPublic:     false,
 		}
 
+		if s.estimationScaleInvalid(w, r, &es) {
+			return
+		}
+
 		createdScale, err := s.PokerDataSvc.CreateEstimationScale(ctx, &es)
 		if err != nil {
 			s.Logger.Ctx(ctx).Error("handleTeamEstimationScaleCreate error", zap.Error(err),
@@ -469,11 +489,7 @@ func (s *Service) handleGetPublicEstimationScales() http.HandlerFunc {
 			return
 		}
 
-		meta := &pagination{
-			Count:  count,
-			Offset: offset,
-			Limit:  limit,
-		}
+		meta := response.NewMeta(count, limit, offset)
 
 		s.Success(w, r, http.StatusOK, scales, meta)
 	}
@@ -605,6 +621,7 @@ func (s *Service) handleTeamEstimationScaleDelete() http.HandlerFunc {
 //	@Param			scaleId	path	string													true	"the estimation scale ID to update"
 //	@Param			scale	body	privateEstimationScaleRequestBody						true	"estimation scale object to update"
 //	@Success		200		object	standardJsonResponse{data=thunderdome.EstimationScale}	"returns updated estimation scale"
+//	@Failure		422		object	standardJsonResponse{data=[]thunderdome.ValidationError}
 //	@Failure		500		object	standardJsonResponse{}
 //	@Security		ApiKeyAuth
 //	@Router			/teams/{teamId}/estimation-scales/{scaleId} [put]
@@ -656,6 +673,10 @@ func (s *Service) handleTeamEstimationScaleUpdate() http.HandlerFunc {
 			IsPublic:     false,
 		}
 
+		if s.estimationScaleInvalid(w, r, &es) {
+			return
+		}
+
 		updatedScale, err := s.PokerDataSvc.UpdateTeamEstimationScale(ctx, &es)
 		if err != nil {
 			s.Logger.Ctx(ctx).Error("handleEstimationScaleUpdate error", zap.Error(err),
@@ -679,6 +700,7 @@ func (s *Service) handleTeamEstimationScaleUpdate() http.HandlerFunc {
 //	@Param			scaleId	path	string													true	"the estimation scale ID to update"
 //	@Param			scale	body	privateEstimationScaleRequestBody						true	"estimation scale object to update"
 //	@Success		200		object	standardJsonResponse{data=thunderdome.EstimationScale}	"returns updated estimation scale"
+//	@Failure		422		object	standardJsonResponse{data=[]thunderdome.ValidationError}
 //	@Failure		500		object	standardJsonResponse{}
 //	@Security		ApiKeyAuth
 //	@Router			/teams/{orgId}/estimation-scales/{scaleId} [put]
@@ -730,6 +752,10 @@ func (s *Service) handleOrganizationEstimationScaleUpdate() http.HandlerFunc {
 			IsPublic:       false,
 		}
 
+		if s.estimationScaleInvalid(w, r, &es) {
+			return
+		}
+
 		updatedScale, err := s.PokerDataSvc.UpdateOrganizationEstimationScale(ctx, &es)
 		if err != nil {
 			s.Logger.Ctx(ctx).Error("handleEstimationScaleUpdate error", zap.Error(err),
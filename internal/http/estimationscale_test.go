@@ -0,0 +1,66 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+func (m *MockPokerDataSvc) CreateEstimationScale(ctx context.Context, scale *thunderdome.EstimationScale) (*thunderdome.EstimationScale, error) {
+	args := m.Called(ctx, scale)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*thunderdome.EstimationScale), args.Error(1)
+}
+
+func TestHandleEstimationScaleCreateValidation(t *testing.T) {
+	tests := []struct {
+		name         string
+		values       string
+		expectedCode int
+		expectedErr  string
+	}{
+		{name: "valid scale", values: `["1","2","3","?"]`, expectedCode: http.StatusOK},
+		{name: "duplicate values", values: `["1","2","2"]`, expectedCode: http.StatusUnprocessableEntity, expectedErr: thunderdome.EstimationScaleDuplicate},
+		{name: "non monotonic", values: `["3","2","1"]`, expectedCode: http.StatusUnprocessableEntity, expectedErr: thunderdome.EstimationScaleNotMonotonic},
+		{name: "single value", values: `["1"]`, expectedCode: http.StatusUnprocessableEntity, expectedErr: thunderdome.EstimationScaleTooFewValues},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPokerDataSvc := new(MockPokerDataSvc)
+			mockPokerDataSvc.On("CreateEstimationScale", mock.Anything, mock.Anything).Return(&thunderdome.EstimationScale{ID: "scale"}, nil).Maybe()
+			service := &Service{PokerDataSvc: mockPokerDataSvc, Logger: otelzap.New(zap.NewNop())}
+
+			body := `{"name":"Points","scaleType":"custom","values":` + tt.values + `}`
+			req := httptest.NewRequest("POST", "/admin/estimation-scales", strings.NewReader(body))
+			req = req.WithContext(context.WithValue(req.Context(), contextKeyUserID, testFacilitatorID))
+			rr := httptest.NewRecorder()
+			service.handleEstimationScaleCreate().ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+			if tt.expectedErr != "" {
+				var response struct {
+					Error string                        `json:"error"`
+					Data  []thunderdome.ValidationError `json:"data"`
+				}
+				assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+				assert.Equal(t, "INVALID_ESTIMATION_SCALE", response.Error)
+				if assert.Len(t, response.Data, 1) {
+					assert.Equal(t, tt.expectedErr, response.Data[0].Code)
+				}
+				mockPokerDataSvc.AssertNotCalled(t, "CreateEstimationScale", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
@@ -0,0 +1,291 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/reminder"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+type gameTemplateRequestBody struct {
+	Name                 string   `json:"name" validate:"required,max=256"`
+	EstimationScaleID    string   `json:"estimationScaleId" validate:"omitempty,uuid"`
+	PointValuesAllowed   []string `json:"pointValuesAllowed" validate:"required"`
+	AutoFinishVoting     bool     `json:"autoFinishVoting"`
+	PointAverageRounding string   `json:"pointAverageRounding" validate:"required,oneof=ceil round floor"`
+	HideVoterIdentity    bool     `json:"hideVoterIdentity"`
+	RecurrenceCron       string   `json:"recurrenceCron" validate:"max=128"`
+}
+
+// readGameTemplateBody reads and validates the game template request body, an empty recurrence cron
+// disables the template's recurrence
+func (s *Service) readGameTemplateBody(w http.ResponseWriter, r *http.Request) (*thunderdome.GameTemplate, bool) {
+	var t = gameTemplateRequestBody{}
+	body, bodyErr := io.ReadAll(r.Body)
+	if bodyErr != nil {
+		s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, bodyErr.Error()))
+		return nil, false
+	}
+
+	jsonErr := json.Unmarshal(body, &t)
+	if jsonErr != nil {
+		s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, jsonErr.Error()))
+		return nil, false
+	}
+
+	inputErr := validate.Struct(t)
+	if inputErr != nil {
+		s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, inputErr.Error()))
+		return nil, false
+	}
+
+	if t.RecurrenceCron != "" {
+		if err := reminder.ValidateCronExpression(t.RecurrenceCron); err != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "INVALID_CRON_EXPRESSION"))
+			return nil, false
+		}
+	}
+
+	return &thunderdome.GameTemplate{
+		Name:                 t.Name,
+		EstimationScaleID:    t.EstimationScaleID,
+		PointValuesAllowed:   t.PointValuesAllowed,
+		AutoFinishVoting:     t.AutoFinishVoting,
+		PointAverageRounding: t.PointAverageRounding,
+		HideVoterIdentity:    t.HideVoterIdentity,
+		RecurrenceCron:       t.RecurrenceCron,
+	}, true
+}
+
+// handleGetGameTemplates gets the team's poker game templates
+//
+//	@Summary		Get Team Game Templates
+//	@Description	Gets the team's poker game templates
+//	@Tags			team
+//	@Produce		json
+//	@Param			teamId	path	string	true	"the team ID"
+//	@Success		200		object	standardJsonResponse{data=[]thunderdome.GameTemplate}
+//	@Failure		400		object	standardJsonResponse{}
+//	@Failure		500		object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/teams/{teamId}/game-templates [get]
+func (s *Service) handleGetGameTemplates() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		vars := mux.Vars(r)
+		teamID := vars["teamId"]
+		idErr := validate.Var(teamID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		templates, err := s.PokerDataSvc.GetGameTemplates(ctx, teamID)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleGetGameTemplates error", zap.Error(err), zap.String("team_id", teamID),
+				zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, templates, nil)
+	}
+}
+
+// handleGetGameTemplate gets a team poker game template
+//
+//	@Summary		Get Team Game Template
+//	@Description	Gets a team poker game template
+//	@Tags			team
+//	@Produce		json
+//	@Param			teamId		path	string	true	"the team ID"
+//	@Param			templateId	path	string	true	"the game template ID"
+//	@Success		200			object	standardJsonResponse{data=thunderdome.GameTemplate}
+//	@Failure		400			object	standardJsonResponse{}
+//	@Failure		404			object	standardJsonResponse{}
+//	@Failure		500			object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/teams/{teamId}/game-templates/{templateId} [get]
+func (s *Service) handleGetGameTemplate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		vars := mux.Vars(r)
+		teamID := vars["teamId"]
+		idErr := validate.Var(teamID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+		templateID := vars["templateId"]
+		idErr = validate.Var(templateID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		template, err := s.PokerDataSvc.GetGameTemplateByID(ctx, teamID, templateID)
+		if err != nil {
+			if err.Error() == "GAME_TEMPLATE_NOT_FOUND" {
+				s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, err.Error()))
+				return
+			}
+			s.Logger.Ctx(ctx).Error("handleGetGameTemplate error", zap.Error(err), zap.String("team_id", teamID),
+				zap.String("template_id", templateID), zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, template, nil)
+	}
+}
+
+// handleGameTemplateCreate handles creating a team poker game template
+//
+//	@Summary		Create Team Game Template
+//	@Description	Creates a team poker game template, when the recurrence cron (standard 5 field format, evaluated in UTC)
+//	@Description	is set a new team game is created from the template each time it comes due
+//	@Tags			team
+//	@Produce		json
+//	@Param			teamId		path	string					true	"the team ID"
+//	@Param			template	body	gameTemplateRequestBody	true	"game template object"
+//	@Success		200			object	standardJsonResponse{data=thunderdome.GameTemplate}
+//	@Failure		400			object	standardJsonResponse{}
+//	@Failure		500			object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/teams/{teamId}/game-templates [post]
+func (s *Service) handleGameTemplateCreate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		vars := mux.Vars(r)
+		teamID := vars["teamId"]
+		idErr := validate.Var(teamID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		t, ok := s.readGameTemplateBody(w, r)
+		if !ok {
+			return
+		}
+
+		template, err := s.PokerDataSvc.CreateGameTemplate(ctx, teamID, sessionUserID, t)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleGameTemplateCreate error", zap.Error(err), zap.String("team_id", teamID),
+				zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, template, nil)
+	}
+}
+
+// handleGameTemplateUpdate handles updating a team poker game template
+//
+//	@Summary		Update Team Game Template
+//	@Description	Updates a team poker game template, an empty recurrence cron stops creating games from it
+//	@Tags			team
+//	@Produce		json
+//	@Param			teamId		path	string					true	"the team ID"
+//	@Param			templateId	path	string					true	"the game template ID"
+//	@Param			template	body	gameTemplateRequestBody	true	"game template object"
+//	@Success		200			object	standardJsonResponse{data=thunderdome.GameTemplate}
+//	@Failure		400			object	standardJsonResponse{}
+//	@Failure		404			object	standardJsonResponse{}
+//	@Failure		500			object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/teams/{teamId}/game-templates/{templateId} [put]
+func (s *Service) handleGameTemplateUpdate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		vars := mux.Vars(r)
+		teamID := vars["teamId"]
+		idErr := validate.Var(teamID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+		templateID := vars["templateId"]
+		idErr = validate.Var(templateID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		t, ok := s.readGameTemplateBody(w, r)
+		if !ok {
+			return
+		}
+
+		template, err := s.PokerDataSvc.UpdateGameTemplate(ctx, teamID, templateID, t)
+		if err != nil {
+			if err.Error() == "GAME_TEMPLATE_NOT_FOUND" {
+				s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, err.Error()))
+				return
+			}
+			s.Logger.Ctx(ctx).Error("handleGameTemplateUpdate error", zap.Error(err), zap.String("team_id", teamID),
+				zap.String("template_id", templateID), zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, template, nil)
+	}
+}
+
+// handleGameTemplateDelete handles deleting a team poker game template
+//
+//	@Summary		Delete Team Game Template
+//	@Description	Deletes a team poker game template, games already created from it are kept
+//	@Tags			team
+//	@Produce		json
+//	@Param			teamId		path	string	true	"the team ID"
+//	@Param			templateId	path	string	true	"the game template ID"
+//	@Success		200			object	standardJsonResponse{}
+//	@Failure		400			object	standardJsonResponse{}
+//	@Failure		404			object	standardJsonResponse{}
+//	@Failure		500			object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/teams/{teamId}/game-templates/{templateId} [delete]
+func (s *Service) handleGameTemplateDelete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		vars := mux.Vars(r)
+		teamID := vars["teamId"]
+		idErr := validate.Var(teamID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+		templateID := vars["templateId"]
+		idErr = validate.Var(templateID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		err := s.PokerDataSvc.DeleteGameTemplate(ctx, teamID, templateID)
+		if err != nil {
+			if err.Error() == "GAME_TEMPLATE_NOT_FOUND" {
+				s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, err.Error()))
+				return
+			}
+			s.Logger.Ctx(ctx).Error("handleGameTemplateDelete error", zap.Error(err), zap.String("team_id", teamID),
+				zap.String("template_id", templateID), zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, nil, nil)
+	}
+}
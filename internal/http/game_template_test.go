@@ -0,0 +1,63 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+func (m *MockPokerDataSvc) CreateGameTemplate(ctx context.Context, teamID string, userID string, template *thunderdome.GameTemplate) (*thunderdome.GameTemplate, error) {
+	args := m.Called(ctx, teamID, userID, template)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*thunderdome.GameTemplate), args.Error(1)
+}
+
+func TestHandleGameTemplateCreate(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectCall     bool
+	}{
+		{name: "recurring template", body: `{"name":"Sprint Planning","pointValuesAllowed":["1","2","3"],"pointAverageRounding":"ceil","recurrenceCron":"0 9 * * 1"}`, expectedStatus: http.StatusOK, expectCall: true},
+		{name: "template without recurrence", body: `{"name":"Sprint Planning","pointValuesAllowed":["1","2","3"],"pointAverageRounding":"ceil"}`, expectedStatus: http.StatusOK, expectCall: true},
+		{name: "invalid cron expression", body: `{"name":"Sprint Planning","pointValuesAllowed":["1","2","3"],"pointAverageRounding":"ceil","recurrenceCron":"every monday"}`, expectedStatus: http.StatusBadRequest},
+		{name: "missing name", body: `{"pointValuesAllowed":["1","2","3"],"pointAverageRounding":"ceil"}`, expectedStatus: http.StatusBadRequest},
+		{name: "invalid rounding", body: `{"name":"Sprint Planning","pointValuesAllowed":["1","2","3"],"pointAverageRounding":"up"}`, expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPokerDataSvc := new(MockPokerDataSvc)
+			if tt.expectCall {
+				mockPokerDataSvc.On("CreateGameTemplate", mock.Anything, testTeamID, testFacilitatorID, mock.MatchedBy(func(template *thunderdome.GameTemplate) bool {
+					return template.Name == "Sprint Planning" && len(template.PointValuesAllowed) == 3
+				})).Return(&thunderdome.GameTemplate{ID: "template", TeamID: testTeamID, Name: "Sprint Planning"}, nil)
+			}
+			service := &Service{
+				PokerDataSvc: mockPokerDataSvc,
+				Logger:       otelzap.New(zap.NewNop()),
+			}
+
+			req := httptest.NewRequest("POST", "/teams/"+testTeamID+"/game-templates", strings.NewReader(tt.body))
+			req = mux.SetURLVars(req, map[string]string{"teamId": testTeamID})
+			req = req.WithContext(context.WithValue(req.Context(), contextKeyUserID, testFacilitatorID))
+			rr := httptest.NewRecorder()
+			service.handleGameTemplateCreate().ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			mockPokerDataSvc.AssertExpectations(t)
+		})
+	}
+}
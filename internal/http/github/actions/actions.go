@@ -0,0 +1,185 @@
+// Package actions posts poker game results to GitHub pull requests, for use from GitHub Actions workflows
+package actions
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// defaultAPIURL is the GitHub REST API used when no API url is configured
+const defaultAPIURL = "https://api.github.com"
+
+// commentsPerPage is the page size used when searching a pull request's comments for the results comment
+const commentsPerPage = 100
+
+// GitHubActionsHandler posts and updates the game results comment on GitHub pull requests
+type GitHubActionsHandler struct {
+	APIURL     string
+	HTTPClient *http.Client
+}
+
+// NewGitHubActionsHandler creates a GitHubActionsHandler for the GitHub API url,
+// GitHub Enterprise servers can be used by setting it to their API url
+func NewGitHubActionsHandler(apiURL string) *GitHubActionsHandler {
+	if apiURL == "" {
+		apiURL = defaultAPIURL
+	}
+
+	return &GitHubActionsHandler{
+		APIURL: strings.TrimSuffix(apiURL, "/"),
+		HTTPClient: &http.Client{
+			Timeout: time.Second * 10,
+		},
+	}
+}
+
+// PullRequest identifies the pull request to comment on
+type PullRequest struct {
+	// Repo is the repository in owner/name form
+	Repo   string
+	Number int
+}
+
+// issueComment is the subset of a GitHub issue comment the handler uses
+type issueComment struct {
+	ID      int64  `json:"id"`
+	Body    string `json:"body"`
+	HTMLURL string `json:"html_url"`
+}
+
+// CommentMarker is the hidden first line of the results comment, used to find and update
+// the game's existing comment instead of posting a duplicate
+func CommentMarker(gameID string) string {
+	return fmt.Sprintf("<!-- thunderdome:%s -->", gameID)
+}
+
+// escapeTableCell keeps story names from breaking out of their markdown table cell
+func escapeTableCell(value string) string {
+	value = strings.ReplaceAll(value, "|", `\|`)
+	value = strings.ReplaceAll(value, "\r", "")
+
+	return strings.ReplaceAll(value, "\n", " ")
+}
+
+// BuildResultsComment builds the markdown results comment for the game, stories without points are shown as -
+func BuildResultsComment(game *thunderdome.Poker) string {
+	var body strings.Builder
+	body.WriteString(CommentMarker(game.ID))
+	body.WriteString("\n")
+	body.WriteString(fmt.Sprintf("### %s estimates\n\n", escapeTableCell(game.Name)))
+	body.WriteString("| Story | Points |\n")
+	body.WriteString("| --- | --- |\n")
+	for _, story := range game.Stories {
+		points := story.Points
+		if points == "" {
+			points = "-"
+		}
+		body.WriteString(fmt.Sprintf("| %s | %s |\n", escapeTableCell(story.Name), escapeTableCell(points)))
+	}
+
+	return body.String()
+}
+
+// PostResults creates the game results comment on the pull request, or updates it when the game
+// already has a results comment there, returning the comment URL
+func (h *GitHubActionsHandler) PostResults(ctx context.Context, token string, pr PullRequest, game *thunderdome.Poker) (string, error) {
+	body := BuildResultsComment(game)
+
+	existing, err := h.findComment(ctx, token, pr, CommentMarker(game.ID))
+	if err != nil {
+		return "", err
+	}
+
+	var comment issueComment
+	if existing != nil {
+		err = h.do(ctx, token, http.MethodPatch,
+			fmt.Sprintf("/repos/%s/issues/comments/%d", pr.Repo, existing.ID),
+			map[string]string{"body": body}, &comment,
+		)
+	} else {
+		err = h.do(ctx, token, http.MethodPost,
+			fmt.Sprintf("/repos/%s/issues/%d/comments", pr.Repo, pr.Number),
+			map[string]string{"body": body}, &comment,
+		)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return comment.HTMLURL, nil
+}
+
+// findComment pages through the pull request comments looking for the one starting with the marker
+func (h *GitHubActionsHandler) findComment(ctx context.Context, token string, pr PullRequest, marker string) (*issueComment, error) {
+	for page := 1; ; page++ {
+		var comments []issueComment
+		err := h.do(ctx, token, http.MethodGet,
+			fmt.Sprintf("/repos/%s/issues/%d/comments?per_page=%d&page=%d", pr.Repo, pr.Number, commentsPerPage, page),
+			nil, &comments,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range comments {
+			if strings.HasPrefix(comments[i].Body, marker) {
+				return &comments[i], nil
+			}
+		}
+		if len(comments) < commentsPerPage {
+			return nil, nil
+		}
+	}
+}
+
+// do sends a GitHub REST API request, decoding the JSON response into result
+func (h *GitHubActionsHandler) do(ctx context.Context, token string, method string, path string, payload interface{}, result interface{}) error {
+	var reqBody io.Reader
+	if payload != nil {
+		body, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("github request encode error: %v", err)
+		}
+		reqBody = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, h.APIURL+path, reqBody)
+	if err != nil {
+		return fmt.Errorf("github request error: %v", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := h.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("github request error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return fmt.Errorf("GITHUB_UNAUTHORIZED")
+	case resp.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("GITHUB_PULL_REQUEST_NOT_FOUND")
+	case resp.StatusCode >= http.StatusBadRequest:
+		return fmt.Errorf("github %s %s error: status %d", method, path, resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("github response decode error: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package actions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// mockGitHub is a minimal GitHub issue comments API for a single pull request
+type mockGitHub struct {
+	mu       sync.Mutex
+	token    string
+	comments []issueComment
+	nextID   int64
+}
+
+func (m *mockGitHub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if r.Header.Get("Authorization") != "Bearer "+m.token {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var payload struct {
+		Body string `json:"body"`
+	}
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/repos/owner/repo/issues/7/comments":
+		_ = json.NewEncoder(w).Encode(m.comments)
+	case r.Method == http.MethodPost && r.URL.Path == "/repos/owner/repo/issues/7/comments":
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		m.nextID++
+		comment := issueComment{ID: m.nextID, Body: payload.Body, HTMLURL: fmt.Sprintf("https://github.test/c/%d", m.nextID)}
+		m.comments = append(m.comments, comment)
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(comment)
+	case r.Method == http.MethodPatch && strings.HasPrefix(r.URL.Path, "/repos/owner/repo/issues/comments/"):
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		for i := range m.comments {
+			if r.URL.Path == fmt.Sprintf("/repos/owner/repo/issues/comments/%d", m.comments[i].ID) {
+				m.comments[i].Body = payload.Body
+				_ = json.NewEncoder(w).Encode(m.comments[i])
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func testGame(points string) *thunderdome.Poker {
+	return &thunderdome.Poker{
+		ID:   "game-id",
+		Name: "Sprint 12",
+		Stories: []*thunderdome.Story{
+			{Name: "Login | signup", Points: points},
+			{Name: "Unpointed"},
+		},
+	}
+}
+
+// TestPostResultsCreatesThenUpdates makes sure posting results twice updates the first comment instead of duplicating it
+func TestPostResultsCreatesThenUpdates(t *testing.T) {
+	gh := &mockGitHub{token: "secret", comments: []issueComment{{ID: 100, Body: "LGTM"}}, nextID: 100}
+	server := httptest.NewServer(gh)
+	defer server.Close()
+
+	h := &GitHubActionsHandler{APIURL: server.URL, HTTPClient: server.Client()}
+	pr := PullRequest{Repo: "owner/repo", Number: 7}
+
+	url, err := h.PostResults(context.Background(), "secret", pr, testGame("3"))
+	if err != nil {
+		t.Fatalf("expected results to be posted, got %v", err)
+	}
+	if url != "https://github.test/c/101" {
+		t.Errorf("expected the new comment url, got %s", url)
+	}
+
+	if _, err := h.PostResults(context.Background(), "secret", pr, testGame("5")); err != nil {
+		t.Fatalf("expected results to be updated, got %v", err)
+	}
+
+	if len(gh.comments) != 2 {
+		t.Fatalf("expected the results comment to be updated, got %d comments", len(gh.comments))
+	}
+	if gh.comments[0].Body != "LGTM" {
+		t.Errorf("expected other comments to be left alone, got %q", gh.comments[0].Body)
+	}
+	if !strings.Contains(gh.comments[1].Body, `| Login \| signup | 5 |`) {
+		t.Errorf("expected the updated points in the comment, got %q", gh.comments[1].Body)
+	}
+}
+
+// TestPostResultsUnauthorized makes sure a rejected token is reported
+func TestPostResultsUnauthorized(t *testing.T) {
+	server := httptest.NewServer(&mockGitHub{token: "secret"})
+	defer server.Close()
+
+	h := &GitHubActionsHandler{APIURL: server.URL, HTTPClient: server.Client()}
+	_, err := h.PostResults(context.Background(), "wrong", PullRequest{Repo: "owner/repo", Number: 7}, testGame("3"))
+	if err == nil || err.Error() != "GITHUB_UNAUTHORIZED" {
+		t.Fatalf("expected GITHUB_UNAUTHORIZED, got %v", err)
+	}
+}
+
+// TestBuildResultsComment makes sure the comment starts with the game marker and lists every story
+func TestBuildResultsComment(t *testing.T) {
+	body := BuildResultsComment(testGame("8"))
+
+	if !strings.HasPrefix(body, "<!-- thunderdome:game-id -->\n") {
+		t.Errorf("expected the comment to start with the game marker, got %q", body)
+	}
+	for _, row := range []string{`| Login \| signup | 8 |`, "| Unpointed | - |"} {
+		if !strings.Contains(body, row) {
+			t.Errorf("expected row %q in the comment, got %q", row, body)
+		}
+	}
+}
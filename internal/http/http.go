@@ -3,27 +3,38 @@ package http
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io/fs"
 	"net/http"
 	"net/url"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
 	"time"
 
 	"github.com/unrolled/secure"
 	"github.com/unrolled/secure/cspbuilder"
 
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/http/auth/saml"
 	"github.com/StevenWeathers/thunderdome-planning-poker/internal/oauth"
 
 	"github.com/gorilla/mux"
 	"go.opentelemetry.io/contrib/instrumentation/github.com/gorilla/mux/otelmux"
+	"go.uber.org/zap"
 
 	"github.com/StevenWeathers/thunderdome-planning-poker/internal/http/storyboard"
 
 	"github.com/StevenWeathers/thunderdome-planning-poker/docs/swagger"
 	"github.com/StevenWeathers/thunderdome-planning-poker/internal/http/ai"
 	"github.com/StevenWeathers/thunderdome-planning-poker/internal/http/checkin"
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/http/github/actions"
 	"github.com/StevenWeathers/thunderdome-planning-poker/internal/http/poker"
 	"github.com/StevenWeathers/thunderdome-planning-poker/internal/http/retro"
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/redis"
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/storage"
 	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
 	"github.com/go-playground/validator/v10"
 	httpSwagger "github.com/swaggo/http-swagger/v2"
@@ -100,48 +111,75 @@ func New(apiService Service, FSS fs.FS, HFS http.FileSystem) *Service {
 	router.Use(otelmux.Middleware("thunderdome"))
 
 	pokerSvc := poker.New(poker.Config{
-		WriteWaitSec:       a.Config.WebsocketConfig.WriteWaitSec,
-		PongWaitSec:        a.Config.WebsocketConfig.PongWaitSec,
-		PingPeriodSec:      a.Config.WebsocketConfig.PingPeriodSec,
-		AppDomain:          a.Config.AppDomain,
-		WebsocketSubdomain: a.Config.WebsocketConfig.WebsocketSubdomain,
+		WriteWaitSec:               a.Config.WebsocketConfig.WriteWaitSec,
+		PongWaitSec:                a.Config.WebsocketConfig.PongWaitSec,
+		PingPeriodSec:              a.Config.WebsocketConfig.PingPeriodSec,
+		AppDomain:                  a.Config.AppDomain,
+		WebsocketSubdomain:         a.Config.WebsocketConfig.WebsocketSubdomain,
+		ShutdownGracePeriodSec:     a.Config.WebsocketConfig.ShutdownGracePeriodSec,
+		ReadBufferSize:             a.Config.WebsocketConfig.ReadBufferSize,
+		WriteBufferSize:            a.Config.WebsocketConfig.WriteBufferSize,
+		ParticipantSyncIntervalSec: a.Config.WebsocketConfig.ParticipantSyncIntervalSec,
+		ReplayStore:                a.WebsocketReplayStore,
+		TrustedProxies:             a.Config.TrustedProxies,
 	}, a.Logger, a.Cookie.ValidateSessionCookie, a.Cookie.ValidateUserCookie, a.UserDataSvc, a.AuthDataSvc, a.PokerDataSvc)
+	go pokerSvc.RearmTimeBoxes(context.Background())
 	retroSvc := retro.New(retro.Config{
-		WriteWaitSec:       a.Config.WebsocketConfig.WriteWaitSec,
-		PongWaitSec:        a.Config.WebsocketConfig.PongWaitSec,
-		PingPeriodSec:      a.Config.WebsocketConfig.PingPeriodSec,
-		AppDomain:          a.Config.AppDomain,
-		WebsocketSubdomain: a.Config.WebsocketConfig.WebsocketSubdomain,
+		WriteWaitSec:           a.Config.WebsocketConfig.WriteWaitSec,
+		PongWaitSec:            a.Config.WebsocketConfig.PongWaitSec,
+		PingPeriodSec:          a.Config.WebsocketConfig.PingPeriodSec,
+		AppDomain:              a.Config.AppDomain,
+		WebsocketSubdomain:     a.Config.WebsocketConfig.WebsocketSubdomain,
+		ShutdownGracePeriodSec: a.Config.WebsocketConfig.ShutdownGracePeriodSec,
+		ReadBufferSize:         a.Config.WebsocketConfig.ReadBufferSize,
+		WriteBufferSize:        a.Config.WebsocketConfig.WriteBufferSize,
+		ReplayStore:            a.WebsocketReplayStore,
 	}, a.Logger, a.Cookie.ValidateSessionCookie, a.Cookie.ValidateUserCookie, a.UserDataSvc, a.AuthDataSvc,
-		a.RetroDataSvc, a.RetroTemplateDataSvc, a.Email)
+		a.RetroDataSvc, a.RetroTemplateDataSvc, a.Email, a)
 	storyboardSvc := storyboard.New(storyboard.Config{
-		WriteWaitSec:       a.Config.WebsocketConfig.WriteWaitSec,
-		PongWaitSec:        a.Config.WebsocketConfig.PongWaitSec,
-		PingPeriodSec:      a.Config.WebsocketConfig.PingPeriodSec,
-		AppDomain:          a.Config.AppDomain,
-		WebsocketSubdomain: a.Config.WebsocketConfig.WebsocketSubdomain,
+		WriteWaitSec:           a.Config.WebsocketConfig.WriteWaitSec,
+		PongWaitSec:            a.Config.WebsocketConfig.PongWaitSec,
+		PingPeriodSec:          a.Config.WebsocketConfig.PingPeriodSec,
+		AppDomain:              a.Config.AppDomain,
+		WebsocketSubdomain:     a.Config.WebsocketConfig.WebsocketSubdomain,
+		ShutdownGracePeriodSec: a.Config.WebsocketConfig.ShutdownGracePeriodSec,
+		ReadBufferSize:         a.Config.WebsocketConfig.ReadBufferSize,
+		WriteBufferSize:        a.Config.WebsocketConfig.WriteBufferSize,
+		ReplayStore:            a.WebsocketReplayStore,
 	}, a.Logger, a.Cookie.ValidateSessionCookie, a.Cookie.ValidateUserCookie, a.UserDataSvc, a.AuthDataSvc, a.StoryboardDataSvc)
 	checkinSvc := checkin.New(checkin.Config{
-		WriteWaitSec:       a.Config.WebsocketConfig.WriteWaitSec,
-		PongWaitSec:        a.Config.WebsocketConfig.PongWaitSec,
-		PingPeriodSec:      a.Config.WebsocketConfig.PingPeriodSec,
-		AppDomain:          a.Config.AppDomain,
-		WebsocketSubdomain: a.Config.WebsocketConfig.WebsocketSubdomain,
+		WriteWaitSec:           a.Config.WebsocketConfig.WriteWaitSec,
+		PongWaitSec:            a.Config.WebsocketConfig.PongWaitSec,
+		PingPeriodSec:          a.Config.WebsocketConfig.PingPeriodSec,
+		AppDomain:              a.Config.AppDomain,
+		WebsocketSubdomain:     a.Config.WebsocketConfig.WebsocketSubdomain,
+		ShutdownGracePeriodSec: a.Config.WebsocketConfig.ShutdownGracePeriodSec,
+		ReadBufferSize:         a.Config.WebsocketConfig.ReadBufferSize,
+		WriteBufferSize:        a.Config.WebsocketConfig.WriteBufferSize,
+		ReplayStore:            a.WebsocketReplayStore,
 	}, a.Logger, a.Cookie.ValidateSessionCookie, a.Cookie.ValidateUserCookie, a.UserDataSvc, a.AuthDataSvc, a.CheckinDataSvc, a.TeamDataSvc)
+	a.websocketServices = []websocketService{pokerSvc, retroSvc, storyboardSvc, checkinSvc}
 
 	validate = validator.New()
 
 	apiRouter := router.PathPrefix("/api").Subrouter()
+	apiRouter.Use(a.apiVersion)
 	userRouter := apiRouter.PathPrefix("/users").Subrouter()
 	orgRouter := apiRouter.PathPrefix("/organizations").Subrouter()
 	teamRouter := apiRouter.PathPrefix("/teams").Subrouter()
 	adminRouter := apiRouter.PathPrefix("/admin").Subrouter()
+	adminRouter.Use(a.requireIPAllowlist)
 
 	// 初始化AI服务
-	aiSvc := ai.NewAIService()
+	aiSvc := ai.NewAIService(a.UIConfig.AppConfig.DefaultLocale, redis.GetClient())
 
-	// 注册AI API路由
-	apiRouter.HandleFunc("/ai/suggest-points", aiSvc.SuggestPoints).Methods("POST")
+	// 注册AI API路由，启用订阅时AI建议仅对订阅用户开放
+	if a.Config.SubscriptionsEnabled {
+		apiRouter.HandleFunc("/ai/suggest-points", a.userOnly(a.subscribedUserOnly(aiSvc.SuggestPoints))).Methods("POST")
+	} else {
+		apiRouter.HandleFunc("/ai/suggest-points", aiSvc.SuggestPoints).Methods("POST")
+	}
+	orgRouter.HandleFunc("/{orgId}/ai/suggest-points", a.userOnly(a.orgUserOnly(a.requireSubscriptionTier(thunderdome.SubscriptionTierTeam)(aiSvc.SuggestPoints)))).Methods("POST")
 
 	apiRouter.HandleFunc("/", a.handleApiIndex()).Methods("GET")
 
@@ -179,11 +217,14 @@ func New(apiService Service, FSS fs.FS, HFS http.FileSystem) *Service {
 	userRouter.HandleFunc("/{userId}", a.userOnly(a.entityUserOnly(a.handleUserProfile()))).Methods("GET")
 	userRouter.HandleFunc("/{userId}", a.userOnly(a.entityUserOnly(a.handleUserProfileUpdate()))).Methods("PUT")
 	userRouter.HandleFunc("/{userId}", a.userOnly(a.entityUserOnly(a.handleUserDelete()))).Methods("DELETE")
+	userRouter.HandleFunc("/{userId}/invalidate-sessions", a.userOnly(a.entityUserOnly(a.handleInvalidateUserSessions()))).Methods("POST")
 	userRouter.HandleFunc("/{userId}/credential", a.userOnly(a.entityUserOnly(a.handleUserCredential()))).Methods("GET")
 	userRouter.HandleFunc("/{userId}/request-verify", a.userOnly(a.entityUserOnly(a.handleVerifyRequest()))).Methods("POST")
 	userRouter.HandleFunc("/{userId}/invite/team/{inviteId}", a.userOnly(a.registeredUserOnly(a.handleUserTeamInvite()))).Methods("POST")
 	userRouter.HandleFunc("/{userId}/invite/organization/{inviteId}", a.userOnly(a.registeredUserOnly(a.handleUserOrganizationInvite()))).Methods("POST")
 	userRouter.HandleFunc("/{userId}/invite/department/{inviteId}", a.userOnly(a.registeredUserOnly(a.handleUserDepartmentInvite()))).Methods("POST")
+	userRouter.HandleFunc("/{userId}/notifications", a.userOnly(a.entityUserOnly(a.handleGetUserNotifications()))).Methods("GET")
+	userRouter.HandleFunc("/{userId}/notifications/{notificationId}/read", a.userOnly(a.entityUserOnly(a.handleMarkNotificationRead()))).Methods("PUT")
 	userRouter.HandleFunc("/{userId}/organizations", a.userOnly(a.entityUserOnly(a.handleGetOrganizationsByUser()))).Methods("GET")
 	userRouter.HandleFunc("/{userId}/organizations", a.userOnly(a.entityUserOnly(a.handleCreateOrganization()))).Methods("POST")
 	userRouter.HandleFunc("/{userId}/teams", a.userOnly(a.entityUserOnly(a.handleGetTeamsByUser()))).Methods("GET")
@@ -198,12 +239,25 @@ func New(apiService Service, FSS fs.FS, HFS http.FileSystem) *Service {
 	userRouter.HandleFunc("/{userId}/jira-instances/{instanceId}", a.userOnly(a.entityUserOnly(a.subscribedEntityUserOnly(a.handleJiraInstanceUpdate())))).Methods("PUT")
 	userRouter.HandleFunc("/{userId}/jira-instances/{instanceId}", a.userOnly(a.entityUserOnly(a.subscribedEntityUserOnly(a.handleJiraInstanceDelete())))).Methods("DELETE")
 	userRouter.HandleFunc("/{userId}/jira-instances/{instanceId}/jql-story-search", a.userOnly(a.entityUserOnly(a.subscribedEntityUserOnly(a.handleJiraStoryJQLSearch())))).Methods("POST")
+	if a.Config.AllowAsanaImport {
+		userRouter.HandleFunc("/{userId}/asana", a.userOnly(a.entityUserOnly(a.handleGetUserAsanaConnections()))).Methods("GET")
+		userRouter.HandleFunc("/{userId}/asana", a.userOnly(a.entityUserOnly(a.handleAsanaConnectionCreate()))).Methods("POST")
+		userRouter.HandleFunc("/{userId}/asana/{connectionId}", a.userOnly(a.entityUserOnly(a.handleAsanaConnectionUpdate()))).Methods("PUT")
+		userRouter.HandleFunc("/{userId}/asana/{connectionId}", a.userOnly(a.entityUserOnly(a.handleAsanaConnectionDelete()))).Methods("DELETE")
+	}
+	if a.Config.AllowNotionImport {
+		userRouter.HandleFunc("/{userId}/notion", a.userOnly(a.entityUserOnly(a.handleGetUserNotionConnections()))).Methods("GET")
+		userRouter.HandleFunc("/{userId}/notion", a.userOnly(a.entityUserOnly(a.handleNotionConnectionCreate()))).Methods("POST")
+		userRouter.HandleFunc("/{userId}/notion/{connectionId}", a.userOnly(a.entityUserOnly(a.handleNotionConnectionUpdate()))).Methods("PUT")
+		userRouter.HandleFunc("/{userId}/notion/{connectionId}", a.userOnly(a.entityUserOnly(a.handleNotionConnectionDelete()))).Methods("DELETE")
+	}
 
 	if a.Config.ExternalAPIEnabled {
 		userRouter.HandleFunc("/{userId}/apikeys", a.userOnly(a.entityUserOnly(a.handleUserAPIKeys()))).Methods("GET")
 		userRouter.HandleFunc("/{userId}/apikeys", a.userOnly(a.verifiedUserOnly(a.handleAPIKeyGenerate()))).Methods("POST")
 		userRouter.HandleFunc("/{userId}/apikeys/{keyID}", a.userOnly(a.entityUserOnly(a.handleUserAPIKeyUpdate()))).Methods("PUT")
 		userRouter.HandleFunc("/{userId}/apikeys/{keyID}", a.userOnly(a.entityUserOnly(a.handleUserAPIKeyDelete()))).Methods("DELETE")
+		userRouter.HandleFunc("/{userId}/apikeys/{keyID}/usage", a.userOnly(a.entityUserOnly(a.handleUserAPIKeyUsage()))).Methods("GET")
 	}
 	// country(s)
 	if a.Config.ShowActiveCountries {
@@ -213,7 +267,9 @@ func New(apiService Service, FSS fs.FS, HFS http.FileSystem) *Service {
 	orgRouter.HandleFunc("/{orgId}", a.userOnly(a.orgUserOnly(a.handleGetOrganizationByUser()))).Methods("GET")
 	orgRouter.HandleFunc("/{orgId}", a.userOnly(a.orgAdminOnly(a.handleOrganizationUpdate()))).Methods("PUT")
 	orgRouter.HandleFunc("/{orgId}", a.userOnly(a.orgAdminOnly(a.handleDeleteOrganization()))).Methods("DELETE")
-	orgRouter.HandleFunc("/{orgId}/metrics", a.userOnly(a.orgUserOnly(a.handleOrganizationMetrics()))).Methods("GET")
+	orgRouter.HandleFunc("/{orgId}/sso", a.userOnly(a.orgAdminOnly(a.handleOrganizationSSOUpdate()))).Methods("PUT")
+	orgRouter.HandleFunc("/{orgId}/default-scale", a.userOnly(a.orgAdminOnly(a.handleOrganizationDefaultScaleUpdate()))).Methods("PUT")
+	orgRouter.HandleFunc("/{orgId}/metrics", a.userOnly(a.orgUserOnly(a.requireSubscriptionTier(thunderdome.SubscriptionTierEnterprise)(a.handleOrganizationMetrics())))).Methods("GET")
 	// org departments(s)
 	orgRouter.HandleFunc("/{orgId}/departments", a.userOnly(a.orgUserOnly(a.handleGetOrganizationDepartments()))).Methods("GET")
 	orgRouter.HandleFunc("/{orgId}/departments", a.userOnly(a.orgAdminOnly(a.handleCreateDepartment()))).Methods("POST")
@@ -248,6 +304,8 @@ func New(apiService Service, FSS fs.FS, HFS http.FileSystem) *Service {
 	orgRouter.HandleFunc("/{orgId}/departments/{departmentId}/teams/{teamId}/checkins/{checkinId}/comments/{commentId}", a.userOnly(a.teamUserOnly(a.handleCheckinCommentEdit(checkinSvc)))).Methods("PUT")
 	orgRouter.HandleFunc("/{orgId}/departments/{departmentId}/teams/{teamId}/checkins/{checkinId}/comments/{commentId}", a.userOnly(a.teamUserOnly(a.handleCheckinCommentDelete(checkinSvc)))).Methods("DELETE")
 	orgRouter.HandleFunc("/{orgId}/departments/{departmentId}/teams/{teamId}/metrics", a.userOnly(a.teamUserOnly(a.handleTeamMetrics()))).Methods("GET")
+	orgRouter.HandleFunc("/{orgId}/departments/{departmentId}/teams/{teamId}/velocity-trend", a.userOnly(a.teamUserOnly(a.handleTeamVelocityTrend()))).Methods("GET")
+	orgRouter.HandleFunc("/{orgId}/departments/{departmentId}/teams/{teamId}/retro-cadence", a.userOnly(a.teamUserOnly(a.handleTeamRetroCadence()))).Methods("GET")
 	// org teams
 	orgRouter.HandleFunc("/{orgId}/teams", a.userOnly(a.orgUserOnly(a.handleGetOrganizationTeams()))).Methods("GET")
 	orgRouter.HandleFunc("/{orgId}/teams", a.userOnly(a.orgAdminOnly(a.handleCreateOrganizationTeam()))).Methods("POST")
@@ -270,10 +328,13 @@ func New(apiService Service, FSS fs.FS, HFS http.FileSystem) *Service {
 	orgRouter.HandleFunc("/{orgId}/teams/{teamId}/checkins/{checkinId}/comments/{commentId}", a.userOnly(a.teamUserOnly(a.handleCheckinCommentEdit(checkinSvc)))).Methods("PUT")
 	orgRouter.HandleFunc("/{orgId}/teams/{teamId}/checkins/{checkinId}/comments/{commentId}", a.userOnly(a.teamUserOnly(a.handleCheckinCommentDelete(checkinSvc)))).Methods("DELETE")
 	orgRouter.HandleFunc("/{orgId}/teams/{teamId}/metrics", a.userOnly(a.teamUserOnly(a.handleTeamMetrics()))).Methods("GET")
+	orgRouter.HandleFunc("/{orgId}/teams/{teamId}/velocity-trend", a.userOnly(a.teamUserOnly(a.handleTeamVelocityTrend()))).Methods("GET")
+	orgRouter.HandleFunc("/{orgId}/teams/{teamId}/retro-cadence", a.userOnly(a.teamUserOnly(a.handleTeamRetroCadence()))).Methods("GET")
 	// org users
 	orgRouter.HandleFunc("/{orgId}/users", a.userOnly(a.orgUserOnly(a.handleGetOrganizationUsers()))).Methods("GET")
 	orgRouter.HandleFunc("/{orgId}/users/{userId}", a.userOnly(a.orgAdminOnly(a.handleOrganizationUpdateUser()))).Methods("PUT")
 	orgRouter.HandleFunc("/{orgId}/users/{userId}", a.userOnly(a.orgAdminOnly(a.handleOrganizationRemoveUser()))).Methods("DELETE")
+	orgRouter.HandleFunc("/{orgId}/members/import", a.userOnly(a.orgAdminOnly(a.handleOrganizationImportUsers()))).Methods("POST")
 	orgRouter.HandleFunc("/{orgId}/invites", a.userOnly(a.orgUserOnly(a.handleGetOrganizationUserInvites()))).Methods("GET")
 	orgRouter.HandleFunc("/{orgId}/invites", a.userOnly(a.orgAdminOnly(a.handleOrganizationInviteUser()))).Methods("POST")
 	orgRouter.HandleFunc("/{orgId}/invites/{inviteId}", a.userOnly(a.orgAdminOnly(a.handleDeleteOrganizationUserInvite()))).Methods("DELETE")
@@ -296,17 +357,43 @@ func New(apiService Service, FSS fs.FS, HFS http.FileSystem) *Service {
 	teamRouter.HandleFunc("/{teamId}/checkins/{checkinId}/comments", a.userOnly(a.teamUserOnly(a.handleCheckinComment(checkinSvc)))).Methods("POST")
 	teamRouter.HandleFunc("/{teamId}/checkins/{checkinId}/comments/{commentId}", a.userOnly(a.teamUserOnly(a.handleCheckinCommentEdit(checkinSvc)))).Methods("PUT")
 	teamRouter.HandleFunc("/{teamId}/checkins/{checkinId}/comments/{commentId}", a.userOnly(a.teamUserOnly(a.handleCheckinCommentDelete(checkinSvc)))).Methods("DELETE")
+	teamRouter.HandleFunc("/{teamId}/checkin-schedule", a.userOnly(a.teamUserOnly(a.handleCheckinScheduleGet()))).Methods("GET")
+	teamRouter.HandleFunc("/{teamId}/checkin-schedule", a.userOnly(a.teamUserOnly(a.teamAdminOnly(a.handleCheckinScheduleUpdate())))).Methods("PUT")
+	teamRouter.HandleFunc("/{teamId}/checkin-schedule", a.userOnly(a.teamUserOnly(a.teamAdminOnly(a.handleCheckinScheduleDelete())))).Methods("DELETE")
+	teamRouter.HandleFunc("/{teamId}/standups", a.userOnly(a.teamUserOnly(a.handleStandupsGet()))).Methods("GET")
+	teamRouter.HandleFunc("/{teamId}/standups", a.userOnly(a.teamUserOnly(a.handleStandupCreate(checkinSvc)))).Methods("POST")
+	teamRouter.HandleFunc("/{teamId}/standups/digest", a.userOnly(a.teamUserOnly(a.teamAdminOnly(a.handleStandupDigestSend())))).Methods("POST")
+	teamRouter.HandleFunc("/{teamId}/standups/{standupId}", a.userOnly(a.teamUserOnly(a.handleStandupDelete(checkinSvc)))).Methods("DELETE")
 	teamRouter.HandleFunc("/{teamId}/metrics", a.userOnly(a.teamUserOnly(a.handleTeamMetrics()))).Methods("GET")
+	teamRouter.HandleFunc("/{teamId}/velocity-trend", a.userOnly(a.teamUserOnly(a.handleTeamVelocityTrend()))).Methods("GET")
+	teamRouter.HandleFunc("/{teamId}/retro-cadence", a.userOnly(a.teamUserOnly(a.handleTeamRetroCadence()))).Methods("GET")
+	teamRouter.HandleFunc("/{teamId}/activity-scores", a.userOnly(a.teamUserOnly(a.handleTeamActivityScores()))).Methods("GET")
 	// admin
 	adminRouter.HandleFunc("/stats", a.userOnly(a.adminOnly(a.handleAppStats()))).Methods("GET")
+	adminRouter.HandleFunc("/migrations/status", a.userOnly(a.adminOnly(a.handleGetMigrationStatus()))).Methods("GET")
+	adminRouter.HandleFunc("/battles/invalid-story-links", a.userOnly(a.adminOnly(a.handleGetInvalidStoryLinks()))).Methods("GET")
+	adminRouter.HandleFunc("/config/password-policy", a.userOnly(a.adminOnly(a.handleGetPasswordPolicy()))).Methods("GET")
+	adminRouter.HandleFunc("/cleanup/games", a.userOnly(a.adminOnly(a.handleCleanupOldGames()))).Methods("POST")
+	adminRouter.HandleFunc("/retros/cleanup", a.userOnly(a.adminOnly(a.handleCleanupOldRetros()))).Methods("POST")
+	adminRouter.HandleFunc("/retros/{retroId}/restore", a.userOnly(a.adminOnly(a.handleRetroRestore()))).Methods("POST")
+	adminRouter.HandleFunc("/battles/{battleId}/restore", a.userOnly(a.adminOnly(a.handlePokerRestore()))).Methods("POST")
+	adminRouter.HandleFunc("/battles/{battleId}/rebuild", a.userOnly(a.adminOnly(a.handlePokerRebuild()))).Methods("POST")
 	adminRouter.HandleFunc("/users", a.userOnly(a.adminOnly(a.handleGetRegisteredUsers()))).Methods("GET")
 	adminRouter.HandleFunc("/users", a.userOnly(a.adminOnly(a.handleUserCreate()))).Methods("POST")
 	adminRouter.HandleFunc("/users/{userId}/promote", a.userOnly(a.adminOnly(a.handleUserPromote()))).Methods("PATCH")
 	adminRouter.HandleFunc("/users/{userId}/demote", a.userOnly(a.adminOnly(a.handleUserDemote()))).Methods("PATCH")
+	adminRouter.HandleFunc("/users/{userId}/approve", a.userOnly(a.adminOnly(a.handleUserRegistrationApprove()))).Methods("PATCH")
+	adminRouter.HandleFunc("/users/{userId}/reject", a.userOnly(a.adminOnly(a.handleUserRegistrationReject()))).Methods("PATCH")
 	adminRouter.HandleFunc("/users/{userId}/disable", a.userOnly(a.adminOnly(a.handleUserDisable()))).Methods("PATCH")
 	adminRouter.HandleFunc("/users/{userId}/enable", a.userOnly(a.adminOnly(a.handleUserEnable()))).Methods("PATCH")
+	adminRouter.HandleFunc("/users/{userId}/invalidate-sessions", a.userOnly(a.adminOnly(a.handleInvalidateUserSessions()))).Methods("POST")
+	adminRouter.HandleFunc("/users/{userId}/merge", a.userOnly(a.adminOnly(a.handleUserMerge()))).Methods("POST")
+	adminRouter.HandleFunc("/users/{userId}/anonymize", a.userOnly(a.adminOnly(a.handleUserAnonymize()))).Methods("POST")
+	adminRouter.HandleFunc("/users/{userId}/estimation-bias", a.userOnly(a.adminOnly(a.handleGetUserEstimationBias()))).Methods("GET")
 	adminRouter.HandleFunc("/users/{userId}/password", a.userOnly(a.adminOnly(a.handleAdminUpdateUserPassword()))).Methods("PATCH")
 	adminRouter.HandleFunc("/organizations", a.userOnly(a.adminOnly(a.handleGetOrganizations()))).Methods("GET")
+	adminRouter.HandleFunc("/organizations/{orgId}/subscription/override", a.userOnly(a.adminOnly(a.handleSubscriptionOverride()))).Methods("POST")
+	adminRouter.HandleFunc("/organizations/{orgId}/subscription/extend", a.userOnly(a.adminOnly(a.handleSubscriptionExtend()))).Methods("POST")
 	adminRouter.HandleFunc("/teams", a.userOnly(a.adminOnly(a.handleGetTeams()))).Methods("GET")
 	adminRouter.HandleFunc("/apikeys", a.userOnly(a.adminOnly(a.handleGetAPIKeys()))).Methods("GET")
 	adminRouter.HandleFunc("/search/users/email", a.userOnly(a.adminOnly(a.handleSearchRegisteredUsersByEmail()))).Methods("GET")
@@ -317,10 +404,14 @@ func New(apiService Service, FSS fs.FS, HFS http.FileSystem) *Service {
 	apiRouter.HandleFunc("/alerts/{alertId}", a.userOnly(a.adminOnly(a.handleAlertDelete()))).Methods("DELETE")
 	// maintenance
 	apiRouter.HandleFunc("/maintenance/clean-guests", a.userOnly(a.adminOnly(a.handleCleanGuests()))).Methods("DELETE")
+	apiRouter.HandleFunc("/maintenance/clean-notifications", a.userOnly(a.adminOnly(a.handleCleanNotifications()))).Methods("DELETE")
 	// poker games(s)
 	if a.Config.FeaturePoker {
 		userRouter.HandleFunc("/{userId}/battles", a.userOnly(a.entityUserOnly(a.handlePokerCreate()))).Methods("POST")
+		orgRouter.HandleFunc("/{orgId}/active-games", a.userOnly(a.orgAdminOnly(a.handleGetOrganizationActiveGames()))).Methods("GET")
+		orgRouter.HandleFunc("/{orgId}/estimation-calibration", a.userOnly(a.orgAdminOnly(a.handleGetOrganizationEstimationCalibration()))).Methods("GET")
 		userRouter.HandleFunc("/{userId}/battles", a.userOnly(a.entityUserOnly(a.handleGetUserGames()))).Methods("GET")
+		userRouter.HandleFunc("/{userId}/estimation-history", a.userOnly(a.entityUserOnly(a.handleGetUserEstimationHistory()))).Methods("GET")
 		orgRouter.HandleFunc("/{orgId}/departments/{departmentId}/teams/{teamId}/battles", a.userOnly(a.teamUserOnly(a.handleGetTeamPokerGames()))).Methods("GET")
 		orgRouter.HandleFunc("/{orgId}/departments/{departmentId}/teams/{teamId}/battles/{battleId}", a.userOnly(a.teamUserOnly(a.teamAdminOnly(a.handleTeamRemovePokerGame())))).Methods("DELETE")
 		orgRouter.HandleFunc("/{orgId}/departments/{departmentId}/teams/{teamId}/users/{userId}/battles", a.userOnly(a.teamUserOnly(a.handlePokerCreate()))).Methods("POST")
@@ -330,13 +421,55 @@ func New(apiService Service, FSS fs.FS, HFS http.FileSystem) *Service {
 		teamRouter.HandleFunc("/{teamId}/battles", a.userOnly(a.teamUserOnly(a.handleGetTeamPokerGames()))).Methods("GET")
 		teamRouter.HandleFunc("/{teamId}/battles/{battleId}", a.userOnly(a.teamUserOnly(a.teamAdminOnly(a.handleTeamRemovePokerGame())))).Methods("DELETE")
 		teamRouter.HandleFunc("/{teamId}/users/{userId}/battles", a.userOnly(a.teamUserOnly(a.entityUserOnly(a.handlePokerCreate())))).Methods("POST")
+		teamRouter.HandleFunc("/{teamId}/stories", a.userOnly(a.teamUserOnly(a.handleGetTeamStoriesByReferenceID()))).Methods("GET")
+		teamRouter.HandleFunc("/{teamId}/story-type-report", a.userOnly(a.teamUserOnly(a.handleGetTeamStoryTypeReport()))).Methods("GET")
+		teamRouter.HandleFunc("/{teamId}/game-templates", a.userOnly(a.teamUserOnly(a.handleGetGameTemplates()))).Methods("GET")
+		teamRouter.HandleFunc("/{teamId}/game-templates", a.userOnly(a.teamUserOnly(a.teamAdminOnly(a.handleGameTemplateCreate())))).Methods("POST")
+		teamRouter.HandleFunc("/{teamId}/game-templates/{templateId}", a.userOnly(a.teamUserOnly(a.handleGetGameTemplate()))).Methods("GET")
+		teamRouter.HandleFunc("/{teamId}/game-templates/{templateId}", a.userOnly(a.teamUserOnly(a.teamAdminOnly(a.handleGameTemplateUpdate())))).Methods("PUT")
+		teamRouter.HandleFunc("/{teamId}/game-templates/{templateId}", a.userOnly(a.teamUserOnly(a.teamAdminOnly(a.handleGameTemplateDelete())))).Methods("DELETE")
 		apiRouter.HandleFunc("/maintenance/clean-battles", a.userOnly(a.adminOnly(a.handleCleanPokerGames()))).Methods("DELETE")
 		apiRouter.HandleFunc("/battles", a.userOnly(a.adminOnly(a.handleGetPokerGames()))).Methods("GET")
 		apiRouter.HandleFunc("/battles/{battleId}", a.userOnly(a.handleGetPokerGame())).Methods("GET")
+		apiRouter.HandleFunc("/battles/{battleId}/leaderboard", a.userOnly(a.handleGetPokerLeaderboard())).Methods("GET")
+		apiRouter.HandleFunc("/battles/{battleId}/statistics", a.userOnly(a.handleGetPokerStatistics())).Methods("GET")
+		apiRouter.HandleFunc("/battles/{battleId}/completion", a.userOnly(a.handleGetPokerCompletion())).Methods("GET")
+		apiRouter.HandleFunc("/battles/{battleId}/access-log", a.userOnly(a.handleGetPokerAccessLog())).Methods("GET")
+		apiRouter.HandleFunc("/battles/{battleId}/export", a.userOnly(a.handlePokerExport())).Methods("GET")
+		apiRouter.HandleFunc("/battles/{battleId}/export/jira", a.userOnly(a.handlePokerJiraExport())).Methods("POST")
+		apiRouter.HandleFunc("/battles/{battleId}/github-pr-comment", a.userOnly(a.handlePokerGitHubPRComment(actions.NewGitHubActionsHandler(a.Config.GitHubAPIURL)))).Methods("POST")
 		apiRouter.HandleFunc("/battles/{battleId}", a.userOnly(a.handlePokerDelete(pokerSvc))).Methods("DELETE")
+		apiRouter.HandleFunc("/battles/{battleId}/join-link", a.userOnly(a.handleGetPokerJoinDeepLink())).Methods("GET")
+		apiRouter.HandleFunc("/battles/{battleId}/join-code/regenerate", a.userOnly(a.handlePokerJoinCodeRegenerate(pokerSvc))).Methods("PUT")
+		apiRouter.HandleFunc("/battles/{battleId}/facilitator-code/regenerate", a.userOnly(a.handlePokerFacilitatorCodeRegenerate(pokerSvc))).Methods("PUT")
+		apiRouter.HandleFunc("/battles/{battleId}/plans", a.userOnly(a.handleGetPokerStories())).Methods("GET")
 		apiRouter.HandleFunc("/battles/{battleId}/plans", a.userOnly(a.handlePokerStoryAdd(pokerSvc))).Methods("POST")
+		apiRouter.HandleFunc("/battles/{battleId}/plans/search", a.userOnly(a.handleSearchPokerStories())).Methods("GET")
+		apiRouter.HandleFunc("/battles/{battleId}/plans/duplicates", a.userOnly(a.handleGetPokerStoryDuplicates())).Methods("GET")
+		apiRouter.HandleFunc("/battles/{battleId}/plans/import", a.userOnly(a.handlePokerStoriesImport(pokerSvc))).Methods("POST")
+		if a.Config.AllowAsanaImport {
+			apiRouter.HandleFunc("/battles/{battleId}/plans/import/asana", a.userOnly(a.handlePokerAsanaImport(pokerSvc))).Methods("POST")
+		}
+		if a.Config.AllowNotionImport {
+			apiRouter.HandleFunc("/battles/{battleId}/plans/import/notion", a.userOnly(a.handlePokerNotionImport(pokerSvc))).Methods("POST")
+		}
+		if a.Config.AllowCsvImport {
+			apiRouter.HandleFunc("/battles/{battleId}/plans/import/csv", a.userOnly(a.handlePokerStoriesCSVImport(pokerSvc))).Methods("POST")
+		}
+		apiRouter.HandleFunc("/battles/{battleId}/plans/priority", a.userOnly(a.handlePokerStoriesPriorityUpdate(pokerSvc))).Methods("PUT")
+		apiRouter.HandleFunc("/battles/{battleId}/plans/sprint", a.userOnly(a.handlePokerStoriesSprintAssign(pokerSvc))).Methods("PUT")
+		apiRouter.HandleFunc("/battles/{battleId}/plans/status", a.userOnly(a.handlePokerStoriesStatusUpdate(pokerSvc))).Methods("PUT")
 		apiRouter.HandleFunc("/battles/{battleId}/plans/{planId}", a.userOnly(a.handlePokerStoryUpdate(pokerSvc))).Methods("PUT")
 		apiRouter.HandleFunc("/battles/{battleId}/plans/{planId}", a.userOnly(a.handlePokerStoryDelete(pokerSvc))).Methods("DELETE")
+		apiRouter.HandleFunc("/battles/{battleId}/plans/{planId}/activate", a.userOnly(a.handlePokerStoryActivate(pokerSvc))).Methods("POST")
+		apiRouter.HandleFunc("/stories/{storyId}/facilitator-notes", a.userOnly(a.handlePokerStoryFacilitatorNotesUpdate())).Methods("PUT")
+		apiRouter.HandleFunc("/stories/{storyId}/comments", a.userOnly(a.handleGetPokerStoryComments())).Methods("GET")
+		apiRouter.HandleFunc("/stories/{storyId}/comments", a.userOnly(a.handlePokerStoryCommentAdd(pokerSvc))).Methods("POST")
+		if a.StorageSvc != nil {
+			apiRouter.HandleFunc("/stories/{storyId}/attachments", a.userOnly(a.handleGetPokerStoryAttachments())).Methods("GET")
+			apiRouter.HandleFunc("/stories/{storyId}/attachments", a.userOnly(a.handlePokerStoryAttachmentConfirm())).Methods("POST")
+			apiRouter.HandleFunc("/stories/{storyId}/attachments/presign", a.userOnly(a.handlePokerStoryAttachmentPresign())).Methods("POST")
+		}
 		apiRouter.HandleFunc("/arena/{battleId}", pokerSvc.ServeBattleWs())
 
 		// estimation scales
@@ -345,18 +478,18 @@ func New(apiService Service, FSS fs.FS, HFS http.FileSystem) *Service {
 		apiRouter.HandleFunc("/estimation-scales/public/{scaleId}", a.userOnly(a.handleGetPublicEstimationScale())).Methods("GET")
 
 		// Organization-specific estimation scale routes
-		orgRouter.HandleFunc("/{orgId}/estimation-scales", a.userOnly(a.subscribedOrgOnly(a.orgUserOnly(a.handleGetOrganizationEstimationScales())))).Methods("GET")
-		orgRouter.HandleFunc("/{orgId}/estimation-scales", a.userOnly(a.subscribedOrgOnly(a.orgAdminOnly(a.handleOrganizationEstimationScaleCreate())))).Methods("POST")
-		orgRouter.HandleFunc("/{orgId}/estimation-scales/{scaleId}", a.userOnly(a.subscribedOrgOnly(a.orgAdminOnly(a.handleOrganizationEstimationScaleUpdate())))).Methods("PUT")
-		orgRouter.HandleFunc("/{orgId}/estimation-scales/{scaleId}", a.userOnly(a.subscribedOrgOnly(a.orgAdminOnly(a.handleOrganizationEstimationScaleDelete())))).Methods("DELETE")
-		orgRouter.HandleFunc("/{orgId}/departments/{departmentId}/teams/{teamId}/estimation-scales", a.userOnly(a.subscribedOrgOnly(a.departmentUserOnly(a.handleGetTeamEstimationScales())))).Methods("GET")
-		orgRouter.HandleFunc("/{orgId}/departments/{departmentId}/teams/{teamId}/estimation-scales", a.userOnly(a.subscribedOrgOnly(a.teamUserOnly(a.teamAdminOnly(a.handleTeamEstimationScaleCreate()))))).Methods("POST")
-		orgRouter.HandleFunc("/{orgId}/departments/{departmentId}/teams/{teamId}/estimation-scales/{scaleId}", a.userOnly(a.subscribedOrgOnly(a.teamUserOnly(a.teamAdminOnly(a.handleTeamEstimationScaleUpdate()))))).Methods("PUT")
-		orgRouter.HandleFunc("/{orgId}/departments/{departmentId}/teams/{teamId}/estimation-scales/{scaleId}", a.userOnly(a.subscribedOrgOnly(a.teamUserOnly(a.teamAdminOnly(a.handleTeamEstimationScaleDelete()))))).Methods("DELETE")
-		orgRouter.HandleFunc("/{orgId}/teams/{teamId}/estimation-scales", a.userOnly(a.subscribedOrgOnly(a.teamUserOnly(a.handleGetTeamEstimationScales())))).Methods("GET")
-		orgRouter.HandleFunc("/{orgId}/teams/{teamId}/estimation-scales", a.userOnly(a.subscribedOrgOnly(a.teamUserOnly(a.teamAdminOnly(a.handleTeamEstimationScaleCreate()))))).Methods("POST")
-		orgRouter.HandleFunc("/{orgId}/teams/{teamId}/estimation-scales/{scaleId}", a.userOnly(a.subscribedOrgOnly(a.teamUserOnly(a.teamAdminOnly(a.handleTeamEstimationScaleUpdate()))))).Methods("PUT")
-		orgRouter.HandleFunc("/{orgId}/teams/{teamId}/estimation-scales/{scaleId}", a.userOnly(a.subscribedOrgOnly(a.teamUserOnly(a.teamAdminOnly(a.handleTeamEstimationScaleDelete()))))).Methods("DELETE")
+		orgRouter.HandleFunc("/{orgId}/estimation-scales", a.userOnly(a.orgUserOnly(a.requireSubscriptionTier(thunderdome.SubscriptionTierTeam)(a.handleGetOrganizationEstimationScales())))).Methods("GET")
+		orgRouter.HandleFunc("/{orgId}/estimation-scales", a.userOnly(a.orgAdminOnly(a.requireSubscriptionTier(thunderdome.SubscriptionTierTeam)(a.handleOrganizationEstimationScaleCreate())))).Methods("POST")
+		orgRouter.HandleFunc("/{orgId}/estimation-scales/{scaleId}", a.userOnly(a.orgAdminOnly(a.requireSubscriptionTier(thunderdome.SubscriptionTierTeam)(a.handleOrganizationEstimationScaleUpdate())))).Methods("PUT")
+		orgRouter.HandleFunc("/{orgId}/estimation-scales/{scaleId}", a.userOnly(a.orgAdminOnly(a.requireSubscriptionTier(thunderdome.SubscriptionTierTeam)(a.handleOrganizationEstimationScaleDelete())))).Methods("DELETE")
+		orgRouter.HandleFunc("/{orgId}/departments/{departmentId}/teams/{teamId}/estimation-scales", a.userOnly(a.departmentUserOnly(a.requireSubscriptionTier(thunderdome.SubscriptionTierTeam)(a.handleGetTeamEstimationScales())))).Methods("GET")
+		orgRouter.HandleFunc("/{orgId}/departments/{departmentId}/teams/{teamId}/estimation-scales", a.userOnly(a.teamUserOnly(a.teamAdminOnly(a.requireSubscriptionTier(thunderdome.SubscriptionTierTeam)(a.handleTeamEstimationScaleCreate()))))).Methods("POST")
+		orgRouter.HandleFunc("/{orgId}/departments/{departmentId}/teams/{teamId}/estimation-scales/{scaleId}", a.userOnly(a.teamUserOnly(a.teamAdminOnly(a.requireSubscriptionTier(thunderdome.SubscriptionTierTeam)(a.handleTeamEstimationScaleUpdate()))))).Methods("PUT")
+		orgRouter.HandleFunc("/{orgId}/departments/{departmentId}/teams/{teamId}/estimation-scales/{scaleId}", a.userOnly(a.teamUserOnly(a.teamAdminOnly(a.requireSubscriptionTier(thunderdome.SubscriptionTierTeam)(a.handleTeamEstimationScaleDelete()))))).Methods("DELETE")
+		orgRouter.HandleFunc("/{orgId}/teams/{teamId}/estimation-scales", a.userOnly(a.teamUserOnly(a.requireSubscriptionTier(thunderdome.SubscriptionTierTeam)(a.handleGetTeamEstimationScales())))).Methods("GET")
+		orgRouter.HandleFunc("/{orgId}/teams/{teamId}/estimation-scales", a.userOnly(a.teamUserOnly(a.teamAdminOnly(a.requireSubscriptionTier(thunderdome.SubscriptionTierTeam)(a.handleTeamEstimationScaleCreate()))))).Methods("POST")
+		orgRouter.HandleFunc("/{orgId}/teams/{teamId}/estimation-scales/{scaleId}", a.userOnly(a.teamUserOnly(a.teamAdminOnly(a.requireSubscriptionTier(thunderdome.SubscriptionTierTeam)(a.handleTeamEstimationScaleUpdate()))))).Methods("PUT")
+		orgRouter.HandleFunc("/{orgId}/teams/{teamId}/estimation-scales/{scaleId}", a.userOnly(a.teamUserOnly(a.teamAdminOnly(a.requireSubscriptionTier(thunderdome.SubscriptionTierTeam)(a.handleTeamEstimationScaleDelete()))))).Methods("DELETE")
 
 		// Team-specific estimation scale routes
 		teamRouter.HandleFunc("/{teamId}/estimation-scales", a.userOnly(a.subscribedTeamOnly(a.teamUserOnly(a.handleGetTeamEstimationScales())))).Methods("GET")
@@ -385,10 +518,20 @@ func New(apiService Service, FSS fs.FS, HFS http.FileSystem) *Service {
 		teamRouter.HandleFunc("/{teamId}/retros", a.userOnly(a.teamUserOnly(a.handleGetTeamRetros()))).Methods("GET")
 		teamRouter.HandleFunc("/{teamId}/retros/{retroId}", a.userOnly(a.teamUserOnly(a.teamAdminOnly(a.handleTeamRemoveRetro())))).Methods("DELETE")
 		teamRouter.HandleFunc("/{teamId}/retro-actions", a.userOnly(a.teamUserOnly(a.handleGetTeamRetroActions()))).Methods("GET")
+		teamRouter.HandleFunc("/{teamId}/retro-schedules", a.userOnly(a.teamUserOnly(a.handleGetRetroSchedules()))).Methods("GET")
+		teamRouter.HandleFunc("/{teamId}/retro-schedules", a.userOnly(a.teamUserOnly(a.teamAdminOnly(a.handleRetroScheduleCreate())))).Methods("POST")
+		teamRouter.HandleFunc("/{teamId}/retro-schedules/{scheduleId}", a.userOnly(a.teamUserOnly(a.handleGetRetroSchedule()))).Methods("GET")
+		teamRouter.HandleFunc("/{teamId}/retro-schedules/{scheduleId}", a.userOnly(a.teamUserOnly(a.teamAdminOnly(a.handleRetroScheduleUpdate())))).Methods("PUT")
+		teamRouter.HandleFunc("/{teamId}/retro-schedules/{scheduleId}", a.userOnly(a.teamUserOnly(a.teamAdminOnly(a.handleRetroScheduleDelete())))).Methods("DELETE")
 		teamRouter.HandleFunc("/{teamId}/users/{userId}/retros", a.userOnly(a.teamUserOnly(a.entityUserOnly(a.handleRetroCreate())))).Methods("POST")
 		apiRouter.HandleFunc("/maintenance/clean-retros", a.userOnly(a.adminOnly(a.handleCleanRetros()))).Methods("DELETE")
 		apiRouter.HandleFunc("/retros", a.userOnly(a.adminOnly(a.handleGetRetros()))).Methods("GET")
 		apiRouter.HandleFunc("/retros/{retroId}", a.userOnly(a.handleRetroGet())).Methods("GET")
+		apiRouter.HandleFunc("/retros/{retroId}/emotions", a.userOnly(a.handleRetroEmotionsGet())).Methods("GET")
+		apiRouter.HandleFunc("/retros/{retroId}/insights", a.userOnly(a.handleRetroInsightsGenerate(aiSvc))).Methods("POST")
+		apiRouter.HandleFunc("/retros/{retroId}/sub-retros", a.userOnly(a.handleSubRetrosGet())).Methods("GET")
+		apiRouter.HandleFunc("/retros/{retroId}/sub-retros", a.userOnly(a.handleSubRetroCreate())).Methods("POST")
+		apiRouter.HandleFunc("/retros/{retroId}/sub-retros/merge", a.userOnly(a.handleSubRetrosMerge())).Methods("POST")
 		apiRouter.HandleFunc("/retros/{retroId}", a.userOnly(a.handleRetroDelete(retroSvc))).Methods("DELETE")
 		apiRouter.HandleFunc("/retros/{retroId}/actions/{actionId}", a.userOnly(a.handleRetroActionUpdate(retroSvc))).Methods("PUT")
 		apiRouter.HandleFunc("/retros/{retroId}/actions/{actionId}", a.userOnly(a.handleRetroActionDelete(retroSvc))).Methods("DELETE")
@@ -400,6 +543,9 @@ func New(apiService Service, FSS fs.FS, HFS http.FileSystem) *Service {
 
 		// Retro Templates
 		apiRouter.HandleFunc("/retro-templates/public", a.userOnly(a.handleGetPublicRetroTemplates())).Methods("GET")
+		apiRouter.HandleFunc("/retro-templates/marketplace", a.userOnly(a.handleGetMarketplaceRetroTemplates())).Methods("GET")
+		apiRouter.HandleFunc("/retro-templates/{templateId}/marketplace/publish", a.userOnly(a.handleRetroTemplateMarketplacePublish())).Methods("POST")
+		apiRouter.HandleFunc("/retro-templates/{templateId}/marketplace/install", a.userOnly(a.handleRetroTemplateMarketplaceInstall())).Methods("POST")
 		// Organization templates
 		orgRouter.HandleFunc("/{orgId}/retro-templates", a.userOnly(a.subscribedOrgOnly(a.orgUserOnly(a.handleGetOrganizationRetroTemplates())))).Methods("GET")
 		orgRouter.HandleFunc("/{orgId}/retro-templates", a.userOnly(a.subscribedOrgOnly(a.orgAdminOnly(a.handleOrganizationRetroTemplateCreate())))).Methods("POST")
@@ -443,11 +589,17 @@ func New(apiService Service, FSS fs.FS, HFS http.FileSystem) *Service {
 		apiRouter.HandleFunc("/maintenance/clean-storyboards", a.userOnly(a.adminOnly(a.handleCleanStoryboards()))).Methods("DELETE")
 		apiRouter.HandleFunc("/storyboards", a.userOnly(a.adminOnly(a.handleGetStoryboards()))).Methods("GET")
 		apiRouter.HandleFunc("/storyboards/{storyboardId}", a.userOnly(a.handleStoryboardGet())).Methods("GET")
+		apiRouter.HandleFunc("/storyboards/{storyboardId}/export", a.userOnly(a.handleStoryboardExport(storyboardSvc))).Methods("GET")
 		apiRouter.HandleFunc("/storyboards/{storyboardId}", a.userOnly(a.handleStoryboardDelete(storyboardSvc))).Methods("DELETE")
 		apiRouter.HandleFunc("/storyboards/{storyboardId}/goals", a.userOnly(a.handleStoryboardGoalAdd(storyboardSvc))).Methods("POST")
 		apiRouter.HandleFunc("/storyboards/{storyboardId}/columns", a.userOnly(a.handleStoryboardColumnAdd(storyboardSvc))).Methods("POST")
 		apiRouter.HandleFunc("/storyboards/{storyboardId}/stories", a.userOnly(a.handleStoryboardStoryAdd(storyboardSvc))).Methods("POST")
 		apiRouter.HandleFunc("/storyboards/{storyboardId}/stories/{storyId}/move", a.userOnly(a.handleStoryboardStoryMove(storyboardSvc))).Methods("PUT")
+		apiRouter.HandleFunc("/storyboard-templates", a.userOnly(a.handleGetStoryboardTemplates())).Methods("GET")
+		apiRouter.HandleFunc("/storyboard-templates", a.userOnly(a.handleStoryboardTemplateCreate())).Methods("POST")
+		apiRouter.HandleFunc("/storyboard-templates/{templateId}", a.userOnly(a.handleGetStoryboardTemplate())).Methods("GET")
+		apiRouter.HandleFunc("/storyboard-templates/{templateId}", a.userOnly(a.handleStoryboardTemplateUpdate())).Methods("PUT")
+		apiRouter.HandleFunc("/storyboard-templates/{templateId}", a.userOnly(a.handleStoryboardTemplateDelete())).Methods("DELETE")
 		apiRouter.HandleFunc("/storyboard/{storyboardId}", storyboardSvc.ServeWs())
 	}
 
@@ -458,6 +610,9 @@ func New(apiService Service, FSS fs.FS, HFS http.FileSystem) *Service {
 	}
 
 	if a.Config.SubscriptionsEnabled {
+		apiRouter.HandleFunc("/subscriptions/billing-webhook/test", a.userOnly(a.adminOnly(a.handleBillingWebhookTest()))).Methods("POST")
+		apiRouter.HandleFunc("/subscriptions/billing-webhook/event-log", a.userOnly(a.adminOnly(a.handleGetBillingWebhookEvents()))).Methods("GET")
+		apiRouter.HandleFunc("/subscriptions/billing-webhook/event-log/{eventId}/replay", a.userOnly(a.adminOnly(a.handleBillingWebhookEventReplay()))).Methods("POST")
 		apiRouter.PathPrefix("/subscriptions/{subscriptionId}").Handler(a.userOnly(a.adminOnly(a.handleSubscriptionGetByID()))).Methods("GET")
 		apiRouter.PathPrefix("/subscriptions/{subscriptionId}").Handler(a.userOnly(a.adminOnly(a.handleSubscriptionUpdate()))).Methods("PUT")
 		apiRouter.PathPrefix("/subscriptions/{subscriptionId}").Handler(a.userOnly(a.adminOnly(a.handleSubscriptionDelete()))).Methods("DELETE")
@@ -467,6 +622,22 @@ func New(apiService Service, FSS fs.FS, HFS http.FileSystem) *Service {
 	}
 
 	a.registerOauthProviderEndpoints(authProviderConfigs)
+	if a.Config.SAMLAuth.Enabled {
+		a.registerSAMLEndpoints(a.Config.SAMLAuth.Config)
+	}
+
+	// local storage attachment uploads and downloads, registered ahead of the static assets
+	if local, ok := a.StorageSvc.(*storage.LocalFSImpl); ok {
+		router.Handle(storage.LocalUploadPath, local.HandleUpload()).Methods("POST")
+		router.PathPrefix(storage.LocalDownloadPath + "/").Handler(
+			http.StripPrefix(a.Config.PathPrefix+storage.LocalDownloadPath, local.HandleDownload())).Methods("GET")
+	}
+
+	// poker join deep links, registered ahead of the index so the ui doesn't handle them
+	if a.Config.FeaturePoker {
+		router.HandleFunc("/join", a.userOnly(a.handlePokerJoinDeepLinkConfirm())).Methods("GET")
+		router.HandleFunc("/join", a.userOnly(a.handlePokerJoinDeepLink())).Methods("POST")
+	}
 
 	// static assets
 	router.PathPrefix("/static/").Handler(http.StripPrefix(a.Config.PathPrefix, staticHandler))
@@ -481,9 +652,8 @@ func New(apiService Service, FSS fs.FS, HFS http.FileSystem) *Service {
 	return a
 }
 
-func (s *Service) registerOauthProviderEndpoints(providers []thunderdome.AuthProviderConfig) {
-	ctx := context.Background()
-	var redirectBaseURL string
+// redirectBaseURL gets the application base url external auth providers redirect back to
+func (s *Service) redirectBaseURL() string {
 	var port string
 
 	// redirect with port for localhost
@@ -492,11 +662,16 @@ func (s *Service) registerOauthProviderEndpoints(providers []thunderdome.AuthPro
 	}
 
 	if s.Config.SecureProtocol {
-		redirectBaseURL = fmt.Sprintf("https://%s%s", s.Config.AppDomain, port)
-	} else {
-		redirectBaseURL = fmt.Sprintf("http://%s%s%s", s.Config.AppDomain, port, s.Config.PathPrefix)
+		return fmt.Sprintf("https://%s%s", s.Config.AppDomain, port)
 	}
 
+	return fmt.Sprintf("http://%s%s%s", s.Config.AppDomain, port, s.Config.PathPrefix)
+}
+
+func (s *Service) registerOauthProviderEndpoints(providers []thunderdome.AuthProviderConfig) {
+	ctx := context.Background()
+	redirectBaseURL := s.redirectBaseURL()
+
 	for _, c := range providers {
 		oauthLoginPathPrefix, _ := url.JoinPath("/oauth/", c.ProviderName, "/login")
 		oauthCallbackPathPrefix, _ := url.JoinPath("/oauth/", c.ProviderName, "/callback")
@@ -505,6 +680,7 @@ func (s *Service) registerOauthProviderEndpoints(providers []thunderdome.AuthPro
 			AuthProviderConfig:  c,
 			CallbackRedirectURL: callbackRedirectURL,
 			UIRedirectURL:       fmt.Sprintf("%s/", s.Config.PathPrefix),
+			StateStore:          s.OAuthStateStore,
 		}, s.Cookie, s.Logger, s.AuthDataSvc, s.SubscriptionDataSvc, ctx)
 		if err != nil {
 			panic(err)
@@ -514,6 +690,23 @@ func (s *Service) registerOauthProviderEndpoints(providers []thunderdome.AuthPro
 	}
 }
 
+func (s *Service) registerSAMLEndpoints(config saml.Config) {
+	if config.SPCallbackURL == "" {
+		config.SPCallbackURL, _ = url.JoinPath(s.redirectBaseURL(), "/auth/saml/acs")
+	}
+	config.UIRedirectURL = fmt.Sprintf("%s/", s.Config.PathPrefix)
+
+	authProvider, err := saml.New(
+		config, s.Cookie, s.Logger, s.AuthDataSvc, s.TeamDataSvc, s.SubscriptionDataSvc, context.Background(),
+	)
+	if err != nil {
+		panic(err)
+	}
+	s.Router.HandleFunc("/auth/saml/metadata", authProvider.HandleMetadata()).Methods("GET")
+	s.Router.HandleFunc("/auth/saml/login", authProvider.HandleLogin()).Methods("GET")
+	s.Router.HandleFunc("/auth/saml/acs", authProvider.HandleACS()).Methods("POST")
+}
+
 func (s *Service) ListenAndServe() error {
 	srv := &http.Server{
 		Handler:           s.Router,
@@ -526,7 +719,67 @@ func (s *Service) ListenAndServe() error {
 
 	s.Logger.Info("Access the WebUI via 127.0.0.1:" + s.Config.Port)
 
-	return srv.ListenAndServe()
+	sigCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-sigCtx.Done():
+	}
+
+	s.Logger.Info("Shutting down, closing websocket connections")
+	gracePeriod := time.Duration(s.Config.WebsocketConfig.ShutdownGracePeriodSec) * time.Second
+	if gracePeriod <= 0 {
+		gracePeriod = 30 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), gracePeriod)
+	defer cancel()
+
+	s.shutdownWebsockets(ctx)
+
+	if err := srv.Shutdown(ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// EvictIdleWebsocketConnections closes the websocket connections of all the websocket services
+// that have been idle for idleMinutes and don't respond to a ping, returning the number evicted
+func (s *Service) EvictIdleWebsocketConnections(idleMinutes int) int {
+	var wg sync.WaitGroup
+	var evicted atomic.Int64
+	for _, ws := range s.websocketServices {
+		wg.Add(1)
+		go func(ws websocketService) {
+			defer wg.Done()
+			evicted.Add(int64(ws.EvictIdleConnections(idleMinutes)))
+		}(ws)
+	}
+	wg.Wait()
+
+	return int(evicted.Load())
+}
+
+// shutdownWebsockets gracefully closes the websocket connections of all the websocket services in parallel
+func (s *Service) shutdownWebsockets(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, ws := range s.websocketServices {
+		wg.Add(1)
+		go func(ws websocketService) {
+			defer wg.Done()
+			if err := ws.Shutdown(ctx); err != nil {
+				s.Logger.Ctx(ctx).Error("websocket shutdown error", zap.Error(err))
+			}
+		}(ws)
+	}
+	wg.Wait()
 }
 
 // handleIndex parses the index html file, injecting any relevant data
@@ -560,11 +813,17 @@ func (s *Service) handleIndex(filesystem fs.FS, uiConfig thunderdome.UIConfig) h
 	}
 }
 
+// handleHealthCheck reports the service is up along with the redis availability and cache hit stats,
+// the service stays up serving from the database while redis is unavailable
 func (s *Service) handleHealthCheck() func(http.ResponseWriter, *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
-		w.Write([]byte(`{"status": "ok"}`))
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":       "ok",
+			"redis_status": redis.Status(),
+			"redis_cache":  redis.GetCacheStats(),
+		})
 	}
 }
 
@@ -0,0 +1,43 @@
+package http
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/http/clientip"
+)
+
+// ParseIPAllowlist parses the allowlist CIDR ranges, an invalid range is returned as an error
+// so a misconfigured allowlist can't silently allow or deny everyone
+func ParseIPAllowlist(cidrs []string) ([]*net.IPNet, error) {
+	return clientip.ParseCIDRs("ip allowlist", cidrs)
+}
+
+// ParseTrustedProxies parses the trusted proxy CIDR ranges, an invalid range is returned as an error
+func ParseTrustedProxies(cidrs []string) ([]*net.IPNet, error) {
+	return clientip.ParseCIDRs("trusted proxies", cidrs)
+}
+
+// requireIPAllowlist rejects requests from outside the admin ip allowlist with an empty 403 response,
+// every request is allowed when the allowlist is empty
+func (s *Service) requireIPAllowlist(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.adminIPAllowed(r) {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		h.ServeHTTP(w, r)
+	})
+}
+
+// adminIPAllowed checks whether the request came from inside the admin ip allowlist,
+// every request is allowed when the allowlist is empty
+func (s *Service) adminIPAllowed(r *http.Request) bool {
+	if len(s.Config.AdminIPAllowlist) == 0 {
+		return true
+	}
+
+	ip := clientip.FromRequest(r, s.Config.TrustedProxies)
+	return ip != nil && clientip.InNets(ip, s.Config.AdminIPAllowlist)
+}
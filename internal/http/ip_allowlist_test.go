@@ -0,0 +1,138 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+// newAllowlistServer serves an ok response behind the admin ip allowlist, test server requests
+// come from the 127.0.0.1 loopback address
+func newAllowlistServer(t *testing.T, cidrs []string, trustedProxyCIDRs []string) *httptest.Server {
+	allowlist, err := ParseIPAllowlist(cidrs)
+	require.NoError(t, err)
+	trustedProxies, err := ParseTrustedProxies(trustedProxyCIDRs)
+	require.NoError(t, err)
+
+	s := &Service{Config: &Config{AdminIPAllowlist: allowlist, TrustedProxies: trustedProxies}}
+	server := httptest.NewServer(s.requireIPAllowlist(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestRequireIPAllowlist(t *testing.T) {
+	loopback := []string{"127.0.0.0/8"}
+	proxies := []string{"127.0.0.0/8", "10.0.0.0/8"}
+	tests := []struct {
+		name           string
+		cidrs          []string
+		trustedProxies []string
+		forwardedFor   string
+		expectedStatus int
+	}{
+		{name: "empty allowlist allows every ip", expectedStatus: http.StatusOK},
+		{name: "loopback remote address allowed", cidrs: []string{"127.0.0.0/8"}, expectedStatus: http.StatusOK},
+		{name: "loopback remote address denied", cidrs: []string{"203.0.113.0/24"}, expectedStatus: http.StatusForbidden},
+		{name: "header ignored when the remote address isn't a trusted proxy", cidrs: []string{"203.0.113.0/24"}, trustedProxies: []string{"10.0.0.0/8"}, forwardedFor: "203.0.113.7", expectedStatus: http.StatusForbidden},
+		{name: "forwarded ipv4 allowed", cidrs: []string{"203.0.113.0/24"}, trustedProxies: loopback, forwardedFor: "203.0.113.7", expectedStatus: http.StatusOK},
+		{name: "forwarded ipv4 denied", cidrs: []string{"203.0.113.0/24"}, trustedProxies: loopback, forwardedFor: "198.51.100.7", expectedStatus: http.StatusForbidden},
+		{name: "spoofed leftmost address ignored", cidrs: []string{"203.0.113.0/24"}, trustedProxies: loopback, forwardedFor: "203.0.113.7, 198.51.100.7", expectedStatus: http.StatusForbidden},
+		{name: "trusted proxies skipped from the right", cidrs: []string{"203.0.113.0/24"}, trustedProxies: proxies, forwardedFor: "198.51.100.7, 203.0.113.7, 10.0.0.4", expectedStatus: http.StatusOK},
+		{name: "untrusted private address used", cidrs: []string{"192.168.0.0/16"}, trustedProxies: proxies, forwardedFor: "192.168.1.9, 10.0.0.4", expectedStatus: http.StatusOK},
+		{name: "forwarded ipv6 allowed", cidrs: []string{"2001:db8::/32"}, trustedProxies: loopback, forwardedFor: "2001:db8::1", expectedStatus: http.StatusOK},
+		{name: "forwarded ipv6 denied", cidrs: []string{"2001:db8::/32"}, trustedProxies: loopback, forwardedFor: "2001:4860::8888", expectedStatus: http.StatusForbidden},
+		{name: "invalid forwarded address stops at the proxy", cidrs: []string{"203.0.113.0/24"}, trustedProxies: loopback, forwardedFor: "203.0.113.7, not-an-ip", expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newAllowlistServer(t, tt.cidrs, tt.trustedProxies)
+
+			req, err := http.NewRequest(http.MethodGet, server.URL+"/api/admin/stats", nil)
+			require.NoError(t, err)
+			if tt.forwardedFor != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwardedFor)
+			}
+			resp, err := server.Client().Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			if tt.expectedStatus == http.StatusForbidden {
+				assert.Empty(t, body, "expected no body for denied requests")
+			}
+		})
+	}
+}
+
+// TestAdminOnlyIPAllowlist makes sure admin routes outside the admin router, like alerts, are held to the ip allowlist
+func TestAdminOnlyIPAllowlist(t *testing.T) {
+	tests := []struct {
+		name           string
+		cidrs          []string
+		expectedStatus int
+	}{
+		{name: "outside the allowlist", cidrs: []string{"203.0.113.0/24"}, expectedStatus: http.StatusForbidden},
+		{name: "inside the allowlist", cidrs: []string{"127.0.0.0/8"}, expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowlist, err := ParseIPAllowlist(tt.cidrs)
+			require.NoError(t, err)
+			s := &Service{Config: &Config{AdminIPAllowlist: allowlist}, Logger: otelzap.New(zap.NewNop())}
+
+			router := mux.NewRouter()
+			// stands in for userOnly with an admin session
+			adminUser := func(h http.HandlerFunc) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					ctx := context.WithValue(r.Context(), contextKeyUserID, testFacilitatorID)
+					ctx = context.WithValue(ctx, contextKeyUserType, thunderdome.AdminUserType)
+					h(w, r.WithContext(ctx))
+				}
+			}
+			router.HandleFunc("/api/alerts", adminUser(s.adminOnly(s.handleAlertCreate()))).Methods("POST")
+			server := httptest.NewServer(router)
+			t.Cleanup(server.Close)
+
+			resp, err := server.Client().Post(server.URL+"/api/alerts", "application/json", strings.NewReader("not json"))
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			if tt.expectedStatus == http.StatusForbidden {
+				assert.Empty(t, body, "expected no body for denied requests")
+			}
+		})
+	}
+}
+
+func TestParseIPAllowlist(t *testing.T) {
+	allowlist, err := ParseIPAllowlist([]string{"10.0.0.0/8", " 2001:db8::/32 ", ""})
+	require.NoError(t, err)
+	assert.Len(t, allowlist, 2)
+
+	_, err = ParseIPAllowlist([]string{"10.0.0.1"})
+	assert.Error(t, err, "expected a bare ip to be rejected")
+	_, err = ParseIPAllowlist([]string{"10.0.0.0/33"})
+	assert.Error(t, err)
+	_, err = ParseTrustedProxies([]string{"10.0.0.1"})
+	assert.Error(t, err)
+}
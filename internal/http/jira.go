@@ -10,6 +10,7 @@ import (
 
 	jira "github.com/StevenWeathers/thunderdome-planning-poker/internal/atlassian/jira"
 	jira_data_center "github.com/StevenWeathers/thunderdome-planning-poker/internal/atlassian/jiraDataCenter"
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/http/response"
 	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
 	"github.com/gorilla/mux"
 )
@@ -56,6 +57,8 @@ type jiraInstanceRequestBody struct {
 	ClientMail     string `json:"client_mail" validate:"required,email"`
 	AccessToken    string `json:"access_token" validate:"required"`
 	JiraDataCenter bool   `json:"jira_data_center"` // Checkbox for enabling Jira Data Center
+	// StoryPointsFieldID is the instance's story points custom field, estimates are exported to it
+	StoryPointsFieldID string `json:"story_points_field_id" validate:"omitempty,max=64"`
 }
 
 // handleJiraInstanceCreate creates a new Jira Instance
@@ -101,7 +104,7 @@ func (s *Service) handleJiraInstanceCreate() http.HandlerFunc {
 			return
 		}
 
-		instance, err := s.JiraDataSvc.CreateInstance(ctx, userID, req.Host, req.ClientMail, req.AccessToken, req.JiraDataCenter)
+		instance, err := s.JiraDataSvc.CreateInstance(ctx, userID, req.Host, req.ClientMail, req.AccessToken, req.JiraDataCenter, req.StoryPointsFieldID)
 		if err != nil {
 			s.Logger.Ctx(ctx).Error(
 				"handleJiraInstanceCreate error", zap.Error(err), zap.String("entity_user_id", userID),
@@ -166,7 +169,7 @@ func (s *Service) handleJiraInstanceUpdate() http.HandlerFunc {
 			return
 		}
 
-		instance, err := s.JiraDataSvc.UpdateInstance(ctx, instanceID, req.Host, req.ClientMail, req.AccessToken)
+		instance, err := s.JiraDataSvc.UpdateInstance(ctx, instanceID, req.Host, req.ClientMail, req.AccessToken, req.StoryPointsFieldID)
 		if err != nil {
 			s.Logger.Ctx(ctx).Error(
 				"handleJiraInstanceUpdate error", zap.Error(err), zap.String("entity_user_id", userID),
@@ -348,18 +351,7 @@ func (s *Service) logErrorWithJSONResponse(err error, errorTitle string, w http.
 	if err != nil {
 		s.createJiraLoggerStructure(err, errorTitle, ctx, vars, fields, req)
 
-		result := &standardJsonResponse{
-			Success: false,
-			Error:   err.Error(),
-			Data:    map[string]interface{}{},
-			Meta:    map[string]interface{}{},
-		}
-
-		response, _ := json.Marshal(result)
-
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		w.Write(response)
+		response.RespondError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
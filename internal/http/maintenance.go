@@ -37,7 +37,7 @@ func (s *Service) handleCleanPokerGames() http.HandlerFunc {
 // handleCleanRetros handles cleaning up old retros (ADMIN Manually Triggered)
 //
 //	@Summary		Clean Old Retros
-//	@Description	Deletes retros older than {config.cleanup_retros_days_old} based on last activity date
+//	@Description	Soft deletes retros older than {config.cleanup_retros_days_old} based on last activity date, they can be restored for 30 days
 //	@Tags			maintenance
 //	@Produce		json
 //	@Success		200	object	standardJsonResponse{}
@@ -117,3 +117,31 @@ func (s *Service) handleCleanGuests() http.HandlerFunc {
 		s.Success(w, r, http.StatusOK, nil, nil)
 	}
 }
+
+// handleCleanNotifications handles cleaning up old notifications (ADMIN Manually Triggered)
+//
+//	@Summary		Clean Old Notifications
+//	@Description	Deletes notifications older than {config.cleanup_notifications_days_old}
+//	@Tags			maintenance
+//	@Produce		json
+//	@Success		200	object	standardJsonResponse{}
+//	@Failure		500	object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/maintenance/clean-notifications [delete]
+func (s *Service) handleCleanNotifications() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		daysOld := s.Config.CleanupNotificationsDaysOld
+
+		err := s.NotificationDataSvc.DeleteOld(ctx, daysOld)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error(
+				"handleCleanNotifications error", zap.Error(err), zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, nil, nil)
+	}
+}
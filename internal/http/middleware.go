@@ -8,6 +8,7 @@ import (
 
 	"go.uber.org/zap"
 
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/http/response"
 	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
 
 	"github.com/gorilla/mux"
@@ -26,6 +27,36 @@ func (s *Service) panicRecovery(h http.Handler) http.Handler {
 	})
 }
 
+// apiVersion sets the running API version response header,
+// warning when it doesn't match the version the client expects
+func (s *Service) apiVersion(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		version := s.UIConfig.AppConfig.AppVersion
+		w.Header().Set(response.VersionHeader, version)
+
+		if acceptVersion := r.Header.Get(response.AcceptVersionHeader); acceptVersion != "" && acceptVersion != version {
+			s.Logger.Ctx(r.Context()).Warn("api version mismatch",
+				zap.String("api_version", version), zap.String("accept_version", acceptVersion),
+				zap.String("path", r.URL.Path))
+		}
+
+		h.ServeHTTP(w, r)
+	})
+}
+
+// apiKeyUsageEndpoint gets the endpoint an api key call is counted against,
+// using the route template so calls for different entities are counted together
+func apiKeyUsageEndpoint(r *http.Request) string {
+	path := r.URL.Path
+	if route := mux.CurrentRoute(r); route != nil {
+		if tpl, err := route.GetPathTemplate(); err == nil {
+			path = tpl
+		}
+	}
+
+	return r.Method + " " + path
+}
+
 // userOnly validates that the request was made by a valid user
 func (s *Service) userOnly(h http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -40,8 +71,12 @@ func (s *Service) userOnly(h http.HandlerFunc) http.HandlerFunc {
 				s.Failure(w, r, http.StatusUnauthorized, Errorf(EINVALID, "INVALID_APIKEY"))
 				return
 			}
+			if err := s.ApiKeyDataSvc.RecordAPIKeyUsage(ctx, apiKey, apiKeyUsageEndpoint(r)); err != nil {
+				s.Logger.Ctx(ctx).Error("record api key usage error", zap.Error(err),
+					zap.String("user_id", user.ID))
+			}
 		} else {
-			sessionID, cookieErr := s.Cookie.ValidateSessionCookie(w, r)
+			sessionID, issuedAt, cookieErr := s.Cookie.ValidateSessionCookieIssuedAt(w, r)
 			if cookieErr != nil && cookieErr.Error() != "COOKIE_NOT_FOUND" {
 				s.Failure(w, r, http.StatusUnauthorized, Errorf(EINVALID, "INVALID_USER"))
 				return
@@ -54,6 +89,20 @@ func (s *Service) userOnly(h http.HandlerFunc) http.HandlerFunc {
 					s.Failure(w, r, http.StatusUnauthorized, Errorf(EINVALID, "INVALID_USER"))
 					return
 				}
+
+				// sessions issued before the user's sessions were invalidated are logged out
+				invalidatedAt, invalidatedErr := s.UserDataSvc.GetSessionsInvalidatedAt(ctx, user.ID)
+				if invalidatedErr != nil {
+					s.Logger.Ctx(ctx).Error("get sessions invalidated at error", zap.Error(invalidatedErr),
+						zap.String("user_id", user.ID))
+					s.Failure(w, r, http.StatusInternalServerError, Errorf(EINTERNAL, "INTERNAL_ERROR"))
+					return
+				}
+				if invalidatedAt != nil && issuedAt.Before(*invalidatedAt) {
+					s.Cookie.ClearUserCookies(w)
+					s.Failure(w, r, http.StatusUnauthorized, Errorf(EINVALID, "SESSION_INVALIDATED"))
+					return
+				}
 			} else {
 				userID, err := s.Cookie.ValidateUserCookie(w, r)
 				if err != nil {
@@ -119,6 +168,12 @@ func (s *Service) adminOnly(h http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		userType := r.Context().Value(contextKeyUserType).(string)
 
+		// admin routes outside the admin router are held to the same ip allowlist
+		if !s.adminIPAllowed(r) {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
 		if userType != thunderdome.AdminUserType {
 			s.Failure(w, r, http.StatusForbidden, Errorf(EUNAUTHORIZED, "REQUIRES_ADMIN"))
 			return
@@ -516,3 +571,61 @@ func (s *Service) subscribedTeamOnly(h http.HandlerFunc) http.HandlerFunc {
 		h(w, r.WithContext(ctx))
 	}
 }
+
+// subscriptionRequired is the data of the SUBSCRIPTION_REQUIRED error response
+type subscriptionRequired struct {
+	RequiredTier thunderdome.SubscriptionTier `json:"required_tier"`
+}
+
+// requireSubscriptionTier validates that the request was made for an organization with at least the minimum subscription tier,
+// on team routes the team must belong to the organization (and department)
+func (s *Service) requireSubscriptionTier(minTier thunderdome.SubscriptionTier) func(http.HandlerFunc) http.HandlerFunc {
+	return func(h http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			vars := mux.Vars(r)
+			ctx := r.Context()
+			userType := ctx.Value(contextKeyUserType).(string)
+			orgID := vars["orgId"]
+			idErr := validate.Var(orgID, "required,uuid")
+			if idErr != nil {
+				s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+				return
+			}
+
+			if teamID := vars["teamId"]; teamID != "" {
+				// the tier is the team's own organization so the team must belong to the route's organization
+				teamRoles, ok := ctx.Value(contextKeyUserTeamRoles).(*thunderdome.UserTeamRoleInfo)
+				if !ok {
+					var err error
+					teamRoles, err = s.TeamDataSvc.TeamUserRolesByUserID(ctx, ctx.Value(contextKeyUserID).(string), teamID)
+					if err != nil {
+						s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, "TEAM_NOT_FOUND"))
+						return
+					}
+				}
+				if teamRoles.OrganizationID == nil || *teamRoles.OrganizationID != orgID ||
+					(vars["departmentId"] != "" && (teamRoles.DepartmentID == nil || *teamRoles.DepartmentID != vars["departmentId"])) {
+					s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, "TEAM_NOT_FOUND"))
+					return
+				}
+			}
+
+			if !s.Config.SubscriptionsEnabled || userType == thunderdome.AdminUserType {
+				h(w, r)
+				return
+			}
+
+			tier, err := s.SubscriptionDataSvc.GetOrgTier(ctx, orgID)
+			if err != nil {
+				s.Failure(w, r, http.StatusInternalServerError, Errorf(EINTERNAL, err.Error()))
+				return
+			}
+			if !tier.Meets(minTier) {
+				response.RespondErrorData(w, http.StatusPaymentRequired, "SUBSCRIPTION_REQUIRED", subscriptionRequired{RequiredTier: minTier})
+				return
+			}
+
+			h(w, r)
+		}
+	}
+}
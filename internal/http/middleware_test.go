@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap/zaptest/observer"
@@ -65,11 +66,19 @@ func (m *MockTeamDataSvc) TeamAddUser(ctx context.Context, TeamID string, UserID
 	panic("implement me")
 }
 
-func (m *MockTeamDataSvc) TeamUserList(ctx context.Context, TeamID string, Limit int, Offset int) ([]*thunderdome.TeamUser, int, error) {
+func (m *MockTeamDataSvc) TeamAddUserByNames(ctx context.Context, OrganizationID string, UserID string, TeamNames []string, Role string) error {
 	//TODO implement me
 	panic("implement me")
 }
 
+func (m *MockTeamDataSvc) TeamUserList(ctx context.Context, TeamID string, Limit int, Offset int) ([]*thunderdome.TeamUser, int, error) {
+	args := m.Called(ctx, TeamID, Limit, Offset)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*thunderdome.TeamUser), args.Int(1), args.Error(2)
+}
+
 func (m *MockTeamDataSvc) TeamUpdateUser(ctx context.Context, TeamID string, UserID string, Role string) (string, error) {
 	//TODO implement me
 	panic("implement me")
@@ -91,8 +100,8 @@ func (m *MockTeamDataSvc) TeamUserGetInviteByID(ctx context.Context, InviteID st
 }
 
 func (m *MockTeamDataSvc) TeamDeleteUserInvite(ctx context.Context, InviteID string) error {
-	//TODO implement me
-	panic("implement me")
+	args := m.Called(ctx, InviteID)
+	return args.Error(0)
 }
 
 func (m *MockTeamDataSvc) TeamGetUserInvites(ctx context.Context, teamID string) ([]thunderdome.TeamUserInvite, error) {
@@ -131,8 +140,8 @@ func (m *MockTeamDataSvc) TeamAddRetro(ctx context.Context, TeamID string, Retro
 }
 
 func (m *MockTeamDataSvc) TeamRemoveRetro(ctx context.Context, TeamID string, RetroID string) error {
-	//TODO implement me
-	panic("implement me")
+	args := m.Called(ctx, TeamID, RetroID)
+	return args.Error(0)
 }
 
 func (m *MockTeamDataSvc) TeamStoryboardList(ctx context.Context, TeamID string, Limit int, Offset int) []*thunderdome.Storyboard {
@@ -146,13 +155,13 @@ func (m *MockTeamDataSvc) TeamAddStoryboard(ctx context.Context, TeamID string,
 }
 
 func (m *MockTeamDataSvc) TeamRemoveStoryboard(ctx context.Context, TeamID string, StoryboardID string) error {
-	//TODO implement me
-	panic("implement me")
+	args := m.Called(ctx, TeamID, StoryboardID)
+	return args.Error(0)
 }
 
 func (m *MockTeamDataSvc) TeamList(ctx context.Context, Limit int, Offset int) ([]*thunderdome.Team, int) {
-	//TODO implement me
-	panic("implement me")
+	args := m.Called(ctx, Limit, Offset)
+	return args.Get(0).([]*thunderdome.Team), args.Int(1)
 }
 
 func (m *MockTeamDataSvc) TeamIsSubscribed(ctx context.Context, teamID string) (bool, error) {
@@ -165,6 +174,21 @@ func (m *MockTeamDataSvc) GetTeamMetrics(ctx context.Context, teamID string) (*t
 	panic("implement me")
 }
 
+func (m *MockTeamDataSvc) GetEstimationVelocityTrend(ctx context.Context, teamID string, granularity string, since time.Time, until time.Time) ([]thunderdome.VelocityDataPoint, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m *MockTeamDataSvc) GetRetroCadenceStats(ctx context.Context, teamID string) (*thunderdome.RetroCadenceStats, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m *MockTeamDataSvc) ComputeMemberActivityScores(ctx context.Context, teamID string, since time.Time) ([]thunderdome.MemberActivityScore, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
 func (m *MockTeamDataSvc) TeamUserRolesByUserID(ctx context.Context, userID, teamID string) (*thunderdome.UserTeamRoleInfo, error) {
 	args := m.Called(ctx, userID, teamID)
 	utr := args.Get(0).(thunderdome.UserTeamRoleInfo)
@@ -573,6 +597,16 @@ func (m *MockOrganizationDataService) OrganizationUpsertUser(ctx context.Context
 	panic("implement me")
 }
 
+func (m *MockOrganizationDataService) OrganizationUpdateSSORequired(ctx context.Context, OrgID string, SSORequired bool) (*thunderdome.Organization, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m *MockOrganizationDataService) SetDefaultEstimationScale(ctx context.Context, orgID string, scaleID string) error {
+	args := m.Called(ctx, orgID, scaleID)
+	return args.Error(0)
+}
+
 func (m *MockOrganizationDataService) OrganizationUpdateUser(ctx context.Context, OrgID string, UserID string, Role string) (string, error) {
 	//TODO implement me
 	panic("implement me")
@@ -633,6 +667,19 @@ func (m *MockOrganizationDataService) GetOrganizationMetrics(ctx context.Context
 	panic("implement me")
 }
 
+func (m *MockOrganizationDataService) GetActiveGamesForOrg(ctx context.Context, orgID string) ([]*thunderdome.Poker, error) {
+	args := m.Called(ctx, orgID)
+	return args.Get(0).([]*thunderdome.Poker), args.Error(1)
+}
+
+func (m *MockOrganizationDataService) OrganizationImportUsers(ctx context.Context, orgID string, rows []thunderdome.OrganizationMemberImportRow) ([]thunderdome.OrganizationMemberImportError, error) {
+	args := m.Called(ctx, orgID, rows)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]thunderdome.OrganizationMemberImportError), nil
+}
+
 func (m *MockOrganizationDataService) DepartmentUserRole(ctx context.Context, userID, orgID, departmentID string) (string, string, error) {
 	args := m.Called(ctx, userID, orgID, departmentID)
 	return args.String(0), args.String(1), args.Error(2)
@@ -1667,6 +1714,11 @@ func (m *MockSubscriptionDataService) CheckActiveSubscriber(ctx context.Context,
 	return args.Error(0)
 }
 
+func (m *MockSubscriptionDataService) GetOrgTier(ctx context.Context, orgID string) (thunderdome.SubscriptionTier, error) {
+	args := m.Called(ctx, orgID)
+	return args.Get(0).(thunderdome.SubscriptionTier), args.Error(1)
+}
+
 func TestVerifiedUserOnly(t *testing.T) {
 	tests := []struct {
 		name                      string
@@ -1928,6 +1980,19 @@ func (m *MockUserDataService) GetUserCredentialByUserID(ctx context.Context, Use
 	panic("implement me")
 }
 
+func (m *MockUserDataService) InvalidateAllSessions(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockUserDataService) GetSessionsInvalidatedAt(ctx context.Context, userID string) (*time.Time, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*time.Time), args.Error(1)
+}
+
 func (m *MockUserDataService) GetUserByID(ctx context.Context, userID string) (*thunderdome.User, error) {
 	args := m.Called(ctx, userID)
 	if args.Get(0) == nil {
@@ -1968,7 +2033,7 @@ func TestAdminOnly(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Create a new service
 			s := &Service{
-				// Add any necessary service configuration here
+				Config: &Config{},
 			}
 
 			// Define a dummy handler for testing
@@ -2221,3 +2286,238 @@ func TestPanicRecovery(t *testing.T) {
 		})
 	}
 }
+
+func TestRequireSubscriptionTier(t *testing.T) {
+	const orgID = "4f2d0d8e-8a55-4c8e-9b3f-1f6a3c1d2e7b"
+	tiers := []thunderdome.SubscriptionTier{
+		thunderdome.SubscriptionTierFree,
+		thunderdome.SubscriptionTierTeam,
+		thunderdome.SubscriptionTierEnterprise,
+	}
+
+	for _, minTier := range tiers {
+		for _, orgTier := range tiers {
+			t.Run(fmt.Sprintf("%s org requiring %s", orgTier, minTier), func(t *testing.T) {
+				mockSubDataSvc := new(MockSubscriptionDataService)
+				mockSubDataSvc.On("GetOrgTier", mock.Anything, orgID).Return(orgTier, nil).Once()
+				service := &Service{
+					SubscriptionDataSvc: mockSubDataSvc,
+					Config:              &Config{SubscriptionsEnabled: true},
+				}
+
+				handler := service.requireSubscriptionTier(minTier)(func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusOK)
+				})
+
+				req := httptest.NewRequest("GET", "/organizations/"+orgID+"/test", nil)
+				req = mux.SetURLVars(req, map[string]string{"orgId": orgID})
+				req = req.WithContext(context.WithValue(req.Context(), contextKeyUserType, thunderdome.RegisteredUserType))
+
+				rr := httptest.NewRecorder()
+				handler.ServeHTTP(rr, req)
+
+				if orgTier.Meets(minTier) {
+					assert.Equal(t, http.StatusOK, rr.Code)
+				} else {
+					assert.Equal(t, http.StatusPaymentRequired, rr.Code)
+
+					var body struct {
+						Error string `json:"error"`
+						Data  struct {
+							RequiredTier string `json:"required_tier"`
+						} `json:"data"`
+					}
+					require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+					assert.Equal(t, "SUBSCRIPTION_REQUIRED", body.Error)
+					assert.Equal(t, string(minTier), body.Data.RequiredTier)
+				}
+				mockSubDataSvc.AssertExpectations(t)
+			})
+		}
+	}
+}
+
+func TestRequireSubscriptionTierBypass(t *testing.T) {
+	const orgID = "4f2d0d8e-8a55-4c8e-9b3f-1f6a3c1d2e7b"
+	tests := []struct {
+		name                 string
+		userType             string
+		subscriptionsEnabled bool
+	}{
+		{name: "Subscriptions disabled", userType: thunderdome.RegisteredUserType, subscriptionsEnabled: false},
+		{name: "Admin user", userType: thunderdome.AdminUserType, subscriptionsEnabled: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSubDataSvc := new(MockSubscriptionDataService)
+			service := &Service{
+				SubscriptionDataSvc: mockSubDataSvc,
+				Config:              &Config{SubscriptionsEnabled: tt.subscriptionsEnabled},
+			}
+
+			handler := service.requireSubscriptionTier(thunderdome.SubscriptionTierEnterprise)(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest("GET", "/organizations/"+orgID+"/test", nil)
+			req = mux.SetURLVars(req, map[string]string{"orgId": orgID})
+			req = req.WithContext(context.WithValue(req.Context(), contextKeyUserType, tt.userType))
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+			mockSubDataSvc.AssertNotCalled(t, "GetOrgTier", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+// TestRequireSubscriptionTierTeamOrganization makes sure team routes use the tier of the team's own organization
+// and a team from another organization or department isn't found
+func TestRequireSubscriptionTierTeamOrganization(t *testing.T) {
+	const orgID = "4f2d0d8e-8a55-4c8e-9b3f-1f6a3c1d2e7b"
+	const otherOrgID = "5f2d0d8e-8a55-4c8e-9b3f-1f6a3c1d2e7b"
+	const departmentID = "6f2d0d8e-8a55-4c8e-9b3f-1f6a3c1d2e7b"
+	const teamID = "7f2d0d8e-8a55-4c8e-9b3f-1f6a3c1d2e7b"
+	const userID = "8f2d0d8e-8a55-4c8e-9b3f-1f6a3c1d2e7b"
+	teamOrgID := orgID
+	teamDepartmentID := departmentID
+
+	tests := []struct {
+		name           string
+		vars           map[string]string
+		teamRoles      bool
+		expectedStatus int
+	}{
+		{name: "team in the organization", vars: map[string]string{"orgId": orgID, "teamId": teamID}, teamRoles: true, expectedStatus: http.StatusOK},
+		{name: "team in another organization", vars: map[string]string{"orgId": otherOrgID, "teamId": teamID}, teamRoles: true, expectedStatus: http.StatusNotFound},
+		{name: "team in the department", vars: map[string]string{"orgId": orgID, "departmentId": departmentID, "teamId": teamID}, expectedStatus: http.StatusOK},
+		{name: "team in another department", vars: map[string]string{"orgId": orgID, "departmentId": teamID, "teamId": teamID}, expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			roles := thunderdome.UserTeamRoleInfo{TeamID: teamID, OrganizationID: &teamOrgID, DepartmentID: &teamDepartmentID}
+			mockTeamDataSvc := new(MockTeamDataSvc)
+			if !tt.teamRoles {
+				mockTeamDataSvc.On("TeamUserRolesByUserID", mock.Anything, userID, teamID).Return(roles, nil).Once()
+			}
+			mockSubDataSvc := new(MockSubscriptionDataService)
+			mockSubDataSvc.On("GetOrgTier", mock.Anything, orgID).Return(thunderdome.SubscriptionTierTeam, nil).Maybe()
+			service := &Service{
+				TeamDataSvc:         mockTeamDataSvc,
+				SubscriptionDataSvc: mockSubDataSvc,
+				Config:              &Config{SubscriptionsEnabled: true},
+				Logger:              otelzap.New(zap.NewNop()),
+			}
+
+			handler := service.requireSubscriptionTier(thunderdome.SubscriptionTierTeam)(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest("GET", "/organizations/"+tt.vars["orgId"]+"/teams/"+teamID+"/estimation-scales", nil)
+			req = mux.SetURLVars(req, tt.vars)
+			ctx := context.WithValue(req.Context(), contextKeyUserType, thunderdome.RegisteredUserType)
+			ctx = context.WithValue(ctx, contextKeyUserID, userID)
+			if tt.teamRoles {
+				ctx = context.WithValue(ctx, contextKeyUserTeamRoles, &roles)
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req.WithContext(ctx))
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusNotFound {
+				mockSubDataSvc.AssertNotCalled(t, "GetOrgTier", mock.Anything, mock.Anything)
+			}
+			mockTeamDataSvc.AssertExpectations(t)
+		})
+	}
+}
+
+// fakeSessionCookie is a session cookie issued at a given time
+type fakeSessionCookie struct {
+	CookieManager
+	sessionID string
+	issuedAt  time.Time
+	cleared   bool
+}
+
+func (f *fakeSessionCookie) ValidateSessionCookieIssuedAt(w http.ResponseWriter, r *http.Request) (string, time.Time, error) {
+	return f.sessionID, f.issuedAt, nil
+}
+
+func (f *fakeSessionCookie) ClearUserCookies(w http.ResponseWriter) {
+	f.cleared = true
+}
+
+func TestUserOnlySessionInvalidated(t *testing.T) {
+	const userID = "923e4567-e89b-12d3-a456-426614174000"
+	invalidatedAt := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name           string
+		issuedAt       time.Time
+		invalidatedAt  *time.Time
+		expectedStatus int
+	}{
+		{
+			name:           "Never Invalidated",
+			issuedAt:       time.Now(),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Issued After Invalidation",
+			issuedAt:       invalidatedAt.Add(time.Minute),
+			invalidatedAt:  &invalidatedAt,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Issued Before Invalidation",
+			issuedAt:       invalidatedAt.Add(-time.Minute),
+			invalidatedAt:  &invalidatedAt,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Legacy Cookie Without Issued At",
+			invalidatedAt:  &invalidatedAt,
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAuthDataSvc := new(MockAuthDataSvc)
+			mockUserDataSvc := new(MockUserDataService)
+			cookie := &fakeSessionCookie{sessionID: "session-id", issuedAt: tt.issuedAt}
+			s := &Service{
+				Config:      &Config{},
+				Cookie:      cookie,
+				AuthDataSvc: mockAuthDataSvc,
+				UserDataSvc: mockUserDataSvc,
+				Logger:      otelzap.New(zap.NewNop()),
+			}
+
+			mockAuthDataSvc.On("GetSessionUserByID", mock.Anything, "session-id").
+				Return(&thunderdome.User{ID: userID, Type: thunderdome.RegisteredUserType}, nil)
+			if tt.invalidatedAt != nil {
+				mockUserDataSvc.On("GetSessionsInvalidatedAt", mock.Anything, userID).Return(tt.invalidatedAt, nil)
+			} else {
+				mockUserDataSvc.On("GetSessionsInvalidatedAt", mock.Anything, userID).Return(nil, nil)
+			}
+
+			dummyHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest("GET", "/api/auth/user", nil)
+			rr := httptest.NewRecorder()
+			s.userOnly(dummyHandler).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Equal(t, tt.expectedStatus == http.StatusUnauthorized, cookie.cleared)
+			mockUserDataSvc.AssertExpectations(t)
+		})
+	}
+}
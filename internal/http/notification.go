@@ -0,0 +1,155 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/http/response"
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/wshub"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// NotifyUser creates an in-app notification for the user and sends it to the user's open websocket connections,
+// failures are logged as notifications shouldn't fail the action that triggered them
+func (s *Service) NotifyUser(ctx context.Context, userID string, notificationType string, payload any) {
+	if s.NotificationDataSvc == nil {
+		return
+	}
+	logger := s.Logger.Ctx(ctx)
+
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		logger.Error("notify user payload marshal error", zap.Error(err),
+			zap.String("user_id", userID), zap.String("notification_type", notificationType))
+		return
+	}
+
+	notification, err := s.NotificationDataSvc.CreateNotification(ctx, userID, notificationType, payloadJSON)
+	if err != nil {
+		logger.Error("notify user create notification error", zap.Error(err),
+			zap.String("user_id", userID), zap.String("notification_type", notificationType))
+		return
+	}
+
+	notificationJSON, _ := json.Marshal(notification)
+	msg := wshub.CreateSocketEvent("notification", string(notificationJSON), "")
+	for _, ws := range s.websocketServices {
+		ws.SendToUser(userID, msg)
+	}
+}
+
+// notifyTeamGameStarted notifies the team's users, other than the facilitator, that a poker game was started
+func (s *Service) notifyTeamGameStarted(ctx context.Context, teamID string, facilitatorID string, game *thunderdome.Poker) {
+	users, _, err := s.TeamDataSvc.TeamUserList(ctx, teamID, 1000, 0)
+	if err != nil {
+		s.Logger.Ctx(ctx).Error("notify team game started error", zap.Error(err),
+			zap.String("team_id", teamID), zap.String("poker_id", game.ID))
+		return
+	}
+
+	payload := map[string]string{
+		"teamId":    teamID,
+		"pokerId":   game.ID,
+		"pokerName": game.Name,
+	}
+	for _, user := range users {
+		if user.ID == facilitatorID {
+			continue
+		}
+		s.NotifyUser(ctx, user.ID, thunderdome.NotificationTypeGameStarted, payload)
+	}
+}
+
+// handleGetUserNotifications gets a list of the user's unread notifications
+//
+//	@Summary		Get User Notifications
+//	@Description	Get a list of the user's unread notifications newest first
+//	@Tags			notification
+//	@Produce		json
+//	@Param			userId	path	string	true	"the user ID"
+//	@Param			limit	query	int		false	"Max number of results to return"
+//	@Param			offset	query	int		false	"Starting point to return rows from, should be multiplied by limit or 0"
+//	@Success		200		object	standardJsonResponse{data=[]thunderdome.Notification}
+//	@Failure		403		object	standardJsonResponse{}
+//	@Failure		500		object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/users/{userId}/notifications [get]
+func (s *Service) handleGetUserNotifications() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		vars := mux.Vars(r)
+		userID := vars["userId"]
+		idErr := validate.Var(userID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		limit, offset := getLimitOffsetFromRequest(r)
+
+		notifications, count, err := s.NotificationDataSvc.ListUnread(ctx, userID, limit, offset)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleGetUserNotifications error", zap.Error(err),
+				zap.String("entity_user_id", userID), zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		meta := response.NewMeta(count, limit, offset)
+
+		s.Success(w, r, http.StatusOK, notifications, meta)
+	}
+}
+
+// handleMarkNotificationRead marks the user's notification as read
+//
+//	@Summary		Mark Notification Read
+//	@Description	Marks the user's notification as read
+//	@Tags			notification
+//	@Produce		json
+//	@Param			userId			path	string	true	"the user ID"
+//	@Param			notificationId	path	string	true	"the notification ID"
+//	@Success		200				object	standardJsonResponse{}
+//	@Failure		403				object	standardJsonResponse{}
+//	@Failure		404				object	standardJsonResponse{}
+//	@Failure		500				object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/users/{userId}/notifications/{notificationId}/read [put]
+func (s *Service) handleMarkNotificationRead() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		vars := mux.Vars(r)
+		userID := vars["userId"]
+		idErr := validate.Var(userID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+		notificationID := vars["notificationId"]
+		idErr = validate.Var(notificationID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		err := s.NotificationDataSvc.MarkAsRead(ctx, userID, notificationID)
+		if err != nil && err.Error() == "NOTIFICATION_NOT_FOUND" {
+			s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, "NOTIFICATION_NOT_FOUND"))
+			return
+		}
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleMarkNotificationRead error", zap.Error(err),
+				zap.String("entity_user_id", userID), zap.String("notification_id", notificationID),
+				zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, nil, nil)
+	}
+}
@@ -0,0 +1,179 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+// MockNotificationDataSvc is an in memory implementation of the NotificationDataSvc
+type MockNotificationDataSvc struct {
+	notifications []*thunderdome.Notification
+}
+
+func (m *MockNotificationDataSvc) CreateNotification(ctx context.Context, userID string, notificationType string, payload json.RawMessage) (*thunderdome.Notification, error) {
+	n := &thunderdome.Notification{
+		ID:        fmt.Sprintf("a23e4567-e89b-12d3-a456-%012d", len(m.notifications)),
+		UserID:    userID,
+		Type:      notificationType,
+		Payload:   payload,
+		CreatedAt: time.Now().Add(time.Duration(len(m.notifications)) * time.Second),
+	}
+	m.notifications = append(m.notifications, n)
+	return n, nil
+}
+
+func (m *MockNotificationDataSvc) MarkAsRead(ctx context.Context, userID string, notificationID string) error {
+	for _, n := range m.notifications {
+		if n.ID == notificationID && n.UserID == userID {
+			n.IsRead = true
+			return nil
+		}
+	}
+	return errors.New("NOTIFICATION_NOT_FOUND")
+}
+
+func (m *MockNotificationDataSvc) ListUnread(ctx context.Context, userID string, limit int, offset int) ([]*thunderdome.Notification, int, error) {
+	unread := make([]*thunderdome.Notification, 0)
+	for _, n := range m.notifications {
+		if n.UserID == userID && !n.IsRead {
+			unread = append(unread, n)
+		}
+	}
+	sort.Slice(unread, func(i, j int) bool {
+		return unread[i].CreatedAt.After(unread[j].CreatedAt)
+	})
+	return unread, len(unread), nil
+}
+
+func (m *MockNotificationDataSvc) DeleteOld(ctx context.Context, daysOld int) error {
+	return nil
+}
+
+// mockWebsocketService records the messages sent to users
+type mockWebsocketService struct {
+	sent map[string][][]byte
+}
+
+func (m *mockWebsocketService) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+func (m *mockWebsocketService) EvictIdleConnections(idleMinutes int) int {
+	return 0
+}
+
+func (m *mockWebsocketService) SendToUser(userID string, data []byte) {
+	m.sent[userID] = append(m.sent[userID], data)
+}
+
+const testNotificationUserID = "c23e4567-e89b-12d3-a456-426614174000"
+
+func listUnreadNotifications(t *testing.T, service *Service, userID string) []*thunderdome.Notification {
+	req := httptest.NewRequest("GET", "/users/"+userID+"/notifications", nil)
+	req = mux.SetURLVars(req, map[string]string{"userId": userID})
+	req = req.WithContext(context.WithValue(req.Context(), contextKeyUserID, userID))
+	rr := httptest.NewRecorder()
+	service.handleGetUserNotifications().ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var response struct {
+		Data []*thunderdome.Notification `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	return response.Data
+}
+
+// TestNotifyUser makes sure the notification is stored and sent to only the user's websocket connections
+func TestNotifyUser(t *testing.T) {
+	notificationDataSvc := &MockNotificationDataSvc{}
+	wsSvc := &mockWebsocketService{sent: make(map[string][][]byte)}
+	service := &Service{
+		Logger:              otelzap.New(zap.NewNop()),
+		NotificationDataSvc: notificationDataSvc,
+		websocketServices:   []websocketService{wsSvc},
+	}
+
+	service.NotifyUser(context.Background(), testNotificationUserID, thunderdome.NotificationTypeTeamInvite,
+		map[string]string{"teamId": testTeamID})
+
+	require.Len(t, notificationDataSvc.notifications, 1)
+	assert.JSONEq(t, `{"teamId":"`+testTeamID+`"}`, string(notificationDataSvc.notifications[0].Payload))
+	require.Len(t, wsSvc.sent, 1)
+	require.Len(t, wsSvc.sent[testNotificationUserID], 1)
+
+	var event struct {
+		Type  string `json:"type"`
+		Value string `json:"value"`
+	}
+	require.NoError(t, json.Unmarshal(wsSvc.sent[testNotificationUserID][0], &event))
+	assert.Equal(t, "notification", event.Type)
+	assert.Contains(t, event.Value, thunderdome.NotificationTypeTeamInvite)
+}
+
+// TestHandleMarkNotificationRead makes sure marking one notification read doesn't affect the others
+func TestHandleMarkNotificationRead(t *testing.T) {
+	notificationDataSvc := &MockNotificationDataSvc{}
+	service := &Service{
+		Logger:              otelzap.New(zap.NewNop()),
+		NotificationDataSvc: notificationDataSvc,
+	}
+	for _, notificationType := range []string{
+		thunderdome.NotificationTypeTeamInvite,
+		thunderdome.NotificationTypeActionAssigned,
+		thunderdome.NotificationTypeGameStarted,
+	} {
+		service.NotifyUser(context.Background(), testNotificationUserID, notificationType, nil)
+	}
+	require.Len(t, listUnreadNotifications(t, service, testNotificationUserID), 3)
+	readID := notificationDataSvc.notifications[1].ID
+
+	req := httptest.NewRequest("PUT", "/users/"+testNotificationUserID+"/notifications/"+readID+"/read", nil)
+	req = mux.SetURLVars(req, map[string]string{"userId": testNotificationUserID, "notificationId": readID})
+	req = req.WithContext(context.WithValue(req.Context(), contextKeyUserID, testNotificationUserID))
+	rr := httptest.NewRecorder()
+	service.handleMarkNotificationRead().ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	unread := listUnreadNotifications(t, service, testNotificationUserID)
+	require.Len(t, unread, 2)
+	for _, n := range unread {
+		assert.NotEqual(t, readID, n.ID)
+		assert.False(t, n.IsRead)
+	}
+	assert.Equal(t, thunderdome.NotificationTypeGameStarted, unread[0].Type)
+	assert.Equal(t, thunderdome.NotificationTypeTeamInvite, unread[1].Type)
+}
+
+// TestHandleMarkNotificationReadNotFound makes sure users can't mark another user's notification as read
+func TestHandleMarkNotificationReadNotFound(t *testing.T) {
+	notificationDataSvc := &MockNotificationDataSvc{}
+	service := &Service{
+		Logger:              otelzap.New(zap.NewNop()),
+		NotificationDataSvc: notificationDataSvc,
+	}
+	service.NotifyUser(context.Background(), testNotificationUserID, thunderdome.NotificationTypeGameStarted, nil)
+	notificationID := notificationDataSvc.notifications[0].ID
+
+	req := httptest.NewRequest("PUT", "/users/"+testParticipantID+"/notifications/"+notificationID+"/read", nil)
+	req = mux.SetURLVars(req, map[string]string{"userId": testParticipantID, "notificationId": notificationID})
+	req = req.WithContext(context.WithValue(req.Context(), contextKeyUserID, testParticipantID))
+	rr := httptest.NewRecorder()
+	service.handleMarkNotificationRead().ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	assert.Len(t, listUnreadNotifications(t, service, testNotificationUserID), 1)
+}
@@ -0,0 +1,331 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/http/poker"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// handleGetUserNotionConnections gets a list of notion connections associated to user
+//
+//	@Summary		Get User Notion Connections
+//	@Description	get list of Notion connections associated to user
+//	@Tags			notion
+//	@Produce		json
+//	@Param			userId	path	string	true	"the user ID to find notion connections for"
+//	@Success		200		object	standardJsonResponse{data=[]thunderdome.NotionConnection}
+//	@Failure		500		object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/users/{userId}/notion [get]
+func (s *Service) handleGetUserNotionConnections() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		vars := mux.Vars(r)
+		userID := vars["userId"]
+
+		idErr := validate.Var(userID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		connections, err := s.NotionDataSvc.FindConnectionsByUserID(ctx, userID)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error(
+				"handleGetUserNotionConnections error", zap.Error(err), zap.String("entity_user_id", userID),
+				zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, connections, nil)
+	}
+}
+
+type notionConnectionRequestBody struct {
+	Name        string `json:"name" validate:"required,max=256"`
+	AccessToken string `json:"access_token" validate:"required"`
+	// DescriptionProperty is the name of the database property imported as the story description
+	DescriptionProperty string `json:"description_property" validate:"max=256"`
+}
+
+// handleNotionConnectionCreate creates a new Notion Connection
+//
+//	@Summary		Create Notion Connection
+//	@Description	Creates a Notion Connection associated to user
+//	@Tags			notion
+//	@Produce		json
+//	@Param			userId	path	string													true	"the user ID to associate notion connection to"
+//	@Param			notion	body	notionConnectionRequestBody								true	"new notion_connection object"
+//	@Success		200		object	standardJsonResponse{data=thunderdome.NotionConnection}	"returns new notion connection"
+//	@Failure		500		object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/users/{userId}/notion [post]
+func (s *Service) handleNotionConnectionCreate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		vars := mux.Vars(r)
+		userID := vars["userId"]
+
+		idErr := validate.Var(userID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		var req = notionConnectionRequestBody{}
+		body, bodyErr := io.ReadAll(r.Body)
+		if bodyErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, bodyErr.Error()))
+			return
+		}
+
+		jsonErr := json.Unmarshal(body, &req)
+		if jsonErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, jsonErr.Error()))
+			return
+		}
+
+		inputErr := validate.Struct(req)
+		if inputErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, inputErr.Error()))
+			return
+		}
+
+		connection, err := s.NotionDataSvc.CreateConnection(ctx, userID, req.Name, req.AccessToken, req.DescriptionProperty)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error(
+				"handleNotionConnectionCreate error", zap.Error(err), zap.String("entity_user_id", userID),
+				zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, connection, nil)
+	}
+}
+
+// handleNotionConnectionUpdate updates a Notion Connection
+//
+//	@Summary		Update Notion Connection
+//	@Description	Updates a Notion Connection associated to user
+//	@Tags			notion
+//	@Produce		json
+//	@Param			userId			path	string													true	"the user ID notion connection associated to"
+//	@Param			connectionId	path	string													true	"the notion_connection ID to update"
+//	@Param			notion			body	notionConnectionRequestBody								true	"updated notion_connection object"
+//	@Success		200				object	standardJsonResponse{data=thunderdome.NotionConnection}	"returns updated notion connection"
+//	@Failure		404				object	standardJsonResponse{}
+//	@Failure		500				object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/users/{userId}/notion/{connectionId} [put]
+func (s *Service) handleNotionConnectionUpdate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		vars := mux.Vars(r)
+		userID := vars["userId"]
+		connectionID := vars["connectionId"]
+
+		cidErr := validate.Var(connectionID, "required,uuid")
+		if cidErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, cidErr.Error()))
+			return
+		}
+
+		idErr := validate.Var(userID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		var req = notionConnectionRequestBody{}
+		body, bodyErr := io.ReadAll(r.Body)
+		if bodyErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, bodyErr.Error()))
+			return
+		}
+
+		jsonErr := json.Unmarshal(body, &req)
+		if jsonErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, jsonErr.Error()))
+			return
+		}
+
+		inputErr := validate.Struct(req)
+		if inputErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, inputErr.Error()))
+			return
+		}
+
+		existing, err := s.NotionDataSvc.GetConnectionByID(ctx, connectionID)
+		if err != nil || existing.UserID != userID {
+			s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, "NOTION_CONNECTION_NOT_FOUND"))
+			return
+		}
+
+		connection, err := s.NotionDataSvc.UpdateConnection(ctx, connectionID, req.Name, req.AccessToken, req.DescriptionProperty)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error(
+				"handleNotionConnectionUpdate error", zap.Error(err), zap.String("entity_user_id", userID),
+				zap.String("session_user_id", sessionUserID), zap.String("notion_connection_id", connectionID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, connection, nil)
+	}
+}
+
+// handleNotionConnectionDelete deletes a Notion Connection
+//
+//	@Summary		Delete Notion Connection
+//	@Description	Deletes a Notion Connection associated to user
+//	@Tags			notion
+//	@Produce		json
+//	@Param			userId			path	string	true	"the user ID notion connection associated to"
+//	@Param			connectionId	path	string	true	"the notion_connection ID to delete"
+//	@Success		200				object	standardJsonResponse{}
+//	@Failure		404				object	standardJsonResponse{}
+//	@Failure		500				object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/users/{userId}/notion/{connectionId} [delete]
+func (s *Service) handleNotionConnectionDelete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		vars := mux.Vars(r)
+		userID := vars["userId"]
+		connectionID := vars["connectionId"]
+
+		cidErr := validate.Var(connectionID, "required,uuid")
+		if cidErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, cidErr.Error()))
+			return
+		}
+
+		idErr := validate.Var(userID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		existing, err := s.NotionDataSvc.GetConnectionByID(ctx, connectionID)
+		if err != nil || existing.UserID != userID {
+			s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, "NOTION_CONNECTION_NOT_FOUND"))
+			return
+		}
+
+		err = s.NotionDataSvc.DeleteConnection(ctx, connectionID)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error(
+				"handleNotionConnectionDelete error", zap.Error(err), zap.String("entity_user_id", userID),
+				zap.String("session_user_id", sessionUserID), zap.String("notion_connection_id", connectionID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, nil, nil)
+	}
+}
+
+type notionImportRequestBody struct {
+	ConnectionID string                    `json:"connectionId" validate:"required,uuid"`
+	DatabaseID   string                    `json:"databaseId" validate:"required,max=64"`
+	Filter       *thunderdome.NotionFilter `json:"filter"`
+}
+
+// handlePokerNotionImport handles importing the pages of a Notion database as poker stories
+//
+//	@Summary		Import Poker Stories from Notion
+//	@Description	Imports the pages of a Notion database as poker stories, optionally filtered by a database property,
+//	@Description	stories with a referenceId already in the game are updated instead of duplicated
+//	@Param			battleId	path	string					true	"the poker game ID"
+//	@Param			notion		body	notionImportRequestBody	true	"notion database to import"
+//	@Tags			poker
+//	@Produce		json
+//	@Success		200	object	standardJsonResponse{data=thunderdome.DuplicationResult}
+//	@Success		403	object	standardJsonResponse{}
+//	@Success		404	object	standardJsonResponse{}
+//	@Failure		422	object	standardJsonResponse{data=[]thunderdome.ValidationError}
+//	@Success		500	object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/battles/{battleId}/plans/import/notion [post]
+func (s *Service) handlePokerNotionImport(pokerSvc *poker.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		gameID := vars["battleId"]
+		idErr := validate.Var(gameID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+
+		body, bodyErr := io.ReadAll(r.Body)
+		if bodyErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, bodyErr.Error()))
+			return
+		}
+
+		var req = notionImportRequestBody{}
+		jsonErr := json.Unmarshal(body, &req)
+		if jsonErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, jsonErr.Error()))
+			return
+		}
+
+		inputErr := validate.Struct(req)
+		if inputErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, inputErr.Error()))
+			return
+		}
+
+		connection, err := s.NotionDataSvc.GetConnectionByID(ctx, req.ConnectionID)
+		if err != nil || connection.UserID != sessionUserID {
+			s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, "NOTION_CONNECTION_NOT_FOUND"))
+			return
+		}
+
+		stories, err := s.NotionDataSvc.ImportPagesFromDatabase(ctx, req.ConnectionID, req.DatabaseID, req.Filter)
+		if err != nil && err.Error() == "NOTION_FILTER_INVALID_CHECKBOX" {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, err.Error()))
+			return
+		}
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handlePokerNotionImport notion pages error", zap.Error(err),
+				zap.String("poker_id", gameID), zap.String("session_user_id", sessionUserID),
+				zap.String("notion_connection_id", req.ConnectionID), zap.String("notion_database_id", req.DatabaseID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		if len(stories) == 0 {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "NOTION_DATABASE_HAS_NO_PAGES"))
+			return
+		}
+
+		if s.storyLinksInvalid(w, stories) {
+			return
+		}
+
+		result, err := pokerSvc.ImportStories(ctx, gameID, sessionUserID, stories, s.Config.ImportDeduplicationEnabled)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handlePokerNotionImport error", zap.Error(err),
+				zap.String("poker_id", gameID), zap.String("session_user_id", sessionUserID),
+				zap.Int("story_count", len(stories)))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, result, nil)
+	}
+}
@@ -7,9 +7,12 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"time"
 
 	"go.uber.org/zap"
 
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/http/response"
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/webhook/subscription"
 	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
 
 	"github.com/gorilla/mux"
@@ -227,6 +230,150 @@ func (s *Service) handleOrganizationUpdate() http.HandlerFunc {
 	}
 }
 
+type organizationSSORequestBody struct {
+	SSORequired bool `json:"ssoRequired"`
+}
+
+// handleOrganizationSSOUpdate handles updating whether an organization requires SSO authentication
+//
+//	@Summary		Update Organization SSO
+//	@Description	Update whether organization members are required to authenticate via SSO
+//	@Tags			organization
+//	@Produce		json
+//	@Param			orgId			path	string						true	"organization id"
+//	@Param			organization	body	organizationSSORequestBody	true	"organization sso object"
+//	@Success		200				object	standardJsonResponse{data=thunderdome.Organization}
+//	@Failure		403				object	standardJsonResponse{}
+//	@Failure		500				object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/organizations/{orgId}/sso [put]
+func (s *Service) handleOrganizationSSOUpdate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.Config.OrganizationsEnabled {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "ORGANIZATIONS_DISABLED"))
+			return
+		}
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		vars := mux.Vars(r)
+		orgID := vars["orgId"]
+		idErr := validate.Var(orgID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		var sso = organizationSSORequestBody{}
+		body, bodyErr := io.ReadAll(r.Body)
+		if bodyErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, bodyErr.Error()))
+			return
+		}
+
+		jsonErr := json.Unmarshal(body, &sso)
+		if jsonErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, jsonErr.Error()))
+			return
+		}
+
+		organization, err := s.OrganizationDataSvc.OrganizationUpdateSSORequired(ctx, orgID, sso.SSORequired)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error(
+				"handleOrganizationSSOUpdate error", zap.Error(err),
+				zap.String("organization_id", orgID),
+				zap.String("session_user_id", sessionUserID),
+				zap.Bool("sso_required", sso.SSORequired))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, organization, nil)
+	}
+}
+
+type organizationDefaultScaleRequestBody struct {
+	EstimationScaleID string `json:"estimationScaleId" validate:"omitempty,uuid"`
+}
+
+// handleOrganizationDefaultScaleUpdate handles setting the organization's default estimation scale
+//
+//	@Summary		Update Organization Default Scale
+//	@Description	Sets the estimation scale used by the organization's games when created without one,
+//	@Description	the scale must belong to the organization or be public, an empty estimationScaleId clears it
+//	@Tags			organization
+//	@Produce		json
+//	@Param			orgId	path	string								true	"organization id"
+//	@Param			scale	body	organizationDefaultScaleRequestBody	true	"default scale object"
+//	@Success		200		object	standardJsonResponse{data=thunderdome.Organization}
+//	@Failure		400		object	standardJsonResponse{}
+//	@Failure		403		object	standardJsonResponse{}
+//	@Failure		500		object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/organizations/{orgId}/default-scale [put]
+func (s *Service) handleOrganizationDefaultScaleUpdate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.Config.OrganizationsEnabled {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "ORGANIZATIONS_DISABLED"))
+			return
+		}
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		vars := mux.Vars(r)
+		orgID := vars["orgId"]
+		idErr := validate.Var(orgID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		var ds = organizationDefaultScaleRequestBody{}
+		body, bodyErr := io.ReadAll(r.Body)
+		if bodyErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, bodyErr.Error()))
+			return
+		}
+
+		jsonErr := json.Unmarshal(body, &ds)
+		if jsonErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, jsonErr.Error()))
+			return
+		}
+
+		inputErr := validate.Struct(ds)
+		if inputErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, inputErr.Error()))
+			return
+		}
+
+		err := s.OrganizationDataSvc.SetDefaultEstimationScale(ctx, orgID, ds.EstimationScaleID)
+		if err != nil {
+			if err.Error() == "ESTIMATION_SCALE_NOT_FOUND" {
+				s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, err.Error()))
+				return
+			}
+			s.Logger.Ctx(ctx).Error(
+				"handleOrganizationDefaultScaleUpdate error", zap.Error(err),
+				zap.String("organization_id", orgID),
+				zap.String("session_user_id", sessionUserID),
+				zap.String("estimation_scale_id", ds.EstimationScaleID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		organization, err := s.OrganizationDataSvc.OrganizationGetByID(ctx, orgID)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error(
+				"handleOrganizationDefaultScaleUpdate error", zap.Error(err),
+				zap.String("organization_id", orgID),
+				zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, organization, nil)
+	}
+}
+
 // handleGetOrganizationTeams gets a list of teams associated to the organization
 //
 //	@Summary		Get Organization Teams
@@ -260,6 +407,124 @@ func (s *Service) handleGetOrganizationTeams() http.HandlerFunc {
 	}
 }
 
+// handleGetOrganizationActiveGames gets a list of the organization teams poker games with active users
+//
+//	@Summary		Get Organization Active Games
+//	@Description	Get a list of the organization teams poker games that currently have active users, most active first
+//	@Tags			organization
+//	@Produce		json
+//	@Param			orgId	path	string	true	"organization id"
+//	@Param			team_id	query	string	false	"only games of the team"
+//	@Param			limit	query	int		false	"Max number of results to return"
+//	@Param			offset	query	int		false	"Starting point to return rows from, should be multiplied by limit or 0"
+//	@Success		200		object	standardJsonResponse{data=[]thunderdome.Poker}
+//	@Failure		400		object	standardJsonResponse{}
+//	@Failure		403		object	standardJsonResponse{}
+//	@Failure		500		object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/organizations/{orgId}/active-games [get]
+func (s *Service) handleGetOrganizationActiveGames() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.Config.OrganizationsEnabled {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "ORGANIZATIONS_DISABLED"))
+			return
+		}
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		vars := mux.Vars(r)
+		orgID := vars["orgId"]
+		idErr := validate.Var(orgID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+		teamID := r.URL.Query().Get("team_id")
+		if teamID != "" {
+			if teamIDErr := validate.Var(teamID, "uuid"); teamIDErr != nil {
+				s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, teamIDErr.Error()))
+				return
+			}
+		}
+		limit, offset := getLimitOffsetFromRequest(r)
+
+		games, err := s.OrganizationDataSvc.GetActiveGamesForOrg(ctx, orgID)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleGetOrganizationActiveGames error", zap.Error(err),
+				zap.String("organization_id", orgID), zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		if teamID != "" {
+			teamGames := make([]*thunderdome.Poker, 0, len(games))
+			for _, game := range games {
+				if game.TeamID == teamID {
+					teamGames = append(teamGames, game)
+				}
+			}
+			games = teamGames
+		}
+
+		count := len(games)
+		start := min(offset, count)
+		end := min(start+limit, count)
+
+		s.Success(w, r, http.StatusOK, games[start:end], response.NewMeta(count, limit, offset))
+	}
+}
+
+// handleGetOrganizationEstimationCalibration gets the organization estimation calibration report
+//
+//	@Summary		Get Organization Estimation Calibration
+//	@Description	Get how the organization teams have been using their estimation scales, including the weekly consensus trend
+//	@Tags			organization
+//	@Produce		json
+//	@Param			orgId	path	string	true	"organization id"
+//	@Param			since	query	string	false	"the first day to include (YYYY-MM-DD), defaults to 12 weeks ago"
+//	@Success		200		object	standardJsonResponse{data=thunderdome.CalibrationReport}
+//	@Failure		400		object	standardJsonResponse{}
+//	@Failure		403		object	standardJsonResponse{}
+//	@Failure		500		object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/organizations/{orgId}/estimation-calibration [get]
+func (s *Service) handleGetOrganizationEstimationCalibration() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.Config.OrganizationsEnabled {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "ORGANIZATIONS_DISABLED"))
+			return
+		}
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		vars := mux.Vars(r)
+		orgID := vars["orgId"]
+		idErr := validate.Var(orgID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		since := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -7*12)
+		if v := r.URL.Query().Get("since"); v != "" {
+			d, err := time.Parse(time.DateOnly, v)
+			if err != nil {
+				s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "INVALID_DATE"))
+				return
+			}
+			since = d
+		}
+
+		report, err := s.AdminDataSvc.GetEstimationCalibration(ctx, orgID, since)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleGetOrganizationEstimationCalibration error", zap.Error(err),
+				zap.String("organization_id", orgID), zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, report, nil)
+	}
+}
+
 // handleGetOrganizationUsers gets a list of users associated to the organization
 //
 //	@Summary		Get Organization Users
@@ -404,6 +669,7 @@ func (s *Service) handleOrganizationInviteUser() http.HandlerFunc {
 					s.Failure(w, r, http.StatusInternalServerError, err)
 					return
 				}
+				s.recordUsage(ctx, orgID, subscription.UsageEventUserAdded)
 				s.Success(w, r, http.StatusOK, nil, userAddMeta{Invited: false, Added: true})
 				return
 			} else if userErr != nil && !errors.Is(userErr, sql.ErrNoRows) {
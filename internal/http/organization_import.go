@@ -0,0 +1,191 @@
+package http
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/webhook/subscription"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+const (
+	// orgMemberImportMaxRows caps the members imported in a single file
+	orgMemberImportMaxRows = 500
+	// orgMemberImportMaxBytes comfortably fits the max rows of emails and roles
+	orgMemberImportMaxBytes = 1 << 20
+)
+
+var (
+	errOrgMemberImportMissingEmail = errors.New("MISSING_EMAIL_COLUMN")
+	errOrgMemberImportTooManyRows  = errors.New("TOO_MANY_ROWS")
+)
+
+// parseOrgMemberImportCSV parses the member import csv, the header row must have an email column
+// and may have a role column, members without a role are added as a MEMBER
+func parseOrgMemberImportCSV(file io.Reader) ([]thunderdome.OrganizationMemberImportRow, error) {
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errOrgMemberImportMissingEmail
+	}
+	if err != nil {
+		return nil, fmt.Errorf("INVALID_CSV: %v", err)
+	}
+
+	emailCol, roleCol := -1, -1
+	for i, col := range header {
+		switch strings.ToLower(strings.TrimSpace(strings.TrimPrefix(col, "\ufeff"))) {
+		case "email":
+			emailCol = i
+		case "role":
+			roleCol = i
+		}
+	}
+	if emailCol == -1 {
+		return nil, errOrgMemberImportMissingEmail
+	}
+
+	rows := make([]thunderdome.OrganizationMemberImportRow, 0)
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("INVALID_CSV: %v", err)
+		}
+		if len(rows) == orgMemberImportMaxRows {
+			return nil, errOrgMemberImportTooManyRows
+		}
+
+		row := thunderdome.OrganizationMemberImportRow{Row: line}
+		if emailCol < len(record) {
+			row.Email = strings.ToLower(strings.TrimSpace(record[emailCol]))
+		}
+		if roleCol != -1 && roleCol < len(record) {
+			row.Role = strings.ToUpper(strings.TrimSpace(record[roleCol]))
+		}
+		if row.Role == "" {
+			row.Role = thunderdome.EntityMemberUserType
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// validateOrgMemberImportRows splits the rows into those that can be imported and the failures
+// for invalid emails, roles, and emails repeated within the file
+func validateOrgMemberImportRows(rows []thunderdome.OrganizationMemberImportRow) ([]thunderdome.OrganizationMemberImportRow, []thunderdome.OrganizationMemberImportError) {
+	valid := make([]thunderdome.OrganizationMemberImportRow, 0, len(rows))
+	failures := make([]thunderdome.OrganizationMemberImportError, 0)
+	seen := make(map[string]bool)
+
+	for _, row := range rows {
+		reason := ""
+		switch {
+		case validate.Var(row.Email, "required,email") != nil:
+			reason = "INVALID_EMAIL"
+		case validate.Var(row.Role, "oneof=MEMBER ADMIN") != nil:
+			reason = "INVALID_ROLE"
+		case seen[row.Email]:
+			reason = "DUPLICATE_EMAIL"
+		}
+		if reason != "" {
+			failures = append(failures, thunderdome.OrganizationMemberImportError{
+				Row: row.Row, Email: row.Email, Reason: reason,
+			})
+			continue
+		}
+
+		seen[row.Email] = true
+		valid = append(valid, row)
+	}
+
+	return valid, failures
+}
+
+// handleOrganizationImportUsers handles bulk importing organization members from a csv file
+//
+//	@Summary		Import Org Users
+//	@Description	Bulk import organization members from a csv file with email and role columns,
+//	@Description	users without an account are created as guests, max 500 rows
+//	@Tags			organization
+//	@Accept			mpfd
+//	@Produce		json
+//	@Param			orgId	path		string	true	"organization id"
+//	@Param			file	formData	file	true	"members csv file"
+//	@Success		200		object		standardJsonResponse{data=thunderdome.OrganizationMemberImportResult}
+//	@Failure		400		object		standardJsonResponse{}
+//	@Failure		403		object		standardJsonResponse{}
+//	@Failure		500		object		standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/organizations/{orgId}/members/import [post]
+func (s *Service) handleOrganizationImportUsers() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.Config.OrganizationsEnabled {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "ORGANIZATIONS_DISABLED"))
+			return
+		}
+		if !s.Config.OrgBulkImportEnabled {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "ORG_BULK_IMPORT_DISABLED"))
+			return
+		}
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		vars := mux.Vars(r)
+		orgID := vars["orgId"]
+		idErr := validate.Var(orgID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, orgMemberImportMaxBytes)
+		file, _, fileErr := r.FormFile("file")
+		if fileErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "INVALID_FILE"))
+			return
+		}
+		defer file.Close()
+
+		rows, parseErr := parseOrgMemberImportCSV(file)
+		if parseErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, parseErr.Error()))
+			return
+		}
+
+		validRows, failures := validateOrgMemberImportRows(rows)
+		if len(validRows) > 0 {
+			importFailures, err := s.OrganizationDataSvc.OrganizationImportUsers(ctx, orgID, validRows)
+			if err != nil {
+				s.Logger.Ctx(ctx).Error("handleOrganizationImportUsers error", zap.Error(err),
+					zap.String("organization_id", orgID), zap.String("session_user_id", sessionUserID))
+				s.Failure(w, r, http.StatusInternalServerError, err)
+				return
+			}
+			failures = append(failures, importFailures...)
+		}
+
+		result := thunderdome.OrganizationMemberImportResult{
+			Total:     len(rows),
+			Succeeded: len(rows) - len(failures),
+			Failed:    len(failures),
+			Errors:    failures,
+		}
+		for i := 0; i < result.Succeeded; i++ {
+			s.recordUsage(ctx, orgID, subscription.UsageEventUserAdded)
+		}
+
+		s.Success(w, r, http.StatusOK, result, nil)
+	}
+}
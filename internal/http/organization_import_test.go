@@ -0,0 +1,155 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+func newOrgMemberImportRequest(t *testing.T, csvContent string) *http.Request {
+	t.Helper()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", "members.csv")
+	assert.NoError(t, err)
+	_, err = part.Write([]byte(csvContent))
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+
+	req := httptest.NewRequest("POST", "/organizations/"+testOrgID+"/members/import", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req = mux.SetURLVars(req, map[string]string{"orgId": testOrgID})
+	ctx := context.WithValue(req.Context(), contextKeyUserID, testFacilitatorID)
+
+	return req.WithContext(ctx)
+}
+
+func newOrgMemberImportService(mockOrgDataSvc *MockOrganizationDataService) *Service {
+	return &Service{
+		Config:              &Config{OrganizationsEnabled: true, OrgBulkImportEnabled: true},
+		OrganizationDataSvc: mockOrgDataSvc,
+		Logger:              otelzap.New(zap.NewNop()),
+	}
+}
+
+func decodeOrgMemberImportResult(t *testing.T, rr *httptest.ResponseRecorder) thunderdome.OrganizationMemberImportResult {
+	t.Helper()
+
+	var response struct {
+		Data thunderdome.OrganizationMemberImportResult `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+
+	return response.Data
+}
+
+func TestHandleOrganizationImportUsersMissingEmailColumn(t *testing.T) {
+	mockOrgDataSvc := new(MockOrganizationDataService)
+	service := newOrgMemberImportService(mockOrgDataSvc)
+
+	rr := httptest.NewRecorder()
+	service.handleOrganizationImportUsers().ServeHTTP(rr,
+		newOrgMemberImportRequest(t, "name,role\nJane,ADMIN\n"))
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "MISSING_EMAIL_COLUMN")
+	mockOrgDataSvc.AssertNotCalled(t, "OrganizationImportUsers", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHandleOrganizationImportUsersTooManyRows(t *testing.T) {
+	mockOrgDataSvc := new(MockOrganizationDataService)
+	service := newOrgMemberImportService(mockOrgDataSvc)
+
+	var csvContent strings.Builder
+	csvContent.WriteString("email,role\n")
+	for i := 0; i <= orgMemberImportMaxRows; i++ {
+		csvContent.WriteString(fmt.Sprintf("user%d@example.com,MEMBER\n", i))
+	}
+
+	rr := httptest.NewRecorder()
+	service.handleOrganizationImportUsers().ServeHTTP(rr, newOrgMemberImportRequest(t, csvContent.String()))
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "TOO_MANY_ROWS")
+	mockOrgDataSvc.AssertNotCalled(t, "OrganizationImportUsers", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHandleOrganizationImportUsersDuplicateMembers(t *testing.T) {
+	mockOrgDataSvc := new(MockOrganizationDataService)
+	expectedRows := []thunderdome.OrganizationMemberImportRow{
+		{Row: 2, Email: "jane@example.com", Role: "ADMIN"},
+		{Row: 3, Email: "existing@example.com", Role: "MEMBER"},
+	}
+	mockOrgDataSvc.On("OrganizationImportUsers", mock.Anything, testOrgID, expectedRows).Return(
+		[]thunderdome.OrganizationMemberImportError{{Row: 3, Email: "existing@example.com", Reason: "ALREADY_MEMBER"}}, nil)
+	service := newOrgMemberImportService(mockOrgDataSvc)
+
+	rr := httptest.NewRecorder()
+	service.handleOrganizationImportUsers().ServeHTTP(rr, newOrgMemberImportRequest(t,
+		"email,role\nJane@Example.com,admin\nexisting@example.com,\njane@example.com,MEMBER\n"))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockOrgDataSvc.AssertExpectations(t)
+	result := decodeOrgMemberImportResult(t, rr)
+	assert.Equal(t, 3, result.Total)
+	assert.Equal(t, 1, result.Succeeded)
+	assert.Equal(t, 2, result.Failed)
+	assert.ElementsMatch(t, []thunderdome.OrganizationMemberImportError{
+		{Row: 4, Email: "jane@example.com", Reason: "DUPLICATE_EMAIL"},
+		{Row: 3, Email: "existing@example.com", Reason: "ALREADY_MEMBER"},
+	}, result.Errors)
+}
+
+func TestHandleOrganizationImportUsersRowFailures(t *testing.T) {
+	mockOrgDataSvc := new(MockOrganizationDataService)
+	expectedRows := []thunderdome.OrganizationMemberImportRow{
+		{Row: 2, Email: "jane@example.com", Role: "MEMBER"},
+		{Row: 5, Email: "sam@example.com", Role: "MEMBER"},
+	}
+	mockOrgDataSvc.On("OrganizationImportUsers", mock.Anything, testOrgID, expectedRows).Return(
+		[]thunderdome.OrganizationMemberImportError{{Row: 5, Email: "sam@example.com", Reason: "MEMBER_ADD_FAILED"}}, nil)
+	service := newOrgMemberImportService(mockOrgDataSvc)
+
+	rr := httptest.NewRecorder()
+	service.handleOrganizationImportUsers().ServeHTTP(rr, newOrgMemberImportRequest(t,
+		"role,email\nMEMBER,jane@example.com\nMEMBER,not-an-email\nOWNER,alex@example.com\nMEMBER,sam@example.com\n"))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockOrgDataSvc.AssertExpectations(t)
+	result := decodeOrgMemberImportResult(t, rr)
+	assert.Equal(t, 4, result.Total)
+	assert.Equal(t, 1, result.Succeeded)
+	assert.Equal(t, 3, result.Failed)
+	assert.ElementsMatch(t, []thunderdome.OrganizationMemberImportError{
+		{Row: 3, Email: "not-an-email", Reason: "INVALID_EMAIL"},
+		{Row: 4, Email: "alex@example.com", Reason: "INVALID_ROLE"},
+		{Row: 5, Email: "sam@example.com", Reason: "MEMBER_ADD_FAILED"},
+	}, result.Errors)
+}
+
+func TestHandleOrganizationImportUsersDisabled(t *testing.T) {
+	mockOrgDataSvc := new(MockOrganizationDataService)
+	service := newOrgMemberImportService(mockOrgDataSvc)
+	service.Config.OrgBulkImportEnabled = false
+
+	rr := httptest.NewRecorder()
+	service.handleOrganizationImportUsers().ServeHTTP(rr,
+		newOrgMemberImportRequest(t, "email,role\njane@example.com,MEMBER\n"))
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockOrgDataSvc.AssertNotCalled(t, "OrganizationImportUsers", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,240 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+const (
+	testOrgID       = "c23e4567-e89b-12d3-a456-426614174000"
+	testOrgTeamID   = "d23e4567-e89b-12d3-a456-426614174000"
+	testOtherTeamID = "e23e4567-e89b-12d3-a456-426614174000"
+)
+
+func orgActiveGames() []*thunderdome.Poker {
+	return []*thunderdome.Poker{
+		{ID: "game1", Name: "Sprint 1", TeamID: testOrgTeamID, ActiveUserCount: 8},
+		{ID: "game2", Name: "Sprint 2", TeamID: testOtherTeamID, ActiveUserCount: 5},
+		{ID: "game3", Name: "Sprint 3", TeamID: testOrgTeamID, ActiveUserCount: 3},
+		{ID: "game4", Name: "Sprint 4", TeamID: testOrgTeamID, ActiveUserCount: 1},
+	}
+}
+
+func newOrgActiveGamesRequest(query string) *http.Request {
+	req := httptest.NewRequest("GET", "/organizations/"+testOrgID+"/active-games"+query, nil)
+	req = mux.SetURLVars(req, map[string]string{"orgId": testOrgID})
+	ctx := context.WithValue(req.Context(), contextKeyUserID, testFacilitatorID)
+	ctx = context.WithValue(ctx, contextKeyUserType, thunderdome.RegisteredUserType)
+
+	return req.WithContext(ctx)
+}
+
+func TestHandleGetOrganizationActiveGames(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         string
+		expectedIDs   []string
+		expectedCount int
+	}{
+		{name: "all games", query: "", expectedIDs: []string{"game1", "game2", "game3", "game4"}, expectedCount: 4},
+		{name: "paginated", query: "?limit=2&offset=2", expectedIDs: []string{"game3", "game4"}, expectedCount: 4},
+		{name: "team filter", query: "?team_id=" + testOrgTeamID, expectedIDs: []string{"game1", "game3", "game4"}, expectedCount: 3},
+		{name: "team filter paginated", query: "?team_id=" + testOrgTeamID + "&limit=2&offset=2", expectedIDs: []string{"game4"}, expectedCount: 3},
+		{name: "offset past end", query: "?limit=2&offset=10", expectedIDs: []string{}, expectedCount: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockOrgDataSvc := new(MockOrganizationDataService)
+			mockOrgDataSvc.On("GetActiveGamesForOrg", mock.Anything, testOrgID).Return(orgActiveGames(), nil)
+			service := &Service{
+				Config:              &Config{OrganizationsEnabled: true},
+				OrganizationDataSvc: mockOrgDataSvc,
+				Logger:              otelzap.New(zap.NewNop()),
+			}
+
+			rr := httptest.NewRecorder()
+			service.handleGetOrganizationActiveGames().ServeHTTP(rr, newOrgActiveGamesRequest(tt.query))
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+			var response struct {
+				Data []*thunderdome.Poker `json:"data"`
+				Meta struct {
+					TotalCount int `json:"count"`
+				} `json:"meta"`
+			}
+			assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+			ids := make([]string, 0, len(response.Data))
+			for _, game := range response.Data {
+				ids = append(ids, game.ID)
+			}
+			assert.Equal(t, tt.expectedIDs, ids)
+			assert.Equal(t, tt.expectedCount, response.Meta.TotalCount)
+		})
+	}
+}
+
+func TestHandleGetOrganizationActiveGamesInvalidTeam(t *testing.T) {
+	mockOrgDataSvc := new(MockOrganizationDataService)
+	service := &Service{
+		Config:              &Config{OrganizationsEnabled: true},
+		OrganizationDataSvc: mockOrgDataSvc,
+		Logger:              otelzap.New(zap.NewNop()),
+	}
+
+	rr := httptest.NewRecorder()
+	service.handleGetOrganizationActiveGames().ServeHTTP(rr, newOrgActiveGamesRequest("?team_id=not-a-uuid"))
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockOrgDataSvc.AssertNotCalled(t, "GetActiveGamesForOrg", mock.Anything, mock.Anything)
+}
+
+// TestHandleGetOrganizationActiveGamesConcurrentLoad makes sure the dashboard endpoint serves
+// 100 concurrent organization admin requests within 200ms
+func TestHandleGetOrganizationActiveGamesConcurrentLoad(t *testing.T) {
+	const concurrentRequests = 100
+
+	mockOrgDataSvc := new(MockOrganizationDataService)
+	mockOrgDataSvc.On("OrganizationUserRole", mock.Anything, testFacilitatorID, testOrgID).Return(thunderdome.AdminUserType, nil)
+	mockOrgDataSvc.On("GetActiveGamesForOrg", mock.Anything, testOrgID).Return(orgActiveGames(), nil)
+	service := &Service{
+		Config:              &Config{OrganizationsEnabled: true},
+		OrganizationDataSvc: mockOrgDataSvc,
+		Logger:              otelzap.New(zap.NewNop()),
+	}
+	handler := service.orgAdminOnly(service.handleGetOrganizationActiveGames())
+
+	statuses := make([]int, concurrentRequests)
+	var wg sync.WaitGroup
+	start := time.Now()
+	for i := 0; i < concurrentRequests; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, newOrgActiveGamesRequest("?team_id="+testOrgTeamID))
+			statuses[i] = rr.Code
+		}(i)
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	for i, status := range statuses {
+		if status != http.StatusOK {
+			t.Fatalf("expected request %d to succeed, got status %d", i, status)
+		}
+	}
+	if elapsed > 200*time.Millisecond {
+		t.Errorf("expected %d concurrent requests to complete under 200ms, took %s", concurrentRequests, elapsed)
+	}
+}
+
+func TestHandleGetOrganizationEstimationCalibration(t *testing.T) {
+	since := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	report := &thunderdome.CalibrationReport{
+		OrganizationID:         testOrgID,
+		Since:                  since,
+		ScaleUsageDistribution: map[string]int{"3": 4},
+		TrendDirection:         thunderdome.CalibrationTrendImproving,
+	}
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+	}{
+		{name: "since", query: "?since=2025-01-06", expectedStatus: http.StatusOK},
+		{name: "invalid since", query: "?since=last-week", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAdminDataSvc := new(MockAdminDataSvc)
+			if tt.expectedStatus == http.StatusOK {
+				mockAdminDataSvc.On("GetEstimationCalibration", mock.Anything, testOrgID, since).Return(report, nil)
+			}
+			service := &Service{
+				Config:       &Config{OrganizationsEnabled: true},
+				AdminDataSvc: mockAdminDataSvc,
+				Logger:       otelzap.New(zap.NewNop()),
+			}
+
+			req := httptest.NewRequest("GET", "/organizations/"+testOrgID+"/estimation-calibration"+tt.query, nil)
+			req = mux.SetURLVars(req, map[string]string{"orgId": testOrgID})
+			req = req.WithContext(context.WithValue(req.Context(), contextKeyUserID, testFacilitatorID))
+			rr := httptest.NewRecorder()
+			service.handleGetOrganizationEstimationCalibration().ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			mockAdminDataSvc.AssertExpectations(t)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var response struct {
+				Data thunderdome.CalibrationReport `json:"data"`
+			}
+			assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+			assert.Equal(t, thunderdome.CalibrationTrendImproving, response.Data.TrendDirection)
+			assert.Equal(t, 4, response.Data.ScaleUsageDistribution["3"])
+		})
+	}
+}
+
+func TestHandleOrganizationDefaultScaleUpdate(t *testing.T) {
+	const scaleID = "f23e4567-e89b-12d3-a456-426614174000"
+
+	tests := []struct {
+		name           string
+		body           string
+		setErr         error
+		expectedStatus int
+		expectSet      bool
+	}{
+		{name: "scale set", body: `{"estimationScaleId":"` + scaleID + `"}`, expectedStatus: http.StatusOK, expectSet: true},
+		{name: "scale from another organization", body: `{"estimationScaleId":"` + scaleID + `"}`, setErr: errors.New("ESTIMATION_SCALE_NOT_FOUND"), expectedStatus: http.StatusBadRequest, expectSet: true},
+		{name: "invalid scale id", body: `{"estimationScaleId":"fibonacci"}`, expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockOrgDataSvc := new(MockOrganizationDataService)
+			if tt.expectSet {
+				mockOrgDataSvc.On("SetDefaultEstimationScale", mock.Anything, testOrgID, scaleID).Return(tt.setErr)
+			}
+			if tt.expectSet && tt.setErr == nil {
+				defaultScaleID := scaleID
+				mockOrgDataSvc.On("OrganizationGetByID", mock.Anything, testOrgID).
+					Return(thunderdome.Organization{ID: testOrgID, DefaultEstimationScaleID: &defaultScaleID}, nil)
+			}
+			service := &Service{
+				Config:              &Config{OrganizationsEnabled: true},
+				OrganizationDataSvc: mockOrgDataSvc,
+				Logger:              otelzap.New(zap.NewNop()),
+			}
+
+			req := httptest.NewRequest("PUT", "/organizations/"+testOrgID+"/default-scale", strings.NewReader(tt.body))
+			req = mux.SetURLVars(req, map[string]string{"orgId": testOrgID})
+			req = req.WithContext(context.WithValue(req.Context(), contextKeyUserID, testFacilitatorID))
+			rr := httptest.NewRecorder()
+			service.handleOrganizationDefaultScaleUpdate().ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			mockOrgDataSvc.AssertExpectations(t)
+		})
+	}
+}
@@ -1,7 +1,9 @@
 package http
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -11,6 +13,9 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/StevenWeathers/thunderdome-planning-poker/internal/http/poker"
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/http/response"
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/validation"
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/webhook/subscription"
 	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
 
 	"github.com/gorilla/mux"
@@ -25,6 +30,7 @@ import (
 //	@Param			userId	path	string	true	"the user ID to get poker games for"
 //	@Param			limit	query	int		false	"Max number of results to return"
 //	@Param			offset	query	int		false	"Starting point to return rows from, should be multiplied by limit or 0"
+//	@Param			archived	query	boolean	false	"Only archived poker games"
 //	@Success		200		object	standardJsonResponse{data=[]thunderdome.Poker}
 //	@Failure		403		object	standardJsonResponse{}
 //	@Failure		404		object	standardJsonResponse{}
@@ -41,33 +47,82 @@ func (s *Service) handleGetUserGames() http.HandlerFunc {
 			return
 		}
 
-		games, count, err := s.PokerDataSvc.GetGamesByUser(userID, limit, offset)
+		var games []*thunderdome.Poker
+		var count int
+		var err error
+		archived, _ := strconv.ParseBool(r.URL.Query().Get("archived"))
+
+		if archived {
+			games, count, err = s.PokerDataSvc.GetArchivedGamesByUser(userID, limit, offset)
+		} else {
+			games, count, err = s.PokerDataSvc.GetGamesByUser(userID, limit, offset)
+		}
 		if err != nil {
 			s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, "BATTLE_NOT_FOUND"))
 			return
 		}
 
-		meta := &pagination{
-			Count:  count,
-			Offset: offset,
-			Limit:  limit,
-		}
+		meta := response.NewMeta(count, limit, offset)
 
 		s.Success(w, r, http.StatusOK, games, meta)
 	}
 }
 
+// handleGetUserEstimationHistory gets the user's votes on finalized stories compared to their final points
+//
+//	@Summary		Get User Estimation History
+//	@Description	get the user's votes on finalized poker stories compared to the final points, newest games first
+//	@Tags			poker
+//	@Produce		json
+//	@Param			userId	path	string	true	"the user ID to get estimation history for"
+//	@Param			limit	query	int		false	"Max number of results to return"
+//	@Param			offset	query	int		false	"Starting point to return rows from, should be multiplied by limit or 0"
+//	@Success		200		object	standardJsonResponse{data=[]thunderdome.UserEstimationRecord}
+//	@Failure		400		object	standardJsonResponse{}
+//	@Failure		500		object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/users/{userId}/estimation-history [get]
+func (s *Service) handleGetUserEstimationHistory() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		limit, offset := getLimitOffsetFromRequest(r)
+		vars := mux.Vars(r)
+		userID := vars["userId"]
+		idErr := validate.Var(userID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		records, err := s.PokerDataSvc.GetUserEstimationHistory(ctx, userID, limit, offset)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleGetUserEstimationHistory error", zap.Error(err),
+				zap.String("entity_user_id", userID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, records, nil)
+	}
+}
+
 type battleRequestBody struct {
-	Name                 string               `json:"name" validate:"required"`
-	EstimationScaleID    string               `json:"estimationScaleId"`
-	PointValuesAllowed   []string             `json:"pointValuesAllowed" validate:"required"`
-	AutoFinishVoting     bool                 `json:"autoFinishVoting"`
-	Stories              []*thunderdome.Story `json:"plans"`
-	PointAverageRounding string               `json:"pointAverageRounding" validate:"required,oneof=ceil round floor"`
-	HideVoterIdentity    bool                 `json:"hideVoterIdentity"`
-	Facilitators         []string             `json:"battleLeaders"`
-	JoinCode             string               `json:"joinCode"`
-	FacilitatorCode      string               `json:"leaderCode"`
+	Name                    string               `json:"name" validate:"required"`
+	EstimationScaleID       string               `json:"estimationScaleId"`
+	PointValuesAllowed      []string             `json:"pointValuesAllowed" validate:"required"`
+	AutoFinishVoting        bool                 `json:"autoFinishVoting"`
+	Stories                 []*thunderdome.Story `json:"plans"`
+	PointAverageRounding    string               `json:"pointAverageRounding" validate:"required,oneof=ceil round floor"`
+	HideVoterIdentity       bool                 `json:"hideVoterIdentity"`
+	AutoFinalizeOnConsensus bool                 `json:"autoFinalizeOnConsensus"`
+	MinParticipants         int                  `json:"minParticipants" validate:"min=0"`
+	TimeBoxMinutes          int                  `json:"timeBoxMinutes" validate:"min=0"`
+	QuorumPercentage        float64              `json:"quorumPercentage" validate:"min=0,max=100"`
+	StoryTypeScaleMap       map[string]string    `json:"storyTypeScaleMap" validate:"dive,keys,required,max=64,endkeys,uuid"`
+	Facilitators            []string             `json:"battleLeaders"`
+	JoinCode                string               `json:"joinCode"`
+	FacilitatorCode         string               `json:"leaderCode"`
+	ObserverCode            string               `json:"observerCode"`
 }
 
 // handlePokerCreate handles creating a poker game
@@ -83,6 +138,7 @@ type battleRequestBody struct {
 //	@Param			battle			body	battleRequestBody	false	"new poker game object"
 //	@Success		200				object	standardJsonResponse{data=thunderdome.Poker}
 //	@Failure		403				object	standardJsonResponse{}
+//	@Failure		422				object	standardJsonResponse{data=[]thunderdome.ValidationError}
 //	@Failure		500				object	standardJsonResponse{}
 //	@Security		ApiKeyAuth
 //	@Router			/users/{userId}/battles [post]
@@ -126,26 +182,21 @@ func (s *Service) handlePokerCreate() http.HandlerFunc {
 			return
 		}
 
-		// set a default for backwards compatibility
-		scale := &thunderdome.EstimationScale{}
-		var scaleErr error
-		if b.EstimationScaleID == "" {
-			scale, scaleErr = s.PokerDataSvc.GetDefaultPublicEstimationScale(ctx)
-			if scaleErr != nil {
-				s.Logger.Error("create poker error", zap.Error(scaleErr))
-				s.Failure(w, r, http.StatusInternalServerError, scaleErr)
-				return
-			}
-			b.EstimationScaleID = scale.ID
-		} else {
-			scale, scaleErr = s.PokerDataSvc.GetEstimationScale(ctx, b.EstimationScaleID)
-			if scaleErr != nil {
-				s.Logger.Error("create poker error", zap.Error(scaleErr))
-				s.Failure(w, r, http.StatusInternalServerError, scaleErr)
-				return
+		orgID := vars["orgId"]
+		if orgID == "" && teamIDExists && b.EstimationScaleID == "" {
+			if team, err := s.TeamDataSvc.TeamGetByID(ctx, teamID); err == nil {
+				orgID = team.OrganizationID
 			}
 		}
 
+		scale, scaleErr := s.resolveGameEstimationScale(ctx, b.EstimationScaleID, orgID)
+		if scaleErr != nil {
+			s.Logger.Error("create poker error", zap.Error(scaleErr))
+			s.Failure(w, r, http.StatusInternalServerError, scaleErr)
+			return
+		}
+		b.EstimationScaleID = scale.ID
+
 		// verify that the point values allowed are in the estimation scale
 		for _, point := range b.PointValuesAllowed {
 			if !slices.Contains(scale.Values, point) {
@@ -154,12 +205,26 @@ func (s *Service) handlePokerCreate() http.HandlerFunc {
 			}
 		}
 
+		for storyType, scaleID := range b.StoryTypeScaleMap {
+			if _, err := s.PokerDataSvc.GetEstimationScale(ctx, scaleID); err != nil {
+				s.Logger.Ctx(ctx).Error("handlePokerCreate error", zap.Error(err),
+					zap.String("story_type", storyType), zap.String("estimation_scale_id", scaleID),
+					zap.String("session_user_id", sessionUserID))
+				s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "INVALID_STORY_TYPE_SCALE"))
+				return
+			}
+		}
+
+		if s.storyLinksInvalid(w, b.Stories) {
+			return
+		}
+
 		var newGame *thunderdome.Poker
 		var err error
 		// if battle created with team association
 		if teamIDExists {
 			if isTeamUserOrAnAdmin(r) {
-				newGame, err = s.PokerDataSvc.TeamCreateGame(ctx, teamID, userID, b.Name, b.EstimationScaleID, b.PointValuesAllowed, b.Stories, b.AutoFinishVoting, b.PointAverageRounding, b.JoinCode, b.FacilitatorCode, b.HideVoterIdentity)
+				newGame, err = s.PokerDataSvc.TeamCreateGame(ctx, teamID, userID, b.Name, b.EstimationScaleID, b.PointValuesAllowed, b.Stories, b.AutoFinishVoting, b.PointAverageRounding, b.JoinCode, b.FacilitatorCode, b.ObserverCode, b.HideVoterIdentity, b.AutoFinalizeOnConsensus, b.MinParticipants, b.TimeBoxMinutes, b.QuorumPercentage, b.StoryTypeScaleMap)
 				if err != nil {
 					s.Logger.Ctx(ctx).Error("handlePokerCreate error", zap.Error(err),
 						zap.String("entity_user_id", userID), zap.String("team_id", teamID),
@@ -167,12 +232,13 @@ func (s *Service) handlePokerCreate() http.HandlerFunc {
 					s.Failure(w, r, http.StatusInternalServerError, err)
 					return
 				}
+				go s.notifyTeamGameStarted(context.WithoutCancel(ctx), teamID, userID, newGame)
 			} else {
 				s.Failure(w, r, http.StatusForbidden, Errorf(EUNAUTHORIZED, "REQUIRES_TEAM_USER"))
 				return
 			}
 		} else {
-			newGame, err = s.PokerDataSvc.CreateGame(ctx, userID, b.Name, b.EstimationScaleID, b.PointValuesAllowed, b.Stories, b.AutoFinishVoting, b.PointAverageRounding, b.JoinCode, b.FacilitatorCode, b.HideVoterIdentity)
+			newGame, err = s.PokerDataSvc.CreateGame(ctx, userID, b.Name, b.EstimationScaleID, b.PointValuesAllowed, b.Stories, b.AutoFinishVoting, b.PointAverageRounding, b.JoinCode, b.FacilitatorCode, b.ObserverCode, b.HideVoterIdentity, b.AutoFinalizeOnConsensus, b.MinParticipants, b.TimeBoxMinutes, b.QuorumPercentage, b.StoryTypeScaleMap)
 			if err != nil {
 				s.Logger.Ctx(ctx).Error("handlePokerCreate error", zap.Error(err),
 					zap.String("entity_user_id", userID), zap.String("poker_name", b.Name),
@@ -194,10 +260,32 @@ func (s *Service) handlePokerCreate() http.HandlerFunc {
 			}
 		}
 
+		s.recordUsage(ctx, vars["orgId"], subscription.UsageEventGameCreated)
+
 		s.Success(w, r, http.StatusOK, newGame, nil)
 	}
 }
 
+// resolveGameEstimationScale gets the estimation scale a new game is created with, the requested scale when set,
+// otherwise the organization's default scale and finally the global default public scale
+func (s *Service) resolveGameEstimationScale(ctx context.Context, scaleID string, orgID string) (*thunderdome.EstimationScale, error) {
+	if scaleID != "" {
+		return s.PokerDataSvc.GetEstimationScale(ctx, scaleID)
+	}
+
+	if orgID != "" && s.Config.OrganizationsEnabled {
+		org, err := s.OrganizationDataSvc.OrganizationGetByID(ctx, orgID)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("resolveGameEstimationScale get organization error", zap.Error(err),
+				zap.String("organization_id", orgID))
+		} else if org.DefaultEstimationScaleID != nil {
+			return s.PokerDataSvc.GetEstimationScale(ctx, *org.DefaultEstimationScaleID)
+		}
+	}
+
+	return s.PokerDataSvc.GetDefaultPublicEstimationScale(ctx)
+}
+
 // handleGetPokerGames gets a list of poker games
 //
 //	@Summary		Get Poker Games
@@ -207,6 +295,7 @@ func (s *Service) handlePokerCreate() http.HandlerFunc {
 //	@Param			limit	query	int		false	"Max number of results to return"
 //	@Param			offset	query	int		false	"Starting point to return rows from, should be multiplied by limit or 0"
 //	@Param			active	query	boolean	false	"Only active poker games"
+//	@Param			archived	query	boolean	false	"Only archived poker games"
 //	@Success		200		object	standardJsonResponse{data=[]thunderdome.Poker}
 //	@Failure		500		object	standardJsonResponse{}
 //	@Security		ApiKeyAuth
@@ -221,8 +310,11 @@ func (s *Service) handleGetPokerGames() http.HandlerFunc {
 		var count int
 		var games []*thunderdome.Poker
 		Active, _ := strconv.ParseBool(query.Get("active"))
+		Archived, _ := strconv.ParseBool(query.Get("archived"))
 
-		if Active {
+		if Archived {
+			games, count, err = s.PokerDataSvc.GetArchivedGames(limit, offset)
+		} else if Active {
 			games, count, err = s.PokerDataSvc.GetActiveGames(limit, offset)
 		} else {
 			games, count, err = s.PokerDataSvc.GetGames(limit, offset)
@@ -235,11 +327,7 @@ func (s *Service) handleGetPokerGames() http.HandlerFunc {
 			return
 		}
 
-		meta := &pagination{
-			Count:  count,
-			Offset: offset,
-			Limit:  limit,
-		}
+		meta := response.NewMeta(count, limit, offset)
 
 		s.Success(w, r, http.StatusOK, games, meta)
 	}
@@ -284,10 +372,232 @@ func (s *Service) handleGetPokerGame() http.HandlerFunc {
 			}
 		}
 
+		// facilitator notes are private to the game facilitators
+		if !slices.Contains(game.Facilitators, sessionUserID) && userType != thunderdome.AdminUserType {
+			thunderdome.RedactFacilitatorNotes(game.Stories)
+		}
+
 		s.Success(w, r, http.StatusOK, game, nil)
 	}
 }
 
+// handleGetPokerLeaderboard gets the poker game estimation accuracy leaderboard
+//
+//	@Summary		Get Poker Game Leaderboard
+//	@Description	get poker game participants ranked by how closely their votes matched the final story points
+//	@Tags			poker
+//	@Produce		json
+//	@Param			battleId	path	string	true	"the poker game ID"
+//	@Success		200			object	standardJsonResponse{data=[]thunderdome.ParticipantAccuracy}
+//	@Failure		403			object	standardJsonResponse{}
+//	@Failure		404			object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/battles/{battleId}/leaderboard [get]
+func (s *Service) handleGetPokerLeaderboard() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		gameID := vars["battleId"]
+		idErr := validate.Var(gameID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		userType := ctx.Value(contextKeyUserType).(string)
+
+		game, err := s.PokerDataSvc.GetGameByID(gameID, sessionUserID)
+		if err != nil {
+			s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, "BATTLE_NOT_FOUND"))
+			return
+		}
+
+		// don't allow retrieving battle leaderboard if battle has JoinCode and user hasn't joined yet
+		if game.JoinCode != "" {
+			userErr := s.PokerDataSvc.GetUserActiveStatus(gameID, sessionUserID)
+			if userErr != nil && userErr.Error() != "DUPLICATE_BATTLE_USER" && userType != thunderdome.AdminUserType {
+				s.Failure(w, r, http.StatusForbidden, Errorf(EUNAUTHORIZED, "USER_MUST_JOIN_BATTLE"))
+				return
+			}
+		}
+
+		leaderboard, err := s.PokerDataSvc.ComputeEstimationAccuracy(ctx, gameID)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleGetPokerLeaderboard error", zap.Error(err),
+				zap.String("poker_id", gameID), zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, leaderboard, nil)
+	}
+}
+
+// handleGetPokerStatistics gets the poker game voting statistics
+//
+//	@Summary		Get Poker Game Statistics
+//	@Description	get poker game voting statistics such as rounds per story, voting duration and consensus rate
+//	@Tags			poker
+//	@Produce		json
+//	@Param			battleId	path	string	true	"the poker game ID"
+//	@Success		200			object	standardJsonResponse{data=thunderdome.GameStatistics}
+//	@Failure		403			object	standardJsonResponse{}
+//	@Failure		404			object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/battles/{battleId}/statistics [get]
+func (s *Service) handleGetPokerStatistics() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		gameID := vars["battleId"]
+		idErr := validate.Var(gameID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		userType := ctx.Value(contextKeyUserType).(string)
+
+		game, err := s.PokerDataSvc.GetGameByID(gameID, sessionUserID)
+		if err != nil {
+			s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, "BATTLE_NOT_FOUND"))
+			return
+		}
+
+		// don't allow retrieving battle statistics if battle has JoinCode and user hasn't joined yet
+		if game.JoinCode != "" {
+			userErr := s.PokerDataSvc.GetUserActiveStatus(gameID, sessionUserID)
+			if userErr != nil && userErr.Error() != "DUPLICATE_BATTLE_USER" && userType != thunderdome.AdminUserType {
+				s.Failure(w, r, http.StatusForbidden, Errorf(EUNAUTHORIZED, "USER_MUST_JOIN_BATTLE"))
+				return
+			}
+		}
+
+		statistics, err := s.PokerDataSvc.GetGameStatistics(ctx, gameID)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleGetPokerStatistics error", zap.Error(err),
+				zap.String("poker_id", gameID), zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, statistics, nil)
+	}
+}
+
+// handleGetPokerCompletion gets the poker game story completion stats
+//
+//	@Summary		Get Poker Game Completion
+//	@Description	get how many of the poker game stories and points have been finalized, useful for burn-up charts
+//	@Tags			poker
+//	@Produce		json
+//	@Param			battleId	path	string	true	"the poker game ID"
+//	@Success		200			object	standardJsonResponse{data=thunderdome.CompletionStats}
+//	@Failure		403			object	standardJsonResponse{}
+//	@Failure		404			object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/battles/{battleId}/completion [get]
+func (s *Service) handleGetPokerCompletion() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		gameID := vars["battleId"]
+		idErr := validate.Var(gameID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		userType := ctx.Value(contextKeyUserType).(string)
+
+		game, err := s.PokerDataSvc.GetGameByID(gameID, sessionUserID)
+		if err != nil {
+			s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, "BATTLE_NOT_FOUND"))
+			return
+		}
+
+		// don't allow retrieving battle completion if battle has JoinCode and user hasn't joined yet
+		if game.JoinCode != "" {
+			userErr := s.PokerDataSvc.GetUserActiveStatus(gameID, sessionUserID)
+			if userErr != nil && userErr.Error() != "DUPLICATE_BATTLE_USER" && userType != thunderdome.AdminUserType {
+				s.Failure(w, r, http.StatusForbidden, Errorf(EUNAUTHORIZED, "USER_MUST_JOIN_BATTLE"))
+				return
+			}
+		}
+
+		completion, err := s.PokerDataSvc.GetGameCompletionStats(ctx, gameID)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleGetPokerCompletion error", zap.Error(err),
+				zap.String("poker_id", gameID), zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, completion, nil)
+	}
+}
+
+// handleGetPokerAccessLog gets the poker game access log
+//
+//	@Summary		Get Poker Game Access Log
+//	@Description	get the poker game join and leave events newest first, restricted to facilitators
+//	@Tags			poker
+//	@Produce		json
+//	@Param			battleId	path	string	true	"the poker game ID"
+//	@Param			event_type	query	string	false	"filter by event type"	Enums(join, leave)
+//	@Param			limit		query	int		false	"Max number of results to return"
+//	@Param			offset		query	int		false	"Starting point to return rows from, should be multiplied by limit or 0"
+//	@Success		200			object	standardJsonResponse{data=[]thunderdome.PokerAccessLog}
+//	@Failure		400			object	standardJsonResponse{}
+//	@Failure		403			object	standardJsonResponse{}
+//	@Failure		404			object	standardJsonResponse{}
+//	@Failure		500			object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/battles/{battleId}/access-log [get]
+func (s *Service) handleGetPokerAccessLog() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		gameID := vars["battleId"]
+		idErr := validate.Var(gameID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		userType := ctx.Value(contextKeyUserType).(string)
+		eventType := r.URL.Query().Get("event_type")
+		if eventErr := validate.Var(eventType, "omitempty,oneof=join leave"); eventErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "INVALID_EVENT_TYPE"))
+			return
+		}
+		limit, offset := getLimitOffsetFromRequest(r)
+
+		game, err := s.PokerDataSvc.GetGameByID(gameID, sessionUserID)
+		if err != nil {
+			s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, "BATTLE_NOT_FOUND"))
+			return
+		}
+
+		if !slices.Contains(game.Facilitators, sessionUserID) && userType != thunderdome.AdminUserType {
+			s.Failure(w, r, http.StatusForbidden, Errorf(EUNAUTHORIZED, "REQUIRES_FACILITATOR"))
+			return
+		}
+
+		entries, count, err := s.PokerDataSvc.GetAccessLog(ctx, gameID, eventType, limit, offset)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleGetPokerAccessLog error", zap.Error(err),
+				zap.String("poker_id", gameID), zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		meta := response.NewMeta(count, limit, offset)
+
+		s.Success(w, r, http.StatusOK, entries, meta)
+	}
+}
+
 type planRequestBody struct {
 	Name               string `json:"planName"`
 	Type               string `json:"type"`
@@ -298,6 +608,31 @@ type planRequestBody struct {
 	Priority           int32  `json:"priority"`
 }
 
+// storyLinkInvalid responds with the story link's validation error, returning whether the link was invalid
+func (s *Service) storyLinkInvalid(w http.ResponseWriter, link string) bool {
+	err := validation.ValidateURL(link)
+	if err == nil {
+		return false
+	}
+
+	response.RespondErrorData(w, http.StatusUnprocessableEntity, err.Error(),
+		[]thunderdome.ValidationError{{Field: "link", Code: "INVALID_URL", Value: link}})
+	return true
+}
+
+// storyLinksInvalid responds with the first invalid story link's validation error, returning whether any link was invalid
+func (s *Service) storyLinksInvalid(w http.ResponseWriter, stories []*thunderdome.Story) bool {
+	for i, story := range stories {
+		if err := validation.ValidateURL(story.Link); err != nil {
+			response.RespondErrorData(w, http.StatusUnprocessableEntity, err.Error(),
+				[]thunderdome.ValidationError{{Field: fmt.Sprintf("stories[%d].link", i), Code: "INVALID_URL", Value: story.Link}})
+			return true
+		}
+	}
+
+	return false
+}
+
 // handlePokerStoryAdd handles adding a story to poker
 //
 //	@Summary		Create Poker Story
@@ -308,6 +643,7 @@ type planRequestBody struct {
 //	@Produce		json
 //	@Success		200	object	standardJsonResponse{}
 //	@Success		403	object	standardJsonResponse{}
+//	@Failure		422	object	standardJsonResponse{data=[]thunderdome.ValidationError}
 //	@Success		500	object	standardJsonResponse{}
 //	@Security		ApiKeyAuth
 //	@Router			/battles/{battleId}/plans [post]
@@ -342,6 +678,10 @@ func (s *Service) handlePokerStoryAdd(pokerSvc *poker.Service) http.HandlerFunc
 			return
 		}
 
+		if s.storyLinkInvalid(w, story.Link) {
+			return
+		}
+
 		err := pokerSvc.APIEvent(ctx, gameID, sessionUserID, "add_plan", string(body))
 		if err != nil {
 			s.Logger.Ctx(ctx).Error("handlePokerStoryAdd error", zap.Error(err),
@@ -355,6 +695,146 @@ func (s *Service) handlePokerStoryAdd(pokerSvc *poker.Service) http.HandlerFunc
 	}
 }
 
+type storiesImportRequestBody struct {
+	Stories []planRequestBody `json:"plans" validate:"required,min=1,dive"`
+}
+
+// handlePokerStoriesImport handles bulk importing stories (e.g. from Jira, Linear, or CSV) to poker
+//
+//	@Summary		Import Poker Stories
+//	@Description	Bulk imports poker stories, stories with a referenceId already in the game are updated instead of duplicated
+//	@Param			battleId	path	string						true	"the poker game ID"
+//	@Param			plans		body	storiesImportRequestBody	true	"stories to import"
+//	@Tags			poker
+//	@Produce		json
+//	@Success		200	object	standardJsonResponse{data=thunderdome.DuplicationResult}
+//	@Success		403	object	standardJsonResponse{}
+//	@Failure		422	object	standardJsonResponse{data=[]thunderdome.ValidationError}
+//	@Success		500	object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/battles/{battleId}/plans/import [post]
+func (s *Service) handlePokerStoriesImport(pokerSvc *poker.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		gameID := vars["battleId"]
+		idErr := validate.Var(gameID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+
+		body, bodyErr := io.ReadAll(r.Body)
+		if bodyErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, bodyErr.Error()))
+			return
+		}
+
+		var si = storiesImportRequestBody{}
+		jsonErr := json.Unmarshal(body, &si)
+		if jsonErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, jsonErr.Error()))
+			return
+		}
+
+		inputErr := validate.Struct(si)
+		if inputErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, inputErr.Error()))
+			return
+		}
+
+		stories := make([]*thunderdome.Story, 0, len(si.Stories))
+		for _, p := range si.Stories {
+			stories = append(stories, &thunderdome.Story{
+				Name:               p.Name,
+				Type:               p.Type,
+				ReferenceID:        p.ReferenceID,
+				Link:               p.Link,
+				Description:        p.Description,
+				AcceptanceCriteria: p.AcceptanceCriteria,
+				Priority:           p.Priority,
+			})
+		}
+
+		if s.storyLinksInvalid(w, stories) {
+			return
+		}
+
+		result, err := pokerSvc.ImportStories(ctx, gameID, sessionUserID, stories, s.Config.ImportDeduplicationEnabled)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handlePokerStoriesImport error", zap.Error(err),
+				zap.String("poker_id", gameID), zap.String("session_user_id", sessionUserID),
+				zap.Int("story_count", len(stories)))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, result, nil)
+	}
+}
+
+type storiesPriorityRequestBody struct {
+	Stories []thunderdome.StoryPriorityUpdate `json:"stories" validate:"required,min=1,dive"`
+}
+
+// handlePokerStoriesPriorityUpdate handles bulk updating the priority of poker stories
+//
+//	@Summary		Update Poker Stories Priority
+//	@Description	Bulk updates the priority of poker stories, stories not in the game fail individually without affecting the rest
+//	@Param			battleId	path	string						true	"the poker game ID"
+//	@Param			stories		body	storiesPriorityRequestBody	true	"story priority updates"
+//	@Tags			poker
+//	@Produce		json
+//	@Success		200	object	standardJsonResponse{data=[]thunderdome.StoryPriorityResult}
+//	@Success		403	object	standardJsonResponse{}
+//	@Success		500	object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/battles/{battleId}/plans/priority [put]
+func (s *Service) handlePokerStoriesPriorityUpdate(pokerSvc *poker.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		gameID := vars["battleId"]
+		idErr := validate.Var(gameID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+
+		body, bodyErr := io.ReadAll(r.Body)
+		if bodyErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, bodyErr.Error()))
+			return
+		}
+
+		var sp = storiesPriorityRequestBody{}
+		jsonErr := json.Unmarshal(body, &sp)
+		if jsonErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, jsonErr.Error()))
+			return
+		}
+
+		inputErr := validate.Struct(sp)
+		if inputErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, inputErr.Error()))
+			return
+		}
+
+		results, err := pokerSvc.UpdateStoryPriorities(ctx, gameID, sessionUserID, sp.Stories)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handlePokerStoriesPriorityUpdate error", zap.Error(err),
+				zap.String("poker_id", gameID), zap.String("session_user_id", sessionUserID),
+				zap.Int("story_count", len(sp.Stories)))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, results, nil)
+	}
+}
+
 type storyUpdateRequestBody struct {
 	ID                 string `json:"planId" swaggerignore:"true"`
 	Name               string `json:"planName"`
@@ -377,6 +857,7 @@ type storyUpdateRequestBody struct {
 //	@Produce		json
 //	@Success		200	object	standardJsonResponse{}
 //	@Success		403	object	standardJsonResponse{}
+//	@Failure		422	object	standardJsonResponse{data=[]thunderdome.ValidationError}
 //	@Success		500	object	standardJsonResponse{}
 //	@Security		ApiKeyAuth
 //	@Router			/battles/{battleId}/plans/{planId} [put]
@@ -421,6 +902,9 @@ func (s *Service) handlePokerStoryUpdate(pokerSvc *poker.Service) http.HandlerFu
 			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, inputErr.Error()))
 			return
 		}
+		if s.storyLinkInvalid(w, story.Link) {
+			return
+		}
 
 		updatedStory, err := json.Marshal(story)
 		if err != nil {
@@ -441,6 +925,158 @@ func (s *Service) handlePokerStoryUpdate(pokerSvc *poker.Service) http.HandlerFu
 	}
 }
 
+type storyFacilitatorNotesRequestBody struct {
+	Notes string `json:"notes" validate:"max=10000"`
+}
+
+// handlePokerStoryFacilitatorNotesUpdate handles setting the private facilitator notes of a poker story
+//
+//	@Summary		Update Poker Story Facilitator Notes
+//	@Description	Sets the poker story notes only visible to the game facilitators, empty notes remove them
+//	@Param			storyId	path	string								true	"the story ID"
+//	@Param			notes	body	storyFacilitatorNotesRequestBody	true	"facilitator notes"
+//	@Tags			poker
+//	@Produce		json
+//	@Success		200	object	standardJsonResponse{}
+//	@Success		403	object	standardJsonResponse{}
+//	@Success		404	object	standardJsonResponse{}
+//	@Success		500	object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/stories/{storyId}/facilitator-notes [put]
+func (s *Service) handlePokerStoryFacilitatorNotesUpdate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		storyID := vars["storyId"]
+		idErr := validate.Var(storyID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+
+		body, bodyErr := io.ReadAll(r.Body)
+		if bodyErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, bodyErr.Error()))
+			return
+		}
+
+		var req = storyFacilitatorNotesRequestBody{}
+		jsonErr := json.Unmarshal(body, &req)
+		if jsonErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, jsonErr.Error()))
+			return
+		}
+
+		inputErr := validate.Struct(req)
+		if inputErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, inputErr.Error()))
+			return
+		}
+
+		err := s.PokerDataSvc.SetFacilitatorNote(ctx, storyID, sessionUserID, req.Notes)
+		if err != nil {
+			switch err.Error() {
+			case "STORY_NOT_FOUND":
+				s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, err.Error()))
+			case "REQUIRES_FACILITATOR":
+				s.Failure(w, r, http.StatusForbidden, Errorf(EUNAUTHORIZED, err.Error()))
+			default:
+				s.Logger.Ctx(ctx).Error("handlePokerStoryFacilitatorNotesUpdate error", zap.Error(err),
+					zap.String("story_id", storyID), zap.String("session_user_id", sessionUserID))
+				s.Failure(w, r, http.StatusInternalServerError, err)
+			}
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, nil, nil)
+	}
+}
+
+type storyActivateRequestBody struct {
+	StoryID string `json:"storyId" swaggerignore:"true"`
+	Force   bool   `json:"force"`
+}
+
+// handlePokerStoryActivate handles activating a poker story for voting
+//
+//	@Summary		Activate Poker Story
+//	@Description	Activates voting for a poker story, fails when fewer than the game's minimum participants are active unless forced
+//	@Param			battleId	path	string						true	"the poker game ID"
+//	@Param			planId		path	string						true	"the story ID"
+//	@Param			activation	body	storyActivateRequestBody	false	"activation options"
+//	@Tags			poker
+//	@Produce		json
+//	@Success		200	object	standardJsonResponse{}
+//	@Success		400	object	standardJsonResponse{}
+//	@Success		403	object	standardJsonResponse{}
+//	@Success		500	object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/battles/{battleId}/plans/{planId}/activate [post]
+func (s *Service) handlePokerStoryActivate(pokerSvc *poker.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		gameID := vars["battleId"]
+		idErr := validate.Var(gameID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+		storyID := vars["planId"]
+		sidErr := validate.Var(storyID, "required,uuid")
+		if sidErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, sidErr.Error()))
+			return
+		}
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		userType := ctx.Value(contextKeyUserType).(string)
+
+		if userType != thunderdome.AdminUserType {
+			if err := s.PokerDataSvc.ConfirmFacilitator(gameID, sessionUserID); err != nil {
+				s.Failure(w, r, http.StatusForbidden, Errorf(EUNAUTHORIZED, "REQUIRES_FACILITATOR"))
+				return
+			}
+		}
+
+		body, bodyErr := io.ReadAll(r.Body)
+		if bodyErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, bodyErr.Error()))
+			return
+		}
+
+		var activation = storyActivateRequestBody{}
+		if len(body) > 0 {
+			if jsonErr := json.Unmarshal(body, &activation); jsonErr != nil {
+				s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, jsonErr.Error()))
+				return
+			}
+		}
+		activation.StoryID = storyID
+
+		activationValue, err := json.Marshal(activation)
+		if err != nil {
+			s.Failure(w, r, http.StatusInternalServerError, Errorf(EINTERNAL, err.Error()))
+			return
+		}
+
+		err = pokerSvc.APIEvent(ctx, gameID, sessionUserID, "activate_plan", string(activationValue))
+		if err != nil {
+			if errors.Is(err, thunderdome.ErrNotEnoughParticipants) {
+				s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "INSUFFICIENT_PARTICIPANTS"))
+				return
+			}
+			s.Logger.Ctx(ctx).Error("handlePokerStoryActivate error", zap.Error(err),
+				zap.String("poker_id", gameID), zap.String("session_user_id", sessionUserID),
+				zap.String("story_id", storyID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, nil, nil)
+	}
+}
+
 // handlePokerStoryDelete handles deleting a story from poker
 //
 //	@Summary		Delete Poker Story
@@ -488,8 +1124,9 @@ func (s *Service) handlePokerStoryDelete(pokerSvc *poker.Service) http.HandlerFu
 // handlePokerDelete handles deleting a poker game
 //
 //	@Summary		Delete Poker Game
-//	@Description	Deletes a poker game
+//	@Description	Archives a poker game so it's kept for auditing, admins can permanently delete it with permanent=true
 //	@Param			battleId	path	string	true	"the poker game ID"
+//	@Param			permanent	query	boolean	false	"Permanently delete the poker game instead of archiving it, admin only"
 //	@Tags			poker
 //	@Produce		json
 //	@Success		200	object	standardJsonResponse{}
@@ -508,6 +1145,25 @@ func (s *Service) handlePokerDelete(pokerSvc *poker.Service) http.HandlerFunc {
 			return
 		}
 		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		permanent, _ := strconv.ParseBool(r.URL.Query().Get("permanent"))
+
+		if permanent {
+			userType := ctx.Value(contextKeyUserType).(string)
+			if userType != thunderdome.AdminUserType {
+				s.Failure(w, r, http.StatusForbidden, Errorf(EUNAUTHORIZED, "REQUIRES_ADMIN"))
+				return
+			}
+
+			if err := s.PokerDataSvc.DeleteGame(gameID); err != nil {
+				s.Logger.Ctx(ctx).Error("handlePokerDelete permanent error", zap.Error(err),
+					zap.String("poker_id", gameID), zap.String("session_user_id", sessionUserID))
+				s.Failure(w, r, http.StatusInternalServerError, err)
+				return
+			}
+
+			s.Success(w, r, http.StatusOK, nil, nil)
+			return
+		}
 
 		err := pokerSvc.APIEvent(ctx, gameID, sessionUserID, "concede_battle", "")
 		if err != nil {
@@ -520,3 +1176,95 @@ func (s *Service) handlePokerDelete(pokerSvc *poker.Service) http.HandlerFunc {
 		s.Success(w, r, http.StatusOK, nil, nil)
 	}
 }
+
+type joinCodeRegenerateResponse struct {
+	JoinCode string `json:"joinCode"`
+}
+
+type facilitatorCodeRegenerateResponse struct {
+	FacilitatorCode string `json:"facilitatorCode"`
+}
+
+// handlePokerJoinCodeRegenerate handles replacing a poker game's join code
+//
+//	@Summary		Regenerate Poker Join Code
+//	@Description	Replaces the poker game join code with a new random code, the old code no longer grants access
+//	@Param			battleId	path	string	true	"the poker game ID"
+//	@Tags			poker
+//	@Produce		json
+//	@Success		200	object	standardJsonResponse{data=joinCodeRegenerateResponse}
+//	@Success		403	object	standardJsonResponse{}
+//	@Success		404	object	standardJsonResponse{}
+//	@Success		500	object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/battles/{battleId}/join-code/regenerate [put]
+func (s *Service) handlePokerJoinCodeRegenerate(pokerSvc *poker.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		gameID := vars["battleId"]
+		idErr := validate.Var(gameID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+
+		code, err := pokerSvc.RegenerateJoinCode(ctx, gameID, sessionUserID)
+		if err != nil {
+			s.failRegenerateCode(w, r, "handlePokerJoinCodeRegenerate", gameID, sessionUserID, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, joinCodeRegenerateResponse{JoinCode: code}, nil)
+	}
+}
+
+// handlePokerFacilitatorCodeRegenerate handles replacing a poker game's facilitator code
+//
+//	@Summary		Regenerate Poker Facilitator Code
+//	@Description	Replaces the poker game facilitator code with a new random code, the old code can no longer be used to become a facilitator
+//	@Param			battleId	path	string	true	"the poker game ID"
+//	@Tags			poker
+//	@Produce		json
+//	@Success		200	object	standardJsonResponse{data=facilitatorCodeRegenerateResponse}
+//	@Success		403	object	standardJsonResponse{}
+//	@Success		404	object	standardJsonResponse{}
+//	@Success		500	object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/battles/{battleId}/facilitator-code/regenerate [put]
+func (s *Service) handlePokerFacilitatorCodeRegenerate(pokerSvc *poker.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		gameID := vars["battleId"]
+		idErr := validate.Var(gameID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+
+		code, err := pokerSvc.RegenerateFacilitatorCode(ctx, gameID, sessionUserID)
+		if err != nil {
+			s.failRegenerateCode(w, r, "handlePokerFacilitatorCodeRegenerate", gameID, sessionUserID, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, facilitatorCodeRegenerateResponse{FacilitatorCode: code}, nil)
+	}
+}
+
+// failRegenerateCode responds with the failure for a poker code regeneration error
+func (s *Service) failRegenerateCode(w http.ResponseWriter, r *http.Request, handler string, gameID string, sessionUserID string, err error) {
+	switch err.Error() {
+	case "REQUIRES_FACILITATOR":
+		s.Failure(w, r, http.StatusForbidden, Errorf(EUNAUTHORIZED, err.Error()))
+	case "BATTLE_NOT_FOUND":
+		s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, err.Error()))
+	default:
+		s.Logger.Ctx(r.Context()).Error(handler+" error", zap.Error(err),
+			zap.String("poker_id", gameID), zap.String("session_user_id", sessionUserID))
+		s.Failure(w, r, http.StatusInternalServerError, err)
+	}
+}
@@ -0,0 +1,31 @@
+package poker
+
+import (
+	"context"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/http/clientip"
+)
+
+// clientIP gets the ip address of the request, X-Forwarded-For is only used when the request
+// came through one of the trusted proxies
+func (b *Service) clientIP(r *http.Request) string {
+	ip := clientip.FromRequest(r, b.config.TrustedProxies)
+	if ip == nil {
+		return ""
+	}
+
+	return ip.String()
+}
+
+// logAccess records the user joining or leaving the poker game, failures are only logged
+// as they should never keep a user from joining
+func (b *Service) logAccess(ctx context.Context, pokerID string, userID string, eventType string, ip string, userAgent string) {
+	if err := b.PokerService.LogAccess(ctx, pokerID, userID, eventType, ip, userAgent); err != nil {
+		b.logger.Ctx(ctx).Error("poker access log error", zap.Error(err),
+			zap.String("poker_id", pokerID), zap.String("session_user_id", userID),
+			zap.String("event_type", eventType))
+	}
+}
@@ -0,0 +1,42 @@
+package poker
+
+import (
+	"net"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestClientIP makes sure the client ip is only taken from X-Forwarded-For when the request came through
+// a trusted proxy, otherwise the remote address is used
+func TestClientIP(t *testing.T) {
+	_, proxies, _ := net.ParseCIDR("10.0.0.0/8")
+	b := &Service{config: Config{TrustedProxies: []*net.IPNet{proxies}}}
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  string
+		expected   string
+	}{
+		{name: "remote address", remoteAddr: "192.0.2.1:52000", expected: "192.0.2.1"},
+		{name: "remote address without port", remoteAddr: "192.0.2.1", expected: "192.0.2.1"},
+		{name: "forwarded", remoteAddr: "10.0.0.1:52000", forwarded: "203.0.113.42", expected: "203.0.113.42"},
+		{name: "forwarded through proxies", remoteAddr: "10.0.0.1:52000", forwarded: "203.0.113.42, 10.0.0.2", expected: "203.0.113.42"},
+		{name: "spoofed leftmost address ignored", remoteAddr: "10.0.0.1:52000", forwarded: "198.51.100.7, 203.0.113.42", expected: "203.0.113.42"},
+		{name: "forwarded from an untrusted address ignored", remoteAddr: "192.0.2.1:52000", forwarded: "203.0.113.42", expected: "192.0.2.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/api/arena/game", nil)
+			r.RemoteAddr = tt.remoteAddr
+			if tt.forwarded != "" {
+				r.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+
+			if got := b.clientIP(r); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package poker
+
+import (
+	"context"
+	"errors"
+)
+
+// activeGameOnly wraps the event handlers so they reject events for archived games,
+// archived games are only kept for auditing and can no longer be played
+func (b *Service) activeGameOnly(
+	handlers map[string]func(context.Context, string, string, string) ([]byte, error, bool),
+) map[string]func(context.Context, string, string, string) ([]byte, error, bool) {
+	for eventType, handler := range handlers {
+		handlers[eventType] = func(ctx context.Context, pokerID string, userID string, eventValue string) ([]byte, error, bool) {
+			archived, err := b.PokerService.IsGameArchived(ctx, pokerID)
+			if err != nil {
+				return nil, err, false
+			}
+			if archived {
+				return nil, errors.New("GAME_ARCHIVED"), false
+			}
+
+			return handler(ctx, pokerID, userID, eventValue)
+		}
+	}
+
+	return handlers
+}
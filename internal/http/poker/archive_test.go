@@ -0,0 +1,42 @@
+package poker
+
+import (
+	"context"
+	"testing"
+)
+
+// fakeArchiveDataSvc reports the games archived state
+type fakeArchiveDataSvc struct {
+	PokerDataSvc
+	archived bool
+}
+
+func (f *fakeArchiveDataSvc) IsGameArchived(ctx context.Context, pokerID string) (bool, error) {
+	return f.archived, nil
+}
+
+// TestActiveGameOnly makes sure events for archived games are rejected without reaching their handler
+func TestActiveGameOnly(t *testing.T) {
+	dataSvc := &fakeArchiveDataSvc{}
+	b := &Service{PokerService: dataSvc}
+
+	handled := 0
+	handlers := b.activeGameOnly(map[string]func(context.Context, string, string, string) ([]byte, error, bool){
+		"vote": func(ctx context.Context, pokerID string, userID string, eventValue string) ([]byte, error, bool) {
+			handled++
+			return []byte("voted"), nil, false
+		},
+	})
+
+	if msg, err, _ := handlers["vote"](context.Background(), "game", "user", ""); err != nil || string(msg) != "voted" {
+		t.Fatalf("expected the vote to be handled, got %s %v", msg, err)
+	}
+
+	dataSvc.archived = true
+	if _, err, _ := handlers["vote"](context.Background(), "game", "user", ""); err == nil || err.Error() != "GAME_ARCHIVED" {
+		t.Errorf("expected GAME_ARCHIVED, got %v", err)
+	}
+	if handled != 1 {
+		t.Errorf("expected the archived game's vote not to be handled, got %d handled", handled)
+	}
+}
@@ -0,0 +1,109 @@
+package poker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/wshub"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// chatAudience is who can see spectator only chat messages in a game
+type chatAudience struct {
+	spectators   map[string]bool
+	facilitators map[string]bool
+}
+
+// gameChatAudience gets the game's spectators and facilitators
+func (b *Service) gameChatAudience(pokerID string) chatAudience {
+	audience := chatAudience{spectators: make(map[string]bool), facilitators: make(map[string]bool)}
+	for _, user := range b.PokerService.GetUsers(pokerID) {
+		if user.Spectator {
+			audience.spectators[user.ID] = true
+		}
+	}
+	facilitators, _ := b.PokerService.GetFacilitators(pokerID)
+	for _, facilitator := range facilitators {
+		audience.facilitators[facilitator.UserID] = true
+	}
+
+	return audience
+}
+
+// chatMessageEvent builds the chat_message event for each user, users that can't see the message get no event
+func chatMessageEvent(chat thunderdome.GameChat, audience chatAudience) func(userID string) []byte {
+	chatJSON, _ := json.Marshal(chat)
+	msg := wshub.CreateSocketEvent("chat_message", string(chatJSON), chat.UserID)
+
+	return func(userID string) []byte {
+		if !thunderdome.CanSeeGameChat(chat, audience.spectators[userID], audience.facilitators[userID]) {
+			return nil
+		}
+		return msg
+	}
+}
+
+// ChatMessage handles sending a chat message to the game, spectator only messages
+// are only sent to the game's spectators and facilitators
+func (b *Service) ChatMessage(ctx context.Context, pokerID string, userID string, eventValue string) ([]byte, error, bool) {
+	var cm struct {
+		Message       string `json:"message"`
+		SpectatorOnly bool   `json:"spectatorOnly"`
+	}
+	err := json.Unmarshal([]byte(eventValue), &cm)
+	if err != nil {
+		return nil, err, false
+	}
+
+	audience := b.gameChatAudience(pokerID)
+	if err := thunderdome.CanSendGameChat(cm.SpectatorOnly, audience.spectators[userID], audience.facilitators[userID]); err != nil {
+		return nil, err, false
+	}
+
+	chat, err := b.PokerService.AddChatMessage(ctx, pokerID, userID, cm.Message, cm.SpectatorOnly)
+	if errors.Is(err, thunderdome.ErrGameChatRateLimited) {
+		msg := wshub.CreateSocketEvent("chat_rate_limited", "", userID)
+		b.hub.Broadcast(wshub.Message{Data: msg, Room: pokerID, UserID: userID})
+		return nil, nil, false
+	}
+	if err != nil {
+		return nil, err, false
+	}
+
+	if !chat.IsSpectatorOnly {
+		chatJSON, _ := json.Marshal(chat)
+		return wshub.CreateSocketEvent("chat_message", string(chatJSON), userID), nil, false
+	}
+
+	b.hub.Broadcast(wshub.Message{Room: pokerID, UserData: chatMessageEvent(*chat, audience)})
+
+	return nil, nil, false
+}
+
+// ChatHistory handles sending the user the game's chat messages they can see,
+// the event value optionally limits the history to messages sent after an RFC 3339 timestamp
+func (b *Service) ChatHistory(ctx context.Context, pokerID string, userID string, eventValue string) ([]byte, error, bool) {
+	var since time.Time
+	if eventValue != "" {
+		parsed, err := time.Parse(time.RFC3339, eventValue)
+		if err != nil {
+			return nil, err, false
+		}
+		since = parsed
+	}
+
+	messages, err := b.PokerService.GetChatHistory(ctx, pokerID, since)
+	if err != nil {
+		return nil, err, false
+	}
+
+	audience := b.gameChatAudience(pokerID)
+	visible := thunderdome.VisibleGameChat(messages, audience.spectators[userID], audience.facilitators[userID])
+	historyJSON, _ := json.Marshal(visible)
+	msg := wshub.CreateSocketEvent("chat_history", string(historyJSON), userID)
+	b.hub.Broadcast(wshub.Message{Data: msg, Room: pokerID, UserID: userID})
+
+	return nil, nil, false
+}
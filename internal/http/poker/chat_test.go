@@ -0,0 +1,122 @@
+package poker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+type fakeChatDataSvc struct {
+	PokerDataSvc
+	added   []thunderdome.GameChat
+	addErr  error
+	history []thunderdome.GameChat
+}
+
+func (f *fakeChatDataSvc) GetUsers(pokerID string) []*thunderdome.PokerUser {
+	return []*thunderdome.PokerUser{
+		{ID: "voter", Active: true},
+		{ID: "spectator", Active: true, Spectator: true},
+		{ID: "facilitator", Active: true},
+	}
+}
+
+func (f *fakeChatDataSvc) GetFacilitators(pokerID string) ([]*thunderdome.PokerFacilitator, error) {
+	return []*thunderdome.PokerFacilitator{{UserID: "facilitator", IsPrimaryFacilitator: true}}, nil
+}
+
+func (f *fakeChatDataSvc) AddChatMessage(ctx context.Context, gameID string, userID string, message string, spectatorOnly bool) (*thunderdome.GameChat, error) {
+	if f.addErr != nil {
+		return nil, f.addErr
+	}
+	chat := thunderdome.GameChat{ID: "chat", GameID: gameID, UserID: userID, Message: message, IsSpectatorOnly: spectatorOnly}
+	f.added = append(f.added, chat)
+
+	return &chat, nil
+}
+
+func (f *fakeChatDataSvc) GetChatHistory(ctx context.Context, gameID string, since time.Time) ([]thunderdome.GameChat, error) {
+	return f.history, nil
+}
+
+func (f *fakeChatDataSvc) SubscribeTimeBoxEvents(ctx context.Context) <-chan thunderdome.PokerTimeBoxEvent {
+	return nil
+}
+
+// TestChatMessageToEveryone makes sure messages to everyone are broadcast to the whole game
+func TestChatMessageToEveryone(t *testing.T) {
+	dataSvc := &fakeChatDataSvc{}
+	b := New(Config{}, otelzap.New(zap.NewNop()), nil, nil, nil, nil, dataSvc)
+
+	msg, err, _ := b.ChatMessage(context.Background(), "game", "voter", `{"message":"hello"}`)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	var event struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(msg, &event); err != nil || event.Type != "chat_message" {
+		t.Errorf("expected a chat_message event for the room, got %s", msg)
+	}
+	if len(dataSvc.added) != 1 || dataSvc.added[0].IsSpectatorOnly {
+		t.Errorf("expected a message to everyone to be added, got %+v", dataSvc.added)
+	}
+}
+
+// TestChatMessageSpectatorOnly makes sure voting participants can't send spectator only messages
+func TestChatMessageSpectatorOnly(t *testing.T) {
+	dataSvc := &fakeChatDataSvc{}
+	b := New(Config{}, otelzap.New(zap.NewNop()), nil, nil, nil, nil, dataSvc)
+
+	if _, err, _ := b.ChatMessage(context.Background(), "game", "voter", `{"message":"psst","spectatorOnly":true}`); !errors.Is(err, thunderdome.ErrSpectatorChatNotAllowed) {
+		t.Errorf("expected %v, got %v", thunderdome.ErrSpectatorChatNotAllowed, err)
+	}
+	if len(dataSvc.added) != 0 {
+		t.Errorf("expected no message to be added, got %+v", dataSvc.added)
+	}
+
+	msg, err, _ := b.ChatMessage(context.Background(), "game", "spectator", `{"message":"psst","spectatorOnly":true}`)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if msg != nil {
+		t.Errorf("expected the spectator only message to be broadcast per user, got %s", msg)
+	}
+}
+
+// TestChatMessageEventAudience makes sure spectator only messages only reach spectators and facilitators
+func TestChatMessageEventAudience(t *testing.T) {
+	dataSvc := &fakeChatDataSvc{}
+	b := New(Config{}, otelzap.New(zap.NewNop()), nil, nil, nil, nil, dataSvc)
+	audience := b.gameChatAudience("game")
+
+	spectatorOnly := chatMessageEvent(thunderdome.GameChat{ID: "1", UserID: "spectator", IsSpectatorOnly: true}, audience)
+	if spectatorOnly("voter") != nil {
+		t.Error("expected voting participants not to receive spectator only messages")
+	}
+	if spectatorOnly("spectator") == nil || spectatorOnly("facilitator") == nil {
+		t.Error("expected spectators and facilitators to receive spectator only messages")
+	}
+
+	everyone := chatMessageEvent(thunderdome.GameChat{ID: "2", UserID: "spectator"}, audience)
+	if everyone("voter") == nil {
+		t.Error("expected voting participants to receive messages to everyone")
+	}
+}
+
+// TestChatMessageRateLimited makes sure rate limited messages aren't broadcast or treated as errors
+func TestChatMessageRateLimited(t *testing.T) {
+	dataSvc := &fakeChatDataSvc{addErr: thunderdome.ErrGameChatRateLimited}
+	b := New(Config{}, otelzap.New(zap.NewNop()), nil, nil, nil, nil, dataSvc)
+
+	msg, err, _ := b.ChatMessage(context.Background(), "game", "voter", `{"message":"spam"}`)
+	if err != nil || msg != nil {
+		t.Errorf("expected the rate limit to only be sent to the user, got %s %v", msg, err)
+	}
+}
@@ -5,7 +5,9 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/StevenWeathers/thunderdome-planning-poker/internal/wshub"
 
@@ -71,6 +73,17 @@ func (b *Service) ServeBattleWs() http.HandlerFunc {
 			}
 			return &authErr
 		}
+		if battle.ArchivedAt != nil {
+			authErr := wshub.AuthError{
+				Code:    4004,
+				Message: "poker game archived",
+			}
+			return &authErr
+		}
+
+		// observer join links grant access to the game as a spectator
+		observerCode, _ := b.PokerService.GetObserverCode(roomID)
+		_, joinAsObserver := JoinCodeAccess(r.URL.Query().Get(observerQueryParam), "", observerCode)
 
 		// check users battle active status
 		userErr := b.PokerService.GetUserActiveStatus(roomID, user.ID)
@@ -93,7 +106,7 @@ func (b *Service) ServeBattleWs() http.HandlerFunc {
 				}
 			}
 			return &authErr
-		} else if (userErr != nil && errors.Is(userErr, sql.ErrNoRows)) && battle.JoinCode != "" {
+		} else if (userErr != nil && errors.Is(userErr, sql.ErrNoRows)) && battle.JoinCode != "" && !joinAsObserver {
 			jcrEvent := wshub.CreateSocketEvent("join_code_required", "", user.ID)
 			_ = c.Write(websocket.TextMessage, jcrEvent)
 
@@ -114,8 +127,10 @@ func (b *Service) ServeBattleWs() http.HandlerFunc {
 						zap.String("poker_id", roomID), zap.String("session_user_id", user.ID))
 				}
 
-				if keyVal["type"] == "auth_game" && keyVal["value"] == battle.JoinCode {
+				authorized, spectator := JoinCodeAccess(keyVal["value"], battle.JoinCode, observerCode)
+				if keyVal["type"] == "auth_game" && authorized {
 					// join code is valid, continue to room
+					joinAsObserver = spectator
 					break
 				} else if keyVal["type"] == "auth_game" {
 					authIncorrect := wshub.CreateSocketEvent("join_code_incorrect", "", user.ID)
@@ -127,8 +142,21 @@ func (b *Service) ServeBattleWs() http.HandlerFunc {
 		sub := b.hub.NewSubscriber(c.Ws, user.ID, roomID)
 
 		users, _ := b.PokerService.AddUser(roomID, user.ID)
+		b.clearDisconnect(roomID, user.ID)
+		ip, userAgent := b.clientIP(r), r.UserAgent()
+		b.logAccess(ctx, roomID, user.ID, thunderdome.PokerAccessEventJoin, ip, userAgent)
+		if joinAsObserver {
+			if spectatorUsers, err := b.PokerService.SetObserver(roomID, user.ID); err == nil {
+				users = spectatorUsers
+				battle.Users = spectatorUsers
+			}
+		}
 		updatedUsers, _ := json.Marshal(users)
 
+		if timeBox, err := b.PokerService.GetStoryTimeBox(ctx, roomID); err == nil {
+			battle.TimeBox = timeBox
+		}
+		b.setActiveStoryEstimationScale(ctx, battle, battle.Stories)
 		Battle, _ := json.Marshal(battle)
 		initEvent := wshub.CreateSocketEvent("init", string(Battle), user.ID)
 		_ = sub.Conn.Write(websocket.TextMessage, initEvent)
@@ -137,7 +165,10 @@ func (b *Service) ServeBattleWs() http.HandlerFunc {
 		b.hub.Broadcast(wshub.Message{Data: userJoinedEvent, Room: roomID})
 
 		go sub.WritePump()
-		go sub.ReadPump(ctx, b.hub)
+		go func() {
+			sub.ReadPump(ctx, b.hub)
+			b.logAccess(context.WithoutCancel(ctx), roomID, user.ID, thunderdome.PokerAccessEventLeave, ip, userAgent)
+		}()
 
 		return nil
 	})
@@ -145,6 +176,7 @@ func (b *Service) ServeBattleWs() http.HandlerFunc {
 
 func (b *Service) RetreatUser(roomID string, userID string) string {
 	users := b.PokerService.RetreatUser(roomID, userID)
+	b.trackDisconnect(roomID, userID, time.Now())
 	updatedUsers, _ := json.Marshal(users)
 
 	return string(updatedUsers)
@@ -154,3 +186,125 @@ func (b *Service) RetreatUser(roomID string, userID string) string {
 func (b *Service) APIEvent(ctx context.Context, pokerID string, userID, eventType string, eventValue string) error {
 	return b.hub.ProcessAPIEventHandler(ctx, userID, pokerID, eventType, eventValue)
 }
+
+// ImportStories handles api driven bulk import of stories into the poker game,
+// broadcasting the updated stories to the game (if active)
+func (b *Service) ImportStories(ctx context.Context, pokerID string, userID string, stories []*thunderdome.Story, deduplicate bool) (*thunderdome.DuplicationResult, error) {
+	if err := b.PokerService.ConfirmFacilitator(pokerID, userID); err != nil {
+		return nil, err
+	}
+
+	result, err := b.PokerService.BulkAddStories(ctx, pokerID, stories, deduplicate)
+	if err != nil {
+		return nil, err
+	}
+
+	if b.hub.RoomExists(pokerID) {
+		updatedStories, _ := json.Marshal(b.PokerService.GetStories(pokerID, ""))
+		msg := wshub.CreateSocketEvent("plan_added", string(updatedStories), "")
+		b.hub.Broadcast(wshub.Message{Data: msg, Room: pokerID})
+	}
+
+	return result, nil
+}
+
+// ImportStoriesFromCSV handles api driven import of stories from a csv file into the poker game,
+// broadcasting the updated stories to the game (if active) even when some rows couldn't be imported
+func (b *Service) ImportStoriesFromCSV(ctx context.Context, pokerID string, userID string, csvData []byte, columnMapping map[string]string, deduplicate bool) ([]*thunderdome.Story, *thunderdome.DuplicationResult, error) {
+	if err := b.PokerService.ConfirmFacilitator(pokerID, userID); err != nil {
+		return nil, nil, err
+	}
+
+	stories, duplication, err := b.PokerService.ImportStoriesFromCSV(ctx, pokerID, csvData, columnMapping, deduplicate)
+	var rowErrors thunderdome.StoryCSVImportRowErrors
+	if err != nil && !errors.As(err, &rowErrors) {
+		return nil, nil, err
+	}
+
+	if len(stories) > 0 && b.hub.RoomExists(pokerID) {
+		updatedStories, _ := json.Marshal(b.PokerService.GetStories(pokerID, ""))
+		msg := wshub.CreateSocketEvent("plan_added", string(updatedStories), "")
+		b.hub.Broadcast(wshub.Message{Data: msg, Room: pokerID})
+	}
+
+	return stories, duplication, err
+}
+
+// UpdateStoryPriorities handles api driven bulk priority updates of the poker game stories,
+// updates for stories not in the game fail individually while the rest are applied and broadcast to the game (if active)
+func (b *Service) UpdateStoryPriorities(ctx context.Context, pokerID string, userID string, updates []thunderdome.StoryPriorityUpdate) ([]thunderdome.StoryPriorityResult, error) {
+	if err := b.PokerService.ConfirmFacilitator(pokerID, userID); err != nil {
+		return nil, err
+	}
+
+	applicable, results := thunderdome.SplitStoryPriorityUpdates(b.PokerService.GetStories(pokerID, ""), updates)
+	if len(applicable) == 0 {
+		return results, nil
+	}
+
+	if err := b.PokerService.BulkUpdateStoryPriority(ctx, pokerID, applicable); err != nil {
+		return nil, err
+	}
+
+	if b.hub.RoomExists(pokerID) {
+		updatedStories, _ := json.Marshal(b.PokerService.GetStories(pokerID, ""))
+		msg := wshub.CreateSocketEvent("plan_revised", string(updatedStories), "")
+		b.hub.Broadcast(wshub.Message{Data: msg, Room: pokerID})
+	}
+
+	return results, nil
+}
+
+// AssignStoriesToSprint assigns the game stories to the sprint, an empty sprint name removes the stories
+// from their sprint, the updated stories are sent to the game's websocket room
+func (b *Service) AssignStoriesToSprint(ctx context.Context, pokerID string, userID string, sprint thunderdome.StorySprint, storyIDs []string) error {
+	if err := b.PokerService.ConfirmFacilitator(pokerID, userID); err != nil {
+		return fmt.Errorf("REQUIRES_FACILITATOR")
+	}
+
+	if err := b.PokerService.BulkUpdateStorySprint(ctx, pokerID, sprint, storyIDs); err != nil {
+		return err
+	}
+
+	if b.hub.RoomExists(pokerID) {
+		updatedStories, _ := json.Marshal(b.PokerService.GetStories(pokerID, ""))
+		msg := wshub.CreateSocketEvent("plan_revised", string(updatedStories), "")
+		b.hub.Broadcast(wshub.Message{Data: msg, Room: pokerID})
+	}
+
+	return nil
+}
+
+// UpdateStoryStatus moves the game stories to the status, the updated stories are sent to the game's websocket room
+func (b *Service) UpdateStoryStatus(ctx context.Context, pokerID string, userID string, status string, storyIDs []string) error {
+	if err := b.PokerService.ConfirmFacilitator(pokerID, userID); err != nil {
+		return fmt.Errorf("REQUIRES_FACILITATOR")
+	}
+
+	if err := b.PokerService.BulkUpdateStoryStatus(ctx, pokerID, status, storyIDs); err != nil {
+		return err
+	}
+
+	if b.hub.RoomExists(pokerID) {
+		updatedStories, _ := json.Marshal(b.PokerService.GetStories(pokerID, ""))
+		msg := wshub.CreateSocketEvent("story_status_updated", string(updatedStories), "")
+		b.hub.Broadcast(wshub.Message{Data: msg, Room: pokerID})
+	}
+
+	return nil
+}
+
+// Shutdown gracefully closes all websocket connections, see wshub.Hub.Shutdown
+func (b *Service) Shutdown(ctx context.Context) error {
+	return b.hub.Shutdown(ctx)
+}
+
+// EvictIdleConnections closes idle websocket connections that don't respond to a ping, see wshub.Hub.EvictIdleConnections
+func (b *Service) EvictIdleConnections(idleMinutes int) int {
+	return b.hub.EvictIdleConnections(idleMinutes)
+}
+
+// SendToUser sends the message to the user's websocket connections, see wshub.Hub.SendToUser
+func (b *Service) SendToUser(userID string, data []byte) {
+	b.hub.SendToUser(userID, data)
+}
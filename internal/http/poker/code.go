@@ -0,0 +1,49 @@
+package poker
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/wshub"
+)
+
+// joinCodeChanged is the join_code_changed event value, the facilitator code itself is never broadcast
+// as every participant in the game receives the event
+type joinCodeChanged struct {
+	CodeType string `json:"codeType"`
+	JoinCode string `json:"joinCode,omitempty"`
+}
+
+// RegenerateJoinCode handles api driven join code rotation, broadcasting the new code to the game (if active)
+func (b *Service) RegenerateJoinCode(ctx context.Context, pokerID string, userID string) (string, error) {
+	code, err := b.PokerService.RegenerateJoinCode(ctx, pokerID, userID)
+	if err != nil {
+		return "", err
+	}
+
+	b.broadcastCodeChanged(pokerID, joinCodeChanged{CodeType: "join", JoinCode: code})
+
+	return code, nil
+}
+
+// RegenerateFacilitatorCode handles api driven facilitator code rotation, notifying the game (if active)
+func (b *Service) RegenerateFacilitatorCode(ctx context.Context, pokerID string, userID string) (string, error) {
+	code, err := b.PokerService.RegenerateFacilitatorCode(ctx, pokerID, userID)
+	if err != nil {
+		return "", err
+	}
+
+	b.broadcastCodeChanged(pokerID, joinCodeChanged{CodeType: "facilitator"})
+
+	return code, nil
+}
+
+func (b *Service) broadcastCodeChanged(pokerID string, changed joinCodeChanged) {
+	if !b.hub.RoomExists(pokerID) {
+		return
+	}
+
+	value, _ := json.Marshal(changed)
+	msg := wshub.CreateSocketEvent("join_code_changed", string(value), "")
+	b.hub.Broadcast(wshub.Message{Data: msg, Room: pokerID})
+}
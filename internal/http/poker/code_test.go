@@ -0,0 +1,70 @@
+package poker
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+type fakeCodeDataSvc struct {
+	PokerDataSvc
+	joinCode    string
+	facilitator string
+}
+
+func (f *fakeCodeDataSvc) RegenerateJoinCode(ctx context.Context, pokerID string, facilitatorID string) (string, error) {
+	if facilitatorID != f.facilitator {
+		return "", errors.New("REQUIRES_FACILITATOR")
+	}
+	f.joinCode = f.joinCode + "-rotated"
+
+	return f.joinCode, nil
+}
+
+func (f *fakeCodeDataSvc) GetGameByID(pokerID string, userID string) (*thunderdome.Poker, error) {
+	return &thunderdome.Poker{ID: pokerID, JoinCode: f.joinCode}, nil
+}
+
+func (f *fakeCodeDataSvc) SubscribeTimeBoxEvents(ctx context.Context) <-chan thunderdome.PokerTimeBoxEvent {
+	return nil
+}
+
+// TestRegenerateJoinCodeRevokesOldCode makes sure the old join code no longer authorizes joining once rotated
+func TestRegenerateJoinCodeRevokesOldCode(t *testing.T) {
+	const oldCode = "old-code"
+	dataSvc := &fakeCodeDataSvc{joinCode: oldCode, facilitator: "facilitator"}
+	b := New(Config{}, otelzap.New(zap.NewNop()), nil, nil, nil, nil, dataSvc)
+
+	newCode, err := b.RegenerateJoinCode(context.Background(), "game", "facilitator")
+	if err != nil {
+		t.Fatalf("expected the join code to be regenerated, got %v", err)
+	}
+	if newCode == oldCode {
+		t.Fatalf("expected a new join code, got the old code")
+	}
+
+	game, _ := dataSvc.GetGameByID("game", "")
+	if authorized, _ := JoinCodeAccess(oldCode, game.JoinCode, ""); authorized {
+		t.Errorf("expected the old join code to be rejected")
+	}
+	if authorized, _ := JoinCodeAccess(newCode, game.JoinCode, ""); !authorized {
+		t.Errorf("expected the new join code to be accepted")
+	}
+}
+
+// TestRegenerateJoinCodeRequiresFacilitator makes sure only a facilitator can rotate the join code
+func TestRegenerateJoinCodeRequiresFacilitator(t *testing.T) {
+	dataSvc := &fakeCodeDataSvc{joinCode: "old-code", facilitator: "facilitator"}
+	b := New(Config{}, otelzap.New(zap.NewNop()), nil, nil, nil, nil, dataSvc)
+
+	if _, err := b.RegenerateJoinCode(context.Background(), "game", "participant"); err == nil || err.Error() != "REQUIRES_FACILITATOR" {
+		t.Fatalf("expected REQUIRES_FACILITATOR, got %v", err)
+	}
+	if dataSvc.joinCode != "old-code" {
+		t.Errorf("expected the join code to be unchanged, got %s", dataSvc.joinCode)
+	}
+}
@@ -0,0 +1,25 @@
+package poker
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/wshub"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// AddStoryComment handles api driven story comments, broadcasting the new comment to the game (if active)
+func (b *Service) AddStoryComment(ctx context.Context, storyID string, userID string, parentID string, body string) (*thunderdome.PokerStoryComment, error) {
+	comment, err := b.PokerService.AddComment(ctx, storyID, userID, parentID, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if b.hub.RoomExists(comment.PokerID) {
+		addedComment, _ := json.Marshal(comment)
+		msg := wshub.CreateSocketEvent("story_comment_added", string(addedComment), userID)
+		b.hub.Broadcast(wshub.Message{Data: msg, Room: comment.PokerID})
+	}
+
+	return comment, nil
+}
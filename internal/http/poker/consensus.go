@@ -0,0 +1,65 @@
+package poker
+
+import (
+	"encoding/json"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/wshub"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+// votingEndedEvent creates the voting_ended event for the story, when the votes reach consensus
+// voting_ended is broadcast first and a vote_consensus event is returned instead, finalizing the
+// story with the consensus value when the game has AutoFinalizeOnConsensus enabled and its quorum voted
+func (b *Service) votingEndedEvent(pokerID string, storyID string, stories []*thunderdome.Story) []byte {
+	updatedStories, _ := json.Marshal(stories)
+	msg := wshub.CreateSocketEvent("voting_ended", string(updatedStories), "")
+
+	var story *thunderdome.Story
+	for _, s := range stories {
+		if s.ID == storyID {
+			story = s
+			break
+		}
+	}
+	if story == nil {
+		return msg
+	}
+
+	game, err := b.PokerService.GetGameByID(pokerID, "")
+	if err != nil {
+		b.logger.Error("poker consensus get game error", zap.Error(err), zap.String("poker_id", pokerID))
+		return msg
+	}
+
+	consensus, points := thunderdome.VoteConsensus(story.Votes, game.PointValuesAllowed)
+	if !consensus {
+		return msg
+	}
+
+	b.hub.Broadcast(wshub.Message{Data: msg, Room: pokerID})
+
+	autoFinalized := false
+	if game.AutoFinalizeOnConsensus && b.confirmQuorum(pokerID, game, story) {
+		finalizedStories, err := b.PokerService.FinalizeStory(pokerID, storyID, points)
+		if err != nil {
+			b.logger.Error("poker consensus finalize story error", zap.Error(err),
+				zap.String("poker_id", pokerID), zap.String("story_id", storyID))
+		} else {
+			autoFinalized = true
+			finalizedJSON, _ := json.Marshal(finalizedStories)
+			b.hub.Broadcast(wshub.Message{
+				Data: wshub.CreateSocketEvent("plan_finalized", string(finalizedJSON), ""),
+				Room: pokerID,
+			})
+		}
+	}
+
+	consensusJSON, _ := json.Marshal(map[string]interface{}{
+		"planId":        storyID,
+		"points":        points,
+		"autoFinalized": autoFinalized,
+	})
+
+	return wshub.CreateSocketEvent("vote_consensus", string(consensusJSON), "")
+}
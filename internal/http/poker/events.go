@@ -29,6 +29,10 @@ func (b *Service) UserVote(ctx context.Context, pokerID string, userID string, e
 		return nil, err, false
 	}
 
+	if isSpectator(b.PokerService.GetUsers(pokerID), userID) {
+		return nil, errors.New("SPECTATOR_CANNOT_VOTE"), false
+	}
+
 	storys, allVoted := b.PokerService.SetVote(pokerID, userID, wv.StoryID, wv.VoteValue)
 
 	updatedStorys, _ := json.Marshal(storys)
@@ -39,8 +43,8 @@ func (b *Service) UserVote(ctx context.Context, pokerID string, userID string, e
 		if err != nil {
 			return nil, err, false
 		}
-		updatedStorys, _ := json.Marshal(plans)
-		msg = wshub.CreateSocketEvent("voting_ended", string(updatedStorys), "")
+		b.stopTimeBox(ctx, pokerID)
+		msg = b.votingEndedEvent(pokerID, wv.StoryID, plans)
 	}
 
 	return msg, nil, false
@@ -112,13 +116,34 @@ func (b *Service) UserPromoteSelf(ctx context.Context, pokerID string, userID st
 // UserSpectatorToggle handles toggling user spectator status
 func (b *Service) UserSpectatorToggle(ctx context.Context, pokerID string, userID string, eventValue string) ([]byte, error, bool) {
 	var st struct {
-		Spectator bool `json:"spectator"`
+		Spectator bool   `json:"spectator"`
+		UserID    string `json:"userId"`
 	}
 	err := json.Unmarshal([]byte(eventValue), &st)
 	if err != nil {
 		return nil, err, false
 	}
-	users, err := b.PokerService.ToggleSpectator(pokerID, userID, st.Spectator)
+
+	targetUserID := userID
+	if st.UserID != "" && st.UserID != userID {
+		if err := b.PokerService.ConfirmFacilitator(pokerID, userID); err != nil {
+			return nil, errors.New("REQUIRES_FACILITATOR"), false
+		}
+		targetUserID = st.UserID
+	} else if !st.Spectator {
+		// observers can only be made participants by a facilitator
+		observer, err := b.PokerService.IsObserver(pokerID, userID)
+		if err != nil {
+			return nil, err, false
+		}
+		if observer {
+			if err := b.PokerService.ConfirmFacilitator(pokerID, userID); err != nil {
+				return nil, errors.New("OBSERVER_REQUIRES_FACILITATOR"), false
+			}
+		}
+	}
+
+	users, err := b.PokerService.ToggleSpectator(pokerID, targetUserID, st.Spectator)
 	if err != nil {
 		return nil, err, false
 	}
@@ -135,8 +160,8 @@ func (b *Service) StoryVoteEnd(ctx context.Context, pokerID string, userID strin
 	if err != nil {
 		return nil, err, false
 	}
-	updatedStories, _ := json.Marshal(plans)
-	msg := wshub.CreateSocketEvent("voting_ended", string(updatedStories), "")
+	b.stopTimeBox(ctx, pokerID)
+	msg := b.votingEndedEvent(pokerID, eventValue, plans)
 
 	return msg, nil, false
 }
@@ -144,19 +169,39 @@ func (b *Service) StoryVoteEnd(ctx context.Context, pokerID string, userID strin
 // Revise handles editing the poker game settings
 func (b *Service) Revise(ctx context.Context, pokerID string, userID string, eventValue string) ([]byte, error, bool) {
 	var rb struct {
-		BattleName           string   `json:"battleName"`
-		PointValuesAllowed   []string `json:"pointValuesAllowed"`
-		AutoFinishVoting     bool     `json:"autoFinishVoting"`
-		PointAverageRounding string   `json:"pointAverageRounding"`
-		HideVoterIdentity    bool     `json:"hideVoterIdentity"`
-		JoinCode             string   `json:"joinCode"`
-		LeaderCode           string   `json:"leaderCode"`
-		TeamID               string   `json:"teamId"`
+		BattleName              string            `json:"battleName"`
+		PointValuesAllowed      []string          `json:"pointValuesAllowed"`
+		AutoFinishVoting        bool              `json:"autoFinishVoting"`
+		PointAverageRounding    string            `json:"pointAverageRounding"`
+		HideVoterIdentity       bool              `json:"hideVoterIdentity"`
+		AutoFinalizeOnConsensus bool              `json:"autoFinalizeOnConsensus"`
+		MinParticipants         int               `json:"minParticipants"`
+		TimeBoxMinutes          int               `json:"timeBoxMinutes"`
+		QuorumPercentage        float64           `json:"quorumPercentage"`
+		StoryTypeScaleMap       map[string]string `json:"storyTypeScaleMap"`
+		JoinCode                string            `json:"joinCode"`
+		LeaderCode              string            `json:"leaderCode"`
+		ObserverCode            string            `json:"observerCode"`
+		TeamID                  string            `json:"teamId"`
 	}
 	err := json.Unmarshal([]byte(eventValue), &rb)
 	if err != nil {
 		return nil, err, false
 	}
+	if rb.MinParticipants < 0 {
+		return nil, errors.New("INVALID_MIN_PARTICIPANTS"), false
+	}
+	if rb.TimeBoxMinutes < 0 {
+		return nil, errors.New("INVALID_TIMEBOX_MINUTES"), false
+	}
+	if rb.QuorumPercentage < 0 || rb.QuorumPercentage > 100 {
+		return nil, errors.New("INVALID_QUORUM_PERCENTAGE"), false
+	}
+	for _, scaleID := range rb.StoryTypeScaleMap {
+		if _, err := b.PokerService.GetEstimationScale(ctx, scaleID); err != nil {
+			return nil, errors.New("INVALID_STORY_TYPE_SCALE"), false
+		}
+	}
 
 	err = b.PokerService.UpdateGame(
 		pokerID,
@@ -165,8 +210,14 @@ func (b *Service) Revise(ctx context.Context, pokerID string, userID string, eve
 		rb.AutoFinishVoting,
 		rb.PointAverageRounding,
 		rb.HideVoterIdentity,
+		rb.AutoFinalizeOnConsensus,
+		rb.MinParticipants,
+		rb.TimeBoxMinutes,
+		rb.QuorumPercentage,
+		rb.StoryTypeScaleMap,
 		rb.JoinCode,
 		rb.LeaderCode,
+		rb.ObserverCode,
 		rb.TeamID,
 	)
 	if err != nil {
@@ -174,6 +225,7 @@ func (b *Service) Revise(ctx context.Context, pokerID string, userID string, eve
 	}
 
 	rb.LeaderCode = ""
+	rb.ObserverCode = ""
 
 	updatedBattle, _ := json.Marshal(rb)
 	msg := wshub.CreateSocketEvent("battle_revised", string(updatedBattle), "")
@@ -181,9 +233,9 @@ func (b *Service) Revise(ctx context.Context, pokerID string, userID string, eve
 	return msg, nil, false
 }
 
-// Delete handles deleting the poker game
+// Delete handles deleting the poker game, it's archived rather than permanently deleted
 func (b *Service) Delete(ctx context.Context, pokerID string, userID string, eventValue string) ([]byte, error, bool) {
-	err := b.PokerService.DeleteGame(pokerID)
+	err := b.PokerService.ArchiveGame(ctx, pokerID)
 	if err != nil {
 		return nil, err, false
 	}
@@ -280,10 +332,21 @@ func (b *Service) StoryArrange(ctx context.Context, pokerID string, userID strin
 
 // StoryActivate handles activating a story for voting
 func (b *Service) StoryActivate(ctx context.Context, pokerID string, userID string, eventValue string) ([]byte, error, bool) {
-	plans, err := b.PokerService.ActivateStoryVoting(pokerID, eventValue)
+	activation := parseStoryActivation(eventValue)
+	if !activation.Force {
+		if err := b.confirmMinParticipants(pokerID); err != nil {
+			return nil, err, false
+		}
+	}
+
+	plans, err := b.PokerService.ActivateStoryVoting(pokerID, activation.StoryID)
 	if err != nil {
 		return nil, err, false
 	}
+	b.startTimeBox(ctx, pokerID, activation.StoryID)
+	if game, err := b.PokerService.GetGameByID(pokerID, userID); err == nil {
+		b.setActiveStoryEstimationScale(ctx, game, plans)
+	}
 	updatedStorys, _ := json.Marshal(plans)
 	msg := wshub.CreateSocketEvent("plan_activated", string(updatedStorys), "")
 
@@ -296,6 +359,7 @@ func (b *Service) StorySkip(ctx context.Context, pokerID string, userID string,
 	if err != nil {
 		return nil, err, false
 	}
+	b.stopTimeBox(ctx, pokerID)
 	updatedStorys, _ := json.Marshal(plans)
 	msg := wshub.CreateSocketEvent("plan_skipped", string(updatedStorys), "")
 
@@ -317,6 +381,7 @@ func (b *Service) StoryFinalize(ctx context.Context, pokerID string, userID stri
 	if err != nil {
 		return nil, err, false
 	}
+	b.stopTimeBox(ctx, pokerID)
 	updatedStorys, _ := json.Marshal(plans)
 	msg := wshub.CreateSocketEvent("plan_finalized", string(updatedStorys), "")
 
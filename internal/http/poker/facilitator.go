@@ -0,0 +1,112 @@
+package poker
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/wshub"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+const (
+	// primaryFacilitatorInactivityTimeout is how long the primary facilitator can be disconnected
+	// before the role is handed over to the next facilitator
+	primaryFacilitatorInactivityTimeout = 10 * time.Minute
+	// facilitatorInactivityCheckInterval is how often disconnected primary facilitators are checked
+	facilitatorInactivityCheckInterval = time.Minute
+)
+
+// gameUser identifies a user within a poker game
+type gameUser struct {
+	pokerID string
+	userID  string
+}
+
+// UserPrimaryTransfer handles the primary facilitator handing the role over to another facilitator
+func (b *Service) UserPrimaryTransfer(ctx context.Context, pokerID string, userID string, eventValue string) ([]byte, error, bool) {
+	if err := b.PokerService.TransferPrimaryFacilitator(ctx, pokerID, userID, eventValue); err != nil {
+		return nil, err, false
+	}
+
+	return b.facilitatorChangedEvent(pokerID), nil, false
+}
+
+// facilitatorChangedEvent creates the facilitator_changed event with the game users and their facilitator roles
+func (b *Service) facilitatorChangedEvent(pokerID string) []byte {
+	users := b.PokerService.GetUsers(pokerID)
+	updatedUsers, _ := json.Marshal(users)
+
+	return wshub.CreateSocketEvent("facilitator_changed", string(updatedUsers), "")
+}
+
+// trackDisconnect records when a user left the game so an inactive primary facilitator can be replaced
+func (b *Service) trackDisconnect(pokerID string, userID string, at time.Time) {
+	b.facilitatorDisconnects.Store(gameUser{pokerID: pokerID, userID: userID}, at)
+}
+
+// clearDisconnect forgets a user's disconnect once they rejoin the game
+func (b *Service) clearDisconnect(pokerID string, userID string) {
+	b.facilitatorDisconnects.Delete(gameUser{pokerID: pokerID, userID: userID})
+}
+
+// runFacilitatorInactivityCheck periodically promotes a new primary facilitator
+// for games whose primary facilitator has been disconnected for too long
+func (b *Service) runFacilitatorInactivityCheck(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		b.promoteInactivePrimaryFacilitators(context.Background(), now)
+	}
+}
+
+// promoteInactivePrimaryFacilitators hands the primary facilitator role over for every
+// disconnected user that has been inactive past the timeout and is still the primary facilitator
+func (b *Service) promoteInactivePrimaryFacilitators(ctx context.Context, now time.Time) {
+	b.facilitatorDisconnects.Range(func(key, value any) bool {
+		if now.Sub(value.(time.Time)) < primaryFacilitatorInactivityTimeout {
+			return true
+		}
+		b.facilitatorDisconnects.Delete(key)
+
+		user := key.(gameUser)
+		b.promoteInactivePrimaryFacilitator(ctx, user.pokerID, user.userID)
+
+		return true
+	})
+}
+
+// promoteInactivePrimaryFacilitator replaces the inactive primary facilitator with the longest serving facilitator
+func (b *Service) promoteInactivePrimaryFacilitator(ctx context.Context, pokerID string, userID string) {
+	// the user rejoined from another connection in the meantime
+	if err := b.PokerService.GetUserActiveStatus(pokerID, userID); err != nil && err.Error() == "DUPLICATE_BATTLE_USER" {
+		return
+	}
+
+	facilitators, err := b.PokerService.GetFacilitators(pokerID)
+	if err != nil {
+		b.logger.Ctx(ctx).Error("get poker facilitators error", zap.Error(err),
+			zap.String("poker_id", pokerID))
+		return
+	}
+	if !thunderdome.IsPrimaryFacilitator(facilitators, userID) {
+		return
+	}
+
+	newPrimaryID := thunderdome.NextPrimaryFacilitator(facilitators, userID)
+	if newPrimaryID == "" {
+		return
+	}
+
+	if err := b.PokerService.TransferPrimaryFacilitator(ctx, pokerID, userID, newPrimaryID); err != nil {
+		b.logger.Ctx(ctx).Error("poker auto promote primary facilitator error", zap.Error(err),
+			zap.String("poker_id", pokerID), zap.String("session_user_id", userID))
+		return
+	}
+
+	if b.hub.RoomExists(pokerID) {
+		b.hub.Broadcast(wshub.Message{Data: b.facilitatorChangedEvent(pokerID), Room: pokerID})
+	}
+}
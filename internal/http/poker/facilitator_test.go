@@ -0,0 +1,126 @@
+package poker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+type fakeFacilitatorDataSvc struct {
+	PokerDataSvc
+	facilitators []*thunderdome.PokerFacilitator
+	activeUsers  map[string]bool
+	transfers    [][2]string
+}
+
+func (f *fakeFacilitatorDataSvc) ConfirmFacilitator(pokerID string, userID string) error {
+	return nil
+}
+
+func (f *fakeFacilitatorDataSvc) GetUserActiveStatus(pokerID string, userID string) error {
+	if f.activeUsers[userID] {
+		return errors.New("DUPLICATE_BATTLE_USER")
+	}
+
+	return nil
+}
+
+func (f *fakeFacilitatorDataSvc) GetFacilitators(pokerID string) ([]*thunderdome.PokerFacilitator, error) {
+	return f.facilitators, nil
+}
+
+func (f *fakeFacilitatorDataSvc) GetUsers(pokerID string) []*thunderdome.PokerUser {
+	return []*thunderdome.PokerUser{}
+}
+
+func (f *fakeFacilitatorDataSvc) TransferPrimaryFacilitator(ctx context.Context, pokerID string, currentPrimaryID string, newPrimaryID string) error {
+	if err := thunderdome.ValidatePrimaryFacilitatorTransfer(f.facilitators, currentPrimaryID, newPrimaryID); err != nil {
+		return err
+	}
+	for _, facilitator := range f.facilitators {
+		facilitator.IsPrimaryFacilitator = facilitator.UserID == newPrimaryID
+	}
+	f.transfers = append(f.transfers, [2]string{currentPrimaryID, newPrimaryID})
+
+	return nil
+}
+
+func (f *fakeFacilitatorDataSvc) SubscribeTimeBoxEvents(ctx context.Context) <-chan thunderdome.PokerTimeBoxEvent {
+	return nil
+}
+
+func newFacilitatorTestService() (*Service, *fakeFacilitatorDataSvc) {
+	created := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	dataSvc := &fakeFacilitatorDataSvc{
+		facilitators: []*thunderdome.PokerFacilitator{
+			{UserID: "primary", IsPrimaryFacilitator: true, CreatedDate: created},
+			{UserID: "second", CreatedDate: created.Add(time.Minute)},
+			{UserID: "third", CreatedDate: created.Add(time.Hour)},
+		},
+		activeUsers: map[string]bool{},
+	}
+
+	return New(Config{}, otelzap.New(zap.NewNop()), nil, nil, nil, nil, dataSvc), dataSvc
+}
+
+// TestUserPrimaryTransferNonFacilitator makes sure the primary role can't be handed to a non facilitator
+func TestUserPrimaryTransferNonFacilitator(t *testing.T) {
+	b, dataSvc := newFacilitatorTestService()
+
+	_, err, _ := b.UserPrimaryTransfer(context.Background(), "game", "primary", "participant")
+	if err == nil || err.Error() != "NOT_A_FACILITATOR" {
+		t.Fatalf("expected NOT_A_FACILITATOR error, got %v", err)
+	}
+	if len(dataSvc.transfers) != 0 {
+		t.Errorf("expected no transfer, got %v", dataSvc.transfers)
+	}
+}
+
+// TestPromoteInactivePrimaryFacilitator makes sure the second-oldest facilitator is promoted
+// once the primary facilitator has been disconnected past the inactivity timeout
+func TestPromoteInactivePrimaryFacilitator(t *testing.T) {
+	b, dataSvc := newFacilitatorTestService()
+	disconnected := time.Date(2025, 3, 10, 10, 0, 0, 0, time.UTC)
+	b.trackDisconnect("game", "primary", disconnected)
+
+	b.promoteInactivePrimaryFacilitators(context.Background(), disconnected.Add(5*time.Minute))
+	if len(dataSvc.transfers) != 0 {
+		t.Fatalf("expected no transfer before the timeout, got %v", dataSvc.transfers)
+	}
+
+	b.promoteInactivePrimaryFacilitators(context.Background(), disconnected.Add(primaryFacilitatorInactivityTimeout+time.Second))
+	if len(dataSvc.transfers) != 1 || dataSvc.transfers[0] != [2]string{"primary", "second"} {
+		t.Fatalf("expected primary to be transferred to second, got %v", dataSvc.transfers)
+	}
+	if !thunderdome.IsPrimaryFacilitator(dataSvc.facilitators, "second") {
+		t.Error("expected second to be the primary facilitator")
+	}
+
+	b.promoteInactivePrimaryFacilitators(context.Background(), disconnected.Add(time.Hour))
+	if len(dataSvc.transfers) != 1 {
+		t.Errorf("expected the disconnect to only be handled once, got %v", dataSvc.transfers)
+	}
+}
+
+// TestPromoteInactivePrimaryFacilitatorRejoined makes sure a primary facilitator that rejoined keeps the role
+func TestPromoteInactivePrimaryFacilitatorRejoined(t *testing.T) {
+	b, dataSvc := newFacilitatorTestService()
+	disconnected := time.Date(2025, 3, 10, 10, 0, 0, 0, time.UTC)
+
+	b.trackDisconnect("game", "primary", disconnected)
+	b.clearDisconnect("game", "primary")
+	b.promoteInactivePrimaryFacilitators(context.Background(), disconnected.Add(time.Hour))
+
+	b.trackDisconnect("game", "primary", disconnected)
+	dataSvc.activeUsers["primary"] = true
+	b.promoteInactivePrimaryFacilitators(context.Background(), disconnected.Add(time.Hour))
+
+	if len(dataSvc.transfers) != 0 {
+		t.Errorf("expected no transfer, got %v", dataSvc.transfers)
+	}
+}
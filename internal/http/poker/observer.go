@@ -0,0 +1,37 @@
+package poker
+
+import (
+	"crypto/subtle"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// observerQueryParam is the websocket query param used by observer join links
+const observerQueryParam = "observerCode"
+
+// JoinCodeAccess determines whether the provided code grants access to the game
+// and whether the user should join as a spectator via the observer code
+func JoinCodeAccess(providedCode string, joinCode string, observerCode string) (authorized bool, spectator bool) {
+	if providedCode == "" {
+		return false, false
+	}
+	if observerCode != "" && subtle.ConstantTimeCompare([]byte(providedCode), []byte(observerCode)) == 1 {
+		return true, true
+	}
+	if joinCode != "" && subtle.ConstantTimeCompare([]byte(providedCode), []byte(joinCode)) == 1 {
+		return true, false
+	}
+
+	return false, false
+}
+
+// isSpectator checks whether the user is a spectator in the game
+func isSpectator(users []*thunderdome.PokerUser, userID string) bool {
+	for _, user := range users {
+		if user.ID == userID {
+			return user.Spectator
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,184 @@
+package poker
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+func TestJoinCodeAccess(t *testing.T) {
+	tests := []struct {
+		name           string
+		providedCode   string
+		joinCode       string
+		observerCode   string
+		wantAuthorized bool
+		wantSpectator  bool
+	}{
+		{
+			name:           "join code",
+			providedCode:   "join",
+			joinCode:       "join",
+			observerCode:   "watch",
+			wantAuthorized: true,
+			wantSpectator:  false,
+		},
+		{
+			name:           "observer code joins as spectator",
+			providedCode:   "watch",
+			joinCode:       "join",
+			observerCode:   "watch",
+			wantAuthorized: true,
+			wantSpectator:  true,
+		},
+		{
+			name:           "observer code without join code",
+			providedCode:   "watch",
+			joinCode:       "",
+			observerCode:   "watch",
+			wantAuthorized: true,
+			wantSpectator:  true,
+		},
+		{
+			name:           "incorrect code",
+			providedCode:   "wrong",
+			joinCode:       "join",
+			observerCode:   "watch",
+			wantAuthorized: false,
+			wantSpectator:  false,
+		},
+		{
+			name:           "empty code with no observer code set",
+			providedCode:   "",
+			joinCode:       "join",
+			observerCode:   "",
+			wantAuthorized: false,
+			wantSpectator:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authorized, spectator := JoinCodeAccess(tt.providedCode, tt.joinCode, tt.observerCode)
+			if authorized != tt.wantAuthorized {
+				t.Errorf("expected authorized %v, got %v", tt.wantAuthorized, authorized)
+			}
+			if spectator != tt.wantSpectator {
+				t.Errorf("expected spectator %v, got %v", tt.wantSpectator, spectator)
+			}
+		})
+	}
+}
+
+func TestIsSpectator(t *testing.T) {
+	users := []*thunderdome.PokerUser{
+		{ID: "voter", Spectator: false},
+		{ID: "observer", Spectator: true},
+	}
+
+	if isSpectator(users, "voter") {
+		t.Error("expected voter not to be a spectator")
+	}
+	if !isSpectator(users, "observer") {
+		t.Error("expected observer to be a spectator")
+	}
+	if isSpectator(users, "unknown") {
+		t.Error("expected unknown user not to be a spectator")
+	}
+}
+
+type fakeObserverDataSvc struct {
+	PokerDataSvc
+	facilitators map[string]bool
+	observers    map[string]bool
+	toggled      map[string]bool
+}
+
+func (f *fakeObserverDataSvc) ConfirmFacilitator(pokerID string, userID string) error {
+	if !f.facilitators[userID] {
+		return errors.New("REQUIRES_FACILITATOR")
+	}
+
+	return nil
+}
+
+func (f *fakeObserverDataSvc) IsObserver(pokerID string, userID string) (bool, error) {
+	return f.observers[userID], nil
+}
+
+func (f *fakeObserverDataSvc) ToggleSpectator(pokerID string, userID string, spectator bool) ([]*thunderdome.PokerUser, error) {
+	f.toggled[userID] = spectator
+
+	return []*thunderdome.PokerUser{}, nil
+}
+
+func TestUserSpectatorToggleObserver(t *testing.T) {
+	tests := []struct {
+		name       string
+		senderID   string
+		eventValue string
+		wantErr    bool
+		wantUserID string
+	}{
+		{
+			name:       "observer cannot become participant",
+			senderID:   "observer",
+			eventValue: `{"spectator":false}`,
+			wantErr:    true,
+		},
+		{
+			name:       "observer can stay spectator",
+			senderID:   "observer",
+			eventValue: `{"spectator":true}`,
+			wantUserID: "observer",
+		},
+		{
+			name:       "participant can become participant",
+			senderID:   "participant",
+			eventValue: `{"spectator":false}`,
+			wantUserID: "participant",
+		},
+		{
+			name:       "participant cannot change another user",
+			senderID:   "participant",
+			eventValue: `{"spectator":false,"userId":"observer"}`,
+			wantErr:    true,
+		},
+		{
+			name:       "facilitator makes observer a participant",
+			senderID:   "facilitator",
+			eventValue: `{"spectator":false,"userId":"observer"}`,
+			wantUserID: "observer",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &fakeObserverDataSvc{
+				facilitators: map[string]bool{"facilitator": true},
+				observers:    map[string]bool{"observer": true},
+				toggled:      map[string]bool{},
+			}
+			b := &Service{PokerService: svc}
+
+			_, err, _ := b.UserSpectatorToggle(context.Background(), "game", tt.senderID, tt.eventValue)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				if len(svc.toggled) != 0 {
+					t.Errorf("expected no spectator change, got %v", svc.toggled)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if _, ok := svc.toggled[tt.wantUserID]; !ok {
+				t.Errorf("expected %s to be toggled, got %v", tt.wantUserID, svc.toggled)
+			}
+		})
+	}
+}
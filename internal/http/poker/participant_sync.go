@@ -0,0 +1,61 @@
+package poker
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/wshub"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+// participantsUpdated is the participants_updated event value
+type participantsUpdated struct {
+	Count int                      `json:"count"`
+	Users []*thunderdome.PokerUser `json:"users"`
+}
+
+// runParticipantSync periodically reconciles the games active users with the users connected to the hub
+func (b *Service) runParticipantSync(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		b.syncParticipants(context.Background())
+	}
+}
+
+// syncParticipants sets active game users without a connection (e.g. after a browser crash) inactive
+// and broadcasts the participants to every game with connected users, returning the IDs of the games
+// that had users set inactive
+func (b *Service) syncParticipants(ctx context.Context) []string {
+	roomUsers := b.hub.RoomUsers()
+
+	ghostGames, err := b.PokerService.DeactivateDisconnectedUsers(ctx, roomUsers)
+	if err != nil {
+		b.logger.Ctx(ctx).Error("poker participant sync error", zap.Error(err))
+		return nil
+	}
+	if len(ghostGames) > 0 {
+		b.logger.Ctx(ctx).Info("deactivated disconnected poker users", zap.Strings("poker_ids", ghostGames))
+	}
+
+	for pokerID := range roomUsers {
+		users := b.PokerService.GetUsers(pokerID)
+		updated := participantsUpdated{Users: users}
+		for _, user := range users {
+			if user.Active {
+				updated.Count++
+			}
+		}
+		updatedJSON, _ := json.Marshal(updated)
+
+		b.hub.Broadcast(wshub.Message{
+			Data: wshub.CreateSocketEvent("participants_updated", string(updatedJSON), ""),
+			Room: pokerID,
+		})
+	}
+
+	return ghostGames
+}
@@ -0,0 +1,85 @@
+package poker
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/wshub"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+// fakeParticipantSyncDataSvc keeps the games users active status in memory
+type fakeParticipantSyncDataSvc struct {
+	PokerDataSvc
+	mu    sync.Mutex
+	users map[string][]*thunderdome.PokerUser
+}
+
+func (f *fakeParticipantSyncDataSvc) SubscribeTimeBoxEvents(ctx context.Context) <-chan thunderdome.PokerTimeBoxEvent {
+	return nil
+}
+
+func (f *fakeParticipantSyncDataSvc) DeactivateDisconnectedUsers(ctx context.Context, connectedUsers map[string][]string) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	games := make([]string, 0)
+	for pokerID, users := range f.users {
+		// games without connections on this hub belong to another instance
+		if _, ok := connectedUsers[pokerID]; !ok {
+			continue
+		}
+		for _, user := range users {
+			if user.Active && !slices.Contains(connectedUsers[pokerID], user.ID) {
+				user.Active = false
+				if !slices.Contains(games, pokerID) {
+					games = append(games, pokerID)
+				}
+			}
+		}
+	}
+
+	return games, nil
+}
+
+func (f *fakeParticipantSyncDataSvc) GetUsers(pokerID string) []*thunderdome.PokerUser {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.users[pokerID]
+}
+
+// TestSyncParticipantsRemovesGhostUser makes sure an active user without a connection is set inactive
+// after one sync cycle while connected users stay active
+func TestSyncParticipantsRemovesGhostUser(t *testing.T) {
+	dataSvc := &fakeParticipantSyncDataSvc{users: map[string][]*thunderdome.PokerUser{
+		"game":       {{ID: "connected", Active: true}, {ID: "ghost", Active: true}},
+		"other-game": {{ID: "elsewhere", Active: true}},
+	}}
+	b := New(Config{}, otelzap.New(zap.NewNop()), nil, nil, nil, nil, dataSvc)
+	b.hub.Register(wshub.Subscription{Conn: b.hub.NewConnection(nil), RoomID: "game", UserID: "connected"})
+
+	ghostGames := b.syncParticipants(context.Background())
+
+	if !slices.Equal(ghostGames, []string{"game"}) {
+		t.Errorf("expected game to have had a ghost user, got %v", ghostGames)
+	}
+	users := dataSvc.GetUsers("game")
+	if !users[0].Active {
+		t.Error("expected the connected user to stay active")
+	}
+	if users[1].Active {
+		t.Error("expected the ghost user to be set inactive")
+	}
+	if !dataSvc.GetUsers("other-game")[0].Active {
+		t.Error("expected users of games not on this hub to stay active")
+	}
+
+	if ghostGames := b.syncParticipants(context.Background()); len(ghostGames) != 0 {
+		t.Errorf("expected no ghost users on the next sync, got %v", ghostGames)
+	}
+}
@@ -0,0 +1,92 @@
+package poker
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/wshub"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// storyActivation is the activate_plan event value, a plain story ID is also accepted
+type storyActivation struct {
+	StoryID string `json:"storyId"`
+	// Force starts voting even when the game's minimum participants haven't joined yet
+	Force bool `json:"force"`
+}
+
+// waitingForParticipants is the waiting_for_participants event value
+type waitingForParticipants struct {
+	CurrentCount  int `json:"currentCount"`
+	RequiredCount int `json:"requiredCount"`
+}
+
+// quorumNotReached is the quorum_not_reached event value
+type quorumNotReached struct {
+	StoryID           string  `json:"planId"`
+	CurrentPercentage float64 `json:"currentPercentage"`
+	QuorumPercentage  float64 `json:"quorumPercentage"`
+}
+
+// parseStoryActivation parses the activate_plan event value
+func parseStoryActivation(eventValue string) storyActivation {
+	var activation storyActivation
+	if err := json.Unmarshal([]byte(eventValue), &activation); err != nil || activation.StoryID == "" {
+		return storyActivation{StoryID: eventValue}
+	}
+
+	return activation
+}
+
+// confirmMinParticipants makes sure the game has at least its minimum number of active participants,
+// otherwise the room is told voting is waiting for more participants
+func (b *Service) confirmMinParticipants(pokerID string) error {
+	game, err := b.PokerService.GetGameByID(pokerID, "")
+	if err != nil {
+		return fmt.Errorf("confirm poker min participants error: %v", err)
+	}
+	if game.MinParticipants < 1 {
+		return nil
+	}
+
+	count := thunderdome.ActiveParticipantCount(b.PokerService.GetUsers(pokerID))
+	if count >= game.MinParticipants {
+		return nil
+	}
+
+	waiting, _ := json.Marshal(waitingForParticipants{
+		CurrentCount:  count,
+		RequiredCount: game.MinParticipants,
+	})
+	b.hub.Broadcast(wshub.Message{
+		Data: wshub.CreateSocketEvent("waiting_for_participants", string(waiting), ""),
+		Room: pokerID,
+	})
+
+	return thunderdome.ErrNotEnoughParticipants
+}
+
+// confirmQuorum makes sure enough of the game's active participants voted on the story for it to be finalized
+// automatically, otherwise the room is told the quorum wasn't reached and the facilitator has to finalize it
+func (b *Service) confirmQuorum(pokerID string, game *thunderdome.Poker, story *thunderdome.Story) bool {
+	if game.QuorumPercentage <= 0 {
+		return true
+	}
+
+	percentage, reached := thunderdome.VoteQuorum(story.Votes, b.PokerService.GetUsers(pokerID), game.QuorumPercentage)
+	if reached {
+		return true
+	}
+
+	notReached, _ := json.Marshal(quorumNotReached{
+		StoryID:           story.ID,
+		CurrentPercentage: percentage,
+		QuorumPercentage:  game.QuorumPercentage,
+	})
+	b.hub.Broadcast(wshub.Message{
+		Data: wshub.CreateSocketEvent("quorum_not_reached", string(notReached), ""),
+		Room: pokerID,
+	})
+
+	return false
+}
@@ -0,0 +1,113 @@
+package poker
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+type fakeParticipantsDataSvc struct {
+	PokerDataSvc
+	minParticipants int
+	users           []*thunderdome.PokerUser
+	activated       []string
+}
+
+func (f *fakeParticipantsDataSvc) GetGameByID(pokerID string, userID string) (*thunderdome.Poker, error) {
+	return &thunderdome.Poker{ID: pokerID, MinParticipants: f.minParticipants}, nil
+}
+
+func (f *fakeParticipantsDataSvc) GetUsers(pokerID string) []*thunderdome.PokerUser {
+	return f.users
+}
+
+func (f *fakeParticipantsDataSvc) ActivateStoryVoting(pokerID string, storyID string) ([]*thunderdome.Story, error) {
+	f.activated = append(f.activated, storyID)
+
+	return []*thunderdome.Story{{ID: storyID, Active: true}}, nil
+}
+
+func (f *fakeParticipantsDataSvc) StartStoryTimeBox(ctx context.Context, pokerID string, storyID string) (*thunderdome.PokerTimeBox, error) {
+	return nil, nil
+}
+
+func (f *fakeParticipantsDataSvc) SubscribeTimeBoxEvents(ctx context.Context) <-chan thunderdome.PokerTimeBoxEvent {
+	return nil
+}
+
+// activeParticipants creates the number of active participants along with a spectator and an inactive user
+func activeParticipants(count int) []*thunderdome.PokerUser {
+	users := []*thunderdome.PokerUser{
+		{ID: "spectator", Active: true, Spectator: true},
+		{ID: "inactive", Active: false},
+	}
+	for i := 0; i < count; i++ {
+		users = append(users, &thunderdome.PokerUser{ID: string(rune('a' + i)), Active: true})
+	}
+
+	return users
+}
+
+// TestStoryActivateMinParticipants makes sure voting only starts once the minimum participants are active
+func TestStoryActivateMinParticipants(t *testing.T) {
+	const minParticipants = 3
+
+	tests := []struct {
+		name         string
+		participants int
+		wantErr      error
+	}{
+		{name: "one below minimum", participants: minParticipants - 1, wantErr: thunderdome.ErrNotEnoughParticipants},
+		{name: "exactly minimum", participants: minParticipants},
+		{name: "one above minimum", participants: minParticipants + 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dataSvc := &fakeParticipantsDataSvc{minParticipants: minParticipants, users: activeParticipants(tt.participants)}
+			b := New(Config{}, otelzap.New(zap.NewNop()), nil, nil, nil, nil, dataSvc)
+
+			msg, err, _ := b.StoryActivate(context.Background(), "game", "facilitator", "story")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr != nil {
+				if msg != nil || len(dataSvc.activated) != 0 {
+					t.Errorf("expected the story not to be activated, got %v", dataSvc.activated)
+				}
+				return
+			}
+			if msg == nil || len(dataSvc.activated) != 1 || dataSvc.activated[0] != "story" {
+				t.Errorf("expected the story to be activated, got %v", dataSvc.activated)
+			}
+		})
+	}
+}
+
+// TestStoryActivateForce makes sure the facilitator can start voting without the minimum participants
+func TestStoryActivateForce(t *testing.T) {
+	dataSvc := &fakeParticipantsDataSvc{minParticipants: 3, users: activeParticipants(1)}
+	b := New(Config{}, otelzap.New(zap.NewNop()), nil, nil, nil, nil, dataSvc)
+
+	_, err, _ := b.StoryActivate(context.Background(), "game", "facilitator", `{"storyId":"story","force":true}`)
+	if err != nil {
+		t.Fatalf("expected forced activation to succeed, got %v", err)
+	}
+	if len(dataSvc.activated) != 1 || dataSvc.activated[0] != "story" {
+		t.Errorf("expected the story to be activated, got %v", dataSvc.activated)
+	}
+}
+
+// TestStoryActivateNoMinimum makes sure games without a minimum start voting with a single participant
+func TestStoryActivateNoMinimum(t *testing.T) {
+	dataSvc := &fakeParticipantsDataSvc{users: activeParticipants(1)}
+	b := New(Config{}, otelzap.New(zap.NewNop()), nil, nil, nil, nil, dataSvc)
+
+	if _, err, _ := b.StoryActivate(context.Background(), "game", "facilitator", "story"); err != nil {
+		t.Fatalf("expected activation to succeed, got %v", err)
+	}
+}
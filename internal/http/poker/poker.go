@@ -3,7 +3,10 @@ package poker
 
 import (
 	"context"
+	"net"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/StevenWeathers/thunderdome-planning-poker/internal/wshub"
 
@@ -22,15 +25,35 @@ type Config struct {
 	AppDomain string
 	// Websocket Subdomain (for Websocket origin check)
 	WebsocketSubdomain string
+	// Time allowed for connections to be closed cleanly on shutdown
+	ShutdownGracePeriodSec int
+	// How often the active game users are reconciled with the connected users, 0 disables it
+	ParticipantSyncIntervalSec int
+	// Websocket read buffer size in bytes
+	ReadBufferSize int
+	// Websocket write buffer size in bytes
+	WriteBufferSize int
+	// Store for messages to replay to clients reconnecting after a shutdown
+	ReplayStore wshub.ReplayStore
+	// Reverse proxy ip ranges whose X-Forwarded-For header is trusted for the access log ip
+	TrustedProxies []*net.IPNet
 }
 
 type PokerDataSvc interface {
 	// UpdateGame updates an existing poker game
-	UpdateGame(pokerID string, name string, pointValuesAllowed []string, autoFinishVoting bool, pointAverageRounding string, hideVoterIdentity bool, joinCode string, facilitatorCode string, teamID string) error
+	UpdateGame(pokerID string, name string, pointValuesAllowed []string, autoFinishVoting bool, pointAverageRounding string, hideVoterIdentity bool, autoFinalizeOnConsensus bool, minParticipants int, timeBoxMinutes int, quorumPercentage float64, storyTypeScaleMap map[string]string, joinCode string, facilitatorCode string, observerCode string, teamID string) error
 	// GetFacilitatorCode retrieves the facilitator code for a poker game
 	GetFacilitatorCode(pokerID string) (string, error)
+	// GetObserverCode retrieves the observer code for a poker game
+	GetObserverCode(pokerID string) (string, error)
+	// RegenerateJoinCode replaces the join code of a poker game with a new random code
+	RegenerateJoinCode(ctx context.Context, pokerID string, facilitatorID string) (string, error)
+	// RegenerateFacilitatorCode replaces the facilitator code of a poker game with a new random code
+	RegenerateFacilitatorCode(ctx context.Context, pokerID string, facilitatorID string) (string, error)
 	// GetGameByID retrieves a poker game by its ID
 	GetGameByID(pokerID string, userID string) (*thunderdome.Poker, error)
+	// GetEstimationScale retrieves an estimation scale by its ID
+	GetEstimationScale(ctx context.Context, scaleID string) (*thunderdome.EstimationScale, error)
 	// ConfirmFacilitator confirms a user as a facilitator for a poker game
 	ConfirmFacilitator(pokerID string, userID string) error
 	// GetUserActiveStatus retrieves the active status of a user in a poker game
@@ -39,16 +62,36 @@ type PokerDataSvc interface {
 	AddUser(pokerID string, userID string) ([]*thunderdome.PokerUser, error)
 	// RetreatUser sets a user as inactive in a poker game
 	RetreatUser(pokerID string, userID string) []*thunderdome.PokerUser
+	// DeactivateDisconnectedUsers sets active poker game users that aren't connected to their game inactive
+	DeactivateDisconnectedUsers(ctx context.Context, connectedUsers map[string][]string) ([]string, error)
 	// AbandonGame sets a user as abandoned in a poker game
 	AbandonGame(pokerID string, userID string) ([]*thunderdome.PokerUser, error)
 	// AddFacilitator adds a facilitator to a poker game
 	AddFacilitator(pokerID string, userID string) ([]string, error)
 	// RemoveFacilitator removes a facilitator from a poker game
 	RemoveFacilitator(pokerID string, userID string) ([]string, error)
+	// GetFacilitators retrieves the facilitators of a poker game ordered by when they became a facilitator
+	GetFacilitators(pokerID string) ([]*thunderdome.PokerFacilitator, error)
+	// TransferPrimaryFacilitator hands the primary facilitator role over to another facilitator of a poker game
+	TransferPrimaryFacilitator(ctx context.Context, pokerID string, currentPrimaryID string, newPrimaryID string) error
+	// GetUsers retrieves a list of users in a poker game
+	GetUsers(pokerID string) []*thunderdome.PokerUser
 	// ToggleSpectator toggles a user's spectator status in a poker game
 	ToggleSpectator(pokerID string, userID string, spectator bool) ([]*thunderdome.PokerUser, error)
-	// DeleteGame deletes a poker game
-	DeleteGame(pokerID string) error
+	// SetObserver makes a user an observer of a poker game
+	SetObserver(pokerID string, userID string) ([]*thunderdome.PokerUser, error)
+	// IsObserver checks whether a user joined a poker game as an observer
+	IsObserver(pokerID string, userID string) (bool, error)
+	// ArchiveGame archives a poker game, keeping it for auditing
+	ArchiveGame(ctx context.Context, pokerID string) error
+	// IsGameArchived checks whether a poker game has been archived
+	IsGameArchived(ctx context.Context, pokerID string) (bool, error)
+	// GetStories retrieves a list of stories in a poker game
+	GetStories(pokerID string, userID string) []*thunderdome.Story
+	// BulkAddStories adds multiple stories to a poker game, optionally deduplicating by reference_id
+	BulkAddStories(ctx context.Context, pokerID string, stories []*thunderdome.Story, deduplicate bool) (*thunderdome.DuplicationResult, error)
+	// ImportStoriesFromCSV adds the stories parsed from a csv file to a poker game using the header to field column mapping
+	ImportStoriesFromCSV(ctx context.Context, pokerID string, csvData []byte, columnMapping map[string]string, deduplicate bool) ([]*thunderdome.Story, *thunderdome.DuplicationResult, error)
 	// CreateStory creates a new story in a poker game
 	CreateStory(pokerID string, name string, storyType string, referenceID string, link string, description string, acceptanceCriteria string, priority int32) ([]*thunderdome.Story, error)
 	// ActivateStoryVoting activates voting for a story in a poker game
@@ -63,12 +106,42 @@ type PokerDataSvc interface {
 	SkipStory(pokerID string, storyID string) ([]*thunderdome.Story, error)
 	// UpdateStory updates an existing story in a poker game
 	UpdateStory(pokerID string, storyID string, name string, storyType string, referenceID string, link string, description string, acceptanceCriteria string, priority int32) ([]*thunderdome.Story, error)
+	// BulkUpdateStoryPriority sets the priority of multiple stories in a poker game
+	BulkUpdateStoryPriority(ctx context.Context, pokerID string, updates []thunderdome.StoryPriorityUpdate) error
+	// BulkUpdateStorySprint assigns multiple stories in a poker game to a sprint
+	BulkUpdateStorySprint(ctx context.Context, pokerID string, sprint thunderdome.StorySprint, storyIDs []string) error
+	// BulkUpdateStoryStatus moves multiple stories in a poker game to the status
+	BulkUpdateStoryStatus(ctx context.Context, pokerID string, status string, storyIDs []string) error
 	// DeleteStory deletes a story from a poker game
 	DeleteStory(pokerID string, storyID string) ([]*thunderdome.Story, error)
 	// ArrangeStory sets the position of the story relative to the story it's being placed before
 	ArrangeStory(pokerID string, storyID string, beforeStoryID string) ([]*thunderdome.Story, error)
 	// FinalizeStory finalizes the points for a story in a poker game
 	FinalizeStory(pokerID string, storyID string, points string) ([]*thunderdome.Story, error)
+	// LogAccess records a user joining or leaving a poker game
+	LogAccess(ctx context.Context, pokerID string, userID string, eventType string, ip string, userAgent string) error
+	// StartStoryTimeBox starts the game's time box for the story, nil when the game has no time box
+	StartStoryTimeBox(ctx context.Context, pokerID string, storyID string) (*thunderdome.PokerTimeBox, error)
+	// ExtendTimeBox adds the seconds to the game's running story time box
+	ExtendTimeBox(ctx context.Context, pokerID string, additionalSeconds int) error
+	// GetStoryTimeBox retrieves the game's story time box, nil when there isn't one
+	GetStoryTimeBox(ctx context.Context, pokerID string) (*thunderdome.PokerTimeBox, error)
+	// ExpireStoryTimeBox removes the story's time box once it has run out, returning whether it was removed
+	ExpireStoryTimeBox(ctx context.Context, pokerID string, storyID string) (bool, error)
+	// GetRunningTimeBox retrieves the game's story time box and how much of it is left by the database clock, nil when there isn't one
+	GetRunningTimeBox(ctx context.Context, pokerID string) (*thunderdome.PokerRunningTimeBox, error)
+	// GetRunningTimeBoxes retrieves every game's story time box and how much of it is left by the database clock
+	GetRunningTimeBoxes(ctx context.Context) ([]*thunderdome.PokerRunningTimeBox, error)
+	// StopStoryTimeBox removes the game's story time box
+	StopStoryTimeBox(ctx context.Context, pokerID string) error
+	// AddComment adds a comment to a poker story, a parent ID makes it a reply to another comment
+	AddComment(ctx context.Context, storyID string, authorID string, parentID string, body string) (*thunderdome.PokerStoryComment, error)
+	// AddChatMessage adds a chat message to a poker game, spectator only messages are only seen by spectators and facilitators
+	AddChatMessage(ctx context.Context, gameID string, userID string, message string, spectatorOnly bool) (*thunderdome.GameChat, error)
+	// GetChatHistory retrieves the chat messages of a poker game sent after since
+	GetChatHistory(ctx context.Context, gameID string, since time.Time) ([]thunderdome.GameChat, error)
+	// SubscribeTimeBoxEvents receives the time box events published by every instance
+	SubscribeTimeBoxEvents(ctx context.Context) <-chan thunderdome.PokerTimeBoxEvent
 }
 
 type AuthDataSvc interface {
@@ -89,6 +162,9 @@ type Service struct {
 	AuthService           AuthDataSvc
 	PokerService          PokerDataSvc
 	hub                   *wshub.Hub
+	timeBoxes             *storyTimeBoxes
+	// facilitatorDisconnects tracks when users left their games, see runFacilitatorInactivityCheck
+	facilitatorDisconnects sync.Map
 }
 
 // New returns a new battle with websocket hub/client and event handlers
@@ -110,50 +186,67 @@ func New(
 	}
 
 	b.hub = wshub.NewHub(logger, wshub.Config{
-		AppDomain:          config.AppDomain,
-		WebsocketSubdomain: config.WebsocketSubdomain,
-		WriteWaitSec:       config.WriteWaitSec,
-		PongWaitSec:        config.PongWaitSec,
-		PingPeriodSec:      config.PingPeriodSec,
-	}, map[string]func(context.Context, string, string, string) ([]byte, error, bool){
-		"jab_warrior":      b.UserNudge,
-		"vote":             b.UserVote,
-		"retract_vote":     b.UserVoteRetract,
-		"end_voting":       b.StoryVoteEnd,
-		"add_plan":         b.StoryAdd,
-		"revise_plan":      b.StoryRevise,
-		"burn_plan":        b.StoryDelete,
-		"story_arrange":    b.StoryArrange,
-		"activate_plan":    b.StoryActivate,
-		"skip_plan":        b.StorySkip,
-		"finalize_plan":    b.StoryFinalize,
-		"promote_leader":   b.UserPromote,
-		"demote_leader":    b.UserDemote,
-		"become_leader":    b.UserPromoteSelf,
-		"spectator_toggle": b.UserSpectatorToggle,
-		"revise_battle":    b.Revise,
-		"concede_battle":   b.Delete,
-		"abandon_battle":   b.Abandon,
-	},
+		AppDomain:              config.AppDomain,
+		WebsocketSubdomain:     config.WebsocketSubdomain,
+		ShutdownGracePeriodSec: config.ShutdownGracePeriodSec,
+		ReadBufferSize:         config.ReadBufferSize,
+		WriteBufferSize:        config.WriteBufferSize,
+		ReplayStore:            config.ReplayStore,
+		WriteWaitSec:           config.WriteWaitSec,
+		PongWaitSec:            config.PongWaitSec,
+		PingPeriodSec:          config.PingPeriodSec,
+	}, b.activeGameOnly(map[string]func(context.Context, string, string, string) ([]byte, error, bool){
+		"jab_warrior":             b.UserNudge,
+		"vote":                    b.UserVote,
+		"retract_vote":            b.UserVoteRetract,
+		"end_voting":              b.StoryVoteEnd,
+		"add_plan":                b.StoryAdd,
+		"revise_plan":             b.StoryRevise,
+		"burn_plan":               b.StoryDelete,
+		"story_arrange":           b.StoryArrange,
+		"activate_plan":           b.StoryActivate,
+		"skip_plan":               b.StorySkip,
+		"finalize_plan":           b.StoryFinalize,
+		"promote_leader":          b.UserPromote,
+		"demote_leader":           b.UserDemote,
+		"become_leader":           b.UserPromoteSelf,
+		"spectator_toggle":        b.UserSpectatorToggle,
+		"revise_battle":           b.Revise,
+		"concede_battle":          b.Delete,
+		"abandon_battle":          b.Abandon,
+		"transfer_primary_leader": b.UserPrimaryTransfer,
+		"extend_timebox":          b.TimeBoxExtend,
+		"chat_message":            b.ChatMessage,
+		"chat_history":            b.ChatHistory,
+	}),
 		map[string]struct{}{
-			"add_plan":       {},
-			"revise_plan":    {},
-			"burn_plan":      {},
-			"activate_plan":  {},
-			"skip_plan":      {},
-			"end_voting":     {},
-			"finalize_plan":  {},
-			"jab_warrior":    {},
-			"promote_leader": {},
-			"demote_leader":  {},
-			"revise_battle":  {},
-			"concede_battle": {},
+			"add_plan":                {},
+			"revise_plan":             {},
+			"burn_plan":               {},
+			"activate_plan":           {},
+			"skip_plan":               {},
+			"end_voting":              {},
+			"finalize_plan":           {},
+			"jab_warrior":             {},
+			"promote_leader":          {},
+			"demote_leader":           {},
+			"revise_battle":           {},
+			"concede_battle":          {},
+			"transfer_primary_leader": {},
+			"extend_timebox":          {},
 		},
 		b.PokerService.ConfirmFacilitator,
 		b.RetreatUser,
 	)
 
+	b.timeBoxes = newStoryTimeBoxes(realClock{}, b.expireTimeBox)
+
 	go b.hub.Run()
+	go b.relayTimeBoxEvents(context.Background())
+	go b.runFacilitatorInactivityCheck(facilitatorInactivityCheckInterval)
+	if config.ParticipantSyncIntervalSec > 0 {
+		go b.runParticipantSync(time.Duration(config.ParticipantSyncIntervalSec) * time.Second)
+	}
 
 	return b
 }
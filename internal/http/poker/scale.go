@@ -0,0 +1,37 @@
+package poker
+
+import (
+	"context"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+// GetStoryEstimationScale gets the estimation scale the story is voted with, the game's override scale for
+// the story type when set otherwise the game's estimation scale
+func (b *Service) GetStoryEstimationScale(ctx context.Context, story *thunderdome.Story, game *thunderdome.Poker) *thunderdome.EstimationScale {
+	scaleID := game.StoryEstimationScaleID(story.Type)
+	if scaleID == game.EstimationScaleID {
+		return game.EstimationScale
+	}
+
+	scale, err := b.PokerService.GetEstimationScale(ctx, scaleID)
+	if err != nil {
+		b.logger.Ctx(ctx).Error("get poker story estimation scale error", zap.Error(err),
+			zap.String("poker_id", game.ID), zap.String("story_id", story.ID),
+			zap.String("estimation_scale_id", scaleID))
+		return game.EstimationScale
+	}
+
+	return scale
+}
+
+// setActiveStoryEstimationScale sets the estimation scale of the game's active story
+func (b *Service) setActiveStoryEstimationScale(ctx context.Context, game *thunderdome.Poker, stories []*thunderdome.Story) {
+	for _, story := range stories {
+		if story.Active {
+			story.EstimationScale = b.GetStoryEstimationScale(ctx, story, game)
+			return
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package poker
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+type fakeScaleDataSvc struct {
+	PokerDataSvc
+	scales  map[string]*thunderdome.EstimationScale
+	fetched []string
+}
+
+func (f *fakeScaleDataSvc) GetEstimationScale(ctx context.Context, scaleID string) (*thunderdome.EstimationScale, error) {
+	f.fetched = append(f.fetched, scaleID)
+	scale, ok := f.scales[scaleID]
+	if !ok {
+		return nil, errors.New("ESTIMATION_SCALE_NOT_FOUND")
+	}
+
+	return scale, nil
+}
+
+func (f *fakeScaleDataSvc) SubscribeTimeBoxEvents(ctx context.Context) <-chan thunderdome.PokerTimeBoxEvent {
+	return nil
+}
+
+// TestGetStoryEstimationScale makes sure the story type's override scale is used, falling back to the game's scale
+func TestGetStoryEstimationScale(t *testing.T) {
+	fibonacci := &thunderdome.EstimationScale{ID: "fibonacci", Values: []string{"1", "2", "3", "5", "8"}}
+	tshirt := &thunderdome.EstimationScale{ID: "tshirt", Values: []string{"S", "M", "L"}}
+	game := &thunderdome.Poker{
+		ID:                "game",
+		EstimationScaleID: fibonacci.ID,
+		EstimationScale:   fibonacci,
+		StoryTypeScaleMap: map[string]string{"epic": tshirt.ID, "bug": "deleted"},
+	}
+
+	tests := []struct {
+		name      string
+		storyType string
+		expected  *thunderdome.EstimationScale
+		fetched   []string
+	}{
+		{name: "overridden story type", storyType: "Epic", expected: tshirt, fetched: []string{"tshirt"}},
+		{name: "story type without override", storyType: "Story", expected: fibonacci},
+		{name: "override scale not found", storyType: "Bug", expected: fibonacci, fetched: []string{"deleted"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dataSvc := &fakeScaleDataSvc{scales: map[string]*thunderdome.EstimationScale{tshirt.ID: tshirt}}
+			b := New(Config{}, otelzap.New(zap.NewNop()), nil, nil, nil, nil, dataSvc)
+
+			scale := b.GetStoryEstimationScale(context.Background(), &thunderdome.Story{ID: "story", Type: tt.storyType}, game)
+			if scale != tt.expected {
+				t.Errorf("expected scale %s, got %+v", tt.expected.ID, scale)
+			}
+			if len(dataSvc.fetched) != len(tt.fetched) || (len(tt.fetched) > 0 && dataSvc.fetched[0] != tt.fetched[0]) {
+				t.Errorf("expected scales %v to be fetched, got %v", tt.fetched, dataSvc.fetched)
+			}
+		})
+	}
+}
@@ -0,0 +1,291 @@
+package poker
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/wshub"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+// clock provides the current time and timers so time boxes can be tested without waiting
+type clock interface {
+	Now() time.Time
+	NewTimer(d time.Duration) (<-chan time.Time, func() bool)
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) NewTimer(d time.Duration) (<-chan time.Time, func() bool) {
+	timer := time.NewTimer(d)
+	return timer.C, timer.Stop
+}
+
+type runningTimeBox struct {
+	timeBox thunderdome.PokerTimeBox
+	stop    chan struct{}
+}
+
+// storyTimeBoxes waits for each game's running story time box to run out
+type storyTimeBoxes struct {
+	clock   clock
+	expire  func(pokerID string, timeBox thunderdome.PokerTimeBox)
+	mu      sync.Mutex
+	running map[string]*runningTimeBox
+}
+
+func newStoryTimeBoxes(clock clock, expire func(pokerID string, timeBox thunderdome.PokerTimeBox)) *storyTimeBoxes {
+	return &storyTimeBoxes{
+		clock:   clock,
+		expire:  expire,
+		running: make(map[string]*runningTimeBox),
+	}
+}
+
+// start waits for the time box to run out, replacing the game's previously running time box
+func (st *storyTimeBoxes) start(pokerID string, timeBox thunderdome.PokerTimeBox) {
+	st.startAfter(pokerID, timeBox, timeBox.Remaining(st.clock.Now()))
+}
+
+// startAfter waits the remaining duration for the time box to run out, replacing the game's previously
+// running time box, used when the remaining time was measured by the database clock
+func (st *storyTimeBoxes) startAfter(pokerID string, timeBox thunderdome.PokerTimeBox, remaining time.Duration) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if existing, ok := st.running[pokerID]; ok {
+		// the same time box is already running, e.g. when the instance receives its own published event
+		if existing.timeBox.StoryID == timeBox.StoryID && existing.timeBox.DurationSeconds == timeBox.DurationSeconds &&
+			existing.timeBox.StartedAt.Equal(timeBox.StartedAt) {
+			return
+		}
+		close(existing.stop)
+	}
+
+	rt := &runningTimeBox{timeBox: timeBox, stop: make(chan struct{})}
+	st.running[pokerID] = rt
+	expired, stopTimer := st.clock.NewTimer(remaining)
+
+	go st.run(pokerID, rt, expired, stopTimer)
+}
+
+// stop stops waiting for the game's time box, returning whether one was running
+func (st *storyTimeBoxes) stop(pokerID string) bool {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	existing, ok := st.running[pokerID]
+	if ok {
+		close(existing.stop)
+		delete(st.running, pokerID)
+	}
+
+	return ok
+}
+
+func (st *storyTimeBoxes) run(pokerID string, rt *runningTimeBox, expired <-chan time.Time, stopTimer func() bool) {
+	defer stopTimer()
+
+	select {
+	case <-rt.stop:
+		return
+	case <-expired:
+		st.mu.Lock()
+		// the time box may have been stopped or replaced as it ran out
+		current := st.running[pokerID] == rt
+		if current {
+			delete(st.running, pokerID)
+		}
+		st.mu.Unlock()
+
+		if current {
+			st.expire(pokerID, rt.timeBox)
+		}
+	}
+}
+
+// relayTimeBoxEvents starts and stops time boxes changed by other instances
+func (b *Service) relayTimeBoxEvents(ctx context.Context) {
+	events := b.PokerService.SubscribeTimeBoxEvents(ctx)
+	if events == nil {
+		return
+	}
+
+	for event := range events {
+		switch event.Type {
+		case thunderdome.PokerTimeBoxEventStart:
+			b.timeBoxes.start(event.PokerID, event.TimeBox)
+		case thunderdome.PokerTimeBoxEventStop:
+			b.timeBoxes.stop(event.PokerID)
+		}
+	}
+}
+
+// RearmTimeBoxes waits for every stored story time box to run out, time boxes that ran out while
+// no instance was running expire right away, used on startup as time boxes are only tracked in memory
+func (b *Service) RearmTimeBoxes(ctx context.Context) {
+	timeBoxes, err := b.PokerService.GetRunningTimeBoxes(ctx)
+	if err != nil {
+		b.logger.Ctx(ctx).Error("get running poker story time boxes error", zap.Error(err))
+		return
+	}
+
+	for _, running := range timeBoxes {
+		b.timeBoxes.startAfter(running.PokerID, running.TimeBox, running.Remaining)
+	}
+}
+
+// startTimeBox starts the time box for the newly activated story when the game has one,
+// letting the room know when it runs out
+func (b *Service) startTimeBox(ctx context.Context, pokerID string, storyID string) {
+	timeBox, err := b.PokerService.StartStoryTimeBox(ctx, pokerID, storyID)
+	if err != nil {
+		b.logger.Ctx(ctx).Error("start poker story time box error", zap.Error(err),
+			zap.String("poker_id", pokerID), zap.String("story_id", storyID))
+		return
+	}
+	if timeBox == nil {
+		b.timeBoxes.stop(pokerID)
+		return
+	}
+
+	b.timeBoxes.start(pokerID, *timeBox)
+
+	started, _ := json.Marshal(timeBox)
+	b.hub.Broadcast(wshub.Message{
+		Data: wshub.CreateSocketEvent("timebox_started", string(started), ""),
+		Room: pokerID,
+	})
+}
+
+// stopTimeBox stops the game's running time box once voting has ended before it ran out
+func (b *Service) stopTimeBox(ctx context.Context, pokerID string) {
+	if !b.timeBoxes.stop(pokerID) {
+		return
+	}
+
+	if err := b.PokerService.StopStoryTimeBox(ctx, pokerID); err != nil {
+		b.logger.Ctx(ctx).Error("stop poker story time box error", zap.Error(err),
+			zap.String("poker_id", pokerID))
+	}
+}
+
+// expireTimeBox ends voting on the story once its time box runs out, finalizing the story with the median vote
+// when the game has AutoFinishVoting enabled and its quorum voted, otherwise the votes are only revealed
+func (b *Service) expireTimeBox(pokerID string, timeBox thunderdome.PokerTimeBox) {
+	ctx := context.Background()
+	logger := b.logger.Ctx(ctx)
+
+	// only one instance acts on the expiry, and not at all once the time box was extended or stopped
+	expired, err := b.PokerService.ExpireStoryTimeBox(ctx, pokerID, timeBox.StoryID)
+	if err != nil {
+		logger.Error("expire poker story time box error", zap.Error(err),
+			zap.String("poker_id", pokerID), zap.String("story_id", timeBox.StoryID))
+		return
+	}
+	if !expired {
+		// this instance's clock may be ahead of the database, wait out what's left of the time box by the
+		// database clock unless it was extended, replaced or stopped in the meantime
+		running, err := b.PokerService.GetRunningTimeBox(ctx, pokerID)
+		if err != nil {
+			logger.Error("get running poker story time box error", zap.Error(err),
+				zap.String("poker_id", pokerID), zap.String("story_id", timeBox.StoryID))
+			return
+		}
+		if running != nil && running.TimeBox.StoryID == timeBox.StoryID && running.TimeBox.DurationSeconds == timeBox.DurationSeconds &&
+			running.TimeBox.StartedAt.Equal(timeBox.StartedAt) && running.Remaining > 0 {
+			b.timeBoxes.startAfter(pokerID, running.TimeBox, running.Remaining)
+		}
+		return
+	}
+
+	expiredJSON, _ := json.Marshal(timeBox)
+	b.hub.Broadcast(wshub.Message{
+		Data: wshub.CreateSocketEvent("timebox_expired", string(expiredJSON), ""),
+		Room: pokerID,
+	})
+
+	game, err := b.PokerService.GetGameByID(pokerID, "")
+	if err != nil {
+		logger.Error("poker time box get game error", zap.Error(err), zap.String("poker_id", pokerID))
+		return
+	}
+
+	stories, err := b.PokerService.EndStoryVoting(pokerID, timeBox.StoryID)
+	if err != nil {
+		logger.Error("poker time box end voting error", zap.Error(err),
+			zap.String("poker_id", pokerID), zap.String("story_id", timeBox.StoryID))
+		return
+	}
+	updatedStories, _ := json.Marshal(stories)
+	b.hub.Broadcast(wshub.Message{
+		Data: wshub.CreateSocketEvent("voting_ended", string(updatedStories), ""),
+		Room: pokerID,
+	})
+
+	if !game.AutoFinishVoting {
+		return
+	}
+
+	var median string
+	for _, story := range stories {
+		if story.ID == timeBox.StoryID {
+			if !b.confirmQuorum(pokerID, game, story) {
+				return
+			}
+			median = thunderdome.MedianVote(story.Votes)
+			break
+		}
+	}
+	if median == "" {
+		return
+	}
+
+	finalizedStories, err := b.PokerService.FinalizeStory(pokerID, timeBox.StoryID, median)
+	if err != nil {
+		logger.Error("poker time box finalize story error", zap.Error(err),
+			zap.String("poker_id", pokerID), zap.String("story_id", timeBox.StoryID))
+		return
+	}
+	finalizedJSON, _ := json.Marshal(finalizedStories)
+	b.hub.Broadcast(wshub.Message{
+		Data: wshub.CreateSocketEvent("plan_finalized", string(finalizedJSON), ""),
+		Room: pokerID,
+	})
+}
+
+// TimeBoxExtend handles the facilitator extending the active story's time box
+func (b *Service) TimeBoxExtend(ctx context.Context, pokerID string, userID string, eventValue string) ([]byte, error, bool) {
+	var te struct {
+		AdditionalSeconds int `json:"additionalSeconds"`
+	}
+	err := json.Unmarshal([]byte(eventValue), &te)
+	if err != nil {
+		return nil, err, false
+	}
+
+	if err := b.PokerService.ExtendTimeBox(ctx, pokerID, te.AdditionalSeconds); err != nil {
+		return nil, err, false
+	}
+
+	timeBox, err := b.PokerService.GetStoryTimeBox(ctx, pokerID)
+	if err != nil {
+		return nil, err, false
+	}
+	if timeBox == nil {
+		return nil, thunderdome.ErrTimeBoxNotRunning, false
+	}
+	b.timeBoxes.start(pokerID, *timeBox)
+
+	extended, _ := json.Marshal(timeBox)
+	msg := wshub.CreateSocketEvent("timebox_extended", string(extended), "")
+
+	return msg, nil, false
+}
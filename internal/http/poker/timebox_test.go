@@ -0,0 +1,259 @@
+package poker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+// mockClock only fires timers when the test says so
+type mockClock struct {
+	now   time.Time
+	fired chan time.Time
+}
+
+func (c *mockClock) Now() time.Time {
+	return c.now
+}
+
+func (c *mockClock) NewTimer(d time.Duration) (<-chan time.Time, func() bool) {
+	return c.fired, func() bool { return true }
+}
+
+type fakeTimeBoxDataSvc struct {
+	PokerDataSvc
+	autoFinishVoting bool
+	quorumPercentage float64
+	users            []*thunderdome.PokerUser
+	votes            []*thunderdome.Vote
+	expired          bool
+	running          *thunderdome.PokerRunningTimeBox
+	votingEnded      chan string
+	finalized        chan string
+}
+
+func newFakeTimeBoxDataSvc(autoFinishVoting bool) *fakeTimeBoxDataSvc {
+	return &fakeTimeBoxDataSvc{
+		autoFinishVoting: autoFinishVoting,
+		votingEnded:      make(chan string, 1),
+		finalized:        make(chan string, 1),
+	}
+}
+
+func (f *fakeTimeBoxDataSvc) SubscribeTimeBoxEvents(ctx context.Context) <-chan thunderdome.PokerTimeBoxEvent {
+	return nil
+}
+
+func (f *fakeTimeBoxDataSvc) ExpireStoryTimeBox(ctx context.Context, pokerID string, storyID string) (bool, error) {
+	expired := !f.expired
+	f.expired = true
+	return expired, nil
+}
+
+func (f *fakeTimeBoxDataSvc) GetRunningTimeBox(ctx context.Context, pokerID string) (*thunderdome.PokerRunningTimeBox, error) {
+	return f.running, nil
+}
+
+func (f *fakeTimeBoxDataSvc) GetRunningTimeBoxes(ctx context.Context) ([]*thunderdome.PokerRunningTimeBox, error) {
+	if f.running == nil {
+		return nil, nil
+	}
+	return []*thunderdome.PokerRunningTimeBox{f.running}, nil
+}
+
+func (f *fakeTimeBoxDataSvc) GetGameByID(pokerID string, userID string) (*thunderdome.Poker, error) {
+	return &thunderdome.Poker{
+		ID: pokerID, AutoFinishVoting: f.autoFinishVoting, TimeBoxMinutes: 5, QuorumPercentage: f.quorumPercentage,
+	}, nil
+}
+
+func (f *fakeTimeBoxDataSvc) GetUsers(pokerID string) []*thunderdome.PokerUser {
+	return f.users
+}
+
+func (f *fakeTimeBoxDataSvc) EndStoryVoting(pokerID string, storyID string) ([]*thunderdome.Story, error) {
+	f.votingEnded <- storyID
+	if f.votes != nil {
+		return []*thunderdome.Story{{ID: storyID, Active: true, Votes: f.votes}}, nil
+	}
+	return []*thunderdome.Story{{ID: storyID, Active: true, Votes: []*thunderdome.Vote{
+		{UserID: "a", VoteValue: "3"},
+		{UserID: "b", VoteValue: "8"},
+		{UserID: "c", VoteValue: "5"},
+		{UserID: "d", VoteValue: "?"},
+	}}}, nil
+}
+
+func (f *fakeTimeBoxDataSvc) FinalizeStory(pokerID string, storyID string, points string) ([]*thunderdome.Story, error) {
+	f.finalized <- points
+	return []*thunderdome.Story{{ID: storyID, Points: points}}, nil
+}
+
+func newTimeBoxTestService(dataSvc *fakeTimeBoxDataSvc) (*Service, *mockClock) {
+	b := New(Config{}, otelzap.New(zap.NewNop()), nil, nil, nil, nil, dataSvc)
+	clock := &mockClock{now: time.Date(2025, 3, 15, 9, 0, 0, 0, time.UTC), fired: make(chan time.Time)}
+	b.timeBoxes = newStoryTimeBoxes(clock, b.expireTimeBox)
+
+	return b, clock
+}
+
+// TestTimeBoxExpiryFinalizesStory makes sure the story is finalized with the median vote once its time box runs out
+func TestTimeBoxExpiryFinalizesStory(t *testing.T) {
+	dataSvc := newFakeTimeBoxDataSvc(true)
+	b, clock := newTimeBoxTestService(dataSvc)
+
+	b.timeBoxes.start("game", thunderdome.PokerTimeBox{StoryID: "story", DurationSeconds: 300, StartedAt: clock.now})
+	clock.fired <- clock.now.Add(5 * time.Minute)
+
+	select {
+	case points := <-dataSvc.finalized:
+		if points != "5" {
+			t.Errorf("expected the story to be finalized with the median vote 5, got %s", points)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the story to be finalized at time box expiry")
+	}
+}
+
+// TestTimeBoxExpiryRevealsVotes makes sure votes are only revealed at expiry when AutoFinishVoting is disabled
+func TestTimeBoxExpiryRevealsVotes(t *testing.T) {
+	dataSvc := newFakeTimeBoxDataSvc(false)
+	b, _ := newTimeBoxTestService(dataSvc)
+
+	b.expireTimeBox("game", thunderdome.PokerTimeBox{StoryID: "story", DurationSeconds: 300})
+
+	select {
+	case storyID := <-dataSvc.votingEnded:
+		if storyID != "story" {
+			t.Errorf("expected voting to end for story, got %s", storyID)
+		}
+	default:
+		t.Fatal("expected voting to end at time box expiry")
+	}
+	select {
+	case points := <-dataSvc.finalized:
+		t.Errorf("expected the story not to be finalized, got %s", points)
+	default:
+	}
+}
+
+// TestTimeBoxExpiryOnce makes sure a time box that was already expired elsewhere isn't acted on again
+func TestTimeBoxExpiryOnce(t *testing.T) {
+	dataSvc := newFakeTimeBoxDataSvc(true)
+	dataSvc.expired = true
+	b, _ := newTimeBoxTestService(dataSvc)
+
+	b.expireTimeBox("game", thunderdome.PokerTimeBox{StoryID: "story", DurationSeconds: 300})
+
+	select {
+	case <-dataSvc.votingEnded:
+		t.Fatal("expected voting not to be ended again")
+	default:
+	}
+}
+
+// TestTimeBoxStop makes sure a stopped time box doesn't expire
+func TestTimeBoxStop(t *testing.T) {
+	dataSvc := newFakeTimeBoxDataSvc(true)
+	b, clock := newTimeBoxTestService(dataSvc)
+
+	b.timeBoxes.start("game", thunderdome.PokerTimeBox{StoryID: "story", DurationSeconds: 300, StartedAt: clock.now})
+	if !b.timeBoxes.stop("game") {
+		t.Fatal("expected a running time box to be stopped")
+	}
+
+	select {
+	case clock.fired <- clock.now.Add(5 * time.Minute):
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	select {
+	case <-dataSvc.votingEnded:
+		t.Fatal("expected the stopped time box not to end voting")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// TestTimeBoxExpiryQuorum makes sure the story is only finalized at expiry once the quorum voted
+func TestTimeBoxExpiryQuorum(t *testing.T) {
+	tests := []struct {
+		name          string
+		votes         []*thunderdome.Vote
+		wantFinalized bool
+	}{
+		{name: "exactly quorum", votes: []*thunderdome.Vote{
+			{UserID: "a", VoteValue: "3"}, {UserID: "b", VoteValue: "5"}, {UserID: "c", VoteValue: "5"},
+		}, wantFinalized: true},
+		{name: "one vote short", votes: []*thunderdome.Vote{
+			{UserID: "a", VoteValue: "3"}, {UserID: "b", VoteValue: "5"},
+		}, wantFinalized: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dataSvc := newFakeTimeBoxDataSvc(true)
+			dataSvc.quorumPercentage = 75
+			dataSvc.users = activeParticipants(4)
+			dataSvc.votes = tt.votes
+			b, _ := newTimeBoxTestService(dataSvc)
+
+			b.expireTimeBox("game", thunderdome.PokerTimeBox{StoryID: "story", DurationSeconds: 300})
+
+			select {
+			case points := <-dataSvc.finalized:
+				if !tt.wantFinalized {
+					t.Errorf("expected the story not to be finalized, got %s", points)
+				}
+			default:
+				if tt.wantFinalized {
+					t.Error("expected the story to be finalized once the quorum voted")
+				}
+			}
+		})
+	}
+}
+
+// TestTimeBoxExpiryRearmsWhenClockAhead makes sure a time box that hasn't run out by the database clock
+// is waited out again instead of being dropped
+func TestTimeBoxExpiryRearmsWhenClockAhead(t *testing.T) {
+	dataSvc := newFakeTimeBoxDataSvc(true)
+	b, clock := newTimeBoxTestService(dataSvc)
+	timeBox := thunderdome.PokerTimeBox{StoryID: "story", DurationSeconds: 300, StartedAt: clock.now}
+	dataSvc.expired = true
+	dataSvc.running = &thunderdome.PokerRunningTimeBox{PokerID: "game", TimeBox: timeBox, Remaining: 2 * time.Second}
+
+	b.expireTimeBox("game", timeBox)
+
+	b.timeBoxes.mu.Lock()
+	_, running := b.timeBoxes.running["game"]
+	b.timeBoxes.mu.Unlock()
+	if !running {
+		t.Fatal("expected the time box to be waited out again")
+	}
+}
+
+// TestRearmTimeBoxes makes sure stored time boxes are waited out again after a restart
+func TestRearmTimeBoxes(t *testing.T) {
+	dataSvc := newFakeTimeBoxDataSvc(true)
+	b, clock := newTimeBoxTestService(dataSvc)
+	dataSvc.running = &thunderdome.PokerRunningTimeBox{
+		PokerID: "game",
+		TimeBox: thunderdome.PokerTimeBox{StoryID: "story", DurationSeconds: 300, StartedAt: clock.now.Add(-10 * time.Minute)},
+	}
+
+	b.RearmTimeBoxes(context.Background())
+	clock.fired <- clock.now
+
+	select {
+	case points := <-dataSvc.finalized:
+		if points != "5" {
+			t.Errorf("expected the story to be finalized with the median vote 5, got %s", points)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the rearmed time box to expire")
+	}
+}
@@ -0,0 +1,162 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+func (m *MockPokerDataSvc) DeleteGame(pokerID string) error {
+	args := m.Called(pokerID)
+	return args.Error(0)
+}
+
+func (m *MockPokerDataSvc) RestoreGame(ctx context.Context, pokerID string) error {
+	args := m.Called(ctx, pokerID)
+	return args.Error(0)
+}
+
+func (m *MockPokerDataSvc) GetGamesByUser(userID string, limit int, offset int) ([]*thunderdome.Poker, int, error) {
+	args := m.Called(userID, limit, offset)
+	return args.Get(0).([]*thunderdome.Poker), args.Int(1), args.Error(2)
+}
+
+func (m *MockPokerDataSvc) GetArchivedGamesByUser(userID string, limit int, offset int) ([]*thunderdome.Poker, int, error) {
+	args := m.Called(userID, limit, offset)
+	return args.Get(0).([]*thunderdome.Poker), args.Int(1), args.Error(2)
+}
+
+func (m *MockPokerDataSvc) GetArchivedGames(limit int, offset int) ([]*thunderdome.Poker, int, error) {
+	args := m.Called(limit, offset)
+	return args.Get(0).([]*thunderdome.Poker), args.Int(1), args.Error(2)
+}
+
+// TestHandlePokerPermanentDelete makes sure only admins can permanently delete a game
+func TestHandlePokerPermanentDelete(t *testing.T) {
+	tests := []struct {
+		name         string
+		userType     string
+		expectDelete bool
+		expectedCode int
+	}{
+		{name: "admin", userType: thunderdome.AdminUserType, expectDelete: true, expectedCode: http.StatusOK},
+		{name: "non admin", userType: thunderdome.RegisteredUserType, expectDelete: false, expectedCode: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPokerDataSvc := new(MockPokerDataSvc)
+			if tt.expectDelete {
+				mockPokerDataSvc.On("DeleteGame", testGameID).Return(nil)
+			}
+			service := &Service{PokerDataSvc: mockPokerDataSvc, Logger: otelzap.New(zap.NewNop())}
+
+			req := httptest.NewRequest("DELETE", "/battles/"+testGameID+"?permanent=true", nil)
+			req = mux.SetURLVars(req, map[string]string{"battleId": testGameID})
+			req = req.WithContext(context.WithValue(req.Context(), contextKeyUserID, testFacilitatorID))
+			req = req.WithContext(context.WithValue(req.Context(), contextKeyUserType, tt.userType))
+			rr := httptest.NewRecorder()
+			service.handlePokerDelete(nil).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+			mockPokerDataSvc.AssertExpectations(t)
+			if !tt.expectDelete {
+				mockPokerDataSvc.AssertNotCalled(t, "DeleteGame", mock.Anything)
+			}
+		})
+	}
+}
+
+func TestHandlePokerRestore(t *testing.T) {
+	const archivedGameID = "623e4567-e89b-12d3-a456-426614174000"
+	const deletedGameID = "723e4567-e89b-12d3-a456-426614174000"
+
+	mockPokerDataSvc := new(MockPokerDataSvc)
+	service := &Service{PokerDataSvc: mockPokerDataSvc, Logger: otelzap.New(zap.NewNop())}
+
+	mockPokerDataSvc.On("RestoreGame", mock.Anything, archivedGameID).Return(nil).Once()
+	mockPokerDataSvc.On("RestoreGame", mock.Anything, deletedGameID).Return(fmt.Errorf("BATTLE_NOT_FOUND")).Once()
+
+	restore := func(gameID string) int {
+		req := httptest.NewRequest("POST", "/admin/battles/"+gameID+"/restore", nil)
+		req = mux.SetURLVars(req, map[string]string{"battleId": gameID})
+		req = req.WithContext(context.WithValue(req.Context(), contextKeyUserID, testFacilitatorID))
+		rr := httptest.NewRecorder()
+		service.handlePokerRestore().ServeHTTP(rr, req)
+
+		return rr.Code
+	}
+
+	assert.Equal(t, http.StatusOK, restore(archivedGameID))
+	assert.Equal(t, http.StatusNotFound, restore(deletedGameID))
+	assert.Equal(t, http.StatusBadRequest, restore("not-a-uuid"))
+
+	mockPokerDataSvc.AssertExpectations(t)
+}
+
+// TestHandleGetUserGamesArchived makes sure archived games are only listed when requested
+func TestHandleGetUserGamesArchived(t *testing.T) {
+	archivedAt := time.Date(2025, 3, 16, 12, 0, 0, 0, time.UTC)
+	mockPokerDataSvc := new(MockPokerDataSvc)
+	mockPokerDataSvc.On("GetGamesByUser", testFacilitatorID, 20, 0).Return([]*thunderdome.Poker{
+		{ID: "active-game", Name: "Sprint 12"},
+	}, 1, nil).Once()
+	mockPokerDataSvc.On("GetArchivedGamesByUser", testFacilitatorID, 20, 0).Return([]*thunderdome.Poker{
+		{ID: "archived-game", Name: "Sprint 11", ArchivedAt: &archivedAt},
+	}, 1, nil).Once()
+	service := &Service{PokerDataSvc: mockPokerDataSvc}
+
+	getGames := func(query string) []*thunderdome.Poker {
+		req := httptest.NewRequest("GET", "/users/"+testFacilitatorID+"/battles"+query, nil)
+		req = mux.SetURLVars(req, map[string]string{"userId": testFacilitatorID})
+		rr := httptest.NewRecorder()
+		service.handleGetUserGames().ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		var body struct {
+			Data []*thunderdome.Poker `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+
+		return body.Data
+	}
+
+	games := getGames("")
+	if assert.Len(t, games, 1) {
+		assert.Equal(t, "active-game", games[0].ID)
+		assert.Nil(t, games[0].ArchivedAt)
+	}
+
+	games = getGames("?archived=true")
+	if assert.Len(t, games, 1) {
+		assert.Equal(t, "archived-game", games[0].ID)
+		assert.True(t, archivedAt.Equal(*games[0].ArchivedAt))
+	}
+
+	mockPokerDataSvc.AssertExpectations(t)
+}
+
+func TestHandleGetPokerGamesArchived(t *testing.T) {
+	mockPokerDataSvc := new(MockPokerDataSvc)
+	mockPokerDataSvc.On("GetArchivedGames", 20, 0).Return([]*thunderdome.Poker{{ID: "archived-game"}}, 1, nil).Once()
+	service := &Service{PokerDataSvc: mockPokerDataSvc, Logger: otelzap.New(zap.NewNop())}
+
+	req := httptest.NewRequest("GET", "/battles?archived=true", nil)
+	req = req.WithContext(context.WithValue(req.Context(), contextKeyUserID, testFacilitatorID))
+	rr := httptest.NewRecorder()
+	service.handleGetPokerGames().ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockPokerDataSvc.AssertExpectations(t)
+}
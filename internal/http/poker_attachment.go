@@ -0,0 +1,261 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/storage"
+)
+
+type storyAttachmentPresignRequestBody struct {
+	Filename    string `json:"filename" validate:"required,max=255"`
+	ContentType string `json:"contentType" validate:"required,max=255"`
+	Size        int64  `json:"size" validate:"required,gt=0"`
+}
+
+type storyAttachmentConfirmRequestBody struct {
+	Key      string `json:"key" validate:"required,max=512"`
+	Filename string `json:"filename" validate:"required,max=255"`
+}
+
+// confirmStoryUser responds with the failure when the session user isn't a user of the story's poker game
+func (s *Service) confirmStoryUser(w http.ResponseWriter, r *http.Request, storyID string, handlerName string) bool {
+	ctx := r.Context()
+	sessionUserID := ctx.Value(contextKeyUserID).(string)
+
+	err := s.PokerDataSvc.ConfirmStoryUser(ctx, storyID, sessionUserID)
+	if err == nil {
+		return true
+	}
+
+	switch err.Error() {
+	case "STORY_NOT_FOUND":
+		s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, err.Error()))
+	case "REQUIRES_GAME_USER":
+		s.Failure(w, r, http.StatusForbidden, Errorf(EUNAUTHORIZED, err.Error()))
+	default:
+		s.Logger.Ctx(ctx).Error(handlerName+" error", zap.Error(err),
+			zap.String("story_id", storyID), zap.String("session_user_id", sessionUserID))
+		s.Failure(w, r, http.StatusInternalServerError, err)
+	}
+
+	return false
+}
+
+// handlePokerStoryAttachmentPresign handles getting a pre-signed upload for a poker story attachment
+//
+//	@Summary		Presign Poker Story Attachment
+//	@Description	Gets the form to upload the file with a multipart POST, limited to the content type and size,
+//	@Description	the attachment is added to the story once the upload is confirmed
+//	@Param			storyId		path	string								true	"the story ID"
+//	@Param			attachment	body	storyAttachmentPresignRequestBody	true	"attachment file details"
+//	@Tags			poker
+//	@Produce		json
+//	@Success		200	object	standardJsonResponse{data=storage.PresignedUpload}
+//	@Success		400	object	standardJsonResponse{}
+//	@Success		403	object	standardJsonResponse{}
+//	@Success		404	object	standardJsonResponse{}
+//	@Success		500	object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/stories/{storyId}/attachments/presign [post]
+func (s *Service) handlePokerStoryAttachmentPresign() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		storyID := vars["storyId"]
+		idErr := validate.Var(storyID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+
+		body, bodyErr := io.ReadAll(r.Body)
+		if bodyErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, bodyErr.Error()))
+			return
+		}
+
+		var req = storyAttachmentPresignRequestBody{}
+		jsonErr := json.Unmarshal(body, &req)
+		if jsonErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, jsonErr.Error()))
+			return
+		}
+
+		inputErr := validate.Struct(req)
+		if inputErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, inputErr.Error()))
+			return
+		}
+		if s.Config.Storage.MaxUploadSize > 0 && req.Size > s.Config.Storage.MaxUploadSize {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "ATTACHMENT_TOO_LARGE"))
+			return
+		}
+
+		if !s.confirmStoryUser(w, r, storyID, "handlePokerStoryAttachmentPresign") {
+			return
+		}
+
+		upload, err := s.StorageSvc.PresignUpload(ctx, storyID, req.Filename, req.ContentType, req.Size)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handlePokerStoryAttachmentPresign error", zap.Error(err),
+				zap.String("story_id", storyID), zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, upload, nil)
+	}
+}
+
+// handlePokerStoryAttachmentConfirm handles adding an uploaded file to the poker story's attachments
+//
+//	@Summary		Confirm Poker Story Attachment
+//	@Description	Adds the file uploaded with the pre-signed upload to the poker story's attachments
+//	@Param			storyId		path	string								true	"the story ID"
+//	@Param			attachment	body	storyAttachmentConfirmRequestBody	true	"uploaded file details"
+//	@Tags			poker
+//	@Produce		json
+//	@Success		200	object	standardJsonResponse{data=thunderdome.StoryAttachment}
+//	@Success		400	object	standardJsonResponse{}
+//	@Success		403	object	standardJsonResponse{}
+//	@Success		404	object	standardJsonResponse{}
+//	@Success		500	object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/stories/{storyId}/attachments [post]
+func (s *Service) handlePokerStoryAttachmentConfirm() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		storyID := vars["storyId"]
+		idErr := validate.Var(storyID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+
+		body, bodyErr := io.ReadAll(r.Body)
+		if bodyErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, bodyErr.Error()))
+			return
+		}
+
+		var req = storyAttachmentConfirmRequestBody{}
+		jsonErr := json.Unmarshal(body, &req)
+		if jsonErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, jsonErr.Error()))
+			return
+		}
+
+		inputErr := validate.Struct(req)
+		if inputErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, inputErr.Error()))
+			return
+		}
+		// keys are generated per story so an upload can't be attached to another story
+		if !strings.HasPrefix(req.Key, storage.StoryKeyPrefix(storyID)) {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "INVALID_ATTACHMENT_KEY"))
+			return
+		}
+
+		if !s.confirmStoryUser(w, r, storyID, "handlePokerStoryAttachmentConfirm") {
+			return
+		}
+
+		object, err := s.StorageSvc.StatObject(ctx, req.Key)
+		if err != nil && errors.Is(err, storage.ErrObjectNotFound) {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "ATTACHMENT_NOT_UPLOADED"))
+			return
+		} else if err != nil {
+			s.Logger.Ctx(ctx).Error("handlePokerStoryAttachmentConfirm error", zap.Error(err),
+				zap.String("story_id", storyID), zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		if s.Config.Storage.MaxUploadSize > 0 && object.Size > s.Config.Storage.MaxUploadSize {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "ATTACHMENT_TOO_LARGE"))
+			return
+		}
+
+		attachment, err := s.PokerDataSvc.CreateStoryAttachment(ctx, storyID, sessionUserID, req.Filename, req.Key, object.ContentType, object.Size)
+		if err != nil && err.Error() == "ATTACHMENT_ALREADY_CONFIRMED" {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, err.Error()))
+			return
+		} else if err != nil {
+			s.Logger.Ctx(ctx).Error("handlePokerStoryAttachmentConfirm error", zap.Error(err),
+				zap.String("story_id", storyID), zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		attachment.DownloadURL, err = s.StorageSvc.PresignDownload(ctx, attachment.ObjectKey)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handlePokerStoryAttachmentConfirm error", zap.Error(err),
+				zap.String("story_id", storyID), zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, attachment, nil)
+	}
+}
+
+// handleGetPokerStoryAttachments gets the poker story's attachments
+//
+//	@Summary		Get Poker Story Attachments
+//	@Description	Gets the files attached to the poker story
+//	@Param			storyId	path	string	true	"the story ID"
+//	@Tags			poker
+//	@Produce		json
+//	@Success		200	object	standardJsonResponse{data=[]thunderdome.StoryAttachment}
+//	@Success		403	object	standardJsonResponse{}
+//	@Success		404	object	standardJsonResponse{}
+//	@Success		500	object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/stories/{storyId}/attachments [get]
+func (s *Service) handleGetPokerStoryAttachments() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		storyID := vars["storyId"]
+		idErr := validate.Var(storyID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+
+		if !s.confirmStoryUser(w, r, storyID, "handleGetPokerStoryAttachments") {
+			return
+		}
+
+		attachments, err := s.PokerDataSvc.GetStoryAttachments(ctx, storyID)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleGetPokerStoryAttachments error", zap.Error(err),
+				zap.String("story_id", storyID), zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		// download URLs expire so they're signed each time the attachments are fetched
+		for _, attachment := range attachments {
+			attachment.DownloadURL, err = s.StorageSvc.PresignDownload(ctx, attachment.ObjectKey)
+			if err != nil {
+				s.Logger.Ctx(ctx).Error("handleGetPokerStoryAttachments error", zap.Error(err),
+					zap.String("story_id", storyID), zap.String("session_user_id", sessionUserID))
+				s.Failure(w, r, http.StatusInternalServerError, err)
+				return
+			}
+		}
+
+		s.Success(w, r, http.StatusOK, attachments, nil)
+	}
+}
@@ -0,0 +1,211 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/storage"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+func (m *MockPokerDataSvc) ConfirmStoryUser(ctx context.Context, storyID string, userID string) error {
+	args := m.Called(ctx, storyID, userID)
+	return args.Error(0)
+}
+
+func (m *MockPokerDataSvc) CreateStoryAttachment(ctx context.Context, storyID string, userID string, filename string, objectKey string, contentType string, size int64) (*thunderdome.StoryAttachment, error) {
+	args := m.Called(ctx, storyID, userID, filename, objectKey, contentType, size)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*thunderdome.StoryAttachment), args.Error(1)
+}
+
+// mockStorageSvc returns fixed pre-signed uploads and the uploaded objects
+type mockStorageSvc struct {
+	objects map[string]*storage.ObjectInfo
+}
+
+func (m *mockStorageSvc) PresignUpload(ctx context.Context, storyID string, filename string, contentType string, size int64) (*storage.PresignedUpload, error) {
+	key := storage.StoryKeyPrefix(storyID) + filename
+	return &storage.PresignedUpload{
+		URL:    "https://storage.test/upload",
+		Fields: map[string]string{"key": key, "Content-Type": contentType, "signature": "abc"},
+		Key:    key,
+	}, nil
+}
+
+func (m *mockStorageSvc) StatObject(ctx context.Context, key string) (*storage.ObjectInfo, error) {
+	object, ok := m.objects[key]
+	if !ok {
+		return nil, storage.ErrObjectNotFound
+	}
+	return object, nil
+}
+
+func (m *mockStorageSvc) PresignDownload(ctx context.Context, key string) (string, error) {
+	return "https://storage.test/" + key + "?signature=abc", nil
+}
+
+func TestHandlePokerStoryAttachmentPresign(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		confirmErr     error
+		expectedStatus int
+	}{
+		{
+			name:           "success",
+			body:           `{"filename":"mockup.png","contentType":"image/png","size":1024}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "too large",
+			body:           `{"filename":"mockup.png","contentType":"image/png","size":4096}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing filename",
+			body:           `{"contentType":"image/png","size":1024}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "not a game user",
+			body:           `{"filename":"mockup.png","contentType":"image/png","size":1024}`,
+			confirmErr:     errors.New("REQUIRES_GAME_USER"),
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "story not found",
+			body:           `{"filename":"mockup.png","contentType":"image/png","size":1024}`,
+			confirmErr:     errors.New("STORY_NOT_FOUND"),
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPokerDataSvc := new(MockPokerDataSvc)
+			mockPokerDataSvc.On("ConfirmStoryUser", mock.Anything, testStoryID, testParticipantID).Return(tt.confirmErr).Maybe()
+			service := &Service{
+				Config:       &Config{Storage: storage.Config{MaxUploadSize: 2048}},
+				Logger:       otelzap.New(zap.NewNop()),
+				PokerDataSvc: mockPokerDataSvc,
+				StorageSvc:   &mockStorageSvc{},
+			}
+
+			req := httptest.NewRequest("POST", "/stories/"+testStoryID+"/attachments/presign", strings.NewReader(tt.body))
+			req = mux.SetURLVars(req, map[string]string{"storyId": testStoryID})
+			req = req.WithContext(context.WithValue(req.Context(), contextKeyUserID, testParticipantID))
+			rr := httptest.NewRecorder()
+			service.handlePokerStoryAttachmentPresign().ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response struct {
+					Data storage.PresignedUpload `json:"data"`
+				}
+				assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+				assert.Equal(t, "https://storage.test/upload", response.Data.URL)
+				assert.Equal(t, "stories/"+testStoryID+"/mockup.png", response.Data.Key)
+			}
+			// the attachment isn't recorded until the upload is confirmed
+			mockPokerDataSvc.AssertNotCalled(t, "CreateStoryAttachment")
+			mockPokerDataSvc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandlePokerStoryAttachmentConfirm(t *testing.T) {
+	uploadedKey := "stories/" + testStoryID + "/mockup.png"
+	tests := []struct {
+		name           string
+		body           string
+		objects        map[string]*storage.ObjectInfo
+		createErr      error
+		expectCreate   bool
+		expectedStatus int
+	}{
+		{
+			name:           "success",
+			body:           `{"key":"` + uploadedKey + `","filename":"mockup.png"}`,
+			objects:        map[string]*storage.ObjectInfo{uploadedKey: {Size: 1024, ContentType: "image/png"}},
+			expectCreate:   true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "not uploaded",
+			body:           `{"key":"` + uploadedKey + `","filename":"mockup.png"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "another story's key",
+			body:           `{"key":"stories/other-story/mockup.png","filename":"mockup.png"}`,
+			objects:        map[string]*storage.ObjectInfo{"stories/other-story/mockup.png": {Size: 1024, ContentType: "image/png"}},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "too large",
+			body:           `{"key":"` + uploadedKey + `","filename":"mockup.png"}`,
+			objects:        map[string]*storage.ObjectInfo{uploadedKey: {Size: 4096, ContentType: "image/png"}},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "already confirmed",
+			body:           `{"key":"` + uploadedKey + `","filename":"mockup.png"}`,
+			objects:        map[string]*storage.ObjectInfo{uploadedKey: {Size: 1024, ContentType: "image/png"}},
+			createErr:      errors.New("ATTACHMENT_ALREADY_CONFIRMED"),
+			expectCreate:   true,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPokerDataSvc := new(MockPokerDataSvc)
+			mockPokerDataSvc.On("ConfirmStoryUser", mock.Anything, testStoryID, testParticipantID).Return(nil).Maybe()
+			if tt.expectCreate {
+				var attachment *thunderdome.StoryAttachment
+				if tt.createErr == nil {
+					attachment = &thunderdome.StoryAttachment{ID: "attachment-id", ObjectKey: uploadedKey}
+				}
+				// the size and content type come from the uploaded object
+				mockPokerDataSvc.On("CreateStoryAttachment", mock.Anything, testStoryID, testParticipantID,
+					"mockup.png", uploadedKey, "image/png", int64(1024)).Return(attachment, tt.createErr)
+			}
+			service := &Service{
+				Config:       &Config{Storage: storage.Config{MaxUploadSize: 2048}},
+				Logger:       otelzap.New(zap.NewNop()),
+				PokerDataSvc: mockPokerDataSvc,
+				StorageSvc:   &mockStorageSvc{objects: tt.objects},
+			}
+
+			req := httptest.NewRequest("POST", "/stories/"+testStoryID+"/attachments", strings.NewReader(tt.body))
+			req = mux.SetURLVars(req, map[string]string{"storyId": testStoryID})
+			req = req.WithContext(context.WithValue(req.Context(), contextKeyUserID, testParticipantID))
+			rr := httptest.NewRecorder()
+			service.handlePokerStoryAttachmentConfirm().ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response struct {
+					Data thunderdome.StoryAttachment `json:"data"`
+				}
+				assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+				assert.Equal(t, "attachment-id", response.Data.ID)
+				assert.Equal(t, "https://storage.test/"+uploadedKey+"?signature=abc", response.Data.DownloadURL)
+			}
+			mockPokerDataSvc.AssertExpectations(t)
+		})
+	}
+}
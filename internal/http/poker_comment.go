@@ -0,0 +1,127 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/http/poker"
+)
+
+type storyCommentRequestBody struct {
+	Body     string `json:"body" validate:"required,max=10000"`
+	ParentID string `json:"parentId" validate:"omitempty,uuid"`
+}
+
+// handlePokerStoryCommentAdd handles adding a comment to a poker story
+//
+//	@Summary		Add Poker Story Comment
+//	@Description	Adds a comment to the poker story, a parent ID makes the comment a reply to another comment on the story
+//	@Param			storyId	path	string					true	"the story ID"
+//	@Param			comment	body	storyCommentRequestBody	true	"the comment"
+//	@Tags			poker
+//	@Produce		json
+//	@Success		200	object	standardJsonResponse{data=thunderdome.PokerStoryComment}
+//	@Success		400	object	standardJsonResponse{}
+//	@Success		403	object	standardJsonResponse{}
+//	@Success		404	object	standardJsonResponse{}
+//	@Success		500	object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/stories/{storyId}/comments [post]
+func (s *Service) handlePokerStoryCommentAdd(pokerSvc *poker.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		storyID := vars["storyId"]
+		idErr := validate.Var(storyID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+
+		body, bodyErr := io.ReadAll(r.Body)
+		if bodyErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, bodyErr.Error()))
+			return
+		}
+
+		var req = storyCommentRequestBody{}
+		jsonErr := json.Unmarshal(body, &req)
+		if jsonErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, jsonErr.Error()))
+			return
+		}
+
+		inputErr := validate.Struct(req)
+		if inputErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, inputErr.Error()))
+			return
+		}
+
+		if !s.confirmStoryUser(w, r, storyID, "handlePokerStoryCommentAdd") {
+			return
+		}
+
+		comment, err := pokerSvc.AddStoryComment(ctx, storyID, sessionUserID, req.ParentID, req.Body)
+		if err != nil {
+			switch err.Error() {
+			case "COMMENT_BODY_REQUIRED":
+				s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, err.Error()))
+			case "COMMENT_PARENT_NOT_FOUND":
+				s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, err.Error()))
+			default:
+				s.Logger.Ctx(ctx).Error("handlePokerStoryCommentAdd error", zap.Error(err),
+					zap.String("story_id", storyID), zap.String("session_user_id", sessionUserID))
+				s.Failure(w, r, http.StatusInternalServerError, err)
+			}
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, comment, nil)
+	}
+}
+
+// handleGetPokerStoryComments gets the poker story's comments
+//
+//	@Summary		Get Poker Story Comments
+//	@Description	Gets the poker story's comments oldest first with replies nested under the comment they reply to
+//	@Param			storyId	path	string	true	"the story ID"
+//	@Tags			poker
+//	@Produce		json
+//	@Success		200	object	standardJsonResponse{data=[]thunderdome.PokerStoryComment}
+//	@Success		403	object	standardJsonResponse{}
+//	@Success		404	object	standardJsonResponse{}
+//	@Success		500	object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/stories/{storyId}/comments [get]
+func (s *Service) handleGetPokerStoryComments() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		storyID := vars["storyId"]
+		idErr := validate.Var(storyID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+
+		if !s.confirmStoryUser(w, r, storyID, "handleGetPokerStoryComments") {
+			return
+		}
+
+		comments, err := s.PokerDataSvc.ListComments(ctx, storyID)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleGetPokerStoryComments error", zap.Error(err),
+				zap.String("story_id", storyID), zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, comments, nil)
+	}
+}
@@ -0,0 +1,107 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/http/poker"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// pokerStoryCSVImportMaxBytes comfortably fits the max rows of stories with lengthy descriptions
+const pokerStoryCSVImportMaxBytes = 5 << 20
+
+// isStoryCSVInputError checks whether the csv import failed because of the file or column mapping
+func isStoryCSVInputError(err error) bool {
+	return errors.Is(err, thunderdome.ErrStoryCSVInvalid) ||
+		errors.Is(err, thunderdome.ErrStoryCSVInvalidMapping) ||
+		errors.Is(err, thunderdome.ErrStoryCSVMissingName) ||
+		errors.Is(err, thunderdome.ErrStoryCSVTooManyRows)
+}
+
+// handlePokerStoriesCSVImport handles importing poker stories from a csv file
+//
+//	@Summary		Import Poker Stories from CSV
+//	@Description	Imports poker stories from a csv file, the optional columnMapping maps csv header names to story fields
+//	@Description	(name, type, reference_id, link, description, acceptance_criteria, priority), without a mapping headers
+//	@Description	named after the story fields are used. A name column is required, rows that can't be imported are listed in errors.
+//	@Description	When import deduplication is enabled stories matching an existing reference_id update it, reported in duplication
+//	@Tags			poker
+//	@Accept			mpfd
+//	@Produce		json
+//	@Param			battleId		path		string	true	"the poker game ID"
+//	@Param			file			formData	file	true	"stories csv file"
+//	@Param			columnMapping	formData	string	false	"json object of csv header to story field"
+//	@Success		200				object		standardJsonResponse{data=thunderdome.StoryCSVImportResult}
+//	@Failure		400				object		standardJsonResponse{}
+//	@Failure		403				object		standardJsonResponse{}
+//	@Failure		500				object		standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/battles/{battleId}/plans/import/csv [post]
+func (s *Service) handlePokerStoriesCSVImport(pokerSvc *poker.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		gameID := vars["battleId"]
+		idErr := validate.Var(gameID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+
+		r.Body = http.MaxBytesReader(w, r.Body, pokerStoryCSVImportMaxBytes)
+		file, _, fileErr := r.FormFile("file")
+		if fileErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "INVALID_FILE"))
+			return
+		}
+		defer file.Close()
+
+		csvData, readErr := io.ReadAll(file)
+		if readErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "INVALID_FILE"))
+			return
+		}
+
+		columnMapping := make(map[string]string)
+		if mapping := r.FormValue("columnMapping"); mapping != "" {
+			if jsonErr := json.Unmarshal([]byte(mapping), &columnMapping); jsonErr != nil {
+				s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, thunderdome.ErrStoryCSVInvalidMapping.Error()))
+				return
+			}
+		}
+
+		stories, duplication, err := pokerSvc.ImportStoriesFromCSV(ctx, gameID, sessionUserID, csvData, columnMapping, s.Config.ImportDeduplicationEnabled)
+		var rowErrors thunderdome.StoryCSVImportRowErrors
+		switch {
+		case err == nil, errors.As(err, &rowErrors):
+		case isStoryCSVInputError(err):
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, err.Error()))
+			return
+		default:
+			s.Logger.Ctx(ctx).Error("handlePokerStoriesCSVImport error", zap.Error(err),
+				zap.String("poker_id", gameID), zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		if rowErrors == nil {
+			rowErrors = make(thunderdome.StoryCSVImportRowErrors, 0)
+		}
+		result := thunderdome.StoryCSVImportResult{
+			Total:       len(stories) + len(rowErrors),
+			Imported:    len(stories),
+			Failed:      len(rowErrors),
+			Stories:     stories,
+			Errors:      rowErrors,
+			Duplication: duplication,
+		}
+
+		s.Success(w, r, http.StatusOK, result, nil)
+	}
+}
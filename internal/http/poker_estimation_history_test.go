@@ -0,0 +1,101 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+// estimationHistoryPokerDataSvc pages through the seeded estimation history newest first
+type estimationHistoryPokerDataSvc struct {
+	*MockPokerDataSvc
+	records []thunderdome.UserEstimationRecord
+}
+
+func (m *estimationHistoryPokerDataSvc) GetUserEstimationHistory(ctx context.Context, userID string, limit int, offset int) ([]thunderdome.UserEstimationRecord, error) {
+	if userID != testFacilitatorID {
+		return []thunderdome.UserEstimationRecord{}, nil
+	}
+	start := min(offset, len(m.records))
+	end := min(offset+limit, len(m.records))
+	return m.records[start:end], nil
+}
+
+func seedEstimationHistory(n int) []thunderdome.UserEstimationRecord {
+	newest := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	records := make([]thunderdome.UserEstimationRecord, n)
+	for i := range records {
+		records[i] = thunderdome.UserEstimationRecord{
+			GameName:    fmt.Sprintf("Game %d", i),
+			StoryName:   fmt.Sprintf("Story %d", i),
+			UserVote:    "5",
+			FinalPoints: "3",
+			Delta:       2,
+			CreatedAt:   newest.AddDate(0, 0, -i),
+		}
+	}
+	return records
+}
+
+func TestHandleGetUserEstimationHistory(t *testing.T) {
+	service := &Service{
+		Logger:       otelzap.New(zap.NewNop()),
+		PokerDataSvc: &estimationHistoryPokerDataSvc{MockPokerDataSvc: new(MockPokerDataSvc), records: seedEstimationHistory(30)},
+	}
+
+	getHistory := func(query string) []thunderdome.UserEstimationRecord {
+		t.Helper()
+		req := httptest.NewRequest("GET", "/users/"+testFacilitatorID+"/estimation-history"+query, nil)
+		req = mux.SetURLVars(req, map[string]string{"userId": testFacilitatorID})
+		rr := httptest.NewRecorder()
+		service.handleGetUserEstimationHistory().ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		var response struct {
+			Data []thunderdome.UserEstimationRecord `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		return response.Data
+	}
+
+	tests := []struct {
+		name      string
+		query     string
+		expected  int
+		firstGame string
+	}{
+		{name: "default page", query: "", expected: 20, firstGame: "Game 0"},
+		{name: "second page", query: "?limit=20&offset=20", expected: 10, firstGame: "Game 20"},
+		{name: "middle page", query: "?limit=7&offset=14", expected: 7, firstGame: "Game 14"},
+		{name: "past the end", query: "?limit=10&offset=30", expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records := getHistory(tt.query)
+			assert.Len(t, records, tt.expected)
+			if tt.expected > 0 {
+				assert.Equal(t, tt.firstGame, records[0].GameName)
+				for i := 1; i < len(records); i++ {
+					assert.True(t, records[i].CreatedAt.Before(records[i-1].CreatedAt), "expected newest first")
+				}
+			}
+		})
+	}
+
+	req := httptest.NewRequest("GET", "/users/not-a-uuid/estimation-history", nil)
+	req = mux.SetURLVars(req, map[string]string{"userId": "not-a-uuid"})
+	rr := httptest.NewRecorder()
+	service.handleGetUserEstimationHistory().ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
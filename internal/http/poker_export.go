@@ -0,0 +1,202 @@
+package http
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// jiraCSVHeaders are the columns of Jira's CSV issue import template
+var jiraCSVHeaders = []string{"Summary", "Description", "Issue Type", "Story Points", "Priority", "External Issue URL", "Comment", "Status"}
+
+// jiraIssueKeyPattern matches a Jira issue key e.g. PROJECT-123
+var jiraIssueKeyPattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]+-[0-9]+$`)
+
+// jiraPriorities are the Jira priority names of the story priorities, stories without a priority are left empty
+var jiraPriorities = map[int32]string{
+	1: "Blocker",
+	2: "Highest",
+	3: "High",
+	4: "Medium",
+	5: "Low",
+	6: "Lowest",
+}
+
+// jiraIssueURL gets the story's Jira issue URL when its reference ID is a Jira issue key,
+// the story link is used when set otherwise the issue URL is built from the Jira URL
+func jiraIssueURL(story *thunderdome.Story, jiraURL string) string {
+	if !jiraIssueKeyPattern.MatchString(story.ReferenceID) {
+		return ""
+	}
+	if story.Link != "" {
+		return story.Link
+	}
+	if jiraURL == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("%s/browse/%s", strings.TrimRight(jiraURL, "/"), story.ReferenceID)
+}
+
+// jiraComment joins the story's comment thread into a single Jira comment, replies follow the comment they reply to
+func jiraComment(comments []thunderdome.PokerStoryComment) string {
+	flat := thunderdome.FlattenPokerStoryComments(comments)
+	bodies := make([]string, 0, len(flat))
+	for _, c := range flat {
+		bodies = append(bodies, c.Body)
+	}
+
+	return strings.Join(bodies, "\n\n")
+}
+
+// writeJiraCSV writes the stories in Jira's CSV issue import format along with their comments
+func writeJiraCSV(w io.Writer, stories []*thunderdome.Story, comments map[string][]thunderdome.PokerStoryComment, jiraURL string) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(jiraCSVHeaders); err != nil {
+		return err
+	}
+
+	for _, story := range stories {
+		if err := writer.Write([]string{
+			story.Name,
+			story.Description,
+			story.Type,
+			story.Points,
+			jiraPriorities[story.Priority],
+			jiraIssueURL(story, jiraURL),
+			jiraComment(comments[story.ID]),
+			story.Status,
+		}); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
+
+// handlePokerExport exports the poker game stories
+//
+//	@Summary		Export Poker Game
+//	@Description	export the poker game stories with their final points and comments in Jira's CSV import format, every story must have points,
+//	@Description	or with the participants-csv format the participants' attendance for the game facilitators, emails are only included for organization admins
+//	@Tags			poker
+//	@Produce		text/csv
+//	@Param			battleId	path	string	true	"the poker game ID to export"
+//	@Param			format		query	string	true	"the export format, jira-csv or participants-csv"
+//	@Param			jiraUrl		query	string	false	"the Jira URL used to link stories with a Jira issue key reference ID"
+//	@Success		200			{string}	string
+//	@Failure		400			object	standardJsonResponse{}
+//	@Failure		403			object	standardJsonResponse{}
+//	@Failure		404			object	standardJsonResponse{}
+//	@Failure		409			object	standardJsonResponse{}
+//	@Failure		500			object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/battles/{battleId}/export [get]
+func (s *Service) handlePokerExport() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		gameID := vars["battleId"]
+		idErr := validate.Var(gameID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+		switch r.URL.Query().Get("format") {
+		case "jira-csv":
+		case "participants-csv":
+			s.handlePokerParticipantsExport(w, r, gameID)
+			return
+		default:
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "INVALID_EXPORT_FORMAT"))
+			return
+		}
+		jiraURL := r.URL.Query().Get("jiraUrl")
+		if jiraURL != "" {
+			if urlErr := validate.Var(jiraURL, "http_url"); urlErr != nil {
+				s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, urlErr.Error()))
+				return
+			}
+		}
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		userType := ctx.Value(contextKeyUserType).(string)
+
+		game, err := s.PokerDataSvc.GetGameByID(gameID, sessionUserID)
+		if err != nil {
+			s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, "BATTLE_NOT_FOUND"))
+			return
+		}
+
+		// don't allow exporting battle if battle has JoinCode and user hasn't joined yet
+		if game.JoinCode != "" {
+			userErr := s.PokerDataSvc.GetUserActiveStatus(gameID, sessionUserID)
+			if userErr != nil && userErr.Error() != "DUPLICATE_BATTLE_USER" && userType != thunderdome.AdminUserType {
+				s.Failure(w, r, http.StatusForbidden, Errorf(EUNAUTHORIZED, "USER_MUST_JOIN_BATTLE"))
+				return
+			}
+		}
+
+		for _, story := range game.Stories {
+			if story.Points == "" {
+				s.Failure(w, r, http.StatusConflict, Errorf(ECONFLICT, "BATTLE_NOT_FINALIZED"))
+				return
+			}
+		}
+
+		comments := make(map[string][]thunderdome.PokerStoryComment, len(game.Stories))
+		for _, story := range game.Stories {
+			storyComments, err := s.PokerDataSvc.ListComments(ctx, story.ID)
+			if err != nil {
+				s.Logger.Ctx(ctx).Error("handlePokerExport error", zap.Error(err),
+					zap.String("poker_id", gameID), zap.String("session_user_id", sessionUserID))
+				s.Failure(w, r, http.StatusInternalServerError, err)
+				return
+			}
+			comments[story.ID] = storyComments
+		}
+
+		var export bytes.Buffer
+		if err := writeJiraCSV(&export, game.Stories, comments, jiraURL); err != nil {
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-jira.csv"`, gameID))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(export.Bytes())
+	}
+}
+
+// handlePokerParticipantsExport writes the participants-csv export of the poker game
+func (s *Service) handlePokerParticipantsExport(w http.ResponseWriter, r *http.Request, gameID string) {
+	ctx := r.Context()
+	sessionUserID := ctx.Value(contextKeyUserID).(string)
+
+	export, err := s.PokerDataSvc.ExportParticipants(ctx, gameID, sessionUserID)
+	if err != nil {
+		if err.Error() == "REQUIRES_FACILITATOR" {
+			s.Failure(w, r, http.StatusForbidden, Errorf(EUNAUTHORIZED, "REQUIRES_FACILITATOR"))
+			return
+		}
+		s.Logger.Ctx(ctx).Error("handlePokerParticipantsExport error", zap.Error(err),
+			zap.String("poker_id", gameID), zap.String("session_user_id", sessionUserID))
+		s.Failure(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-participants.csv"`, gameID))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(export)
+}
@@ -0,0 +1,116 @@
+package http
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func exportRequest(query string) *http.Request {
+	req := httptest.NewRequest("GET", "/battles/"+testGameID+"/export?"+query, nil)
+	req = mux.SetURLVars(req, map[string]string{"battleId": testGameID})
+	req = req.WithContext(context.WithValue(req.Context(), contextKeyUserID, testParticipantID))
+	return req.WithContext(context.WithValue(req.Context(), contextKeyUserType, thunderdome.RegisteredUserType))
+}
+
+func TestHandlePokerExportJiraCSV(t *testing.T) {
+	mockPokerDataSvc := new(MockPokerDataSvc)
+	mockPokerDataSvc.On("GetGameByID", testGameID, testParticipantID).Return(&thunderdome.Poker{
+		ID: testGameID,
+		Stories: []*thunderdome.Story{
+			{ID: "login", Name: "Login page", Description: "users can log in, with SSO", Type: "Story", Points: "5", Priority: 3, ReferenceID: "WEB-42", Status: thunderdome.StoryStatusReady},
+			{ID: "typo", Name: "Fix typo", Type: "Bug", Points: "1", Priority: 99, ReferenceID: "not a key", Link: "https://example.com/typo"},
+			{ID: "linked", Name: "Linked", Type: "Task", Points: "3", Priority: 1, ReferenceID: "OPS-7", Link: "https://jira.example.com/browse/OPS-7"},
+		},
+	}, nil)
+	mockPokerDataSvc.On("ListComments", mock.Anything, "login").Return([]thunderdome.PokerStoryComment{
+		{ID: "a", Body: "<p>which SSO providers?</p>", Replies: []thunderdome.PokerStoryComment{
+			{ID: "a1", ParentID: "a", Body: "<p>google only</p>"},
+		}},
+		{ID: "b", Body: "<p>does this include the api?</p>"},
+	}, nil)
+	mockPokerDataSvc.On("ListComments", mock.Anything, "typo").Return([]thunderdome.PokerStoryComment{}, nil)
+	mockPokerDataSvc.On("ListComments", mock.Anything, "linked").Return([]thunderdome.PokerStoryComment{}, nil)
+	service := &Service{PokerDataSvc: mockPokerDataSvc}
+
+	rr := httptest.NewRecorder()
+	service.handlePokerExport().ServeHTTP(rr, exportRequest("format=jira-csv&jiraUrl=https://acme.atlassian.net/"))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", rr.Header().Get("Content-Type"))
+
+	records, err := csv.NewReader(strings.NewReader(rr.Body.String())).ReadAll()
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"Summary", "Description", "Issue Type", "Story Points", "Priority", "External Issue URL", "Comment", "Status"},
+		{"Login page", "users can log in, with SSO", "Story", "5", "High", "https://acme.atlassian.net/browse/WEB-42", "<p>which SSO providers?</p>\n\n<p>google only</p>\n\n<p>does this include the api?</p>", "ready"},
+		{"Fix typo", "", "Bug", "1", "", "", "", ""},
+		{"Linked", "", "Task", "3", "Blocker", "https://jira.example.com/browse/OPS-7", "", ""},
+	}, records)
+	mockPokerDataSvc.AssertExpectations(t)
+}
+
+func TestHandlePokerExportNotFinalized(t *testing.T) {
+	mockPokerDataSvc := new(MockPokerDataSvc)
+	mockPokerDataSvc.On("GetGameByID", testGameID, testParticipantID).Return(&thunderdome.Poker{
+		ID: testGameID,
+		Stories: []*thunderdome.Story{
+			{Name: "Estimated", Points: "5"},
+			{Name: "Not estimated"},
+		},
+	}, nil)
+	service := &Service{PokerDataSvc: mockPokerDataSvc}
+
+	rr := httptest.NewRecorder()
+	service.handlePokerExport().ServeHTTP(rr, exportRequest("format=jira-csv"))
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	assert.Contains(t, rr.Body.String(), "BATTLE_NOT_FINALIZED")
+}
+
+func TestHandlePokerExportInvalidFormat(t *testing.T) {
+	service := &Service{PokerDataSvc: new(MockPokerDataSvc)}
+
+	rr := httptest.NewRecorder()
+	service.handlePokerExport().ServeHTTP(rr, exportRequest("format=xlsx"))
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestHandlePokerExportParticipantsCSV(t *testing.T) {
+	t.Run("facilitator", func(t *testing.T) {
+		export := []byte("UserID,Username,Email,JoinedAt,LeftAt,VoteCount,WasSpectator\n")
+		mockPokerDataSvc := new(MockPokerDataSvc)
+		mockPokerDataSvc.On("ExportParticipants", mock.Anything, testGameID, testParticipantID).Return(export, nil)
+		service := &Service{PokerDataSvc: mockPokerDataSvc}
+
+		rr := httptest.NewRecorder()
+		service.handlePokerExport().ServeHTTP(rr, exportRequest("format=participants-csv"))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", rr.Header().Get("Content-Type"))
+		assert.Contains(t, rr.Header().Get("Content-Disposition"), "-participants.csv")
+		assert.Equal(t, string(export), rr.Body.String())
+		mockPokerDataSvc.AssertExpectations(t)
+	})
+
+	t.Run("non facilitator", func(t *testing.T) {
+		mockPokerDataSvc := new(MockPokerDataSvc)
+		mockPokerDataSvc.On("ExportParticipants", mock.Anything, testGameID, testParticipantID).Return(nil, errors.New("REQUIRES_FACILITATOR"))
+		service := &Service{PokerDataSvc: mockPokerDataSvc}
+
+		rr := httptest.NewRecorder()
+		service.handlePokerExport().ServeHTTP(rr, exportRequest("format=participants-csv"))
+
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+}
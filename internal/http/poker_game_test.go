@@ -0,0 +1,146 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+func (m *MockPokerDataSvc) GetGames(limit int, offset int) ([]*thunderdome.Poker, int, error) {
+	args := m.Called(limit, offset)
+	return args.Get(0).([]*thunderdome.Poker), args.Int(1), args.Error(2)
+}
+
+func (m *MockPokerDataSvc) GetActiveGames(limit int, offset int) ([]*thunderdome.Poker, int, error) {
+	args := m.Called(limit, offset)
+	return args.Get(0).([]*thunderdome.Poker), args.Int(1), args.Error(2)
+}
+
+func (m *MockPokerDataSvc) GetUserActiveStatus(pokerID string, userID string) error {
+	args := m.Called(pokerID, userID)
+	return args.Error(0)
+}
+
+func newPokerGameRequest(method string, target string, vars map[string]string, userID string, userType string) *http.Request {
+	req := httptest.NewRequest(method, target, nil)
+	req = mux.SetURLVars(req, vars)
+	req = req.WithContext(context.WithValue(req.Context(), contextKeyUserID, userID))
+	return req.WithContext(context.WithValue(req.Context(), contextKeyUserType, userType))
+}
+
+func TestHandleGetPokerGames(t *testing.T) {
+	tests := []struct {
+		name         string
+		query        string
+		method       string
+		err          error
+		expectedCode int
+	}{
+		{name: "all games", query: "", method: "GetGames", expectedCode: http.StatusOK},
+		{name: "active games", query: "?active=true", method: "GetActiveGames", expectedCode: http.StatusOK},
+		{name: "query error", query: "", method: "GetGames", err: errors.New("get poker games query error"), expectedCode: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPokerDataSvc := new(MockPokerDataSvc)
+			mockPokerDataSvc.On(tt.method, 20, 0).Return([]*thunderdome.Poker{{ID: testGameID}}, 1, tt.err).Once()
+			service := &Service{PokerDataSvc: mockPokerDataSvc, Logger: otelzap.New(zap.NewNop())}
+
+			req := newPokerGameRequest("GET", "/battles"+tt.query, nil, testFacilitatorID, thunderdome.AdminUserType)
+			rr := httptest.NewRecorder()
+			service.handleGetPokerGames().ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+			mockPokerDataSvc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandleGetUserGames(t *testing.T) {
+	tests := []struct {
+		name         string
+		userID       string
+		err          error
+		expectedCode int
+	}{
+		{name: "invalid user id", userID: "not-a-uuid", expectedCode: http.StatusBadRequest},
+		{name: "query error", userID: testParticipantID, err: errors.New("get poker games by user query error"), expectedCode: http.StatusNotFound},
+		{name: "games", userID: testParticipantID, expectedCode: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPokerDataSvc := new(MockPokerDataSvc)
+			if tt.expectedCode != http.StatusBadRequest {
+				mockPokerDataSvc.On("GetGamesByUser", tt.userID, 20, 0).Return([]*thunderdome.Poker{}, 0, tt.err).Once()
+			}
+			service := &Service{PokerDataSvc: mockPokerDataSvc, Logger: otelzap.New(zap.NewNop())}
+
+			req := newPokerGameRequest("GET", "/users/"+tt.userID+"/battles", map[string]string{"userId": tt.userID},
+				tt.userID, thunderdome.RegisteredUserType)
+			rr := httptest.NewRecorder()
+			service.handleGetUserGames().ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+			mockPokerDataSvc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandleGetPokerGameAccess(t *testing.T) {
+	tests := []struct {
+		name         string
+		gameID       string
+		game         *thunderdome.Poker
+		activeStatus error
+		userType     string
+		expectedCode int
+	}{
+		{name: "invalid game id", gameID: "not-a-uuid", userType: thunderdome.RegisteredUserType, expectedCode: http.StatusBadRequest},
+		{name: "not found", gameID: testGameID, userType: thunderdome.RegisteredUserType, expectedCode: http.StatusNotFound},
+		{
+			name: "join code not joined", gameID: testGameID, game: &thunderdome.Poker{ID: testGameID, JoinCode: "secret"},
+			activeStatus: errors.New("BATTLE_USER_NOT_FOUND"), userType: thunderdome.RegisteredUserType, expectedCode: http.StatusForbidden,
+		},
+		{
+			name: "join code joined", gameID: testGameID, game: &thunderdome.Poker{ID: testGameID, JoinCode: "secret"},
+			activeStatus: errors.New("DUPLICATE_BATTLE_USER"), userType: thunderdome.RegisteredUserType, expectedCode: http.StatusOK,
+		},
+		{
+			name: "join code admin", gameID: testGameID, game: &thunderdome.Poker{ID: testGameID, JoinCode: "secret"},
+			activeStatus: errors.New("BATTLE_USER_NOT_FOUND"), userType: thunderdome.AdminUserType, expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPokerDataSvc := new(MockPokerDataSvc)
+			if tt.gameID == testGameID {
+				if tt.game == nil {
+					mockPokerDataSvc.On("GetGameByID", testGameID, testParticipantID).Return(nil, errors.New("BATTLE_NOT_FOUND"))
+				} else {
+					mockPokerDataSvc.On("GetGameByID", testGameID, testParticipantID).Return(tt.game, nil)
+					mockPokerDataSvc.On("GetUserActiveStatus", testGameID, testParticipantID).Return(tt.activeStatus)
+				}
+			}
+			service := &Service{PokerDataSvc: mockPokerDataSvc, Logger: otelzap.New(zap.NewNop())}
+
+			req := newPokerGameRequest("GET", "/battles/"+tt.gameID, map[string]string{"battleId": tt.gameID},
+				testParticipantID, tt.userType)
+			rr := httptest.NewRecorder()
+			service.handleGetPokerGame().ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+			mockPokerDataSvc.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/http/github/actions"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// githubIntegrationProvider is the game_integration provider for GitHub pull request comments
+const githubIntegrationProvider = "github"
+
+// githubRepoPattern matches a GitHub repository in owner/name form
+var githubRepoPattern = regexp.MustCompile(`^[\w.-]+/[\w.-]+$`)
+
+// validGithubRepo checks the repository is in owner/name form without dot path segments
+func validGithubRepo(repo string) bool {
+	if !githubRepoPattern.MatchString(repo) {
+		return false
+	}
+	owner, name, _ := strings.Cut(repo, "/")
+
+	return strings.Trim(owner, ".") != "" && strings.Trim(name, ".") != ""
+}
+
+// githubRepoAllowed checks the repository against the configured allowed repositories,
+// GitHub repository names are case-insensitive
+func githubRepoAllowed(repo string, allowedRepos []string) bool {
+	if len(allowedRepos) == 0 {
+		return true
+	}
+
+	return slices.ContainsFunc(allowedRepos, func(allowed string) bool {
+		return strings.EqualFold(allowed, repo)
+	})
+}
+
+type githubPRCommentRequestBody struct {
+	// Token is the GitHub token used to comment, when empty the token saved for the game is used
+	Token    string `json:"token" validate:"omitempty,max=512"`
+	Repo     string `json:"repo" validate:"required,max=200"`
+	PRNumber int    `json:"prNumber" validate:"required,min=1"`
+}
+
+type githubPRCommentResponse struct {
+	CommentURL string `json:"commentUrl"`
+}
+
+// handlePokerGitHubPRComment handles posting the poker game results as a GitHub pull request comment
+//
+//	@Summary		Post Poker Results to GitHub PR
+//	@Description	Posts the game's story points as a comment on the GitHub pull request, posting again updates the same comment.
+//	@Description	The token is saved encrypted for the game so later requests from GitHub Actions for the same repository can leave it out.
+//	@Param			battleId	path	string						true	"the poker game ID"
+//	@Param			comment		body	githubPRCommentRequestBody	true	"the pull request to comment on"
+//	@Tags			poker
+//	@Produce		json
+//	@Success		200	object	standardJsonResponse{data=githubPRCommentResponse}
+//	@Failure		400	object	standardJsonResponse{}
+//	@Failure		403	object	standardJsonResponse{}
+//	@Failure		404	object	standardJsonResponse{}
+//	@Failure		502	object	standardJsonResponse{}
+//	@Failure		500	object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/battles/{battleId}/github-pr-comment [post]
+func (s *Service) handlePokerGitHubPRComment(gh *actions.GitHubActionsHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		gameID := vars["battleId"]
+		idErr := validate.Var(gameID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		userType := ctx.Value(contextKeyUserType).(string)
+
+		body, bodyErr := io.ReadAll(r.Body)
+		if bodyErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, bodyErr.Error()))
+			return
+		}
+
+		var req = githubPRCommentRequestBody{}
+		jsonErr := json.Unmarshal(body, &req)
+		if jsonErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, jsonErr.Error()))
+			return
+		}
+
+		inputErr := validate.Struct(req)
+		if inputErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, inputErr.Error()))
+			return
+		}
+
+		if !validGithubRepo(req.Repo) {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "INVALID_GITHUB_REPO"))
+			return
+		}
+
+		if !githubRepoAllowed(req.Repo, s.Config.GitHubAllowedRepos) {
+			s.Failure(w, r, http.StatusForbidden, Errorf(EUNAUTHORIZED, "GITHUB_REPO_NOT_ALLOWED"))
+			return
+		}
+
+		if userType != thunderdome.AdminUserType {
+			if err := s.PokerDataSvc.ConfirmFacilitator(gameID, sessionUserID); err != nil {
+				s.Failure(w, r, http.StatusForbidden, Errorf(EUNAUTHORIZED, "REQUIRES_FACILITATOR"))
+				return
+			}
+		}
+
+		game, err := s.PokerDataSvc.GetGameByID(gameID, sessionUserID)
+		if err != nil {
+			s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, "BATTLE_NOT_FOUND"))
+			return
+		}
+
+		token := req.Token
+		if token == "" {
+			var tokenRepo string
+			token, tokenRepo, err = s.PokerDataSvc.GetGameIntegrationToken(ctx, gameID, githubIntegrationProvider)
+			if err != nil {
+				if err.Error() == "INTEGRATION_NOT_FOUND" {
+					s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "GITHUB_TOKEN_REQUIRED"))
+					return
+				}
+				s.Logger.Ctx(ctx).Error("handlePokerGitHubPRComment error", zap.Error(err),
+					zap.String("poker_id", gameID), zap.String("session_user_id", sessionUserID))
+				s.Failure(w, r, http.StatusInternalServerError, err)
+				return
+			}
+			// the saved token can only be used for the repository it was saved with
+			if !strings.EqualFold(tokenRepo, req.Repo) {
+				s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "GITHUB_TOKEN_REQUIRED"))
+				return
+			}
+		}
+
+		commentURL, err := gh.PostResults(ctx, token, actions.PullRequest{Repo: req.Repo, Number: req.PRNumber}, game)
+		if err != nil {
+			switch err.Error() {
+			case "GITHUB_UNAUTHORIZED":
+				s.Failure(w, r, http.StatusForbidden, Errorf(EUNAUTHORIZED, err.Error()))
+			case "GITHUB_PULL_REQUEST_NOT_FOUND":
+				s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, err.Error()))
+			default:
+				s.Logger.Ctx(ctx).Error("handlePokerGitHubPRComment error", zap.Error(err),
+					zap.String("poker_id", gameID), zap.String("session_user_id", sessionUserID))
+				s.Failure(w, r, http.StatusBadGateway, Errorf(EINTERNAL, "GITHUB_REQUEST_FAILED"))
+			}
+			return
+		}
+
+		if req.Token != "" {
+			if err := s.PokerDataSvc.SaveGameIntegration(ctx, gameID, githubIntegrationProvider, req.Repo, req.Token); err != nil {
+				s.Logger.Ctx(ctx).Error("handlePokerGitHubPRComment save integration error", zap.Error(err),
+					zap.String("poker_id", gameID), zap.String("session_user_id", sessionUserID))
+			}
+		}
+
+		s.Success(w, r, http.StatusOK, githubPRCommentResponse{CommentURL: commentURL}, nil)
+	}
+}
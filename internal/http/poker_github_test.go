@@ -0,0 +1,36 @@
+package http
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidGithubRepo(t *testing.T) {
+	tests := []struct {
+		repo  string
+		valid bool
+	}{
+		{repo: "StevenWeathers/thunderdome-planning-poker", valid: true},
+		{repo: "owner/repo.name_1", valid: true},
+		{repo: "owner", valid: false},
+		{repo: "owner/repo/issues", valid: false},
+		{repo: "../owner/repo", valid: false},
+		{repo: "owner/repo?x=1", valid: false},
+		{repo: "owner /repo", valid: false},
+		{repo: "../..", valid: false},
+		{repo: "owner/.", valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.repo, func(t *testing.T) {
+			assert.Equal(t, tt.valid, validGithubRepo(tt.repo))
+		})
+	}
+}
+
+func TestGithubRepoAllowed(t *testing.T) {
+	assert.True(t, githubRepoAllowed("owner/repo", nil))
+	assert.True(t, githubRepoAllowed("Owner/Repo", []string{"owner/repo"}))
+	assert.False(t, githubRepoAllowed("owner/other", []string{"owner/repo"}))
+}
@@ -0,0 +1,102 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+type jiraPointsExportRequestBody struct {
+	InstanceID string `json:"instanceId" validate:"required,uuid"`
+}
+
+// handlePokerJiraExport handles pushing the poker game's finalized story points back to their Jira issues
+//
+//	@Summary		Export Poker Story Points to Jira
+//	@Description	Sets the story points of the Jira issue referenced by each story with finalized points,
+//	@Description	reporting whether each story's points were exported
+//	@Param			battleId	path	string						true	"the poker game ID"
+//	@Param			jira		body	jiraPointsExportRequestBody	true	"the jira instance to export to"
+//	@Tags			poker
+//	@Produce		json
+//	@Success		200	object	standardJsonResponse{data=[]thunderdome.JiraPointsExportResult}
+//	@Failure		400	object	standardJsonResponse{}
+//	@Failure		403	object	standardJsonResponse{}
+//	@Failure		404	object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/battles/{battleId}/export/jira [post]
+func (s *Service) handlePokerJiraExport() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		gameID := vars["battleId"]
+		idErr := validate.Var(gameID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		userType := ctx.Value(contextKeyUserType).(string)
+
+		body, bodyErr := io.ReadAll(r.Body)
+		if bodyErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, bodyErr.Error()))
+			return
+		}
+
+		var req = jiraPointsExportRequestBody{}
+		jsonErr := json.Unmarshal(body, &req)
+		if jsonErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, jsonErr.Error()))
+			return
+		}
+
+		inputErr := validate.Struct(req)
+		if inputErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, inputErr.Error()))
+			return
+		}
+
+		if userType != thunderdome.AdminUserType {
+			if err := s.PokerDataSvc.ConfirmFacilitator(gameID, sessionUserID); err != nil {
+				s.Failure(w, r, http.StatusForbidden, Errorf(EUNAUTHORIZED, "REQUIRES_FACILITATOR"))
+				return
+			}
+		}
+
+		instance, err := s.JiraDataSvc.GetInstanceByID(ctx, req.InstanceID)
+		if err != nil || instance.UserID != sessionUserID {
+			s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, "JIRA_INSTANCE_NOT_FOUND"))
+			return
+		}
+
+		results := make([]*thunderdome.JiraPointsExportResult, 0)
+		for _, story := range s.PokerDataSvc.GetStories(gameID, sessionUserID) {
+			if story.ReferenceID == "" || story.Points == "" {
+				continue
+			}
+
+			result := &thunderdome.JiraPointsExportResult{
+				StoryID:     story.ID,
+				ReferenceID: story.ReferenceID,
+				Points:      story.Points,
+				Success:     true,
+			}
+			if err := s.JiraDataSvc.UpdateJiraIssuePoints(ctx, req.InstanceID, story.ReferenceID, story.Points); err != nil {
+				s.Logger.Ctx(ctx).Warn("handlePokerJiraExport update issue points error", zap.Error(err),
+					zap.String("poker_id", gameID), zap.String("session_user_id", sessionUserID),
+					zap.String("jira_instance_id", req.InstanceID), zap.String("story_id", story.ID))
+				result.Success = false
+				result.Error = err.Error()
+			}
+			results = append(results, result)
+		}
+
+		s.Success(w, r, http.StatusOK, results, nil)
+	}
+}
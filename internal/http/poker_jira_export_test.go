@@ -0,0 +1,93 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+// MockJiraDataSvc is a mock implementation of the JiraDataSvc, methods not overridden panic when called
+type MockJiraDataSvc struct {
+	mock.Mock
+	JiraDataSvc
+}
+
+func (m *MockJiraDataSvc) GetInstanceByID(ctx context.Context, instanceID string) (thunderdome.JiraInstance, error) {
+	args := m.Called(ctx, instanceID)
+	return args.Get(0).(thunderdome.JiraInstance), args.Error(1)
+}
+
+func (m *MockJiraDataSvc) UpdateJiraIssuePoints(ctx context.Context, instanceID string, issueKey string, points string) error {
+	args := m.Called(ctx, instanceID, issueKey, points)
+	return args.Error(0)
+}
+
+// TestHandlePokerJiraExport makes sure only stories with a reference and finalized points are exported,
+// reporting each story's outcome
+func TestHandlePokerJiraExport(t *testing.T) {
+	const instanceID = "823e4567-e89b-12d3-a456-426614174000"
+
+	mockPokerDataSvc := new(MockPokerDataSvc)
+	mockPokerDataSvc.On("ConfirmFacilitator", testGameID, testFacilitatorID).Return(nil)
+	mockPokerDataSvc.On("GetStories", testGameID, testFacilitatorID).Return([]*thunderdome.Story{
+		{ID: "story-1", ReferenceID: "TD-1", Points: "5"},
+		{ID: "story-2", ReferenceID: "TD-404", Points: "3"},
+		{ID: "story-3", ReferenceID: "TD-3"},
+		{ID: "story-4", Points: "8"},
+	})
+	mockJiraDataSvc := new(MockJiraDataSvc)
+	mockJiraDataSvc.On("GetInstanceByID", mock.Anything, instanceID).Return(thunderdome.JiraInstance{ID: instanceID, UserID: testFacilitatorID}, nil)
+	mockJiraDataSvc.On("UpdateJiraIssuePoints", mock.Anything, instanceID, "TD-1", "5").Return(nil).Once()
+	mockJiraDataSvc.On("UpdateJiraIssuePoints", mock.Anything, instanceID, "TD-404", "3").Return(fmt.Errorf("JIRA_ISSUE_NOT_FOUND")).Once()
+	service := &Service{PokerDataSvc: mockPokerDataSvc, JiraDataSvc: mockJiraDataSvc, Logger: otelzap.New(zap.NewNop())}
+
+	req := httptest.NewRequest("POST", "/battles/"+testGameID+"/export/jira", strings.NewReader(`{"instanceId":"`+instanceID+`"}`))
+	req = mux.SetURLVars(req, map[string]string{"battleId": testGameID})
+	req = req.WithContext(context.WithValue(req.Context(), contextKeyUserID, testFacilitatorID))
+	req = req.WithContext(context.WithValue(req.Context(), contextKeyUserType, thunderdome.RegisteredUserType))
+	rr := httptest.NewRecorder()
+	service.handlePokerJiraExport().ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var body struct {
+		Data []*thunderdome.JiraPointsExportResult `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+	assert.Equal(t, []*thunderdome.JiraPointsExportResult{
+		{StoryID: "story-1", ReferenceID: "TD-1", Points: "5", Success: true},
+		{StoryID: "story-2", ReferenceID: "TD-404", Points: "3", Success: false, Error: "JIRA_ISSUE_NOT_FOUND"},
+	}, body.Data)
+	mockPokerDataSvc.AssertExpectations(t)
+	mockJiraDataSvc.AssertExpectations(t)
+}
+
+func TestHandlePokerJiraExportOtherUsersInstance(t *testing.T) {
+	const instanceID = "823e4567-e89b-12d3-a456-426614174000"
+
+	mockPokerDataSvc := new(MockPokerDataSvc)
+	mockPokerDataSvc.On("ConfirmFacilitator", testGameID, testFacilitatorID).Return(nil)
+	mockJiraDataSvc := new(MockJiraDataSvc)
+	mockJiraDataSvc.On("GetInstanceByID", mock.Anything, instanceID).Return(thunderdome.JiraInstance{ID: instanceID, UserID: testParticipantID}, nil)
+	service := &Service{PokerDataSvc: mockPokerDataSvc, JiraDataSvc: mockJiraDataSvc, Logger: otelzap.New(zap.NewNop())}
+
+	req := httptest.NewRequest("POST", "/battles/"+testGameID+"/export/jira", strings.NewReader(`{"instanceId":"`+instanceID+`"}`))
+	req = mux.SetURLVars(req, map[string]string{"battleId": testGameID})
+	req = req.WithContext(context.WithValue(req.Context(), contextKeyUserID, testFacilitatorID))
+	req = req.WithContext(context.WithValue(req.Context(), contextKeyUserType, thunderdome.RegisteredUserType))
+	rr := httptest.NewRecorder()
+	service.handlePokerJiraExport().ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	mockJiraDataSvc.AssertNotCalled(t, "UpdateJiraIssuePoints", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,165 @@
+package http
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/http/poker"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+type joinDeepLinkResponse struct {
+	JoinLink string `json:"joinLink"`
+}
+
+// handleGetPokerJoinDeepLink gets the link that joins the poker game with its join code in one step
+//
+//	@Summary		Get Poker Join Deep Link
+//	@Description	Gets the link that joins the poker game with its join code in one step, for sharing from mobile devices
+//	@Param			battleId	path	string	true	"the poker game ID"
+//	@Tags			poker
+//	@Produce		json
+//	@Success		200	object	standardJsonResponse{data=joinDeepLinkResponse}
+//	@Failure		400	object	standardJsonResponse{}
+//	@Failure		403	object	standardJsonResponse{}
+//	@Failure		404	object	standardJsonResponse{}
+//	@Failure		500	object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/battles/{battleId}/join-link [get]
+func (s *Service) handleGetPokerJoinDeepLink() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		gameID := vars["battleId"]
+		idErr := validate.Var(gameID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		userType := ctx.Value(contextKeyUserType).(string)
+
+		// only users that have joined the game may share its join code
+		userErr := s.PokerDataSvc.GetUserActiveStatus(gameID, sessionUserID)
+		if userErr != nil && userErr.Error() != "DUPLICATE_BATTLE_USER" && userType != thunderdome.AdminUserType {
+			s.Failure(w, r, http.StatusForbidden, Errorf(EUNAUTHORIZED, "USER_MUST_JOIN_BATTLE"))
+			return
+		}
+
+		link, err := s.PokerDataSvc.GenerateJoinDeepLink(ctx, gameID)
+		if err != nil {
+			switch err.Error() {
+			case "BATTLE_NOT_FOUND":
+				s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, "BATTLE_NOT_FOUND"))
+			case "JOIN_CODE_NOT_SET":
+				s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "JOIN_CODE_NOT_SET"))
+			default:
+				s.Logger.Ctx(ctx).Error("handleGetPokerJoinDeepLink error", zap.Error(err),
+					zap.String("poker_id", gameID), zap.String("session_user_id", sessionUserID))
+				s.Failure(w, r, http.StatusInternalServerError, err)
+			}
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, joinDeepLinkResponse{JoinLink: s.Config.PathPrefix + link}, nil)
+	}
+}
+
+// joinDeepLinkConfirmTemplate is served for GET requests to a join deep link so following the link
+// doesn't join the game, the form posts the link back to join it
+var joinDeepLinkConfirmTemplate = template.Must(template.New("join").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><meta name="viewport" content="width=device-width, initial-scale=1"><title>Join Game</title></head>
+<body>
+<form method="post" action="{{.Action}}">
+<input type="hidden" name="game" value="{{.Game}}">
+<input type="hidden" name="code" value="{{.Code}}">
+<button type="submit">Join Game</button>
+</form>
+</body>
+</html>`))
+
+// handlePokerJoinDeepLinkConfirm serves the page that confirms joining the poker game from a join deep link
+func (s *Service) handlePokerJoinDeepLinkConfirm() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		game, code := r.URL.Query().Get("game"), r.URL.Query().Get("code")
+		if _, _, err := thunderdome.ParseJoinDeepLink(game, code); err != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, err.Error()))
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		_ = joinDeepLinkConfirmTemplate.Execute(w, map[string]string{
+			"Action": s.Config.PathPrefix + "/join",
+			"Game":   game,
+			"Code":   code,
+		})
+	}
+}
+
+// handlePokerJoinDeepLink joins the poker game from a join deep link and redirects to the game,
+// or to the mobile app when the request accepts the app's URL scheme.
+// The link's code is validated the same as the game's join flow, the observer code joins as a spectator
+func (s *Service) handlePokerJoinDeepLink() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+
+		gameID, joinCode, err := thunderdome.ParseJoinDeepLink(r.FormValue("game"), r.FormValue("code"))
+		if err != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, err.Error()))
+			return
+		}
+		idErr := validate.Var(gameID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, thunderdome.ErrInvalidJoinDeepLink.Error()))
+			return
+		}
+
+		game, err := s.PokerDataSvc.GetGameByID(gameID, sessionUserID)
+		if err != nil {
+			s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, "BATTLE_NOT_FOUND"))
+			return
+		}
+		if game.ArchivedAt != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "BATTLE_ARCHIVED"))
+			return
+		}
+
+		// links made before the join code was changed no longer grant access
+		observerCode, _ := s.PokerDataSvc.GetObserverCode(gameID)
+		authorized, spectator := poker.JoinCodeAccess(joinCode, game.JoinCode, observerCode)
+		if !authorized && game.JoinCode != "" {
+			s.Failure(w, r, http.StatusForbidden, Errorf(EUNAUTHORIZED, "JOIN_CODE_EXPIRED"))
+			return
+		}
+
+		if err := s.PokerDataSvc.JoinGame(ctx, gameID, sessionUserID); err != nil {
+			s.Logger.Ctx(ctx).Error("handlePokerJoinDeepLink error", zap.Error(err),
+				zap.String("poker_id", gameID), zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		if spectator {
+			if _, err := s.PokerDataSvc.SetObserver(gameID, sessionUserID); err != nil {
+				s.Logger.Ctx(ctx).Error("handlePokerJoinDeepLink error", zap.Error(err),
+					zap.String("poker_id", gameID), zap.String("session_user_id", sessionUserID))
+				s.Failure(w, r, http.StatusInternalServerError, err)
+				return
+			}
+		}
+
+		redirectURL := fmt.Sprintf("%s/game/%s", s.Config.PathPrefix, gameID)
+		if s.Config.MobileAppScheme != "" && strings.Contains(r.Header.Get("Accept"), s.Config.MobileAppScheme+"://") {
+			redirectURL = fmt.Sprintf("%s://game/%s", s.Config.MobileAppScheme, gameID)
+		}
+
+		http.Redirect(w, r, redirectURL, http.StatusSeeOther)
+	}
+}
@@ -0,0 +1,64 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+func (m *MockPokerDataSvc) RebuildGameState(ctx context.Context, pokerID string) (*thunderdome.Poker, error) {
+	args := m.Called(ctx, pokerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*thunderdome.Poker), args.Error(1)
+}
+
+func TestHandlePokerRebuild(t *testing.T) {
+	tests := []struct {
+		name         string
+		game         *thunderdome.Poker
+		err          error
+		expectedCode int
+	}{
+		{name: "rebuilt", game: &thunderdome.Poker{ID: testGameID, Name: "Sprint 1", ActiveStoryID: testStoryID}, expectedCode: http.StatusOK},
+		{name: "game not found", err: fmt.Errorf("BATTLE_NOT_FOUND"), expectedCode: http.StatusNotFound},
+		{name: "no events", err: thunderdome.ErrGameEventsNotFound, expectedCode: http.StatusNotFound},
+		{name: "sequence gap", err: thunderdome.ErrGameEventSequenceGap, expectedCode: http.StatusConflict},
+		{name: "query error", err: fmt.Errorf("poker rebuild query error"), expectedCode: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPokerDataSvc := new(MockPokerDataSvc)
+			mockPokerDataSvc.On("RebuildGameState", mock.Anything, testGameID).Return(tt.game, tt.err)
+			service := &Service{PokerDataSvc: mockPokerDataSvc, Logger: otelzap.New(zap.NewNop())}
+
+			req := httptest.NewRequest("POST", "/admin/battles/"+testGameID+"/rebuild", nil)
+			req = mux.SetURLVars(req, map[string]string{"battleId": testGameID})
+			req = req.WithContext(context.WithValue(req.Context(), contextKeyUserID, testFacilitatorID))
+			rr := httptest.NewRecorder()
+			service.handlePokerRebuild().ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+			if tt.game != nil {
+				var body struct {
+					Data *thunderdome.Poker `json:"data"`
+				}
+				assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+				assert.Equal(t, testStoryID, body.Data.ActiveStoryID)
+			}
+			mockPokerDataSvc.AssertExpectations(t)
+		})
+	}
+}
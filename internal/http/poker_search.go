@@ -0,0 +1,247 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// handleSearchPokerStories searches the poker game stories
+//
+//	@Summary		Search Poker Stories
+//	@Description	Full-text searches the poker game stories by name, description, acceptance criteria or all of them
+//	@Param			battleId	path	string	true	"the poker game ID"
+//	@Param			q			query	string	true	"the search query"
+//	@Param			field		query	string	false	"the field to search, one of name, description, acceptance_criteria or all (default)"
+//	@Tags			poker
+//	@Produce		json
+//	@Success		200	object	standardJsonResponse{data=[]thunderdome.Story}
+//	@Failure		400	object	standardJsonResponse{}
+//	@Failure		403	object	standardJsonResponse{}
+//	@Failure		404	object	standardJsonResponse{}
+//	@Failure		500	object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/battles/{battleId}/plans/search [get]
+func (s *Service) handleSearchPokerStories() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		gameID := vars["battleId"]
+		idErr := validate.Var(gameID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		userType := ctx.Value(contextKeyUserType).(string)
+
+		query := r.URL.Query().Get("q")
+		queryErr := validate.Var(query, "required,max=256")
+		if queryErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, queryErr.Error()))
+			return
+		}
+		field, fieldErr := thunderdome.ParseStorySearchField(r.URL.Query().Get("field"))
+		if fieldErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, fieldErr.Error()))
+			return
+		}
+
+		game, err := s.PokerDataSvc.GetGameByID(gameID, sessionUserID)
+		if err != nil {
+			s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, "BATTLE_NOT_FOUND"))
+			return
+		}
+
+		// don't allow searching the stories if battle has JoinCode and user hasn't joined yet
+		if game.JoinCode != "" {
+			userErr := s.PokerDataSvc.GetUserActiveStatus(gameID, sessionUserID)
+			if userErr != nil && userErr.Error() != "DUPLICATE_BATTLE_USER" && userType != thunderdome.AdminUserType {
+				s.Failure(w, r, http.StatusForbidden, Errorf(EUNAUTHORIZED, "USER_MUST_JOIN_BATTLE"))
+				return
+			}
+		}
+
+		stories, err := s.PokerDataSvc.SearchStories(ctx, gameID, sessionUserID, query, field)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleSearchPokerStories error", zap.Error(err),
+				zap.String("poker_id", gameID), zap.String("session_user_id", sessionUserID),
+				zap.String("search_field", string(field)))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, stories, nil)
+	}
+}
+
+// handleGetPokerStoryDuplicates gets the groups of poker game stories with similar names
+//
+//	@Summary		Get Poker Story Duplicates
+//	@Description	Groups the poker game stories whose names have a trigram similarity at or above the threshold,
+//	@Description	most similar group first, so the facilitator can delete or merge near duplicate imports
+//	@Param			battleId	path	string	true	"the poker game ID"
+//	@Param			threshold	query	number	false	"the name similarity above 0 and at most 1 (default 0.8)"
+//	@Tags			poker
+//	@Produce		json
+//	@Success		200	object	standardJsonResponse{data=[]thunderdome.DuplicateGroup}
+//	@Failure		400	object	standardJsonResponse{}
+//	@Failure		403	object	standardJsonResponse{}
+//	@Failure		404	object	standardJsonResponse{}
+//	@Failure		500	object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/battles/{battleId}/plans/duplicates [get]
+func (s *Service) handleGetPokerStoryDuplicates() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		gameID := vars["battleId"]
+		idErr := validate.Var(gameID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		userType := ctx.Value(contextKeyUserType).(string)
+
+		threshold, thresholdErr := thunderdome.ParseDuplicateThreshold(r.URL.Query().Get("threshold"))
+		if thresholdErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, thresholdErr.Error()))
+			return
+		}
+
+		game, err := s.PokerDataSvc.GetGameByID(gameID, sessionUserID)
+		if err != nil {
+			s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, "BATTLE_NOT_FOUND"))
+			return
+		}
+
+		// don't allow getting the duplicates if battle has JoinCode and user hasn't joined yet
+		if game.JoinCode != "" {
+			userErr := s.PokerDataSvc.GetUserActiveStatus(gameID, sessionUserID)
+			if userErr != nil && userErr.Error() != "DUPLICATE_BATTLE_USER" && userType != thunderdome.AdminUserType {
+				s.Failure(w, r, http.StatusForbidden, Errorf(EUNAUTHORIZED, "USER_MUST_JOIN_BATTLE"))
+				return
+			}
+		}
+
+		duplicates, err := s.PokerDataSvc.DetectDuplicateStories(ctx, gameID, threshold)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleGetPokerStoryDuplicates error", zap.Error(err),
+				zap.String("poker_id", gameID), zap.String("session_user_id", sessionUserID),
+				zap.Float64("threshold", threshold))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, duplicates, nil)
+	}
+}
+
+// handleGetTeamStoriesByReferenceID gets the team's poker stories with the reference ID across all of its games
+//
+//	@Summary		Get Team Stories By Reference ID
+//	@Description	Gets the estimation history of a story reference ID (e.g. a Jira issue key) across the team's poker games, newest game first
+//	@Param			teamId			path	string	true	"the team ID"
+//	@Param			reference_id	query	string	true	"the story reference ID"
+//	@Tags			team
+//	@Produce		json
+//	@Success		200	object	standardJsonResponse{data=[]thunderdome.StoryWithGame}
+//	@Failure		400	object	standardJsonResponse{}
+//	@Failure		403	object	standardJsonResponse{}
+//	@Failure		500	object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/teams/{teamId}/stories [get]
+func (s *Service) handleGetTeamStoriesByReferenceID() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		vars := mux.Vars(r)
+		teamID := vars["teamId"]
+		idErr := validate.Var(teamID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		referenceID := r.URL.Query().Get("reference_id")
+		referenceErr := validate.Var(referenceID, "required,max=128")
+		if referenceErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, referenceErr.Error()))
+			return
+		}
+
+		stories, err := s.PokerDataSvc.GetStoriesByReferenceID(ctx, teamID, referenceID)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleGetTeamStoriesByReferenceID error", zap.Error(err),
+				zap.String("team_id", teamID), zap.String("session_user_id", sessionUserID),
+				zap.String("reference_id", referenceID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, stories, nil)
+	}
+}
+
+// handleGetTeamStoryTypeReport gets the team's story type points comparison across its poker games
+//
+//	@Summary		Get Team Story Type Report
+//	@Description	Compares the final points of the team's stories of a type across its poker games with the average, median,
+//	@Description	standard deviation and distribution of points, covers all of the team's games when no since date is given
+//	@Param			teamId	path	string	true	"the team ID"
+//	@Param			type	query	string	true	"the story type, e.g. story or bug"
+//	@Param			since	query	string	false	"the earliest game creation date (YYYY-MM-DD)"
+//	@Tags			team
+//	@Produce		json
+//	@Success		200	object	standardJsonResponse{data=thunderdome.StoryTypeReport}
+//	@Failure		400	object	standardJsonResponse{}
+//	@Failure		403	object	standardJsonResponse{}
+//	@Failure		500	object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/teams/{teamId}/story-type-report [get]
+func (s *Service) handleGetTeamStoryTypeReport() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		vars := mux.Vars(r)
+		teamID := vars["teamId"]
+		idErr := validate.Var(teamID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		storyType := r.URL.Query().Get("type")
+		typeErr := validate.Var(storyType, "required,max=64")
+		if typeErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, typeErr.Error()))
+			return
+		}
+
+		var since time.Time
+		if v := r.URL.Query().Get("since"); v != "" {
+			d, err := time.Parse(time.DateOnly, v)
+			if err != nil {
+				s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "INVALID_DATE"))
+				return
+			}
+			since = d
+		}
+
+		report, err := s.PokerDataSvc.CrossGameStoryTypeComparison(ctx, teamID, storyType, since)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleGetTeamStoryTypeReport error", zap.Error(err),
+				zap.String("team_id", teamID), zap.String("session_user_id", sessionUserID),
+				zap.String("story_type", storyType))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, report, nil)
+	}
+}
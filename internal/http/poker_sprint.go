@@ -0,0 +1,139 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/http/poker"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+type storiesSprintRequestBody struct {
+	thunderdome.StorySprint
+	StoryIDs []string `json:"storyIds" validate:"required,min=1,dive,uuid"`
+}
+
+// handlePokerStoriesSprintAssign handles assigning poker stories to a sprint
+//
+//	@Summary		Assign Poker Stories to Sprint
+//	@Description	Assigns the poker stories to the sprint, an empty sprint name removes the stories from their sprint
+//	@Param			battleId	path	string						true	"the poker game ID"
+//	@Param			sprint		body	storiesSprintRequestBody	true	"the sprint and stories to assign"
+//	@Tags			poker
+//	@Produce		json
+//	@Success		200	object	standardJsonResponse{}
+//	@Failure		400	object	standardJsonResponse{}
+//	@Failure		403	object	standardJsonResponse{}
+//	@Failure		404	object	standardJsonResponse{}
+//	@Failure		500	object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/battles/{battleId}/plans/sprint [put]
+func (s *Service) handlePokerStoriesSprintAssign(pokerSvc *poker.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		gameID := vars["battleId"]
+		idErr := validate.Var(gameID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+
+		body, bodyErr := io.ReadAll(r.Body)
+		if bodyErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, bodyErr.Error()))
+			return
+		}
+
+		var req = storiesSprintRequestBody{}
+		jsonErr := json.Unmarshal(body, &req)
+		if jsonErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, jsonErr.Error()))
+			return
+		}
+
+		inputErr := validate.Struct(req)
+		if inputErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, inputErr.Error()))
+			return
+		}
+		if req.StartDate != nil && req.EndDate != nil && req.EndDate.Before(*req.StartDate) {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "INVALID_DATE"))
+			return
+		}
+
+		err := pokerSvc.AssignStoriesToSprint(ctx, gameID, sessionUserID, req.StorySprint, req.StoryIDs)
+		if err != nil {
+			switch err.Error() {
+			case "REQUIRES_FACILITATOR":
+				s.Failure(w, r, http.StatusForbidden, Errorf(EUNAUTHORIZED, err.Error()))
+			case "STORY_NOT_FOUND":
+				s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, err.Error()))
+			default:
+				s.Logger.Ctx(ctx).Error("handlePokerStoriesSprintAssign error", zap.Error(err),
+					zap.String("poker_id", gameID), zap.String("session_user_id", sessionUserID),
+					zap.Int("story_count", len(req.StoryIDs)))
+				s.Failure(w, r, http.StatusInternalServerError, err)
+			}
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, nil, nil)
+	}
+}
+
+// handleGetPokerStories gets the poker game stories, optionally only those in a sprint
+//
+//	@Summary		Get Poker Stories
+//	@Description	Get the poker game stories, the sprint query only returns the stories assigned to that sprint
+//	@Param			battleId	path	string	true	"the poker game ID"
+//	@Param			sprint		query	string	false	"the sprint name to filter by"
+//	@Tags			poker
+//	@Produce		json
+//	@Success		200	object	standardJsonResponse{data=[]thunderdome.Story}
+//	@Failure		400	object	standardJsonResponse{}
+//	@Failure		403	object	standardJsonResponse{}
+//	@Failure		404	object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/battles/{battleId}/plans [get]
+func (s *Service) handleGetPokerStories() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		gameID := vars["battleId"]
+		idErr := validate.Var(gameID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		userType := ctx.Value(contextKeyUserType).(string)
+
+		game, err := s.PokerDataSvc.GetGameByID(gameID, sessionUserID)
+		if err != nil {
+			s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, "BATTLE_NOT_FOUND"))
+			return
+		}
+
+		// don't allow getting the stories if battle has JoinCode and user hasn't joined yet
+		if game.JoinCode != "" {
+			userErr := s.PokerDataSvc.GetUserActiveStatus(gameID, sessionUserID)
+			if userErr != nil && userErr.Error() != "DUPLICATE_BATTLE_USER" && userType != thunderdome.AdminUserType {
+				s.Failure(w, r, http.StatusForbidden, Errorf(EUNAUTHORIZED, "USER_MUST_JOIN_BATTLE"))
+				return
+			}
+		}
+
+		stories := game.Stories
+		if sprint, ok := r.URL.Query()["sprint"]; ok {
+			stories = thunderdome.FilterStoriesBySprint(stories, sprint[0])
+		}
+
+		s.Success(w, r, http.StatusOK, stories, nil)
+	}
+}
@@ -0,0 +1,90 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/http/poker"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+type storiesStatusRequestBody struct {
+	Status   string   `json:"status" validate:"required,oneof=pending estimated ready in-sprint"`
+	StoryIDs []string `json:"storyIds" validate:"required,min=1,dive,uuid"`
+}
+
+// handlePokerStoriesStatusUpdate handles moving poker stories to a status
+//
+//	@Summary		Update Poker Stories Status
+//	@Description	Moves the poker stories to the status (pending, estimated, ready or in-sprint), stories move one step
+//	@Description	at a time and must have final points to be estimated, none of the stories are updated when any can't be moved
+//	@Param			battleId	path	string						true	"the poker game ID"
+//	@Param			status		body	storiesStatusRequestBody	true	"the status and stories to update"
+//	@Tags			poker
+//	@Produce		json
+//	@Success		200	object	standardJsonResponse{}
+//	@Failure		400	object	standardJsonResponse{}
+//	@Failure		403	object	standardJsonResponse{}
+//	@Failure		404	object	standardJsonResponse{}
+//	@Failure		500	object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/battles/{battleId}/plans/status [put]
+func (s *Service) handlePokerStoriesStatusUpdate(pokerSvc *poker.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		gameID := vars["battleId"]
+		idErr := validate.Var(gameID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+
+		body, bodyErr := io.ReadAll(r.Body)
+		if bodyErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, bodyErr.Error()))
+			return
+		}
+
+		var req = storiesStatusRequestBody{}
+		jsonErr := json.Unmarshal(body, &req)
+		if jsonErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, jsonErr.Error()))
+			return
+		}
+
+		inputErr := validate.Struct(req)
+		if inputErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, inputErr.Error()))
+			return
+		}
+
+		err := pokerSvc.UpdateStoryStatus(ctx, gameID, sessionUserID, req.Status, req.StoryIDs)
+		if err != nil {
+			switch {
+			case errors.Is(err, thunderdome.ErrInvalidStoryStatus),
+				errors.Is(err, thunderdome.ErrInvalidStoryStatusTransition),
+				errors.Is(err, thunderdome.ErrStoryNotEstimated):
+				s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, err.Error()))
+			case err.Error() == "REQUIRES_FACILITATOR":
+				s.Failure(w, r, http.StatusForbidden, Errorf(EUNAUTHORIZED, err.Error()))
+			case err.Error() == "STORY_NOT_FOUND":
+				s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, err.Error()))
+			default:
+				s.Logger.Ctx(ctx).Error("handlePokerStoriesStatusUpdate error", zap.Error(err),
+					zap.String("poker_id", gameID), zap.String("session_user_id", sessionUserID),
+					zap.String("status", req.Status), zap.Int("story_count", len(req.StoryIDs)))
+				s.Failure(w, r, http.StatusInternalServerError, err)
+			}
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, nil, nil)
+	}
+}
@@ -0,0 +1,827 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/http/poker"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+// MockPokerDataSvc is a mock implementation of the PokerDataSvc, methods not overridden panic when called
+type MockPokerDataSvc struct {
+	mock.Mock
+	PokerDataSvc
+}
+
+// SubscribeTimeBoxEvents has nothing to subscribe to, like running without redis
+func (m *MockPokerDataSvc) SubscribeTimeBoxEvents(ctx context.Context) <-chan thunderdome.PokerTimeBoxEvent {
+	return nil
+}
+
+func (m *MockPokerDataSvc) GetGameByID(pokerID string, userID string) (*thunderdome.Poker, error) {
+	args := m.Called(pokerID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*thunderdome.Poker), args.Error(1)
+}
+
+func (m *MockPokerDataSvc) SetFacilitatorNote(ctx context.Context, storyID string, facilitatorID string, notes string) error {
+	args := m.Called(ctx, storyID, facilitatorID, notes)
+	return args.Error(0)
+}
+
+func (m *MockPokerDataSvc) ConfirmFacilitator(pokerID string, userID string) error {
+	args := m.Called(pokerID, userID)
+	return args.Error(0)
+}
+
+func (m *MockPokerDataSvc) GetStories(pokerID string, userID string) []*thunderdome.Story {
+	args := m.Called(pokerID, userID)
+	return args.Get(0).([]*thunderdome.Story)
+}
+
+func (m *MockPokerDataSvc) BulkUpdateStoryPriority(ctx context.Context, pokerID string, updates []thunderdome.StoryPriorityUpdate) error {
+	args := m.Called(ctx, pokerID, updates)
+	return args.Error(0)
+}
+
+func (m *MockPokerDataSvc) BulkUpdateStorySprint(ctx context.Context, pokerID string, sprint thunderdome.StorySprint, storyIDs []string) error {
+	args := m.Called(ctx, pokerID, sprint, storyIDs)
+	return args.Error(0)
+}
+
+func (m *MockPokerDataSvc) BulkUpdateStoryStatus(ctx context.Context, pokerID string, status string, storyIDs []string) error {
+	args := m.Called(ctx, pokerID, status, storyIDs)
+	return args.Error(0)
+}
+
+func (m *MockPokerDataSvc) ListComments(ctx context.Context, storyID string) ([]thunderdome.PokerStoryComment, error) {
+	args := m.Called(ctx, storyID)
+	return args.Get(0).([]thunderdome.PokerStoryComment), args.Error(1)
+}
+
+func (m *MockPokerDataSvc) ExportParticipants(ctx context.Context, pokerID string, requestingUserID string) ([]byte, error) {
+	args := m.Called(ctx, pokerID, requestingUserID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func (m *MockPokerDataSvc) GetAccessLog(ctx context.Context, pokerID string, eventType string, limit int, offset int) ([]*thunderdome.PokerAccessLog, int, error) {
+	args := m.Called(ctx, pokerID, eventType, limit, offset)
+	return args.Get(0).([]*thunderdome.PokerAccessLog), args.Int(1), args.Error(2)
+}
+
+func (m *MockPokerDataSvc) SearchStories(ctx context.Context, pokerID string, userID string, query string, field thunderdome.StorySearchField) ([]*thunderdome.Story, error) {
+	args := m.Called(ctx, pokerID, userID, query, field)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*thunderdome.Story), args.Error(1)
+}
+
+func (m *MockPokerDataSvc) DetectDuplicateStories(ctx context.Context, pokerID string, threshold float64) ([]thunderdome.DuplicateGroup, error) {
+	args := m.Called(ctx, pokerID, threshold)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]thunderdome.DuplicateGroup), args.Error(1)
+}
+
+func (m *MockPokerDataSvc) GetStoriesByReferenceID(ctx context.Context, teamID string, referenceID string) ([]*thunderdome.StoryWithGame, error) {
+	args := m.Called(ctx, teamID, referenceID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*thunderdome.StoryWithGame), args.Error(1)
+}
+
+func (m *MockPokerDataSvc) CrossGameStoryTypeComparison(ctx context.Context, teamID string, storyType string, since time.Time) (*thunderdome.StoryTypeReport, error) {
+	args := m.Called(ctx, teamID, storyType, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*thunderdome.StoryTypeReport), args.Error(1)
+}
+
+func (m *MockPokerDataSvc) JoinGame(ctx context.Context, pokerID string, userID string) error {
+	args := m.Called(ctx, pokerID, userID)
+	return args.Error(0)
+}
+
+func (m *MockPokerDataSvc) GetObserverCode(pokerID string) (string, error) {
+	args := m.Called(pokerID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockPokerDataSvc) SetObserver(pokerID string, userID string) ([]*thunderdome.PokerUser, error) {
+	args := m.Called(pokerID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*thunderdome.PokerUser), args.Error(1)
+}
+
+const (
+	testGameID        = "523e4567-e89b-12d3-a456-426614174000"
+	testStoryID       = "623e4567-e89b-12d3-a456-426614174000"
+	testOtherStoryID  = "b23e4567-e89b-12d3-a456-426614174000"
+	testFacilitatorID = "723e4567-e89b-12d3-a456-426614174000"
+	testParticipantID = "823e4567-e89b-12d3-a456-426614174000"
+)
+
+func (m *MockPokerDataSvc) GetEstimationScale(ctx context.Context, scaleID string) (*thunderdome.EstimationScale, error) {
+	args := m.Called(ctx, scaleID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*thunderdome.EstimationScale), args.Error(1)
+}
+
+func (m *MockPokerDataSvc) GetDefaultPublicEstimationScale(ctx context.Context) (*thunderdome.EstimationScale, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*thunderdome.EstimationScale), args.Error(1)
+}
+
+func TestHandleGetPokerGameFacilitatorNotes(t *testing.T) {
+	tests := []struct {
+		name          string
+		userID        string
+		userType      string
+		expectedNotes any
+	}{
+		{name: "participant", userID: testParticipantID, userType: thunderdome.RegisteredUserType, expectedNotes: nil},
+		{name: "facilitator", userID: testFacilitatorID, userType: thunderdome.RegisteredUserType, expectedNotes: "discussed splitting"},
+		{name: "admin", userID: testParticipantID, userType: thunderdome.AdminUserType, expectedNotes: "discussed splitting"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notes := "discussed splitting"
+			mockPokerDataSvc := new(MockPokerDataSvc)
+			mockPokerDataSvc.On("GetGameByID", testGameID, tt.userID).Return(&thunderdome.Poker{
+				ID:           testGameID,
+				Facilitators: []string{testFacilitatorID},
+				Stories:      []*thunderdome.Story{{ID: testStoryID, Name: "story", FacilitatorNotes: &notes}},
+			}, nil)
+			service := &Service{PokerDataSvc: mockPokerDataSvc}
+
+			req := httptest.NewRequest("GET", "/battles/"+testGameID, nil)
+			req = mux.SetURLVars(req, map[string]string{"battleId": testGameID})
+			req = req.WithContext(context.WithValue(req.Context(), contextKeyUserID, tt.userID))
+			req = req.WithContext(context.WithValue(req.Context(), contextKeyUserType, tt.userType))
+			rr := httptest.NewRecorder()
+			service.handleGetPokerGame().ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+
+			var response struct {
+				Data struct {
+					Plans []map[string]any `json:"plans"`
+				} `json:"data"`
+			}
+			err := json.Unmarshal(rr.Body.Bytes(), &response)
+			assert.NoError(t, err)
+			if assert.Len(t, response.Data.Plans, 1) {
+				storyNotes, ok := response.Data.Plans[0]["facilitatorNotes"]
+				assert.True(t, ok, "expected facilitatorNotes to be present")
+				assert.Equal(t, tt.expectedNotes, storyNotes)
+			}
+			mockPokerDataSvc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandlePokerStoryFacilitatorNotesUpdate(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		setupErr       error
+		expectedStatus int
+		expectCall     bool
+	}{
+		{name: "facilitator", body: `{"notes":"discussed splitting"}`, expectedStatus: http.StatusOK, expectCall: true},
+		{name: "non facilitator", body: `{"notes":"discussed splitting"}`, setupErr: errors.New("REQUIRES_FACILITATOR"), expectedStatus: http.StatusForbidden, expectCall: true},
+		{name: "story not found", body: `{"notes":"discussed splitting"}`, setupErr: errors.New("STORY_NOT_FOUND"), expectedStatus: http.StatusNotFound, expectCall: true},
+		{name: "notes too long", body: `{"notes":"` + strings.Repeat("a", 10001) + `"}`, expectedStatus: http.StatusBadRequest, expectCall: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPokerDataSvc := new(MockPokerDataSvc)
+			if tt.expectCall {
+				mockPokerDataSvc.On("SetFacilitatorNote", mock.Anything, testStoryID, testFacilitatorID, "discussed splitting").Return(tt.setupErr)
+			}
+			service := &Service{
+				PokerDataSvc: mockPokerDataSvc,
+				Logger:       otelzap.New(zap.NewNop()),
+			}
+
+			req := httptest.NewRequest("PUT", "/stories/"+testStoryID+"/facilitator-notes", strings.NewReader(tt.body))
+			req = mux.SetURLVars(req, map[string]string{"storyId": testStoryID})
+			req = req.WithContext(context.WithValue(req.Context(), contextKeyUserID, testFacilitatorID))
+			rr := httptest.NewRecorder()
+			service.handlePokerStoryFacilitatorNotesUpdate().ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			mockPokerDataSvc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandlePokerStoriesPriorityUpdatePartialFailure(t *testing.T) {
+	const unknownStoryID = "a23e4567-e89b-12d3-a456-426614174000"
+	stories := []*thunderdome.Story{
+		{ID: testStoryID, Priority: 99},
+		{ID: testOtherStoryID, Priority: 99},
+	}
+
+	mockPokerDataSvc := new(MockPokerDataSvc)
+	mockPokerDataSvc.On("ConfirmFacilitator", testGameID, testFacilitatorID).Return(nil)
+	mockPokerDataSvc.On("GetStories", testGameID, "").Return(stories)
+	mockPokerDataSvc.On("BulkUpdateStoryPriority", mock.Anything, testGameID, []thunderdome.StoryPriorityUpdate{
+		{StoryID: testStoryID, Priority: 1},
+		{StoryID: testOtherStoryID, Priority: 3},
+	}).Run(func(args mock.Arguments) {
+		for _, update := range args.Get(2).([]thunderdome.StoryPriorityUpdate) {
+			for _, story := range stories {
+				if story.ID == update.StoryID {
+					story.Priority = update.Priority
+				}
+			}
+		}
+	}).Return(nil)
+	service := &Service{
+		PokerDataSvc: mockPokerDataSvc,
+		Logger:       otelzap.New(zap.NewNop()),
+	}
+	pokerSvc := poker.New(poker.Config{}, service.Logger, nil, nil, nil, nil, mockPokerDataSvc)
+
+	body := `{"stories":[` +
+		`{"storyId":"` + testStoryID + `","priority":1},` +
+		`{"storyId":"` + unknownStoryID + `","priority":2},` +
+		`{"storyId":"` + testOtherStoryID + `","priority":3}]}`
+	req := httptest.NewRequest("PUT", "/battles/"+testGameID+"/plans/priority", strings.NewReader(body))
+	req = mux.SetURLVars(req, map[string]string{"battleId": testGameID})
+	req = req.WithContext(context.WithValue(req.Context(), contextKeyUserID, testFacilitatorID))
+	rr := httptest.NewRecorder()
+	service.handlePokerStoriesPriorityUpdate(pokerSvc).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockPokerDataSvc.AssertExpectations(t)
+
+	var response struct {
+		Data []thunderdome.StoryPriorityResult `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, []thunderdome.StoryPriorityResult{
+		{StoryID: testStoryID, Success: true},
+		{StoryID: unknownStoryID, Error: "STORY_NOT_FOUND"},
+		{StoryID: testOtherStoryID, Success: true},
+	}, response.Data)
+
+	assert.Equal(t, int32(1), stories[0].Priority)
+	assert.Equal(t, int32(3), stories[1].Priority)
+}
+
+func TestHandlePokerStoriesStatusUpdate(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		updateErr      error
+		expectedStatus int
+		expectCall     bool
+	}{
+		{name: "valid transition", body: `{"status":"ready","storyIds":["` + testStoryID + `"]}`, expectedStatus: http.StatusOK, expectCall: true},
+		{name: "invalid transition", body: `{"status":"in-sprint","storyIds":["` + testStoryID + `"]}`, updateErr: thunderdome.ErrInvalidStoryStatusTransition, expectedStatus: http.StatusBadRequest, expectCall: true},
+		{name: "story not estimated", body: `{"status":"estimated","storyIds":["` + testStoryID + `"]}`, updateErr: thunderdome.ErrStoryNotEstimated, expectedStatus: http.StatusBadRequest, expectCall: true},
+		{name: "story not in game", body: `{"status":"ready","storyIds":["` + testStoryID + `"]}`, updateErr: errors.New("STORY_NOT_FOUND"), expectedStatus: http.StatusNotFound, expectCall: true},
+		{name: "unknown status", body: `{"status":"done","storyIds":["` + testStoryID + `"]}`, expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPokerDataSvc := new(MockPokerDataSvc)
+			mockPokerDataSvc.On("ConfirmFacilitator", testGameID, testFacilitatorID).Return(nil).Maybe()
+			if tt.expectCall {
+				mockPokerDataSvc.On("BulkUpdateStoryStatus", mock.Anything, testGameID, mock.Anything, []string{testStoryID}).Return(tt.updateErr)
+			}
+			service := &Service{
+				PokerDataSvc: mockPokerDataSvc,
+				Logger:       otelzap.New(zap.NewNop()),
+			}
+			pokerSvc := poker.New(poker.Config{}, service.Logger, nil, nil, nil, nil, mockPokerDataSvc)
+
+			req := httptest.NewRequest("PUT", "/battles/"+testGameID+"/plans/status", strings.NewReader(tt.body))
+			req = mux.SetURLVars(req, map[string]string{"battleId": testGameID})
+			req = req.WithContext(context.WithValue(req.Context(), contextKeyUserID, testFacilitatorID))
+			rr := httptest.NewRecorder()
+			service.handlePokerStoriesStatusUpdate(pokerSvc).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			mockPokerDataSvc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandlePokerStoriesSprintAssignAndFilter(t *testing.T) {
+	stories := []*thunderdome.Story{
+		{ID: testStoryID},
+		{ID: testOtherStoryID},
+		{ID: "c23e4567-e89b-12d3-a456-426614174000", SprintName: "Sprint 11"},
+	}
+
+	mockPokerDataSvc := new(MockPokerDataSvc)
+	mockPokerDataSvc.On("ConfirmFacilitator", testGameID, testFacilitatorID).Return(nil)
+	mockPokerDataSvc.On("BulkUpdateStorySprint", mock.Anything, testGameID, thunderdome.StorySprint{Name: "Sprint 12"}, []string{testStoryID}).
+		Run(func(args mock.Arguments) {
+			stories[0].SprintName = args.Get(2).(thunderdome.StorySprint).Name
+		}).Return(nil)
+	mockPokerDataSvc.On("GetGameByID", testGameID, testParticipantID).Return(&thunderdome.Poker{ID: testGameID, Stories: stories}, nil)
+	service := &Service{
+		PokerDataSvc: mockPokerDataSvc,
+		Logger:       otelzap.New(zap.NewNop()),
+	}
+	pokerSvc := poker.New(poker.Config{}, service.Logger, nil, nil, nil, nil, mockPokerDataSvc)
+
+	body := `{"sprintName":"Sprint 12","storyIds":["` + testStoryID + `"]}`
+	req := httptest.NewRequest("PUT", "/battles/"+testGameID+"/plans/sprint", strings.NewReader(body))
+	req = mux.SetURLVars(req, map[string]string{"battleId": testGameID})
+	req = req.WithContext(context.WithValue(req.Context(), contextKeyUserID, testFacilitatorID))
+	rr := httptest.NewRecorder()
+	service.handlePokerStoriesSprintAssign(pokerSvc).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	req = httptest.NewRequest("GET", "/battles/"+testGameID+"/plans?sprint=Sprint+12", nil)
+	req = mux.SetURLVars(req, map[string]string{"battleId": testGameID})
+	ctx := context.WithValue(req.Context(), contextKeyUserID, testParticipantID)
+	req = req.WithContext(context.WithValue(ctx, contextKeyUserType, thunderdome.RegisteredUserType))
+	rr = httptest.NewRecorder()
+	service.handleGetPokerStories().ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockPokerDataSvc.AssertExpectations(t)
+
+	var response struct {
+		Data []thunderdome.Story `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Len(t, response.Data, 1)
+	assert.Equal(t, testStoryID, response.Data[0].ID)
+}
+
+func TestHandleGetPokerAccessLog(t *testing.T) {
+	accessLog := []*thunderdome.PokerAccessLog{
+		{ID: "3", PokerID: testGameID, UserID: testParticipantID, EventType: thunderdome.PokerAccessEventLeave},
+		{ID: "2", PokerID: testGameID, UserID: testParticipantID, EventType: thunderdome.PokerAccessEventJoin},
+		{ID: "1", PokerID: testGameID, UserID: testFacilitatorID, EventType: thunderdome.PokerAccessEventJoin},
+	}
+
+	tests := []struct {
+		name           string
+		userID         string
+		userType       string
+		query          string
+		expectedFilter string
+		expectedStatus int
+		expectedIDs    []string
+	}{
+		{name: "facilitator", userID: testFacilitatorID, userType: thunderdome.RegisteredUserType, expectedStatus: http.StatusOK, expectedIDs: []string{"3", "2", "1"}},
+		{name: "facilitator join events", userID: testFacilitatorID, userType: thunderdome.RegisteredUserType, query: "?event_type=join", expectedFilter: thunderdome.PokerAccessEventJoin, expectedStatus: http.StatusOK, expectedIDs: []string{"2", "1"}},
+		{name: "facilitator leave events", userID: testFacilitatorID, userType: thunderdome.RegisteredUserType, query: "?event_type=leave", expectedFilter: thunderdome.PokerAccessEventLeave, expectedStatus: http.StatusOK, expectedIDs: []string{"3"}},
+		{name: "admin", userID: testParticipantID, userType: thunderdome.AdminUserType, query: "?event_type=join", expectedFilter: thunderdome.PokerAccessEventJoin, expectedStatus: http.StatusOK, expectedIDs: []string{"2", "1"}},
+		{name: "participant", userID: testParticipantID, userType: thunderdome.RegisteredUserType, expectedStatus: http.StatusForbidden},
+		{name: "invalid event type", userID: testFacilitatorID, userType: thunderdome.RegisteredUserType, query: "?event_type=vote", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPokerDataSvc := new(MockPokerDataSvc)
+			if tt.expectedStatus != http.StatusBadRequest {
+				mockPokerDataSvc.On("GetGameByID", testGameID, tt.userID).Return(&thunderdome.Poker{
+					ID:           testGameID,
+					Facilitators: []string{testFacilitatorID},
+				}, nil)
+			}
+			if tt.expectedStatus == http.StatusOK {
+				filtered := make([]*thunderdome.PokerAccessLog, 0)
+				for _, entry := range accessLog {
+					if tt.expectedFilter == "" || entry.EventType == tt.expectedFilter {
+						filtered = append(filtered, entry)
+					}
+				}
+				mockPokerDataSvc.On("GetAccessLog", mock.Anything, testGameID, tt.expectedFilter, 20, 0).
+					Return(filtered, len(filtered), nil)
+			}
+			service := &Service{
+				PokerDataSvc: mockPokerDataSvc,
+				Logger:       otelzap.New(zap.NewNop()),
+			}
+
+			req := httptest.NewRequest("GET", "/battles/"+testGameID+"/access-log"+tt.query, nil)
+			req = mux.SetURLVars(req, map[string]string{"battleId": testGameID})
+			req = req.WithContext(context.WithValue(req.Context(), contextKeyUserID, tt.userID))
+			req = req.WithContext(context.WithValue(req.Context(), contextKeyUserType, tt.userType))
+			rr := httptest.NewRecorder()
+			service.handleGetPokerAccessLog().ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			mockPokerDataSvc.AssertExpectations(t)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var response struct {
+				Data []thunderdome.PokerAccessLog `json:"data"`
+				Meta struct {
+					Count int `json:"count"`
+				} `json:"meta"`
+			}
+			assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+			ids := make([]string, 0, len(response.Data))
+			for _, entry := range response.Data {
+				ids = append(ids, entry.ID)
+			}
+			assert.Equal(t, tt.expectedIDs, ids)
+			assert.Equal(t, len(tt.expectedIDs), response.Meta.Count)
+		})
+	}
+}
+
+func joinDeepLinkRequest(service *Service, link string, accept string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", link, nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	req = req.WithContext(context.WithValue(req.Context(), contextKeyUserID, testParticipantID))
+	rr := httptest.NewRecorder()
+	service.handlePokerJoinDeepLink().ServeHTTP(rr, req)
+
+	return rr
+}
+
+func TestHandlePokerJoinDeepLink(t *testing.T) {
+	mockPokerDataSvc := new(MockPokerDataSvc)
+	mockPokerDataSvc.On("GetGameByID", testGameID, testParticipantID).Return(&thunderdome.Poker{ID: testGameID, JoinCode: "current-code"}, nil)
+	mockPokerDataSvc.On("GetObserverCode", testGameID).Return("observer-code", nil)
+	mockPokerDataSvc.On("JoinGame", mock.Anything, testGameID, testParticipantID).Return(nil).Twice()
+	service := &Service{
+		Config:       &Config{PathPrefix: "/thunderdome", MobileAppScheme: "thunderdome"},
+		PokerDataSvc: mockPokerDataSvc,
+		Logger:       otelzap.New(zap.NewNop()),
+	}
+
+	rr := joinDeepLinkRequest(service, thunderdome.JoinDeepLink(testGameID, "current-code"), "text/html")
+	assert.Equal(t, http.StatusSeeOther, rr.Code)
+	assert.Equal(t, "/thunderdome/game/"+testGameID, rr.Header().Get("Location"))
+
+	rr = joinDeepLinkRequest(service, thunderdome.JoinDeepLink(testGameID, "current-code"), "thunderdome://, text/html")
+	assert.Equal(t, http.StatusSeeOther, rr.Code)
+	assert.Equal(t, "thunderdome://game/"+testGameID, rr.Header().Get("Location"))
+
+	mockPokerDataSvc.AssertExpectations(t)
+	mockPokerDataSvc.AssertNotCalled(t, "SetObserver", mock.Anything, mock.Anything)
+}
+
+// TestHandlePokerJoinDeepLinkObserverCode makes sure the observer code joins the game as a spectator
+func TestHandlePokerJoinDeepLinkObserverCode(t *testing.T) {
+	mockPokerDataSvc := new(MockPokerDataSvc)
+	mockPokerDataSvc.On("GetGameByID", testGameID, testParticipantID).Return(&thunderdome.Poker{ID: testGameID, JoinCode: "current-code"}, nil)
+	mockPokerDataSvc.On("GetObserverCode", testGameID).Return("observer-code", nil)
+	mockPokerDataSvc.On("JoinGame", mock.Anything, testGameID, testParticipantID).Return(nil).Once()
+	mockPokerDataSvc.On("SetObserver", testGameID, testParticipantID).Return([]*thunderdome.PokerUser{}, nil).Once()
+	service := &Service{
+		Config:       &Config{},
+		PokerDataSvc: mockPokerDataSvc,
+		Logger:       otelzap.New(zap.NewNop()),
+	}
+
+	rr := joinDeepLinkRequest(service, thunderdome.JoinDeepLink(testGameID, "observer-code"), "")
+	assert.Equal(t, http.StatusSeeOther, rr.Code)
+	mockPokerDataSvc.AssertExpectations(t)
+}
+
+func TestHandlePokerJoinDeepLinkExpiredCode(t *testing.T) {
+	mockPokerDataSvc := new(MockPokerDataSvc)
+	mockPokerDataSvc.On("GetGameByID", testGameID, testParticipantID).Return(&thunderdome.Poker{ID: testGameID, JoinCode: "regenerated-code"}, nil)
+	mockPokerDataSvc.On("GetObserverCode", testGameID).Return("", nil)
+	service := &Service{
+		Config:       &Config{},
+		PokerDataSvc: mockPokerDataSvc,
+		Logger:       otelzap.New(zap.NewNop()),
+	}
+
+	rr := joinDeepLinkRequest(service, thunderdome.JoinDeepLink(testGameID, "old-code"), "")
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	mockPokerDataSvc.AssertNotCalled(t, "JoinGame", mock.Anything, mock.Anything, mock.Anything)
+
+	rr = joinDeepLinkRequest(service, "/join?game=not-base64!&code=b2xkLWNvZGU", "")
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+// TestHandlePokerJoinDeepLinkArchived makes sure archived games can't be joined
+func TestHandlePokerJoinDeepLinkArchived(t *testing.T) {
+	archivedAt := time.Now()
+	mockPokerDataSvc := new(MockPokerDataSvc)
+	mockPokerDataSvc.On("GetGameByID", testGameID, testParticipantID).
+		Return(&thunderdome.Poker{ID: testGameID, JoinCode: "current-code", ArchivedAt: &archivedAt}, nil)
+	service := &Service{
+		Config:       &Config{},
+		PokerDataSvc: mockPokerDataSvc,
+		Logger:       otelzap.New(zap.NewNop()),
+	}
+
+	rr := joinDeepLinkRequest(service, thunderdome.JoinDeepLink(testGameID, "current-code"), "")
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockPokerDataSvc.AssertNotCalled(t, "JoinGame", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestHandlePokerJoinDeepLinkConfirm makes sure following the link doesn't join the game
+func TestHandlePokerJoinDeepLinkConfirm(t *testing.T) {
+	mockPokerDataSvc := new(MockPokerDataSvc)
+	service := &Service{
+		Config:       &Config{PathPrefix: "/thunderdome"},
+		PokerDataSvc: mockPokerDataSvc,
+		Logger:       otelzap.New(zap.NewNop()),
+	}
+
+	req := httptest.NewRequest("GET", thunderdome.JoinDeepLink(testGameID, "current-code"), nil)
+	rr := httptest.NewRecorder()
+	service.handlePokerJoinDeepLinkConfirm().ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `action="/thunderdome/join"`)
+	assert.Contains(t, rr.Body.String(), `method="post"`)
+	mockPokerDataSvc.AssertNotCalled(t, "JoinGame", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHandleSearchPokerStories(t *testing.T) {
+	mockPokerDataSvc := new(MockPokerDataSvc)
+	mockPokerDataSvc.On("GetGameByID", testGameID, testParticipantID).Return(&thunderdome.Poker{ID: testGameID}, nil)
+	mockPokerDataSvc.On("SearchStories", mock.Anything, testGameID, testParticipantID, "login", thunderdome.StorySearchFieldAcceptanceCriteria).
+		Return([]*thunderdome.Story{{ID: testStoryID, Name: "Profile", AcceptanceCriteria: "requires login"}}, nil).Once()
+	service := &Service{
+		PokerDataSvc: mockPokerDataSvc,
+		Logger:       otelzap.New(zap.NewNop()),
+	}
+
+	search := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/battles/"+testGameID+"/plans/search?"+query, nil)
+		req = mux.SetURLVars(req, map[string]string{"battleId": testGameID})
+		ctx := context.WithValue(req.Context(), contextKeyUserID, testParticipantID)
+		req = req.WithContext(context.WithValue(ctx, contextKeyUserType, thunderdome.RegisteredUserType))
+		rr := httptest.NewRecorder()
+		service.handleSearchPokerStories().ServeHTTP(rr, req)
+
+		return rr
+	}
+
+	rr := search("q=login&field=acceptance_criteria")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response struct {
+		Data []thunderdome.Story `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Len(t, response.Data, 1)
+	assert.Equal(t, testStoryID, response.Data[0].ID)
+
+	rr = search("q=login&field=points")
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = search("field=name")
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	mockPokerDataSvc.AssertExpectations(t)
+}
+
+// TestHandleGetPokerStoryDuplicates makes sure the threshold defaults to 0.8 and is validated
+func TestHandleGetPokerStoryDuplicates(t *testing.T) {
+	mockPokerDataSvc := new(MockPokerDataSvc)
+	mockPokerDataSvc.On("GetGameByID", testGameID, testParticipantID).Return(&thunderdome.Poker{ID: testGameID}, nil)
+	mockPokerDataSvc.On("DetectDuplicateStories", mock.Anything, testGameID, 0.8).
+		Return([]thunderdome.DuplicateGroup{{StoryIDs: []string{testStoryID, testGameID}, Similarity: 0.9}}, nil).Once()
+	mockPokerDataSvc.On("DetectDuplicateStories", mock.Anything, testGameID, 0.6).
+		Return([]thunderdome.DuplicateGroup{}, nil).Once()
+	service := &Service{
+		PokerDataSvc: mockPokerDataSvc,
+		Logger:       otelzap.New(zap.NewNop()),
+	}
+
+	duplicates := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/battles/"+testGameID+"/plans/duplicates?"+query, nil)
+		req = mux.SetURLVars(req, map[string]string{"battleId": testGameID})
+		ctx := context.WithValue(req.Context(), contextKeyUserID, testParticipantID)
+		req = req.WithContext(context.WithValue(ctx, contextKeyUserType, thunderdome.RegisteredUserType))
+		rr := httptest.NewRecorder()
+		service.handleGetPokerStoryDuplicates().ServeHTTP(rr, req)
+
+		return rr
+	}
+
+	rr := duplicates("")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response struct {
+		Data []thunderdome.DuplicateGroup `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Len(t, response.Data, 1)
+	assert.Equal(t, []string{testStoryID, testGameID}, response.Data[0].StoryIDs)
+
+	rr = duplicates("threshold=0.6")
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	rr = duplicates("threshold=2")
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	mockPokerDataSvc.AssertExpectations(t)
+}
+
+// TestHandleGetTeamStoriesByReferenceID makes sure a reference ID estimated in two games returns both, newest game first
+func TestHandleGetTeamStoriesByReferenceID(t *testing.T) {
+	sprint12 := time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC)
+	sprint13 := sprint12.Add(14 * 24 * time.Hour)
+	mockPokerDataSvc := new(MockPokerDataSvc)
+	mockPokerDataSvc.On("GetStoriesByReferenceID", mock.Anything, testTeamID, "PROJ-123").Return([]*thunderdome.StoryWithGame{
+		{Story: thunderdome.Story{ID: "story-2", ReferenceID: "PROJ-123", Points: "8"}, GameID: "game-2", GameName: "Sprint 13", CreatedDate: sprint13},
+		{Story: thunderdome.Story{ID: "story-1", ReferenceID: "PROJ-123", Points: "5"}, GameID: "game-1", GameName: "Sprint 12", CreatedDate: sprint12},
+	}, nil).Once()
+	service := &Service{
+		PokerDataSvc: mockPokerDataSvc,
+		Logger:       otelzap.New(zap.NewNop()),
+	}
+
+	get := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/teams/"+testTeamID+"/stories?"+query, nil)
+		req = mux.SetURLVars(req, map[string]string{"teamId": testTeamID})
+		req = req.WithContext(context.WithValue(req.Context(), contextKeyUserID, testParticipantID))
+		rr := httptest.NewRecorder()
+		service.handleGetTeamStoriesByReferenceID().ServeHTTP(rr, req)
+
+		return rr
+	}
+
+	rr := get("reference_id=PROJ-123")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response struct {
+		Data []thunderdome.StoryWithGame `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Len(t, response.Data, 2)
+	assert.Equal(t, "game-2", response.Data[0].GameID)
+	assert.Equal(t, "8", response.Data[0].Points)
+	assert.Equal(t, "game-1", response.Data[1].GameID)
+	assert.Equal(t, "PROJ-123", response.Data[1].ReferenceID)
+
+	rr = get("")
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	mockPokerDataSvc.AssertExpectations(t)
+}
+
+// TestHandleGetTeamStoryTypeReport makes sure the report covers all games without a since date and the date is validated
+func TestHandleGetTeamStoryTypeReport(t *testing.T) {
+	since := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	report := thunderdome.ComputeStoryTypeReport("bug", map[string][]string{"game-1": {"1", "2"}, "game-2": {"2"}})
+	mockPokerDataSvc := new(MockPokerDataSvc)
+	mockPokerDataSvc.On("CrossGameStoryTypeComparison", mock.Anything, testTeamID, "bug", time.Time{}).Return(report, nil).Once()
+	mockPokerDataSvc.On("CrossGameStoryTypeComparison", mock.Anything, testTeamID, "bug", since).Return(report, nil).Once()
+	service := &Service{
+		PokerDataSvc: mockPokerDataSvc,
+		Logger:       otelzap.New(zap.NewNop()),
+	}
+
+	get := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/teams/"+testTeamID+"/story-type-report?"+query, nil)
+		req = mux.SetURLVars(req, map[string]string{"teamId": testTeamID})
+		req = req.WithContext(context.WithValue(req.Context(), contextKeyUserID, testParticipantID))
+		rr := httptest.NewRecorder()
+		service.handleGetTeamStoryTypeReport().ServeHTTP(rr, req)
+
+		return rr
+	}
+
+	rr := get("type=bug")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response struct {
+		Data thunderdome.StoryTypeReport `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, 2, response.Data.GameCount)
+	assert.Equal(t, map[string]int{"1": 1, "2": 2}, response.Data.PointDistribution)
+
+	assert.Equal(t, http.StatusOK, get("type=bug&since=2025-01-01").Code)
+	assert.Equal(t, http.StatusBadRequest, get("type=bug&since=last-week").Code)
+	assert.Equal(t, http.StatusBadRequest, get("since=2025-01-01").Code)
+
+	mockPokerDataSvc.AssertExpectations(t)
+}
+
+// TestResolveGameEstimationScale makes sure new games use the requested scale, then the organization's default
+// scale and finally the global default scale
+func TestResolveGameEstimationScale(t *testing.T) {
+	orgScaleID := "b23e4567-e89b-12d3-a456-426614174000"
+
+	tests := []struct {
+		name          string
+		scaleID       string
+		orgID         string
+		org           *thunderdome.Organization
+		expectedScale string
+	}{
+		{name: "explicit scale", scaleID: "explicit", orgID: testOrgID, expectedScale: "explicit"},
+		{name: "organization default scale", orgID: testOrgID, org: &thunderdome.Organization{ID: testOrgID, DefaultEstimationScaleID: &orgScaleID}, expectedScale: orgScaleID},
+		{name: "organization without default scale", orgID: testOrgID, org: &thunderdome.Organization{ID: testOrgID}, expectedScale: "global"},
+		{name: "no organization", expectedScale: "global"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPokerDataSvc := new(MockPokerDataSvc)
+			mockOrganizationDataSvc := new(MockOrganizationDataService)
+			mockPokerDataSvc.On("GetEstimationScale", mock.Anything, tt.expectedScale).Return(&thunderdome.EstimationScale{ID: tt.expectedScale}, nil).Maybe()
+			mockPokerDataSvc.On("GetDefaultPublicEstimationScale", mock.Anything).Return(&thunderdome.EstimationScale{ID: "global"}, nil).Maybe()
+			if tt.org != nil {
+				mockOrganizationDataSvc.On("OrganizationGetByID", mock.Anything, tt.orgID).Return(*tt.org, nil)
+			}
+			service := &Service{
+				Config:              &Config{OrganizationsEnabled: true},
+				PokerDataSvc:        mockPokerDataSvc,
+				OrganizationDataSvc: mockOrganizationDataSvc,
+				Logger:              otelzap.New(zap.NewNop()),
+			}
+
+			scale, err := service.resolveGameEstimationScale(context.Background(), tt.scaleID, tt.orgID)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedScale, scale.ID)
+			mockOrganizationDataSvc.AssertExpectations(t)
+		})
+	}
+}
+
+// TestHandlePokerStoryInvalidLink makes sure story links that aren't http or https URLs are rejected before reaching the game
+func TestHandlePokerStoryInvalidLink(t *testing.T) {
+	service := &Service{Logger: otelzap.New(zap.NewNop())}
+
+	storyRequest := func(method string, target string, body string, handler http.HandlerFunc) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req = mux.SetURLVars(req, map[string]string{"battleId": testGameID, "planId": testStoryID})
+		req = req.WithContext(context.WithValue(req.Context(), contextKeyUserID, testFacilitatorID))
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		return rr
+	}
+
+	for _, link := range []string{"TBD", "ftp://files.example.com/spec.pdf", "https://"} {
+		body := `{"planName":"Login page","link":"` + link + `"}`
+		for _, rr := range []*httptest.ResponseRecorder{
+			storyRequest("POST", "/battles/"+testGameID+"/plans", body, service.handlePokerStoryAdd(nil)),
+			storyRequest("PUT", "/battles/"+testGameID+"/plans/"+testStoryID, body, service.handlePokerStoryUpdate(nil)),
+		} {
+			assert.Equal(t, http.StatusUnprocessableEntity, rr.Code, link)
+			var response struct {
+				Error string                        `json:"error"`
+				Data  []thunderdome.ValidationError `json:"data"`
+			}
+			assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+			assert.Equal(t, "invalid URL", response.Error)
+			assert.Len(t, response.Data, 1)
+			assert.Equal(t, "link", response.Data[0].Field)
+		}
+	}
+}
+
+// TestHandlePokerStoryActivateNonFacilitator makes sure a participant that isn't a facilitator is forbidden
+// from activating a story
+func TestHandlePokerStoryActivateNonFacilitator(t *testing.T) {
+	mockPokerDataSvc := new(MockPokerDataSvc)
+	mockPokerDataSvc.On("ConfirmFacilitator", testGameID, testParticipantID).Return(errors.New("confirm poker facilitator query error: sql: no rows in result set"))
+	service := &Service{PokerDataSvc: mockPokerDataSvc, Logger: otelzap.New(zap.NewNop())}
+
+	req := httptest.NewRequest("POST", "/battles/"+testGameID+"/plans/"+testStoryID+"/activate", nil)
+	req = mux.SetURLVars(req, map[string]string{"battleId": testGameID, "planId": testStoryID})
+	req = req.WithContext(context.WithValue(req.Context(), contextKeyUserID, testParticipantID))
+	req = req.WithContext(context.WithValue(req.Context(), contextKeyUserType, thunderdome.RegisteredUserType))
+	rr := httptest.NewRecorder()
+	service.handlePokerStoryActivate(nil).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Contains(t, rr.Body.String(), "REQUIRES_FACILITATOR")
+	mockPokerDataSvc.AssertExpectations(t)
+}
@@ -0,0 +1,113 @@
+// Package response provides the standard JSON envelope used by all restful API responses
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+const (
+	// VersionHeader is the response header holding the running API version
+	VersionHeader = "X-API-Version"
+	// AcceptVersionHeader is the request header a client uses to state the API version it expects
+	AcceptVersionHeader = "Accept-Version"
+)
+
+// Envelope is the response body structure used for all restful API responses
+type Envelope struct {
+	Success bool        `json:"success"`
+	Error   string      `json:"error"`
+	Data    interface{} `json:"data" swaggertype:"object"`
+	Meta    interface{} `json:"meta" swaggertype:"object"`
+}
+
+// Meta is the list endpoint pagination meta
+type Meta struct {
+	TotalCount int `json:"count"`
+	Page       int `json:"page"`
+	PageSize   int `json:"limit"`
+	Offset     int `json:"offset"`
+}
+
+// NewMeta creates list pagination meta from the total count and the limit and offset used for the query
+func NewMeta(totalCount int, limit int, offset int) *Meta {
+	page := 1
+	if limit > 0 {
+		page = offset/limit + 1
+	}
+
+	return &Meta{
+		TotalCount: totalCount,
+		Page:       page,
+		PageSize:   limit,
+		Offset:     offset,
+	}
+}
+
+// Respond writes the successful response envelope with the given status code, data and meta
+func Respond(w http.ResponseWriter, code int, data interface{}, meta interface{}) {
+	result := &Envelope{
+		Success: true,
+		Error:   "",
+		Data:    map[string]interface{}{},
+		Meta:    map[string]interface{}{},
+	}
+
+	if meta != nil {
+		result.Meta = meta
+	}
+
+	if data != nil {
+		result.Data = data
+	}
+
+	write(w, code, result)
+}
+
+// RespondOK writes a 200 OK response envelope
+func RespondOK(w http.ResponseWriter, data interface{}, meta *Meta) {
+	Respond(w, http.StatusOK, data, metaOrNil(meta))
+}
+
+// RespondCreated writes a 201 Created response envelope
+func RespondCreated(w http.ResponseWriter, data interface{}, meta *Meta) {
+	Respond(w, http.StatusCreated, data, metaOrNil(meta))
+}
+
+// RespondError writes the failed response envelope with the given status code and error message
+func RespondError(w http.ResponseWriter, code int, message string) {
+	write(w, code, &Envelope{
+		Success: false,
+		Error:   message,
+		Data:    map[string]interface{}{},
+		Meta:    map[string]interface{}{},
+	})
+}
+
+// RespondErrorData writes the failed response envelope with the given status code, error message
+// and data describing the error
+func RespondErrorData(w http.ResponseWriter, code int, message string, data interface{}) {
+	write(w, code, &Envelope{
+		Success: false,
+		Error:   message,
+		Data:    data,
+		Meta:    map[string]interface{}{},
+	})
+}
+
+// metaOrNil avoids a nil *Meta being treated as a non nil meta value
+func metaOrNil(meta *Meta) interface{} {
+	if meta == nil {
+		return nil
+	}
+
+	return meta
+}
+
+func write(w http.ResponseWriter, code int, result *Envelope) {
+	response, _ := json.Marshal(result)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	w.Write(response)
+}
@@ -0,0 +1,78 @@
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMeta(t *testing.T) {
+	tests := []struct {
+		name                      string
+		totalCount, limit, offset int
+		wantPage                  int
+	}{
+		{name: "first page", totalCount: 45, limit: 20, offset: 0, wantPage: 1},
+		{name: "third page", totalCount: 45, limit: 20, offset: 40, wantPage: 3},
+		{name: "no limit", totalCount: 45, limit: 0, offset: 10, wantPage: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			meta := NewMeta(tt.totalCount, tt.limit, tt.offset)
+			assert.Equal(t, tt.totalCount, meta.TotalCount)
+			assert.Equal(t, tt.wantPage, meta.Page)
+			assert.Equal(t, tt.limit, meta.PageSize)
+			assert.Equal(t, tt.offset, meta.Offset)
+		})
+	}
+}
+
+func TestRespond(t *testing.T) {
+	tests := []struct {
+		name     string
+		respond  func(w http.ResponseWriter)
+		wantCode int
+		wantBody string
+	}{
+		{
+			name:     "ok without data",
+			respond:  func(w http.ResponseWriter) { RespondOK(w, nil, nil) },
+			wantCode: http.StatusOK,
+			wantBody: `{"success":true,"error":"","data":{},"meta":{}}`,
+		},
+		{
+			name:     "ok with meta",
+			respond:  func(w http.ResponseWriter) { RespondOK(w, []string{"a"}, NewMeta(1, 20, 0)) },
+			wantCode: http.StatusOK,
+			wantBody: `{"success":true,"error":"","data":["a"],"meta":{"count":1,"page":1,"limit":20,"offset":0}}`,
+		},
+		{
+			name:     "created",
+			respond:  func(w http.ResponseWriter) { RespondCreated(w, map[string]string{"id": "1"}, nil) },
+			wantCode: http.StatusCreated,
+			wantBody: `{"success":true,"error":"","data":{"id":"1"},"meta":{}}`,
+		},
+		{
+			name:     "error",
+			respond:  func(w http.ResponseWriter) { RespondError(w, http.StatusNotFound, "NOT_FOUND") },
+			wantCode: http.StatusNotFound,
+			wantBody: `{"success":false,"error":"NOT_FOUND","data":{},"meta":{}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			tt.respond(rr)
+
+			assert.Equal(t, tt.wantCode, rr.Code)
+			assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+			assert.True(t, json.Valid(rr.Body.Bytes()))
+			assert.JSONEq(t, tt.wantBody, rr.Body.String())
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/http/response"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+const (
+	envelopeTestUserID = "323e4567-e89b-12d3-a456-426614174000"
+	envelopeTestTeamID = "423e4567-e89b-12d3-a456-426614174000"
+	envelopeTestItemID = "523e4567-e89b-12d3-a456-426614174000"
+)
+
+// TestHandlerResponseEnvelope makes sure handlers respond with the same envelope shape for success and failure
+func TestHandlerResponseEnvelope(t *testing.T) {
+	mockTeamDataSvc := new(MockTeamDataSvc)
+	mockAdminDataSvc := new(MockAdminDataSvc)
+	service := &Service{
+		Config:       &Config{CleanupBattlesDaysOld: 180},
+		Logger:       otelzap.New(zap.NewNop()),
+		TeamDataSvc:  mockTeamDataSvc,
+		AdminDataSvc: mockAdminDataSvc,
+	}
+
+	mockTeamDataSvc.On("TeamList", mock.Anything, 20, 0).
+		Return([]*thunderdome.Team{{ID: envelopeTestTeamID, Name: "Team"}}, 1)
+	mockTeamDataSvc.On("TeamUserList", mock.Anything, envelopeTestTeamID, 20, 0).
+		Return([]*thunderdome.TeamUser{{ID: envelopeTestUserID}}, 1, nil).Once()
+	mockTeamDataSvc.On("TeamUserList", mock.Anything, envelopeTestTeamID, 20, 0).
+		Return(nil, 0, errors.New("db error")).Once()
+	mockTeamDataSvc.On("TeamRemoveRetro", mock.Anything, envelopeTestTeamID, envelopeTestItemID).Return(nil)
+	mockTeamDataSvc.On("TeamRemoveStoryboard", mock.Anything, envelopeTestTeamID, envelopeTestItemID).Return(nil)
+	mockTeamDataSvc.On("TeamDeleteUserInvite", mock.Anything, envelopeTestItemID).Return(nil)
+	mockAdminDataSvc.On("CleanupOldGames", mock.Anything, 30, true).
+		Return(&thunderdome.CleanupResult{DryRun: true, GameIDs: []string{}}, nil)
+
+	tests := []struct {
+		name        string
+		handler     http.HandlerFunc
+		body        string
+		vars        map[string]string
+		wantCode    int
+		wantSuccess bool
+		wantMeta    bool
+	}{
+		{
+			name:        "admin teams list",
+			handler:     service.handleGetTeams(),
+			wantCode:    http.StatusOK,
+			wantSuccess: true,
+			wantMeta:    true,
+		},
+		{
+			name:        "team users list",
+			handler:     service.handleGetTeamUsers(),
+			vars:        map[string]string{"teamId": envelopeTestTeamID},
+			wantCode:    http.StatusOK,
+			wantSuccess: true,
+			wantMeta:    true,
+		},
+		{
+			name:     "team users list error",
+			handler:  service.handleGetTeamUsers(),
+			vars:     map[string]string{"teamId": envelopeTestTeamID},
+			wantCode: http.StatusInternalServerError,
+		},
+		{
+			name:        "team remove retro",
+			handler:     service.handleTeamRemoveRetro(),
+			vars:        map[string]string{"teamId": envelopeTestTeamID, "retroId": envelopeTestItemID},
+			wantCode:    http.StatusOK,
+			wantSuccess: true,
+		},
+		{
+			name:        "team remove storyboard",
+			handler:     service.handleTeamRemoveStoryboard(),
+			vars:        map[string]string{"teamId": envelopeTestTeamID, "storyboardId": envelopeTestItemID},
+			wantCode:    http.StatusOK,
+			wantSuccess: true,
+		},
+		{
+			name:        "team delete user invite",
+			handler:     service.handleDeleteTeamUserInvite(),
+			vars:        map[string]string{"teamId": envelopeTestTeamID, "inviteId": envelopeTestItemID},
+			wantCode:    http.StatusOK,
+			wantSuccess: true,
+		},
+		{
+			name:        "admin cleanup old games",
+			handler:     service.handleCleanupOldGames(),
+			body:        `{"days_old":30,"dry_run":true}`,
+			wantCode:    http.StatusOK,
+			wantSuccess: true,
+		},
+		{
+			name:     "get poker game invalid id",
+			handler:  service.handleGetPokerGame(),
+			vars:     map[string]string{"battleId": "not-a-uuid"},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "get poker leaderboard invalid id",
+			handler:  service.handleGetPokerLeaderboard(),
+			vars:     map[string]string{"battleId": "not-a-uuid"},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "create poker game invalid user",
+			handler:  service.handlePokerCreate(),
+			vars:     map[string]string{"userId": "not-a-uuid"},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "create retro invalid user",
+			handler:  service.handleRetroCreate(),
+			vars:     map[string]string{"userId": "not-a-uuid"},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "get team users invalid team",
+			handler:  service.handleGetTeamUsers(),
+			vars:     map[string]string{"teamId": "not-a-uuid"},
+			wantCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", strings.NewReader(tt.body))
+			ctx := context.WithValue(req.Context(), contextKeyUserID, envelopeTestUserID)
+			ctx = context.WithValue(ctx, contextKeyUserType, thunderdome.RegisteredUserType)
+			req = mux.SetURLVars(req.WithContext(ctx), tt.vars)
+			rr := httptest.NewRecorder()
+
+			tt.handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantCode, rr.Code)
+			assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+
+			var envelope map[string]json.RawMessage
+			assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &envelope))
+			assert.Len(t, envelope, 4)
+			for _, key := range []string{"success", "error", "data", "meta"} {
+				assert.Contains(t, envelope, key)
+			}
+
+			var result response.Envelope
+			assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &result))
+			assert.Equal(t, tt.wantSuccess, result.Success)
+			if tt.wantSuccess {
+				assert.Empty(t, result.Error)
+			} else {
+				assert.NotEmpty(t, result.Error)
+			}
+
+			if tt.wantMeta {
+				var meta response.Meta
+				assert.NoError(t, json.Unmarshal(envelope["meta"], &meta))
+				assert.Equal(t, 1, meta.TotalCount)
+				assert.Equal(t, 1, meta.Page)
+				assert.Equal(t, 20, meta.PageSize)
+			}
+		})
+	}
+}
+
+// TestAPIVersionHeader makes sure the running version is always sent back
+func TestAPIVersionHeader(t *testing.T) {
+	service := &Service{
+		Logger:   otelzap.New(zap.NewNop()),
+		UIConfig: thunderdome.UIConfig{AppConfig: thunderdome.AppConfig{AppVersion: "v4.2.0"}},
+	}
+	handler := service.apiVersion(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		service.Success(w, r, http.StatusOK, nil, nil)
+	}))
+
+	for _, acceptVersion := range []string{"", "v4.2.0", "v3.0.0"} {
+		req := httptest.NewRequest(http.MethodGet, "/api/", nil)
+		if acceptVersion != "" {
+			req.Header.Set(response.AcceptVersionHeader, acceptVersion)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "v4.2.0", rr.Header().Get(response.VersionHeader))
+	}
+}
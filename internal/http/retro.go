@@ -5,10 +5,13 @@ import (
 	"io"
 	"net/http"
 	"strconv"
+	"time"
 
 	"go.uber.org/zap"
 
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/http/response"
 	"github.com/StevenWeathers/thunderdome-planning-poker/internal/http/retro"
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/webhook/subscription"
 	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
 
 	"github.com/gorilla/mux"
@@ -24,6 +27,9 @@ type retroCreateRequestBody struct {
 	PhaseAutoAdvance      bool    `json:"phaseAutoAdvance"`
 	AllowCumulativeVoting bool    `json:"allowCumulativeVoting"`
 	TemplateID            *string `json:"templateId"`
+	// SubmissionPhase lets participants privately submit items before the facilitator opens the session
+	SubmissionPhase    bool       `json:"submissionPhase"`
+	SubmissionDeadline *time.Time `json:"submissionDeadline"`
 }
 
 // handleRetroCreate handles creating a retro
@@ -81,13 +87,21 @@ func (s *Service) handleRetroCreate() http.HandlerFunc {
 			return
 		}
 
+		if nr.SubmissionPhase && nr.SubmissionDeadline != nil && !nr.SubmissionDeadline.After(time.Now()) {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "SUBMISSION_DEADLINE_MUST_BE_IN_FUTURE"))
+			return
+		}
+		if !nr.SubmissionPhase {
+			nr.SubmissionDeadline = nil
+		}
+
 		if nr.TemplateID == nil {
 			// get default template
 			template, err := s.RetroTemplateDataSvc.GetDefaultPublicTemplate(ctx)
 			if err != nil {
 				s.Logger.Ctx(ctx).Error("handleRetroCreate get default template by id error", zap.Error(err),
 					zap.String("session_user_id", sessionUserID))
-				w.WriteHeader(http.StatusInternalServerError)
+				s.Failure(w, r, http.StatusInternalServerError, err)
 				return
 			}
 			nr.TemplateID = &template.ID
@@ -102,17 +116,19 @@ func (s *Service) handleRetroCreate() http.HandlerFunc {
 			return
 		}
 
-		newRetro, err = s.RetroDataSvc.CreateRetro(ctx, userID, teamID, nr.RetroName, nr.JoinCode, nr.FacilitatorCode, nr.MaxVotes, nr.BrainstormVisibility, nr.PhaseTimeLimitMin, nr.PhaseAutoAdvance, nr.AllowCumulativeVoting, *nr.TemplateID)
+		newRetro, err = s.RetroDataSvc.CreateRetro(ctx, userID, teamID, nr.RetroName, nr.JoinCode, nr.FacilitatorCode, nr.MaxVotes, nr.BrainstormVisibility, nr.PhaseTimeLimitMin, nr.PhaseAutoAdvance, nr.AllowCumulativeVoting, *nr.TemplateID, nr.SubmissionPhase, nr.SubmissionDeadline)
 		if err != nil {
 			s.Logger.Ctx(ctx).Error("handleRetroCreate error", zap.Error(err),
 				zap.String("entity_user_id", userID),
 				zap.String("retro_name", nr.RetroName),
 				zap.String("session_user_id", sessionUserID),
 				zap.String("team_id", teamID))
-			w.WriteHeader(http.StatusInternalServerError)
+			s.Failure(w, r, http.StatusInternalServerError, err)
 			return
 		}
 
+		s.recordUsage(ctx, vars["orgId"], subscription.UsageEventRetroCreated)
+
 		s.Success(w, r, http.StatusOK, newRetro, nil)
 	}
 }
@@ -151,6 +167,46 @@ func (s *Service) handleRetroGet() http.HandlerFunc {
 	}
 }
 
+// handleRetroEmotionsGet gets the retro's item emotion histogram
+//
+//	@Summary		Get Retro Emotions
+//	@Description	get the number of retro items tagged with each emotion, items without an emotion are counted as untagged
+//	@Tags			retro
+//	@Produce		json
+//	@Param			retroId	path	string	true	"the retro ID to get emotions for"
+//	@Success		200		object	standardJsonResponse{data=map[string]int}
+//	@Failure		403		object	standardJsonResponse{}
+//	@Failure		404		object	standardJsonResponse{}
+//	@Failure		500		object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/retros/{retroId}/emotions [get]
+func (s *Service) handleRetroEmotionsGet() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		retroID := vars["retroId"]
+		idErr := validate.Var(retroID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		histogram, err := s.RetroDataSvc.GetEmotionHistogram(ctx, retroID)
+		if err != nil {
+			if err.Error() == "RETRO_NOT_FOUND" {
+				s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, err.Error()))
+				return
+			}
+			s.Logger.Ctx(ctx).Error("handleRetroEmotionsGet error", zap.Error(err),
+				zap.String("retro_id", retroID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, histogram, nil)
+	}
+}
+
 // handleRetrosGetByUser looks up retros associated with userID
 //
 //	@Summary		Get Retros by User
@@ -182,11 +238,7 @@ func (s *Service) handleRetrosGetByUser() http.HandlerFunc {
 			return
 		}
 
-		meta := &pagination{
-			Count:  count,
-			Offset: offset,
-			Limit:  limit,
-		}
+		meta := response.NewMeta(count, limit, offset)
 
 		s.Success(w, r, http.StatusOK, retros, meta)
 	}
@@ -230,11 +282,7 @@ func (s *Service) handleGetRetros() http.HandlerFunc {
 			return
 		}
 
-		meta := &pagination{
-			Count:  count,
-			Offset: offset,
-			Limit:  limit,
-		}
+		meta := response.NewMeta(count, limit, offset)
 
 		s.Success(w, r, http.StatusOK, retros, meta)
 	}
@@ -0,0 +1,86 @@
+package retro
+
+import (
+	"context"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+// findRetroAction finds the action by ID in the retro actions
+func findRetroAction(actions []*thunderdome.RetroAction, actionID string) *thunderdome.RetroAction {
+	for _, action := range actions {
+		if action.ID == actionID {
+			return action
+		}
+	}
+
+	return nil
+}
+
+// isActionAssignee checks whether the user is assigned to the retro action
+func isActionAssignee(actions []*thunderdome.RetroAction, actionID string, userID string) bool {
+	action := findRetroAction(actions, actionID)
+	if action == nil {
+		return false
+	}
+
+	for _, assignee := range action.Assignees {
+		if assignee.ID == userID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sendActionAssigneeEmail notifies the user that they were assigned to or unassigned from the retro action
+func (b *Service) sendActionAssigneeEmail(retroID string, actionID string, userID string, actions []*thunderdome.RetroAction, assigned bool) {
+	action := findRetroAction(actions, actionID)
+	if action == nil {
+		return
+	}
+
+	var assignee *thunderdome.RetroUser
+	for _, user := range b.RetroService.RetroGetUsers(retroID) {
+		if user.ID == userID {
+			assignee = user
+			break
+		}
+	}
+	// don't send emails to guest's as they have no email
+	if assignee == nil || assignee.Email == "" {
+		return
+	}
+
+	retro, err := b.RetroService.RetroGetByID(retroID, userID)
+	if err != nil {
+		b.logger.Error("Error getting retro for action assignee email", zap.Error(err),
+			zap.String("retro_id", retroID), zap.String("action_id", actionID))
+		return
+	}
+
+	if assigned {
+		err = b.EmailService.SendRetroActionAssigned(retro, action, assignee.Name, assignee.Email)
+	} else {
+		err = b.EmailService.SendRetroActionUnassigned(retro, action, assignee.Name, assignee.Email)
+	}
+	if err != nil {
+		b.logger.Error("Error sending retro action assignee email", zap.Error(err),
+			zap.String("retro_id", retroID), zap.String("action_id", actionID))
+	}
+}
+
+// notifyActionAssignee creates an in-app notification for the user assigned to the retro action
+func (b *Service) notifyActionAssignee(ctx context.Context, retroID string, actionID string, userID string, actions []*thunderdome.RetroAction) {
+	action := findRetroAction(actions, actionID)
+	if b.Notifier == nil || action == nil {
+		return
+	}
+
+	b.Notifier.NotifyUser(ctx, userID, thunderdome.NotificationTypeActionAssigned, map[string]string{
+		"retroId":  retroID,
+		"actionId": actionID,
+		"content":  action.Content,
+	})
+}
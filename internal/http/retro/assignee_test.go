@@ -0,0 +1,192 @@
+package retro
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+const (
+	testRetroID  = "retro-1"
+	testActionID = "action-1"
+)
+
+type fakeRetroDataSvc struct {
+	RetroDataSvc
+	actions []*thunderdome.RetroAction
+	users   []*thunderdome.RetroUser
+}
+
+func (f *fakeRetroDataSvc) GetRetroActions(retroID string) []*thunderdome.RetroAction {
+	return f.actions
+}
+
+func (f *fakeRetroDataSvc) RetroActionAssigneeAdd(retroID string, actionID string, userID string) ([]*thunderdome.RetroAction, error) {
+	action := findRetroAction(f.actions, actionID)
+	if !isActionAssignee(f.actions, actionID, userID) {
+		action.Assignees = append(action.Assignees, &thunderdome.User{ID: userID})
+	}
+
+	return f.actions, nil
+}
+
+func (f *fakeRetroDataSvc) RetroActionAssigneeDelete(retroID string, actionID string, userID string) ([]*thunderdome.RetroAction, error) {
+	return []*thunderdome.RetroAction{{ID: actionID, Content: "fix the build"}}, nil
+}
+
+func (f *fakeRetroDataSvc) RetroGetUsers(retroID string) []*thunderdome.RetroUser {
+	return f.users
+}
+
+func (f *fakeRetroDataSvc) RetroGetByID(retroID string, userID string) (*thunderdome.Retro, error) {
+	return &thunderdome.Retro{ID: retroID, Name: "Sprint 12"}, nil
+}
+
+type sentAssigneeEmail struct {
+	assigned  bool
+	retroName string
+	content   string
+	userName  string
+	userEmail string
+}
+
+type mockEmailService struct {
+	sent chan sentAssigneeEmail
+}
+
+func (m *mockEmailService) SendRetroOverview(retro *thunderdome.Retro, template *thunderdome.RetroTemplate, userName string, userEmail string) error {
+	return nil
+}
+
+func (m *mockEmailService) SendRetroActionAssigned(retro *thunderdome.Retro, action *thunderdome.RetroAction, userName string, userEmail string) error {
+	m.sent <- sentAssigneeEmail{assigned: true, retroName: retro.Name, content: action.Content, userName: userName, userEmail: userEmail}
+	return nil
+}
+
+func (m *mockEmailService) SendRetroActionUnassigned(retro *thunderdome.Retro, action *thunderdome.RetroAction, userName string, userEmail string) error {
+	m.sent <- sentAssigneeEmail{assigned: false, retroName: retro.Name, content: action.Content, userName: userName, userEmail: userEmail}
+	return nil
+}
+
+func newAssigneeTestService(assignees []*thunderdome.User) (*Service, *mockEmailService) {
+	emailSvc := &mockEmailService{sent: make(chan sentAssigneeEmail, 2)}
+	b := &Service{
+		logger: otelzap.New(zap.NewNop()),
+		RetroService: &fakeRetroDataSvc{
+			actions: []*thunderdome.RetroAction{
+				{ID: testActionID, Content: "fix the build", Assignees: assignees},
+			},
+			users: []*thunderdome.RetroUser{
+				{ID: "old", Name: "Old Assignee", Email: "old@example.com"},
+				{ID: "new", Name: "New Assignee", Email: "new@example.com"},
+				{ID: "guest", Name: "Guest"},
+			},
+		},
+		EmailService: emailSvc,
+	}
+
+	return b, emailSvc
+}
+
+func assigneeEvent(userID string) string {
+	event, _ := json.Marshal(map[string]string{"id": testActionID, "user_id": userID})
+	return string(event)
+}
+
+func waitForEmail(t *testing.T, emailSvc *mockEmailService) sentAssigneeEmail {
+	t.Helper()
+	select {
+	case sent := <-emailSvc.sent:
+		return sent
+	case <-time.After(time.Second):
+		t.Fatal("expected an email to be sent")
+	}
+
+	return sentAssigneeEmail{}
+}
+
+func assertNoEmail(t *testing.T, emailSvc *mockEmailService) {
+	t.Helper()
+	select {
+	case sent := <-emailSvc.sent:
+		t.Fatalf("expected no email to be sent, got %+v", sent)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestActionAddAssigneeSendsAssignedEmail(t *testing.T) {
+	b, emailSvc := newAssigneeTestService([]*thunderdome.User{})
+
+	if _, err, _ := b.ActionAddAssignee(context.Background(), testRetroID, "facilitator", assigneeEvent("new")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sent := waitForEmail(t, emailSvc)
+	if !sent.assigned {
+		t.Error("expected assigned email")
+	}
+	if sent.userEmail != "new@example.com" || sent.userName != "New Assignee" {
+		t.Errorf("expected email to new assignee, got %s <%s>", sent.userName, sent.userEmail)
+	}
+	if sent.retroName != "Sprint 12" || sent.content != "fix the build" {
+		t.Errorf("expected retro and action details, got %+v", sent)
+	}
+}
+
+func TestActionAddAssigneeUnchangedSendsNoEmail(t *testing.T) {
+	b, emailSvc := newAssigneeTestService([]*thunderdome.User{{ID: "new"}})
+
+	if _, err, _ := b.ActionAddAssignee(context.Background(), testRetroID, "facilitator", assigneeEvent("new")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	assertNoEmail(t, emailSvc)
+}
+
+func TestActionAddAssigneeGuestSendsNoEmail(t *testing.T) {
+	b, emailSvc := newAssigneeTestService([]*thunderdome.User{})
+
+	if _, err, _ := b.ActionAddAssignee(context.Background(), testRetroID, "facilitator", assigneeEvent("guest")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	assertNoEmail(t, emailSvc)
+}
+
+func TestActionReassignSendsEmailToBothAssignees(t *testing.T) {
+	b, emailSvc := newAssigneeTestService([]*thunderdome.User{{ID: "old"}})
+
+	if _, err, _ := b.ActionRemoveAssignee(context.Background(), testRetroID, "facilitator", assigneeEvent("old")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	unassigned := waitForEmail(t, emailSvc)
+	if unassigned.assigned || unassigned.userEmail != "old@example.com" {
+		t.Errorf("expected unassigned email to old assignee, got %+v", unassigned)
+	}
+	if unassigned.content != "fix the build" {
+		t.Errorf("expected action content in unassigned email, got %q", unassigned.content)
+	}
+
+	if _, err, _ := b.ActionAddAssignee(context.Background(), testRetroID, "facilitator", assigneeEvent("new")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assigned := waitForEmail(t, emailSvc)
+	if !assigned.assigned || assigned.userEmail != "new@example.com" {
+		t.Errorf("expected assigned email to new assignee, got %+v", assigned)
+	}
+}
+
+func TestActionRemoveAssigneeNotAssignedSendsNoEmail(t *testing.T) {
+	b, emailSvc := newAssigneeTestService([]*thunderdome.User{})
+
+	if _, err, _ := b.ActionRemoveAssignee(context.Background(), testRetroID, "facilitator", assigneeEvent("old")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	assertNoEmail(t, emailSvc)
+}
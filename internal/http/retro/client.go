@@ -122,6 +122,14 @@ func (b *Service) ServeWs() http.HandlerFunc {
 			}
 		}
 
+		if retro.SubmissionPhase && retro.SubmissionDeadline != nil {
+			b.scheduleSubmissionDeadline(roomID, *retro.SubmissionDeadline)
+		}
+		// resume relaying timers left running, e.g. after the instance restarted
+		for _, timer := range retro.Timers {
+			b.timers.start(roomID, *timer)
+		}
+
 		sub := b.hub.NewSubscriber(c.Ws, user.ID, roomID)
 
 		users, _ := b.RetroService.RetroAddUser(roomID, user.ID)
@@ -152,3 +160,18 @@ func (b *Service) RetreatUser(roomID string, userID string) string {
 func (b *Service) APIEvent(ctx context.Context, retroID string, userID, eventType string, eventValue string) error {
 	return b.hub.ProcessAPIEventHandler(ctx, userID, retroID, eventType, eventValue)
 }
+
+// Shutdown gracefully closes all websocket connections, see wshub.Hub.Shutdown
+func (b *Service) Shutdown(ctx context.Context) error {
+	return b.hub.Shutdown(ctx)
+}
+
+// EvictIdleConnections closes idle websocket connections that don't respond to a ping, see wshub.Hub.EvictIdleConnections
+func (b *Service) EvictIdleConnections(idleMinutes int) int {
+	return b.hub.EvictIdleConnections(idleMinutes)
+}
+
+// SendToUser sends the message to the user's websocket connections, see wshub.Hub.SendToUser
+func (b *Service) SendToUser(userID string, data []byte) {
+	b.hub.SendToUser(userID, data)
+}
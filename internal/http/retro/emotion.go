@@ -0,0 +1,32 @@
+package retro
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/wshub"
+)
+
+// retroItemEmotion is the tag_item_emotion and retro_item_emotion_updated event value
+type retroItemEmotion struct {
+	ItemID  string `json:"itemId"`
+	Emotion string `json:"emotion"`
+}
+
+// TagItemEmotion tags a retro item with an emotion, an empty emotion removes the tag
+func (b *Service) TagItemEmotion(ctx context.Context, RetroID string, UserID string, EventValue string) ([]byte, error, bool) {
+	var rs retroItemEmotion
+	err := json.Unmarshal([]byte(EventValue), &rs)
+	if err != nil {
+		return nil, err, false
+	}
+
+	if err := b.RetroService.TagItemEmotion(ctx, RetroID, rs.ItemID, rs.Emotion); err != nil {
+		return nil, err, false
+	}
+
+	updatedEmotion, _ := json.Marshal(rs)
+	msg := wshub.CreateSocketEvent("retro_item_emotion_updated", string(updatedEmotion), "")
+
+	return msg, nil, false
+}
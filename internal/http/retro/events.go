@@ -28,8 +28,7 @@ func (b *Service) CreateItem(ctx context.Context, RetroID string, UserID string,
 		return nil, err, false
 	}
 
-	updatedItems, _ := json.Marshal(items)
-	msg := wshub.CreateSocketEvent("items_updated", string(updatedItems), "")
+	msg := b.itemsUpdatedEvent(RetroID, items)
 
 	return msg, nil, false
 }
@@ -50,8 +49,7 @@ func (b *Service) ItemCommentAdd(ctx context.Context, RetroID string, UserID str
 		return nil, err, false
 	}
 
-	updatedItems, _ := json.Marshal(items)
-	msg := wshub.CreateSocketEvent("items_updated", string(updatedItems), "")
+	msg := b.itemsUpdatedEvent(RetroID, items)
 
 	return msg, nil, false
 }
@@ -72,8 +70,7 @@ func (b *Service) ItemCommentEdit(ctx context.Context, RetroID string, UserID st
 		return nil, err, false
 	}
 
-	updatedItems, _ := json.Marshal(items)
-	msg := wshub.CreateSocketEvent("items_updated", string(updatedItems), "")
+	msg := b.itemsUpdatedEvent(RetroID, items)
 
 	return msg, nil, false
 }
@@ -93,8 +90,7 @@ func (b *Service) ItemCommentDelete(ctx context.Context, RetroID string, UserID
 		return nil, err, false
 	}
 
-	updatedItems, _ := json.Marshal(items)
-	msg := wshub.CreateSocketEvent("items_updated", string(updatedItems), "")
+	msg := b.itemsUpdatedEvent(RetroID, items)
 
 	return msg, nil, false
 }
@@ -141,10 +137,7 @@ func (b *Service) GroupItem(ctx context.Context, RetroID string, UserID string,
 		return nil, err, false
 	}
 
-	updatedItem, _ := json.Marshal(item)
-	msg := wshub.CreateSocketEvent("item_moved", string(updatedItem), "")
-
-	return msg, nil, false
+	return b.itemMovedEvent(RetroID, &item), nil, false
 }
 
 // DeleteItem deletes a retro item
@@ -164,8 +157,7 @@ func (b *Service) DeleteItem(ctx context.Context, RetroID string, UserID string,
 		return nil, err, false
 	}
 
-	updatedItems, _ := json.Marshal(items)
-	msg := wshub.CreateSocketEvent("items_updated", string(updatedItems), "")
+	msg := b.itemsUpdatedEvent(RetroID, items)
 
 	return msg, nil, false
 }
@@ -192,6 +184,81 @@ func (b *Service) GroupNameChange(ctx context.Context, RetroID string, UserID st
 	return msg, nil, false
 }
 
+// GroupCreate creates a named and colored retro group for clustering items
+func (b *Service) GroupCreate(ctx context.Context, RetroID string, UserID string, EventValue string) ([]byte, error, bool) {
+	var rs struct {
+		Name  string `json:"name"`
+		Color string `json:"color"`
+	}
+	err := json.Unmarshal([]byte(EventValue), &rs)
+	if err != nil {
+		return nil, err, false
+	}
+
+	groups, err := b.RetroService.CreateGroup(RetroID, rs.Name, rs.Color)
+	if err != nil {
+		return nil, err, false
+	}
+
+	return b.groupUpdatedEvent(RetroID, groups)
+}
+
+// GroupItemAdd adds a retro item to a group, moving it out of any group it was already in
+func (b *Service) GroupItemAdd(ctx context.Context, RetroID string, UserID string, EventValue string) ([]byte, error, bool) {
+	var rs struct {
+		GroupID string `json:"groupId"`
+		ItemID  string `json:"itemId"`
+	}
+	err := json.Unmarshal([]byte(EventValue), &rs)
+	if err != nil {
+		return nil, err, false
+	}
+
+	groups, err := b.RetroService.AddItemToGroup(RetroID, rs.GroupID, rs.ItemID)
+	if err != nil {
+		return nil, err, false
+	}
+
+	return b.groupUpdatedEvent(RetroID, groups)
+}
+
+// GroupItemRemove removes a retro item from a group
+func (b *Service) GroupItemRemove(ctx context.Context, RetroID string, UserID string, EventValue string) ([]byte, error, bool) {
+	var rs struct {
+		GroupID string `json:"groupId"`
+		ItemID  string `json:"itemId"`
+	}
+	err := json.Unmarshal([]byte(EventValue), &rs)
+	if err != nil {
+		return nil, err, false
+	}
+
+	groups, err := b.RetroService.RemoveItemFromGroup(RetroID, rs.GroupID, rs.ItemID)
+	if err != nil {
+		return nil, err, false
+	}
+
+	return b.groupUpdatedEvent(RetroID, groups)
+}
+
+// GroupDelete deletes a retro group
+func (b *Service) GroupDelete(ctx context.Context, RetroID string, UserID string, EventValue string) ([]byte, error, bool) {
+	var rs struct {
+		GroupID string `json:"groupId"`
+	}
+	err := json.Unmarshal([]byte(EventValue), &rs)
+	if err != nil {
+		return nil, err, false
+	}
+
+	groups, err := b.RetroService.DeleteGroup(RetroID, rs.GroupID)
+	if err != nil {
+		return nil, err, false
+	}
+
+	return b.groupUpdatedEvent(RetroID, groups)
+}
+
 // GroupUserVote handles a users vote for an item group
 func (b *Service) GroupUserVote(ctx context.Context, RetroID string, UserID string, EventValue string) ([]byte, error, bool) {
 	var rs struct {
@@ -289,11 +356,20 @@ func (b *Service) ActionAddAssignee(ctx context.Context, RetroID string, UserID
 		return nil, err, false
 	}
 
+	alreadyAssigned := isActionAssignee(b.RetroService.GetRetroActions(RetroID), rs.ActionID, rs.UserID)
+
 	items, err := b.RetroService.RetroActionAssigneeAdd(RetroID, rs.ActionID, rs.UserID)
 	if err != nil {
 		return nil, err, false
 	}
 
+	if !alreadyAssigned {
+		go b.sendActionAssigneeEmail(RetroID, rs.ActionID, rs.UserID, items, true)
+		if rs.UserID != UserID {
+			go b.notifyActionAssignee(context.Background(), RetroID, rs.ActionID, rs.UserID, items)
+		}
+	}
+
 	updatedItems, _ := json.Marshal(items)
 	msg := wshub.CreateSocketEvent("action_updated", string(updatedItems), "")
 
@@ -311,11 +387,17 @@ func (b *Service) ActionRemoveAssignee(ctx context.Context, RetroID string, User
 		return nil, err, false
 	}
 
+	actions := b.RetroService.GetRetroActions(RetroID)
+
 	items, err := b.RetroService.RetroActionAssigneeDelete(RetroID, rs.ActionID, rs.UserID)
 	if err != nil {
 		return nil, err, false
 	}
 
+	if isActionAssignee(actions, rs.ActionID, rs.UserID) {
+		go b.sendActionAssigneeEmail(RetroID, rs.ActionID, rs.UserID, actions, false)
+	}
+
 	updatedItems, _ := json.Marshal(items)
 	msg := wshub.CreateSocketEvent("action_updated", string(updatedItems), "")
 
@@ -358,8 +440,7 @@ func (b *Service) AdvancePhase(ctx context.Context, RetroID string, UserID strin
 		return nil, err, false
 	}
 
-	updatedItems, _ := json.Marshal(retro)
-	msg := wshub.CreateSocketEvent("phase_updated", string(updatedItems), "")
+	msg := b.phaseUpdatedEvent(retro)
 
 	// if retro is completed send retro email to attendees
 	if rs.Phase == "completed" {
@@ -384,8 +465,7 @@ func (b *Service) PhaseTimeout(ctx context.Context, RetroID string, UserID strin
 		return nil, err, false
 	}
 
-	updatedItems, _ := json.Marshal(retro)
-	msg := wshub.CreateSocketEvent("phase_updated", string(updatedItems), "")
+	msg := b.phaseUpdatedEvent(retro)
 
 	return msg, nil, false
 }
@@ -405,8 +485,7 @@ func (b *Service) PhaseAllReady(ctx context.Context, RetroID string, UserID stri
 		return nil, err, false
 	}
 
-	updatedItems, _ := json.Marshal(retro)
-	msg := wshub.CreateSocketEvent("phase_updated", string(updatedItems), "")
+	msg := b.phaseUpdatedEvent(retro)
 
 	return msg, nil, false
 }
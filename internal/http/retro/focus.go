@@ -0,0 +1,44 @@
+package retro
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/wshub"
+	"go.uber.org/zap"
+)
+
+// FocusItem handles the facilitator focusing the retro on the item being reviewed,
+// an empty item ID removes the focus
+func (b *Service) FocusItem(ctx context.Context, RetroID string, UserID string, EventValue string) ([]byte, error, bool) {
+	if err := b.RetroService.SetFocusItem(ctx, RetroID, EventValue); err != nil {
+		return nil, err, false
+	}
+
+	// participants are only shown the focused item's content so their items change with the focus
+	if msg := b.itemsUpdatedEvent(RetroID, b.RetroService.GetRetroItems(RetroID)); msg != nil {
+		b.hub.Broadcast(wshub.Message{Data: msg, Room: RetroID})
+	}
+
+	var focus struct {
+		ActiveItemID *string `json:"activeItemId"`
+	}
+	if EventValue != "" {
+		focus.ActiveItemID = &EventValue
+	}
+	updatedFocus, _ := json.Marshal(focus)
+	msg := wshub.CreateSocketEvent("retro_focus_changed", string(updatedFocus), "")
+
+	return msg, nil, false
+}
+
+// focusItem gets the ID of the item the retro is focused on, nil when the retro isn't focused
+func (b *Service) focusItem(RetroID string) *string {
+	activeItemID, err := b.RetroService.GetRetroFocusItem(RetroID)
+	if err != nil {
+		b.logger.Error("get retro focus item error", zap.Error(err), zap.String("retro_id", RetroID))
+		return nil
+	}
+
+	return activeItemID
+}
@@ -0,0 +1,145 @@
+package retro
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+type fakeFocusRetroDataSvc struct {
+	RetroDataSvc
+	activeItemID *string
+}
+
+func (f *fakeFocusRetroDataSvc) SetFocusItem(ctx context.Context, retroID string, itemID string) error {
+	f.activeItemID = nil
+	if itemID != "" {
+		f.activeItemID = &itemID
+	}
+	return nil
+}
+
+func (f *fakeFocusRetroDataSvc) GetRetroFocusItem(retroID string) (*string, error) {
+	return f.activeItemID, nil
+}
+
+func (f *fakeFocusRetroDataSvc) GetRetroSubmissionPhase(retroID string) (bool, *time.Time, error) {
+	return false, nil, nil
+}
+
+func (f *fakeFocusRetroDataSvc) GetRetroFacilitators(retroID string) []string {
+	return []string{"facilitator"}
+}
+
+func (f *fakeFocusRetroDataSvc) SubscribeTimerEvents(ctx context.Context) <-chan thunderdome.RetroTimerEvent {
+	return nil
+}
+
+func (f *fakeFocusRetroDataSvc) GetRetroItems(retroID string) []*thunderdome.RetroItem {
+	return []*thunderdome.RetroItem{
+		{ID: "item-1", UserID: "author", Content: "went well", Comments: []*thunderdome.RetroItemComment{}},
+		{ID: "item-2", UserID: "author", Content: "needs work", Comments: []*thunderdome.RetroItemComment{}},
+	}
+}
+
+func newFocusTestService(dataSvc *fakeFocusRetroDataSvc) *Service {
+	return New(Config{}, otelzap.New(zap.NewNop()), nil, nil, nil, nil, dataSvc, nil, nil, nil)
+}
+
+// focusChangedItemID gets the active item ID sent in a retro_focus_changed event
+func focusChangedItemID(t *testing.T, msg []byte) *string {
+	t.Helper()
+
+	var event struct {
+		Type  string `json:"type"`
+		Value string `json:"value"`
+	}
+	if err := json.Unmarshal(msg, &event); err != nil {
+		t.Fatalf("unexpected event %s: %v", msg, err)
+	}
+	if event.Type != "retro_focus_changed" {
+		t.Fatalf("expected retro_focus_changed event, got %s", event.Type)
+	}
+
+	var focus struct {
+		ActiveItemID *string `json:"activeItemId"`
+	}
+	if err := json.Unmarshal([]byte(event.Value), &focus); err != nil {
+		t.Fatalf("unexpected event value %s: %v", event.Value, err)
+	}
+
+	return focus.ActiveItemID
+}
+
+// TestFocusItemUnset makes sure unsetting the focus removes the focus state
+func TestFocusItemUnset(t *testing.T) {
+	dataSvc := &fakeFocusRetroDataSvc{}
+	b := newFocusTestService(dataSvc)
+
+	msg, err, _ := b.FocusItem(context.Background(), testRetroID, "facilitator", "item-2")
+	if err != nil {
+		t.Fatalf("unexpected error focusing item: %v", err)
+	}
+	if id := focusChangedItemID(t, msg); id == nil || *id != "item-2" {
+		t.Fatalf("expected focus on item-2, got %v", id)
+	}
+
+	msg, err, _ = b.FocusItem(context.Background(), testRetroID, "facilitator", "")
+	if err != nil {
+		t.Fatalf("unexpected error unsetting focus: %v", err)
+	}
+	if id := focusChangedItemID(t, msg); id != nil {
+		t.Errorf("expected no focused item, got %s", *id)
+	}
+	if dataSvc.activeItemID != nil {
+		t.Errorf("expected focus state to be removed, got %s", *dataSvc.activeItemID)
+	}
+	if b.itemsUpdatedEvent(testRetroID, dataSvc.GetRetroItems(testRetroID)) == nil {
+		t.Error("expected unfocused retro items to be sent to the whole room")
+	}
+}
+
+// TestUserVisibleItemsFocus makes sure participants receive the redacted view while the facilitator receives full content
+func TestUserVisibleItemsFocus(t *testing.T) {
+	dataSvc := &fakeFocusRetroDataSvc{}
+	activeItemID := "item-2"
+
+	participantItems := userVisibleItems(dataSvc.GetRetroItems(testRetroID), "participant", false, false, &activeItemID)
+	if !participantItems[0].Redacted || participantItems[0].Content != "" {
+		t.Errorf("expected participant to receive redacted item, got %+v", participantItems[0])
+	}
+	if participantItems[1].Redacted || participantItems[1].Content != "needs work" {
+		t.Errorf("expected participant to receive focused item in full, got %+v", participantItems[1])
+	}
+
+	facilitatorItems := userVisibleItems(dataSvc.GetRetroItems(testRetroID), "facilitator", true, false, &activeItemID)
+	for _, item := range facilitatorItems {
+		if item.Redacted || item.Content == "" {
+			t.Errorf("expected facilitator to receive item %s in full, got %+v", item.ID, item)
+		}
+	}
+}
+
+// TestUserVisibleItemFocus makes sure moved items are redacted for participants while the retro is focused on another item
+func TestUserVisibleItemFocus(t *testing.T) {
+	activeItemID := "item-2"
+	groupID := "group-2"
+	item := &thunderdome.RetroItem{ID: "item-1", UserID: "author", GroupID: &groupID, Content: "went well", Comments: []*thunderdome.RetroItemComment{}}
+
+	visible := userVisibleItem(item, "participant", false, false, &activeItemID)
+	if visible == nil || !visible.Redacted || visible.Content != "" {
+		t.Fatalf("expected participant to receive redacted item, got %+v", visible)
+	}
+	if visible.GroupID == nil || *visible.GroupID != groupID {
+		t.Errorf("expected redacted item to keep its group, got %v", visible.GroupID)
+	}
+
+	if visible := userVisibleItem(item, "facilitator", true, false, &activeItemID); visible != item {
+		t.Errorf("expected facilitator to receive item in full, got %+v", visible)
+	}
+}
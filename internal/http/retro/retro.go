@@ -3,6 +3,8 @@ package retro
 import (
 	"context"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/StevenWeathers/thunderdome-planning-poker/internal/wshub"
 	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
@@ -24,6 +26,16 @@ type Config struct {
 
 	// Websocket Subdomain (for Websocket origin check)
 	WebsocketSubdomain string
+
+	// Time allowed for connections to be closed cleanly on shutdown
+	ShutdownGracePeriodSec int
+	// Websocket read buffer size in bytes
+	ReadBufferSize int
+	// Websocket write buffer size in bytes
+	WriteBufferSize int
+
+	// Store for messages to replay to clients reconnecting after a shutdown
+	ReplayStore wshub.ReplayStore
 }
 
 type AuthDataSvc interface {
@@ -50,17 +62,32 @@ type RetroDataSvc interface {
 	GetRetroFacilitatorCode(retroID string) (string, error)
 	MarkUserReady(retroID string, userID string) ([]string, error)
 	UnmarkUserReady(retroID string, userID string) ([]string, error)
+	GetRetroFacilitators(retroID string) []string
+	GetRetroSubmissionPhase(retroID string) (bool, *time.Time, error)
+	OpenRetroSession(ctx context.Context, retroID string) error
+	SetFocusItem(ctx context.Context, retroID string, itemID string) error
+	GetRetroFocusItem(retroID string) (*string, error)
+	StartCategoryTimer(ctx context.Context, retroID string, categoryID string, durationSeconds int) (*thunderdome.RetroTimer, error)
+	PauseCategoryTimer(ctx context.Context, retroID string, categoryID string) (*thunderdome.RetroTimer, error)
+	SubscribeTimerEvents(ctx context.Context) <-chan thunderdome.RetroTimerEvent
 
 	CreateRetroAction(retroID string, userID string, content string) ([]*thunderdome.RetroAction, error)
 	UpdateRetroAction(retroID string, actionID string, content string, completed bool) (Actions []*thunderdome.RetroAction, DeleteError error)
 	DeleteRetroAction(retroID string, userID string, actionID string) ([]*thunderdome.RetroAction, error)
+	GetRetroActions(retroID string) []*thunderdome.RetroAction
 	RetroActionAssigneeAdd(retroID string, actionID string, userID string) ([]*thunderdome.RetroAction, error)
 	RetroActionAssigneeDelete(retroID string, actionID string, userID string) ([]*thunderdome.RetroAction, error)
 
 	CreateRetroItem(retroID string, userID string, itemType string, content string) ([]*thunderdome.RetroItem, error)
 	GroupRetroItem(retroID string, itemId string, groupId string) (thunderdome.RetroItem, error)
 	DeleteRetroItem(retroID string, userID string, itemType string, itemID string) ([]*thunderdome.RetroItem, error)
+	GetRetroItems(retroID string) []*thunderdome.RetroItem
+	TagItemEmotion(ctx context.Context, retroID string, itemID string, emotion string) error
 	GroupNameChange(retroID string, groupID string, name string) (thunderdome.RetroGroup, error)
+	CreateGroup(retroID string, name string, color string) ([]*thunderdome.RetroGroup, error)
+	AddItemToGroup(retroID string, groupID string, itemID string) ([]*thunderdome.RetroGroup, error)
+	RemoveItemFromGroup(retroID string, groupID string, itemID string) ([]*thunderdome.RetroGroup, error)
+	DeleteGroup(retroID string, groupID string) ([]*thunderdome.RetroGroup, error)
 	GroupUserVote(retroID string, groupID string, userID string) ([]*thunderdome.RetroVote, error)
 	GroupUserSubtractVote(retroID string, groupID string, userID string) ([]*thunderdome.RetroVote, error)
 	ItemCommentAdd(retroID string, itemID string, userID string, comment string) ([]*thunderdome.RetroItem, error)
@@ -76,6 +103,15 @@ type RetroTemplateDataSvc interface {
 type EmailService interface {
 	// SendRetroOverview sends the retro overview (items, action items) email to attendees
 	SendRetroOverview(retro *thunderdome.Retro, template *thunderdome.RetroTemplate, userName string, userEmail string) error
+	// SendRetroActionAssigned sends the retro action item assigned email to the assignee
+	SendRetroActionAssigned(retro *thunderdome.Retro, action *thunderdome.RetroAction, userName string, userEmail string) error
+	// SendRetroActionUnassigned sends the retro action item unassigned email to the previous assignee
+	SendRetroActionUnassigned(retro *thunderdome.Retro, action *thunderdome.RetroAction, userName string, userEmail string) error
+}
+
+// Notifier creates in-app notifications for users
+type Notifier interface {
+	NotifyUser(ctx context.Context, userID string, notificationType string, payload any)
 }
 
 // Service provides retro service
@@ -89,7 +125,10 @@ type Service struct {
 	RetroService          RetroDataSvc
 	TemplateService       RetroTemplateDataSvc
 	EmailService          EmailService
+	Notifier              Notifier
 	hub                   *wshub.Hub
+	submissionDeadlines   sync.Map
+	timers                *categoryTimers
 }
 
 // New returns a new retro with websocket hub/client and event handlers
@@ -100,7 +139,7 @@ func New(
 	validateUserCookie func(w http.ResponseWriter, r *http.Request) (string, error),
 	userService UserDataSvc, authService AuthDataSvc,
 	retroService RetroDataSvc, templateService RetroTemplateDataSvc,
-	emailService EmailService,
+	emailService EmailService, notifier Notifier,
 ) *Service {
 	rs := &Service{
 		config:                config,
@@ -112,20 +151,29 @@ func New(
 		RetroService:          retroService,
 		TemplateService:       templateService,
 		EmailService:          emailService,
+		Notifier:              notifier,
 	}
 
 	rs.hub = wshub.NewHub(logger, wshub.Config{
-		AppDomain:          config.AppDomain,
-		WebsocketSubdomain: config.WebsocketSubdomain,
-		WriteWaitSec:       config.WriteWaitSec,
-		PongWaitSec:        config.PongWaitSec,
-		PingPeriodSec:      config.PingPeriodSec,
+		AppDomain:              config.AppDomain,
+		WebsocketSubdomain:     config.WebsocketSubdomain,
+		ShutdownGracePeriodSec: config.ShutdownGracePeriodSec,
+		ReadBufferSize:         config.ReadBufferSize,
+		WriteBufferSize:        config.WriteBufferSize,
+		ReplayStore:            config.ReplayStore,
+		WriteWaitSec:           config.WriteWaitSec,
+		PongWaitSec:            config.PongWaitSec,
+		PingPeriodSec:          config.PingPeriodSec,
 	}, map[string]func(context.Context, string, string, string) ([]byte, error, bool){
 		"create_item":            rs.CreateItem,
 		"user_ready":             rs.UserMarkReady,
 		"user_unready":           rs.UserUnMarkReady,
 		"group_item":             rs.GroupItem,
 		"group_name_change":      rs.GroupNameChange,
+		"group_create":           rs.GroupCreate,
+		"group_item_add":         rs.GroupItemAdd,
+		"group_item_remove":      rs.GroupItemRemove,
+		"group_delete":           rs.GroupDelete,
 		"group_vote":             rs.GroupUserVote,
 		"group_vote_subtract":    rs.GroupUserSubtractVote,
 		"delete_item":            rs.DeleteItem,
@@ -146,6 +194,11 @@ func New(
 		"edit_retro":             rs.EditRetro,
 		"concede_retro":          rs.Delete,
 		"abandon_retro":          rs.Abandon,
+		"open_retro":             rs.OpenRetro,
+		"timer_start":            rs.TimerStart,
+		"timer_pause":            rs.TimerPause,
+		"focus_item":             rs.FocusItem,
+		"tag_item_emotion":       rs.TagItemEmotion,
 	},
 		map[string]struct{}{
 			"advance_phase":      {},
@@ -155,12 +208,27 @@ func New(
 			"concede_retro":      {},
 			"phase_time_ran_out": {},
 			"phase_all_ready":    {},
+			"group_create":       {},
+			"group_item_add":     {},
+			"group_item_remove":  {},
+			"group_delete":       {},
+			"open_retro":         {},
+			"timer_start":        {},
+			"timer_pause":        {},
+			"focus_item":         {},
 		},
 		rs.RetroService.RetroConfirmFacilitator,
 		rs.RetreatUser,
 	)
 
+	rs.timers = newCategoryTimers(realClock{}, func(retroID string, event []byte) {
+		if rs.hub.RoomExists(retroID) {
+			rs.hub.Broadcast(wshub.Message{Data: event, Room: retroID})
+		}
+	})
+
 	go rs.hub.Run()
+	go rs.relayTimerEvents(context.Background())
 
 	return rs
 }
@@ -0,0 +1,208 @@
+package retro
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/wshub"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+// OpenRetro ends the retro submission phase revealing all items to everyone
+func (b *Service) OpenRetro(ctx context.Context, RetroID string, UserID string, EventValue string) ([]byte, error, bool) {
+	if err := b.RetroService.OpenRetroSession(ctx, RetroID); err != nil {
+		return nil, err, false
+	}
+
+	return b.sessionOpenedEvent(RetroID), nil, false
+}
+
+// sessionOpenedEvent creates the retro_session_opened event with all the retro items
+func (b *Service) sessionOpenedEvent(RetroID string) []byte {
+	items := b.RetroService.GetRetroItems(RetroID)
+	updatedItems, _ := json.Marshal(items)
+
+	return wshub.CreateSocketEvent("retro_session_opened", string(updatedItems), "")
+}
+
+// openSessionAtDeadline opens the retro session and notifies the room once the submission deadline passes
+func (b *Service) openSessionAtDeadline(RetroID string) {
+	if err := b.RetroService.OpenRetroSession(context.Background(), RetroID); err != nil {
+		// session was already opened by a facilitator
+		return
+	}
+
+	if b.hub.RoomExists(RetroID) {
+		b.hub.Broadcast(wshub.Message{Data: b.sessionOpenedEvent(RetroID), Room: RetroID})
+	}
+}
+
+// scheduleSubmissionDeadline opens the retro session when its submission deadline passes,
+// only one timer is scheduled per retro regardless of how many users join
+func (b *Service) scheduleSubmissionDeadline(RetroID string, deadline time.Time) {
+	if _, scheduled := b.submissionDeadlines.LoadOrStore(RetroID, struct{}{}); scheduled {
+		return
+	}
+
+	time.AfterFunc(time.Until(deadline), func() {
+		b.submissionDeadlines.Delete(RetroID)
+		b.openSessionAtDeadline(RetroID)
+	})
+}
+
+// submissionPhaseActive checks whether the retro is still in its submission phase,
+// opening the session when the deadline has passed without the timer having fired yet
+func (b *Service) submissionPhaseActive(RetroID string) bool {
+	submissionPhase, deadline, err := b.RetroService.GetRetroSubmissionPhase(RetroID)
+	if err != nil {
+		b.logger.Error("get retro submission phase error", zap.Error(err), zap.String("retro_id", RetroID))
+		return false
+	}
+
+	if thunderdome.SubmissionPhaseActive(submissionPhase, deadline, time.Now()) {
+		return true
+	}
+	if submissionPhase {
+		b.openSessionAtDeadline(RetroID)
+	}
+
+	return false
+}
+
+// itemsUpdatedEvent creates the items_updated event, during the submission phase or while the retro
+// is focused on an item each user is instead sent only the items they are allowed to see
+func (b *Service) itemsUpdatedEvent(RetroID string, items []*thunderdome.RetroItem) []byte {
+	submissionPhase := b.submissionPhaseActive(RetroID)
+	activeItemID := b.focusItem(RetroID)
+	if !submissionPhase && activeItemID == nil {
+		updatedItems, _ := json.Marshal(items)
+		return wshub.CreateSocketEvent("items_updated", string(updatedItems), "")
+	}
+
+	facilitators := b.RetroService.GetRetroFacilitators(RetroID)
+	b.hub.Broadcast(wshub.Message{
+		Room: RetroID,
+		UserData: func(userID string) []byte {
+			visibleItems := userVisibleItems(items, userID, isRetroFacilitator(facilitators, userID), submissionPhase, activeItemID)
+			updatedItems, _ := json.Marshal(visibleItems)
+			return wshub.CreateSocketEvent("items_updated", string(updatedItems), "")
+		},
+	})
+
+	return nil
+}
+
+// groupUpdatedEvent creates the retro_group_updated event with the groups and their items, during the submission
+// phase or while the retro is focused on an item each user is instead sent only the items they are allowed to see
+func (b *Service) groupUpdatedEvent(RetroID string, groups []*thunderdome.RetroGroup) ([]byte, error, bool) {
+	items := b.RetroService.GetRetroItems(RetroID)
+	submissionPhase := b.submissionPhaseActive(RetroID)
+	activeItemID := b.focusItem(RetroID)
+	if !submissionPhase && activeItemID == nil {
+		return createGroupUpdatedEvent(groups, items), nil, false
+	}
+
+	facilitators := b.RetroService.GetRetroFacilitators(RetroID)
+	b.hub.Broadcast(wshub.Message{
+		Room: RetroID,
+		UserData: func(userID string) []byte {
+			visibleItems := userVisibleItems(items, userID, isRetroFacilitator(facilitators, userID), submissionPhase, activeItemID)
+			return createGroupUpdatedEvent(groups, visibleItems)
+		},
+	})
+
+	return nil, nil, false
+}
+
+func createGroupUpdatedEvent(groups []*thunderdome.RetroGroup, items []*thunderdome.RetroItem) []byte {
+	updatedGroups, _ := json.Marshal(map[string]interface{}{
+		"groups": groups,
+		"items":  items,
+	})
+
+	return wshub.CreateSocketEvent("retro_group_updated", string(updatedGroups), "")
+}
+
+// itemMovedEvent creates the item_moved event, during the submission phase or while the retro is focused
+// on an item each user is instead sent the item as they are allowed to see it, if at all
+func (b *Service) itemMovedEvent(RetroID string, item *thunderdome.RetroItem) []byte {
+	submissionPhase := b.submissionPhaseActive(RetroID)
+	activeItemID := b.focusItem(RetroID)
+	if !submissionPhase && activeItemID == nil {
+		return createItemMovedEvent(item)
+	}
+
+	facilitators := b.RetroService.GetRetroFacilitators(RetroID)
+	b.hub.Broadcast(wshub.Message{
+		Room: RetroID,
+		UserData: func(userID string) []byte {
+			visibleItem := userVisibleItem(item, userID, isRetroFacilitator(facilitators, userID), submissionPhase, activeItemID)
+			if visibleItem == nil {
+				return nil
+			}
+			return createItemMovedEvent(visibleItem)
+		},
+	})
+
+	return nil
+}
+
+func createItemMovedEvent(item *thunderdome.RetroItem) []byte {
+	updatedItem, _ := json.Marshal(item)
+
+	return wshub.CreateSocketEvent("item_moved", string(updatedItem), "")
+}
+
+// userVisibleItem returns the retro item as the user may see it, or nil when it's hidden from them
+func userVisibleItem(item *thunderdome.RetroItem, userID string, isFacilitator bool, submissionPhase bool, activeItemID *string) *thunderdome.RetroItem {
+	visibleItems := userVisibleItems([]*thunderdome.RetroItem{item}, userID, isFacilitator, submissionPhase, activeItemID)
+	if len(visibleItems) == 0 {
+		return nil
+	}
+
+	return visibleItems[0]
+}
+
+// userVisibleItems returns the retro items the user may see given the submission phase and item focus
+func userVisibleItems(items []*thunderdome.RetroItem, userID string, isFacilitator bool, submissionPhase bool, activeItemID *string) []*thunderdome.RetroItem {
+	if submissionPhase {
+		items = thunderdome.SubmissionVisibleItems(items, userID, isFacilitator)
+	}
+
+	return thunderdome.FocusVisibleItems(items, activeItemID, isFacilitator)
+}
+
+// phaseUpdatedEvent creates the phase_updated event, during the submission phase or while the retro
+// is focused on an item each user is instead sent only the items they are allowed to see
+func (b *Service) phaseUpdatedEvent(retro *thunderdome.Retro) []byte {
+	submissionPhase := thunderdome.SubmissionPhaseActive(retro.SubmissionPhase, retro.SubmissionDeadline, time.Now())
+	if !submissionPhase && retro.ActiveItemID == nil {
+		updatedRetro, _ := json.Marshal(retro)
+		return wshub.CreateSocketEvent("phase_updated", string(updatedRetro), "")
+	}
+
+	facilitators := b.RetroService.GetRetroFacilitators(retro.ID)
+	b.hub.Broadcast(wshub.Message{
+		Room: retro.ID,
+		UserData: func(userID string) []byte {
+			userRetro := *retro
+			userRetro.Items = userVisibleItems(retro.Items, userID, isRetroFacilitator(facilitators, userID), submissionPhase, retro.ActiveItemID)
+			updatedRetro, _ := json.Marshal(userRetro)
+			return wshub.CreateSocketEvent("phase_updated", string(updatedRetro), "")
+		},
+	})
+
+	return nil
+}
+
+func isRetroFacilitator(facilitators []string, userID string) bool {
+	for _, facilitatorID := range facilitators {
+		if facilitatorID == userID {
+			return true
+		}
+	}
+
+	return false
+}
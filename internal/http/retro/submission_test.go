@@ -0,0 +1,46 @@
+package retro
+
+import (
+	"testing"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// TestUserVisibleItemSubmission makes sure moved items are only sent to users allowed to see them during the submission phase
+func TestUserVisibleItemSubmission(t *testing.T) {
+	item := &thunderdome.RetroItem{ID: "item-1", UserID: "author", Content: "went well", Comments: []*thunderdome.RetroItemComment{}}
+
+	if visible := userVisibleItem(item, "participant", false, true, nil); visible != nil {
+		t.Errorf("expected item to be hidden from participant, got %+v", visible)
+	}
+
+	if visible := userVisibleItem(item, "author", false, true, nil); visible == nil || visible.UserID != "author" {
+		t.Errorf("expected author to receive their own item, got %+v", visible)
+	}
+
+	visible := userVisibleItem(item, "facilitator", true, true, nil)
+	if visible == nil || visible.Content != "went well" {
+		t.Fatalf("expected facilitator to receive item content, got %+v", visible)
+	}
+	if visible.UserID != "" {
+		t.Errorf("expected facilitator to receive anonymous item, got author %s", visible.UserID)
+	}
+
+	if visible := userVisibleItem(item, "participant", false, false, nil); visible != item {
+		t.Errorf("expected item to be visible once the submission phase ends, got %+v", visible)
+	}
+}
+
+// TestGroupUpdatedEventOpenRetro makes sure group updates are sent to the whole room outside the submission phase
+func TestGroupUpdatedEventOpenRetro(t *testing.T) {
+	dataSvc := &fakeFocusRetroDataSvc{}
+	b := newFocusTestService(dataSvc)
+
+	msg, err, _ := b.groupUpdatedEvent(testRetroID, []*thunderdome.RetroGroup{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if msg == nil {
+		t.Error("expected retro_group_updated event to be sent to the whole room")
+	}
+}
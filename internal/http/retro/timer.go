@@ -0,0 +1,197 @@
+package retro
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/wshub"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// timerTickInterval is how often running category timers send their remaining time to participants
+const timerTickInterval = time.Second
+
+// clock provides the current time and tickers so timers can be tested without waiting
+type clock interface {
+	Now() time.Time
+	NewTicker(d time.Duration) (<-chan time.Time, func())
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) NewTicker(d time.Duration) (<-chan time.Time, func()) {
+	ticker := time.NewTicker(d)
+	return ticker.C, ticker.Stop
+}
+
+type runningTimer struct {
+	timer thunderdome.RetroTimer
+	stop  chan struct{}
+}
+
+// categoryTimers relays the running retro category timers to participants every tick
+type categoryTimers struct {
+	clock     clock
+	broadcast func(retroID string, event []byte)
+	mu        sync.Mutex
+	running   map[string]*runningTimer
+}
+
+func newCategoryTimers(clock clock, broadcast func(retroID string, event []byte)) *categoryTimers {
+	return &categoryTimers{
+		clock:     clock,
+		broadcast: broadcast,
+		running:   make(map[string]*runningTimer),
+	}
+}
+
+func timerKey(retroID string, categoryID string) string {
+	return retroID + ":" + categoryID
+}
+
+// start relays the timer until it runs out or is stopped, replacing any other run of the category's timer
+func (ct *categoryTimers) start(retroID string, timer thunderdome.RetroTimer) {
+	if !timer.Running(ct.clock.Now()) {
+		ct.stop(retroID, timer.CategoryID)
+		return
+	}
+
+	key := timerKey(retroID, timer.CategoryID)
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+
+	if existing, ok := ct.running[key]; ok {
+		// the same timer is already being relayed, e.g. when the instance receives its own published event
+		if existing.timer.DurationSeconds == timer.DurationSeconds && existing.timer.StartedAt.Equal(*timer.StartedAt) {
+			return
+		}
+		close(existing.stop)
+	}
+
+	rt := &runningTimer{timer: timer, stop: make(chan struct{})}
+	ct.running[key] = rt
+	ticks, stopTicker := ct.clock.NewTicker(timerTickInterval)
+
+	go ct.run(retroID, rt, ticks, stopTicker)
+}
+
+// stop stops relaying the category's timer
+func (ct *categoryTimers) stop(retroID string, categoryID string) {
+	key := timerKey(retroID, categoryID)
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+
+	if existing, ok := ct.running[key]; ok {
+		close(existing.stop)
+		delete(ct.running, key)
+	}
+}
+
+// run sends the timer's remaining time every tick, once it runs out the category time up event is sent
+func (ct *categoryTimers) run(retroID string, rt *runningTimer, ticks <-chan time.Time, stopTicker func()) {
+	defer stopTicker()
+
+	for {
+		select {
+		case <-rt.stop:
+			return
+		case <-ticks:
+			remaining := rt.timer.Remaining(ct.clock.Now())
+			if remaining > 0 {
+				ct.broadcast(retroID, timerTickEvent(rt.timer, remaining))
+				continue
+			}
+
+			ct.mu.Lock()
+			if ct.running[timerKey(retroID, rt.timer.CategoryID)] == rt {
+				delete(ct.running, timerKey(retroID, rt.timer.CategoryID))
+			}
+			ct.mu.Unlock()
+
+			timeUp, _ := json.Marshal(rt.timer)
+			ct.broadcast(retroID, wshub.CreateSocketEvent("category_time_up", string(timeUp), ""))
+			return
+		}
+	}
+}
+
+func timerTickEvent(timer thunderdome.RetroTimer, remaining time.Duration) []byte {
+	tick, _ := json.Marshal(struct {
+		CategoryID       string `json:"categoryId"`
+		RemainingSeconds int    `json:"remainingSeconds"`
+	}{
+		CategoryID:       timer.CategoryID,
+		RemainingSeconds: int(math.Ceil(remaining.Seconds())),
+	})
+
+	return wshub.CreateSocketEvent("timer_tick", string(tick), "")
+}
+
+// relayTimerEvents starts and stops relaying timers changed by other instances
+func (b *Service) relayTimerEvents(ctx context.Context) {
+	events := b.RetroService.SubscribeTimerEvents(ctx)
+	if events == nil {
+		return
+	}
+
+	for event := range events {
+		switch event.Type {
+		case thunderdome.RetroTimerEventStart:
+			b.timers.start(event.RetroID, event.Timer)
+		case thunderdome.RetroTimerEventPause:
+			b.timers.stop(event.RetroID, event.Timer.CategoryID)
+		}
+	}
+}
+
+// TimerStart starts or resumes a retro category timer
+func (b *Service) TimerStart(ctx context.Context, RetroID string, UserID string, EventValue string) ([]byte, error, bool) {
+	var rs struct {
+		CategoryID      string `json:"categoryId"`
+		DurationSeconds int    `json:"durationSeconds"`
+	}
+	err := json.Unmarshal([]byte(EventValue), &rs)
+	if err != nil {
+		return nil, err, false
+	}
+
+	timer, err := b.RetroService.StartCategoryTimer(ctx, RetroID, rs.CategoryID, rs.DurationSeconds)
+	if err != nil {
+		return nil, err, false
+	}
+	b.timers.start(RetroID, *timer)
+
+	updatedTimer, _ := json.Marshal(timer)
+	msg := wshub.CreateSocketEvent("timer_started", string(updatedTimer), "")
+
+	return msg, nil, false
+}
+
+// TimerPause pauses a running retro category timer
+func (b *Service) TimerPause(ctx context.Context, RetroID string, UserID string, EventValue string) ([]byte, error, bool) {
+	var rs struct {
+		CategoryID string `json:"categoryId"`
+	}
+	err := json.Unmarshal([]byte(EventValue), &rs)
+	if err != nil {
+		return nil, err, false
+	}
+
+	timer, err := b.RetroService.PauseCategoryTimer(ctx, RetroID, rs.CategoryID)
+	if err != nil {
+		return nil, err, false
+	}
+	b.timers.stop(RetroID, rs.CategoryID)
+
+	updatedTimer, _ := json.Marshal(timer)
+	msg := wshub.CreateSocketEvent("timer_paused", string(updatedTimer), "")
+
+	return msg, nil, false
+}
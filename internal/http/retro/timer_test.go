@@ -0,0 +1,159 @@
+package retro
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// mockClock only moves forward and ticks when the test says so
+type mockClock struct {
+	mu    sync.Mutex
+	now   time.Time
+	ticks chan time.Time
+}
+
+func newMockClock(now time.Time) *mockClock {
+	return &mockClock{now: now, ticks: make(chan time.Time)}
+}
+
+func (c *mockClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *mockClock) NewTicker(d time.Duration) (<-chan time.Time, func()) {
+	return c.ticks, func() {}
+}
+
+// tick advances the clock by a second and waits for the timer to receive the tick
+func (c *mockClock) tick(t *testing.T) {
+	t.Helper()
+	c.mu.Lock()
+	c.now = c.now.Add(time.Second)
+	now := c.now
+	c.mu.Unlock()
+
+	select {
+	case c.ticks <- now:
+	case <-time.After(time.Second):
+		t.Fatal("expected the timer to receive the tick")
+	}
+}
+
+type timerEvent struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+func newTestCategoryTimers(clock clock) (*categoryTimers, chan timerEvent) {
+	events := make(chan timerEvent, 10)
+	ct := newCategoryTimers(clock, func(retroID string, event []byte) {
+		var e timerEvent
+		_ = json.Unmarshal(event, &e)
+		events <- e
+	})
+
+	return ct, events
+}
+
+func nextTimerEvent(t *testing.T, events chan timerEvent) timerEvent {
+	t.Helper()
+	select {
+	case e := <-events:
+		return e
+	case <-time.After(time.Second):
+		t.Fatal("expected a timer event")
+	}
+
+	return timerEvent{}
+}
+
+func TestCategoryTimerTicksUntilTimeUp(t *testing.T) {
+	start := time.Date(2025, 3, 15, 14, 0, 0, 0, time.UTC)
+	clock := newMockClock(start)
+	ct, events := newTestCategoryTimers(clock)
+
+	ct.start(testRetroID, thunderdome.RetroTimer{CategoryID: "worked", DurationSeconds: 3, StartedAt: &start})
+
+	for _, expectedRemaining := range []int{2, 1} {
+		clock.tick(t)
+		e := nextTimerEvent(t, events)
+		if e.Type != "timer_tick" {
+			t.Fatalf("expected timer_tick, got %s", e.Type)
+		}
+		var tick struct {
+			CategoryID       string `json:"categoryId"`
+			RemainingSeconds int    `json:"remainingSeconds"`
+		}
+		if err := json.Unmarshal([]byte(e.Value), &tick); err != nil {
+			t.Fatalf("unexpected tick json error: %v", err)
+		}
+		if tick.CategoryID != "worked" || tick.RemainingSeconds != expectedRemaining {
+			t.Errorf("expected worked with %ds remaining, got %s with %ds", expectedRemaining, tick.CategoryID, tick.RemainingSeconds)
+		}
+	}
+
+	clock.tick(t)
+	if e := nextTimerEvent(t, events); e.Type != "category_time_up" {
+		t.Fatalf("expected category_time_up at expiry, got %s", e.Type)
+	}
+
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+	if len(ct.running) != 0 {
+		t.Errorf("expected no running timers after time up, got %d", len(ct.running))
+	}
+	if len(events) != 0 {
+		t.Errorf("expected 2 timer_tick and 1 category_time_up events, got %d more", len(events))
+	}
+}
+
+func TestCategoryTimerStopsWhenPaused(t *testing.T) {
+	start := time.Date(2025, 3, 15, 14, 0, 0, 0, time.UTC)
+	clock := newMockClock(start)
+	ct, events := newTestCategoryTimers(clock)
+
+	ct.start(testRetroID, thunderdome.RetroTimer{CategoryID: "improve", DurationSeconds: 60, StartedAt: &start})
+	clock.tick(t)
+	if e := nextTimerEvent(t, events); e.Type != "timer_tick" {
+		t.Fatalf("expected timer_tick, got %s", e.Type)
+	}
+
+	ct.stop(testRetroID, "improve")
+
+	select {
+	case clock.ticks <- clock.Now():
+		t.Fatal("expected a paused timer to stop receiving ticks")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestCategoryTimerIgnoresDuplicateStart(t *testing.T) {
+	start := time.Date(2025, 3, 15, 14, 0, 0, 0, time.UTC)
+	clock := newMockClock(start)
+	ct, _ := newTestCategoryTimers(clock)
+	timer := thunderdome.RetroTimer{CategoryID: "worked", DurationSeconds: 60, StartedAt: &start}
+	key := timerKey(testRetroID, "worked")
+
+	ct.start(testRetroID, timer)
+	ct.mu.Lock()
+	first := ct.running[key]
+	ct.mu.Unlock()
+
+	ct.start(testRetroID, timer)
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+	if ct.running[key] != first {
+		t.Error("expected starting the same timer again to keep the running timer")
+	}
+	select {
+	case <-first.stop:
+		t.Error("expected the running timer not to be stopped")
+	default:
+	}
+}
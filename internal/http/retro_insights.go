@@ -0,0 +1,84 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/http/ai"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// handleRetroInsightsGenerate handles getting the retro's AI generated insights
+//
+//	@Summary		Generate Retro Insights
+//	@Description	Gets the key themes, action items and mood of the retro's items as summarized by AI,
+//	@Description	insights are generated again once new items are added after they were generated
+//	@Tags			retro
+//	@Produce		json
+//	@Param			retroId	path	string	true	"the retro ID to generate insights for"
+//	@Success		200		object	standardJsonResponse{data=thunderdome.RetroInsights}
+//	@Failure		400		object	standardJsonResponse{}
+//	@Failure		403		object	standardJsonResponse{}
+//	@Failure		500		object	standardJsonResponse{}
+//	@Failure		502		object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/retros/{retroId}/insights [post]
+func (s *Service) handleRetroInsightsGenerate(aiSvc *ai.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		retroID := vars["retroId"]
+		idErr := validate.Var(retroID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		userType := ctx.Value(contextKeyUserType).(string)
+
+		if userType != thunderdome.AdminUserType {
+			if err := s.RetroDataSvc.RetroConfirmFacilitator(retroID, sessionUserID); err != nil {
+				s.Failure(w, r, http.StatusForbidden, Errorf(EUNAUTHORIZED, "REQUIRES_FACILITATOR"))
+				return
+			}
+		}
+
+		insights, err := s.RetroDataSvc.GetRetroInsights(ctx, retroID)
+		if err == nil {
+			s.Success(w, r, http.StatusOK, insights, nil)
+			return
+		}
+		if err.Error() != "RETRO_INSIGHTS_NOT_FOUND" {
+			s.Logger.Ctx(ctx).Error("handleRetroInsightsGenerate get insights error", zap.Error(err),
+				zap.String("retro_id", retroID), zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		items := s.RetroDataSvc.GetRetroItems(retroID)
+		if len(items) == 0 {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "RETRO_HAS_NO_ITEMS"))
+			return
+		}
+
+		insights, err = aiSvc.ExtractRetroInsights(ctx, items)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleRetroInsightsGenerate extract insights error", zap.Error(err),
+				zap.String("retro_id", retroID), zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusBadGateway, Errorf(EINTERNAL, "AI_REQUEST_FAILED"))
+			return
+		}
+
+		insights, err = s.RetroDataSvc.SaveRetroInsights(ctx, retroID, insights)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleRetroInsightsGenerate save insights error", zap.Error(err),
+				zap.String("retro_id", retroID), zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, insights, nil)
+	}
+}
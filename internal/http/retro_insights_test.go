@@ -0,0 +1,151 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/http/ai"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+const testRetroID = "923e4567-e89b-12d3-a456-426614174000"
+
+func (m *MockRetroDataSvc) RetroConfirmFacilitator(retroID string, userID string) error {
+	args := m.Called(retroID, userID)
+	return args.Error(0)
+}
+
+func (m *MockRetroDataSvc) GetRetroItems(retroID string) []*thunderdome.RetroItem {
+	args := m.Called(retroID)
+	return args.Get(0).([]*thunderdome.RetroItem)
+}
+
+func (m *MockRetroDataSvc) GetRetroInsights(ctx context.Context, retroID string) (*thunderdome.RetroInsights, error) {
+	args := m.Called(ctx, retroID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*thunderdome.RetroInsights), args.Error(1)
+}
+
+func (m *MockRetroDataSvc) SaveRetroInsights(ctx context.Context, retroID string, insights *thunderdome.RetroInsights) (*thunderdome.RetroInsights, error) {
+	args := m.Called(ctx, retroID, insights)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*thunderdome.RetroInsights), args.Error(1)
+}
+
+// mockAIProvider replies to every prompt with the same generated text
+type mockAIProvider struct {
+	content string
+	calls   int
+}
+
+func (p *mockAIProvider) Name() string {
+	return "mock"
+}
+
+func (p *mockAIProvider) SuggestPoints(ctx context.Context, prompt string, availablePoints []string) (*ai.PointSuggestionResponse, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (p *mockAIProvider) Generate(ctx context.Context, prompt string) (string, error) {
+	p.calls++
+	return p.content, nil
+}
+
+func retroInsightsRequest(service *Service, aiSvc *ai.Service, userID string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/retros/"+testRetroID+"/insights", nil)
+	req = mux.SetURLVars(req, map[string]string{"retroId": testRetroID})
+	req = req.WithContext(context.WithValue(req.Context(), contextKeyUserID, userID))
+	req = req.WithContext(context.WithValue(req.Context(), contextKeyUserType, thunderdome.RegisteredUserType))
+	rr := httptest.NewRecorder()
+	service.handleRetroInsightsGenerate(aiSvc).ServeHTTP(rr, req)
+
+	return rr
+}
+
+// TestHandleRetroInsightsGenerate makes sure insights are generated by the AI provider and saved
+// when there are none for the retro's current items
+func TestHandleRetroInsightsGenerate(t *testing.T) {
+	generatedAt := time.Date(2025, 3, 16, 12, 0, 0, 0, time.UTC)
+	provider := &mockAIProvider{content: `{"themes": ["Slow deploys", "Pairing", "Requirements churn"],
+		"actionItems": ["Speed up the pipeline"], "moodSummary": "Optimistic"}`}
+	aiSvc := &ai.Service{Chain: ai.NewFallbackChain([]ai.AIProvider{provider}, nil)}
+
+	mockRetroDataSvc := new(MockRetroDataSvc)
+	mockRetroDataSvc.On("RetroConfirmFacilitator", testRetroID, testFacilitatorID).Return(nil)
+	mockRetroDataSvc.On("GetRetroInsights", mock.Anything, testRetroID).Return(nil, fmt.Errorf("RETRO_INSIGHTS_NOT_FOUND"))
+	mockRetroDataSvc.On("GetRetroItems", testRetroID).Return([]*thunderdome.RetroItem{
+		{Type: "improve", Content: "Deploys take an hour"},
+		{Type: "worked", Content: "Pairing on the migration"},
+	})
+	mockRetroDataSvc.On("SaveRetroInsights", mock.Anything, testRetroID, mock.MatchedBy(func(insights *thunderdome.RetroInsights) bool {
+		return len(insights.TopThemes) == 3 && len(insights.KeyActionItems) == 1 && insights.MoodSummary == "Optimistic"
+	})).Return(&thunderdome.RetroInsights{
+		RetroID:        testRetroID,
+		TopThemes:      []string{"Slow deploys", "Pairing", "Requirements churn"},
+		KeyActionItems: []string{"Speed up the pipeline"},
+		MoodSummary:    "Optimistic",
+		GeneratedAt:    generatedAt,
+	}, nil)
+	service := &Service{RetroDataSvc: mockRetroDataSvc, Logger: otelzap.New(zap.NewNop())}
+
+	rr := retroInsightsRequest(service, aiSvc, testFacilitatorID)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var body struct {
+		Data thunderdome.RetroInsights `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+	assert.Equal(t, []string{"Slow deploys", "Pairing", "Requirements churn"}, body.Data.TopThemes)
+	assert.Equal(t, []string{"Speed up the pipeline"}, body.Data.KeyActionItems)
+	assert.Equal(t, "Optimistic", body.Data.MoodSummary)
+	assert.Equal(t, 1, provider.calls)
+	mockRetroDataSvc.AssertExpectations(t)
+}
+
+// TestHandleRetroInsightsGenerateUpToDate makes sure insights still matching the retro's items aren't regenerated
+func TestHandleRetroInsightsGenerateUpToDate(t *testing.T) {
+	provider := &mockAIProvider{}
+	aiSvc := &ai.Service{Chain: ai.NewFallbackChain([]ai.AIProvider{provider}, nil)}
+
+	mockRetroDataSvc := new(MockRetroDataSvc)
+	mockRetroDataSvc.On("RetroConfirmFacilitator", testRetroID, testFacilitatorID).Return(nil)
+	mockRetroDataSvc.On("GetRetroInsights", mock.Anything, testRetroID).Return(&thunderdome.RetroInsights{
+		RetroID:   testRetroID,
+		TopThemes: []string{"Slow deploys"},
+	}, nil)
+	service := &Service{RetroDataSvc: mockRetroDataSvc, Logger: otelzap.New(zap.NewNop())}
+
+	rr := retroInsightsRequest(service, aiSvc, testFacilitatorID)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, 0, provider.calls)
+	mockRetroDataSvc.AssertNotCalled(t, "SaveRetroInsights", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHandleRetroInsightsGenerateRequiresFacilitator(t *testing.T) {
+	provider := &mockAIProvider{}
+	aiSvc := &ai.Service{Chain: ai.NewFallbackChain([]ai.AIProvider{provider}, nil)}
+
+	mockRetroDataSvc := new(MockRetroDataSvc)
+	mockRetroDataSvc.On("RetroConfirmFacilitator", testRetroID, testParticipantID).Return(fmt.Errorf("REQUIRES_FACILITATOR"))
+	service := &Service{RetroDataSvc: mockRetroDataSvc, Logger: otelzap.New(zap.NewNop())}
+
+	rr := retroInsightsRequest(service, aiSvc, testParticipantID)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Equal(t, 0, provider.calls)
+}
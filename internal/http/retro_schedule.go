@@ -0,0 +1,285 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/reminder"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+type retroScheduleRequestBody struct {
+	Name                 string `json:"name" validate:"required,max=256"`
+	TemplateID           string `json:"templateId" validate:"omitempty,uuid"`
+	MaxVotes             int    `json:"maxVotes" validate:"required,min=1,max=9"`
+	BrainstormVisibility string `json:"brainstormVisibility" validate:"required,oneof=visible concealed hidden"`
+	RecurrenceCron       string `json:"recurrenceCron" validate:"required,max=128"`
+}
+
+// readRetroScheduleBody reads and validates the retro schedule request body, an empty template ID uses
+// the team's default retro template
+func (s *Service) readRetroScheduleBody(w http.ResponseWriter, r *http.Request) (*thunderdome.RetroSchedule, bool) {
+	var t = retroScheduleRequestBody{}
+	body, bodyErr := io.ReadAll(r.Body)
+	if bodyErr != nil {
+		s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, bodyErr.Error()))
+		return nil, false
+	}
+
+	jsonErr := json.Unmarshal(body, &t)
+	if jsonErr != nil {
+		s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, jsonErr.Error()))
+		return nil, false
+	}
+
+	inputErr := validate.Struct(t)
+	if inputErr != nil {
+		s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, inputErr.Error()))
+		return nil, false
+	}
+
+	if err := reminder.ValidateCronExpression(t.RecurrenceCron); err != nil {
+		s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "INVALID_CRON_EXPRESSION"))
+		return nil, false
+	}
+
+	return &thunderdome.RetroSchedule{
+		Name:                 t.Name,
+		TemplateID:           t.TemplateID,
+		MaxVotes:             t.MaxVotes,
+		BrainstormVisibility: t.BrainstormVisibility,
+		RecurrenceCron:       t.RecurrenceCron,
+	}, true
+}
+
+// handleGetRetroSchedules gets the team's recurring retro schedules
+//
+//	@Summary		Get Team Retro Schedules
+//	@Description	Gets the team's recurring retro schedules
+//	@Tags			team
+//	@Produce		json
+//	@Param			teamId	path	string	true	"the team ID"
+//	@Success		200		object	standardJsonResponse{data=[]thunderdome.RetroSchedule}
+//	@Failure		400		object	standardJsonResponse{}
+//	@Failure		500		object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/teams/{teamId}/retro-schedules [get]
+func (s *Service) handleGetRetroSchedules() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		vars := mux.Vars(r)
+		teamID := vars["teamId"]
+		idErr := validate.Var(teamID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		schedules, err := s.RetroDataSvc.GetRetroSchedules(ctx, teamID)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleGetRetroSchedules error", zap.Error(err), zap.String("team_id", teamID),
+				zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, schedules, nil)
+	}
+}
+
+// handleGetRetroSchedule gets a team recurring retro schedule
+//
+//	@Summary		Get Team Retro Schedule
+//	@Description	Gets a team recurring retro schedule
+//	@Tags			team
+//	@Produce		json
+//	@Param			teamId		path	string	true	"the team ID"
+//	@Param			scheduleId	path	string	true	"the retro schedule ID"
+//	@Success		200			object	standardJsonResponse{data=thunderdome.RetroSchedule}
+//	@Failure		400			object	standardJsonResponse{}
+//	@Failure		404			object	standardJsonResponse{}
+//	@Failure		500			object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/teams/{teamId}/retro-schedules/{scheduleId} [get]
+func (s *Service) handleGetRetroSchedule() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		vars := mux.Vars(r)
+		teamID := vars["teamId"]
+		idErr := validate.Var(teamID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+		scheduleID := vars["scheduleId"]
+		idErr = validate.Var(scheduleID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		schedule, err := s.RetroDataSvc.GetRetroScheduleByID(ctx, teamID, scheduleID)
+		if err != nil {
+			if err.Error() == "RETRO_SCHEDULE_NOT_FOUND" {
+				s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, err.Error()))
+				return
+			}
+			s.Logger.Ctx(ctx).Error("handleGetRetroSchedule error", zap.Error(err), zap.String("team_id", teamID),
+				zap.String("schedule_id", scheduleID), zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, schedule, nil)
+	}
+}
+
+// handleRetroScheduleCreate handles creating a team recurring retro schedule
+//
+//	@Summary		Create Team Retro Schedule
+//	@Description	Creates a team recurring retro schedule, a new team retro is created from the schedule each time the
+//	@Description	recurrence cron (standard 5 field format, evaluated in UTC) comes due
+//	@Tags			team
+//	@Produce		json
+//	@Param			teamId		path	string					true	"the team ID"
+//	@Param			schedule	body	retroScheduleRequestBody	true	"retro schedule object"
+//	@Success		200			object	standardJsonResponse{data=thunderdome.RetroSchedule}
+//	@Failure		400			object	standardJsonResponse{}
+//	@Failure		500			object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/teams/{teamId}/retro-schedules [post]
+func (s *Service) handleRetroScheduleCreate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		vars := mux.Vars(r)
+		teamID := vars["teamId"]
+		idErr := validate.Var(teamID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		t, ok := s.readRetroScheduleBody(w, r)
+		if !ok {
+			return
+		}
+
+		schedule, err := s.RetroDataSvc.CreateRetroSchedule(ctx, teamID, sessionUserID, t)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleRetroScheduleCreate error", zap.Error(err), zap.String("team_id", teamID),
+				zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, schedule, nil)
+	}
+}
+
+// handleRetroScheduleUpdate handles updating a team recurring retro schedule
+//
+//	@Summary		Update Team Retro Schedule
+//	@Description	Updates a team recurring retro schedule
+//	@Tags			team
+//	@Produce		json
+//	@Param			teamId		path	string					true	"the team ID"
+//	@Param			scheduleId	path	string					true	"the retro schedule ID"
+//	@Param			schedule	body	retroScheduleRequestBody	true	"retro schedule object"
+//	@Success		200			object	standardJsonResponse{data=thunderdome.RetroSchedule}
+//	@Failure		400			object	standardJsonResponse{}
+//	@Failure		404			object	standardJsonResponse{}
+//	@Failure		500			object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/teams/{teamId}/retro-schedules/{scheduleId} [put]
+func (s *Service) handleRetroScheduleUpdate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		vars := mux.Vars(r)
+		teamID := vars["teamId"]
+		idErr := validate.Var(teamID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+		scheduleID := vars["scheduleId"]
+		idErr = validate.Var(scheduleID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		t, ok := s.readRetroScheduleBody(w, r)
+		if !ok {
+			return
+		}
+
+		schedule, err := s.RetroDataSvc.UpdateRetroSchedule(ctx, teamID, scheduleID, t)
+		if err != nil {
+			if err.Error() == "RETRO_SCHEDULE_NOT_FOUND" {
+				s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, err.Error()))
+				return
+			}
+			s.Logger.Ctx(ctx).Error("handleRetroScheduleUpdate error", zap.Error(err), zap.String("team_id", teamID),
+				zap.String("schedule_id", scheduleID), zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, schedule, nil)
+	}
+}
+
+// handleRetroScheduleDelete handles deleting a team recurring retro schedule
+//
+//	@Summary		Delete Team Retro Schedule
+//	@Description	Deletes a team recurring retro schedule, retros already created from it are kept
+//	@Tags			team
+//	@Produce		json
+//	@Param			teamId		path	string	true	"the team ID"
+//	@Param			scheduleId	path	string	true	"the retro schedule ID"
+//	@Success		200			object	standardJsonResponse{}
+//	@Failure		400			object	standardJsonResponse{}
+//	@Failure		404			object	standardJsonResponse{}
+//	@Failure		500			object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/teams/{teamId}/retro-schedules/{scheduleId} [delete]
+func (s *Service) handleRetroScheduleDelete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		vars := mux.Vars(r)
+		teamID := vars["teamId"]
+		idErr := validate.Var(teamID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+		scheduleID := vars["scheduleId"]
+		idErr = validate.Var(scheduleID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		err := s.RetroDataSvc.DeleteRetroSchedule(ctx, teamID, scheduleID)
+		if err != nil {
+			if err.Error() == "RETRO_SCHEDULE_NOT_FOUND" {
+				s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, err.Error()))
+				return
+			}
+			s.Logger.Ctx(ctx).Error("handleRetroScheduleDelete error", zap.Error(err), zap.String("team_id", teamID),
+				zap.String("schedule_id", scheduleID), zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, nil, nil)
+	}
+}
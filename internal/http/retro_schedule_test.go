@@ -0,0 +1,63 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+func (m *MockRetroDataSvc) CreateRetroSchedule(ctx context.Context, teamID string, userID string, schedule *thunderdome.RetroSchedule) (*thunderdome.RetroSchedule, error) {
+	args := m.Called(ctx, teamID, userID, schedule)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*thunderdome.RetroSchedule), args.Error(1)
+}
+
+func TestHandleRetroScheduleCreate(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectCall     bool
+	}{
+		{name: "schedule with template", body: `{"name":"Sprint Retro","templateId":"a23e4567-e89b-12d3-a456-426614174000","maxVotes":3,"brainstormVisibility":"visible","recurrenceCron":"0 16 * * 5"}`, expectedStatus: http.StatusOK, expectCall: true},
+		{name: "schedule with team default template", body: `{"name":"Sprint Retro","maxVotes":3,"brainstormVisibility":"visible","recurrenceCron":"0 16 * * 5"}`, expectedStatus: http.StatusOK, expectCall: true},
+		{name: "missing recurrence cron", body: `{"name":"Sprint Retro","maxVotes":3,"brainstormVisibility":"visible"}`, expectedStatus: http.StatusBadRequest},
+		{name: "invalid cron expression", body: `{"name":"Sprint Retro","maxVotes":3,"brainstormVisibility":"visible","recurrenceCron":"every friday"}`, expectedStatus: http.StatusBadRequest},
+		{name: "invalid brainstorm visibility", body: `{"name":"Sprint Retro","maxVotes":3,"brainstormVisibility":"public","recurrenceCron":"0 16 * * 5"}`, expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRetroDataSvc := new(MockRetroDataSvc)
+			if tt.expectCall {
+				mockRetroDataSvc.On("CreateRetroSchedule", mock.Anything, testTeamID, testFacilitatorID, mock.MatchedBy(func(schedule *thunderdome.RetroSchedule) bool {
+					return schedule.Name == "Sprint Retro" && schedule.RecurrenceCron == "0 16 * * 5"
+				})).Return(&thunderdome.RetroSchedule{ID: "schedule", TeamID: testTeamID, Name: "Sprint Retro"}, nil)
+			}
+			service := &Service{
+				RetroDataSvc: mockRetroDataSvc,
+				Logger:       otelzap.New(zap.NewNop()),
+			}
+
+			req := httptest.NewRequest("POST", "/teams/"+testTeamID+"/retro-schedules", strings.NewReader(tt.body))
+			req = mux.SetURLVars(req, map[string]string{"teamId": testTeamID})
+			req = req.WithContext(context.WithValue(req.Context(), contextKeyUserID, testFacilitatorID))
+			rr := httptest.NewRecorder()
+			service.handleRetroScheduleCreate().ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			mockRetroDataSvc.AssertExpectations(t)
+		})
+	}
+}
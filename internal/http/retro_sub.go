@@ -0,0 +1,180 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+type subRetroCreateRequestBody struct {
+	Name string `json:"name" example:"platform squad retro" validate:"required,max=256"`
+}
+
+// subRetroFailure responds with the status matching a sub retro data service error
+func (s *Service) subRetroFailure(w http.ResponseWriter, r *http.Request, handler string, retroID string, err error) {
+	switch {
+	case err.Error() == "RETRO_NOT_FOUND":
+		s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, err.Error()))
+	case errors.Is(err, thunderdome.ErrSubRetroMaxDepth):
+		s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, err.Error()))
+	case errors.Is(err, thunderdome.ErrSubRetroCircularReference):
+		s.Failure(w, r, http.StatusConflict, Errorf(ECONFLICT, err.Error()))
+	default:
+		s.Logger.Ctx(r.Context()).Error(handler+" error", zap.Error(err), zap.String("retro_id", retroID))
+		s.Failure(w, r, http.StatusInternalServerError, err)
+	}
+}
+
+// confirmRetroFacilitator makes sure the session user is an admin or a facilitator of the retro
+func (s *Service) confirmRetroFacilitator(w http.ResponseWriter, r *http.Request, retroID string) bool {
+	ctx := r.Context()
+	sessionUserID := ctx.Value(contextKeyUserID).(string)
+	userType := ctx.Value(contextKeyUserType).(string)
+
+	if userType != thunderdome.AdminUserType {
+		if err := s.RetroDataSvc.RetroConfirmFacilitator(retroID, sessionUserID); err != nil {
+			s.Failure(w, r, http.StatusForbidden, Errorf(EUNAUTHORIZED, "REQUIRES_FACILITATOR"))
+			return false
+		}
+	}
+
+	return true
+}
+
+// handleSubRetrosGet handles getting the retro's sub retros
+//
+//	@Summary		Get Sub Retros
+//	@Description	Gets the retros nested below the retro for sub groups of the team,
+//	@Description	along with the item counts aggregated from all of them
+//	@Tags			retro
+//	@Produce		json
+//	@Param			retroId	path	string	true	"the parent retro ID"
+//	@Success		200		object	standardJsonResponse{data=thunderdome.SubRetroSummary}
+//	@Failure		400		object	standardJsonResponse{}
+//	@Failure		404		object	standardJsonResponse{}
+//	@Failure		500		object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/retros/{retroId}/sub-retros [get]
+func (s *Service) handleSubRetrosGet() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		retroID := mux.Vars(r)["retroId"]
+		if idErr := validate.Var(retroID, "required,uuid"); idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		summary, err := s.RetroDataSvc.GetSubRetroSummary(r.Context(), retroID)
+		if err != nil {
+			s.subRetroFailure(w, r, "handleSubRetrosGet", retroID, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, summary, nil)
+	}
+}
+
+// handleSubRetroCreate handles creating a sub retro for a sub group of the retro's team
+//
+//	@Summary		Create Sub Retro
+//	@Description	Creates a retro nested below the retro using its template and settings,
+//	@Description	the facilitator creating it becomes its owner, retros can be nested up to 5 levels deep
+//	@Tags			retro
+//	@Produce		json
+//	@Param			retroId		path	string						true	"the parent retro ID"
+//	@Param			subRetro	body	subRetroCreateRequestBody	true	"new sub retro object"
+//	@Success		200			object	standardJsonResponse{data=thunderdome.Retro}
+//	@Failure		400			object	standardJsonResponse{}
+//	@Failure		403			object	standardJsonResponse{}
+//	@Failure		404			object	standardJsonResponse{}
+//	@Failure		409			object	standardJsonResponse{}
+//	@Failure		500			object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/retros/{retroId}/sub-retros [post]
+func (s *Service) handleSubRetroCreate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		retroID := mux.Vars(r)["retroId"]
+		if idErr := validate.Var(retroID, "required,uuid"); idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+
+		body, bodyErr := io.ReadAll(r.Body)
+		if bodyErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, bodyErr.Error()))
+			return
+		}
+
+		var sr = subRetroCreateRequestBody{}
+		if jsonErr := json.Unmarshal(body, &sr); jsonErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, jsonErr.Error()))
+			return
+		}
+		if inputErr := validate.Struct(sr); inputErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, inputErr.Error()))
+			return
+		}
+
+		if !s.confirmRetroFacilitator(w, r, retroID) {
+			return
+		}
+
+		subRetro, err := s.RetroDataSvc.CreateSubRetro(ctx, retroID, sessionUserID, sr.Name)
+		if err != nil {
+			s.subRetroFailure(w, r, "handleSubRetroCreate", retroID, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, subRetro, nil)
+	}
+}
+
+// handleSubRetrosMerge handles merging the items of the retro's sub retros into the retro
+//
+//	@Summary		Merge Sub Retros
+//	@Description	Copies the items of every retro nested below the retro into it keeping their authors,
+//	@Description	items the author already has in the retro are skipped
+//	@Tags			retro
+//	@Produce		json
+//	@Param			retroId	path	string	true	"the parent retro ID"
+//	@Success		200		object	standardJsonResponse{data=thunderdome.SubRetroSummary}
+//	@Failure		400		object	standardJsonResponse{}
+//	@Failure		403		object	standardJsonResponse{}
+//	@Failure		404		object	standardJsonResponse{}
+//	@Failure		500		object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/retros/{retroId}/sub-retros/merge [post]
+func (s *Service) handleSubRetrosMerge() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		retroID := mux.Vars(r)["retroId"]
+		if idErr := validate.Var(retroID, "required,uuid"); idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		if !s.confirmRetroFacilitator(w, r, retroID) {
+			return
+		}
+
+		if err := s.RetroDataSvc.MergeSubRetros(ctx, retroID); err != nil {
+			s.subRetroFailure(w, r, "handleSubRetrosMerge", retroID, err)
+			return
+		}
+
+		summary, err := s.RetroDataSvc.GetSubRetroSummary(ctx, retroID)
+		if err != nil {
+			s.subRetroFailure(w, r, "handleSubRetrosMerge", retroID, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, summary, nil)
+	}
+}
@@ -0,0 +1,157 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+func (m *MockRetroDataSvc) CreateSubRetro(ctx context.Context, parentRetroID string, facilitatorID string, name string) (*thunderdome.Retro, error) {
+	args := m.Called(ctx, parentRetroID, facilitatorID, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*thunderdome.Retro), args.Error(1)
+}
+
+func (m *MockRetroDataSvc) GetSubRetroSummary(ctx context.Context, parentRetroID string) (*thunderdome.SubRetroSummary, error) {
+	args := m.Called(ctx, parentRetroID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*thunderdome.SubRetroSummary), args.Error(1)
+}
+
+func (m *MockRetroDataSvc) MergeSubRetros(ctx context.Context, parentRetroID string) error {
+	args := m.Called(ctx, parentRetroID)
+	return args.Error(0)
+}
+
+func subRetroRequest(method string, target string, body []byte, userID string) *http.Request {
+	req := httptest.NewRequest(method, target, bytes.NewReader(body))
+	req = mux.SetURLVars(req, map[string]string{"retroId": testRetroID})
+	req = req.WithContext(context.WithValue(req.Context(), contextKeyUserID, userID))
+	return req.WithContext(context.WithValue(req.Context(), contextKeyUserType, thunderdome.RegisteredUserType))
+}
+
+func TestHandleSubRetroCreate(t *testing.T) {
+	subRetroID := "a23e4567-e89b-12d3-a456-426614174000"
+	parentRetroID := testRetroID
+
+	tests := []struct {
+		name         string
+		body         string
+		confirmErr   error
+		creates      bool
+		createErr    error
+		expectedCode int
+	}{
+		{name: "created", body: `{"name":"Platform squad"}`, creates: true, expectedCode: http.StatusOK},
+		{name: "missing name", body: `{}`, expectedCode: http.StatusBadRequest},
+		{name: "not facilitator", body: `{"name":"Platform squad"}`, confirmErr: fmt.Errorf("REQUIRES_FACILITATOR"), expectedCode: http.StatusForbidden},
+		{name: "parent not found", body: `{"name":"Platform squad"}`, creates: true, createErr: fmt.Errorf("RETRO_NOT_FOUND"), expectedCode: http.StatusNotFound},
+		{name: "max depth", body: `{"name":"Platform squad"}`, creates: true, createErr: thunderdome.ErrSubRetroMaxDepth, expectedCode: http.StatusBadRequest},
+		{name: "circular reference", body: `{"name":"Platform squad"}`, creates: true, createErr: thunderdome.ErrSubRetroCircularReference, expectedCode: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRetroDataSvc := new(MockRetroDataSvc)
+			service := &Service{RetroDataSvc: mockRetroDataSvc, Logger: otelzap.New(zap.NewNop())}
+			if tt.creates || tt.confirmErr != nil {
+				mockRetroDataSvc.On("RetroConfirmFacilitator", testRetroID, testFacilitatorID).Return(tt.confirmErr)
+			}
+			if tt.creates {
+				var subRetro *thunderdome.Retro
+				if tt.createErr == nil {
+					subRetro = &thunderdome.Retro{ID: subRetroID, Name: "Platform squad", ParentRetroID: &parentRetroID}
+				}
+				mockRetroDataSvc.On("CreateSubRetro", mock.Anything, testRetroID, testFacilitatorID, "Platform squad").
+					Return(subRetro, tt.createErr)
+			}
+
+			req := subRetroRequest("POST", "/retros/"+testRetroID+"/sub-retros", []byte(tt.body), testFacilitatorID)
+			rr := httptest.NewRecorder()
+			service.handleSubRetroCreate().ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+			if tt.expectedCode == http.StatusOK {
+				var body struct {
+					Data *thunderdome.Retro `json:"data"`
+				}
+				assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+				assert.Equal(t, subRetroID, body.Data.ID)
+				assert.Equal(t, testRetroID, *body.Data.ParentRetroID)
+			}
+			mockRetroDataSvc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandleSubRetrosGet(t *testing.T) {
+	mockRetroDataSvc := new(MockRetroDataSvc)
+	service := &Service{RetroDataSvc: mockRetroDataSvc, Logger: otelzap.New(zap.NewNop())}
+	summary := &thunderdome.SubRetroSummary{
+		ParentRetroID:    testRetroID,
+		SubRetros:        []*thunderdome.Retro{{ID: "a23e4567-e89b-12d3-a456-426614174000"}},
+		ItemCount:        3,
+		ItemCountsByType: map[string]int{"worked": 2, "improve": 1},
+	}
+	mockRetroDataSvc.On("GetSubRetroSummary", mock.Anything, testRetroID).Return(summary, nil)
+
+	req := subRetroRequest("GET", "/retros/"+testRetroID+"/sub-retros", nil, testFacilitatorID)
+	rr := httptest.NewRecorder()
+	service.handleSubRetrosGet().ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var body struct {
+		Data *thunderdome.SubRetroSummary `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+	assert.Equal(t, 3, body.Data.ItemCount)
+	assert.Equal(t, 2, body.Data.ItemCountsByType["worked"])
+	assert.Len(t, body.Data.SubRetros, 1)
+	mockRetroDataSvc.AssertExpectations(t)
+}
+
+func TestHandleSubRetrosMerge(t *testing.T) {
+	t.Run("merged", func(t *testing.T) {
+		mockRetroDataSvc := new(MockRetroDataSvc)
+		service := &Service{RetroDataSvc: mockRetroDataSvc, Logger: otelzap.New(zap.NewNop())}
+		mockRetroDataSvc.On("RetroConfirmFacilitator", testRetroID, testFacilitatorID).Return(nil)
+		mockRetroDataSvc.On("MergeSubRetros", mock.Anything, testRetroID).Return(nil)
+		mockRetroDataSvc.On("GetSubRetroSummary", mock.Anything, testRetroID).
+			Return(&thunderdome.SubRetroSummary{ParentRetroID: testRetroID, SubRetros: []*thunderdome.Retro{}}, nil)
+
+		req := subRetroRequest("POST", "/retros/"+testRetroID+"/sub-retros/merge", nil, testFacilitatorID)
+		rr := httptest.NewRecorder()
+		service.handleSubRetrosMerge().ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		mockRetroDataSvc.AssertExpectations(t)
+	})
+
+	t.Run("not facilitator", func(t *testing.T) {
+		mockRetroDataSvc := new(MockRetroDataSvc)
+		service := &Service{RetroDataSvc: mockRetroDataSvc, Logger: otelzap.New(zap.NewNop())}
+		mockRetroDataSvc.On("RetroConfirmFacilitator", testRetroID, testFacilitatorID).Return(fmt.Errorf("REQUIRES_FACILITATOR"))
+
+		req := subRetroRequest("POST", "/retros/"+testRetroID+"/sub-retros/merge", nil, testFacilitatorID)
+		rr := httptest.NewRecorder()
+		service.handleSubRetrosMerge().ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+		mockRetroDataSvc.AssertNotCalled(t, "MergeSubRetros", mock.Anything, testRetroID)
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/gorilla/mux"
 	"go.uber.org/zap"
 
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/http/response"
 	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
 )
 
@@ -56,11 +57,7 @@ func (s *Service) handleGetRetroTemplates() http.HandlerFunc {
 			return
 		}
 
-		meta := &pagination{
-			Count:  count,
-			Offset: offset,
-			Limit:  limit,
-		}
+		meta := response.NewMeta(count, limit, offset)
 
 		s.Success(w, r, http.StatusOK, templates, meta)
 	}
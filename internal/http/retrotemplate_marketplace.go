@@ -0,0 +1,182 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// handleGetMarketplaceRetroTemplates gets the list of retro templates shared to the marketplace
+//
+//	@Summary		Get Marketplace Retro Templates
+//	@Description	get list of retro templates shared to the marketplace sorted by download count
+//	@Tags			retroTemplate
+//	@Produce		json
+//	@Success		200	{object}	standardJsonResponse{data=[]thunderdome.RetroTemplate}
+//	@Failure		500	{object}	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/retro-templates/marketplace [get]
+func (s *Service) handleGetMarketplaceRetroTemplates() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+
+		templates, err := s.RetroTemplateDataSvc.GetMarketplaceTemplates(ctx)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleGetMarketplaceRetroTemplates error", zap.Error(err),
+				zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, templates, nil)
+	}
+}
+
+// handleRetroTemplateMarketplacePublish shares a retro template to the marketplace
+//
+//	@Summary		Publish Retro Template to Marketplace
+//	@Description	Shares a retro template to the marketplace, only the template owner or an admin may publish
+//	@Tags			retroTemplate
+//	@Produce		json
+//	@Param			templateId	path		string	true	"the retro template ID to publish"
+//	@Success		200			{object}	standardJsonResponse{}
+//	@Failure		400			{object}	standardJsonResponse{}
+//	@Failure		403			{object}	standardJsonResponse{}
+//	@Failure		404			{object}	standardJsonResponse{}
+//	@Failure		500			{object}	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/retro-templates/{templateId}/marketplace/publish [post]
+func (s *Service) handleRetroTemplateMarketplacePublish() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		sessionUserType := ctx.Value(contextKeyUserType).(string)
+		vars := mux.Vars(r)
+		templateID := vars["templateId"]
+		idErr := validate.Var(templateID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		template, err := s.RetroTemplateDataSvc.GetTemplateByID(ctx, templateID)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleRetroTemplateMarketplacePublish error", zap.Error(err),
+				zap.String("template_id", templateID), zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		if template == nil {
+			s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, "TEMPLATE_NOT_FOUND"))
+			return
+		}
+		if !thunderdome.CanPublishRetroTemplate(template, sessionUserID, sessionUserType) {
+			s.Failure(w, r, http.StatusForbidden, Errorf(EUNAUTHORIZED, "REQUIRES_TEMPLATE_OWNER"))
+			return
+		}
+
+		err = s.RetroTemplateDataSvc.PublishMarketplaceTemplate(ctx, templateID)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleRetroTemplateMarketplacePublish error", zap.Error(err),
+				zap.String("template_id", templateID), zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, nil, nil)
+	}
+}
+
+type retroTemplateInstallRequestBody struct {
+	OrganizationID string `json:"organizationId" validate:"required,uuid"`
+}
+
+// handleRetroTemplateMarketplaceInstall installs a marketplace retro template into an organization
+//
+//	@Summary		Install Marketplace Retro Template
+//	@Description	Copies a marketplace retro template into the organization, requires being an organization admin
+//	@Tags			retroTemplate
+//	@Produce		json
+//	@Param			templateId	path		string							true	"the marketplace retro template ID to install"
+//	@Param			install		body		retroTemplateInstallRequestBody	true	"the organization to install the template to"
+//	@Success		200			{object}	standardJsonResponse{data=thunderdome.RetroTemplate}
+//	@Failure		400			{object}	standardJsonResponse{}
+//	@Failure		403			{object}	standardJsonResponse{}
+//	@Failure		404			{object}	standardJsonResponse{}
+//	@Failure		500			{object}	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/retro-templates/{templateId}/marketplace/install [post]
+func (s *Service) handleRetroTemplateMarketplaceInstall() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		sessionUserType := ctx.Value(contextKeyUserType).(string)
+		vars := mux.Vars(r)
+		templateID := vars["templateId"]
+		idErr := validate.Var(templateID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		var install = retroTemplateInstallRequestBody{}
+		body, bodyErr := io.ReadAll(r.Body)
+		if bodyErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, bodyErr.Error()))
+			return
+		}
+
+		jsonErr := json.Unmarshal(body, &install)
+		if jsonErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, jsonErr.Error()))
+			return
+		}
+
+		inputErr := validate.Struct(install)
+		if inputErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, inputErr.Error()))
+			return
+		}
+
+		if sessionUserType != thunderdome.AdminUserType {
+			role, roleErr := s.OrganizationDataSvc.OrganizationUserRole(ctx, sessionUserID, install.OrganizationID)
+			if roleErr != nil {
+				s.Failure(w, r, http.StatusForbidden, Errorf(EUNAUTHORIZED, "ORGANIZATION_USER_REQUIRED"))
+				return
+			}
+			if role != thunderdome.AdminUserType {
+				s.Failure(w, r, http.StatusForbidden, Errorf(EUNAUTHORIZED, "REQUIRES_ORG_ADMIN"))
+				return
+			}
+
+			if s.Config.SubscriptionsEnabled {
+				subscribed, subErr := s.OrganizationDataSvc.OrganizationIsSubscribed(ctx, install.OrganizationID)
+				if subErr != nil || !subscribed {
+					s.Failure(w, r, http.StatusForbidden, Errorf(EUNAUTHORIZED, "ORGANIZATION_SUBSCRIPTION_REQUIRED"))
+					return
+				}
+			}
+		}
+
+		template, err := s.RetroTemplateDataSvc.InstallMarketplaceTemplate(ctx, templateID, install.OrganizationID, sessionUserID)
+		if err != nil {
+			if err.Error() == "MARKETPLACE_TEMPLATE_NOT_FOUND" {
+				s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, err.Error()))
+				return
+			}
+			s.Logger.Ctx(ctx).Error("handleRetroTemplateMarketplaceInstall error", zap.Error(err),
+				zap.String("template_id", templateID), zap.String("organization_id", install.OrganizationID),
+				zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, template, nil)
+	}
+}
@@ -0,0 +1,282 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/http/checkin"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+
+	"github.com/gorilla/mux"
+)
+
+// handleStandupsGet gets a list of team standups
+//
+//	@Summary		Get Team Standups
+//	@Description	Get a list of team standups for a date or date range, defaults to the last 7 days
+//	@Tags			team
+//	@Produce		json
+//	@Param			teamId		path	string	true	"the team ID"
+//	@Param			date		query	string	false	"the date in YYYY-MM-DD format"
+//	@Param			startDate	query	string	false	"the range start date in YYYY-MM-DD format"
+//	@Param			endDate		query	string	false	"the range end date in YYYY-MM-DD format, defaults to today"
+//	@Success		200			object	standardJsonResponse{data=[]thunderdome.Standup}
+//	@Failure		400			object	standardJsonResponse{}
+//	@Failure		500			object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/teams/{teamId}/standups [get]
+func (s *Service) handleStandupsGet() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		vars := mux.Vars(r)
+		teamID := vars["teamId"]
+		idErr := validate.Var(teamID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		startDate, endDate, err := standupDateRange(r, time.Now())
+		if err != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, err.Error()))
+			return
+		}
+
+		standups, err := s.CheckinDataSvc.GetStandupsByTeam(ctx, teamID, startDate, endDate)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleStandupsGet error", zap.Error(err), zap.String("team_id", teamID),
+				zap.Time("start_date", startDate), zap.Time("end_date", endDate),
+				zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, standups, nil)
+	}
+}
+
+// standupDateRange gets the standups date range from the date, or startDate and endDate query params,
+// defaulting to the last 7 days
+func standupDateRange(r *http.Request, now time.Time) (time.Time, time.Time, error) {
+	query := r.URL.Query()
+	if date := query.Get("date"); date != "" {
+		d, err := time.Parse(time.DateOnly, date)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("INVALID_DATE")
+		}
+		return d, d, nil
+	}
+
+	endDate := now
+	if end := query.Get("endDate"); end != "" {
+		d, err := time.Parse(time.DateOnly, end)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("INVALID_DATE")
+		}
+		endDate = d
+	}
+	startDate := endDate.AddDate(0, 0, -(thunderdome.StandupDigestDays - 1))
+	if start := query.Get("startDate"); start != "" {
+		d, err := time.Parse(time.DateOnly, start)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("INVALID_DATE")
+		}
+		startDate = d
+	}
+	if startDate.After(endDate) {
+		return time.Time{}, time.Time{}, errors.New("INVALID_DATE_RANGE")
+	}
+
+	return startDate, endDate, nil
+}
+
+type standupCreateRequestBody struct {
+	UserID    string `json:"userId" validate:"required,uuid"`
+	Yesterday string `json:"yesterday"`
+	Today     string `json:"today"`
+	Blockers  string `json:"blockers"`
+}
+
+// handleStandupCreate handles logging a team user daily standup
+//
+//	@Summary		Create Team Standup
+//	@Description	Logs a team users daily standup, replacing any standup already logged that day
+//	@Param			teamId	path	string						true	"the team ID"
+//	@Param			standup	body	standupCreateRequestBody	true	"new standup object"
+//	@Tags			team
+//	@Produce		json
+//	@Success		200	object	standardJsonResponse{}
+//	@Failure		400	object	standardJsonResponse{}
+//	@Failure		500	object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/teams/{teamId}/standups [post]
+func (s *Service) handleStandupCreate(tc *checkin.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		vars := mux.Vars(r)
+		teamID := vars["teamId"]
+		idErr := validate.Var(teamID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		var c = standupCreateRequestBody{}
+		body, bodyErr := io.ReadAll(r.Body)
+		if bodyErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, bodyErr.Error()))
+			return
+		}
+
+		jsonErr := json.Unmarshal(body, &c)
+		if jsonErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, jsonErr.Error()))
+			return
+		}
+
+		inputErr := validate.Struct(c)
+		if inputErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, inputErr.Error()))
+			return
+		}
+
+		err := tc.APIEvent(ctx, teamID, c.UserID, "standup_create", string(body))
+		if err != nil {
+			if err.Error() == "REQUIRES_TEAM_USER" {
+				s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, err.Error()))
+				return
+			}
+			s.Logger.Ctx(ctx).Error("handleStandupCreate error", zap.Error(err), zap.String("team_id", teamID),
+				zap.String("entity_user_id", c.UserID), zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, nil, nil)
+	}
+}
+
+// handleStandupDelete handles deleting a team standup
+//
+//	@Summary		Delete Team Standup
+//	@Description	Deletes a team standup
+//	@Param			teamId		path	string	true	"the team ID"
+//	@Param			standupId	path	string	true	"the standup ID"
+//	@Tags			team
+//	@Produce		json
+//	@Success		200	object	standardJsonResponse{}
+//	@Failure		400	object	standardJsonResponse{}
+//	@Failure		500	object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/teams/{teamId}/standups/{standupId} [delete]
+func (s *Service) handleStandupDelete(tc *checkin.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		vars := mux.Vars(r)
+		teamID := vars["teamId"]
+		idErr := validate.Var(teamID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+		standupID := vars["standupId"]
+		idErr = validate.Var(standupID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		cu, jsonErr := json.Marshal(map[string]string{"standupId": standupID})
+		if jsonErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, jsonErr.Error()))
+			return
+		}
+
+		err := tc.APIEvent(ctx, teamID, sessionUserID, "standup_delete", string(cu))
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleStandupDelete error", zap.Error(err), zap.String("team_id", teamID),
+				zap.String("session_user_id", sessionUserID), zap.String("standup_id", standupID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, nil, nil)
+	}
+}
+
+// handleStandupDigestSend handles sending the weekly standup digest email to the team users
+//
+//	@Summary		Send Team Standup Digest
+//	@Description	Sends the weekly standup digest listing each team users last 5 standups to the team users
+//	@Param			teamId	path	string	true	"the team ID"
+//	@Tags			team
+//	@Produce		json
+//	@Success		200	object	standardJsonResponse{data=[]thunderdome.StandupDigestEntry}
+//	@Failure		400	object	standardJsonResponse{}
+//	@Failure		500	object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/teams/{teamId}/standups/digest [post]
+func (s *Service) handleStandupDigestSend() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		vars := mux.Vars(r)
+		teamID := vars["teamId"]
+		idErr := validate.Var(teamID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		team, err := s.TeamDataSvc.TeamGetByID(ctx, teamID)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleStandupDigestSend error", zap.Error(err), zap.String("team_id", teamID),
+				zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		endDate := time.Now()
+		startDate := endDate.AddDate(0, 0, -(thunderdome.StandupDigestDays - 1))
+		digest, err := s.CheckinDataSvc.GetStandupDigest(ctx, teamID, startDate, endDate)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleStandupDigestSend error", zap.Error(err), zap.String("team_id", teamID),
+				zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		users, _, err := s.TeamDataSvc.TeamUserList(ctx, teamID, 1000, 0)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleStandupDigestSend error", zap.Error(err), zap.String("team_id", teamID),
+				zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		if len(digest) > 0 {
+			go func(ctx context.Context) {
+				for _, user := range users {
+					if user.Email == "" {
+						continue
+					}
+					if err := s.Email.SendStandupDigest(team.Name, digest, user.Name, user.Email); err != nil {
+						s.Logger.Ctx(ctx).Error("handleStandupDigestSend send email error", zap.Error(err),
+							zap.String("team_id", teamID), zap.String("user_id", user.ID))
+					}
+				}
+			}(context.WithoutCancel(ctx))
+		}
+
+		s.Success(w, r, http.StatusOK, digest, nil)
+	}
+}
@@ -0,0 +1,48 @@
+package http
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestStandupDateRange makes sure the standups can be filtered by a date or a date range
+func TestStandupDateRange(t *testing.T) {
+	now := time.Date(2025, 3, 10, 15, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		query         string
+		expectedStart string
+		expectedEnd   string
+		expectedErr   string
+	}{
+		{name: "default last 7 days", query: "", expectedStart: "2025-03-04", expectedEnd: "2025-03-10"},
+		{name: "single date", query: "date=2025-03-05", expectedStart: "2025-03-05", expectedEnd: "2025-03-05"},
+		{name: "range", query: "startDate=2025-02-01&endDate=2025-02-28", expectedStart: "2025-02-01", expectedEnd: "2025-02-28"},
+		{name: "start only", query: "startDate=2025-03-01", expectedStart: "2025-03-01", expectedEnd: "2025-03-10"},
+		{name: "end only", query: "endDate=2025-02-28", expectedStart: "2025-02-22", expectedEnd: "2025-02-28"},
+		{name: "reversed range", query: "startDate=2025-03-08&endDate=2025-03-01", expectedErr: "INVALID_DATE_RANGE"},
+		{name: "invalid date", query: "startDate=March", expectedErr: "INVALID_DATE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/teams/team/standups?"+tt.query, nil)
+			start, end, err := standupDateRange(req, now)
+			if tt.expectedErr != "" {
+				if err == nil || err.Error() != tt.expectedErr {
+					t.Fatalf("expected error %s, got %v", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if start.Format(time.DateOnly) != tt.expectedStart || end.Format(time.DateOnly) != tt.expectedEnd {
+				t.Errorf("expected %s to %s, got %s to %s", tt.expectedStart, tt.expectedEnd,
+					start.Format(time.DateOnly), end.Format(time.DateOnly))
+			}
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"go.uber.org/zap"
 
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/http/response"
 	"github.com/StevenWeathers/thunderdome-planning-poker/internal/http/storyboard"
 	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
 	"github.com/gorilla/mux"
@@ -17,6 +18,7 @@ type storyboardCreateRequestBody struct {
 	StoryboardName  string `json:"storyboardName" validate:"required"`
 	JoinCode        string `json:"joinCode"`
 	FacilitatorCode string `json:"facilitatorCode"`
+	TemplateID      string `json:"templateId" validate:"omitempty,uuid"`
 }
 
 // handleStoryboardCreate handles creating a storyboard (arena)
@@ -74,6 +76,25 @@ func (s *Service) handleStoryboardCreate() http.HandlerFunc {
 			return
 		}
 
+		// the storyboard starts with the goals and columns of the template
+		var templateFormat *thunderdome.StoryboardTemplateFormat
+		if sb.TemplateID != "" {
+			template, err := s.StoryboardDataSvc.GetTemplate(ctx, sb.TemplateID)
+			if err != nil {
+				s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, "STORYBOARD_TEMPLATE_NOT_FOUND"))
+				return
+			}
+			if !s.canViewStoryboardTemplate(ctx, template) {
+				s.Failure(w, r, http.StatusForbidden, Errorf(EUNAUTHORIZED, "REQUIRES_TEMPLATE_ACCESS"))
+				return
+			}
+			templateFormat, err = thunderdome.ParseStoryboardTemplateFormat(template.Format)
+			if err != nil {
+				s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, err.Error()))
+				return
+			}
+		}
+
 		var newStoryboard *thunderdome.Storyboard
 		var err error
 		// if storyboard created with team association
@@ -103,6 +124,17 @@ func (s *Service) handleStoryboardCreate() http.HandlerFunc {
 			}
 		}
 
+		if templateFormat != nil {
+			newStoryboard.Goals, err = s.StoryboardDataSvc.ApplyTemplate(ctx, newStoryboard.ID, templateFormat)
+			if err != nil {
+				s.Logger.Ctx(ctx).Error("handleStoryboardCreate apply template error", zap.Error(err),
+					zap.String("storyboard_id", newStoryboard.ID), zap.String("template_id", sb.TemplateID),
+					zap.String("session_user_id", sessionUserID))
+				s.Failure(w, r, http.StatusInternalServerError, err)
+				return
+			}
+		}
+
 		s.Success(w, r, http.StatusOK, newStoryboard, nil)
 	}
 }
@@ -150,6 +182,82 @@ func (s *Service) handleStoryboardGet() http.HandlerFunc {
 	}
 }
 
+// handleStoryboardExport exports the storyboard as a Mermaid diagram or JSON
+//
+//	@Summary		Export Storyboard
+//	@Description	export storyboard as a Mermaid flowchart (text/plain) or the full storyboard structure as JSON
+//	@Tags			storyboard
+//	@Produce		plain
+//	@Produce		json
+//	@Param			storyboardId	path	string	true	"the storyboard ID to export"
+//	@Param			format			query	string	false	"the export format, mermaid (default) or json"
+//	@Success		200				{string}	string
+//	@Failure		400				object	standardJsonResponse{}
+//	@Failure		403				object	standardJsonResponse{}
+//	@Failure		404				object	standardJsonResponse{}
+//	@Failure		500				object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/storyboards/{storyboardId}/export [get]
+func (s *Service) handleStoryboardExport(sbs *storyboard.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		storyboardID := vars["storyboardId"]
+		idErr := validate.Var(storyboardID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+		format := r.URL.Query().Get("format")
+		if format == "" {
+			format = "mermaid"
+		}
+		if format != "mermaid" && format != "json" {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "INVALID_EXPORT_FORMAT"))
+			return
+		}
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		userType := ctx.Value(contextKeyUserType).(string)
+
+		sb, err := s.StoryboardDataSvc.GetStoryboardByID(storyboardID, sessionUserID)
+		if err != nil {
+			s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, "STORYBOARD_NOT_FOUND"))
+			return
+		}
+
+		// don't allow exporting storyboard if storyboard has JoinCode and user hasn't joined yet
+		if sb.JoinCode != "" {
+			UserErr := s.StoryboardDataSvc.GetStoryboardUserActiveStatus(storyboardID, sessionUserID)
+			if UserErr != nil && userType != thunderdome.AdminUserType {
+				s.Failure(w, r, http.StatusForbidden, Errorf(EUNAUTHORIZED, "USER_MUST_JOIN_STORYBOARD"))
+				return
+			}
+		}
+
+		if format == "json" {
+			// codes are only for joining and shouldn't leave the app in an export
+			sb.JoinCode = ""
+			sb.FacilitatorCode = ""
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_ = json.NewEncoder(w).Encode(sb)
+			return
+		}
+
+		flowchart, err := sbs.ExportToMermaid(ctx, storyboardID)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleStoryboardExport error", zap.Error(err),
+				zap.String("storyboard_id", storyboardID), zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(flowchart))
+	}
+}
+
 // handleGetUserStoryboards looks up storyboards associated with UserID
 //
 //	@Summary		Get Storyboards
@@ -186,11 +294,7 @@ func (s *Service) handleGetUserStoryboards() http.HandlerFunc {
 			return
 		}
 
-		meta := &pagination{
-			Count:  count,
-			Offset: offset,
-			Limit:  limit,
-		}
+		meta := response.NewMeta(count, limit, offset)
 
 		s.Success(w, r, http.StatusOK, storyboards, meta)
 	}
@@ -234,11 +338,7 @@ func (s *Service) handleGetStoryboards() http.HandlerFunc {
 			return
 		}
 
-		meta := &pagination{
-			Count:  count,
-			Offset: offset,
-			Limit:  limit,
-		}
+		meta := response.NewMeta(count, limit, offset)
 
 		s.Success(w, r, http.StatusOK, storyboards, meta)
 	}
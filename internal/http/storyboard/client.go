@@ -152,3 +152,18 @@ func (b *Service) RetreatUser(roomID string, userID string) string {
 func (b *Service) APIEvent(ctx context.Context, storyboardID string, userID, eventType string, eventValue string) error {
 	return b.hub.ProcessAPIEventHandler(ctx, userID, storyboardID, eventType, eventValue)
 }
+
+// Shutdown gracefully closes all websocket connections, see wshub.Hub.Shutdown
+func (b *Service) Shutdown(ctx context.Context) error {
+	return b.hub.Shutdown(ctx)
+}
+
+// EvictIdleConnections closes idle websocket connections that don't respond to a ping, see wshub.Hub.EvictIdleConnections
+func (b *Service) EvictIdleConnections(idleMinutes int) int {
+	return b.hub.EvictIdleConnections(idleMinutes)
+}
+
+// SendToUser sends the message to the user's websocket connections, see wshub.Hub.SendToUser
+func (b *Service) SendToUser(userID string, data []byte) {
+	b.hub.SendToUser(userID, data)
+}
@@ -0,0 +1,156 @@
+package storyboard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/wshub"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+const (
+	// cursorTTL is how long a cursor is shown after its user last moved it
+	cursorTTL = 5 * time.Second
+	// cursorBroadcastInterval is how often changed cursors are broadcast, batching the moves in between
+	cursorBroadcastInterval = 100 * time.Millisecond
+)
+
+// cursorTracker keeps the storyboards live cursor positions by storyboard and user
+type cursorTracker struct {
+	mu      sync.Mutex
+	cursors map[string]map[string]*thunderdome.CursorPosition
+	// changed are the storyboards with cursors moved or evicted since the last broadcast
+	changed map[string]bool
+}
+
+func newCursorTracker() *cursorTracker {
+	return &cursorTracker{
+		cursors: make(map[string]map[string]*thunderdome.CursorPosition),
+		changed: make(map[string]bool),
+	}
+}
+
+// username gets the name of the user's tracked cursor, returning false when the user has no cursor
+func (t *cursorTracker) username(storyboardID string, userID string) (string, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	cursor, ok := t.cursors[storyboardID][userID]
+	if !ok {
+		return "", false
+	}
+
+	return cursor.Username, true
+}
+
+// move sets the user's cursor position
+func (t *cursorTracker) move(storyboardID string, cursor thunderdome.CursorPosition) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.cursors[storyboardID] == nil {
+		t.cursors[storyboardID] = make(map[string]*thunderdome.CursorPosition)
+	}
+	t.cursors[storyboardID][cursor.UserID] = &cursor
+	t.changed[storyboardID] = true
+}
+
+// takeChanged evicts the cursors not moved within the cursor TTL and gets the cursors of each storyboard
+// changed since the last call ordered by user ID, a storyboard whose last cursor was evicted gets no cursors
+func (t *cursorTracker) takeChanged(now time.Time) map[string][]thunderdome.CursorPosition {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for storyboardID, cursors := range t.cursors {
+		for userID, cursor := range cursors {
+			if now.Sub(cursor.LastUpdated) > cursorTTL {
+				delete(cursors, userID)
+				t.changed[storyboardID] = true
+			}
+		}
+		if len(cursors) == 0 {
+			delete(t.cursors, storyboardID)
+		}
+	}
+
+	changed := make(map[string][]thunderdome.CursorPosition, len(t.changed))
+	for storyboardID := range t.changed {
+		cursors := make([]thunderdome.CursorPosition, 0, len(t.cursors[storyboardID]))
+		for _, cursor := range t.cursors[storyboardID] {
+			cursors = append(cursors, *cursor)
+		}
+		sort.Slice(cursors, func(i, j int) bool { return cursors[i].UserID < cursors[j].UserID })
+		changed[storyboardID] = cursors
+		delete(t.changed, storyboardID)
+	}
+
+	return changed
+}
+
+// CursorMove handles a user moving their cursor on the storyboard, the positions are broadcast
+// in batches every cursor broadcast interval rather than per move
+func (b *Service) CursorMove(ctx context.Context, storyboardID string, userID string, eventValue string) ([]byte, error, bool) {
+	var position struct {
+		X float64 `json:"x"`
+		Y float64 `json:"y"`
+	}
+	if err := json.Unmarshal([]byte(eventValue), &position); err != nil {
+		return nil, err, false
+	}
+	if math.IsNaN(position.X) || math.IsInf(position.X, 0) || math.IsNaN(position.Y) || math.IsInf(position.Y, 0) {
+		return nil, errors.New("INVALID_CURSOR_POSITION"), false
+	}
+
+	username, ok := b.cursors.username(storyboardID, userID)
+	if !ok {
+		storyboard, err := b.StoryboardService.GetStoryboardByID(storyboardID, userID)
+		if err != nil {
+			return nil, err, false
+		}
+		for _, user := range storyboard.Users {
+			if user.ID == userID {
+				username = user.Name
+				break
+			}
+		}
+	}
+
+	b.cursors.move(storyboardID, thunderdome.CursorPosition{
+		UserID:      userID,
+		Username:    username,
+		X:           position.X,
+		Y:           position.Y,
+		LastUpdated: time.Now(),
+	})
+
+	return nil, nil, false
+}
+
+// runCursorBroadcast broadcasts the changed cursors every interval
+func (b *Service) runCursorBroadcast(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		for storyboardID, msg := range b.cursorUpdates(now) {
+			b.hub.Broadcast(wshub.Message{Data: msg, Room: storyboardID})
+		}
+	}
+}
+
+// cursorUpdates gets the cursors_update event of each storyboard whose cursors changed
+func (b *Service) cursorUpdates(now time.Time) map[string][]byte {
+	changed := b.cursors.takeChanged(now)
+	updates := make(map[string][]byte, len(changed))
+	for storyboardID, cursors := range changed {
+		cursorsJSON, _ := json.Marshal(cursors)
+		updates[storyboardID] = wshub.CreateSocketEvent("cursors_update", string(cursorsJSON), "")
+	}
+
+	return updates
+}
@@ -0,0 +1,128 @@
+package storyboard
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+// fakeCursorDataSvc returns a storyboard with five users, counting the lookups
+type fakeCursorDataSvc struct {
+	StoryboardDataSvc
+	lookups int
+}
+
+func (f *fakeCursorDataSvc) GetStoryboardByID(storyboardID string, userID string) (*thunderdome.Storyboard, error) {
+	f.lookups++
+	storyboard := &thunderdome.Storyboard{ID: storyboardID}
+	for i := 1; i <= 5; i++ {
+		storyboard.Users = append(storyboard.Users, &thunderdome.StoryboardUser{
+			ID:   fmt.Sprintf("user-%d", i),
+			Name: fmt.Sprintf("User %d", i),
+		})
+	}
+
+	return storyboard, nil
+}
+
+// newCursorTestService creates the service without its hub and cursor broadcast ticker,
+// so the tests take the cursor updates themselves
+func newCursorTestService(dataSvc StoryboardDataSvc) *Service {
+	return &Service{logger: otelzap.New(zap.NewNop()), StoryboardService: dataSvc, cursors: newCursorTracker()}
+}
+
+// cursorsUpdate decodes the cursors of a cursors_update event
+func cursorsUpdate(t *testing.T, msg []byte) []thunderdome.CursorPosition {
+	t.Helper()
+	var event struct {
+		Type  string `json:"type"`
+		Value string `json:"value"`
+	}
+	if err := json.Unmarshal(msg, &event); err != nil || event.Type != "cursors_update" {
+		t.Fatalf("expected a cursors_update event, got %s", msg)
+	}
+	var cursors []thunderdome.CursorPosition
+	if err := json.Unmarshal([]byte(event.Value), &cursors); err != nil {
+		t.Fatalf("expected cursors, got %v", err)
+	}
+
+	return cursors
+}
+
+// TestCursorMoveBatchesUpdates sends 20 cursor moves from 5 users and makes sure the broadcast
+// has exactly one cursor per user at its latest position
+func TestCursorMoveBatchesUpdates(t *testing.T) {
+	dataSvc := &fakeCursorDataSvc{}
+	b := newCursorTestService(dataSvc)
+	ctx := context.Background()
+
+	for move := 0; move < 20; move++ {
+		userID := fmt.Sprintf("user-%d", move%5+1)
+		msg, err, _ := b.CursorMove(ctx, "storyboard", userID, fmt.Sprintf(`{"x":%d,"y":%d.5}`, move, move))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if msg != nil {
+			t.Fatalf("expected cursor moves to be batched, got %s", msg)
+		}
+	}
+
+	updates := b.cursorUpdates(time.Now())
+	if len(updates) != 1 {
+		t.Fatalf("expected an update for the storyboard, got %d", len(updates))
+	}
+	cursors := cursorsUpdate(t, updates["storyboard"])
+	if len(cursors) != 5 {
+		t.Fatalf("expected 5 cursors, got %d", len(cursors))
+	}
+	seen := make(map[string]bool)
+	for i, cursor := range cursors {
+		seen[cursor.UserID] = true
+		if cursor.UserID != fmt.Sprintf("user-%d", i+1) || cursor.Username != fmt.Sprintf("User %d", i+1) {
+			t.Errorf("unexpected cursor %+v", cursor)
+		}
+		// each user's last move was one of the final five
+		if cursor.X != float64(15+i) || cursor.Y != float64(15+i)+0.5 {
+			t.Errorf("expected the latest position of %s, got %v,%v", cursor.UserID, cursor.X, cursor.Y)
+		}
+	}
+	if len(seen) != 5 {
+		t.Errorf("expected 5 unique user cursors, got %d", len(seen))
+	}
+	if dataSvc.lookups != 5 {
+		t.Errorf("expected each username to be looked up once, got %d lookups", dataSvc.lookups)
+	}
+
+	if updates := b.cursorUpdates(time.Now()); len(updates) != 0 {
+		t.Errorf("expected no update without cursor moves, got %d", len(updates))
+	}
+}
+
+// TestCursorUpdatesEvictStaleCursors makes sure cursors not moved within the TTL are removed
+func TestCursorUpdatesEvictStaleCursors(t *testing.T) {
+	b := newCursorTestService(&fakeCursorDataSvc{})
+	ctx := context.Background()
+
+	if _, err, _ := b.CursorMove(ctx, "storyboard", "user-1", `{"x":1,"y":1}`); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err, _ := b.CursorMove(ctx, "storyboard", "user-2", `{"x":2,"y":2}`); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	b.cursorUpdates(time.Now())
+
+	updates := b.cursorUpdates(time.Now().Add(cursorTTL + time.Second))
+	if cursors := cursorsUpdate(t, updates["storyboard"]); len(cursors) != 0 {
+		t.Errorf("expected the stale cursors to be evicted, got %+v", cursors)
+	}
+
+	if _, err, _ := b.CursorMove(ctx, "storyboard", "user-1", `{"x":"left"}`); err == nil {
+		t.Error("expected an error for an invalid cursor position")
+	}
+}
@@ -0,0 +1,74 @@
+package storyboard
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+// mermaidNodeWarnThreshold is the node count past which mermaid diagrams become hard to render
+const mermaidNodeWarnThreshold = 50
+
+// ExportToMermaid exports the storyboard as a Mermaid flowchart with the goals, columns and stories as nodes
+func (b *Service) ExportToMermaid(ctx context.Context, storyboardID string) (string, error) {
+	sb, err := b.StoryboardService.GetStoryboardByID(storyboardID, "")
+	if err != nil {
+		return "", err
+	}
+
+	flowchart, nodeCount := mermaidFlowchart(sb)
+	if nodeCount > mermaidNodeWarnThreshold {
+		b.logger.Ctx(ctx).Warn("storyboard mermaid export exceeds recommended node count",
+			zap.String("storyboard_id", storyboardID), zap.Int("node_count", nodeCount))
+	}
+
+	return flowchart, nil
+}
+
+// mermaidFlowchart builds the Mermaid flowchart text for the storyboard returning it along with its node count
+func mermaidFlowchart(sb *thunderdome.Storyboard) (string, int) {
+	var builder strings.Builder
+	nodeCount := 1
+
+	builder.WriteString("flowchart LR\n")
+	builder.WriteString(fmt.Sprintf("    storyboard[\"%s\"]\n", mermaidLabel(sb.Name)))
+
+	for gi, goal := range sb.Goals {
+		goalNode := fmt.Sprintf("g%d", gi+1)
+		builder.WriteString(fmt.Sprintf("    storyboard --> %s[\"%s\"]\n", goalNode, mermaidLabel(goal.Name)))
+		nodeCount++
+
+		for ci, column := range goal.Columns {
+			columnNode := fmt.Sprintf("%sc%d", goalNode, ci+1)
+			builder.WriteString(fmt.Sprintf("    %s --> %s[\"%s\"]\n", goalNode, columnNode, mermaidLabel(column.Name)))
+			nodeCount++
+
+			for si, story := range column.Stories {
+				storyNode := fmt.Sprintf("%ss%d", columnNode, si+1)
+				builder.WriteString(fmt.Sprintf("    %s --> %s[\"%s\"]\n", columnNode, storyNode, mermaidLabel(story.Name)))
+				if story.Closed {
+					builder.WriteString(fmt.Sprintf("    class %s closed\n", storyNode))
+				}
+				nodeCount++
+			}
+		}
+	}
+
+	builder.WriteString("    classDef closed fill:#d1fae5,stroke:#059669\n")
+
+	return builder.String(), nodeCount
+}
+
+// mermaidLabel escapes text for use as a quoted Mermaid node label
+func mermaidLabel(text string) string {
+	label := strings.Join(strings.Fields(text), " ")
+	label = strings.ReplaceAll(label, "#", "#35;")
+	label = strings.ReplaceAll(label, `"`, "#quot;")
+	label = strings.ReplaceAll(label, "<", "#lt;")
+	label = strings.ReplaceAll(label, ">", "#gt;")
+
+	return label
+}
@@ -0,0 +1,82 @@
+package storyboard
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden files")
+
+func fixtureStoryboard() *thunderdome.Storyboard {
+	return &thunderdome.Storyboard{
+		ID:   "storyboard-1",
+		Name: "Checkout \"v2\"",
+		Goals: []*thunderdome.StoryboardGoal{
+			{
+				ID:   "goal-1",
+				Name: "Browse Products",
+				Columns: []*thunderdome.StoryboardColumn{
+					{
+						ID:   "column-1",
+						Name: "Search",
+						Stories: []*thunderdome.StoryboardStory{
+							{ID: "story-1", Name: "Search by keyword", Closed: true},
+							{ID: "story-2", Name: "Filter by price <$50>"},
+						},
+					},
+					{
+						ID:      "column-2",
+						Name:    "Product Page",
+						Stories: []*thunderdome.StoryboardStory{},
+					},
+				},
+			},
+			{
+				ID:   "goal-2",
+				Name: "Purchase",
+				Columns: []*thunderdome.StoryboardColumn{
+					{
+						ID:   "column-3",
+						Name: "Cart",
+						Stories: []*thunderdome.StoryboardStory{
+							{ID: "story-3", Name: "Add item\nto cart #1"},
+						},
+					},
+				},
+			},
+			{
+				ID:      "goal-3",
+				Name:    "Empty Goal",
+				Columns: []*thunderdome.StoryboardColumn{},
+			},
+		},
+	}
+}
+
+// TestMermaidFlowchartGolden compares the mermaid export of the fixture storyboard against the golden file
+func TestMermaidFlowchartGolden(t *testing.T) {
+	golden := filepath.Join("testdata", "storyboard.mmd")
+
+	flowchart, nodeCount := mermaidFlowchart(fixtureStoryboard())
+
+	if *updateGolden {
+		if err := os.WriteFile(golden, []byte(flowchart), 0644); err != nil {
+			t.Fatalf("failed to update golden file: %v", err)
+		}
+	}
+
+	expected, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("failed to read golden file: %v", err)
+	}
+	if flowchart != string(expected) {
+		t.Errorf("mermaid output does not match %s\ngot:\n%s\nexpected:\n%s", golden, flowchart, expected)
+	}
+	if nodeCount != 10 {
+		t.Errorf("expected 10 nodes, got %d", nodeCount)
+	}
+}
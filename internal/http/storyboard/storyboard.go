@@ -25,6 +25,16 @@ type Config struct {
 
 	// Websocket Subdomain (for Websocket origin check)
 	WebsocketSubdomain string
+
+	// Time allowed for connections to be closed cleanly on shutdown
+	ShutdownGracePeriodSec int
+	// Websocket read buffer size in bytes
+	ReadBufferSize int
+	// Websocket write buffer size in bytes
+	WriteBufferSize int
+
+	// Store for messages to replay to clients reconnecting after a shutdown
+	ReplayStore wshub.ReplayStore
 }
 
 type AuthDataSvc interface {
@@ -87,6 +97,7 @@ type Service struct {
 	AuthService           AuthDataSvc
 	StoryboardService     StoryboardDataSvc
 	hub                   *wshub.Hub
+	cursors               *cursorTracker
 }
 
 // New returns a new storyboard with websocket hub/client and event handlers
@@ -106,14 +117,19 @@ func New(
 		UserService:           userService,
 		AuthService:           authService,
 		StoryboardService:     storyboardService,
+		cursors:               newCursorTracker(),
 	}
 
 	sb.hub = wshub.NewHub(logger, wshub.Config{
-		AppDomain:          config.AppDomain,
-		WebsocketSubdomain: config.WebsocketSubdomain,
-		WriteWaitSec:       config.WriteWaitSec,
-		PongWaitSec:        config.PongWaitSec,
-		PingPeriodSec:      config.PingPeriodSec,
+		AppDomain:              config.AppDomain,
+		WebsocketSubdomain:     config.WebsocketSubdomain,
+		ShutdownGracePeriodSec: config.ShutdownGracePeriodSec,
+		ReadBufferSize:         config.ReadBufferSize,
+		WriteBufferSize:        config.WriteBufferSize,
+		ReplayStore:            config.ReplayStore,
+		WriteWaitSec:           config.WriteWaitSec,
+		PongWaitSec:            config.PongWaitSec,
+		PingPeriodSec:          config.PingPeriodSec,
 	}, map[string]func(context.Context, string, string, string) ([]byte, error, bool){
 		"add_goal":              sb.AddGoal,
 		"revise_goal":           sb.ReviseGoal,
@@ -145,6 +161,7 @@ func New(
 		"edit_storyboard":       sb.EditStoryboard,
 		"concede_storyboard":    sb.Delete,
 		"abandon_storyboard":    sb.Abandon,
+		"cursor_move":           sb.CursorMove,
 	},
 		map[string]struct{}{
 			"facilitator_add":    {},
@@ -157,6 +174,7 @@ func New(
 	)
 
 	go sb.hub.Run()
+	go sb.runCursorBroadcast(cursorBroadcastInterval)
 
 	return sb
 }
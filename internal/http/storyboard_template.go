@@ -0,0 +1,345 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+type storyboardTemplateRequestBody struct {
+	Name           string          `json:"name" validate:"required,max=256"`
+	Description    string          `json:"description"`
+	Format         json.RawMessage `json:"format" validate:"required" swaggertype:"object"`
+	IsPublic       bool            `json:"isPublic"`
+	OrganizationID *string         `json:"organizationId" validate:"omitempty,uuid"`
+	TeamID         *string         `json:"teamId" validate:"omitempty,uuid"`
+}
+
+// readStoryboardTemplateBody reads and validates the storyboard template request body,
+// a template belongs to an organization or a team but not both
+func (s *Service) readStoryboardTemplateBody(w http.ResponseWriter, r *http.Request) (*thunderdome.StoryboardTemplate, bool) {
+	var t = storyboardTemplateRequestBody{}
+	body, bodyErr := io.ReadAll(r.Body)
+	if bodyErr != nil {
+		s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, bodyErr.Error()))
+		return nil, false
+	}
+
+	jsonErr := json.Unmarshal(body, &t)
+	if jsonErr != nil {
+		s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, jsonErr.Error()))
+		return nil, false
+	}
+
+	inputErr := validate.Struct(t)
+	if inputErr != nil {
+		s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, inputErr.Error()))
+		return nil, false
+	}
+
+	if t.OrganizationID != nil && t.TeamID != nil {
+		s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "STORYBOARD_TEMPLATE_REQUIRES_ORGANIZATION_OR_TEAM"))
+		return nil, false
+	}
+
+	if _, err := thunderdome.ParseStoryboardTemplateFormat(t.Format); err != nil {
+		s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, err.Error()))
+		return nil, false
+	}
+
+	return &thunderdome.StoryboardTemplate{
+		OrgID:       t.OrganizationID,
+		TeamID:      t.TeamID,
+		Name:        t.Name,
+		Description: t.Description,
+		Format:      t.Format,
+		IsPublic:    t.IsPublic,
+	}, true
+}
+
+// storyboardTemplateRoles gets the user's role in the template's organization and roles for the template's team,
+// a user without a role gets an empty organization role and nil team roles
+func (s *Service) storyboardTemplateRoles(ctx context.Context, userID string, template *thunderdome.StoryboardTemplate) (string, *thunderdome.UserTeamRoleInfo) {
+	var orgRole string
+	var teamRoles *thunderdome.UserTeamRoleInfo
+
+	if template.OrgID != nil {
+		role, err := s.OrganizationDataSvc.OrganizationUserRole(ctx, userID, *template.OrgID)
+		if err == nil {
+			orgRole = role
+		}
+	}
+	if template.TeamID != nil {
+		roles, err := s.TeamDataSvc.TeamUserRolesByUserID(ctx, userID, *template.TeamID)
+		if err == nil {
+			teamRoles = roles
+		}
+	}
+
+	return orgRole, teamRoles
+}
+
+// canViewStoryboardTemplate checks whether the session user may use the storyboard template
+func (s *Service) canViewStoryboardTemplate(ctx context.Context, template *thunderdome.StoryboardTemplate) bool {
+	userID := ctx.Value(contextKeyUserID).(string)
+	userType := ctx.Value(contextKeyUserType).(string)
+	orgRole, teamRoles := s.storyboardTemplateRoles(ctx, userID, template)
+
+	return thunderdome.CanViewStoryboardTemplate(template, userType, orgRole, teamRoles)
+}
+
+// canManageStoryboardTemplate checks whether the session user may create, update or delete the storyboard template
+func (s *Service) canManageStoryboardTemplate(ctx context.Context, template *thunderdome.StoryboardTemplate) bool {
+	userID := ctx.Value(contextKeyUserID).(string)
+	userType := ctx.Value(contextKeyUserType).(string)
+	orgRole, teamRoles := s.storyboardTemplateRoles(ctx, userID, template)
+
+	return thunderdome.CanManageStoryboardTemplate(template, userType, orgRole, teamRoles)
+}
+
+// handleGetStoryboardTemplates gets the storyboard templates available to the organization
+//
+//	@Summary		Get Storyboard Templates
+//	@Description	Gets the public storyboard templates, along with the organization's and its teams' templates
+//	@Description	the user has access to when an organization is given
+//	@Tags			storyboard
+//	@Produce		json
+//	@Param			orgId	query	string	false	"the organization ID"
+//	@Success		200		object	standardJsonResponse{data=[]thunderdome.StoryboardTemplate}
+//	@Failure		400		object	standardJsonResponse{}
+//	@Failure		403		object	standardJsonResponse{}
+//	@Failure		500		object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/storyboard-templates [get]
+func (s *Service) handleGetStoryboardTemplates() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		userType := ctx.Value(contextKeyUserType).(string)
+		orgID := r.URL.Query().Get("orgId")
+		if orgID != "" {
+			if idErr := validate.Var(orgID, "uuid"); idErr != nil {
+				s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+				return
+			}
+			if userType != thunderdome.AdminUserType {
+				if _, err := s.OrganizationDataSvc.OrganizationUserRole(ctx, sessionUserID, orgID); err != nil {
+					s.Failure(w, r, http.StatusForbidden, Errorf(EUNAUTHORIZED, "ORGANIZATION_USER_REQUIRED"))
+					return
+				}
+			}
+		}
+
+		templates, err := s.StoryboardDataSvc.ListTemplatesForOrg(ctx, orgID)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleGetStoryboardTemplates error", zap.Error(err),
+				zap.String("organization_id", orgID), zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		// team templates are only listed to the team's users
+		visible := make([]*thunderdome.StoryboardTemplate, 0, len(templates))
+		for _, template := range templates {
+			if s.canViewStoryboardTemplate(ctx, template) {
+				visible = append(visible, template)
+			}
+		}
+
+		s.Success(w, r, http.StatusOK, visible, nil)
+	}
+}
+
+// handleGetStoryboardTemplate gets a storyboard template
+//
+//	@Summary		Get Storyboard Template
+//	@Description	Gets a storyboard template the user has access to
+//	@Tags			storyboard
+//	@Produce		json
+//	@Param			templateId	path	string	true	"the storyboard template ID"
+//	@Success		200			object	standardJsonResponse{data=thunderdome.StoryboardTemplate}
+//	@Failure		400			object	standardJsonResponse{}
+//	@Failure		403			object	standardJsonResponse{}
+//	@Failure		404			object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/storyboard-templates/{templateId} [get]
+func (s *Service) handleGetStoryboardTemplate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		templateID := vars["templateId"]
+		idErr := validate.Var(templateID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		template, err := s.StoryboardDataSvc.GetTemplate(ctx, templateID)
+		if err != nil {
+			s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, "STORYBOARD_TEMPLATE_NOT_FOUND"))
+			return
+		}
+
+		if !s.canViewStoryboardTemplate(ctx, template) {
+			s.Failure(w, r, http.StatusForbidden, Errorf(EUNAUTHORIZED, "REQUIRES_TEMPLATE_ACCESS"))
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, template, nil)
+	}
+}
+
+// handleStoryboardTemplateCreate creates a storyboard template
+//
+//	@Summary		Create Storyboard Template
+//	@Description	Creates a storyboard template, public templates can only be created by admins,
+//	@Description	organization and team templates by the organization or team admins
+//	@Tags			storyboard
+//	@Produce		json
+//	@Param			template	body	storyboardTemplateRequestBody	true	"new storyboard template object"
+//	@Success		200			object	standardJsonResponse{data=thunderdome.StoryboardTemplate}
+//	@Failure		400			object	standardJsonResponse{}
+//	@Failure		403			object	standardJsonResponse{}
+//	@Failure		500			object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/storyboard-templates [post]
+func (s *Service) handleStoryboardTemplateCreate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+
+		template, ok := s.readStoryboardTemplateBody(w, r)
+		if !ok {
+			return
+		}
+		template.CreatedBy = sessionUserID
+
+		if !s.canManageStoryboardTemplate(ctx, template) {
+			s.Failure(w, r, http.StatusForbidden, Errorf(EUNAUTHORIZED, "REQUIRES_TEMPLATE_ADMIN"))
+			return
+		}
+
+		newTemplate, err := s.StoryboardDataSvc.CreateTemplate(ctx, template)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleStoryboardTemplateCreate error", zap.Error(err),
+				zap.String("template_name", template.Name), zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, newTemplate, nil)
+	}
+}
+
+// handleStoryboardTemplateUpdate updates a storyboard template
+//
+//	@Summary		Update Storyboard Template
+//	@Description	Updates a storyboard template, the organization or team it belongs to can't be changed
+//	@Tags			storyboard
+//	@Produce		json
+//	@Param			templateId	path	string							true	"the storyboard template ID"
+//	@Param			template	body	storyboardTemplateRequestBody	true	"storyboard template object to update"
+//	@Success		200			object	standardJsonResponse{data=thunderdome.StoryboardTemplate}
+//	@Failure		400			object	standardJsonResponse{}
+//	@Failure		403			object	standardJsonResponse{}
+//	@Failure		404			object	standardJsonResponse{}
+//	@Failure		500			object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/storyboard-templates/{templateId} [put]
+func (s *Service) handleStoryboardTemplateUpdate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		vars := mux.Vars(r)
+		templateID := vars["templateId"]
+		idErr := validate.Var(templateID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		template, ok := s.readStoryboardTemplateBody(w, r)
+		if !ok {
+			return
+		}
+
+		existing, err := s.StoryboardDataSvc.GetTemplate(ctx, templateID)
+		if err != nil {
+			s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, "STORYBOARD_TEMPLATE_NOT_FOUND"))
+			return
+		}
+
+		template.ID = templateID
+		template.OrgID = existing.OrgID
+		template.TeamID = existing.TeamID
+		// the user must be able to manage the template both before and after the change
+		if !s.canManageStoryboardTemplate(ctx, existing) || !s.canManageStoryboardTemplate(ctx, template) {
+			s.Failure(w, r, http.StatusForbidden, Errorf(EUNAUTHORIZED, "REQUIRES_TEMPLATE_ADMIN"))
+			return
+		}
+
+		updatedTemplate, err := s.StoryboardDataSvc.UpdateTemplate(ctx, template)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleStoryboardTemplateUpdate error", zap.Error(err),
+				zap.String("template_id", templateID), zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, updatedTemplate, nil)
+	}
+}
+
+// handleStoryboardTemplateDelete deletes a storyboard template
+//
+//	@Summary		Delete Storyboard Template
+//	@Description	Deletes a storyboard template, storyboards created from it are kept
+//	@Tags			storyboard
+//	@Produce		json
+//	@Param			templateId	path	string	true	"the storyboard template ID"
+//	@Success		200			object	standardJsonResponse{}
+//	@Failure		400			object	standardJsonResponse{}
+//	@Failure		403			object	standardJsonResponse{}
+//	@Failure		404			object	standardJsonResponse{}
+//	@Failure		500			object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/storyboard-templates/{templateId} [delete]
+func (s *Service) handleStoryboardTemplateDelete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		vars := mux.Vars(r)
+		templateID := vars["templateId"]
+		idErr := validate.Var(templateID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		template, err := s.StoryboardDataSvc.GetTemplate(ctx, templateID)
+		if err != nil {
+			s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, "STORYBOARD_TEMPLATE_NOT_FOUND"))
+			return
+		}
+
+		if !s.canManageStoryboardTemplate(ctx, template) {
+			s.Failure(w, r, http.StatusForbidden, Errorf(EUNAUTHORIZED, "REQUIRES_TEMPLATE_ADMIN"))
+			return
+		}
+
+		err = s.StoryboardDataSvc.DeleteTemplate(ctx, templateID)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleStoryboardTemplateDelete error", zap.Error(err),
+				zap.String("template_id", templateID), zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, nil, nil)
+	}
+}
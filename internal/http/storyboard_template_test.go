@@ -0,0 +1,171 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+const testStoryboardTemplateFormat = `{"goals":[{"name":"Onboarding","columns":[{"name":"Sign up"}]}]}`
+
+type MockStoryboardDataSvc struct {
+	mock.Mock
+	StoryboardDataSvc
+}
+
+func (m *MockStoryboardDataSvc) CreateStoryboard(ctx context.Context, ownerID string, storyboardName string, joinCode string, facilitatorCode string) (*thunderdome.Storyboard, error) {
+	args := m.Called(ctx, ownerID, storyboardName, joinCode, facilitatorCode)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*thunderdome.Storyboard), args.Error(1)
+}
+
+func (m *MockStoryboardDataSvc) CreateTemplate(ctx context.Context, template *thunderdome.StoryboardTemplate) (*thunderdome.StoryboardTemplate, error) {
+	args := m.Called(ctx, template)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*thunderdome.StoryboardTemplate), args.Error(1)
+}
+
+func (m *MockStoryboardDataSvc) GetTemplate(ctx context.Context, templateID string) (*thunderdome.StoryboardTemplate, error) {
+	args := m.Called(ctx, templateID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*thunderdome.StoryboardTemplate), args.Error(1)
+}
+
+func (m *MockStoryboardDataSvc) ApplyTemplate(ctx context.Context, storyboardID string, format *thunderdome.StoryboardTemplateFormat) ([]*thunderdome.StoryboardGoal, error) {
+	args := m.Called(ctx, storyboardID, format)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*thunderdome.StoryboardGoal), args.Error(1)
+}
+
+func storyboardTemplateRequest(method string, target string, body string, userType string) *http.Request {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	ctx := context.WithValue(req.Context(), contextKeyUserID, testFacilitatorID)
+	ctx = context.WithValue(ctx, contextKeyUserType, userType)
+	return req.WithContext(ctx)
+}
+
+// TestHandleStoryboardTemplateCreateScoping makes sure only admins of the template's scope can create it
+func TestHandleStoryboardTemplateCreateScoping(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		userType       string
+		orgRole        string
+		expectedStatus int
+	}{
+		{name: "org admin creates org template", body: `{"name":"Journey","format":` + testStoryboardTemplateFormat + `,"organizationId":"` + testOrgID + `"}`,
+			userType: thunderdome.RegisteredUserType, orgRole: thunderdome.AdminUserType, expectedStatus: http.StatusOK},
+		{name: "org member creates org template", body: `{"name":"Journey","format":` + testStoryboardTemplateFormat + `,"organizationId":"` + testOrgID + `"}`,
+			userType: thunderdome.RegisteredUserType, orgRole: thunderdome.EntityMemberUserType, expectedStatus: http.StatusForbidden},
+		{name: "user creates public template", body: `{"name":"Journey","format":` + testStoryboardTemplateFormat + `,"isPublic":true}`,
+			userType: thunderdome.RegisteredUserType, expectedStatus: http.StatusForbidden},
+		{name: "admin creates public template", body: `{"name":"Journey","format":` + testStoryboardTemplateFormat + `,"isPublic":true}`,
+			userType: thunderdome.AdminUserType, expectedStatus: http.StatusOK},
+		{name: "org and team template", body: `{"name":"Journey","format":` + testStoryboardTemplateFormat + `,"organizationId":"` + testOrgID + `","teamId":"` + testTeamID + `"}`,
+			userType: thunderdome.AdminUserType, expectedStatus: http.StatusBadRequest},
+		{name: "template without goals", body: `{"name":"Journey","format":{"goals":[]},"isPublic":true}`,
+			userType: thunderdome.AdminUserType, expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStoryboardDataSvc := new(MockStoryboardDataSvc)
+			mockOrgDataSvc := new(MockOrganizationDataService)
+			if tt.orgRole != "" {
+				mockOrgDataSvc.On("OrganizationUserRole", mock.Anything, testFacilitatorID, testOrgID).Return(tt.orgRole, nil)
+			}
+			if tt.expectedStatus == http.StatusOK {
+				mockStoryboardDataSvc.On("CreateTemplate", mock.Anything, mock.MatchedBy(func(template *thunderdome.StoryboardTemplate) bool {
+					return template.Name == "Journey" && template.CreatedBy == testFacilitatorID
+				})).Return(&thunderdome.StoryboardTemplate{ID: "template", Name: "Journey"}, nil)
+			}
+			service := &Service{
+				StoryboardDataSvc:   mockStoryboardDataSvc,
+				OrganizationDataSvc: mockOrgDataSvc,
+				Logger:              otelzap.New(zap.NewNop()),
+			}
+
+			rr := httptest.NewRecorder()
+			service.handleStoryboardTemplateCreate().ServeHTTP(rr, storyboardTemplateRequest("POST", "/storyboard-templates", tt.body, tt.userType))
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			mockStoryboardDataSvc.AssertExpectations(t)
+		})
+	}
+}
+
+// TestHandleStoryboardCreateFromTemplate makes sure a storyboard created from a template starts with its goals,
+// and that templates outside the user's scope can't be used
+func TestHandleStoryboardCreateFromTemplate(t *testing.T) {
+	templateID := "a23e4567-e89b-12d3-a456-426614174000"
+	orgID := testOrgID
+	body := `{"storyboardName":"Checkout","templateId":"` + templateID + `"}`
+
+	t.Run("template in scope", func(t *testing.T) {
+		mockStoryboardDataSvc := new(MockStoryboardDataSvc)
+		mockStoryboardDataSvc.On("GetTemplate", mock.Anything, templateID).Return(&thunderdome.StoryboardTemplate{
+			ID: templateID, IsPublic: true, Format: json.RawMessage(testStoryboardTemplateFormat),
+		}, nil)
+		mockStoryboardDataSvc.On("CreateStoryboard", mock.Anything, testFacilitatorID, "Checkout", "", "").
+			Return(&thunderdome.Storyboard{ID: "storyboard", Name: "Checkout"}, nil)
+		mockStoryboardDataSvc.On("ApplyTemplate", mock.Anything, "storyboard", mock.MatchedBy(func(format *thunderdome.StoryboardTemplateFormat) bool {
+			return len(format.Goals) == 1 && format.Goals[0].Name == "Onboarding" && format.Goals[0].Columns[0].Name == "Sign up"
+		})).Return([]*thunderdome.StoryboardGoal{{ID: "goal", Name: "Onboarding"}}, nil)
+		service := &Service{
+			Config:            &Config{},
+			StoryboardDataSvc: mockStoryboardDataSvc,
+			Logger:            otelzap.New(zap.NewNop()),
+		}
+
+		req := storyboardTemplateRequest("POST", "/users/"+testFacilitatorID+"/storyboards", body, thunderdome.RegisteredUserType)
+		req = mux.SetURLVars(req, map[string]string{"userId": testFacilitatorID})
+		rr := httptest.NewRecorder()
+		service.handleStoryboardCreate().ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), `"Onboarding"`)
+		mockStoryboardDataSvc.AssertExpectations(t)
+	})
+
+	t.Run("template of another organization", func(t *testing.T) {
+		mockStoryboardDataSvc := new(MockStoryboardDataSvc)
+		mockStoryboardDataSvc.On("GetTemplate", mock.Anything, templateID).Return(&thunderdome.StoryboardTemplate{
+			ID: templateID, OrgID: &orgID, Format: json.RawMessage(testStoryboardTemplateFormat),
+		}, nil)
+		mockOrgDataSvc := new(MockOrganizationDataService)
+		mockOrgDataSvc.On("OrganizationUserRole", mock.Anything, testFacilitatorID, testOrgID).Return("", errors.New("ORGANIZATION_USER_NOT_FOUND"))
+		service := &Service{
+			Config:              &Config{},
+			StoryboardDataSvc:   mockStoryboardDataSvc,
+			OrganizationDataSvc: mockOrgDataSvc,
+			Logger:              otelzap.New(zap.NewNop()),
+		}
+
+		req := storyboardTemplateRequest("POST", "/users/"+testFacilitatorID+"/storyboards", body, thunderdome.RegisteredUserType)
+		req = mux.SetURLVars(req, map[string]string{"userId": testFacilitatorID})
+		rr := httptest.NewRecorder()
+		service.handleStoryboardCreate().ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+		mockStoryboardDataSvc.AssertNotCalled(t, "CreateStoryboard", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,83 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+func (m *MockStoryboardDataSvc) GetStoryboardsByUser(userID string, limit int, offset int) ([]*thunderdome.Storyboard, int, error) {
+	args := m.Called(userID, limit, offset)
+	return args.Get(0).([]*thunderdome.Storyboard), args.Int(1), args.Error(2)
+}
+
+// TestHandleGetUserStoryboardsPagination makes sure the page boundaries are passed through
+// and the total count is reported along with the storyboards' team and member count
+func TestHandleGetUserStoryboardsPagination(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		limit          int
+		offset         int
+		expectedPage   int
+		storyboards    []*thunderdome.Storyboard
+		expectedLength int
+	}{
+		{name: "default page", query: "", limit: 20, offset: 0, expectedPage: 1,
+			storyboards: []*thunderdome.Storyboard{
+				{ID: "a", Name: "Checkout", TeamName: "Payments", MemberCount: 4},
+				{ID: "b", Name: "Onboarding", MemberCount: 1},
+			}, expectedLength: 2},
+		{name: "last page", query: "?limit=2&offset=2", limit: 2, offset: 2, expectedPage: 2,
+			storyboards: []*thunderdome.Storyboard{
+				{ID: "c", Name: "Search", MemberCount: 2},
+			}, expectedLength: 1},
+		{name: "past the last page", query: "?limit=2&offset=4", limit: 2, offset: 4, expectedPage: 3,
+			storyboards: []*thunderdome.Storyboard{}, expectedLength: 0},
+		{name: "invalid boundaries use defaults", query: "?limit=0&offset=-1", limit: 20, offset: 0, expectedPage: 1,
+			storyboards: []*thunderdome.Storyboard{}, expectedLength: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStoryboardDataSvc := new(MockStoryboardDataSvc)
+			mockStoryboardDataSvc.On("GetStoryboardsByUser", testFacilitatorID, tt.limit, tt.offset).Return(tt.storyboards, 3, nil)
+			service := &Service{
+				StoryboardDataSvc: mockStoryboardDataSvc,
+				Logger:            otelzap.New(zap.NewNop()),
+			}
+
+			req := storyboardTemplateRequest("GET", "/users/"+testFacilitatorID+"/storyboards"+tt.query, "", thunderdome.RegisteredUserType)
+			req = mux.SetURLVars(req, map[string]string{"userId": testFacilitatorID})
+			rr := httptest.NewRecorder()
+			service.handleGetUserStoryboards().ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+			var body struct {
+				Data []*thunderdome.Storyboard `json:"data"`
+				Meta struct {
+					Count  int `json:"count"`
+					Page   int `json:"page"`
+					Offset int `json:"offset"`
+				} `json:"meta"`
+			}
+			assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+			assert.Len(t, body.Data, tt.expectedLength)
+			assert.Equal(t, 3, body.Meta.Count)
+			assert.Equal(t, tt.expectedPage, body.Meta.Page)
+			assert.Equal(t, tt.offset, body.Meta.Offset)
+			if tt.expectedLength > 0 {
+				assert.Equal(t, tt.storyboards[0].MemberCount, body.Data[0].MemberCount)
+				assert.Equal(t, tt.storyboards[0].TeamName, body.Data[0].TeamName)
+			}
+			mockStoryboardDataSvc.AssertExpectations(t)
+		})
+	}
+}
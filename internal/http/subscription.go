@@ -1,11 +1,15 @@
 package http
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"time"
 
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/http/response"
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/webhook/subscription"
 	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
 	"github.com/gorilla/mux"
 
@@ -85,11 +89,7 @@ func (s *Service) handleGetSubscriptions() http.HandlerFunc {
 			return
 		}
 
-		meta := &pagination{
-			Count:  count,
-			Offset: offset,
-			Limit:  limit,
-		}
+		meta := response.NewMeta(count, limit, offset)
 
 		s.Success(w, r, http.StatusOK, subscriptions, meta)
 	}
@@ -382,3 +382,141 @@ func (s *Service) handleEntityUserUpdateSubscription() http.HandlerFunc {
 		s.Success(w, r, http.StatusOK, subscription, nil)
 	}
 }
+
+// handleBillingWebhookTest sends a test delivery to the billing webhook
+//
+//	@Summary		Test Billing Webhook
+//	@Description	Sends a signed test payload to the billing webhook returning the status and body it responded with, limited to once a minute
+//	@Tags			subscription
+//	@Produce		json
+//	@Success		200	object	standardJsonResponse{data=subscription.WebhookDelivery}
+//	@Failure		400	object	standardJsonResponse{}
+//	@Failure		429	object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/subscriptions/billing-webhook/test [post]
+func (s *Service) handleBillingWebhookTest() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+
+		delivery, err := s.SubscriptionSvc.SendTestWebhook(ctx)
+		if err != nil {
+			switch {
+			case errors.Is(err, subscription.ErrBillingWebhookNotConfigured):
+				s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, err.Error()))
+			case errors.Is(err, subscription.ErrTestWebhookRateLimited):
+				s.Failure(w, r, http.StatusTooManyRequests, Errorf(EINVALID, err.Error()))
+			default:
+				s.Logger.Ctx(ctx).Error("handleBillingWebhookTest error", zap.Error(err),
+					zap.String("session_user_id", sessionUserID))
+				s.Failure(w, r, http.StatusInternalServerError, err)
+			}
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, delivery, nil)
+	}
+}
+
+// handleGetBillingWebhookEvents gets the billing webhook event log
+//
+//	@Summary		Get Billing Webhook Event Log
+//	@Description	Gets the billing webhook delivery attempts of the last 30 days newest first including the payload sent and the response received
+//	@Tags			subscription
+//	@Produce		json
+//	@Param			event_type	query	string	false	"the event type to filter by, one of usage or test"
+//	@Param			status		query	string	false	"the delivery status to filter by, one of success or failure"
+//	@Param			limit		query	int		false	"Max number of results to return"
+//	@Param			offset		query	int		false	"Starting point to return rows from, should be multiplied by limit or 0"
+//	@Success		200			object	standardJsonResponse{data=[]thunderdome.WebhookEvent}
+//	@Failure		400			object	standardJsonResponse{}
+//	@Failure		500			object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/subscriptions/billing-webhook/event-log [get]
+func (s *Service) handleGetBillingWebhookEvents() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		query := r.URL.Query()
+
+		eventType := query.Get("event_type")
+		if typeErr := validate.Var(eventType, "omitempty,oneof=usage test"); typeErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, typeErr.Error()))
+			return
+		}
+		status, statusErr := thunderdome.ParseWebhookEventStatus(query.Get("status"))
+		if statusErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, statusErr.Error()))
+			return
+		}
+		limit, offset := getLimitOffsetFromRequest(r)
+
+		events, count, err := s.SubscriptionDataSvc.GetWebhookEvents(ctx, eventType, status, limit, offset)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleGetBillingWebhookEvents error", zap.Error(err),
+				zap.String("webhook_event_type", eventType), zap.String("webhook_event_status", status),
+				zap.Int("limit", limit), zap.Int("offset", offset),
+				zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		meta := response.NewMeta(count, limit, offset)
+
+		s.Success(w, r, http.StatusOK, events, meta)
+	}
+}
+
+// handleBillingWebhookEventReplay re-delivers a logged billing webhook event
+//
+//	@Summary		Replay Billing Webhook Event
+//	@Description	Re-delivers the payload of a logged billing webhook event with its original Idempotency-Key header returning the status and body it responded with, the replay is logged as a new event
+//	@Tags			subscription
+//	@Produce		json
+//	@Param			eventId	path	string	true	"the webhook event ID"
+//	@Success		200		object	standardJsonResponse{data=subscription.WebhookDelivery}
+//	@Failure		400		object	standardJsonResponse{}
+//	@Failure		404		object	standardJsonResponse{}
+//	@Failure		500		object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/subscriptions/billing-webhook/event-log/{eventId}/replay [post]
+func (s *Service) handleBillingWebhookEventReplay() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		eventID := mux.Vars(r)["eventId"]
+		if idErr := validate.Var(eventID, "required,uuid"); idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		delivery, err := s.SubscriptionSvc.ReplayWebhookEvent(ctx, eventID)
+		if err != nil {
+			switch {
+			case errors.Is(err, subscription.ErrBillingWebhookNotConfigured):
+				s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, err.Error()))
+			case err.Error() == "WEBHOOK_EVENT_NOT_FOUND":
+				s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, err.Error()))
+			default:
+				s.Logger.Ctx(ctx).Error("handleBillingWebhookEventReplay error", zap.Error(err),
+					zap.String("webhook_event_id", eventID), zap.String("session_user_id", sessionUserID))
+				s.Failure(w, r, http.StatusInternalServerError, err)
+			}
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, delivery, nil)
+	}
+}
+
+// recordUsage reports a usage event to the billing integration, failures are logged and never block the request
+func (s *Service) recordUsage(ctx context.Context, orgID string, eventType string) {
+	if s.SubscriptionSvc == nil {
+		return
+	}
+
+	if err := s.SubscriptionSvc.RecordUsageEvent(ctx, orgID, eventType, 1); err != nil {
+		s.Logger.Ctx(ctx).Warn("record usage event error", zap.Error(err),
+			zap.String("organization_id", orgID), zap.String("usage_event_type", eventType))
+	}
+}
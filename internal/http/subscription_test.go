@@ -0,0 +1,107 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/webhook/subscription"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+const testWebhookEventID = "c23e4567-e89b-12d3-a456-426614174000"
+
+func (m *MockSubscriptionDataService) GetWebhookEvents(ctx context.Context, eventType string, status string, limit int, offset int) ([]*thunderdome.WebhookEvent, int, error) {
+	args := m.Called(ctx, eventType, status, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*thunderdome.WebhookEvent), args.Int(1), args.Error(2)
+}
+
+func (m *MockSubscriptionDataService) GetWebhookEvent(ctx context.Context, eventID string) (*thunderdome.WebhookEvent, error) {
+	args := m.Called(ctx, eventID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*thunderdome.WebhookEvent), args.Error(1)
+}
+
+func (m *MockSubscriptionDataService) LogWebhookEvent(ctx context.Context, event *thunderdome.WebhookEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+func TestHandleGetBillingWebhookEvents(t *testing.T) {
+	mockSubDataSvc := new(MockSubscriptionDataService)
+	mockSubDataSvc.On("GetWebhookEvents", mock.Anything, "usage", thunderdome.WebhookEventStatusFailure, 20, 0).
+		Return([]*thunderdome.WebhookEvent{{ID: testWebhookEventID, EventType: "usage", HTTPStatus: http.StatusInternalServerError}}, 1, nil).Once()
+	service := &Service{SubscriptionDataSvc: mockSubDataSvc, Logger: otelzap.New(zap.NewNop())}
+
+	eventLog := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/subscriptions/billing-webhook/event-log?"+query, nil)
+		req = req.WithContext(context.WithValue(req.Context(), contextKeyUserID, testFacilitatorID))
+		rr := httptest.NewRecorder()
+		service.handleGetBillingWebhookEvents().ServeHTTP(rr, req)
+
+		return rr
+	}
+
+	rr := eventLog("event_type=usage&status=failure")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response struct {
+		Data []thunderdome.WebhookEvent `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Len(t, response.Data, 1)
+	assert.Equal(t, testWebhookEventID, response.Data[0].ID)
+
+	assert.Equal(t, http.StatusBadRequest, eventLog("status=pending").Code)
+	assert.Equal(t, http.StatusBadRequest, eventLog("event_type=invoice").Code)
+
+	mockSubDataSvc.AssertExpectations(t)
+}
+
+func TestHandleBillingWebhookEventReplay(t *testing.T) {
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(webhook.Close)
+
+	mockSubDataSvc := new(MockSubscriptionDataService)
+	mockSubDataSvc.On("GetWebhookEvent", mock.Anything, testWebhookEventID).
+		Return(&thunderdome.WebhookEvent{ID: testWebhookEventID, EventType: "usage", Payload: []byte(`{"events":[]}`)}, nil).Once()
+	mockSubDataSvc.On("GetWebhookEvent", mock.Anything, testGameID).Return(nil, fmt.Errorf("WEBHOOK_EVENT_NOT_FOUND")).Once()
+	mockSubDataSvc.On("LogWebhookEvent", mock.Anything, mock.MatchedBy(func(event *thunderdome.WebhookEvent) bool {
+		return event.EventType == "usage" && event.Succeeded
+	})).Return(nil).Once()
+	logger := otelzap.New(zap.NewNop())
+	service := &Service{
+		SubscriptionSvc: subscription.New(subscription.Config{BillingWebhookURL: webhook.URL}, logger, mockSubDataSvc, nil, nil, nil),
+		Logger:          logger,
+	}
+
+	replay := func(eventID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/subscriptions/billing-webhook/event-log/"+eventID+"/replay", nil)
+		req = mux.SetURLVars(req, map[string]string{"eventId": eventID})
+		req = req.WithContext(context.WithValue(req.Context(), contextKeyUserID, testFacilitatorID))
+		rr := httptest.NewRecorder()
+		service.handleBillingWebhookEventReplay().ServeHTTP(rr, req)
+
+		return rr
+	}
+
+	assert.Equal(t, http.StatusOK, replay(testWebhookEventID).Code)
+	assert.Equal(t, http.StatusNotFound, replay(testGameID).Code)
+	assert.Equal(t, http.StatusBadRequest, replay("not-a-uuid").Code)
+
+	mockSubDataSvc.AssertExpectations(t)
+}
@@ -8,9 +8,11 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"go.uber.org/zap"
 
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/http/response"
 	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
 
 	"github.com/gorilla/mux"
@@ -162,11 +164,7 @@ func (s *Service) handleGetTeamUsers() http.HandlerFunc {
 			return
 		}
 
-		meta := &pagination{
-			Count:  userCount,
-			Offset: offset,
-			Limit:  limit,
-		}
+		meta := response.NewMeta(userCount, limit, offset)
 
 		s.Success(w, r, http.StatusOK, users, meta)
 	}
@@ -381,6 +379,15 @@ func (s *Service) handleTeamInviteUser() http.HandlerFunc {
 			return
 		}
 
+		// let existing users know about the invite in app as well
+		if user, userErr := s.UserDataSvc.GetUserByEmail(ctx, userEmail); userErr == nil {
+			s.NotifyUser(ctx, user.ID, thunderdome.NotificationTypeTeamInvite, map[string]string{
+				"teamId":   teamID,
+				"teamName": team.Name,
+				"inviteId": inviteID,
+			})
+		}
+
 		emailErr := s.Email.SendTeamInvite(team.Name, userEmail, inviteID)
 		if emailErr != nil {
 			s.Logger.Ctx(ctx).Error("handleTeamInviteUser error", zap.Error(emailErr),
@@ -674,9 +681,11 @@ func (s *Service) handleTeamRemoveRetro() http.HandlerFunc {
 		if err != nil {
 			s.Logger.Ctx(ctx).Error("handleTeamRemoveRetro error", zap.Error(err), zap.String("team_id", teamID),
 				zap.String("retro_id", retrospectiveID), zap.String("session_user_id", sessionUserID))
-			w.WriteHeader(http.StatusInternalServerError)
+			s.Failure(w, r, http.StatusInternalServerError, err)
 			return
 		}
+
+		s.Success(w, r, http.StatusOK, nil, nil)
 	}
 }
 
@@ -743,9 +752,11 @@ func (s *Service) handleTeamRemoveStoryboard() http.HandlerFunc {
 		if err != nil {
 			s.Logger.Ctx(ctx).Error("handleTeamRemoveStoryboard error", zap.Error(err), zap.String("team_id", teamID),
 				zap.String("storyboard_id", storyboardID), zap.String("session_user_id", sessionUserID))
-			w.WriteHeader(http.StatusInternalServerError)
+			s.Failure(w, r, http.StatusInternalServerError, err)
 			return
 		}
+
+		s.Success(w, r, http.StatusOK, nil, nil)
 	}
 }
 
@@ -789,11 +800,7 @@ func (s *Service) handleGetTeamRetroActions() http.HandlerFunc {
 			return
 		}
 
-		meta := &pagination{
-			Count:  count,
-			Offset: offset,
-			Limit:  limit,
-		}
+		meta := response.NewMeta(count, limit, offset)
 
 		s.Success(w, r, http.StatusOK, actions, meta)
 	}
@@ -868,9 +875,11 @@ func (s *Service) handleDeleteTeamUserInvite() http.HandlerFunc {
 		if err != nil {
 			s.Logger.Ctx(ctx).Error("handleDeleteTeamUserInvite error", zap.Error(err), zap.String("team_id", teamID),
 				zap.String("invite_id", inviteID), zap.String("session_user_id", sessionUserID))
-			w.WriteHeader(http.StatusInternalServerError)
+			s.Failure(w, r, http.StatusInternalServerError, err)
 			return
 		}
+
+		s.Success(w, r, http.StatusOK, nil, nil)
 	}
 }
 
@@ -920,3 +929,182 @@ func (s *Service) handleTeamMetrics() http.HandlerFunc {
 		s.Success(w, r, http.StatusOK, metrics, nil)
 	}
 }
+
+// velocityTrendDefaultWeeks is the number of weeks the velocity trend covers when no since date is given
+const velocityTrendDefaultWeeks = 12
+
+// velocityTrendMaxWeeks is the most weeks the velocity trend covers, earlier since dates are clamped to it
+const velocityTrendMaxWeeks = 104
+
+// velocityTrendDateRange gets the velocity trend since and until dates from the request query,
+// defaulting to the velocityTrendDefaultWeeks up to now and clamped to velocityTrendMaxWeeks
+func velocityTrendDateRange(r *http.Request, now time.Time) (time.Time, time.Time, error) {
+	query := r.URL.Query()
+	until := now
+	if v := query.Get("until"); v != "" {
+		d, err := time.Parse(time.DateOnly, v)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("INVALID_DATE")
+		}
+		until = d
+	}
+	since := until.AddDate(0, 0, -7*velocityTrendDefaultWeeks)
+	if v := query.Get("since"); v != "" {
+		d, err := time.Parse(time.DateOnly, v)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("INVALID_DATE")
+		}
+		since = d
+	}
+	if since.After(until) {
+		return time.Time{}, time.Time{}, errors.New("INVALID_DATE")
+	}
+	if earliest := until.AddDate(0, 0, -7*velocityTrendMaxWeeks); since.Before(earliest) {
+		since = earliest
+	}
+
+	return since, until, nil
+}
+
+// handleTeamVelocityTrend gets the team's completed estimation velocity over time
+//
+//	@Summary		Get Team Velocity Trend
+//	@Description	Get the team's completed poker games, stories and points per week or month, defaults to the last 12 weeks and covers at most 104 weeks
+//	@Tags			team
+//	@Produce		json
+//	@Param			teamId		path	string	true	"the team ID"
+//	@Param			granularity	query	string	false	"week or month, defaults to week"
+//	@Param			since		query	string	false	"the first day to include (YYYY-MM-DD)"
+//	@Param			until		query	string	false	"the last day to include (YYYY-MM-DD)"
+//	@Success		200			object	standardJsonResponse{data=[]thunderdome.VelocityDataPoint}
+//	@Failure		400			object	standardJsonResponse{}
+//	@Failure		500			object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/teams/{teamId}/velocity-trend [get]
+func (s *Service) handleTeamVelocityTrend() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		vars := mux.Vars(r)
+		teamID := vars["teamId"]
+		idErr := validate.Var(teamID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		query := r.URL.Query()
+		granularity := thunderdome.VelocityGranularityWeek
+		if v := query.Get("granularity"); v != "" {
+			granularity = v
+		}
+		if err := validate.Var(granularity, "oneof=week month"); err != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "INVALID_GRANULARITY"))
+			return
+		}
+
+		since, until, err := velocityTrendDateRange(r, time.Now().UTC().Truncate(24*time.Hour))
+		if err != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, err.Error()))
+			return
+		}
+
+		trend, err := s.TeamDataSvc.GetEstimationVelocityTrend(ctx, teamID, granularity, since, until)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleTeamVelocityTrend error", zap.Error(err),
+				zap.String("team_id", teamID), zap.String("granularity", granularity),
+				zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, trend, nil)
+	}
+}
+
+// handleTeamRetroCadence gets how consistently the team holds retros
+//
+//	@Summary		Get Team Retro Cadence
+//	@Description	Get the average days between the team's retros, the longest gap between retros and the last retro date
+//	@Tags			team
+//	@Produce		json
+//	@Param			teamId	path	string	true	"the team ID"
+//	@Success		200		object	standardJsonResponse{data=thunderdome.RetroCadenceStats}
+//	@Failure		400		object	standardJsonResponse{}
+//	@Failure		500		object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/teams/{teamId}/retro-cadence [get]
+func (s *Service) handleTeamRetroCadence() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		vars := mux.Vars(r)
+		teamID := vars["teamId"]
+		idErr := validate.Var(teamID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		stats, err := s.TeamDataSvc.GetRetroCadenceStats(ctx, teamID)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleTeamRetroCadence error", zap.Error(err),
+				zap.String("team_id", teamID), zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, stats, nil)
+	}
+}
+
+// activityScoresDefaultDays is the number of days the member activity scores cover when no since date is given
+const activityScoresDefaultDays = 7
+
+// handleTeamActivityScores gets the team members' activity scores
+//
+//	@Summary		Get Team Member Activity Scores
+//	@Description	Get the team members' weighted activity in the team's poker games, story voting, retros and standups,
+//	@Description	ordered by the highest score, covers the last 7 days when no since date is given
+//	@Tags			team
+//	@Produce		json
+//	@Param			teamId	path	string	true	"the team ID"
+//	@Param			since	query	string	false	"the start date (YYYY-MM-DD)"
+//	@Success		200		object	standardJsonResponse{data=[]thunderdome.MemberActivityScore}
+//	@Failure		400		object	standardJsonResponse{}
+//	@Failure		500		object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/teams/{teamId}/activity-scores [get]
+func (s *Service) handleTeamActivityScores() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		vars := mux.Vars(r)
+		teamID := vars["teamId"]
+		idErr := validate.Var(teamID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		since := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -activityScoresDefaultDays)
+		if v := r.URL.Query().Get("since"); v != "" {
+			d, err := time.Parse(time.DateOnly, v)
+			if err != nil {
+				s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "INVALID_DATE"))
+				return
+			}
+			since = d
+		}
+
+		scores, err := s.TeamDataSvc.ComputeMemberActivityScores(ctx, teamID, since)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleTeamActivityScores error", zap.Error(err),
+				zap.String("team_id", teamID), zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, scores, nil)
+	}
+}
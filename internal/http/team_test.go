@@ -0,0 +1,48 @@
+package http
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestVelocityTrendDateRange makes sure the velocity trend defaults to the last 12 weeks
+// and a since date further back than the max window is clamped
+func TestVelocityTrendDateRange(t *testing.T) {
+	now := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		query         string
+		expectedSince string
+		expectedUntil string
+		expectedErr   string
+	}{
+		{name: "default last 12 weeks", query: "", expectedSince: "2024-12-16", expectedUntil: "2025-03-10"},
+		{name: "until only", query: "until=2025-01-31", expectedSince: "2024-11-08", expectedUntil: "2025-01-31"},
+		{name: "range", query: "since=2025-01-01&until=2025-02-28", expectedSince: "2025-01-01", expectedUntil: "2025-02-28"},
+		{name: "since clamped to max window", query: "since=2000-01-01", expectedSince: "2023-03-13", expectedUntil: "2025-03-10"},
+		{name: "reversed range", query: "since=2025-03-08&until=2025-03-01", expectedErr: "INVALID_DATE"},
+		{name: "invalid date", query: "until=March", expectedErr: "INVALID_DATE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/teams/team/velocity-trend?"+tt.query, nil)
+			since, until, err := velocityTrendDateRange(req, now)
+			if tt.expectedErr != "" {
+				if err == nil || err.Error() != tt.expectedErr {
+					t.Fatalf("expected error %s, got %v", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if since.Format(time.DateOnly) != tt.expectedSince || until.Format(time.DateOnly) != tt.expectedUntil {
+				t.Errorf("expected %s to %s, got %s to %s", tt.expectedSince, tt.expectedUntil,
+					since.Format(time.DateOnly), until.Format(time.DateOnly))
+			}
+		})
+	}
+}
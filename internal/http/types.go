@@ -2,9 +2,17 @@ package http
 
 import (
 	"context"
+	"encoding/json"
+	"net"
 	"net/http"
+	"time"
 
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/http/auth/saml"
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/http/response"
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/oauth"
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/storage"
 	"github.com/StevenWeathers/thunderdome-planning-poker/internal/webhook/subscription"
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/wshub"
 	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
 	"github.com/go-playground/validator/v10"
 	"github.com/gorilla/mux"
@@ -34,6 +42,18 @@ type WebsocketConfig struct {
 
 	// Websocket subdomain (allow websockets to be routed via a subdomain)
 	WebsocketSubdomain string
+
+	// Time allowed for websocket connections to be closed cleanly on shutdown
+	ShutdownGracePeriodSec int
+
+	// How often poker game active users are reconciled with the connected users, 0 disables it
+	ParticipantSyncIntervalSec int
+
+	// Websocket read buffer size in bytes
+	ReadBufferSize int
+
+	// Websocket write buffer size in bytes
+	WriteBufferSize int
 }
 
 type AuthProvider struct {
@@ -41,6 +61,12 @@ type AuthProvider struct {
 	thunderdome.AuthProviderConfig
 }
 
+// SAMLAuthProvider is the SAML 2.0 authentication provider configuration
+type SAMLAuthProvider struct {
+	Enabled bool
+	saml.Config
+}
+
 // Config contains configuration values used by the APIs
 type Config struct {
 	Port                  string
@@ -54,6 +80,17 @@ type Config struct {
 	PathPrefix string
 	// SecureProtocol whether the application is accessed through HTTPS
 	SecureProtocol bool
+	// MobileAppScheme is the custom URL scheme of the mobile app, join deep links redirect to the app
+	// when the request accepts the scheme
+	MobileAppScheme string
+	// AdminIPAllowlist are the ip ranges allowed to reach the admin endpoints, empty allows every ip
+	AdminIPAllowlist []*net.IPNet
+	// TrustedProxies are the reverse proxy ip ranges whose X-Forwarded-For header is trusted for the request ip
+	TrustedProxies []*net.IPNet
+	// GitHubAPIURL is the GitHub REST API url game results are posted to
+	GitHubAPIURL string
+	// GitHubAllowedRepos are the owner/name repositories game results can be posted to, empty allows every repository
+	GitHubAllowedRepos []string
 	// Whether the external API is enabled
 	ExternalAPIEnabled bool
 	// Whether the external API requires user verified email
@@ -77,28 +114,44 @@ type Config struct {
 	// ID of default template to select for Retro creation
 	RetroDefaultTemplateID string
 	// Whether to use the OS filesystem or embedded
-	EmbedUseOS                bool
-	CleanupBattlesDaysOld     int
-	CleanupRetrosDaysOld      int
-	CleanupStoryboardsDaysOld int
-	CleanupGuestsDaysOld      int
-	RequireTeams              bool
-	AuthLdapUrl               string
-	AuthLdapUseTls            bool
-	AuthLdapBindname          string
-	AuthLdapBindpass          string
-	AuthLdapBasedn            string
-	AuthLdapFilter            string
-	AuthLdapMailAttr          string
-	AuthLdapCnAttr            string
-	AuthHeaderUsernameHeader  string
-	AuthHeaderEmailHeader     string
-	AllowGuests               bool
-	AllowRegistration         bool
-	ShowActiveCountries       bool
-	SubscriptionsEnabled      bool
+	EmbedUseOS                  bool
+	CleanupBattlesDaysOld       int
+	CleanupRetrosDaysOld        int
+	CleanupStoryboardsDaysOld   int
+	CleanupGuestsDaysOld        int
+	CleanupNotificationsDaysOld int
+	RequireTeams                bool
+	AuthLdapUrl                 string
+	AuthLdapUseTls              bool
+	AuthLdapBindname            string
+	AuthLdapBindpass            string
+	AuthLdapBasedn              string
+	AuthLdapFilter              string
+	AuthLdapMailAttr            string
+	AuthLdapCnAttr              string
+	AuthHeaderUsernameHeader    string
+	AuthHeaderEmailHeader       string
+	AllowGuests                 bool
+	AllowRegistration           bool
+	ShowActiveCountries         bool
+	SubscriptionsEnabled        bool
+	// Whether story imports update existing stories with a matching reference_id instead of duplicating them
+	ImportDeduplicationEnabled bool
+	// Password strength policy enforced when users register or change their password
+	PasswordPolicy thunderdome.PasswordPolicy
+	// Whether importing poker stories from Asana projects is allowed
+	AllowAsanaImport bool
+	// Whether importing poker stories from Notion databases is allowed
+	AllowNotionImport bool
+	// Whether importing poker stories from CSV files is allowed
+	AllowCsvImport bool
+	// Whether organization admins can bulk import members from a CSV file
+	OrgBulkImportEnabled bool
 
 	GoogleAuth AuthProvider
+	SAMLAuth   SAMLAuthProvider
+	// Story attachment storage, attachments are disabled when the provider is empty
+	Storage storage.Config
 	WebsocketConfig
 }
 
@@ -121,25 +174,31 @@ type Service struct {
 	OrganizationDataSvc  OrganizationDataSvc
 	AdminDataSvc         AdminDataSvc
 	JiraDataSvc          JiraDataSvc
+	AsanaDataSvc         AsanaDataSvc
+	NotionDataSvc        NotionDataSvc
 	SubscriptionDataSvc  SubscriptionDataSvc
 	RetroTemplateDataSvc RetroTemplateDataSvc
+	NotificationDataSvc  NotificationDataSvc
 	SubscriptionSvc      *subscription.Service
+	StorageSvc           storage.StorageService
+	// Store for websocket messages to replay to clients reconnecting after a shutdown
+	WebsocketReplayStore wshub.ReplayStore
+	// Store for oauth state tokens so each login callback can only be used once
+	OAuthStateStore oauth.StateStore
+
+	// websocket services closed on shutdown
+	websocketServices []websocketService
 }
 
-// standardJsonResponse structure used for all restful APIs response body
-type standardJsonResponse struct {
-	Success bool        `json:"success"`
-	Error   string      `json:"error"`
-	Data    interface{} `json:"data" swaggertype:"object"`
-	Meta    interface{} `json:"meta" swaggertype:"object"`
+// websocketService is a websocket hub backed service that can be gracefully shut down
+type websocketService interface {
+	Shutdown(ctx context.Context) error
+	EvictIdleConnections(idleMinutes int) int
+	SendToUser(userID string, data []byte)
 }
 
-// pagination meta structure for query result pagination
-type pagination struct {
-	Count  int `json:"count"`
-	Limit  int `json:"limit"`
-	Offset int `json:"offset"`
-}
+// standardJsonResponse structure used for all restful APIs response body
+type standardJsonResponse = response.Envelope
 
 type contextKey string
 
@@ -150,6 +209,7 @@ type CookieManager interface {
 	ClearUserCookies(w http.ResponseWriter)
 	ValidateUserCookie(w http.ResponseWriter, r *http.Request) (string, error)
 	ValidateSessionCookie(w http.ResponseWriter, r *http.Request) (string, error)
+	ValidateSessionCookieIssuedAt(w http.ResponseWriter, r *http.Request) (string, time.Time, error)
 	CreateCookie(w http.ResponseWriter, cookieName string, value string, maxAge int) error
 	GetCookie(w http.ResponseWriter, r *http.Request, cookieName string) (string, error)
 	DeleteCookie(w http.ResponseWriter, cookieName string)
@@ -160,6 +220,18 @@ type CookieManager interface {
 
 type AdminDataSvc interface {
 	GetAppStats(ctx context.Context) (*thunderdome.ApplicationStats, error)
+	CleanupOldGames(ctx context.Context, daysOld int, dryRun bool) (*thunderdome.CleanupResult, error)
+	ApproveUser(ctx context.Context, userID string) error
+	RejectUser(ctx context.Context, userID string, reason string) error
+	GetUsersByRegistrationStatus(ctx context.Context, status thunderdome.RegistrationStatus, limit int, offset int) ([]*thunderdome.User, int, error)
+	GetEstimationCalibration(ctx context.Context, orgID string, since time.Time) (*thunderdome.CalibrationReport, error)
+	GetUserEstimationBias(ctx context.Context, userID string, since time.Time) (*thunderdome.EstimationBias, error)
+	GetMigrationStatus(ctx context.Context) (*thunderdome.MigrationStatus, error)
+	GetInvalidStoryLinks(ctx context.Context, limit int, offset int) ([]*thunderdome.InvalidStoryLink, int, error)
+	MergeUsers(ctx context.Context, sourceUserID string, targetUserID string) error
+	AnonymizeUser(ctx context.Context, userID string) error
+	OverrideSubscription(ctx context.Context, actorID string, orgID string, tier string, expiresAt time.Time, reason string) error
+	ExtendSubscription(ctx context.Context, actorID string, orgID string, days int) error
 }
 
 type AlertDataSvc interface {
@@ -177,6 +249,8 @@ type APIKeyDataSvc interface {
 	GetAPIKeys(ctx context.Context, limit int, offset int) []*thunderdome.UserAPIKey
 	UpdateUserAPIKey(ctx context.Context, userID string, keyID string, active bool) ([]*thunderdome.APIKey, error)
 	DeleteUserAPIKey(ctx context.Context, userID string, keyID string) ([]*thunderdome.APIKey, error)
+	RecordAPIKeyUsage(ctx context.Context, apiKey string, endpoint string) error
+	GetAPIKeyUsage(ctx context.Context, userID string, keyID string, since time.Time, until time.Time) ([]*thunderdome.APIKeyUsage, error)
 }
 
 type AuthDataSvc interface {
@@ -184,6 +258,7 @@ type AuthDataSvc interface {
 	OauthCreateNonce(ctx context.Context) (string, error)
 	OauthValidateNonce(ctx context.Context, nonceId string) error
 	OauthAuthUser(ctx context.Context, provider string, sub string, email string, emailVerified bool, name string, pictureUrl string) (*thunderdome.User, string, error)
+	SAMLAuthUser(ctx context.Context, nameID string, name string) (*thunderdome.User, string, error)
 	UserResetRequest(ctx context.Context, email string) (resetID string, userName string, resetErr error)
 	UserResetPassword(ctx context.Context, resetID string, password string) (userName string, email string, resetErr error)
 	UserUpdatePassword(ctx context.Context, userID string, password string) (name string, email string, resetErr error)
@@ -193,7 +268,7 @@ type AuthDataSvc interface {
 	MFASetupValidate(ctx context.Context, userID string, secret string, passcode string) error
 	MFARemove(ctx context.Context, userID string) error
 	MFATokenValidate(ctx context.Context, sessionId string, passcode string) error
-	CreateSession(ctx context.Context, userId string, enabled bool) (string, error)
+	CreateSession(ctx context.Context, userId string, enabled bool, authMethod string) (string, error)
 	EnableSession(ctx context.Context, sessionId string) error
 	GetSessionUserByID(ctx context.Context, sessionId string) (*thunderdome.User, error)
 	DeleteSession(ctx context.Context, sessionId string) error
@@ -208,14 +283,40 @@ type CheckinDataSvc interface {
 	CheckinCommentEdit(ctx context.Context, teamID string, userId string, commentId string, comment string) error
 	CheckinCommentDelete(ctx context.Context, commentId string) error
 	CheckinLastByUser(ctx context.Context, teamID string, userId string) (*thunderdome.TeamCheckin, error)
+	CreateStandup(ctx context.Context, teamID string, userID string, yesterday string, today string, blockers string) (*thunderdome.Standup, error)
+	GetStandupsByTeam(ctx context.Context, teamID string, startDate time.Time, endDate time.Time) ([]*thunderdome.Standup, error)
+	DeleteStandup(ctx context.Context, teamID string, standupID string) error
+	GetStandupDigest(ctx context.Context, teamID string, startDate time.Time, endDate time.Time) ([]*thunderdome.StandupDigestEntry, error)
+	CheckinScheduleGet(ctx context.Context, teamID string) (*thunderdome.CheckinSchedule, error)
+	CheckinScheduleUpsert(ctx context.Context, teamID string, cronExpression string, timezoneOffset int, reminderEnabled bool) (*thunderdome.CheckinSchedule, error)
+	CheckinScheduleDelete(ctx context.Context, teamID string) error
 }
 
 type JiraDataSvc interface {
 	FindInstancesByUserID(ctx context.Context, userId string) ([]thunderdome.JiraInstance, error)
 	GetInstanceByID(ctx context.Context, instanceId string) (thunderdome.JiraInstance, error)
-	CreateInstance(ctx context.Context, userId string, host string, clientMail string, accessToken string, jiraDataCenter bool) (thunderdome.JiraInstance, error)
-	UpdateInstance(ctx context.Context, instanceId string, host string, clientMail string, accessToken string) (thunderdome.JiraInstance, error)
+	CreateInstance(ctx context.Context, userId string, host string, clientMail string, accessToken string, jiraDataCenter bool, storyPointsFieldID string) (thunderdome.JiraInstance, error)
+	UpdateInstance(ctx context.Context, instanceId string, host string, clientMail string, accessToken string, storyPointsFieldID string) (thunderdome.JiraInstance, error)
 	DeleteInstance(ctx context.Context, instanceId string) error
+	UpdateJiraIssuePoints(ctx context.Context, instanceID string, issueKey string, points string) error
+}
+
+type AsanaDataSvc interface {
+	FindConnectionsByUserID(ctx context.Context, userID string) ([]thunderdome.AsanaConnection, error)
+	GetConnectionByID(ctx context.Context, connectionID string) (thunderdome.AsanaConnection, error)
+	CreateConnection(ctx context.Context, userID string, name string, accessToken string) (thunderdome.AsanaConnection, error)
+	UpdateConnection(ctx context.Context, connectionID string, name string, accessToken string) (thunderdome.AsanaConnection, error)
+	DeleteConnection(ctx context.Context, connectionID string) error
+	ImportTasksFromProject(ctx context.Context, connectionID string, projectID string, sectionID *string) ([]*thunderdome.Story, error)
+}
+
+type NotionDataSvc interface {
+	FindConnectionsByUserID(ctx context.Context, userID string) ([]thunderdome.NotionConnection, error)
+	GetConnectionByID(ctx context.Context, connectionID string) (thunderdome.NotionConnection, error)
+	CreateConnection(ctx context.Context, userID string, name string, accessToken string, descriptionProperty string) (thunderdome.NotionConnection, error)
+	UpdateConnection(ctx context.Context, connectionID string, name string, accessToken string, descriptionProperty string) (thunderdome.NotionConnection, error)
+	DeleteConnection(ctx context.Context, connectionID string) error
+	ImportPagesFromDatabase(ctx context.Context, connectionID string, databaseID string, filter *thunderdome.NotionFilter) ([]*thunderdome.Story, error)
 }
 
 type OrganizationDataSvc interface {
@@ -224,6 +325,8 @@ type OrganizationDataSvc interface {
 	OrganizationListByUser(ctx context.Context, userID string, limit int, offset int) []*thunderdome.UserOrganization
 	OrganizationCreate(ctx context.Context, userID string, orgName string) (*thunderdome.Organization, error)
 	OrganizationUpdate(ctx context.Context, orgID string, orgName string) (*thunderdome.Organization, error)
+	OrganizationUpdateSSORequired(ctx context.Context, orgID string, ssoRequired bool) (*thunderdome.Organization, error)
+	SetDefaultEstimationScale(ctx context.Context, orgID string, scaleID string) error
 	OrganizationUserList(ctx context.Context, orgID string, limit int, offset int) []*thunderdome.OrganizationUser
 	OrganizationAddUser(ctx context.Context, orgID string, userID string, Role string) (string, error)
 	OrganizationUpsertUser(ctx context.Context, orgID string, userID string, Role string) (string, error)
@@ -240,6 +343,8 @@ type OrganizationDataSvc interface {
 	OrganizationList(ctx context.Context, limit int, offset int) []*thunderdome.Organization
 	OrganizationIsSubscribed(ctx context.Context, orgID string) (bool, error)
 	GetOrganizationMetrics(ctx context.Context, organizationID string) (*thunderdome.OrganizationMetrics, error)
+	GetActiveGamesForOrg(ctx context.Context, orgID string) ([]*thunderdome.Poker, error)
+	OrganizationImportUsers(ctx context.Context, orgID string, rows []thunderdome.OrganizationMemberImportRow) ([]thunderdome.OrganizationMemberImportError, error)
 
 	DepartmentUserRole(ctx context.Context, userID string, orgID string, departmentID string) (string, string, error)
 	DepartmentGetByID(ctx context.Context, departmentID string) (*thunderdome.Department, error)
@@ -269,6 +374,7 @@ type TeamDataSvc interface {
 	TeamCreate(ctx context.Context, userID string, teamName string) (*thunderdome.Team, error)
 	TeamUpdate(ctx context.Context, teamID string, teamName string) (*thunderdome.Team, error)
 	TeamAddUser(ctx context.Context, teamID string, userID string, role string) (string, error)
+	TeamAddUserByNames(ctx context.Context, organizationID string, userID string, teamNames []string, role string) error
 	TeamUserList(ctx context.Context, teamID string, limit int, offset int) ([]*thunderdome.TeamUser, int, error)
 	TeamUpdateUser(ctx context.Context, teamID string, userID string, role string) (string, error)
 	TeamRemoveUser(ctx context.Context, teamID string, userID string) error
@@ -289,6 +395,9 @@ type TeamDataSvc interface {
 	TeamList(ctx context.Context, limit int, offset int) ([]*thunderdome.Team, int)
 	TeamIsSubscribed(ctx context.Context, teamID string) (bool, error)
 	GetTeamMetrics(ctx context.Context, teamID string) (*thunderdome.TeamMetrics, error)
+	GetEstimationVelocityTrend(ctx context.Context, teamID string, granularity string, since time.Time, until time.Time) ([]thunderdome.VelocityDataPoint, error)
+	GetRetroCadenceStats(ctx context.Context, teamID string) (*thunderdome.RetroCadenceStats, error)
+	ComputeMemberActivityScores(ctx context.Context, teamID string, since time.Time) ([]thunderdome.MemberActivityScore, error)
 	TeamUserRolesByUserID(ctx context.Context, userID string, teamID string) (*thunderdome.UserTeamRoleInfo, error)
 }
 
@@ -301,6 +410,8 @@ type SubscriptionDataSvc interface {
 	UpdateSubscription(ctx context.Context, subscriptionID string, subscription thunderdome.Subscription) (thunderdome.Subscription, error)
 	GetSubscriptions(ctx context.Context, limit int, offset int) ([]thunderdome.Subscription, int, error)
 	DeleteSubscription(ctx context.Context, subscriptionID string) error
+	GetOrgTier(ctx context.Context, orgID string) (thunderdome.SubscriptionTier, error)
+	GetWebhookEvents(ctx context.Context, eventType string, status string, limit int, offset int) ([]*thunderdome.WebhookEvent, int, error)
 }
 
 type UserDataSvc interface {
@@ -323,21 +434,53 @@ type UserDataSvc interface {
 	CleanGuests(ctx context.Context, daysOld int) error
 	GetActiveCountries(ctx context.Context) ([]string, error)
 	GetUserCredentialByUserID(ctx context.Context, userID string) (*thunderdome.Credential, error)
+	InvalidateAllSessions(ctx context.Context, userID string) error
+	GetSessionsInvalidatedAt(ctx context.Context, userID string) (*time.Time, error)
 }
 
 type PokerDataSvc interface {
 	// CreateGame creates a new poker game
-	CreateGame(ctx context.Context, facilitatorID string, name string, estimationScaleID string, pointValuesAllowed []string, stories []*thunderdome.Story, autoFinishVoting bool, pointAverageRounding string, joinCode string, facilitatorCode string, hideVoterIdentity bool) (*thunderdome.Poker, error)
+	CreateGame(ctx context.Context, facilitatorID string, name string, estimationScaleID string, pointValuesAllowed []string, stories []*thunderdome.Story, autoFinishVoting bool, pointAverageRounding string, joinCode string, facilitatorCode string, observerCode string, hideVoterIdentity bool, autoFinalizeOnConsensus bool, minParticipants int, timeBoxMinutes int, quorumPercentage float64, storyTypeScaleMap map[string]string) (*thunderdome.Poker, error)
 	// TeamCreateGame creates a new poker game for a team
-	TeamCreateGame(ctx context.Context, teamID string, facilitatorID string, name string, estimationScaleID string, pointValuesAllowed []string, stories []*thunderdome.Story, autoFinishVoting bool, pointAverageRounding string, joinCode string, facilitatorCode string, hideVoterIdentity bool) (*thunderdome.Poker, error)
+	TeamCreateGame(ctx context.Context, teamID string, facilitatorID string, name string, estimationScaleID string, pointValuesAllowed []string, stories []*thunderdome.Story, autoFinishVoting bool, pointAverageRounding string, joinCode string, facilitatorCode string, observerCode string, hideVoterIdentity bool, autoFinalizeOnConsensus bool, minParticipants int, timeBoxMinutes int, quorumPercentage float64, storyTypeScaleMap map[string]string) (*thunderdome.Poker, error)
 	// UpdateGame updates an existing poker game
-	UpdateGame(pokerID string, name string, pointValuesAllowed []string, autoFinishVoting bool, pointAverageRounding string, hideVoterIdentity bool, joinCode string, facilitatorCode string, teamID string) error
+	UpdateGame(pokerID string, name string, pointValuesAllowed []string, autoFinishVoting bool, pointAverageRounding string, hideVoterIdentity bool, autoFinalizeOnConsensus bool, minParticipants int, timeBoxMinutes int, quorumPercentage float64, storyTypeScaleMap map[string]string, joinCode string, facilitatorCode string, observerCode string, teamID string) error
 	// GetFacilitatorCode retrieves the facilitator code for a poker game
 	GetFacilitatorCode(pokerID string) (string, error)
+	// GetObserverCode retrieves the observer code for a poker game
+	GetObserverCode(pokerID string) (string, error)
+	// RegenerateJoinCode replaces the join code of a poker game with a new random code
+	RegenerateJoinCode(ctx context.Context, pokerID string, facilitatorID string) (string, error)
+	// RegenerateFacilitatorCode replaces the facilitator code of a poker game with a new random code
+	RegenerateFacilitatorCode(ctx context.Context, pokerID string, facilitatorID string) (string, error)
+	// GenerateJoinDeepLink gets the link that joins a poker game with its join code in one step
+	GenerateJoinDeepLink(ctx context.Context, pokerID string) (string, error)
+	// JoinGame adds a user to a poker game without activating them
+	JoinGame(ctx context.Context, pokerID string, userID string) error
+	// SearchStories retrieves the stories of a poker game matching the full-text query in the search field
+	SearchStories(ctx context.Context, pokerID string, userID string, query string, field thunderdome.StorySearchField) ([]*thunderdome.Story, error)
+	// DetectDuplicateStories retrieves the groups of a poker game's stories with names similar at or above the threshold
+	DetectDuplicateStories(ctx context.Context, pokerID string, threshold float64) ([]thunderdome.DuplicateGroup, error)
+	// GetStoriesByReferenceID retrieves the stories with the reference ID across the team's poker games
+	GetStoriesByReferenceID(ctx context.Context, teamID string, referenceID string) ([]*thunderdome.StoryWithGame, error)
+	// CrossGameStoryTypeComparison retrieves the report comparing the final points of a story type across the team's poker games
+	CrossGameStoryTypeComparison(ctx context.Context, teamID string, storyType string, since time.Time) (*thunderdome.StoryTypeReport, error)
+	// GetGameTemplates retrieves the team's poker game templates
+	GetGameTemplates(ctx context.Context, teamID string) ([]*thunderdome.GameTemplate, error)
+	// GetGameTemplateByID retrieves a team's poker game template
+	GetGameTemplateByID(ctx context.Context, teamID string, templateID string) (*thunderdome.GameTemplate, error)
+	// CreateGameTemplate creates a poker game template for the team
+	CreateGameTemplate(ctx context.Context, teamID string, userID string, template *thunderdome.GameTemplate) (*thunderdome.GameTemplate, error)
+	// UpdateGameTemplate updates a team's poker game template
+	UpdateGameTemplate(ctx context.Context, teamID string, templateID string, template *thunderdome.GameTemplate) (*thunderdome.GameTemplate, error)
+	// DeleteGameTemplate deletes a team's poker game template
+	DeleteGameTemplate(ctx context.Context, teamID string, templateID string) error
 	// GetGameByID retrieves a poker game by its ID
 	GetGameByID(pokerID string, userID string) (*thunderdome.Poker, error)
 	// GetGamesByUser retrieves a list of poker games for a user
 	GetGamesByUser(userID string, limit int, offset int) ([]*thunderdome.Poker, int, error)
+	// GetArchivedGamesByUser retrieves a list of archived poker games for a user
+	GetArchivedGamesByUser(userID string, limit int, offset int) ([]*thunderdome.Poker, int, error)
 	// ConfirmFacilitator confirms a user as a facilitator for a poker game
 	ConfirmFacilitator(pokerID string, userID string) error
 	// GetUserActiveStatus retrieves the active status of a user in a poker game
@@ -350,26 +493,100 @@ type PokerDataSvc interface {
 	AddUser(pokerID string, userID string) ([]*thunderdome.PokerUser, error)
 	// RetreatUser sets a user as inactive in a poker game
 	RetreatUser(pokerID string, userID string) []*thunderdome.PokerUser
+	// DeactivateDisconnectedUsers sets active poker game users that aren't connected to their game inactive
+	DeactivateDisconnectedUsers(ctx context.Context, connectedUsers map[string][]string) ([]string, error)
+	// AddChatMessage adds a chat message to a poker game, spectator only messages are only seen by spectators and facilitators
+	AddChatMessage(ctx context.Context, gameID string, userID string, message string, spectatorOnly bool) (*thunderdome.GameChat, error)
+	// GetChatHistory retrieves the chat messages of a poker game sent after since
+	GetChatHistory(ctx context.Context, gameID string, since time.Time) ([]thunderdome.GameChat, error)
 	// AbandonGame sets a user as abandoned in a poker game
 	AbandonGame(pokerID string, userID string) ([]*thunderdome.PokerUser, error)
 	// AddFacilitator adds a facilitator to a poker game
 	AddFacilitator(pokerID string, userID string) ([]string, error)
 	// RemoveFacilitator removes a facilitator from a poker game
 	RemoveFacilitator(pokerID string, userID string) ([]string, error)
+	// GetFacilitators retrieves the facilitators of a poker game ordered by when they became a facilitator
+	GetFacilitators(pokerID string) ([]*thunderdome.PokerFacilitator, error)
+	// TransferPrimaryFacilitator hands the primary facilitator role over to another facilitator of a poker game
+	TransferPrimaryFacilitator(ctx context.Context, pokerID string, currentPrimaryID string, newPrimaryID string) error
 	// ToggleSpectator toggles a user's spectator status in a poker game
 	ToggleSpectator(pokerID string, userID string, spectator bool) ([]*thunderdome.PokerUser, error)
-	// DeleteGame deletes a poker game
+	// SetObserver makes a user an observer of a poker game
+	SetObserver(pokerID string, userID string) ([]*thunderdome.PokerUser, error)
+	// IsObserver checks whether a user joined a poker game as an observer
+	IsObserver(pokerID string, userID string) (bool, error)
+	// DeleteGame permanently deletes a poker game
 	DeleteGame(pokerID string) error
+	// ArchiveGame archives a poker game, keeping it for auditing
+	ArchiveGame(ctx context.Context, pokerID string) error
+	// IsGameArchived checks whether a poker game has been archived
+	IsGameArchived(ctx context.Context, pokerID string) (bool, error)
+	// RestoreGame restores an archived poker game
+	RestoreGame(ctx context.Context, pokerID string) error
+	// RebuildGameState rebuilds a poker game's state by replaying its event log, updating the stored and cached game
+	RebuildGameState(ctx context.Context, pokerID string) (*thunderdome.Poker, error)
 	// AddFacilitatorsByEmail adds facilitators to a poker game by email
 	AddFacilitatorsByEmail(ctx context.Context, pokerID string, facilitatorEmails []string) ([]string, error)
 	// GetGames retrieves a list of poker games
 	GetGames(limit int, offset int) ([]*thunderdome.Poker, int, error)
+	// GetArchivedGames retrieves a list of archived poker games
+	GetArchivedGames(limit int, offset int) ([]*thunderdome.Poker, int, error)
 	// GetActiveGames retrieves a list of active poker games
 	GetActiveGames(limit int, offset int) ([]*thunderdome.Poker, int, error)
 	// PurgeOldGames purges poker games older than a specified number of days
 	PurgeOldGames(ctx context.Context, daysOld int) error
 	// GetStories retrieves a list of stories in a poker game
 	GetStories(pokerID string, userID string) []*thunderdome.Story
+	// SetFacilitatorNote sets the private facilitator notes of a story in a poker game
+	SetFacilitatorNote(ctx context.Context, storyID string, facilitatorID string, notes string) error
+	// ComputeEstimationAccuracy computes the poker game participant estimation accuracy leaderboard
+	ComputeEstimationAccuracy(ctx context.Context, pokerID string) ([]thunderdome.ParticipantAccuracy, error)
+	// GetUserEstimationHistory gets the user's votes on finalized stories compared to their final points
+	GetUserEstimationHistory(ctx context.Context, userID string, limit int, offset int) ([]thunderdome.UserEstimationRecord, error)
+	// GetGameStatistics gets the poker game voting statistics computed from its voting round history
+	GetGameStatistics(ctx context.Context, pokerID string) (*thunderdome.GameStatistics, error)
+	// GetGameCompletionStats gets how many of the poker game's stories and points have been finalized
+	GetGameCompletionStats(ctx context.Context, pokerID string) (*thunderdome.CompletionStats, error)
+	// LogAccess records a user joining or leaving a poker game
+	LogAccess(ctx context.Context, pokerID string, userID string, eventType string, ip string, userAgent string) error
+	// StartStoryTimeBox starts the game's time box for the story, nil when the game has no time box
+	StartStoryTimeBox(ctx context.Context, pokerID string, storyID string) (*thunderdome.PokerTimeBox, error)
+	// ExtendTimeBox adds the seconds to the game's running story time box
+	ExtendTimeBox(ctx context.Context, pokerID string, additionalSeconds int) error
+	// GetStoryTimeBox retrieves the game's story time box, nil when there isn't one
+	GetStoryTimeBox(ctx context.Context, pokerID string) (*thunderdome.PokerTimeBox, error)
+	// GetRunningTimeBox retrieves the game's story time box and how much of it is left by the database clock, nil when there isn't one
+	GetRunningTimeBox(ctx context.Context, pokerID string) (*thunderdome.PokerRunningTimeBox, error)
+	// GetRunningTimeBoxes retrieves every game's story time box and how much of it is left by the database clock
+	GetRunningTimeBoxes(ctx context.Context) ([]*thunderdome.PokerRunningTimeBox, error)
+	// ExpireStoryTimeBox removes the story's time box once it has run out, returning whether it was removed
+	ExpireStoryTimeBox(ctx context.Context, pokerID string, storyID string) (bool, error)
+	// StopStoryTimeBox removes the game's story time box
+	StopStoryTimeBox(ctx context.Context, pokerID string) error
+	// SubscribeTimeBoxEvents receives the time box events published by every instance
+	SubscribeTimeBoxEvents(ctx context.Context) <-chan thunderdome.PokerTimeBoxEvent
+	// GetAccessLog gets the poker game access log newest first, optionally filtered by event type
+	GetAccessLog(ctx context.Context, pokerID string, eventType string, limit int, offset int) ([]*thunderdome.PokerAccessLog, int, error)
+	// ExportParticipants exports the poker game participants' attendance as csv for the game facilitators
+	ExportParticipants(ctx context.Context, pokerID string, requestingUserID string) ([]byte, error)
+	// ConfirmStoryUser checks the user is a user of the story's poker game
+	ConfirmStoryUser(ctx context.Context, storyID string, userID string) error
+	// CreateStoryAttachment records a file uploaded to a poker story
+	CreateStoryAttachment(ctx context.Context, storyID string, userID string, filename string, objectKey string, contentType string, size int64) (*thunderdome.StoryAttachment, error)
+	// GetStoryAttachments gets the poker story's attachments
+	GetStoryAttachments(ctx context.Context, storyID string) ([]*thunderdome.StoryAttachment, error)
+	// AddComment adds a comment to a poker story, a parent ID makes it a reply to another comment
+	AddComment(ctx context.Context, storyID string, authorID string, parentID string, body string) (*thunderdome.PokerStoryComment, error)
+	// ListComments gets the poker story's comments with replies nested under their parent comment
+	ListComments(ctx context.Context, storyID string) ([]thunderdome.PokerStoryComment, error)
+	// SaveGameIntegration stores the game's encrypted integration access token for the provider
+	SaveGameIntegration(ctx context.Context, pokerID string, provider string, target string, accessToken string) error
+	// GetGameIntegrationToken gets the decrypted integration access token and its target saved for the game's provider
+	GetGameIntegrationToken(ctx context.Context, pokerID string, provider string) (string, string, error)
+	// BulkAddStories adds multiple stories to a poker game, optionally deduplicating by reference_id
+	BulkAddStories(ctx context.Context, pokerID string, stories []*thunderdome.Story, deduplicate bool) (*thunderdome.DuplicationResult, error)
+	// ImportStoriesFromCSV adds the stories parsed from a csv file to a poker game using the header to field column mapping
+	ImportStoriesFromCSV(ctx context.Context, pokerID string, csvData []byte, columnMapping map[string]string, deduplicate bool) ([]*thunderdome.Story, *thunderdome.DuplicationResult, error)
 	// CreateStory creates a new story in a poker game
 	CreateStory(pokerID string, name string, storyType string, referenceID string, link string, description string, acceptanceCriteria string, priority int32) ([]*thunderdome.Story, error)
 	// ActivateStoryVoting activates voting for a story in a poker game
@@ -384,6 +601,12 @@ type PokerDataSvc interface {
 	SkipStory(pokerID string, storyID string) ([]*thunderdome.Story, error)
 	// UpdateStory updates an existing story in a poker game
 	UpdateStory(pokerID string, storyID string, name string, storyType string, referenceID string, link string, description string, acceptanceCriteria string, priority int32) ([]*thunderdome.Story, error)
+	// BulkUpdateStoryPriority sets the priority of multiple stories in a poker game
+	BulkUpdateStoryPriority(ctx context.Context, pokerID string, updates []thunderdome.StoryPriorityUpdate) error
+	// BulkUpdateStorySprint assigns multiple stories in a poker game to a sprint
+	BulkUpdateStorySprint(ctx context.Context, pokerID string, sprint thunderdome.StorySprint, storyIDs []string) error
+	// BulkUpdateStoryStatus moves multiple stories in a poker game to the status
+	BulkUpdateStoryStatus(ctx context.Context, pokerID string, status string, storyIDs []string) error
 	// DeleteStory deletes a story from a poker game
 	DeleteStory(pokerID string, storyID string) ([]*thunderdome.Story, error)
 	// ArrangeStory sets the position of the story relative to the story it's being placed before
@@ -423,7 +646,7 @@ type PokerDataSvc interface {
 }
 
 type RetroDataSvc interface {
-	CreateRetro(ctx context.Context, ownerID, teamID string, retroName, joinCode, facilitatorCode string, maxVotes int, brainstormVisibility string, phaseTimeLimitMin int, phaseAutoAdvance bool, allowCumulativeVoting bool, templateID string) (*thunderdome.Retro, error)
+	CreateRetro(ctx context.Context, ownerID, teamID string, retroName, joinCode, facilitatorCode string, maxVotes int, brainstormVisibility string, phaseTimeLimitMin int, phaseAutoAdvance bool, allowCumulativeVoting bool, templateID string, submissionPhase bool, submissionDeadline *time.Time) (*thunderdome.Retro, error)
 	EditRetro(retroID string, retroName string, joinCode string, facilitatorCode string, maxVotes int, brainstormVisibility string, phaseAutoAdvance bool) error
 	RetroGetByID(retroID string, userID string) (*thunderdome.Retro, error)
 	RetroGetByUser(userID string, limit int, offset int) ([]*thunderdome.Retro, int, error)
@@ -442,8 +665,21 @@ type RetroDataSvc interface {
 	GetActiveRetros(limit int, offset int) ([]*thunderdome.Retro, int, error)
 	GetRetroFacilitatorCode(retroID string) (string, error)
 	CleanRetros(ctx context.Context, daysOld int) error
+	SoftDeleteRetro(ctx context.Context, retroID string) error
+	SoftDeleteOldRetros(ctx context.Context, daysOld int) (*thunderdome.RetroCleanupResult, error)
+	RestoreRetro(ctx context.Context, retroID string) error
 	MarkUserReady(retroID string, userID string) ([]string, error)
 	UnmarkUserReady(retroID string, userID string) ([]string, error)
+	GetRetroSubmissionPhase(retroID string) (bool, *time.Time, error)
+	OpenRetroSession(ctx context.Context, retroID string) error
+	SetFocusItem(ctx context.Context, retroID string, itemID string) error
+	GetRetroFocusItem(retroID string) (*string, error)
+	StartCategoryTimer(ctx context.Context, retroID string, categoryID string, durationSeconds int) (*thunderdome.RetroTimer, error)
+	PauseCategoryTimer(ctx context.Context, retroID string, categoryID string) (*thunderdome.RetroTimer, error)
+	SubscribeTimerEvents(ctx context.Context) <-chan thunderdome.RetroTimerEvent
+	CreateSubRetro(ctx context.Context, parentRetroID string, facilitatorID string, name string) (*thunderdome.Retro, error)
+	GetSubRetroSummary(ctx context.Context, parentRetroID string) (*thunderdome.SubRetroSummary, error)
+	MergeSubRetros(ctx context.Context, parentRetroID string) error
 
 	CreateRetroAction(retroID string, userID string, content string) ([]*thunderdome.RetroAction, error)
 	UpdateRetroAction(retroID string, actionID string, content string, completed bool) (Actions []*thunderdome.RetroAction, DeleteError error)
@@ -460,8 +696,23 @@ type RetroDataSvc interface {
 	GroupRetroItem(retroID string, itemId string, groupId string) (thunderdome.RetroItem, error)
 	DeleteRetroItem(retroID string, userID string, itemType string, itemID string) ([]*thunderdome.RetroItem, error)
 	GetRetroItems(retroID string) []*thunderdome.RetroItem
+	TagItemEmotion(ctx context.Context, retroID string, itemID string, emotion string) error
+	GetEmotionHistogram(ctx context.Context, retroID string) (map[string]int, error)
+	GetRetroInsights(ctx context.Context, retroID string) (*thunderdome.RetroInsights, error)
+	SaveRetroInsights(ctx context.Context, retroID string, insights *thunderdome.RetroInsights) (*thunderdome.RetroInsights, error)
+
+	GetRetroSchedules(ctx context.Context, teamID string) ([]*thunderdome.RetroSchedule, error)
+	GetRetroScheduleByID(ctx context.Context, teamID string, scheduleID string) (*thunderdome.RetroSchedule, error)
+	CreateRetroSchedule(ctx context.Context, teamID string, userID string, schedule *thunderdome.RetroSchedule) (*thunderdome.RetroSchedule, error)
+	UpdateRetroSchedule(ctx context.Context, teamID string, scheduleID string, schedule *thunderdome.RetroSchedule) (*thunderdome.RetroSchedule, error)
+	DeleteRetroSchedule(ctx context.Context, teamID string, scheduleID string) error
+
 	GetRetroGroups(retroID string) []*thunderdome.RetroGroup
 	GroupNameChange(retroID string, groupID string, name string) (thunderdome.RetroGroup, error)
+	CreateGroup(retroID string, name string, color string) ([]*thunderdome.RetroGroup, error)
+	AddItemToGroup(retroID string, groupID string, itemID string) ([]*thunderdome.RetroGroup, error)
+	RemoveItemFromGroup(retroID string, groupID string, itemID string) ([]*thunderdome.RetroGroup, error)
+	DeleteGroup(retroID string, groupID string) ([]*thunderdome.RetroGroup, error)
 	GetRetroVotes(retroID string) []*thunderdome.RetroVote
 	GroupUserVote(retroID string, groupID string, userID string) ([]*thunderdome.RetroVote, error)
 	GroupUserSubtractVote(retroID string, groupID string, userID string) ([]*thunderdome.RetroVote, error)
@@ -501,6 +752,19 @@ type RetroTemplateDataSvc interface {
 	DeleteOrganizationTemplate(ctx context.Context, orgID string, templateID string) error
 	// DeleteTeamTemplate deletes a team retro template by its ID
 	DeleteTeamTemplate(ctx context.Context, teamID string, templateID string) error
+	// GetMarketplaceTemplates retrieves the templates shared to the marketplace, most downloaded first
+	GetMarketplaceTemplates(ctx context.Context) ([]*thunderdome.RetroTemplate, error)
+	// PublishMarketplaceTemplate shares the template to the marketplace
+	PublishMarketplaceTemplate(ctx context.Context, templateID string) error
+	// InstallMarketplaceTemplate copies the marketplace template into the organization
+	InstallMarketplaceTemplate(ctx context.Context, templateID string, organizationID string, userID string) (*thunderdome.RetroTemplate, error)
+}
+
+type NotificationDataSvc interface {
+	CreateNotification(ctx context.Context, userID string, notificationType string, payload json.RawMessage) (*thunderdome.Notification, error)
+	MarkAsRead(ctx context.Context, userID string, notificationID string) error
+	ListUnread(ctx context.Context, userID string, limit int, offset int) ([]*thunderdome.Notification, int, error)
+	DeleteOld(ctx context.Context, daysOld int) error
 }
 
 type StoryboardDataSvc interface {
@@ -552,6 +816,13 @@ type StoryboardDataSvc interface {
 	AddStoryComment(storyboardID string, userID string, storyID string, comment string) ([]*thunderdome.StoryboardGoal, error)
 	EditStoryComment(storyboardID string, commentID string, comment string) ([]*thunderdome.StoryboardGoal, error)
 	DeleteStoryComment(storyboardID string, commentID string) ([]*thunderdome.StoryboardGoal, error)
+
+	CreateTemplate(ctx context.Context, template *thunderdome.StoryboardTemplate) (*thunderdome.StoryboardTemplate, error)
+	GetTemplate(ctx context.Context, templateID string) (*thunderdome.StoryboardTemplate, error)
+	UpdateTemplate(ctx context.Context, template *thunderdome.StoryboardTemplate) (*thunderdome.StoryboardTemplate, error)
+	DeleteTemplate(ctx context.Context, templateID string) error
+	ListTemplatesForOrg(ctx context.Context, orgID string) ([]*thunderdome.StoryboardTemplate, error)
+	ApplyTemplate(ctx context.Context, storyboardID string, format *thunderdome.StoryboardTemplateFormat) ([]*thunderdome.StoryboardGoal, error)
 }
 
 type EmailService interface {
@@ -568,4 +839,16 @@ type EmailService interface {
 	SendDepartmentInvite(organizationName string, departmentName string, userEmail string, inviteID string) error
 	// SendRetroOverview sends the retro overview (items, action items) email to attendees
 	SendRetroOverview(retro *thunderdome.Retro, template *thunderdome.RetroTemplate, userName string, userEmail string) error
+	// SendRetroActionAssigned sends the retro action item assigned email to the assignee
+	SendRetroActionAssigned(retro *thunderdome.Retro, action *thunderdome.RetroAction, userName string, userEmail string) error
+	// SendRetroActionUnassigned sends the retro action item unassigned email to the previous assignee
+	SendRetroActionUnassigned(retro *thunderdome.Retro, action *thunderdome.RetroAction, userName string, userEmail string) error
+	// SendStandupDigest sends the weekly team standup digest to a team user
+	SendStandupDigest(teamName string, digest []*thunderdome.StandupDigestEntry, userName string, userEmail string) error
+	// SendCheckinReminder sends a reminder to a team user who hasn't checked in yet today
+	SendCheckinReminder(teamID string, teamName string, userName string, userEmail string) error
+	// SendRegistrationRejected sends the user the reason their registration was rejected
+	SendRegistrationRejected(userName string, userEmail string, reason string) error
+	// SendAccountMerged notifies the user that their duplicate account was merged into their account
+	SendAccountMerged(userName string, userEmail string, mergedUserEmail string) error
 }
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/webhook/subscription"
 	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
 
 	"go.uber.org/zap"
@@ -245,6 +246,53 @@ func (s *Service) handleUserDelete() http.HandlerFunc {
 	}
 }
 
+// handleInvalidateUserSessions logs the user out of every device
+//
+//	@Summary		Invalidate User Sessions
+//	@Description	Invalidates all of the user's active sessions, e.g. after a password change
+//	@Tags			user
+//	@Produce		json
+//	@Param			userId	path	string	true	"the user ID"
+//	@Success		200		object	standardJsonResponse{}
+//	@Failure		403		object	standardJsonResponse{}
+//	@Failure		404		object	standardJsonResponse{}
+//	@Failure		500		object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/users/{userId}/invalidate-sessions [post]
+//	@Router			/admin/users/{userId}/invalidate-sessions [post]
+func (s *Service) handleInvalidateUserSessions() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		userID := vars["userId"]
+		idErr := validate.Var(userID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+
+		err := s.UserDataSvc.InvalidateAllSessions(ctx, userID)
+		if err != nil {
+			if err.Error() == "USER_NOT_FOUND" {
+				s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, "USER_NOT_FOUND"))
+				return
+			}
+			s.Logger.Ctx(ctx).Error("handleInvalidateUserSessions error", zap.Error(err),
+				zap.String("entity_user_id", userID), zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		// don't clear admins user cookies when invalidating other users sessions
+		if userID == sessionUserID {
+			s.Cookie.ClearUserCookies(w)
+		}
+
+		s.Success(w, r, http.StatusOK, nil, nil)
+	}
+}
+
 // handleVerifyRequest sends verification Email
 //
 //	@Summary		Request Verification Email
@@ -454,6 +502,7 @@ func (s *Service) handleUserOrganizationInvite() http.HandlerFunc {
 			s.Failure(w, r, http.StatusInternalServerError, err)
 			return
 		}
+		s.recordUsage(ctx, orgID, subscription.UsageEventUserAdded)
 
 		delInviteErr := s.OrganizationDataSvc.OrganizationDeleteUserInvite(ctx, orgInvite.InviteID)
 		if delInviteErr != nil {
@@ -0,0 +1,75 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+func TestHandleInvalidateUserSessions(t *testing.T) {
+	const userID = "a23e4567-e89b-12d3-a456-426614174000"
+	const adminID = "b23e4567-e89b-12d3-a456-426614174000"
+	const missingUserID = "c23e4567-e89b-12d3-a456-426614174000"
+
+	tests := []struct {
+		name           string
+		entityUserID   string
+		sessionUserID  string
+		invalidateErr  error
+		expectedStatus int
+		expectCleared  bool
+	}{
+		{
+			name:           "Self",
+			entityUserID:   userID,
+			sessionUserID:  userID,
+			expectedStatus: http.StatusOK,
+			expectCleared:  true,
+		},
+		{
+			name:           "Admin For Another User",
+			entityUserID:   userID,
+			sessionUserID:  adminID,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "User Not Found",
+			entityUserID:   missingUserID,
+			sessionUserID:  adminID,
+			invalidateErr:  fmt.Errorf("USER_NOT_FOUND"),
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUserDataSvc := new(MockUserDataService)
+			cookie := &fakeSessionCookie{}
+			s := &Service{
+				Cookie:      cookie,
+				UserDataSvc: mockUserDataSvc,
+				Logger:      otelzap.New(zap.NewNop()),
+			}
+
+			mockUserDataSvc.On("InvalidateAllSessions", mock.Anything, tt.entityUserID).Return(tt.invalidateErr).Once()
+
+			req := httptest.NewRequest("POST", "/users/"+tt.entityUserID+"/invalidate-sessions", nil)
+			req = mux.SetURLVars(req, map[string]string{"userId": tt.entityUserID})
+			req = req.WithContext(context.WithValue(req.Context(), contextKeyUserID, tt.sessionUserID))
+			rr := httptest.NewRecorder()
+			s.handleInvalidateUserSessions().ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Equal(t, tt.expectCleared, cookie.cleared)
+			mockUserDataSvc.AssertExpectations(t)
+		})
+	}
+}
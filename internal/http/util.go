@@ -3,7 +3,6 @@ package http
 import (
 	"context"
 	"crypto/tls"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"html/template"
@@ -13,6 +12,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/http/response"
 	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
 
 	"github.com/go-ldap/ldap/v3"
@@ -78,45 +78,12 @@ func validateUserPassword(pwd1 string, pwd2 string) (updatedPassword string, val
 
 // Success returns the successful response including any data and meta
 func (s *Service) Success(w http.ResponseWriter, r *http.Request, code int, data interface{}, meta interface{}) {
-	result := &standardJsonResponse{
-		Success: true,
-		Error:   "",
-		Data:    map[string]interface{}{},
-		Meta:    map[string]interface{}{},
-	}
-
-	if meta != nil {
-		result.Meta = meta
-	}
-
-	if data != nil {
-		result.Data = data
-	}
-
-	response, _ := json.Marshal(result)
-
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(code)
-	w.Write(response)
+	response.Respond(w, code, data, meta)
 }
 
 // Failure responds with an error and its associated status code header
 func (s *Service) Failure(w http.ResponseWriter, r *http.Request, code int, err error) {
-	// Extract error message.
-	_, errMessage := ErrorCode(err), ErrorMessage(err)
-
-	result := &standardJsonResponse{
-		Success: false,
-		Error:   errMessage,
-		Data:    map[string]interface{}{},
-		Meta:    map[string]interface{}{},
-	}
-
-	response, _ := json.Marshal(result)
-
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(code)
-	w.Write(response)
+	response.RespondError(w, code, ErrorMessage(err))
 }
 
 // getLimitOffsetFromRequest gets the limit and offset query parameters from the request
@@ -229,7 +196,7 @@ func (s *Service) authAndCreateUserLdap(ctx context.Context, userName string, us
 			s.Logger.Ctx(ctx).Error("Failed verifying new user", zap.Error(err))
 			return authedUser, sessionID, err
 		}
-		sessionID, err = s.AuthDataSvc.CreateSession(ctx, authedUser.ID, true)
+		sessionID, err = s.AuthDataSvc.CreateSession(ctx, authedUser.ID, true, thunderdome.AuthMethodLDAP)
 		if err != nil {
 			s.Logger.Ctx(ctx).Error("Failed creating user session", zap.Error(err))
 			return authedUser, sessionID, err
@@ -239,7 +206,7 @@ func (s *Service) authAndCreateUserLdap(ctx context.Context, userName string, us
 			return nil, "", fmt.Errorf("user is disabled")
 		}
 
-		sessionID, sessErr = s.AuthDataSvc.CreateSession(ctx, authedUser.ID, true)
+		sessionID, sessErr = s.AuthDataSvc.CreateSession(ctx, authedUser.ID, true, thunderdome.AuthMethodLDAP)
 		if sessErr != nil {
 			s.Logger.Ctx(ctx).Error("Failed creating user session", zap.Error(err))
 			return nil, "", err
@@ -270,7 +237,7 @@ func (s *Service) authAndCreateUserHeader(ctx context.Context, username string,
 			s.Logger.Ctx(ctx).Error("Failed verifying new user", zap.Error(err))
 			return authedUser, sessionId, err
 		}
-		sessionId, err = s.AuthDataSvc.CreateSession(ctx, authedUser.ID, true)
+		sessionId, err = s.AuthDataSvc.CreateSession(ctx, authedUser.ID, true, thunderdome.AuthMethodHeader)
 		if err != nil {
 			s.Logger.Ctx(ctx).Error("Failed creating user session", zap.Error(err))
 			return authedUser, sessionId, err
@@ -280,7 +247,7 @@ func (s *Service) authAndCreateUserHeader(ctx context.Context, username string,
 			return nil, "", fmt.Errorf("user is disabled")
 		}
 
-		sessionId, sessErr = s.AuthDataSvc.CreateSession(ctx, authedUser.ID, true)
+		sessionId, sessErr = s.AuthDataSvc.CreateSession(ctx, authedUser.ID, true, thunderdome.AuthMethodHeader)
 		if sessErr != nil {
 			s.Logger.Ctx(ctx).Error("Failed creating user session", zap.Error(err))
 			return nil, "", err
@@ -9,7 +9,6 @@ import (
 	"github.com/uptrace/opentelemetry-go-extra/otelzap"
 
 	"github.com/coreos/go-oidc/v3/oidc"
-	"github.com/google/uuid"
 	"go.uber.org/zap"
 	"golang.org/x/oauth2"
 )
@@ -63,13 +62,21 @@ func (s *Service) HandleOAuth2Redirect() http.HandlerFunc {
 		}
 
 		// create state cookie for callback state verification
-		state, err := uuid.NewUUID()
+		stateString, err := newStateToken()
 		if err != nil {
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
 
-		stateString := state.String()
+		if s.config.StateStore != nil {
+			saved, err := s.config.StateStore.Save(ctx, stateString, stateTTL)
+			if err != nil || !saved {
+				s.logger.Ctx(ctx).Error("error saving oauth state", zap.Error(err), zap.Bool("saved", saved))
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+		}
+
 		err = s.cookie.CreateAuthStateCookie(w, stateString)
 		if err != nil {
 			w.WriteHeader(http.StatusInternalServerError)
@@ -90,10 +97,25 @@ func (s *Service) HandleOAuth2Callback() http.HandlerFunc {
 		code := rq.Get("code")
 
 		// Verify state
+		if s.config.StateStore != nil {
+			storedState, err := s.config.StateStore.Consume(ctx, state)
+			if err != nil {
+				logger.Error("error consuming oauth state", zap.Error(err))
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			// expired or already used
+			if storedState == "" {
+				logger.Warn("oauth state expired or replayed")
+				http.Error(w, "INVALID_STATE", http.StatusBadRequest)
+				return
+			}
+			state = storedState
+		}
 		err := s.cookie.ValidateAuthStateCookie(w, r, state)
 		if err != nil {
 			logger.Error("invalid oauth state", zap.Error(err))
-			w.WriteHeader(http.StatusBadRequest)
+			http.Error(w, "INVALID_STATE", http.StatusBadRequest)
 			return
 		}
 
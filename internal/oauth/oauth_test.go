@@ -0,0 +1,163 @@
+package oauth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+	"golang.org/x/oauth2"
+)
+
+// memoryStateStore is a StateStore with the same single use semantics as the redis store
+type memoryStateStore struct {
+	mu     sync.Mutex
+	states map[string]string
+}
+
+func (m *memoryStateStore) Save(ctx context.Context, state string, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.states[state]; ok {
+		return false, nil
+	}
+	m.states[state] = state
+
+	return true, nil
+}
+
+func (m *memoryStateStore) Consume(ctx context.Context, state string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	value := m.states[state]
+	delete(m.states, state)
+
+	return value, nil
+}
+
+// fakeStateCookie keeps the auth state cookie value the same way the browser would between requests
+type fakeStateCookie struct {
+	CookieManager
+	value string
+}
+
+func (f *fakeStateCookie) CreateAuthStateCookie(w http.ResponseWriter, state string) error {
+	f.value = state
+	return nil
+}
+
+func (f *fakeStateCookie) ValidateAuthStateCookie(w http.ResponseWriter, r *http.Request, state string) error {
+	if f.value == "" || f.value != state {
+		return fmt.Errorf("INVALID_AUTH_STATE")
+	}
+
+	return nil
+}
+
+type fakeNonceDataSvc struct {
+	AuthDataSvc
+}
+
+func (f *fakeNonceDataSvc) OauthCreateNonce(ctx context.Context) (string, error) {
+	return "nonce", nil
+}
+
+func newStateTestService(t *testing.T, store StateStore) (*Service, *fakeStateCookie) {
+	t.Helper()
+
+	// the token exchange always fails, the tests only cover the state verification before it
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	t.Cleanup(tokenServer.Close)
+
+	cookie := &fakeStateCookie{}
+	return &Service{
+		config: Config{StateStore: store},
+		cookie: cookie,
+		logger: otelzap.New(zap.NewNop()),
+		oauth2Config: &oauth2.Config{
+			ClientID: "client",
+			Endpoint: oauth2.Endpoint{AuthURL: "https://provider.test/auth", TokenURL: tokenServer.URL},
+		},
+		authDataSvc: &fakeNonceDataSvc{},
+	}, cookie
+}
+
+func redirectState(t *testing.T, s *Service) string {
+	t.Helper()
+
+	rr := httptest.NewRecorder()
+	s.HandleOAuth2Redirect().ServeHTTP(rr, httptest.NewRequest("GET", "/oauth/test/login", nil))
+	if rr.Code != http.StatusSeeOther {
+		t.Fatalf("expected redirect, got %d", rr.Code)
+	}
+	location, err := url.Parse(rr.Header().Get("Location"))
+	if err != nil {
+		t.Fatalf("invalid redirect location: %v", err)
+	}
+
+	return location.Query().Get("state")
+}
+
+func callback(s *Service, state string) *httptest.ResponseRecorder {
+	rr := httptest.NewRecorder()
+	s.HandleOAuth2Callback().ServeHTTP(rr, httptest.NewRequest("GET", "/oauth/test/callback?code=code&state="+url.QueryEscape(state), nil))
+
+	return rr
+}
+
+// TestOAuth2CallbackStateReplay makes sure a state token can only be used by a single callback
+func TestOAuth2CallbackStateReplay(t *testing.T) {
+	store := &memoryStateStore{states: make(map[string]string)}
+	s, _ := newStateTestService(t, store)
+
+	state := redirectState(t, s)
+	if len(state) != 43 {
+		t.Errorf("expected a 32 byte state token, got %q", state)
+	}
+	if _, ok := store.states[state]; !ok {
+		t.Fatal("expected the state token to be stored")
+	}
+
+	first := callback(s, state)
+	if strings.Contains(first.Body.String(), "INVALID_STATE") {
+		t.Fatalf("expected the first callback to pass state verification, got %d %q", first.Code, first.Body.String())
+	}
+
+	replay := callback(s, state)
+	if replay.Code != http.StatusBadRequest || !strings.Contains(replay.Body.String(), "INVALID_STATE") {
+		t.Errorf("expected the replayed state to be rejected with INVALID_STATE, got %d %q", replay.Code, replay.Body.String())
+	}
+}
+
+// TestOAuth2CallbackUnknownState makes sure a state token that wasn't issued (or has expired) is rejected
+func TestOAuth2CallbackUnknownState(t *testing.T) {
+	s, cookie := newStateTestService(t, &memoryStateStore{states: make(map[string]string)})
+	cookie.value = "forged"
+
+	rr := callback(s, "forged")
+	if rr.Code != http.StatusBadRequest || !strings.Contains(rr.Body.String(), "INVALID_STATE") {
+		t.Errorf("expected the unknown state to be rejected with INVALID_STATE, got %d %q", rr.Code, rr.Body.String())
+	}
+}
+
+// TestOAuth2CallbackStateCookieMismatch makes sure the state must also match the auth state cookie
+func TestOAuth2CallbackStateCookieMismatch(t *testing.T) {
+	s, cookie := newStateTestService(t, &memoryStateStore{states: make(map[string]string)})
+
+	state := redirectState(t, s)
+	cookie.value = "other-browser"
+
+	rr := callback(s, state)
+	if rr.Code != http.StatusBadRequest || !strings.Contains(rr.Body.String(), "INVALID_STATE") {
+		t.Errorf("expected the cookie mismatch to be rejected with INVALID_STATE, got %d %q", rr.Code, rr.Body.String())
+	}
+}
@@ -0,0 +1,62 @@
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// stateTokenBytes is the random bytes of an oauth state token
+const stateTokenBytes = 32
+
+// stateTTL is how long an oauth state token can be used, matching the auth state cookie
+const stateTTL = 10 * time.Minute
+
+// StateStore tracks the oauth state tokens issued by the redirect so each can only be used by a single callback.
+type StateStore interface {
+	// Save stores the state token, returning false when the token already exists.
+	Save(ctx context.Context, state string, ttl time.Duration) (bool, error)
+	// Consume removes the state token returning its stored value, the value is empty when the token
+	// doesn't exist because it expired or was already used.
+	Consume(ctx context.Context, state string) (string, error)
+}
+
+// RedisStateStore is a StateStore backed by a redis key per state token.
+type RedisStateStore struct {
+	Client *redis.Client
+}
+
+func stateKey(state string) string {
+	return fmt.Sprintf("oauth:state:%s", state)
+}
+
+// Save stores the state token, returning false when the token already exists.
+func (s *RedisStateStore) Save(ctx context.Context, state string, ttl time.Duration) (bool, error) {
+	return s.Client.SetNX(ctx, stateKey(state), state, ttl).Result()
+}
+
+// Consume removes the state token returning its stored value, the value is empty when the token
+// doesn't exist because it expired or was already used.
+func (s *RedisStateStore) Consume(ctx context.Context, state string) (string, error) {
+	value, err := s.Client.GetDel(ctx, stateKey(state)).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+
+	return value, err
+}
+
+// newStateToken generates a random url safe oauth state token
+func newStateToken() (string, error) {
+	b := make([]byte, stateTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -15,6 +15,8 @@ type Config struct {
 	thunderdome.AuthProviderConfig
 	CallbackRedirectURL string
 	UIRedirectURL       string
+	// StateStore makes each state token single use, when nil the state is only checked against the auth state cookie
+	StateStore StateStore
 }
 
 // CookieManager is an interface for managing cookies
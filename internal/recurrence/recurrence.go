@@ -0,0 +1,105 @@
+// Package recurrence provides the recurring poker game and retro schedulers for Thunderdome
+package recurrence
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/robfig/cron"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+// checkInterval is how often the recurring game templates are evaluated
+const checkInterval = time.Minute
+
+// GameTemplateDataSvc provides the recurring game templates and creates the team games from them
+type GameTemplateDataSvc interface {
+	GetRecurringGameTemplates(ctx context.Context) ([]*thunderdome.GameTemplate, error)
+	CreateRecurringGame(ctx context.Context, template *thunderdome.GameTemplate, name string, windowStart time.Time) (*thunderdome.Poker, bool, error)
+}
+
+// Scheduler creates a new team poker game from each recurring game template when its cron comes due
+type Scheduler struct {
+	logger  *otelzap.Logger
+	dataSvc GameTemplateDataSvc
+	now     func() time.Time
+}
+
+// New returns a new recurring poker game scheduler
+func New(logger *otelzap.Logger, dataSvc GameTemplateDataSvc) *Scheduler {
+	return &Scheduler{
+		logger:  logger,
+		dataSvc: dataSvc,
+		now:     time.Now,
+	}
+}
+
+// Run evaluates the recurring game templates every minute until the context is cancelled
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.tick(ctx)
+		}
+	}
+}
+
+// tick creates a game for every template that came due since its last game was created
+func (s *Scheduler) tick(ctx context.Context) {
+	templates, err := s.dataSvc.GetRecurringGameTemplates(ctx)
+	if err != nil {
+		s.logger.Ctx(ctx).Error("game recurrence get templates error", zap.Error(err))
+		return
+	}
+
+	now := s.now()
+	for _, template := range templates {
+		windowStart, due := gameWindow(template, now)
+		if !due {
+			continue
+		}
+		s.createGame(ctx, template, windowStart, now)
+	}
+}
+
+// gameWindow returns the first time the template's cron came due after its last game was created (or the
+// template was created when it has no games yet), and whether that was no later than now, the cron expression
+// is evaluated in UTC. Only one game is created for however many times the cron came due, so downtime doesn't
+// create a backlog.
+func gameWindow(template *thunderdome.GameTemplate, now time.Time) (time.Time, bool) {
+	cronSchedule, err := cron.ParseStandard(template.RecurrenceCron)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	from := template.CreatedDate
+	if template.LastGameDate != nil {
+		from = *template.LastGameDate
+	}
+
+	next := cronSchedule.Next(from.UTC())
+	if next.IsZero() || next.After(now) {
+		return time.Time{}, false
+	}
+
+	return next, true
+}
+
+// createGame creates the team game from the template for the cron window, named after the template and the
+// day it was created, nothing is created when another server already created the window's game
+func (s *Scheduler) createGame(ctx context.Context, template *thunderdome.GameTemplate, windowStart time.Time, now time.Time) {
+	name := fmt.Sprintf("%s %s", template.Name, now.UTC().Format(time.DateOnly))
+
+	if _, _, err := s.dataSvc.CreateRecurringGame(ctx, template, name, windowStart); err != nil {
+		s.logger.Ctx(ctx).Error("game recurrence create game error", zap.Error(err),
+			zap.String("team_id", template.TeamID), zap.String("template_id", template.ID))
+	}
+}
@@ -0,0 +1,145 @@
+package recurrence
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+type createdGame struct {
+	teamID        string
+	facilitatorID string
+	name          string
+	createdDate   time.Time
+}
+
+// fakeGameTemplateDataSvc records the games created and tracks each template's last game and claimed
+// window like the database does
+type fakeGameTemplateDataSvc struct {
+	templates     []*thunderdome.GameTemplate
+	games         []createdGame
+	now           *time.Time
+	claimedWindow map[string]time.Time
+}
+
+func (f *fakeGameTemplateDataSvc) GetRecurringGameTemplates(ctx context.Context) ([]*thunderdome.GameTemplate, error) {
+	return f.templates, nil
+}
+
+func (f *fakeGameTemplateDataSvc) CreateRecurringGame(ctx context.Context, template *thunderdome.GameTemplate, name string, windowStart time.Time) (*thunderdome.Poker, bool, error) {
+	if f.claimedWindow == nil {
+		f.claimedWindow = make(map[string]time.Time)
+	}
+	if claimed, ok := f.claimedWindow[template.ID]; ok && !claimed.Before(windowStart) {
+		return nil, false, nil
+	}
+	f.claimedWindow[template.ID] = windowStart
+
+	f.games = append(f.games, createdGame{teamID: template.TeamID, facilitatorID: template.CreatedBy, name: name, createdDate: *f.now})
+	created := *f.now
+	template.LastGameDate = &created
+
+	return &thunderdome.Poker{ID: name}, true, nil
+}
+
+// newTestScheduler returns a scheduler whose clock is read from now
+func newTestScheduler(dataSvc *fakeGameTemplateDataSvc) *Scheduler {
+	s := New(otelzap.New(zap.NewNop()), dataSvc)
+	s.now = func() time.Time { return *dataSvc.now }
+
+	return s
+}
+
+// TestSchedulerCreatesGameAfterCronBoundary makes sure a game is only created once the mock clock passes the cron time
+func TestSchedulerCreatesGameAfterCronBoundary(t *testing.T) {
+	now := time.Date(2025, 3, 17, 8, 0, 0, 0, time.UTC) // Monday
+	dataSvc := &fakeGameTemplateDataSvc{
+		templates: []*thunderdome.GameTemplate{{
+			ID: "template", TeamID: "team", CreatedBy: "facilitator", Name: "Sprint Planning",
+			RecurrenceCron: "0 9 * * 1", CreatedDate: now,
+		}},
+		now: &now,
+	}
+	s := newTestScheduler(dataSvc)
+
+	now = now.Add(59 * time.Minute)
+	s.tick(context.Background())
+	if len(dataSvc.games) != 0 {
+		t.Fatalf("expected no game before the cron boundary, got %v", dataSvc.games)
+	}
+
+	now = now.Add(time.Minute)
+	s.tick(context.Background())
+	if len(dataSvc.games) != 1 {
+		t.Fatalf("expected a game at the cron boundary, got %v", dataSvc.games)
+	}
+	game := dataSvc.games[0]
+	if game.teamID != "team" || game.facilitatorID != "facilitator" || game.name != "Sprint Planning 2025-03-17" {
+		t.Errorf("expected the game to be created from the template, got %+v", game)
+	}
+
+	now = now.Add(24 * time.Hour)
+	s.tick(context.Background())
+	if len(dataSvc.games) != 1 {
+		t.Fatalf("expected no game before the next cron boundary, got %v", dataSvc.games)
+	}
+
+	now = now.Add(6 * 24 * time.Hour)
+	s.tick(context.Background())
+	if len(dataSvc.games) != 2 || dataSvc.games[1].name != "Sprint Planning 2025-03-24" {
+		t.Fatalf("expected a game at the next cron boundary, got %v", dataSvc.games)
+	}
+}
+
+// TestSchedulerCatchesUpOnce makes sure a template that missed several cron times only creates a single game
+func TestSchedulerCatchesUpOnce(t *testing.T) {
+	lastGame := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	now := lastGame.Add(10 * 24 * time.Hour)
+	dataSvc := &fakeGameTemplateDataSvc{
+		templates: []*thunderdome.GameTemplate{{
+			ID: "template", TeamID: "team", CreatedBy: "facilitator", Name: "Daily",
+			RecurrenceCron: "0 9 * * *", CreatedDate: lastGame.Add(-time.Hour), LastGameDate: &lastGame,
+		}},
+		now: &now,
+	}
+	s := newTestScheduler(dataSvc)
+
+	s.tick(context.Background())
+	s.tick(context.Background())
+	if len(dataSvc.games) != 1 {
+		t.Fatalf("expected a single catch up game, got %v", dataSvc.games)
+	}
+}
+
+// TestSchedulerClaimsWindowOnce makes sure two schedulers evaluating the same template only create one game
+func TestSchedulerClaimsWindowOnce(t *testing.T) {
+	now := time.Date(2025, 3, 17, 9, 0, 0, 0, time.UTC)
+	created := now.Add(-time.Hour)
+	dataSvc := &fakeGameTemplateDataSvc{now: &now}
+	newTemplate := func() *thunderdome.GameTemplate {
+		return &thunderdome.GameTemplate{
+			ID: "template", TeamID: "team", CreatedBy: "facilitator", Name: "Sprint Planning",
+			RecurrenceCron: "0 9 * * 1", CreatedDate: created,
+		}
+	}
+	s := newTestScheduler(dataSvc)
+
+	// both servers read the template before either created the game
+	s.createGame(context.Background(), newTemplate(), now, now)
+	s.createGame(context.Background(), newTemplate(), now, now)
+	if len(dataSvc.games) != 1 {
+		t.Fatalf("expected a single game for the window, got %v", dataSvc.games)
+	}
+}
+
+// TestGameWindowInvalidCron makes sure templates with an invalid cron never create games
+func TestGameWindowInvalidCron(t *testing.T) {
+	template := &thunderdome.GameTemplate{RecurrenceCron: "not a cron", CreatedDate: time.Now().Add(-24 * time.Hour)}
+	if _, due := gameWindow(template, time.Now()); due {
+		t.Error("expected an invalid cron to never be due")
+	}
+}
@@ -0,0 +1,165 @@
+package recurrence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/robfig/cron"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+// RetroScheduleDataSvc provides the recurring retro schedules and creates the team retros from them
+type RetroScheduleDataSvc interface {
+	GetRecurringRetroSchedules(ctx context.Context) ([]*thunderdome.RetroSchedule, error)
+	ClaimRetroScheduleWindow(ctx context.Context, scheduleID string, windowStart time.Time) (bool, error)
+	CreateRetro(ctx context.Context, ownerID, teamID string, retroName, joinCode, facilitatorCode string, maxVotes int, brainstormVisibility string, phaseTimeLimitMin int, phaseAutoAdvance bool, allowCumulativeVoting bool, templateID string, submissionPhase bool, submissionDeadline *time.Time) (*thunderdome.Retro, error)
+	SetRetroScheduleID(ctx context.Context, retroID string, scheduleID string) error
+}
+
+// RetroTemplateDataSvc provides the default retro templates for schedules without a template
+type RetroTemplateDataSvc interface {
+	GetDefaultTeamTemplate(ctx context.Context, teamID string) (*thunderdome.RetroTemplate, error)
+	GetDefaultPublicTemplate(ctx context.Context) (*thunderdome.RetroTemplate, error)
+}
+
+// RetroScheduler creates a new team retro from each recurring retro schedule when its cron comes due
+type RetroScheduler struct {
+	logger      *otelzap.Logger
+	dataSvc     RetroScheduleDataSvc
+	templateSvc RetroTemplateDataSvc
+	now         func() time.Time
+}
+
+// NewRetroScheduler returns a new recurring retro scheduler
+func NewRetroScheduler(logger *otelzap.Logger, dataSvc RetroScheduleDataSvc, templateSvc RetroTemplateDataSvc) *RetroScheduler {
+	return &RetroScheduler{
+		logger:      logger,
+		dataSvc:     dataSvc,
+		templateSvc: templateSvc,
+		now:         time.Now,
+	}
+}
+
+// Run evaluates the recurring retro schedules every minute until the context is cancelled
+func (s *RetroScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.tick(ctx)
+		}
+	}
+}
+
+// tick creates a retro for every schedule whose current window doesn't have one yet
+func (s *RetroScheduler) tick(ctx context.Context) {
+	schedules, err := s.dataSvc.GetRecurringRetroSchedules(ctx)
+	if err != nil {
+		s.logger.Ctx(ctx).Error("retro recurrence get schedules error", zap.Error(err))
+		return
+	}
+
+	now := s.now()
+	for _, schedule := range schedules {
+		windowStart, due := retroWindow(schedule, now)
+		if !due {
+			continue
+		}
+
+		claimed, err := s.dataSvc.ClaimRetroScheduleWindow(ctx, schedule.ID, windowStart)
+		if err != nil {
+			s.logger.Ctx(ctx).Error("retro recurrence claim window error", zap.Error(err),
+				zap.String("schedule_id", schedule.ID))
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		s.createRetro(ctx, schedule, windowStart)
+	}
+}
+
+// retroWindow returns the start of the schedule's current window, the latest time its cron came due
+// no later than now, and whether that window came after the last one a retro was created for (or the
+// schedule was created when it has none yet). The cron expression is evaluated in UTC, and missed
+// windows are skipped so downtime doesn't create a backlog of retros.
+func retroWindow(schedule *thunderdome.RetroSchedule, now time.Time) (time.Time, bool) {
+	cronSchedule, err := cron.ParseStandard(schedule.RecurrenceCron)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	from := schedule.CreatedDate
+	if schedule.LastWindowDate != nil {
+		from = *schedule.LastWindowDate
+	}
+
+	windowStart := cronSchedule.Next(from.UTC())
+	if windowStart.IsZero() || windowStart.After(now) {
+		return time.Time{}, false
+	}
+	for {
+		next := cronSchedule.Next(windowStart)
+		if next.IsZero() || next.After(now) {
+			return windowStart, true
+		}
+		windowStart = next
+	}
+}
+
+// createRetro creates the team retro from the schedule, named after the schedule and the day of the window
+func (s *RetroScheduler) createRetro(ctx context.Context, schedule *thunderdome.RetroSchedule, windowStart time.Time) {
+	templateID, err := s.templateID(ctx, schedule)
+	if err != nil {
+		s.logger.Ctx(ctx).Error("retro recurrence get template error", zap.Error(err),
+			zap.String("team_id", schedule.TeamID), zap.String("schedule_id", schedule.ID))
+		return
+	}
+
+	name := fmt.Sprintf("%s %s", schedule.Name, windowStart.UTC().Format(time.DateOnly))
+	retro, err := s.dataSvc.CreateRetro(ctx, schedule.CreatedBy, schedule.TeamID, name, "", "",
+		schedule.MaxVotes, schedule.BrainstormVisibility, 0, false, false, templateID, false, nil,
+	)
+	if err != nil {
+		s.logger.Ctx(ctx).Error("retro recurrence create retro error", zap.Error(err),
+			zap.String("team_id", schedule.TeamID), zap.String("schedule_id", schedule.ID))
+		return
+	}
+
+	if err := s.dataSvc.SetRetroScheduleID(ctx, retro.ID, schedule.ID); err != nil {
+		s.logger.Ctx(ctx).Error("retro recurrence set schedule error", zap.Error(err),
+			zap.String("retro_id", retro.ID), zap.String("schedule_id", schedule.ID))
+	}
+}
+
+// templateID returns the schedule's retro template, or the team's default template falling back to
+// the default public template when the schedule doesn't have one
+func (s *RetroScheduler) templateID(ctx context.Context, schedule *thunderdome.RetroSchedule) (string, error) {
+	if schedule.TemplateID != "" {
+		return schedule.TemplateID, nil
+	}
+
+	template, err := s.templateSvc.GetDefaultTeamTemplate(ctx, schedule.TeamID)
+	if err != nil {
+		return "", err
+	}
+	if template == nil {
+		template, err = s.templateSvc.GetDefaultPublicTemplate(ctx)
+		if err != nil {
+			return "", err
+		}
+	}
+	if template == nil {
+		return "", errors.New("RETRO_TEMPLATE_NOT_FOUND")
+	}
+
+	return template.ID, nil
+}
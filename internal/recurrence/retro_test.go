@@ -0,0 +1,183 @@
+package recurrence
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+type createdRetro struct {
+	ownerID    string
+	teamID     string
+	name       string
+	templateID string
+}
+
+// fakeRetroScheduleDataSvc records the retros created and claims schedule windows like the database does
+type fakeRetroScheduleDataSvc struct {
+	mu        sync.Mutex
+	schedules []*thunderdome.RetroSchedule
+	retros    []createdRetro
+	scheduled map[string]string
+}
+
+func (f *fakeRetroScheduleDataSvc) GetRecurringRetroSchedules(ctx context.Context) ([]*thunderdome.RetroSchedule, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	// copy the schedules so a scheduler holding a stale read behaves like a second server would
+	schedules := make([]*thunderdome.RetroSchedule, 0, len(f.schedules))
+	for _, schedule := range f.schedules {
+		copied := *schedule
+		schedules = append(schedules, &copied)
+	}
+
+	return schedules, nil
+}
+
+func (f *fakeRetroScheduleDataSvc) ClaimRetroScheduleWindow(ctx context.Context, scheduleID string, windowStart time.Time) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, schedule := range f.schedules {
+		if schedule.ID == scheduleID && (schedule.LastWindowDate == nil || schedule.LastWindowDate.Before(windowStart)) {
+			schedule.LastWindowDate = &windowStart
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (f *fakeRetroScheduleDataSvc) CreateRetro(ctx context.Context, ownerID, teamID string, retroName, joinCode, facilitatorCode string, maxVotes int, brainstormVisibility string, phaseTimeLimitMin int, phaseAutoAdvance bool, allowCumulativeVoting bool, templateID string, submissionPhase bool, submissionDeadline *time.Time) (*thunderdome.Retro, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.retros = append(f.retros, createdRetro{ownerID: ownerID, teamID: teamID, name: retroName, templateID: templateID})
+
+	return &thunderdome.Retro{ID: retroName}, nil
+}
+
+func (f *fakeRetroScheduleDataSvc) SetRetroScheduleID(ctx context.Context, retroID string, scheduleID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.scheduled == nil {
+		f.scheduled = make(map[string]string)
+	}
+	f.scheduled[retroID] = scheduleID
+
+	return nil
+}
+
+type fakeRetroTemplateDataSvc struct {
+	teamTemplate *thunderdome.RetroTemplate
+}
+
+func (f *fakeRetroTemplateDataSvc) GetDefaultTeamTemplate(ctx context.Context, teamID string) (*thunderdome.RetroTemplate, error) {
+	return f.teamTemplate, nil
+}
+
+func (f *fakeRetroTemplateDataSvc) GetDefaultPublicTemplate(ctx context.Context) (*thunderdome.RetroTemplate, error) {
+	return &thunderdome.RetroTemplate{ID: "public-template"}, nil
+}
+
+// newTestRetroScheduler returns a retro scheduler whose clock is read from now
+func newTestRetroScheduler(dataSvc *fakeRetroScheduleDataSvc, templateSvc *fakeRetroTemplateDataSvc, now *time.Time) *RetroScheduler {
+	s := NewRetroScheduler(otelzap.New(zap.NewNop()), dataSvc, templateSvc)
+	s.now = func() time.Time { return *now }
+
+	return s
+}
+
+// TestRetroSchedulerOnePerWindow makes sure consecutive ticks in the same window only create one retro
+func TestRetroSchedulerOnePerWindow(t *testing.T) {
+	now := time.Date(2025, 3, 14, 15, 0, 0, 0, time.UTC) // Friday
+	dataSvc := &fakeRetroScheduleDataSvc{
+		schedules: []*thunderdome.RetroSchedule{{
+			ID: "schedule", TeamID: "team", CreatedBy: "facilitator", Name: "Sprint Retro",
+			RecurrenceCron: "0 16 * * 5", MaxVotes: 3, BrainstormVisibility: "visible",
+			CreatedDate: now.Add(-24 * time.Hour),
+		}},
+	}
+	s := newTestRetroScheduler(dataSvc, &fakeRetroTemplateDataSvc{}, &now)
+
+	s.tick(context.Background())
+	if len(dataSvc.retros) != 0 {
+		t.Fatalf("expected no retro before the cron boundary, got %v", dataSvc.retros)
+	}
+
+	now = now.Add(time.Hour)
+	s.tick(context.Background())
+	now = now.Add(time.Minute)
+	s.tick(context.Background())
+	if len(dataSvc.retros) != 1 {
+		t.Fatalf("expected a single retro in the window, got %v", dataSvc.retros)
+	}
+	retro := dataSvc.retros[0]
+	if retro.ownerID != "facilitator" || retro.teamID != "team" || retro.name != "Sprint Retro 2025-03-14" {
+		t.Errorf("expected the retro to be created from the schedule, got %+v", retro)
+	}
+	if retro.templateID != "public-template" {
+		t.Errorf("expected the default public template to be used, got %s", retro.templateID)
+	}
+	if dataSvc.scheduled[retro.name] != "schedule" {
+		t.Errorf("expected the retro to record its schedule, got %v", dataSvc.scheduled)
+	}
+
+	now = now.Add(7 * 24 * time.Hour)
+	s.tick(context.Background())
+	if len(dataSvc.retros) != 2 || dataSvc.retros[1].name != "Sprint Retro 2025-03-21" {
+		t.Fatalf("expected a retro in the next window, got %v", dataSvc.retros)
+	}
+}
+
+// TestRetroSchedulerConcurrentServers makes sure two schedulers evaluating the same window only create one retro
+func TestRetroSchedulerConcurrentServers(t *testing.T) {
+	now := time.Date(2025, 3, 14, 16, 0, 0, 0, time.UTC)
+	dataSvc := &fakeRetroScheduleDataSvc{
+		schedules: []*thunderdome.RetroSchedule{{
+			ID: "schedule", TeamID: "team", CreatedBy: "facilitator", Name: "Sprint Retro",
+			TemplateID: "template", RecurrenceCron: "0 16 * * 5", CreatedDate: now.Add(-time.Hour),
+		}},
+	}
+	templateSvc := &fakeRetroTemplateDataSvc{}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		s := newTestRetroScheduler(dataSvc, templateSvc, &now)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.tick(context.Background())
+		}()
+	}
+	wg.Wait()
+
+	if len(dataSvc.retros) != 1 || dataSvc.retros[0].templateID != "template" {
+		t.Fatalf("expected a single retro from the schedule template, got %v", dataSvc.retros)
+	}
+}
+
+// TestRetroWindowSkipsMissedWindows makes sure downtime only creates the retro for the current window
+func TestRetroWindowSkipsMissedWindows(t *testing.T) {
+	lastWindow := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	schedule := &thunderdome.RetroSchedule{
+		RecurrenceCron: "0 9 * * *", CreatedDate: lastWindow.Add(-time.Hour), LastWindowDate: &lastWindow,
+	}
+
+	windowStart, due := retroWindow(schedule, lastWindow.Add(10*24*time.Hour+time.Hour))
+	if !due || !windowStart.Equal(lastWindow.Add(10*24*time.Hour)) {
+		t.Fatalf("expected the current window to be due, got %v %v", windowStart, due)
+	}
+
+	schedule.RecurrenceCron = "not a cron"
+	if _, due := retroWindow(schedule, lastWindow.Add(24*time.Hour)); due {
+		t.Error("expected an invalid cron to never be due")
+	}
+}
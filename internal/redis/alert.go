@@ -0,0 +1,78 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// 缓存命中率告警相关常量（百分比）
+const (
+	DefaultCacheHitRateAlertThreshold = 50.0
+	CacheHitRateCriticalThreshold     = 20.0
+	CacheHitRateCheckInterval         = 60 * time.Second
+)
+
+// CacheStatsSource 提供缓存统计信息，测试时可注入模拟的指标来源
+type CacheStatsSource func() map[string]interface{}
+
+// ResetCacheStats 重置缓存命中和未命中计数
+func ResetCacheStats() {
+	metrics.mutex.Lock()
+	defer metrics.mutex.Unlock()
+
+	metrics.HitCount = 0
+	metrics.MissCount = 0
+}
+
+// CheckCacheHitRate 检查缓存命中率，低于阈值时记录警告，低于严重阈值时记录错误，
+// 返回记录的日志级别，未告警时返回 zapcore.InfoLevel
+func CheckCacheHitRate(ctx context.Context, log *otelzap.Logger, stats map[string]interface{}, threshold float64) zapcore.Level {
+	// 没有缓存请求时命中率没有意义
+	totalRequests, _ := stats["total_requests"].(int64)
+	if totalRequests == 0 {
+		return zapcore.InfoLevel
+	}
+
+	hitRate, _ := stats["hit_rate"].(float64)
+	fields := []zap.Field{
+		zap.Float64("hit_rate", hitRate),
+		zap.Float64("threshold", threshold),
+		zap.Int64("total_requests", totalRequests),
+		zap.Any("hit_count", stats["hit_count"]),
+		zap.Any("miss_count", stats["miss_count"]),
+	}
+
+	switch {
+	case hitRate < CacheHitRateCriticalThreshold:
+		log.Ctx(ctx).Error("Redis cache hit rate critically low", fields...)
+		return zapcore.ErrorLevel
+	case hitRate < threshold:
+		log.Ctx(ctx).Warn("Redis cache hit rate below threshold", fields...)
+		return zapcore.WarnLevel
+	}
+
+	return zapcore.InfoLevel
+}
+
+// MonitorCacheHitRate 定时检查缓存命中率直到ctx取消，threshold 不大于0时使用默认阈值
+func MonitorCacheHitRate(ctx context.Context, log *otelzap.Logger, source CacheStatsSource, threshold float64, interval time.Duration) {
+	if threshold <= 0 {
+		threshold = DefaultCacheHitRateAlertThreshold
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			CheckCacheHitRate(ctx, log, source(), threshold)
+		}
+	}
+}
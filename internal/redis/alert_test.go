@@ -0,0 +1,102 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// mockCacheStats is a metrics source reporting the hit and miss counts
+func mockCacheStats(hits int64, misses int64) CacheStatsSource {
+	return func() map[string]interface{} {
+		total := hits + misses
+		hitRate := float64(0)
+		if total > 0 {
+			hitRate = float64(hits) / float64(total) * 100
+		}
+
+		return map[string]interface{}{
+			"hit_count":      hits,
+			"miss_count":     misses,
+			"total_requests": total,
+			"hit_rate":       hitRate,
+		}
+	}
+}
+
+// TestCheckCacheHitRate makes sure the log level matches the threshold the hit rate dropped below
+func TestCheckCacheHitRate(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   CacheStatsSource
+		expected zapcore.Level
+		logged   int
+	}{
+		{name: "healthy", source: mockCacheStats(80, 20), expected: zapcore.InfoLevel},
+		{name: "below threshold", source: mockCacheStats(40, 60), expected: zapcore.WarnLevel, logged: 1},
+		{name: "critical", source: mockCacheStats(10, 90), expected: zapcore.ErrorLevel, logged: 1},
+		{name: "no requests", source: mockCacheStats(0, 0), expected: zapcore.InfoLevel},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zapcore.DebugLevel)
+			log := otelzap.New(zap.New(core))
+
+			level := CheckCacheHitRate(context.Background(), log, tt.source(), DefaultCacheHitRateAlertThreshold)
+			if level != tt.expected {
+				t.Errorf("expected level %s, got %s", tt.expected, level)
+			}
+			if logs.Len() != tt.logged {
+				t.Fatalf("expected %d log entries, got %d", tt.logged, logs.Len())
+			}
+			if tt.logged > 0 && logs.All()[0].Level != tt.expected {
+				t.Errorf("expected a %s log entry, got %s", tt.expected, logs.All()[0].Level)
+			}
+		})
+	}
+}
+
+// TestMonitorCacheHitRate makes sure the monitor checks the source until it's stopped
+func TestMonitorCacheHitRate(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	log := otelzap.New(zap.New(core))
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		MonitorCacheHitRate(ctx, log, mockCacheStats(30, 70), 0, time.Millisecond)
+		close(done)
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for logs.FilterLevelExact(zapcore.WarnLevel).Len() == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	if logs.FilterLevelExact(zapcore.WarnLevel).Len() == 0 {
+		t.Error("expected the default threshold to log a warning")
+	}
+}
+
+// TestResetCacheStats makes sure the hit and miss counts start over after a reset
+func TestResetCacheStats(t *testing.T) {
+	metrics.mutex.Lock()
+	metrics.HitCount = 5
+	metrics.MissCount = 3
+	metrics.mutex.Unlock()
+
+	ResetCacheStats()
+
+	stats := GetCacheStats()
+	if stats["total_requests"].(int64) != 0 || stats["hit_rate"].(float64) != 0 {
+		t.Errorf("expected empty stats after reset, got %v", stats)
+	}
+}
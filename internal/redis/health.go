@@ -0,0 +1,136 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// Redis可用性检查相关常量
+const (
+	HealthCheckInterval = 30 * time.Second
+	healthCheckTimeout  = 2 * time.Second
+)
+
+// Redis可用状态，由健康检查定时更新
+var available atomic.Bool
+
+// ErrUnavailable Redis不可用时非游戏缓存命令返回的错误
+var ErrUnavailable = errors.New("redis unavailable")
+
+// IsAvailable 返回最近一次健康检查的结果，客户端未初始化时视为不可用
+func IsAvailable() bool {
+	return client != nil && available.Load()
+}
+
+// Status 返回Redis状态，用于健康检查接口
+func Status() string {
+	switch {
+	case client == nil:
+		return "disabled"
+	case available.Load():
+		return "available"
+	default:
+		return "unavailable"
+	}
+}
+
+// CheckAvailability ping Redis并更新可用状态，状态变化时记录日志
+func CheckAvailability(ctx context.Context) bool {
+	if client == nil {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	err := client.Ping(ctx).Err()
+	up := err == nil
+	if was := available.Swap(up); was != up {
+		if up {
+			logger.Ctx(ctx).Info("Redis is available again, cache enabled")
+		} else {
+			logger.Ctx(ctx).Warn("Redis is unavailable, falling back to database only", zap.Error(err))
+		}
+	}
+
+	return up
+}
+
+// MonitorAvailability 定时检查Redis可用性直到ctx取消
+func MonitorAvailability(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			CheckAvailability(ctx)
+		}
+	}
+}
+
+// availabilityHook Redis不可用时游戏缓存的读取视为缓存未命中，写入直接跳过，
+// 其他命令返回ErrUnavailable，健康检查的ping不受影响
+type availabilityHook struct{}
+
+func (availabilityHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (availabilityHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if available.Load() || cmd.Name() == "ping" {
+			return next(ctx, cmd)
+		}
+
+		if isGameCacheCommand(cmd) {
+			if cmd.Name() == "get" {
+				cmd.SetErr(redis.Nil)
+				return redis.Nil
+			}
+
+			logger.Ctx(ctx).Debug("Redis unavailable, skipping cache command",
+				zap.String("command", cmd.Name()))
+			return nil
+		}
+
+		cmd.SetErr(ErrUnavailable)
+		return ErrUnavailable
+	}
+}
+
+func (availabilityHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		if available.Load() {
+			return next(ctx, cmds)
+		}
+
+		for _, cmd := range cmds {
+			cmd.SetErr(ErrUnavailable)
+		}
+		return ErrUnavailable
+	}
+}
+
+// isGameCacheCommand 判断命令是否为游戏缓存的读取或写入
+func isGameCacheCommand(cmd redis.Cmder) bool {
+	if cmd.Name() != "get" && cmd.Name() != "set" {
+		return false
+	}
+
+	args := cmd.Args()
+	if len(args) < 2 {
+		return false
+	}
+	key, ok := args[1].(string)
+
+	return ok && strings.HasPrefix(key, KeyPrefixGame)
+}
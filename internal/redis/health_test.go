@@ -0,0 +1,198 @@
+package redis
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// fakeRedis is a minimal in memory redis server answering PING, GET and SET until it's stopped
+type fakeRedis struct {
+	listener net.Listener
+	mu       sync.Mutex
+	data     map[string]string
+	conns    []net.Conn
+}
+
+func startFakeRedis(t *testing.T) *fakeRedis {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	f := &fakeRedis{listener: listener, data: make(map[string]string)}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			f.mu.Lock()
+			f.conns = append(f.conns, conn)
+			f.mu.Unlock()
+			go f.serve(conn)
+		}
+	}()
+	t.Cleanup(f.stop)
+
+	return f
+}
+
+func (f *fakeRedis) serve(conn net.Conn) {
+	reader := bufio.NewReader(conn)
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+
+		f.mu.Lock()
+		var reply string
+		switch strings.ToLower(args[0]) {
+		case "ping":
+			reply = "+PONG\r\n"
+		case "get":
+			if v, ok := f.data[args[1]]; ok {
+				reply = fmt.Sprintf("$%d\r\n%s\r\n", len(v), v)
+			} else {
+				reply = "$-1\r\n"
+			}
+		case "set":
+			f.data[args[1]] = args[2]
+			reply = "+OK\r\n"
+		default:
+			reply = "-ERR unknown command\r\n"
+		}
+		f.mu.Unlock()
+
+		if _, err := conn.Write([]byte(reply)); err != nil {
+			return
+		}
+	}
+}
+
+// stop closes the listener and every open connection as if redis went down
+func (f *fakeRedis) stop() {
+	_ = f.listener.Close()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, conn := range f.conns {
+		_ = conn.Close()
+	}
+}
+
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(line)[1:])
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		if _, err := reader.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		arg, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, strings.TrimSuffix(arg, "\r\n"))
+	}
+
+	return args, nil
+}
+
+// TestRedisFailover makes sure game cache reads fall back to the database and writes are skipped
+// without error logs once redis goes down mid operation, while other commands fail
+func TestRedisFailover(t *testing.T) {
+	server := startFakeRedis(t)
+	core, logs := observer.New(zapcore.DebugLevel)
+	logger = otelzap.New(zap.New(core))
+	client = redis.NewClient(&redis.Options{
+		Addr:             server.listener.Addr().String(),
+		Protocol:         2,
+		DisableIndentity: true,
+		DialTimeout:      100 * time.Millisecond,
+		MaxRetries:       -1,
+	})
+	client.AddHook(availabilityHook{})
+	testClient := client
+	t.Cleanup(func() {
+		_ = testClient.Close()
+		client = nil
+		available.Store(false)
+	})
+
+	dbReads := 0
+	// getGame reads through the cache like the data services do
+	getGame := func(ctx context.Context) string {
+		if cached, err := client.Get(ctx, "game:1").Result(); err == nil {
+			return cached
+		}
+		dbReads++
+		_ = client.Set(ctx, "game:1", "from db", time.Hour).Err()
+		return "from db"
+	}
+
+	ctx := context.Background()
+	if !CheckAvailability(ctx) || Status() != "available" {
+		t.Fatalf("expected redis to be available, got %s", Status())
+	}
+	getGame(ctx)
+	getGame(ctx)
+	if dbReads != 1 {
+		t.Fatalf("expected the second read to hit the cache, got %d database reads", dbReads)
+	}
+
+	server.stop()
+	if CheckAvailability(ctx) || IsAvailable() || Status() != "unavailable" {
+		t.Fatalf("expected redis to be unavailable, got %s", Status())
+	}
+
+	for i := 0; i < 3; i++ {
+		if game := getGame(ctx); game != "from db" {
+			t.Errorf("expected the game from the database, got %q", game)
+		}
+	}
+	if dbReads != 4 {
+		t.Errorf("expected every read to fall back to the database, got %d database reads", dbReads)
+	}
+	if _, err := client.Get(ctx, "game:1").Result(); !errors.Is(err, redis.Nil) {
+		t.Errorf("expected reads to be cache misses, got %v", err)
+	}
+	if n := logs.FilterLevelExact(zapcore.ErrorLevel).Len(); n != 0 {
+		t.Errorf("expected no error logs while redis is down, got %d", n)
+	}
+	if logs.FilterMessage("Redis unavailable, skipping cache command").Len() == 0 {
+		t.Error("expected skipped cache writes to be logged at debug")
+	}
+
+	if err := client.Get(ctx, "user:1").Err(); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("expected reads outside the game cache to fail, got %v", err)
+	}
+	if err := client.Del(ctx, "game:1").Err(); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("expected deletes to fail, got %v", err)
+	}
+	if _, err := client.SetNX(ctx, "lock:1", "1", time.Minute).Result(); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("expected set if not exists to fail, got %v", err)
+	}
+}
@@ -3,6 +3,7 @@ package redis
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"sync"
@@ -10,9 +11,16 @@ import (
 
 	"github.com/redis/go-redis/v9"
 	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
+// tracerName 链路追踪的instrumentation名称
+const tracerName = "github.com/StevenWeathers/thunderdome-planning-poker/internal/redis"
+
 // 缓存键前缀常量
 const (
 	KeyPrefixGame     = "game:"
@@ -98,6 +106,10 @@ func InitRedis(cfg *Config, zapLogger *otelzap.Logger) error {
 	}
 
 	client = redis.NewClient(opts)
+	// Redis不可用时跳过缓存命令，需在链路追踪之前添加
+	client.AddHook(availabilityHook{})
+	// 为底层Redis命令添加链路追踪
+	client.AddHook(tracingHook{})
 	logger.Info("Redis client created, attempting to ping")
 
 	// 测试连接，使用带超时的context
@@ -110,6 +122,7 @@ func InitRedis(cfg *Config, zapLogger *otelzap.Logger) error {
 			zap.String("addr", addr))
 		return fmt.Errorf("failed to ping redis: %v", err)
 	}
+	available.Store(true)
 
 	// 尝试设置一个测试值
 	testKey := "test_connection"
@@ -153,7 +166,10 @@ func GetClient() *redis.Client {
 }
 
 // Set 设置缓存
-func Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+func Set(ctx context.Context, key string, value interface{}, expiration time.Duration) (err error) {
+	ctx, span := startSpan(ctx, "set", key)
+	defer func() { endSpan(span, err) }()
+
 	if client == nil {
 		return fmt.Errorf("redis client is nil")
 	}
@@ -165,20 +181,27 @@ func Set(ctx context.Context, key string, value interface{}, expiration time.Dur
 
 	err = client.Set(ctx, key, data, expiration).Err()
 	if err != nil {
-		logger.Error("Failed to set cache",
+		logger.Ctx(ctx).Error("Failed to set cache",
 			zap.Error(err),
 			zap.String("key", key))
 		return err
 	}
 
-	logger.Info("Cache set successfully",
+	logger.Ctx(ctx).Info("Cache set successfully",
 		zap.String("key", key),
 		zap.Int("data_size", len(data)))
 	return nil
 }
 
 // Get 获取缓存
-func Get(ctx context.Context, key string, value interface{}) error {
+func Get(ctx context.Context, key string, value interface{}) (err error) {
+	ctx, span := startSpan(ctx, "get", key)
+	defer func() { endSpan(span, err) }()
+
+	if client == nil {
+		return fmt.Errorf("redis client is nil")
+	}
+
 	data, err := client.Get(ctx, key).Bytes()
 	if err != nil {
 		// 更新缓存未命中计数
@@ -199,7 +222,14 @@ func Get(ctx context.Context, key string, value interface{}) error {
 }
 
 // Delete 删除缓存
-func Delete(ctx context.Context, key string) error {
+func Delete(ctx context.Context, key string) (err error) {
+	ctx, span := startSpan(ctx, "delete", key)
+	defer func() { endSpan(span, err) }()
+
+	if client == nil {
+		return fmt.Errorf("redis client is nil")
+	}
+
 	return client.Del(ctx, key).Err()
 }
 
@@ -213,7 +243,14 @@ func Exists(ctx context.Context, key string) (bool, error) {
 }
 
 // SetNX 设置缓存（如果不存在）
-func SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+func SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (set bool, err error) {
+	ctx, span := startSpan(ctx, "setnx", key)
+	defer func() { endSpan(span, err) }()
+
+	if client == nil {
+		return false, fmt.Errorf("redis client is nil")
+	}
+
 	data, err := json.Marshal(value)
 	if err != nil {
 		return false, err
@@ -223,7 +260,10 @@ func SetNX(ctx context.Context, key string, value interface{}, expiration time.D
 }
 
 // GetOrSet 获取缓存，如果不存在则设置
-func GetOrSet(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+func GetOrSet(ctx context.Context, key string, value interface{}, expiration time.Duration) (err error) {
+	ctx, span := startSpan(ctx, "getorset", key)
+	defer func() { endSpan(span, err) }()
+
 	exists, err := Exists(ctx, key)
 	if err != nil {
 		return err
@@ -246,14 +286,17 @@ func GenerateCacheKey(prefix string, id string, subType ...string) string {
 }
 
 // InvalidateByPattern 根据模式使缓存失效
-func InvalidateByPattern(ctx context.Context, pattern string) (int64, error) {
+func InvalidateByPattern(ctx context.Context, pattern string) (deletedCount int64, err error) {
+	ctx, span := startSpan(ctx, "invalidate_by_pattern", pattern)
+	defer func() { endSpan(span, err) }()
+
 	if client == nil {
 		return 0, fmt.Errorf("redis client is nil")
 	}
 
 	keys, err := client.Keys(ctx, pattern).Result()
 	if err != nil {
-		logger.Error("Failed to get keys for invalidation",
+		logger.Ctx(ctx).Error("Failed to get keys for invalidation",
 			zap.Error(err), zap.String("pattern", pattern))
 		return 0, err
 	}
@@ -261,11 +304,11 @@ func InvalidateByPattern(ctx context.Context, pattern string) (int64, error) {
 	if len(keys) > 0 {
 		deleted, err := client.Del(ctx, keys...).Result()
 		if err != nil {
-			logger.Error("Failed to delete keys",
+			logger.Ctx(ctx).Error("Failed to delete keys",
 				zap.Error(err), zap.Strings("keys", keys))
 			return 0, err
 		}
-		logger.Info("Invalidated cache keys",
+		logger.Ctx(ctx).Info("Invalidated cache keys",
 			zap.String("pattern", pattern),
 			zap.Int64("deleted_count", deleted))
 		return deleted, nil
@@ -292,3 +335,64 @@ func GetCacheStats() map[string]interface{} {
 		"hit_rate":       hitRate,
 	}
 }
+
+// startSpan 为Redis操作创建子span
+func startSpan(ctx context.Context, operation string, key string) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, "redis."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("redis.key", key),
+			attribute.String("redis.operation", operation),
+		),
+	)
+}
+
+// endSpan 记录错误并结束span，缓存未命中不视为错误
+func endSpan(span trace.Span, err error) {
+	if err != nil && !errors.Is(err, redis.Nil) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	} else {
+		span.SetStatus(codes.Ok, "")
+	}
+	span.End()
+}
+
+// tracingHook 为底层Redis命令和管道创建span
+type tracingHook struct{}
+
+func (tracingHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (tracingHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		ctx, span := otel.Tracer(tracerName).Start(ctx, cmd.FullName(),
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				attribute.String("db.system", "redis"),
+				attribute.String("redis.operation", cmd.FullName()),
+			),
+		)
+		err := next(ctx, cmd)
+		endSpan(span, err)
+
+		return err
+	}
+}
+
+func (tracingHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		ctx, span := otel.Tracer(tracerName).Start(ctx, "redis.pipeline",
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				attribute.String("db.system", "redis"),
+				attribute.Int("redis.pipeline_length", len(cmds)),
+			),
+		)
+		err := next(ctx, cmds)
+		endSpan(span, err)
+
+		return err
+	}
+}
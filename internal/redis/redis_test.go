@@ -0,0 +1,119 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/zap"
+)
+
+func setupTracing(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previousProvider := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+
+	logger = otelzap.New(zap.NewNop())
+	// nothing listens on port 1 so commands fail fast without a running redis
+	client = redis.NewClient(&redis.Options{
+		Addr:        "127.0.0.1:1",
+		DialTimeout: 100 * time.Millisecond,
+		MaxRetries:  -1,
+	})
+	client.AddHook(tracingHook{})
+
+	testClient := client
+	t.Cleanup(func() {
+		_ = testClient.Close()
+		client = nil
+		otel.SetTracerProvider(previousProvider)
+		_ = provider.Shutdown(context.Background())
+	})
+
+	return recorder
+}
+
+func findSpan(spans []sdktrace.ReadOnlySpan, name string) sdktrace.ReadOnlySpan {
+	for _, span := range spans {
+		if span.Name() == name {
+			return span
+		}
+	}
+
+	return nil
+}
+
+func spanAttribute(span sdktrace.ReadOnlySpan, key attribute.Key) string {
+	for _, attr := range span.Attributes() {
+		if attr.Key == key {
+			return attr.Value.AsString()
+		}
+	}
+
+	return ""
+}
+
+// TestGetTracing makes sure Get creates a redis.get span with the command span as its child
+func TestGetTracing(t *testing.T) {
+	recorder := setupTracing(t)
+
+	var value string
+	err := Get(context.Background(), "game:123", &value)
+	if err == nil {
+		t.Fatal("expected connection error")
+	}
+
+	spans := recorder.Ended()
+	getSpan := findSpan(spans, "redis.get")
+	if getSpan == nil {
+		t.Fatalf("expected redis.get span, got %d spans", len(spans))
+	}
+	if got := spanAttribute(getSpan, "redis.key"); got != "game:123" {
+		t.Errorf("expected redis.key attribute game:123, got %q", got)
+	}
+	if got := spanAttribute(getSpan, "redis.operation"); got != "get" {
+		t.Errorf("expected redis.operation attribute get, got %q", got)
+	}
+	if getSpan.Status().Code != codes.Error {
+		t.Errorf("expected error status, got %v", getSpan.Status().Code)
+	}
+	if len(getSpan.Events()) == 0 {
+		t.Error("expected the error to be recorded on the span")
+	}
+
+	commandSpan := findSpan(spans, "get")
+	if commandSpan == nil {
+		t.Fatal("expected hook command span")
+	}
+	if commandSpan.Parent().SpanID() != getSpan.SpanContext().SpanID() {
+		t.Error("expected command span to be a child of redis.get")
+	}
+}
+
+// TestDeleteTracingWithoutClient makes sure operations are traced even when redis isn't initialized
+func TestDeleteTracingWithoutClient(t *testing.T) {
+	recorder := setupTracing(t)
+	client = nil
+
+	if err := Delete(context.Background(), "user:123"); err == nil {
+		t.Fatal("expected nil client error")
+	}
+
+	deleteSpan := findSpan(recorder.Ended(), "redis.delete")
+	if deleteSpan == nil {
+		t.Fatal("expected redis.delete span")
+	}
+	if deleteSpan.Status().Code != codes.Error {
+		t.Errorf("expected error status, got %v", deleteSpan.Status().Code)
+	}
+}
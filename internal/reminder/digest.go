@@ -0,0 +1,131 @@
+package reminder
+
+import (
+	"context"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/robfig/cron"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+// digestCronExpression is when the weekly standup digest is sent, Mondays at 9am UTC
+const digestCronExpression = "0 9 * * 1"
+
+// StandupDataSvc provides the teams with standups and their weekly standup digest
+type StandupDataSvc interface {
+	StandupDigestTeams(ctx context.Context, startDate time.Time, endDate time.Time) ([]*thunderdome.Team, error)
+	ClaimStandupDigest(ctx context.Context, teamID string, dueAt time.Time) (bool, error)
+	GetStandupDigest(ctx context.Context, teamID string, startDate time.Time, endDate time.Time) ([]*thunderdome.StandupDigestEntry, error)
+}
+
+// TeamDataSvc provides the team users the standup digest is sent to
+type TeamDataSvc interface {
+	TeamUserList(ctx context.Context, teamID string, limit int, offset int) ([]*thunderdome.TeamUser, int, error)
+}
+
+// DigestEmailService sends the standup digest emails
+type DigestEmailService interface {
+	SendStandupDigest(teamName string, digest []*thunderdome.StandupDigestEntry, userName string, userEmail string) error
+}
+
+// DigestScheduler sends each team with standups their weekly standup digest
+type DigestScheduler struct {
+	logger   *otelzap.Logger
+	dataSvc  StandupDataSvc
+	teamSvc  TeamDataSvc
+	emailSvc DigestEmailService
+	schedule cron.Schedule
+}
+
+// NewDigestScheduler returns a new weekly standup digest scheduler
+func NewDigestScheduler(logger *otelzap.Logger, dataSvc StandupDataSvc, teamSvc TeamDataSvc, emailSvc DigestEmailService) *DigestScheduler {
+	schedule, _ := cron.ParseStandard(digestCronExpression)
+
+	return &DigestScheduler{
+		logger:   logger,
+		dataSvc:  dataSvc,
+		teamSvc:  teamSvc,
+		emailSvc: emailSvc,
+		schedule: schedule,
+	}
+}
+
+// Run checks whether the weekly digest came due every minute until the context is cancelled
+func (s *DigestScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	last := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.tick(ctx, last, now)
+			last = now
+		}
+	}
+}
+
+// tick sends the digests when the digest came due after from and up to (and including) to
+func (s *DigestScheduler) tick(ctx context.Context, from time.Time, to time.Time) {
+	dueAt := s.schedule.Next(from.UTC())
+	if dueAt.IsZero() || dueAt.After(to) {
+		return
+	}
+
+	// the digest covers the week before it came due
+	endDate := dueAt.AddDate(0, 0, -1)
+	startDate := endDate.AddDate(0, 0, -(thunderdome.StandupDigestDays - 1))
+	teams, err := s.dataSvc.StandupDigestTeams(ctx, startDate, endDate)
+	if err != nil {
+		s.logger.Ctx(ctx).Error("standup digest get teams error", zap.Error(err))
+		return
+	}
+
+	for _, team := range teams {
+		// another instance may have already sent the team's digest
+		claimed, err := s.dataSvc.ClaimStandupDigest(ctx, team.ID, dueAt)
+		if err != nil {
+			s.logger.Ctx(ctx).Error("standup digest claim error", zap.Error(err),
+				zap.String("team_id", team.ID))
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		s.send(ctx, team, startDate, endDate)
+	}
+}
+
+// send emails the team's standup digest to the team users
+func (s *DigestScheduler) send(ctx context.Context, team *thunderdome.Team, startDate time.Time, endDate time.Time) {
+	digest, err := s.dataSvc.GetStandupDigest(ctx, team.ID, startDate, endDate)
+	if err != nil {
+		s.logger.Ctx(ctx).Error("standup digest get digest error", zap.Error(err),
+			zap.String("team_id", team.ID))
+		return
+	}
+	if len(digest) == 0 {
+		return
+	}
+
+	users, _, err := s.teamSvc.TeamUserList(ctx, team.ID, 1000, 0)
+	if err != nil {
+		s.logger.Ctx(ctx).Error("standup digest get team users error", zap.Error(err),
+			zap.String("team_id", team.ID))
+		return
+	}
+
+	for _, user := range users {
+		if user.Email == "" {
+			continue
+		}
+		if err := s.emailSvc.SendStandupDigest(team.Name, digest, user.Name, user.Email); err != nil {
+			s.logger.Ctx(ctx).Error("standup digest send email error", zap.Error(err),
+				zap.String("team_id", team.ID), zap.String("user_id", user.ID))
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package reminder
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+type fakeStandupDataSvc struct {
+	ranges  []string
+	claimed map[string]time.Time
+}
+
+func (f *fakeStandupDataSvc) StandupDigestTeams(ctx context.Context, startDate time.Time, endDate time.Time) ([]*thunderdome.Team, error) {
+	f.ranges = append(f.ranges, startDate.Format(time.DateOnly)+":"+endDate.Format(time.DateOnly))
+
+	return []*thunderdome.Team{{ID: "team", Name: "Team"}}, nil
+}
+
+func (f *fakeStandupDataSvc) ClaimStandupDigest(ctx context.Context, teamID string, dueAt time.Time) (bool, error) {
+	if f.claimed == nil {
+		f.claimed = make(map[string]time.Time)
+	}
+	if last, ok := f.claimed[teamID]; ok && !last.Before(dueAt) {
+		return false, nil
+	}
+	f.claimed[teamID] = dueAt
+
+	return true, nil
+}
+
+func (f *fakeStandupDataSvc) GetStandupDigest(ctx context.Context, teamID string, startDate time.Time, endDate time.Time) ([]*thunderdome.StandupDigestEntry, error) {
+	return []*thunderdome.StandupDigestEntry{{User: &thunderdome.TeamUser{ID: "1"}}}, nil
+}
+
+type fakeTeamDataSvc struct{}
+
+func (f *fakeTeamDataSvc) TeamUserList(ctx context.Context, teamID string, limit int, offset int) ([]*thunderdome.TeamUser, int, error) {
+	return []*thunderdome.TeamUser{
+		{ID: "1", Name: "Member", Email: "member@thunderdome.dev"},
+		{ID: "2", Name: "Guest"},
+	}, 2, nil
+}
+
+type fakeDigestEmailService struct {
+	sent map[string]int
+}
+
+func (f *fakeDigestEmailService) SendStandupDigest(teamName string, digest []*thunderdome.StandupDigestEntry, userName string, userEmail string) error {
+	f.sent[userEmail]++
+	return nil
+}
+
+// TestDigestSchedulerSendsWeekly makes sure the digest is sent once a week covering the previous week,
+// even with multiple instances running the scheduler
+func TestDigestSchedulerSendsWeekly(t *testing.T) {
+	// a Sunday
+	start := time.Date(2025, 3, 9, 0, 0, 0, 0, time.UTC)
+	dataSvc := &fakeStandupDataSvc{}
+	emailSvc := &fakeDigestEmailService{sent: make(map[string]int)}
+	first := NewDigestScheduler(otelzap.New(zap.NewNop()), dataSvc, &fakeTeamDataSvc{}, emailSvc)
+	second := NewDigestScheduler(otelzap.New(zap.NewNop()), dataSvc, &fakeTeamDataSvc{}, emailSvc)
+
+	last := start
+	for now := start.Add(time.Minute); !now.After(start.Add(7 * 24 * time.Hour)); now = now.Add(time.Minute) {
+		first.tick(context.Background(), last, now)
+		second.tick(context.Background(), last, now)
+		last = now
+	}
+
+	if sent := emailSvc.sent["member@thunderdome.dev"]; sent != 1 {
+		t.Errorf("expected a single digest email, got %d", sent)
+	}
+	if len(emailSvc.sent) != 1 {
+		t.Errorf("expected users without an email to be skipped, got %v", emailSvc.sent)
+	}
+	if len(dataSvc.ranges) == 0 || dataSvc.ranges[0] != "2025-03-03:2025-03-09" {
+		t.Errorf("expected the digest to cover the week before Monday, got %v", dataSvc.ranges)
+	}
+}
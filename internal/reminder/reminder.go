@@ -0,0 +1,130 @@
+// Package reminder provides the team checkin reminder scheduler for Thunderdome
+package reminder
+
+import (
+	"context"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/robfig/cron"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+// checkInterval is how often the checkin schedules are evaluated
+const checkInterval = time.Minute
+
+// CheckinDataSvc provides the team checkin schedules and the users missing a checkin
+type CheckinDataSvc interface {
+	CheckinSchedulesEnabled(ctx context.Context) ([]*thunderdome.CheckinSchedule, error)
+	CheckinScheduleClaim(ctx context.Context, teamID string, dueAt time.Time) (bool, error)
+	CheckinMissingUsers(ctx context.Context, teamID string, date time.Time, timezoneOffset int) (string, []*thunderdome.TeamUser, error)
+}
+
+// EmailService sends the checkin reminder emails
+type EmailService interface {
+	SendCheckinReminder(teamID string, teamName string, userName string, userEmail string) error
+}
+
+// Scheduler sends the team checkin reminders when their cron schedule comes due
+type Scheduler struct {
+	logger   *otelzap.Logger
+	dataSvc  CheckinDataSvc
+	emailSvc EmailService
+}
+
+// New returns a new checkin reminder scheduler
+func New(logger *otelzap.Logger, dataSvc CheckinDataSvc, emailSvc EmailService) *Scheduler {
+	return &Scheduler{
+		logger:   logger,
+		dataSvc:  dataSvc,
+		emailSvc: emailSvc,
+	}
+}
+
+// ValidateCronExpression makes sure the cron expression is a valid standard (5 field) cron expression
+func ValidateCronExpression(cronExpression string) error {
+	_, err := cron.ParseStandard(cronExpression)
+	return err
+}
+
+// Run evaluates the checkin schedules every minute until the context is cancelled
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	last := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.tick(ctx, last, now)
+			last = now
+		}
+	}
+}
+
+// tick sends the reminders for every schedule that came due after from and up to (and including) to
+func (s *Scheduler) tick(ctx context.Context, from time.Time, to time.Time) {
+	schedules, err := s.dataSvc.CheckinSchedulesEnabled(ctx)
+	if err != nil {
+		s.logger.Ctx(ctx).Error("checkin reminder get schedules error", zap.Error(err))
+		return
+	}
+
+	for _, schedule := range schedules {
+		dueAt, ok := scheduleDue(schedule, from, to)
+		if !ok {
+			continue
+		}
+		// another instance may have already sent the reminder for this due time
+		claimed, err := s.dataSvc.CheckinScheduleClaim(ctx, schedule.TeamID, dueAt)
+		if err != nil {
+			s.logger.Ctx(ctx).Error("checkin reminder claim schedule error", zap.Error(err),
+				zap.String("team_id", schedule.TeamID))
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		s.remind(ctx, schedule, dueAt)
+	}
+}
+
+// scheduleDue returns when the schedule comes due after from, and whether that is no later than to,
+// the cron expression is evaluated in the schedule's timezone offset
+func scheduleDue(schedule *thunderdome.CheckinSchedule, from time.Time, to time.Time) (time.Time, bool) {
+	cronSchedule, err := cron.ParseStandard(schedule.CronExpression)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	location := time.FixedZone("", schedule.TimezoneOffset*60)
+	next := cronSchedule.Next(from.In(location))
+	if next.IsZero() || next.After(to) {
+		return time.Time{}, false
+	}
+
+	return next, true
+}
+
+// remind emails the team users who have not checked in on the day the schedule came due
+func (s *Scheduler) remind(ctx context.Context, schedule *thunderdome.CheckinSchedule, dueAt time.Time) {
+	teamName, users, err := s.dataSvc.CheckinMissingUsers(ctx, schedule.TeamID, dueAt, schedule.TimezoneOffset)
+	if err != nil {
+		s.logger.Ctx(ctx).Error("checkin reminder get missing users error", zap.Error(err),
+			zap.String("team_id", schedule.TeamID))
+		return
+	}
+
+	for _, user := range users {
+		if user.Email == "" {
+			continue
+		}
+		if err := s.emailSvc.SendCheckinReminder(schedule.TeamID, teamName, user.Name, user.Email); err != nil {
+			s.logger.Ctx(ctx).Error("checkin reminder send email error", zap.Error(err),
+				zap.String("team_id", schedule.TeamID), zap.String("user_id", user.ID))
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package reminder
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+type reminderCall struct {
+	teamID string
+	date   string
+}
+
+type fakeCheckinDataSvc struct {
+	schedules []*thunderdome.CheckinSchedule
+	calls     []reminderCall
+	claimed   map[string]time.Time
+}
+
+func (f *fakeCheckinDataSvc) CheckinSchedulesEnabled(ctx context.Context) ([]*thunderdome.CheckinSchedule, error) {
+	return f.schedules, nil
+}
+
+func (f *fakeCheckinDataSvc) CheckinScheduleClaim(ctx context.Context, teamID string, dueAt time.Time) (bool, error) {
+	if f.claimed == nil {
+		f.claimed = make(map[string]time.Time)
+	}
+	if last, ok := f.claimed[teamID]; ok && !last.Before(dueAt) {
+		return false, nil
+	}
+	f.claimed[teamID] = dueAt
+
+	return true, nil
+}
+
+func (f *fakeCheckinDataSvc) CheckinMissingUsers(ctx context.Context, teamID string, date time.Time, timezoneOffset int) (string, []*thunderdome.TeamUser, error) {
+	f.calls = append(f.calls, reminderCall{teamID: teamID, date: date.Format(time.DateOnly)})
+
+	return "Team " + teamID, []*thunderdome.TeamUser{
+		{ID: "1", Name: "Missing", Email: "missing@thunderdome.dev"},
+		{ID: "2", Name: "Guest"},
+	}, nil
+}
+
+type fakeEmailService struct {
+	sent map[string]int
+}
+
+func (f *fakeEmailService) SendCheckinReminder(teamID string, teamName string, userName string, userEmail string) error {
+	f.sent[teamID+":"+userEmail]++
+	return nil
+}
+
+// simulateDay runs the scheduler minute by minute over 24 hours from start
+func simulateDay(schedules []*thunderdome.CheckinSchedule, start time.Time) (*fakeCheckinDataSvc, *fakeEmailService) {
+	dataSvc := &fakeCheckinDataSvc{schedules: schedules}
+	emailSvc := &fakeEmailService{sent: make(map[string]int)}
+	s := New(otelzap.New(zap.NewNop()), dataSvc, emailSvc)
+
+	last := start
+	for now := start.Add(time.Minute); !now.After(start.Add(24 * time.Hour)); now = now.Add(time.Minute) {
+		s.tick(context.Background(), last, now)
+		last = now
+	}
+
+	return dataSvc, emailSvc
+}
+
+func TestSchedulerTriggersOncePerInterval(t *testing.T) {
+	// a Monday
+	start := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		cron     string
+		offset   int
+		expected int
+	}{
+		{name: "daily", cron: "0 9 * * *", expected: 1},
+		{name: "weekdays", cron: "30 9 * * 1-5", expected: 1},
+		{name: "weekends", cron: "30 9 * * 0,6", expected: 0},
+		{name: "every 6 hours", cron: "0 */6 * * *", expected: 4},
+		{name: "hourly", cron: "15 * * * *", expected: 24},
+		{name: "daily with timezone offset", cron: "0 9 * * *", offset: -300, expected: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dataSvc, emailSvc := simulateDay([]*thunderdome.CheckinSchedule{
+				{TeamID: "team", CronExpression: tt.cron, TimezoneOffset: tt.offset, ReminderEnabled: true},
+			}, start)
+
+			if len(dataSvc.calls) != tt.expected {
+				t.Fatalf("expected %d reminders, got %d", tt.expected, len(dataSvc.calls))
+			}
+			if sent := emailSvc.sent["team:missing@thunderdome.dev"]; sent != tt.expected {
+				t.Errorf("expected %d reminder emails, got %d", tt.expected, sent)
+			}
+			if len(emailSvc.sent) > 1 {
+				t.Errorf("expected users without an email to be skipped, got %v", emailSvc.sent)
+			}
+		})
+	}
+}
+
+func TestSchedulerTimezoneOffset(t *testing.T) {
+	start := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	dataSvc := &fakeCheckinDataSvc{schedules: []*thunderdome.CheckinSchedule{
+		{TeamID: "team", CronExpression: "0 9 * * *", TimezoneOffset: -300, ReminderEnabled: true},
+	}}
+	s := New(otelzap.New(zap.NewNop()), dataSvc, &fakeEmailService{sent: make(map[string]int)})
+
+	// 9am at UTC-5 is 2pm UTC
+	s.tick(context.Background(), start.Add(13*time.Hour+59*time.Minute), start.Add(14*time.Hour))
+	if len(dataSvc.calls) != 1 {
+		t.Fatalf("expected a reminder at 2pm UTC, got %d", len(dataSvc.calls))
+	}
+	if dataSvc.calls[0].date != "2025-03-10" {
+		t.Errorf("expected reminder for 2025-03-10, got %s", dataSvc.calls[0].date)
+	}
+
+	s.tick(context.Background(), start.Add(8*time.Hour+59*time.Minute), start.Add(9*time.Hour))
+	if len(dataSvc.calls) != 1 {
+		t.Errorf("expected no reminder at 9am UTC, got %d", len(dataSvc.calls))
+	}
+}
+
+// TestSchedulerClaimsEachDueTimeOnce makes sure instances sharing the schedules send each reminder once
+func TestSchedulerClaimsEachDueTimeOnce(t *testing.T) {
+	start := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	dataSvc := &fakeCheckinDataSvc{schedules: []*thunderdome.CheckinSchedule{
+		{TeamID: "team", CronExpression: "0 9 * * *", ReminderEnabled: true},
+	}}
+	emailSvc := &fakeEmailService{sent: make(map[string]int)}
+	first := New(otelzap.New(zap.NewNop()), dataSvc, emailSvc)
+	second := New(otelzap.New(zap.NewNop()), dataSvc, emailSvc)
+
+	from, to := start.Add(8*time.Hour+59*time.Minute), start.Add(9*time.Hour)
+	first.tick(context.Background(), from, to)
+	second.tick(context.Background(), from, to)
+
+	if len(dataSvc.calls) != 1 {
+		t.Fatalf("expected a single reminder, got %d", len(dataSvc.calls))
+	}
+	if sent := emailSvc.sent["team:missing@thunderdome.dev"]; sent != 1 {
+		t.Errorf("expected a single reminder email, got %d", sent)
+	}
+}
+
+func TestValidateCronExpression(t *testing.T) {
+	for _, expr := range []string{"0 9 * * *", "30 9 * * 1-5", "*/15 * * * *", "@daily"} {
+		if err := ValidateCronExpression(expr); err != nil {
+			t.Errorf("expected %q to be valid, got %v", expr, err)
+		}
+	}
+	for _, expr := range []string{"", "not a cron", "61 9 * * *", "0 9 * *", "0 0 9 * * *"} {
+		if err := ValidateCronExpression(expr); err == nil {
+			t.Errorf("expected %q to be invalid", expr)
+		}
+	}
+}
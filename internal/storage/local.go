@@ -0,0 +1,247 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Local provider routes, relative to the application path prefix
+const (
+	LocalUploadPath   = "/storage/upload"
+	LocalDownloadPath = "/static/attachments"
+)
+
+// Max size in bytes of a local upload form field other than the file
+const maxFormFieldSize = 4096
+
+// LocalFSImpl stores attachments on the local filesystem for development, upload and download URLs are
+// signed with a key generated on startup so they stop working when the application restarts
+type LocalFSImpl struct {
+	config     Config
+	signingKey []byte
+	now        func() time.Time
+}
+
+// NewLocalFS creates the local filesystem storage service, creating the storage directory if needed
+func NewLocalFS(config Config) (*LocalFSImpl, error) {
+	if config.LocalPath == "" {
+		return nil, errors.New("local storage requires a path")
+	}
+	if err := os.MkdirAll(config.LocalPath, 0o750); err != nil {
+		return nil, fmt.Errorf("local storage create path error: %v", err)
+	}
+
+	signingKey := make([]byte, 32)
+	if _, err := rand.Read(signingKey); err != nil {
+		return nil, fmt.Errorf("local storage generate signing key error: %v", err)
+	}
+
+	return &LocalFSImpl{config: config, signingKey: signingKey, now: time.Now}, nil
+}
+
+// PresignUpload gets a signed form upload served by HandleUpload, the URL is relative to the application
+func (l *LocalFSImpl) PresignUpload(ctx context.Context, storyID string, filename string, contentType string, size int64) (*PresignedUpload, error) {
+	key, err := objectKey(storyID, filename)
+	if err != nil {
+		return nil, err
+	}
+
+	expires := strconv.FormatInt(l.now().Add(l.config.URLExpiry).Unix(), 10)
+	maxSize := strconv.FormatInt(size, 10)
+
+	return &PresignedUpload{
+		URL: l.config.PathPrefix + LocalUploadPath,
+		Fields: map[string]string{
+			"key":          key,
+			"Content-Type": contentType,
+			"size":         maxSize,
+			"expires":      expires,
+			"signature":    l.sign("upload", key, contentType, maxSize, expires),
+		},
+		Key: key,
+	}, nil
+}
+
+// StatObject gets the uploaded file's size, the content type is detected from the file extension
+func (l *LocalFSImpl) StatObject(ctx context.Context, key string) (*ObjectInfo, error) {
+	filePath, ok := l.filePath(key)
+	if !ok {
+		return nil, ErrObjectNotFound
+	}
+
+	info, err := os.Stat(filePath)
+	if errors.Is(err, os.ErrNotExist) || (err == nil && info.IsDir()) {
+		return nil, ErrObjectNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("local storage stat error: %v", err)
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	return &ObjectInfo{Size: info.Size(), ContentType: contentType}, nil
+}
+
+// PresignDownload gets a signed download URL served by HandleDownload, the URL is relative to the application
+func (l *LocalFSImpl) PresignDownload(ctx context.Context, key string) (string, error) {
+	expires := strconv.FormatInt(l.now().Add(l.config.URLExpiry).Unix(), 10)
+	query := url.Values{}
+	query.Set("expires", expires)
+	query.Set("signature", l.sign("download", key, expires))
+
+	return l.config.PathPrefix + LocalDownloadPath + "/" + key + "?" + query.Encode(), nil
+}
+
+// sign gets the hex encoded HMAC-SHA256 signature of the newline separated values
+func (l *LocalFSImpl) sign(values ...string) string {
+	mac := hmac.New(sha256.New, l.signingKey)
+	mac.Write([]byte(strings.Join(values, "\n")))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// verify checks the signature is valid and hasn't expired, the expiry is the last signed value
+func (l *LocalFSImpl) verify(signature string, values ...string) bool {
+	expiresAt, err := strconv.ParseInt(values[len(values)-1], 10, 64)
+	if err != nil || l.now().Unix() > expiresAt {
+		return false
+	}
+
+	return hmac.Equal([]byte(l.sign(values...)), []byte(signature))
+}
+
+// filePath gets the path on disk for the object key, rejecting keys that escape the storage directory
+func (l *LocalFSImpl) filePath(key string) (string, bool) {
+	key = strings.TrimPrefix(key, "/")
+	if key == "" || path.Clean(key) != key || strings.HasPrefix(key, "..") {
+		return "", false
+	}
+
+	return filepath.Join(l.config.LocalPath, filepath.FromSlash(key)), true
+}
+
+// HandleUpload handles the signed form upload POST requests, the form fields must come before the file
+func (l *LocalFSImpl) HandleUpload() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		reader, err := r.MultipartReader()
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		fields := make(map[string]string)
+		for {
+			part, err := reader.NextPart()
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			if part.FormName() == "file" {
+				l.saveUpload(w, fields, part)
+				return
+			}
+
+			value, err := io.ReadAll(io.LimitReader(part, maxFormFieldSize))
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			fields[part.FormName()] = string(value)
+		}
+	}
+}
+
+// saveUpload writes the uploaded file after checking the signed fields, removing it when it's larger than the signed size
+func (l *LocalFSImpl) saveUpload(w http.ResponseWriter, fields map[string]string, file io.Reader) {
+	key := fields["key"]
+	if !l.verify(fields["signature"], "upload", key, fields["Content-Type"], fields["size"], fields["expires"]) {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	maxSize, err := strconv.ParseInt(fields["size"], 10, 64)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if l.config.MaxUploadSize > 0 && maxSize > l.config.MaxUploadSize {
+		maxSize = l.config.MaxUploadSize
+	}
+
+	filePath, ok := l.filePath(key)
+	if !ok {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filePath), 0o750); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	out, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o640)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	defer out.Close()
+
+	// read one byte past the max size to tell a file of exactly the max size from a larger one
+	written, err := io.Copy(out, io.LimitReader(file, maxSize+1))
+	if err != nil || written > maxSize {
+		_ = out.Close()
+		_ = os.Remove(filePath)
+		if err == nil {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleDownload serves the uploaded files for signed download URLs, the object key is the request path
+// so the handler should be served with the LocalDownloadPath stripped
+func (l *LocalFSImpl) HandleDownload() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimPrefix(r.URL.Path, "/")
+		query := r.URL.Query()
+		if !l.verify(query.Get("signature"), "download", key, query.Get("expires")) {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		filePath, ok := l.filePath(key)
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		info, err := os.Stat(filePath)
+		if err != nil || info.IsDir() {
+			http.NotFound(w, r)
+			return
+		}
+
+		// uploads are served from the application origin so prevent them from running scripts
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.Header().Set("Content-Security-Policy", "sandbox")
+		http.ServeFile(w, r, filePath)
+	}
+}
@@ -0,0 +1,158 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestLocalFS(t *testing.T) *LocalFSImpl {
+	l, err := NewLocalFS(Config{
+		LocalPath:     t.TempDir(),
+		PathPrefix:    "/prefix",
+		URLExpiry:     time.Minute,
+		MaxUploadSize: 16,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return l
+}
+
+func testUpload(t *testing.T, l *LocalFSImpl, fields map[string]string, body string) int {
+	var form bytes.Buffer
+	writer := multipart.NewWriter(&form)
+	for k, v := range fields {
+		if err := writer.WriteField(k, v); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	file, err := writer.CreateFormFile("file", "mockup.png")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, _ = file.Write([]byte(body))
+	_ = writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, LocalUploadPath, &form)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	rr := httptest.NewRecorder()
+	l.HandleUpload().ServeHTTP(rr, req)
+
+	return rr.Code
+}
+
+func testDownload(l *LocalFSImpl, downloadURL string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, strings.TrimPrefix(downloadURL, "/prefix"+LocalDownloadPath), nil)
+	rr := httptest.NewRecorder()
+	l.HandleDownload().ServeHTTP(rr, req)
+
+	return rr
+}
+
+// TestLocalFSUploadAndDownload makes sure a file uploaded with the signed form is served from the signed download URL
+func TestLocalFSUploadAndDownload(t *testing.T) {
+	ctx := context.Background()
+	l := newTestLocalFS(t)
+
+	upload, err := l.PresignUpload(ctx, "story-id", "mockup.png", "image/png", 6)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if upload.URL != "/prefix"+LocalUploadPath || !strings.HasPrefix(upload.Key, "stories/story-id/") {
+		t.Fatalf("unexpected upload %+v", upload)
+	}
+
+	if _, err := l.StatObject(ctx, upload.Key); !errors.Is(err, ErrObjectNotFound) {
+		t.Errorf("expected object not found before upload, got %v", err)
+	}
+
+	if code := testUpload(t, l, upload.Fields, "mockup"); code != http.StatusNoContent {
+		t.Fatalf("expected upload status %d, got %d", http.StatusNoContent, code)
+	}
+
+	info, err := l.StatObject(ctx, upload.Key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.Size != 6 || info.ContentType != "image/png" {
+		t.Errorf("unexpected object info %+v", info)
+	}
+
+	downloadURL, err := l.PresignDownload(ctx, upload.Key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rr := testDownload(l, downloadURL)
+	if rr.Code != http.StatusOK || rr.Body.String() != "mockup" {
+		t.Errorf("expected the uploaded file to be downloaded, got status %d body %q", rr.Code, rr.Body.String())
+	}
+
+	unsigned := "/prefix" + LocalDownloadPath + "/" + upload.Key
+	if rr := testDownload(l, unsigned); rr.Code != http.StatusForbidden {
+		t.Errorf("expected unsigned download status %d, got %d", http.StatusForbidden, rr.Code)
+	}
+}
+
+// TestLocalFSUploadRejected makes sure tampered, expired and oversized uploads are rejected
+func TestLocalFSUploadRejected(t *testing.T) {
+	l := newTestLocalFS(t)
+	upload, err := l.PresignUpload(context.Background(), "story-id", "mockup.png", "image/png", 6)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tampered := map[string]string{}
+	for k, v := range upload.Fields {
+		tampered[k] = v
+	}
+	tampered["Content-Type"] = "text/html"
+	if code := testUpload(t, l, tampered, "mockup"); code != http.StatusForbidden {
+		t.Errorf("expected tampered upload status %d, got %d", http.StatusForbidden, code)
+	}
+
+	if code := testUpload(t, l, upload.Fields, "larger than declared"); code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected oversized upload status %d, got %d", http.StatusRequestEntityTooLarge, code)
+	}
+	files, _ := filepath.Glob(filepath.Join(l.config.LocalPath, "stories", "story-id", "*"))
+	if len(files) != 0 {
+		t.Errorf("expected oversized upload to be removed, found %v", files)
+	}
+
+	l.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	if code := testUpload(t, l, upload.Fields, "mockup"); code != http.StatusForbidden {
+		t.Errorf("expected expired upload status %d, got %d", http.StatusForbidden, code)
+	}
+}
+
+// TestLocalFSDownloadOutsidePath makes sure files outside the storage directory aren't served
+func TestLocalFSDownloadOutsidePath(t *testing.T) {
+	l := newTestLocalFS(t)
+	outside := filepath.Join(filepath.Dir(l.config.LocalPath), "secret.txt")
+	if err := os.WriteFile(outside, []byte("secret"), 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	downloadURL, err := l.PresignDownload(context.Background(), "../secret.txt")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/stories", nil)
+	req.URL.Path = "/../secret.txt"
+	req.URL.RawQuery = downloadURL[strings.Index(downloadURL, "?")+1:]
+	rr := httptest.NewRecorder()
+	l.HandleDownload().ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, rr.Code)
+	}
+}
@@ -0,0 +1,125 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// S3Impl stores attachments in an S3 compatible bucket using pre-signed POST policies for uploads
+// and pre-signed GET URLs for downloads so the bucket can stay private
+type S3Impl struct {
+	config    Config
+	client    *s3.Client
+	presigner *s3.PresignClient
+}
+
+// NewS3 creates the S3 storage service
+func NewS3(config Config) (*S3Impl, error) {
+	if config.Bucket == "" || config.Region == "" {
+		return nil, errors.New("s3 storage requires a bucket and region")
+	}
+	if config.AccessKeyID == "" || config.SecretAccessKey == "" {
+		return nil, errors.New("s3 storage requires an access key id and secret access key")
+	}
+	if config.Endpoint != "" {
+		if _, err := url.Parse(config.Endpoint); err != nil {
+			return nil, fmt.Errorf("s3 storage invalid endpoint: %v", err)
+		}
+	}
+
+	credentials := aws.Credentials{
+		AccessKeyID:     config.AccessKeyID,
+		SecretAccessKey: config.SecretAccessKey,
+		Source:          "StorageConfig",
+	}
+	client := s3.New(s3.Options{
+		Region: config.Region,
+		Credentials: aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+			return credentials, nil
+		}),
+	}, func(o *s3.Options) {
+		// custom endpoints such as minio are addressed path style
+		if config.Endpoint != "" {
+			o.BaseEndpoint = aws.String(config.Endpoint)
+			o.UsePathStyle = true
+		}
+	})
+
+	return &S3Impl{
+		config: config,
+		client: client,
+		presigner: s3.NewPresignClient(client, func(o *s3.PresignOptions) {
+			o.Expires = config.URLExpiry
+		}),
+	}, nil
+}
+
+// PresignUpload gets a pre-signed POST policy for the object that S3 enforces the content type
+// and a content length of at most the declared size for
+func (s *S3Impl) PresignUpload(ctx context.Context, storyID string, filename string, contentType string, size int64) (*PresignedUpload, error) {
+	key, err := objectKey(storyID, filename)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := s.presigner.PresignPostObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(s.config.Bucket),
+		Key:    aws.String(key),
+	}, func(o *s3.PresignPostOptions) {
+		o.Expires = s.config.URLExpiry
+		o.Conditions = []any{
+			[]any{"content-length-range", 1, size},
+			map[string]string{"Content-Type": contentType},
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("s3 presign upload error: %v", err)
+	}
+
+	fields := make(map[string]string, len(req.Values)+1)
+	for k, v := range req.Values {
+		fields[k] = v
+	}
+	fields["Content-Type"] = contentType
+
+	return &PresignedUpload{URL: req.URL, Fields: fields, Key: key}, nil
+}
+
+// StatObject gets the uploaded object's size and content type
+func (s *S3Impl) StatObject(ctx context.Context, key string) (*ObjectInfo, error) {
+	out, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s.config.Bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		var notFound *types.NotFound
+		if errors.As(err, &notFound) {
+			return nil, ErrObjectNotFound
+		}
+		return nil, fmt.Errorf("s3 head object error: %v", err)
+	}
+
+	return &ObjectInfo{
+		Size:        aws.ToInt64(out.ContentLength),
+		ContentType: aws.ToString(out.ContentType),
+	}, nil
+}
+
+// PresignDownload gets a pre-signed GET URL for the object
+func (s *S3Impl) PresignDownload(ctx context.Context, key string) (string, error) {
+	req, err := s.presigner.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.config.Bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return "", fmt.Errorf("s3 presign download error: %v", err)
+	}
+
+	return req.URL, nil
+}
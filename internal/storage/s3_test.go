@@ -0,0 +1,129 @@
+package storage
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestS3(t *testing.T, endpoint string) *S3Impl {
+	s, err := NewS3(Config{
+		Bucket:          "attachments",
+		Region:          "us-west-2",
+		Endpoint:        endpoint,
+		AccessKeyID:     "access",
+		SecretAccessKey: "secret",
+		URLExpiry:       time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return s
+}
+
+// TestS3PresignUpload makes sure the POST policy restricts the key, content type and size
+func TestS3PresignUpload(t *testing.T) {
+	tests := []struct {
+		name        string
+		endpoint    string
+		expectedURL string
+	}{
+		{name: "aws", endpoint: "", expectedURL: "https://attachments.s3.us-west-2.amazonaws.com"},
+		{name: "custom endpoint", endpoint: "http://minio:9000", expectedURL: "http://minio:9000/attachments"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestS3(t, tt.endpoint)
+
+			upload, err := s.PresignUpload(context.Background(), "story-id", "../my screenshot.png", "image/png", 1024)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if strings.TrimSuffix(upload.URL, "/") != tt.expectedURL {
+				t.Errorf("expected upload url %s, got %s", tt.expectedURL, upload.URL)
+			}
+			if !strings.HasPrefix(upload.Key, "stories/story-id/") || !strings.HasSuffix(upload.Key, "-my_screenshot.png") {
+				t.Errorf("unexpected key %s", upload.Key)
+			}
+			if upload.Fields["key"] != upload.Key || upload.Fields["Content-Type"] != "image/png" ||
+				upload.Fields["X-Amz-Signature"] == "" {
+				t.Errorf("unexpected upload fields %v", upload.Fields)
+			}
+
+			policyJSON, err := base64.StdEncoding.DecodeString(upload.Fields["policy"])
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var policy struct {
+				Conditions []json.RawMessage `json:"conditions"`
+			}
+			if err := json.Unmarshal(policyJSON, &policy); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			conditions := make([]string, 0, len(policy.Conditions))
+			for _, c := range policy.Conditions {
+				conditions = append(conditions, string(c))
+			}
+			for _, expected := range []string{
+				`["content-length-range",1,1024]`,
+				`{"Content-Type":"image/png"}`,
+				`{"key":"` + upload.Key + `"}`,
+			} {
+				if !strings.Contains(strings.Join(conditions, ","), expected) {
+					t.Errorf("expected policy condition %s, got %v", expected, conditions)
+				}
+			}
+		})
+	}
+}
+
+// TestS3PresignDownload makes sure download URLs are signed for the object
+func TestS3PresignDownload(t *testing.T) {
+	s := newTestS3(t, "http://minio:9000")
+
+	downloadURL, err := s.PresignDownload(context.Background(), "stories/story-id/abc-mockup.png")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.HasPrefix(downloadURL, "http://minio:9000/attachments/stories/story-id/abc-mockup.png?") ||
+		!strings.Contains(downloadURL, "X-Amz-Signature=") || !strings.Contains(downloadURL, "X-Amz-Expires=60") {
+		t.Errorf("unexpected download url %s", downloadURL)
+	}
+}
+
+// TestS3StatObject makes sure the uploaded object's details come from a HEAD request
+func TestS3StatObject(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead || r.URL.Path != "/attachments/stories/story-id/abc-mockup.png" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "image/png")
+		w.Header().Set("Content-Length", "1024")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	s := newTestS3(t, server.URL)
+
+	info, err := s.StatObject(context.Background(), "stories/story-id/abc-mockup.png")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.Size != 1024 || info.ContentType != "image/png" {
+		t.Errorf("unexpected object info %+v", info)
+	}
+
+	if _, err := s.StatObject(context.Background(), "stories/story-id/missing.png"); !errors.Is(err, ErrObjectNotFound) {
+		t.Errorf("expected object not found, got %v", err)
+	}
+}
@@ -0,0 +1,109 @@
+// Package storage provides file storage for user uploaded attachments using pre-signed upload URLs
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"time"
+)
+
+// Storage providers
+const (
+	ProviderS3    = "s3"
+	ProviderLocal = "local"
+)
+
+const (
+	defaultURLExpiry  = 15 * time.Minute
+	maxFilenameLength = 100
+)
+
+var unsafeFilenameChars = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
+
+// ErrObjectNotFound is returned when an uploaded object doesn't exist
+var ErrObjectNotFound = errors.New("storage object not found")
+
+// Config holds the configuration for the storage service
+type Config struct {
+	// Storage provider, either s3 or local, attachments are disabled when empty
+	Provider string
+	Bucket   string
+	Region   string
+	// S3 compatible endpoint such as minio, defaults to AWS S3 when empty
+	Endpoint        string
+	AccessKeyID     string
+	SecretAccessKey string
+	// Directory the local provider writes files to
+	LocalPath string
+	// Application path prefix the local provider upload and download routes are served under
+	PathPrefix string
+	// How long pre-signed upload and download URLs are valid for
+	URLExpiry time.Duration
+	// Max size in bytes of an uploaded file
+	MaxUploadSize int64
+}
+
+// PresignedUpload is a pre-signed form upload, the fields are sent as a multipart POST to the URL
+// followed by the file in a field named file
+type PresignedUpload struct {
+	URL    string            `json:"url"`
+	Fields map[string]string `json:"fields"`
+	Key    string            `json:"key"`
+}
+
+// ObjectInfo describes an uploaded object
+type ObjectInfo struct {
+	Size        int64
+	ContentType string
+}
+
+// StorageService generates pre-signed URLs for uploading and downloading story attachments
+type StorageService interface {
+	// PresignUpload gets a form upload for the story's file limited to the content type and size
+	PresignUpload(ctx context.Context, storyID string, filename string, contentType string, size int64) (*PresignedUpload, error)
+	// StatObject gets the uploaded object's details, returning ErrObjectNotFound when it hasn't been uploaded
+	StatObject(ctx context.Context, key string) (*ObjectInfo, error)
+	// PresignDownload gets a URL the object can be downloaded from until it expires
+	PresignDownload(ctx context.Context, key string) (string, error)
+}
+
+// New creates the storage service for the configured provider
+func New(config Config) (StorageService, error) {
+	if config.URLExpiry <= 0 {
+		config.URLExpiry = defaultURLExpiry
+	}
+
+	switch config.Provider {
+	case ProviderS3:
+		return NewS3(config)
+	case ProviderLocal:
+		return NewLocalFS(config)
+	default:
+		return nil, fmt.Errorf("unsupported storage provider: %q", config.Provider)
+	}
+}
+
+// objectKey builds a unique object key for the story's file, keeping a sanitized version of the filename
+func objectKey(storyID string, filename string) (string, error) {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return "", fmt.Errorf("generate object key error: %v", err)
+	}
+
+	name := unsafeFilenameChars.ReplaceAllString(path.Base(filename), "_")
+	if len(name) > maxFilenameLength {
+		name = name[len(name)-maxFilenameLength:]
+	}
+
+	return StoryKeyPrefix(storyID) + hex.EncodeToString(id) + "-" + name, nil
+}
+
+// StoryKeyPrefix gets the prefix of the story's object keys
+func StoryKeyPrefix(storyID string) string {
+	return fmt.Sprintf("stories/%s/", storyID)
+}
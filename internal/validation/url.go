@@ -0,0 +1,31 @@
+// Package validation provides input validation shared across services
+package validation
+
+import (
+	"errors"
+	"net/url"
+)
+
+// ErrInvalidURL is returned when a URL isn't an absolute http or https URL
+var ErrInvalidURL = errors.New("invalid URL")
+
+// ValidateURL checks the URL is an absolute http or https URL with a host,
+// an empty URL is valid as links are optional
+func ValidateURL(rawURL string) error {
+	if rawURL == "" {
+		return nil
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ErrInvalidURL
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return ErrInvalidURL
+	}
+	if u.Host == "" {
+		return ErrInvalidURL
+	}
+
+	return nil
+}
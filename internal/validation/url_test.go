@@ -0,0 +1,36 @@
+package validation
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		rawURL  string
+		wantErr error
+	}{
+		{name: "http", rawURL: "http://example.com/story/1"},
+		{name: "https", rawURL: "https://jira.example.com/browse/TD-42?focus=true"},
+		{name: "https with port", rawURL: "https://localhost:8080"},
+		{name: "empty", rawURL: ""},
+		{name: "uppercase scheme", rawURL: "HTTPS://example.com"},
+		{name: "ftp scheme", rawURL: "ftp://files.example.com/spec.pdf", wantErr: ErrInvalidURL},
+		{name: "javascript scheme", rawURL: "javascript:alert(1)", wantErr: ErrInvalidURL},
+		{name: "no host", rawURL: "https://", wantErr: ErrInvalidURL},
+		{name: "no host with path", rawURL: "http:///story/1", wantErr: ErrInvalidURL},
+		{name: "no scheme", rawURL: "example.com/story/1", wantErr: ErrInvalidURL},
+		{name: "placeholder", rawURL: "TBD", wantErr: ErrInvalidURL},
+		{name: "malformed", rawURL: "https://exa mple.com/%zz", wantErr: ErrInvalidURL},
+		{name: "malformed scheme", rawURL: "://example.com", wantErr: ErrInvalidURL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateURL(tt.rawURL); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ValidateURL(%q) expected error %v, got %v", tt.rawURL, tt.wantErr, err)
+			}
+		})
+	}
+}
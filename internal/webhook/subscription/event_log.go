@@ -0,0 +1,78 @@
+package subscription
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// Billing webhook event types recorded in the webhook event log
+const (
+	WebhookEventUsage = "usage"
+	WebhookEventTest  = "test"
+)
+
+// deliverBillingWebhook posts the signed payload to the billing webhook and records the attempt
+// in the webhook event log whether or not it was delivered, an empty delivery ID starts a new delivery
+func (s *Service) deliverBillingWebhook(ctx context.Context, deliveryID string, eventType string, payload []byte) (int, []byte, error) {
+	if deliveryID == "" {
+		deliveryID = s.newBillingDeliveryID(ctx)
+	}
+	sentAt := time.Now().UTC()
+	statusCode, body, err := s.postBillingWebhook(ctx, deliveryID, payload)
+
+	event := &thunderdome.WebhookEvent{
+		DeliveryID:   deliveryID,
+		EventType:    eventType,
+		Payload:      payload,
+		HTTPStatus:   statusCode,
+		ResponseBody: string(body),
+		SentAt:       sentAt,
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	event.Succeeded = thunderdome.WebhookDeliverySucceeded(event.HTTPStatus, event.Error)
+	s.logWebhookEvent(ctx, event)
+
+	return statusCode, body, err
+}
+
+// logWebhookEvent records the webhook delivery attempt, failing to log never fails the delivery
+func (s *Service) logWebhookEvent(ctx context.Context, event *thunderdome.WebhookEvent) {
+	if s.dataSvc == nil {
+		return
+	}
+
+	if err := s.dataSvc.LogWebhookEvent(ctx, event); err != nil {
+		s.logger.Ctx(ctx).Error("webhook event log error", zap.Error(err),
+			zap.String("webhook_event_type", event.EventType), zap.Int("http_status", event.HTTPStatus))
+	}
+}
+
+// ReplayWebhookEvent re-delivers the payload of a logged webhook event to the billing webhook,
+// the replay is logged as a new event of the same type. The original delivery ID is sent as the
+// idempotency key so a receiver that already processed the delivery doesn't count the usage again
+func (s *Service) ReplayWebhookEvent(ctx context.Context, eventID string) (*WebhookDelivery, error) {
+	if s.config.BillingWebhookURL == "" {
+		return nil, ErrBillingWebhookNotConfigured
+	}
+
+	event, err := s.dataSvc.GetWebhookEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery := &WebhookDelivery{DeliveredAt: time.Now().UTC()}
+	statusCode, body, err := s.deliverBillingWebhook(ctx, event.DeliveryID, event.EventType, event.Payload)
+	delivery.StatusCode = statusCode
+	delivery.ResponseBody = string(body)
+	if err != nil {
+		delivery.Error = err.Error()
+	}
+
+	return delivery, nil
+}
@@ -0,0 +1,150 @@
+package subscription
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// fakeEventLogDataSvc keeps the logged webhook events in memory
+type fakeEventLogDataSvc struct {
+	DataSvc
+	mu     sync.Mutex
+	events []*thunderdome.WebhookEvent
+}
+
+func (f *fakeEventLogDataSvc) LogWebhookEvent(_ context.Context, event *thunderdome.WebhookEvent) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	event.ID = fmt.Sprintf("event-%d", len(f.events)+1)
+	f.events = append(f.events, event)
+	return nil
+}
+
+func (f *fakeEventLogDataSvc) GetWebhookEvent(_ context.Context, eventID string) (*thunderdome.WebhookEvent, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, event := range f.events {
+		if event.ID == eventID {
+			return event, nil
+		}
+	}
+	return nil, fmt.Errorf("WEBHOOK_EVENT_NOT_FOUND")
+}
+
+func (f *fakeEventLogDataSvc) loggedEvents() []*thunderdome.WebhookEvent {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]*thunderdome.WebhookEvent{}, f.events...)
+}
+
+func newEventLogTestService(billingWebhookURL string, dataSvc DataSvc) *Service {
+	return New(Config{
+		AccountSecret:      "sk_test_secret",
+		BillingWebhookURL:  billingWebhookURL,
+		UsageFlushInterval: time.Hour,
+	}, otelzap.New(zap.NewNop()), dataSvc, nil, nil, nil)
+}
+
+func TestBillingWebhookDeliveriesLogged(t *testing.T) {
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"received":true}`))
+	}))
+	t.Cleanup(server.Close)
+	dataSvc := &fakeEventLogDataSvc{}
+	s := newEventLogTestService(server.URL, dataSvc)
+	ctx := context.Background()
+
+	events := []UsageEvent{{OrganizationID: "org-1", EventType: UsageEventGameCreated, Quantity: 1}}
+	require.NoError(t, s.sendUsageEvents(ctx, mustUsageBatch(t, s, events)))
+
+	status = http.StatusInternalServerError
+	assert.Error(t, s.sendUsageEvents(ctx, mustUsageBatch(t, s, events)))
+
+	logged := dataSvc.loggedEvents()
+	require.Len(t, logged, 2)
+
+	assert.Equal(t, WebhookEventUsage, logged[0].EventType)
+	assert.Equal(t, http.StatusOK, logged[0].HTTPStatus)
+	assert.Equal(t, `{"received":true}`, logged[0].ResponseBody)
+	assert.True(t, logged[0].Succeeded)
+	assert.Contains(t, string(logged[0].Payload), `"organizationId":"org-1"`)
+
+	assert.Equal(t, WebhookEventUsage, logged[1].EventType)
+	assert.Equal(t, http.StatusInternalServerError, logged[1].HTTPStatus)
+	assert.False(t, logged[1].Succeeded)
+}
+
+func TestBillingWebhookUnreachableDeliveryLogged(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Close()
+	dataSvc := &fakeEventLogDataSvc{}
+	s := newEventLogTestService(server.URL, dataSvc)
+
+	_, err := s.SendTestWebhook(context.Background())
+	require.NoError(t, err)
+
+	logged := dataSvc.loggedEvents()
+	require.Len(t, logged, 1)
+	assert.Equal(t, WebhookEventTest, logged[0].EventType)
+	assert.Equal(t, 0, logged[0].HTTPStatus)
+	assert.Contains(t, logged[0].Error, "billing webhook send error")
+	assert.False(t, logged[0].Succeeded)
+}
+
+func TestReplayWebhookEvent(t *testing.T) {
+	var bodies []string
+	var signatures []string
+	var idempotencyKeys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		signatures = append(signatures, r.Header.Get(UsageSignatureHeader))
+		idempotencyKeys = append(idempotencyKeys, r.Header.Get(IdempotencyKeyHeader))
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	dataSvc := &fakeEventLogDataSvc{}
+	s := newEventLogTestService(server.URL, dataSvc)
+	ctx := context.Background()
+
+	require.NoError(t, s.sendUsageEvents(ctx, mustUsageBatch(t, s, []UsageEvent{{OrganizationID: "org-1", EventType: UsageEventUserAdded, Quantity: 2}})))
+	require.NoError(t, s.sendUsageEvents(ctx, mustUsageBatch(t, s, []UsageEvent{{OrganizationID: "org-1", EventType: UsageEventUserAdded, Quantity: 1}})))
+
+	delivery, err := s.ReplayWebhookEvent(ctx, "event-1")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, delivery.StatusCode)
+
+	// the replay sends the same signed payload with the original delivery's idempotency key
+	// and is logged as a new event of the same type
+	require.Len(t, bodies, 3)
+	assert.Equal(t, bodies[0], bodies[2])
+	assert.Equal(t, signatures[0], signatures[2])
+	assert.NotEmpty(t, idempotencyKeys[0])
+	assert.NotEqual(t, idempotencyKeys[0], idempotencyKeys[1], "expected new deliveries to get their own idempotency key")
+	assert.Equal(t, idempotencyKeys[0], idempotencyKeys[2])
+	logged := dataSvc.loggedEvents()
+	require.Len(t, logged, 3)
+	assert.Equal(t, WebhookEventUsage, logged[2].EventType)
+	assert.Equal(t, logged[0].DeliveryID, logged[2].DeliveryID)
+
+	_, err = s.ReplayWebhookEvent(ctx, "event-9")
+	assert.EqualError(t, err, "WEBHOOK_EVENT_NOT_FOUND")
+
+	_, err = newEventLogTestService("", dataSvc).ReplayWebhookEvent(ctx, "event-1")
+	assert.ErrorIs(t, err, ErrBillingWebhookNotConfigured)
+}
@@ -0,0 +1,132 @@
+package subscription
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	// IdempotencyKeyHeader is the header set on outgoing webhook requests
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IncomingIdempotencyKeyHeader is the header checked on incoming webhook requests
+	IncomingIdempotencyKeyHeader = "X-Idempotency-Key"
+	// idempotencyKeyTTL is how long sent and processed keys are remembered
+	idempotencyKeyTTL = 24 * time.Hour
+	// billingWebhookID is the webhook ID the billing webhook's sent keys are recorded under
+	billingWebhookID = "billing"
+)
+
+// IdempotencyStore is the subset of the redis client used to track webhook idempotency keys
+type IdempotencyStore interface {
+	SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.BoolCmd
+	SAdd(ctx context.Context, key string, members ...interface{}) *redis.IntCmd
+	Expire(ctx context.Context, key string, expiration time.Duration) *redis.BoolCmd
+	Del(ctx context.Context, keys ...string) *redis.IntCmd
+}
+
+func sentKeysKey(webhookID string) string {
+	return fmt.Sprintf("webhook:sent:%s", webhookID)
+}
+
+func processedKeyKey(endpointID string, idempotencyKey string) string {
+	return fmt.Sprintf("webhook:processed:%s:%s", endpointID, idempotencyKey)
+}
+
+// NewOutgoingIdempotencyKey generates an idempotency key for an outgoing webhook delivery
+// and records it in the webhooks sent keys set
+func (s *Service) NewOutgoingIdempotencyKey(ctx context.Context, webhookID string) (string, error) {
+	key := uuid.NewString()
+	if s.idempotencyStore == nil {
+		return key, nil
+	}
+
+	setKey := sentKeysKey(webhookID)
+	if err := s.idempotencyStore.SAdd(ctx, setKey, key).Err(); err != nil {
+		return key, fmt.Errorf("webhook record sent idempotency key error: %v", err)
+	}
+	if err := s.idempotencyStore.Expire(ctx, setKey, idempotencyKeyTTL).Err(); err != nil {
+		return key, fmt.Errorf("webhook set sent idempotency key ttl error: %v", err)
+	}
+
+	return key, nil
+}
+
+// SetOutgoingIdempotencyKey sets the delivery's idempotency key on the outgoing request,
+// retries of a delivery send the same key
+func SetOutgoingIdempotencyKey(req *http.Request, idempotencyKey string) {
+	req.Header.Set(IdempotencyKeyHeader, idempotencyKey)
+}
+
+// newBillingDeliveryID generates the idempotency key of a new billing webhook delivery,
+// failing to record the sent key doesn't fail the delivery
+func (s *Service) newBillingDeliveryID(ctx context.Context) string {
+	key, err := s.NewOutgoingIdempotencyKey(ctx, billingWebhookID)
+	if err != nil {
+		s.logger.Ctx(ctx).Error("billing webhook idempotency key error", zap.Error(err))
+	}
+
+	return key
+}
+
+// MarkIncomingProcessed records the idempotency key as processed for the endpoint,
+// returning false if the key was already processed
+func (s *Service) MarkIncomingProcessed(ctx context.Context, endpointID string, idempotencyKey string) (bool, error) {
+	if s.idempotencyStore == nil || idempotencyKey == "" {
+		return true, nil
+	}
+
+	isNew, err := s.idempotencyStore.SetNX(ctx, processedKeyKey(endpointID, idempotencyKey), 1, idempotencyKeyTTL).Result()
+	if err != nil {
+		return false, fmt.Errorf("webhook check processed idempotency key error: %v", err)
+	}
+
+	return isNew, nil
+}
+
+// statusRecorder captures the response status written by the wrapped handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// idempotent skips processing of incoming webhook requests whose idempotency key was already processed,
+// releasing the key when processing fails so the sender can retry
+func (s *Service) idempotent(endpointID string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+		idempotencyKey := req.Header.Get(IncomingIdempotencyKeyHeader)
+
+		isNew, err := s.MarkIncomingProcessed(ctx, endpointID, idempotencyKey)
+		if err != nil {
+			// fall through to processing, the handlers own duplicate checks still apply
+			s.logger.Ctx(ctx).Error("webhook idempotency check error", zap.Error(err),
+				zap.String("endpointId", endpointID))
+		} else if !isNew {
+			s.logger.Ctx(ctx).Info("webhook already processed, skipping",
+				zap.String("endpointId", endpointID), zap.String("idempotencyKey", idempotencyKey))
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next(rec, req)
+
+		if err == nil && idempotencyKey != "" && s.idempotencyStore != nil && rec.status >= http.StatusBadRequest {
+			if delErr := s.idempotencyStore.Del(ctx, processedKeyKey(endpointID, idempotencyKey)).Err(); delErr != nil {
+				s.logger.Ctx(ctx).Error("webhook release idempotency key error", zap.Error(delErr),
+					zap.String("endpointId", endpointID))
+			}
+		}
+	}
+}
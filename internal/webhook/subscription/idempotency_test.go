@@ -0,0 +1,152 @@
+package subscription
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+type fakeIdempotencyStore struct {
+	mu   sync.Mutex
+	keys map[string]bool
+	sets map[string][]interface{}
+}
+
+func newFakeIdempotencyStore() *fakeIdempotencyStore {
+	return &fakeIdempotencyStore{
+		keys: make(map[string]bool),
+		sets: make(map[string][]interface{}),
+	}
+}
+
+func (f *fakeIdempotencyStore) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.BoolCmd {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.keys[key] {
+		return redis.NewBoolResult(false, nil)
+	}
+	f.keys[key] = true
+	return redis.NewBoolResult(true, nil)
+}
+
+func (f *fakeIdempotencyStore) SAdd(ctx context.Context, key string, members ...interface{}) *redis.IntCmd {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sets[key] = append(f.sets[key], members...)
+	return redis.NewIntResult(int64(len(members)), nil)
+}
+
+func (f *fakeIdempotencyStore) Expire(ctx context.Context, key string, expiration time.Duration) *redis.BoolCmd {
+	return redis.NewBoolResult(true, nil)
+}
+
+func (f *fakeIdempotencyStore) Del(ctx context.Context, keys ...string) *redis.IntCmd {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, k := range keys {
+		delete(f.keys, k)
+	}
+	return redis.NewIntResult(int64(len(keys)), nil)
+}
+
+func newTestService(store IdempotencyStore) *Service {
+	return &Service{
+		logger:           otelzap.New(zap.NewNop()),
+		idempotencyStore: store,
+	}
+}
+
+func deliver(handler http.HandlerFunc, idempotencyKey string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/subscriptions", nil)
+	if idempotencyKey != "" {
+		req.Header.Set(IncomingIdempotencyKeyHeader, idempotencyKey)
+	}
+	rr := httptest.NewRecorder()
+	handler(rr, req)
+	return rr
+}
+
+func TestIdempotentSkipsDuplicateDelivery(t *testing.T) {
+	s := newTestService(newFakeIdempotencyStore())
+	calls := 0
+	handler := s.idempotent("subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusOK)
+	})
+
+	first := deliver(handler, "key-1")
+	second := deliver(handler, "key-1")
+
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, http.StatusOK, second.Code)
+	assert.Equal(t, 1, calls)
+}
+
+func TestIdempotentReleasesKeyOnFailure(t *testing.T) {
+	s := newTestService(newFakeIdempotencyStore())
+	calls := 0
+	handler := s.idempotent("subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	assert.Equal(t, http.StatusInternalServerError, deliver(handler, "key-1").Code)
+	assert.Equal(t, http.StatusOK, deliver(handler, "key-1").Code)
+	assert.Equal(t, http.StatusOK, deliver(handler, "key-1").Code)
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotentWithoutKeyAlwaysProcesses(t *testing.T) {
+	s := newTestService(newFakeIdempotencyStore())
+	calls := 0
+	handler := s.idempotent("subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	})
+
+	deliver(handler, "")
+	deliver(handler, "")
+
+	assert.Equal(t, 2, calls)
+}
+
+func TestNewOutgoingIdempotencyKey(t *testing.T) {
+	store := newFakeIdempotencyStore()
+	s := newTestService(store)
+
+	key, err := s.NewOutgoingIdempotencyKey(context.Background(), "webhook-1")
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, key)
+	assert.Equal(t, []interface{}{key}, store.sets["webhook:sent:webhook-1"])
+}
+
+// TestBillingWebhookRecordsSentIdempotencyKey makes sure billing webhook deliveries send a recorded idempotency key
+func TestBillingWebhookRecordsSentIdempotencyKey(t *testing.T) {
+	var idempotencyKeys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		idempotencyKeys = append(idempotencyKeys, r.Header.Get(IdempotencyKeyHeader))
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	store := newFakeIdempotencyStore()
+	s := New(Config{BillingWebhookURL: server.URL, UsageFlushInterval: time.Hour}, otelzap.New(zap.NewNop()), nil, nil, nil, store)
+
+	_, err := s.SendTestWebhook(context.Background())
+	assert.NoError(t, err)
+
+	assert.Len(t, idempotencyKeys, 1)
+	assert.NotEmpty(t, idempotencyKeys[0])
+	assert.Equal(t, []interface{}{idempotencyKeys[0]}, store.sets["webhook:sent:billing"])
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/stripe/stripe-go/v81/checkout/session"
@@ -25,6 +26,12 @@ import (
 type Config struct {
 	AccountSecret string
 	WebhookSecret string
+	// BillingWebhookURL receives the usage telemetry, usage reporting is disabled when empty
+	BillingWebhookURL string
+	// BillingWebhookEventFilters are the usage event types sent to the billing webhook, see MatchesFilter
+	BillingWebhookEventFilters []string
+	// UsageFlushInterval is how often batched usage events are sent, defaults to one minute
+	UsageFlushInterval time.Duration
 }
 
 // DataSvc is the interface for the subscription data service
@@ -32,6 +39,8 @@ type DataSvc interface {
 	GetSubscriptionBySubscriptionID(ctx context.Context, subscriptionID string) (thunderdome.Subscription, error)
 	CreateSubscription(ctx context.Context, subscription thunderdome.Subscription) (thunderdome.Subscription, error)
 	UpdateSubscription(ctx context.Context, subscriptionID string, subscription thunderdome.Subscription) (thunderdome.Subscription, error)
+	LogWebhookEvent(ctx context.Context, event *thunderdome.WebhookEvent) error
+	GetWebhookEvent(ctx context.Context, eventID string) (*thunderdome.WebhookEvent, error)
 }
 
 // UserDataSvc is the interface for the user data service
@@ -52,6 +61,13 @@ type Service struct {
 	dataSvc     DataSvc
 	emailSvc    EmailService
 	userDataSvc UserDataSvc
+
+	idempotencyStore IdempotencyStore
+	usageEvents      chan UsageEvent
+	httpClient       *http.Client
+
+	testWebhookMu   sync.Mutex
+	lastTestWebhook time.Time
 }
 
 // New creates a new subscription service
@@ -61,22 +77,37 @@ func New(
 	dataSvc DataSvc,
 	emailSvc EmailService,
 	userDataSvc UserDataSvc,
+	idempotencyStore IdempotencyStore,
 ) *Service {
 	// The library needs to be configured with your account's secret key.
 	// Ensure the key is kept out of any version control system you might be using.
 	stripe.Key = config.AccountSecret
-	return &Service{
+	s := &Service{
 		logger:      logger,
 		config:      config,
 		dataSvc:     dataSvc,
 		emailSvc:    emailSvc,
 		userDataSvc: userDataSvc,
+
+		idempotencyStore: idempotencyStore,
+		httpClient:       &http.Client{},
+	}
+
+	if config.BillingWebhookURL != "" {
+		flushInterval := config.UsageFlushInterval
+		if flushInterval <= 0 {
+			flushInterval = defaultUsageFlushInterval
+		}
+		s.usageEvents = make(chan UsageEvent, usageBufferSize)
+		go s.runUsageReporter(flushInterval)
 	}
+
+	return s
 }
 
 // HandleWebhook handles the stripe subscription webhook
 func (s *Service) HandleWebhook() http.HandlerFunc {
-	return func(w http.ResponseWriter, req *http.Request) {
+	return s.idempotent("subscriptions", func(w http.ResponseWriter, req *http.Request) {
 		const MaxBodyBytes = int64(65536)
 		ctx := req.Context()
 		logger := s.logger.Ctx(ctx)
@@ -273,5 +304,5 @@ func (s *Service) HandleWebhook() http.HandlerFunc {
 		logger.Info(fmt.Sprintf("Successfully processed Stripe webhook event type: %s", event.Type), zap.String("eventId", event.ID))
 
 		w.WriteHeader(http.StatusOK)
-	}
+	})
 }
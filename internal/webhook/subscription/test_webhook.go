@@ -0,0 +1,55 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// testWebhookInterval is how often the billing webhook test delivery can be sent
+const testWebhookInterval = time.Minute
+
+// testWebhookPayload is the fixed body of the billing webhook test delivery, its type tells it apart from usage batches
+var testWebhookPayload = []byte(`{"type":"test","events":[]}`)
+
+var (
+	// ErrBillingWebhookNotConfigured is returned when testing the billing webhook without a billing webhook url
+	ErrBillingWebhookNotConfigured = errors.New("BILLING_WEBHOOK_NOT_CONFIGURED")
+	// ErrTestWebhookRateLimited is returned when testing the billing webhook more than once per interval
+	ErrTestWebhookRateLimited = errors.New("TEST_WEBHOOK_RATE_LIMITED")
+)
+
+// WebhookDelivery is the result of delivering a webhook, a failed delivery includes the error detail
+type WebhookDelivery struct {
+	StatusCode   int       `json:"statusCode"`
+	ResponseBody string    `json:"responseBody"`
+	Error        string    `json:"error,omitempty"`
+	DeliveredAt  time.Time `json:"deliveredAt"`
+}
+
+// SendTestWebhook delivers a signed test payload to the billing webhook so the receiving endpoint
+// can be verified without waiting on real usage, deliveries are limited to once a minute
+func (s *Service) SendTestWebhook(ctx context.Context) (*WebhookDelivery, error) {
+	if s.config.BillingWebhookURL == "" {
+		return nil, ErrBillingWebhookNotConfigured
+	}
+
+	s.testWebhookMu.Lock()
+	now := time.Now()
+	if !s.lastTestWebhook.IsZero() && now.Sub(s.lastTestWebhook) < testWebhookInterval {
+		s.testWebhookMu.Unlock()
+		return nil, ErrTestWebhookRateLimited
+	}
+	s.lastTestWebhook = now
+	s.testWebhookMu.Unlock()
+
+	delivery := &WebhookDelivery{DeliveredAt: now.UTC()}
+	statusCode, body, err := s.deliverBillingWebhook(ctx, "", WebhookEventTest, testWebhookPayload)
+	delivery.StatusCode = statusCode
+	delivery.ResponseBody = string(body)
+	if err != nil {
+		delivery.Error = err.Error()
+	}
+
+	return delivery, nil
+}
@@ -0,0 +1,84 @@
+package subscription
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSendTestWebhook(t *testing.T) {
+	var body []byte
+	var signature string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		signature = r.Header.Get(UsageSignatureHeader)
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(`{"received":true}`))
+	}))
+	t.Cleanup(server.Close)
+	s := newUsageTestService(server.URL, time.Hour)
+
+	delivery, err := s.SendTestWebhook(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusAccepted, delivery.StatusCode)
+	assert.Equal(t, `{"received":true}`, delivery.ResponseBody)
+	assert.Empty(t, delivery.Error)
+	assert.JSONEq(t, `{"type":"test","events":[]}`, string(body))
+	assert.Equal(t, SignUsagePayload(body, "sk_test_secret"), signature)
+}
+
+func TestSendTestWebhookFailedDelivery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+	}))
+	t.Cleanup(server.Close)
+	s := newUsageTestService(server.URL, time.Hour)
+
+	delivery, err := s.SendTestWebhook(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, delivery.StatusCode)
+	assert.Equal(t, "invalid signature\n", delivery.ResponseBody)
+
+	// an unreachable endpoint returns the error detail instead of a response
+	server.Close()
+	unreachable := newUsageTestService(server.URL, time.Hour)
+	delivery, err = unreachable.SendTestWebhook(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, delivery.StatusCode)
+	assert.Contains(t, delivery.Error, "billing webhook send error")
+}
+
+func TestSendTestWebhookRateLimited(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	s := newUsageTestService(server.URL, time.Hour)
+
+	_, err := s.SendTestWebhook(context.Background())
+	require.NoError(t, err)
+	_, err = s.SendTestWebhook(context.Background())
+	assert.ErrorIs(t, err, ErrTestWebhookRateLimited)
+	assert.Equal(t, 1, requests)
+
+	s.lastTestWebhook = time.Now().Add(-testWebhookInterval)
+	_, err = s.SendTestWebhook(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, requests)
+}
+
+func TestSendTestWebhookNotConfigured(t *testing.T) {
+	s := newUsageTestService("", time.Hour)
+
+	_, err := s.SendTestWebhook(context.Background())
+	assert.ErrorIs(t, err, ErrBillingWebhookNotConfigured)
+}
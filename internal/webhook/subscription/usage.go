@@ -0,0 +1,219 @@
+package subscription
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Usage event types reported to the billing webhook
+const (
+	UsageEventGameCreated  = "game_created"
+	UsageEventUserAdded    = "user_added"
+	UsageEventRetroCreated = "retro_created"
+)
+
+// UsageSignatureHeader is the billing webhook request header holding the HMAC-SHA256 payload signature
+const UsageSignatureHeader = "X-Thunderdome-Signature"
+
+const (
+	usageBufferSize            = 1000
+	defaultUsageFlushInterval  = time.Minute
+	usageWebhookRequestTimeout = 10 * time.Second
+	maxWebhookResponseBytes    = 64 * 1024
+)
+
+// UsageEvent is a single usage telemetry event
+type UsageEvent struct {
+	OrganizationID string    `json:"organizationId"`
+	EventType      string    `json:"eventType"`
+	Quantity       int       `json:"quantity"`
+	OccurredAt     time.Time `json:"occurredAt"`
+}
+
+// UsagePayload is the batch of usage events posted to the billing webhook
+type UsagePayload struct {
+	Events []UsageEvent `json:"events"`
+	SentAt time.Time    `json:"sentAt"`
+}
+
+// RecordUsageEvent queues a usage event for delivery to the billing webhook,
+// events are batched and sent at most once per flush interval
+func (s *Service) RecordUsageEvent(ctx context.Context, orgID string, eventType string, quantity int) error {
+	if s.usageEvents == nil {
+		return nil
+	}
+
+	switch eventType {
+	case UsageEventGameCreated, UsageEventUserAdded, UsageEventRetroCreated:
+	default:
+		return fmt.Errorf("INVALID_USAGE_EVENT_TYPE")
+	}
+	if quantity < 1 {
+		return fmt.Errorf("INVALID_USAGE_EVENT_QUANTITY")
+	}
+	if !MatchesFilter(eventType, s.config.BillingWebhookEventFilters) {
+		return nil
+	}
+
+	event := UsageEvent{
+		OrganizationID: orgID,
+		EventType:      eventType,
+		Quantity:       quantity,
+		OccurredAt:     time.Now().UTC(),
+	}
+
+	select {
+	case s.usageEvents <- event:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+		return errors.New("USAGE_EVENT_BUFFER_FULL")
+	}
+}
+
+// MatchesFilter reports whether the event type is selected by the event filters, an empty filter list
+// matches every event type and a filter ending in * matches every event type starting with the rest
+// of the filter, e.g. game_* matches game_created
+func MatchesFilter(eventType string, filters []string) bool {
+	if len(filters) == 0 {
+		return true
+	}
+
+	for _, filter := range filters {
+		if prefix, ok := strings.CutSuffix(filter, "*"); ok {
+			if strings.HasPrefix(eventType, prefix) {
+				return true
+			}
+			continue
+		}
+		if filter == eventType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// usageBatch is a batch of usage events frozen for delivery, retries resend the same payload
+// with the same delivery ID so the receiver can recognize them by the idempotency key
+type usageBatch struct {
+	deliveryID string
+	payload    []byte
+	eventCount int
+}
+
+// newUsageBatch freezes the usage events into a batch for delivery
+func (s *Service) newUsageBatch(ctx context.Context, events []UsageEvent) (*usageBatch, error) {
+	payload, err := json.Marshal(UsagePayload{
+		Events: events,
+		SentAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("billing webhook payload marshal error: %v", err)
+	}
+
+	return &usageBatch{deliveryID: s.newBillingDeliveryID(ctx), payload: payload, eventCount: len(events)}, nil
+}
+
+// runUsageReporter batches queued usage events and sends them to the billing webhook every flush interval,
+// a batch that fails to send is retried as is while new events are collected into the next batch
+func (s *Service) runUsageReporter(flushInterval time.Duration) {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	var pending *usageBatch
+	next := make([]UsageEvent, 0)
+	for {
+		select {
+		case event := <-s.usageEvents:
+			// keep the newest events while a failed batch is retried without growing past the buffer size
+			if len(next) == usageBufferSize {
+				next = next[1:]
+			}
+			next = append(next, event)
+		case <-ticker.C:
+			if pending == nil {
+				if len(next) == 0 {
+					continue
+				}
+				batch, err := s.newUsageBatch(context.Background(), next)
+				if err != nil {
+					s.logger.Error("billing webhook usage batch error", zap.Error(err),
+						zap.Int("event_count", len(next)))
+					continue
+				}
+				pending = batch
+				next = make([]UsageEvent, 0)
+			}
+			if err := s.sendUsageEvents(context.Background(), pending); err != nil {
+				s.logger.Error("billing webhook usage delivery error", zap.Error(err),
+					zap.String("delivery_id", pending.deliveryID), zap.Int("event_count", pending.eventCount))
+				continue
+			}
+			pending = nil
+		}
+	}
+}
+
+// sendUsageEvents posts the signed usage events batch to the billing webhook
+func (s *Service) sendUsageEvents(ctx context.Context, batch *usageBatch) error {
+	statusCode, _, err := s.deliverBillingWebhook(ctx, batch.deliveryID, WebhookEventUsage, batch.payload)
+	if err != nil {
+		return err
+	}
+
+	if statusCode < 200 || statusCode > 299 {
+		return fmt.Errorf("billing webhook unexpected status: %d", statusCode)
+	}
+
+	return nil
+}
+
+// postBillingWebhook posts the signed payload to the billing webhook returning its response status and body,
+// the delivery ID is sent as the idempotency key
+func (s *Service) postBillingWebhook(ctx context.Context, deliveryID string, payload []byte) (int, []byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, usageWebhookRequestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.config.BillingWebhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return 0, nil, fmt.Errorf("billing webhook request error: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(UsageSignatureHeader, SignUsagePayload(payload, s.config.AccountSecret))
+	SetOutgoingIdempotencyKey(req, deliveryID)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, nil, fmt.Errorf("billing webhook send error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxWebhookResponseBytes))
+	if err != nil {
+		return resp.StatusCode, nil, fmt.Errorf("billing webhook read response error: %v", err)
+	}
+
+	return resp.StatusCode, body, nil
+}
+
+// SignUsagePayload returns the hex encoded HMAC-SHA256 signature of the payload
+func SignUsagePayload(payload []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
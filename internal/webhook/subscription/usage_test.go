@@ -0,0 +1,197 @@
+package subscription
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+type billingRequest struct {
+	payload   UsagePayload
+	body      []byte
+	signature string
+}
+
+func newBillingEndpoint(t *testing.T) (*httptest.Server, chan billingRequest) {
+	requests := make(chan billingRequest, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		var payload UsagePayload
+		require.NoError(t, json.Unmarshal(body, &payload))
+		requests <- billingRequest{payload: payload, body: body, signature: r.Header.Get(UsageSignatureHeader)}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, requests
+}
+
+func newUsageTestService(billingWebhookURL string, flushInterval time.Duration) *Service {
+	return New(Config{
+		AccountSecret:      "sk_test_secret",
+		BillingWebhookURL:  billingWebhookURL,
+		UsageFlushInterval: flushInterval,
+	}, otelzap.New(zap.NewNop()), nil, nil, nil, nil)
+}
+
+// mustUsageBatch freezes the usage events into a batch for delivery
+func mustUsageBatch(t *testing.T, s *Service, events []UsageEvent) *usageBatch {
+	t.Helper()
+	batch, err := s.newUsageBatch(context.Background(), events)
+	require.NoError(t, err)
+
+	return batch
+}
+
+func TestRecordUsageEventBatchDelivery(t *testing.T) {
+	server, requests := newBillingEndpoint(t)
+	flushInterval := 100 * time.Millisecond
+	s := newUsageTestService(server.URL, flushInterval)
+	ctx := context.Background()
+
+	require.NoError(t, s.RecordUsageEvent(ctx, "org-1", UsageEventGameCreated, 1))
+	require.NoError(t, s.RecordUsageEvent(ctx, "org-1", UsageEventUserAdded, 3))
+	require.NoError(t, s.RecordUsageEvent(ctx, "org-2", UsageEventRetroCreated, 1))
+
+	select {
+	case req := <-requests:
+		require.Len(t, req.payload.Events, 3)
+		assert.Equal(t, UsageEventGameCreated, req.payload.Events[0].EventType)
+		assert.Equal(t, "org-1", req.payload.Events[0].OrganizationID)
+		assert.Equal(t, UsageEventUserAdded, req.payload.Events[1].EventType)
+		assert.Equal(t, 3, req.payload.Events[1].Quantity)
+		assert.Equal(t, UsageEventRetroCreated, req.payload.Events[2].EventType)
+		assert.Equal(t, "org-2", req.payload.Events[2].OrganizationID)
+		assert.Equal(t, SignUsagePayload(req.body, "sk_test_secret"), req.signature)
+	case <-time.After(5 * flushInterval):
+		t.Fatal("expected usage events batch to be delivered within the flush interval")
+	}
+
+	select {
+	case req := <-requests:
+		t.Fatalf("expected a single batch, got another with %d events", len(req.payload.Events))
+	case <-time.After(3 * flushInterval):
+	}
+}
+
+// TestRecordUsageEventRetriesFrozenBatch makes sure a failed batch is retried with the same payload and
+// idempotency key while events recorded in the meantime are sent in the next batch
+func TestRecordUsageEventRetriesFrozenBatch(t *testing.T) {
+	type delivery struct {
+		body           string
+		idempotencyKey string
+		events         int
+	}
+	deliveries := make(chan delivery, 10)
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		var payload UsagePayload
+		require.NoError(t, json.Unmarshal(body, &payload))
+		deliveries <- delivery{body: string(body), idempotencyKey: r.Header.Get(IdempotencyKeyHeader), events: len(payload.Events)}
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	flushInterval := 100 * time.Millisecond
+	s := newUsageTestService(server.URL, flushInterval)
+	ctx := context.Background()
+
+	require.NoError(t, s.RecordUsageEvent(ctx, "org-1", UsageEventGameCreated, 1))
+	next := func() delivery {
+		select {
+		case d := <-deliveries:
+			return d
+		case <-time.After(5 * flushInterval):
+			t.Fatal("expected a usage events delivery within the flush interval")
+		}
+		return delivery{}
+	}
+
+	failed := next()
+	require.NoError(t, s.RecordUsageEvent(ctx, "org-1", UsageEventUserAdded, 1))
+	retried := next()
+	assert.Equal(t, failed.body, retried.body)
+	assert.Equal(t, failed.idempotencyKey, retried.idempotencyKey)
+	assert.NotEmpty(t, retried.idempotencyKey)
+
+	following := next()
+	assert.Equal(t, 1, following.events)
+	assert.Contains(t, following.body, UsageEventUserAdded)
+	assert.NotEqual(t, failed.idempotencyKey, following.idempotencyKey)
+}
+
+func TestRecordUsageEventInvalid(t *testing.T) {
+	s := newUsageTestService("http://billing.invalid", time.Hour)
+	ctx := context.Background()
+
+	assert.Error(t, s.RecordUsageEvent(ctx, "org-1", "story_created", 1))
+	assert.Error(t, s.RecordUsageEvent(ctx, "org-1", UsageEventGameCreated, 0))
+}
+
+func TestRecordUsageEventDisabled(t *testing.T) {
+	s := newUsageTestService("", 0)
+
+	assert.NoError(t, s.RecordUsageEvent(context.Background(), "org-1", UsageEventGameCreated, 1))
+	assert.Nil(t, s.usageEvents)
+}
+
+func TestRecordUsageEventBufferFull(t *testing.T) {
+	s := &Service{usageEvents: make(chan UsageEvent, 1)}
+	ctx := context.Background()
+
+	assert.NoError(t, s.RecordUsageEvent(ctx, "org-1", UsageEventGameCreated, 1))
+	assert.EqualError(t, s.RecordUsageEvent(ctx, "org-1", UsageEventGameCreated, 1), "USAGE_EVENT_BUFFER_FULL")
+}
+
+func TestRecordUsageEventFiltered(t *testing.T) {
+	s := &Service{
+		config:      Config{BillingWebhookEventFilters: []string{UsageEventRetroCreated}},
+		usageEvents: make(chan UsageEvent, 2),
+	}
+	ctx := context.Background()
+
+	require.NoError(t, s.RecordUsageEvent(ctx, "org-1", UsageEventGameCreated, 1))
+	require.NoError(t, s.RecordUsageEvent(ctx, "org-1", UsageEventRetroCreated, 1))
+
+	require.Len(t, s.usageEvents, 1)
+	assert.Equal(t, UsageEventRetroCreated, (<-s.usageEvents).EventType)
+}
+
+func TestMatchesFilter(t *testing.T) {
+	tests := []struct {
+		name      string
+		eventType string
+		filters   []string
+		want      bool
+	}{
+		{name: "exact match", eventType: "game_created", filters: []string{"retro_created", "game_created"}, want: true},
+		{name: "wildcard match", eventType: "game_created", filters: []string{"game_*"}, want: true},
+		{name: "dotted wildcard match", eventType: "game.created", filters: []string{"game.*"}, want: true},
+		{name: "match all wildcard", eventType: "user_added", filters: []string{"*"}, want: true},
+		{name: "empty filters match all", eventType: "user_added", want: true},
+		{name: "no matching filter", eventType: "user_added", filters: []string{"game_*", "retro_created"}, want: false},
+		{name: "exact filter is not a prefix", eventType: "game_created", filters: []string{"game"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, MatchesFilter(tt.eventType, tt.filters))
+		})
+	}
+}
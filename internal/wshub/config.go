@@ -14,6 +14,14 @@ type Config struct {
 	AppDomain string
 	// Websocket Subdomain (for Websocket origin check)
 	WebsocketSubdomain string
+	// Time allowed for connections to be closed cleanly when the hub is shut down.
+	ShutdownGracePeriodSec int
+	// Websocket read buffer size in bytes.
+	ReadBufferSize int
+	// Websocket write buffer size in bytes.
+	WriteBufferSize int
+	// Store for messages to replay to clients reconnecting after a shutdown, optional.
+	ReplayStore ReplayStore
 }
 
 // WriteWait returns the write wait duration.
@@ -42,3 +50,31 @@ func (c *Config) PongWait() time.Duration {
 	}
 	return time.Duration(waitSec) * time.Second
 }
+
+// ShutdownGracePeriod returns the shutdown grace period duration.
+func (c *Config) ShutdownGracePeriod() time.Duration {
+	periodSec := c.ShutdownGracePeriodSec
+	if periodSec <= 0 {
+		periodSec = 30
+	}
+	return time.Duration(periodSec) * time.Second
+}
+
+// defaultBufferSize is the websocket buffer size used when one isn't configured
+const defaultBufferSize = 4096
+
+// ReadBuffer returns the websocket read buffer size in bytes.
+func (c *Config) ReadBuffer() int {
+	if c.ReadBufferSize <= 0 {
+		return defaultBufferSize
+	}
+	return c.ReadBufferSize
+}
+
+// WriteBuffer returns the websocket write buffer size in bytes.
+func (c *Config) WriteBuffer() int {
+	if c.WriteBufferSize <= 0 {
+		return defaultBufferSize
+	}
+	return c.WriteBufferSize
+}
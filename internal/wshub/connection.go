@@ -1,6 +1,7 @@
 package wshub
 
 import (
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -11,7 +12,12 @@ type Connection struct {
 	// The websocket connection.
 	Ws *websocket.Conn
 	// Buffered channel of outbound messages.
-	send       chan []byte
+	send chan []byte
+	// Closed once the write pump has stopped.
+	done chan struct{}
+	// Unix nano times the last message and pong were received from the client.
+	lastRead   *atomic.Int64
+	lastPong   *atomic.Int64
 	WriteWait  time.Duration
 	PingPeriod time.Duration
 	PongWait   time.Duration
@@ -23,6 +29,20 @@ func (c *Connection) Send() chan<- []byte { return c.send }
 // Close closes the websocket client connection.
 func (c *Connection) Close() { c.Ws.Close() }
 
+// markRead records that a message was received from the client.
+func (c *Connection) markRead() {
+	if c.lastRead != nil {
+		c.lastRead.Store(time.Now().UnixNano())
+	}
+}
+
+// markPong records that a pong was received from the client.
+func (c *Connection) markPong() {
+	if c.lastPong != nil {
+		c.lastPong.Store(time.Now().UnixNano())
+	}
+}
+
 // Write a message with the given message type and payload.
 func (c *Connection) Write(mt int, payload []byte) error {
 	_ = c.Ws.SetWriteDeadline(time.Now().Add(c.WriteWait))
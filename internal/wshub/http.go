@@ -2,6 +2,7 @@ package wshub
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 
 	"github.com/gorilla/mux"
@@ -11,10 +12,11 @@ import (
 )
 
 // CreateWebsocketUpgrader creates a websocket.Upgrader with the given AppDomain and WebsocketSubdomain
+// and the configured buffer sizes
 func (h *Hub) CreateWebsocketUpgrader() websocket.Upgrader {
 	return websocket.Upgrader{
-		ReadBufferSize:  1024,
-		WriteBufferSize: 1024,
+		ReadBufferSize:  h.config.ReadBuffer(),
+		WriteBufferSize: h.config.WriteBuffer(),
 		CheckOrigin: func(r *http.Request) bool {
 			return checkOrigin(r, h.config.AppDomain, h.config.WebsocketSubdomain)
 		},
@@ -53,6 +55,12 @@ func (h *Hub) WebSocketHandler(
 		vars := mux.Vars(r)
 		RoomID := vars[roomIDVar]
 
+		if h.shuttingDown.Load() {
+			w.Header().Set("Retry-After", fmt.Sprintf("%d", int(h.config.ShutdownGracePeriod().Seconds())))
+			http.Error(w, "server shutting down", http.StatusServiceUnavailable)
+			return
+		}
+
 		// upgrade to WebSocket connection
 		var upgrader = h.CreateWebsocketUpgrader()
 		ws, err := upgrader.Upgrade(w, r, nil)
@@ -90,7 +98,7 @@ func (h *Hub) ProcessAPIEventHandler(ctx context.Context, userID, roomID, eventT
 			return eventErr
 		}
 
-		if h.RoomExists(roomID) {
+		if msg != nil && h.RoomExists(roomID) {
 			h.Broadcast(Message{Data: msg, Room: roomID})
 		}
 	}
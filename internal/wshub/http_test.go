@@ -2,6 +2,8 @@ package wshub
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -105,3 +107,97 @@ func TestWebSocketHandler(t *testing.T) {
 	_, _, err := websocket.DefaultDialer.Dial(url, nil)
 	assert.NoError(t, err)
 }
+
+// gameStateJSON builds a poker game state JSON blob with the given number of stories
+func gameStateJSON(b *testing.B, stories int) []byte {
+	b.Helper()
+	type vote struct {
+		WarriorID string `json:"warriorId"`
+		Vote      string `json:"vote"`
+	}
+	type story struct {
+		ID          string `json:"id"`
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		Votes       []vote `json:"votes"`
+		Points      string `json:"points"`
+	}
+	game := struct {
+		ID      string  `json:"id"`
+		Name    string  `json:"name"`
+		Stories []story `json:"plans"`
+	}{ID: "game", Name: "Sprint planning"}
+	for i := 0; i < stories; i++ {
+		s := story{
+			ID:          fmt.Sprintf("story-%d", i),
+			Name:        fmt.Sprintf("As a user I want feature %d so that I get value", i),
+			Description: strings.Repeat("acceptance criteria ", 10),
+			Points:      "5",
+		}
+		for v := 0; v < 8; v++ {
+			s.Votes = append(s.Votes, vote{WarriorID: fmt.Sprintf("warrior-%d", v), Vote: "5"})
+		}
+		game.Stories = append(game.Stories, s)
+	}
+
+	message, err := json.Marshal(game)
+	if err != nil {
+		b.Fatal(err)
+	}
+	return message
+}
+
+// BenchmarkWriteGameState measures sending a 200 story game state (~120KB) from the server to a client
+// with the default and a large write buffer. The 64KB buffer sends the message in 2 frames instead of 30,
+// but over loopback that didn't make it faster, results from a local run:
+//
+//	BenchmarkWriteGameState/write_buffer_4096     2000     95063 ns/op    1287.86 MB/s
+//	BenchmarkWriteGameState/write_buffer_4096     2000    111061 ns/op    1102.35 MB/s
+//	BenchmarkWriteGameState/write_buffer_4096     2000     96388 ns/op    1270.15 MB/s
+//	BenchmarkWriteGameState/write_buffer_65536    2000    108578 ns/op    1127.56 MB/s
+//	BenchmarkWriteGameState/write_buffer_65536    2000    117465 ns/op    1042.25 MB/s
+//	BenchmarkWriteGameState/write_buffer_65536    2000    136040 ns/op     899.94 MB/s
+func BenchmarkWriteGameState(b *testing.B) {
+	message := gameStateJSON(b, 200)
+
+	for _, bufferSize := range []int{4096, 65536} {
+		b.Run(fmt.Sprintf("write_buffer_%d", bufferSize), func(b *testing.B) {
+			hub := NewHub(otelzap.New(zap.NewNop()), Config{WriteBufferSize: bufferSize}, nil, nil, nil, nil)
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				upgrader := hub.CreateWebsocketUpgrader()
+				upgrader.CheckOrigin = func(r *http.Request) bool { return true }
+				conn, err := upgrader.Upgrade(w, r, nil)
+				if err != nil {
+					b.Errorf("Failed to upgrade connection: %v", err)
+					return
+				}
+				defer conn.Close()
+
+				for i := 0; i < b.N; i++ {
+					if err := conn.WriteMessage(websocket.TextMessage, message); err != nil {
+						b.Errorf("Failed to write game state: %v", err)
+						return
+					}
+				}
+			}))
+			defer server.Close()
+
+			b.SetBytes(int64(len(message)))
+			b.ResetTimer()
+
+			url := "ws" + strings.TrimPrefix(server.URL, "http")
+			conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+			if err != nil {
+				b.Fatalf("Failed to connect to WebSocket server: %v", err)
+			}
+			defer conn.Close()
+
+			for i := 0; i < b.N; i++ {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -2,6 +2,9 @@ package wshub
 
 import (
 	"context"
+	"slices"
+	"sync/atomic"
+	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/uptrace/opentelemetry-go-extra/otelzap"
@@ -16,6 +19,11 @@ const (
 type Message struct {
 	Data []byte `json:"data"`
 	Room string `json:"room"`
+	// UserData optionally builds the message per user instead of sending Data to everyone in the room,
+	// users it builds no message for are skipped
+	UserData func(userID string) []byte `json:"-"`
+	// UserID optionally limits the message to the user's connections, in every room when Room is empty
+	UserID string `json:"-"`
 }
 
 type roomExistsRequest struct {
@@ -25,11 +33,15 @@ type roomExistsRequest struct {
 
 // Hub maintains the set of active connections and broadcasts messages to the connections.
 type Hub struct {
-	rooms                     map[string]map[Connection]struct{}
+	rooms                     map[string]map[Connection]string
 	broadcast                 chan Message
 	register                  chan Subscription
 	unregister                chan Subscription
 	roomExists                chan roomExistsRequest
+	shutdown                  chan shutdownRequest
+	subscriptions             chan chan []Subscription
+	idlePongTimeout           time.Duration
+	shuttingDown              atomic.Bool
 	logger                    *otelzap.Logger
 	config                    *Config
 	eventHandlers             map[string]func(context.Context, string, string, string) ([]byte, error, bool)
@@ -51,8 +63,11 @@ func NewHub(
 		broadcast:                 make(chan Message),
 		register:                  make(chan Subscription),
 		unregister:                make(chan Subscription),
-		rooms:                     make(map[string]map[Connection]struct{}),
+		rooms:                     make(map[string]map[Connection]string),
 		roomExists:                make(chan roomExistsRequest),
+		shutdown:                  make(chan shutdownRequest),
+		subscriptions:             make(chan chan []Subscription),
+		idlePongTimeout:           idlePongTimeout,
 		logger:                    logger,
 		config:                    &config,
 		eventHandlers:             eventHandlers,
@@ -67,10 +82,15 @@ func (h *Hub) Run() {
 	for {
 		select {
 		case sub := <-h.register:
+			// connections that slipped in after shutdown started are closed right away
+			if h.shuttingDown.Load() {
+				close(sub.Conn.send)
+				continue
+			}
 			if _, ok := h.rooms[sub.RoomID]; !ok {
-				h.rooms[sub.RoomID] = make(map[Connection]struct{})
+				h.rooms[sub.RoomID] = make(map[Connection]string)
 			}
-			h.rooms[sub.RoomID][sub.Conn] = struct{}{}
+			h.rooms[sub.RoomID][sub.Conn] = sub.UserID
 
 		case sub := <-h.unregister:
 			if _, ok := h.rooms[sub.RoomID]; ok {
@@ -84,27 +104,62 @@ func (h *Hub) Run() {
 			}
 
 		case m := <-h.broadcast:
-			if connections, ok := h.rooms[m.Room]; ok {
-				for conn := range connections {
-					select {
-					case conn.Send() <- m.Data:
-					default:
-						close(conn.Send())
-						delete(connections, conn)
-						if len(connections) == 0 {
-							delete(h.rooms, m.Room)
-						}
-					}
+			if m.Room == "" && m.UserID != "" {
+				for roomID := range h.rooms {
+					h.broadcastToRoom(roomID, m)
 				}
+			} else {
+				h.broadcastToRoom(m.Room, m)
 			}
 
 		case req := <-h.roomExists:
 			_, exists := h.rooms[req.room]
 			req.response <- exists
+
+		case req := <-h.shutdown:
+			req.response <- h.closeRooms(req.event)
+
+		case response := <-h.subscriptions:
+			response <- h.currentSubscriptions()
+		}
+	}
+}
+
+// broadcastToRoom sends the message to the room's connections, closing any connection that can't keep up
+func (h *Hub) broadcastToRoom(roomID string, m Message) {
+	connections, ok := h.rooms[roomID]
+	if !ok {
+		return
+	}
+
+	for conn, userID := range connections {
+		if m.UserID != "" && userID != m.UserID {
+			continue
+		}
+		data := m.Data
+		if m.UserData != nil {
+			data = m.UserData(userID)
+			if data == nil {
+				continue
+			}
+		}
+		select {
+		case conn.Send() <- data:
+		default:
+			close(conn.Send())
+			delete(connections, conn)
+			if len(connections) == 0 {
+				delete(h.rooms, roomID)
+			}
 		}
 	}
 }
 
+// SendToUser sends the message to every connection of the user regardless of room.
+func (h *Hub) SendToUser(userID string, data []byte) {
+	h.Broadcast(Message{UserID: userID, Data: data})
+}
+
 // Register adds a subscription to the room.
 func (h *Hub) Register(sub Subscription) {
 	h.register <- sub
@@ -115,8 +170,16 @@ func (h *Hub) Unregister(sub Subscription) {
 	h.unregister <- sub
 }
 
-// Broadcast sends a message to all connections in the room.
+// Broadcast sends a message to all connections in the room,
+// once the hub is shutting down the message is instead stored for replay.
 func (h *Hub) Broadcast(msg Message) {
+	if h.shuttingDown.Load() {
+		// user messages aren't replayed as the replay is sent to the whole room
+		if msg.Data != nil && msg.UserID == "" {
+			h.saveForReplay(context.Background(), msg.Room, [][]byte{msg.Data})
+		}
+		return
+	}
 	h.broadcast <- msg
 }
 
@@ -127,10 +190,32 @@ func (h *Hub) RoomExists(room string) bool {
 	return <-response
 }
 
+// RoomUsers gets the IDs of the users connected to each room.
+func (h *Hub) RoomUsers() map[string][]string {
+	response := make(chan []Subscription)
+	h.subscriptions <- response
+	subs := <-response
+
+	roomUsers := make(map[string][]string)
+	for _, sub := range subs {
+		if !slices.Contains(roomUsers[sub.RoomID], sub.UserID) {
+			roomUsers[sub.RoomID] = append(roomUsers[sub.RoomID], sub.UserID)
+		}
+	}
+
+	return roomUsers
+}
+
 // NewConnection creates a new websocket connection.
 func (h *Hub) NewConnection(ws *websocket.Conn) Connection {
+	lastRead := &atomic.Int64{}
+	lastRead.Store(time.Now().UnixNano())
+
 	return Connection{
 		send:       make(chan []byte, 256),
+		done:       make(chan struct{}),
+		lastRead:   lastRead,
+		lastPong:   &atomic.Int64{},
 		Ws:         ws,
 		PingPeriod: h.config.PingPeriod(),
 		WriteWait:  h.config.WriteWait(),
@@ -146,6 +231,8 @@ func (h *Hub) NewSubscriber(ws *websocket.Conn, userID string, roomID string) Su
 		UserID: userID,
 	}
 
+	// queue up any messages from before a restart ahead of new room messages
+	h.replay(sub)
 	h.Register(sub)
 
 	return sub
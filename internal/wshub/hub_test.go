@@ -40,7 +40,55 @@ func TestCreateWebsocketUpgrader(t *testing.T) {
 	upgrader := hub.CreateWebsocketUpgrader()
 
 	assert.NotNil(t, upgrader)
-	assert.Equal(t, 1024, upgrader.ReadBufferSize)
-	assert.Equal(t, 1024, upgrader.WriteBufferSize)
+	assert.Equal(t, 4096, upgrader.ReadBufferSize)
+	assert.Equal(t, 4096, upgrader.WriteBufferSize)
 	assert.NotNil(t, upgrader.CheckOrigin)
+
+	hub = NewHub(otelzap.New(zap.NewNop()), Config{ReadBufferSize: 2048, WriteBufferSize: 65536}, nil, nil, nil, nil)
+	upgrader = hub.CreateWebsocketUpgrader()
+	assert.Equal(t, 2048, upgrader.ReadBufferSize)
+	assert.Equal(t, 65536, upgrader.WriteBufferSize)
+}
+
+// TestSendToUser makes sure a user message only reaches that user's connections across every room
+func TestSendToUser(t *testing.T) {
+	hub := NewHub(otelzap.New(zap.NewNop()), Config{}, nil, nil, nil, nil)
+	go hub.Run()
+
+	userRoomA := Connection{send: make(chan []byte, 1)}
+	userRoomB := Connection{send: make(chan []byte, 1)}
+	otherRoomA := Connection{send: make(chan []byte, 1)}
+	hub.Register(Subscription{Conn: userRoomA, RoomID: "a", UserID: "user"})
+	hub.Register(Subscription{Conn: userRoomB, RoomID: "b", UserID: "user"})
+	hub.Register(Subscription{Conn: otherRoomA, RoomID: "a", UserID: "other"})
+
+	hub.SendToUser("user", []byte("notification"))
+	// the hub handles messages in order so once the room exists check returns the message was delivered
+	assert.True(t, hub.RoomExists("a"))
+
+	assert.Equal(t, []byte("notification"), <-userRoomA.send)
+	assert.Equal(t, []byte("notification"), <-userRoomB.send)
+	assert.Len(t, otherRoomA.send, 0)
+}
+
+// TestBroadcastUserDataSkipsUsers makes sure users without a built message aren't sent anything
+func TestBroadcastUserDataSkipsUsers(t *testing.T) {
+	hub := NewHub(otelzap.New(zap.NewNop()), Config{}, nil, nil, nil, nil)
+	go hub.Run()
+
+	included := Connection{send: make(chan []byte, 1)}
+	skipped := Connection{send: make(chan []byte, 1)}
+	hub.Register(Subscription{Conn: included, RoomID: "a", UserID: "included"})
+	hub.Register(Subscription{Conn: skipped, RoomID: "a", UserID: "skipped"})
+
+	hub.Broadcast(Message{Room: "a", UserData: func(userID string) []byte {
+		if userID == "skipped" {
+			return nil
+		}
+		return []byte("for " + userID)
+	}})
+	assert.True(t, hub.RoomExists("a"))
+
+	assert.Equal(t, []byte("for included"), <-included.send)
+	assert.Len(t, skipped.send, 0)
 }
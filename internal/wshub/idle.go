@@ -0,0 +1,96 @@
+package wshub
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/zap"
+)
+
+// idlePongTimeout is how long an idle connection has to respond to a ping before it's evicted.
+const idlePongTimeout = 5 * time.Second
+
+var idleEvictionsCounter, _ = otel.Meter("github.com/StevenWeathers/thunderdome-planning-poker/internal/wshub").
+	Int64Counter("ws_idle_evictions_total",
+		metric.WithDescription("Number of idle websocket connections evicted for not responding to a ping"))
+
+// currentSubscriptions lists the subscriptions of every room, must only be called from the Run loop.
+func (h *Hub) currentSubscriptions() []Subscription {
+	subs := make([]Subscription, 0)
+	for roomID, connections := range h.rooms {
+		for conn, userID := range connections {
+			subs = append(subs, Subscription{Conn: conn, RoomID: roomID, UserID: userID})
+		}
+	}
+
+	return subs
+}
+
+// EvictIdleConnections pings every connection that hasn't sent a message in the last idleMinutes
+// and closes those that don't respond with a pong in time, returning the number of evicted connections.
+func (h *Hub) EvictIdleConnections(idleMinutes int) int {
+	if h.shuttingDown.Load() {
+		return 0
+	}
+
+	response := make(chan []Subscription)
+	h.subscriptions <- response
+	subs := <-response
+
+	idleSince := time.Now().Add(-time.Duration(idleMinutes) * time.Minute).UnixNano()
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	evicted := 0
+
+	for _, sub := range subs {
+		if sub.Conn.lastRead == nil || sub.Conn.lastRead.Load() > idleSince {
+			continue
+		}
+
+		wg.Add(1)
+		go func(sub Subscription) {
+			defer wg.Done()
+			if h.pingIdleConnection(sub) {
+				return
+			}
+
+			// closing the connection ends its read pump, which removes the user from the room
+			sub.Conn.Close()
+			mu.Lock()
+			evicted++
+			mu.Unlock()
+		}(sub)
+	}
+	wg.Wait()
+
+	if evicted > 0 {
+		idleEvictionsCounter.Add(context.Background(), int64(evicted))
+		h.logger.Info("evicted idle websocket connections", zap.Int("evicted_count", evicted))
+	}
+
+	return evicted
+}
+
+// pingIdleConnection pings the connection and reports whether it responded with a pong in time.
+func (h *Hub) pingIdleConnection(sub Subscription) bool {
+	pingSent := time.Now().UnixNano()
+	if err := sub.Conn.Ws.WriteControl(
+		websocket.PingMessage, nil, time.Now().Add(sub.Conn.WriteWait),
+	); err != nil {
+		return false
+	}
+
+	deadline := time.Now().Add(h.idlePongTimeout)
+	for time.Now().Before(deadline) {
+		if sub.Conn.lastPong.Load() >= pingSent {
+			return true
+		}
+		time.Sleep(h.idlePongTimeout / 50)
+	}
+
+	return sub.Conn.lastPong.Load() >= pingSent
+}
@@ -0,0 +1,80 @@
+package wshub
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+func hubSubscriptionCount(hub *Hub) int {
+	response := make(chan []Subscription)
+	hub.subscriptions <- response
+	return len(<-response)
+}
+
+// TestEvictIdleConnections makes sure an idle connection that doesn't respond to the ping
+// is evicted within one tick while an idle connection that responds is kept
+func TestEvictIdleConnections(t *testing.T) {
+	hub := NewHub(otelzap.New(zap.NewNop()), Config{}, nil, nil, nil, nil)
+	hub.idlePongTimeout = 200 * time.Millisecond
+	go hub.Run()
+	subs := make(chan Subscription, 2)
+	server := newTestRoomServer(hub, subs)
+	defer server.Close()
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/room"
+
+	// reading lets the client answer pings with a pong
+	responsive, _, err := websocket.DefaultDialer.Dial(url, nil)
+	assert.NoError(t, err)
+	defer responsive.Close()
+	go func() {
+		for {
+			if _, _, err := responsive.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+	<-subs
+
+	// never reading means the client never answers pings
+	unresponsive, _, err := websocket.DefaultDialer.Dial(url, nil)
+	assert.NoError(t, err)
+	defer unresponsive.Close()
+	<-subs
+
+	assert.Equal(t, 2, hubSubscriptionCount(hub))
+
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+	<-ticker.C
+	started := time.Now()
+	evicted := hub.EvictIdleConnections(0)
+
+	assert.Equal(t, 1, evicted)
+	assert.Less(t, time.Since(started), time.Second)
+	assert.Eventually(t, func() bool { return hubSubscriptionCount(hub) == 1 }, 5*time.Second, 10*time.Millisecond)
+	assert.True(t, hub.RoomExists("room"))
+}
+
+// TestEvictIdleConnectionsSkipsActive makes sure connections that recently sent a message aren't pinged
+func TestEvictIdleConnectionsSkipsActive(t *testing.T) {
+	hub := NewHub(otelzap.New(zap.NewNop()), Config{}, nil, nil, nil, nil)
+	hub.idlePongTimeout = 200 * time.Millisecond
+	go hub.Run()
+	subs := make(chan Subscription, 1)
+	server := newTestRoomServer(hub, subs)
+	defer server.Close()
+
+	client, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/room", nil)
+	assert.NoError(t, err)
+	defer client.Close()
+	<-subs
+
+	assert.Equal(t, 0, hub.EvictIdleConnections(5))
+	assert.Equal(t, 1, hubSubscriptionCount(hub))
+}
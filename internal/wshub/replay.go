@@ -0,0 +1,81 @@
+package wshub
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// defaultReplayTTL is how long messages are kept for replay when no TTL is configured.
+const defaultReplayTTL = 5 * time.Minute
+
+// defaultReplayMaxMessages is how many of a rooms latest messages are kept for replay when no max is configured.
+const defaultReplayMaxMessages = 256
+
+// ReplayStore stores room messages to be replayed to clients reconnecting after a server restart.
+type ReplayStore interface {
+	// Save appends messages to the rooms replay buffer.
+	Save(ctx context.Context, roomID string, messages [][]byte) error
+	// Load returns the rooms replay buffer in the order the messages were saved.
+	Load(ctx context.Context, roomID string) ([][]byte, error)
+}
+
+// RedisReplayStore is a ReplayStore backed by a redis list per room.
+type RedisReplayStore struct {
+	Client *redis.Client
+	// How long a rooms replay buffer is kept after the last save.
+	TTL time.Duration
+	// How many of a rooms latest messages are kept, older messages are trimmed on save.
+	MaxMessages int
+}
+
+func replayKey(roomID string) string {
+	return fmt.Sprintf("ws:replay:%s", roomID)
+}
+
+// Save appends messages to the rooms replay buffer, trimming it to the latest MaxMessages
+// and refreshing its expiry so repeated restarts can't grow it unbounded.
+func (s *RedisReplayStore) Save(ctx context.Context, roomID string, messages [][]byte) error {
+	ttl := s.TTL
+	if ttl <= 0 {
+		ttl = defaultReplayTTL
+	}
+	maxMessages := s.MaxMessages
+	if maxMessages <= 0 {
+		maxMessages = defaultReplayMaxMessages
+	}
+	values := make([]interface{}, 0, len(messages))
+	for _, msg := range messages {
+		values = append(values, msg)
+	}
+
+	key := replayKey(roomID)
+	_, err := s.Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.RPush(ctx, key, values...)
+		pipe.LTrim(ctx, key, int64(-maxMessages), -1)
+		pipe.Expire(ctx, key, ttl)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("replay save error: %v", err)
+	}
+
+	return nil
+}
+
+// Load returns the rooms replay buffer in the order the messages were saved.
+func (s *RedisReplayStore) Load(ctx context.Context, roomID string) ([][]byte, error) {
+	values, err := s.Client.LRange(ctx, replayKey(roomID), 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("replay load error: %v", err)
+	}
+
+	messages := make([][]byte, 0, len(values))
+	for _, v := range values {
+		messages = append(messages, []byte(v))
+	}
+
+	return messages, nil
+}
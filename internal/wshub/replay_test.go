@@ -0,0 +1,49 @@
+package wshub
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingHook records the pipelined commands instead of sending them to redis
+type recordingHook struct {
+	cmds []redis.Cmder
+}
+
+func (h *recordingHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (h *recordingHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return next
+}
+
+func (h *recordingHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		h.cmds = append(h.cmds, cmds...)
+		return nil
+	}
+}
+
+// TestRedisReplayStoreSaveBounds makes sure saving trims the rooms replay buffer and refreshes its expiry
+func TestRedisReplayStoreSaveBounds(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:0"})
+	defer client.Close()
+	hook := &recordingHook{}
+	client.AddHook(hook)
+
+	store := &RedisReplayStore{Client: client, TTL: time.Minute, MaxMessages: 10}
+	assert.NoError(t, store.Save(context.Background(), "room", [][]byte{[]byte("a"), []byte("b")}))
+
+	args := make([][]interface{}, 0)
+	for _, cmd := range hook.cmds {
+		args = append(args, cmd.Args())
+	}
+	assert.Contains(t, args, []interface{}{"rpush", "ws:replay:room", []byte("a"), []byte("b")})
+	assert.Contains(t, args, []interface{}{"ltrim", "ws:replay:room", int64(-10), int64(-1)})
+	assert.Contains(t, args, []interface{}{"expire", "ws:replay:room", int64(60)})
+}
@@ -0,0 +1,151 @@
+package wshub
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type shutdownRequest struct {
+	event    []byte
+	response chan shutdownResult
+}
+
+type shutdownResult struct {
+	// undelivered messages by room
+	pending map[string][][]byte
+	// write pump done channels of the closed connections
+	closed []chan struct{}
+}
+
+// ShuttingDown returns whether the hub has begun shutting down.
+func (h *Hub) ShuttingDown() bool {
+	return h.shuttingDown.Load()
+}
+
+// Shutdown stops accepting new connections, notifies all rooms with a server_shutdown event,
+// stores undelivered messages for replay once clients reconnect and then closes all connections,
+// waiting until they are closed or the context is done.
+func (h *Hub) Shutdown(ctx context.Context) error {
+	if !h.shuttingDown.CompareAndSwap(false, true) {
+		return nil
+	}
+
+	shutdownValue, _ := json.Marshal(struct {
+		RestartAt time.Time `json:"restartAt"`
+	}{
+		RestartAt: time.Now().Add(h.config.ShutdownGracePeriod()),
+	})
+	req := shutdownRequest{
+		event:    CreateSocketEvent("server_shutdown", string(shutdownValue), ""),
+		response: make(chan shutdownResult),
+	}
+
+	select {
+	case h.shutdown <- req:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	result := <-req.response
+
+	var err error
+	for roomID, messages := range result.pending {
+		err = errors.Join(err, h.saveForReplay(ctx, roomID, messages))
+	}
+
+	for _, done := range result.closed {
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return errors.Join(err, ctx.Err())
+		}
+	}
+
+	return err
+}
+
+// closeRooms collects the undelivered messages for each room, queues the shutdown event
+// and closes every connection, must only be called from the Run loop.
+func (h *Hub) closeRooms(event []byte) shutdownResult {
+	result := shutdownResult{
+		pending: make(map[string][][]byte),
+		closed:  make([]chan struct{}, 0),
+	}
+
+	for roomID, connections := range h.rooms {
+		for conn := range connections {
+			// every connection in a room receives the same broadcasts,
+			// so the one furthest behind holds all the undelivered messages
+			pending := drainPending(conn.send)
+			if len(pending) > len(result.pending[roomID]) {
+				result.pending[roomID] = pending
+			}
+
+			select {
+			case conn.send <- event:
+			default:
+			}
+			close(conn.send)
+			result.closed = append(result.closed, conn.done)
+		}
+		delete(h.rooms, roomID)
+	}
+
+	return result
+}
+
+// drainPending empties the connections outbound message buffer without blocking.
+func drainPending(send chan []byte) [][]byte {
+	pending := make([][]byte, 0)
+	for {
+		select {
+		case msg := <-send:
+			pending = append(pending, msg)
+		default:
+			return pending
+		}
+	}
+}
+
+// saveForReplay stores room messages in the replay store when one is configured.
+func (h *Hub) saveForReplay(ctx context.Context, roomID string, messages [][]byte) error {
+	if h.config.ReplayStore == nil || len(messages) == 0 {
+		return nil
+	}
+
+	err := h.config.ReplayStore.Save(ctx, roomID, messages)
+	if err != nil {
+		h.logger.Ctx(ctx).Error("websocket replay save error", zap.Error(err),
+			zap.String("room_id", roomID))
+	}
+
+	return err
+}
+
+// replay queues any stored room messages onto the subscriptions connection.
+func (h *Hub) replay(sub Subscription) {
+	if h.config.ReplayStore == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.config.WriteWait())
+	defer cancel()
+
+	messages, err := h.config.ReplayStore.Load(ctx, sub.RoomID)
+	if err != nil {
+		h.logger.Ctx(ctx).Error("websocket replay load error", zap.Error(err),
+			zap.String("room_id", sub.RoomID), zap.String("session_user_id", sub.UserID))
+		return
+	}
+
+	for _, msg := range messages {
+		select {
+		case sub.Conn.send <- msg:
+		default:
+			return
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package wshub
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+type memoryReplayStore struct {
+	mu       sync.Mutex
+	messages map[string][][]byte
+}
+
+func (s *memoryReplayStore) Save(ctx context.Context, roomID string, messages [][]byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages[roomID] = append(s.messages[roomID], messages...)
+	return nil
+}
+
+func (s *memoryReplayStore) Load(ctx context.Context, roomID string) ([][]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.messages[roomID], nil
+}
+
+func (s *memoryReplayStore) count(roomID string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.messages[roomID])
+}
+
+// newTestRoomServer serves the hub on /{roomId}, subscribing each connection to the room
+// and handing the subscription to the test before its write pump is started
+func newTestRoomServer(hub *Hub, subs chan<- Subscription) *httptest.Server {
+	router := mux.NewRouter()
+	router.HandleFunc("/{roomId}", hub.WebSocketHandler("roomId", func(w http.ResponseWriter, r *http.Request, c *Connection, roomID string) *AuthError {
+		sub := hub.NewSubscriber(c.Ws, "user", roomID)
+		go sub.ReadPump(r.Context(), hub)
+		subs <- sub
+		return nil
+	}))
+
+	return httptest.NewServer(router)
+}
+
+func readEventType(t *testing.T, conn *websocket.Conn) (string, string) {
+	t.Helper()
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, msg, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatalf("failed to read message: %v", err)
+	}
+	var event SocketEvent
+	if err := json.Unmarshal(msg, &event); err != nil {
+		t.Fatalf("failed to parse message: %v", err)
+	}
+	return event.Type, event.Value
+}
+
+// TestShutdownReplaysPendingMessages shuts the hub down while a message is still waiting to be
+// written to a client and makes sure it is replayed when the client reconnects after the restart
+func TestShutdownReplaysPendingMessages(t *testing.T) {
+	store := &memoryReplayStore{messages: make(map[string][][]byte)}
+	config := Config{ShutdownGracePeriodSec: 5, ReplayStore: store}
+
+	hub := NewHub(otelzap.New(zap.NewNop()), config, nil, nil, nil, nil)
+	go hub.Run()
+	subs := make(chan Subscription, 1)
+	server := newTestRoomServer(hub, subs)
+	defer server.Close()
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/room"
+
+	client, _, err := websocket.DefaultDialer.Dial(url, nil)
+	assert.NoError(t, err)
+	defer client.Close()
+	sub := <-subs
+
+	// the write pump isn't running yet so the vote stays pending in the connections buffer
+	vote := CreateSocketEvent("vote_activity", "pending vote", "")
+	hub.Broadcast(Message{Data: vote, Room: "room"})
+
+	shutdownErr := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), config.ShutdownGracePeriod())
+		defer cancel()
+		shutdownErr <- hub.Shutdown(ctx)
+	}()
+	assert.Eventually(t, func() bool { return store.count("room") == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	// new connections are turned away while shutting down
+	_, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	assert.Error(t, err)
+	if assert.NotNil(t, resp) {
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	}
+
+	go sub.WritePump()
+	eventType, _ := readEventType(t, client)
+	assert.Equal(t, "server_shutdown", eventType)
+	assert.NoError(t, <-shutdownErr)
+
+	// restarted server sharing the replay store
+	restartedHub := NewHub(otelzap.New(zap.NewNop()), config, nil, nil, nil, nil)
+	go restartedHub.Run()
+	restartedSubs := make(chan Subscription, 1)
+	restartedServer := newTestRoomServer(restartedHub, restartedSubs)
+	defer restartedServer.Close()
+
+	reconnected, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(restartedServer.URL, "http")+"/room", nil)
+	assert.NoError(t, err)
+	defer reconnected.Close()
+	restartedSub := <-restartedSubs
+	go restartedSub.WritePump()
+
+	eventType, eventValue := readEventType(t, reconnected)
+	assert.Equal(t, "vote_activity", eventType)
+	assert.Equal(t, "pending vote", eventValue)
+}
@@ -22,6 +22,9 @@ func (s *Subscription) WritePump() {
 	defer func() {
 		ticker.Stop()
 		_ = s.Conn.Ws.Close()
+		if s.Conn.done != nil {
+			close(s.Conn.done)
+		}
 	}()
 	for {
 		select {
@@ -76,6 +79,7 @@ func (s *Subscription) ReadPump(
 	s.Conn.Ws.SetReadLimit(maxMessageSize)
 	_ = s.Conn.Ws.SetReadDeadline(time.Now().Add(s.Conn.PongWait))
 	s.Conn.Ws.SetPongHandler(func(string) error {
+		s.Conn.markPong()
 		_ = s.Conn.Ws.SetReadDeadline(time.Now().Add(s.Conn.PongWait))
 		return nil
 	})
@@ -94,6 +98,7 @@ func (s *Subscription) ReadPump(
 			}
 			break
 		}
+		s.Conn.markRead()
 
 		keyVal := make(map[string]string)
 		err = json.Unmarshal(msg, &keyVal)
@@ -131,7 +136,8 @@ func (s *Subscription) ReadPump(
 			}
 		}
 
-		if !badEvent && hub.RoomExists(s.RoomID) {
+		// a nil message means the event handler already broadcast its own messages
+		if !badEvent && msg != nil && hub.RoomExists(s.RoomID) {
 			hub.Broadcast(Message{Data: msg, Room: s.RoomID})
 		}
 
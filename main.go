@@ -5,11 +5,18 @@ import (
 	_ "embed"
 	"os"
 	"strconv"
+	"time"
 
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/alerting"
+	asanaData "github.com/StevenWeathers/thunderdome-planning-poker/internal/db/asana"
 	jiraData "github.com/StevenWeathers/thunderdome-planning-poker/internal/db/jira"
+	notionData "github.com/StevenWeathers/thunderdome-planning-poker/internal/db/notion"
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/recurrence"
 	"github.com/StevenWeathers/thunderdome-planning-poker/internal/redis"
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/reminder"
 
 	"github.com/StevenWeathers/thunderdome-planning-poker/internal/webhook/subscription"
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/wshub"
 
 	"github.com/StevenWeathers/thunderdome-planning-poker/internal/cookie"
 
@@ -18,6 +25,7 @@ import (
 	"github.com/StevenWeathers/thunderdome-planning-poker/internal/db/alert"
 	"github.com/StevenWeathers/thunderdome-planning-poker/internal/db/apikey"
 	"github.com/StevenWeathers/thunderdome-planning-poker/internal/db/auth"
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/db/notification"
 	"github.com/StevenWeathers/thunderdome-planning-poker/internal/db/poker"
 	"github.com/StevenWeathers/thunderdome-planning-poker/internal/db/retro"
 	"github.com/StevenWeathers/thunderdome-planning-poker/internal/db/retrotemplate"
@@ -27,6 +35,9 @@ import (
 	"github.com/StevenWeathers/thunderdome-planning-poker/internal/db/user"
 
 	"github.com/StevenWeathers/thunderdome-planning-poker/internal/http"
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/http/auth/saml"
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/oauth"
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/storage"
 	"github.com/StevenWeathers/thunderdome-planning-poker/ui"
 
 	"github.com/StevenWeathers/thunderdome-planning-poker/internal/config"
@@ -67,6 +78,9 @@ func main() {
 	embedUseOS = len(os.Args) > 1 && os.Args[1] == "live"
 
 	c := config.InitConfig(logger)
+	if err := c.Http.ValidateWebsocketBufferSizes(); err != nil {
+		logger.Fatal("invalid websocket buffer size configuration", zap.Error(err))
+	}
 
 	// 初始化 Redis
 	redisPort, err := strconv.Atoi(os.Getenv("REDIS_PORT"))
@@ -147,16 +161,29 @@ func main() {
 				logger.Info("Redis initialized and connected successfully",
 					zap.String("host", redisConfig.Host),
 					zap.Int("port", redisConfig.Port))
+				// 定时检查缓存命中率，过低时告警
+				go redis.MonitorCacheHitRate(context.Background(), logger, redis.GetCacheStats,
+					c.Redis.CacheHitRateAlertThreshold, redis.CacheHitRateCheckInterval)
 			}
 		}
 	}
 
+	// 定时检查Redis可用性，不可用时仅使用数据库
+	if redis.GetClient() != nil {
+		go redis.MonitorAvailability(context.Background(), redis.HealthCheckInterval)
+	}
+
 	if c.Otel.Enabled {
+		sampler, err := c.Otel.Sampler()
+		if err != nil {
+			logger.Fatal("invalid open telemetry sampling configuration", zap.Error(err))
+		}
 		cleanup := initTracer(
 			logger,
 			c.Otel.ServiceName,
 			c.Otel.CollectorUrl,
 			c.Otel.InsecureMode,
+			sampler,
 		)
 		defer func() {
 			_ = cleanup(context.Background())
@@ -179,26 +206,39 @@ func main() {
 		MaxOpenConns:           c.Db.MaxOpenConns,
 		ConnMaxLifetime:        c.Db.ConnMaxLifetime,
 		DefaultEstimationScale: c.Config.AllowedPointValues,
+		ReadReplicaHost:        c.Db.ReadReplicaHost,
+		ReadReplicaPort:        c.Db.ReadReplicaPort,
+		ReadReplicaUser:        c.Db.ReadReplicaUser,
+		ReadReplicaPassword:    c.Db.ReadReplicaPass,
 	}, logger)
 
-	userService := &user.Service{DB: d.DB, Logger: logger}
-	apkService := &apikey.Service{DB: d.DB, Logger: logger}
+	cacheTTL := c.Cache.TTLConfig()
+	userService := &user.Service{DB: d.DB, Logger: logger, Redis: redis.GetClient(), CacheTTL: cacheTTL, RequireRegistrationApproval: c.Config.RequireRegistrationApproval}
+	apkService := &apikey.Service{DB: d.DB, Logger: logger, Redis: redis.GetClient()}
 	alertService := &alert.Service{DB: d.DB, Logger: logger}
 	authService := &auth.Service{DB: d.DB, Logger: logger, AESHashkey: d.Config.AESHashkey}
 	battleService := &poker.Service{
-		DB: d.DB, Logger: logger, AESHashKey: d.Config.AESHashkey,
+		DB: d.DB, ReadDB: d.ReadDB, Logger: logger, AESHashKey: d.Config.AESHashkey,
+		IPHashSalt:          c.Config.IPHashSalt,
 		HTMLSanitizerPolicy: d.HTMLSanitizerPolicy,
 		Redis:               redis.GetClient(),
+		CacheTTL:            cacheTTL,
 	}
 	checkinService := &team.CheckinService{DB: d.DB, Logger: logger, HTMLSanitizerPolicy: d.HTMLSanitizerPolicy}
-	retroService := &retro.Service{DB: d.DB, Logger: logger, AESHashKey: d.Config.AESHashkey}
+	retroService := &retro.Service{DB: d.DB, Logger: logger, AESHashKey: d.Config.AESHashkey, Redis: redis.GetClient()}
 	storyboardService := &storyboard.Service{DB: d.DB, Logger: logger, AESHashKey: d.Config.AESHashkey}
-	teamService := &team.Service{DB: d.DB, Logger: logger}
-	organizationService := &team.OrganizationService{DB: d.DB, Logger: logger}
-	adminService := &admin.Service{DB: d.DB, Logger: logger}
-	subscriptionDataSvc := &subscriptionData.Service{DB: d.DB, Logger: logger}
+	teamService := &team.Service{
+		DB: d.DB, Logger: logger, Redis: redis.GetClient(), CacheTTL: cacheTTL,
+		ActivityScoreWeights: c.Config.ActivityScoreWeights,
+	}
+	organizationService := &team.OrganizationService{DB: d.DB, Logger: logger, Redis: redis.GetClient(), CacheTTL: cacheTTL}
+	adminService := &admin.Service{DB: d.DB, Logger: logger, Redis: redis.GetClient(), CacheTTL: cacheTTL}
+	subscriptionDataSvc := &subscriptionData.Service{DB: d.DB, Logger: logger, Redis: redis.GetClient(), CacheTTL: cacheTTL}
 	jiraDataSvc := &jiraData.Service{DB: d.DB, Logger: logger, AESHashKey: d.Config.AESHashkey}
+	asanaDataSvc := &asanaData.Service{DB: d.DB, Logger: logger, AESHashKey: d.Config.AESHashkey}
+	notionDataSvc := &notionData.Service{DB: d.DB, Logger: logger, AESHashKey: d.Config.AESHashkey}
 	retroTemplateDataSvc := &retrotemplate.Service{DB: d.DB, Logger: logger}
+	notificationDataSvc := &notification.Service{DB: d.DB, Logger: logger}
 	cook := cookie.New(cookie.Config{
 		AppDomain:           c.Http.Domain,
 		PathPrefix:          c.Http.PathPrefix,
@@ -223,54 +263,133 @@ func main() {
 		SmtpSkipTLSVerify: c.Smtp.SkipTLSVerify,
 		SmtpAuth:          c.Smtp.Auth,
 	}, logger)
+	go reminder.New(logger, checkinService, emailSvc).Run(context.Background())
+	go reminder.NewDigestScheduler(logger, checkinService, teamService, emailSvc).Run(context.Background())
+	if c.Feature.Poker {
+		go recurrence.New(logger, battleService).Run(context.Background())
+	}
+	if c.Feature.Retro {
+		go recurrence.NewRetroScheduler(logger, retroService, retroTemplateDataSvc).Run(context.Background())
+	}
+	if c.Config.AllowExternalApi {
+		go apkService.RunUsageFlusher(context.Background(), apikey.UsageFlushInterval)
+	}
+	if c.Alerting.AlertmanagerURL != "" {
+		// 运维告警通过 Alertmanager 发送
+		notifier := alerting.NewAlertmanagerNotifier(c.Alerting.AlertmanagerURL, "https://"+c.Http.Domain+c.Http.PathPrefix+"/admin")
+		var cacheStats alerting.CacheStatsSource
+		if redis.GetClient() != nil {
+			cacheStats = redis.GetCacheStats
+		}
+		cacheHitRateThreshold := c.Redis.CacheHitRateAlertThreshold
+		if cacheHitRateThreshold <= 0 {
+			cacheHitRateThreshold = redis.DefaultCacheHitRateAlertThreshold
+		}
+		go alerting.NewHealthChecker(logger, notifier, adminService, time.Duration(c.Alerting.StaleGameHours)*time.Hour,
+			cacheStats, cacheHitRateThreshold).Run(context.Background(), alerting.HealthCheckInterval)
+	}
+	var webhookIdempotencyStore subscription.IdempotencyStore
+	if redisClient := redis.GetClient(); redisClient != nil {
+		webhookIdempotencyStore = redisClient
+	}
 	subscriptionService := subscription.New(subscription.Config{
-		AccountSecret: c.Subscription.AccountSecret,
-		WebhookSecret: c.Subscription.WebhookSecret,
-	}, logger, subscriptionDataSvc, emailSvc, userService,
+		AccountSecret:              c.Subscription.AccountSecret,
+		WebhookSecret:              c.Subscription.WebhookSecret,
+		BillingWebhookURL:          c.Subscription.BillingWebhookURL,
+		BillingWebhookEventFilters: c.Subscription.BillingWebhookEventFilters,
+	}, logger, subscriptionDataSvc, emailSvc, userService, webhookIdempotencyStore,
 	)
 
+	storageConfig := storage.Config{
+		Provider:        c.Storage.Provider,
+		Bucket:          c.Storage.Bucket,
+		Region:          c.Storage.Region,
+		Endpoint:        c.Storage.Endpoint,
+		AccessKeyID:     c.Storage.AccessKeyID,
+		SecretAccessKey: c.Storage.SecretAccessKey,
+		LocalPath:       c.Storage.LocalPath,
+		PathPrefix:      c.Http.PathPrefix,
+		URLExpiry:       time.Duration(c.Storage.URLExpiryMinutes) * time.Minute,
+		MaxUploadSize:   int64(c.Storage.MaxUploadSizeMB) * 1024 * 1024,
+	}
+	var storageService storage.StorageService
+	if storageConfig.Provider != "" {
+		storageService, err = storage.New(storageConfig)
+		if err != nil {
+			logger.Fatal("error creating attachment storage", zap.Error(err))
+		}
+	}
+
+	var websocketReplayStore wshub.ReplayStore
+	var oauthStateStore oauth.StateStore
+	if redisClient := redis.GetClient(); redisClient != nil {
+		websocketReplayStore = &wshub.RedisReplayStore{Client: redisClient}
+		oauthStateStore = &oauth.RedisStateStore{Client: redisClient}
+	}
+
+	adminIPAllowlist, err := http.ParseIPAllowlist(c.Http.AdminIPAllowlist)
+	if err != nil {
+		logger.Fatal("error parsing admin ip allowlist", zap.Error(err))
+	}
+	trustedProxies, err := http.ParseTrustedProxies(c.Http.TrustedProxies)
+	if err != nil {
+		logger.Fatal("error parsing trusted proxies", zap.Error(err))
+	}
+
 	uiHTTPFilesystem, uiFilesystem := ui.New(embedUseOS)
 	h := http.New(http.Service{
 		Config: &http.Config{
-			Port:                      c.Http.Port,
-			HttpWriteTimeout:          c.Http.WriteTimeout,
-			HttpReadTimeout:           c.Http.ReadTimeout,
-			HttpIdleTimeout:           c.Http.IdleTimeout,
-			HttpReadHeaderTimeout:     c.Http.ReadHeaderTimeout,
-			AppDomain:                 c.Http.Domain,
-			SecureProtocol:            c.Http.SecureProtocol,
-			PathPrefix:                c.Http.PathPrefix,
-			ExternalAPIEnabled:        c.Config.AllowExternalApi,
-			ExternalAPIVerifyRequired: c.Config.ExternalApiVerifyRequired,
-			UserAPIKeyLimit:           c.Config.UserApikeyLimit,
-			LdapEnabled:               ldapEnabled,
-			HeaderAuthEnabled:         headerAuthEnabled,
-			FeaturePoker:              c.Feature.Poker,
-			FeatureRetro:              c.Feature.Retro,
-			FeatureStoryboard:         c.Feature.Storyboard,
-			OrganizationsEnabled:      c.Config.OrganizationsEnabled,
-			AvatarService:             c.Config.AvatarService,
-			EmbedUseOS:                embedUseOS,
-			CleanupBattlesDaysOld:     c.Config.CleanupBattlesDaysOld,
-			CleanupRetrosDaysOld:      c.Config.CleanupRetrosDaysOld,
-			CleanupStoryboardsDaysOld: c.Config.CleanupStoryboardsDaysOld,
-			CleanupGuestsDaysOld:      c.Config.CleanupGuestsDaysOld,
-			RequireTeams:              c.Config.RequireTeams,
-			RetroDefaultTemplateID:    c.Config.RetroDefaultTemplateID,
-			AuthLdapUrl:               c.Auth.Ldap.Url,
-			AuthLdapUseTls:            c.Auth.Ldap.UseTls,
-			AuthLdapBindname:          c.Auth.Ldap.Bindname,
-			AuthLdapBindpass:          c.Auth.Ldap.Bindpass,
-			AuthLdapBasedn:            c.Auth.Ldap.Basedn,
-			AuthLdapFilter:            c.Auth.Ldap.Filter,
-			AuthLdapMailAttr:          c.Auth.Ldap.MailAttr,
-			AuthLdapCnAttr:            c.Auth.Ldap.CnAttr,
-			AuthHeaderUsernameHeader:  c.Auth.Header.UsernameHeader,
-			AuthHeaderEmailHeader:     c.Auth.Header.EmailHeader,
-			AllowGuests:               c.Config.AllowGuests,
-			AllowRegistration:         c.Config.AllowRegistration,
-			ShowActiveCountries:       c.Config.ShowActiveCountries,
-			SubscriptionsEnabled:      c.Config.SubscriptionsEnabled,
+			Port:                        c.Http.Port,
+			HttpWriteTimeout:            c.Http.WriteTimeout,
+			HttpReadTimeout:             c.Http.ReadTimeout,
+			HttpIdleTimeout:             c.Http.IdleTimeout,
+			HttpReadHeaderTimeout:       c.Http.ReadHeaderTimeout,
+			AppDomain:                   c.Http.Domain,
+			SecureProtocol:              c.Http.SecureProtocol,
+			PathPrefix:                  c.Http.PathPrefix,
+			MobileAppScheme:             c.Http.MobileAppScheme,
+			AdminIPAllowlist:            adminIPAllowlist,
+			TrustedProxies:              trustedProxies,
+			GitHubAPIURL:                c.Github.APIURL,
+			GitHubAllowedRepos:          c.Github.AllowedRepos,
+			ExternalAPIEnabled:          c.Config.AllowExternalApi,
+			ExternalAPIVerifyRequired:   c.Config.ExternalApiVerifyRequired,
+			UserAPIKeyLimit:             c.Config.UserApikeyLimit,
+			LdapEnabled:                 ldapEnabled,
+			HeaderAuthEnabled:           headerAuthEnabled,
+			FeaturePoker:                c.Feature.Poker,
+			FeatureRetro:                c.Feature.Retro,
+			FeatureStoryboard:           c.Feature.Storyboard,
+			OrganizationsEnabled:        c.Config.OrganizationsEnabled,
+			AvatarService:               c.Config.AvatarService,
+			EmbedUseOS:                  embedUseOS,
+			CleanupBattlesDaysOld:       c.Config.CleanupBattlesDaysOld,
+			CleanupRetrosDaysOld:        c.Config.CleanupRetrosDaysOld,
+			CleanupStoryboardsDaysOld:   c.Config.CleanupStoryboardsDaysOld,
+			CleanupGuestsDaysOld:        c.Config.CleanupGuestsDaysOld,
+			CleanupNotificationsDaysOld: c.Config.CleanupNotificationsDaysOld,
+			RequireTeams:                c.Config.RequireTeams,
+			RetroDefaultTemplateID:      c.Config.RetroDefaultTemplateID,
+			AuthLdapUrl:                 c.Auth.Ldap.Url,
+			AuthLdapUseTls:              c.Auth.Ldap.UseTls,
+			AuthLdapBindname:            c.Auth.Ldap.Bindname,
+			AuthLdapBindpass:            c.Auth.Ldap.Bindpass,
+			AuthLdapBasedn:              c.Auth.Ldap.Basedn,
+			AuthLdapFilter:              c.Auth.Ldap.Filter,
+			AuthLdapMailAttr:            c.Auth.Ldap.MailAttr,
+			AuthLdapCnAttr:              c.Auth.Ldap.CnAttr,
+			AuthHeaderUsernameHeader:    c.Auth.Header.UsernameHeader,
+			AuthHeaderEmailHeader:       c.Auth.Header.EmailHeader,
+			AllowGuests:                 c.Config.AllowGuests,
+			AllowRegistration:           c.Config.AllowRegistration,
+			ShowActiveCountries:         c.Config.ShowActiveCountries,
+			SubscriptionsEnabled:        c.Config.SubscriptionsEnabled,
+			ImportDeduplicationEnabled:  c.Config.ImportDeduplicationEnabled,
+			PasswordPolicy:              c.Auth.Password,
+			AllowAsanaImport:            c.Config.AllowAsanaImport,
+			AllowNotionImport:           c.Config.AllowNotionImport,
+			AllowCsvImport:              c.Config.AllowCsvImport,
+			OrgBulkImportEnabled:        c.Config.OrgBulkImportEnabled,
 			GoogleAuth: http.AuthProvider{
 				Enabled: c.Auth.Google.Enabled,
 				AuthProviderConfig: thunderdome.AuthProviderConfig{
@@ -280,11 +399,29 @@ func main() {
 					ClientSecret: c.Auth.Google.ClientSecret,
 				},
 			},
+			SAMLAuth: http.SAMLAuthProvider{
+				Enabled: c.Auth.SAML.IDPMetadataURL != "",
+				Config: saml.Config{
+					IDPURL:              c.Auth.SAML.IDPURL,
+					IDPMetadataURL:      c.Auth.SAML.IDPMetadataURL,
+					SPEntityID:          c.Auth.SAML.SPEntityID,
+					SPCallbackURL:       c.Auth.SAML.SPCallbackURL,
+					CertFile:            c.Auth.SAML.CertFile,
+					KeyFile:             c.Auth.SAML.KeyFile,
+					GroupAttribute:      c.Auth.SAML.GroupAttribute,
+					GroupOrganizationID: c.Auth.SAML.GroupOrganizationID,
+				},
+			},
+			Storage: storageConfig,
 			WebsocketConfig: http.WebsocketConfig{
-				WriteWaitSec:       c.Http.WebsocketWriteWaitSec,
-				PingPeriodSec:      c.Http.WebsocketPingPeriodSec,
-				PongWaitSec:        c.Http.WebsocketPongWaitSec,
-				WebsocketSubdomain: c.Http.WebsocketSubdomain,
+				WriteWaitSec:               c.Http.WebsocketWriteWaitSec,
+				PingPeriodSec:              c.Http.WebsocketPingPeriodSec,
+				PongWaitSec:                c.Http.WebsocketPongWaitSec,
+				WebsocketSubdomain:         c.Http.WebsocketSubdomain,
+				ShutdownGracePeriodSec:     c.Http.WebsocketShutdownGraceSec,
+				ReadBufferSize:             c.Http.WebsocketReadBufferSize,
+				WriteBufferSize:            c.Http.WebsocketWriteBufferSize,
+				ParticipantSyncIntervalSec: c.Http.ParticipantSyncIntervalSec,
 			},
 		},
 		Email:                emailSvc,
@@ -303,8 +440,14 @@ func main() {
 		AdminDataSvc:         adminService,
 		SubscriptionDataSvc:  subscriptionDataSvc,
 		JiraDataSvc:          jiraDataSvc,
+		AsanaDataSvc:         asanaDataSvc,
+		NotionDataSvc:        notionDataSvc,
 		RetroTemplateDataSvc: retroTemplateDataSvc,
+		NotificationDataSvc:  notificationDataSvc,
 		SubscriptionSvc:      subscriptionService,
+		StorageSvc:           storageService,
+		WebsocketReplayStore: websocketReplayStore,
+		OAuthStateStore:      oauthStateStore,
 		UIConfig: thunderdome.UIConfig{
 			AnalyticsEnabled: c.Analytics.Enabled,
 			AnalyticsID:      c.Analytics.ID,
@@ -317,6 +460,8 @@ func main() {
 				AllowGuests:                 c.Config.AllowGuests,
 				AllowRegistration:           c.Config.AllowRegistration && c.Auth.Method == "normal",
 				AllowJiraImport:             c.Config.AllowJiraImport,
+				AllowAsanaImport:            c.Config.AllowAsanaImport,
+				AllowNotionImport:           c.Config.AllowNotionImport,
 				AllowCsvImport:              c.Config.AllowCsvImport,
 				DefaultLocale:               c.Config.DefaultLocale,
 				OrganizationsEnabled:        c.Config.OrganizationsEnabled,
@@ -333,6 +478,7 @@ func main() {
 				LdapEnabled:                 ldapEnabled,
 				HeaderAuthEnabled:           headerAuthEnabled,
 				GoogleAuthEnabled:           c.Auth.Google.Enabled,
+				SAMLAuthEnabled:             c.Auth.SAML.IDPMetadataURL != "",
 				FeaturePoker:                c.Feature.Poker,
 				FeatureRetro:                c.Feature.Retro,
 				FeatureStoryboard:           c.Feature.Storyboard,
@@ -347,13 +493,55 @@ func main() {
 		},
 	}, uiFilesystem, uiHTTPFilesystem)
 
+	// 定时永久删除超过保留期的已软删除回顾
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for range ticker.C {
+			deleted, err := retroService.PurgeDeletedRetros(context.Background())
+			if err != nil {
+				logger.Error("purge deleted retros error", zap.Error(err))
+				continue
+			}
+			if deleted > 0 {
+				logger.Info("purged deleted retros", zap.Int64("retro_count", deleted))
+			}
+		}
+	}()
+
+	// 定时清理超过保留期的 webhook 事件日志
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for range ticker.C {
+			deleted, err := subscriptionDataSvc.CleanWebhookEvents(context.Background(), thunderdome.WebhookEventLogRetention)
+			if err != nil {
+				logger.Error("clean webhook event log error", zap.Error(err))
+				continue
+			}
+			if deleted > 0 {
+				logger.Info("cleaned webhook event log", zap.Int64("webhook_event_count", deleted))
+			}
+		}
+	}()
+
+	if c.Config.WsIdleTimeoutMinutes > 0 {
+		go func() {
+			ticker := time.NewTicker(time.Minute)
+			defer ticker.Stop()
+			for range ticker.C {
+				h.EvictIdleWebsocketConnections(c.Config.WsIdleTimeoutMinutes)
+			}
+		}()
+	}
+
 	err = h.ListenAndServe()
 	if err != nil {
 		logger.Fatal(err.Error())
 	}
 }
 
-func initTracer(logger *otelzap.Logger, serviceName string, collectorURL string, insecure bool) func(context.Context) error {
+func initTracer(logger *otelzap.Logger, serviceName string, collectorURL string, insecure bool, sampler sdktrace.Sampler) func(context.Context) error {
 	ctx := context.Background()
 	logger.Ctx(ctx).Info("initializing open telemetry")
 	secureOption := otlptracegrpc.WithTLSCredentials(credentials.NewClientTLSFromCert(nil, ""))
@@ -385,7 +573,7 @@ func initTracer(logger *otelzap.Logger, serviceName string, collectorURL string,
 
 	otel.SetTracerProvider(
 		sdktrace.NewTracerProvider(
-			sdktrace.WithSampler(sdktrace.AlwaysSample()),
+			sdktrace.WithSampler(sampler),
 			sdktrace.WithBatcher(exporter),
 			sdktrace.WithResource(resources),
 		),
@@ -1,5 +1,7 @@
 package thunderdome
 
+import "time"
+
 // ApplicationStats includes counts of different data points of the application
 type ApplicationStats struct {
 	UnregisteredCount                int `json:"unregisteredUserCount"`
@@ -37,3 +39,32 @@ type ApplicationStats struct {
 	TeamRetroTemplateCount           int `json:"teamRetroTemplateCount"`
 	PublicRetroTemplateCount         int `json:"publicRetroTemplateCount"`
 }
+
+// CleanupResult includes the games that were (or in dry run mode would be) deleted by a cleanup
+type CleanupResult struct {
+	DryRun     bool     `json:"dryRun"`
+	GameCount  int      `json:"gameCount"`
+	StoryCount int      `json:"storyCount"`
+	GameIDs    []string `json:"gameIds"`
+}
+
+// Migration record statuses
+const (
+	MigrationStatusApplied = "applied"
+	MigrationStatusPending = "pending"
+)
+
+// MigrationRecord is a database migration and whether it has been applied
+type MigrationRecord struct {
+	Version   int64      `json:"version"`
+	Status    string     `json:"status"`
+	AppliedAt *time.Time `json:"appliedAt"`
+}
+
+// MigrationStatus is the database migration state, degraded when the migration table can't be read
+type MigrationStatus struct {
+	Current          int64              `json:"current"`
+	PendingCount     int                `json:"pendingCount"`
+	Degraded         bool               `json:"degraded"`
+	MigrationHistory []*MigrationRecord `json:"migrationHistory"`
+}
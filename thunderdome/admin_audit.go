@@ -0,0 +1,88 @@
+package thunderdome
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Admin audit log actions
+const (
+	AuditActionSubscriptionOverride = "subscription_override"
+	AuditActionSubscriptionExtend   = "subscription_extend"
+)
+
+// MaxSubscriptionExtensionDays is the most days an admin can extend a subscription by at once
+const MaxSubscriptionExtensionDays = 366
+
+var (
+	// ErrInvalidSubscriptionTier is returned when overriding a subscription with an unknown tier
+	ErrInvalidSubscriptionTier = errors.New("INVALID_SUBSCRIPTION_TIER")
+	// ErrInvalidSubscriptionExtension is returned when extending a subscription by less than a day
+	// or more than MaxSubscriptionExtensionDays
+	ErrInvalidSubscriptionExtension = errors.New("INVALID_SUBSCRIPTION_EXTENSION")
+)
+
+// AdminAuditLog is a record of an admin changing something on behalf of users such as a subscription override
+type AdminAuditLog struct {
+	ID          string          `json:"id"`
+	ActorID     string          `json:"actorId"`
+	Action      string          `json:"action"`
+	EntityType  string          `json:"entityType"`
+	EntityID    string          `json:"entityId"`
+	Details     json.RawMessage `json:"details"`
+	CreatedDate time.Time       `json:"createdDate"`
+}
+
+// SubscriptionAuditDetails are the details of a subscription override or extension audit log entry
+type SubscriptionAuditDetails struct {
+	Tier           SubscriptionTier `json:"tier,omitempty"`
+	Days           int              `json:"days,omitempty"`
+	PreviousExpiry *time.Time       `json:"previousExpiry,omitempty"`
+	Expires        time.Time        `json:"expires"`
+	Reason         string           `json:"reason,omitempty"`
+}
+
+// NewSubscriptionAuditLog creates the audit log entry of an admin changing the organization's subscription
+func NewSubscriptionAuditLog(actorID string, orgID string, action string, details SubscriptionAuditDetails) (*AdminAuditLog, error) {
+	detailsJSON, err := json.Marshal(details)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AdminAuditLog{
+		ActorID:    actorID,
+		Action:     action,
+		EntityType: "organization",
+		EntityID:   orgID,
+		Details:    detailsJSON,
+	}, nil
+}
+
+// TierSubscriptionType gets the subscription type that grants the tier, the reverse of SubscriptionTypeTier,
+// the free tier has no subscription type
+func TierSubscriptionType(tier SubscriptionTier) (string, error) {
+	switch tier {
+	case SubscriptionTierEnterprise:
+		return "organization", nil
+	case SubscriptionTierTeam:
+		return "team", nil
+	case SubscriptionTierFree:
+		return "", nil
+	default:
+		return "", ErrInvalidSubscriptionTier
+	}
+}
+
+// ExtendSubscriptionExpiry adds the days to the subscription's expiry,
+// an expired subscription is extended from now so the extension isn't lost to the lapsed time
+func ExtendSubscriptionExpiry(expires time.Time, now time.Time, days int) (time.Time, error) {
+	if days < 1 || days > MaxSubscriptionExtensionDays {
+		return expires, ErrInvalidSubscriptionExtension
+	}
+	if expires.Before(now) {
+		expires = now
+	}
+
+	return expires.AddDate(0, 0, days), nil
+}
@@ -0,0 +1,85 @@
+package thunderdome
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestExtendSubscriptionExpiry makes sure active subscriptions are extended from their expiry
+// and lapsed subscriptions from now
+func TestExtendSubscriptionExpiry(t *testing.T) {
+	now := time.Date(2025, 3, 16, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		expires     time.Time
+		days        int
+		expected    time.Time
+		expectedErr error
+	}{
+		{name: "active", expires: now.AddDate(0, 0, 10), days: 30, expected: now.AddDate(0, 0, 40)},
+		{name: "expires now", expires: now, days: 1, expected: now.AddDate(0, 0, 1)},
+		{name: "expired", expires: now.AddDate(0, -2, 0), days: 30, expected: now.AddDate(0, 0, 30)},
+		{name: "max days", expires: now, days: MaxSubscriptionExtensionDays, expected: now.AddDate(0, 0, MaxSubscriptionExtensionDays)},
+		{name: "zero days", expires: now, days: 0, expectedErr: ErrInvalidSubscriptionExtension},
+		{name: "negative days", expires: now, days: -5, expectedErr: ErrInvalidSubscriptionExtension},
+		{name: "too many days", expires: now, days: MaxSubscriptionExtensionDays + 1, expectedErr: ErrInvalidSubscriptionExtension},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExtendSubscriptionExpiry(tt.expires, now, tt.days)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("expected error %v, got %v", tt.expectedErr, err)
+			}
+			if tt.expectedErr == nil && !got.Equal(tt.expected) {
+				t.Errorf("expected expiry %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestTierSubscriptionType(t *testing.T) {
+	for _, tier := range []SubscriptionTier{SubscriptionTierTeam, SubscriptionTierEnterprise} {
+		subscriptionType, err := TierSubscriptionType(tier)
+		if err != nil {
+			t.Fatalf("expected no error for %s, got %v", tier, err)
+		}
+		if SubscriptionTypeTier(subscriptionType) != tier {
+			t.Errorf("expected subscription type %s to grant %s", subscriptionType, tier)
+		}
+	}
+	if subscriptionType, err := TierSubscriptionType(SubscriptionTierFree); err != nil || subscriptionType != "" {
+		t.Errorf("expected no subscription type for the free tier, got %q %v", subscriptionType, err)
+	}
+	if _, err := TierSubscriptionType("platinum"); !errors.Is(err, ErrInvalidSubscriptionTier) {
+		t.Errorf("expected %v for an unknown tier, got %v", ErrInvalidSubscriptionTier, err)
+	}
+}
+
+// TestNewSubscriptionAuditLog makes sure the audit log entry records the actor, tier, expiry and reason
+func TestNewSubscriptionAuditLog(t *testing.T) {
+	expires := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	entry, err := NewSubscriptionAuditLog("admin", "org", AuditActionSubscriptionOverride, SubscriptionAuditDetails{
+		Tier:    SubscriptionTierEnterprise,
+		Expires: expires,
+		Reason:  "sales trial",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if entry.ActorID != "admin" || entry.EntityType != "organization" || entry.EntityID != "org" ||
+		entry.Action != AuditActionSubscriptionOverride {
+		t.Errorf("unexpected audit log entry %+v", entry)
+	}
+
+	var details SubscriptionAuditDetails
+	if err := json.Unmarshal(entry.Details, &details); err != nil {
+		t.Fatalf("expected details json, got %v", err)
+	}
+	if details.Tier != SubscriptionTierEnterprise || !details.Expires.Equal(expires) || details.Reason != "sales trial" {
+		t.Errorf("unexpected audit log details %+v", details)
+	}
+}
@@ -29,3 +29,11 @@ type UserAPIKey struct {
 	CreatedDate time.Time `json:"createdDate"`
 	UpdatedDate time.Time `json:"updatedDate"`
 }
+
+// APIKeyUsage is the number of calls made with an API key to an endpoint on a day
+type APIKeyUsage struct {
+	APIKeyID  string `json:"apiKeyId"`
+	Date      string `json:"date"`
+	Endpoint  string `json:"endpoint"`
+	CallCount int    `json:"callCount"`
+}
@@ -9,12 +9,15 @@ type SubscriptionPlanConfig struct {
 }
 
 type SubscriptionConfig struct {
-	ManageLink    string                 `mapstructure:"manage_link"`
-	AccountSecret string                 `mapstructure:"account_secret" json:"-"`
-	WebhookSecret string                 `mapstructure:"webhook_secret" json:"-"`
-	Individual    SubscriptionPlanConfig `mapstructure:"individual"`
-	Team          SubscriptionPlanConfig `mapstructure:"team"`
-	Organization  SubscriptionPlanConfig `mapstructure:"organization"`
+	ManageLink        string `mapstructure:"manage_link"`
+	AccountSecret     string `mapstructure:"account_secret" json:"-"`
+	WebhookSecret     string `mapstructure:"webhook_secret" json:"-"`
+	BillingWebhookURL string `mapstructure:"billing_webhook_url" json:"-"`
+	// BillingWebhookEventFilters limits the usage event types sent to the billing webhook, empty sends every type
+	BillingWebhookEventFilters []string               `mapstructure:"billing_webhook_event_filters" json:"-"`
+	Individual                 SubscriptionPlanConfig `mapstructure:"individual"`
+	Team                       SubscriptionPlanConfig `mapstructure:"team"`
+	Organization               SubscriptionPlanConfig `mapstructure:"organization"`
 }
 
 type AppConfig struct {
@@ -26,6 +29,8 @@ type AppConfig struct {
 	AllowGuests                 bool
 	AllowRegistration           bool
 	AllowJiraImport             bool
+	AllowAsanaImport            bool
+	AllowNotionImport           bool
 	AllowCsvImport              bool
 	DefaultLocale               string
 	OrganizationsEnabled        bool
@@ -42,6 +47,7 @@ type AppConfig struct {
 	LdapEnabled                 bool
 	HeaderAuthEnabled           bool
 	GoogleAuthEnabled           bool
+	SAMLAuthEnabled             bool
 	FeaturePoker                bool
 	FeatureRetro                bool
 	FeatureStoryboard           bool
@@ -0,0 +1,15 @@
+package thunderdome
+
+import (
+	"time"
+)
+
+// AsanaConnection is a user's Asana personal access token used to import project tasks as poker stories
+type AsanaConnection struct {
+	ID          string    `json:"id"`
+	UserID      string    `json:"user_id"`
+	Name        string    `json:"name"`
+	AccessToken string    `json:"access_token"`
+	CreatedDate time.Time `json:"created_date"`
+	UpdatedDate time.Time `json:"updated_date"`
+}
@@ -1,9 +1,47 @@
 package thunderdome
 
 import (
+	"errors"
+	"slices"
 	"time"
 )
 
+// Authentication methods a user session can be created with
+const (
+	AuthMethodPassword = "password"
+	AuthMethodLDAP     = "ldap"
+	AuthMethodHeader   = "header"
+	AuthMethodOIDC     = "oidc"
+	AuthMethodSAML     = "saml"
+)
+
+// SSOAuthMethods are the auth methods allowed by default for members of organizations that require SSO
+var SSOAuthMethods = []string{AuthMethodOIDC, AuthMethodLDAP, AuthMethodHeader, AuthMethodSAML}
+
+// ErrSSORequired is returned when a user belongs to an organization that requires SSO
+// and attempted to authenticate with a non SSO method
+var ErrSSORequired = errors.New("SSO_REQUIRED")
+
+// CheckSSOAuthMethod checks the auth method is allowed by every SSO required organization the user belongs to,
+// each entry of orgAllowedAuthMethods is a membership's allowed auth methods with nil meaning the SSO defaults.
+// SSO exempt users (e.g. admin created) may use any auth method
+func CheckSSOAuthMethod(authMethod string, ssoExempt bool, orgAllowedAuthMethods [][]string) error {
+	if ssoExempt {
+		return nil
+	}
+
+	for _, allowed := range orgAllowedAuthMethods {
+		if allowed == nil {
+			allowed = SSOAuthMethods
+		}
+		if !slices.Contains(allowed, authMethod) {
+			return ErrSSORequired
+		}
+	}
+
+	return nil
+}
+
 type AuthProviderConfig struct {
 	ProviderName string `mapstructure:"provider_name"`
 	ProviderURL  string `mapstructure:"provider_url"`
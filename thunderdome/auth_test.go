@@ -0,0 +1,41 @@
+package thunderdome
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestCheckSSOAuthMethod makes sure members of SSO required organizations can only use the allowed auth methods
+// and SSO exempt users bypass the check
+func TestCheckSSOAuthMethod(t *testing.T) {
+	tests := []struct {
+		name                  string
+		authMethod            string
+		ssoExempt             bool
+		orgAllowedAuthMethods [][]string
+		expectedErr           error
+	}{
+		{name: "no sso required organizations", authMethod: AuthMethodPassword},
+		{name: "password rejected by default", authMethod: AuthMethodPassword, orgAllowedAuthMethods: [][]string{nil}, expectedErr: ErrSSORequired},
+		{name: "oidc allowed by default", authMethod: AuthMethodOIDC, orgAllowedAuthMethods: [][]string{nil}},
+		{name: "saml allowed by default", authMethod: AuthMethodSAML, orgAllowedAuthMethods: [][]string{nil}},
+		{name: "password allowed by membership", authMethod: AuthMethodPassword, orgAllowedAuthMethods: [][]string{{AuthMethodPassword}}},
+		{name: "empty allowed methods reject everything", authMethod: AuthMethodOIDC, orgAllowedAuthMethods: [][]string{{}}, expectedErr: ErrSSORequired},
+		{
+			name:                  "every organization must allow the method",
+			authMethod:            AuthMethodLDAP,
+			orgAllowedAuthMethods: [][]string{nil, {AuthMethodOIDC}},
+			expectedErr:           ErrSSORequired,
+		},
+		{name: "sso exempt user bypasses the check", authMethod: AuthMethodPassword, ssoExempt: true, orgAllowedAuthMethods: [][]string{nil}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckSSOAuthMethod(tt.authMethod, tt.ssoExempt, tt.orgAllowedAuthMethods)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("expected %v, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package thunderdome
+
+import "time"
+
+// CacheTTLConfig is how long each entity type is kept in the Redis cache, a zero TTL disables caching the entity
+type CacheTTLConfig struct {
+	GameTTL  time.Duration
+	StoryTTL time.Duration
+	UserTTL  time.Duration
+	TeamTTL  time.Duration
+	OrgTTL   time.Duration
+}
+
+// CappedTTL returns the entity TTL limited to maxTTL, caches of frequently changing data
+// keep their shorter TTL when the entity TTL is longer
+func CappedTTL(ttl time.Duration, maxTTL time.Duration) time.Duration {
+	if ttl > maxTTL {
+		return maxTTL
+	}
+
+	return ttl
+}
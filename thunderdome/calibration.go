@@ -0,0 +1,57 @@
+package thunderdome
+
+import "time"
+
+// Consensus velocity trend directions
+const (
+	CalibrationTrendImproving = "improving"
+	CalibrationTrendDeclining = "declining"
+	CalibrationTrendSteady    = "steady"
+)
+
+// CalibrationReport summarizes how an organization's teams have been using their estimation scales
+type CalibrationReport struct {
+	OrganizationID string    `json:"organizationId"`
+	Since          time.Time `json:"since"`
+	// ScaleUsageDistribution is how often each scale value was used as a story's final points
+	ScaleUsageDistribution map[string]int `json:"scaleUsageDistribution"`
+	// AverageRoundsToConsensus is the average rounds of voting stories took to be finalized at each scale value
+	AverageRoundsToConsensus map[string]float64 `json:"averageRoundsToConsensus"`
+	// ConsensusVelocityTrend is the first round consensus rate of each week with finalized stories, oldest first
+	ConsensusVelocityTrend []*ConsensusWeek `json:"consensusVelocityTrend"`
+	// TrendDirection is whether the weekly consensus rate is improving, declining or steady
+	TrendDirection string `json:"trendDirection"`
+}
+
+// ConsensusWeek is the share of a week's finalized stories that reached consensus in their first round of voting
+type ConsensusWeek struct {
+	WeekStart     time.Time `json:"weekStart"`
+	Stories       int       `json:"stories"`
+	ConsensusRate float64   `json:"consensusRate"`
+}
+
+// EstimationBias is how a user's votes compared to the final points of the stories they voted on,
+// deviations are the user's vote minus the final points so under-estimating is negative
+type EstimationBias struct {
+	UserID string    `json:"userId"`
+	Since  time.Time `json:"since"`
+	// AverageDeviation is the average of the user's vote minus the story's final points
+	AverageDeviation float64 `json:"averageDeviation"`
+	// UnderestimationRate is the share of the user's votes lower than the final points
+	UnderestimationRate float64 `json:"underestimationRate"`
+	// OverestimationRate is the share of the user's votes higher than the final points
+	OverestimationRate float64 `json:"overestimationRate"`
+	VotesAnalyzed      int     `json:"votesAnalyzed"`
+	GamesAnalyzed      int     `json:"gamesAnalyzed"`
+}
+
+// UserEstimationRecord is a user's vote on a finalized story compared to the story's final points,
+// Delta is the vote minus the final points rounded to a whole number, 0 when either isn't numeric
+type UserEstimationRecord struct {
+	GameName    string    `json:"gameName"`
+	StoryName   string    `json:"storyName"`
+	UserVote    string    `json:"userVote"`
+	FinalPoints string    `json:"finalPoints"`
+	Delta       int       `json:"delta"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
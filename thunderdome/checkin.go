@@ -1,5 +1,7 @@
 package thunderdome
 
+import "time"
+
 type TeamCheckin struct {
 	ID          string            `json:"id"`
 	User        *TeamUser         `json:"user"`
@@ -22,3 +24,34 @@ type CheckinComment struct {
 	CreateDate  string `json:"created_date"`
 	UpdatedDate string `json:"updated_date"`
 }
+
+// StandupDigestDays is the number of days covered by the weekly standup digest
+const StandupDigestDays = 7
+
+// Standup A daily standup logged by a team user
+type Standup struct {
+	ID        string    `json:"id"`
+	TeamID    string    `json:"teamId"`
+	UserID    string    `json:"userId"`
+	Yesterday string    `json:"yesterday"`
+	Today     string    `json:"today"`
+	Blockers  string    `json:"blockers"`
+	Date      time.Time `json:"date"`
+}
+
+// StandupDigestEntry A team users standups included in the standup digest
+type StandupDigestEntry struct {
+	User     *TeamUser  `json:"user"`
+	Standups []*Standup `json:"standups"`
+}
+
+// CheckinSchedule A team's checkin reminder schedule
+type CheckinSchedule struct {
+	TeamID         string `json:"teamId"`
+	CronExpression string `json:"cronExpression"`
+	// TimezoneOffset is the schedule timezone offset from UTC in minutes
+	TimezoneOffset  int       `json:"timezoneOffset"`
+	ReminderEnabled bool      `json:"reminderEnabled"`
+	CreatedDate     time.Time `json:"createdDate"`
+	UpdatedDate     time.Time `json:"updatedDate"`
+}
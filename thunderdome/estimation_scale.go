@@ -0,0 +1,87 @@
+package thunderdome
+
+import (
+	"strconv"
+	"strings"
+)
+
+// MaxEstimationScaleValues is the most values an estimation scale can have
+const MaxEstimationScaleValues = 20
+
+// Estimation scale validation error codes
+const (
+	EstimationScaleTooFewValues  = "ESTIMATION_SCALE_TOO_FEW_VALUES"
+	EstimationScaleTooManyValues = "ESTIMATION_SCALE_TOO_MANY_VALUES"
+	EstimationScaleEmptyValue    = "ESTIMATION_SCALE_EMPTY_VALUE"
+	EstimationScaleDuplicate     = "ESTIMATION_SCALE_DUPLICATE_VALUE"
+	EstimationScaleNotMonotonic  = "ESTIMATION_SCALE_NOT_MONOTONIC"
+)
+
+// estimationScaleSpecialValues are the non-numeric values allowed in numeric scales
+var estimationScaleSpecialValues = map[string]bool{
+	"?":  true,
+	"☕":  true,
+	"☕️": true,
+	"∞":  true,
+}
+
+// ValidationError is a single problem found validating a field
+type ValidationError struct {
+	Field string `json:"field"`
+	Code  string `json:"code"`
+	Value string `json:"value,omitempty"`
+}
+
+// ValidateEstimationScale checks the scale has between 2 and MaxEstimationScaleValues unique values,
+// scales whose values are all numeric apart from the special values (?, ☕, ∞) must not decrease
+func ValidateEstimationScale(scale *EstimationScale) []ValidationError {
+	errs := make([]ValidationError, 0)
+
+	if len(scale.Values) < 2 {
+		errs = append(errs, ValidationError{Field: "values", Code: EstimationScaleTooFewValues})
+	}
+	if len(scale.Values) > MaxEstimationScaleValues {
+		errs = append(errs, ValidationError{Field: "values", Code: EstimationScaleTooManyValues})
+	}
+
+	seen := make(map[string]bool, len(scale.Values))
+	numbers := make([]float64, 0, len(scale.Values))
+	allNumeric := true
+	for _, value := range scale.Values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			errs = append(errs, ValidationError{Field: "values", Code: EstimationScaleEmptyValue})
+			continue
+		}
+		if seen[value] {
+			errs = append(errs, ValidationError{Field: "values", Code: EstimationScaleDuplicate, Value: value})
+			continue
+		}
+		seen[value] = true
+
+		if estimationScaleSpecialValues[value] {
+			continue
+		}
+		number, ok := ParsePointValue(value)
+		if !ok {
+			allNumeric = false
+			continue
+		}
+		numbers = append(numbers, number)
+	}
+
+	if allNumeric {
+		for i := 1; i < len(numbers); i++ {
+			if numbers[i] < numbers[i-1] {
+				errs = append(errs, ValidationError{
+					Field: "values",
+					Code:  EstimationScaleNotMonotonic,
+					Value: strconv.FormatFloat(numbers[i], 'f', -1, 64),
+				})
+				break
+			}
+		}
+	}
+
+	return errs
+}
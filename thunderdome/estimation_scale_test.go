@@ -0,0 +1,43 @@
+package thunderdome
+
+import (
+	"testing"
+)
+
+func TestValidateEstimationScale(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   []string
+		expected []string
+	}{
+		{name: "fibonacci", values: []string{"0", "1/2", "1", "2", "3", "5", "8", "13", "?", "☕️"}},
+		{name: "special values anywhere", values: []string{"?", "1", "∞", "2", "☕"}},
+		{name: "t-shirt sizes", values: []string{"XS", "S", "M", "L", "XL"}},
+		{name: "repeated equal numbers allowed", values: []string{"1", "1.0", "2"}},
+		{name: "duplicate values", values: []string{"1", "2", " 2 ", "3"}, expected: []string{EstimationScaleDuplicate}},
+		{name: "non monotonic", values: []string{"1", "3", "2", "5"}, expected: []string{EstimationScaleNotMonotonic}},
+		{name: "non monotonic fraction", values: []string{"1", "½", "2"}, expected: []string{EstimationScaleNotMonotonic}},
+		{name: "single value", values: []string{"1"}, expected: []string{EstimationScaleTooFewValues}},
+		{name: "empty value", values: []string{"1", "", "2"}, expected: []string{EstimationScaleEmptyValue}},
+		{
+			name:     "too many values",
+			values:   []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12", "13", "14", "15", "16", "17", "18", "19", "20", "21"},
+			expected: []string{EstimationScaleTooManyValues},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := ValidateEstimationScale(&EstimationScale{Values: tt.values})
+
+			if len(errs) != len(tt.expected) {
+				t.Fatalf("expected %d validation errors, got %+v", len(tt.expected), errs)
+			}
+			for i, code := range tt.expected {
+				if errs[i].Code != code || errs[i].Field != "values" {
+					t.Errorf("expected validation error %s on values, got %+v", code, errs[i])
+				}
+			}
+		})
+	}
+}
@@ -5,12 +5,23 @@ import (
 )
 
 type JiraInstance struct {
-	ID             string    `json:"id"`
-	UserID         string    `json:"user_id"`
-	Host           string    `json:"host"`
-	ClientMail     string    `json:"client_mail"`
-	AccessToken    string    `json:"access_token"`
-	JiraDataCenter bool      `json:"jira_data_center"` // Checkbox for enabling Jira Data Center
-	CreatedDate    time.Time `json:"created_date"`
-	UpdatedDate    time.Time `json:"updated_date"`
+	ID             string `json:"id"`
+	UserID         string `json:"user_id"`
+	Host           string `json:"host"`
+	ClientMail     string `json:"client_mail"`
+	AccessToken    string `json:"access_token"`
+	JiraDataCenter bool   `json:"jira_data_center"` // Checkbox for enabling Jira Data Center
+	// StoryPointsFieldID is the id of the instance's story points custom field, e.g. customfield_10016
+	StoryPointsFieldID string    `json:"story_points_field_id"`
+	CreatedDate        time.Time `json:"created_date"`
+	UpdatedDate        time.Time `json:"updated_date"`
+}
+
+// JiraPointsExportResult is the result of pushing a poker story's points to its Jira issue
+type JiraPointsExportResult struct {
+	StoryID     string `json:"storyId"`
+	ReferenceID string `json:"referenceId"`
+	Points      string `json:"points"`
+	Success     bool   `json:"success"`
+	Error       string `json:"error,omitempty"`
 }
@@ -0,0 +1,23 @@
+package thunderdome
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Notification types
+const (
+	NotificationTypeTeamInvite     = "team_invite"
+	NotificationTypeActionAssigned = "action_assigned"
+	NotificationTypeGameStarted    = "game_started"
+)
+
+// Notification is an in-app notification for a user
+type Notification struct {
+	ID        string          `json:"id" db:"id"`
+	UserID    string          `json:"userId" db:"user_id"`
+	Type      string          `json:"type" db:"type"`
+	Payload   json.RawMessage `json:"payload" db:"payload"`
+	IsRead    bool            `json:"isRead" db:"is_read"`
+	CreatedAt time.Time       `json:"createdAt" db:"created_at"`
+}
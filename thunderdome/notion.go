@@ -0,0 +1,26 @@
+package thunderdome
+
+import (
+	"time"
+)
+
+// NotionConnection is a user's Notion integration token used to import database pages as poker stories
+type NotionConnection struct {
+	ID          string `json:"id"`
+	UserID      string `json:"user_id"`
+	Name        string `json:"name"`
+	AccessToken string `json:"access_token"`
+	// DescriptionProperty is the name of the database property imported as the story description
+	DescriptionProperty string    `json:"description_property"`
+	CreatedDate         time.Time `json:"created_date"`
+	UpdatedDate         time.Time `json:"updated_date"`
+}
+
+// NotionFilter limits the database pages imported to those whose property equals the value
+type NotionFilter struct {
+	// Property is the name of the database property to filter by
+	Property string `json:"property" validate:"required"`
+	// Type is the Notion property type, one of select, status, rich_text, title or checkbox
+	Type   string `json:"type" validate:"required,oneof=select status rich_text title checkbox"`
+	Equals string `json:"equals"`
+}
@@ -6,11 +6,15 @@ import (
 
 // Organization can be a company
 type Organization struct {
-	ID          string    `json:"id"`
-	Name        string    `json:"name"`
-	Subscribed  *bool     `json:"subscribed,omitempty"`
-	CreatedDate time.Time `json:"createdDate"`
-	UpdatedDate time.Time `json:"updatedDate"`
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Subscribed  *bool  `json:"subscribed,omitempty"`
+	SSORequired bool   `json:"ssoRequired"`
+	// DefaultEstimationScaleID is the scale the organization's games use when created without one,
+	// nil uses the global default scale
+	DefaultEstimationScaleID *string   `json:"defaultEstimationScaleId"`
+	CreatedDate              time.Time `json:"createdDate"`
+	UpdatedDate              time.Time `json:"updatedDate"`
 }
 
 type UserOrganization struct {
@@ -83,3 +87,26 @@ type OrganizationMetrics struct {
 	EstimationScaleCount int    `json:"estimation_scale_count"`
 	RetroTemplateCount   int    `json:"retro_template_count"`
 }
+
+// OrganizationMemberImportRow is a member to add to an organization from a bulk import
+type OrganizationMemberImportRow struct {
+	// Row is the line number of the member in the imported file
+	Row   int
+	Email string
+	Role  string
+}
+
+// OrganizationMemberImportError is the reason a bulk import row could not be added
+type OrganizationMemberImportError struct {
+	Row    int    `json:"row"`
+	Email  string `json:"email"`
+	Reason string `json:"reason"`
+}
+
+// OrganizationMemberImportResult summarizes an organization member bulk import
+type OrganizationMemberImportResult struct {
+	Total     int                             `json:"total"`
+	Succeeded int                             `json:"succeeded"`
+	Failed    int                             `json:"failed"`
+	Errors    []OrganizationMemberImportError `json:"errors"`
+}
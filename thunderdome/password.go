@@ -0,0 +1,81 @@
+package thunderdome
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// PasswordPolicy is the password strength policy enforced when a user sets their password
+type PasswordPolicy struct {
+	MinLength        int  `mapstructure:"min_length" json:"minLength"`
+	MaxLength        int  `mapstructure:"max_length" json:"maxLength"`
+	RequireUppercase bool `mapstructure:"require_uppercase" json:"requireUppercase"`
+	RequireLowercase bool `mapstructure:"require_lowercase" json:"requireLowercase"`
+	RequireDigit     bool `mapstructure:"require_digit" json:"requireDigit"`
+	RequireSpecial   bool `mapstructure:"require_special" json:"requireSpecial"`
+}
+
+// Password policy rules a password can violate
+const (
+	PasswordTooShort          = "PASSWORD_TOO_SHORT"
+	PasswordTooLong           = "PASSWORD_TOO_LONG"
+	PasswordRequiresUppercase = "PASSWORD_REQUIRES_UPPERCASE"
+	PasswordRequiresLowercase = "PASSWORD_REQUIRES_LOWERCASE"
+	PasswordRequiresDigit     = "PASSWORD_REQUIRES_DIGIT"
+	PasswordRequiresSpecial   = "PASSWORD_REQUIRES_SPECIAL"
+)
+
+// PasswordValidationError lists every password policy rule the password violated
+type PasswordValidationError struct {
+	Violations []string `json:"violations"`
+}
+
+func (e *PasswordValidationError) Error() string {
+	return "PASSWORD_POLICY_VIOLATION: " + strings.Join(e.Violations, ", ")
+}
+
+// ValidatePassword checks the password against the policy returning a PasswordValidationError
+// listing all violated rules, a zero MinLength or MaxLength disables that length rule.
+// MinLength counts characters while MaxLength counts bytes since bcrypt only hashes the first 72 bytes
+func ValidatePassword(password string, policy PasswordPolicy) error {
+	var hasUpper, hasLower, hasDigit, hasSpecial bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			hasSpecial = true
+		}
+	}
+
+	violations := make([]string, 0)
+	if policy.MinLength > 0 && utf8.RuneCountInString(password) < policy.MinLength {
+		violations = append(violations, PasswordTooShort)
+	}
+	if policy.MaxLength > 0 && len([]byte(password)) > policy.MaxLength {
+		violations = append(violations, PasswordTooLong)
+	}
+	if policy.RequireUppercase && !hasUpper {
+		violations = append(violations, PasswordRequiresUppercase)
+	}
+	if policy.RequireLowercase && !hasLower {
+		violations = append(violations, PasswordRequiresLowercase)
+	}
+	if policy.RequireDigit && !hasDigit {
+		violations = append(violations, PasswordRequiresDigit)
+	}
+	if policy.RequireSpecial && !hasSpecial {
+		violations = append(violations, PasswordRequiresSpecial)
+	}
+
+	if len(violations) > 0 {
+		return &PasswordValidationError{Violations: violations}
+	}
+
+	return nil
+}
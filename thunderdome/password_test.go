@@ -0,0 +1,79 @@
+package thunderdome
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// TestValidatePassword makes sure every violated password policy rule is reported
+func TestValidatePassword(t *testing.T) {
+	strictPolicy := PasswordPolicy{
+		MinLength:        8,
+		MaxLength:        16,
+		RequireUppercase: true,
+		RequireLowercase: true,
+		RequireDigit:     true,
+		RequireSpecial:   true,
+	}
+
+	tests := []struct {
+		name       string
+		password   string
+		policy     PasswordPolicy
+		violations []string
+	}{
+		{name: "empty policy", password: "a", policy: PasswordPolicy{}, violations: nil},
+		{name: "default length policy", password: "infinity", policy: PasswordPolicy{MinLength: 6, MaxLength: 72}, violations: nil},
+		{name: "too short", password: "Ab1!", policy: PasswordPolicy{MinLength: 6}, violations: []string{PasswordTooShort}},
+		{name: "too long", password: "Abcdefgh1!", policy: PasswordPolicy{MaxLength: 8}, violations: []string{PasswordTooLong}},
+		{name: "min length counts characters not bytes", password: "ÄÖÜäöü", policy: PasswordPolicy{MinLength: 6, MaxLength: 12}, violations: nil},
+		{name: "max length counts bytes not characters", password: "ÄÖÜäöü", policy: PasswordPolicy{MaxLength: 6}, violations: []string{PasswordTooLong}},
+		{name: "missing uppercase", password: "abcdef", policy: PasswordPolicy{RequireUppercase: true}, violations: []string{PasswordRequiresUppercase}},
+		{name: "missing lowercase", password: "ABCDEF", policy: PasswordPolicy{RequireLowercase: true}, violations: []string{PasswordRequiresLowercase}},
+		{name: "missing digit", password: "abcdef", policy: PasswordPolicy{RequireDigit: true}, violations: []string{PasswordRequiresDigit}},
+		{name: "missing special", password: "abcdef1", policy: PasswordPolicy{RequireSpecial: true}, violations: []string{PasswordRequiresSpecial}},
+		{name: "symbol counts as special", password: "abc+def", policy: PasswordPolicy{RequireSpecial: true}, violations: nil},
+		{name: "strict policy satisfied", password: "Thanos1snap!", policy: strictPolicy, violations: nil},
+		{
+			name:     "strict policy all character rules violated",
+			password: "        ",
+			policy:   strictPolicy,
+			violations: []string{
+				PasswordRequiresUppercase, PasswordRequiresLowercase, PasswordRequiresDigit, PasswordRequiresSpecial,
+			},
+		},
+		{
+			name:       "strict policy short lowercase only",
+			password:   "snap",
+			policy:     strictPolicy,
+			violations: []string{PasswordTooShort, PasswordRequiresUppercase, PasswordRequiresDigit, PasswordRequiresSpecial},
+		},
+		{
+			name:       "strict policy long without special",
+			password:   "Infinitystones12345",
+			policy:     strictPolicy,
+			violations: []string{PasswordTooLong, PasswordRequiresSpecial},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePassword(tt.password, tt.policy)
+			if tt.violations == nil {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				return
+			}
+
+			var validationErr *PasswordValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("expected PasswordValidationError, got %v", err)
+			}
+			if !reflect.DeepEqual(validationErr.Violations, tt.violations) {
+				t.Errorf("expected violations %v, got %v", tt.violations, validationErr.Violations)
+			}
+		})
+	}
+}
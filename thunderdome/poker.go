@@ -1,43 +1,71 @@
 package thunderdome
 
 import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
 	"time"
 )
 
 // PokerUser aka user
 type PokerUser struct {
-	ID           string `json:"id"`
-	Name         string `json:"name"`
-	Type         string `json:"rank"`
-	Avatar       string `json:"avatar"`
-	Active       bool   `json:"active"`
-	Abandoned    bool   `json:"abandoned"`
-	Spectator    bool   `json:"spectator"`
-	GravatarHash string `json:"gravatarHash"`
-	PictureURL   string `json:"pictureUrl"`
+	ID                   string `json:"id"`
+	Name                 string `json:"name"`
+	Type                 string `json:"rank"`
+	Avatar               string `json:"avatar"`
+	Active               bool   `json:"active"`
+	Abandoned            bool   `json:"abandoned"`
+	Spectator            bool   `json:"spectator"`
+	Observer             bool   `json:"observer"`
+	IsPrimaryFacilitator bool   `json:"isPrimaryFacilitator"`
+	GravatarHash         string `json:"gravatarHash"`
+	PictureURL           string `json:"pictureUrl"`
 }
 
 // Poker aka arena
 type Poker struct {
-	ID                   string           `json:"id"`
-	Name                 string           `json:"name"`
-	Users                []*PokerUser     `json:"users"`
-	Stories              []*Story         `json:"plans"`
-	VotingLocked         bool             `json:"votingLocked"`
-	ActiveStoryID        string           `json:"activePlanId"`
-	PointValuesAllowed   []string         `json:"pointValuesAllowed"`
-	AutoFinishVoting     bool             `json:"autoFinishVoting"`
-	Facilitators         []string         `json:"leaders"`
-	PointAverageRounding string           `json:"pointAverageRounding"`
-	HideVoterIdentity    bool             `json:"hideVoterIdentity"`
-	JoinCode             string           `json:"joinCode"`
-	FacilitatorCode      string           `json:"leaderCode,omitempty"`
-	TeamID               string           `json:"teamId"`
-	TeamName             string           `json:"teamName"`
-	EstimationScaleID    string           `json:"estimationScaleId"`
-	EstimationScale      *EstimationScale `json:"estimationScale,omitempty"`
-	CreatedDate          time.Time        `json:"createdDate"`
-	UpdatedDate          time.Time        `json:"updatedDate"`
+	ID                      string       `json:"id"`
+	Name                    string       `json:"name"`
+	Users                   []*PokerUser `json:"users"`
+	Stories                 []*Story     `json:"plans"`
+	VotingLocked            bool         `json:"votingLocked"`
+	ActiveStoryID           string       `json:"activePlanId"`
+	PointValuesAllowed      []string     `json:"pointValuesAllowed"`
+	AutoFinishVoting        bool         `json:"autoFinishVoting"`
+	Facilitators            []string     `json:"leaders"`
+	PointAverageRounding    string       `json:"pointAverageRounding"`
+	HideVoterIdentity       bool         `json:"hideVoterIdentity"`
+	AutoFinalizeOnConsensus bool         `json:"autoFinalizeOnConsensus"`
+	MinParticipants         int          `json:"minParticipants"`
+	TimeBoxMinutes          int          `json:"timeBoxMinutes"`
+	// QuorumPercentage is the percentage of active participants that must vote before the story is
+	// finalized automatically, 0 disables the quorum
+	QuorumPercentage float64 `json:"quorumPercentage"`
+	// TimeBox is the active story's running time box, only populated when joining the game
+	TimeBox           *PokerTimeBox    `json:"timeBox,omitempty"`
+	JoinCode          string           `json:"joinCode"`
+	FacilitatorCode   string           `json:"leaderCode,omitempty"`
+	ObserverCode      string           `json:"observerCode,omitempty"`
+	TeamID            string           `json:"teamId"`
+	TeamName          string           `json:"teamName"`
+	EstimationScaleID string           `json:"estimationScaleId"`
+	EstimationScale   *EstimationScale `json:"estimationScale,omitempty"`
+	// StoryTypeScaleMap overrides the game's estimation scale by story type, keyed by the lowercase story type
+	StoryTypeScaleMap map[string]string `json:"storyTypeScaleMap"`
+	ActiveUserCount   int               `json:"activeUserCount,omitempty"`
+	Completion        *CompletionStats  `json:"completion,omitempty"`
+	CreatedDate       time.Time         `json:"createdDate"`
+	UpdatedDate       time.Time         `json:"updatedDate"`
+	ArchivedAt        *time.Time        `json:"archivedAt"`
+}
+
+// PokerFacilitator is a facilitator of a poker game, the primary facilitator owns the game
+// while the others co-facilitate it
+type PokerFacilitator struct {
+	UserID               string    `json:"userId"`
+	IsPrimaryFacilitator bool      `json:"isPrimaryFacilitator"`
+	CreatedDate          time.Time `json:"createdDate"`
 }
 
 // Vote structure
@@ -63,6 +91,143 @@ type Story struct {
 	VoteStartTime      time.Time `json:"voteStartTime"`
 	VoteEndTime        time.Time `json:"voteEndTime"`
 	Position           int32     `json:"position"`
+	// EstimateHint is the estimate carried over from the story's source when imported
+	EstimateHint string `json:"estimateHint,omitempty"`
+	// SprintName is the sprint the story is assigned to, empty when unassigned
+	SprintName      string     `json:"sprintName"`
+	SprintStartDate *time.Time `json:"sprintStartDate"`
+	SprintEndDate   *time.Time `json:"sprintEndDate"`
+	// Status is where the story is in planning, one of the StoryStatus values
+	Status string `json:"status"`
+	// Analysis is computed when the story is retrieved and is never stored
+	Analysis *StoryAnalysis `json:"analysis,omitempty"`
+	// FacilitatorNotes are only ever populated for the game facilitators
+	FacilitatorNotes *string `json:"facilitatorNotes"`
+	// EstimationScale is the scale the story is voted with, only populated for the active story
+	EstimationScale *EstimationScale `json:"estimationScale,omitempty"`
+}
+
+// StoryWithGame is a story along with the game it was estimated in
+type StoryWithGame struct {
+	Story
+	GameID   string `json:"gameId"`
+	GameName string `json:"gameName"`
+	// CreatedDate is when the game was created
+	CreatedDate time.Time `json:"createdDate"`
+}
+
+// InvalidStoryLink is a stored story link that isn't a valid http or https URL,
+// saved before story links were validated
+type InvalidStoryLink struct {
+	StoryID   string `json:"storyId"`
+	StoryName string `json:"storyName"`
+	GameID    string `json:"gameId"`
+	Link      string `json:"link"`
+}
+
+// StoryAnalysis holds story text metrics used as estimation hints
+type StoryAnalysis struct {
+	WordCount               int     `json:"wordCount"`
+	ReadabilityScore        float64 `json:"readabilityScore"`
+	AcceptanceCriteriaCount int     `json:"acceptanceCriteriaCount"`
+}
+
+// StoryVoteRound is a single round of voting on a poker game story, recorded when voting ends
+type StoryVoteRound struct {
+	StoryID       string    `json:"storyId"`
+	Round         int       `json:"round"`
+	Votes         []*Vote   `json:"votes"`
+	VoteStartTime time.Time `json:"voteStartTime"`
+	VoteEndTime   time.Time `json:"voteEndTime"`
+}
+
+// StoryAttachment is a file such as a screenshot or mockup attached to a poker story
+type StoryAttachment struct {
+	ID          string    `json:"id"`
+	StoryID     string    `json:"storyId"`
+	Filename    string    `json:"filename"`
+	ObjectKey   string    `json:"-"`
+	DownloadURL string    `json:"downloadUrl"`
+	ContentType string    `json:"contentType"`
+	Size        int64     `json:"size"`
+	UploadedBy  string    `json:"uploadedBy"`
+	CreatedDate time.Time `json:"createdDate"`
+}
+
+// Poker game access log event types
+const (
+	PokerAccessEventJoin  = "join"
+	PokerAccessEventLeave = "leave"
+)
+
+// PokerAccessLog is a user joining or leaving a poker game, the ip address is stored salted and hashed
+type PokerAccessLog struct {
+	ID          string    `json:"id"`
+	PokerID     string    `json:"pokerId"`
+	UserID      string    `json:"userId"`
+	UserName    string    `json:"userName"`
+	EventType   string    `json:"eventType"`
+	IPAddress   string    `json:"ipAddress"`
+	UserAgent   string    `json:"userAgent"`
+	CreatedDate time.Time `json:"createdDate"`
+}
+
+// PokerParticipantRecord is a poker game participant's attendance, join and leave times come from the access log
+type PokerParticipantRecord struct {
+	UserID       string     `json:"userId"`
+	Username     string     `json:"username"`
+	Email        string     `json:"email"`
+	JoinedAt     *time.Time `json:"joinedAt"`
+	LeftAt       *time.Time `json:"leftAt"`
+	VoteCount    int        `json:"voteCount"`
+	WasSpectator bool       `json:"wasSpectator"`
+}
+
+// GameStatistics summarizes how a poker game's stories were voted on
+type GameStatistics struct {
+	TotalStories             int           `json:"totalStories"`
+	AverageRoundsPerStory    float64       `json:"averageRoundsPerStory"`
+	AverageVotingDuration    time.Duration `json:"averageVotingDuration"`
+	HighestDisagreementStory *Story        `json:"highestDisagreementStory"`
+	ConsensusRate            float64       `json:"consensusRate"`
+}
+
+// CompletionStats summarizes how many of a poker game's stories have been finalized,
+// TotalPoints includes the estimate hint of stories not finalized yet
+type CompletionStats struct {
+	TotalStories         int     `json:"totalStories"`
+	FinalizedStories     int     `json:"finalizedStories"`
+	TotalPoints          float64 `json:"totalPoints"`
+	FinalizedPoints      float64 `json:"finalizedPoints"`
+	CompletionPercentage float64 `json:"completionPercentage"`
+}
+
+// ParticipantAccuracy is a poker game participant's estimation accuracy across the game's finalized stories
+type ParticipantAccuracy struct {
+	UserID           string  `json:"userId"`
+	Username         string  `json:"username"`
+	AverageDeviation float64 `json:"averageDeviation"`
+	StoryCount       int     `json:"storyCount"`
+}
+
+// DuplicationResult is the outcome of a bulk story import with reference_id deduplication
+type DuplicationResult struct {
+	InsertedCount int `json:"insertedCount"`
+	UpdatedCount  int `json:"updatedCount"`
+	SkippedCount  int `json:"skippedCount"`
+}
+
+// StoryPriorityUpdate is a single story priority change of a bulk priority update
+type StoryPriorityUpdate struct {
+	StoryID  string `json:"storyId" validate:"required,uuid"`
+	Priority int32  `json:"priority"`
+}
+
+// StoryPriorityResult is the outcome of a single story priority change of a bulk priority update
+type StoryPriorityResult struct {
+	StoryID string `json:"storyId"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
 }
 
 type EstimationScale struct {
@@ -79,3 +244,179 @@ type EstimationScale struct {
 	TeamID         string    `json:"teamId"`
 	DefaultScale   bool      `json:"defaultScale"`
 }
+
+// StoryEstimationScaleID gets the estimation scale ID stories of the type are voted with,
+// falling back to the game's estimation scale when the story type isn't overridden
+func (p *Poker) StoryEstimationScaleID(storyType string) string {
+	if scaleID := p.StoryTypeScaleMap[NormalizeStoryType(storyType)]; scaleID != "" {
+		return scaleID
+	}
+
+	return p.EstimationScaleID
+}
+
+// NormalizeStoryType lowercases the story type as story types are stored as they were displayed when added
+func NormalizeStoryType(storyType string) string {
+	return strings.ToLower(strings.TrimSpace(storyType))
+}
+
+// NormalizeStoryTypeScaleMap lowercases the story type keys dropping any without a story type or scale
+func NormalizeStoryTypeScaleMap(scaleMap map[string]string) map[string]string {
+	normalized := make(map[string]string, len(scaleMap))
+	for storyType, scaleID := range scaleMap {
+		if t := NormalizeStoryType(storyType); t != "" && scaleID != "" {
+			normalized[t] = scaleID
+		}
+	}
+
+	return normalized
+}
+
+// ErrNotEnoughParticipants is returned when voting is started with fewer active participants than the game requires
+var ErrNotEnoughParticipants = errors.New("INSUFFICIENT_PARTICIPANTS")
+
+// RedactFacilitatorNotes removes the facilitator notes from the stories
+func RedactFacilitatorNotes(stories []*Story) {
+	for _, story := range stories {
+		story.FacilitatorNotes = nil
+	}
+}
+
+// IsPrimaryFacilitator checks whether the user is the primary facilitator of the game
+func IsPrimaryFacilitator(facilitators []*PokerFacilitator, userID string) bool {
+	for _, facilitator := range facilitators {
+		if facilitator.UserID == userID {
+			return facilitator.IsPrimaryFacilitator
+		}
+	}
+
+	return false
+}
+
+// ValidatePrimaryFacilitatorTransfer makes sure the current user is the primary facilitator
+// and the new primary facilitator is already one of the game facilitators
+func ValidatePrimaryFacilitatorTransfer(facilitators []*PokerFacilitator, currentPrimaryID string, newPrimaryID string) error {
+	if currentPrimaryID == newPrimaryID {
+		return errors.New("ALREADY_PRIMARY_FACILITATOR")
+	}
+
+	var currentFound, newFound bool
+	for _, facilitator := range facilitators {
+		switch facilitator.UserID {
+		case currentPrimaryID:
+			if !facilitator.IsPrimaryFacilitator {
+				return errors.New("NOT_PRIMARY_FACILITATOR")
+			}
+			currentFound = true
+		case newPrimaryID:
+			newFound = true
+		}
+	}
+
+	if !currentFound || !newFound {
+		return errors.New("NOT_A_FACILITATOR")
+	}
+
+	return nil
+}
+
+// NextPrimaryFacilitator returns the longest serving facilitator other than the current primary,
+// or an empty string when there is no other facilitator to promote
+func NextPrimaryFacilitator(facilitators []*PokerFacilitator, primaryID string) string {
+	var next *PokerFacilitator
+	for _, facilitator := range facilitators {
+		if facilitator.UserID == primaryID {
+			continue
+		}
+		if next == nil || facilitator.CreatedDate.Before(next.CreatedDate) {
+			next = facilitator
+		}
+	}
+
+	if next == nil {
+		return ""
+	}
+
+	return next.UserID
+}
+
+// SplitStoryPriorityUpdates separates the priority updates for stories belonging to the game from the rest,
+// returning the applicable updates along with a result for every update in the order they were requested
+func SplitStoryPriorityUpdates(stories []*Story, updates []StoryPriorityUpdate) ([]StoryPriorityUpdate, []StoryPriorityResult) {
+	gameStories := make(map[string]struct{}, len(stories))
+	for _, story := range stories {
+		gameStories[story.ID] = struct{}{}
+	}
+
+	applicable := make([]StoryPriorityUpdate, 0, len(updates))
+	results := make([]StoryPriorityResult, 0, len(updates))
+	seen := make(map[string]struct{}, len(updates))
+	for _, update := range updates {
+		result := StoryPriorityResult{StoryID: update.StoryID}
+		if _, ok := gameStories[update.StoryID]; !ok {
+			result.Error = "STORY_NOT_FOUND"
+		} else if _, ok := seen[update.StoryID]; ok {
+			result.Error = "DUPLICATE_STORY"
+		} else {
+			seen[update.StoryID] = struct{}{}
+			applicable = append(applicable, update)
+			result.Success = true
+		}
+		results = append(results, result)
+	}
+
+	return applicable, results
+}
+
+// ActiveParticipantCount counts the game users that can vote, spectators and users no longer in the game are excluded
+func ActiveParticipantCount(users []*PokerUser) int {
+	count := 0
+	for _, user := range users {
+		if user.Active && !user.Abandoned && !user.Spectator {
+			count++
+		}
+	}
+
+	return count
+}
+
+// VoteQuorum gets the percentage of the game's active participants that voted and whether it meets the quorum
+// percentage, votes from spectators or users no longer in the game aren't counted and a quorum of 0 is always met
+func VoteQuorum(votes []*Vote, users []*PokerUser, quorumPercentage float64) (float64, bool) {
+	participants := make(map[string]struct{}, len(users))
+	for _, user := range users {
+		if user.Active && !user.Abandoned && !user.Spectator {
+			participants[user.ID] = struct{}{}
+		}
+	}
+
+	voted := 0
+	for _, vote := range votes {
+		if _, ok := participants[vote.UserID]; ok {
+			voted++
+		}
+	}
+
+	percentage := 0.0
+	if len(participants) > 0 {
+		percentage = float64(voted*100) / float64(len(participants))
+	}
+
+	return percentage, quorumPercentage <= 0 || percentage >= quorumPercentage
+}
+
+// ParsePointValue converts a point value to a number, returning false for special values such as ? or ☕️
+func ParsePointValue(value string) (float64, bool) {
+	value = strings.TrimSpace(value)
+	switch value {
+	case "1/2", "½":
+		return 0.5, true
+	}
+
+	points, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(points) || math.IsInf(points, 0) {
+		return 0, false
+	}
+
+	return points, true
+}
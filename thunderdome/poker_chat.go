@@ -0,0 +1,65 @@
+package thunderdome
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	// GameChatRateLimit is how many chat messages a user can send within GameChatRateWindow
+	GameChatRateLimit = 5
+	// GameChatRateWindow is the window the chat rate limit is counted over
+	GameChatRateWindow = 10 * time.Second
+	// GameChatMaxLength is the maximum length of a chat message
+	GameChatMaxLength = 1000
+)
+
+var (
+	// ErrGameChatRateLimited is returned when a user sends more than GameChatRateLimit messages within GameChatRateWindow
+	ErrGameChatRateLimited = errors.New("CHAT_RATE_LIMITED")
+	// ErrGameChatMessageRequired is returned when a chat message is empty once sanitized
+	ErrGameChatMessageRequired = errors.New("CHAT_MESSAGE_REQUIRED")
+	// ErrGameChatMessageTooLong is returned when a chat message is longer than GameChatMaxLength
+	ErrGameChatMessageTooLong = errors.New("CHAT_MESSAGE_TOO_LONG")
+	// ErrSpectatorChatNotAllowed is returned when a voting participant sends a spectator only message
+	ErrSpectatorChatNotAllowed = errors.New("SPECTATOR_CHAT_NOT_ALLOWED")
+)
+
+// GameChat is a chat message sent in a poker game, spectator only messages let spectators
+// talk amongst themselves and the facilitators without disrupting the voting participants
+type GameChat struct {
+	ID              string    `json:"id"`
+	GameID          string    `json:"gameId"`
+	UserID          string    `json:"userId"`
+	Message         string    `json:"message"`
+	IsSpectatorOnly bool      `json:"isSpectatorOnly"`
+	CreatedAt       time.Time `json:"createdAt"`
+}
+
+// CanSendGameChat checks whether the user can send the message, only spectators and facilitators
+// can send spectator only messages
+func CanSendGameChat(spectatorOnly bool, isSpectator bool, isFacilitator bool) error {
+	if spectatorOnly && !isSpectator && !isFacilitator {
+		return ErrSpectatorChatNotAllowed
+	}
+
+	return nil
+}
+
+// CanSeeGameChat checks whether the user can see the chat message, spectator only messages
+// are only seen by spectators and facilitators while facilitators see every message
+func CanSeeGameChat(chat GameChat, isSpectator bool, isFacilitator bool) bool {
+	return !chat.IsSpectatorOnly || isSpectator || isFacilitator
+}
+
+// VisibleGameChat returns the chat messages the user can see keeping their order
+func VisibleGameChat(messages []GameChat, isSpectator bool, isFacilitator bool) []GameChat {
+	visible := make([]GameChat, 0, len(messages))
+	for _, chat := range messages {
+		if CanSeeGameChat(chat, isSpectator, isFacilitator) {
+			visible = append(visible, chat)
+		}
+	}
+
+	return visible
+}
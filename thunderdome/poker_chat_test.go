@@ -0,0 +1,72 @@
+package thunderdome
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestCanSeeGameChat makes sure spectator only messages are hidden from voting participants
+func TestCanSeeGameChat(t *testing.T) {
+	everyone := GameChat{ID: "1", Message: "hello"}
+	spectatorOnly := GameChat{ID: "2", Message: "psst", IsSpectatorOnly: true}
+
+	tests := []struct {
+		name          string
+		chat          GameChat
+		isSpectator   bool
+		isFacilitator bool
+		expected      bool
+	}{
+		{name: "participant sees message to everyone", chat: everyone, expected: true},
+		{name: "participant doesn't see spectator message", chat: spectatorOnly, expected: false},
+		{name: "spectator sees message to everyone", chat: everyone, isSpectator: true, expected: true},
+		{name: "spectator sees spectator message", chat: spectatorOnly, isSpectator: true, expected: true},
+		{name: "facilitator sees spectator message", chat: spectatorOnly, isFacilitator: true, expected: true},
+		{name: "spectating facilitator sees spectator message", chat: spectatorOnly, isSpectator: true, isFacilitator: true, expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CanSeeGameChat(tt.chat, tt.isSpectator, tt.isFacilitator); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+// TestVisibleGameChat makes sure the chat history is filtered by visibility keeping its order
+func TestVisibleGameChat(t *testing.T) {
+	messages := []GameChat{
+		{ID: "1", Message: "welcome"},
+		{ID: "2", Message: "they'll pick 8", IsSpectatorOnly: true},
+		{ID: "3", Message: "next story"},
+	}
+
+	participant := VisibleGameChat(messages, false, false)
+	if len(participant) != 2 || participant[0].ID != "1" || participant[1].ID != "3" {
+		t.Errorf("expected participants to see messages 1 and 3, got %+v", participant)
+	}
+
+	if spectator := VisibleGameChat(messages, true, false); len(spectator) != 3 {
+		t.Errorf("expected spectators to see all 3 messages, got %d", len(spectator))
+	}
+	if facilitator := VisibleGameChat(messages, false, true); len(facilitator) != 3 {
+		t.Errorf("expected facilitators to see all 3 messages, got %d", len(facilitator))
+	}
+}
+
+// TestCanSendGameChat makes sure voting participants can't send spectator only messages
+func TestCanSendGameChat(t *testing.T) {
+	if err := CanSendGameChat(false, false, false); err != nil {
+		t.Errorf("expected participants to send messages to everyone, got %v", err)
+	}
+	if err := CanSendGameChat(true, false, false); !errors.Is(err, ErrSpectatorChatNotAllowed) {
+		t.Errorf("expected %v for a participant's spectator message, got %v", ErrSpectatorChatNotAllowed, err)
+	}
+	if err := CanSendGameChat(true, true, false); err != nil {
+		t.Errorf("expected spectators to send spectator messages, got %v", err)
+	}
+	if err := CanSendGameChat(true, false, true); err != nil {
+		t.Errorf("expected facilitators to send spectator messages, got %v", err)
+	}
+}
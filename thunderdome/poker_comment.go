@@ -0,0 +1,72 @@
+package thunderdome
+
+import "time"
+
+// PokerStoryComment is a comment on a poker story capturing the discussion during estimation,
+// comments replying to another comment are nested in its replies
+type PokerStoryComment struct {
+	ID        string              `json:"id"`
+	PokerID   string              `json:"pokerId"`
+	StoryID   string              `json:"storyId"`
+	ParentID  string              `json:"parentId,omitempty"`
+	AuthorID  string              `json:"authorId"`
+	Body      string              `json:"body"`
+	CreatedAt time.Time           `json:"createdAt"`
+	UpdatedAt time.Time           `json:"updatedAt"`
+	Replies   []PokerStoryComment `json:"replies"`
+}
+
+// ThreadPokerStoryComments nests the replies under the comments they reply to keeping the order of the comments,
+// replies to a comment that isn't in the list are kept at the top level
+func ThreadPokerStoryComments(comments []PokerStoryComment) []PokerStoryComment {
+	byID := make(map[string]int, len(comments))
+	for i, c := range comments {
+		byID[c.ID] = i
+	}
+
+	children := make(map[string][]int)
+	roots := make([]int, 0, len(comments))
+	for i, c := range comments {
+		if _, ok := byID[c.ParentID]; c.ParentID != "" && ok && c.ParentID != c.ID {
+			children[c.ParentID] = append(children[c.ParentID], i)
+			continue
+		}
+		roots = append(roots, i)
+	}
+
+	var build func(i int, visited map[string]bool) PokerStoryComment
+	build = func(i int, visited map[string]bool) PokerStoryComment {
+		comment := comments[i]
+		visited[comment.ID] = true
+		comment.Replies = make([]PokerStoryComment, 0, len(children[comment.ID]))
+		for _, child := range children[comment.ID] {
+			if visited[comments[child].ID] {
+				continue
+			}
+			comment.Replies = append(comment.Replies, build(child, visited))
+		}
+
+		return comment
+	}
+
+	threaded := make([]PokerStoryComment, 0, len(roots))
+	visited := make(map[string]bool, len(comments))
+	for _, i := range roots {
+		threaded = append(threaded, build(i, visited))
+	}
+
+	return threaded
+}
+
+// FlattenPokerStoryComments lists the comments and their replies depth first, the reverse of ThreadPokerStoryComments
+func FlattenPokerStoryComments(comments []PokerStoryComment) []PokerStoryComment {
+	flat := make([]PokerStoryComment, 0, len(comments))
+	for _, c := range comments {
+		replies := c.Replies
+		c.Replies = nil
+		flat = append(flat, c)
+		flat = append(flat, FlattenPokerStoryComments(replies)...)
+	}
+
+	return flat
+}
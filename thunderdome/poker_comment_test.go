@@ -0,0 +1,70 @@
+package thunderdome
+
+import "testing"
+
+// TestThreadPokerStoryCommentsNestedReplies makes sure replies are nested under their parent at any depth
+func TestThreadPokerStoryCommentsNestedReplies(t *testing.T) {
+	comments := []PokerStoryComment{
+		{ID: "a", Body: "does this include the api?"},
+		{ID: "b", Body: "is there a design?"},
+		{ID: "a1", ParentID: "a", Body: "yes"},
+		{ID: "a1x", ParentID: "a1", Body: "then it's bigger"},
+		{ID: "a2", ParentID: "a", Body: "only the read endpoints"},
+		{ID: "a1x1", ParentID: "a1x", Body: "agreed"},
+	}
+
+	threaded := ThreadPokerStoryComments(comments)
+
+	if len(threaded) != 2 || threaded[0].ID != "a" || threaded[1].ID != "b" {
+		t.Fatalf("expected top level comments a and b, got %+v", threaded)
+	}
+	a := threaded[0]
+	if len(a.Replies) != 2 || a.Replies[0].ID != "a1" || a.Replies[1].ID != "a2" {
+		t.Fatalf("expected a to have replies a1 and a2 in order, got %+v", a.Replies)
+	}
+	a1 := a.Replies[0]
+	if len(a1.Replies) != 1 || a1.Replies[0].ID != "a1x" {
+		t.Fatalf("expected a1 to have reply a1x, got %+v", a1.Replies)
+	}
+	if len(a1.Replies[0].Replies) != 1 || a1.Replies[0].Replies[0].ID != "a1x1" {
+		t.Fatalf("expected a1x to have reply a1x1, got %+v", a1.Replies[0].Replies)
+	}
+	if threaded[1].Replies == nil || len(threaded[1].Replies) != 0 {
+		t.Errorf("expected b to have an empty list of replies, got %+v", threaded[1].Replies)
+	}
+}
+
+// TestThreadPokerStoryCommentsMissingParent makes sure replies to a comment that isn't listed aren't lost
+func TestThreadPokerStoryCommentsMissingParent(t *testing.T) {
+	threaded := ThreadPokerStoryComments([]PokerStoryComment{
+		{ID: "a"},
+		{ID: "orphan", ParentID: "deleted"},
+	})
+
+	if len(threaded) != 2 || threaded[1].ID != "orphan" {
+		t.Errorf("expected the orphaned reply at the top level, got %+v", threaded)
+	}
+}
+
+// TestFlattenPokerStoryComments makes sure flattening a thread lists every comment once, parents before replies
+func TestFlattenPokerStoryComments(t *testing.T) {
+	comments := []PokerStoryComment{
+		{ID: "a"},
+		{ID: "a1", ParentID: "a"},
+		{ID: "a1x", ParentID: "a1"},
+		{ID: "b"},
+		{ID: "a2", ParentID: "a"},
+	}
+
+	flat := FlattenPokerStoryComments(ThreadPokerStoryComments(comments))
+
+	expected := []string{"a", "a1", "a1x", "a2", "b"}
+	if len(flat) != len(expected) {
+		t.Fatalf("expected %d comments, got %d", len(expected), len(flat))
+	}
+	for i, id := range expected {
+		if flat[i].ID != id {
+			t.Errorf("expected comment %d to be %s, got %s", i, id, flat[i].ID)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package thunderdome
+
+import "slices"
+
+// abstainVoteValues are special point values that mean the participant is not estimating
+var abstainVoteValues = []string{"?", "☕️", "☕"}
+
+// VoteConsensus returns true and the consensus value when all non-abstain votes are identical,
+// when allowed points are given votes not in them are treated as abstaining
+func VoteConsensus(votes []*Vote, allowedPoints []string) (bool, string) {
+	consensus := ""
+	for _, vote := range votes {
+		if vote.VoteValue == "" || slices.Contains(abstainVoteValues, vote.VoteValue) {
+			continue
+		}
+		if len(allowedPoints) > 0 && !slices.Contains(allowedPoints, vote.VoteValue) {
+			continue
+		}
+		if consensus == "" {
+			consensus = vote.VoteValue
+		} else if vote.VoteValue != consensus {
+			return false, ""
+		}
+	}
+
+	return consensus != "", consensus
+}
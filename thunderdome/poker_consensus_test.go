@@ -0,0 +1,71 @@
+package thunderdome
+
+import "testing"
+
+var consensusPoints = []string{"0", "1/2", "1", "2", "3", "5", "8", "13", "?", "☕️"}
+
+// TestVoteConsensus makes sure abstain and disallowed votes are ignored when checking for consensus
+func TestVoteConsensus(t *testing.T) {
+	tests := []struct {
+		name          string
+		votes         []string
+		wantConsensus bool
+		wantValue     string
+	}{
+		{
+			name:          "all same",
+			votes:         []string{"5", "5", "5"},
+			wantConsensus: true,
+			wantValue:     "5",
+		},
+		{
+			name:          "one outlier",
+			votes:         []string{"5", "5", "8"},
+			wantConsensus: false,
+			wantValue:     "",
+		},
+		{
+			name:          "all abstain",
+			votes:         []string{"?", "☕️", "?"},
+			wantConsensus: false,
+			wantValue:     "",
+		},
+		{
+			name:          "same with abstains",
+			votes:         []string{"1/2", "?", "1/2", "☕️"},
+			wantConsensus: true,
+			wantValue:     "1/2",
+		},
+		{
+			name:          "no votes",
+			votes:         []string{},
+			wantConsensus: false,
+			wantValue:     "",
+		},
+		{
+			name:          "coffee without variation selector abstains",
+			votes:         []string{"8", "☕"},
+			wantConsensus: true,
+			wantValue:     "8",
+		},
+		{
+			name:          "vote not allowed is ignored",
+			votes:         []string{"3", "3", "100"},
+			wantConsensus: true,
+			wantValue:     "3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			votes := make([]*Vote, 0, len(tt.votes))
+			for _, v := range tt.votes {
+				votes = append(votes, &Vote{VoteValue: v})
+			}
+			consensus, value := VoteConsensus(votes, consensusPoints)
+			if consensus != tt.wantConsensus || value != tt.wantValue {
+				t.Errorf("expected (%v, %q), got (%v, %q)", tt.wantConsensus, tt.wantValue, consensus, value)
+			}
+		})
+	}
+}
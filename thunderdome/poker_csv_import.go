@@ -0,0 +1,153 @@
+package thunderdome
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/validation"
+)
+
+// StoryCSVImportMaxRows caps the stories imported from a single csv file
+const StoryCSVImportMaxRows = 1000
+
+// storyCSVFields are the story fields csv columns can be mapped to
+var storyCSVFields = map[string]bool{
+	"name":                true,
+	"type":                true,
+	"reference_id":        true,
+	"link":                true,
+	"description":         true,
+	"acceptance_criteria": true,
+	"priority":            true,
+}
+
+var (
+	// ErrStoryCSVInvalidMapping is returned when the column mapping targets a field stories can't be imported into
+	ErrStoryCSVInvalidMapping = errors.New("INVALID_COLUMN_MAPPING")
+	// ErrStoryCSVMissingName is returned when no csv column is mapped to the story name
+	ErrStoryCSVMissingName = errors.New("MISSING_NAME_COLUMN")
+	// ErrStoryCSVInvalid is returned when the file can't be read as csv
+	ErrStoryCSVInvalid = errors.New("INVALID_CSV")
+	// ErrStoryCSVTooManyRows is returned when the file has more than StoryCSVImportMaxRows rows
+	ErrStoryCSVTooManyRows = errors.New("TOO_MANY_ROWS")
+)
+
+// StoryCSVImportRowError is a csv row that couldn't be imported as a story
+type StoryCSVImportRowError struct {
+	Row    int    `json:"row"`
+	Reason string `json:"reason"`
+}
+
+// StoryCSVImportRowErrors is returned along with the imported stories when some csv rows couldn't be imported
+type StoryCSVImportRowErrors []StoryCSVImportRowError
+
+func (e StoryCSVImportRowErrors) Error() string {
+	return fmt.Sprintf("%d CSV_ROWS_NOT_IMPORTED", len(e))
+}
+
+// StoryCSVImportResult is the outcome of a poker story csv import
+type StoryCSVImportResult struct {
+	Total       int                      `json:"total"`
+	Imported    int                      `json:"imported"`
+	Failed      int                      `json:"failed"`
+	Stories     []*Story                 `json:"stories"`
+	Errors      []StoryCSVImportRowError `json:"errors"`
+	Duplication *DuplicationResult       `json:"duplication"`
+}
+
+// ParseStoryCSV parses the csv rows into stories, columnMapping maps csv header names to story fields
+// (e.g. "Story Name" to "name"), headers are matched ignoring case. Without a mapping headers named after
+// a story field are used as is, other columns are ignored. Rows that can't be imported are returned separately
+func ParseStoryCSV(csvData []byte, columnMapping map[string]string) ([]*Story, StoryCSVImportRowErrors, error) {
+	mapping := make(map[string]string, len(columnMapping))
+	for header, field := range columnMapping {
+		field = strings.ToLower(strings.TrimSpace(field))
+		if !storyCSVFields[field] {
+			return nil, nil, ErrStoryCSVInvalidMapping
+		}
+		mapping[strings.ToLower(strings.TrimSpace(header))] = field
+	}
+
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(csvData, []byte("\ufeff"))))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil, ErrStoryCSVMissingName
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrStoryCSVInvalid, err)
+	}
+
+	columns := make(map[string]int)
+	for i, col := range header {
+		col = strings.ToLower(strings.TrimSpace(col))
+		field := col
+		if len(mapping) > 0 {
+			field = mapping[col]
+		}
+		if _, mapped := columns[field]; storyCSVFields[field] && !mapped {
+			columns[field] = i
+		}
+	}
+	if _, ok := columns["name"]; !ok {
+		return nil, nil, ErrStoryCSVMissingName
+	}
+
+	stories := make([]*Story, 0)
+	rowErrors := make(StoryCSVImportRowErrors, 0)
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", ErrStoryCSVInvalid, err)
+		}
+		if len(stories)+len(rowErrors) == StoryCSVImportMaxRows {
+			return nil, nil, ErrStoryCSVTooManyRows
+		}
+
+		value := func(field string) string {
+			i, ok := columns[field]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		story := &Story{
+			Name:               value("name"),
+			Type:               value("type"),
+			ReferenceID:        value("reference_id"),
+			Link:               value("link"),
+			Description:        value("description"),
+			AcceptanceCriteria: value("acceptance_criteria"),
+		}
+		if story.Name == "" {
+			rowErrors = append(rowErrors, StoryCSVImportRowError{Row: line, Reason: "MISSING_NAME"})
+			continue
+		}
+		if validation.ValidateURL(story.Link) != nil {
+			rowErrors = append(rowErrors, StoryCSVImportRowError{Row: line, Reason: "INVALID_LINK"})
+			continue
+		}
+		if priority := value("priority"); priority != "" {
+			p, err := strconv.ParseInt(priority, 10, 32)
+			if err != nil {
+				rowErrors = append(rowErrors, StoryCSVImportRowError{Row: line, Reason: "INVALID_PRIORITY"})
+				continue
+			}
+			story.Priority = int32(p)
+		}
+		stories = append(stories, story)
+	}
+
+	return stories, rowErrors, nil
+}
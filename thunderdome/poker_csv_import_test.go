@@ -0,0 +1,127 @@
+package thunderdome
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestParseStoryCSV makes sure csv columns are mapped to story fields with extra columns ignored,
+// files without a name column are rejected and a UTF-8 BOM doesn't break header matching
+func TestParseStoryCSV(t *testing.T) {
+	mapping := map[string]string{"Story Name": "name", "Details": "description", "Rank": "priority"}
+
+	tests := []struct {
+		name      string
+		csv       string
+		mapping   map[string]string
+		stories   []Story
+		rowErrors []StoryCSVImportRowError
+		wantErr   error
+	}{
+		{
+			name:    "mapped columns with extra columns",
+			csv:     "Story Name,Sprint,Details,Rank,Owner\nLogin,4,<p>Users can log in</p>,2,Ann\nLogout,4,,,Bob\n",
+			mapping: mapping,
+			stories: []Story{
+				{Name: "Login", Description: "<p>Users can log in</p>", Priority: 2},
+				{Name: "Logout"},
+			},
+		},
+		{
+			name:    "headers named after fields without a mapping",
+			csv:     "name,reference_id,acceptance_criteria,notes\nLogin,TD-1,Works,ignored\n",
+			stories: []Story{{Name: "Login", ReferenceID: "TD-1", AcceptanceCriteria: "Works"}},
+		},
+		{
+			name:    "BOM prefixed header",
+			csv:     "\ufeffStory Name,Details\nLogin,Users can log in\n",
+			mapping: mapping,
+			stories: []Story{{Name: "Login", Description: "Users can log in"}},
+		},
+		{
+			name:    "headers matched ignoring case",
+			csv:     "STORY NAME\nLogin\n",
+			mapping: mapping,
+			stories: []Story{{Name: "Login"}},
+		},
+		{
+			name:    "row errors",
+			csv:     "Story Name,Rank\nLogin,high\n,1\nLogout,3\n",
+			mapping: mapping,
+			stories: []Story{{Name: "Logout", Priority: 3}},
+			rowErrors: []StoryCSVImportRowError{
+				{Row: 2, Reason: "INVALID_PRIORITY"},
+				{Row: 3, Reason: "MISSING_NAME"},
+			},
+		},
+		{
+			name:      "invalid link",
+			csv:       "name,link\nLogin,javascript:alert(1)\nLogout,https://thunderdome.dev/stories/logout\n",
+			stories:   []Story{{Name: "Logout", Link: "https://thunderdome.dev/stories/logout"}},
+			rowErrors: []StoryCSVImportRowError{{Row: 2, Reason: "INVALID_LINK"}},
+		},
+		{
+			name:    "missing name column",
+			csv:     "Details,Rank\nUsers can log in,1\n",
+			mapping: mapping,
+			wantErr: ErrStoryCSVMissingName,
+		},
+		{
+			name:    "mapped name header not in file",
+			csv:     "Title,Details\nLogin,Users can log in\n",
+			mapping: mapping,
+			wantErr: ErrStoryCSVMissingName,
+		},
+		{
+			name:    "empty file",
+			csv:     "",
+			wantErr: ErrStoryCSVMissingName,
+		},
+		{
+			name:    "mapping to unknown field",
+			csv:     "Story Name,Points\nLogin,5\n",
+			mapping: map[string]string{"Story Name": "name", "Points": "points"},
+			wantErr: ErrStoryCSVInvalidMapping,
+		},
+		{
+			name:    "malformed csv",
+			csv:     "name\n\"Login\n",
+			wantErr: ErrStoryCSVInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stories, rowErrors, err := ParseStoryCSV([]byte(tt.csv), tt.mapping)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(stories) != len(tt.stories) {
+				t.Fatalf("expected %d stories, got %d", len(tt.stories), len(stories))
+			}
+			for i, want := range tt.stories {
+				got := stories[i]
+				if got.Name != want.Name || got.Description != want.Description || got.ReferenceID != want.ReferenceID ||
+					got.AcceptanceCriteria != want.AcceptanceCriteria || got.Priority != want.Priority || got.Link != want.Link {
+					t.Errorf("story %d: expected %+v, got %+v", i, want, *got)
+				}
+			}
+
+			if len(rowErrors) != len(tt.rowErrors) {
+				t.Fatalf("expected %d row errors, got %v", len(tt.rowErrors), rowErrors)
+			}
+			for i, want := range tt.rowErrors {
+				if rowErrors[i] != want {
+					t.Errorf("row error %d: expected %+v, got %+v", i, want, rowErrors[i])
+				}
+			}
+		})
+	}
+}
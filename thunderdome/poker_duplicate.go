@@ -0,0 +1,98 @@
+package thunderdome
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+)
+
+// DefaultDuplicateStoryThreshold is the name similarity at or above which stories are considered duplicates
+const DefaultDuplicateStoryThreshold = 0.8
+
+// ErrInvalidDuplicateThreshold is returned when the duplicate story threshold isn't a number above 0 and at most 1
+var ErrInvalidDuplicateThreshold = errors.New("INVALID_DUPLICATE_THRESHOLD")
+
+// StorySimilarity is the name similarity of two stories of the same game
+type StorySimilarity struct {
+	StoryID      string
+	OtherStoryID string
+	Similarity   float64
+}
+
+// DuplicateGroup is a group of a game's stories with similar names
+type DuplicateGroup struct {
+	StoryIDs   []string `json:"storyIds"`
+	Similarity float64  `json:"similarity"`
+}
+
+// ParseDuplicateThreshold validates the duplicate story threshold, an empty threshold uses the default
+func ParseDuplicateThreshold(threshold string) (float64, error) {
+	if threshold == "" {
+		return DefaultDuplicateStoryThreshold, nil
+	}
+
+	t, err := strconv.ParseFloat(threshold, 64)
+	if err != nil || t <= 0 || t > 1 {
+		return 0, ErrInvalidDuplicateThreshold
+	}
+
+	return t, nil
+}
+
+// GroupDuplicateStories groups the similar story pairs, stories similar through another story share its group.
+// Each group's similarity is its most similar pair, groups are sorted by similarity descending
+func GroupDuplicateStories(pairs []StorySimilarity) []DuplicateGroup {
+	parents := make(map[string]string)
+	var find func(storyID string) string
+	find = func(storyID string) string {
+		parent, ok := parents[storyID]
+		if !ok {
+			parents[storyID] = storyID
+			return storyID
+		}
+		if parent == storyID {
+			return storyID
+		}
+		root := find(parent)
+		parents[storyID] = root
+		return root
+	}
+
+	for _, pair := range pairs {
+		storyRoot, otherRoot := find(pair.StoryID), find(pair.OtherStoryID)
+		if storyRoot != otherRoot {
+			parents[otherRoot] = storyRoot
+		}
+	}
+
+	groups := make(map[string]*DuplicateGroup)
+	for _, pair := range pairs {
+		root := find(pair.StoryID)
+		group, ok := groups[root]
+		if !ok {
+			group = &DuplicateGroup{}
+			groups[root] = group
+		}
+		if pair.Similarity > group.Similarity {
+			group.Similarity = pair.Similarity
+		}
+	}
+	for storyID := range parents {
+		group := groups[find(storyID)]
+		group.StoryIDs = append(group.StoryIDs, storyID)
+	}
+
+	duplicates := make([]DuplicateGroup, 0, len(groups))
+	for _, group := range groups {
+		sort.Strings(group.StoryIDs)
+		duplicates = append(duplicates, *group)
+	}
+	sort.Slice(duplicates, func(i, j int) bool {
+		if duplicates[i].Similarity != duplicates[j].Similarity {
+			return duplicates[i].Similarity > duplicates[j].Similarity
+		}
+		return duplicates[i].StoryIDs[0] < duplicates[j].StoryIDs[0]
+	})
+
+	return duplicates
+}
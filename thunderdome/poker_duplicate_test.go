@@ -0,0 +1,122 @@
+package thunderdome
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+// trigrams gets the pg_trgm trigrams of the text, each lower cased word padded with two spaces before and one after
+func trigrams(text string) map[string]bool {
+	grams := make(map[string]bool)
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		padded := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			grams[string(padded[i:i+3])] = true
+		}
+	}
+
+	return grams
+}
+
+// trigramSimilarity mirrors pg_trgm's similarity() so the grouping can be tested without a database
+func trigramSimilarity(a string, b string) float64 {
+	aGrams, bGrams := trigrams(a), trigrams(b)
+	shared := 0
+	for gram := range aGrams {
+		if bGrams[gram] {
+			shared++
+		}
+	}
+	total := len(aGrams) + len(bGrams) - shared
+	if total == 0 {
+		return 0
+	}
+
+	return float64(shared) / float64(total)
+}
+
+// TestGroupDuplicateStories seeds stories imported more than once with slightly different names
+// and makes sure they're grouped at the default threshold, most similar group first
+func TestGroupDuplicateStories(t *testing.T) {
+	stories := []Story{
+		{ID: "1", Name: "User login page"},
+		{ID: "2", Name: "User login pages"},
+		{ID: "3", Name: "Export estimates to CSV"},
+		{ID: "4", Name: "export estimates to csv"},
+		{ID: "5", Name: "Dark mode"},
+		{ID: "6", Name: "Password reset email"},
+		{ID: "7", Name: "User logout page"},
+	}
+
+	var pairs []StorySimilarity
+	for i := range stories {
+		for j := i + 1; j < len(stories); j++ {
+			if similarity := trigramSimilarity(stories[i].Name, stories[j].Name); similarity >= DefaultDuplicateStoryThreshold {
+				pairs = append(pairs, StorySimilarity{StoryID: stories[i].ID, OtherStoryID: stories[j].ID, Similarity: similarity})
+			}
+		}
+	}
+
+	groups := GroupDuplicateStories(pairs)
+	if len(groups) != 2 {
+		t.Fatalf("expected 2 duplicate groups, got %+v", groups)
+	}
+	if strings.Join(groups[0].StoryIDs, ",") != "3,4" || groups[0].Similarity != 1 {
+		t.Errorf("expected the case only differing stories first, got %+v", groups[0])
+	}
+	if strings.Join(groups[1].StoryIDs, ",") != "1,2" || groups[1].Similarity >= 1 {
+		t.Errorf("expected the pluralized stories second, got %+v", groups[1])
+	}
+}
+
+// TestGroupDuplicateStoriesTransitive makes sure stories similar through another story share a group
+func TestGroupDuplicateStoriesTransitive(t *testing.T) {
+	groups := GroupDuplicateStories([]StorySimilarity{
+		{StoryID: "a", OtherStoryID: "b", Similarity: 0.85},
+		{StoryID: "c", OtherStoryID: "d", Similarity: 0.9},
+		{StoryID: "b", OtherStoryID: "e", Similarity: 0.95},
+	})
+	if len(groups) != 2 {
+		t.Fatalf("expected 2 duplicate groups, got %+v", groups)
+	}
+	if strings.Join(groups[0].StoryIDs, ",") != "a,b,e" || groups[0].Similarity != 0.95 {
+		t.Errorf("expected stories a, b and e grouped first, got %+v", groups[0])
+	}
+	if strings.Join(groups[1].StoryIDs, ",") != "c,d" {
+		t.Errorf("expected stories c and d grouped, got %+v", groups[1])
+	}
+
+	if groups := GroupDuplicateStories(nil); len(groups) != 0 {
+		t.Errorf("expected no groups without similar stories, got %+v", groups)
+	}
+}
+
+func TestParseDuplicateThreshold(t *testing.T) {
+	tests := []struct {
+		threshold string
+		want      float64
+		wantErr   error
+	}{
+		{threshold: "", want: DefaultDuplicateStoryThreshold},
+		{threshold: "0.6", want: 0.6},
+		{threshold: "1", want: 1},
+		{threshold: "0", wantErr: ErrInvalidDuplicateThreshold},
+		{threshold: "1.5", wantErr: ErrInvalidDuplicateThreshold},
+		{threshold: "high", wantErr: ErrInvalidDuplicateThreshold},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseDuplicateThreshold(tt.threshold)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("threshold %q expected error %v, got %v", tt.threshold, tt.wantErr, err)
+		}
+		if got != tt.want {
+			t.Errorf("threshold %q expected %v, got %v", tt.threshold, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package thunderdome
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Poker game event types, each state changing game operation appends one of these to the game's event log
+const (
+	GameEventCreated        = "game_created"
+	GameEventUpdated        = "game_updated"
+	GameEventStoryAdded     = "story_added"
+	GameEventStoryActivated = "story_activated"
+	GameEventVoteCast       = "vote_cast"
+	GameEventVoteRetracted  = "vote_retracted"
+	GameEventVotingEnded    = "voting_ended"
+	GameEventStorySkipped   = "story_skipped"
+	GameEventStoryFinalized = "story_finalized"
+	GameEventStoryDeleted   = "story_deleted"
+)
+
+var (
+	// ErrGameEventsNotFound is returned when the game has no events to replay
+	ErrGameEventsNotFound = errors.New("GAME_EVENTS_NOT_FOUND")
+	// ErrGameEventSequenceGap is returned when the game's events aren't numbered contiguously from 1
+	ErrGameEventSequenceGap = errors.New("GAME_EVENT_SEQUENCE_GAP")
+)
+
+// GameEvent is an entry in a poker game's event log
+type GameEvent struct {
+	ID             string          `json:"id"`
+	PokerID        string          `json:"pokerId"`
+	SequenceNumber int64           `json:"sequenceNumber"`
+	EventType      string          `json:"eventType"`
+	Payload        json.RawMessage `json:"payload"`
+	CreatedAt      time.Time       `json:"createdAt"`
+}
+
+// GameSettingsEvent is the payload of the game created and game updated events
+type GameSettingsEvent struct {
+	Name                    string            `json:"name"`
+	PointValuesAllowed      []string          `json:"pointValuesAllowed"`
+	AutoFinishVoting        bool              `json:"autoFinishVoting"`
+	PointAverageRounding    string            `json:"pointAverageRounding"`
+	HideVoterIdentity       bool              `json:"hideVoterIdentity"`
+	AutoFinalizeOnConsensus bool              `json:"autoFinalizeOnConsensus"`
+	MinParticipants         int               `json:"minParticipants"`
+	TimeBoxMinutes          int               `json:"timeBoxMinutes"`
+	QuorumPercentage        float64           `json:"quorumPercentage"`
+	StoryTypeScaleMap       map[string]string `json:"storyTypeScaleMap"`
+}
+
+// GameStoryEvent is the payload of the story events
+type GameStoryEvent struct {
+	StoryID string `json:"storyId"`
+	Name    string `json:"name,omitempty"`
+	UserID  string `json:"userId,omitempty"`
+	Vote    string `json:"vote,omitempty"`
+	Points  string `json:"points,omitempty"`
+}
+
+// ReplayGameEvents reconstructs the game's state by applying its events in sequence number order,
+// the events must start at sequence 1 with the game created event and have no gaps
+func ReplayGameEvents(pokerID string, events []*GameEvent) (*Poker, error) {
+	if len(events) == 0 {
+		return nil, ErrGameEventsNotFound
+	}
+
+	ordered := make([]*GameEvent, len(events))
+	copy(ordered, events)
+	sort.Slice(ordered, func(i, j int) bool {
+		return ordered[i].SequenceNumber < ordered[j].SequenceNumber
+	})
+
+	game := &Poker{
+		ID:           pokerID,
+		VotingLocked: true,
+		Stories:      make([]*Story, 0),
+	}
+	for i, event := range ordered {
+		if event.SequenceNumber != int64(i+1) {
+			return nil, ErrGameEventSequenceGap
+		}
+		if i == 0 && event.EventType != GameEventCreated {
+			return nil, fmt.Errorf("first game event must be %s, got %s", GameEventCreated, event.EventType)
+		}
+		if err := applyGameEvent(game, event); err != nil {
+			return nil, fmt.Errorf("replay game event %d error: %v", event.SequenceNumber, err)
+		}
+	}
+
+	return game, nil
+}
+
+// applyGameEvent applies a single event to the game the same way the game's database operations do
+func applyGameEvent(game *Poker, event *GameEvent) error {
+	switch event.EventType {
+	case GameEventCreated, GameEventUpdated:
+		var settings GameSettingsEvent
+		if err := json.Unmarshal(event.Payload, &settings); err != nil {
+			return err
+		}
+		game.Name = settings.Name
+		game.PointValuesAllowed = settings.PointValuesAllowed
+		game.AutoFinishVoting = settings.AutoFinishVoting
+		game.PointAverageRounding = settings.PointAverageRounding
+		game.HideVoterIdentity = settings.HideVoterIdentity
+		game.AutoFinalizeOnConsensus = settings.AutoFinalizeOnConsensus
+		game.MinParticipants = settings.MinParticipants
+		game.TimeBoxMinutes = settings.TimeBoxMinutes
+		game.QuorumPercentage = settings.QuorumPercentage
+		game.StoryTypeScaleMap = settings.StoryTypeScaleMap
+		return nil
+	}
+
+	var payload GameStoryEvent
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return err
+	}
+
+	if event.EventType == GameEventStoryAdded {
+		game.Stories = append(game.Stories, &Story{
+			ID:    payload.StoryID,
+			Name:  payload.Name,
+			Votes: make([]*Vote, 0),
+		})
+		return nil
+	}
+
+	storyIndex := -1
+	for i, s := range game.Stories {
+		if s.ID == payload.StoryID {
+			storyIndex = i
+			break
+		}
+	}
+	if storyIndex == -1 {
+		return fmt.Errorf("STORY_NOT_FOUND")
+	}
+	story := game.Stories[storyIndex]
+
+	switch event.EventType {
+	case GameEventStoryActivated:
+		for _, s := range game.Stories {
+			s.Active = false
+		}
+		story.Active = true
+		story.Skipped = false
+		story.Points = ""
+		story.Votes = make([]*Vote, 0)
+		game.VotingLocked = false
+		game.ActiveStoryID = story.ID
+	case GameEventVoteCast:
+		for _, v := range story.Votes {
+			if v.UserID == payload.UserID {
+				v.VoteValue = payload.Vote
+				return nil
+			}
+		}
+		story.Votes = append(story.Votes, &Vote{UserID: payload.UserID, VoteValue: payload.Vote})
+	case GameEventVoteRetracted:
+		votes := make([]*Vote, 0, len(story.Votes))
+		for _, v := range story.Votes {
+			if v.UserID != payload.UserID {
+				votes = append(votes, v)
+			}
+		}
+		story.Votes = votes
+	case GameEventVotingEnded:
+		story.Active = false
+		game.VotingLocked = true
+	case GameEventStorySkipped:
+		story.Active = false
+		story.Skipped = true
+		game.VotingLocked = true
+		game.ActiveStoryID = ""
+	case GameEventStoryFinalized:
+		story.Active = false
+		story.Points = payload.Points
+		game.ActiveStoryID = ""
+	case GameEventStoryDeleted:
+		if game.ActiveStoryID == story.ID {
+			game.VotingLocked = true
+			game.ActiveStoryID = ""
+		}
+		game.Stories = append(game.Stories[:storyIndex], game.Stories[storyIndex+1:]...)
+	default:
+		return fmt.Errorf("unknown game event type %s", event.EventType)
+	}
+
+	return nil
+}
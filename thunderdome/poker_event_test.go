@@ -0,0 +1,99 @@
+package thunderdome
+
+import (
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func gameEvent(t *testing.T, sequence int64, eventType string, payload any) *GameEvent {
+	t.Helper()
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("unable to marshal event payload: %v", err)
+	}
+
+	return &GameEvent{PokerID: "game", SequenceNumber: sequence, EventType: eventType, Payload: raw}
+}
+
+// TestReplayGameEventsShuffled makes sure events are replayed in sequence order regardless of the order they're loaded in
+func TestReplayGameEventsShuffled(t *testing.T) {
+	events := []*GameEvent{
+		gameEvent(t, 1, GameEventCreated, GameSettingsEvent{Name: "Sprint 1", PointValuesAllowed: []string{"1", "2", "3", "5", "8"}}),
+		gameEvent(t, 2, GameEventStoryAdded, GameStoryEvent{StoryID: "s1", Name: "Login"}),
+		gameEvent(t, 3, GameEventStoryAdded, GameStoryEvent{StoryID: "s2", Name: "Signup"}),
+		gameEvent(t, 4, GameEventStoryAdded, GameStoryEvent{StoryID: "s3", Name: "Reset password"}),
+		gameEvent(t, 5, GameEventStoryActivated, GameStoryEvent{StoryID: "s1"}),
+		gameEvent(t, 6, GameEventVoteCast, GameStoryEvent{StoryID: "s1", UserID: "u1", Vote: "3"}),
+		gameEvent(t, 7, GameEventVoteCast, GameStoryEvent{StoryID: "s1", UserID: "u2", Vote: "5"}),
+		gameEvent(t, 8, GameEventVoteCast, GameStoryEvent{StoryID: "s1", UserID: "u1", Vote: "5"}),
+		gameEvent(t, 9, GameEventVotingEnded, GameStoryEvent{StoryID: "s1"}),
+		gameEvent(t, 10, GameEventStoryFinalized, GameStoryEvent{StoryID: "s1", Points: "5"}),
+		gameEvent(t, 11, GameEventStoryActivated, GameStoryEvent{StoryID: "s2"}),
+		gameEvent(t, 12, GameEventVoteCast, GameStoryEvent{StoryID: "s2", UserID: "u1", Vote: "8"}),
+		gameEvent(t, 13, GameEventVoteCast, GameStoryEvent{StoryID: "s2", UserID: "u2", Vote: "8"}),
+		gameEvent(t, 14, GameEventVoteRetracted, GameStoryEvent{StoryID: "s2", UserID: "u2"}),
+		gameEvent(t, 15, GameEventStorySkipped, GameStoryEvent{StoryID: "s2"}),
+		gameEvent(t, 16, GameEventStoryAdded, GameStoryEvent{StoryID: "s4", Name: "Profile"}),
+		gameEvent(t, 17, GameEventStoryDeleted, GameStoryEvent{StoryID: "s3"}),
+		gameEvent(t, 18, GameEventUpdated, GameSettingsEvent{Name: "Sprint 1 planning", PointValuesAllowed: []string{"1", "2", "3", "5", "8"}, AutoFinishVoting: true}),
+		gameEvent(t, 19, GameEventStoryActivated, GameStoryEvent{StoryID: "s4"}),
+		gameEvent(t, 20, GameEventVoteCast, GameStoryEvent{StoryID: "s4", UserID: "u2", Vote: "2"}),
+	}
+
+	expected := &Poker{
+		ID:                 "game",
+		Name:               "Sprint 1 planning",
+		PointValuesAllowed: []string{"1", "2", "3", "5", "8"},
+		AutoFinishVoting:   true,
+		VotingLocked:       false,
+		ActiveStoryID:      "s4",
+		Stories: []*Story{
+			{ID: "s1", Name: "Login", Points: "5", Votes: []*Vote{{UserID: "u1", VoteValue: "5"}, {UserID: "u2", VoteValue: "5"}}},
+			{ID: "s2", Name: "Signup", Skipped: true, Votes: []*Vote{{UserID: "u1", VoteValue: "8"}}},
+			{ID: "s4", Name: "Profile", Active: true, Votes: []*Vote{{UserID: "u2", VoteValue: "2"}}},
+		},
+	}
+
+	r := rand.New(rand.NewSource(1900))
+	for i := 0; i < 5; i++ {
+		shuffled := make([]*GameEvent, len(events))
+		copy(shuffled, events)
+		r.Shuffle(len(shuffled), func(a, b int) { shuffled[a], shuffled[b] = shuffled[b], shuffled[a] })
+
+		game, err := ReplayGameEvents("game", shuffled)
+		if err != nil {
+			t.Fatalf("unexpected replay error: %v", err)
+		}
+		if !reflect.DeepEqual(expected, game) {
+			gameJSON, _ := json.Marshal(game)
+			expectedJSON, _ := json.Marshal(expected)
+			t.Fatalf("expected rebuilt game %s, got %s", expectedJSON, gameJSON)
+		}
+	}
+}
+
+func TestReplayGameEventsInvalidLog(t *testing.T) {
+	created := gameEvent(t, 1, GameEventCreated, GameSettingsEvent{Name: "Sprint 1"})
+
+	if _, err := ReplayGameEvents("game", nil); !errors.Is(err, ErrGameEventsNotFound) {
+		t.Errorf("expected %v for an empty log, got %v", ErrGameEventsNotFound, err)
+	}
+
+	gap := []*GameEvent{created, gameEvent(t, 3, GameEventStoryAdded, GameStoryEvent{StoryID: "s1"})}
+	if _, err := ReplayGameEvents("game", gap); !errors.Is(err, ErrGameEventSequenceGap) {
+		t.Errorf("expected %v for a log with a gap, got %v", ErrGameEventSequenceGap, err)
+	}
+
+	unknownStory := []*GameEvent{created, gameEvent(t, 2, GameEventStoryActivated, GameStoryEvent{StoryID: "missing"})}
+	if _, err := ReplayGameEvents("game", unknownStory); err == nil {
+		t.Error("expected an error activating a story that was never added")
+	}
+
+	notCreated := []*GameEvent{gameEvent(t, 1, GameEventStoryAdded, GameStoryEvent{StoryID: "s1"})}
+	if _, err := ReplayGameEvents("game", notCreated); err == nil {
+		t.Error("expected an error when the log doesn't start with the game being created")
+	}
+}
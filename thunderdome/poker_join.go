@@ -0,0 +1,35 @@
+package thunderdome
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/url"
+)
+
+// ErrInvalidJoinDeepLink is returned when a join deep link is missing or has malformed parameters
+var ErrInvalidJoinDeepLink = errors.New("INVALID_JOIN_DEEP_LINK")
+
+// JoinDeepLink builds the link that joins the poker game with the join code in one step,
+// the game ID and join code are base64url encoded so the link survives being shared from mobile apps
+func JoinDeepLink(pokerID string, joinCode string) string {
+	query := url.Values{}
+	query.Set("game", base64.RawURLEncoding.EncodeToString([]byte(pokerID)))
+	query.Set("code", base64.RawURLEncoding.EncodeToString([]byte(joinCode)))
+
+	return "/join?" + query.Encode()
+}
+
+// ParseJoinDeepLink decodes the game and code parameters of a join deep link
+func ParseJoinDeepLink(game string, code string) (pokerID string, joinCode string, err error) {
+	if game == "" || code == "" {
+		return "", "", ErrInvalidJoinDeepLink
+	}
+
+	decodedGame, gameErr := base64.RawURLEncoding.DecodeString(game)
+	decodedCode, codeErr := base64.RawURLEncoding.DecodeString(code)
+	if gameErr != nil || codeErr != nil || len(decodedGame) == 0 || len(decodedCode) == 0 {
+		return "", "", ErrInvalidJoinDeepLink
+	}
+
+	return string(decodedGame), string(decodedCode), nil
+}
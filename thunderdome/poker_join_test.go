@@ -0,0 +1,50 @@
+package thunderdome
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// TestJoinDeepLinkRoundTrip makes sure the game ID and join code can be read back from the deep link
+func TestJoinDeepLinkRoundTrip(t *testing.T) {
+	link := JoinDeepLink("3fa85f64-5717-4562-b3fc-2c963f66afa6", "s3cret/+code")
+	if !strings.HasPrefix(link, "/join?") {
+		t.Fatalf("expected a /join link, got %s", link)
+	}
+
+	parsed, err := url.Parse(link)
+	if err != nil {
+		t.Fatalf("expected a valid url, got %v", err)
+	}
+	pokerID, joinCode, err := ParseJoinDeepLink(parsed.Query().Get("game"), parsed.Query().Get("code"))
+	if err != nil {
+		t.Fatalf("expected the deep link to parse, got %v", err)
+	}
+	if pokerID != "3fa85f64-5717-4562-b3fc-2c963f66afa6" || joinCode != "s3cret/+code" {
+		t.Errorf("expected the original game ID and join code, got %s %s", pokerID, joinCode)
+	}
+}
+
+// TestParseJoinDeepLinkInvalid makes sure missing and malformed parameters are rejected
+func TestParseJoinDeepLinkInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		game string
+		code string
+	}{
+		{name: "missing game", code: "Y29kZQ"},
+		{name: "missing code", game: "Z2FtZQ"},
+		{name: "malformed game", game: "not base64!", code: "Y29kZQ"},
+		{name: "padded code", game: "Z2FtZQ", code: "Y29kZQ=="},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := ParseJoinDeepLink(tt.game, tt.code); !errors.Is(err, ErrInvalidJoinDeepLink) {
+				t.Errorf("expected ErrInvalidJoinDeepLink, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package thunderdome
+
+import "errors"
+
+// StorySearchField is the poker story field a story search matches against
+type StorySearchField string
+
+const (
+	StorySearchFieldName               StorySearchField = "name"
+	StorySearchFieldDescription        StorySearchField = "description"
+	StorySearchFieldAcceptanceCriteria StorySearchField = "acceptance_criteria"
+	StorySearchFieldAll                StorySearchField = "all"
+)
+
+// ErrInvalidStorySearchField is returned when the story search field isn't one of the allowed fields
+var ErrInvalidStorySearchField = errors.New("INVALID_STORY_SEARCH_FIELD")
+
+// ParseStorySearchField validates the story search field against the allowed fields, an empty field searches all fields
+func ParseStorySearchField(field string) (StorySearchField, error) {
+	switch StorySearchField(field) {
+	case "":
+		return StorySearchFieldAll, nil
+	case StorySearchFieldName, StorySearchFieldDescription, StorySearchFieldAcceptanceCriteria, StorySearchFieldAll:
+		return StorySearchField(field), nil
+	default:
+		return "", ErrInvalidStorySearchField
+	}
+}
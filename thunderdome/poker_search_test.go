@@ -0,0 +1,33 @@
+package thunderdome
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestParseStorySearchField makes sure only the allowed fields are accepted
+func TestParseStorySearchField(t *testing.T) {
+	tests := []struct {
+		field    string
+		expected StorySearchField
+		wantErr  error
+	}{
+		{field: "", expected: StorySearchFieldAll},
+		{field: "name", expected: StorySearchFieldName},
+		{field: "description", expected: StorySearchFieldDescription},
+		{field: "acceptance_criteria", expected: StorySearchFieldAcceptanceCriteria},
+		{field: "all", expected: StorySearchFieldAll},
+		{field: "points", wantErr: ErrInvalidStorySearchField},
+		{field: "name) OR (1=1", wantErr: ErrInvalidStorySearchField},
+	}
+
+	for _, tt := range tests {
+		field, err := ParseStorySearchField(tt.field)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("expected field %q error %v, got %v", tt.field, tt.wantErr, err)
+		}
+		if field != tt.expected {
+			t.Errorf("expected field %q to parse to %q, got %q", tt.field, tt.expected, field)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package thunderdome
+
+import (
+	"strings"
+	"time"
+)
+
+// StorySprint is the sprint poker stories are assigned to, an empty name removes the stories from their sprint
+type StorySprint struct {
+	Name      string     `json:"sprintName" validate:"max=256"`
+	StartDate *time.Time `json:"sprintStartDate"`
+	EndDate   *time.Time `json:"sprintEndDate"`
+}
+
+// FilterStoriesBySprint gets the stories assigned to the sprint, sprint names are matched ignoring case
+func FilterStoriesBySprint(stories []*Story, sprintName string) []*Story {
+	sprintName = strings.TrimSpace(sprintName)
+	filtered := make([]*Story, 0)
+	for _, story := range stories {
+		if story.SprintName != "" && strings.EqualFold(story.SprintName, sprintName) {
+			filtered = append(filtered, story)
+		}
+	}
+
+	return filtered
+}
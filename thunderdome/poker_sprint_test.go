@@ -0,0 +1,40 @@
+package thunderdome
+
+import (
+	"testing"
+)
+
+// TestFilterStoriesBySprint makes sure filtering by sprint name returns exactly the assigned stories
+func TestFilterStoriesBySprint(t *testing.T) {
+	stories := []*Story{
+		{ID: "a", SprintName: "Sprint 12"},
+		{ID: "b", SprintName: "Sprint 13"},
+		{ID: "c"},
+		{ID: "d", SprintName: "sprint 12"},
+	}
+
+	tests := []struct {
+		name       string
+		sprintName string
+		expected   []string
+	}{
+		{name: "assigned sprint", sprintName: "Sprint 12", expected: []string{"a", "d"}},
+		{name: "other sprint", sprintName: " Sprint 13 ", expected: []string{"b"}},
+		{name: "unknown sprint", sprintName: "Sprint 14", expected: []string{}},
+		{name: "empty sprint", sprintName: "", expected: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filtered := FilterStoriesBySprint(stories, tt.sprintName)
+			if len(filtered) != len(tt.expected) {
+				t.Fatalf("expected %d stories, got %d", len(tt.expected), len(filtered))
+			}
+			for i, id := range tt.expected {
+				if filtered[i].ID != id {
+					t.Errorf("expected story %s at %d, got %s", id, i, filtered[i].ID)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package thunderdome
+
+import "errors"
+
+// Poker story statuses, stories move from pending through to in-sprint as they're estimated and planned
+const (
+	StoryStatusPending   = "pending"
+	StoryStatusEstimated = "estimated"
+	StoryStatusReady     = "ready"
+	StoryStatusInSprint  = "in-sprint"
+)
+
+// ErrInvalidStoryStatus is returned for a status that isn't one of the story statuses
+var ErrInvalidStoryStatus = errors.New("INVALID_STORY_STATUS")
+
+// ErrInvalidStoryStatusTransition is returned when the story can't move from its status to the new status
+var ErrInvalidStoryStatusTransition = errors.New("INVALID_STORY_STATUS_TRANSITION")
+
+// ErrStoryNotEstimated is returned when a story without final points is moved past pending
+var ErrStoryNotEstimated = errors.New("STORY_NOT_ESTIMATED")
+
+// storyStatusTransitions are the statuses each status can move to, stories move one step forward or back at a time
+var storyStatusTransitions = map[string][]string{
+	StoryStatusPending:   {StoryStatusEstimated},
+	StoryStatusEstimated: {StoryStatusPending, StoryStatusReady},
+	StoryStatusReady:     {StoryStatusEstimated, StoryStatusInSprint},
+	StoryStatusInSprint:  {StoryStatusReady},
+}
+
+// ValidateStoryStatusTransition checks the story can move to the status, stories already in the status are valid.
+// Only stories with final points can be estimated, ready or in a sprint.
+func ValidateStoryStatusTransition(story *Story, status string) error {
+	if _, ok := storyStatusTransitions[status]; !ok {
+		return ErrInvalidStoryStatus
+	}
+	if status != StoryStatusPending && story.Points == "" {
+		return ErrStoryNotEstimated
+	}
+
+	current := story.Status
+	if current == "" {
+		current = StoryStatusPending
+	}
+	if current == status {
+		return nil
+	}
+	for _, next := range storyStatusTransitions[current] {
+		if next == status {
+			return nil
+		}
+	}
+
+	return ErrInvalidStoryStatusTransition
+}
@@ -0,0 +1,38 @@
+package thunderdome
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestValidateStoryStatusTransition makes sure stories only move one step along the status state machine
+func TestValidateStoryStatusTransition(t *testing.T) {
+	tests := []struct {
+		name    string
+		story   Story
+		status  string
+		wantErr error
+	}{
+		{name: "pending to estimated", story: Story{Status: StoryStatusPending, Points: "5"}, status: StoryStatusEstimated},
+		{name: "unset status is pending", story: Story{Points: "5"}, status: StoryStatusEstimated},
+		{name: "estimated to ready", story: Story{Status: StoryStatusEstimated, Points: "5"}, status: StoryStatusReady},
+		{name: "ready to in-sprint", story: Story{Status: StoryStatusReady, Points: "5"}, status: StoryStatusInSprint},
+		{name: "in-sprint back to ready", story: Story{Status: StoryStatusInSprint, Points: "5"}, status: StoryStatusReady},
+		{name: "estimated back to pending", story: Story{Status: StoryStatusEstimated, Points: "5"}, status: StoryStatusPending},
+		{name: "unchanged status", story: Story{Status: StoryStatusReady, Points: "5"}, status: StoryStatusReady},
+		{name: "estimated without points", story: Story{Status: StoryStatusPending}, status: StoryStatusEstimated, wantErr: ErrStoryNotEstimated},
+		{name: "pending skips to ready", story: Story{Status: StoryStatusPending, Points: "5"}, status: StoryStatusReady, wantErr: ErrInvalidStoryStatusTransition},
+		{name: "pending skips to in-sprint", story: Story{Status: StoryStatusPending, Points: "5"}, status: StoryStatusInSprint, wantErr: ErrInvalidStoryStatusTransition},
+		{name: "in-sprint back to pending", story: Story{Status: StoryStatusInSprint, Points: "5"}, status: StoryStatusPending, wantErr: ErrInvalidStoryStatusTransition},
+		{name: "unknown status", story: Story{Status: StoryStatusPending, Points: "5"}, status: "done", wantErr: ErrInvalidStoryStatus},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateStoryStatusTransition(&tt.story, tt.status)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
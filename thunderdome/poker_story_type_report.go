@@ -0,0 +1,67 @@
+package thunderdome
+
+import (
+	"math"
+	"sort"
+)
+
+// StoryTypeReport compares the final points of a story type across a team's poker games,
+// the averages only include numeric points while the distribution counts every final point value
+type StoryTypeReport struct {
+	StoryType         string         `json:"storyType"`
+	GameCount         int            `json:"gameCount"`
+	TotalStories      int            `json:"totalStories"`
+	AveragePoints     float64        `json:"averagePoints"`
+	MedianPoints      float64        `json:"medianPoints"`
+	StdDeviation      float64        `json:"stdDeviation"`
+	PointDistribution map[string]int `json:"pointDistribution"`
+}
+
+// ComputeStoryTypeReport builds the story type report from the final points of the type's stories by game ID
+func ComputeStoryTypeReport(storyType string, gamePoints map[string][]string) *StoryTypeReport {
+	report := &StoryTypeReport{
+		StoryType:         storyType,
+		PointDistribution: make(map[string]int),
+	}
+
+	numericPoints := make([]float64, 0)
+	for _, points := range gamePoints {
+		if len(points) == 0 {
+			continue
+		}
+		report.GameCount++
+		for _, point := range points {
+			report.TotalStories++
+			report.PointDistribution[point]++
+			if value, ok := ParsePointValue(point); ok {
+				numericPoints = append(numericPoints, value)
+			}
+		}
+	}
+	if len(numericPoints) == 0 {
+		return report
+	}
+
+	sort.Float64s(numericPoints)
+	var total float64
+	for _, value := range numericPoints {
+		total += value
+	}
+	count := float64(len(numericPoints))
+	report.AveragePoints = total / count
+
+	middle := len(numericPoints) / 2
+	if len(numericPoints)%2 == 0 {
+		report.MedianPoints = (numericPoints[middle-1] + numericPoints[middle]) / 2
+	} else {
+		report.MedianPoints = numericPoints[middle]
+	}
+
+	var variance float64
+	for _, value := range numericPoints {
+		variance += (value - report.AveragePoints) * (value - report.AveragePoints)
+	}
+	report.StdDeviation = math.Sqrt(variance / count)
+
+	return report
+}
@@ -0,0 +1,64 @@
+package thunderdome
+
+import (
+	"math"
+	"testing"
+)
+
+// TestComputeStoryTypeReport seeds the final points of two story types across games and makes sure
+// each report's distribution keys are exactly the final points of its type
+func TestComputeStoryTypeReport(t *testing.T) {
+	fixture := map[string]map[string][]string{
+		"story": {
+			"game-1": {"3", "5", "5"},
+			"game-2": {"8", "?"},
+			"game-3": {"1/2"},
+		},
+		"bug": {
+			"game-1": {"1", "2"},
+			"game-4": {"2"},
+		},
+	}
+
+	story := ComputeStoryTypeReport("story", fixture["story"])
+	expectedStoryDistribution := map[string]int{"3": 1, "5": 2, "8": 1, "?": 1, "1/2": 1}
+	if len(story.PointDistribution) != len(expectedStoryDistribution) {
+		t.Fatalf("expected story distribution %v, got %v", expectedStoryDistribution, story.PointDistribution)
+	}
+	for points, count := range expectedStoryDistribution {
+		if story.PointDistribution[points] != count {
+			t.Errorf("expected %d story with %s points, got %d", count, points, story.PointDistribution[points])
+		}
+	}
+	if story.StoryType != "story" || story.GameCount != 3 || story.TotalStories != 6 {
+		t.Errorf("unexpected story report counts %+v", story)
+	}
+	// the ? story is counted in the distribution but left out of the averages
+	if story.AveragePoints != 4.3 || story.MedianPoints != 5 {
+		t.Errorf("expected average 4.3 and median 5, got %v and %v", story.AveragePoints, story.MedianPoints)
+	}
+	if math.Abs(story.StdDeviation-2.4819) > 0.0001 {
+		t.Errorf("expected standard deviation 2.4819, got %v", story.StdDeviation)
+	}
+
+	bug := ComputeStoryTypeReport("bug", fixture["bug"])
+	if len(bug.PointDistribution) != 2 || bug.PointDistribution["1"] != 1 || bug.PointDistribution["2"] != 2 {
+		t.Errorf("expected bug distribution of 1 and 2 points, got %v", bug.PointDistribution)
+	}
+	if bug.GameCount != 2 || bug.TotalStories != 3 || bug.MedianPoints != 2 {
+		t.Errorf("unexpected bug report %+v", bug)
+	}
+}
+
+func TestComputeStoryTypeReportEmpty(t *testing.T) {
+	report := ComputeStoryTypeReport("spike", map[string][]string{"game-1": {}})
+	if report.GameCount != 0 || report.TotalStories != 0 || len(report.PointDistribution) != 0 || report.AveragePoints != 0 {
+		t.Errorf("expected an empty report, got %+v", report)
+	}
+
+	// an even count of numeric points uses the mean of the middle two
+	report = ComputeStoryTypeReport("spike", map[string][]string{"game-1": {"1", "2", "3", "13"}})
+	if report.MedianPoints != 2.5 {
+		t.Errorf("expected median 2.5, got %v", report.MedianPoints)
+	}
+}
@@ -0,0 +1,23 @@
+package thunderdome
+
+import "time"
+
+// GameTemplate is a team's saved poker game settings, when the template has a recurrence cron
+// a new team game is created from it each time the cron comes due
+type GameTemplate struct {
+	ID                   string   `json:"id"`
+	TeamID               string   `json:"teamId"`
+	Name                 string   `json:"name"`
+	EstimationScaleID    string   `json:"estimationScaleId"`
+	PointValuesAllowed   []string `json:"pointValuesAllowed"`
+	AutoFinishVoting     bool     `json:"autoFinishVoting"`
+	PointAverageRounding string   `json:"pointAverageRounding"`
+	HideVoterIdentity    bool     `json:"hideVoterIdentity"`
+	RecurrenceCron       string   `json:"recurrenceCron"`
+	// CreatedBy is the user that created the template, they facilitate the games created from it
+	CreatedBy string `json:"createdBy"`
+	// LastGameDate is when the last game was created from the template, nil when none have been
+	LastGameDate *time.Time `json:"lastGameDate"`
+	CreatedDate  time.Time  `json:"createdDate"`
+	UpdatedDate  time.Time  `json:"updatedDate"`
+}
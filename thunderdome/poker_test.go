@@ -0,0 +1,166 @@
+package thunderdome
+
+import (
+	"testing"
+	"time"
+)
+
+func pokerFacilitators() []*PokerFacilitator {
+	created := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+
+	return []*PokerFacilitator{
+		{UserID: "primary", IsPrimaryFacilitator: true, CreatedDate: created},
+		{UserID: "newest", CreatedDate: created.Add(2 * time.Hour)},
+		{UserID: "second", CreatedDate: created.Add(time.Hour)},
+	}
+}
+
+// TestValidatePrimaryFacilitatorTransfer makes sure the primary facilitator can only hand over to another facilitator
+func TestValidatePrimaryFacilitatorTransfer(t *testing.T) {
+	tests := []struct {
+		name        string
+		currentID   string
+		newID       string
+		expectedErr string
+	}{
+		{name: "to facilitator", currentID: "primary", newID: "second", expectedErr: ""},
+		{name: "to non facilitator", currentID: "primary", newID: "participant", expectedErr: "NOT_A_FACILITATOR"},
+		{name: "from non facilitator", currentID: "participant", newID: "second", expectedErr: "NOT_A_FACILITATOR"},
+		{name: "from co-facilitator", currentID: "second", newID: "newest", expectedErr: "NOT_PRIMARY_FACILITATOR"},
+		{name: "to self", currentID: "primary", newID: "primary", expectedErr: "ALREADY_PRIMARY_FACILITATOR"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePrimaryFacilitatorTransfer(pokerFacilitators(), tt.currentID, tt.newID)
+			if tt.expectedErr == "" && err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if tt.expectedErr != "" && (err == nil || err.Error() != tt.expectedErr) {
+				t.Fatalf("expected error %s, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+// TestNextPrimaryFacilitator makes sure the longest serving co-facilitator is promoted
+func TestNextPrimaryFacilitator(t *testing.T) {
+	if next := NextPrimaryFacilitator(pokerFacilitators(), "primary"); next != "second" {
+		t.Errorf("expected second, got %q", next)
+	}
+	if next := NextPrimaryFacilitator(pokerFacilitators()[:1], "primary"); next != "" {
+		t.Errorf("expected no facilitator to promote, got %q", next)
+	}
+}
+
+// TestIsPrimaryFacilitator makes sure only the primary facilitator is reported as such
+func TestIsPrimaryFacilitator(t *testing.T) {
+	if !IsPrimaryFacilitator(pokerFacilitators(), "primary") {
+		t.Error("expected primary to be the primary facilitator")
+	}
+	if IsPrimaryFacilitator(pokerFacilitators(), "second") {
+		t.Error("expected second not to be the primary facilitator")
+	}
+	if IsPrimaryFacilitator(pokerFacilitators(), "participant") {
+		t.Error("expected participant not to be the primary facilitator")
+	}
+}
+
+// TestSplitStoryPriorityUpdates makes sure updates for stories outside the game fail without affecting the rest
+func TestSplitStoryPriorityUpdates(t *testing.T) {
+	stories := []*Story{{ID: "story1"}, {ID: "story2"}, {ID: "story3"}}
+	updates := []StoryPriorityUpdate{
+		{StoryID: "story1", Priority: 1},
+		{StoryID: "unknown", Priority: 2},
+		{StoryID: "story3", Priority: 3},
+		{StoryID: "story1", Priority: 4},
+	}
+
+	applicable, results := SplitStoryPriorityUpdates(stories, updates)
+	if len(applicable) != 2 || applicable[0].StoryID != "story1" || applicable[1].StoryID != "story3" {
+		t.Fatalf("expected story1 and story3 updates to apply, got %+v", applicable)
+	}
+
+	expected := []StoryPriorityResult{
+		{StoryID: "story1", Success: true},
+		{StoryID: "unknown", Error: "STORY_NOT_FOUND"},
+		{StoryID: "story3", Success: true},
+		{StoryID: "story1", Error: "DUPLICATE_STORY"},
+	}
+	if len(results) != len(expected) {
+		t.Fatalf("expected %d results, got %d", len(expected), len(results))
+	}
+	for i, result := range results {
+		if result != expected[i] {
+			t.Errorf("expected result %d to be %+v, got %+v", i, expected[i], result)
+		}
+	}
+}
+
+// TestStoryEstimationScaleID makes sure story types with an override scale use it and the rest use the game's scale
+func TestStoryEstimationScaleID(t *testing.T) {
+	game := &Poker{
+		EstimationScaleID: "fibonacci",
+		StoryTypeScaleMap: NormalizeStoryTypeScaleMap(map[string]string{"Epic": "tshirt", "bug": "", " ": "hours"}),
+	}
+
+	tests := []struct {
+		storyType string
+		expected  string
+	}{
+		{storyType: "epic", expected: "tshirt"},
+		{storyType: "Epic", expected: "tshirt"},
+		{storyType: "story", expected: "fibonacci"},
+		{storyType: "bug", expected: "fibonacci"},
+		{storyType: "", expected: "fibonacci"},
+	}
+
+	for _, tt := range tests {
+		if scaleID := game.StoryEstimationScaleID(tt.storyType); scaleID != tt.expected {
+			t.Errorf("expected story type %q to use scale %s, got %s", tt.storyType, tt.expected, scaleID)
+		}
+	}
+}
+
+// TestVoteQuorum makes sure the quorum is met at exactly the quorum percentage and not one vote short of it
+func TestVoteQuorum(t *testing.T) {
+	users := []*PokerUser{
+		{ID: "a", Active: true}, {ID: "b", Active: true}, {ID: "c", Active: true}, {ID: "d", Active: true},
+		{ID: "spectator", Active: true, Spectator: true},
+		{ID: "left", Active: false},
+	}
+	votes := func(userIDs ...string) []*Vote {
+		v := make([]*Vote, 0, len(userIDs))
+		for _, id := range userIDs {
+			v = append(v, &Vote{UserID: id, VoteValue: "3"})
+		}
+		return v
+	}
+
+	tests := []struct {
+		name               string
+		votes              []*Vote
+		quorum             float64
+		expectedPercentage float64
+		expectedReached    bool
+	}{
+		{name: "exactly quorum", votes: votes("a", "b", "c"), quorum: 75, expectedPercentage: 75, expectedReached: true},
+		{name: "one vote short", votes: votes("a", "b"), quorum: 75, expectedPercentage: 50, expectedReached: false},
+		{name: "spectator and departed votes not counted", votes: votes("a", "b", "spectator", "left"), quorum: 75, expectedPercentage: 50, expectedReached: false},
+		{name: "quorum disabled", votes: votes(), quorum: 0, expectedPercentage: 0, expectedReached: true},
+		{name: "everyone voted", votes: votes("a", "b", "c", "d"), quorum: 100, expectedPercentage: 100, expectedReached: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			percentage, reached := VoteQuorum(tt.votes, users, tt.quorum)
+			if percentage != tt.expectedPercentage || reached != tt.expectedReached {
+				t.Errorf("expected %v%% reached %v, got %v%% reached %v", tt.expectedPercentage, tt.expectedReached, percentage, reached)
+			}
+		})
+	}
+
+	if percentage, reached := VoteQuorum(votes("a"), nil, 50); percentage != 0 || reached {
+		t.Errorf("expected no quorum without participants, got %v%% reached %v", percentage, reached)
+	}
+}
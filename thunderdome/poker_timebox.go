@@ -0,0 +1,79 @@
+package thunderdome
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+// Poker story time box pub/sub event types
+const (
+	PokerTimeBoxEventStart = "timebox:start"
+	PokerTimeBoxEventStop  = "timebox:stop"
+)
+
+var (
+	// ErrTimeBoxNotRunning is returned when extending a poker story time box that isn't running
+	ErrTimeBoxNotRunning = errors.New("TIMEBOX_NOT_RUNNING")
+	// ErrInvalidTimeBoxExtension is returned when extending a poker story time box without a positive number of seconds
+	ErrInvalidTimeBoxExtension = errors.New("INVALID_TIMEBOX_EXTENSION")
+)
+
+// PokerTimeBox is the discussion window of the active poker story, started when the story is activated
+// for games with TimeBoxMinutes set
+type PokerTimeBox struct {
+	StoryID         string    `json:"planId"`
+	DurationSeconds int       `json:"durationSeconds"`
+	StartedAt       time.Time `json:"startedAt"`
+}
+
+// PokerRunningTimeBox is a game's stored story time box along with how much of it is left,
+// measured by the database clock so every instance agrees on when it runs out
+type PokerRunningTimeBox struct {
+	PokerID   string
+	TimeBox   PokerTimeBox
+	Remaining time.Duration
+}
+
+// PokerTimeBoxEvent is a poker story time box being started, extended or stopped, published so every instance
+// with participants in the game can track the time box
+type PokerTimeBoxEvent struct {
+	Type    string       `json:"type"`
+	PokerID string       `json:"pokerId"`
+	TimeBox PokerTimeBox `json:"timeBox"`
+}
+
+// Remaining returns how much time is left in the time box at the given time
+func (t PokerTimeBox) Remaining(now time.Time) time.Duration {
+	remaining := time.Duration(t.DurationSeconds)*time.Second - now.Sub(t.StartedAt)
+	if remaining < 0 {
+		return 0
+	}
+
+	return remaining
+}
+
+// MedianVote returns the median of the story's numeric votes, for an even number of votes the higher
+// of the middle two is used, returns an empty string when there are no numeric votes
+func MedianVote(votes []*Vote) string {
+	type numericVote struct {
+		value  string
+		points float64
+	}
+
+	numericVotes := make([]numericVote, 0, len(votes))
+	for _, v := range votes {
+		if points, ok := ParsePointValue(v.VoteValue); ok {
+			numericVotes = append(numericVotes, numericVote{value: v.VoteValue, points: points})
+		}
+	}
+	if len(numericVotes) == 0 {
+		return ""
+	}
+
+	sort.SliceStable(numericVotes, func(i, j int) bool {
+		return numericVotes[i].points < numericVotes[j].points
+	})
+
+	return numericVotes[len(numericVotes)/2].value
+}
@@ -0,0 +1,46 @@
+package thunderdome
+
+import (
+	"testing"
+	"time"
+)
+
+// TestMedianVote makes sure the median ignores non numeric votes and takes the higher middle vote for even counts
+func TestMedianVote(t *testing.T) {
+	tests := []struct {
+		name     string
+		votes    []string
+		expected string
+	}{
+		{name: "odd", votes: []string{"8", "3", "5"}, expected: "5"},
+		{name: "even", votes: []string{"1", "2", "3", "5"}, expected: "3"},
+		{name: "half point", votes: []string{"1/2", "1/2", "2"}, expected: "1/2"},
+		{name: "abstain ignored", votes: []string{"?", "☕️", "13"}, expected: "13"},
+		{name: "no numeric votes", votes: []string{"?"}, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			votes := make([]*Vote, 0, len(tt.votes))
+			for _, v := range tt.votes {
+				votes = append(votes, &Vote{VoteValue: v})
+			}
+			if got := MedianVote(votes); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+// TestPokerTimeBoxRemaining makes sure the remaining time counts down to zero
+func TestPokerTimeBoxRemaining(t *testing.T) {
+	started := time.Date(2025, 3, 15, 9, 0, 0, 0, time.UTC)
+	timeBox := PokerTimeBox{DurationSeconds: 120, StartedAt: started}
+
+	if remaining := timeBox.Remaining(started.Add(30 * time.Second)); remaining != 90*time.Second {
+		t.Errorf("expected 90s remaining, got %s", remaining)
+	}
+	if remaining := timeBox.Remaining(started.Add(5 * time.Minute)); remaining != 0 {
+		t.Errorf("expected no time remaining, got %s", remaining)
+	}
+}
@@ -0,0 +1,49 @@
+package thunderdome
+
+import "errors"
+
+// RegistrationStatus is where a registered user is in the registration approval workflow
+type RegistrationStatus string
+
+// Registration statuses, users can only log in once their registration is approved
+const (
+	RegistrationStatusPending  RegistrationStatus = "pending"
+	RegistrationStatusApproved RegistrationStatus = "approved"
+	RegistrationStatusRejected RegistrationStatus = "rejected"
+)
+
+// ErrAccountPending is returned when a user attempts to log in before their registration is approved
+var ErrAccountPending = errors.New("ACCOUNT_PENDING")
+
+// ErrInvalidRegistrationTransition is returned when a registration can't move to the requested status
+var ErrInvalidRegistrationTransition = errors.New("INVALID_REGISTRATION_STATUS_TRANSITION")
+
+// registrationTransitions are the statuses each registration status can move to,
+// a rejected registration can still be approved should an admin change their mind
+var registrationTransitions = map[RegistrationStatus][]RegistrationStatus{
+	RegistrationStatusPending:  {RegistrationStatusApproved, RegistrationStatusRejected},
+	RegistrationStatusRejected: {RegistrationStatusApproved},
+	RegistrationStatusApproved: {},
+}
+
+// Valid checks whether the registration status is a known status
+func (s RegistrationStatus) Valid() bool {
+	_, ok := registrationTransitions[s]
+	return ok
+}
+
+// CanLogin checks whether a user with the registration status is allowed to log in
+func (s RegistrationStatus) CanLogin() bool {
+	return s == RegistrationStatusApproved
+}
+
+// ValidateRegistrationTransition checks whether a registration can move from one status to the other
+func ValidateRegistrationTransition(from RegistrationStatus, to RegistrationStatus) error {
+	for _, next := range registrationTransitions[from] {
+		if next == to {
+			return nil
+		}
+	}
+
+	return ErrInvalidRegistrationTransition
+}
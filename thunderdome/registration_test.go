@@ -0,0 +1,66 @@
+package thunderdome
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestValidateRegistrationTransition makes sure only the allowed registration status transitions are accepted
+func TestValidateRegistrationTransition(t *testing.T) {
+	statuses := []RegistrationStatus{RegistrationStatusPending, RegistrationStatusApproved, RegistrationStatusRejected}
+	allowed := map[RegistrationStatus]map[RegistrationStatus]bool{
+		RegistrationStatusPending:  {RegistrationStatusApproved: true, RegistrationStatusRejected: true},
+		RegistrationStatusRejected: {RegistrationStatusApproved: true},
+		RegistrationStatusApproved: {},
+	}
+
+	for _, from := range statuses {
+		for _, to := range statuses {
+			t.Run(string(from)+" to "+string(to), func(t *testing.T) {
+				err := ValidateRegistrationTransition(from, to)
+				if allowed[from][to] && err != nil {
+					t.Errorf("expected transition to be allowed, got %v", err)
+				}
+				if !allowed[from][to] && !errors.Is(err, ErrInvalidRegistrationTransition) {
+					t.Errorf("expected ErrInvalidRegistrationTransition, got %v", err)
+				}
+			})
+		}
+	}
+}
+
+// TestValidateRegistrationTransitionUnknown makes sure unknown statuses can't be transitioned from or to
+func TestValidateRegistrationTransitionUnknown(t *testing.T) {
+	if err := ValidateRegistrationTransition("unknown", RegistrationStatusApproved); !errors.Is(err, ErrInvalidRegistrationTransition) {
+		t.Errorf("expected ErrInvalidRegistrationTransition from unknown status, got %v", err)
+	}
+	if err := ValidateRegistrationTransition(RegistrationStatusPending, "unknown"); !errors.Is(err, ErrInvalidRegistrationTransition) {
+		t.Errorf("expected ErrInvalidRegistrationTransition to unknown status, got %v", err)
+	}
+}
+
+// TestRegistrationStatus makes sure only approved users can log in and only known statuses are valid
+func TestRegistrationStatus(t *testing.T) {
+	tests := []struct {
+		status   RegistrationStatus
+		valid    bool
+		canLogin bool
+	}{
+		{status: RegistrationStatusPending, valid: true, canLogin: false},
+		{status: RegistrationStatusApproved, valid: true, canLogin: true},
+		{status: RegistrationStatusRejected, valid: true, canLogin: false},
+		{status: "unknown", valid: false, canLogin: false},
+		{status: "", valid: false, canLogin: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			if got := tt.status.Valid(); got != tt.valid {
+				t.Errorf("expected valid %v, got %v", tt.valid, got)
+			}
+			if got := tt.status.CanLogin(); got != tt.canLogin {
+				t.Errorf("expected can login %v, got %v", tt.canLogin, got)
+			}
+		})
+	}
+}
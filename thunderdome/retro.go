@@ -43,9 +43,14 @@ type Retro struct {
 	MaxVotes              int            `json:"maxVotes" db:"max_votes"`
 	BrainstormVisibility  string         `json:"brainstormVisibility" db:"brainstorm_visibility"`
 	AllowCumulativeVoting bool           `json:"allowCumulativeVoting" db:"allow_cumulative_voting"`
+	SubmissionPhase       bool           `json:"submissionPhase" db:"submission_phase"`
+	SubmissionDeadline    *time.Time     `json:"submissionDeadline" db:"submission_deadline"`
+	ActiveItemID          *string        `json:"activeItemId" db:"active_item_id"`
+	Timers                []*RetroTimer  `json:"timers"`
 	Template              RetroTemplate  `json:"template"`
 	TeamID                string         `json:"teamId" db:"team_id"`
 	TeamName              string         `json:"teamName"`
+	ParentRetroID         *string        `json:"parentRetroId" db:"parent_retro_id"`
 	CreatedDate           string         `json:"createdDate" db:"created_date"`
 	UpdatedDate           string         `json:"updatedDate" db:"updated_date"`
 }
@@ -54,16 +59,23 @@ type Retro struct {
 type RetroItem struct {
 	ID       string              `json:"id" db:"id"`
 	UserID   string              `json:"userId" db:"user_id"`
-	GroupID  string              `json:"groupId" db:"group_id"`
+	GroupID  *string             `json:"groupId" db:"group_id"`
 	Content  string              `json:"content" db:"content"`
 	Type     string              `json:"type" db:"type"`
+	Emotion  string              `json:"emotion" db:"emotion"`
 	Comments []*RetroItemComment `json:"comments"`
+	// Redacted is set when the item content is hidden while the retro is focused on another item
+	Redacted bool `json:"redacted,omitempty"`
 }
 
-// RetroGroup is a grouping of retro items
+// RetroGroup is a grouping of retro items, facilitators can name and color a group
+// to cluster similar items into themes
 type RetroGroup struct {
-	ID   string `json:"id" db:"id"`
-	Name string `json:"name" db:"name"`
+	ID      string   `json:"id" db:"id"`
+	RetroID string   `json:"retroId" db:"retro_id"`
+	Name    string   `json:"name" db:"name"`
+	ItemIDs []string `json:"itemIds"`
+	Color   string   `json:"color" db:"color"`
 }
 
 // RetroAction is an action the team can take based on retro feedback
@@ -102,3 +114,79 @@ type RetroVote struct {
 	GroupID string `json:"groupId" db:"group_id"`
 	Count   int    `json:"count" db:"vote_count"`
 }
+
+// SubmissionPhaseActive returns whether a retro is still in its pre-session submission phase at the given time,
+// the phase ends when the facilitator opens the session or the submission deadline passes
+func SubmissionPhaseActive(submissionPhase bool, submissionDeadline *time.Time, now time.Time) bool {
+	if !submissionPhase {
+		return false
+	}
+
+	return submissionDeadline == nil || now.Before(*submissionDeadline)
+}
+
+// SubmissionVisibleItems returns the retro items a user may see during the submission phase,
+// users only see their own items while facilitators see every item, authors are never revealed
+func SubmissionVisibleItems(items []*RetroItem, userID string, isFacilitator bool) []*RetroItem {
+	visible := make([]*RetroItem, 0)
+	for _, item := range items {
+		if item.UserID == userID {
+			visible = append(visible, item)
+		} else if isFacilitator {
+			anonymous := *item
+			anonymous.UserID = ""
+			anonymous.Comments = make([]*RetroItemComment, 0, len(item.Comments))
+			for _, comment := range item.Comments {
+				anonymousComment := *comment
+				anonymousComment.UserID = ""
+				anonymous.Comments = append(anonymous.Comments, &anonymousComment)
+			}
+			visible = append(visible, &anonymous)
+		}
+	}
+
+	return visible
+}
+
+// FocusVisibleItems returns the retro items a user may see while the retro is focused on an item,
+// participants only see the focused item's content while facilitators see every item in full
+func FocusVisibleItems(items []*RetroItem, activeItemID *string, isFacilitator bool) []*RetroItem {
+	if activeItemID == nil || isFacilitator {
+		return items
+	}
+
+	visible := make([]*RetroItem, 0, len(items))
+	for _, item := range items {
+		if item.ID == *activeItemID {
+			visible = append(visible, item)
+			continue
+		}
+		visible = append(visible, &RetroItem{
+			ID:       item.ID,
+			GroupID:  item.GroupID,
+			Type:     item.Type,
+			Comments: make([]*RetroItemComment, 0),
+			Redacted: true,
+		})
+	}
+
+	return visible
+}
+
+// RetroSoftDeleteRetentionDays is how many days a soft deleted retro can be restored before it's permanently deleted
+const RetroSoftDeleteRetentionDays = 30
+
+// RetroCleanupResult includes the retros soft deleted by a cleanup
+type RetroCleanupResult struct {
+	RetroCount int      `json:"retroCount"`
+	RetroIDs   []string `json:"retroIds"`
+}
+
+// RetroInsights are the key themes, action items and mood of a retro's items as summarized by AI
+type RetroInsights struct {
+	RetroID        string    `json:"retroId"`
+	TopThemes      []string  `json:"topThemes"`
+	KeyActionItems []string  `json:"keyActionItems"`
+	MoodSummary    string    `json:"moodSummary"`
+	GeneratedAt    time.Time `json:"generatedAt"`
+}
@@ -0,0 +1,59 @@
+package thunderdome
+
+import "errors"
+
+// Retro item emotions, an item without an emotion is counted as untagged in the emotion histogram
+const (
+	RetroEmotionHappy      = "happy"
+	RetroEmotionNeutral    = "neutral"
+	RetroEmotionFrustrated = "frustrated"
+	RetroEmotionExcited    = "excited"
+	RetroEmotionConfused   = "confused"
+	RetroEmotionUntagged   = "untagged"
+)
+
+// RetroEmotions are the emotions a retro item can be tagged with
+var RetroEmotions = []string{
+	RetroEmotionHappy,
+	RetroEmotionNeutral,
+	RetroEmotionFrustrated,
+	RetroEmotionExcited,
+	RetroEmotionConfused,
+}
+
+// ErrInvalidRetroEmotion is returned when tagging a retro item with an unknown emotion
+var ErrInvalidRetroEmotion = errors.New("INVALID_RETRO_EMOTION")
+
+// ValidateRetroEmotion makes sure the emotion is one of the retro emotions, an empty emotion clears the tag
+func ValidateRetroEmotion(emotion string) error {
+	if emotion == "" {
+		return nil
+	}
+	for _, e := range RetroEmotions {
+		if e == emotion {
+			return nil
+		}
+	}
+
+	return ErrInvalidRetroEmotion
+}
+
+// RetroEmotionHistogram counts the retro items by emotion, every emotion is included even when no item has it
+// so the counts always add up to the number of items
+func RetroEmotionHistogram(items []*RetroItem) map[string]int {
+	histogram := make(map[string]int, len(RetroEmotions)+1)
+	for _, e := range RetroEmotions {
+		histogram[e] = 0
+	}
+	histogram[RetroEmotionUntagged] = 0
+
+	for _, item := range items {
+		if item.Emotion == "" {
+			histogram[RetroEmotionUntagged]++
+			continue
+		}
+		histogram[item.Emotion]++
+	}
+
+	return histogram
+}
@@ -0,0 +1,56 @@
+package thunderdome
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestValidateRetroEmotion makes sure only the retro emotions (or clearing the tag) are accepted
+func TestValidateRetroEmotion(t *testing.T) {
+	for _, emotion := range append([]string{""}, RetroEmotions...) {
+		if err := ValidateRetroEmotion(emotion); err != nil {
+			t.Errorf("expected %q to be valid, got %v", emotion, err)
+		}
+	}
+	for _, emotion := range []string{"angry", "Happy", RetroEmotionUntagged} {
+		if err := ValidateRetroEmotion(emotion); !errors.Is(err, ErrInvalidRetroEmotion) {
+			t.Errorf("expected %q to be invalid, got %v", emotion, err)
+		}
+	}
+}
+
+// TestRetroEmotionHistogram makes sure the histogram counts every item once
+func TestRetroEmotionHistogram(t *testing.T) {
+	items := []*RetroItem{
+		{ID: "1", Emotion: RetroEmotionHappy},
+		{ID: "2", Emotion: RetroEmotionHappy},
+		{ID: "3", Emotion: RetroEmotionConfused},
+		{ID: "4"},
+		{ID: "5", Emotion: RetroEmotionFrustrated},
+	}
+
+	histogram := RetroEmotionHistogram(items)
+
+	total := 0
+	for _, count := range histogram {
+		total += count
+	}
+	if total != len(items) {
+		t.Errorf("expected the histogram to sum to %d items, got %d", len(items), total)
+	}
+	if len(histogram) != len(RetroEmotions)+1 {
+		t.Errorf("expected every emotion in the histogram, got %v", histogram)
+	}
+	if histogram[RetroEmotionHappy] != 2 || histogram[RetroEmotionUntagged] != 1 || histogram[RetroEmotionExcited] != 0 {
+		t.Errorf("unexpected histogram %v", histogram)
+	}
+}
+
+// TestRetroEmotionHistogramEmpty makes sure a retro without items has an all zero histogram
+func TestRetroEmotionHistogramEmpty(t *testing.T) {
+	for emotion, count := range RetroEmotionHistogram(nil) {
+		if count != 0 {
+			t.Errorf("expected %s to be 0, got %d", emotion, count)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package thunderdome
+
+import "time"
+
+// RetroSchedule is a team's recurring retro, a new team retro is created from it each time its recurrence cron comes due
+type RetroSchedule struct {
+	ID     string `json:"id"`
+	TeamID string `json:"teamId"`
+	Name   string `json:"name"`
+	// TemplateID is the retro template (and so the retro format) used, when empty the team's default
+	// template is used, falling back to the default public template
+	TemplateID           string `json:"templateId"`
+	MaxVotes             int    `json:"maxVotes"`
+	BrainstormVisibility string `json:"brainstormVisibility"`
+	RecurrenceCron       string `json:"recurrenceCron"`
+	// CreatedBy is the user that created the schedule, they facilitate the retros created from it
+	CreatedBy string `json:"createdBy"`
+	// LastWindowDate is the cron time of the last window a retro was created for, nil when none have been
+	LastWindowDate *time.Time `json:"lastWindowDate"`
+	CreatedDate    time.Time  `json:"createdDate"`
+	UpdatedDate    time.Time  `json:"updatedDate"`
+}
@@ -0,0 +1,102 @@
+package thunderdome
+
+import (
+	"errors"
+	"strings"
+)
+
+// MaxSubRetroDepth is how many levels of sub retros can be nested below a top level retro
+const MaxSubRetroDepth = 5
+
+var (
+	// ErrSubRetroMaxDepth is returned when creating a sub retro would nest deeper than MaxSubRetroDepth
+	ErrSubRetroMaxDepth = errors.New("SUB_RETRO_MAX_DEPTH_EXCEEDED")
+	// ErrSubRetroCircularReference is returned when a retro's parent chain leads back to itself
+	ErrSubRetroCircularReference = errors.New("SUB_RETRO_CIRCULAR_REFERENCE")
+)
+
+// SubRetroSummary is a parent retro's sub retros along with the item counts aggregated from all of them
+type SubRetroSummary struct {
+	ParentRetroID    string         `json:"parentRetroId"`
+	SubRetros        []*Retro       `json:"subRetros"`
+	ItemCount        int            `json:"itemCount"`
+	ItemCountsByType map[string]int `json:"itemCountsByType"`
+}
+
+// SubRetroDepth returns how many parents are above the retro by following the parents map of retro ID to parent retro ID,
+// a top level retro has a depth of 0, revisiting a retro while following the chain is a circular reference
+func SubRetroDepth(retroID string, parents map[string]string) (int, error) {
+	visited := map[string]bool{retroID: true}
+	depth := 0
+	current := retroID
+	for {
+		parentID, ok := parents[current]
+		if !ok || parentID == "" {
+			return depth, nil
+		}
+		if visited[parentID] {
+			return depth, ErrSubRetroCircularReference
+		}
+		visited[parentID] = true
+		depth++
+		current = parentID
+	}
+}
+
+// ValidateSubRetroParent makes sure a new sub retro can be nested below the parent retro
+func ValidateSubRetroParent(parentRetroID string, parents map[string]string) error {
+	parentDepth, err := SubRetroDepth(parentRetroID, parents)
+	if err != nil {
+		return err
+	}
+	if parentDepth+1 > MaxSubRetroDepth {
+		return ErrSubRetroMaxDepth
+	}
+
+	return nil
+}
+
+// subRetroItemKey identifies an item by author, type and content ignoring case and surrounding whitespace
+func subRetroItemKey(item *RetroItem) string {
+	return item.UserID + "\x00" + item.Type + "\x00" + strings.ToLower(strings.TrimSpace(item.Content))
+}
+
+// MergeableSubRetroItems returns the sub retro items to copy into the parent retro, keeping their author,
+// items the same author already added to the parent or another sub retro with the same type and content are skipped
+// so merging again doesn't duplicate items
+func MergeableSubRetroItems(parentItems []*RetroItem, subRetroItems []*RetroItem) []*RetroItem {
+	seen := make(map[string]bool, len(parentItems)+len(subRetroItems))
+	for _, item := range parentItems {
+		seen[subRetroItemKey(item)] = true
+	}
+
+	mergeable := make([]*RetroItem, 0, len(subRetroItems))
+	for _, item := range subRetroItems {
+		if strings.TrimSpace(item.Content) == "" {
+			continue
+		}
+		key := subRetroItemKey(item)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		mergeable = append(mergeable, &RetroItem{
+			UserID:  item.UserID,
+			Type:    item.Type,
+			Content: item.Content,
+			Emotion: item.Emotion,
+		})
+	}
+
+	return mergeable
+}
+
+// CountSubRetroItems aggregates the number of items by type across the sub retros items
+func CountSubRetroItems(items []*RetroItem) (int, map[string]int) {
+	byType := make(map[string]int)
+	for _, item := range items {
+		byType[item.Type]++
+	}
+
+	return len(items), byType
+}
@@ -0,0 +1,105 @@
+package thunderdome
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestSubRetroDepth makes sure the depth follows the parent chain and circular chains are rejected
+func TestSubRetroDepth(t *testing.T) {
+	tests := []struct {
+		name          string
+		retroID       string
+		parents       map[string]string
+		expectedDepth int
+		expectedErr   error
+	}{
+		{name: "top level", retroID: "a", parents: map[string]string{}, expectedDepth: 0},
+		{name: "empty parent", retroID: "a", parents: map[string]string{"a": ""}, expectedDepth: 0},
+		{name: "nested", retroID: "c", parents: map[string]string{"c": "b", "b": "a"}, expectedDepth: 2},
+		{name: "self reference", retroID: "a", parents: map[string]string{"a": "a"}, expectedErr: ErrSubRetroCircularReference},
+		{name: "circular chain", retroID: "c", parents: map[string]string{"c": "b", "b": "a", "a": "c"}, expectedErr: ErrSubRetroCircularReference},
+		{name: "circular above retro", retroID: "d", parents: map[string]string{"d": "c", "c": "b", "b": "c"}, expectedErr: ErrSubRetroCircularReference},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			depth, err := SubRetroDepth(tt.retroID, tt.parents)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("expected error %v, got %v", tt.expectedErr, err)
+			}
+			if tt.expectedErr == nil && depth != tt.expectedDepth {
+				t.Errorf("expected depth %d, got %d", tt.expectedDepth, depth)
+			}
+		})
+	}
+}
+
+// TestValidateSubRetroParent makes sure sub retros can't be nested more than MaxSubRetroDepth levels
+func TestValidateSubRetroParent(t *testing.T) {
+	// r5 is nested 4 levels below r1
+	parents := map[string]string{"r2": "r1", "r3": "r2", "r4": "r3", "r5": "r4", "r6": "r5"}
+
+	if err := ValidateSubRetroParent("r1", parents); err != nil {
+		t.Errorf("expected a sub retro of a top level retro to be allowed, got %v", err)
+	}
+	if err := ValidateSubRetroParent("r5", parents); err != nil {
+		t.Errorf("expected the 5th level of nesting to be allowed, got %v", err)
+	}
+	if err := ValidateSubRetroParent("r6", parents); !errors.Is(err, ErrSubRetroMaxDepth) {
+		t.Errorf("expected %v for the 6th level of nesting, got %v", ErrSubRetroMaxDepth, err)
+	}
+
+	circular := map[string]string{"r1": "r2", "r2": "r1"}
+	if err := ValidateSubRetroParent("r1", circular); !errors.Is(err, ErrSubRetroCircularReference) {
+		t.Errorf("expected %v for a circular parent chain, got %v", ErrSubRetroCircularReference, err)
+	}
+}
+
+// TestMergeableSubRetroItems makes sure duplicate items are merged once and keep their author
+func TestMergeableSubRetroItems(t *testing.T) {
+	parentItems := []*RetroItem{
+		{ID: "p1", UserID: "alice", Type: "worked", Content: "Pairing"},
+	}
+	subRetroItems := []*RetroItem{
+		{ID: "s1", UserID: "alice", Type: "worked", Content: " pairing "},
+		{ID: "s2", UserID: "bob", Type: "worked", Content: "Pairing"},
+		{ID: "s3", UserID: "bob", Type: "improve", Content: "Standups run long", Emotion: "frustrated"},
+		{ID: "s4", UserID: "bob", Type: "improve", Content: "standups run long"},
+		{ID: "s5", UserID: "bob", Type: "question", Content: "Standups run long"},
+		{ID: "s6", UserID: "carol", Type: "question", Content: "   "},
+	}
+
+	merged := MergeableSubRetroItems(parentItems, subRetroItems)
+	if len(merged) != 3 {
+		t.Fatalf("expected 3 items to merge, got %d: %+v", len(merged), merged)
+	}
+
+	expected := []RetroItem{
+		{UserID: "bob", Type: "worked", Content: "Pairing"},
+		{UserID: "bob", Type: "improve", Content: "Standups run long", Emotion: "frustrated"},
+		{UserID: "bob", Type: "question", Content: "Standups run long"},
+	}
+	for i, item := range merged {
+		if item.ID != "" {
+			t.Errorf("expected merged item %d to not keep the sub retro item ID, got %s", i, item.ID)
+		}
+		if item.UserID != expected[i].UserID || item.Type != expected[i].Type ||
+			item.Content != expected[i].Content || item.Emotion != expected[i].Emotion {
+			t.Errorf("expected merged item %d to be %+v, got %+v", i, expected[i], *item)
+		}
+	}
+
+	if again := MergeableSubRetroItems(append(parentItems, merged...), subRetroItems); len(again) != 0 {
+		t.Errorf("expected merging again to add no items, got %d", len(again))
+	}
+}
+
+func TestCountSubRetroItems(t *testing.T) {
+	count, byType := CountSubRetroItems([]*RetroItem{
+		{Type: "worked"}, {Type: "improve"}, {Type: "worked"},
+	})
+	if count != 3 || byType["worked"] != 2 || byType["improve"] != 1 {
+		t.Errorf("unexpected counts %d %+v", count, byType)
+	}
+}
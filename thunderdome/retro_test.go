@@ -0,0 +1,132 @@
+package thunderdome
+
+import (
+	"testing"
+	"time"
+)
+
+// TestSubmissionPhaseActive makes sure the submission phase ends when opened or when the deadline passes
+func TestSubmissionPhaseActive(t *testing.T) {
+	now := time.Date(2025, 3, 7, 14, 0, 0, 0, time.UTC)
+	future := now.Add(time.Hour)
+	past := now.Add(-time.Hour)
+
+	tests := []struct {
+		name            string
+		submissionPhase bool
+		deadline        *time.Time
+		expected        bool
+	}{
+		{name: "session open", submissionPhase: false, deadline: nil, expected: false},
+		{name: "session open with deadline", submissionPhase: false, deadline: &future, expected: false},
+		{name: "no deadline", submissionPhase: true, deadline: nil, expected: true},
+		{name: "deadline not reached", submissionPhase: true, deadline: &future, expected: true},
+		{name: "deadline reached", submissionPhase: true, deadline: &now, expected: false},
+		{name: "deadline passed", submissionPhase: true, deadline: &past, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SubmissionPhaseActive(tt.submissionPhase, tt.deadline, now); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func submissionItems() []*RetroItem {
+	return []*RetroItem{
+		{ID: "1", UserID: "author", Content: "went well", Comments: []*RetroItemComment{
+			{ID: "c1", UserID: "other", Comment: "agreed"},
+		}},
+		{ID: "2", UserID: "other", Content: "needs work", Comments: []*RetroItemComment{}},
+	}
+}
+
+// TestSubmissionVisibleItemsAuthor makes sure participants only see their own items
+func TestSubmissionVisibleItemsAuthor(t *testing.T) {
+	visible := SubmissionVisibleItems(submissionItems(), "author", false)
+	if len(visible) != 1 {
+		t.Fatalf("expected 1 item, got %d", len(visible))
+	}
+	if visible[0].ID != "1" || visible[0].UserID != "author" {
+		t.Errorf("expected own item with author, got %+v", visible[0])
+	}
+}
+
+// TestSubmissionVisibleItemsParticipant makes sure participants without items see nothing
+func TestSubmissionVisibleItemsParticipant(t *testing.T) {
+	visible := SubmissionVisibleItems(submissionItems(), "lurker", false)
+	if len(visible) != 0 {
+		t.Fatalf("expected no items, got %d", len(visible))
+	}
+}
+
+// TestSubmissionVisibleItemsFacilitator makes sure facilitators see every item without its author
+func TestSubmissionVisibleItemsFacilitator(t *testing.T) {
+	items := submissionItems()
+	visible := SubmissionVisibleItems(items, "facilitator", true)
+	if len(visible) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(visible))
+	}
+	for _, item := range visible {
+		if item.UserID != "" {
+			t.Errorf("expected item %s author to be hidden, got %s", item.ID, item.UserID)
+		}
+		for _, comment := range item.Comments {
+			if comment.UserID != "" {
+				t.Errorf("expected comment %s author to be hidden, got %s", comment.ID, comment.UserID)
+			}
+		}
+	}
+	if items[0].UserID != "author" || items[0].Comments[0].UserID != "other" {
+		t.Error("expected original items to be left untouched")
+	}
+}
+
+// TestSubmissionVisibleItemsFacilitatorOwn makes sure facilitators keep authorship of their own items
+func TestSubmissionVisibleItemsFacilitatorOwn(t *testing.T) {
+	visible := SubmissionVisibleItems(submissionItems(), "other", true)
+	if len(visible) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(visible))
+	}
+	if visible[1].UserID != "other" {
+		t.Errorf("expected own item author to be kept, got %q", visible[1].UserID)
+	}
+}
+
+// TestFocusVisibleItemsParticipant makes sure participants only see the focused item's content
+func TestFocusVisibleItemsParticipant(t *testing.T) {
+	activeItemID := "2"
+	visible := FocusVisibleItems(submissionItems(), &activeItemID, false)
+	if len(visible) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(visible))
+	}
+	if !visible[0].Redacted || visible[0].Content != "" || visible[0].UserID != "" || len(visible[0].Comments) != 0 {
+		t.Errorf("expected unfocused item to be redacted, got %+v", visible[0])
+	}
+	if visible[1].Redacted || visible[1].Content != "needs work" {
+		t.Errorf("expected focused item in full, got %+v", visible[1])
+	}
+}
+
+// TestFocusVisibleItemsFacilitator makes sure facilitators see every item in full while focused
+func TestFocusVisibleItemsFacilitator(t *testing.T) {
+	activeItemID := "2"
+	visible := FocusVisibleItems(submissionItems(), &activeItemID, true)
+	for _, item := range visible {
+		if item.Redacted || item.Content == "" {
+			t.Errorf("expected item %s in full, got %+v", item.ID, item)
+		}
+	}
+}
+
+// TestFocusVisibleItemsUnfocused makes sure items aren't redacted once the focus is removed
+func TestFocusVisibleItemsUnfocused(t *testing.T) {
+	visible := FocusVisibleItems(submissionItems(), nil, false)
+	for _, item := range visible {
+		if item.Redacted || item.Content == "" {
+			t.Errorf("expected item %s in full, got %+v", item.ID, item)
+		}
+	}
+}
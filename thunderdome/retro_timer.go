@@ -0,0 +1,90 @@
+package thunderdome
+
+import (
+	"errors"
+	"time"
+)
+
+// Retro timer pub/sub event types
+const (
+	RetroTimerEventStart = "timer:start"
+	RetroTimerEventPause = "timer:pause"
+)
+
+var (
+	// ErrInvalidTimerDuration is returned when starting a new retro category timer without a positive duration
+	ErrInvalidTimerDuration = errors.New("INVALID_TIMER_DURATION")
+	// ErrTimerNotRunning is returned when pausing a retro category timer that isn't running
+	ErrTimerNotRunning = errors.New("TIMER_NOT_RUNNING")
+)
+
+// RetroTimer is a countdown for brainstorming a retro category, durations are configured per retro
+type RetroTimer struct {
+	CategoryID      string     `json:"categoryId"`
+	DurationSeconds int        `json:"durationSeconds"`
+	StartedAt       *time.Time `json:"startedAt"`
+	PausedAt        *time.Time `json:"pausedAt"`
+}
+
+// RetroTimerEvent is a retro category timer being started or paused, published so every instance
+// with participants in the retro can relay the timer to them
+type RetroTimerEvent struct {
+	Type    string     `json:"type"`
+	RetroID string     `json:"retroId"`
+	Timer   RetroTimer `json:"timer"`
+}
+
+// Remaining returns how much time is left on the timer at the given time,
+// a paused timer keeps the time that was left when it was paused
+func (t RetroTimer) Remaining(now time.Time) time.Duration {
+	duration := time.Duration(t.DurationSeconds) * time.Second
+	if t.StartedAt == nil {
+		return duration
+	}
+	if t.PausedAt != nil {
+		now = *t.PausedAt
+	}
+
+	remaining := duration - now.Sub(*t.StartedAt)
+	if remaining < 0 {
+		return 0
+	}
+
+	return remaining
+}
+
+// Running returns whether the timer is counting down at the given time
+func (t RetroTimer) Running(now time.Time) bool {
+	return t.StartedAt != nil && t.PausedAt == nil && t.Remaining(now) > 0
+}
+
+// Start resumes a paused timer where it left off, otherwise the timer is restarted with the given duration
+func (t *RetroTimer) Start(durationSeconds int, now time.Time) error {
+	if t.StartedAt != nil && t.PausedAt != nil && t.Remaining(now) > 0 {
+		startedAt := t.StartedAt.Add(now.Sub(*t.PausedAt))
+		t.StartedAt = &startedAt
+		t.PausedAt = nil
+		return nil
+	}
+
+	if durationSeconds <= 0 {
+		return ErrInvalidTimerDuration
+	}
+
+	t.DurationSeconds = durationSeconds
+	t.StartedAt = &now
+	t.PausedAt = nil
+
+	return nil
+}
+
+// Pause stops the timer counting down keeping the time it has left
+func (t *RetroTimer) Pause(now time.Time) error {
+	if !t.Running(now) {
+		return ErrTimerNotRunning
+	}
+
+	t.PausedAt = &now
+
+	return nil
+}
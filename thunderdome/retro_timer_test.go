@@ -0,0 +1,80 @@
+package thunderdome
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestRetroTimerRemaining makes sure the remaining time counts down while running and holds while paused
+func TestRetroTimerRemaining(t *testing.T) {
+	start := time.Date(2025, 3, 15, 14, 0, 0, 0, time.UTC)
+	paused := start.Add(20 * time.Second)
+
+	tests := []struct {
+		name     string
+		timer    RetroTimer
+		now      time.Time
+		expected time.Duration
+	}{
+		{name: "not started", timer: RetroTimer{DurationSeconds: 60}, now: start, expected: time.Minute},
+		{name: "running", timer: RetroTimer{DurationSeconds: 60, StartedAt: &start}, now: start.Add(15 * time.Second), expected: 45 * time.Second},
+		{name: "paused", timer: RetroTimer{DurationSeconds: 60, StartedAt: &start, PausedAt: &paused}, now: start.Add(50 * time.Second), expected: 40 * time.Second},
+		{name: "expired", timer: RetroTimer{DurationSeconds: 60, StartedAt: &start}, now: start.Add(2 * time.Minute), expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.timer.Remaining(tt.now); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+// TestRetroTimerPauseResume makes sure a resumed timer picks up where it was paused
+func TestRetroTimerPauseResume(t *testing.T) {
+	start := time.Date(2025, 3, 15, 14, 0, 0, 0, time.UTC)
+	timer := RetroTimer{CategoryID: "worked"}
+
+	if err := timer.Start(60, start); err != nil {
+		t.Fatalf("unexpected start error: %v", err)
+	}
+	if err := timer.Pause(start.Add(10 * time.Second)); err != nil {
+		t.Fatalf("unexpected pause error: %v", err)
+	}
+	if err := timer.Pause(start.Add(20 * time.Second)); !errors.Is(err, ErrTimerNotRunning) {
+		t.Errorf("expected %v pausing a paused timer, got %v", ErrTimerNotRunning, err)
+	}
+	if err := timer.Start(30, start.Add(5*time.Minute)); err != nil {
+		t.Fatalf("unexpected resume error: %v", err)
+	}
+
+	if timer.DurationSeconds != 60 {
+		t.Errorf("expected resume to keep the 60 second duration, got %d", timer.DurationSeconds)
+	}
+	if got := timer.Remaining(start.Add(5*time.Minute + 10*time.Second)); got != 40*time.Second {
+		t.Errorf("expected 40s remaining, got %v", got)
+	}
+}
+
+// TestRetroTimerStart makes sure an expired or new timer restarts with the given duration
+func TestRetroTimerStart(t *testing.T) {
+	start := time.Date(2025, 3, 15, 14, 0, 0, 0, time.UTC)
+	timer := RetroTimer{CategoryID: "improve"}
+
+	if err := timer.Start(0, start); !errors.Is(err, ErrInvalidTimerDuration) {
+		t.Errorf("expected %v, got %v", ErrInvalidTimerDuration, err)
+	}
+	if err := timer.Start(60, start); err != nil {
+		t.Fatalf("unexpected start error: %v", err)
+	}
+
+	restart := start.Add(2 * time.Minute)
+	if err := timer.Start(90, restart); err != nil {
+		t.Fatalf("unexpected restart error: %v", err)
+	}
+	if !timer.Running(restart) || timer.Remaining(restart) != 90*time.Second {
+		t.Errorf("expected a fresh 90s timer, got %v remaining", timer.Remaining(restart))
+	}
+}
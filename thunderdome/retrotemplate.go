@@ -6,17 +6,19 @@ import (
 
 // RetroTemplate is a template for a retro board
 type RetroTemplate struct {
-	ID              string               `json:"id" db:"id"`
-	Name            string               `json:"name" db:"name"`
-	Description     string               `json:"description" db:"description"`
-	Format          *RetroTemplateFormat `json:"format" db:"format"`
-	IsPublic        bool                 `json:"isPublic" db:"is_public"`
-	DefaultTemplate bool                 `json:"defaultTemplate" db:"default_template"`
-	CreatedBy       string               `json:"createdBy" db:"created_by"`
-	OrganizationID  *string              `json:"organizationId" db:"organization_id"`
-	TeamID          *string              `json:"teamId" db:"team_id"`
-	CreatedAt       time.Time            `json:"createdAt" db:"created_at"`
-	UpdatedAt       time.Time            `json:"updatedAt" db:"updated_at"`
+	ID                   string               `json:"id" db:"id"`
+	Name                 string               `json:"name" db:"name"`
+	Description          string               `json:"description" db:"description"`
+	Format               *RetroTemplateFormat `json:"format" db:"format"`
+	IsPublic             bool                 `json:"isPublic" db:"is_public"`
+	IsPublicMarketplace  bool                 `json:"isPublicMarketplace" db:"is_public_marketplace"`
+	MarketplaceDownloads int                  `json:"marketplaceDownloads" db:"marketplace_downloads"`
+	DefaultTemplate      bool                 `json:"defaultTemplate" db:"default_template"`
+	CreatedBy            string               `json:"createdBy" db:"created_by"`
+	OrganizationID       *string              `json:"organizationId" db:"organization_id"`
+	TeamID               *string              `json:"teamId" db:"team_id"`
+	CreatedAt            time.Time            `json:"createdAt" db:"created_at"`
+	UpdatedAt            time.Time            `json:"updatedAt" db:"updated_at"`
 }
 
 type RetroTemplateFormatColumn struct {
@@ -30,3 +32,28 @@ type RetroTemplateFormatColumn struct {
 type RetroTemplateFormat struct {
 	Columns []RetroTemplateFormatColumn `json:"columns"`
 }
+
+// CanPublishRetroTemplate checks whether the user may publish the template to the marketplace,
+// only application admins and the template owner may
+func CanPublishRetroTemplate(template *RetroTemplate, userID string, userType string) bool {
+	return userType == AdminUserType || (template.CreatedBy != "" && template.CreatedBy == userID)
+}
+
+// MarketplaceTemplateCopy creates a copy of the marketplace template scoped to the organization,
+// the copy is private to the organization and doesn't share its format with the original
+func MarketplaceTemplateCopy(template *RetroTemplate, organizationID string, userID string) *RetroTemplate {
+	var format *RetroTemplateFormat
+	if template.Format != nil {
+		format = &RetroTemplateFormat{
+			Columns: append(make([]RetroTemplateFormatColumn, 0, len(template.Format.Columns)), template.Format.Columns...),
+		}
+	}
+
+	return &RetroTemplate{
+		Name:           template.Name,
+		Description:    template.Description,
+		Format:         format,
+		CreatedBy:      userID,
+		OrganizationID: &organizationID,
+	}
+}
@@ -0,0 +1,104 @@
+package thunderdome
+
+import (
+	"testing"
+)
+
+func marketplaceTemplate() *RetroTemplate {
+	orgID := "source-org"
+
+	return &RetroTemplate{
+		ID:          "template",
+		Name:        "Sailboat",
+		Description: "wind, anchors, rocks and island",
+		Format: &RetroTemplateFormat{Columns: []RetroTemplateFormatColumn{
+			{Name: "wind", Label: "Wind", Color: "green"},
+			{Name: "anchors", Label: "Anchors", Color: "red"},
+		}},
+		IsPublicMarketplace:  true,
+		MarketplaceDownloads: 12,
+		DefaultTemplate:      true,
+		CreatedBy:            "owner",
+		OrganizationID:       &orgID,
+	}
+}
+
+// TestMarketplaceTemplateCopy makes sure the installed copy is scoped to the installing organization
+func TestMarketplaceTemplateCopy(t *testing.T) {
+	installed := MarketplaceTemplateCopy(marketplaceTemplate(), "install-org", "installer")
+
+	if installed.ID != "" {
+		t.Errorf("expected copy to have no ID until created, got %s", installed.ID)
+	}
+	if installed.OrganizationID == nil || *installed.OrganizationID != "install-org" {
+		t.Errorf("expected copy to belong to install-org, got %v", installed.OrganizationID)
+	}
+	if installed.TeamID != nil {
+		t.Errorf("expected copy to not belong to a team, got %v", *installed.TeamID)
+	}
+	if installed.CreatedBy != "installer" {
+		t.Errorf("expected copy to be created by installer, got %s", installed.CreatedBy)
+	}
+	if installed.IsPublic || installed.IsPublicMarketplace || installed.DefaultTemplate || installed.MarketplaceDownloads != 0 {
+		t.Errorf("expected copy to be a private non default template, got %+v", installed)
+	}
+	if installed.Name != "Sailboat" || len(installed.Format.Columns) != 2 {
+		t.Errorf("expected copy to keep the template name and format, got %+v", installed)
+	}
+}
+
+// TestMarketplaceTemplateCopyLeavesOriginal makes sure installing doesn't mutate the original template
+func TestMarketplaceTemplateCopyLeavesOriginal(t *testing.T) {
+	original := marketplaceTemplate()
+	installed := MarketplaceTemplateCopy(original, "install-org", "installer")
+
+	installed.Name = "Renamed"
+	installed.Format.Columns[0].Label = "Tailwind"
+	installed.Format.Columns = append(installed.Format.Columns, RetroTemplateFormatColumn{Name: "rocks", Label: "Rocks"})
+	*installed.OrganizationID = "other-org"
+
+	expected := marketplaceTemplate()
+	if original.Name != expected.Name || original.CreatedBy != expected.CreatedBy ||
+		original.MarketplaceDownloads != expected.MarketplaceDownloads || !original.IsPublicMarketplace {
+		t.Errorf("expected original template to be unchanged, got %+v", original)
+	}
+	if *original.OrganizationID != *expected.OrganizationID {
+		t.Errorf("expected original organization to be unchanged, got %s", *original.OrganizationID)
+	}
+	if len(original.Format.Columns) != len(expected.Format.Columns) {
+		t.Fatalf("expected original to keep %d columns, got %d", len(expected.Format.Columns), len(original.Format.Columns))
+	}
+	for i, column := range original.Format.Columns {
+		if column != expected.Format.Columns[i] {
+			t.Errorf("expected original column %d to be %+v, got %+v", i, expected.Format.Columns[i], column)
+		}
+	}
+}
+
+// TestCanPublishRetroTemplate makes sure only the template owner or an admin may publish
+func TestCanPublishRetroTemplate(t *testing.T) {
+	template := marketplaceTemplate()
+
+	tests := []struct {
+		name     string
+		userID   string
+		userType string
+		expected bool
+	}{
+		{name: "owner", userID: "owner", userType: RegisteredUserType, expected: true},
+		{name: "admin", userID: "admin", userType: AdminUserType, expected: true},
+		{name: "other user", userID: "other", userType: RegisteredUserType, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CanPublishRetroTemplate(template, tt.userID, tt.userType); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+
+	if CanPublishRetroTemplate(&RetroTemplate{}, "", RegisteredUserType) {
+		t.Error("expected ownerless template to require an admin")
+	}
+}
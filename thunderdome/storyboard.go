@@ -1,5 +1,7 @@
 package thunderdome
 
+import "time"
+
 // StoryboardUser aka user
 type StoryboardUser struct {
 	ID           string `json:"id"`
@@ -25,6 +27,7 @@ type Storyboard struct {
 	FacilitatorCode string               `json:"facilitatorCode" db:"facilitator_code"`
 	TeamID          string               `json:"teamId" db:"team_id"`
 	TeamName        string               `json:"teamName"`
+	MemberCount     int                  `json:"memberCount"`
 	CreatedDate     string               `json:"createdDate" db:"created_date"`
 	UpdatedDate     string               `json:"updatedDate" db:"updated_date"`
 }
@@ -78,3 +81,12 @@ type StoryboardPersona struct {
 	Role        string `json:"role"`
 	Description string `json:"description"`
 }
+
+// CursorPosition A storyboard user's live cursor position, cursors are only kept in memory
+type CursorPosition struct {
+	UserID      string    `json:"userId"`
+	Username    string    `json:"username"`
+	X           float64   `json:"x"`
+	Y           float64   `json:"y"`
+	LastUpdated time.Time `json:"lastUpdated"`
+}
@@ -0,0 +1,100 @@
+package thunderdome
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// StoryboardTemplate is a template for a storyboard's goals and columns, scoped like estimation scales:
+// public templates are for everyone, otherwise the template belongs to an organization or a team
+type StoryboardTemplate struct {
+	ID          string          `json:"id" db:"id"`
+	OrgID       *string         `json:"organizationId" db:"organization_id"`
+	TeamID      *string         `json:"teamId" db:"team_id"`
+	Name        string          `json:"name" db:"name"`
+	Description string          `json:"description" db:"description"`
+	Format      json.RawMessage `json:"format" db:"format" swaggertype:"object"`
+	IsPublic    bool            `json:"isPublic" db:"is_public"`
+	CreatedBy   string          `json:"createdBy" db:"created_by"`
+	CreatedAt   time.Time       `json:"createdAt" db:"created_at"`
+	UpdatedAt   time.Time       `json:"updatedAt" db:"updated_at"`
+}
+
+// StoryboardTemplateFormat is the structure a storyboard created from a template starts with
+type StoryboardTemplateFormat struct {
+	Goals []StoryboardTemplateGoal `json:"goals"`
+}
+
+// StoryboardTemplateGoal is a goal row of a storyboard template
+type StoryboardTemplateGoal struct {
+	Name    string                     `json:"name"`
+	Columns []StoryboardTemplateColumn `json:"columns"`
+}
+
+// StoryboardTemplateColumn is a column of a storyboard template goal
+type StoryboardTemplateColumn struct {
+	Name string `json:"name"`
+}
+
+// ParseStoryboardTemplateFormat parses the template format, it must have at least one goal and every goal
+// and column must be named
+func ParseStoryboardTemplateFormat(format json.RawMessage) (*StoryboardTemplateFormat, error) {
+	var f StoryboardTemplateFormat
+	if err := json.Unmarshal(format, &f); err != nil {
+		return nil, errors.New("INVALID_STORYBOARD_TEMPLATE_FORMAT")
+	}
+	if len(f.Goals) == 0 {
+		return nil, errors.New("INVALID_STORYBOARD_TEMPLATE_FORMAT")
+	}
+	for _, goal := range f.Goals {
+		if goal.Name == "" {
+			return nil, errors.New("INVALID_STORYBOARD_TEMPLATE_FORMAT")
+		}
+		for _, column := range goal.Columns {
+			if column.Name == "" {
+				return nil, errors.New("INVALID_STORYBOARD_TEMPLATE_FORMAT")
+			}
+		}
+	}
+
+	return &f, nil
+}
+
+// CanViewStoryboardTemplate checks whether the user may use the template, orgRole is the user's role in the
+// template's organization and teamRoles the user's roles for the template's team, if any
+func CanViewStoryboardTemplate(template *StoryboardTemplate, userType string, orgRole string, teamRoles *UserTeamRoleInfo) bool {
+	switch {
+	case template.IsPublic || userType == AdminUserType:
+		return true
+	case template.OrgID != nil:
+		return orgRole != ""
+	case template.TeamID != nil:
+		return teamRoles != nil && (teamRoles.AssociationLevel == "TEAM" ||
+			isAdminRole(teamRoles.DepartmentRole) || isAdminRole(teamRoles.OrganizationRole))
+	}
+
+	return false
+}
+
+// CanManageStoryboardTemplate checks whether the user may update or delete the template,
+// public templates are managed by application admins, private ones by their organization or team admins
+func CanManageStoryboardTemplate(template *StoryboardTemplate, userType string, orgRole string, teamRoles *UserTeamRoleInfo) bool {
+	switch {
+	case userType == AdminUserType:
+		return true
+	case template.IsPublic:
+		return false
+	case template.OrgID != nil:
+		return orgRole == AdminUserType
+	case template.TeamID != nil:
+		return teamRoles != nil && (isAdminRole(teamRoles.TeamRole) ||
+			isAdminRole(teamRoles.DepartmentRole) || isAdminRole(teamRoles.OrganizationRole))
+	}
+
+	return false
+}
+
+func isAdminRole(role *string) bool {
+	return role != nil && *role == AdminUserType
+}
@@ -0,0 +1,91 @@
+package thunderdome
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestStoryboardTemplateScoping makes sure templates are used and managed with the same scoping as estimation scales
+func TestStoryboardTemplateScoping(t *testing.T) {
+	orgID := "org"
+	teamID := "team"
+	admin := AdminUserType
+	member := EntityMemberUserType
+
+	public := &StoryboardTemplate{IsPublic: true}
+	private := &StoryboardTemplate{}
+	orgTemplate := &StoryboardTemplate{OrgID: &orgID}
+	teamTemplate := &StoryboardTemplate{TeamID: &teamID}
+
+	tests := []struct {
+		name      string
+		template  *StoryboardTemplate
+		userType  string
+		orgRole   string
+		teamRoles *UserTeamRoleInfo
+		canView   bool
+		canManage bool
+	}{
+		{name: "public template user", template: public, userType: RegisteredUserType, canView: true},
+		{name: "public template admin", template: public, userType: AdminUserType, canView: true, canManage: true},
+		{name: "private template user", template: private, userType: RegisteredUserType},
+		{name: "private template admin", template: private, userType: AdminUserType, canView: true, canManage: true},
+		{name: "org template outsider", template: orgTemplate, userType: RegisteredUserType},
+		{name: "org template member", template: orgTemplate, userType: RegisteredUserType, orgRole: EntityMemberUserType, canView: true},
+		{name: "org template org admin", template: orgTemplate, userType: RegisteredUserType, orgRole: AdminUserType, canView: true, canManage: true},
+		{name: "team template outsider", template: teamTemplate, userType: RegisteredUserType},
+		{name: "team template member", template: teamTemplate, userType: RegisteredUserType,
+			teamRoles: &UserTeamRoleInfo{TeamRole: &member, AssociationLevel: "TEAM"}, canView: true},
+		{name: "team template team admin", template: teamTemplate, userType: RegisteredUserType,
+			teamRoles: &UserTeamRoleInfo{TeamRole: &admin, AssociationLevel: "TEAM"}, canView: true, canManage: true},
+		{name: "team template org admin", template: teamTemplate, userType: RegisteredUserType,
+			teamRoles: &UserTeamRoleInfo{OrganizationRole: &admin, AssociationLevel: "ORGANIZATION"}, canView: true, canManage: true},
+		{name: "team template org member", template: teamTemplate, userType: RegisteredUserType,
+			teamRoles: &UserTeamRoleInfo{OrganizationRole: &member, AssociationLevel: "ORGANIZATION"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CanViewStoryboardTemplate(tt.template, tt.userType, tt.orgRole, tt.teamRoles); got != tt.canView {
+				t.Errorf("expected can view %v, got %v", tt.canView, got)
+			}
+			if got := CanManageStoryboardTemplate(tt.template, tt.userType, tt.orgRole, tt.teamRoles); got != tt.canManage {
+				t.Errorf("expected can manage %v, got %v", tt.canManage, got)
+			}
+		})
+	}
+}
+
+// TestParseStoryboardTemplateFormat makes sure only formats with named goals and columns are accepted
+func TestParseStoryboardTemplateFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		goals   int
+		wantErr bool
+	}{
+		{name: "goals with columns", format: `{"goals":[{"name":"Onboarding","columns":[{"name":"Sign up"},{"name":"Verify"}]},{"name":"Billing"}]}`, goals: 2},
+		{name: "no goals", format: `{"goals":[]}`, wantErr: true},
+		{name: "unnamed goal", format: `{"goals":[{"name":""}]}`, wantErr: true},
+		{name: "unnamed column", format: `{"goals":[{"name":"Onboarding","columns":[{"name":""}]}]}`, wantErr: true},
+		{name: "not an object", format: `["Onboarding"]`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, err := ParseStoryboardTemplateFormat(json.RawMessage(tt.format))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(format.Goals) != tt.goals {
+				t.Errorf("expected %d goals, got %d", tt.goals, len(format.Goals))
+			}
+		})
+	}
+}
@@ -18,3 +18,37 @@ type Subscription struct {
 	UpdatedDate    time.Time `json:"updated_date"`
 	User           User      `json:"user"`
 }
+
+// SubscriptionTier is the level of subscription an organization has, advanced features require a minimum tier
+type SubscriptionTier string
+
+// Subscription tiers from lowest to highest
+const (
+	SubscriptionTierFree       SubscriptionTier = "free"
+	SubscriptionTierTeam       SubscriptionTier = "team"
+	SubscriptionTierEnterprise SubscriptionTier = "enterprise"
+)
+
+var subscriptionTierRanks = map[SubscriptionTier]int{
+	SubscriptionTierFree:       0,
+	SubscriptionTierTeam:       1,
+	SubscriptionTierEnterprise: 2,
+}
+
+// Meets returns whether the tier is at least the minimum tier, unknown tiers are treated as free
+func (t SubscriptionTier) Meets(minTier SubscriptionTier) bool {
+	return subscriptionTierRanks[t] >= subscriptionTierRanks[minTier]
+}
+
+// SubscriptionTypeTier gets the tier a subscription type grants, organization subscriptions are enterprise
+// and team subscriptions are team, anything else is free
+func SubscriptionTypeTier(subscriptionType string) SubscriptionTier {
+	switch subscriptionType {
+	case "organization":
+		return SubscriptionTierEnterprise
+	case "team":
+		return SubscriptionTierTeam
+	default:
+		return SubscriptionTierFree
+	}
+}
@@ -0,0 +1,31 @@
+package thunderdome
+
+import "testing"
+
+// TestSubscriptionTierMeets makes sure each tier only meets the minimum tiers at or below it
+func TestSubscriptionTierMeets(t *testing.T) {
+	tests := []struct {
+		tier     SubscriptionTier
+		minTier  SubscriptionTier
+		expected bool
+	}{
+		{tier: SubscriptionTierFree, minTier: SubscriptionTierFree, expected: true},
+		{tier: SubscriptionTierFree, minTier: SubscriptionTierTeam, expected: false},
+		{tier: SubscriptionTierFree, minTier: SubscriptionTierEnterprise, expected: false},
+		{tier: SubscriptionTierTeam, minTier: SubscriptionTierFree, expected: true},
+		{tier: SubscriptionTierTeam, minTier: SubscriptionTierTeam, expected: true},
+		{tier: SubscriptionTierTeam, minTier: SubscriptionTierEnterprise, expected: false},
+		{tier: SubscriptionTierEnterprise, minTier: SubscriptionTierFree, expected: true},
+		{tier: SubscriptionTierEnterprise, minTier: SubscriptionTierTeam, expected: true},
+		{tier: SubscriptionTierEnterprise, minTier: SubscriptionTierEnterprise, expected: true},
+		{tier: "unknown", minTier: SubscriptionTierTeam, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.tier)+" meets "+string(tt.minTier), func(t *testing.T) {
+			if got := tt.tier.Meets(tt.minTier); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -66,3 +66,46 @@ type UserTeamRoleInfo struct {
 	OrganizationRole *string `db:"organization_role" json:"organizationRole"`
 	AssociationLevel string  `db:"association_level" json:"associationLevel"`
 }
+
+// Velocity trend granularities
+const (
+	VelocityGranularityWeek  = "week"
+	VelocityGranularityMonth = "month"
+)
+
+// VelocityDataPoint is a team's completed poker estimation totals for a single week or month,
+// Period is the start date of a week (YYYY-MM-DD) or the month (YYYY-MM)
+type VelocityDataPoint struct {
+	Period           string  `json:"period"`
+	PointsCompleted  float64 `json:"pointsCompleted"`
+	StoriesCompleted int     `json:"storiesCompleted"`
+	GamesCompleted   int     `json:"gamesCompleted"`
+}
+
+// MemberActivityScore is a team member's weighted activity across the team's games, retros and standups
+type MemberActivityScore struct {
+	UserID             string `json:"userId"`
+	Username           string `json:"username"`
+	GamesParticipated  int    `json:"gamesParticipated"`
+	StoriesVoted       int    `json:"storiesVoted"`
+	RetrosParticipated int    `json:"retrosParticipated"`
+	StandupsFiled      int    `json:"standupsFiled"`
+	TotalScore         int    `json:"totalScore"`
+}
+
+// ActivityScoreWeights are the points each activity adds to a team member's activity score
+type ActivityScoreWeights struct {
+	Game      int `mapstructure:"game" json:"game"`
+	StoryVote int `mapstructure:"story_vote" json:"storyVote"`
+	Retro     int `mapstructure:"retro" json:"retro"`
+	Standup   int `mapstructure:"standup" json:"standup"`
+}
+
+// RetroCadenceStats is how consistently a team holds retros, LongestGap is in nanoseconds when encoded
+// and is 0 when the team has fewer than two retros
+type RetroCadenceStats struct {
+	AverageDaysBetweenRetros float64       `json:"averageDaysBetweenRetros"`
+	LongestGap               time.Duration `json:"longestGap"`
+	LastRetroDate            *time.Time    `json:"lastRetroDate"`
+	TotalRetros              int           `json:"totalRetros"`
+}
@@ -41,4 +41,10 @@ type User struct {
 	Disabled             bool      `json:"disabled"`
 	Theme                string    `json:"theme"`
 	Picture              string    `json:"picture"`
+	// RegistrationStatus is only pending or rejected when registration approval is required
+	RegistrationStatus RegistrationStatus `json:"registrationStatus,omitempty"`
+	// RegistrationRejectedReason is the reason given by the admin that rejected the registration
+	RegistrationRejectedReason string `json:"registrationRejectedReason,omitempty"`
+	// MergedIntoUserID is the user this duplicate account was merged into by an admin
+	MergedIntoUserID string `json:"mergedIntoUserId,omitempty"`
 }
@@ -0,0 +1,26 @@
+package thunderdome
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// AnonymizedUserHash gets a short hash of the user ID used to keep anonymized profiles unique
+func AnonymizedUserHash(userID string) string {
+	sum := sha256.Sum256([]byte(userID))
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// AnonymizeUser replaces the user's identifying profile fields, the user ID is kept
+// so the user's game participation remains intact
+func AnonymizeUser(user *User) {
+	hash := AnonymizedUserHash(user.ID)
+	user.Name = "Anonymized User " + hash
+	user.Email = "anonymized+" + hash + "@thunderdome.invalid"
+	user.Avatar = ""
+	user.Picture = ""
+	user.Country = ""
+	user.Company = ""
+	user.JobTitle = ""
+	user.GravatarHash = ""
+}
@@ -0,0 +1,37 @@
+package thunderdome
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAnonymizeUser(t *testing.T) {
+	user := &User{
+		ID:       "a",
+		Name:     "Thor",
+		Email:    "thor@asgard.dev",
+		Avatar:   "robohash",
+		Picture:  "https://asgard.dev/thor.png",
+		Country:  "NO",
+		Company:  "Asgard",
+		JobTitle: "God of Thunder",
+	}
+	AnonymizeUser(user)
+
+	hash := AnonymizedUserHash("a")
+	if user.ID != "a" {
+		t.Errorf("expected the user ID to be kept, got %s", user.ID)
+	}
+	if user.Name != "Anonymized User "+hash || user.Email != "anonymized+"+hash+"@thunderdome.invalid" {
+		t.Errorf("unexpected anonymized name %q and email %q", user.Name, user.Email)
+	}
+	if user.Avatar != "" || user.Picture != "" || user.Country != "" || user.Company != "" || user.JobTitle != "" {
+		t.Errorf("expected profile fields to be cleared, got %+v", user)
+	}
+	if strings.Contains(user.Name+user.Email, "thor") {
+		t.Errorf("expected no identifying data to remain, got %q %q", user.Name, user.Email)
+	}
+	if AnonymizedUserHash("b") == hash {
+		t.Error("expected different users to get different hashes")
+	}
+}
@@ -0,0 +1,19 @@
+package thunderdome
+
+import "errors"
+
+// ErrUserMerged is returned when a user attempts to log in to an account that was merged into another
+var ErrUserMerged = errors.New("USER_MERGED")
+
+// ErrInvalidUserMerge is returned when the source user can't be merged into the target user
+var ErrInvalidUserMerge = errors.New("INVALID_USER_MERGE")
+
+// ValidateUserMerge checks whether the source user can be merged into the target user,
+// a user can't be merged into themselves and merged users can't be merged from or into again
+func ValidateUserMerge(source *User, target *User) error {
+	if source.ID == target.ID || source.MergedIntoUserID != "" || target.MergedIntoUserID != "" {
+		return ErrInvalidUserMerge
+	}
+
+	return nil
+}
@@ -0,0 +1,28 @@
+package thunderdome
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateUserMerge(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  *User
+		target  *User
+		wantErr error
+	}{
+		{name: "duplicate accounts", source: &User{ID: "a"}, target: &User{ID: "b"}},
+		{name: "same user", source: &User{ID: "a"}, target: &User{ID: "a"}, wantErr: ErrInvalidUserMerge},
+		{name: "source already merged", source: &User{ID: "a", MergedIntoUserID: "c"}, target: &User{ID: "b"}, wantErr: ErrInvalidUserMerge},
+		{name: "target already merged", source: &User{ID: "a"}, target: &User{ID: "b", MergedIntoUserID: "c"}, wantErr: ErrInvalidUserMerge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateUserMerge(tt.source, tt.target); !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
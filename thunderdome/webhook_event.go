@@ -0,0 +1,49 @@
+package thunderdome
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// WebhookEventLogRetention is how long webhook delivery attempts are kept in the event log
+const WebhookEventLogRetention = 30 * 24 * time.Hour
+
+// Webhook event log delivery status filters
+const (
+	WebhookEventStatusSuccess = "success"
+	WebhookEventStatusFailure = "failure"
+)
+
+// ErrInvalidWebhookEventStatus is returned when filtering the webhook event log by an unknown delivery status
+var ErrInvalidWebhookEventStatus = errors.New("INVALID_WEBHOOK_EVENT_STATUS")
+
+// WebhookEvent is a logged webhook delivery attempt, failed attempts include the error detail.
+// Replays of a delivery share its delivery ID so the receiver can tell them apart from new events
+type WebhookEvent struct {
+	ID           string          `json:"id"`
+	DeliveryID   string          `json:"deliveryId"`
+	EventType    string          `json:"eventType"`
+	Payload      json.RawMessage `json:"payload" swaggertype:"object"`
+	HTTPStatus   int             `json:"httpStatus"`
+	ResponseBody string          `json:"responseBody"`
+	Error        string          `json:"error,omitempty"`
+	Succeeded    bool            `json:"succeeded"`
+	SentAt       time.Time       `json:"sentAt"`
+}
+
+// WebhookDeliverySucceeded reports whether the webhook delivery was received, a delivery without
+// an error that got a 2xx response
+func WebhookDeliverySucceeded(httpStatus int, deliveryErr string) bool {
+	return deliveryErr == "" && httpStatus >= 200 && httpStatus <= 299
+}
+
+// ParseWebhookEventStatus validates the webhook event log status filter, an empty status matches every delivery
+func ParseWebhookEventStatus(status string) (string, error) {
+	switch status {
+	case "", WebhookEventStatusSuccess, WebhookEventStatusFailure:
+		return status, nil
+	default:
+		return "", ErrInvalidWebhookEventStatus
+	}
+}
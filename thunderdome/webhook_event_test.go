@@ -0,0 +1,39 @@
+package thunderdome
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWebhookDeliverySucceeded(t *testing.T) {
+	tests := []struct {
+		name       string
+		httpStatus int
+		err        string
+		want       bool
+	}{
+		{name: "ok", httpStatus: 200, want: true},
+		{name: "accepted", httpStatus: 202, want: true},
+		{name: "server error", httpStatus: 500},
+		{name: "redirect", httpStatus: 302},
+		{name: "unreachable", err: "billing webhook send error"},
+		{name: "unreadable response", httpStatus: 200, err: "billing webhook read response error"},
+	}
+
+	for _, tt := range tests {
+		if got := WebhookDeliverySucceeded(tt.httpStatus, tt.err); got != tt.want {
+			t.Errorf("%s expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestParseWebhookEventStatus(t *testing.T) {
+	for _, status := range []string{"", WebhookEventStatusSuccess, WebhookEventStatusFailure} {
+		if got, err := ParseWebhookEventStatus(status); err != nil || got != status {
+			t.Errorf("expected status %q to be valid, got %q %v", status, got, err)
+		}
+	}
+	if _, err := ParseWebhookEventStatus("pending"); !errors.Is(err, ErrInvalidWebhookEventStatus) {
+		t.Errorf("expected %v for an unknown status, got %v", ErrInvalidWebhookEventStatus, err)
+	}
+}
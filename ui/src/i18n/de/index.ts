@@ -376,6 +376,7 @@ const de: Translation = {
   retroAddSuccess: 'Retro erfolgreich hinzugefügt.',
   retroDeleted: 'Retro gelöscht',
   retroFeedbackConcealed: 'Feedback versteckt',
+  retroItemEmotion: 'Emotion',
   retroFeedbackHidden: 'Feedback ausgeblendet',
   retroImprovePlaceholder: 'Was verbessert werden muss...',
   retroItems: 'Retro Punkte',
@@ -623,6 +624,19 @@ const de: Translation = {
   deptUpdateSuccess: 'Abteilung erfolgreich aktualisiert',
   deptUpdateError: 'Fehler beim Aktualisieren der Abteilung',
   hideVoterIdentity: 'Identität des Schätzers verbergen',
+  autoFinalizeOnConsensus: 'Auto Finalize Story on Consensus',
+  minParticipants: 'Minimum participants to start voting',
+  waitingForParticipants:
+    'Waiting for participants, {currentCount} of {requiredCount} needed to start voting',
+  timeBoxMinutes: 'Story time box in minutes (0 for none)',
+  timeBoxExpired: 'Time is up, voting has ended',
+  quorumPercentage: 'Vote quorum percentage to auto finalize (0 for none)',
+  quorumNotReached:
+    'Quorum not reached, {currentPercentage}% of {quorumPercentage}% voted',
+  observerCode: 'Observer Code',
+  optionalObservercodePlaceholder: 'Optional observer code',
+  passwordUpdateRequired:
+    'Your password no longer meets the password requirements, please update it from your profile.',
   storyboardName: 'Storyboard Name',
   storyboardNamePlaceholder: 'Geben Sie einen Storyboard-Namen ein',
   retroPhaseTimeLimitMinLabel: 'Brainstorm Phase Zeitlimite in Minuten',
@@ -361,6 +361,7 @@ const es: Translation = {
   retroAddSuccess: 'Retro agregado con éxito.',
   retroDeleted: 'Retro eliminado',
   retroFeedbackConcealed: 'Comentarios Escondidos',
+  retroItemEmotion: 'Emoción',
   retroFeedbackHidden: 'Comentarios Ocultos',
   retroImprovePlaceholder: 'Qué necesita mejorar...',
   retroItems: 'Elementos Retro',
@@ -603,6 +604,19 @@ const es: Translation = {
   deptUpdateSuccess: 'Departamento actualizado con éxito',
   deptUpdateError: 'Error al actualizar el Departamento',
   hideVoterIdentity: 'Ocultar Identidad del Votante',
+  autoFinalizeOnConsensus: 'Auto Finalize Story on Consensus',
+  minParticipants: 'Minimum participants to start voting',
+  waitingForParticipants:
+    'Waiting for participants, {currentCount} of {requiredCount} needed to start voting',
+  timeBoxMinutes: 'Story time box in minutes (0 for none)',
+  timeBoxExpired: 'Time is up, voting has ended',
+  quorumPercentage: 'Vote quorum percentage to auto finalize (0 for none)',
+  quorumNotReached:
+    'Quorum not reached, {currentPercentage}% of {quorumPercentage}% voted',
+  observerCode: 'Observer Code',
+  optionalObservercodePlaceholder: 'Optional observer code',
+  passwordUpdateRequired:
+    'Your password no longer meets the password requirements, please update it from your profile.',
   storyboardName: 'Nombre del Storyboard',
   storyboardNamePlaceholder: 'Ingresa un nombre de storyboard',
   retroPhaseTimeLimitMinLabel:
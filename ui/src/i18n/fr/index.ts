@@ -376,6 +376,7 @@ const fr: Translation = {
   retroAddSuccess: 'Rétro ajoutée avec succès.',
   retroDeleted: 'Rétro supprimée',
   retroFeedbackConcealed: 'Retour dissimulé',
+  retroItemEmotion: 'Émotion',
   retroFeedbackHidden: 'Retour caché',
   retroImprovePlaceholder: 'Ce qui doit être amélioré...',
   retroItems: 'Éléments de rétro',
@@ -633,6 +634,19 @@ const fr: Translation = {
   deptUpdateSuccess: 'Département mis à jour avec succès',
   deptUpdateError: 'Erreur lors de la mise à jour du département',
   hideVoterIdentity: "Masquer l'identité du votant",
+  autoFinalizeOnConsensus: 'Auto Finalize Story on Consensus',
+  minParticipants: 'Minimum participants to start voting',
+  waitingForParticipants:
+    'Waiting for participants, {currentCount} of {requiredCount} needed to start voting',
+  timeBoxMinutes: 'Story time box in minutes (0 for none)',
+  timeBoxExpired: 'Time is up, voting has ended',
+  quorumPercentage: 'Vote quorum percentage to auto finalize (0 for none)',
+  quorumNotReached:
+    'Quorum not reached, {currentPercentage}% of {quorumPercentage}% voted',
+  observerCode: 'Observer Code',
+  optionalObservercodePlaceholder: 'Optional observer code',
+  passwordUpdateRequired:
+    'Your password no longer meets the password requirements, please update it from your profile.',
   storyboardName: 'Nom du storyboard',
   storyboardNamePlaceholder: 'Entrez un nom de storyboard',
   retroPhaseTimeLimitMinLabel:
@@ -1381,6 +1381,10 @@ type RootTranslation = {
    * F​e​e​d​b​a​c​k​ ​C​o​n​c​e​a​l​e​d
    */
   retroFeedbackConcealed: string;
+  /**
+   * E​m​o​t​i​o​n
+   */
+  retroItemEmotion: string;
   /**
    * F​e​e​d​b​a​c​k​ ​H​i​d​d​e​n
    */
@@ -2312,6 +2316,50 @@ type RootTranslation = {
    * H​i​d​e​ ​V​o​t​e​r​ ​I​d​e​n​t​i​t​y
    */
   hideVoterIdentity: string;
+  /**
+   * A​u​t​o​ ​F​i​n​a​l​i​z​e​ ​S​t​o​r​y​ ​o​n​ ​C​o​n​s​e​n​s​u​s
+   */
+  autoFinalizeOnConsensus: string;
+  /**
+   * M​i​n​i​m​u​m​ ​p​a​r​t​i​c​i​p​a​n​t​s​ ​t​o​ ​s​t​a​r​t​ ​v​o​t​i​n​g
+   */
+  minParticipants: string;
+  /**
+   * W​a​i​t​i​n​g​ ​f​o​r​ ​p​a​r​t​i​c​i​p​a​n​t​s​,​ ​{​c​u​r​r​e​n​t​C​o​u​n​t​}​ ​o​f​ ​{​r​e​q​u​i​r​e​d​C​o​u​n​t​}​ ​n​e​e​d​e​d​ ​t​o​ ​s​t​a​r​t​ ​v​o​t​i​n​g
+   * @param {unknown} currentCount
+   * @param {unknown} requiredCount
+   */
+  waitingForParticipants: RequiredParams<'currentCount' | 'requiredCount'>;
+  /**
+   * S​t​o​r​y​ ​t​i​m​e​ ​b​o​x​ ​i​n​ ​m​i​n​u​t​e​s​ ​(​0​ ​f​o​r​ ​n​o​n​e​)
+   */
+  timeBoxMinutes: string;
+  /**
+   * T​i​m​e​ ​i​s​ ​u​p​,​ ​v​o​t​i​n​g​ ​h​a​s​ ​e​n​d​e​d
+   */
+  timeBoxExpired: string;
+  /**
+   * V​o​t​e​ ​q​u​o​r​u​m​ ​p​e​r​c​e​n​t​a​g​e​ ​t​o​ ​a​u​t​o​ ​f​i​n​a​l​i​z​e​ ​(​0​ ​f​o​r​ ​n​o​n​e​)
+   */
+  quorumPercentage: string;
+  /**
+   * Q​u​o​r​u​m​ ​n​o​t​ ​r​e​a​c​h​e​d​,​ ​{​c​u​r​r​e​n​t​P​e​r​c​e​n​t​a​g​e​}​%​ ​o​f​ ​{​q​u​o​r​u​m​P​e​r​c​e​n​t​a​g​e​}​%​ ​v​o​t​e​d
+   * @param {unknown} currentPercentage
+   * @param {unknown} quorumPercentage
+   */
+  quorumNotReached: RequiredParams<'currentPercentage' | 'quorumPercentage'>;
+  /**
+   * O​b​s​e​r​v​e​r​ ​C​o​d​e
+   */
+  observerCode: string;
+  /**
+   * O​p​t​i​o​n​a​l​ ​o​b​s​e​r​v​e​r​ ​c​o​d​e
+   */
+  optionalObservercodePlaceholder: string;
+  /**
+   * Y​o​u​r​ ​p​a​s​s​w​o​r​d​ ​n​o​ ​l​o​n​g​e​r​ ​m​e​e​t​s​ ​t​h​e​ ​p​a​s​s​w​o​r​d​ ​r​e​q​u​i​r​e​m​e​n​t​s​,​ ​p​l​e​a​s​e​ ​u​p​d​a​t​e​ ​i​t​ ​f​r​o​m​ ​y​o​u​r​ ​p​r​o​f​i​l​e​.
+   */
+  passwordUpdateRequired: string;
   /**
    * S​t​o​r​y​b​o​a​r​d​ ​N​a​m​e
    */
@@ -3923,6 +3971,10 @@ export type TranslationFunctions = {
    * Feedback Concealed
    */
   retroFeedbackConcealed: () => LocalizedString;
+  /**
+   * Emotion
+   */
+  retroItemEmotion: () => LocalizedString;
   /**
    * Feedback Hidden
    */
@@ -4847,6 +4899,52 @@ export type TranslationFunctions = {
    * Hide Voter Identity
    */
   hideVoterIdentity: () => LocalizedString;
+  /**
+   * Auto Finalize Story on Consensus
+   */
+  autoFinalizeOnConsensus: () => LocalizedString;
+  /**
+   * Minimum participants to start voting
+   */
+  minParticipants: () => LocalizedString;
+  /**
+   * Waiting for participants, {currentCount} of {requiredCount} needed to start voting
+   */
+  waitingForParticipants: (arg: {
+    currentCount: unknown;
+    requiredCount: unknown;
+  }) => LocalizedString;
+  /**
+   * Story time box in minutes (0 for none)
+   */
+  timeBoxMinutes: () => LocalizedString;
+  /**
+   * Time is up, voting has ended
+   */
+  timeBoxExpired: () => LocalizedString;
+  /**
+   * Vote quorum percentage to auto finalize (0 for none)
+   */
+  quorumPercentage: () => LocalizedString;
+  /**
+   * Quorum not reached, {currentPercentage}% of {quorumPercentage}% voted
+   */
+  quorumNotReached: (arg: {
+    currentPercentage: unknown;
+    quorumPercentage: unknown;
+  }) => LocalizedString;
+  /**
+   * Observer Code
+   */
+  observerCode: () => LocalizedString;
+  /**
+   * Optional observer code
+   */
+  optionalObservercodePlaceholder: () => LocalizedString;
+  /**
+   * Your password no longer meets the password requirements, please update it from your profile.
+   */
+  passwordUpdateRequired: () => LocalizedString;
   /**
    * Storyboard Name
    */
@@ -349,6 +349,7 @@ const ru: Translation = {
   retroAddSuccess: 'Retro added successfully.',
   retroDeleted: 'Retro deleted',
   retroFeedbackConcealed: 'Feedback Concealed',
+  retroItemEmotion: 'Emotion',
   retroFeedbackHidden: 'Feedback Hidden',
   retroImprovePlaceholder: 'What needs improvement...',
   retroItems: 'Retro Items',
@@ -583,6 +584,19 @@ const ru: Translation = {
   deptUpdateSuccess: 'Department updated successfully',
   deptUpdateError: 'Error updating Department',
   hideVoterIdentity: 'Hide Voter Identity',
+  autoFinalizeOnConsensus: 'Auto Finalize Story on Consensus',
+  minParticipants: 'Minimum participants to start voting',
+  waitingForParticipants:
+    'Waiting for participants, {currentCount} of {requiredCount} needed to start voting',
+  timeBoxMinutes: 'Story time box in minutes (0 for none)',
+  timeBoxExpired: 'Time is up, voting has ended',
+  quorumPercentage: 'Vote quorum percentage to auto finalize (0 for none)',
+  quorumNotReached:
+    'Quorum not reached, {currentPercentage}% of {quorumPercentage}% voted',
+  observerCode: 'Observer Code',
+  optionalObservercodePlaceholder: 'Optional observer code',
+  passwordUpdateRequired:
+    'Your password no longer meets the password requirements, please update it from your profile.',
   storyboardName: 'Storyboard Name',
   storyboardNamePlaceholder: 'Enter a storyboard name',
   retroPhaseTimeLimitMinLabel: 'Brainstorm Phase time limit in minutes',
@@ -3,9 +3,16 @@ export type PokerGame = {
   autoFinishVoting: boolean;
   createdDate: Date;
   hideVoterIdentity: boolean;
+  autoFinalizeOnConsensus?: boolean;
+  minParticipants?: number;
+  timeBoxMinutes?: number;
+  quorumPercentage?: number;
+  estimationScaleId?: string;
+  storyTypeScaleMap?: { [storyType: string]: string };
   id: string;
   joinCode?: string;
   leaderCode?: string;
+  observerCode?: string;
   leaders: Array<String>;
   name: string;
   plans: Array<PokerStory>;
@@ -33,6 +40,7 @@ export type PokerStory = {
   voteStartTime: Date;
   votes: Array<PokerStoryVote>;
   position: number;
+  estimationScale?: { id: string; values: Array<string> };
 };
 
 export type PokerStoryVote = {
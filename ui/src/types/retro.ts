@@ -1,5 +1,6 @@
 export type Retro = {
   actionItems: Array<RetroAction>;
+  activeItemId?: string | null;
   brainstormVisibility: string;
   createdDate: string;
   facilitatorCode: string;
@@ -45,6 +46,7 @@ export type RetroItem = {
   content: string;
   groupId: string;
   id: string;
+  redacted?: boolean;
   type: string;
   userId: string;
 };